@@ -0,0 +1,139 @@
+// Package app builds the dependencies of the API once, so the router takes them from a single
+// container instead of creating its own clients and repositories. Tests and alternate storage
+// backends replace the stores of the container before the router is built.
+package app
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// Container holds the configuration, the clients and the stores the controllers are built with
+type Container struct {
+	Config       *config.Config
+	Supabase     *supa.Client
+	DB           *pgxpool.Pool // Direct Postgres connection, nil with the REST driver
+	Repositories *storage.RepositoryFactory
+
+	Persons                  storage.PersonStore
+	Properties               storage.PropertyStore
+	PropertyPhotos           storage.PropertyPhotoStore
+	Rentals                  storage.RentalStore
+	RentalHistory            storage.RentalHistoryStore
+	RentalParties            storage.RentalPartyStore
+	Users                    storage.UserStore
+	UserSessions             storage.UserSessionStore
+	UserBulkJobs             storage.UserBulkJobStore
+	LoginAttempts            storage.LoginAttemptStore
+	AuditLog                 storage.AuditLogStore
+	RentPayments             storage.RentPaymentStore
+	PaymentStatements        storage.PaymentStatementStore
+	Pricing                  storage.PricingStore
+	BankAccounts             storage.BankAccountStore
+	MaintenanceRequests      storage.MaintenanceRequestStore
+	MaintenanceComments      storage.MaintenanceCommentStore
+	MaintenanceStatusHistory storage.MaintenanceStatusHistoryStore
+	ServiceProviders         storage.ServiceProviderStore
+	Organizations            storage.OrganizationStore
+	Buildings                storage.BuildingStore
+	Reglamentos              storage.ReglamentoStore
+	ContractSignings         storage.ContractSigningStore
+	ContractSigningEvents    storage.ContractSigningEventStore
+	ContractTemplates        storage.ContractTemplateStore
+	ContractCessions         storage.ContractCessionStore
+	Notarizations            storage.NotarizationStore
+	Inventory                storage.InventoryStore
+	Inspections              storage.InspectionStore
+	Promotions               storage.PromotionStore
+	GuaranteeStudies         storage.GuaranteeStudyStore
+	SecurityDeposits         storage.SecurityDepositStore
+	Listings                 storage.ListingStore
+	FileMetadata             storage.FileMetadataStore
+	UploadLimits             storage.UploadLimitStore
+	EmailOutbox              storage.EmailOutboxStore
+	ReminderPreferences      storage.ReminderPreferenceStore
+	ManagerDigests           storage.ManagerDigestStore
+	Webhooks                 storage.WebhookStore
+	Search                   storage.SearchStore
+	FeatureFlags             storage.FeatureFlagStore
+}
+
+// New connects to the Supabase of cfg, and to Postgres when its storage driver is postgres,
+// and fills the container with the repositories of both
+func New(ctx context.Context, cfg *config.Config) (*Container, error) {
+	client, err := storage.InitializeSupabaseClient(cfg.Supabase)
+	if err != nil {
+		return nil, err
+	}
+	db, err := storage.OpenPostgres(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	repos := storage.NewRepositoryFactory(client).UsePostgres(db).UseReferenceCacheTTL(cfg.Database.ReferenceCacheTTL)
+	return NewWithRepositories(cfg, repos, client, db), nil
+}
+
+// NewWithRepositories fills a container with the repositories of a factory. The services that
+// take the whole factory keep using it; the controllers use the stores of the container.
+func NewWithRepositories(cfg *config.Config, repos *storage.RepositoryFactory, client *supa.Client, db *pgxpool.Pool) *Container {
+	return &Container{
+		Config:       cfg,
+		Supabase:     client,
+		DB:           db,
+		Repositories: repos,
+
+		Persons:                  repos.GetPersonRepository(),
+		Properties:               repos.GetPropertyRepository(),
+		PropertyPhotos:           repos.GetPropertyPhotoRepository(),
+		Rentals:                  repos.GetRentalRepository(),
+		RentalHistory:            repos.GetRentalHistoryRepository(),
+		RentalParties:            repos.GetRentalPartyRepository(),
+		Users:                    repos.GetUserRepository(),
+		UserSessions:             repos.GetUserSessionRepository(),
+		UserBulkJobs:             repos.GetUserBulkJobRepository(),
+		LoginAttempts:            repos.GetLoginAttemptRepository(),
+		AuditLog:                 repos.GetAuditLogRepository(),
+		RentPayments:             repos.GetRentPaymentRepository(),
+		PaymentStatements:        repos.GetPaymentStatementRepository(),
+		Pricing:                  repos.GetPricingRepository(),
+		BankAccounts:             repos.GetBankAccountRepository(),
+		MaintenanceRequests:      repos.GetMaintenanceRequestRepository(),
+		MaintenanceComments:      repos.GetMaintenanceCommentRepository(),
+		MaintenanceStatusHistory: repos.GetMaintenanceStatusHistoryRepository(),
+		ServiceProviders:         repos.GetServiceProviderRepository(),
+		Organizations:            repos.GetOrganizationRepository(),
+		Buildings:                repos.GetBuildingRepository(),
+		Reglamentos:              repos.GetReglamentoRepository(),
+		ContractSignings:         repos.GetContractSigningRepository(),
+		ContractSigningEvents:    repos.GetContractSigningEventRepository(),
+		ContractTemplates:        repos.GetContractTemplateRepository(),
+		ContractCessions:         repos.GetContractCessionRepository(),
+		Notarizations:            repos.GetNotarizationRepository(),
+		Inventory:                repos.GetInventoryRepository(),
+		Inspections:              repos.GetInspectionRepository(),
+		Promotions:               repos.GetPromotionRepository(),
+		GuaranteeStudies:         repos.GetGuaranteeStudyRepository(),
+		SecurityDeposits:         repos.GetSecurityDepositRepository(),
+		Listings:                 repos.GetListingRepository(),
+		FileMetadata:             repos.GetFileMetadataRepository(),
+		UploadLimits:             repos.GetUploadLimitRepository(),
+		EmailOutbox:              repos.GetEmailOutboxRepository(),
+		ReminderPreferences:      repos.GetReminderPreferenceRepository(),
+		ManagerDigests:           repos.GetManagerDigestRepository(),
+		Webhooks:                 repos.GetWebhookRepository(),
+		Search:                   repos.GetSearchRepository(),
+		FeatureFlags:             repos.GetFeatureFlagRepository(),
+	}
+}
+
+// Close releases the connections of the container once nothing uses them
+func (c *Container) Close() {
+	if c.DB != nil {
+		c.DB.Close()
+	}
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// Demo users created by Seed, who log in with SeedPassword
+const (
+	SeedAdminEmail    = "admin@demo.local"
+	SeedManagerEmail  = "manager@demo.local"
+	SeedResidentEmail = "resident@demo.local"
+)
+
+// defaultSeedPassword is the password of the demo users when SEED_PASSWORD is not set
+const defaultSeedPassword = "demo12345"
+
+// SeedPassword returns the password of the demo users of c (SEED_PASSWORD), defaultSeedPassword
+// when it is not set
+func (c *Container) SeedPassword() string {
+	if c.Config != nil && c.Config.SeedPassword != "" {
+		return c.Config.SeedPassword
+	}
+	return defaultSeedPassword
+}
+
+// Seed fills an empty database with demo data to run the full stack locally: an organization,
+// an admin, a manager and a resident who can log in, two properties of the manager, the rental
+// of the resident with its pricing and a pending signature request of its contract. It does
+// nothing when the demo admin already exists, and refuses to run with the prod profile.
+func Seed(ctx context.Context, c *Container) error {
+	if c.Config != nil && c.Config.Profile == config.ProfileProd {
+		return fmt.Errorf("the demo data is not seeded with the %s profile, set APP_PROFILE=%s", config.ProfileProd, config.ProfileDev)
+	}
+
+	existing, err := c.Users.GetByEmail(ctx, SeedAdminEmail)
+	if err != nil {
+		return fmt.Errorf("failed to check for seeded data: %w", err)
+	}
+	if existing != nil {
+		logging.FromContext(ctx).Info("the demo data was seeded before", "component", "seed", "email", SeedAdminEmail)
+		return nil
+	}
+
+	admin, err := seedUser(ctx, c, "Ana Administradora", "3001112233", "52123456", SeedAdminEmail, "admin")
+	if err != nil {
+		return err
+	}
+	manager, err := seedUser(ctx, c, "Mateo Gestor", "3014445566", "80123456", SeedManagerEmail, "manager")
+	if err != nil {
+		return err
+	}
+	resident, err := seedUser(ctx, c, "Rosa Residente", "3027778899", "1020304050", SeedResidentEmail, "resident")
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.Organizations.Create(ctx, model.Organization{
+		Name:       "Inmobiliaria Demo",
+		ManagerIDs: []uuid.UUID{manager.PersonID},
+	}); err != nil {
+		return fmt.Errorf("failed to create the demo organization: %w", err)
+	}
+
+	account, err := c.BankAccounts.Create(ctx, model.BankAccount{
+		ID:            uuid.New(),
+		PersonID:      manager.PersonID,
+		BankName:      "Bancolombia",
+		AccountType:   "Ahorros",
+		AccountNumber: "00012345678",
+		AccountHolder: "Mateo Gestor",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the demo bank account: %w", err)
+	}
+
+	apartment, err := c.Properties.Create(ctx, model.Property{
+		ID:          uuid.New(),
+		Address:     "Calle 93 #11-26",
+		AptNumber:   "502",
+		City:        "Bogotá",
+		State:       "Cundinamarca",
+		ZipCode:     "110221",
+		Type:        "apartment",
+		ResidentID:  resident.PersonID,
+		ManagerIDs:  []uuid.UUID{manager.PersonID},
+		Bedrooms:    2,
+		Bathrooms:   2,
+		AreaM2:      68,
+		Estrato:     4,
+		Furnished:   false,
+		PetsAllowed: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the demo apartment: %w", err)
+	}
+	if _, err := c.Properties.Create(ctx, model.Property{
+		ID:           uuid.New(),
+		Address:      "Carrera 43A #7-50",
+		City:         "Medellín",
+		State:        "Antioquia",
+		ZipCode:      "050021",
+		Type:         "house",
+		ResidentID:   manager.PersonID, // Vacant, listed for rent
+		ManagerIDs:   []uuid.UUID{manager.PersonID},
+		Bedrooms:     3,
+		Bathrooms:    2,
+		AreaM2:       120,
+		Estrato:      5,
+		ParkingSpots: 1,
+	}); err != nil {
+		return fmt.Errorf("failed to create the demo house: %w", err)
+	}
+
+	start := time.Now().AddDate(0, -3, 0).Truncate(24 * time.Hour)
+	rental, err := c.Rentals.Create(ctx, model.Rental{
+		ID:            uuid.New(),
+		PropertyID:    apartment.ID,
+		RenterID:      resident.PersonID,
+		BankAccountID: account.ID,
+		StartDate:     model.FlexibleTime(start),
+		EndDate:       model.FlexibleTime(start.AddDate(1, 0, 0)),
+		PaymentTerms:  "Mensual",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the demo rental: %w", err)
+	}
+	// The API is not serving yet, so the history service is set up here
+	service.InitializeRentalHistory(c.RentalHistory).RentalCreated(*rental)
+
+	if _, err := c.Pricing.Create(ctx, model.Pricing{
+		ID:                   uuid.New(),
+		RentalID:             rental.ID,
+		MonthlyRent:          2300000,
+		SecurityDeposit:      2300000,
+		UtilitiesIncluded:    []string{"agua"},
+		TenantResponsibleFor: []string{"energía", "gas", "internet"},
+		LateFee:              80000,
+		DueDay:               5,
+		IncreasePolicy:       model.IncreasePolicyIPC,
+	}); err != nil {
+		return fmt.Errorf("failed to create the demo pricing: %w", err)
+	}
+
+	// The resident finds the contract waiting for their signature after logging in
+	now := time.Now()
+	if _, err := c.ContractSignings.CreateSigningRequest(ctx, model.ContractSigningRequest{
+		ID:             uuid.New().String(),
+		ContractID:     rental.ID.String(),
+		RecipientID:    resident.PersonID.String(),
+		RecipientEmail: SeedResidentEmail,
+		Status:         model.StatusPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.AddDate(0, 0, 7),
+		RequestedBy:    manager.PersonID.String(),
+	}); err != nil {
+		return fmt.Errorf("failed to create the demo signature request: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("demo data created", "component", "seed", "password", c.SeedPassword())
+	for _, user := range []*model.User{admin, manager, resident} {
+		logging.FromContext(ctx).Info("demo user", "component", "seed", "role", user.Role, "email", user.Email)
+	}
+	return nil
+}
+
+// seedUser creates an active user with its person record
+func seedUser(ctx context.Context, c *Container, fullName, phone, nit, email, role string) (*model.User, error) {
+	person, err := c.Persons.Create(ctx, model.Person{
+		ID:       uuid.New(),
+		FullName: fullName,
+		Phone:    phone,
+		NIT:      nit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the person of %s: %w", email, err)
+	}
+
+	user, err := c.Users.Create(ctx, model.User{
+		ID:             uuid.New(),
+		Email:          email,
+		PasswordBase64: base64.StdEncoding.EncodeToString([]byte(c.SeedPassword())),
+		Role:           role,
+		PersonID:       person.ID,
+		Status:         "active",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the user %s: %w", email, err)
+	}
+	return user, nil
+}
@@ -10,32 +10,45 @@ import (
 	"github.com/nescool101/rentManager/model"
 )
 
-// JWTSecretKey is the secret key used to sign JWT tokens
-// In production, this should be set via environment variables
-const JWTSecretKey = "your-super-secret-key-change-this-in-production"
-
 // CustomClaims represents the claims in our JWT
 type CustomClaims struct {
 	UserID   string `json:"user_id"`
 	Email    string `json:"email"`
 	Role     string `json:"role"`
 	PersonID string `json:"person_id"`
+	// ImpersonatorID is the admin acting as the user, set on impersonation tokens only
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	// Locale is the language preference of the user, empty when the user has none
+	Locale string `json:"locale,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a new JWT token for a user
-func GenerateToken(user *model.User) (string, error) {
-	// Token expiration time - 24 hours
-	expirationTime := time.Now().Add(24 * time.Hour)
+// TokenTTL is how long a JWT is valid after login
+const TokenTTL = 24 * time.Hour
+
+// GenerateToken creates a new JWT token for a user, signed with secret (JWT_SECRET). sessionID is
+// its jti claim, the session the token can be revoked by, and expiresAt its expiry.
+func GenerateToken(secret []byte, user *model.User, sessionID string, expiresAt time.Time) (string, error) {
+	return signToken(secret, user, "", sessionID, expiresAt)
+}
+
+// GenerateImpersonationToken creates a JWT token that lets the admin impersonatorID act as user
+func GenerateImpersonationToken(secret []byte, user *model.User, impersonatorID uuid.UUID, sessionID string, expiresAt time.Time) (string, error) {
+	return signToken(secret, user, impersonatorID.String(), sessionID, expiresAt)
+}
 
+func signToken(secret []byte, user *model.User, impersonatorID, sessionID string, expiresAt time.Time) (string, error) {
 	// Create the JWT claims
 	claims := CustomClaims{
-		UserID:   user.ID.String(),
-		Email:    user.Email,
-		Role:     user.Role,
-		PersonID: user.PersonID.String(),
+		UserID:         user.ID.String(),
+		Email:          user.Email,
+		Role:           user.Role,
+		PersonID:       user.PersonID.String(),
+		ImpersonatorID: impersonatorID,
+		Locale:         user.Locale,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "rentManager",
@@ -47,7 +60,7 @@ func GenerateToken(user *model.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign the token with the secret key
-	tokenString, err := token.SignedString([]byte(JWTSecretKey))
+	tokenString, err := token.SignedString(secret)
 	if err != nil {
 		return "", err
 	}
@@ -55,8 +68,8 @@ func GenerateToken(user *model.User) (string, error) {
 	return tokenString, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*CustomClaims, error) {
+// ValidateToken validates a JWT token signed with secret and returns the claims
+func ValidateToken(secret []byte, tokenString string) (*CustomClaims, error) {
 	// Parse the token
 	token, err := jwt.ParseWithClaims(
 		tokenString,
@@ -66,7 +79,7 @@ func ValidateToken(tokenString string) (*CustomClaims, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
-			return []byte(JWTSecretKey), nil
+			return secret, nil
 		},
 	)
 
@@ -83,27 +96,32 @@ func ValidateToken(tokenString string) (*CustomClaims, error) {
 }
 
 // ExtractUserFromToken extracts user information from a JWT token
-func ExtractUserFromToken(tokenString string) (*model.User, error) {
-	claims, err := ValidateToken(tokenString)
+func ExtractUserFromToken(secret []byte, tokenString string) (*model.User, error) {
+	claims, err := ValidateToken(secret, tokenString)
 	if err != nil {
 		return nil, err
 	}
+	return claims.User()
+}
 
-	userID, err := uuid.Parse(claims.UserID)
+// User returns the user the claims were issued to
+func (c *CustomClaims) User() (*model.User, error) {
+	userID, err := uuid.Parse(c.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	personID, err := uuid.Parse(claims.PersonID)
+	personID, err := uuid.Parse(c.PersonID)
 	if err != nil {
 		return nil, err
 	}
 
 	user := &model.User{
 		ID:       userID,
-		Email:    claims.Email,
-		Role:     claims.Role,
+		Email:    c.Email,
+		Role:     c.Role,
 		PersonID: personID,
+		Locale:   c.Locale,
 	}
 
 	return user, nil
@@ -0,0 +1,513 @@
+// Package config reads the settings of the server from the environment once, at startup, into
+// a typed Config. It is the only package that reads the environment: the other packages take
+// the sections they need from the Config main loads.
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds every setting of the server. Optional settings left unset keep their zero
+// value, and each package falls back to its own default for them.
+type Config struct {
+	Profile       string
+	Server        ServerConfig
+	URLs          URLConfig
+	Supabase      SupabaseConfig
+	Database      DatabaseConfig
+	Log           LogConfig
+	Locale        LocaleConfig
+	Email         EmailConfig
+	SMS           SMSConfig
+	Telegram      TelegramConfig
+	Features      FeatureFlags
+	Secrets       SecretsConfig
+	Auth          AuthConfig
+	RateLimits    RateLimitConfig
+	Files         FilesConfig
+	Signing       SigningConfig
+	Notary        NotaryConfig
+	EInvoice      EInvoiceConfig
+	Guarantee     GuaranteeConfig
+	Wompi         WompiConfig
+	Subscriptions SubscriptionConfig
+	Reminders     ReminderConfig
+	Schedules     ScheduleConfig
+	SeedPassword  string // Password of the demo users of go run . --seed (SEED_PASSWORD)
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port               string
+	ShutdownTimeout    time.Duration // Drain of the requests and jobs in flight (SHUTDOWN_TIMEOUT)
+	ClientIPHeader     string        // Header the proxy sets the client IP in (CLIENT_IP_HEADER)
+	TrustedProxies     []string      // IPs and CIDRs whose X-Forwarded-For is believed, none by default (TRUSTED_PROXIES)
+	LegacyRoutesSunset string        // Sunset date of the legacy signing routes, YYYY-MM-DD
+	MetricsToken       string        // Bearer token the metrics scraper sends (METRICS_TOKEN)
+}
+
+// URLConfig holds the public URLs used to build the links of emails and documents
+type URLConfig struct {
+	AppBaseURL string // Frontend (APP_BASE_URL)
+	APIBaseURL string // This backend (API_BASE_URL), "" when not exposed
+}
+
+// SupabaseConfig holds the database and file storage settings
+type SupabaseConfig struct {
+	URL              string
+	Key              string
+	ServiceRoleKey   string
+	StorageBucket    string
+	QuarantineBucket string // Bucket of the infected files (SUPABASE_QUARANTINE_BUCKET)
+}
+
+// DatabaseConfig holds the storage driver of the repositories
+type DatabaseConfig struct {
+	Driver            string         // rest or postgres (STORAGE_DRIVER)
+	URL               string         // Postgres of the postgres driver (DATABASE_URL)
+	ReferenceCacheTTL *time.Duration // REFERENCE_CACHE_TTL, 0 disables the caches
+}
+
+// LogConfig holds the level and format of the structured logs
+type LogConfig struct {
+	Level  string // debug, info, warn or error (LOG_LEVEL)
+	Format string // json or text (LOG_FORMAT)
+}
+
+// LocaleConfig holds the locale and timezone the documents and emails are rendered in
+type LocaleConfig struct {
+	Locale   string // APP_LOCALE
+	Timezone string // APP_TIMEZONE
+}
+
+// SMSConfig holds the gateway the signing codes are sent by SMS through
+type SMSConfig struct {
+	GatewayURL   string
+	GatewayToken string
+}
+
+// TelegramConfig holds the bot the deleted files are backed up to
+type TelegramConfig struct {
+	BotToken string
+	ChatID   string
+}
+
+// FeatureFlags holds the toggles read at startup. The flags admins can change at runtime are
+// nil when the environment does not set them.
+type FeatureFlags struct {
+	Telegram           *bool         // TELEGRAM_ENABLED
+	EInvoiceAutoSubmit *bool         // EINVOICE_AUTO_SUBMIT
+	ReminderSendTime   bool          // REMINDER_SEND_TIME_ENABLED
+	Refresh            time.Duration // Cache of the flags saved by admins (FEATURE_FLAG_REFRESH)
+}
+
+// SecretsConfig holds the HMAC keys of the links and codes sent by email. They must survive
+// restarts, or every link sent before one stops working; they are required with the prod profile.
+type SecretsConfig struct {
+	SigningOTP        string // SIGNING_OTP_SECRET
+	FileShare         string // FILE_SHARE_SECRET
+	NotificationToken string // NOTIFICATION_TOKEN_SECRET
+	PasswordReset     string // PASSWORD_RESET_SECRET
+	EmailVerification string // EMAIL_VERIFICATION_SECRET
+	Invitation        string // INVITATION_SECRET
+}
+
+// AuthConfig holds the key of the session tokens, the lifetimes of the emailed links and the
+// login lockout thresholds
+type AuthConfig struct {
+	JWTSecret               string // HMAC key the session tokens are signed with (JWT_SECRET), always required
+	PasswordResetTTL        time.Duration
+	EmailVerificationTTL    time.Duration
+	InvitationTTL           time.Duration
+	ImpersonationTTL        time.Duration
+	LoginLockoutDuration    time.Duration
+	LoginLockoutThreshold   int
+	LoginIPLockoutThreshold int
+	LoginCaptchaThreshold   int
+	SessionFailOpen         bool // SESSION_FAIL_OPEN
+}
+
+// RateLimitConfig holds the limits of the public routes, as "requests/period" (e.g. 10/1m)
+type RateLimitConfig struct {
+	Login          string // RATE_LIMIT_LOGIN
+	Register       string // RATE_LIMIT_REGISTER
+	Invitation     string // RATE_LIMIT_INVITATION
+	PasswordReset  string // RATE_LIMIT_PASSWORD_RESET
+	Signing        string // RATE_LIMIT_SIGNING
+	SigningRequest string // RATE_LIMIT_SIGNING_REQUEST
+	Upload         string // RATE_LIMIT_UPLOAD
+	UploadToken    string // RATE_LIMIT_UPLOAD_TOKEN
+}
+
+// ScheduleConfig holds the cron schedules of the background jobs, "off" disables a job
+type ScheduleConfig struct {
+	BucketBackup      string // BUCKET_BACKUP_SCHEDULE
+	EmailOutbox       string // EMAIL_OUTBOX_SCHEDULE
+	EInvoiceStatus    string // EINVOICE_STATUS_SCHEDULE
+	SubscriptionCheck string // SUBSCRIPTION_CHECK_SCHEDULE
+	ManagerDigest     string // MANAGER_DIGEST_SCHEDULE
+	FileTrashPurge    string // FILE_TRASH_PURGE_SCHEDULE
+	SigningReminders  string // SIGNING_REMINDER_SCHEDULE
+	CertificateExpiry string // CERT_EXPIRY_CHECK_SCHEDULE
+	ContractCession   string // CONTRACT_CESSION_SCHEDULE
+	WebhookDelivery   string // WEBHOOK_DELIVERY_SCHEDULE
+}
+
+// ReminderConfig holds the settings of the rent reminders and renewals
+type ReminderConfig struct {
+	DefaultSendHour      *int     // Hour of the recipients without a preference (REMINDER_DEFAULT_SEND_HOUR)
+	RenewalIPCPercentage *float64 // Yearly increase of the renewals (RENEWAL_IPC_PERCENTAGE)
+}
+
+// ValidationError lists every setting that is missing or malformed, so a deployment is fixed
+// in one pass instead of one restart per variable
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load reads the settings from the environment, after InitProfile applied the defaults of the
+// profile, and validates them. The returned error is a *ValidationError with every problem.
+func Load() (*Config, error) {
+	r := &reader{}
+
+	cfg := &Config{
+		Profile: activeProfile(),
+		Server: ServerConfig{
+			Port:               r.stringOr("SERVER_PORT", "8080"),
+			ShutdownTimeout:    r.duration("SHUTDOWN_TIMEOUT"),
+			ClientIPHeader:     r.string("CLIENT_IP_HEADER"),
+			TrustedProxies:     r.networks("TRUSTED_PROXIES"),
+			LegacyRoutesSunset: r.date("LEGACY_ROUTES_SUNSET"),
+			MetricsToken:       r.string("METRICS_TOKEN"),
+		},
+		URLs: URLConfig{
+			AppBaseURL: r.absoluteURL("APP_BASE_URL"),
+			APIBaseURL: r.absoluteURL("API_BASE_URL"),
+		},
+		Supabase: SupabaseConfig{
+			URL:              r.require("SUPABASE_URL"),
+			Key:              r.require("SUPABASE_KEY"),
+			ServiceRoleKey:   r.string("SUPABASE_SERVICE_ROLE_KEY"),
+			StorageBucket:    r.string("SUPABASE_STORAGE_BUCKET"),
+			QuarantineBucket: r.string("SUPABASE_QUARANTINE_BUCKET"),
+		},
+		Database: DatabaseConfig{
+			Driver:            strings.ToLower(r.string("STORAGE_DRIVER")),
+			URL:               r.string("DATABASE_URL"),
+			ReferenceCacheTTL: r.optionalDuration("REFERENCE_CACHE_TTL"),
+		},
+		Log: LogConfig{
+			Level:  r.string("LOG_LEVEL"),
+			Format: r.string("LOG_FORMAT"),
+		},
+		Locale: LocaleConfig{
+			Locale:   r.string("APP_LOCALE"),
+			Timezone: r.string("APP_TIMEZONE"),
+		},
+		Email: loadEmail(r),
+		SMS: SMSConfig{
+			GatewayURL:   r.absoluteURL("SMS_GATEWAY_URL"),
+			GatewayToken: r.string("SMS_GATEWAY_TOKEN"),
+		},
+		Features: FeatureFlags{
+			Telegram:           r.optionalFlag("TELEGRAM_ENABLED"),
+			EInvoiceAutoSubmit: r.optionalFlag("EINVOICE_AUTO_SUBMIT"),
+			ReminderSendTime:   r.flag("REMINDER_SEND_TIME_ENABLED"),
+			Refresh:            r.duration("FEATURE_FLAG_REFRESH"),
+		},
+		Secrets: SecretsConfig{
+			SigningOTP:        r.string("SIGNING_OTP_SECRET"),
+			FileShare:         r.string("FILE_SHARE_SECRET"),
+			NotificationToken: r.string("NOTIFICATION_TOKEN_SECRET"),
+			PasswordReset:     r.string("PASSWORD_RESET_SECRET"),
+			EmailVerification: r.string("EMAIL_VERIFICATION_SECRET"),
+			Invitation:        r.string("INVITATION_SECRET"),
+		},
+		Auth: AuthConfig{
+			JWTSecret:               r.require("JWT_SECRET"),
+			PasswordResetTTL:        r.duration("PASSWORD_RESET_TTL"),
+			EmailVerificationTTL:    r.duration("EMAIL_VERIFICATION_TTL"),
+			InvitationTTL:           r.duration("INVITATION_TTL"),
+			ImpersonationTTL:        r.duration("IMPERSONATION_TTL"),
+			LoginLockoutDuration:    r.duration("LOGIN_LOCKOUT_DURATION"),
+			LoginLockoutThreshold:   r.positiveInt("LOGIN_LOCKOUT_THRESHOLD"),
+			LoginIPLockoutThreshold: r.positiveInt("LOGIN_IP_LOCKOUT_THRESHOLD"),
+			LoginCaptchaThreshold:   r.positiveInt("LOGIN_CAPTCHA_THRESHOLD"),
+			SessionFailOpen:         r.flag("SESSION_FAIL_OPEN"),
+		},
+		RateLimits: RateLimitConfig{
+			Login:          r.string("RATE_LIMIT_LOGIN"),
+			Register:       r.string("RATE_LIMIT_REGISTER"),
+			Invitation:     r.string("RATE_LIMIT_INVITATION"),
+			PasswordReset:  r.string("RATE_LIMIT_PASSWORD_RESET"),
+			Signing:        r.string("RATE_LIMIT_SIGNING"),
+			SigningRequest: r.string("RATE_LIMIT_SIGNING_REQUEST"),
+			Upload:         r.string("RATE_LIMIT_UPLOAD"),
+			UploadToken:    r.string("RATE_LIMIT_UPLOAD_TOKEN"),
+		},
+		Files:         loadFiles(r),
+		Signing:       loadSigning(r),
+		Notary:        loadNotary(r),
+		EInvoice:      loadEInvoice(r),
+		Guarantee:     loadGuarantee(r),
+		Wompi:         loadWompi(r),
+		Subscriptions: loadSubscriptions(r),
+		Reminders: ReminderConfig{
+			DefaultSendHour:      r.optionalInt("REMINDER_DEFAULT_SEND_HOUR", 0, 23),
+			RenewalIPCPercentage: r.optionalPercentage("RENEWAL_IPC_PERCENTAGE"),
+		},
+		Schedules: ScheduleConfig{
+			BucketBackup:      r.string("BUCKET_BACKUP_SCHEDULE"),
+			EmailOutbox:       r.string("EMAIL_OUTBOX_SCHEDULE"),
+			EInvoiceStatus:    r.string("EINVOICE_STATUS_SCHEDULE"),
+			SubscriptionCheck: r.string("SUBSCRIPTION_CHECK_SCHEDULE"),
+			ManagerDigest:     r.string("MANAGER_DIGEST_SCHEDULE"),
+			FileTrashPurge:    r.string("FILE_TRASH_PURGE_SCHEDULE"),
+			SigningReminders:  r.string("SIGNING_REMINDER_SCHEDULE"),
+			CertificateExpiry: r.string("CERT_EXPIRY_CHECK_SCHEDULE"),
+			ContractCession:   r.string("CONTRACT_CESSION_SCHEDULE"),
+			WebhookDelivery:   r.string("WEBHOOK_DELIVERY_SCHEDULE"),
+		},
+		SeedPassword: r.string("SEED_PASSWORD"),
+	}
+
+	if cfg.Supabase.URL != "" && !isHTTPURL(cfg.Supabase.URL) {
+		r.problem("SUPABASE_URL must be an http(s) URL, got %q", cfg.Supabase.URL)
+	}
+	if port, err := strconv.Atoi(cfg.Server.Port); err != nil || port < 1 || port > 65535 {
+		r.problem("SERVER_PORT must be a port number, got %q", cfg.Server.Port)
+	}
+	if cfg.URLs.AppBaseURL == "" {
+		cfg.URLs.AppBaseURL = DefaultAppBaseURL
+	}
+	switch cfg.Database.Driver {
+	case "", StorageDriverREST:
+	case StorageDriverPostgres:
+		if cfg.Database.URL == "" {
+			r.problem("DATABASE_URL is required with STORAGE_DRIVER=%s", StorageDriverPostgres)
+		}
+	default:
+		r.problem("STORAGE_DRIVER must be %s or %s, got %q", StorageDriverREST, StorageDriverPostgres, cfg.Database.Driver)
+	}
+
+	// Admins can turn the backups on at runtime, so the bot is read even when they are off
+	readTelegram := r.string
+	if cfg.Features.Telegram != nil && *cfg.Features.Telegram {
+		readTelegram = r.require
+	}
+	cfg.Telegram = TelegramConfig{
+		BotToken: readTelegram("TELEGRAM_BOT_TOKEN"),
+		ChatID:   readTelegram("TELEGRAM_CHAT_ID"),
+	}
+
+	if cfg.Profile == ProfileProd {
+		checkSecrets(r, cfg.Secrets)
+	}
+
+	if len(r.problems) > 0 {
+		return nil, &ValidationError{Problems: r.problems}
+	}
+	return cfg, nil
+}
+
+// checkSecrets requires the HMAC keys of the emailed links. Without them each instance signs
+// with a random key, and the links stop working when fly.io stops or replaces the machine.
+func checkSecrets(r *reader, secrets SecretsConfig) {
+	for _, secret := range []struct {
+		key   string
+		value string
+	}{
+		{"SIGNING_OTP_SECRET", secrets.SigningOTP},
+		{"FILE_SHARE_SECRET", secrets.FileShare},
+		{"NOTIFICATION_TOKEN_SECRET", secrets.NotificationToken},
+		{"PASSWORD_RESET_SECRET", secrets.PasswordReset},
+		{"EMAIL_VERIFICATION_SECRET", secrets.EmailVerification},
+		{"INVITATION_SECRET", secrets.Invitation},
+	} {
+		if secret.value == "" {
+			r.problem("%s is required with the %s profile, the links signed with it must outlive a restart", secret.key, ProfileProd)
+		}
+	}
+}
+
+// reader reads typed variables and collects the problems of every malformed one. Unset
+// variables return the zero value.
+type reader struct {
+	problems []string
+}
+
+func (r *reader) problem(format string, args ...interface{}) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+func (r *reader) string(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+func (r *reader) stringOr(key, fallback string) string {
+	if value := r.string(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (r *reader) require(key string) string {
+	value := r.string(key)
+	if value == "" {
+		r.problem("%s is required", key)
+	}
+	return value
+}
+
+func (r *reader) flag(key string) bool {
+	enabled := r.optionalFlag(key)
+	return enabled != nil && *enabled
+}
+
+func (r *reader) optionalFlag(key string) *bool {
+	value := r.string(key)
+	if value == "" {
+		return nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problem("%s must be true or false, got %q", key, value)
+		return nil
+	}
+	return &enabled
+}
+
+// duration reads a positive duration such as 30m or 2h
+func (r *reader) duration(key string) time.Duration {
+	value := r.string(key)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		r.problem("%s must be a positive duration such as 30m or 2h, got %q", key, value)
+		return 0
+	}
+	return d
+}
+
+// optionalDuration reads a duration that may be 0
+func (r *reader) optionalDuration(key string) *time.Duration {
+	value := r.string(key)
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		r.problem("%s must be a duration such as 30s or 0 to disable it, got %q", key, value)
+		return nil
+	}
+	return &d
+}
+
+func (r *reader) positiveInt(key string) int {
+	value := r.string(key)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		r.problem("%s must be a positive number, got %q", key, value)
+		return 0
+	}
+	return n
+}
+
+// optionalInt reads a number between min and max, which may be 0
+func (r *reader) optionalInt(key string, min, max int) *int {
+	value := r.string(key)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		r.problem("%s must be a number between %d and %d, got %q", key, min, max, value)
+		return nil
+	}
+	return &n
+}
+
+// optionalMegabytes reads a size in MB, which may be 0
+func (r *reader) optionalMegabytes(key string) *int64 {
+	value := r.string(key)
+	if value == "" {
+		return nil
+	}
+	mb, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || mb < 0 {
+		r.problem("%s must be a size in MB, got %q", key, value)
+		return nil
+	}
+	return &mb
+}
+
+func (r *reader) optionalPercentage(key string) *float64 {
+	value := r.string(key)
+	if value == "" {
+		return nil
+	}
+	percentage, err := strconv.ParseFloat(value, 64)
+	if err != nil || percentage < 0 {
+		r.problem("%s must be a percentage such as 5.2, got %q", key, value)
+		return nil
+	}
+	return &percentage
+}
+
+func (r *reader) absoluteURL(key string) string {
+	value := strings.TrimSuffix(r.string(key), "/")
+	if value != "" && !isHTTPURL(value) {
+		r.problem("%s must be an http(s) URL, got %q", key, value)
+	}
+	return value
+}
+
+func (r *reader) date(key string) string {
+	value := r.string(key)
+	if value == "" {
+		return ""
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		r.problem("%s must be a date as YYYY-MM-DD, got %q", key, value)
+		return ""
+	}
+	return value
+}
+
+// networks reads a comma-separated list of IPs and CIDRs
+func (r *reader) networks(key string) []string {
+	var networks []string
+	for _, value := range strings.Split(r.string(key), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+			r.problem("%s must list IPs or CIDRs separated by commas, got %q", key, value)
+			continue
+		}
+		networks = append(networks, value)
+	}
+	return networks
+}
+
+// isHTTPURL reports whether value is an absolute http or https URL
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Email drivers selected with EMAIL_DRIVER
+const (
+	EmailDriverSMTP    = "smtp"
+	EmailDriverResend  = "resend"
+	EmailDriverSES     = "ses"
+	EmailDriverMailgun = "mailgun"
+)
+
+// EmailConfig holds the email driver and its sender
+type EmailConfig struct {
+	Driver     string // smtp, resend, ses or mailgun
+	FromName   string
+	From       string
+	SandboxDir string // Emails are written here instead of being sent, "" to send them
+	SMTP       SMTPConfig
+	Resend     ResendConfig
+	SES        SESConfig
+	Mailgun    MailgunConfig
+}
+
+// SMTPConfig holds the server of the smtp driver
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// ResendConfig holds the API key of the resend driver
+type ResendConfig struct {
+	APIKey string
+}
+
+// SESConfig holds the credentials of the ses driver. Region is us-east-1 and Endpoint the
+// regional endpoint when they are empty.
+type SESConfig struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	Endpoint        string
+}
+
+// MailgunConfig holds the domain of the mailgun driver. APIBase selects the region
+// (https://api.eu.mailgun.net for EU domains).
+type MailgunConfig struct {
+	APIKey  string
+	Domain  string
+	APIBase string
+}
+
+// emailDriverVariables are the variables each HTTP email driver needs besides EMAIL_FROM
+var emailDriverVariables = map[string][]string{
+	EmailDriverResend:  {"RESEND_API_KEY"},
+	EmailDriverSES:     {"SES_ACCESS_KEY_ID", "SES_SECRET_ACCESS_KEY"},
+	EmailDriverMailgun: {"MAILGUN_API_KEY", "MAILGUN_DOMAIN"},
+}
+
+// loadEmail reads the settings of the email drivers and checks the variables of the selected
+// one. Sandboxed environments write emails to disk, so an incomplete sender is fine there.
+func loadEmail(r *reader) EmailConfig {
+	email := EmailConfig{
+		Driver:     strings.ToLower(r.stringOr("EMAIL_DRIVER", EmailDriverSMTP)),
+		FromName:   r.stringOr("EMAIL_FROM_NAME", "Sistema de Gestión de Propiedades"),
+		From:       r.string("EMAIL_FROM"),
+		SandboxDir: r.string("EMAIL_SANDBOX_DIR"),
+		SMTP: SMTPConfig{
+			Host:     r.string("EMAIL_HOST"),
+			Username: r.string("EMAIL_USER"),
+			Password: r.string("EMAIL_PASS"),
+		},
+		Resend: ResendConfig{APIKey: r.string("RESEND_API_KEY")},
+		SES: SESConfig{
+			AccessKeyID:     r.string("SES_ACCESS_KEY_ID"),
+			SecretAccessKey: r.string("SES_SECRET_ACCESS_KEY"),
+			Region:          r.string("SES_REGION"),
+			Endpoint:        r.absoluteURL("SES_ENDPOINT"),
+		},
+		Mailgun: MailgunConfig{
+			APIKey:  r.string("MAILGUN_API_KEY"),
+			Domain:  r.string("MAILGUN_DOMAIN"),
+			APIBase: r.absoluteURL("MAILGUN_API_BASE"),
+		},
+	}
+
+	var missing []string
+	switch email.Driver {
+	case EmailDriverSMTP:
+		for _, key := range []string{"EMAIL_HOST", "EMAIL_USER", "EMAIL_PASS"} {
+			if r.string(key) == "" {
+				missing = append(missing, key)
+			}
+		}
+		if portStr := r.string("EMAIL_PORT"); portStr == "" {
+			missing = append(missing, "EMAIL_PORT")
+		} else if port, err := strconv.Atoi(portStr); err != nil || port < 1 || port > 65535 {
+			// A malformed port is a mistake even where emails are sandboxed
+			r.problem("EMAIL_PORT must be a port number, got %q", portStr)
+			return email
+		} else {
+			email.SMTP.Port = port
+		}
+	default:
+		variables, ok := emailDriverVariables[email.Driver]
+		if !ok {
+			r.problem("EMAIL_DRIVER must be one of smtp, resend, ses or mailgun, got %q", email.Driver)
+			return email
+		}
+		for _, key := range append([]string{"EMAIL_FROM"}, variables...) {
+			if r.string(key) == "" {
+				missing = append(missing, key)
+			}
+		}
+	}
+
+	if email.SandboxDir != "" {
+		return email
+	}
+	for _, key := range missing {
+		r.problem("%s is required by the %s email driver (or set EMAIL_SANDBOX_DIR to write emails to disk)", key, email.Driver)
+	}
+	return email
+}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAppBaseURL is used for public links when APP_BASE_URL is not set
+const DefaultAppBaseURL = "http://localhost:5173"
+
+// Storage drivers selected by STORAGE_DRIVER
+const (
+	StorageDriverREST     = "rest"     // Supabase REST API (PostgREST), the default
+	StorageDriverPostgres = "postgres" // Direct connection to Postgres at DATABASE_URL
+)
+
+// FilesConfig holds the file storage, its links and the processing of the uploads
+type FilesConfig struct {
+	Backend            string        // supabase, local or none (FILE_STORAGE_BACKEND)
+	Dir                string        // Folder of the local backend (FILE_STORAGE_DIR)
+	URLTTL             time.Duration // Download links (FILE_URL_TTL)
+	ShareTTL           time.Duration // Shared links (FILE_SHARE_TTL)
+	ListCacheTTL       time.Duration // FILE_LIST_CACHE_TTL
+	TrashRetentionDays *int          // FILE_TRASH_RETENTION_DAYS, 0 deletes at once
+	Chunked            ChunkedUploadConfig
+	UploadLimits       UploadLimitConfig
+	Encryption         FileEncryptionConfig
+	Backups            FileBackupConfig
+	VirusScan          VirusScanConfig
+}
+
+// ChunkedUploadConfig holds the limits of the uploads sent in parts
+type ChunkedUploadConfig struct {
+	Dir         string // CHUNKED_UPLOAD_DIR
+	MaxSizeMB   int64  // CHUNKED_UPLOAD_MAX_SIZE_MB
+	ChunkSizeMB int64  // CHUNKED_UPLOAD_CHUNK_SIZE_MB
+}
+
+// UploadLimitConfig holds the upload limits of the environment: the shared ones
+// (UPLOAD_MAX_FILE_SIZE_MB, UPLOAD_QUOTA_MB) and those of each role, keyed by the lowercase
+// role of the suffix (UPLOAD_QUOTA_MB_MANAGER)
+type UploadLimitConfig struct {
+	MaxFileSizeMB     *int64
+	QuotaMB           *int64
+	RoleMaxFileSizeMB map[string]int64
+	RoleQuotaMB       map[string]int64
+}
+
+// FileEncryptionConfig holds the keys the files are encrypted at rest with
+type FileEncryptionConfig struct {
+	KMS          string // "" for Key, or vault (FILE_ENCRYPTION_KMS)
+	Key          string // 32 bytes in base64 (FILE_ENCRYPTION_KEY)
+	PreviousKeys string // Comma separated (FILE_ENCRYPTION_PREVIOUS_KEYS)
+	VaultAddr    string
+	VaultToken   string
+	VaultKey     string // FILE_ENCRYPTION_VAULT_KEY
+	WrappedKey   string // FILE_ENCRYPTION_WRAPPED_KEY
+}
+
+// FileBackupConfig holds the providers the files are backed up to before they are deleted
+type FileBackupConfig struct {
+	Providers   string // Comma separated: telegram, s3, gdrive (FILE_BACKUP_PROVIDERS)
+	Required    bool   // FILE_BACKUP_REQUIRED
+	S3          S3BackupConfig
+	GoogleDrive GoogleDriveBackupConfig
+}
+
+// S3BackupConfig holds the bucket of the s3 backups
+type S3BackupConfig struct {
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	Endpoint        string
+	Prefix          string
+}
+
+// GoogleDriveBackupConfig holds the folder of the gdrive backups
+type GoogleDriveBackupConfig struct {
+	FolderID           string // GOOGLE_DRIVE_BACKUP_FOLDER_ID
+	ServiceAccountPath string // GOOGLE_SERVICE_ACCOUNT_PATH
+}
+
+// VirusScanConfig holds the scanner of the uploads
+type VirusScanConfig struct {
+	Provider         string // clamav or virustotal (VIRUS_SCAN_PROVIDER)
+	ClamAVAddress    string
+	VirusTotalAPIKey string
+	Timeout          time.Duration // VIRUS_SCAN_TIMEOUT_SECONDS
+	FailOpen         bool          // VIRUS_SCAN_FAIL_OPEN
+}
+
+// SigningConfig holds the certificate the contracts are signed with and the external
+// signature providers
+type SigningConfig struct {
+	TSAURL                string // Timestamp authority, "" to sign without one (TSA_URL)
+	CertP12Path           string // SIGNING_CERT_P12_PATH
+	CertP12Base64         string // SIGNING_CERT_P12_BASE64
+	CertPassword          string // SIGNING_CERT_PASSWORD
+	CertExpiryWarningDays int    // CERT_EXPIRY_WARNING_DAYS
+	ReminderDays          []int  // SIGNING_REMINDER_DAYS, comma separated
+	ZapSign               ZapSignConfig
+	DocuSign              DocuSignConfig
+}
+
+// ZapSignConfig holds the ZapSign account
+type ZapSignConfig struct {
+	APIToken      string
+	WebhookSecret string
+	APIURL        string
+}
+
+// DocuSignConfig holds the DocuSign account
+type DocuSignConfig struct {
+	BaseURL        string
+	AccountID      string
+	AccessToken    string
+	ConnectHMACKey string
+}
+
+// NotaryConfig holds the digital notary the signed contracts are e-stamped by
+type NotaryConfig struct {
+	APIURL        string
+	APIToken      string
+	WebhookSecret string
+	Name          string
+}
+
+// EInvoiceConfig holds the issuer, the numbering resolution and the provider of the
+// electronic invoices. They are checked when invoicing is initialized.
+type EInvoiceConfig struct {
+	APIURL              string
+	APIToken            string
+	ProviderName        string
+	Environment         string
+	IssuerPersonType    string
+	IssuerNIT           string
+	IssuerName          string
+	IssuerAddress       string
+	IssuerCity          string
+	IssuerEmail         string
+	IssuerTaxLevel      string
+	Prefix              string
+	Resolution          string
+	ResolutionStart     string
+	ResolutionEnd       string
+	RangeFrom           string
+	RangeTo             string
+	TechnicalKey        string
+	SoftwareID          string
+	SoftwarePIN         string
+	SoftwareProviderNIT string
+}
+
+// GuaranteeConfig holds the afianzadora that studies the tenants
+type GuaranteeConfig struct {
+	APIURL   string
+	APIToken string
+	Name     string
+}
+
+// WompiConfig holds the payment gateway of the rent payments
+type WompiConfig struct {
+	PublicKey       string
+	IntegritySecret string
+	EventsSecret    string
+	CheckoutURL     string
+}
+
+// SubscriptionConfig holds the billing of the platform subscriptions
+type SubscriptionConfig struct {
+	GraceDays *int // SUBSCRIPTION_GRACE_DAYS
+	Stripe    StripeConfig
+}
+
+// StripeConfig holds the Stripe account of the subscriptions
+type StripeConfig struct {
+	SecretKey     string
+	PriceID       string
+	WebhookSecret string
+	APIURL        string
+}
+
+func loadFiles(r *reader) FilesConfig {
+	files := FilesConfig{
+		Backend:            strings.ToLower(r.string("FILE_STORAGE_BACKEND")),
+		Dir:                r.string("FILE_STORAGE_DIR"),
+		URLTTL:             r.duration("FILE_URL_TTL"),
+		ShareTTL:           r.duration("FILE_SHARE_TTL"),
+		ListCacheTTL:       r.duration("FILE_LIST_CACHE_TTL"),
+		TrashRetentionDays: r.optionalInt("FILE_TRASH_RETENTION_DAYS", 0, 36500),
+		Chunked: ChunkedUploadConfig{
+			Dir:         r.string("CHUNKED_UPLOAD_DIR"),
+			MaxSizeMB:   int64(r.positiveInt("CHUNKED_UPLOAD_MAX_SIZE_MB")),
+			ChunkSizeMB: int64(r.positiveInt("CHUNKED_UPLOAD_CHUNK_SIZE_MB")),
+		},
+		UploadLimits: UploadLimitConfig{
+			MaxFileSizeMB:     r.optionalMegabytes("UPLOAD_MAX_FILE_SIZE_MB"),
+			QuotaMB:           r.optionalMegabytes("UPLOAD_QUOTA_MB"),
+			RoleMaxFileSizeMB: r.roleMegabytes("UPLOAD_MAX_FILE_SIZE_MB_"),
+			RoleQuotaMB:       r.roleMegabytes("UPLOAD_QUOTA_MB_"),
+		},
+		Encryption: FileEncryptionConfig{
+			KMS:          strings.ToLower(r.string("FILE_ENCRYPTION_KMS")),
+			Key:          r.string("FILE_ENCRYPTION_KEY"),
+			PreviousKeys: r.string("FILE_ENCRYPTION_PREVIOUS_KEYS"),
+			VaultAddr:    r.absoluteURL("VAULT_ADDR"),
+			VaultToken:   r.string("VAULT_TOKEN"),
+			VaultKey:     r.string("FILE_ENCRYPTION_VAULT_KEY"),
+			WrappedKey:   r.string("FILE_ENCRYPTION_WRAPPED_KEY"),
+		},
+		Backups: FileBackupConfig{
+			Providers: r.string("FILE_BACKUP_PROVIDERS"),
+			Required:  r.flag("FILE_BACKUP_REQUIRED"),
+			S3: S3BackupConfig{
+				Bucket:          r.string("BACKUP_S3_BUCKET"),
+				AccessKeyID:     r.string("BACKUP_S3_ACCESS_KEY_ID"),
+				SecretAccessKey: r.string("BACKUP_S3_SECRET_ACCESS_KEY"),
+				Region:          r.string("BACKUP_S3_REGION"),
+				Endpoint:        r.absoluteURL("BACKUP_S3_ENDPOINT"),
+				Prefix:          r.string("BACKUP_S3_PREFIX"),
+			},
+			GoogleDrive: GoogleDriveBackupConfig{
+				FolderID:           r.string("GOOGLE_DRIVE_BACKUP_FOLDER_ID"),
+				ServiceAccountPath: r.string("GOOGLE_SERVICE_ACCOUNT_PATH"),
+			},
+		},
+		VirusScan: VirusScanConfig{
+			Provider:         strings.ToLower(r.string("VIRUS_SCAN_PROVIDER")),
+			ClamAVAddress:    r.string("CLAMAV_ADDRESS"),
+			VirusTotalAPIKey: r.string("VIRUSTOTAL_API_KEY"),
+			FailOpen:         r.flag("VIRUS_SCAN_FAIL_OPEN"),
+		},
+	}
+	if seconds := r.positiveInt("VIRUS_SCAN_TIMEOUT_SECONDS"); seconds > 0 {
+		files.VirusScan.Timeout = time.Duration(seconds) * time.Second
+	}
+	return files
+}
+
+// roleMegabytes reads the sizes of the variables starting with prefix, keyed by the lowercase
+// rest of their name
+func (r *reader) roleMegabytes(prefix string) map[string]int64 {
+	sizes := map[string]int64{}
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		role, ok := strings.CutPrefix(key, prefix)
+		if !ok || role == "" {
+			continue
+		}
+		if mb := r.optionalMegabytes(key); mb != nil {
+			sizes[strings.ToLower(role)] = *mb
+		}
+	}
+	return sizes
+}
+
+func loadSigning(r *reader) SigningConfig {
+	signing := SigningConfig{
+		TSAURL:                r.absoluteURL("TSA_URL"),
+		CertP12Path:           r.string("SIGNING_CERT_P12_PATH"),
+		CertP12Base64:         strings.Join(strings.Fields(os.Getenv("SIGNING_CERT_P12_BASE64")), ""),
+		CertPassword:          os.Getenv("SIGNING_CERT_PASSWORD"), // Spaces may be part of it
+		CertExpiryWarningDays: r.positiveInt("CERT_EXPIRY_WARNING_DAYS"),
+		ZapSign: ZapSignConfig{
+			APIToken:      r.string("ZAPSIGN_API_TOKEN"),
+			WebhookSecret: r.string("ZAPSIGN_WEBHOOK_SECRET"),
+			APIURL:        r.absoluteURL("ZAPSIGN_API_URL"),
+		},
+		DocuSign: DocuSignConfig{
+			BaseURL:        r.absoluteURL("DOCUSIGN_BASE_URL"),
+			AccountID:      r.string("DOCUSIGN_ACCOUNT_ID"),
+			AccessToken:    r.string("DOCUSIGN_ACCESS_TOKEN"),
+			ConnectHMACKey: r.string("DOCUSIGN_CONNECT_HMAC_KEY"),
+		},
+	}
+	if value := r.string("SIGNING_REMINDER_DAYS"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			day, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || day <= 0 {
+				r.problem("SIGNING_REMINDER_DAYS must be positive days separated by commas, got %q", value)
+				return signing
+			}
+			signing.ReminderDays = append(signing.ReminderDays, day)
+		}
+	}
+	return signing
+}
+
+func loadNotary(r *reader) NotaryConfig {
+	return NotaryConfig{
+		APIURL:        r.absoluteURL("NOTARY_API_URL"),
+		APIToken:      r.string("NOTARY_API_TOKEN"),
+		WebhookSecret: r.string("NOTARY_WEBHOOK_SECRET"),
+		Name:          r.string("NOTARY_NAME"),
+	}
+}
+
+func loadEInvoice(r *reader) EInvoiceConfig {
+	return EInvoiceConfig{
+		APIURL:              r.absoluteURL("EINVOICE_API_URL"),
+		APIToken:            r.string("EINVOICE_API_TOKEN"),
+		ProviderName:        r.string("EINVOICE_PROVIDER_NAME"),
+		Environment:         r.string("EINVOICE_ENVIRONMENT"),
+		IssuerPersonType:    r.string("EINVOICE_ISSUER_PERSON_TYPE"),
+		IssuerNIT:           r.string("EINVOICE_ISSUER_NIT"),
+		IssuerName:          r.string("EINVOICE_ISSUER_NAME"),
+		IssuerAddress:       r.string("EINVOICE_ISSUER_ADDRESS"),
+		IssuerCity:          r.string("EINVOICE_ISSUER_CITY"),
+		IssuerEmail:         r.string("EINVOICE_ISSUER_EMAIL"),
+		IssuerTaxLevel:      r.string("EINVOICE_ISSUER_TAX_LEVEL"),
+		Prefix:              r.string("EINVOICE_PREFIX"),
+		Resolution:          r.string("EINVOICE_RESOLUTION"),
+		ResolutionStart:     r.string("EINVOICE_RESOLUTION_START"),
+		ResolutionEnd:       r.string("EINVOICE_RESOLUTION_END"),
+		RangeFrom:           r.string("EINVOICE_RANGE_FROM"),
+		RangeTo:             r.string("EINVOICE_RANGE_TO"),
+		TechnicalKey:        r.string("EINVOICE_TECHNICAL_KEY"),
+		SoftwareID:          r.string("EINVOICE_SOFTWARE_ID"),
+		SoftwarePIN:         r.string("EINVOICE_SOFTWARE_PIN"),
+		SoftwareProviderNIT: r.string("EINVOICE_SOFTWARE_PROVIDER_NIT"),
+	}
+}
+
+func loadGuarantee(r *reader) GuaranteeConfig {
+	return GuaranteeConfig{
+		APIURL:   r.absoluteURL("AFIANZADORA_API_URL"),
+		APIToken: r.string("AFIANZADORA_API_TOKEN"),
+		Name:     r.string("AFIANZADORA_NAME"),
+	}
+}
+
+func loadWompi(r *reader) WompiConfig {
+	return WompiConfig{
+		PublicKey:       r.string("WOMPI_PUBLIC_KEY"),
+		IntegritySecret: r.string("WOMPI_INTEGRITY_SECRET"),
+		EventsSecret:    r.string("WOMPI_EVENTS_SECRET"),
+		CheckoutURL:     r.absoluteURL("WOMPI_CHECKOUT_URL"),
+	}
+}
+
+func loadSubscriptions(r *reader) SubscriptionConfig {
+	return SubscriptionConfig{
+		GraceDays: r.optionalInt("SUBSCRIPTION_GRACE_DAYS", 0, 365),
+		Stripe: StripeConfig{
+			SecretKey:     r.string("STRIPE_SECRET_KEY"),
+			PriceID:       r.string("STRIPE_PRICE_ID"),
+			WebhookSecret: r.string("STRIPE_WEBHOOK_SECRET"),
+			APIURL:        r.absoluteURL("STRIPE_API_URL"),
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Environment profiles
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
+)
+
+// DefaultProfile is the profile of deployments that do not set APP_PROFILE, it keeps the
+// production behavior
+const DefaultProfile = ProfileProd
+
+// productionBucket is the storage bucket of production, other profiles must not write to it
+const productionBucket = "uploads"
+
+// profileDefaults holds the settings of each profile. They are defaults: a variable set in the
+// environment or the .env file always wins, so a profile never hides an explicit setting.
+var profileDefaults = map[string]map[string]string{
+	ProfileDev: {
+		"SUPABASE_STORAGE_BUCKET":    "uploads-dev",
+		"EMAIL_SANDBOX_DIR":          "./tmp/emails",
+		"TSA_URL":                    "", // Sign offline, without trusted timestamps
+		"TELEGRAM_ENABLED":           "false",
+		"REMINDER_SEND_TIME_ENABLED": "false",
+		"LOG_FORMAT":                 "text", // Readable logs in the terminal
+		"LOG_LEVEL":                  "debug",
+	},
+	ProfileStaging: {
+		"SUPABASE_STORAGE_BUCKET":    "uploads-staging",
+		"EMAIL_SANDBOX_DIR":          "./tmp/emails",
+		"TSA_URL":                    "https://freetsa.org/tsr",
+		"TELEGRAM_ENABLED":           "false",
+		"REMINDER_SEND_TIME_ENABLED": "true",
+	},
+	ProfileProd: {
+		"SUPABASE_STORAGE_BUCKET": productionBucket,
+		"TSA_URL":                 "https://freetsa.org/tsr",
+	},
+}
+
+// featureFlags are the flags printed in the startup banner
+var featureFlags = []string{"TELEGRAM_ENABLED", "REMINDER_SEND_TIME_ENABLED"}
+
+// InitProfile selects the environment profile (APP_PROFILE: dev, staging or prod, prod by
+// default), applies its defaults and prints a banner with the settings that differ between
+// environments. Must run before the rest of the configuration is read.
+func InitProfile() {
+	profile := activeProfile()
+	defaults, ok := profileDefaults[profile]
+	if !ok {
+		slog.Error("invalid APP_PROFILE", "profile", profile, "expected", strings.Join(profileNames(), ", "))
+		os.Exit(1)
+	}
+
+	for key, value := range defaults {
+		if _, set := os.LookupEnv(key); !set {
+			os.Setenv(key, value)
+		}
+	}
+
+	printProfileBanner(profile)
+	checkProfileIsolation(profile)
+}
+
+// printProfileBanner logs the active profile and the settings that differ between environments
+func printProfileBanner(profile string) {
+	emails := "SMTP (envío real)"
+	if dir := os.Getenv("EMAIL_SANDBOX_DIR"); dir != "" {
+		emails = "sandbox en " + dir
+	}
+	tsa := os.Getenv("TSA_URL")
+	if tsa == "" {
+		tsa = "sin sello de tiempo"
+	}
+
+	flags := make([]string, 0, len(featureFlags))
+	for _, flag := range featureFlags {
+		flags = append(flags, flag+"="+getEnvOr(flag, "false"))
+	}
+
+	slog.Info("perfil activo",
+		"profile", profile,
+		"bucket", os.Getenv("SUPABASE_STORAGE_BUCKET"),
+		"emails", emails,
+		"tsa", tsa,
+		"flags", strings.Join(flags, " "),
+	)
+}
+
+// checkProfileIsolation warns about settings that mix environments
+func checkProfileIsolation(profile string) {
+	bucket := os.Getenv("SUPABASE_STORAGE_BUCKET")
+	if profile != ProfileProd && bucket == productionBucket {
+		slog.Warn("el perfil usa el bucket de producción", "profile", profile, "bucket", bucket)
+	}
+	if profile != ProfileProd && os.Getenv("EMAIL_SANDBOX_DIR") == "" {
+		slog.Warn("el perfil envía emails reales, define EMAIL_SANDBOX_DIR para evitarlo", "profile", profile)
+	}
+	if profile == ProfileProd && os.Getenv("EMAIL_SANDBOX_DIR") != "" {
+		slog.Warn("el perfil prod tiene EMAIL_SANDBOX_DIR definido, los emails no se están enviando", "profile", profile)
+	}
+}
+
+// activeProfile reads APP_PROFILE, DefaultProfile when it is not set
+func activeProfile() string {
+	profile := strings.ToLower(strings.TrimSpace(os.Getenv("APP_PROFILE")))
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// getEnvOr returns environment variable value or default if not set
+func getEnvOr(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// profileNames returns the supported profiles in alphabetical order
+func profileNames() []string {
+	names := make([]string, 0, len(profileDefaults))
+	for name := range profileDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
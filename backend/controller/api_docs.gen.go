@@ -0,0 +1,2186 @@
+// Code generated by controller/gen from the annotations of the handlers; DO NOT EDIT.
+
+package controller
+
+import (
+	"reflect"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// handlerDocs are the annotations of the documented handlers, by receiver and method
+var handlerDocs = map[string]handlerDoc{
+	"BankAccountController.Create": {
+		Summary:     "Create a new bank account",
+		Description: "Create a new bank account. The owner of the account must be the authenticated user or an admin.",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "account", In: "body", Type: "model.BankAccount", Required: true, Description: "Bank Account object"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.BankAccount", Description: ""},
+		},
+	},
+	"BankAccountController.Delete": {
+		Summary:     "Delete a bank account",
+		Description: "Delete a bank account. Admin only operation.",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Bank Account ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: "No Content"},
+		},
+	},
+	"BankAccountController.GetAll": {
+		Summary:     "Get all bank accounts",
+		Description: "Get all bank accounts",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.BankAccount", Description: ""},
+		},
+	},
+	"BankAccountController.GetByID": {
+		Summary:     "Get bank account by ID",
+		Description: "Get bank account by ID",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Bank Account ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.BankAccount", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Bank account not found"},
+		},
+	},
+	"BankAccountController.GetByPersonID": {
+		Summary:     "Get bank accounts by person ID",
+		Description: "Get bank accounts by person ID",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "personId", In: "path", Type: "string", Required: true, Description: "Person ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.BankAccount", Description: ""},
+		},
+	},
+	"BankAccountController.Update": {
+		Summary:     "Update a bank account",
+		Description: "Update a bank account. The owner of the account must be the authenticated user or an admin.",
+		Tags:        []string{"bank-accounts"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Bank Account ID"},
+			{Name: "account", In: "body", Type: "model.BankAccount", Required: true, Description: "Bank Account object"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.BankAccount", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Bank account not found"},
+		},
+	},
+	"BucketBackupController.HandleGetBackup": {
+		Summary:     "Consultar backup del bucket",
+		Description: "Devuelve el estado de un backup y su manifiesto: ruta, tamaño, hash y ubicación de cada archivo",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del backup"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.BucketBackup", Description: ""},
+		},
+	},
+	"BucketBackupController.HandleListBackups": {
+		Summary:     "Listar backups del bucket",
+		Description: "Lista los últimos backups del bucket de archivos, sin sus manifiestos",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.BucketBackup", Description: ""},
+		},
+	},
+	"BucketBackupController.HandleRestoreBackup": {
+		Summary:     "Restaurar archivos de un backup",
+		Description: "Recupera de los proveedores de backup los archivos indicados, o todos, verifica su hash y los sube a su ruta original",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del backup"},
+			{Name: "request", In: "body", Type: "RestoreBucketBackupRequest", Required: false, Description: "Archivos a restaurar"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"BucketBackupController.HandleStartBackup": {
+		Summary:     "Iniciar backup del bucket",
+		Description: "Copia a los proveedores de backup los archivos nuevos o modificados desde el último backup, o todos con full",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "StartBucketBackupRequest", Required: false, Description: "Tipo de backup"},
+		},
+		Responses: []responseDoc{
+			{Code: 202, Kind: "object", Type: "model.BucketBackup", Description: ""},
+		},
+	},
+	"BuildingController.Create": {
+		Summary:     "Create a building",
+		Description: "The manual de convivencia of the building is the reglamento uploaded for a property with its address and city",
+		Tags:        []string{"buildings"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "building", In: "body", Type: "BuildingRequest", Required: true, Description: "Building data"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Building", Description: ""},
+		},
+	},
+	"BuildingController.GetByID": {
+		Summary: "Get a building",
+		Tags:    []string{"buildings"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Building ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "BuildingResponse", Description: ""},
+		},
+	},
+	"ContractSigningController.StreamSigningStatus": {
+		Summary:     "Stream the status of a signing request",
+		Description: "Server-sent events: a status event with the current status, then one on every change until the request is signed, rejected or expired",
+		Tags:        []string{"signing"},
+		Produce:     []string{"text/event-stream"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Signing request ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "string", Type: "string", Description: "status events"},
+			{Code: 404, Kind: "object", Type: "string", Description: "Signing request not found"},
+		},
+	},
+	"DashboardController.GetSummary": {
+		Summary:     "Dashboard summary",
+		Description: "Active rentals, vacancies, pending signatures, overdue payments, open maintenance requests and contracts expiring in 60 days. Admins see every property and managers the properties they manage.",
+		Tags:        []string{"dashboard"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.DashboardSummary", Description: ""},
+			{Code: 403, Kind: "object", Type: "map[string]string", Description: "Admin or manager role required"},
+		},
+	},
+	"EInvoiceController.Refresh": {
+		Summary: "Refresh the status of the electronic invoice of a payment",
+		Tags:    []string{"payments"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rent Payment ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "storage.RentPayment", Description: ""},
+			{Code: 503, Kind: "object", Type: "map[string]string", Description: "Electronic invoicing not configured"},
+		},
+	},
+	"EInvoiceController.Submit": {
+		Summary:     "Issue the DIAN electronic invoice of a payment",
+		Description: "Generates the UBL 2.1 invoice of a rent payment and submits it through the authorized provider. Invoices rejected or not sent are submitted again with the same number.",
+		Tags:        []string{"payments"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rent Payment ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "storage.RentPayment", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Already invoiced"},
+			{Code: 503, Kind: "object", Type: "map[string]string", Description: "Electronic invoicing not configured"},
+		},
+	},
+	"EmailController.HandleEmailHealth": {
+		Summary:     "Email driver health",
+		Description: "Checks the configured email driver, or the one in the driver query parameter, without sending an email",
+		Tags:        []string{"emails"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "driver", In: "query", Type: "string", Required: false, Description: "smtp, resend, ses or mailgun"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailController.HandleSendTestEmail": {
+		Summary:     "Send test email",
+		Description: "Runs the health check of the configured email driver, or the requested one, and sends it a test email",
+		Tags:        []string{"emails"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "EmailTestRequest", Required: true, Description: "Recipient and driver"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailTemplateController.HandleGetTemplate": {
+		Summary:     "Get email template",
+		Description: "Returns the subject and body currently sent, the default, the placeholders and the saved versions",
+		Tags:        []string{"emails"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Template key"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailTemplateController.HandleListTemplates": {
+		Summary:     "List email templates",
+		Description: "Lists the emails admins can customize with the subject and body currently sent",
+		Tags:        []string{"emails"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailTemplateController.HandlePreviewTemplate": {
+		Summary:     "Preview email template",
+		Description: "Renders the given subject and body, or the version currently sent, with sample data of the email",
+		Tags:        []string{"emails"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Template key"},
+			{Name: "request", In: "body", Type: "EmailTemplatePreviewRequest", Required: false, Description: "Unsaved subject and body"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailTemplateController.HandleResetTemplate": {
+		Summary:     "Reset email template",
+		Description: "Deletes the saved versions so the default email is sent again",
+		Tags:        []string{"emails"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Template key"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"EmailTemplateController.HandleSaveTemplate": {
+		Summary:     "Save email template",
+		Description: "Validates the template against the data of the email and saves it as the version that is sent",
+		Tags:        []string{"emails"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Template key"},
+			{Name: "request", In: "body", Type: "EmailTemplateRequest", Required: true, Description: "Subject and body"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.EmailTemplate", Description: ""},
+		},
+	},
+	"FeatureFlagController.ListFeatureFlags": {
+		Summary:     "List feature flags",
+		Description: "Lists the features and scheduled jobs that can be toggled at runtime, with their value and whether it comes from the default, the environment or an admin",
+		Tags:        []string{"admin"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.FeatureFlag", Description: ""},
+		},
+	},
+	"FeatureFlagController.ResetFeatureFlag": {
+		Summary:     "Reset a feature flag",
+		Description: "Removes the value saved by an admin, so the flag goes back to its environment variable or default",
+		Tags:        []string{"admin"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Feature flag key"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.FeatureFlag", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Unknown feature flag"},
+		},
+	},
+	"FeatureFlagController.UpdateFeatureFlag": {
+		Summary:     "Toggle a feature flag",
+		Description: "Turns a feature or scheduled job on or off for every instance, without a restart. Other instances pick the change up within FEATURE_FLAG_REFRESH.",
+		Tags:        []string{"admin"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "key", In: "path", Type: "string", Required: true, Description: "Feature flag key"},
+			{Name: "request", In: "body", Type: "UpdateFeatureFlagRequest", Required: true, Description: "New value"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.FeatureFlag", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Unknown feature flag"},
+			{Code: 409, Kind: "object", Type: "string", Description: "The feature is not configured"},
+		},
+	},
+	"FileUploadController.HandleAbortChunkedUpload": {
+		Summary: "Cancelar subida por partes",
+		Tags:    []string{"file-upload"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID de la subida"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: ""},
+		},
+	},
+	"FileUploadController.HandleAuthenticatedUpload": {
+		Summary:     "Subir archivo autenticado",
+		Description: "Sube un archivo cuando el usuario está autenticado",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"multipart/form-data"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "file", In: "formData", Type: "file", Required: true, Description: "Archivo a subir"},
+			{Name: "category", In: "formData", Type: "string", Required: false, Description: "cedula, contrato, comprobante_pago, certificado_laboral u otro"},
+			{Name: "tags", In: "formData", Type: "[]string", Required: false, Description: "Etiquetas (repetidas o separadas por comas)"},
+			{Name: "rental_id", In: "formData", Type: "string", Required: false, Description: "Arriendo al que se vincula el archivo"},
+			{Name: "contract_id", In: "formData", Type: "string", Required: false, Description: "Contrato al que se vincula el archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.SupabaseUploadResponse", Description: ""},
+		},
+	},
+	"FileUploadController.HandleBulkDeleteFiles": {
+		Summary:     "Eliminar archivos en lote",
+		Description: "Elimina (o mueve a la papelera) los archivos seleccionados y devuelve el resultado de cada uno (solo admins)",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "BatchFilesRequest", Required: true, Description: "Rutas de los archivos"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleCompleteChunkedUpload": {
+		Summary:     "Completar subida por partes",
+		Description: "Sube a Supabase el archivo ensamblado una vez recibidas todas las partes",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID de la subida"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.SupabaseUploadResponse", Description: ""},
+		},
+	},
+	"FileUploadController.HandleCreateChunkedUpload": {
+		Summary:     "Iniciar subida por partes",
+		Description: "Crea una sesión para subir un archivo grande en partes que se pueden reanudar",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "CreateChunkedUploadRequest", Required: true, Description: "Datos del archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDeleteFile": {
+		Summary:     "Eliminar archivo",
+		Description: "Elimina un archivo específico (solo admins)",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "filePath", In: "path", Type: "string", Required: true, Description: "Ruta del archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDownloadFile": {
+		Summary:     "Descargar archivo",
+		Description: "Descarga un archivo y lo elimina después de la descarga (solo admins)",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"application/octet-stream"},
+		Params: []paramDoc{
+			{Name: "filePath", In: "path", Type: "string", Required: true, Description: "Ruta del archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "binary", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDownloadFileOnly": {
+		Summary:     "Descargar archivo solamente",
+		Description: "Descarga un archivo sin eliminarlo (solo admins)",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"application/octet-stream"},
+		Params: []paramDoc{
+			{Name: "filePath", In: "path", Type: "string", Required: true, Description: "Ruta del archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "binary", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDownloadFilesZip": {
+		Summary:     "Descargar archivos en ZIP",
+		Description: "Genera un ZIP con los archivos seleccionados a medida que se descargan (solo admins). Los archivos que no se pudieron leer se listan en ERRORES.txt dentro del ZIP.",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"application/zip"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "BatchFilesRequest", Required: true, Description: "Rutas de los archivos"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "binary", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDownloadRentalFile": {
+		Summary: "Descargar documento de un arriendo",
+		Tags:    []string{"rentals"},
+		Produce: []string{"application/octet-stream"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del arriendo"},
+			{Name: "fileId", In: "path", Type: "string", Required: true, Description: "ID del documento"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "binary", Description: ""},
+		},
+	},
+	"FileUploadController.HandleDownloadSharedFile": {
+		Summary: "Descargar archivo compartido",
+		Tags:    []string{"file-upload"},
+		Produce: []string{"application/octet-stream"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token del enlace"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "binary", Description: ""},
+		},
+	},
+	"FileUploadController.HandleGenerateUploadLink": {
+		Summary:     "Generar enlace de subida",
+		Description: "Genera un enlace temporal para que un usuario pueda subir archivos",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "GenerateUploadLinkRequest", Required: true, Description: "Datos del destinatario"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleGetChunkedUpload": {
+		Summary:     "Consultar subida por partes",
+		Description: "Devuelve el offset desde el que se debe enviar la siguiente parte",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID de la subida"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleGetMyStorageUsage": {
+		Summary:     "Consultar uso de almacenamiento",
+		Description: "Devuelve el espacio usado, la cuota y el tamaño máximo de archivo del usuario",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.StorageUsage", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListFileScans": {
+		Summary:     "Listar análisis de virus",
+		Description: "Lista los últimos análisis; result=infected muestra solo los archivos rechazados",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "result", In: "query", Type: "string", Required: false, Description: "clean, infected o error"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.FileScan", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListRentalFiles": {
+		Summary:     "Listar documentos de un arriendo",
+		Description: "Lista los archivos subidos con el arriendo o su contrato. Los admins ven todos, los managers los de sus propiedades y los residentes los suyos.",
+		Tags:        []string{"rentals"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del arriendo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.FileMetadata", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListTrash": {
+		Summary:     "Listar papelera",
+		Description: "Lista los archivos eliminados que aún se pueden restaurar (solo admins)",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListUploadLimits": {
+		Summary:     "Listar límites de subida",
+		Description: "Lista los límites de subida de cada rol y si vienen del entorno o de un admin",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.UploadLimit", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListUploadTokens": {
+		Summary:     "Listar tokens de subida",
+		Description: "Lista todos los tokens de subida generados",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "UploadToken", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListUploadedFiles": {
+		Summary:     "Listar archivos subidos",
+		Description: "Lista todos los archivos subidos por usuarios, opcionalmente solo los de una categoría o con ciertas etiquetas",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "category", In: "query", Type: "string", Required: false, Description: "Categoría del documento"},
+			{Name: "tag", In: "query", Type: "[]string", Required: false, Description: "Etiquetas que debe tener el archivo"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Cantidad máxima de archivos"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Archivos a omitir"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "service.SupabaseFileInfo", Description: ""},
+		},
+	},
+	"FileUploadController.HandleListUserFiles": {
+		Summary:     "Listar archivos de un usuario",
+		Description: "Lista todos los archivos subidos por un usuario específico, opcionalmente solo los de una categoría o con ciertas etiquetas",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "userID", In: "path", Type: "string", Required: true, Description: "ID del usuario"},
+			{Name: "category", In: "query", Type: "string", Required: false, Description: "Categoría del documento"},
+			{Name: "tag", In: "query", Type: "[]string", Required: false, Description: "Etiquetas que debe tener el archivo"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Cantidad máxima de archivos"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Archivos a omitir"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "service.SupabaseFileInfo", Description: ""},
+		},
+	},
+	"FileUploadController.HandlePurgeTrashedFile": {
+		Summary: "Purgar archivo",
+		Tags:    []string{"file-upload"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del archivo en la papelera"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleReindexFiles": {
+		Summary:     "Reindexar archivos",
+		Description: "Recorre las carpetas del almacenamiento y actualiza el índice de archivos, p. ej. después de cambios hechos fuera de la aplicación (solo admins)",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleResetUploadLimit": {
+		Summary: "Restablecer límites de subida",
+		Tags:    []string{"file-upload"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "role", In: "path", Type: "string", Required: true, Description: "Rol"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.UploadLimit", Description: ""},
+		},
+	},
+	"FileUploadController.HandleRestoreTrashedFile": {
+		Summary: "Restaurar archivo",
+		Tags:    []string{"file-upload"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID del archivo en la papelera"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleShareFile": {
+		Summary:     "Compartir archivo",
+		Description: "Genera un enlace firmado de corta duración para descargar un archivo sin iniciar sesión (solo admins)",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "filePath", In: "path", Type: "string", Required: true, Description: "Ruta del archivo"},
+			{Name: "request", In: "body", Type: "ShareFileRequest", Required: false, Description: "Vigencia del enlace"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleUpdateUploadLimit": {
+		Summary:     "Definir límites de subida",
+		Description: "Define el tamaño máximo de archivo y la cuota por usuario de un rol (MB, 0 sin límite)",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "role", In: "path", Type: "string", Required: true, Description: "Rol"},
+			{Name: "request", In: "body", Type: "UpdateUploadLimitRequest", Required: true, Description: "Límites en MB"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.UploadLimit", Description: ""},
+		},
+	},
+	"FileUploadController.HandleUploadChunk": {
+		Summary:     "Subir parte",
+		Description: "Agrega una parte al archivo a partir del offset indicado en Upload-Offset",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"application/offset+octet-stream"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "ID de la subida"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"FileUploadController.HandleUploadFileWithAuth": {
+		Summary:     "Subir archivo con token",
+		Description: "Sube un archivo usando un token de autorización temporal",
+		Tags:        []string{"file-upload"},
+		Accept:      []string{"multipart/form-data"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "formData", Type: "string", Required: true, Description: "Token de autorización"},
+			{Name: "file", In: "formData", Type: "file", Required: true, Description: "Archivo a subir"},
+			{Name: "category", In: "formData", Type: "string", Required: false, Description: "cedula, contrato, comprobante_pago, certificado_laboral u otro"},
+			{Name: "tags", In: "formData", Type: "[]string", Required: false, Description: "Etiquetas (repetidas o separadas por comas)"},
+			{Name: "rental_id", In: "formData", Type: "string", Required: false, Description: "Arriendo al que se vincula el archivo"},
+			{Name: "contract_id", In: "formData", Type: "string", Required: false, Description: "Contrato al que se vincula el archivo"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.SupabaseUploadResponse", Description: ""},
+		},
+	},
+	"FileUploadController.HandleValidateToken": {
+		Summary:     "Validar token de subida",
+		Description: "Valida si un token de subida es válido y no ha expirado",
+		Tags:        []string{"file-upload"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token de subida"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"GraphQLController.Query": {
+		Summary:     "GraphQL query",
+		Description: "Read-only queries over persons, properties, rentals, payments and maintenance requests, so nested data such as property → rentals → renter → lastPayment comes in one request. The top-level lists take limit (50 by default, 100 at most) and offset. Admins see everything, managers their properties with their rentals and renters, and the other users their own rentals and properties. Errors of the query come in the errors array of the body.",
+		Tags:        []string{"dashboard"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "service.GraphQLRequest", Required: true, Description: "Query, operationName and variables"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.GraphQLResponse", Description: ""},
+			{Code: 400, Kind: "object", Type: "service.GraphQLResponse", Description: "The query is invalid"},
+		},
+	},
+	"ImpersonationController.GetAuditLog": {
+		Summary:     "Recent audit log entries",
+		Description: "Entries of the last 24 hours by default, newest first. impersonated=true lists only the requests made while impersonating.",
+		Tags:        []string{"admin"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "changed_by", In: "query", Type: "string", Required: false, Description: "User the actions were made as"},
+			{Name: "impersonated_by", In: "query", Type: "string", Required: false, Description: "Admin who impersonated the user"},
+			{Name: "impersonated", In: "query", Type: "bool", Required: false, Description: "Only the impersonated actions"},
+			{Name: "hours", In: "query", Type: "int", Required: false, Description: "Hours back, 24 by default and 720 at most"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Maximum entries, 100 by default and 500 at most"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.AuditLog", Description: ""},
+		},
+	},
+	"ImpersonationController.Impersonate": {
+		Summary:     "Impersonate a user",
+		Description: "Issues a short-lived token (IMPERSONATION_TTL, 30 minutes by default) that acts as the user. Every request made with it is recorded in the audit log with the user and the admin. Admins cannot be impersonated, and the token cannot change the password or manage the sessions of the user.",
+		Tags:        []string{"admin"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "userId", In: "path", Type: "string", Required: true, Description: "User ID"},
+			{Name: "request", In: "body", Type: "object", Required: false, Description: "Reason for the impersonation"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "object", Description: ""},
+			{Code: 403, Kind: "object", Type: "string", Description: "The user cannot be impersonated"},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"InspectionController.Create": {
+		Summary:     "Start an inspection of a rental",
+		Description: "The checklist comes from the chosen template, or else from the move-in inspection for move-outs, the property inventory or the default checklist",
+		Tags:        []string{"inspections"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+			{Name: "inspection", In: "body", Type: "CreateInspectionRequest", Required: true, Description: "Inspection"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Inspection", Description: ""},
+		},
+	},
+	"InspectionController.GetByRentalID": {
+		Summary: "List the inspections of a rental",
+		Tags:    []string{"inspections"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Inspection", Description: ""},
+		},
+	},
+	"InspectionController.GetComparison": {
+		Summary:     "Compare the move-in and move-out inspections of a rental",
+		Description: "Reports the items returned deteriorated or missing, which justify the deductions from the security deposit",
+		Tags:        []string{"inspections"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.InspectionComparison", Description: ""},
+			{Code: 404, Kind: "object", Type: "map[string]string", Description: "The rental lacks a move-in or move-out inspection"},
+		},
+	},
+	"InspectionController.RequestAcknowledgment": {
+		Summary:     "Send an inspection to the tenant to sign",
+		Description: "Every item must have a condition. The tenant receives the signing link by email, signs with a one-time code and the signed acta is stored with the rental files.",
+		Tags:        []string{"inspections"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Inspection ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Inspection waiting for or with the tenant's signature"},
+		},
+	},
+	"InspectionController.Update": {
+		Summary:     "Update an inspection",
+		Description: "Inspections can be edited until the tenant signs them. Editing one sent to sign is only possible after the tenant rejected it or the request expired.",
+		Tags:        []string{"inspections"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Inspection ID"},
+			{Name: "inspection", In: "body", Type: "UpdateInspectionRequest", Required: true, Description: "Rooms and notes"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Inspection", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Inspection waiting for or with the tenant's signature"},
+		},
+	},
+	"InvitationController.Accept": {
+		Summary:     "Accept an invitation",
+		Description: "Creates the account with the password and links it to the organization and property of the invitation. The link cannot be used again.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "object", Required: true, Description: "Token of the invitation link and password"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid or expired invitation"},
+			{Code: 409, Kind: "object", Type: "string", Description: "Email already registered"},
+		},
+	},
+	"InvitationController.GetAll": {
+		Summary:     "List invitations",
+		Description: "Every invitation with its status (pending, accepted, revoked or expired), newest first",
+		Tags:        []string{"admin"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "invitationResponse", Description: ""},
+		},
+	},
+	"InvitationController.GetByToken": {
+		Summary: "Open an invitation link",
+		Tags:    []string{"users"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token of the invitation link"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid or expired invitation"},
+		},
+	},
+	"InvitationController.Revoke": {
+		Summary: "Revoke an invitation",
+		Tags:    []string{"admin"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Invitation ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "No pending invitation"},
+		},
+	},
+	"InvitationController.Send": {
+		Summary:     "Send an invitation",
+		Description: "Emails a signed link valid for INVITATION_TTL (7 days by default) to create an account with the role. Without person_id a person is created with name. Managers join the organization and manage the property; residents live in the property.",
+		Tags:        []string{"admin"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "invitation", In: "body", Type: "service.InvitationInput", Required: true, Description: "Invitation"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Invitation", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid invitation"},
+			{Code: 409, Kind: "object", Type: "string", Description: "Email already registered"},
+		},
+	},
+	"ListingController.Apply": {
+		Summary: "Apply to rent an available property",
+		Tags:    []string{"listings"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Listing ID"},
+			{Name: "application", In: "body", Type: "ListingApplicationRequest", Required: true, Description: "Prospect"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"ListingController.GetPublicListings": {
+		Summary:     "List the available properties",
+		Description: "Public catalog of the listings, cheapest first. On the domain of an organization only its properties are listed.",
+		Tags:        []string{"listings"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "city", In: "query", Type: "string", Required: false, Description: "City, case insensitive"},
+			{Name: "min_price", In: "query", Type: "number", Required: false, Description: "Minimum monthly rent"},
+			{Name: "max_price", In: "query", Type: "number", Required: false, Description: "Maximum monthly rent"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Page size"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Page offset"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.PublicListing", Description: ""},
+		},
+	},
+	"ListingController.Save": {
+		Summary:     "Create or update the listing of a property",
+		Description: "A new listing is published right away. The city is taken from the property.",
+		Tags:        []string{"listings"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "propertyId", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "listing", In: "body", Type: "ListingRequest", Required: true, Description: "Rent terms"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.PropertyListing", Description: ""},
+		},
+	},
+	"ListingController.UpdateStatus": {
+		Summary:     "Change the status of a listing",
+		Description: "Only available listings are shown in the public catalog and accept applications",
+		Tags:        []string{"listings"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Listing ID"},
+			{Name: "status", In: "body", Type: "ListingStatusRequest", Required: true, Description: "New status"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.PropertyListing", Description: ""},
+		},
+	},
+	"ManagerInvitationController.SendInvitation": {
+		Summary:     "Send invitation to a manager",
+		Description: "Create a user with temporary password and send invitation email",
+		Tags:        []string{"admin"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "invitation", In: "body", Type: "InvitationRequest", Required: true, Description: "Invitation data"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal server error"},
+		},
+	},
+	"ManagerRegistrationController.Register": {
+		Summary:     "Register a new manager",
+		Description: "Register a new manager with property, pricing, and bank account details",
+		Tags:        []string{"manager"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "registration", In: "body", Type: "model.ManagerRegistrationRequest", Required: true, Description: "Manager registration data"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.ManagerRegistrationResponse", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal server error"},
+		},
+	},
+	"NotificationController.GetMine": {
+		Summary:     "List my notifications",
+		Description: "Newest first, with the total in the X-Total-Count header. unread=true lists only the unread ones.",
+		Tags:        []string{"users"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "unread", In: "query", Type: "bool", Required: false, Description: "Only the unread notifications"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Maximum notifications, 20 by default and 100 at most"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Notifications to skip"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Notification", Description: ""},
+		},
+	},
+	"NotificationController.GetUnreadCount": {
+		Summary: "Count my unread notifications",
+		Tags:    []string{"users"},
+		Produce: []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+		},
+	},
+	"NotificationController.MarkAllRead": {
+		Summary: "Mark all my notifications as read",
+		Tags:    []string{"users"},
+		Produce: []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+		},
+	},
+	"NotificationController.MarkRead": {
+		Summary: "Mark a notification as read",
+		Tags:    []string{"users"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Notification ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "No unread notification"},
+		},
+	},
+	"NotificationPreferenceController.GetByPersonID": {
+		Summary: "Get notification preferences of a person",
+		Tags:    []string{"notifications"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "personId", In: "path", Type: "string", Required: true, Description: "Person ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.GetByToken": {
+		Summary: "Get notification preferences from an email link",
+		Tags:    []string{"notifications"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token of the manage-preferences link"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.GetMine": {
+		Summary:     "Get my notification preferences",
+		Description: "Returns the frequency of every notification type per channel for the current user",
+		Tags:        []string{"notifications"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.UnsubscribeByToken": {
+		Summary:     "Unsubscribe from notification emails",
+		Description: "Sets the email frequency of the given type, or of every type when empty, to never",
+		Tags:        []string{"notifications"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token of the unsubscribe link"},
+			{Name: "request", In: "body", Type: "UnsubscribeRequest", Required: false, Description: "Notification type"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.UpdateByPersonID": {
+		Summary: "Update notification preferences of a person",
+		Tags:    []string{"notifications"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "personId", In: "path", Type: "string", Required: true, Description: "Person ID"},
+			{Name: "request", In: "body", Type: "NotificationPreferencesRequest", Required: true, Description: "Preferences"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.UpdateByToken": {
+		Summary: "Update notification preferences from an email link",
+		Tags:    []string{"notifications"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "token", In: "path", Type: "string", Required: true, Description: "Token of the manage-preferences link"},
+			{Name: "request", In: "body", Type: "NotificationPreferencesRequest", Required: true, Description: "Preferences"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"NotificationPreferenceController.UpdateMine": {
+		Summary:     "Update my notification preferences",
+		Description: "Saves the frequency (always, monthly, never) of notification types per channel (email, sms)",
+		Tags:        []string{"notifications"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "NotificationPreferencesRequest", Required: true, Description: "Preferences"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"PasswordResetController.ForgotPassword": {
+		Summary:     "Request a password reset",
+		Description: "Emails a one-time reset link valid for PASSWORD_RESET_TTL. The response is the same whether the email has an account or not.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "object", Required: true, Description: "Email of the account"},
+		},
+		Responses: []responseDoc{
+			{Code: 202, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+		},
+	},
+	"PasswordResetController.ResetPassword": {
+		Summary:     "Reset a forgotten password",
+		Description: "Sets the new password of the user of a reset token. The token stops working once used.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "object", Required: true, Description: "Token and new password"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid or expired token"},
+		},
+	},
+	"PaymentCheckoutController.Create": {
+		Summary:     "Pay this month's rent online",
+		Description: "Creates a checkout on the payment gateway (Wompi: cards, PSE, Nequi) for the rent of the current month of the authenticated tenant and returns the page to pay it",
+		Tags:        []string{"payments"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "service.RentCheckout", Description: ""},
+			{Code: 404, Kind: "object", Type: "map[string]string", Description: "No active rental"},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Already paid"},
+			{Code: 503, Kind: "object", Type: "map[string]string", Description: "Online payments not configured"},
+		},
+	},
+	"PaymentCheckoutController.GetByReference": {
+		Summary: "Get the state of an online payment",
+		Tags:    []string{"payments"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "reference", In: "path", Type: "string", Required: true, Description: "Checkout reference"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.PaymentCheckout", Description: ""},
+		},
+	},
+	"PaymentCheckoutController.HandleWebhook": {
+		Summary:     "Payment gateway webhook",
+		Description: "Receives the signed transaction events of the gateway. Approved transactions register the rent payment.",
+		Tags:        []string{"payments"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]string", Description: ""},
+			{Code: 401, Kind: "object", Type: "map[string]string", Description: "Invalid signature"},
+		},
+	},
+	"PersonController.Create": {
+		Summary:     "Create a new person",
+		Description: "Create a new person. Allowed for Admin and Manager roles.",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "person", In: "body", Type: "model.Person", Required: true, Description: "Person object"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Person", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid input"},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+		},
+	},
+	"PersonController.Delete": {
+		Summary:     "Delete a person",
+		Description: "Delete a person. Allowed for Admin role only. Cascade deletes all associated bank accounts first.",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Person ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: "No Content"},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid ID format"},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal server error"},
+		},
+	},
+	"PersonController.GetAll": {
+		Summary:     "Get all persons (role-based)",
+		Description: "Get all persons. Admins get all. Managers get persons (renters and self) associated with their managed properties.",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Page size, the whole list without it"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Rows skipped"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Column to sort by, prefixed with - for descending order"},
+			{Name: "filter[column]", In: "query", Type: "string", Required: false, Description: "Exact value of a column"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Person", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+		},
+		Headers: []headerDoc{
+			{Code: 200, Type: "int", Name: "X-Total-Count", Description: "Size of the list before limit and offset"},
+		},
+	},
+	"PersonController.GetByID": {
+		Summary:     "Get person by ID",
+		Description: "Get person by ID. Admins/Managers can get any. Residents can get their own.",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Person ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Person", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 404, Kind: "object", Type: "string", Description: "Person not found"},
+		},
+	},
+	"PersonController.GetByRole": {
+		Summary:     "Get persons by role",
+		Description: "Get persons by role",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "role", In: "path", Type: "string", Required: true, Description: "Role name"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Person", Description: ""},
+		},
+	},
+	"PersonController.Update": {
+		Summary:     "Update a person",
+		Description: "Update a person. Allowed for Admin role and for users updating their own information.",
+		Tags:        []string{"persons"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Person ID"},
+			{Name: "person", In: "body", Type: "model.Person", Required: true, Description: "Person object"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "Version of the person, instead of the version of the body"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Person", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid input or ID format"},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 404, Kind: "object", Type: "string", Description: "Person not found"},
+			{Code: 409, Kind: "object", Type: "object", Description: "The person changed since its version was read"},
+			{Code: 428, Kind: "object", Type: "string", Description: "The version of the person was not sent"},
+		},
+	},
+	"PropertyController.Create": {
+		Summary:     "Create a new property",
+		Description: "Create a new property",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "property", In: "body", Type: "model.Property", Required: true, Description: "Property object"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Property", Description: ""},
+		},
+	},
+	"PropertyController.Delete": {
+		Summary:     "Delete a property",
+		Description: "Delete a property",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: "No Content"},
+		},
+	},
+	"PropertyController.DeletePhoto": {
+		Summary: "Delete a property photo",
+		Tags:    []string{"properties"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "photoId", In: "path", Type: "string", Required: true, Description: "Photo ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.PropertyPhoto", Description: "The remaining gallery"},
+		},
+	},
+	"PropertyController.GetAll": {
+		Summary:     "Get properties (role-based)",
+		Description: "Get properties. Admins get all. Managers get their managed properties. Residents get their resident properties.",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "city", In: "query", Type: "string", Required: false, Description: "City, case insensitive"},
+			{Name: "min_bedrooms", In: "query", Type: "int", Required: false, Description: "Minimum number of bedrooms"},
+			{Name: "min_bathrooms", In: "query", Type: "int", Required: false, Description: "Minimum number of bathrooms"},
+			{Name: "min_area", In: "query", Type: "number", Required: false, Description: "Minimum area in m²"},
+			{Name: "max_area", In: "query", Type: "number", Required: false, Description: "Maximum area in m²"},
+			{Name: "estrato", In: "query", Type: "int", Required: false, Description: "Socioeconomic stratum, 1 to 6"},
+			{Name: "parking", In: "query", Type: "bool", Required: false, Description: "With (true) or without (false) parking spots"},
+			{Name: "furnished", In: "query", Type: "bool", Required: false, Description: "Furnished"},
+			{Name: "pets_allowed", In: "query", Type: "bool", Required: false, Description: "Pets allowed"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Page size, the whole list without it"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Rows skipped"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Column to sort by, prefixed with - for descending order"},
+			{Name: "filter[column]", In: "query", Type: "string", Required: false, Description: "Exact value of a column"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Property", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+		},
+		Headers: []headerDoc{
+			{Code: 200, Type: "int", Name: "X-Total-Count", Description: "Size of the list before limit and offset"},
+		},
+	},
+	"PropertyController.GetByID": {
+		Summary:     "Get property by ID",
+		Description: "Get property by ID",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Property", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Property not found"},
+		},
+	},
+	"PropertyController.GetByManagerID": {
+		Summary:     "Get properties by manager ID",
+		Description: "Get properties by manager ID",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "managerId", In: "path", Type: "string", Required: true, Description: "Manager ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Property", Description: ""},
+		},
+	},
+	"PropertyController.GetByResident": {
+		Summary:     "Get properties by resident ID",
+		Description: "Get properties by resident ID",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "residentId", In: "path", Type: "string", Required: true, Description: "Resident ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Property", Description: ""},
+		},
+	},
+	"PropertyController.GetByUserID": {
+		Summary:     "Get properties for a specific user",
+		Description: "Get properties that a user is renting",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "userId", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Property", Description: ""},
+		},
+	},
+	"PropertyController.ReorderPhotos": {
+		Summary:     "Reorder the photos of a property",
+		Description: "photo_ids must list every photo of the property once, in the new order",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "order", In: "body", Type: "ReorderPhotosRequest", Required: true, Description: "Photo IDs in order"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.PropertyPhoto", Description: ""},
+		},
+	},
+	"PropertyController.Update": {
+		Summary:     "Update a property",
+		Description: "Update a property",
+		Tags:        []string{"properties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "property", In: "body", Type: "model.Property", Required: true, Description: "Property object"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "Version of the property, instead of the version of the body"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Property", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Property not found"},
+			{Code: 409, Kind: "object", Type: "object", Description: "The property changed since its version was read"},
+			{Code: 428, Kind: "object", Type: "string", Description: "The version of the property was not sent"},
+		},
+	},
+	"PropertyController.UpdatePhoto": {
+		Summary: "Caption a property photo",
+		Tags:    []string{"properties"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "photoId", In: "path", Type: "string", Required: true, Description: "Photo ID"},
+			{Name: "photo", In: "body", Type: "UpdatePhotoRequest", Required: true, Description: "Caption"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.PropertyPhoto", Description: ""},
+		},
+	},
+	"PropertyController.UploadPhoto": {
+		Summary:     "Upload a property photo",
+		Description: "The photo is stored under property_<id>/ and returned with the property payload, in gallery order",
+		Tags:        []string{"properties"},
+		Accept:      []string{"multipart/form-data"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "file", In: "formData", Type: "file", Required: true, Description: "JPG, PNG or GIF photo up to 10 MB"},
+			{Name: "caption", In: "formData", Type: "string", Required: false, Description: "Caption of the photo"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.PropertyPhoto", Description: ""},
+		},
+	},
+	"RentPaymentController.GetAll": {
+		Summary:     "Get all rent payments (Admin only)",
+		Description: "Retrieves all rent payments. Restricted to Admin users.",
+		Tags:        []string{"payments"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "storage.RentPayment", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal Server Error"},
+		},
+	},
+	"RentPaymentController.GetByID": {
+		Summary:     "Get rent payment by ID",
+		Description: "Retrieves a specific rent payment by its ID. Admins can get any. Managers/Residents can get if related to their rentals.",
+		Tags:        []string{"payments"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rent Payment ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "storage.RentPayment", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 404, Kind: "object", Type: "string", Description: "Not Found"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal Server Error"},
+		},
+	},
+	"RentPaymentController.GetByRentalIDs": {
+		Summary:     "Get payments by multiple rental IDs",
+		Description: "Retrieves payments for a given list of rental IDs. For Managers, only rentals on managed properties are implicitly allowed (though filtering happens client-side or via rental ID list).",
+		Tags:        []string{"payments"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "rentalIDs", In: "body", Type: "[]string", Required: true, Description: "List of Rental IDs"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "storage.RentPayment", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid input"},
+			{Code: 401, Kind: "object", Type: "string", Description: "Unauthorized"},
+			{Code: 403, Kind: "object", Type: "string", Description: "Forbidden"},
+			{Code: 500, Kind: "object", Type: "string", Description: "Internal Server Error"},
+		},
+	},
+	"RentalController.Create": {
+		Summary:     "Create a new rental",
+		Description: "Create a new rental",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "rental", In: "body", Type: "model.Rental", Required: true, Description: "Rental object"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.Rental", Description: ""},
+		},
+	},
+	"RentalController.Delete": {
+		Summary:     "Delete a rental",
+		Description: "Delete a rental",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: "No Content"},
+		},
+	},
+	"RentalController.GetActiveRentals": {
+		Summary:     "Get active rentals",
+		Description: "Get active rentals",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Rental", Description: ""},
+		},
+	},
+	"RentalController.GetAll": {
+		Summary:     "Get rentals (role-based for admin/manager)",
+		Description: "Get rentals. Admins get all. Managers get rentals for their properties.",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Rental", Description: ""},
+		},
+	},
+	"RentalController.GetByID": {
+		Summary:     "Get rental by ID",
+		Description: "Get rental by ID",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Rental", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Rental not found"},
+		},
+	},
+	"RentalController.GetByPropertyID": {
+		Summary:     "Get rentals by property ID",
+		Description: "Get rentals by property ID",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "propertyId", In: "path", Type: "string", Required: true, Description: "Property ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Rental", Description: ""},
+		},
+	},
+	"RentalController.GetByRenterID": {
+		Summary:     "Get rentals by renter ID",
+		Description: "Get rentals by renter ID",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "renterId", In: "path", Type: "string", Required: true, Description: "Renter ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.Rental", Description: ""},
+		},
+	},
+	"RentalController.Update": {
+		Summary:     "Update a rental",
+		Description: "Update a rental",
+		Tags:        []string{"rentals"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+			{Name: "rental", In: "body", Type: "model.Rental", Required: true, Description: "Rental object"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "Version of the rental, instead of the version of the body"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.Rental", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Rental not found"},
+			{Code: 409, Kind: "object", Type: "object", Description: "The rental changed since its version was read"},
+			{Code: 428, Kind: "object", Type: "string", Description: "The version of the rental was not sent"},
+		},
+	},
+	"RentalHistoryController.GetPropertyOccupancy": {
+		Summary:     "Occupancy timeline of a property",
+		Description: "Rentals closed early (transfers) end on the date recorded in the rental history. The range defaults to the first rental until today.",
+		Tags:        []string{"rental-history"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Property ID"},
+			{Name: "from", In: "query", Type: "string", Required: false, Description: "Start of the range (YYYY-MM-DD)"},
+			{Name: "to", In: "query", Type: "string", Required: false, Description: "End of the range (YYYY-MM-DD), today by default"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.OccupancyTimeline", Description: ""},
+		},
+	},
+	"RentalPartyController.Create": {
+		Summary:     "Add a party to a rental contract",
+		Description: "The cosigner and witness are printed in the contracts of the rental and the cosigners are asked to sign its renewals. An afianzadora policy substitutes the cosigner.",
+		Tags:        []string{"rental-parties"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+			{Name: "party", In: "body", Type: "RentalPartyRequest", Required: true, Description: "Person and role"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "RentalPartyResponse", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "The person already has the role in the rental"},
+		},
+	},
+	"RentalPartyController.GetByRentalID": {
+		Summary: "List the parties of a rental contract",
+		Tags:    []string{"rental-parties"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "RentalPartyResponse", Description: ""},
+		},
+	},
+	"SearchController.Search": {
+		Summary:     "Full-text search",
+		Description: "Every word of q is matched as a prefix, ignoring case and accents. Admins search every record, managers the records of the properties they manage and the other users their own properties, rentals and files.",
+		Tags:        []string{"search"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "q", In: "query", Type: "string", Required: true, Description: "Text to search, at least 2 characters"},
+			{Name: "types", In: "query", Type: "string", Required: false, Description: "Comma-separated result types: person, property, rental, file"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Maximum results, 20 by default and 50 at most"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.SearchResult", Description: ""},
+		},
+	},
+	"SecurityDepositController.AddDeduction": {
+		Summary: "Record a deduction from a security deposit",
+		Tags:    []string{"deposits"},
+		Accept:  []string{"multipart/form-data"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "category", In: "formData", Type: "string", Required: true, Description: "damages, unpaid_rent, utilities, cleaning or other"},
+			{Name: "reason", In: "formData", Type: "string", Required: true, Description: "Reason of the deduction"},
+			{Name: "amount", In: "formData", Type: "number", Required: true, Description: "Amount withheld"},
+			{Name: "file", In: "formData", Type: "file", Required: false, Description: "Invoice or quote backing the deduction"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Settlement already signed"},
+		},
+	},
+	"SecurityDepositController.Create": {
+		Summary: "Record the security deposit of a rental",
+		Tags:    []string{"deposits"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+			{Name: "deposit", In: "body", Type: "SecurityDepositRequest", Required: true, Description: "Deposit"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Deposit already recorded"},
+		},
+	},
+	"SecurityDepositController.GetByRentalID": {
+		Summary: "Get the security deposit of a rental",
+		Tags:    []string{"deposits"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Rental ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 404, Kind: "object", Type: "map[string]string", Description: "No deposit recorded"},
+		},
+	},
+	"SecurityDepositController.GetMine": {
+		Summary: "List my security deposits",
+		Tags:    []string{"deposits"},
+		Produce: []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"SecurityDepositController.IssueSettlement": {
+		Summary:     "Issue the move-out settlement of a deposit",
+		Description: "Generates the settlement PDF with the deductions and the balance to refund, stores it with the rental files and emails it to the tenant, who signs it from the platform",
+		Tags:        []string{"deposits"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "settlement", In: "body", Type: "IssueSettlementRequest", Required: true, Description: "Move-out date"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"SecurityDepositController.Refund": {
+		Summary: "Record the refund of a deposit",
+		Tags:    []string{"deposits"},
+		Accept:  []string{"json"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "refund", In: "body", Type: "RefundDepositRequest", Required: true, Description: "Refund"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Settlement not signed"},
+		},
+	},
+	"SecurityDepositController.RemoveDeduction": {
+		Summary: "Remove a deduction from a security deposit",
+		Tags:    []string{"deposits"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "deductionId", In: "path", Type: "string", Required: true, Description: "Deduction ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+		},
+	},
+	"SecurityDepositController.ServeSettlement": {
+		Summary: "Download the settlement of a deposit",
+		Tags:    []string{"deposits"},
+		Produce: []string{"application/pdf"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "file", Type: "file", Description: ""},
+			{Code: 403, Kind: "object", Type: "map[string]string", Description: "Not the tenant of the rental"},
+		},
+	},
+	"SecurityDepositController.SignSettlement": {
+		Summary:     "Sign the settlement of my deposit",
+		Description: "The tenant accepts the settlement, which is re-issued with their electronic signature, the evidence of the signature and a verification QR code",
+		Tags:        []string{"deposits"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "location", In: "body", Type: "SignerLocation", Required: false, Description: "Location of the signer"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 403, Kind: "object", Type: "map[string]string", Description: "Not the tenant of the rental"},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "No settlement waiting for a signature"},
+		},
+	},
+	"SecurityDepositController.Update": {
+		Summary:     "Update a security deposit",
+		Description: "Editing a deposit whose settlement was already sent discards the settlement, which must be issued again",
+		Tags:        []string{"deposits"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+			{Name: "deposit", In: "body", Type: "SecurityDepositRequest", Required: true, Description: "Deposit"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Settlement already signed"},
+		},
+	},
+	"SecurityDepositController.VerifySettlement": {
+		Summary:     "Verify a signed deposit settlement",
+		Description: "Public endpoint opened from the QR code printed on the signed settlement",
+		Tags:        []string{"deposits"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Deposit ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]interface{}", Description: ""},
+			{Code: 404, Kind: "object", Type: "map[string]interface{}", Description: "Settlement not found"},
+		},
+	},
+	"SessionController.GetMine": {
+		Summary:     "List my active sessions",
+		Description: "Sessions that are neither expired nor revoked, with the device and IP they were opened from, most recently used first. current marks the session of the request.",
+		Tags:        []string{"users"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "sessionResponse", Description: ""},
+		},
+	},
+	"SessionController.RevokeMine": {
+		Summary:     "Revoke one of my sessions",
+		Description: "Logs a device out. Revoking the current session logs the request's own device out.",
+		Tags:        []string{"users"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Session ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "Session not found"},
+		},
+	},
+	"SubscriptionController.GetAll": {
+		Summary: "List the platform subscriptions",
+		Tags:    []string{"subscriptions"},
+		Produce: []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.PlatformSubscription", Description: ""},
+		},
+	},
+	"SubscriptionController.GetMine": {
+		Summary:     "Get my platform subscription",
+		Description: "Returns the monthly plan of the authenticated manager, with the page to pay the open invoice when one is owed",
+		Tags:        []string{"subscriptions"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.PlatformSubscription", Description: ""},
+			{Code: 404, Kind: "object", Type: "map[string]string", Description: "No subscription"},
+			{Code: 503, Kind: "object", Type: "map[string]string", Description: "Subscription billing not configured"},
+		},
+	},
+	"SubscriptionController.HandleWebhook": {
+		Summary:     "Subscription billing webhook",
+		Description: "Receives the signed events of the billing provider. Paid invoices activate the manager, subscriptions left unpaid disable it.",
+		Tags:        []string{"subscriptions"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "map[string]string", Description: ""},
+			{Code: 401, Kind: "object", Type: "map[string]string", Description: "Invalid signature"},
+		},
+	},
+	"SubscriptionController.Subscribe": {
+		Summary:     "Subscribe a manager to the platform plan",
+		Description: "Creates the subscription of a manager registered before billing was enabled, or replaces an ended one. The user is activated when the first invoice is paid.",
+		Tags:        []string{"subscriptions"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.PlatformSubscription", Description: ""},
+			{Code: 409, Kind: "object", Type: "map[string]string", Description: "Subscription in force"},
+			{Code: 503, Kind: "object", Type: "map[string]string", Description: "Subscription billing not configured"},
+		},
+	},
+	"UserBulkController.CreateJob": {
+		Summary:     "Bulk user operation",
+		Description: "Activate, disable or resend the invitation to the users matching a filter (status, role, organization, creation date)",
+		Tags:        []string{"admin"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "UserBulkRequest", Required: true, Description: "Action and filter"},
+		},
+		Responses: []responseDoc{
+			{Code: 202, Kind: "object", Type: "model.UserBulkJob", Description: ""},
+		},
+	},
+	"UserBulkController.GetJob": {
+		Summary: "Get bulk user job",
+		Tags:    []string{"admin"},
+		Produce: []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Job ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.UserBulkJob", Description: ""},
+		},
+	},
+	"UserBulkController.ListJobs": {
+		Summary: "List bulk user jobs",
+		Tags:    []string{"admin"},
+		Produce: []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.UserBulkJob", Description: ""},
+		},
+	},
+	"UserController.ChangePassword": {
+		Summary:     "Change user password",
+		Description: "Change user password with current password verification",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+			{Name: "passwords", In: "body", Type: "object", Required: true, Description: "Password change data"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+			{Code: 401, Kind: "object", Type: "string", Description: "Invalid current password"},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"UserController.Create": {
+		Summary:     "Create a new user",
+		Description: "Create a new user",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "user", In: "body", Type: "model.User", Required: true, Description: "User object"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "model.User", Description: ""},
+		},
+	},
+	"UserController.Delete": {
+		Summary:     "Delete a user",
+		Description: "Delete a user",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 204, Kind: "", Type: "", Description: "No Content"},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"UserController.GetAll": {
+		Summary:     "Get all users",
+		Description: "Get all users",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Page size, the whole list without it"},
+			{Name: "offset", In: "query", Type: "int", Required: false, Description: "Rows skipped"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Column to sort by, prefixed with - for descending order"},
+			{Name: "filter[column]", In: "query", Type: "string", Required: false, Description: "Exact value of a column"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.User", Description: ""},
+		},
+		Headers: []headerDoc{
+			{Code: 200, Type: "int", Name: "X-Total-Count", Description: "Size of the list before limit and offset"},
+		},
+	},
+	"UserController.GetByEmail": {
+		Summary:     "Get user by email",
+		Description: "Get user by email",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "email", In: "query", Type: "string", Required: true, Description: "Email address"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.User", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"UserController.GetByID": {
+		Summary:     "Get user by ID",
+		Description: "Get user by ID",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.User", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"UserController.GetLoginAttempts": {
+		Summary:     "Recent login attempts",
+		Description: "Failed attempts of the last 24 hours by default, newest first. failed=false includes the successful ones.",
+		Tags:        []string{"users"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "email", In: "query", Type: "string", Required: false, Description: "Email of the attempts"},
+			{Name: "ip", In: "query", Type: "string", Required: false, Description: "IP address of the attempts"},
+			{Name: "failed", In: "query", Type: "bool", Required: false, Description: "Only the failed attempts, true by default"},
+			{Name: "hours", In: "query", Type: "int", Required: false, Description: "Hours back, 24 by default and 720 at most"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Maximum attempts, 100 by default and 500 at most"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "array", Type: "model.LoginAttempt", Description: ""},
+		},
+	},
+	"UserController.Login": {
+		Summary:     "Login user",
+		Description: "Authenticate user by email and password. After LOGIN_CAPTCHA_THRESHOLD failures the responses carry captcha_required; after LOGIN_LOCKOUT_THRESHOLD failures of the email, or LOGIN_IP_LOCKOUT_THRESHOLD of the IP, logins are rejected until locked_until.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "credentials", In: "body", Type: "LoginRequest", Required: true, Description: "User credentials"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "LoginResponse", Description: ""},
+			{Code: 401, Kind: "object", Type: "string", Description: "Authentication failed"},
+			{Code: 429, Kind: "object", Type: "service.LoginStatus", Description: "Too many failed attempts"},
+		},
+	},
+	"UserController.Update": {
+		Summary:     "Update a user",
+		Description: "Update a user's information",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+			{Name: "user", In: "body", Type: "model.User", Required: true, Description: "User object"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "model.User", Description: ""},
+			{Code: 404, Kind: "object", Type: "string", Description: "User not found"},
+		},
+	},
+	"UserController.UpdateMyLocale": {
+		Summary:     "Set my language",
+		Description: "Sets the locale of the emails and the API errors of the authenticated user, es-CO or en-US. An empty locale follows the Accept-Language header and the organization again. The API errors use it from the next login.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "locale", In: "body", Type: "object", Required: true, Description: "Locale, e.g. {\\\"locale\\\": \\\"en-US\\\"}"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Unsupported locale"},
+		},
+	},
+	"UserRegistrationController.Register": {
+		Summary:     "Register a new user",
+		Description: "Creates a manager account in newuser status that cannot log in until its email is verified. Once verified, the admins are notified to approve it.",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "registration", In: "body", Type: "object", Required: true, Description: "Name, email, password and optional phone"},
+		},
+		Responses: []responseDoc{
+			{Code: 201, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+			{Code: 409, Kind: "object", Type: "string", Description: "Email already registered"},
+		},
+	},
+	"UserRegistrationController.ResendVerification": {
+		Summary:     "Resend the verification email",
+		Description: "The response is the same whether the email is pending verification or not",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "object", Required: true, Description: "Email of the account"},
+		},
+		Responses: []responseDoc{
+			{Code: 202, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Bad request"},
+		},
+	},
+	"UserRegistrationController.VerifyEmail": {
+		Summary:     "Verify the email of a registered user",
+		Description: "Lets the user log in and notifies the admins that the account awaits their approval",
+		Tags:        []string{"users"},
+		Accept:      []string{"json"},
+		Produce:     []string{"json"},
+		Params: []paramDoc{
+			{Name: "request", In: "body", Type: "object", Required: true, Description: "Token of the verification link"},
+		},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "object", Description: ""},
+			{Code: 400, Kind: "object", Type: "string", Description: "Invalid or expired token"},
+		},
+	},
+	"getCapabilities": {
+		Summary:     "Enabled capabilities",
+		Description: "Optional features configured in this deployment (file storage and its backend, chunked uploads, virus scanning, Telegram backups, SMS, guarantee and notary integrations)",
+		Tags:        []string{"system"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.Capabilities", Description: ""},
+		},
+	},
+	"getHealth": {
+		Summary:     "Liveness and dependency status",
+		Description: "Always 200 while the API is running. The status is degraded or unavailable when a dependency is down, see /readyz.",
+		Tags:        []string{"system"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.HealthReport", Description: ""},
+		},
+	},
+	"getReadiness": {
+		Summary:     "Readiness with dependency checks",
+		Description: "Checks the database, the file storage bucket, the email driver and the Telegram bot token. 503 when the database or the storage is down.",
+		Tags:        []string{"system"},
+		Produce:     []string{"json"},
+		Responses: []responseDoc{
+			{Code: 200, Kind: "object", Type: "service.HealthReport", Description: ""},
+			{Code: 503, Kind: "object", Type: "service.HealthReport", Description: ""},
+		},
+	},
+}
+
+// docTypes are the Go types the annotations reference, described with reflection
+var docTypes = map[string]reflect.Type{
+	"BatchFilesRequest":                 reflect.TypeOf(BatchFilesRequest{}),
+	"BuildingRequest":                   reflect.TypeOf(BuildingRequest{}),
+	"BuildingResponse":                  reflect.TypeOf(BuildingResponse{}),
+	"CreateChunkedUploadRequest":        reflect.TypeOf(CreateChunkedUploadRequest{}),
+	"CreateInspectionRequest":           reflect.TypeOf(CreateInspectionRequest{}),
+	"EmailTemplatePreviewRequest":       reflect.TypeOf(EmailTemplatePreviewRequest{}),
+	"EmailTemplateRequest":              reflect.TypeOf(EmailTemplateRequest{}),
+	"EmailTestRequest":                  reflect.TypeOf(EmailTestRequest{}),
+	"GenerateUploadLinkRequest":         reflect.TypeOf(GenerateUploadLinkRequest{}),
+	"InvitationRequest":                 reflect.TypeOf(InvitationRequest{}),
+	"IssueSettlementRequest":            reflect.TypeOf(IssueSettlementRequest{}),
+	"ListingApplicationRequest":         reflect.TypeOf(ListingApplicationRequest{}),
+	"ListingRequest":                    reflect.TypeOf(ListingRequest{}),
+	"ListingStatusRequest":              reflect.TypeOf(ListingStatusRequest{}),
+	"LoginRequest":                      reflect.TypeOf(LoginRequest{}),
+	"LoginResponse":                     reflect.TypeOf(LoginResponse{}),
+	"NotificationPreferencesRequest":    reflect.TypeOf(NotificationPreferencesRequest{}),
+	"RefundDepositRequest":              reflect.TypeOf(RefundDepositRequest{}),
+	"RentalPartyRequest":                reflect.TypeOf(RentalPartyRequest{}),
+	"RentalPartyResponse":               reflect.TypeOf(RentalPartyResponse{}),
+	"ReorderPhotosRequest":              reflect.TypeOf(ReorderPhotosRequest{}),
+	"RestoreBucketBackupRequest":        reflect.TypeOf(RestoreBucketBackupRequest{}),
+	"SecurityDepositRequest":            reflect.TypeOf(SecurityDepositRequest{}),
+	"ShareFileRequest":                  reflect.TypeOf(ShareFileRequest{}),
+	"SignerLocation":                    reflect.TypeOf(SignerLocation{}),
+	"StartBucketBackupRequest":          reflect.TypeOf(StartBucketBackupRequest{}),
+	"UnsubscribeRequest":                reflect.TypeOf(UnsubscribeRequest{}),
+	"UpdateFeatureFlagRequest":          reflect.TypeOf(UpdateFeatureFlagRequest{}),
+	"UpdateInspectionRequest":           reflect.TypeOf(UpdateInspectionRequest{}),
+	"UpdatePhotoRequest":                reflect.TypeOf(UpdatePhotoRequest{}),
+	"UpdateUploadLimitRequest":          reflect.TypeOf(UpdateUploadLimitRequest{}),
+	"UploadToken":                       reflect.TypeOf(UploadToken{}),
+	"UserBulkRequest":                   reflect.TypeOf(UserBulkRequest{}),
+	"invitationResponse":                reflect.TypeOf(invitationResponse{}),
+	"model.AuditLog":                    reflect.TypeOf(model.AuditLog{}),
+	"model.BankAccount":                 reflect.TypeOf(model.BankAccount{}),
+	"model.BucketBackup":                reflect.TypeOf(model.BucketBackup{}),
+	"model.Building":                    reflect.TypeOf(model.Building{}),
+	"model.EmailTemplate":               reflect.TypeOf(model.EmailTemplate{}),
+	"model.FeatureFlag":                 reflect.TypeOf(model.FeatureFlag{}),
+	"model.FileMetadata":                reflect.TypeOf(model.FileMetadata{}),
+	"model.FileScan":                    reflect.TypeOf(model.FileScan{}),
+	"model.Inspection":                  reflect.TypeOf(model.Inspection{}),
+	"model.Invitation":                  reflect.TypeOf(model.Invitation{}),
+	"model.LoginAttempt":                reflect.TypeOf(model.LoginAttempt{}),
+	"model.ManagerRegistrationRequest":  reflect.TypeOf(model.ManagerRegistrationRequest{}),
+	"model.ManagerRegistrationResponse": reflect.TypeOf(model.ManagerRegistrationResponse{}),
+	"model.Notification":                reflect.TypeOf(model.Notification{}),
+	"model.PaymentCheckout":             reflect.TypeOf(model.PaymentCheckout{}),
+	"model.Person":                      reflect.TypeOf(model.Person{}),
+	"model.PlatformSubscription":        reflect.TypeOf(model.PlatformSubscription{}),
+	"model.Property":                    reflect.TypeOf(model.Property{}),
+	"model.PropertyListing":             reflect.TypeOf(model.PropertyListing{}),
+	"model.PropertyPhoto":               reflect.TypeOf(model.PropertyPhoto{}),
+	"model.PublicListing":               reflect.TypeOf(model.PublicListing{}),
+	"model.Rental":                      reflect.TypeOf(model.Rental{}),
+	"model.SearchResult":                reflect.TypeOf(model.SearchResult{}),
+	"model.UploadLimit":                 reflect.TypeOf(model.UploadLimit{}),
+	"model.User":                        reflect.TypeOf(model.User{}),
+	"model.UserBulkJob":                 reflect.TypeOf(model.UserBulkJob{}),
+	"service.Capabilities":              reflect.TypeOf(service.Capabilities{}),
+	"service.DashboardSummary":          reflect.TypeOf(service.DashboardSummary{}),
+	"service.GraphQLRequest":            reflect.TypeOf(service.GraphQLRequest{}),
+	"service.GraphQLResponse":           reflect.TypeOf(service.GraphQLResponse{}),
+	"service.HealthReport":              reflect.TypeOf(service.HealthReport{}),
+	"service.InspectionComparison":      reflect.TypeOf(service.InspectionComparison{}),
+	"service.InvitationInput":           reflect.TypeOf(service.InvitationInput{}),
+	"service.LoginStatus":               reflect.TypeOf(service.LoginStatus{}),
+	"service.OccupancyTimeline":         reflect.TypeOf(service.OccupancyTimeline{}),
+	"service.RentCheckout":              reflect.TypeOf(service.RentCheckout{}),
+	"service.StorageUsage":              reflect.TypeOf(service.StorageUsage{}),
+	"service.SupabaseFileInfo":          reflect.TypeOf(service.SupabaseFileInfo{}),
+	"service.SupabaseUploadResponse":    reflect.TypeOf(service.SupabaseUploadResponse{}),
+	"sessionResponse":                   reflect.TypeOf(sessionResponse{}),
+	"storage.RentPayment":               reflect.TypeOf(storage.RentPayment{}),
+}
@@ -0,0 +1,543 @@
+package controller
+
+//go:generate go run ./gen -dir . -out api_docs.gen.go
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/middleware"
+)
+
+// handlerDoc holds the swag annotations of a handler, collected by controller/gen
+type handlerDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string
+	Produce     []string
+	Params      []paramDoc
+	Responses   []responseDoc
+	Headers     []headerDoc
+}
+
+// paramDoc is an @Param annotation
+type paramDoc struct {
+	Name        string
+	In          string // path, query, header, body or formData
+	Type        string
+	Required    bool
+	Description string
+}
+
+// responseDoc is an @Success or @Failure annotation
+type responseDoc struct {
+	Code        int
+	Kind        string // object, array, file or string; empty for responses without a body
+	Type        string
+	Description string
+}
+
+// headerDoc is an @Header annotation
+type headerDoc struct {
+	Code        int
+	Type        string
+	Name        string
+	Description string
+}
+
+// APIDocsController serves the OpenAPI 3 spec of every /api route, built from the registered
+// routes and the annotations of their handlers, and the Swagger UI that renders it
+type APIDocsController struct {
+	spec map[string]any
+}
+
+// NewAPIDocsController builds the spec of routes. authenticated are the routes that require a
+// JWT, by method and path.
+func NewAPIDocsController(routes gin.RoutesInfo, authenticated map[string]bool) *APIDocsController {
+	builder := &specBuilder{
+		schemas:    map[string]any{},
+		deprecated: map[string]bool{},
+	}
+	for _, usage := range middleware.GetDeprecatedRouteUsage() {
+		builder.deprecated[usage.Method+" "+usage.Path] = true
+	}
+	return &APIDocsController{spec: builder.build(routes, authenticated)}
+}
+
+// RegisterPublicRoutes registers the spec and the Swagger UI
+func (c *APIDocsController) RegisterPublicRoutes(router *gin.Engine) {
+	router.GET("/api/docs", c.ServeUI)
+	router.GET("/api/docs/openapi.json", c.ServeSpec)
+}
+
+// ServeSpec returns the OpenAPI 3 spec of the API
+func (c *APIDocsController) ServeSpec(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.spec)
+}
+
+// swaggerUIPage renders the spec with the Swagger UI bundle of the CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="es">
+<head>
+    <meta charset="UTF-8">
+    <title>Rental Manager API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({ url: %q, dom_id: "#swagger-ui", persistAuthorization: true });
+    </script>
+</body>
+</html>`
+
+// ServeUI returns the Swagger UI page
+func (c *APIDocsController) ServeUI(ctx *gin.Context) {
+	page := fmt.Sprintf(swaggerUIPage, strings.TrimSuffix(ctx.Request.URL.Path, "/")+"/openapi.json")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// routeKeys returns the method and path of routes, as used by NewAPIDocsController
+func routeKeys(routes gin.RoutesInfo) map[string]bool {
+	keys := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		keys[route.Method+" "+route.Path] = true
+	}
+	return keys
+}
+
+// specBuilder turns the routes into OpenAPI operations and the Go types of their bodies into
+// component schemas
+type specBuilder struct {
+	schemas      map[string]any
+	deprecated   map[string]bool
+	operationIDs map[string]int
+}
+
+// pathParamPattern matches the :param and *param segments of the Gin paths
+var pathParamPattern = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func (b *specBuilder) build(routes gin.RoutesInfo, authenticated map[string]bool) map[string]any {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	b.operationIDs = map[string]int{}
+	paths := map[string]map[string]any{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") || strings.HasPrefix(route.Path, "/api/docs") {
+			continue
+		}
+		path := pathParamPattern.ReplaceAllString(strings.TrimPrefix(route.Path, "/api"), "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(route.Method)] = b.operation(route, authenticated[route.Method+" "+route.Path])
+	}
+
+	b.schemas["Error"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"error":      map[string]any{"type": "string", "description": "Message in the locale of the request"},
+			"message_id": map[string]any{"type": "string", "description": "ID of the message in the catalogs"},
+			"detail":     map[string]any{"type": "string", "description": "Untranslated detail appended to the message"},
+			"params":     map[string]any{"type": "object", "description": "Data of the message"},
+		},
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Rental Manager API",
+			"version":     "1.0.0",
+			"description": "Todas las rutas públicas y autenticadas del API, generadas a partir de las rutas registradas y las anotaciones de sus handlers. Las rutas autenticadas requieren el JWT de /users/login.",
+		},
+		"servers": []map[string]any{{"url": "/api"}},
+		"paths":   paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+			"schemas": b.schemas,
+		},
+	}
+}
+
+// handlerKey returns the receiver and method of a handler name, such as
+// NotificationController.GetMine for controller.(*NotificationController).GetMine-fm
+func handlerKey(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+	if _, rest, ok := strings.Cut(name, "."); ok {
+		name = rest
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// docOf returns the annotations of a handler; the closures returned by a documented function
+// use its annotations
+func docOf(key string) (handlerDoc, bool) {
+	if doc, ok := handlerDocs[key]; ok {
+		return doc, true
+	}
+	if i := strings.Index(key, ".func"); i > 0 {
+		doc, ok := handlerDocs[key[:i]]
+		return doc, ok
+	}
+	return handlerDoc{}, false
+}
+
+func (b *specBuilder) operation(route gin.RouteInfo, authenticated bool) map[string]any {
+	key := handlerKey(route.Handler)
+	doc, documented := docOf(key)
+
+	operationID := key
+	if b.operationIDs[key]++; b.operationIDs[key] > 1 {
+		operationID += "_" + strconv.Itoa(b.operationIDs[key])
+	}
+
+	tags := doc.Tags
+	if len(tags) == 0 {
+		tags = []string{defaultTag(route.Path)}
+	}
+	op := map[string]any{
+		"operationId": operationID,
+		"tags":        tags,
+	}
+	if doc.Summary != "" {
+		op["summary"] = doc.Summary
+	}
+
+	description := doc.Description
+	if strings.HasPrefix(route.Path, "/api/admin/") {
+		description = strings.TrimSpace(description + " Requires the admin role.")
+	}
+	if description != "" {
+		op["description"] = description
+	}
+	if authenticated {
+		op["security"] = []map[string][]string{{"bearerAuth": {}}}
+	} else {
+		op["security"] = []any{}
+	}
+	if b.deprecated[route.Method+" "+route.Path] {
+		op["deprecated"] = true
+	}
+
+	var parameters []map[string]any
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		parameter := map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		}
+		for _, p := range doc.Params {
+			if p.In == "path" && p.Name == match[1] && p.Description != "" {
+				parameter["description"] = p.Description
+			}
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	formProperties := map[string]any{}
+	var formRequired []string
+	for _, p := range doc.Params {
+		switch p.In {
+		case "query", "header":
+			parameter := map[string]any{
+				"name":     p.Name,
+				"in":       p.In,
+				"required": p.Required,
+				"schema":   b.annotationSchema(p.Type),
+			}
+			if p.Description != "" {
+				parameter["description"] = p.Description
+			}
+			parameters = append(parameters, parameter)
+		case "body":
+			content := map[string]any{}
+			for _, mime := range mimeTypes(doc.Accept, "application/json") {
+				content[mime] = map[string]any{"schema": b.annotationSchema(p.Type)}
+			}
+			body := map[string]any{"required": p.Required, "content": content}
+			if p.Description != "" {
+				body["description"] = p.Description
+			}
+			op["requestBody"] = body
+		case "formData":
+			property := b.annotationSchema(p.Type)
+			if p.Description != "" {
+				property["description"] = p.Description
+			}
+			formProperties[p.Name] = property
+			if p.Required {
+				formRequired = append(formRequired, p.Name)
+			}
+		}
+	}
+	if len(formProperties) > 0 {
+		schema := map[string]any{"type": "object", "properties": formProperties}
+		if len(formRequired) > 0 {
+			schema["required"] = formRequired
+		}
+		op["requestBody"] = map[string]any{
+			"required": len(formRequired) > 0,
+			"content":  map[string]any{"multipart/form-data": map[string]any{"schema": schema}},
+		}
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+	// Lists paged with limit and offset, which the generated clients iterate page by page
+	if hasQueryParam(doc, "limit") && hasQueryParam(doc, "offset") {
+		op["x-paginated"] = true
+	}
+
+	responses := map[string]any{}
+	for _, r := range doc.Responses {
+		responses[strconv.Itoa(r.Code)] = b.response(r, doc)
+	}
+	for _, h := range doc.Headers {
+		response, ok := responses[strconv.Itoa(h.Code)].(map[string]any)
+		if !ok {
+			continue
+		}
+		headers, _ := response["headers"].(map[string]any)
+		if headers == nil {
+			headers = map[string]any{}
+			response["headers"] = headers
+		}
+		headers[h.Name] = map[string]any{"description": h.Description, "schema": b.annotationSchema(h.Type)}
+	}
+	if len(responses) == 0 {
+		description := "Successful response"
+		if !documented {
+			description = "Not documented yet"
+		}
+		responses["200"] = map[string]any{"description": description}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// hasQueryParam reports whether doc has the query parameter name
+func hasQueryParam(doc handlerDoc, name string) bool {
+	for _, p := range doc.Params {
+		if p.In == "query" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// response describes an @Success or @Failure annotation
+func (b *specBuilder) response(r responseDoc, doc handlerDoc) map[string]any {
+	description := r.Description
+	if description == "" {
+		description = http.StatusText(r.Code)
+	}
+	response := map[string]any{"description": description}
+
+	var schema map[string]any
+	mimes := mimeTypes(doc.Produce, "application/json")
+	switch {
+	case r.Kind == "":
+		return response
+	case r.Kind == "file":
+		schema = map[string]any{"type": "string", "format": "binary"}
+		if mimes[0] == "application/json" {
+			mimes = []string{"application/octet-stream"}
+		}
+	case r.Code >= 400 && r.Type == "string":
+		schema = map[string]any{"$ref": "#/components/schemas/Error"}
+		mimes = []string{"application/json"}
+	case r.Kind == "array":
+		schema = map[string]any{"type": "array", "items": b.annotationSchema(r.Type)}
+	default:
+		schema = b.annotationSchema(r.Type)
+	}
+
+	content := map[string]any{}
+	for _, mime := range mimes {
+		content[mime] = map[string]any{"schema": schema}
+	}
+	response["content"] = content
+	return response
+}
+
+// annotationSchema returns the schema of a type written in an annotation
+func (b *specBuilder) annotationSchema(annotation string) map[string]any {
+	switch annotation {
+	case "string":
+		return map[string]any{"type": "string"}
+	case "int", "integer", "int64":
+		return map[string]any{"type": "integer"}
+	case "number", "float64":
+		return map[string]any{"type": "number"}
+	case "bool", "boolean":
+		return map[string]any{"type": "boolean"}
+	case "file", "binary":
+		return map[string]any{"type": "string", "format": "binary"}
+	case "map[string]string":
+		return map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}
+	}
+	if item, ok := strings.CutPrefix(annotation, "[]"); ok {
+		return map[string]any{"type": "array", "items": b.annotationSchema(item)}
+	}
+	if t, ok := docTypes[annotation]; ok {
+		return b.typeSchema(t)
+	}
+	return map[string]any{"type": "object"}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	marshalerType = reflect.TypeOf((*interface{ MarshalJSON() ([]byte, error) })(nil)).Elem()
+)
+
+// typeSchema describes a Go type as its JSON encoding, with the named structs as components
+func (b *specBuilder) typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		if t.Kind() == reflect.Struct || t.Kind() == reflect.String {
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := b.schemas[name]; !ok {
+			// Reserved before the fields are described so recursive types end
+			b.schemas[name] = map[string]any{}
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema describes the JSON object of a struct, with the fields of its embedded structs
+func (b *specBuilder) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	b.addFields(t, properties)
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+func (b *specBuilder) addFields(t reflect.Type, properties map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.addFields(fieldType, properties)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.typeSchema(field.Type)
+	}
+}
+
+// schemaName returns the component name of a named type, such as model.Notification
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	if pkg == "controller" {
+		return t.Name()
+	}
+	return pkg + "." + t.Name()
+}
+
+// mimeTypes expands the swag shorthands of @Accept and @Produce
+func mimeTypes(values []string, fallback string) []string {
+	if len(values) == 0 {
+		return []string{fallback}
+	}
+	mimes := make([]string, 0, len(values))
+	for _, value := range values {
+		switch value {
+		case "json":
+			value = "application/json"
+		case "mpfd":
+			value = "multipart/form-data"
+		case "plain":
+			value = "text/plain"
+		case "html":
+			value = "text/html"
+		case "octet-stream":
+			value = "application/octet-stream"
+		}
+		mimes = append(mimes, value)
+	}
+	return mimes
+}
+
+// defaultTag groups an undocumented route by the first segment of its path after /api, /admin
+// and /public
+func defaultTag(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api"), "/"), "/")
+	for _, segment := range segments {
+		if segment != "admin" && segment != "public" && !strings.HasPrefix(segment, ":") {
+			return segment
+		}
+	}
+	return "api"
+}
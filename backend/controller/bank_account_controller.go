@@ -1,23 +1,23 @@
 package controller
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // BankAccountController handles HTTP requests for bank account entities
 type BankAccountController struct {
-	repository *storage.BankAccountRepository
+	repository storage.BankAccountStore
 }
 
 // NewBankAccountController creates a new BankAccountController
-func NewBankAccountController(repository *storage.BankAccountRepository) *BankAccountController {
+func NewBankAccountController(repository storage.BankAccountStore) *BankAccountController {
 	return &BankAccountController{
 		repository: repository,
 	}
@@ -35,17 +35,17 @@ func (c *BankAccountController) GetAll(ctx *gin.Context) {
 	// Check for admin role
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Admin or manager role required to view all bank accounts"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminOrManagerRequiredForBankAccounts"})
 		return
 	}
 
@@ -75,19 +75,19 @@ func (c *BankAccountController) GetAll(ctx *gin.Context) {
 func (c *BankAccountController) GetByID(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
 	// Authorization: Check if user is authorized to view this account
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
@@ -99,13 +99,13 @@ func (c *BankAccountController) GetByID(ctx *gin.Context) {
 	}
 
 	if account == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Bank account not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "BankAccountNotFound"})
 		return
 	}
 
 	// Admin can view any account, others can only view their own
 	if authUser.Role != "admin" && authUser.PersonID != account.PersonID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access your own bank accounts"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnBankAccountsOnly"})
 		return
 	}
 
@@ -124,24 +124,24 @@ func (c *BankAccountController) GetByID(ctx *gin.Context) {
 func (c *BankAccountController) GetByPersonID(ctx *gin.Context) {
 	personID, err := uuid.Parse(ctx.Param("personId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
 	// Authorization: Check if the authenticated user is the person or an admin
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.PersonID != personID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access your own bank accounts"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnBankAccountsOnly"})
 		return
 	}
 
@@ -177,19 +177,18 @@ func (c *BankAccountController) Create(ctx *gin.Context) {
 	// Authorization: Only admin can create accounts for others
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.PersonID != account.PersonID {
-		log.Printf("User %s (PersonID: %s) attempting to create bank account for PersonID: %s",
-			authUser.Email, authUser.PersonID, account.PersonID)
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only create bank accounts for yourself"})
+		logging.FromContext(ctx).Info("user attempting to create bank account", "email", authUser.Email, "person_id", authUser.PersonID, "account_person_id", account.PersonID)
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnBankAccountsCreateOnly"})
 		return
 	}
 
@@ -221,7 +220,7 @@ func (c *BankAccountController) Create(ctx *gin.Context) {
 func (c *BankAccountController) Update(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -233,24 +232,24 @@ func (c *BankAccountController) Update(ctx *gin.Context) {
 	}
 
 	if existingAccount == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Bank account not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "BankAccountNotFound"})
 		return
 	}
 
 	// Authorization: Check if user is authorized to update this account
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.PersonID != existingAccount.PersonID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only update your own bank accounts"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnBankAccountsUpdateOnly"})
 		return
 	}
 
@@ -288,7 +287,7 @@ func (c *BankAccountController) Delete(ctx *gin.Context) {
 	// This endpoint is registered under AdminMiddleware, so only admins can access it
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -300,7 +299,7 @@ func (c *BankAccountController) Delete(ctx *gin.Context) {
 	}
 
 	if existingAccount == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Bank account not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "BankAccountNotFound"})
 		return
 	}
 
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// BucketBackupController permite a los admins consultar los backups del bucket de archivos,
+// iniciar uno y restaurar archivos desde ellos
+type BucketBackupController struct {
+	backups *service.BucketBackupService
+}
+
+// NewBucketBackupController crea un nuevo BucketBackupController
+func NewBucketBackupController(backups *service.BucketBackupService) *BucketBackupController {
+	return &BucketBackupController{
+		backups: backups,
+	}
+}
+
+// StartBucketBackupRequest estructura para iniciar un backup: full copia todos los archivos
+type StartBucketBackupRequest struct {
+	Full bool `json:"full"`
+}
+
+// RestoreBucketBackupRequest estructura para restaurar archivos de un backup. Sin rutas se
+// restauran todos; sin overwrite no se reemplazan los archivos que ya existen.
+type RestoreBucketBackupRequest struct {
+	Paths     []string `json:"paths"`
+	Overwrite bool     `json:"overwrite"`
+}
+
+// RegisterRoutes registra las rutas de backups del bucket en el grupo de admin
+func (c *BucketBackupController) RegisterRoutes(router *gin.RouterGroup) {
+	backups := router.Group("/file-backups")
+	{
+		backups.GET("", c.HandleListBackups)
+		backups.POST("", c.HandleStartBackup)
+		backups.GET("/:id", c.HandleGetBackup)
+		backups.POST("/:id/restore", c.HandleRestoreBackup)
+	}
+}
+
+// bucketBackupID lee el ID del backup de la ruta
+func bucketBackupID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondBucketBackupError responde con el estado que corresponde a un error de los backups
+func respondBucketBackupError(ctx *gin.Context, err error, messageID string) {
+	switch {
+	case errors.Is(err, service.ErrBucketBackupNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBucketBackupRunning), errors.Is(err, service.ErrBucketBackupDisabled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logging.FromContext(ctx).Error("request failed", "message_id", messageID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": messageID})
+	}
+}
+
+// HandleListBackups lista los últimos backups del bucket
+// @Summary Listar backups del bucket
+// @Description Lista los últimos backups del bucket de archivos, sin sus manifiestos
+// @Tags file-upload
+// @Produce json
+// @Success 200 {array} model.BucketBackup
+// @Router /admin/file-backups [get]
+func (c *BucketBackupController) HandleListBackups(ctx *gin.Context) {
+	backups, err := c.backups.List(ctx)
+	if err != nil {
+		respondBucketBackupError(ctx, err, "FailedToGetBackups")
+		return
+	}
+	ctx.JSON(http.StatusOK, backups)
+}
+
+// HandleStartBackup inicia un backup del bucket en segundo plano
+// @Summary Iniciar backup del bucket
+// @Description Copia a los proveedores de backup los archivos nuevos o modificados desde el último backup, o todos con full
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param request body StartBucketBackupRequest false "Tipo de backup"
+// @Success 202 {object} model.BucketBackup
+// @Router /admin/file-backups [post]
+func (c *BucketBackupController) HandleStartBackup(ctx *gin.Context) {
+	var req StartBucketBackupRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+			return
+		}
+	}
+
+	mode := model.BucketBackupDelta
+	if req.Full {
+		mode = model.BucketBackupFull
+	}
+	backup, err := c.backups.Trigger(mode)
+	if err != nil {
+		respondBucketBackupError(ctx, err, "FailedToStartBackup")
+		return
+	}
+	ctx.JSON(http.StatusAccepted, backup)
+}
+
+// HandleGetBackup devuelve un backup con su manifiesto
+// @Summary Consultar backup del bucket
+// @Description Devuelve el estado de un backup y su manifiesto: ruta, tamaño, hash y ubicación de cada archivo
+// @Tags file-upload
+// @Produce json
+// @Param id path string true "ID del backup"
+// @Success 200 {object} model.BucketBackup
+// @Router /admin/file-backups/{id} [get]
+func (c *BucketBackupController) HandleGetBackup(ctx *gin.Context) {
+	id, ok := bucketBackupID(ctx)
+	if !ok {
+		return
+	}
+	backup, err := c.backups.Get(ctx, id)
+	if err != nil {
+		respondBucketBackupError(ctx, err, "FailedToGetBackup")
+		return
+	}
+	ctx.JSON(http.StatusOK, backup)
+}
+
+// HandleRestoreBackup restaura archivos de un backup a su ruta original
+// @Summary Restaurar archivos de un backup
+// @Description Recupera de los proveedores de backup los archivos indicados, o todos, verifica su hash y los sube a su ruta original
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del backup"
+// @Param request body RestoreBucketBackupRequest false "Archivos a restaurar"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-backups/{id}/restore [post]
+func (c *BucketBackupController) HandleRestoreBackup(ctx *gin.Context) {
+	id, ok := bucketBackupID(ctx)
+	if !ok {
+		return
+	}
+	var req RestoreBucketBackupRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+			return
+		}
+	}
+
+	results, err := c.backups.Restore(ctx, id, req.Paths, req.Overwrite)
+	if err != nil {
+		respondBucketBackupError(ctx, err, "FailedToRestoreBackup")
+		return
+	}
+
+	restored := 0
+	for _, result := range results {
+		if result.Success {
+			restored++
+		}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":  restored == len(results),
+		"restored": restored,
+		"failed":   len(results) - restored,
+		"results":  results,
+	})
+}
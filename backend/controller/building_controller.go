@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// BuildingController handles the buildings (copropiedades) grouping properties under the same
+// administration, whose name, cuota de administración and administrator are printed in the
+// contracts of their properties
+type BuildingController struct {
+	repository     storage.BuildingStore
+	propertyRepo   storage.PropertyStore
+	reglamentoRepo storage.ReglamentoStore
+}
+
+// NewBuildingController creates a new BuildingController
+func NewBuildingController(
+	repository storage.BuildingStore,
+	propertyRepo storage.PropertyStore,
+	reglamentoRepo storage.ReglamentoStore,
+) *BuildingController {
+	return &BuildingController{
+		repository:     repository,
+		propertyRepo:   propertyRepo,
+		reglamentoRepo: reglamentoRepo,
+	}
+}
+
+// BuildingRequest defines the data of a building
+type BuildingRequest struct {
+	Name               string  `json:"name" binding:"required"`
+	Address            string  `json:"address" binding:"required"`
+	City               string  `json:"city" binding:"required"`
+	NIT                string  `json:"nit"`
+	AdminFee           float64 `json:"admin_fee" binding:"gte=0"`
+	AdministratorName  string  `json:"administrator_name"`
+	AdministratorPhone string  `json:"administrator_phone"`
+	AdministratorEmail string  `json:"administrator_email" binding:"omitempty,email"`
+	Notes              string  `json:"notes"`
+}
+
+// BuildingResponse is a building with its properties and the current version of its manual de
+// convivencia
+type BuildingResponse struct {
+	model.Building
+	Properties []model.Property  `json:"properties"`
+	Reglamento *model.Reglamento `json:"reglamento,omitempty"`
+}
+
+// RegisterAdminRoutes registers the building routes on an admin-protected group
+func (c *BuildingController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	buildings := adminRouter.Group("/buildings")
+	{
+		buildings.GET("", c.GetAll)
+		buildings.POST("", c.Create)
+		buildings.GET("/:id", c.GetByID)
+		buildings.PUT("/:id", c.Update)
+		buildings.DELETE("/:id", c.Delete)
+		buildings.PUT("/:id/properties/:propertyId", c.AddProperty)
+		buildings.DELETE("/:id/properties/:propertyId", c.RemoveProperty)
+	}
+}
+
+// GetAll lists the buildings sorted by name
+func (c *BuildingController) GetAll(ctx *gin.Context) {
+	buildings, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if buildings == nil {
+		buildings = []model.Building{}
+	}
+
+	ctx.JSON(http.StatusOK, paginate(ctx, buildings))
+}
+
+// GetByID retrieves a building with its properties and current reglamento
+// @Summary Get a building
+// @Tags buildings
+// @Produce json
+// @Param id path string true "Building ID"
+// @Success 200 {object} BuildingResponse
+// @Router /admin/buildings/{id} [get]
+func (c *BuildingController) GetByID(ctx *gin.Context) {
+	building, ok := c.loadBuilding(ctx)
+	if !ok {
+		return
+	}
+
+	properties, err := c.propertyRepo.GetByBuildingID(ctx, building.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if properties == nil {
+		properties = []model.Property{}
+	}
+
+	response := BuildingResponse{Building: *building, Properties: properties}
+	if reglamento, err := c.reglamentoRepo.GetLatestByBuilding(ctx, building.BuildingKey()); err != nil {
+		logging.FromContext(ctx).Error("error fetching the reglamento of building", "building_id", building.ID, "error", err)
+	} else {
+		response.Reglamento = reglamento
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Create adds a building
+// @Summary Create a building
+// @Description The manual de convivencia of the building is the reglamento uploaded for a property with its address and city
+// @Tags buildings
+// @Accept json
+// @Produce json
+// @Param building body BuildingRequest true "Building data"
+// @Success 201 {object} model.Building
+// @Router /admin/buildings [post]
+func (c *BuildingController) Create(ctx *gin.Context) {
+	var req BuildingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	var building model.Building
+	req.apply(&building)
+	created, err := c.repository.Create(ctx, building)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveBuilding"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Update replaces the data of a building
+func (c *BuildingController) Update(ctx *gin.Context) {
+	building, ok := c.loadBuilding(ctx)
+	if !ok {
+		return
+	}
+
+	var req BuildingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	req.apply(building)
+	updated, err := c.repository.Update(ctx, *building)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveBuilding"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete removes a building, leaving its properties without building
+func (c *BuildingController) Delete(ctx *gin.Context) {
+	building, ok := c.loadBuilding(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.repository.Delete(ctx, building.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.propertyRepo.InvalidateCache() // The properties of the building were detached
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddProperty assigns a property to the building
+func (c *BuildingController) AddProperty(ctx *gin.Context) {
+	building, ok := c.loadBuilding(ctx)
+	if !ok {
+		return
+	}
+	property, ok := c.loadProperty(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.propertyRepo.SetBuilding(ctx, property.ID, &building.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	property.BuildingID = &building.ID
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+// RemoveProperty detaches a property from the building
+func (c *BuildingController) RemoveProperty(ctx *gin.Context) {
+	building, ok := c.loadBuilding(ctx)
+	if !ok {
+		return
+	}
+	property, ok := c.loadProperty(ctx)
+	if !ok {
+		return
+	}
+	if property.BuildingID == nil || *property.BuildingID != building.ID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotInBuilding"})
+		return
+	}
+
+	if err := c.propertyRepo.SetBuilding(ctx, property.ID, nil); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// apply copies the request into a building
+func (req BuildingRequest) apply(building *model.Building) {
+	building.Name = strings.TrimSpace(req.Name)
+	building.Address = strings.TrimSpace(req.Address)
+	building.City = strings.TrimSpace(req.City)
+	building.NIT = strings.TrimSpace(req.NIT)
+	building.AdminFee = req.AdminFee
+	building.AdministratorName = strings.TrimSpace(req.AdministratorName)
+	building.AdministratorPhone = strings.TrimSpace(req.AdministratorPhone)
+	building.AdministratorEmail = strings.TrimSpace(req.AdministratorEmail)
+	building.Notes = strings.TrimSpace(req.Notes)
+}
+
+// loadBuilding reads the building in the id path parameter, responding with an error when it
+// does not exist
+func (c *BuildingController) loadBuilding(ctx *gin.Context) (*model.Building, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	building, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if building == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "BuildingNotFound"})
+		return nil, false
+	}
+	return building, true
+}
+
+// loadProperty reads the property in the propertyId path parameter
+func (c *BuildingController) loadProperty(ctx *gin.Context) (*model.Property, bool) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return nil, false
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return nil, false
+	}
+	return property, true
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/service"
+)
+
+// getCapabilities lists the optional features enabled in this deployment
+// @Summary Enabled capabilities
+// @Description Optional features configured in this deployment (file storage and its backend, chunked uploads, virus scanning, Telegram backups, SMS, guarantee and notary integrations)
+// @Tags system
+// @Produce json
+// @Success 200 {object} service.Capabilities
+// @Router /capabilities [get]
+func getCapabilities(cfg *config.Config, virusScans *service.VirusScanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, service.CurrentCapabilities(cfg, virusScans))
+	}
+}
+
+// respondCapabilityUnavailable answers 503 for a feature that is not configured in this
+// deployment. The capability is named so clients can hide the feature, see /api/capabilities.
+func respondCapabilityUnavailable(c *gin.Context, capability, messageID string) {
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":      messageID,
+		"capability": capability,
+		"enabled":    false,
+	})
+}
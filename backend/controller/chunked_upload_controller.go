@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// Headers del protocolo de subida por partes (estilo tus)
+const (
+	uploadOffsetHeader = "Upload-Offset"
+	uploadLengthHeader = "Upload-Length"
+	uploadTokenHeader  = "Upload-Token"
+)
+
+// CreateChunkedUploadRequest estructura para iniciar una subida por partes
+type CreateChunkedUploadRequest struct {
+	Token       string   `json:"token"` // Solo para subidas con enlace de subida
+	FileName    string   `json:"file_name" binding:"required"`
+	Size        int64    `json:"size" binding:"required"`
+	ContentType string   `json:"content_type"`
+	Category    string   `json:"category"` // cedula, contrato, comprobante_pago, certificado_laboral u otro
+	Tags        []string `json:"tags"`
+	RentalID    string   `json:"rental_id"`   // Arriendo al que se vincula el archivo
+	ContractID  string   `json:"contract_id"` // Contrato al que se vincula el archivo
+}
+
+// chunkedUploader identifica a quien sube el archivo: el usuario autenticado o el token del enlace
+type chunkedUploader struct {
+	owner    string
+	userID   string
+	userName string
+	role     string
+	token    *UploadToken
+}
+
+// resolveChunkedUploader obtiene el usuario autenticado o valida el token de subida,
+// respondiendo con el error si no es válido
+func (ctrl *FileUploadController) resolveChunkedUploader(ctx *gin.Context, token string) (*chunkedUploader, bool) {
+	if userInterface, exists := ctx.Get("user"); exists {
+		authUser, ok := userInterface.(*model.User)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+			return nil, false
+		}
+		return &chunkedUploader{
+			owner:    "user:" + authUser.ID.String(),
+			userID:   authUser.ID.String(),
+			userName: authUser.Email,
+			role:     authUser.Role,
+		}, true
+	}
+
+	if token == "" {
+		token = ctx.GetHeader(uploadTokenHeader)
+	}
+	if token == "" {
+		token = ctx.Query("token")
+	}
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "TokenRequired"})
+		return nil, false
+	}
+
+	uploadToken, exists := uploadTokens[token]
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenInvalid"})
+		return nil, false
+	}
+	if time.Now().After(uploadToken.ExpiresAt) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenExpired"})
+		return nil, false
+	}
+	if uploadToken.Used {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenAlreadyUsed"})
+		return nil, false
+	}
+
+	return &chunkedUploader{
+		owner:    "token:" + token,
+		userID:   uploadToken.UserID,
+		userName: uploadToken.Email,
+		role:     ctrl.userRole(ctx, uploadToken.UserID),
+		token:    uploadToken,
+	}, true
+}
+
+// getOwnedChunkedUpload obtiene una subida por partes que pertenezca a quien hace la petición
+func (ctrl *FileUploadController) getOwnedChunkedUpload(ctx *gin.Context) (*service.ChunkedUpload, *chunkedUploader, bool) {
+	uploader, ok := ctrl.resolveChunkedUploader(ctx, "")
+	if !ok {
+		return nil, nil, false
+	}
+
+	upload, err := ctrl.chunkedUploads.Get(ctx.Param("id"))
+	if err != nil || upload.Owner != uploader.owner {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": service.ErrChunkedUploadNotFound.Error()})
+		return nil, nil, false
+	}
+	return upload, uploader, true
+}
+
+// chunkedUploadResponse agrega a la subida los datos que el cliente necesita para continuar
+func (ctrl *FileUploadController) chunkedUploadResponse(upload *service.ChunkedUpload) gin.H {
+	return gin.H{
+		"id":             upload.ID,
+		"file_name":      upload.FileName,
+		"content_type":   upload.ContentType,
+		"size":           upload.Size,
+		"offset":         upload.Offset,
+		"complete":       upload.Complete(),
+		"max_chunk_size": ctrl.chunkedUploads.MaxChunkSize(),
+		"created_at":     upload.CreatedAt,
+		"expires_at":     upload.ExpiresAt,
+	}
+}
+
+// setChunkedUploadHeaders informa el progreso en los headers del protocolo
+func setChunkedUploadHeaders(ctx *gin.Context, upload *service.ChunkedUpload) {
+	ctx.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	ctx.Header(uploadLengthHeader, strconv.FormatInt(upload.Size, 10))
+	ctx.Header("Cache-Control", "no-store")
+}
+
+// HandleCreateChunkedUpload inicia una subida por partes para archivos grandes
+// @Summary Iniciar subida por partes
+// @Description Crea una sesión para subir un archivo grande en partes que se pueden reanudar
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param request body CreateChunkedUploadRequest true "Datos del archivo"
+// @Success 201 {object} map[string]interface{}
+// @Router /upload/chunked [post]
+func (ctrl *FileUploadController) HandleCreateChunkedUpload(ctx *gin.Context) {
+	var req CreateChunkedUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	uploader, ok := ctrl.resolveChunkedUploader(ctx, req.Token)
+	if !ok {
+		return
+	}
+
+	// Validar tipo de archivo
+	if err := validateFileType(req.FileName, req.ContentType); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	metadata, ok := ctrl.resolveFileMetadata(ctx, uploader.userID, FileMetadataRequest{
+		Category:   req.Category,
+		Tags:       req.Tags,
+		RentalID:   req.RentalID,
+		ContractID: req.ContractID,
+	})
+	if !ok {
+		return
+	}
+
+	if service.GetSupabaseStorageService() == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	upload, err := ctrl.chunkedUploads.Create(req.FileName, req.ContentType, req.Size, uploader.userID, uploader.userName, uploader.role, uploader.owner)
+	if err != nil {
+		var limitErr *service.UploadLimitError
+		if errors.As(err, &limitErr) {
+			respondUploadError(ctx, err)
+			return
+		}
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrChunkedUploadTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		ctx.JSON(status, gin.H{"error": err.Error(), "max_size": ctrl.chunkedUploads.MaxSize()})
+		return
+	}
+
+	if err := ctrl.chunkedUploads.SetMetadata(upload.ID, metadata); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	setChunkedUploadHeaders(ctx, upload)
+	ctx.Header("Location", ctx.Request.URL.Path+"/"+upload.ID)
+	ctx.JSON(http.StatusCreated, ctrl.chunkedUploadResponse(upload))
+}
+
+// HandleGetChunkedUpload devuelve el progreso de una subida para poder reanudarla
+// @Summary Consultar subida por partes
+// @Description Devuelve el offset desde el que se debe enviar la siguiente parte
+// @Tags file-upload
+// @Produce json
+// @Param id path string true "ID de la subida"
+// @Success 200 {object} map[string]interface{}
+// @Router /upload/chunked/{id} [get]
+func (ctrl *FileUploadController) HandleGetChunkedUpload(ctx *gin.Context) {
+	upload, _, ok := ctrl.getOwnedChunkedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	setChunkedUploadHeaders(ctx, upload)
+	if ctx.Request.Method == http.MethodHead {
+		ctx.Status(http.StatusOK)
+		return
+	}
+	ctx.JSON(http.StatusOK, ctrl.chunkedUploadResponse(upload))
+}
+
+// HandleUploadChunk recibe una parte del archivo. El header Upload-Offset debe coincidir con
+// los bytes ya recibidos y el cuerpo es el contenido binario de la parte.
+// @Summary Subir parte
+// @Description Agrega una parte al archivo a partir del offset indicado en Upload-Offset
+// @Tags file-upload
+// @Accept application/offset+octet-stream
+// @Produce json
+// @Param id path string true "ID de la subida"
+// @Success 200 {object} map[string]interface{}
+// @Router /upload/chunked/{id} [patch]
+func (ctrl *FileUploadController) HandleUploadChunk(ctx *gin.Context) {
+	upload, _, ok := ctrl.getOwnedChunkedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UploadOffsetRequired"})
+		return
+	}
+
+	upload, err = ctrl.chunkedUploads.WriteChunk(upload.ID, offset, ctx.Request.Body)
+	if upload != nil {
+		setChunkedUploadHeaders(ctx, upload)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrChunkedUploadNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrChunkedUploadOffset), errors.Is(err, service.ErrChunkedUploadBusy):
+			current, _ := ctrl.chunkedUploads.Get(ctx.Param("id"))
+			response := gin.H{"error": err.Error()}
+			if current != nil {
+				setChunkedUploadHeaders(ctx, current)
+				response["offset"] = current.Offset
+			}
+			ctx.JSON(http.StatusConflict, response)
+		case errors.Is(err, service.ErrChunkedUploadTooLarge):
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "ChunkTooLarge", "offset": upload.Offset, "max_chunk_size": ctrl.chunkedUploads.MaxChunkSize()})
+		default:
+			logging.FromContext(ctx).Error("error recibiendo parte de la subida", "id", ctx.Param("id"), "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "ChunkReceiveFailed", "offset": upload.Offset})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ctrl.chunkedUploadResponse(upload))
+}
+
+// HandleCompleteChunkedUpload ensambla el archivo recibido y lo sube a Supabase Storage
+// @Summary Completar subida por partes
+// @Description Sube a Supabase el archivo ensamblado una vez recibidas todas las partes
+// @Tags file-upload
+// @Produce json
+// @Param id path string true "ID de la subida"
+// @Success 200 {object} service.SupabaseUploadResponse
+// @Router /upload/chunked/{id}/complete [post]
+func (ctrl *FileUploadController) HandleCompleteChunkedUpload(ctx *gin.Context) {
+	upload, uploader, ok := ctrl.getOwnedChunkedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	uploadResponse, err := ctrl.chunkedUploads.Finish(upload.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrChunkedUploadIncomplete), errors.Is(err, service.ErrChunkedUploadBusy):
+			setChunkedUploadHeaders(ctx, upload)
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error(), "offset": upload.Offset, "size": upload.Size})
+		case errors.Is(err, service.ErrChunkedUploadNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			logging.FromContext(ctx).Error("error subiendo archivo ensamblado", "upload_id", upload.ID, "error", err)
+			respondUploadError(ctx, err)
+		}
+		return
+	}
+
+	ctrl.recordFileMetadata(ctx, uploadResponse, uploader.userID, upload.Metadata)
+
+	// Marcar token como usado
+	if uploader.token != nil {
+		uploader.token.Used = true
+	}
+
+	logging.FromContext(ctx).Info("archivo subido por partes", "file_name", upload.FileName, "user_name", uploader.userName)
+
+	ctx.JSON(http.StatusOK, uploadResponse)
+}
+
+// HandleAbortChunkedUpload cancela una subida y elimina las partes recibidas
+// @Summary Cancelar subida por partes
+// @Tags file-upload
+// @Param id path string true "ID de la subida"
+// @Success 204
+// @Router /upload/chunked/{id} [delete]
+func (ctrl *FileUploadController) HandleAbortChunkedUpload(ctx *gin.Context) {
+	upload, _, ok := ctrl.getOwnedChunkedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	ctrl.chunkedUploads.Abort(upload.ID)
+	ctx.Status(http.StatusNoContent)
+}
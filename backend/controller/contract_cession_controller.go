@@ -0,0 +1,522 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ContractCessionController handles the cession of rental contracts to a new owner, e.g. when
+// the owner sells the property
+type ContractCessionController struct {
+	repository      storage.ContractCessionStore
+	rentalRepo      storage.RentalStore
+	propertyRepo    storage.PropertyStore
+	personRepo      storage.PersonStore
+	userRepo        storage.UserStore
+	bankAccountRepo storage.BankAccountStore
+	paymentRepo     storage.RentPaymentStore
+	cessionService  *service.ContractCessionService
+	orgService      *service.OrganizationService
+}
+
+// NewContractCessionController creates a new ContractCessionController
+func NewContractCessionController(
+	repository storage.ContractCessionStore,
+	rentalRepo storage.RentalStore,
+	propertyRepo storage.PropertyStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+	bankAccountRepo storage.BankAccountStore,
+	paymentRepo storage.RentPaymentStore,
+	cessionService *service.ContractCessionService,
+	orgService *service.OrganizationService,
+) *ContractCessionController {
+	return &ContractCessionController{
+		repository:      repository,
+		rentalRepo:      rentalRepo,
+		propertyRepo:    propertyRepo,
+		personRepo:      personRepo,
+		userRepo:        userRepo,
+		bankAccountRepo: bankAccountRepo,
+		paymentRepo:     paymentRepo,
+		cessionService:  cessionService,
+		orgService:      orgService,
+	}
+}
+
+// ContractCessionRequest defines the new owner of a rental and the account the rent is paid to
+// from the effective date. The new owner and the account are registered when no ID is given.
+type ContractCessionRequest struct {
+	NewOwnerID      string             `json:"new_owner_id"`
+	NewOwner        *model.Person      `json:"new_owner"`
+	BankAccountID   string             `json:"bank_account_id"`
+	BankAccount     *model.BankAccount `json:"bank_account"`
+	PreviousOwnerID string             `json:"previous_owner_id"`                 // Defaults to the holder of the rental's bank account
+	EffectiveDate   string             `json:"effective_date" binding:"required"` // YYYY-MM-DD
+	Reason          string             `json:"reason"`                            // sale (default) or cession
+}
+
+// RegisterRoutes registers the contract cession routes on an admin-protected group
+func (c *ContractCessionController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.POST("/contracts/:id/cession", c.Create)
+	adminRouter.GET("/contracts/:id/cessions", c.GetByRentalID)
+	adminRouter.GET("/contracts/:id/owner-payments", c.GetPaymentsByOwner)
+	adminRouter.GET("/contract-cessions/:id/letter", c.ServeLetter)
+}
+
+// Create registers the cession of a rental to a new owner, notifies the tenant with the
+// cession letter and switches the rent to the new owner's account from the effective date
+func (c *ContractCessionController) Create(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	var req ContractCessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	// The cession takes effect at the start of the day in the timezone of the organization
+	effectiveDate, err := time.ParseInLocation("2006-01-02", req.EffectiveDate, service.OrganizationLocation(c.orgService.ForRental(ctx, rentalID)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidEffectiveDate"})
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = model.CessionReasonSale
+	}
+	if req.Reason != model.CessionReasonSale && req.Reason != model.CessionReasonCession {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCessionReason"})
+		return
+	}
+
+	rental, err := c.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rental == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return
+	}
+	if effectiveDate.After(rental.EndDate.Time()) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "EffectiveDateAfterRentalEnd"})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, rental.PropertyID)
+	if err != nil || property == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentedProperty"})
+		return
+	}
+	tenant, err := c.personRepo.GetByID(ctx, rental.RenterID)
+	if err != nil || tenant == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetTenant"})
+		return
+	}
+
+	// The previous owner receives the rent until the effective date
+	cessions, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, existing := range cessions {
+		if !existing.EffectiveDate.Before(effectiveDate) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "CessionAlreadyEffective", "cession": existing})
+			return
+		}
+	}
+
+	previousOwner, ok := c.previousOwner(ctx, req, rental, property, cessions)
+	if !ok {
+		return
+	}
+
+	newOwner, ok := c.newOwner(ctx, req)
+	if !ok {
+		return
+	}
+	if newOwner.ID == previousOwner.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "NewOwnerAlreadyOwner"})
+		return
+	}
+
+	bankAccount, ok := c.newOwnerBankAccount(ctx, req, newOwner)
+	if !ok {
+		return
+	}
+
+	var createdBy uuid.UUID
+	if authUser, exists := ctx.Get("user"); exists {
+		if user, ok := authUser.(*model.User); ok {
+			createdBy = user.PersonID
+		}
+	}
+
+	previousBankAccountID := rental.BankAccountID
+	if len(cessions) > 0 {
+		previousBankAccountID = cessions[len(cessions)-1].NewBankAccountID
+	}
+
+	cession, err := c.repository.Create(ctx, model.ContractCession{
+		ID:                    uuid.New(),
+		RentalID:              rental.ID,
+		PreviousOwnerID:       previousOwner.ID,
+		NewOwnerID:            newOwner.ID,
+		PreviousBankAccountID: previousBankAccountID,
+		NewBankAccountID:      bankAccount.ID,
+		Reason:                req.Reason,
+		EffectiveDate:         effectiveDate,
+		CreatedBy:             createdBy,
+		CreatedAt:             time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveCession"})
+		return
+	}
+
+	letter := service.ContractCessionLetter{
+		Cession:            *cession,
+		Rental:             rental,
+		Property:           property,
+		Tenant:             tenant,
+		PreviousOwner:      previousOwner,
+		PreviousOwnerEmail: c.personEmail(ctx, previousOwner.ID),
+		NewOwner:           newOwner,
+		NewOwnerEmail:      c.personEmail(ctx, newOwner.ID),
+		BankAccount:        bankAccount,
+		IssuedAt:           time.Now(),
+	}
+	letterPDF, err := service.GenerateCessionLetterPDF(letter)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating cession letter of rental", "rental_id", rental.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "CessionLetterFailed"})
+		return
+	}
+
+	cession.LetterPath, err = storeCessionLetter(cession, letterPDF)
+	if err != nil {
+		logging.FromContext(ctx).Error("error storing cession letter", "cession_id", cession.ID, "error", err)
+	}
+
+	// The cession is effective for the tenant once notified
+	if tenantEmail := c.personEmail(ctx, tenant.ID); tenantEmail != "" {
+		if err := service.SendCessionLetterEmail(tenantEmail, tenant.FullName, newOwner.FullName, effectiveDate, letterPDF); err != nil {
+			logging.FromContext(ctx).Error("error sending cession letter", "tenant_email", tenantEmail, "error", err)
+		} else {
+			now := time.Now()
+			cession.NotifiedAt = &now
+		}
+	}
+	if err := c.repository.SetLetter(ctx, cession.ID, cession.LetterPath, cession.NotifiedAt); err != nil {
+		logging.FromContext(ctx).Error("error saving letter of cession", "cession_id", cession.ID, "error", err)
+	}
+
+	// Cessions effective today or earlier switch the billing account right away, later ones
+	// are applied by the nightly job
+	if cession.IsEffective(time.Now()) {
+		if err := c.cessionService.Apply(ctx, *cession, time.Now()); err != nil {
+			logging.FromContext(ctx).Error("error applying cession", "cession_id", cession.ID, "error", err)
+		} else {
+			now := time.Now()
+			cession.AppliedAt = &now
+		}
+	}
+
+	logging.FromContext(ctx).Info("rental ceded", "rental_id", rental.ID, "previous_owner", previousOwner.FullName, "new_owner", newOwner.FullName, "effective_date", service.FormatDate(effectiveDate))
+	ctx.JSON(http.StatusCreated, cessionResponse(*cession))
+}
+
+// GetByRentalID lists the cessions of a rental with the owner on each period
+func (c *ContractCessionController) GetByRentalID(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	cessions, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(cessions))
+	for _, cession := range cessions {
+		response = append(response, cessionResponse(cession))
+	}
+
+	currentOwner, _ := model.OwnerAt(cessions, time.Now())
+	ctx.JSON(http.StatusOK, gin.H{
+		"rental_id":     rentalID,
+		"current_owner": currentOwner,
+		"cessions":      response,
+	})
+}
+
+// GetPaymentsByOwner groups the payments of a rental by the owner they were made to: payments
+// before a cession stay attributed to the previous owner
+func (c *ContractCessionController) GetPaymentsByOwner(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	cessions, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	payments, err := c.paymentRepo.GetByRentalID(rentalID.String())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Rentals never ceded belong to the holder of their bank account
+	defaultOwner := uuid.Nil
+	if len(cessions) == 0 {
+		if rental, err := c.rentalRepo.GetByID(ctx, rentalID); err == nil && rental != nil {
+			if account, err := c.bankAccountRepo.GetByID(ctx, rental.BankAccountID); err == nil && account != nil {
+				defaultOwner = account.PersonID
+			}
+		}
+	}
+
+	var ownerOrder []uuid.UUID
+	byOwner := make(map[uuid.UUID][]storage.RentPayment)
+	totals := make(map[uuid.UUID]float64)
+	for _, payment := range payments {
+		owner, ok := model.OwnerAt(cessions, payment.PaymentDate.Time())
+		if !ok {
+			owner = defaultOwner
+		}
+		if _, seen := byOwner[owner]; !seen {
+			ownerOrder = append(ownerOrder, owner)
+		}
+		byOwner[owner] = append(byOwner[owner], payment)
+		totals[owner] += payment.AmountPaid
+	}
+
+	owners := make([]gin.H, 0, len(ownerOrder))
+	for _, ownerID := range ownerOrder {
+		owner := gin.H{
+			"owner_id": ownerID,
+			"payments": byOwner[ownerID],
+			"total":    totals[ownerID],
+		}
+		if person, err := c.personRepo.GetByID(ctx, ownerID); err == nil && person != nil {
+			owner["owner_name"] = person.FullName
+		}
+		owners = append(owners, owner)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"rental_id": rentalID,
+		"owners":    owners,
+	})
+}
+
+// ServeLetter serves the cession notification letter sent to the tenant
+func (c *ContractCessionController) ServeLetter(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	cession, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if cession == nil || cession.LetterPath == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "CessionLetterNotFound"})
+		return
+	}
+
+	pdfData, err := loadStoredPDF(cession.LetterPath)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading letter of cession", "cession_id", cession.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadCessionLetter"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename=notificacion_cesion.pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// previousOwner resolves who cedes the contract: the requested person, the new owner of the
+// last cession, or the holder of the rental's bank account. It writes the error response when
+// none is found.
+func (c *ContractCessionController) previousOwner(ctx *gin.Context, req ContractCessionRequest, rental *model.Rental, property *model.Property, cessions []model.ContractCession) (*model.Person, bool) {
+	ownerID := uuid.Nil
+	switch {
+	case req.PreviousOwnerID != "":
+		id, err := uuid.Parse(req.PreviousOwnerID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPreviousOwnerID"})
+			return nil, false
+		}
+		ownerID = id
+	case len(cessions) > 0:
+		ownerID = cessions[len(cessions)-1].NewOwnerID
+	default:
+		if account, err := c.bankAccountRepo.GetByID(ctx, rental.BankAccountID); err == nil && account != nil {
+			ownerID = account.PersonID
+		} else if len(property.ManagerIDs) > 0 {
+			ownerID = property.ManagerIDs[0]
+		}
+	}
+
+	if ownerID == uuid.Nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RentalOwnerUnknown"})
+		return nil, false
+	}
+	owner, err := c.personRepo.GetByID(ctx, ownerID)
+	if err != nil || owner == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PreviousOwnerNotFound"})
+		return nil, false
+	}
+	return owner, true
+}
+
+// newOwner loads the new owner, registering them when only their data is sent
+func (c *ContractCessionController) newOwner(ctx *gin.Context, req ContractCessionRequest) (*model.Person, bool) {
+	if req.NewOwnerID != "" {
+		ownerID, err := uuid.Parse(req.NewOwnerID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidNewOwnerID"})
+			return nil, false
+		}
+		owner, err := c.personRepo.GetByID(ctx, ownerID)
+		if err != nil || owner == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "NewOwnerNotFound"})
+			return nil, false
+		}
+		return owner, true
+	}
+
+	if req.NewOwner == nil || strings.TrimSpace(req.NewOwner.FullName) == "" || strings.TrimSpace(req.NewOwner.NIT) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CessionNewOwnerRequired"})
+		return nil, false
+	}
+
+	person := *req.NewOwner
+	person.ID = uuid.New()
+	owner, err := c.personRepo.Create(ctx, person)
+	if err != nil || owner == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRegisterNewOwner"})
+		return nil, false
+	}
+	return owner, true
+}
+
+// newOwnerBankAccount loads the account of the new owner the rent is paid to, registering it
+// when only its data is sent
+func (c *ContractCessionController) newOwnerBankAccount(ctx *gin.Context, req ContractCessionRequest, owner *model.Person) (*model.BankAccount, bool) {
+	if req.BankAccountID != "" {
+		accountID, err := uuid.Parse(req.BankAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidBankAccountID"})
+			return nil, false
+		}
+		account, err := c.bankAccountRepo.GetByID(ctx, accountID)
+		if err != nil || account == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "BankAccountNotFound"})
+			return nil, false
+		}
+		if account.PersonID != owner.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "BankAccountNotOfNewOwner"})
+			return nil, false
+		}
+		return account, true
+	}
+
+	if req.BankAccount == nil || req.BankAccount.BankName == "" || req.BankAccount.AccountNumber == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CessionBankAccountRequired"})
+		return nil, false
+	}
+
+	account := *req.BankAccount
+	account.ID = uuid.New()
+	account.PersonID = owner.ID
+	if account.AccountHolder == "" {
+		account.AccountHolder = owner.FullName
+	}
+	created, err := c.bankAccountRepo.Create(ctx, account)
+	if err != nil || created == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRegisterNewOwnerBankAccount"})
+		return nil, false
+	}
+	return created, true
+}
+
+// personEmail returns the email of the user of a person, empty when they have no user
+func (c *ContractCessionController) personEmail(ctx *gin.Context, personID uuid.UUID) string {
+	if user, err := c.userRepo.GetByPersonID(ctx, personID); err == nil && user != nil {
+		return user.Email
+	}
+	return ""
+}
+
+// storeCessionLetter saves the cession letter next to the contract documents and returns its path
+func storeCessionLetter(cession *model.ContractCession, letterPDF []byte) (string, error) {
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		filePath := fmt.Sprintf("contracts/%s/%s_cession.pdf", cession.RentalID, cession.ID)
+		if _, err := storageService.UploadBytes(filePath, letterPDF, "application/pdf"); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "contracts")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", err
+	}
+	letterPath := filepath.Join(tempDir, cession.ID.String()+"_cession.pdf")
+	if err := os.WriteFile(letterPath, letterPDF, 0644); err != nil {
+		return "", err
+	}
+	return letterPath, nil
+}
+
+// cessionResponse adds the letter URL to a cession
+func cessionResponse(cession model.ContractCession) gin.H {
+	response := gin.H{
+		"id":                       cession.ID,
+		"rental_id":                cession.RentalID,
+		"previous_owner_id":        cession.PreviousOwnerID,
+		"new_owner_id":             cession.NewOwnerID,
+		"previous_bank_account_id": cession.PreviousBankAccountID,
+		"new_bank_account_id":      cession.NewBankAccountID,
+		"reason":                   cession.Reason,
+		"effective_date":           cession.EffectiveDate,
+		"notified_at":              cession.NotifiedAt,
+		"applied_at":               cession.AppliedAt,
+		"created_by":               cession.CreatedBy,
+		"created_at":               cession.CreatedAt,
+	}
+	if cession.LetterPath != "" {
+		response["letter_url"] = "/api/admin/contract-cessions/" + cession.ID.String() + "/letter"
+	}
+	return response
+}
@@ -1,12 +1,13 @@
 package controller
 
 import (
-	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
@@ -14,35 +15,121 @@ import (
 
 // ContractController handles contract-related operations
 type ContractController struct {
-	personRepo   *storage.PersonRepository
-	propertyRepo *storage.PropertyRepository
-	pricingRepo  *storage.PricingRepository
+	personRepo        storage.PersonStore
+	propertyRepo      storage.PropertyStore
+	pricingRepo       storage.PricingStore
+	rentalRepo        storage.RentalStore
+	rentalHistoryRepo storage.RentalHistoryStore
+	userRepo          storage.UserStore
+	signingRepo       storage.ContractSigningStore
+	templateRepo      storage.ContractTemplateStore
+	inventoryRepo     storage.InventoryStore
+	promotionRepo     storage.PromotionStore
+	guaranteeRepo     storage.GuaranteeStudyStore
+	partyRepo         storage.RentalPartyStore
+	buildingRepo      storage.BuildingStore
+	orgService        *service.OrganizationService
+	webhooks          *service.SigningWebhookDispatcher
+	// renewalIPC is the yearly increase (IPC) percentage renewals of housing are capped at
+	renewalIPC float64
 }
 
 // NewContractController creates a new ContractController
-func NewContractController(personRepo *storage.PersonRepository, propertyRepo *storage.PropertyRepository, pricingRepo *storage.PricingRepository) *ContractController {
+func NewContractController(
+	personRepo storage.PersonStore,
+	propertyRepo storage.PropertyStore,
+	pricingRepo storage.PricingStore,
+	rentalRepo storage.RentalStore,
+	rentalHistoryRepo storage.RentalHistoryStore,
+	userRepo storage.UserStore,
+	signingRepo storage.ContractSigningStore,
+	templateRepo storage.ContractTemplateStore,
+	inventoryRepo storage.InventoryStore,
+	promotionRepo storage.PromotionStore,
+	guaranteeRepo storage.GuaranteeStudyStore,
+	partyRepo storage.RentalPartyStore,
+	buildingRepo storage.BuildingStore,
+	orgService *service.OrganizationService,
+	webhooks *service.SigningWebhookDispatcher,
+	renewalIPC *float64,
+) *ContractController {
 	return &ContractController{
-		personRepo:   personRepo,
-		propertyRepo: propertyRepo,
-		pricingRepo:  pricingRepo,
+		personRepo:        personRepo,
+		propertyRepo:      propertyRepo,
+		pricingRepo:       pricingRepo,
+		rentalRepo:        rentalRepo,
+		rentalHistoryRepo: rentalHistoryRepo,
+		userRepo:          userRepo,
+		signingRepo:       signingRepo,
+		templateRepo:      templateRepo,
+		inventoryRepo:     inventoryRepo,
+		promotionRepo:     promotionRepo,
+		guaranteeRepo:     guaranteeRepo,
+		partyRepo:         partyRepo,
+		buildingRepo:      buildingRepo,
+		orgService:        orgService,
+		webhooks:          webhooks,
+		renewalIPC:        service.RenewalIncreasePercentage(renewalIPC),
 	}
 }
 
 // GenerateContractRequest defines the request structure for generating a contract
 type GenerateContractRequest struct {
-	RenterID         string    `json:"renter_id" binding:"required"`
-	OwnerID          string    `json:"owner_id" binding:"required"`
-	PropertyID       string    `json:"property_id" binding:"required"`
-	CoSignerID       string    `json:"cosigner_id"` // Optional
-	WitnessID        string    `json:"witness_id"`  // Optional
-	StartDate        time.Time `json:"start_date" binding:"required"`
-	EndDate          time.Time `json:"end_date" binding:"required"`
-	ContractDuration string    `json:"contract_duration"`
-	MonthlyRent      float64   `json:"monthly_rent" binding:"required"`
-	RequiresDeposit  bool      `json:"requires_deposit"`
-	DepositAmount    float64   `json:"deposit_amount"`
-	DepositText      string    `json:"deposit_text"`
-	AdditionalInfo   string    `json:"additional_info"`
+	RenterID         string                   `json:"renter_id" binding:"required"`
+	OwnerID          string                   `json:"owner_id" binding:"required"`
+	PropertyID       string                   `json:"property_id" binding:"required"`
+	CoSignerID       string                   `json:"cosigner_id"` // Optional
+	WitnessID        string                   `json:"witness_id"`  // Optional
+	StartDate        time.Time                `json:"start_date" binding:"required"`
+	EndDate          time.Time                `json:"end_date" binding:"required"`
+	ContractDuration string                   `json:"contract_duration"`
+	MonthlyRent      float64                  `json:"monthly_rent" binding:"required"`
+	CanonTaxable     bool                     `json:"canon_taxable"`
+	Components       []model.PricingComponent `json:"components"` // Administración, parking and other monthly charges
+	Currency         string                   `json:"currency"`   // Currency of the amounts, COP if empty
+	RequiresDeposit  bool                     `json:"requires_deposit"`
+	DepositAmount    float64                  `json:"deposit_amount"`
+	DepositText      string                   `json:"deposit_text"`
+	AdditionalInfo   string                   `json:"additional_info"`
+	TemplateID       string                   `json:"template_id"`        // Optional, defaults to the template of the property managers
+	ContractType     string                   `json:"contract_type"`      // Optional, defaults to the type matching the property
+	SkipPromotions   bool                     `json:"skip_promotions"`    // Do not apply the active promotions of the property
+	GuaranteeStudyID string                   `json:"guarantee_study_id"` // Optional approved afianzadora study, its policy substitutes the cosigner
+	RentalID         string                   `json:"rental_id"`          // Optional, its parties fill the cosigner and witness not sent
+}
+
+// RenewContractRequest defines the optional parameters for renewing a contract.
+// Omitted values fall back to the increase policy of the pricing and the length of the previous term.
+type RenewContractRequest struct {
+	IncreasePercentage *float64 `json:"increase_percentage"` // One-off fixed increase overriding the policy
+	NewMonthlyRent     *float64 `json:"new_monthly_rent"`    // Required by the manual policy
+	DurationMonths     int      `json:"duration_months"`
+	OwnerID            string   `json:"owner_id"` // Defaults to the first manager of the property
+	ExpirationDays     int      `json:"expiration_days"`
+}
+
+// TransferContractRequest defines the parameters for moving the tenant of a contract to another
+// property of the same portfolio. Omitted dates start the new rental today and keep the length
+// of the previous term.
+type TransferContractRequest struct {
+	PropertyID        string     `json:"property_id" binding:"required"`
+	StartDate         *time.Time `json:"start_date"`
+	EndDate           *time.Time `json:"end_date"`
+	DurationMonths    int        `json:"duration_months"`
+	MonthlyRent       float64    `json:"monthly_rent" binding:"required"`
+	DepositAdjustment float64    `json:"deposit_adjustment"` // Positive when paid by the tenant, negative when refunded
+	AdjustmentReason  string     `json:"adjustment_reason"`
+	TemplateID        string     `json:"template_id"`   // Optional, defaults to the template of the property managers
+	ContractType      string     `json:"contract_type"` // Optional, defaults to the type matching the property
+	OwnerID           string     `json:"owner_id"`      // Defaults to the first manager of the new property
+	ExpirationDays    int        `json:"expiration_days"`
+}
+
+// RentalChainLink is one rental of the chain of renewals and transfers of a tenant
+type RentalChainLink struct {
+	Rental   *model.Rental          `json:"rental"`
+	Property *model.Property        `json:"property,omitempty"`
+	History  *storage.RentalHistory `json:"history,omitempty"` // How the rental ended, nil for the current one
 }
 
 // RegisterRoutes registers the contract routes
@@ -50,6 +137,9 @@ func (ctrl *ContractController) RegisterRoutes(router *gin.RouterGroup) {
 	contractRoutes := router.Group("/contracts")
 	{
 		contractRoutes.POST("/generate", ctrl.HandleGenerateContract)
+		contractRoutes.POST("/:id/renew", ctrl.HandleRenewContract)
+		contractRoutes.POST("/:id/transfer", ctrl.HandleTransferContract)
+		contractRoutes.GET("/:id/chain", ctrl.HandleGetRentalChain)
 	}
 }
 
@@ -57,62 +147,70 @@ func (ctrl *ContractController) RegisterRoutes(router *gin.RouterGroup) {
 func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 	var req GenerateContractRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+		return
+	}
+	if err := model.ValidatePricingComponents(req.Components); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidCurrency(req.Currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCurrency"})
 		return
 	}
 
 	// Parse IDs
 	renterID, err := uuid.Parse(req.RenterID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid renter ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRenterID"})
 		return
 	}
 
 	ownerID, err := uuid.Parse(req.OwnerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidOwnerID"})
 		return
 	}
 
 	propertyID, err := uuid.Parse(req.PropertyID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid property ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyID"})
 		return
 	}
 
 	// Get renter
 	renter, err := ctrl.personRepo.GetByID(c, renterID)
 	if err != nil {
-		log.Printf("Error getting renter: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get renter details"})
+		logging.FromContext(c).Error("error getting renter", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRenterDetails"})
 		return
 	}
 	if renter == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Renter not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "RenterNotFound"})
 		return
 	}
 
 	// Get owner
 	owner, err := ctrl.personRepo.GetByID(c, ownerID)
 	if err != nil {
-		log.Printf("Error getting owner: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get owner details"})
+		logging.FromContext(c).Error("error getting owner", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetOwnerDetails"})
 		return
 	}
 	if owner == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Owner not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "OwnerNotFound"})
 		return
 	}
 
 	// Get property
 	property, err := ctrl.propertyRepo.GetByID(c, propertyID)
 	if err != nil {
-		log.Printf("Error getting property: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get property details"})
+		logging.FromContext(c).Error("error getting property", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPropertyDetails"})
 		return
 	}
 	if property == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Property not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
 		return
 	}
 
@@ -121,6 +219,9 @@ func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 		ID:              uuid.New(),
 		MonthlyRent:     req.MonthlyRent,
 		SecurityDeposit: req.DepositAmount,
+		CanonTaxable:    req.CanonTaxable,
+		Components:      req.Components,
+		Currency:        req.Currency,
 	}
 
 	// Get cosigner if provided
@@ -128,18 +229,52 @@ func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 	if req.CoSignerID != "" {
 		coSignerID, err := uuid.Parse(req.CoSignerID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cosigner ID"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCosignerID"})
 			return
 		}
 
 		cosigner, err = ctrl.personRepo.GetByID(c, coSignerID)
 		if err != nil {
-			log.Printf("Error getting cosigner: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cosigner details"})
+			logging.FromContext(c).Error("error getting cosigner", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetCosignerDetails"})
 			return
 		}
 		if cosigner == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Cosigner not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "CosignerNotFound"})
+			return
+		}
+	}
+
+	// Get the guarantee policy if provided, it substitutes the cosigner
+	var guarantee *model.GuaranteeStudy
+	if req.GuaranteeStudyID != "" {
+		if cosigner != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CosignerOrGuaranteeStudy"})
+			return
+		}
+
+		studyID, err := uuid.Parse(req.GuaranteeStudyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidGuaranteeStudyID"})
+			return
+		}
+
+		guarantee, err = ctrl.guaranteeRepo.GetByID(c, studyID)
+		if err != nil {
+			logging.FromContext(c).Error("error getting guarantee study", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetGuaranteeStudy"})
+			return
+		}
+		if guarantee == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "GuaranteeStudyNotFound"})
+			return
+		}
+		if guarantee.RenterID != renter.ID || guarantee.PropertyID != property.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "GuaranteeStudyOfAnotherRental"})
+			return
+		}
+		if !guarantee.HasPolicy() {
+			c.JSON(http.StatusConflict, gin.H{"error": "GuaranteeStudyNotApproved"})
 			return
 		}
 	}
@@ -149,20 +284,98 @@ func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 	if req.WitnessID != "" {
 		witnessID, err := uuid.Parse(req.WitnessID)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid witness ID"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidWitnessID"})
 			return
 		}
 
 		witness, err = ctrl.personRepo.GetByID(c, witnessID)
 		if err != nil {
-			log.Printf("Error getting witness: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get witness details"})
+			logging.FromContext(c).Error("error getting witness", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetWitnessDetails"})
 			return
 		}
 		if witness == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Witness not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "WitnessNotFound"})
+			return
+		}
+	}
+
+	// The parties recorded for the rental fill the cosigner and witness not sent, and the
+	// contract is signed as the rental so its parties are printed again when signed
+	var rental *model.Rental
+	if req.RentalID != "" {
+		rentalID, err := uuid.Parse(req.RentalID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalID"})
+			return
+		}
+
+		rental, err = ctrl.rentalRepo.GetByID(c, rentalID)
+		if err != nil {
+			logging.FromContext(c).Error("error getting rental", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentalDetails"})
+			return
+		}
+		if rental == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+			return
+		}
+		if rental.RenterID != renter.ID || rental.PropertyID != property.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "RentalOfAnotherRenter"})
+			return
+		}
+
+		parties := ctrl.rentalParties(c, rental.ID)
+		if cosigner == nil && guarantee == nil {
+			cosigner = ctrl.partyPerson(c, parties, model.RentalPartyCoSigner)
+		}
+		if witness == nil {
+			witness = ctrl.partyPerson(c, parties, model.RentalPartyWitness)
+		}
+	}
+
+	// Get the selected template, or the default one of the property managers for the contract type
+	if !model.IsValidContractType(req.ContractType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractType", "detail": strings.Join(model.ContractTypes, ", ")})
+		return
+	}
+	contractType := req.ContractType
+	if contractType == "" {
+		contractType = service.ContractTypeForProperty(property.Type)
+	}
+
+	var template *model.ContractTemplate
+	if req.TemplateID != "" {
+		templateID, err := uuid.Parse(req.TemplateID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidTemplateID"})
+			return
+		}
+
+		template, err = ctrl.templateRepo.GetByID(c, templateID)
+		if err != nil {
+			logging.FromContext(c).Error("error getting contract template", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetContractTemplate"})
 			return
 		}
+		if template == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+			return
+		}
+	} else {
+		template = ctrl.defaultTemplate(c, property, contractType)
+	}
+
+	// Active promotions of the listing are worded in the contract
+	var promotions []model.Promotion
+	if !req.SkipPromotions {
+		promotions = ctrl.activePromotions(c, property.ID)
+	}
+	offer := service.ApplyPromotions(pricing.MonthlyRent, pricing.SecurityDeposit, promotions)
+	pricing.SecurityDeposit = offer.SecurityDeposit
+	depositText := req.DepositText
+	if waived := service.PromotionDepositText(offer); waived != "" {
+		depositText = waived
 	}
 
 	// Create contract data
@@ -170,27 +383,47 @@ func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 		Renter:         renter,
 		Owner:          owner,
 		Property:       property,
+		Building:       ctrl.propertyBuilding(c, property),
 		Pricing:        &pricing,
 		CoSigner:       cosigner,
 		Witness:        witness,
+		RenterEmail:    ctrl.personEmail(c, renter),
+		OwnerEmail:     ctrl.personEmail(c, owner),
+		CoSignerEmail:  ctrl.personEmail(c, cosigner),
+		WitnessEmail:   ctrl.personEmail(c, witness),
+		Template:       template,
 		StartDate:      req.StartDate,
 		EndDate:        req.EndDate,
 		AdditionalInfo: req.AdditionalInfo,
 		CreationDate:   time.Now(),
-		DepositText:    req.DepositText,
+		DepositText:    depositText,
+		Inventory:      ctrl.propertyInventory(c, property.ID),
+		Promotions:     promotions,
+		Guarantee:      guarantee,
+		Organization:   ctrl.orgService.ForProperty(c, property.ID),
 	}
 
-	// Generate a contract ID
+	// Generate a contract ID, the rental ID when the contract belongs to one
 	contractID := uuid.New().String()
+	if rental != nil {
+		contractID = rental.ID.String()
+	}
 
 	// Generate PDF
 	pdfBytes, err := service.GenerateContractPDF(contractData)
 	if err != nil {
-		log.Printf("Error generating contract PDF: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate contract"})
+		logging.FromContext(c).Error("error generating contract PDF", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateContract"})
 		return
 	}
 
+	ctrl.recordPromotionOffers(c, promotions, property.ID, renter.ID, contractID)
+
+	// Keep a copy so the contract can be sent for signing with X-Contract-ID
+	if _, err := service.SaveTempPDF(pdfBytes, contractID); err != nil {
+		logging.FromContext(c).Warn("failed to keep contract PDF for signing", "contract_id", contractID, "error", err)
+	}
+
 	// Set response headers for PDF download
 	fileName := "contrato_arrendamiento.pdf"
 	c.Header("Content-Disposition", "attachment; filename="+fileName)
@@ -198,3 +431,974 @@ func (ctrl *ContractController) HandleGenerateContract(c *gin.Context) {
 	c.Header("X-Contract-ID", contractID)
 	c.Data(http.StatusOK, "application/pdf", pdfBytes)
 }
+
+// HandleRenewContract renews a rental contract: it creates the next rental term with the
+// yearly increase applied, regenerates the contract PDF and sends it to the renter for signing
+func (ctrl *ContractController) HandleRenewContract(c *gin.Context) {
+	rentalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractID"})
+		return
+	}
+
+	// The body is optional
+	var req RenewContractRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+			return
+		}
+	}
+
+	if req.ExpirationDays <= 0 {
+		req.ExpirationDays = 7 // Same default as regular signing requests
+	}
+
+	// Get the current term
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting rental", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetContractDetails"})
+		return
+	}
+	if rental == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ContractNotFound"})
+		return
+	}
+
+	pricing, err := ctrl.pricingRepo.GetByRentalID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting pricing", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPricingDetails"})
+		return
+	}
+	if pricing == nil || pricing.MonthlyRent <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ContractHasNoMonthlyRent"})
+		return
+	}
+
+	renter, err := ctrl.personRepo.GetByID(c, rental.RenterID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting renter", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRenterDetails"})
+		return
+	}
+	if renter == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "RenterNotFound"})
+		return
+	}
+
+	renterUser, err := ctrl.userRepo.GetByPersonID(c, rental.RenterID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting renter user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRenterUserDetails"})
+		return
+	}
+	if renterUser == nil || renterUser.Email == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "RenterEmailNotFound"})
+		return
+	}
+
+	property, err := ctrl.propertyRepo.GetByID(c, rental.PropertyID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting property", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPropertyDetails"})
+		return
+	}
+	if property == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	// Get owner, defaulting to the owner party of the contract or the first manager of the property
+	parties := ctrl.rentalParties(c, rental.ID)
+	var ownerID uuid.UUID
+	if req.OwnerID != "" {
+		ownerID, err = uuid.Parse(req.OwnerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidOwnerID"})
+			return
+		}
+	} else if partyOwnerID := model.FirstRentalParty(parties, model.RentalPartyOwner); partyOwnerID != uuid.Nil {
+		ownerID = partyOwnerID
+	} else if len(property.ManagerIDs) > 0 {
+		ownerID = property.ManagerIDs[0]
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "PropertyHasNoManager"})
+		return
+	}
+
+	owner, err := ctrl.personRepo.GetByID(c, ownerID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting owner", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetOwnerDetails"})
+		return
+	}
+	if owner == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OwnerNotFound"})
+		return
+	}
+
+	// Compute the new canon from the pricing policy
+	policyPricing := *pricing
+	if req.IncreasePercentage != nil {
+		policyPricing.IncreasePolicy = model.IncreasePolicyFixed
+		policyPricing.IncreasePercentage = *req.IncreasePercentage
+	}
+	increase, err := service.CalculateRentIncrease(policyPricing, property.Type, req.NewMonthlyRent, ctrl.renewalIPC)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CannotComputeRentIncrease", "detail": err.Error()})
+		return
+	}
+
+	// Create the new rental term
+	previousStart := rental.StartDate.Time()
+	previousEnd := rental.EndDate.Time()
+	durationMonths := req.DurationMonths
+	if durationMonths <= 0 {
+		durationMonths = service.TermLengthInMonths(previousStart, previousEnd)
+	}
+	newStart, newEnd := service.NextRentalTerm(previousEnd, durationMonths)
+
+	newRental := model.Rental{
+		ID:            uuid.New(),
+		PropertyID:    rental.PropertyID,
+		RenterID:      rental.RenterID,
+		BankAccountID: rental.BankAccountID,
+		StartDate:     model.FlexibleTime(newStart),
+		EndDate:       model.FlexibleTime(newEnd),
+		PaymentTerms:  rental.PaymentTerms,
+	}
+
+	createdRental, err := ctrl.rentalRepo.Create(c, newRental)
+	if err != nil {
+		logging.FromContext(c).Error("error creating renewed rental", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateNewRentalTerm"})
+		return
+	}
+	if createdRental == nil {
+		// The insert does not always return the row
+		createdRental = &newRental
+	}
+
+	newPricing := *pricing
+	newPricing.ID = uuid.New()
+	newPricing.RentalID = createdRental.ID
+	newPricing.MonthlyRent = increase.NewRent
+
+	createdPricing, err := ctrl.pricingRepo.Create(c, newPricing)
+	if err != nil {
+		logging.FromContext(c).Error("error creating renewed pricing", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateNewRentalTermPricing"})
+		return
+	}
+
+	// Close the previous term in the rental history, keeping the increase calculation, and
+	// open the new one
+	service.GetRentalHistory().RentalRenewed(*rental, *createdRental, increase)
+
+	// The parties of the contract stay the same in the new term
+	ctrl.copyRentalParties(c, parties, createdRental.ID)
+	guarantee := ctrl.approvedGuarantee(c, renter.ID, property.ID)
+	var cosigner *model.Person
+	if guarantee == nil {
+		cosigner = ctrl.partyPerson(c, parties, model.RentalPartyCoSigner)
+	}
+	witness := ctrl.partyPerson(c, parties, model.RentalPartyWitness)
+
+	renewal := &service.ContractRenewal{
+		PreviousRentalID:   rental.ID.String(),
+		PreviousStartDate:  previousStart,
+		PreviousEndDate:    previousEnd,
+		PreviousRent:       pricing.MonthlyRent,
+		IncreasePercentage: increase.AppliedPercentage,
+		NewRent:            createdPricing.MonthlyRent,
+	}
+
+	org := ctrl.orgService.ForProperty(c, rental.PropertyID)
+	pdfBytes, err := service.GenerateContractPDF(service.ContractPDF{
+		Renter:        renter,
+		Owner:         owner,
+		Property:      property,
+		Building:      ctrl.propertyBuilding(c, property),
+		Pricing:       createdPricing,
+		CoSigner:      cosigner,
+		Witness:       witness,
+		RenterEmail:   renterUser.Email,
+		OwnerEmail:    ctrl.personEmail(c, owner),
+		CoSignerEmail: ctrl.personEmail(c, cosigner),
+		WitnessEmail:  ctrl.personEmail(c, witness),
+		Template:      ctrl.defaultTemplate(c, property, service.ContractTypeForProperty(property.Type)),
+		StartDate:     newStart,
+		EndDate:       newEnd,
+		CreationDate:  time.Now(),
+		Renewal:       renewal,
+		Inventory:     ctrl.propertyInventory(c, property.ID),
+		Guarantee:     guarantee,
+		Organization:  org,
+	})
+	if err != nil {
+		logging.FromContext(c).Error("error generating renewed contract PDF", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateRenewedContract"})
+		return
+	}
+
+	// Kick off the signing of the renewed contract
+	signingRequest, err := service.CreateSignatureRequest(model.ContractSigningInfo{
+		ContractID:     createdRental.ID.String(),
+		RecipientID:    renter.ID.String(),
+		RecipientEmail: renterUser.Email,
+		PDFData:        pdfBytes,
+		SignerName:     renter.FullName,
+		BaseURL:        service.OrganizationBaseURL(org),
+		RequestedBy:    requesterPersonID(c),
+		Organization:   org,
+	}, req.ExpirationDays)
+	if err != nil {
+		logging.FromContext(c).Error("error creating signature request for renewed contract", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "NewRentalTermSignatureRequestFailed"})
+		return
+	}
+
+	if ctrl.signingRepo != nil {
+		if _, err := ctrl.signingRepo.CreateSigningRequest(c, *signingRequest); err != nil {
+			logging.FromContext(c).Error("error saving signature request to database", "error", err)
+			// Continue anyway since the email has been sent
+		} else {
+			ctrl.webhooks.Dispatch(model.WebhookEventSigningCreated, signingRequest.ID)
+		}
+	}
+
+	// The cosigners sign the renewed contract too, as deudores solidarios
+	var cosignerSigningIDs []string
+	if cosigner != nil {
+		for _, party := range parties {
+			if party.Role != model.RentalPartyCoSigner {
+				continue
+			}
+			if signingID := ctrl.requestPartySignature(c, createdRental, party.PersonID, pdfBytes, req.ExpirationDays); signingID != "" {
+				cosignerSigningIDs = append(cosignerSigningIDs, signingID)
+			}
+		}
+	}
+
+	logging.FromContext(c).Info("contract renewed with rent increase", "rental_id", rental.ID, "created_rental_id", createdRental.ID, "applied_percentage", increase.AppliedPercentage, "previous_rent", service.FormatAmount(pricing.Money(pricing.MonthlyRent)), "new_rent", service.FormatAmount(createdPricing.Money(createdPricing.MonthlyRent)))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":               "Contract renewed and signature request sent",
+		"previous_rental_id":    rental.ID,
+		"rental":                createdRental,
+		"pricing":               createdPricing,
+		"previous_rent":         pricing.MonthlyRent,
+		"increase_percentage":   increase.AppliedPercentage,
+		"rent_increase":         increase,
+		"signing_id":            signingRequest.ID,
+		"cosigner_signing_ids":  cosignerSigningIDs,
+		"expires_at":            signingRequest.ExpiresAt,
+		"previous_rent_display": service.FormatAmount(pricing.Money(pricing.MonthlyRent)),
+		"new_rent_display":      service.FormatAmount(createdPricing.Money(createdPricing.MonthlyRent)),
+		"expires_at_display":    service.FormatDate(signingRequest.ExpiresAt),
+	})
+}
+
+// HandleTransferContract moves the tenant of a contract to another property of the same portfolio:
+// it ends the current rental the day before the move, carries the deposit over with the given
+// adjustment, creates the new rental and sends its contract to the renter for signing
+func (ctrl *ContractController) HandleTransferContract(c *gin.Context) {
+	rentalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractID"})
+		return
+	}
+
+	var req TransferContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+		return
+	}
+	if req.MonthlyRent <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "MonthlyRentMustBeAboveZero"})
+		return
+	}
+	if !model.IsValidContractType(req.ContractType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractType", "detail": strings.Join(model.ContractTypes, ", ")})
+		return
+	}
+	if req.ExpirationDays <= 0 {
+		req.ExpirationDays = 7 // Same default as regular signing requests
+	}
+
+	newPropertyID, err := uuid.Parse(req.PropertyID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyID"})
+		return
+	}
+
+	// Get the current rental
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting rental", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetContractDetails"})
+		return
+	}
+	if rental == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ContractNotFound"})
+		return
+	}
+	if rental.PropertyID == newPropertyID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "TenantAlreadyRentsProperty"})
+		return
+	}
+
+	pricing, err := ctrl.pricingRepo.GetByRentalID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting pricing", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPricingDetails"})
+		return
+	}
+	if pricing == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ContractHasNoPricing"})
+		return
+	}
+
+	previousProperty, err := ctrl.propertyRepo.GetByID(c, rental.PropertyID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting property", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPropertyDetails"})
+		return
+	}
+	if previousProperty == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	property, err := ctrl.propertyRepo.GetByID(c, newPropertyID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting property", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPropertyDetails"})
+		return
+	}
+	if property == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "TargetPropertyNotFound"})
+		return
+	}
+
+	// Transfers stay within the portfolio of the managers of the current property
+	if !sharesManager(previousProperty, property) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "TargetPropertyInAnotherPortfolio"})
+		return
+	}
+
+	renter, err := ctrl.personRepo.GetByID(c, rental.RenterID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting renter", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRenterDetails"})
+		return
+	}
+	if renter == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "RenterNotFound"})
+		return
+	}
+
+	renterUser, err := ctrl.userRepo.GetByPersonID(c, rental.RenterID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting renter user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRenterUserDetails"})
+		return
+	}
+	if renterUser == nil || renterUser.Email == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "RenterEmailNotFound"})
+		return
+	}
+
+	// Get owner, defaulting to the first manager of the new property
+	var ownerID uuid.UUID
+	if req.OwnerID != "" {
+		ownerID, err = uuid.Parse(req.OwnerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidOwnerID"})
+			return
+		}
+	} else {
+		ownerID = property.ManagerIDs[0]
+	}
+
+	owner, err := ctrl.personRepo.GetByID(c, ownerID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting owner", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetOwnerDetails"})
+		return
+	}
+	if owner == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "OwnerNotFound"})
+		return
+	}
+
+	// Compute the term of the new rental
+	previousStart := rental.StartDate.Time()
+	previousEnd := rental.EndDate.Time()
+	newStart := time.Now().In(service.AppLocation())
+	newStart = time.Date(newStart.Year(), newStart.Month(), newStart.Day(), 0, 0, 0, 0, newStart.Location())
+	if req.StartDate != nil {
+		newStart = *req.StartDate
+	}
+	if !newStart.After(previousStart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "TransferBeforeContractStart"})
+		return
+	}
+
+	var newEnd time.Time
+	if req.EndDate != nil {
+		newEnd = *req.EndDate
+	} else {
+		durationMonths := req.DurationMonths
+		if durationMonths <= 0 {
+			durationMonths = service.TermLengthInMonths(previousStart, previousEnd)
+		}
+		newEnd = service.TermEndDate(newStart, durationMonths)
+	}
+	if !newEnd.After(newStart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "EndDateBeforeStartDate"})
+		return
+	}
+
+	// The target property must be free during the new term
+	propertyRentals, err := ctrl.rentalRepo.GetByPropertyID(c, property.ID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting rentals of property", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCheckTargetPropertyAvailability"})
+		return
+	}
+	for _, existing := range propertyRentals {
+		if service.RentalsOverlap(existing, newStart, newEnd) {
+			c.JSON(http.StatusConflict, gin.H{"error": "TargetPropertyAlreadyRented", "rental_id": existing.ID})
+			return
+		}
+	}
+
+	deposit, err := service.CalculateDepositTransfer(pricing.SecurityDeposit, req.DepositAdjustment, req.AdjustmentReason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDepositAdjustment", "detail": err.Error()})
+		return
+	}
+
+	contractType := req.ContractType
+	if contractType == "" {
+		contractType = service.ContractTypeForProperty(property.Type)
+	}
+
+	var template *model.ContractTemplate
+	if req.TemplateID != "" {
+		templateID, err := uuid.Parse(req.TemplateID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidTemplateID"})
+			return
+		}
+
+		template, err = ctrl.templateRepo.GetByID(c, templateID)
+		if err != nil {
+			logging.FromContext(c).Error("error getting contract template", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetContractTemplate"})
+			return
+		}
+		if template == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+			return
+		}
+	} else {
+		template = ctrl.defaultTemplate(c, property, contractType)
+	}
+
+	// Create the new rental
+	newRental := model.Rental{
+		ID:            uuid.New(),
+		PropertyID:    property.ID,
+		RenterID:      rental.RenterID,
+		BankAccountID: rental.BankAccountID,
+		StartDate:     model.FlexibleTime(newStart),
+		EndDate:       model.FlexibleTime(newEnd),
+		PaymentTerms:  rental.PaymentTerms,
+	}
+
+	createdRental, err := ctrl.rentalRepo.Create(c, newRental)
+	if err != nil {
+		logging.FromContext(c).Error("error creating transferred rental", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateNewRental"})
+		return
+	}
+	if createdRental == nil {
+		// The insert does not always return the row
+		createdRental = &newRental
+	}
+
+	newPricing := *pricing
+	newPricing.ID = uuid.New()
+	newPricing.RentalID = createdRental.ID
+	newPricing.MonthlyRent = req.MonthlyRent
+	newPricing.SecurityDeposit = deposit.NewDeposit
+
+	createdPricing, err := ctrl.pricingRepo.Create(c, newPricing)
+	if err != nil {
+		logging.FromContext(c).Error("error creating transferred pricing", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateNewRentalPricing"})
+		return
+	}
+
+	// End the previous rental the day before the move
+	previousEndDate := service.TransferEndDate(newStart)
+	if previousEndDate.Before(previousEnd) {
+		endedRental := *rental
+		endedRental.EndDate = model.FlexibleTime(previousEndDate)
+		if _, err := ctrl.rentalRepo.Update(c, endedRental); err != nil {
+			logging.FromContext(c).Error("error ending transferred rental", "rental_id", rental.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "PreviousRentalNotEnded"})
+			return
+		}
+	}
+
+	// Close the previous rental in the history, linking the new one
+	service.GetRentalHistory().RentalTransferred(*rental, *createdRental, previousEndDate, property.Address, deposit)
+
+	org := ctrl.orgService.ForProperty(c, property.ID)
+	pdfBytes, err := service.GenerateContractPDF(service.ContractPDF{
+		Renter:       renter,
+		Owner:        owner,
+		Property:     property,
+		Building:     ctrl.propertyBuilding(c, property),
+		Pricing:      createdPricing,
+		RenterEmail:  renterUser.Email,
+		OwnerEmail:   ctrl.personEmail(c, owner),
+		Template:     template,
+		StartDate:    newStart,
+		EndDate:      newEnd,
+		CreationDate: time.Now(),
+		Transfer: &service.ContractTransfer{
+			PreviousRentalID:        rental.ID.String(),
+			PreviousPropertyAddress: previousProperty.Address,
+			TransferDate:            newStart,
+			Deposit:                 *deposit,
+		},
+		Inventory:    ctrl.propertyInventory(c, property.ID),
+		Organization: org,
+	})
+	if err != nil {
+		logging.FromContext(c).Error("error generating transferred contract PDF", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateNewContract"})
+		return
+	}
+
+	// Kick off the signing of the new contract
+	signingRequest, err := service.CreateSignatureRequest(model.ContractSigningInfo{
+		ContractID:     createdRental.ID.String(),
+		RecipientID:    renter.ID.String(),
+		RecipientEmail: renterUser.Email,
+		PDFData:        pdfBytes,
+		SignerName:     renter.FullName,
+		BaseURL:        service.OrganizationBaseURL(org),
+		RequestedBy:    requesterPersonID(c),
+		Organization:   org,
+	}, req.ExpirationDays)
+	if err != nil {
+		logging.FromContext(c).Error("error creating signature request for transferred contract", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "NewRentalSignatureRequestFailed"})
+		return
+	}
+
+	if ctrl.signingRepo != nil {
+		if _, err := ctrl.signingRepo.CreateSigningRequest(c, *signingRequest); err != nil {
+			logging.FromContext(c).Error("error saving signature request to database", "error", err)
+			// Continue anyway since the email has been sent
+		} else {
+			ctrl.webhooks.Dispatch(model.WebhookEventSigningCreated, signingRequest.ID)
+		}
+	}
+
+	logging.FromContext(c).Info("tenant transferred to another property", "rental_id", rental.ID, "previous_property_id", previousProperty.ID, "property_id", property.ID, "created_rental_id", createdRental.ID, "previous_deposit", service.FormatMoney(deposit.PreviousDeposit), "new_deposit", service.FormatMoney(deposit.NewDeposit))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":              "Tenant transferred and signature request sent",
+		"previous_rental_id":   rental.ID,
+		"previous_end_date":    previousEndDate,
+		"rental":               createdRental,
+		"pricing":              createdPricing,
+		"deposit_transfer":     deposit,
+		"signing_id":           signingRequest.ID,
+		"expires_at":           signingRequest.ExpiresAt,
+		"new_deposit_display":  service.FormatMoney(deposit.NewDeposit),
+		"new_rent_display":     service.FormatAmount(createdPricing.Money(createdPricing.MonthlyRent)),
+		"expires_at_display":   service.FormatDate(signingRequest.ExpiresAt),
+		"previous_end_display": service.FormatDate(previousEndDate),
+	})
+}
+
+// HandleGetRentalChain returns the chain of rentals linked to a contract through renewals and
+// transfers, oldest first, so the full tenancy of a renter can be reported
+func (ctrl *ContractController) HandleGetRentalChain(c *gin.Context) {
+	rentalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractID"})
+		return
+	}
+
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting rental", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetContractDetails"})
+		return
+	}
+	if rental == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ContractNotFound"})
+		return
+	}
+
+	// Walk back to the first rental of the chain
+	visited := map[uuid.UUID]bool{rental.ID: true}
+	first := rental
+	for {
+		previous, err := ctrl.rentalHistoryRepo.GetByNextRentalID(first.ID.String())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentalHistory"})
+			return
+		}
+		if len(previous) == 0 {
+			break
+		}
+
+		previousID, err := uuid.Parse(previous[0].RentalID)
+		if err != nil || visited[previousID] {
+			break
+		}
+		previousRental, err := ctrl.rentalRepo.GetByID(c, previousID)
+		if err != nil || previousRental == nil {
+			break
+		}
+		visited[previousID] = true
+		first = previousRental
+	}
+
+	// Walk forward following the next rental of each history record
+	properties := map[uuid.UUID]*model.Property{}
+	chain := []RentalChainLink{}
+	current := first
+	seen := map[uuid.UUID]bool{}
+	for current != nil && !seen[current.ID] {
+		seen[current.ID] = true
+		link := RentalChainLink{Rental: current}
+
+		if _, ok := properties[current.PropertyID]; !ok {
+			property, err := ctrl.propertyRepo.GetByID(c, current.PropertyID)
+			if err != nil {
+				logging.FromContext(c).Warn("could not load property of the rental chain", "property_id", current.PropertyID, "error", err)
+			}
+			properties[current.PropertyID] = property
+		}
+		link.Property = properties[current.PropertyID]
+
+		histories, err := ctrl.rentalHistoryRepo.GetByRentalID(current.ID.String())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentalHistory"})
+			return
+		}
+
+		var next *model.Rental
+		for i := range histories {
+			if histories[i].NextRentalID == "" {
+				continue
+			}
+			link.History = &histories[i]
+
+			nextID, err := uuid.Parse(histories[i].NextRentalID)
+			if err != nil {
+				break
+			}
+			next, err = ctrl.rentalRepo.GetByID(c, nextID)
+			if err != nil {
+				logging.FromContext(c).Warn("could not load rental of the rental chain", "next_id", nextID, "error", err)
+			}
+			break
+		}
+
+		chain = append(chain, link)
+		current = next
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rental_id": rental.ID,
+		"renter_id": rental.RenterID,
+		"chain":     chain,
+	})
+}
+
+// sharesManager reports whether two properties have a manager in common
+func sharesManager(a, b *model.Property) bool {
+	for _, managerA := range a.ManagerIDs {
+		for _, managerB := range b.ManagerIDs {
+			if managerA == managerB {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// defaultTemplate returns the default template of the property managers for a contract type,
+// falling back to the shared default template and then to the built-in template of the type
+func (ctrl *ContractController) defaultTemplate(c *gin.Context, property *model.Property, contractType string) *model.ContractTemplate {
+	builtin := service.BuiltinContractTemplate(contractType)
+	if ctrl.templateRepo == nil {
+		return &builtin
+	}
+
+	template, err := ctrl.templateRepo.GetDefault(c, property.ManagerIDs, contractType)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the default contract template, using the built-in one", "error", err)
+		return &builtin
+	}
+	if template == nil {
+		return &builtin
+	}
+	return template
+}
+
+// propertyBuilding returns the copropiedad printed in the contracts of a property, nil when the
+// property does not belong to a building
+func (ctrl *ContractController) propertyBuilding(c *gin.Context, property *model.Property) *model.Building {
+	if ctrl.buildingRepo == nil || property == nil || property.BuildingID == nil {
+		return nil
+	}
+
+	building, err := ctrl.buildingRepo.GetByID(c, *property.BuildingID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the building of property", "property_id", property.ID, "error", err)
+		return nil
+	}
+	return building
+}
+
+// propertyInventory returns the inventory printed as an annex of the contracts of a property,
+// nil when the property has no inventory
+func (ctrl *ContractController) propertyInventory(c *gin.Context, propertyID uuid.UUID) *model.Inventory {
+	if ctrl.inventoryRepo == nil {
+		return nil
+	}
+
+	inventory, err := ctrl.inventoryRepo.GetByPropertyID(c, propertyID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the inventory of property, the contract is generated without annex", "property_id", propertyID, "error", err)
+		return nil
+	}
+	return inventory
+}
+
+// contractInventory returns the inventory of the property of a contract (rental) ID, nil when
+// the contract or the inventory cannot be found
+func (ctrl *ContractController) contractInventory(c *gin.Context, contractID string) *model.Inventory {
+	rentalID, err := uuid.Parse(contractID)
+	if err != nil {
+		return nil
+	}
+
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil || rental == nil {
+		return nil
+	}
+	return ctrl.propertyInventory(c, rental.PropertyID)
+}
+
+// approvedGuarantee returns the approved afianzadora study of a renter and property, nil when
+// the rental is not guaranteed by a policy
+func (ctrl *ContractController) approvedGuarantee(c *gin.Context, renterID, propertyID uuid.UUID) *model.GuaranteeStudy {
+	if ctrl.guaranteeRepo == nil {
+		return nil
+	}
+
+	study, err := ctrl.guaranteeRepo.GetApproved(c, renterID, propertyID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the guarantee policy of renter for property", "renter_id", renterID, "property_id", propertyID, "error", err)
+		return nil
+	}
+	return study
+}
+
+// contractGuarantee returns the approved afianzadora study of the renter and property of a
+// contract (rental) ID, nil when the contract cannot be found or is not guaranteed by a policy
+func (ctrl *ContractController) contractGuarantee(c *gin.Context, contractID string) *model.GuaranteeStudy {
+	rentalID, err := uuid.Parse(contractID)
+	if err != nil {
+		return nil
+	}
+
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil || rental == nil {
+		return nil
+	}
+	return ctrl.approvedGuarantee(c, rental.RenterID, rental.PropertyID)
+}
+
+// activePromotions returns the promotions of a property valid now, none when they cannot be loaded
+func (ctrl *ContractController) activePromotions(c *gin.Context, propertyID uuid.UUID) []model.Promotion {
+	if ctrl.promotionRepo == nil {
+		return nil
+	}
+
+	promotions, err := ctrl.promotionRepo.GetByPropertyID(c, propertyID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the promotions of property, the contract is generated without them", "property_id", propertyID, "error", err)
+		return nil
+	}
+	return service.ActivePromotions(promotions, time.Now())
+}
+
+// recordPromotionOffers records the contract offered with each promotion, to track conversions
+func (ctrl *ContractController) recordPromotionOffers(c *gin.Context, promotions []model.Promotion, propertyID, renterID uuid.UUID, contractID string) {
+	for _, promotion := range promotions {
+		_, err := ctrl.promotionRepo.CreateRedemption(c, model.PromotionRedemption{
+			PromotionID: promotion.ID,
+			PropertyID:  propertyID,
+			RenterID:    renterID,
+			ContractID:  contractID,
+		})
+		if err != nil {
+			logging.FromContext(c).Warn("could not record the offer of promotion with contract", "promotion_id", promotion.ID, "contract_id", contractID, "error", err)
+		}
+	}
+}
+
+// markPromotionsConverted records that the promotions offered with a contract converted
+// because the contract was signed
+func (ctrl *ContractController) markPromotionsConverted(c *gin.Context, contractID string) {
+	if ctrl.promotionRepo == nil {
+		return
+	}
+	if err := ctrl.promotionRepo.MarkConverted(c, contractID, time.Now()); err != nil {
+		logging.FromContext(c).Warn("could not record the conversion of the promotions of contract", "contract_id", contractID, "error", err)
+	}
+}
+
+// personEmail returns the login email of a person, "" when the person is nil or has no user
+func (ctrl *ContractController) personEmail(c *gin.Context, person *model.Person) string {
+	if person == nil {
+		return ""
+	}
+
+	user, err := ctrl.userRepo.GetByPersonID(c, person.ID)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Email
+}
+
+// rentalParties returns the parties recorded for a rental, none when they cannot be loaded
+func (ctrl *ContractController) rentalParties(c *gin.Context, rentalID uuid.UUID) []model.RentalParty {
+	if ctrl.partyRepo == nil {
+		return nil
+	}
+
+	parties, err := ctrl.partyRepo.GetByRentalID(c, rentalID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the parties of rental", "rental_id", rentalID, "error", err)
+		return nil
+	}
+	return parties
+}
+
+// partyPerson returns the person of the first party with the role, nil when there is none
+func (ctrl *ContractController) partyPerson(c *gin.Context, parties []model.RentalParty, role string) *model.Person {
+	personID := model.FirstRentalParty(parties, role)
+	if personID == uuid.Nil {
+		return nil
+	}
+
+	person, err := ctrl.personRepo.GetByID(c, personID)
+	if err != nil {
+		logging.FromContext(c).Warn("could not load the of the contract", "role", role, "person_id", personID, "error", err)
+		return nil
+	}
+	return person
+}
+
+// copyRentalParties records the parties of a contract for another rental, e.g. its renewal
+func (ctrl *ContractController) copyRentalParties(c *gin.Context, parties []model.RentalParty, rentalID uuid.UUID) {
+	for _, party := range parties {
+		if _, err := ctrl.partyRepo.Create(c, model.RentalParty{RentalID: rentalID, PersonID: party.PersonID, Role: party.Role}); err != nil {
+			logging.FromContext(c).Warn("could not copy the to rental", "role", party.Role, "person_id", party.PersonID, "rental_id", rentalID, "error", err)
+		}
+	}
+}
+
+// requestPartySignature sends the contract of a rental to one of its parties for signing and
+// returns the ID of the signing request, "" when it could not be sent
+func (ctrl *ContractController) requestPartySignature(c *gin.Context, rental *model.Rental, personID uuid.UUID, pdfBytes []byte, expirationDays int) string {
+	rentalID := rental.ID
+	person, err := ctrl.personRepo.GetByID(c, personID)
+	if err != nil || person == nil {
+		logging.FromContext(c).Warn("could not load party of rental to sign", "person_id", personID, "rental_id", rentalID, "error", err)
+		return ""
+	}
+	email := ctrl.personEmail(c, person)
+	if email == "" {
+		logging.FromContext(c).Warn("party of rental has no email to sign the contract", "person_id", personID, "rental_id", rentalID)
+		return ""
+	}
+
+	org := ctrl.orgService.ForProperty(c, rental.PropertyID)
+	signingRequest, err := service.CreateSignatureRequest(model.ContractSigningInfo{
+		ContractID:     rentalID.String(),
+		RecipientID:    person.ID.String(),
+		RecipientEmail: email,
+		PDFData:        pdfBytes,
+		SignerName:     person.FullName,
+		BaseURL:        service.OrganizationBaseURL(org),
+		RequestedBy:    requesterPersonID(c),
+		Organization:   org,
+	}, expirationDays)
+	if err != nil {
+		logging.FromContext(c).Warn("could not send the contract of rental to party", "rental_id", rentalID, "person_id", personID, "error", err)
+		return ""
+	}
+
+	if ctrl.signingRepo != nil {
+		if _, err := ctrl.signingRepo.CreateSigningRequest(c, *signingRequest); err != nil {
+			logging.FromContext(c).Error("error saving signature request to database", "error", err)
+		} else {
+			ctrl.webhooks.Dispatch(model.WebhookEventSigningCreated, signingRequest.ID)
+		}
+	}
+	return signingRequest.ID
+}
+
+// applyContractParties fills the contract data of a contract (rental) ID with the renter of the
+// rental and the owner, cosigner and witness recorded for it. Contracts not generated for a
+// rental keep the data they have.
+func (ctrl *ContractController) applyContractParties(c *gin.Context, contractID string, data *service.ContractPDF) {
+	rentalID, err := uuid.Parse(contractID)
+	if err != nil {
+		return
+	}
+	rental, err := ctrl.rentalRepo.GetByID(c, rentalID)
+	if err != nil || rental == nil {
+		return
+	}
+
+	if renter, err := ctrl.personRepo.GetByID(c, rental.RenterID); err == nil && renter != nil {
+		data.Renter = renter
+		data.RenterEmail = ctrl.personEmail(c, renter)
+	}
+
+	parties := ctrl.rentalParties(c, rental.ID)
+	if owner := ctrl.partyPerson(c, parties, model.RentalPartyOwner); owner != nil {
+		data.Owner = owner
+		data.OwnerEmail = ctrl.personEmail(c, owner)
+	}
+	// An afianzadora policy substitutes the cosigner
+	if data.Guarantee == nil {
+		if cosigner := ctrl.partyPerson(c, parties, model.RentalPartyCoSigner); cosigner != nil {
+			data.CoSigner = cosigner
+			data.CoSignerEmail = ctrl.personEmail(c, cosigner)
+		}
+	}
+	if witness := ctrl.partyPerson(c, parties, model.RentalPartyWitness); witness != nil {
+		data.Witness = witness
+		data.WitnessEmail = ctrl.personEmail(c, witness)
+	}
+}
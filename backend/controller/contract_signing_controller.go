@@ -2,12 +2,9 @@ package controller
 
 import (
 	"crypto"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
-	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -19,11 +16,17 @@ import (
 	"github.com/digitorus/pdfsign/sign"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
+// maxVerifiedPDFSize limits the PDFs uploaded for signature verification
+const maxVerifiedPDFSize = 20 << 20
+
 // SignatureMetadata holds additional information to include in the signature
 type SignatureMetadata struct {
 	SignID     string // ID of the signature request
@@ -33,30 +36,40 @@ type SignatureMetadata struct {
 
 // ContractSigningController handles operations related to contract signing
 type ContractSigningController struct {
-	personRepo         *storage.PersonRepository
-	propertyRepo       *storage.PropertyRepository
-	pricingRepo        *storage.PricingRepository
-	userRepo           *storage.UserRepository
+	personRepo         storage.PersonStore
+	propertyRepo       storage.PropertyStore
+	pricingRepo        storage.PricingStore
+	userRepo           storage.UserStore
 	contractController *ContractController
-	signingRepo        *storage.ContractSigningRepository
+	signingRepo        storage.ContractSigningStore
+	eventRepo          storage.ContractSigningEventStore
+	orgService         *service.OrganizationService
+	webhooks           *service.SigningWebhookDispatcher
+	inspections        *service.InspectionService
+	// signing holds the external providers of the signing requests
+	signing config.SigningConfig
+	// otpSecret keys the hashes of the one-time signing codes (SIGNING_OTP_SECRET)
+	otpSecret []byte
 }
 
 // NewContractSigningController creates a new ContractSigningController
 func NewContractSigningController(
-	personRepo *storage.PersonRepository,
-	propertyRepo *storage.PropertyRepository,
-	pricingRepo *storage.PricingRepository,
-	userRepo *storage.UserRepository,
+	personRepo storage.PersonStore,
+	propertyRepo storage.PropertyStore,
+	pricingRepo storage.PricingStore,
+	userRepo storage.UserStore,
 	contractController *ContractController,
-	signingRepo *storage.ContractSigningRepository,
+	signingRepo storage.ContractSigningStore,
+	eventRepo storage.ContractSigningEventStore,
+	orgService *service.OrganizationService,
+	webhooks *service.SigningWebhookDispatcher,
+	inspections *service.InspectionService,
+	signing config.SigningConfig,
+	otpSecret string,
 ) *ContractSigningController {
-	// Generate self-signed certificates for development
-	certsDir := "./certs"
-	if _, err := os.Stat(filepath.Join(certsDir, "certificate.crt")); os.IsNotExist(err) {
-		log.Printf("Generating self-signed certificates in %s", certsDir)
-		if err := service.GenerateSelfSignedCert(certsDir); err != nil {
-			log.Printf("Warning: Failed to generate self-signed certificates: %v", err)
-		}
+	// Load the configured signing certificate, a self-signed one is generated only when none is configured
+	if _, err := service.ActiveSigningCertificate(); err != nil {
+		slog.Warn("failed to load signing certificate", "error", err)
 	}
 
 	return &ContractSigningController{
@@ -66,6 +79,12 @@ func NewContractSigningController(
 		userRepo:           userRepo,
 		contractController: contractController,
 		signingRepo:        signingRepo,
+		eventRepo:          eventRepo,
+		orgService:         orgService,
+		webhooks:           webhooks,
+		inspections:        inspections,
+		signing:            signing,
+		otpSecret:          []byte(otpSecret),
 	}
 }
 
@@ -74,6 +93,7 @@ type SigningRequest struct {
 	ContractID     string `json:"contract_id" binding:"required"`
 	RecipientID    string `json:"recipient_id" binding:"required"`
 	ExpirationDays int    `json:"expiration_days"`
+	Provider       string `json:"provider"` // "builtin" (default), "zapsign" or "docusign"
 }
 
 // SigningStatusResponse represents the current status of a signing request
@@ -87,46 +107,56 @@ type SigningStatusResponse struct {
 	SignedAt    *time.Time `json:"signed_at,omitempty"`
 }
 
+// legacySigningRoutesSunset is the default date after which the legacy
+// /contract-signing/* public paths may be removed. Override with LEGACY_ROUTES_SUNSET (YYYY-MM-DD).
+const legacySigningRoutesSunset = "2027-01-31"
+
 // RegisterRoutes registers the contract signing routes
-func (ctrl *ContractSigningController) RegisterRoutes(router *gin.RouterGroup) {
-	signingRoutes := router.Group("/contract-signing")
-	{
-		// Routes that require authentication
-		signingRoutes.POST("/request", ctrl.CreateSigningRequest)
-	}
+func (ctrl *ContractSigningController) RegisterRoutes(router *gin.RouterGroup, cfg *config.Config) {
+	ctrl.RegisterAuthRoutes(router)
+	ctrl.RegisterPublicRoutes(router, cfg.RateLimits, cfg.Server.LegacyRoutesSunset)
+}
 
-	// Public routes that don't require authentication
-	publicRoutes := router.Group("/public/contract-signing")
-	{
-		publicRoutes.GET("/status/:id", ctrl.GetSigningStatus)
-		publicRoutes.POST("/sign/:id", ctrl.SignContract)
-		publicRoutes.POST("/reject/:id", ctrl.RejectContract)
-		publicRoutes.GET("/pdf/:id", ctrl.ServePDF)
+// RegisterPublicRoutes registers only the public contract signing routes, limited by the
+// Signing and SigningRequest limits. The legacy paths are deprecated until legacySunset
+// (LEGACY_ROUTES_SUNSET), legacySigningRoutesSunset when it is empty.
+func (ctrl *ContractSigningController) RegisterPublicRoutes(router *gin.RouterGroup, limits config.RateLimitConfig, legacySunset string) {
+	// The signing links are unauthenticated: each client IP and each signing request, whose
+	// OTP could be guessed, get a limited number of requests
+	rateLimits := []gin.HandlerFunc{
+		middleware.RateLimit("signing_ip", middleware.RateLimitOr(limits.Signing, "60/1m"), middleware.ClientIPKey),
+		middleware.RateLimit("signing_request", middleware.RateLimitOr(limits.SigningRequest, "20/1m"), middleware.ParamKey("id")),
 	}
 
-	// Keep original endpoints for backward compatibility but make them public too
-	router.GET("/contract-signing/status/:id", ctrl.GetSigningStatus)
-	router.POST("/contract-signing/sign/:id", ctrl.SignContract)
-	router.POST("/contract-signing/reject/:id", ctrl.RejectContract)
-	router.GET("/contract-signing/pdf/:id", ctrl.ServePDF)
-}
-
-// RegisterPublicRoutes registers only the public contract signing routes
-func (ctrl *ContractSigningController) RegisterPublicRoutes(router *gin.RouterGroup) {
 	// Public routes that don't require authentication
-	publicRoutes := router.Group("/public/contract-signing")
+	publicRoutes := router.Group("/public/contract-signing", rateLimits...)
 	{
 		publicRoutes.GET("/status/:id", ctrl.GetSigningStatus)
+		publicRoutes.GET("/status/:id/stream", ctrl.StreamSigningStatus)
+		publicRoutes.POST("/otp/:id", ctrl.SendSigningOTP)
 		publicRoutes.POST("/sign/:id", ctrl.SignContract)
 		publicRoutes.POST("/reject/:id", ctrl.RejectContract)
 		publicRoutes.GET("/pdf/:id", ctrl.ServePDF)
+		publicRoutes.GET("/verify/:id", ctrl.VerifySignature)
+		publicRoutes.POST("/verify", ctrl.VerifySignedPDF)
 	}
 
-	// Keep original endpoints for backward compatibility but make them public too
-	router.GET("/contract-signing/status/:id", ctrl.GetSigningStatus)
-	router.POST("/contract-signing/sign/:id", ctrl.SignContract)
-	router.POST("/contract-signing/reject/:id", ctrl.RejectContract)
-	router.GET("/contract-signing/pdf/:id", ctrl.ServePDF)
+	// Callbacks from external e-sign providers, authenticated by the provider signature
+	router.POST("/public/esign/webhook/:provider", ctrl.HandleProviderWebhook)
+
+	// Original endpoints are kept for backward compatibility behind the deprecation
+	// middleware, which records their usage until they can be removed
+	sunset := legacyRoutesSunset(legacySunset)
+	legacyRoutes := router.Group("/contract-signing", rateLimits...)
+	legacy := func(method, relativePath string, handler gin.HandlerFunc) {
+		path := legacyRoutes.BasePath() + relativePath
+		replacement := publicRoutes.BasePath() + relativePath
+		legacyRoutes.Handle(method, relativePath, middleware.DeprecatedRoute(method, path, replacement, sunset), handler)
+	}
+	legacy(http.MethodGet, "/status/:id", ctrl.GetSigningStatus)
+	legacy(http.MethodPost, "/sign/:id", ctrl.SignContract)
+	legacy(http.MethodPost, "/reject/:id", ctrl.RejectContract)
+	legacy(http.MethodGet, "/pdf/:id", ctrl.ServePDF)
 }
 
 // RegisterAuthRoutes registers only the authenticated contract signing routes
@@ -135,14 +165,44 @@ func (ctrl *ContractSigningController) RegisterAuthRoutes(router *gin.RouterGrou
 	{
 		// Routes that require authentication
 		signingRoutes.POST("/request", ctrl.CreateSigningRequest)
+		signingRoutes.GET("/:id/events", ctrl.GetSigningEvents)
+	}
+}
+
+// GetSigningEvents returns the audit trail of a signing request
+func (ctrl *ContractSigningController) GetSigningEvents(c *gin.Context) {
+	if ctrl.eventRepo == nil {
+		c.JSON(http.StatusOK, []storage.ContractSigningEvent{})
+		return
+	}
+
+	events, err := ctrl.eventRepo.GetBySigningID(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningEvents"})
+		return
 	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// legacyRoutesSunset returns the sunset date for the legacy contract signing routes
+func legacyRoutesSunset(value string) time.Time {
+	if value == "" {
+		value = legacySigningRoutesSunset
+	}
+	sunset, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		slog.Warn("invalid LEGACY_ROUTES_SUNSET, using the default", "value", value, "default", legacySigningRoutesSunset)
+		sunset, _ = time.Parse("2006-01-02", legacySigningRoutesSunset)
+	}
+	return sunset
 }
 
 // CreateSigningRequest initiates a contract signing process
 func (ctrl *ContractSigningController) CreateSigningRequest(c *gin.Context) {
 	var req SigningRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
 		return
 	}
 
@@ -154,37 +214,42 @@ func (ctrl *ContractSigningController) CreateSigningRequest(c *gin.Context) {
 	// Parse UUIDs
 	_, err := uuid.Parse(req.ContractID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidContractID"})
 		return
 	}
 
 	recipientID, err := uuid.Parse(req.RecipientID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recipient ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRecipientID"})
 		return
 	}
 
 	// Get recipient details
 	recipient, err := ctrl.personRepo.GetByID(c, recipientID)
 	if err != nil {
-		log.Printf("Error getting recipient: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recipient details"})
+		logging.FromContext(c).Error("error getting recipient", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRecipientDetails"})
 		return
 	}
 	if recipient == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Recipient not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "RecipientNotFound"})
 		return
 	}
 
 	// Get recipient email from user record
 	recipientUser, err := ctrl.userRepo.GetByPersonID(c, recipientID)
 	if err != nil {
-		log.Printf("Error getting recipient user: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recipient user details"})
+		logging.FromContext(c).Error("error getting recipient user", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRecipientUserDetails"})
 		return
 	}
 	if recipientUser == nil || recipientUser.Email == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Recipient email not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "RecipientEmailNotFound"})
+		return
+	}
+
+	if service.IsExternalESignProvider(req.Provider) {
+		ctrl.createExternalSigningRequest(c, req, recipient, recipientUser.Email)
 		return
 	}
 
@@ -200,6 +265,7 @@ func (ctrl *ContractSigningController) CreateSigningRequest(c *gin.Context) {
 	mockPDFData := []byte("Sample PDF data for contract " + req.ContractID)
 
 	// Create signing info
+	org := ctrl.orgService.ForPerson(c, recipientID)
 	signingInfo := model.ContractSigningInfo{
 		ContractID:     req.ContractID,
 		RecipientID:    req.RecipientID,
@@ -207,13 +273,16 @@ func (ctrl *ContractSigningController) CreateSigningRequest(c *gin.Context) {
 		PDFData:        mockPDFData,
 		SignerName:     recipient.FullName,
 		SignatureID:    signingID,
+		BaseURL:        service.OrganizationBaseURL(org),
+		RequestedBy:    requesterPersonID(c),
+		Organization:   org,
 	}
 
 	// Create the signature request
 	signingRequest, err := service.CreateSignatureRequest(signingInfo, req.ExpirationDays)
 	if err != nil {
-		log.Printf("Error creating signature request: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create signature request"})
+		logging.FromContext(c).Error("error creating signature request", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateSignatureRequest"})
 		return
 	}
 
@@ -221,24 +290,260 @@ func (ctrl *ContractSigningController) CreateSigningRequest(c *gin.Context) {
 	if ctrl.signingRepo != nil {
 		_, err = ctrl.signingRepo.CreateSigningRequest(c, *signingRequest)
 		if err != nil {
-			log.Printf("Error saving signature request to database: %v", err)
+			logging.FromContext(c).Error("error saving signature request to database", "error", err)
 			// Continue anyway since the email has been sent
+		} else {
+			ctrl.webhooks.Dispatch(model.WebhookEventSigningCreated, signingRequest.ID)
 		}
 	}
 
 	// Return the signature request details
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "Signature request created and email sent",
-		"signing_id": signingRequest.ID,
-		"expires_at": signingRequest.ExpiresAt,
+		"message":            "Signature request created and email sent",
+		"signing_id":         signingRequest.ID,
+		"expires_at":         signingRequest.ExpiresAt,
+		"expires_at_display": service.FormatDate(signingRequest.ExpiresAt),
 	})
 }
 
+// requesterPersonID returns the person ID of the authenticated admin or manager requesting a
+// signature, empty when unknown
+func requesterPersonID(c *gin.Context) string {
+	if value, exists := c.Get("user"); exists {
+		if authUser, ok := value.(*model.User); ok && authUser.PersonID != uuid.Nil {
+			return authUser.PersonID.String()
+		}
+	}
+	return ""
+}
+
+// createExternalSigningRequest sends the contract to an external e-sign provider.
+// The PDF must have been generated before (see HandleGenerateContract).
+func (ctrl *ContractSigningController) createExternalSigningRequest(c *gin.Context, req SigningRequest, recipient *model.Person, recipientEmail string) {
+	provider, err := service.GetESignProvider(req.Provider, ctrl.signing)
+	if err != nil {
+		logging.FromContext(c).Error("error getting e-sign provider", "provider", req.Provider, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ESignProviderUnavailable", "detail": req.Provider})
+		return
+	}
+
+	if ctrl.signingRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SigningRepositoryUnavailable"})
+		return
+	}
+
+	pdfData, err := service.ReadTempPDF(req.ContractID)
+	if err != nil {
+		logging.FromContext(c).Error("error reading contract PDF", "contract_id", req.ContractID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "ContractPDFNotFound"})
+		return
+	}
+
+	signingID := uuid.New().String()
+	envelope, err := provider.CreateEnvelope(c, service.ESignEnvelopeRequest{
+		SigningID:    signingID,
+		ContractID:   req.ContractID,
+		DocumentName: "Contrato de arrendamiento " + req.ContractID,
+		PDFData:      pdfData,
+		SignerName:   recipient.FullName,
+		SignerEmail:  recipientEmail,
+	})
+	if err != nil {
+		logging.FromContext(c).Error("error creating envelope for contract", "name", provider.Name(), "contract_id", req.ContractID, "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "FailedToSendContractToProvider", "params": gin.H{"Provider": provider.Name()}})
+		return
+	}
+
+	now := time.Now()
+	signingRequest := model.ContractSigningRequest{
+		ID:             signingID,
+		ContractID:     req.ContractID,
+		RecipientID:    req.RecipientID,
+		RecipientEmail: recipientEmail,
+		Status:         model.StatusPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.AddDate(0, 0, req.ExpirationDays),
+		Provider:       provider.Name(),
+		ExternalID:     envelope.ExternalID,
+		RequestedBy:    requesterPersonID(c),
+	}
+
+	// Unlike the built-in flow the record is required: webhooks are matched through it
+	if _, err := ctrl.signingRepo.CreateSigningRequest(c, signingRequest); err != nil {
+		if existing, getErr := ctrl.signingRepo.GetByID(c, signingID); getErr != nil || existing == nil {
+			logging.FromContext(c).Error("error saving signature request", "name", provider.Name(), "external_id", envelope.ExternalID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "SigningRequestNotSaved"})
+			return
+		}
+	}
+	ctrl.webhooks.Dispatch(model.WebhookEventSigningCreated, signingID)
+
+	logging.FromContext(c).Info("contract sent", "contract_id", req.ContractID, "name", provider.Name(), "external_id", envelope.ExternalID, "recipient_email", recipientEmail)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Signature request sent through " + provider.Name(),
+		"signing_id":         signingID,
+		"provider":           provider.Name(),
+		"external_id":        envelope.ExternalID,
+		"signing_url":        envelope.SigningURL,
+		"expires_at":         signingRequest.ExpiresAt,
+		"expires_at_display": service.FormatDate(signingRequest.ExpiresAt),
+	})
+}
+
+// HandleProviderWebhook receives status callbacks from external e-sign providers.
+// When a contract is signed the final PDF is downloaded and stored in Supabase Storage.
+func (ctrl *ContractSigningController) HandleProviderWebhook(c *gin.Context) {
+	provider, err := service.GetESignProvider(c.Param("provider"), ctrl.signing)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "UnknownESignProvider"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadWebhookBody"})
+		return
+	}
+
+	event, err := provider.ParseWebhook(c.Request.Header, body)
+	if err != nil {
+		logging.FromContext(c).Warn("rejected webhook", "name", provider.Name(), "error", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "InvalidWebhook"})
+		return
+	}
+
+	if event.Type == service.ESignEventOther {
+		c.JSON(http.StatusOK, gin.H{"message": "Event ignored", "event": event.RawStatus})
+		return
+	}
+
+	if ctrl.signingRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SigningRepositoryUnavailable"})
+		return
+	}
+
+	record, err := ctrl.signingRepo.GetByExternalID(c, provider.Name(), event.ExternalID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting signing request envelope", "name", provider.Name(), "external_id", event.ExternalID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+		return
+	}
+
+	// Providers retry deliveries, so repeated events are acknowledged without changes
+	if record.Status != string(model.StatusPending) {
+		c.JSON(http.StatusOK, gin.H{"message": "Signing request already " + record.Status})
+		return
+	}
+
+	if event.Type == service.ESignEventRejected {
+		if err := ctrl.signingRepo.MarkAsRejected(c, record.ID, nil); err != nil {
+			logging.FromContext(c).Error("error marking signing request as rejected", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToMarkSigningRejected"})
+			return
+		}
+		ctrl.webhooks.Dispatch(model.WebhookEventSigningRejected, record.ID)
+		c.JSON(http.StatusOK, gin.H{"id": record.ID, "status": model.StatusRejected})
+		return
+	}
+
+	signedPDFData, err := provider.DownloadSignedDocument(c, event.ExternalID)
+	if err != nil {
+		logging.FromContext(c).Error("error downloading signed PDF", "name", provider.Name(), "error", err)
+		// A non-2xx answer makes the provider retry the delivery later
+		c.JSON(http.StatusBadGateway, gin.H{"error": "FailedToDownloadSignedDocument"})
+		return
+	}
+
+	signedPDFPath, err := storeSignedPDF(record, signedPDFData)
+	if err != nil {
+		logging.FromContext(c).Error("error storing signed PDF for signing request", "record_id", record.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToStoreSignedDocument"})
+		return
+	}
+
+	if err := ctrl.signingRepo.MarkAsSigned(c, record.ID, signedPDFPath, nil); err != nil {
+		logging.FromContext(c).Error("error marking signing request as signed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToMarkSigningSigned"})
+		return
+	}
+
+	ctrl.contractController.markPromotionsConverted(c, record.ContractID)
+	ctrl.webhooks.Dispatch(model.WebhookEventSigningSigned, record.ID)
+	service.GetNotifications().SignatureCompleted(record.ID)
+
+	logging.FromContext(c).Info("contract signed through, stored", "contract_id", record.ContractID, "name", provider.Name(), "signed_pdf_path", signedPDFPath)
+	c.JSON(http.StatusOK, gin.H{"id": record.ID, "status": model.StatusSigned})
+}
+
+// storeSignedPDF saves a signed contract in the file storage, or in the temp directory
+// when storage is not configured, and returns its path
+func storeSignedPDF(record *storage.ContractSigningRecord, signedPDFData []byte) (string, error) {
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		filePath := fmt.Sprintf("contracts/%s/%s_signed.pdf", record.ContractID, record.ID)
+		if _, err := storageService.UploadBytes(filePath, signedPDFData, "application/pdf"); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "contracts")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", err
+	}
+	signedPDFPath := filepath.Join(tempDir, record.ContractID+"_signed.pdf")
+	if err := os.WriteFile(signedPDFPath, signedPDFData, 0644); err != nil {
+		return "", err
+	}
+	return signedPDFPath, nil
+}
+
+// storeNotarizedPDF saves the document authenticated by a notary next to the signed contract
+// and returns its path
+func storeNotarizedPDF(notarization *model.Notarization, stampedPDFData []byte) (string, error) {
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		filePath := fmt.Sprintf("contracts/%s/%s_notarized.pdf", notarization.ContractID, notarization.ID)
+		if _, err := storageService.UploadBytes(filePath, stampedPDFData, "application/pdf"); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "contracts")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", err
+	}
+	stampedPDFPath := filepath.Join(tempDir, notarization.ID.String()+"_notarized.pdf")
+	if err := os.WriteFile(stampedPDFPath, stampedPDFData, 0644); err != nil {
+		return "", err
+	}
+	return stampedPDFPath, nil
+}
+
+// loadStoredPDF reads a document saved by storeSignedPDF or storeNotarizedPDF, from the local
+// disk or from the file storage
+func loadStoredPDF(path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("document has no stored file")
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		return storageService.DownloadFile(path)
+	}
+	return nil, fmt.Errorf("document %s not found", path)
+}
+
 // GetSigningStatus retrieves the status of a signing request
 func (ctrl *ContractSigningController) GetSigningStatus(c *gin.Context) {
 	signingID := c.Param("id")
 	if signingID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Signing ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningIDRequired"})
 		return
 	}
 
@@ -246,32 +551,25 @@ func (ctrl *ContractSigningController) GetSigningStatus(c *gin.Context) {
 	if ctrl.signingRepo != nil {
 		record, err := ctrl.signingRepo.GetByID(c, signingID)
 		if err != nil {
-			log.Printf("Error getting signing request: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get signing request"})
+			logging.FromContext(c).Error("error getting signing request", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
 			return
 		}
 
 		if record == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Signing request not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
 			return
 		}
 
-		// Get Spanish translation of status
-		spanishStatus := model.StatusTranslations[record.Status]
-		if spanishStatus == "" {
-			spanishStatus = record.Status // Fallback to English if no translation found
+		// The recipient opens the signing page through this endpoint, the first time is reported
+		// to the webhooks
+		if record.Status == string(model.StatusPending) && record.ViewedAt == nil {
+			if firstView, err := ctrl.signingRepo.MarkViewed(c, record.ID, time.Now()); err == nil && firstView {
+				ctrl.webhooks.Dispatch(model.WebhookEventSigningViewed, record.ID)
+			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"id":             record.ID,
-			"contract_id":    record.ContractID,
-			"recipient_id":   record.RecipientID,
-			"status":         record.Status,
-			"status_spanish": spanishStatus,
-			"created_at":     record.CreatedAt,
-			"expires_at":     record.ExpiresAt,
-			"signed_at":      record.SignedAt,
-		})
+		c.JSON(http.StatusOK, signingStatusResponse(c, record))
 		return
 	}
 
@@ -284,42 +582,551 @@ func (ctrl *ContractSigningController) GetSigningStatus(c *gin.Context) {
 	})
 }
 
-// SignContract marks a contract as signed
+// signingStatusResponse is the status of a signing request shown on the signing page
+func signingStatusResponse(c *gin.Context, record *storage.ContractSigningRecord) gin.H {
+	// Get Spanish translation of status
+	spanishStatus := model.StatusTranslations[record.Status]
+	if spanishStatus == "" {
+		spanishStatus = record.Status // Fallback to English if no translation found
+	}
+
+	signedAtDisplay := ""
+	if record.SignedAt != nil {
+		signedAtDisplay = service.FormatDateTime(*record.SignedAt)
+	}
+
+	return gin.H{
+		"id":                 record.ID,
+		"contract_id":        record.ContractID,
+		"document_type":      record.DocumentType,
+		"recipient_id":       record.RecipientID,
+		"status":             record.Status,
+		"status_spanish":     spanishStatus,
+		"provider":           record.Provider,
+		"organization":       organizationName(middleware.GetOrganization(c)),
+		"created_at":         record.CreatedAt,
+		"expires_at":         record.ExpiresAt,
+		"signed_at":          record.SignedAt,
+		"created_at_display": service.FormatDateTime(record.CreatedAt),
+		"expires_at_display": service.FormatDate(record.ExpiresAt),
+		"signed_at_display":  signedAtDisplay,
+	}
+}
+
+const (
+	// signingStreamPollInterval is how often a status stream reads the signing request, for
+	// the changes made on other instances
+	signingStreamPollInterval = 5 * time.Second
+	// signingStreamHeartbeat is how often an idle stream sends a comment so proxies keep it open
+	signingStreamHeartbeat = 15 * time.Second
+	// signingStreamMaxDuration is how long a stream lasts before the browser has to reconnect
+	signingStreamMaxDuration = 10 * time.Minute
+	// signingStreamRetry is how long the browser waits before reconnecting, in milliseconds
+	signingStreamRetry = 3000
+)
+
+// StreamSigningStatus sends the status of a signing request as server-sent events while the
+// signing page is open: a status event with the current status, then one on every change. The
+// stream ends once the request is signed, rejected or expired, and after
+// signingStreamMaxDuration, when EventSource reconnects by itself.
+// @Summary Stream the status of a signing request
+// @Description Server-sent events: a status event with the current status, then one on every change until the request is signed, rejected or expired
+// @Tags signing
+// @Produce text/event-stream
+// @Param id path string true "Signing request ID"
+// @Success 200 {string} string "status events"
+// @Failure 404 {object} string "Signing request not found"
+// @Router /public/contract-signing/status/{id}/stream [get]
+func (ctrl *ContractSigningController) StreamSigningStatus(c *gin.Context) {
+	signingID := c.Param("id")
+	if ctrl.signingRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SigningRequestsUnavailable"})
+		return
+	}
+
+	record, err := ctrl.signingRepo.GetByID(c, signingID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting signing request", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+		return
+	}
+
+	hub := service.GetSigningStatusHub()
+	updates, unsubscribe := hub.Subscribe(signingID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx would otherwise buffer the events
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", signingStreamRetry)
+
+	send := func(record *storage.ContractSigningRecord) {
+		c.SSEvent("status", signingStatusResponse(c, record))
+		c.Writer.Flush()
+	}
+	send(record)
+
+	poll := time.NewTicker(signingStreamPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(signingStreamHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(signingStreamMaxDuration)
+	defer deadline.Stop()
+
+	status := record.Status
+	for status == string(model.StatusPending) {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-hub.Closed():
+			return
+		case <-deadline.C:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+			continue
+		case <-updates:
+		case <-poll.C:
+		}
+
+		current, err := ctrl.signingRepo.GetByID(c, signingID)
+		if err != nil || current == nil {
+			continue
+		}
+		if current.Status != status {
+			status = current.Status
+			send(current)
+		}
+	}
+}
+
+// VerifySignature confirms the authenticity of a signed contract. It is the target of the QR
+// code printed on the signature stamp, so it only exposes what the stamp already shows.
+func (ctrl *ContractSigningController) VerifySignature(c *gin.Context) {
+	signingID := c.Param("id")
+	if signingID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningIDRequired"})
+		return
+	}
+
+	if ctrl.signingRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SignatureVerificationUnavailable"})
+		return
+	}
+
+	record, err := ctrl.signingRepo.GetByID(c, signingID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting signing request", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"authentic": false,
+			"error":     "SigningRequestNotFound",
+		})
+		return
+	}
+
+	spanishStatus := model.StatusTranslations[record.Status]
+	if spanishStatus == "" {
+		spanishStatus = record.Status
+	}
+
+	signerName := ""
+	if recipientID, err := uuid.Parse(record.RecipientID); err == nil {
+		if recipient, err := ctrl.personRepo.GetByID(c, recipientID); err == nil && recipient != nil {
+			signerName = recipient.FullName
+		}
+	}
+
+	authentic := record.Status == string(model.StatusSigned) && record.SignedAt != nil
+	signedAtDisplay := ""
+	if record.SignedAt != nil {
+		signedAtDisplay = service.FormatDateTime(*record.SignedAt)
+	}
+
+	message := "Este documento fue firmado electrónicamente y la firma es auténtica."
+	if !authentic {
+		message = "La solicitud de firma existe pero el documento no ha sido firmado."
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"authentic":         authentic,
+		"message":           message,
+		"id":                record.ID,
+		"contract_id":       record.ContractID,
+		"signer_name":       signerName,
+		"signer_email":      maskEmail(record.RecipientEmail),
+		"status":            record.Status,
+		"status_spanish":    spanishStatus,
+		"provider":          record.Provider,
+		"organization":      organizationName(middleware.GetOrganization(c)),
+		"signed_at":         record.SignedAt,
+		"signed_at_display": signedAtDisplay,
+	})
+}
+
+// VerifySignedPDF verifies the digital signatures of an uploaded PDF ("file"): whether each
+// signature is cryptographically valid, whether the document was modified after signing and
+// who signed it and when. Signatures made by this platform are matched with their signing request.
+func (ctrl *ContractSigningController) VerifySignedPDF(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxVerifiedPDFSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "PDFTooLarge"})
+		return
+	}
+
+	pdfData, err := io.ReadAll(io.LimitReader(file, maxVerifiedPDFSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadPDF"})
+		return
+	}
+	if len(pdfData) > maxVerifiedPDFSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "PDFTooLarge"})
+		return
+	}
+
+	verification, err := service.VerifyPDFSignature(pdfData)
+	if err != nil {
+		logging.FromContext(c).Error("error verifying PDF signature", "filename", header.Filename, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "PDFVerificationFailed", "detail": err.Error()})
+		return
+	}
+
+	// Signing requests of the signatures made by this platform
+	signingRecords := []gin.H{}
+	if ctrl.signingRepo != nil {
+		for _, signer := range verification.Signers {
+			if signer.SigningID == "" {
+				continue
+			}
+			record, err := ctrl.signingRepo.GetByID(c, signer.SigningID)
+			if err != nil || record == nil {
+				continue
+			}
+			signingRecords = append(signingRecords, gin.H{
+				"id":          record.ID,
+				"contract_id": record.ContractID,
+				"status":      record.Status,
+				"signed_at":   record.SignedAt,
+			})
+		}
+	}
+
+	message := "El documento no contiene firmas digitales."
+	switch {
+	case verification.Signed && verification.Tampered:
+		message = "El documento fue modificado después de ser firmado."
+	case verification.Valid && verification.Trusted:
+		message = "Las firmas del documento son válidas y el documento no ha sido modificado."
+	case verification.Valid:
+		message = "Las firmas del documento son válidas pero el certificado del firmante no es de confianza."
+	case verification.Signed:
+		message = "Las firmas del documento no son válidas."
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"file_name":       header.Filename,
+		"verification":    verification,
+		"message":         message,
+		"signing_records": signingRecords,
+	})
+}
+
+// maskEmail hides most of the local part of an email address (j***@example.com)
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return ""
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPhone hides all but the last digits of a phone number (******4567)
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return phone
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
+
+// recipientOrganization returns the organization of the recipient of a signing request, whose
+// branding the signing emails and contract carry, or nil
+func (ctrl *ContractSigningController) recipientOrganization(c *gin.Context, record *storage.ContractSigningRecord) *model.Organization {
+	recipientID, err := uuid.Parse(record.RecipientID)
+	if err != nil || ctrl.orgService == nil {
+		return nil
+	}
+	return ctrl.orgService.ForPerson(c, recipientID)
+}
+
+// recordSigningEvent adds an entry to the audit trail of a signing request
+func (ctrl *ContractSigningController) recordSigningEvent(c *gin.Context, signingID, event, channel, detail string) {
+	addSigningEvent(c, ctrl.eventRepo, signingID, event, channel, detail)
+}
+
+// addSigningEvent adds an entry to the audit trail of a signing request with the IP and user
+// agent of the request. Failures are only logged, the signing flow does not depend on them.
+func addSigningEvent(c *gin.Context, eventRepo storage.ContractSigningEventStore, signingID, event, channel, detail string) {
+	if eventRepo == nil {
+		return
+	}
+
+	_, err := eventRepo.Create(c, &storage.ContractSigningEvent{
+		SigningID: signingID,
+		Event:     event,
+		Channel:   channel,
+		Detail:    detail,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		CreatedAt: model.FlexibleTime(time.Now()),
+	})
+	if err != nil {
+		logging.FromContext(c).Error("error recording event of signing request", "event", event, "signing_id", signingID, "error", err)
+	}
+}
+
+// SigningOTPRequest selects where the one-time signing code is sent
+type SigningOTPRequest struct {
+	Channel string `json:"channel"` // "email" (default) or "sms"
+}
+
+// SendSigningOTP sends the recipient the one-time code SignContract requires
+func (ctrl *ContractSigningController) SendSigningOTP(c *gin.Context) {
+	signingID := c.Param("id")
+	if ctrl.signingRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "ContractSigningUnavailable"})
+		return
+	}
+
+	var req SigningOTPRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+			return
+		}
+	}
+	if req.Channel == "" {
+		req.Channel = service.OTPChannelEmail
+	}
+	if req.Channel != service.OTPChannelEmail && req.Channel != service.OTPChannelSMS {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidOTPChannel"})
+		return
+	}
+
+	record, err := ctrl.signingRepo.GetByID(c, signingID)
+	if err != nil {
+		logging.FromContext(c).Error("error getting signing request", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+		return
+	}
+
+	now := time.Now()
+	if record.Status != string(model.StatusPending) || now.After(record.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningRequestNotPending"})
+		return
+	}
+	if record.OTPSentAt != nil && now.Sub(*record.OTPSentAt) < service.SigningOTPResendInterval {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "VerificationCodeJustSent"})
+		return
+	}
+
+	destination := record.RecipientEmail
+	maskedDestination := maskEmail(destination)
+	if req.Channel == service.OTPChannelSMS {
+		if !service.SMSEnabled() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SMSVerificationUnavailable"})
+			return
+		}
+		destination = ""
+		if recipientID, err := uuid.Parse(record.RecipientID); err == nil {
+			if recipient, err := ctrl.personRepo.GetByID(c, recipientID); err == nil && recipient != nil {
+				destination = strings.TrimSpace(recipient.Phone)
+			}
+		}
+		if destination == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "RecipientHasNoPhone"})
+			return
+		}
+		maskedDestination = maskPhone(destination)
+	}
+
+	code, err := service.GenerateSigningOTP()
+	if err != nil {
+		logging.FromContext(c).Error("error generating signing code", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateVerificationCode"})
+		return
+	}
+
+	expiresAt := now.Add(service.SigningOTPTTL)
+	if err := ctrl.signingRepo.SaveOTP(c, signingID, service.HashSigningOTP(ctrl.otpSecret, signingID, code), req.Channel, now, expiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveVerificationCode"})
+		return
+	}
+
+	if req.Channel == service.OTPChannelSMS {
+		err = service.SendSigningOTPSMS(destination, code, ctrl.recipientOrganization(c, record), middleware.RequestLocale(c))
+	} else {
+		err = service.SendSigningOTPEmail(destination, code, ctrl.recipientOrganization(c, record), middleware.RequestLocale(c))
+	}
+	if err != nil {
+		logging.FromContext(c).Error("error sending signing code", "signing_id", signingID, "channel", req.Channel, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSendVerificationCode"})
+		return
+	}
+
+	ctrl.recordSigningEvent(c, signingID, storage.SigningEventOTPSent, req.Channel, maskedDestination)
+
+	c.JSON(http.StatusOK, gin.H{
+		"channel":            req.Channel,
+		"destination":        maskedDestination,
+		"expires_at":         expiresAt,
+		"expires_in_seconds": int(service.SigningOTPTTL.Seconds()),
+	})
+}
+
+// SignerLocation is the optional location reported by the signer's browser
+type SignerLocation struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Accuracy  *float64 `json:"accuracy"` // Meters
+}
+
+// SignContractRequest carries the one-time code sent by SendSigningOTP
+type SignContractRequest struct {
+	OTP string `json:"otp"`
+	SignerLocation
+}
+
+// maxUserAgentLength bounds the user agent stored as signing evidence
+const maxUserAgentLength = 512
+
+// signingEvidence collects the IP address, user agent and reported location of the signer
+func signingEvidence(c *gin.Context, location SignerLocation) *model.SigningEvidence {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	evidence := &model.SigningEvidence{
+		IPAddress: c.ClientIP(),
+		UserAgent: userAgent,
+		Latitude:  location.Latitude,
+		Longitude: location.Longitude,
+		Accuracy:  location.Accuracy,
+	}
+	if !evidence.HasLocation() {
+		evidence.Latitude, evidence.Longitude, evidence.Accuracy = nil, nil, nil
+	}
+	return evidence
+}
+
+// verifySigningOTP checks the one-time code of a signing request, writing the error response
+// when it is missing, expired or wrong
+func (ctrl *ContractSigningController) verifySigningOTP(c *gin.Context, record *storage.ContractSigningRecord, code string) bool {
+	if record.OTPHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VerificationCodeRequested", "code": "otp_required"})
+		return false
+	}
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VerificationCodeRequired", "code": "otp_required"})
+		return false
+	}
+	if record.OTPExpiresAt == nil || time.Now().After(*record.OTPExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VerificationCodeExpired", "code": "otp_expired"})
+		return false
+	}
+	if record.OTPAttempts >= service.SigningOTPMaxAttempts {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "TooManyWrongCodes", "code": "otp_locked"})
+		return false
+	}
+
+	if !service.CheckSigningOTP(ctrl.otpSecret, record.ID, code, record.OTPHash) {
+		attempts := record.OTPAttempts + 1
+		if err := ctrl.signingRepo.SetOTPAttempts(c, record.ID, attempts); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyCode"})
+			return false
+		}
+		ctrl.recordSigningEvent(c, record.ID, storage.SigningEventOTPFailed, record.OTPChannel, fmt.Sprintf("attempt %d of %d", attempts, service.SigningOTPMaxAttempts))
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":              "InvalidVerificationCode",
+			"code":               "otp_invalid",
+			"remaining_attempts": service.SigningOTPMaxAttempts - attempts,
+		})
+		return false
+	}
+
+	return true
+}
+
+// SignContract marks a contract as signed. The recipient must send the one-time code obtained
+// from SendSigningOTP.
 func (ctrl *ContractSigningController) SignContract(c *gin.Context) {
 	signingId := c.Param("id")
 	if signingId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Signing ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningIDRequired"})
 		return
 	}
 
+	var req SignContractRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+			return
+		}
+	}
+
 	// If repository is available, update actual record
 	if ctrl.signingRepo != nil {
 		// Get the signing request
 		record, err := ctrl.signingRepo.GetByID(c, signingId)
 		if err != nil {
-			log.Printf("Error getting signing request: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get signing request"})
+			logging.FromContext(c).Error("error getting signing request", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
 			return
 		}
 
 		if record == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Signing request not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
 			return
 		}
 
 		// If already signed or rejected, return error
 		if record.Status == string(model.StatusSigned) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Contract already signed"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ContractAlreadySigned"})
 			return
 		}
 
 		if record.Status == string(model.StatusRejected) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Contract signing was rejected"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SigningRequestRejected"})
 			return
 		}
 
 		if record.Status == string(model.StatusExpired) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Contract signing request has expired"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SigningRequestExpired"})
+			return
+		}
+
+		if !ctrl.verifySigningOTP(c, record, strings.TrimSpace(req.OTP)) {
 			return
 		}
 
@@ -335,39 +1142,53 @@ func (ctrl *ContractSigningController) SignContract(c *gin.Context) {
 
 		signerEmail = record.RecipientEmail
 
-		// Create a basic contract data structure for signing
-		// In a real implementation, this data should be retrieved from the contract record
-		contractData := service.ContractPDF{
-			Renter:       &model.Person{FullName: signerName},
-			Owner:        nil, // Will use defaults
-			Property:     nil, // Will use defaults
-			Pricing:      nil, // Will use defaults
-			CoSigner:     nil, // Will use defaults
-			Witness:      nil, // Will use defaults
-			StartDate:    time.Now(),
-			EndDate:      time.Now().AddDate(0, 6, 0), // 6 months default
-			CreationDate: time.Now(),
-		}
+		// Evidence of the signer, printed in the PDF and stored with the record
+		evidence := signingEvidence(c, req.SignerLocation)
 
-		// Use the simple PDF signing approach with the new template
-		signedPDFData, err := service.SimpleSignPDF(
-			contractData,
-			signerName,
-			signerEmail,
-			signingId,
-		)
+		var signedPDFData []byte
+		if record.IsInspection() {
+			// Inspections are acknowledged with their own acta, stored with the rental files
+			signedPDFData, err = ctrl.acknowledgeInspection(c, record, signerName, signerEmail, evidence)
+		} else {
+			// Create a basic contract data structure for signing
+			// In a real implementation, this data should be retrieved from the contract record
+			contractData := service.ContractPDF{
+				Renter:       &model.Person{FullName: signerName},
+				Owner:        nil, // Filled from the parties of the rental below
+				Property:     nil, // Will use defaults
+				Pricing:      nil, // Will use defaults
+				CoSigner:     nil, // Filled from the parties of the rental below
+				Witness:      nil, // Filled from the parties of the rental below
+				StartDate:    time.Now(),
+				EndDate:      time.Now().AddDate(0, 6, 0), // 6 months default
+				CreationDate: time.Now(),
+				Inventory:    ctrl.contractController.contractInventory(c, record.ContractID),
+				Guarantee:    ctrl.contractController.contractGuarantee(c, record.ContractID),
+				Organization: ctrl.recipientOrganization(c, record),
+			}
+			ctrl.contractController.applyContractParties(c, record.ContractID, &contractData)
+
+			// Use the simple PDF signing approach with the new template
+			signedPDFData, err = service.SimpleSignPDF(
+				contractData,
+				signerName,
+				signerEmail,
+				signingId,
+				evidence,
+			)
+		}
 
 		if err != nil {
-			log.Printf("Error signing PDF with simple approach: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign PDF: " + err.Error()})
+			logging.FromContext(c).Error("error signing PDF with simple approach", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSignPDF", "detail": err.Error()})
 			return
 		}
 
 		// Make sure the temp directory exists
 		tempDir := filepath.Join(os.TempDir(), "contracts")
 		if err := os.MkdirAll(tempDir, 0755); err != nil {
-			log.Printf("Error creating temp directory: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create temporary directory"})
+			logging.FromContext(c).Error("error creating temp directory", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateTempDirectory"})
 			return
 		}
 
@@ -376,18 +1197,31 @@ func (ctrl *ContractSigningController) SignContract(c *gin.Context) {
 
 		// Save the signed PDF to file
 		if err := os.WriteFile(signedPDFPath, signedPDFData, 0644); err != nil {
-			log.Printf("Error writing signed PDF to file: %v", err)
+			logging.FromContext(c).Error("error writing signed PDF to file", "error", err)
 			// Continue anyway as we still have the signed PDF data
 		}
 
 		// Mark as signed in the database
-		err = ctrl.signingRepo.MarkAsSigned(c, signingId, signedPDFPath)
+		err = ctrl.signingRepo.MarkAsSigned(c, signingId, signedPDFPath, evidence)
 		if err != nil {
-			log.Printf("Error marking signing request as signed: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark signing request as signed"})
+			logging.FromContext(c).Error("error marking signing request as signed", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToMarkSigningSigned"})
 			return
 		}
 
+		// Consume the code and record the verification and the signature in the audit trail
+		if err := ctrl.signingRepo.MarkOTPVerified(c, signingId, time.Now()); err != nil {
+			logging.FromContext(c).Error("error consuming signing code", "signing_id", signingId, "error", err)
+		}
+		ctrl.recordSigningEvent(c, signingId, storage.SigningEventOTPVerified, record.OTPChannel, "")
+		ctrl.recordSigningEvent(c, signingId, storage.SigningEventSigned, "", signedPDFPath)
+		ctrl.webhooks.Dispatch(model.WebhookEventSigningSigned, signingId)
+		service.GetNotifications().SignatureCompleted(signingId)
+
+		if !record.IsInspection() {
+			ctrl.contractController.markPromotionsConverted(c, record.ContractID)
+		}
+
 		// Create signing info for sending the signed PDF back to the signer
 		signingInfo := &model.ContractSigningRequest{
 			ID:             record.ID,
@@ -397,9 +1231,9 @@ func (ctrl *ContractSigningController) SignContract(c *gin.Context) {
 		}
 
 		// Send the signed PDF to the signer via email
-		err = service.SendSignedPDFByEmail(signingInfo, signedPDFData)
+		err = service.SendSignedPDFByEmail(signingInfo, signedPDFData, ctrl.recipientOrganization(c, record), middleware.RequestLocale(c))
 		if err != nil {
-			log.Printf("Error sending signed PDF by email: %v", err)
+			logging.FromContext(c).Error("error sending signed PDF by email", "error", err)
 			// Continue anyway as the contract is already marked as signed
 		}
 
@@ -510,13 +1344,13 @@ func createProperPDF(outputPath string, contractID string) error {
 		return fmt.Errorf("failed to write PDF file: %w", err)
 	}
 
-	log.Printf("Created proper PDF at %s for contract ID %s", outputPath, contractID)
+	slog.Info("created proper PDF for contract", "output_path", outputPath, "contract_id", contractID)
 	return nil
 }
 
 // signPDFWithDigitorus signs a PDF using the Digitorus library
-func signPDFWithDigitorus(input, output, signerName string, metadata *SignatureMetadata) error {
-	log.Printf("Starting PDF signing process. Input: %s, Output: %s", input, output)
+func signPDFWithDigitorus(input, output, signerName, tsaURL string, metadata *SignatureMetadata) error {
+	slog.Info("starting PDF signing process", "input", input, "output", output)
 
 	// Check if input file exists
 	if _, err := os.Stat(input); os.IsNotExist(err) {
@@ -571,20 +1405,13 @@ func signPDFWithDigitorus(input, output, signerName string, metadata *SignatureM
 		return fmt.Errorf("failed to create PDF reader: %w", err)
 	}
 
-	// Get certificate and private key from cert files
-	certificate, privateKey, err := getSigningCertificateAndKey()
+	// Configured certificate (PKCS#12 from the environment or uploaded by an admin), or the
+	// self-signed one when none is configured
+	signingCert, err := service.ActiveSigningCertificate()
 	if err != nil {
 		return fmt.Errorf("failed to get certificate and key: %w", err)
 	}
 
-	// Get certificate chains
-	certificate_chains, err := certificate.Verify(getX509VerifyOptions())
-	if err != nil {
-		log.Printf("Warning: Certificate verification error: %v", err)
-		// Continue with empty chains
-		certificate_chains = [][]*x509.Certificate{}
-	}
-
 	// Use the signer name in uppercase for the signature
 	upperCaseSignerName := strings.ToUpper(signerName)
 
@@ -595,7 +1422,7 @@ func signPDFWithDigitorus(input, output, signerName string, metadata *SignatureM
 			metadata.SignID, metadata.SignedBy, metadata.TimeSigned)
 	}
 
-	log.Printf("Signing PDF with signer name: %s, Contact info: %s", upperCaseSignerName, contactInfo)
+	slog.Info("signing PDF", "signer_name", upperCaseSignerName, "contact_info", contactInfo)
 
 	// Sign the PDF using Digitorus - this adds the signature without modifying existing content
 	err = sign.Sign(input_file, output_file, rdr, size, sign.SignData{
@@ -610,13 +1437,13 @@ func signPDFWithDigitorus(input, output, signerName string, metadata *SignatureM
 			CertType:   sign.CertificationSignature,
 			DocMDPPerm: sign.AllowFillingExistingFormFieldsAndSignaturesPerms,
 		},
-		Signer:            privateKey,
+		Signer:            signingCert.Signer,
 		DigestAlgorithm:   crypto.SHA256,
-		Certificate:       certificate,
-		CertificateChains: certificate_chains,
-		// TSA settings for timestamp authority
+		Certificate:       signingCert.Certificate,
+		CertificateChains: service.SigningCertificateChains(signingCert),
+		// Timestamp authority of the active profile (TSA_URL), none when empty
 		TSA: sign.TSA{
-			URL:      "https://freetsa.org/tsr",
+			URL:      tsaURL,
 			Username: "",
 			Password: "",
 		},
@@ -629,117 +1456,39 @@ func signPDFWithDigitorus(input, output, signerName string, metadata *SignatureM
 		return fmt.Errorf("failed to sign PDF: %w", err)
 	}
 
-	log.Printf("PDF signed successfully. Signed PDF written to %s", output)
+	slog.Info("PDF signed successfully", "output", output)
 	return nil
 }
 
-// getSigningCertificateAndKey retrieves the certificate and private key for signing
-func getSigningCertificateAndKey() (*x509.Certificate, crypto.Signer, error) {
-	// In a real implementation, you would load your certificate and private key
-	// For this example, we'll use the self-signed certificate generated by service.GenerateSelfSignedCert
-
-	certPath := filepath.Join("./certs", "certificate.crt")
-	keyPath := filepath.Join("./certs", "private.key")
-
-	// Load certificate
-	certData, err := os.ReadFile(certPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	certificate, err := parseCertificate(certData)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Load private key
-	keyData, err := os.ReadFile(keyPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	privateKey, err := parsePrivateKey(keyData)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return certificate, privateKey, nil
-}
-
-// parseCertificate parses a PEM encoded certificate
-func parseCertificate(certPEMBlock []byte) (*x509.Certificate, error) {
-	block, _ := pem.Decode(certPEMBlock)
-	if block == nil || block.Type != "CERTIFICATE" {
-		return nil, errors.New("failed to decode PEM block containing certificate")
-	}
-
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return nil, err
-	}
-
-	return cert, nil
-}
-
-// parsePrivateKey parses a PEM encoded private key
-func parsePrivateKey(keyPEMBlock []byte) (crypto.Signer, error) {
-	block, _ := pem.Decode(keyPEMBlock)
-	if block == nil {
-		return nil, errors.New("failed to decode PEM block containing private key")
-	}
-
-	switch block.Type {
-	case "RSA PRIVATE KEY":
-		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
-		if err != nil {
-			return nil, err
-		}
-		return privateKey, nil
-
-	case "PRIVATE KEY":
-		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
-		if err != nil {
-			return nil, err
-		}
-
-		switch k := privateKey.(type) {
-		case *rsa.PrivateKey:
-			return k, nil
-		default:
-			return nil, errors.New("unsupported private key type")
-		}
-
-	default:
-		return nil, errors.New("unsupported PEM block type: " + block.Type)
-	}
-}
-
-// getX509VerifyOptions returns the options for certificate verification
-func getX509VerifyOptions() x509.VerifyOptions {
-	// In a real implementation, you would configure this with proper root CAs
-	return x509.VerifyOptions{
-		// You might want to add trusted roots here
-		Roots: nil, // Use system roots
-	}
-}
-
 // RejectContract marks a contract signing request as rejected
 func (ctrl *ContractSigningController) RejectContract(c *gin.Context) {
 	signingID := c.Param("id")
 	if signingID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Signing ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningIDRequired"})
 		return
 	}
 
+	// The body with the location of the signer is optional
+	var location SignerLocation
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&location); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+			return
+		}
+	}
+
 	// If repository is available, update actual record
 	if ctrl.signingRepo != nil {
-		err := ctrl.signingRepo.MarkAsRejected(c, signingID)
+		err := ctrl.signingRepo.MarkAsRejected(c, signingID, signingEvidence(c, location))
 		if err != nil {
-			log.Printf("Error marking signing request as rejected: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark signing request as rejected"})
+			logging.FromContext(c).Error("error marking signing request as rejected", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToMarkSigningRejected"})
 			return
 		}
 
+		ctrl.recordSigningEvent(c, signingID, storage.SigningEventRejected, "", "")
+		ctrl.webhooks.Dispatch(model.WebhookEventSigningRejected, signingID)
+
 		c.JSON(http.StatusOK, gin.H{
 			"id":      signingID,
 			"status":  "rejected",
@@ -760,7 +1509,7 @@ func (ctrl *ContractSigningController) RejectContract(c *gin.Context) {
 func (ctrl *ContractSigningController) ServePDF(c *gin.Context) {
 	signingId := c.Param("id")
 	if signingId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Signing ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SigningIDRequired"})
 		return
 	}
 
@@ -772,13 +1521,18 @@ func (ctrl *ContractSigningController) ServePDF(c *gin.Context) {
 		// Get the signing request
 		record, err := ctrl.signingRepo.GetByID(c, signingId)
 		if err != nil {
-			log.Printf("Error getting signing request: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get signing request"})
+			logging.FromContext(c).Error("error getting signing request", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
 			return
 		}
 
 		if record == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Signing request not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+			return
+		}
+
+		if record.IsInspection() {
+			ctrl.serveInspectionPDF(c, record, isSigned)
 			return
 		}
 
@@ -806,34 +1560,39 @@ func (ctrl *ContractSigningController) ServePDF(c *gin.Context) {
 				// Create a basic contract data structure for regenerating signed PDF
 				contractData := service.ContractPDF{
 					Renter:       &model.Person{FullName: signerName},
-					Owner:        nil, // Will use defaults
+					Owner:        nil, // Filled from the parties of the rental below
 					Property:     nil, // Will use defaults
 					Pricing:      nil, // Will use defaults
-					CoSigner:     nil, // Will use defaults
-					Witness:      nil, // Will use defaults
+					CoSigner:     nil, // Filled from the parties of the rental below
+					Witness:      nil, // Filled from the parties of the rental below
 					StartDate:    time.Now(),
 					EndDate:      time.Now().AddDate(0, 6, 0), // 6 months default
 					CreationDate: time.Now(),
+					Inventory:    ctrl.contractController.contractInventory(c, record.ContractID),
+					Guarantee:    ctrl.contractController.contractGuarantee(c, record.ContractID),
+					Organization: ctrl.recipientOrganization(c, record),
 				}
+				ctrl.contractController.applyContractParties(c, record.ContractID, &contractData)
 
-				// Regenerate the signed PDF
+				// Regenerate the signed PDF with the evidence stored when it was signed
 				signedPDFData, err := service.SimpleSignPDF(
 					contractData,
 					signerName,
 					signerEmail,
 					signingId,
+					record.Evidence(),
 				)
 
 				if err != nil {
-					log.Printf("Error regenerating signed PDF: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate signed PDF"})
+					logging.FromContext(c).Error("error regenerating signed PDF", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRegenerateSignedPDF"})
 					return
 				}
 
 				// Save the regenerated file
 				if err := os.WriteFile(pdfPath, signedPDFData, 0644); err != nil {
-					log.Printf("Error writing regenerated signed PDF: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save regenerated signed PDF"})
+					logging.FromContext(c).Error("error writing regenerated signed PDF", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveRegeneratedSignedPDF"})
 					return
 				}
 			}
@@ -869,22 +1628,22 @@ func (ctrl *ContractSigningController) ServePDF(c *gin.Context) {
 				// Generate a simple contract PDF
 				pdfData, err := service.CreateSimpleContractPDF(record.ContractID, propertyAddress, renterName)
 				if err != nil {
-					log.Printf("Error creating simple contract PDF: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate contract PDF"})
+					logging.FromContext(c).Error("error creating simple contract PDF", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateContractPDF"})
 					return
 				}
 
 				// Ensure the directory exists
 				if err := os.MkdirAll(filepath.Dir(pdfPath), 0755); err != nil {
-					log.Printf("Error creating directory for PDF: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory for PDF"})
+					logging.FromContext(c).Error("error creating directory for PDF", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePDFDirectory"})
 					return
 				}
 
 				// Save the PDF
 				if err := os.WriteFile(pdfPath, pdfData, 0644); err != nil {
-					log.Printf("Error writing contract PDF: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save contract PDF"})
+					logging.FromContext(c).Error("error writing contract PDF", "error", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveContractPDF"})
 					return
 				}
 			}
@@ -903,12 +1662,60 @@ func (ctrl *ContractSigningController) ServePDF(c *gin.Context) {
 	ctrl.createSamplePDF(c, signingId, isSigned)
 }
 
+// acknowledgeInspection signs the acta of the inspection of a signing request and marks the
+// inspection as acknowledged by the tenant
+func (ctrl *ContractSigningController) acknowledgeInspection(c *gin.Context, record *storage.ContractSigningRecord, signerName, signerEmail string, evidence *model.SigningEvidence) ([]byte, error) {
+	if ctrl.inspections == nil {
+		return nil, fmt.Errorf("inspections are not available")
+	}
+	inspectionID, err := uuid.Parse(record.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inspection ID %q: %w", record.ContractID, err)
+	}
+	return ctrl.inspections.Acknowledge(c, inspectionID, signerName, signerEmail, record.ID, evidence)
+}
+
+// serveInspectionPDF serves the acta of the inspection of a signing request, the copy signed by
+// the tenant once acknowledged
+func (ctrl *ContractSigningController) serveInspectionPDF(c *gin.Context, record *storage.ContractSigningRecord, isSigned bool) {
+	if ctrl.inspections == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "InspectionsUnavailable"})
+		return
+	}
+
+	inspectionID, err := uuid.Parse(record.ContractID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInspectionID"})
+		return
+	}
+
+	var pdfData []byte
+	if isSigned && record.Status == string(model.StatusSigned) {
+		// The acta stored with the rental files outlives the temporary copy of the record
+		signedPath := record.SignedPDFPath
+		if inspection, getErr := ctrl.inspections.GetByID(c, inspectionID); getErr == nil && inspection != nil && inspection.SignedPDFPath != "" {
+			signedPath = inspection.SignedPDFPath
+		}
+		pdfData, err = loadStoredPDF(signedPath)
+	} else {
+		pdfData, err = ctrl.inspections.RenderPDF(c, inspectionID)
+	}
+	if err != nil {
+		logging.FromContext(c).Error("error loading inspection of signing request", "record_id", record.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateInspectionPDF"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=inspeccion_%s.pdf", record.ContractID))
+	c.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
 // createSamplePDF creates and serves a sample PDF for testing or development
 func (ctrl *ContractSigningController) createSamplePDF(c *gin.Context, signingId string, isSigned bool) {
 	// Create a temporary file for the PDF
 	tempDir := filepath.Join(os.TempDir(), "contracts")
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create temp directory"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateTempDirectory"})
 		return
 	}
 
@@ -1022,7 +1829,7 @@ func (ctrl *ContractSigningController) createSamplePDF(c *gin.Context, signingId
 
 	// Write to file
 	if err := os.WriteFile(pdfPath, pdfContent, 0644); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sample PDF"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateSamplePDF"})
 		return
 	}
 
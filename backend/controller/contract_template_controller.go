@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ContractTemplateController handles HTTP requests for editable contract templates
+type ContractTemplateController struct {
+	repository storage.ContractTemplateStore
+}
+
+// NewContractTemplateController creates a new ContractTemplateController
+func NewContractTemplateController(repository storage.ContractTemplateStore) *ContractTemplateController {
+	return &ContractTemplateController{
+		repository: repository,
+	}
+}
+
+// RegisterRoutes registers the contract template routes on an admin-protected group
+func (c *ContractTemplateController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	templates := adminRouter.Group("/contract-templates")
+	{
+		templates.GET("", c.GetAll)
+		templates.GET("/placeholders", c.GetPlaceholders)
+		templates.GET("/:id", c.GetByID)
+		templates.GET("/:id/preview", c.Preview)
+		templates.POST("", c.Create)
+		templates.PUT("/:id", c.Update)
+		templates.DELETE("/:id", c.Delete)
+	}
+}
+
+// GetAll retrieves all contract templates
+func (c *ContractTemplateController) GetAll(ctx *gin.Context) {
+	templates, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if templates == nil {
+		templates = []model.ContractTemplate{}
+	}
+
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// GetPlaceholders lists the placeholders available in templates, the contract types and the
+// built-in template of each type, which can be used as the starting point of a new template
+func (c *ContractTemplateController) GetPlaceholders(ctx *gin.Context) {
+	defaultTemplates := make(map[string]model.ContractTemplate, len(model.ContractTypes))
+	for _, contractType := range model.ContractTypes {
+		defaultTemplates[contractType] = service.BuiltinContractTemplate(contractType)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"placeholders":      service.ContractPlaceholders,
+		"contract_types":    model.ContractTypes,
+		"default_template":  service.DefaultContractTemplate(),
+		"default_templates": defaultTemplates,
+	})
+}
+
+// GetByID retrieves a contract template by ID
+func (c *ContractTemplateController) GetByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	template, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if template == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, template)
+}
+
+// Preview renders a template without contract data, leaving the contract fields blank
+func (c *ContractTemplateController) Preview(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	template, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+		return
+	}
+
+	pdfBytes, err := service.GenerateContractPDF(service.ContractPDF{
+		CreationDate: time.Now(),
+		Template:     template,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error rendering contract template", "id", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRenderContractTemplate"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename=plantilla_contrato.pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// Create adds a new contract template
+func (c *ContractTemplateController) Create(ctx *gin.Context) {
+	var template model.ContractTemplate
+	if err := ctx.ShouldBindJSON(&template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if err := service.ValidateContractTemplate(template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template.ID = uuid.New()
+
+	createdTemplate, err := c.repository.Create(ctx, template)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating contract template", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateContractTemplate"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdTemplate)
+}
+
+// Update updates an existing contract template
+func (c *ContractTemplateController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var template model.ContractTemplate
+	if err := ctx.ShouldBindJSON(&template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if err := service.ValidateContractTemplate(template); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existingTemplate, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingTemplate == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+		return
+	}
+
+	template.ID = id
+
+	updatedTemplate, err := c.repository.Update(ctx, template)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedTemplate)
+}
+
+// Delete removes a contract template
+func (c *ContractTemplateController) Delete(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	existingTemplate, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingTemplate == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ContractTemplateNotFound"})
+		return
+	}
+
+	if err := c.repository.Delete(ctx, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/service"
+)
+
+// DashboardController serves the dashboard summary of admins and managers
+type DashboardController struct {
+	dashboardService *service.DashboardService
+}
+
+// NewDashboardController creates a new DashboardController
+func NewDashboardController(dashboardService *service.DashboardService) *DashboardController {
+	return &DashboardController{
+		dashboardService: dashboardService,
+	}
+}
+
+// RegisterRoutes registers the dashboard routes of the authenticated user
+func (c *DashboardController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/dashboard/summary", c.GetSummary)
+}
+
+// GetSummary returns the counts of the dashboard in a single call
+// @Summary Dashboard summary
+// @Description Active rentals, vacancies, pending signatures, overdue payments, open maintenance requests and contracts expiring in 60 days. Admins see every property and managers the properties they manage.
+// @Tags dashboard
+// @Produce json
+// @Success 200 {object} service.DashboardSummary
+// @Failure 403 {object} map[string]string "Admin or manager role required"
+// @Router /dashboard/summary [get]
+func (c *DashboardController) GetSummary(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	if authUser.Role != "admin" && authUser.Role != "manager" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminOrManagerRequired"})
+		return
+	}
+
+	summary, err := c.dashboardService.BuildSummary(ctx, authUser, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// EInvoiceController handles the DIAN electronic invoices of the rent payments
+type EInvoiceController struct {
+	einvoices *service.EInvoiceService
+}
+
+// NewEInvoiceController creates a new EInvoiceController. einvoices is nil when electronic
+// invoicing is not configured.
+func NewEInvoiceController(einvoices *service.EInvoiceService) *EInvoiceController {
+	return &EInvoiceController{
+		einvoices: einvoices,
+	}
+}
+
+// RegisterRoutes registers the electronic invoice routes on an admin-protected group
+func (c *EInvoiceController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	payments := adminRouter.Group("/payments/:id/einvoice")
+	{
+		payments.POST("", c.Submit)
+		payments.POST("/refresh", c.Refresh)
+	}
+}
+
+// Submit issues the electronic invoice of a payment
+// @Summary Issue the DIAN electronic invoice of a payment
+// @Description Generates the UBL 2.1 invoice of a rent payment and submits it through the authorized provider. Invoices rejected or not sent are submitted again with the same number.
+// @Tags payments
+// @Produce json
+// @Param id path string true "Rent Payment ID"
+// @Success 200 {object} storage.RentPayment
+// @Failure 409 {object} map[string]string "Already invoiced"
+// @Failure 503 {object} map[string]string "Electronic invoicing not configured"
+// @Router /admin/payments/{id}/einvoice [post]
+func (c *EInvoiceController) Submit(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+
+	payment, err := c.einvoices.InvoicePayment(ctx, ctx.Param("id"))
+	if err != nil {
+		if payment != nil {
+			// Stored as failed with its number, the admin can retry
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": "EInvoiceRejected", "payment": payment})
+			return
+		}
+		respondEInvoiceError(ctx, err, "FailedToIssueEInvoice")
+		return
+	}
+	ctx.JSON(http.StatusOK, payment)
+}
+
+// Refresh updates the payment with the DIAN validation state of its invoice
+// @Summary Refresh the status of the electronic invoice of a payment
+// @Tags payments
+// @Produce json
+// @Param id path string true "Rent Payment ID"
+// @Success 200 {object} storage.RentPayment
+// @Failure 503 {object} map[string]string "Electronic invoicing not configured"
+// @Router /admin/payments/{id}/einvoice/refresh [post]
+func (c *EInvoiceController) Refresh(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+
+	payment, err := c.einvoices.RefreshStatus(ctx, ctx.Param("id"))
+	if err != nil {
+		respondEInvoiceError(ctx, err, "FailedToRefreshEInvoice")
+		return
+	}
+	ctx.JSON(http.StatusOK, payment)
+}
+
+// enabled writes the 503 response when electronic invoicing is not configured
+func (c *EInvoiceController) enabled(ctx *gin.Context) bool {
+	if c.einvoices == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityEInvoice, "EInvoiceNotConfigured")
+		return false
+	}
+	return true
+}
+
+// respondEInvoiceError answers with the status matching an electronic invoice error
+func respondEInvoiceError(ctx *gin.Context, err error, messageID string) {
+	switch {
+	case errors.Is(err, service.ErrEInvoicePaymentNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PaymentNotFound"})
+	case errors.Is(err, service.ErrEInvoiceAlreadyIssued):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidEInvoice), errors.Is(err, service.ErrEInvoiceNotSubmitted):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEInvoiceRangeExhausted):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		logging.FromContext(ctx).Error("request failed", "message_id", messageID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": messageID})
+	}
+}
@@ -2,30 +2,33 @@ package controller
 
 import (
 	"context"
-	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // EmailController handles HTTP requests for sending emails
 type EmailController struct {
-	userRepo     *storage.UserRepository
-	personRepo   *storage.PersonRepository
-	rentalRepo   *storage.RentalRepository
-	propertyRepo *storage.PropertyRepository
+	userRepo     storage.UserStore
+	personRepo   storage.PersonStore
+	rentalRepo   storage.RentalStore
+	propertyRepo storage.PropertyStore
+	orgService   *service.OrganizationService
 }
 
 // NewEmailController creates a new EmailController
-func NewEmailController(userRepo *storage.UserRepository, personRepo *storage.PersonRepository, rentalRepo *storage.RentalRepository, propertyRepo *storage.PropertyRepository) *EmailController {
+func NewEmailController(userRepo storage.UserStore, personRepo storage.PersonStore, rentalRepo storage.RentalStore, propertyRepo storage.PropertyStore, orgService *service.OrganizationService) *EmailController {
 	return &EmailController{
 		userRepo:     userRepo,
 		personRepo:   personRepo,
 		rentalRepo:   rentalRepo,
 		propertyRepo: propertyRepo,
+		orgService:   orgService,
 	}
 }
 
@@ -36,6 +39,12 @@ type CustomEmailRequest struct {
 	Body              string `json:"body" binding:"required"`
 }
 
+// EmailTestRequest defines the body of a test email. Driver defaults to the configured one.
+type EmailTestRequest struct {
+	To     string `json:"to" binding:"required,email"`
+	Driver string `json:"driver"`
+}
+
 // AnnualRenewalRequest defines the structure for the annual renewal trigger
 type AnnualRenewalRequest struct {
 	OptionalMessage string `json:"optional_message"`
@@ -49,52 +58,139 @@ func (ctrl *EmailController) RegisterRoutes(adminRouter *gin.RouterGroup) {
 		emailRoutes.POST("/custom", ctrl.HandleSendCustomEmail)                                 // POST /api/admin/emails/custom (if adminRouter is /api/admin)
 		emailRoutes.POST("/annual-renewal-reminders", ctrl.HandleTriggerAnnualRenewalReminders) // New route
 	}
+
+	// Email provider diagnostics: /api/admin/email/health and /api/admin/email/test
+	driverRoutes := adminRouter.Group("/email")
+	{
+		driverRoutes.GET("/health", ctrl.HandleEmailHealth)
+		driverRoutes.POST("/test", ctrl.HandleSendTestEmail)
+	}
+}
+
+// emailSenderFor returns the configured email driver, or the named one built from its
+// environment variables
+func emailSenderFor(driver string) (service.EmailSender, error) {
+	if driver == "" || strings.EqualFold(driver, service.GetEmailSender().Name()) {
+		return service.GetEmailSender(), nil
+	}
+	return service.NewEmailSender(driver)
+}
+
+// HandleEmailHealth checks the credentials and connectivity of an email driver
+// @Summary Email driver health
+// @Description Checks the configured email driver, or the one in the driver query parameter, without sending an email
+// @Tags emails
+// @Produce json
+// @Param driver query string false "smtp, resend, ses or mailgun"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/email/health [get]
+func (ctrl *EmailController) HandleEmailHealth(ctx *gin.Context) {
+	sender, err := emailSenderFor(ctx.Query("driver"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"driver":  sender.Name(),
+		"active":  sender.Name() == service.GetEmailSender().Name(),
+		"sandbox": service.EmailSandboxDir() != "",
+		"healthy": true,
+	}
+	if err := sender.HealthCheck(ctx); err != nil {
+		logging.FromContext(ctx).Error("email driver health check failed", "name", sender.Name(), "error", err)
+		response["healthy"] = false
+		response["error"] = err.Error()
+		ctx.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// HandleSendTestEmail checks an email driver and sends a test email with it
+// @Summary Send test email
+// @Description Runs the health check of the configured email driver, or the requested one, and sends it a test email
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param request body EmailTestRequest true "Recipient and driver"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/email/test [post]
+func (ctrl *EmailController) HandleSendTestEmail(ctx *gin.Context) {
+	var req EmailTestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
+		return
+	}
+	sender, err := emailSenderFor(req.Driver)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sender.HealthCheck(ctx); err != nil {
+		logging.FromContext(ctx).Error("email driver health check failed", "name", sender.Name(), "error", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"driver": sender.Name(), "healthy": false, "sent": false, "error": err.Error()})
+		return
+	}
+	if err := service.SendTestEmail(ctx, sender, req.To); err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"driver": sender.Name(), "healthy": true, "sent": false, "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"driver":  sender.Name(),
+		"healthy": true,
+		"sent":    true,
+		"sandbox": service.EmailSandboxDir() != "",
+		"message": "Test email sent to " + req.To,
+	})
 }
 
 // HandleSendCustomEmail sends a custom email to a specified person
 func (ctrl *EmailController) HandleSendCustomEmail(ctx *gin.Context) {
 	var req CustomEmailRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
 		return
 	}
 
 	recipientPersonUUID, err := uuid.Parse(req.RecipientPersonID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recipient_person_id format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRecipientPersonID"})
 		return
 	}
 
 	// Fetch the user record for the recipient to get their email
 	recipientUser, err := ctrl.userRepo.GetByPersonID(ctx, recipientPersonUUID)
 	if err != nil {
-		log.Printf("Error fetching user for person ID %s: %v", req.RecipientPersonID, err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve recipient details"})
+		logging.FromContext(ctx).Error("error fetching user for person", "recipient_person_id", req.RecipientPersonID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveRecipientDetails"})
 		return
 	}
 	if recipientUser == nil || recipientUser.Email == "" {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Recipient user not found or email is missing"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RecipientUserEmailNotFound"})
 		return
 	}
 
 	if req.Subject == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Subject cannot be empty"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "EmailSubjectRequired"})
 		return
 	}
 	if req.Body == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Body cannot be empty"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "EmailBodyRequired"})
 		return
 	}
 
 	// Call the email service function
 	err = service.SendSimpleEmail(recipientUser.Email, req.Subject, req.Body)
 	if err != nil {
-		log.Printf("Error sending custom email to %s: %v", recipientUser.Email, err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send email"})
+		logging.FromContext(ctx).Error("error sending custom email", "email", recipientUser.Email, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSendEmail"})
 		return
 	}
 
-	log.Printf("Custom email sent successfully to %s (PersonID: %s)", recipientUser.Email, req.RecipientPersonID)
+	logging.FromContext(ctx).Info("custom email sent successfully", "email", recipientUser.Email, "recipient_person_id", req.RecipientPersonID)
 	ctx.JSON(http.StatusOK, gin.H{"message": "Custom email sent successfully to " + recipientUser.Email})
 }
 
@@ -103,7 +199,7 @@ func (ctrl *EmailController) HandleTriggerAnnualRenewalReminders(ctx *gin.Contex
 	var req AnnualRenewalRequest
 	// BindJSON will bind an empty struct if the body is empty or not JSON, which is fine for optional fields.
 	if err := ctx.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" { // Allow empty body for no optional message
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
 		return
 	}
 
@@ -111,13 +207,13 @@ func (ctrl *EmailController) HandleTriggerAnnualRenewalReminders(ctx *gin.Contex
 	go func() {
 		// Create a new background context for the goroutine
 		bgCtx := context.Background()
-		emailsSent, err := service.SendAnnualRenewalReminders(bgCtx, ctrl.personRepo, ctrl.rentalRepo, ctrl.propertyRepo, ctrl.userRepo, req.OptionalMessage)
+		emailsSent, err := service.SendAnnualRenewalReminders(bgCtx, ctrl.personRepo, ctrl.rentalRepo, ctrl.propertyRepo, ctrl.userRepo, ctrl.orgService, req.OptionalMessage)
 		if err != nil {
-			log.Printf("❌ [ERROR] HandleTriggerAnnualRenewalReminders: Error in service call: %v", err)
+			logging.FromContext(ctx).Error("handleTriggerAnnualRenewalReminders: Error in service call", "error", err)
 			// Since this is a background task, we can't directly return an HTTP error for this failure.
 			// Logging is the primary way to observe issues here.
 		} else {
-			log.Printf("ℹ️ [INFO] HandleTriggerAnnualRenewalReminders: Service call completed. Emails sent: %d", emailsSent)
+			logging.FromContext(ctx).Info("handleTriggerAnnualRenewalReminders: Service call completed", "emails_sent", emailsSent)
 		}
 	}()
 
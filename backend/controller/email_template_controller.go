@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// EmailTemplateController lets admins customize the rent reminder and signing emails
+type EmailTemplateController struct {
+	templates *service.EmailTemplateService
+}
+
+// NewEmailTemplateController creates a new EmailTemplateController
+func NewEmailTemplateController(templates *service.EmailTemplateService) *EmailTemplateController {
+	return &EmailTemplateController{
+		templates: templates,
+	}
+}
+
+// EmailTemplateRequest is the body of a new version of an email template. Subject and body are
+// Go templates using the placeholders of the email.
+type EmailTemplateRequest struct {
+	Subject string `json:"subject" binding:"required"`
+	Body    string `json:"body" binding:"required"`
+}
+
+// EmailTemplatePreviewRequest is the body of a preview. Empty fields use the version that is
+// currently sent.
+type EmailTemplatePreviewRequest struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// RegisterRoutes registers the email template routes in the admin group
+func (c *EmailTemplateController) RegisterRoutes(router *gin.RouterGroup) {
+	templates := router.Group("/email-templates")
+	{
+		templates.GET("", c.HandleListTemplates)
+		templates.GET("/:key", c.HandleGetTemplate)
+		templates.PUT("/:key", c.HandleSaveTemplate)
+		templates.DELETE("/:key", c.HandleResetTemplate)
+		templates.POST("/:key/preview", c.HandlePreviewTemplate)
+	}
+}
+
+// emailTemplateResponse describes an email with the version that is currently sent
+func emailTemplateResponse(definition service.EmailTemplateDefinition, current *model.EmailTemplate) gin.H {
+	response := gin.H{
+		"key":             definition.Key,
+		"name":            definition.Name,
+		"description":     definition.Description,
+		"placeholders":    definition.Placeholders,
+		"default_subject": definition.DefaultSubject,
+		"default_body":    definition.DefaultBody,
+		"customized":      current != nil,
+		"subject":         definition.DefaultSubject,
+		"body":            definition.DefaultBody,
+		"version":         0,
+	}
+	if current != nil {
+		response["subject"] = current.Subject
+		response["body"] = current.Body
+		response["version"] = current.Version
+		response["updated_at"] = current.CreatedAt
+	}
+	return response
+}
+
+// respondEmailTemplateError answers with the status matching an email template error
+func respondEmailTemplateError(ctx *gin.Context, err error, messageID string) {
+	switch {
+	case errors.Is(err, service.ErrEmailTemplateNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidEmailTemplate):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logging.FromContext(ctx).Error("request failed", "message_id", messageID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": messageID})
+	}
+}
+
+// HandleListTemplates lists the customizable emails
+// @Summary List email templates
+// @Description Lists the emails admins can customize with the subject and body currently sent
+// @Tags emails
+// @Produce json
+// @Success 200 {array} map[string]interface{}
+// @Router /admin/email-templates [get]
+func (c *EmailTemplateController) HandleListTemplates(ctx *gin.Context) {
+	definitions := service.EmailTemplateDefinitions()
+	response := make([]gin.H, 0, len(definitions))
+	for _, definition := range definitions {
+		current, err := c.templates.Current(ctx, definition.Key)
+		if err != nil {
+			respondEmailTemplateError(ctx, err, "FailedToGetEmailTemplates")
+			return
+		}
+		response = append(response, emailTemplateResponse(definition, current))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// HandleGetTemplate returns an email template with its saved versions
+// @Summary Get email template
+// @Description Returns the subject and body currently sent, the default, the placeholders and the saved versions
+// @Tags emails
+// @Produce json
+// @Param key path string true "Template key"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/email-templates/{key} [get]
+func (c *EmailTemplateController) HandleGetTemplate(ctx *gin.Context) {
+	key := ctx.Param("key")
+	definition, err := service.GetEmailTemplateDefinition(key)
+	if err != nil {
+		respondEmailTemplateError(ctx, err, "FailedToGetEmailTemplate")
+		return
+	}
+	versions, err := c.templates.Versions(ctx, key)
+	if err != nil {
+		respondEmailTemplateError(ctx, err, "FailedToGetEmailTemplate")
+		return
+	}
+
+	var current *model.EmailTemplate
+	if len(versions) > 0 {
+		current = &versions[0]
+	}
+	response := emailTemplateResponse(*definition, current)
+	response["versions"] = versions
+	ctx.JSON(http.StatusOK, response)
+}
+
+// HandleSaveTemplate saves a new version of an email template
+// @Summary Save email template
+// @Description Validates the template against the data of the email and saves it as the version that is sent
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param key path string true "Template key"
+// @Param request body EmailTemplateRequest true "Subject and body"
+// @Success 201 {object} model.EmailTemplate
+// @Router /admin/email-templates/{key} [put]
+func (c *EmailTemplateController) HandleSaveTemplate(ctx *gin.Context) {
+	var req EmailTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
+		return
+	}
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	saved, err := c.templates.Save(ctx, ctx.Param("key"), req.Subject, req.Body, &authUser.PersonID)
+	if err != nil {
+		respondEmailTemplateError(ctx, err, "FailedToSaveEmailTemplate")
+		return
+	}
+	ctx.JSON(http.StatusCreated, saved)
+}
+
+// HandleResetTemplate deletes the saved versions of an email template
+// @Summary Reset email template
+// @Description Deletes the saved versions so the default email is sent again
+// @Tags emails
+// @Produce json
+// @Param key path string true "Template key"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/email-templates/{key} [delete]
+func (c *EmailTemplateController) HandleResetTemplate(ctx *gin.Context) {
+	if err := c.templates.Reset(ctx, ctx.Param("key")); err != nil {
+		respondEmailTemplateError(ctx, err, "FailedToResetEmailTemplate")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Email template reset to the default"})
+}
+
+// HandlePreviewTemplate renders an email template with sample data
+// @Summary Preview email template
+// @Description Renders the given subject and body, or the version currently sent, with sample data of the email
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param key path string true "Template key"
+// @Param request body EmailTemplatePreviewRequest false "Unsaved subject and body"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/email-templates/{key}/preview [post]
+func (c *EmailTemplateController) HandlePreviewTemplate(ctx *gin.Context) {
+	var req EmailTemplatePreviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
+			return
+		}
+	}
+
+	subject, html, err := c.templates.Preview(ctx, ctx.Param("key"), req.Subject, req.Body)
+	if err != nil {
+		respondEmailTemplateError(ctx, err, "FailedToPreviewEmailTemplate")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"subject": subject, "html": html})
+}
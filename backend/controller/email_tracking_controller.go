@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// trackingPixel is a transparent 1x1 GIF
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// EmailTrackingController records when emails sent through the outbox are opened
+type EmailTrackingController struct {
+	outbox *service.EmailOutbox
+}
+
+// NewEmailTrackingController creates a new EmailTrackingController
+func NewEmailTrackingController(outbox *service.EmailOutbox) *EmailTrackingController {
+	return &EmailTrackingController{
+		outbox: outbox,
+	}
+}
+
+// RegisterPublicRoutes registers the tracking pixel route, loaded by email clients without authentication
+func (c *EmailTrackingController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/public/email/open/:file", c.TrackOpen)
+}
+
+// TrackOpen records the open of an email and returns the tracking pixel. The pixel is returned
+// even for unknown emails so nothing is revealed to the email client.
+func (c *EmailTrackingController) TrackOpen(ctx *gin.Context) {
+	id, err := uuid.Parse(strings.TrimSuffix(ctx.Param("file"), ".gif"))
+	if err == nil {
+		if err := c.outbox.RecordOpen(ctx, id); err != nil {
+			logging.FromContext(ctx).Warn("could not record the open of email", "id", id, "error", err)
+		}
+	}
+
+	ctx.Header("Cache-Control", "no-store, no-cache, must-revalidate")
+	ctx.Data(http.StatusOK, "image/gif", trackingPixel)
+}
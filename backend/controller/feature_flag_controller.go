@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// FeatureFlagController lets the admins turn features and scheduled jobs on and off without a
+// restart
+type FeatureFlagController struct {
+	flags *service.FeatureFlagService
+}
+
+// NewFeatureFlagController creates a new FeatureFlagController
+func NewFeatureFlagController(flags *service.FeatureFlagService) *FeatureFlagController {
+	return &FeatureFlagController{
+		flags: flags,
+	}
+}
+
+// UpdateFeatureFlagRequest turns a feature flag on or off
+type UpdateFeatureFlagRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// RegisterAdminRoutes registers the feature flag routes on an admin-protected group
+func (c *FeatureFlagController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	flags := adminRouter.Group("/feature-flags")
+	{
+		flags.GET("", c.ListFeatureFlags)
+		flags.PUT("/:key", c.UpdateFeatureFlag)
+		flags.DELETE("/:key", c.ResetFeatureFlag)
+	}
+}
+
+// respondFeatureFlagError answers with the status of a feature flag error
+func respondFeatureFlagError(ctx *gin.Context, err error, messageID string) {
+	switch {
+	case errors.Is(err, service.ErrUnknownFeatureFlag):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFeatureUnavailable):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logging.FromContext(ctx).Error("request failed", "message_id", messageID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": messageID})
+	}
+}
+
+// ListFeatureFlags lists every feature flag with its current value
+// @Summary List feature flags
+// @Description Lists the features and scheduled jobs that can be toggled at runtime, with their value and whether it comes from the default, the environment or an admin
+// @Tags admin
+// @Produce json
+// @Success 200 {array} model.FeatureFlag
+// @Router /admin/feature-flags [get]
+func (c *FeatureFlagController) ListFeatureFlags(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.flags.All(ctx))
+}
+
+// UpdateFeatureFlag turns a feature flag on or off
+// @Summary Toggle a feature flag
+// @Description Turns a feature or scheduled job on or off for every instance, without a restart. Other instances pick the change up within FEATURE_FLAG_REFRESH.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Feature flag key"
+// @Param request body UpdateFeatureFlagRequest true "New value"
+// @Success 200 {object} model.FeatureFlag
+// @Failure 404 {object} string "Unknown feature flag"
+// @Failure 409 {object} string "The feature is not configured"
+// @Router /admin/feature-flags/{key} [put]
+func (c *FeatureFlagController) UpdateFeatureFlag(ctx *gin.Context) {
+	adminID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateFeatureFlagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequest", "detail": err.Error()})
+		return
+	}
+
+	flag, err := c.flags.Set(ctx, ctx.Param("key"), *req.Enabled, adminID)
+	if err != nil {
+		respondFeatureFlagError(ctx, err, "FailedToSaveFeatureFlag")
+		return
+	}
+	ctx.JSON(http.StatusOK, flag)
+}
+
+// ResetFeatureFlag removes the value an admin saved for a feature flag
+// @Summary Reset a feature flag
+// @Description Removes the value saved by an admin, so the flag goes back to its environment variable or default
+// @Tags admin
+// @Produce json
+// @Param key path string true "Feature flag key"
+// @Success 200 {object} model.FeatureFlag
+// @Failure 404 {object} string "Unknown feature flag"
+// @Router /admin/feature-flags/{key} [delete]
+func (c *FeatureFlagController) ResetFeatureFlag(ctx *gin.Context) {
+	flag, err := c.flags.Reset(ctx, ctx.Param("key"))
+	if err != nil {
+		respondFeatureFlagError(ctx, err, "FailedToResetFeatureFlag")
+		return
+	}
+	ctx.JSON(http.StatusOK, flag)
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// maxBatchFiles es la cantidad máxima de archivos de una operación en lote
+const maxBatchFiles = 500
+
+// BatchFilesRequest estructura con las rutas de una operación en lote
+type BatchFilesRequest struct {
+	Paths []string `json:"paths" binding:"required,min=1"`
+}
+
+// BatchFileResult es el resultado de una operación en lote para un archivo
+type BatchFileResult struct {
+	Path    string `json:"path"`
+	Success bool   `json:"success"`
+	Trashed bool   `json:"trashed,omitempty"` // Se movió a la papelera
+	Error   string `json:"error,omitempty"`
+}
+
+// HandleFileAction atiende los POST bajo /files: zip, bulk-delete, reindex y {ruta}/share. Gin no permite
+// rutas fijas junto al comodín de la ruta del archivo, así que se distinguen aquí.
+func (ctrl *FileUploadController) HandleFileAction(ctx *gin.Context) {
+	filePath := strings.TrimPrefix(ctx.Param("filePath"), "/")
+	switch {
+	case filePath == "zip":
+		ctrl.HandleDownloadFilesZip(ctx)
+	case filePath == "bulk-delete":
+		ctrl.HandleBulkDeleteFiles(ctx)
+	case filePath == "reindex":
+		ctrl.HandleReindexFiles(ctx)
+	case strings.HasSuffix(filePath, "/share"):
+		ctrl.HandleShareFile(ctx)
+	default:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RouteNotFound"})
+	}
+}
+
+// bindBatchFiles valida que el usuario sea admin y lee las rutas de la operación, sin repetidas
+func bindBatchFiles(ctx *gin.Context) (*model.User, []string, bool) {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return nil, nil, false
+	}
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return nil, nil, false
+	}
+	if authUser.Role != "admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsManageFileBatches"})
+		return nil, nil, false
+	}
+
+	var req BatchFilesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return nil, nil, false
+	}
+
+	seen := make(map[string]bool, len(req.Paths))
+	paths := make([]string, 0, len(req.Paths))
+	for _, path := range req.Paths {
+		path = strings.TrimPrefix(strings.TrimSpace(path), "/")
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "BatchFilesRequired"})
+		return nil, nil, false
+	}
+	if len(paths) > maxBatchFiles {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "TooManyBatchFiles", "params": gin.H{"Max": maxBatchFiles}})
+		return nil, nil, false
+	}
+	return authUser, paths, true
+}
+
+// HandleDownloadFilesZip descarga varios archivos en un ZIP
+// @Summary Descargar archivos en ZIP
+// @Description Genera un ZIP con los archivos seleccionados a medida que se descargan (solo admins). Los archivos que no se pudieron leer se listan en ERRORES.txt dentro del ZIP.
+// @Tags file-upload
+// @Accept json
+// @Produce application/zip
+// @Param request body BatchFilesRequest true "Rutas de los archivos"
+// @Success 200 {file} binary
+// @Router /admin/file-upload/files/zip [post]
+func (ctrl *FileUploadController) HandleDownloadFilesZip(ctx *gin.Context) {
+	authUser, paths, ok := bindBatchFiles(ctx)
+	if !ok {
+		return
+	}
+
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	fileName := fmt.Sprintf("archivos_%s.zip", time.Now().Format("20060102_150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Status(http.StatusOK)
+
+	// El ZIP se escribe a medida que se leen los archivos, sin cargarlos en memoria
+	archive := zip.NewWriter(ctx.Writer)
+	var failed []string
+	added := 0
+	for _, path := range paths {
+		if err := addFileToZip(archive, supabaseStorage, path); err != nil {
+			logging.FromContext(ctx).Warn("error agregando al ZIP", "path", path, "error", err)
+			failed = append(failed, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		added++
+	}
+
+	if len(failed) > 0 {
+		if entry, err := archive.Create("ERRORES.txt"); err == nil {
+			fmt.Fprintf(entry, "No se pudieron incluir %d archivos:\n%s\n", len(failed), strings.Join(failed, "\n"))
+		}
+	}
+	if err := archive.Close(); err != nil {
+		logging.FromContext(ctx).Error("error cerrando ZIP", "error", err)
+		return
+	}
+
+	logging.FromContext(ctx).Info("ZIP de archivos descargado por admin", "added", added, "paths_count", len(paths), "email", authUser.Email)
+}
+
+// addFileToZip copia un archivo del almacenamiento al ZIP, con su ruta en el bucket
+func addFileToZip(archive *zip.Writer, supabaseStorage *service.SupabaseStorageService, path string) error {
+	content, err := supabaseStorage.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, content)
+	return err
+}
+
+// HandleBulkDeleteFiles elimina varios archivos y devuelve el resultado de cada uno
+// @Summary Eliminar archivos en lote
+// @Description Elimina (o mueve a la papelera) los archivos seleccionados y devuelve el resultado de cada uno (solo admins)
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param request body BatchFilesRequest true "Rutas de los archivos"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/files/bulk-delete [post]
+func (ctrl *FileUploadController) HandleBulkDeleteFiles(ctx *gin.Context) {
+	authUser, paths, ok := bindBatchFiles(ctx)
+	if !ok {
+		return
+	}
+
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	results := make([]BatchFileResult, 0, len(paths))
+	deleted := 0
+	for _, path := range paths {
+		trashed, err := supabaseStorage.DeleteFile(path, authUser.Email)
+		if err != nil {
+			logging.FromContext(ctx).Error("error eliminando archivo", "path", path, "error", err)
+			results = append(results, BatchFileResult{Path: path, Error: err.Error()})
+			continue
+		}
+		if trashed == nil {
+			ctrl.forgetFileMetadata(ctx, path)
+		}
+		results = append(results, BatchFileResult{Path: path, Success: true, Trashed: trashed != nil})
+		deleted++
+	}
+
+	logging.FromContext(ctx).Info("archivos eliminados en lote por admin", "deleted", deleted, "paths_count", len(paths), "email", authUser.Email)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": deleted == len(paths),
+		"deleted": deleted,
+		"failed":  len(paths) - deleted,
+		"results": results,
+	})
+}
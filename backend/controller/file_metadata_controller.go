@@ -0,0 +1,371 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// newFileCategorization valida la categoría y normaliza las etiquetas de un archivo
+func newFileCategorization(category string, tags []string) (string, []string, error) {
+	if category != "" && !model.IsValidFileCategory(category) {
+		return "", nil, fmt.Errorf("categoría inválida: %s (cedula, contrato, comprobante_pago, certificado_laboral u otro)", category)
+	}
+	normalized := model.NormalizeFileTags(tags)
+	if len(normalized) > model.MaxFileTags {
+		return "", nil, fmt.Errorf("se permiten como máximo %d etiquetas", model.MaxFileTags)
+	}
+	for _, tag := range normalized {
+		if len([]rune(tag)) > model.MaxFileTagLength {
+			return "", nil, fmt.Errorf("la etiqueta %q supera los %d caracteres", tag, model.MaxFileTagLength)
+		}
+	}
+	return category, normalized, nil
+}
+
+// FileMetadataRequest son los datos opcionales con los que se sube un documento
+type FileMetadataRequest struct {
+	Category   string   `json:"category"` // cedula, contrato, comprobante_pago, certificado_laboral u otro
+	Tags       []string `json:"tags"`
+	RentalID   string   `json:"rental_id"`
+	ContractID string   `json:"contract_id"`
+}
+
+// formFileMetadataRequest lee los campos category, tags (repetidos o separados por comas),
+// rental_id y contract_id del formulario de subida
+func formFileMetadataRequest(ctx *gin.Context) FileMetadataRequest {
+	return FileMetadataRequest{
+		Category:   ctx.PostForm("category"),
+		Tags:       ctx.PostFormArray("tags"),
+		RentalID:   ctx.PostForm("rental_id"),
+		ContractID: ctx.PostForm("contract_id"),
+	}
+}
+
+// resolveFileMetadata valida los datos con los que userID sube un documento: la categoría, las
+// etiquetas y el arriendo o contrato al que se vincula, que el usuario debe poder ver. Responde
+// con el error y devuelve false si no son válidos.
+func (ctrl *FileUploadController) resolveFileMetadata(ctx *gin.Context, userID string, req FileMetadataRequest) (*model.FileMetadata, bool) {
+	category, tags, err := newFileCategorization(req.Category, req.Tags)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	metadata := &model.FileMetadata{
+		Category:   category,
+		Tags:       tags,
+		ContractID: req.ContractID,
+	}
+	if req.RentalID == "" && req.ContractID == "" {
+		return metadata, true
+	}
+
+	user := ctrl.uploader(ctx, userID)
+	if user == nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "CannotLinkFiles"})
+		return nil, false
+	}
+
+	if req.RentalID != "" {
+		rentalID, err := uuid.Parse(req.RentalID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDParam"})
+			return nil, false
+		}
+		if !ctrl.requireRentalAccess(ctx, user, rentalID) {
+			return nil, false
+		}
+		metadata.RentalID = &rentalID
+	}
+
+	if req.ContractID != "" {
+		// Los contratos creados con el arriendo usan su ID
+		if rentalID, err := uuid.Parse(req.ContractID); err == nil {
+			if rental, err := ctrl.rentalRepo.GetByID(ctx, rentalID); err == nil && rental != nil {
+				if metadata.RentalID != nil && *metadata.RentalID != rentalID {
+					ctx.JSON(http.StatusBadRequest, gin.H{"error": "ContractNotOfRental"})
+					return nil, false
+				}
+				if !ctrl.canAccessRental(ctx, user, rental) {
+					ctx.JSON(http.StatusForbidden, gin.H{"error": "NoContractAccess"})
+					return nil, false
+				}
+				metadata.RentalID = &rentalID
+				return metadata, true
+			}
+		}
+		if !ctrl.requireContractAccess(ctx, user, req.ContractID) {
+			return nil, false
+		}
+	}
+
+	return metadata, true
+}
+
+// uploader obtiene el usuario que sube un archivo
+func (ctrl *FileUploadController) uploader(ctx *gin.Context, userID string) *model.User {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil
+	}
+	user, err := ctrl.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// canAccessRental indica si un usuario puede ver un arriendo: los admins todos, los managers los
+// de sus propiedades y los residentes los suyos
+func (ctrl *FileUploadController) canAccessRental(ctx *gin.Context, user *model.User, rental *model.Rental) bool {
+	switch user.Role {
+	case "admin":
+		return true
+	case "manager":
+		property, err := ctrl.propertyRepo.GetByID(ctx, rental.PropertyID)
+		if err != nil || property == nil {
+			return false
+		}
+		for _, managerID := range property.ManagerIDs {
+			if managerID == user.PersonID {
+				return true
+			}
+		}
+		return false
+	default:
+		return rental.RenterID == user.PersonID
+	}
+}
+
+// requireRentalAccess verifica que el arriendo exista y que el usuario pueda verlo, respondiendo
+// con el error si no
+func (ctrl *FileUploadController) requireRentalAccess(ctx *gin.Context, user *model.User, rentalID uuid.UUID) bool {
+	rental, err := ctrl.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetFileRental"})
+		return false
+	}
+	if rental == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return false
+	}
+	if !ctrl.canAccessRental(ctx, user, rental) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NoRentalAccess"})
+		return false
+	}
+	return true
+}
+
+// requireContractAccess verifica que exista una solicitud de firma del contrato y que el usuario
+// sea admin, manager o uno de sus firmantes, respondiendo con el error si no
+func (ctrl *FileUploadController) requireContractAccess(ctx *gin.Context, user *model.User, contractID string) bool {
+	records, err := ctrl.signingRepo.GetByContractID(ctx, contractID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetFileContract"})
+		return false
+	}
+	if len(records) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ContractNotFound"})
+		return false
+	}
+	if user.Role == "admin" || user.Role == "manager" {
+		return true
+	}
+	for _, record := range records {
+		if record.RecipientID == user.PersonID.String() {
+			return true
+		}
+	}
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "NoContractAccess"})
+	return false
+}
+
+// recordFileMetadata guarda la categoría, las etiquetas y el arriendo o contrato de un archivo
+// subido y los agrega a la respuesta. Un error se registra sin afectar la subida, que ya se completó.
+func (ctrl *FileUploadController) recordFileMetadata(ctx *gin.Context, upload *service.SupabaseUploadResponse, userID string, metadata *model.FileMetadata) {
+	if metadata == nil || metadata.IsEmpty() {
+		return
+	}
+
+	record := *metadata
+	record.FileName = filepath.Base(upload.Path)
+	record.Path = upload.Path
+	record.OriginalName = upload.Name
+	record.Size = upload.Size
+	record.MimeType = upload.MimeType
+	record.UserID = userID
+	record.UploadedBy = upload.UploadedBy
+	if _, err := ctrl.fileMetadataRepo.Save(ctx, record); err != nil {
+		logging.FromContext(ctx).Warn("error guardando los datos del archivo", "path", upload.Path, "error", err)
+		return
+	}
+
+	upload.Category = record.Category
+	upload.Tags = record.Tags
+	if record.RentalID != nil {
+		upload.RentalID = record.RentalID.String()
+	}
+	upload.ContractID = record.ContractID
+}
+
+// filterFilesByMetadata agrega la categoría, las etiquetas y los vínculos a los archivos listados
+// y, con los parámetros category o tag, deja solo los que coinciden. Responde 400 si el filtro no
+// es válido.
+func (ctrl *FileUploadController) filterFilesByMetadata(ctx *gin.Context, files []service.SupabaseFileInfo, userID string) ([]service.SupabaseFileInfo, bool) {
+	category, tags, err := newFileCategorization(ctx.Query("category"), ctx.QueryArray("tag"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	filter := model.FileMetadataFilter{
+		UserID:   userID,
+		Category: category,
+		Tags:     tags,
+	}
+
+	metadata, err := ctrl.fileMetadataRepo.Find(ctx, filter)
+	if err != nil {
+		if filter.IsEmpty() {
+			// Sin filtro el listado sigue siendo útil aunque falten las categorías
+			logging.FromContext(ctx).Warn("error obteniendo categorías de archivos", "error", err)
+			return files, true
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFilterFiles"})
+		return nil, false
+	}
+
+	byName := make(map[string]model.FileMetadata, len(metadata))
+	for _, m := range metadata {
+		byName[m.FileName] = m
+	}
+
+	filtered := make([]service.SupabaseFileInfo, 0, len(files))
+	for _, file := range files {
+		m, ok := byName[filepath.Base(file.Path)]
+		if ok {
+			file.Category = m.Category
+			file.Tags = m.Tags
+			if m.RentalID != nil {
+				file.RentalID = m.RentalID.String()
+			}
+			file.ContractID = m.ContractID
+		} else if !filter.IsEmpty() {
+			continue
+		}
+		filtered = append(filtered, file)
+	}
+	return filtered, true
+}
+
+// forgetFileMetadata elimina los datos de un archivo eliminado
+func (ctrl *FileUploadController) forgetFileMetadata(ctx *gin.Context, filePath string) {
+	if err := ctrl.fileMetadataRepo.DeleteByFileName(ctx, filepath.Base(filePath)); err != nil {
+		logging.FromContext(ctx).Warn("error eliminando los datos del archivo", "file_path", filePath, "error", err)
+	}
+}
+
+// HandleListRentalFiles lista los documentos vinculados a un arriendo o a su contrato
+// @Summary Listar documentos de un arriendo
+// @Description Lista los archivos subidos con el arriendo o su contrato. Los admins ven todos, los managers los de sus propiedades y los residentes los suyos.
+// @Tags rentals
+// @Produce json
+// @Param id path string true "ID del arriendo"
+// @Success 200 {array} model.FileMetadata
+// @Router /rentals/{id}/files [get]
+func (ctrl *FileUploadController) HandleListRentalFiles(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+	if !ctrl.requireRentalAccess(ctx, authUser, rentalID) {
+		return
+	}
+
+	files, err := ctrl.fileMetadataRepo.GetByRental(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "RentalDocumentsFailed"})
+		return
+	}
+	if files == nil {
+		files = []model.FileMetadata{}
+	}
+
+	ctx.JSON(http.StatusOK, files)
+}
+
+// HandleDownloadRentalFile descarga un documento vinculado a un arriendo
+// @Summary Descargar documento de un arriendo
+// @Tags rentals
+// @Produce application/octet-stream
+// @Param id path string true "ID del arriendo"
+// @Param fileId path string true "ID del documento"
+// @Success 200 {file} binary
+// @Router /rentals/{id}/files/{fileId} [get]
+func (ctrl *FileUploadController) HandleDownloadRentalFile(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+	fileID, err := uuid.Parse(ctx.Param("fileId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidFileIDFormat"})
+		return
+	}
+	if !ctrl.requireRentalAccess(ctx, authUser, rentalID) {
+		return
+	}
+
+	files, err := ctrl.fileMetadataRepo.GetByRental(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "RentalDocumentsFailed"})
+		return
+	}
+	var file *model.FileMetadata
+	for i := range files {
+		if files[i].ID == fileID {
+			file = &files[i]
+			break
+		}
+	}
+	if file == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "DocumentNotFound"})
+		return
+	}
+
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	data, err := supabaseStorage.DownloadFile(file.Path)
+	if err != nil {
+		logging.FromContext(ctx).Error("error descargando documento del arriendo", "path", file.Path, "rental_id", rentalID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FileDownloadFailed"})
+		return
+	}
+
+	contentType := file.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", file.OriginalName))
+	ctx.Data(http.StatusOK, contentType, data)
+}
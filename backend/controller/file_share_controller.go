@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// ShareFileRequest estructura para pedir un enlace compartido
+type ShareFileRequest struct {
+	ExpiresInMinutes int `json:"expires_in_minutes" binding:"min=0"` // 0 usa FILE_SHARE_TTL
+}
+
+// HandleShareFile genera un enlace de descarga que caduca para compartir un archivo
+// @Summary Compartir archivo
+// @Description Genera un enlace firmado de corta duración para descargar un archivo sin iniciar sesión (solo admins)
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param filePath path string true "Ruta del archivo"
+// @Param request body ShareFileRequest false "Vigencia del enlace"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/files/{filePath}/share [post]
+func (ctrl *FileUploadController) HandleShareFile(ctx *gin.Context) {
+	filePath, ok := strings.CutSuffix(strings.TrimPrefix(ctx.Param("filePath"), "/"), "/share")
+	if !ok || filePath == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RouteNotFound"})
+		return
+	}
+
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return
+	}
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return
+	}
+
+	// Solo admin puede compartir archivos, igual que descargarlos
+	if authUser.Role != "admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsShareFiles"})
+		return
+	}
+
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	var req ShareFileRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+			return
+		}
+	}
+	ttl := supabaseStorage.ShareTTL()
+	if req.ExpiresInMinutes > 0 {
+		ttl = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+	if ttl > service.MaxFileShareTTL {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ShareTTLTooLong", "params": gin.H{"Minutes": int(service.MaxFileShareTTL.Minutes())}})
+		return
+	}
+
+	url, err := supabaseStorage.SignedURL(filePath, ttl)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generando enlace compartido", "file_path", filePath, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateShareLink"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("enlace compartido generado", "file_path", filePath, "email", authUser.Email, "ttl", ttl)
+	ctx.JSON(http.StatusOK, gin.H{
+		"url":        url,
+		"path":       filePath,
+		"expires_at": time.Now().Add(ttl).Format(time.RFC3339),
+	})
+}
+
+// HandleDownloadSharedFile sirve un archivo con un enlace firmado por la API, usado por los
+// backends de almacenamiento que no firman URLs (disco local)
+// @Summary Descargar archivo compartido
+// @Tags file-upload
+// @Produce application/octet-stream
+// @Param token path string true "Token del enlace"
+// @Success 200 {file} binary
+// @Router /files/shared/{token} [get]
+func (ctrl *FileUploadController) HandleDownloadSharedFile(ctx *gin.Context) {
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	filePath, err := supabaseStorage.ParseShareToken(ctx.Param("token"))
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := supabaseStorage.DownloadFile(filePath)
+	if err != nil {
+		logging.FromContext(ctx).Error("error descargando archivo compartido", "file_path", filePath, "error", err)
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "FileNotFound"})
+		return
+	}
+
+	// Servir en línea para que los PDF e imágenes se puedan previsualizar
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filepath.Base(filePath)))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Data(http.StatusOK, contentType, data)
+}
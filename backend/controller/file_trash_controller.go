@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// fileTrash devuelve la papelera de archivos habilitada, respondiendo con el error si no lo está
+func fileTrash(ctx *gin.Context) (*service.FileTrashService, bool) {
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return nil, false
+	}
+	trash := supabaseStorage.FileTrash()
+	if !trash.Enabled() {
+		ctx.JSON(http.StatusConflict, gin.H{"error": service.ErrFileTrashDisabled.Error()})
+		return nil, false
+	}
+	return trash, true
+}
+
+// trashedFileID lee el ID del archivo de la papelera de la ruta
+func trashedFileID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// HandleListTrash lista los archivos de la papelera
+// @Summary Listar papelera
+// @Description Lista los archivos eliminados que aún se pueden restaurar (solo admins)
+// @Tags file-upload
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/trash [get]
+func (ctrl *FileUploadController) HandleListTrash(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+	trash, ok := fileTrash(ctx)
+	if !ok {
+		return
+	}
+
+	files, err := trash.List(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error listando la papelera", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetTrash"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"retention_days": int(trash.Retention().Hours() / 24),
+		"files":          files,
+	})
+}
+
+// HandleRestoreTrashedFile devuelve un archivo de la papelera a su ruta original
+// @Summary Restaurar archivo
+// @Tags file-upload
+// @Produce json
+// @Param id path string true "ID del archivo en la papelera"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/trash/{id}/restore [post]
+func (ctrl *FileUploadController) HandleRestoreTrashedFile(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+	id, ok := trashedFileID(ctx)
+	if !ok {
+		return
+	}
+	trash, ok := fileTrash(ctx)
+	if !ok {
+		return
+	}
+
+	restored, err := trash.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrTrashedFileNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logging.FromContext(ctx).Error("error restaurando archivo", "id", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRestoreFile"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Archivo restaurado",
+		"path":    restored.Path,
+	})
+}
+
+// HandlePurgeTrashedFile elimina definitivamente un archivo de la papelera
+// @Summary Purgar archivo
+// @Tags file-upload
+// @Produce json
+// @Param id path string true "ID del archivo en la papelera"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/trash/{id} [delete]
+func (ctrl *FileUploadController) HandlePurgeTrashedFile(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+	id, ok := trashedFileID(ctx)
+	if !ok {
+		return
+	}
+	trash, ok := fileTrash(ctx)
+	if !ok {
+		return
+	}
+
+	if err := trash.Purge(ctx, id); err != nil {
+		if errors.Is(err, service.ErrTrashedFileNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logging.FromContext(ctx).Error("error purgando archivo", "id", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FileDeleteFailed"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Archivo eliminado definitivamente",
+	})
+}
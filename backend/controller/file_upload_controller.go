@@ -3,8 +3,8 @@ package controller
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -12,6 +12,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
@@ -19,15 +22,31 @@ import (
 
 // FileUploadController maneja las operaciones de subida de archivos
 type FileUploadController struct {
-	userRepo   *storage.UserRepository
-	personRepo *storage.PersonRepository
+	userRepo         storage.UserStore
+	personRepo       storage.PersonStore
+	orgService       *service.OrganizationService
+	chunkedUploads   *service.ChunkedUploadService
+	uploadLimits     *service.UploadLimitService
+	virusScans       *service.VirusScanService
+	fileMetadataRepo storage.FileMetadataStore
+	rentalRepo       storage.RentalStore
+	propertyRepo     storage.PropertyStore
+	signingRepo      storage.ContractSigningStore
 }
 
 // NewFileUploadController crea un nuevo controlador de subida de archivos
-func NewFileUploadController(userRepo *storage.UserRepository, personRepo *storage.PersonRepository) *FileUploadController {
+func NewFileUploadController(userRepo storage.UserStore, personRepo storage.PersonStore, orgService *service.OrganizationService, chunkedUploads *service.ChunkedUploadService, uploadLimits *service.UploadLimitService, virusScans *service.VirusScanService, fileMetadataRepo storage.FileMetadataStore, rentalRepo storage.RentalStore, propertyRepo storage.PropertyStore, signingRepo storage.ContractSigningStore) *FileUploadController {
 	return &FileUploadController{
-		userRepo:   userRepo,
-		personRepo: personRepo,
+		userRepo:         userRepo,
+		personRepo:       personRepo,
+		orgService:       orgService,
+		chunkedUploads:   chunkedUploads,
+		uploadLimits:     uploadLimits,
+		virusScans:       virusScans,
+		fileMetadataRepo: fileMetadataRepo,
+		rentalRepo:       rentalRepo,
+		propertyRepo:     propertyRepo,
+		signingRepo:      signingRepo,
 	}
 }
 
@@ -47,30 +66,62 @@ type UploadToken struct {
 // Almacenamiento temporal de tokens (en producción usar base de datos)
 var uploadTokens = make(map[string]*UploadToken)
 
-// validateFileType valida el tipo de archivo permitido
+// validateFileType valida la extensión antes de recibir el archivo. El contenido se verifica
+// al subirlo (service.VerifyFileType).
 func validateFileType(filename, contentType string) error {
-	// Obtener extensión del archivo
-	ext := strings.ToLower(filepath.Ext(filename))
-
-	// Tipos de archivo permitidos
-	allowedExtensions := map[string]bool{
-		".pdf":  true,
-		".doc":  true,
-		".docx": true,
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".txt":  true,
-		".zip":  true,
-		".rar":  true,
+	if !service.IsAllowedFileExtension(filename) {
+		return fmt.Errorf("tipo de archivo no permitido: %s", strings.ToLower(filepath.Ext(filename)))
 	}
 
-	if !allowedExtensions[ext] {
-		return fmt.Errorf("tipo de archivo no permitido: %s", ext)
+	return nil
+}
+
+// respondUploadError responde el error de una subida: 400 cuando el contenido no corresponde a
+// la extensión, 413 cuando el archivo supera el tamaño máximo o la cuota del usuario, 422 cuando
+// se detectó malware, 503 si no se pudo analizar y 500 en otro caso
+func respondUploadError(ctx *gin.Context, err error) {
+	var limitErr *service.UploadLimitError
+	var rejectedErr *service.FileRejectedError
+	var typeErr *service.FileTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": typeErr.Error(), "code": "invalid_file_type"})
+		return
+	case errors.As(err, &rejectedErr):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "FileRejectedMalware",
+			"code":  "infected",
+		})
+		return
+	case errors.Is(err, service.ErrVirusScanUnavailable):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "code": "scan_unavailable"})
+		return
+	case errors.As(err, &limitErr):
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":       limitErr.Error(),
+			"code":        limitErr.Code,
+			"limit_bytes": limitErr.Limit,
+			"used_bytes":  limitErr.Used,
+			"size":        limitErr.Size,
+		})
+		return
 	}
 
-	return nil
+	logging.FromContext(ctx).Error("error subiendo archivo", "error", err)
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadFile"})
+}
+
+// userRole obtiene el rol de un usuario para aplicar sus límites de subida
+func (ctrl *FileUploadController) userRole(ctx *gin.Context, userID string) string {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "user"
+	}
+	user, err := ctrl.userRepo.GetByID(ctx, id)
+	if err != nil || user == nil {
+		return "user"
+	}
+	return user.Role
 }
 
 // GenerateUploadLinkRequest estructura para generar enlace de subida
@@ -79,6 +130,7 @@ type GenerateUploadLinkRequest struct {
 	RecipientName  string `json:"recipient_name" binding:"required"`
 	UserID         string `json:"user_id" binding:"required"` // ID del usuario que subirá archivos
 	ExpirationDays int    `json:"expiration_days"`
+	SendEmail      bool   `json:"send_email"` // Enviar el enlace por email al destinatario
 }
 
 // UploadFileRequest estructura para subir archivo
@@ -87,6 +139,12 @@ type UploadFileRequest struct {
 	FolderName string `form:"folder_name"`
 }
 
+// UpdateUploadLimitRequest estructura para definir los límites de subida de un rol (en MB, 0 sin límite)
+type UpdateUploadLimitRequest struct {
+	MaxFileSizeMB int64 `json:"max_file_size_mb" binding:"min=0"`
+	QuotaMB       int64 `json:"quota_mb" binding:"min=0"`
+}
+
 // RegisterRoutes registra las rutas de subida de archivos
 func (ctrl *FileUploadController) RegisterRoutes(adminRouter *gin.RouterGroup) {
 	uploadRoutes := adminRouter.Group("/file-upload")
@@ -101,16 +159,47 @@ func (ctrl *FileUploadController) RegisterRoutes(adminRouter *gin.RouterGroup) {
 		uploadRoutes.DELETE("/files/*filePath", ctrl.HandleDeleteFile)
 		uploadRoutes.GET("/files/download/*filePath", ctrl.HandleDownloadFile)
 		uploadRoutes.GET("/files/download-only/*filePath", ctrl.HandleDownloadFileOnly)
+		uploadRoutes.POST("/files/*filePath", ctrl.HandleFileAction) // zip, bulk-delete y {ruta}/share
+
+		// Límites de tamaño y cuota por rol (solo admins)
+		uploadRoutes.GET("/limits", ctrl.HandleListUploadLimits)
+		uploadRoutes.PUT("/limits/:role", ctrl.HandleUpdateUploadLimit)
+		uploadRoutes.DELETE("/limits/:role", ctrl.HandleResetUploadLimit)
+
+		// Resultados del análisis de virus (solo admins)
+		uploadRoutes.GET("/scans", ctrl.HandleListFileScans)
+
+		// Papelera de archivos eliminados (solo admins)
+		uploadRoutes.GET("/trash", ctrl.HandleListTrash)
+		uploadRoutes.POST("/trash/:id/restore", ctrl.HandleRestoreTrashedFile)
+		uploadRoutes.DELETE("/trash/:id", ctrl.HandlePurgeTrashedFile)
 	}
 }
 
-// RegisterPublicRoutes registra rutas públicas (con token)
-func (ctrl *FileUploadController) RegisterPublicRoutes(router *gin.RouterGroup) {
-	publicRoutes := router.Group("/upload")
+// RegisterPublicRoutes registra rutas públicas (con token), limitadas por IP y por token según
+// limits (RATE_LIMIT_UPLOAD y RATE_LIMIT_UPLOAD_TOKEN)
+func (ctrl *FileUploadController) RegisterPublicRoutes(router *gin.RouterGroup, limits config.RateLimitConfig) {
+	// Límites por IP y por token de subida, los tokens se podrían adivinar por fuerza bruta
+	uploadToken := func(c *gin.Context) string {
+		if token := c.GetHeader(uploadTokenHeader); token != "" {
+			return token
+		}
+		return c.Param("token")
+	}
+	publicRoutes := router.Group("/upload",
+		middleware.RateLimit("upload_ip", middleware.RateLimitOr(limits.Upload, "300/1m"), middleware.ClientIPKey),
+		middleware.RateLimit("upload_token", middleware.RateLimitOr(limits.UploadToken, "300/1m"), uploadToken),
+	)
 	{
 		publicRoutes.POST("/file", ctrl.HandleUploadFileWithAuth)
 		publicRoutes.GET("/validate-token/:token", ctrl.HandleValidateToken)
+
+		// Subida por partes para archivos grandes (token en el header Upload-Token)
+		ctrl.registerChunkedUploadRoutes(publicRoutes.Group("/chunked"))
 	}
+
+	// Enlaces compartidos firmados por la API
+	router.GET("/files/shared/:token", ctrl.HandleDownloadSharedFile)
 }
 
 // RegisterAuthenticatedUploadRoutes registra rutas autenticadas para subir archivos
@@ -118,9 +207,23 @@ func (ctrl *FileUploadController) RegisterAuthenticatedUploadRoutes(router *gin.
 	authRoutes := router.Group("/upload")
 	{
 		authRoutes.POST("/file-authenticated", ctrl.HandleAuthenticatedUpload)
+		authRoutes.GET("/usage", ctrl.HandleGetMyStorageUsage)
+
+		// Subida por partes para archivos grandes
+		ctrl.registerChunkedUploadRoutes(authRoutes.Group("/chunked-authenticated"))
 	}
 }
 
+// registerChunkedUploadRoutes registra las rutas de subida por partes en un grupo
+func (ctrl *FileUploadController) registerChunkedUploadRoutes(chunkedRoutes *gin.RouterGroup) {
+	chunkedRoutes.POST("", ctrl.HandleCreateChunkedUpload)
+	chunkedRoutes.HEAD("/:id", ctrl.HandleGetChunkedUpload)
+	chunkedRoutes.GET("/:id", ctrl.HandleGetChunkedUpload)
+	chunkedRoutes.PATCH("/:id", ctrl.HandleUploadChunk)
+	chunkedRoutes.POST("/:id/complete", ctrl.HandleCompleteChunkedUpload)
+	chunkedRoutes.DELETE("/:id", ctrl.HandleAbortChunkedUpload)
+}
+
 // HandleGenerateUploadLink genera un enlace de subida para un usuario
 // @Summary Generar enlace de subida
 // @Description Genera un enlace temporal para que un usuario pueda subir archivos
@@ -133,58 +236,58 @@ func (ctrl *FileUploadController) RegisterAuthenticatedUploadRoutes(router *gin.
 func (ctrl *FileUploadController) HandleGenerateUploadLink(ctx *gin.Context) {
 	var req GenerateUploadLinkRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
 		return
 	}
 
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin y manager pueden generar enlaces
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores y managers pueden generar enlaces de subida"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersGenerateUploadLinks"})
 		return
 	}
 
 	// Verificar que el usuario destinatario existe
 	targetUserID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidUserID"})
 		return
 	}
 
 	targetUser, err := ctrl.userRepo.GetByID(ctx, targetUserID)
 	if err != nil {
-		log.Printf("Error buscando usuario destinatario: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error validando usuario destinatario"})
+		logging.FromContext(ctx).Error("error buscando usuario destinatario", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToValidateRecipientUser"})
 		return
 	}
 	if targetUser == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Usuario destinatario no encontrado"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RecipientUserNotFound"})
 		return
 	}
 
 	// Verificar que el email coincide con el usuario
 	if targetUser.Email != req.RecipientEmail {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El email no coincide con el usuario especificado"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "EmailDoesNotMatchUser"})
 		return
 	}
 
 	// Generar token único
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
-		log.Printf("Error generando token: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error generando token"})
+		logging.FromContext(ctx).Error("error generando token", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateToken"})
 		return
 	}
 	token := hex.EncodeToString(tokenBytes)
@@ -214,12 +317,24 @@ func (ctrl *FileUploadController) HandleGenerateUploadLink(ctx *gin.Context) {
 	// Almacenar token (en producción usar base de datos)
 	uploadTokens[token] = uploadToken
 
+	// El enlace público usa el dominio de la organización del destinatario
+	baseURL := service.OrganizationBaseURL(ctrl.orgService.ForPerson(ctx, targetUser.PersonID))
+	publicURL := fmt.Sprintf("%s/file-upload?token=%s", baseURL, token)
+
+	if req.SendEmail {
+		if err := service.SendUploadLinkEmail(req.RecipientEmail, req.RecipientName, token, baseURL); err != nil {
+			logging.FromContext(ctx).Warn("error enviando enlace de subida", "recipient_email", req.RecipientEmail, "error", err)
+		}
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
-		"success":     true,
-		"message":     "Enlace de subida generado exitosamente",
-		"token":       token,
-		"expires_at":  expiresAt,
-		"upload_link": fmt.Sprintf("/upload/file?token=%s", token),
+		"success":            true,
+		"message":            "Enlace de subida generado exitosamente",
+		"token":              token,
+		"expires_at":         expiresAt,
+		"expires_at_display": service.FormatDateTime(expiresAt),
+		"upload_link":        fmt.Sprintf("/upload/file?token=%s", token),
+		"public_url":         publicURL,
 	})
 }
 
@@ -234,19 +349,19 @@ func (ctrl *FileUploadController) HandleListUploadTokens(ctx *gin.Context) {
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin y manager pueden ver tokens
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores y managers pueden ver tokens"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersViewTokens"})
 		return
 	}
 
@@ -273,33 +388,35 @@ func (ctrl *FileUploadController) HandleListUploadTokens(ctx *gin.Context) {
 func (ctrl *FileUploadController) HandleValidateToken(ctx *gin.Context) {
 	token := ctx.Param("token")
 	if token == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token requerido"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "TokenRequired"})
 		return
 	}
 
 	uploadToken, exists := uploadTokens[token]
 	if !exists {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Token no válido"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "TokenInvalid"})
 		return
 	}
 
 	// Verificar si el token ha expirado
 	if time.Now().After(uploadToken.ExpiresAt) {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token expirado"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenExpired"})
 		return
 	}
 
 	// Verificar si el token ya fue usado
 	if uploadToken.Used {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token ya utilizado"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenAlreadyUsed"})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"message":    "Token válido",
-		"recipient":  uploadToken.Name,
-		"expires_at": uploadToken.ExpiresAt,
+		"success":            true,
+		"message":            "Token válido",
+		"recipient":          uploadToken.Name,
+		"expires_at":         uploadToken.ExpiresAt,
+		"organization":       organizationName(middleware.GetOrganization(ctx)),
+		"expires_at_display": service.FormatDateTime(uploadToken.ExpiresAt),
 	})
 }
 
@@ -311,39 +428,43 @@ func (ctrl *FileUploadController) HandleValidateToken(ctx *gin.Context) {
 // @Produce json
 // @Param token formData string true "Token de autorización"
 // @Param file formData file true "Archivo a subir"
+// @Param category formData string false "cedula, contrato, comprobante_pago, certificado_laboral u otro"
+// @Param tags formData []string false "Etiquetas (repetidas o separadas por comas)"
+// @Param rental_id formData string false "Arriendo al que se vincula el archivo"
+// @Param contract_id formData string false "Contrato al que se vincula el archivo"
 // @Success 200 {object} service.SupabaseUploadResponse
 // @Router /upload/file [post]
 func (ctrl *FileUploadController) HandleUploadFileWithAuth(ctx *gin.Context) {
 	// Obtener token del formulario
 	token := ctx.PostForm("token")
 	if token == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token requerido"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "TokenRequired"})
 		return
 	}
 
 	// Validar token
 	uploadToken, exists := uploadTokens[token]
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token no válido"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenInvalid"})
 		return
 	}
 
 	// Verificar si el token ha expirado
 	if time.Now().After(uploadToken.ExpiresAt) {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token expirado"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenExpired"})
 		return
 	}
 
 	// Verificar si el token ya fue usado
 	if uploadToken.Used {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token ya utilizado"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "TokenAlreadyUsed"})
 		return
 	}
 
 	// Obtener archivo del formulario
 	file, header, err := ctx.Request.FormFile("file")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Archivo requerido: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
 		return
 	}
 	defer file.Close()
@@ -354,24 +475,29 @@ func (ctrl *FileUploadController) HandleUploadFileWithAuth(ctx *gin.Context) {
 		return
 	}
 
+	metadata, ok := ctrl.resolveFileMetadata(ctx, uploadToken.UserID, formFileMetadataRequest(ctx))
+	if !ok {
+		return
+	}
+
 	// Subir archivo usando Supabase Storage
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
-	uploadResponse, err := supabaseStorage.UploadFile(file, header, uploadToken.UserID, uploadToken.Email)
+	uploadResponse, err := supabaseStorage.UploadFile(file, header, uploadToken.UserID, uploadToken.Email, ctrl.userRole(ctx, uploadToken.UserID))
 	if err != nil {
-		log.Printf("Error subiendo archivo: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error subiendo archivo"})
+		respondUploadError(ctx, err)
 		return
 	}
+	ctrl.recordFileMetadata(ctx, uploadResponse, uploadToken.UserID, metadata)
 
 	// Marcar token como usado
 	uploadToken.Used = true
 
-	log.Printf("✅ Archivo subido con token: %s por %s", header.Filename, uploadToken.Email)
+	logging.FromContext(ctx).Info("archivo subido con token", "filename", header.Filename, "email", uploadToken.Email)
 
 	ctx.JSON(http.StatusOK, uploadResponse)
 }
@@ -383,26 +509,30 @@ func (ctrl *FileUploadController) HandleUploadFileWithAuth(ctx *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "Archivo a subir"
+// @Param category formData string false "cedula, contrato, comprobante_pago, certificado_laboral u otro"
+// @Param tags formData []string false "Etiquetas (repetidas o separadas por comas)"
+// @Param rental_id formData string false "Arriendo al que se vincula el archivo"
+// @Param contract_id formData string false "Contrato al que se vincula el archivo"
 // @Success 200 {object} service.SupabaseUploadResponse
 // @Router /upload/file-authenticated [post]
 func (ctrl *FileUploadController) HandleAuthenticatedUpload(ctx *gin.Context) {
 	// Verificar autenticación del usuario
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Debe iniciar sesión para subir archivos"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "LoginRequiredToUpload"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Obtener archivo del formulario
 	file, header, err := ctx.Request.FormFile("file")
 	if err != nil {
-		log.Printf("Error obteniendo archivo del formulario: %v", err)
+		logging.FromContext(ctx).Error("error obteniendo archivo del formulario", "error", err)
 		// También intentar con otros nombres de campo comunes
 		if file2, header2, err2 := ctx.Request.FormFile("files"); err2 == nil {
 			file = file2
@@ -411,7 +541,7 @@ func (ctrl *FileUploadController) HandleAuthenticatedUpload(ctx *gin.Context) {
 			file = file3
 			header = header3
 		} else {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Archivo requerido. Campo 'file' esperado: " + err.Error()})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileFieldRequired", "detail": err.Error()})
 			return
 		}
 	}
@@ -423,129 +553,358 @@ func (ctrl *FileUploadController) HandleAuthenticatedUpload(ctx *gin.Context) {
 		return
 	}
 
+	metadata, ok := ctrl.resolveFileMetadata(ctx, authUser.ID.String(), formFileMetadataRequest(ctx))
+	if !ok {
+		return
+	}
+
 	// Subir archivo usando Supabase Storage
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
-	uploadResponse, err := supabaseStorage.UploadFile(file, header, authUser.ID.String(), authUser.Email)
+	uploadResponse, err := supabaseStorage.UploadFile(file, header, authUser.ID.String(), authUser.Email, authUser.Role)
 	if err != nil {
-		log.Printf("Error subiendo archivo: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error subiendo archivo"})
+		respondUploadError(ctx, err)
 		return
 	}
+	ctrl.recordFileMetadata(ctx, uploadResponse, authUser.ID.String(), metadata)
 
-	log.Printf("✅ Archivo subido autenticado: %s por usuario %s", header.Filename, authUser.Email)
+	logging.FromContext(ctx).Info("archivo subido autenticado: por usuario", "filename", header.Filename, "email", authUser.Email)
 
 	ctx.JSON(http.StatusOK, uploadResponse)
 }
 
 // HandleListUploadedFiles lista todos los archivos subidos para administradores
 // @Summary Listar archivos subidos
-// @Description Lista todos los archivos subidos por usuarios
+// @Description Lista todos los archivos subidos por usuarios, opcionalmente solo los de una categoría o con ciertas etiquetas
 // @Tags file-upload
 // @Produce json
+// @Param category query string false "Categoría del documento"
+// @Param tag query []string false "Etiquetas que debe tener el archivo"
+// @Param limit query int false "Cantidad máxima de archivos"
+// @Param offset query int false "Archivos a omitir"
 // @Success 200 {array} service.SupabaseFileInfo
 // @Router /admin/file-upload/files [get]
 func (ctrl *FileUploadController) HandleListUploadedFiles(ctx *gin.Context) {
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin y manager pueden ver archivos
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores y managers pueden ver archivos"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersViewFiles"})
 		return
 	}
 
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
 	files, err := supabaseStorage.ListAllFiles()
 	if err != nil {
-		log.Printf("Error listando archivos: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo archivos"})
+		logging.FromContext(ctx).Error("error listando archivos", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetFiles"})
+		return
+	}
+	files, ok = ctrl.filterFilesByMetadata(ctx, files, "")
+	if !ok {
 		return
 	}
 
-	log.Printf("📋 CONTROLLER: Enviando %d archivos al frontend", len(files))
+	logging.FromContext(ctx).Info("CONTROLLER: Enviando archivos al frontend", "files_count", len(files))
 	for i, file := range files {
-		log.Printf("📄 CONTROLLER File %d: Name='%s', Path='%s'", i+1, file.Name, file.Path)
+		logging.FromContext(ctx).Debug("file sent to the frontend", "index", i+1, "name", file.Name, "path", file.Path)
 	}
 
+	// Espacio usado por cada usuario según los límites de su rol
+	roles := make(map[string]string)
+	if users, err := ctrl.userRepo.GetAll(ctx); err == nil {
+		for _, user := range users {
+			roles[user.ID.String()] = user.Role
+		}
+	} else {
+		logging.FromContext(ctx).Warn("error obteniendo roles para el uso de almacenamiento", "error", err)
+	}
+
+	// El uso se calcula con todos los archivos, antes de paginar
+	usage := supabaseStorage.UsageByUser(files, roles)
+	total := len(files)
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"files":   files,
+		"files":   paginate(ctx, files),
+		"total":   total,
+		"usage":   usage,
 	})
 }
 
 // HandleListUserFiles lista archivos de un usuario específico
 // @Summary Listar archivos de un usuario
-// @Description Lista todos los archivos subidos por un usuario específico
+// @Description Lista todos los archivos subidos por un usuario específico, opcionalmente solo los de una categoría o con ciertas etiquetas
 // @Tags file-upload
 // @Param userID path string true "ID del usuario"
+// @Param category query string false "Categoría del documento"
+// @Param tag query []string false "Etiquetas que debe tener el archivo"
+// @Param limit query int false "Cantidad máxima de archivos"
+// @Param offset query int false "Archivos a omitir"
 // @Produce json
 // @Success 200 {array} service.SupabaseFileInfo
 // @Router /admin/file-upload/files/{userID} [get]
 func (ctrl *FileUploadController) HandleListUserFiles(ctx *gin.Context) {
 	userID := ctx.Param("userID")
 	if userID == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario requerido"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UserIDRequired"})
 		return
 	}
 
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin y manager pueden ver archivos de otros usuarios
 	if authUser.Role != "admin" && authUser.Role != "manager" && authUser.ID.String() != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo puede ver sus propios archivos"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnFilesOnly"})
 		return
 	}
 
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
 	files, err := supabaseStorage.ListUserFiles(userID)
 	if err != nil {
-		log.Printf("Error listando archivos del usuario: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo archivos del usuario"})
+		logging.FromContext(ctx).Error("error listando archivos del usuario", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetUserFiles"})
+		return
+	}
+	files, ok = ctrl.filterFilesByMetadata(ctx, files, userID)
+	if !ok {
+		return
+	}
+
+	usage, err := supabaseStorage.Usage(userID, ctrl.userRole(ctx, userID))
+	if err != nil {
+		logging.FromContext(ctx).Warn("error calculando uso de almacenamiento del usuario", "user_id", userID, "error", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"files":   paginate(ctx, files),
+		"total":   len(files),
+		"usage":   usage,
+	})
+}
+
+// HandleReindexFiles reconstruye el índice de archivos que usan los listados
+// @Summary Reindexar archivos
+// @Description Recorre las carpetas del almacenamiento y actualiza el índice de archivos, p. ej. después de cambios hechos fuera de la aplicación (solo admins)
+// @Tags file-upload
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/file-upload/files/reindex [post]
+func (ctrl *FileUploadController) HandleReindexFiles(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+	index := supabaseStorage.FileIndex()
+	if index == nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "FileIndexNotConfigured"})
+		return
+	}
+
+	count, err := index.Reindex(ctx, supabaseStorage)
+	if errors.Is(err, service.ErrFileReindexRunning) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error reindexando archivos", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToReindexFiles"})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"files":   files,
+		"files":   count,
 	})
 }
 
+// HandleGetMyStorageUsage devuelve el espacio usado por el usuario autenticado y sus límites
+// @Summary Consultar uso de almacenamiento
+// @Description Devuelve el espacio usado, la cuota y el tamaño máximo de archivo del usuario
+// @Tags file-upload
+// @Produce json
+// @Success 200 {object} service.StorageUsage
+// @Router /upload/usage [get]
+func (ctrl *FileUploadController) HandleGetMyStorageUsage(ctx *gin.Context) {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return
+	}
+
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return
+	}
+
+	supabaseStorage := service.GetSupabaseStorageService()
+	if supabaseStorage == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	usage, err := supabaseStorage.Usage(authUser.ID.String(), authUser.Role)
+	if err != nil {
+		logging.FromContext(ctx).Error("error calculando uso de almacenamiento", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetStorageUsage"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
+
+// requireUploadAdmin verifica que el usuario autenticado sea administrador
+func requireUploadAdmin(ctx *gin.Context) bool {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return false
+	}
+
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return false
+	}
+
+	if authUser.Role != "admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsConfigureUploadLimits"})
+		return false
+	}
+	return true
+}
+
+// HandleListUploadLimits lista el tamaño máximo de archivo y la cuota vigentes de cada rol
+// @Summary Listar límites de subida
+// @Description Lista los límites de subida de cada rol y si vienen del entorno o de un admin
+// @Tags file-upload
+// @Produce json
+// @Success 200 {array} model.UploadLimit
+// @Router /admin/file-upload/limits [get]
+func (ctrl *FileUploadController) HandleListUploadLimits(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"limits":  ctrl.uploadLimits.All(ctx),
+	})
+}
+
+// HandleUpdateUploadLimit define los límites de subida de un rol
+// @Summary Definir límites de subida
+// @Description Define el tamaño máximo de archivo y la cuota por usuario de un rol (MB, 0 sin límite)
+// @Tags file-upload
+// @Accept json
+// @Produce json
+// @Param role path string true "Rol"
+// @Param request body UpdateUploadLimitRequest true "Límites en MB"
+// @Success 200 {object} model.UploadLimit
+// @Router /admin/file-upload/limits/{role} [put]
+func (ctrl *FileUploadController) HandleUpdateUploadLimit(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+
+	role := ctx.Param("role")
+	if !model.IsUploadLimitRole(role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRole"})
+		return
+	}
+
+	var req UpdateUploadLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	limit, err := ctrl.uploadLimits.Save(ctx, model.UploadLimit{
+		Role:          role,
+		MaxFileSizeMB: req.MaxFileSizeMB,
+		QuotaMB:       req.QuotaMB,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error guardando límites de subida", "role", role, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveUploadLimits"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("límites de subida MB por archivo, cuota MB", "role", role, "max_file_size_mb", limit.MaxFileSizeMB, "quota_mb", limit.QuotaMB)
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+// HandleResetUploadLimit elimina los límites definidos para un rol, que vuelve a los del entorno
+// @Summary Restablecer límites de subida
+// @Tags file-upload
+// @Produce json
+// @Param role path string true "Rol"
+// @Success 200 {object} model.UploadLimit
+// @Router /admin/file-upload/limits/{role} [delete]
+func (ctrl *FileUploadController) HandleResetUploadLimit(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+
+	role := ctx.Param("role")
+	if !model.IsUploadLimitRole(role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRole"})
+		return
+	}
+
+	limit, err := ctrl.uploadLimits.Reset(ctx, role)
+	if err != nil {
+		logging.FromContext(ctx).Error("error restableciendo límites de subida", "role", role, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToResetUploadLimits"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
 // HandleDownloadFile descarga un archivo y lo elimina (para admins)
 // @Summary Descargar archivo
 // @Description Descarga un archivo y lo elimina después de la descarga (solo admins)
@@ -557,45 +916,45 @@ func (ctrl *FileUploadController) HandleListUserFiles(ctx *gin.Context) {
 func (ctrl *FileUploadController) HandleDownloadFile(ctx *gin.Context) {
 	filePath := ctx.Param("filePath")
 	if filePath == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Ruta de archivo requerida"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FilePathRequired"})
 		return
 	}
 
 	// Remove leading slash from wildcard parameter
 	filePath = strings.TrimPrefix(filePath, "/")
 
-	log.Printf("🔍 HandleDownloadFile - Intentando descargar archivo con ruta: '%s'", filePath)
+	logging.FromContext(ctx).Debug("intentando descargar archivo", "file_path", filePath)
 
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin puede descargar y eliminar archivos
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores pueden descargar archivos"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsDownloadFiles"})
 		return
 	}
 
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
 	// Descargar y eliminar archivo automáticamente
-	fileData, err := supabaseStorage.DownloadAndDeleteFile(filePath)
+	fileData, err := supabaseStorage.DownloadAndDeleteFile(filePath, authUser.Email)
 	if err != nil {
-		log.Printf("Error descargando archivo: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error descargando archivo"})
+		logging.FromContext(ctx).Error("error descargando archivo", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FileDownloadFailed"})
 		return
 	}
 
@@ -605,7 +964,12 @@ func (ctrl *FileUploadController) HandleDownloadFile(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/octet-stream")
 	ctx.Data(http.StatusOK, "application/octet-stream", fileData)
 
-	log.Printf("✅ Archivo descargado y eliminado: %s por admin %s", filePath, authUser.Email)
+	// Los archivos en la papelera conservan sus datos hasta que se purgan
+	if !supabaseStorage.FileTrash().Enabled() {
+		ctrl.forgetFileMetadata(ctx, filePath)
+	}
+
+	logging.FromContext(ctx).Info("archivo descargado y eliminado: por admin", "file_path", filePath, "email", authUser.Email)
 }
 
 // HandleDownloadFileOnly descarga un archivo SIN eliminarlo (para admins)
@@ -619,45 +983,45 @@ func (ctrl *FileUploadController) HandleDownloadFile(ctx *gin.Context) {
 func (ctrl *FileUploadController) HandleDownloadFileOnly(ctx *gin.Context) {
 	filePath := ctx.Param("filePath")
 	if filePath == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Ruta de archivo requerida"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FilePathRequired"})
 		return
 	}
 
 	// Remove leading slash from wildcard parameter
 	filePath = strings.TrimPrefix(filePath, "/")
 
-	log.Printf("🔍 HandleDownloadFileOnly - Intentando descargar archivo con ruta: '%s'", filePath)
+	logging.FromContext(ctx).Debug("intentando descargar archivo sin eliminar", "file_path", filePath)
 
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin puede descargar archivos
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores pueden descargar archivos"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsDownloadFiles"})
 		return
 	}
 
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
 	// Solo descargar archivo (SIN eliminar)
 	fileData, err := supabaseStorage.DownloadFile(filePath)
 	if err != nil {
-		log.Printf("Error descargando archivo: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error descargando archivo"})
+		logging.FromContext(ctx).Error("error descargando archivo", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FileDownloadFailed"})
 		return
 	}
 
@@ -667,7 +1031,7 @@ func (ctrl *FileUploadController) HandleDownloadFileOnly(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/octet-stream")
 	ctx.Data(http.StatusOK, "application/octet-stream", fileData)
 
-	log.Printf("✅ Archivo descargado (sin eliminar): %s por admin %s", filePath, authUser.Email)
+	logging.FromContext(ctx).Info("archivo descargado (sin eliminar): por admin", "file_path", filePath, "email", authUser.Email)
 }
 
 // HandleDeleteFile elimina un archivo específico
@@ -681,7 +1045,7 @@ func (ctrl *FileUploadController) HandleDownloadFileOnly(ctx *gin.Context) {
 func (ctrl *FileUploadController) HandleDeleteFile(ctx *gin.Context) {
 	filePath := ctx.Param("filePath")
 	if filePath == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Ruta de archivo requerida"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FilePathRequired"})
 		return
 	}
 
@@ -691,39 +1055,84 @@ func (ctrl *FileUploadController) HandleDeleteFile(ctx *gin.Context) {
 	// Verificar autenticación y permisos
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticación requerida"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Datos de usuario inválidos"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// Solo admin puede eliminar archivos
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo administradores pueden eliminar archivos"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsDeleteFiles"})
 		return
 	}
 
 	supabaseStorage := service.GetSupabaseStorageService()
 	if supabaseStorage == nil {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Servicio de archivos no disponible"})
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
 		return
 	}
 
-	err := supabaseStorage.DeleteFile(filePath)
+	trashed, err := supabaseStorage.DeleteFile(filePath, authUser.Email)
 	if err != nil {
-		log.Printf("Error eliminando archivo: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando archivo"})
+		logging.FromContext(ctx).Error("error eliminando archivo", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FileDeleteFailed"})
+		return
+	}
+
+	if trashed != nil {
+		logging.FromContext(ctx).Info("archivo movido a la papelera: por admin", "file_path", filePath, "email", authUser.Email)
+		ctx.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Archivo movido a la papelera",
+			"trash":   trashed,
+		})
 		return
 	}
 
-	log.Printf("✅ Archivo eliminado: %s por admin %s", filePath, authUser.Email)
+	ctrl.forgetFileMetadata(ctx, filePath)
+
+	logging.FromContext(ctx).Info("archivo eliminado: por admin", "file_path", filePath, "email", authUser.Email)
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Archivo eliminado exitosamente",
 	})
 }
+
+// HandleListFileScans lista los últimos análisis de virus de los archivos subidos
+// @Summary Listar análisis de virus
+// @Description Lista los últimos análisis; result=infected muestra solo los archivos rechazados
+// @Tags file-upload
+// @Produce json
+// @Param result query string false "clean, infected o error"
+// @Success 200 {array} model.FileScan
+// @Router /admin/file-upload/scans [get]
+func (ctrl *FileUploadController) HandleListFileScans(ctx *gin.Context) {
+	if !requireUploadAdmin(ctx) {
+		return
+	}
+
+	result := ctx.Query("result")
+	if result != "" && result != model.FileScanClean && result != model.FileScanInfected && result != model.FileScanError {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidScanResult"})
+		return
+	}
+
+	scans, err := ctrl.virusScans.Recent(ctx, result, 100)
+	if err != nil {
+		logging.FromContext(ctx).Error("error listando análisis de virus", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetScans"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"enabled": ctrl.virusScans.Enabled(),
+		"scans":   scans,
+	})
+}
@@ -0,0 +1,360 @@
+// Command gen collects the swag annotations (@Summary, @Param, @Success...) of the handlers into
+// the table the OpenAPI spec served at /api/docs is built from. Run it through
+// `go generate ./controller` from the backend directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// typePackages are the packages whose types the annotations may reference, besides the
+// controllers themselves
+var typePackages = []string{"model", "service", "storage"}
+
+type param struct {
+	Name, In, Type, Description string
+	Required                    bool
+}
+
+type response struct {
+	Code                    int
+	Kind, Type, Description string
+}
+
+type header struct {
+	Code                    int
+	Type, Name, Description string
+}
+
+type handler struct {
+	Key                  string
+	Summary, Description string
+	Tags, Accept         []string
+	Produce              []string
+	Params               []param
+	Responses            []response
+	Headers              []header
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the controllers")
+	out := flag.String("out", "api_docs.gen.go", "generated file")
+	flag.Parse()
+
+	handlers, err := parseHandlers(*dir, filepath.Base(*out))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	declared := map[string]bool{}
+	if err := collectStructs(*dir, "", declared); err != nil {
+		log.Fatal(err)
+	}
+	for _, pkg := range typePackages {
+		if err := collectStructs(filepath.Join(*dir, "..", pkg), pkg, declared); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	source, err := format.Source(generate(handlers, declared))
+	if err != nil {
+		log.Fatalf("formatting generated docs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), source, 0644); err != nil {
+		log.Fatalf("writing generated docs: %v", err)
+	}
+}
+
+// parseHandlers reads the annotations of the functions and methods documented with @Router
+func parseHandlers(dir, skip string) ([]handler, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var handlers []handler
+	for _, path := range files {
+		if filepath.Base(path) == skip || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			h, ok, err := parseAnnotations(fn.Doc.List)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", filepath.Base(path), fn.Name.Name, err)
+			}
+			if !ok {
+				continue
+			}
+			h.Key = fn.Name.Name
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				h.Key = receiverName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+			}
+			handlers = append(handlers, h)
+		}
+	}
+
+	sort.Slice(handlers, func(i, j int) bool { return handlers[i].Key < handlers[j].Key })
+	return handlers, nil
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// parseAnnotations reads the swag annotations of a doc comment. ok is false without @Router.
+func parseAnnotations(comments []*ast.Comment) (h handler, ok bool, err error) {
+	for _, comment := range comments {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		name, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch name {
+		case "@Summary":
+			h.Summary = rest
+		case "@Description":
+			h.Description = strings.TrimSpace(h.Description + " " + rest)
+		case "@Tags":
+			h.Tags = append(h.Tags, splitList(rest)...)
+		case "@Accept":
+			h.Accept = append(h.Accept, splitList(rest)...)
+		case "@Produce":
+			h.Produce = append(h.Produce, splitList(rest)...)
+		case "@Router":
+			ok = true
+		case "@Param":
+			fields, description := splitQuoted(rest)
+			if len(fields) < 4 {
+				return h, false, fmt.Errorf("invalid @Param %q", rest)
+			}
+			h.Params = append(h.Params, param{
+				Name:        fields[0],
+				In:          fields[1],
+				Type:        fields[2],
+				Required:    fields[3] == "true",
+				Description: description,
+			})
+		case "@Success", "@Failure":
+			fields, description := splitQuoted(rest)
+			if len(fields) == 0 {
+				continue
+			}
+			code, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return h, false, fmt.Errorf("invalid %s %q", name, rest)
+			}
+			r := response{Code: code, Description: description}
+			if len(fields) >= 3 {
+				r.Kind = strings.Trim(fields[1], "{}")
+				r.Type = fields[2]
+			}
+			h.Responses = append(h.Responses, r)
+		case "@Header":
+			fields, description := splitQuoted(rest)
+			if len(fields) < 3 {
+				return h, false, fmt.Errorf("invalid @Header %q", rest)
+			}
+			code, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return h, false, fmt.Errorf("invalid @Header %q", rest)
+			}
+			h.Headers = append(h.Headers, header{
+				Code:        code,
+				Type:        strings.Trim(fields[1], "{}"),
+				Name:        fields[2],
+				Description: description,
+			})
+		}
+	}
+	return h, ok, nil
+}
+
+// splitList splits a comma separated annotation value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitQuoted splits the fields of an annotation from its quoted description
+func splitQuoted(value string) ([]string, string) {
+	description := ""
+	if i := strings.Index(value, `"`); i >= 0 {
+		description = strings.Trim(strings.TrimSpace(value[i:]), `"`)
+		value = value[:i]
+	}
+	return strings.Fields(value), description
+}
+
+// collectStructs records the struct types declared in a package directory, qualified with pkg
+func collectStructs(dir, pkg string, declared map[string]bool) error {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || strings.HasSuffix(path, ".gen.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, isStruct := typeSpec.Type.(*ast.StructType); !isStruct || typeSpec.TypeParams != nil {
+					continue
+				}
+				name := typeSpec.Name.Name
+				if pkg != "" {
+					if !ast.IsExported(name) {
+						continue
+					}
+					name = pkg + "." + name
+				}
+				declared[name] = true
+			}
+		}
+	}
+	return nil
+}
+
+// referencedType returns the struct type an annotation type refers to, if it is declared
+func referencedType(annotation string, declared map[string]bool) (string, bool) {
+	name := strings.TrimPrefix(annotation, "[]")
+	return name, declared[name]
+}
+
+func generate(handlers []handler, declared map[string]bool) []byte {
+	types := map[string]bool{}
+	for _, h := range handlers {
+		for _, p := range h.Params {
+			if name, ok := referencedType(p.Type, declared); ok {
+				types[name] = true
+			}
+		}
+		for _, r := range h.Responses {
+			if name, ok := referencedType(r.Type, declared); ok {
+				types[name] = true
+			}
+		}
+	}
+	typeNames := make([]string, 0, len(types))
+	imports := map[string]bool{}
+	for name := range types {
+		typeNames = append(typeNames, name)
+		if pkg, _, qualified := strings.Cut(name, "."); qualified {
+			imports[pkg] = true
+		}
+	}
+	sort.Strings(typeNames)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by controller/gen from the annotations of the handlers; DO NOT EDIT.\n\n")
+	b.WriteString("package controller\n\nimport (\n\t\"reflect\"\n\n")
+	for _, pkg := range typePackages {
+		if imports[pkg] {
+			fmt.Fprintf(&b, "\t%q\n", "github.com/nescool101/rentManager/"+pkg)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// handlerDocs are the annotations of the documented handlers, by receiver and method\n")
+	b.WriteString("var handlerDocs = map[string]handlerDoc{\n")
+	for _, h := range handlers {
+		fmt.Fprintf(&b, "%q: {\n", h.Key)
+		writeField(&b, "Summary", h.Summary)
+		writeField(&b, "Description", h.Description)
+		writeList(&b, "Tags", h.Tags)
+		writeList(&b, "Accept", h.Accept)
+		writeList(&b, "Produce", h.Produce)
+		if len(h.Params) > 0 {
+			b.WriteString("Params: []paramDoc{\n")
+			for _, p := range h.Params {
+				fmt.Fprintf(&b, "{Name: %q, In: %q, Type: %q, Required: %t, Description: %q},\n", p.Name, p.In, p.Type, p.Required, p.Description)
+			}
+			b.WriteString("},\n")
+		}
+		if len(h.Responses) > 0 {
+			b.WriteString("Responses: []responseDoc{\n")
+			for _, r := range h.Responses {
+				fmt.Fprintf(&b, "{Code: %d, Kind: %q, Type: %q, Description: %q},\n", r.Code, r.Kind, r.Type, r.Description)
+			}
+			b.WriteString("},\n")
+		}
+		if len(h.Headers) > 0 {
+			b.WriteString("Headers: []headerDoc{\n")
+			for _, hd := range h.Headers {
+				fmt.Fprintf(&b, "{Code: %d, Type: %q, Name: %q, Description: %q},\n", hd.Code, hd.Type, hd.Name, hd.Description)
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// docTypes are the Go types the annotations reference, described with reflection\n")
+	b.WriteString("var docTypes = map[string]reflect.Type{\n")
+	for _, name := range typeNames {
+		fmt.Fprintf(&b, "%q: reflect.TypeOf(%s{}),\n", name, name)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeField(b *bytes.Buffer, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "%s: %q,\n", name, value)
+	}
+}
+
+func writeList(b *bytes.Buffer, name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, "%s: []string{%s},\n", name, strings.Join(quoted, ", "))
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/service"
+)
+
+// graphQLMaxQueryLength bounds the size of the queries, which are parsed before validation
+const graphQLMaxQueryLength = 16 << 10
+
+// GraphQLController serves the read-only GraphQL endpoint of the dashboard
+type GraphQLController struct {
+	graphQLService *service.GraphQLService
+}
+
+// NewGraphQLController creates a new GraphQLController
+func NewGraphQLController(graphQLService *service.GraphQLService) *GraphQLController {
+	return &GraphQLController{graphQLService: graphQLService}
+}
+
+// RegisterRoutes registers the GraphQL route of the authenticated user
+func (c *GraphQLController) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/graphql", c.Query)
+}
+
+// Query runs a GraphQL query with the scope of the authenticated user
+// @Summary GraphQL query
+// @Description Read-only queries over persons, properties, rentals, payments and maintenance requests, so nested data such as property → rentals → renter → lastPayment comes in one request. The top-level lists take limit (50 by default, 100 at most) and offset. Admins see everything, managers their properties with their rentals and renters, and the other users their own rentals and properties. Errors of the query come in the errors array of the body.
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Param request body service.GraphQLRequest true "Query, operationName and variables"
+// @Success 200 {object} service.GraphQLResponse
+// @Failure 400 {object} service.GraphQLResponse "The query is invalid"
+// @Router /graphql [post]
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var request service.GraphQLRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, service.GraphQLResponse{Errors: []service.GraphQLError{{Message: "Invalid request body: " + err.Error()}}})
+		return
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		ctx.JSON(http.StatusBadRequest, service.GraphQLResponse{Errors: []service.GraphQLError{{Message: "query is required"}}})
+		return
+	}
+	if len(request.Query) > graphQLMaxQueryLength {
+		ctx.JSON(http.StatusRequestEntityTooLarge, service.GraphQLResponse{Errors: []service.GraphQLError{{Message: "The query is too long"}}})
+		return
+	}
+
+	response := c.graphQLService.Execute(ctx, authUser, request)
+	if len(response.Data) == 0 {
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
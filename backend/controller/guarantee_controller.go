@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// GuaranteeController handles the studies of tenants requested to a guarantee company (afianzadora)
+type GuaranteeController struct {
+	repository   storage.GuaranteeStudyStore
+	personRepo   storage.PersonStore
+	propertyRepo storage.PropertyStore
+	userRepo     storage.UserStore
+	guarantee    config.GuaranteeConfig
+}
+
+// NewGuaranteeController creates a new GuaranteeController
+func NewGuaranteeController(
+	repository storage.GuaranteeStudyStore,
+	personRepo storage.PersonStore,
+	propertyRepo storage.PropertyStore,
+	userRepo storage.UserStore,
+	guarantee config.GuaranteeConfig,
+) *GuaranteeController {
+	return &GuaranteeController{
+		repository:   repository,
+		personRepo:   personRepo,
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		guarantee:    guarantee,
+	}
+}
+
+// SubmitGuaranteeStudyRequest defines the tenant and rental submitted to the afianzadora
+type SubmitGuaranteeStudyRequest struct {
+	RenterID    string  `json:"renter_id" binding:"required"`
+	PropertyID  string  `json:"property_id" binding:"required"`
+	MonthlyRent float64 `json:"monthly_rent" binding:"required"`
+	TermMonths  int     `json:"term_months"` // Defaults to 12
+}
+
+// RegisterRoutes registers the guarantee study routes available to authenticated users
+func (c *GuaranteeController) RegisterRoutes(router *gin.RouterGroup) {
+	studies := router.Group("/guarantee-studies")
+	{
+		studies.GET("/:id", c.GetByID)
+		studies.GET("/renter/:renterId", c.GetByRenterID)
+	}
+}
+
+// RegisterAdminRoutes registers the guarantee study routes on an admin-protected group
+func (c *GuaranteeController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	studies := adminRouter.Group("/guarantee-studies")
+	{
+		studies.POST("", c.Submit)
+		studies.POST("/:id/refresh", c.Refresh)
+	}
+}
+
+// GetByID retrieves a guarantee study by ID
+func (c *GuaranteeController) GetByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	study, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if study == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "GuaranteeStudyNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, guaranteeStudyResponse(*study))
+}
+
+// GetByRenterID retrieves the guarantee studies of a renter, newest first
+func (c *GuaranteeController) GetByRenterID(ctx *gin.Context) {
+	renterID, err := uuid.Parse(ctx.Param("renterId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRenterIDFormat"})
+		return
+	}
+
+	studies, err := c.repository.GetByRenterID(ctx, renterID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(studies))
+	for _, study := range studies {
+		response = append(response, guaranteeStudyResponse(study))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Submit sends the data of a tenant to the afianzadora and stores the study
+func (c *GuaranteeController) Submit(ctx *gin.Context) {
+	var req SubmitGuaranteeStudyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	renterID, err := uuid.Parse(req.RenterID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRenterID"})
+		return
+	}
+	propertyID, err := uuid.Parse(req.PropertyID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyID"})
+		return
+	}
+	if req.MonthlyRent <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "GuaranteeMonthlyRentRequired"})
+		return
+	}
+	if req.TermMonths <= 0 {
+		req.TermMonths = 12
+	}
+
+	provider, err := service.GetGuaranteeProvider(c.guarantee)
+	if err != nil {
+		logging.FromContext(ctx).Info("guarantee provider not available", "error", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "GuaranteeCompanyNotConfigured"})
+		return
+	}
+
+	renter, err := c.personRepo.GetByID(ctx, renterID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if renter == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RenterNotFound"})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	renterEmail := ""
+	if user, err := c.userRepo.GetByPersonID(ctx, renter.ID); err == nil && user != nil {
+		renterEmail = user.Email
+	}
+
+	study := model.GuaranteeStudy{
+		ID:          uuid.New(),
+		RenterID:    renter.ID,
+		PropertyID:  property.ID,
+		Provider:    provider.Name(),
+		Company:     provider.Company(),
+		MonthlyRent: req.MonthlyRent,
+		CreatedAt:   time.Now(),
+	}
+
+	result, err := provider.SubmitStudy(ctx, service.GuaranteeStudyRequest{
+		StudyID:         study.ID.String(),
+		Applicant:       renter,
+		ApplicantEmail:  renterEmail,
+		PropertyAddress: property.Address,
+		PropertyCity:    property.City,
+		MonthlyRent:     req.MonthlyRent,
+		TermMonths:      req.TermMonths,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error submitting guarantee study of renter", "renter_id", renter.ID, "error", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "FailedToSubmitGuaranteeStudy"})
+		return
+	}
+	study.ExternalID = result.ExternalID
+	applyGuaranteeResult(&study, result)
+
+	createdStudy, err := c.repository.Create(ctx, study)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating guarantee study", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveGuaranteeStudy"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, guaranteeStudyResponse(*createdStudy))
+}
+
+// Refresh fetches the result of a pending study from the afianzadora
+func (c *GuaranteeController) Refresh(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	study, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if study == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "GuaranteeStudyNotFound"})
+		return
+	}
+
+	// Decided studies do not change anymore
+	if study.Status != model.GuaranteeStatusPending {
+		ctx.JSON(http.StatusOK, guaranteeStudyResponse(*study))
+		return
+	}
+
+	provider, err := service.GetGuaranteeProvider(c.guarantee)
+	if err != nil {
+		logging.FromContext(ctx).Info("guarantee provider not available", "error", err)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "GuaranteeCompanyNotConfigured"})
+		return
+	}
+
+	result, err := provider.GetStudy(ctx, study.ExternalID)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching guarantee study", "study_id", study.ID, "error", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "FailedToGetGuaranteeCompanyStudy"})
+		return
+	}
+	applyGuaranteeResult(study, result)
+
+	updatedStudy, err := c.repository.UpdateResult(ctx, *study)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, guaranteeStudyResponse(*updatedStudy))
+}
+
+// applyGuaranteeResult copies the result reported by the afianzadora to a study
+func applyGuaranteeResult(study *model.GuaranteeStudy, result *service.GuaranteeStudyResult) {
+	study.Status = result.Status
+	study.PolicyNumber = result.PolicyNumber
+	study.Observations = result.Observations
+	if result.Status != model.GuaranteeStatusPending && study.DecidedAt == nil {
+		now := time.Now()
+		study.DecidedAt = &now
+	}
+}
+
+// guaranteeStudyResponse adds the Spanish status to a study
+func guaranteeStudyResponse(study model.GuaranteeStudy) gin.H {
+	statusSpanish := model.GuaranteeStatusTranslations[study.Status]
+	if statusSpanish == "" {
+		statusSpanish = study.Status
+	}
+	return gin.H{
+		"study":          study,
+		"status_spanish": statusSpanish,
+		"has_policy":     study.HasPolicy(),
+	}
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/service"
+)
+
+// getHealth is the liveness probe: it answers 200 while the process serves requests and
+// reports the active profile, to catch cross-environment mistakes, and the last status of the
+// dependencies
+// @Summary Liveness and dependency status
+// @Description Always 200 while the API is running. The status is degraded or unavailable when a dependency is down, see /readyz.
+// @Tags system
+// @Produce json
+// @Success 200 {object} service.HealthReport
+// @Router /healthz [get]
+func getHealth(health *service.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, health.Check(c.Request.Context()))
+	}
+}
+
+// getReadiness is the readiness probe: it answers 503 while a critical dependency (database
+// or file storage) is down, so the platform stops routing traffic to the instance. Email and
+// Telegram failures only degrade the status.
+// @Summary Readiness with dependency checks
+// @Description Checks the database, the file storage bucket, the email driver and the Telegram bot token. 503 when the database or the storage is down.
+// @Tags system
+// @Produce json
+// @Success 200 {object} service.HealthReport
+// @Failure 503 {object} service.HealthReport
+// @Router /readyz [get]
+func getReadiness(health *service.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := health.Check(c.Request.Context())
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
@@ -1,20 +1,147 @@
 package controller
 
 import (
-	"log" // Standard Go log package
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/nescool101/rentManager/app"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
 	"github.com/nescool101/rentManager/middleware"
 	"github.com/nescool101/rentManager/service"
-	"github.com/nescool101/rentManager/storage"
 )
 
-func StartHTTPServer() error {
-	router := gin.Default()
+// defaultShutdownTimeout is how long in-flight requests and scheduled jobs get to finish after
+// SIGTERM or SIGINT, SHUTDOWN_TIMEOUT overrides it
+const defaultShutdownTimeout = 25 * time.Second
+
+// StartHTTPServer serves the API until SIGTERM or SIGINT. The server then stops taking
+// connections, drains the requests in flight, such as PDF signings and uploads, and stops the
+// schedulers waiting for their running jobs, for up to the shutdown timeout of the configuration.
+func StartHTTPServer(c *app.Container) error {
+	router, err := NewRouter(c)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              ":" + c.Config.Server.Port,
+		Handler:           router,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	// Open signing status streams would otherwise hold the drain until the timeout
+	server.RegisterOnShutdown(service.GetSigningStatusHub().Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting
+	stop()
+
+	timeout := c.Config.Server.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	slog.Info("shutting down, waiting up for in-flight requests and jobs", "timeout", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("requests still in flight after the drain timeout", "error", err)
+	}
+	if err := service.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("schedulers did not stop cleanly", "error", err)
+	}
+	// The jobs stopped, nothing uses the database connections anymore
+	c.Close()
+
+	slog.Info("server stopped")
+	return nil
+}
+
+// NewRouter builds the Gin engine with every controller and route registered, from the
+// clients and stores of the container, and starts the schedulers and workers. It is separate
+// from StartHTTPServer so the full HTTP stack can be served by other listeners (e.g. httptest in
+// the integration harness).
+func NewRouter(c *app.Container) (*gin.Engine, error) {
+	router, _, jobs, err := buildRouter(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, start := range jobs {
+		if err := start(); err != nil {
+			return nil, err
+		}
+	}
+	return router, nil
+}
+
+// OpenAPISpec returns the spec served at /api/docs/openapi.json for the routes of the
+// container, without starting the schedulers and workers. sdk/gen generates the API clients
+// from it.
+func OpenAPISpec(c *app.Container) (map[string]any, error) {
+	_, docs, _, err := buildRouter(c)
+	if err != nil {
+		return nil, err
+	}
+	return docs.spec, nil
+}
+
+// buildRouter registers every controller and route on a new Gin engine. It returns the API docs
+// of the routes and the Start functions of the schedulers and workers, which NewRouter calls.
+func buildRouter(c *app.Container) (*gin.Engine, *APIDocsController, []func() error, error) {
+	// Structured request logs with the request ID instead of the default Gin logger, and the
+	// Prometheus request metrics
+	router := gin.New()
+	// Behind a proxy that sets the client IP in a header (Fly-Client-IP, CF-Connecting-IP) the
+	// rate limits read it from there instead of the spoofable X-Forwarded-For. Otherwise
+	// X-Forwarded-For is only believed from TRUSTED_PROXIES, and by default the client IP is the
+	// address of the connection.
+	router.TrustedPlatform = c.Config.Server.ClientIPHeader
+	if err := router.SetTrustedProxies(c.Config.Server.TrustedProxies); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	router.Use(middleware.RequestLogger(), middleware.Metrics(), gin.Recovery())
+	// The JSON errors are translated to the locale of the Accept-Language header or of the user
+	router.Use(middleware.Locale(), middleware.LocalizeErrors())
+
+	// The services that run their own queries take the whole factory, the controllers take the
+	// stores of the container
+	repoFactory := c.Repositories
+	// Schedulers and workers, started by NewRouter once every route is registered
+	var jobs []func() error
+
+	// Create controllers
+	personRepo := c.Persons
+	propertyRepo := c.Properties
+	rentalRepo := c.Rentals
+	userRepo := c.Users
+	rentPaymentRepo := c.RentPayments
+	rentalHistoryRepo := c.RentalHistory
+	maintRepo := c.MaintenanceRequests
+	pricingRepo := c.Pricing
+	bankAccountRepo := c.BankAccounts
+	orgRepo := c.Organizations
+	orgService := service.NewOrganizationService(orgRepo, propertyRepo, rentalRepo)
 
 	// Configure CORS to allow requests from the frontend
 	config := cors.DefaultConfig()
@@ -24,69 +151,224 @@ func StartHTTPServer() error {
 		"http://localhost:3000",        // Desarrollo - alternativo
 		"https://nescool101.github.io", // Producción - GitHub Pages (root domain)
 	}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization"}
+	// Custom domains registered by organizations are allowed as well
+	config.AllowOriginFunc = orgService.IsAllowedOrigin
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "HEAD", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", uploadOffsetHeader, uploadTokenHeader, logging.RequestIDHeader}
+	// Paginated lists report their total size in a header the frontend must be able to read,
+	// chunked uploads report their progress the same way
+	config.ExposeHeaders = []string{totalCountHeader, uploadOffsetHeader, uploadLengthHeader, logging.RequestIDHeader}
 	config.AllowCredentials = true
 	router.Use(cors.New(config))
 
-	// Initialize Supabase client
-	supabaseClient, err := storage.InitializeSupabaseClient()
-	if err != nil {
-		return err
-	}
-
-	// Create repository factory
-	repoFactory := storage.NewRepositoryFactory(supabaseClient)
-
-	// Create controllers
-	personRepo := repoFactory.GetPersonRepository()
-	propertyRepo := repoFactory.GetPropertyRepository()
-	rentalRepo := repoFactory.GetRentalRepository()
-	userRepo := repoFactory.GetUserRepository()
-	rentPaymentRepo := repoFactory.GetRentPaymentRepository()
-	rentalHistoryRepo := repoFactory.GetRentalHistoryRepository()
-	maintRepo := repoFactory.GetMaintenanceRequestRepository()
-	pricingRepo := repoFactory.GetPricingRepository()
-	bankAccountRepo := repoFactory.GetBankAccountRepository()
-
 	personController := NewPersonController(personRepo, propertyRepo, rentalRepo, bankAccountRepo, userRepo)
-	propertyController := NewPropertyController(propertyRepo)
+	propertyController := NewPropertyController(propertyRepo, c.PropertyPhotos)
 	rentalController := NewRentalController(rentalRepo, propertyRepo)
-	userController := NewUserController(userRepo)
-	rentPaymentController := NewRentPaymentController(rentPaymentRepo, rentalRepo, propertyRepo)
-	rentalHistoryController := NewRentalHistoryController(rentalHistoryRepo, rentalRepo, propertyRepo, personRepo)
-	maintenanceRequestController := NewMaintenanceRequestController(maintRepo, propertyRepo, rentalRepo)
+	sessionService := service.NewSessionService(c.UserSessions, c.Config.Auth)
+	userController := NewUserController(userRepo, service.NewLoginAttemptService(c.LoginAttempts, c.Config.Auth), sessionService)
+	sessionController := NewSessionController(sessionService)
+	auditService := service.NewAuditService(c.AuditLog)
+	impersonationController := NewImpersonationController(userRepo, sessionService, auditService, c.Config.Auth.ImpersonationTTL)
+	passwordResetController := NewPasswordResetController(service.NewPasswordResetService(userRepo, orgService, sessionService, c.Config.Secrets.PasswordReset, c.Config.Auth.PasswordResetTTL))
+	userRegistrationController := NewUserRegistrationController(userRepo, personRepo, service.NewEmailVerificationService(userRepo, c.Config.Secrets.EmailVerification, c.Config.Auth.EmailVerificationTTL))
+	rentPaymentController := NewRentPaymentController(rentPaymentRepo, rentalRepo, propertyRepo, pricingRepo, orgService)
+	rentalHistoryController := NewRentalHistoryController(rentalHistoryRepo, rentalRepo, propertyRepo, personRepo, pricingRepo, orgService)
+	// Rentals record their creation, renewal, transfer and early termination in the history
+	service.InitializeRentalHistory(rentalHistoryRepo)
+	serviceProviderRepo := c.ServiceProviders
+	maintCommentRepo := c.MaintenanceComments
+	maintStatusRepo := c.MaintenanceStatusHistory
+	maintenanceRequestController := NewMaintenanceRequestController(maintRepo, propertyRepo, rentalRepo, maintCommentRepo, maintStatusRepo, personRepo, userRepo, serviceProviderRepo)
 	pricingController := NewPricingController(pricingRepo)
-	emailController := NewEmailController(userRepo, personRepo, rentalRepo, propertyRepo)
-	contractController := NewContractController(personRepo, propertyRepo, pricingRepo)
-	signingRepo := repoFactory.GetContractSigningRepository()
-	contractSigningController := NewContractSigningController(personRepo, propertyRepo, pricingRepo, userRepo, contractController, signingRepo)
+	emailController := NewEmailController(userRepo, personRepo, rentalRepo, propertyRepo, orgService)
+	emailTemplateController := NewEmailTemplateController(service.InitializeEmailTemplates(repoFactory))
+	signingRepo := c.ContractSignings
+	contractTemplateRepo := c.ContractTemplates
+	webhookDispatcher := service.NewSigningWebhookDispatcher(repoFactory, c.Config.Schedules.WebhookDelivery)
+	inspectionService := service.NewInspectionService(repoFactory)
+	contractController := NewContractController(personRepo, propertyRepo, pricingRepo, rentalRepo, rentalHistoryRepo, userRepo, signingRepo, contractTemplateRepo, c.Inventory, c.Promotions, c.GuaranteeStudies, c.RentalParties, c.Buildings, orgService, webhookDispatcher, c.Config.Reminders.RenewalIPCPercentage)
+	contractSigningController := NewContractSigningController(personRepo, propertyRepo, pricingRepo, userRepo, contractController, signingRepo, c.ContractSigningEvents, orgService, webhookDispatcher, inspectionService, c.Config.Signing, c.Config.Secrets.SigningOTP)
 	managerRegistrationController := NewManagerRegistrationController(repoFactory)
 	managerInvitationController := NewManagerInvitationController(repoFactory)
+	invitationController := NewInvitationController(service.NewInvitationService(repoFactory, orgService, c.Config.Secrets.Invitation, c.Config.Auth.InvitationTTL), repoFactory, orgService)
 	bankAccountController := NewBankAccountController(bankAccountRepo)
-	fileUploadController := NewFileUploadController(userRepo, personRepo)
+	uploadLimits := service.NewUploadLimitService(c.UploadLimits, c.Config.Files.UploadLimits)
+	virusScans := service.NewVirusScanService(repoFactory, c.Config.Files.VirusScan, c.Config.Supabase.QuarantineBucket)
+	fileTrash := service.NewFileTrashService(repoFactory, c.Config.Files.TrashRetentionDays, c.Config.Schedules.FileTrashPurge)
+	fileIndex := service.NewFileIndexService(repoFactory, c.Config.Files.ListCacheTTL)
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		storageService.SetUploadLimits(uploadLimits)
+		storageService.SetFileTrash(fileTrash)
+		jobs = append(jobs, fileTrash.Start)
+		storageService.SetFileIndex(fileIndex)
+		jobs = append(jobs, func() error {
+			fileIndex.Start(storageService)
+			return nil
+		})
+		if err := storageService.SetVirusScanner(virusScans); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	bucketBackups := service.NewBucketBackupService(repoFactory, c.Config.Schedules.BucketBackup)
+	jobs = append(jobs, bucketBackups.Start)
+	bucketBackupController := NewBucketBackupController(bucketBackups)
+	fileUploadController := NewFileUploadController(userRepo, personRepo, orgService, service.NewChunkedUploadService(c.Config.Files.Chunked), uploadLimits, virusScans, c.FileMetadata, rentalRepo, propertyRepo, signingRepo)
+	serviceProviderController := NewServiceProviderController(serviceProviderRepo)
+	contractTemplateController := NewContractTemplateController(contractTemplateRepo)
+	organizationController := NewOrganizationController(orgRepo, orgService)
+	inventoryController := NewInventoryController(c.Inventory, propertyRepo)
+	promotionController := NewPromotionController(c.Promotions, propertyRepo)
+	guaranteeController := NewGuaranteeController(c.GuaranteeStudies, personRepo, propertyRepo, userRepo, c.Config.Guarantee)
+	signingCertificateController := NewSigningCertificateController()
+	reglamentoController := NewReglamentoController(c.Reglamentos, propertyRepo, rentalRepo, personRepo, userRepo)
+	notaryController := NewNotaryController(c.Notarizations, signingRepo, c.ContractSigningEvents, personRepo, c.Config.Notary)
+	contractCessionService := service.NewContractCessionService(repoFactory, c.Config.Schedules.ContractCession)
+	contractCessionController := NewContractCessionController(c.ContractCessions, rentalRepo, propertyRepo, personRepo, userRepo, bankAccountRepo, rentPaymentRepo, contractCessionService, orgService)
+	securityDepositController := NewSecurityDepositController(c.SecurityDeposits, rentalRepo, propertyRepo, personRepo, userRepo, pricingRepo, bankAccountRepo)
+	inspectionController := NewInspectionController(c.Inspections, rentalRepo, c.Inventory, signingRepo, inspectionService, orgService, webhookDispatcher)
+	listingController := NewListingController(c.Listings, propertyRepo, personRepo, userRepo, orgService)
+	rentalPartyController := NewRentalPartyController(c.RentalParties, rentalRepo, personRepo, userRepo)
+	buildingController := NewBuildingController(c.Buildings, propertyRepo, c.Reglamentos)
+	dashboardController := NewDashboardController(service.NewDashboardService(repoFactory, orgService))
+	graphQLController := NewGraphQLController(service.NewGraphQLService(repoFactory, orgService))
+	searchController := NewSearchController(c.Search, propertyRepo, rentalRepo)
+	// Runtime toggles of features and scheduled jobs, read from the stores of this container
+	featureFlagController := NewFeatureFlagController(service.InitializeFeatureFlags(c.FeatureFlags, c.Config))
+
+	// Reminders are queued in the outbox at the send time of each renter when enabled
+	emailOutboxRepo := c.EmailOutbox
+	emailOutbox := service.NewEmailOutbox(emailOutboxRepo, c.Config.Schedules.EmailOutbox)
+	reminderScheduler := service.NewReminderScheduler(c.ReminderPreferences, emailOutboxRepo, emailOutbox, orgService, c.Config.Features.ReminderSendTime, c.Config.Reminders.DefaultSendHour)
+	if reminderScheduler.Enabled() {
+		jobs = append(jobs, emailOutbox.Start)
+	}
+	reminderPreferenceController := NewReminderPreferenceController(c.ReminderPreferences, reminderScheduler)
+	notificationPreferenceController := NewNotificationPreferenceController(service.InitializeNotificationPreferences(repoFactory, orgService, c.Config.Secrets.NotificationToken))
+	// In-app feed of payments, signatures and maintenance changes, next to the emails
+	notificationController := NewNotificationController(service.InitializeNotifications(repoFactory))
+	// Numbered PDF receipts attached to the monthly rent reminders, numbered per organization
+	// and year like the receipts of the payments
+	service.InitializeBillingReceipts(repoFactory, service.InitializeDocumentNumbering(repoFactory, orgService))
+	emailTrackingController := NewEmailTrackingController(emailOutbox)
+
+	// Opt-in weekly/monthly digest of managers, sent by a daily job
+	digestService := service.NewManagerDigestService(repoFactory, orgService, c.Config.Schedules.ManagerDigest)
+	jobs = append(jobs, digestService.Start)
+	managerDigestController := NewManagerDigestController(c.ManagerDigests, digestService)
+
+	// Reminders of pending signing requests and expiry of the overdue ones
+	jobs = append(jobs, service.NewSigningReminderService(repoFactory, orgService, webhookDispatcher, c.Config.Signing.ReminderDays, c.Config.Schedules.SigningReminders).Start)
+
+	// Retries of the signing events posted to the webhooks of the managers
+	jobs = append(jobs, webhookDispatcher.Start)
+
+	// Bulk status operations on users, run by a background worker
+	userBulkQueue := service.NewUserBulkJobQueue(repoFactory, orgService)
+	jobs = append(jobs, userBulkQueue.Start)
+	userBulkController := NewUserBulkController(c.UserBulkJobs, userBulkQueue)
+	webhookController := NewWebhookController(c.Webhooks, webhookDispatcher)
+	paymentStatementController := NewPaymentStatementController(c.PaymentStatements, rentalRepo, propertyRepo, personRepo, rentPaymentRepo, orgService)
+
+	// Cessions of rentals switch the account the rent is paid to on their effective date
+	jobs = append(jobs, contractCessionService.Start)
+
+	// DIAN electronic invoices of the rent payments, whose acceptance is checked periodically
+	einvoices := service.InitializeEInvoices(repoFactory, orgService, c.Config.EInvoice, c.Config.Schedules.EInvoiceStatus)
+	if einvoices != nil {
+		jobs = append(jobs, einvoices.Start)
+	}
+	einvoiceController := NewEInvoiceController(einvoices)
+
+	// Online payment of the rent through the payment gateway, confirmed by its webhook
+	var paymentCheckouts *service.PaymentCheckoutService
+	if gateway, err := service.GetPaymentGateway(c.Config.Wompi); err != nil {
+		slog.Warn("pagos en línea no configurados", "error", err)
+	} else {
+		paymentCheckouts = service.NewPaymentCheckoutService(repoFactory, gateway, orgService)
+	}
+	paymentCheckoutController := NewPaymentCheckoutController(paymentCheckouts)
+
+	// Monthly platform plan of the managers, whose invoices activate or disable their users
+	subscriptions := service.InitializeSubscriptions(repoFactory, c.Config.Subscriptions, c.Config.Schedules.SubscriptionCheck)
+	if subscriptions != nil {
+		jobs = append(jobs, subscriptions.Start)
+	}
+	subscriptionController := NewSubscriptionController(subscriptions)
+
+	// Daily expiry warning of the signing certificate and hot reload of rotated certificates
+	jobs = append(jobs, service.NewCertificateMonitor(userRepo, c.Config.Signing.CertExpiryWarningDays, c.Config.Schedules.CertificateExpiry).Start)
 
 	// Public API routes (no auth required)
 	publicApi := router.Group("/api")
+	// Public links may be opened on an organization custom domain
+	publicApi.Use(middleware.ResolveOrganization(orgService))
 	{
 		// Public routes - login doesn't require authentication
 		users := publicApi.Group("/users")
-		users.POST("/login", userController.Login)
+		users.POST("/login", middleware.RateLimit("login", middleware.RateLimitOr(c.Config.RateLimits.Login, "10/1m"), middleware.ClientIPKey), userController.Login)
+
+		// Forgotten password recovery through an emailed one-time link
+		passwordResetController.RegisterPublicRoutes(publicApi, c.Config.RateLimits)
+
+		// Self-registration of new users, who log in once their email is verified
+		userRegistrationController.RegisterPublicRoutes(publicApi, c.Config.RateLimits)
+
+		// Invitation links, which create the account of the invited person
+		invitationController.RegisterPublicRoutes(publicApi, c.Config.RateLimits)
 
 		// Public contract signing routes
-		contractSigningController.RegisterPublicRoutes(publicApi)
+		contractSigningController.RegisterPublicRoutes(publicApi, c.Config.RateLimits, c.Config.Server.LegacyRoutesSunset)
+
+		// Status callbacks of the digital notary
+		notaryController.RegisterPublicRoutes(publicApi)
+
+		// Transaction events of the payment gateway
+		paymentCheckoutController.RegisterPublicRoutes(publicApi)
+
+		// Invoice and subscription events of the platform billing provider
+		subscriptionController.RegisterPublicRoutes(publicApi)
+
+		// Verification of the yearly payment certificates issued to the tenants
+		paymentStatementController.RegisterPublicRoutes(publicApi)
+
+		// Verification of the deposit settlements signed by the tenants
+		securityDepositController.RegisterPublicRoutes(publicApi)
+
+		// Public catalog of the available properties and the applications of prospects
+		listingController.RegisterPublicRoutes(publicApi)
 
 		// Public file upload routes (with token validation)
-		fileUploadController.RegisterPublicRoutes(publicApi)
+		fileUploadController.RegisterPublicRoutes(publicApi, c.Config.RateLimits)
+
+		// Public branding of the organization behind the current domain
+		organizationController.RegisterPublicRoutes(publicApi)
+
+		// Open tracking pixel of the emails sent through the outbox
+		emailTrackingController.RegisterPublicRoutes(publicApi)
+
+		// Unsubscribe and manage-preferences links of the notification emails
+		notificationPreferenceController.RegisterPublicRoutes(publicApi)
 	}
 
+	// Routes registered from here to the end of the protected group require a JWT, which the
+	// API docs report
+	publicRoutes := routeKeys(router.Routes())
+
 	// Protected API routes (requires authentication)
 	api := router.Group("/api")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(sessionService))
+	// Requests made by admins impersonating a user are recorded in the audit log
+	api.Use(middleware.AuditImpersonation(auditService))
 	{
 		// Register user routes
 		userController.RegisterRoutes(api)
 
+		// Active sessions of the authenticated user, which they can revoke
+		sessionController.RegisterRoutes(api)
+
+		// In-app notifications of the authenticated user
+		notificationController.RegisterRoutes(api)
+
 		// Register person routes
 		personController.RegisterRoutes(api)
 
@@ -98,6 +380,7 @@ func StartHTTPServer() error {
 			properties.GET("/resident/:residentId", propertyController.GetByResident)
 			properties.GET("/manager/:managerId", propertyController.GetByManagerID)
 			properties.GET("/user/:userId", propertyController.GetByUserID)
+			properties.GET("/:id/occupancy", rentalHistoryController.GetPropertyOccupancy)
 			properties.POST("", propertyController.Create)
 		}
 
@@ -108,6 +391,8 @@ func StartHTTPServer() error {
 		rentals.GET("/:id", rentalController.GetByID)
 		rentals.GET("/by-property/:property_id", rentalController.GetByPropertyID)
 		rentals.GET("/by-renter/:renter_id", rentalController.GetByRenterID)
+		rentals.GET("/:id/files", fileUploadController.HandleListRentalFiles)
+		rentals.GET("/:id/files/:fileId", fileUploadController.HandleDownloadRentalFile)
 
 		// Register maintenance request routes
 		maintenanceRequests := api.Group("/maintenance-requests")
@@ -119,6 +404,9 @@ func StartHTTPServer() error {
 			maintenanceRequests.GET("/renter/:renterId", maintenanceRequestController.GetByRenterID)
 			maintenanceRequests.GET("/status/:status", maintenanceRequestController.GetByStatus)
 			maintenanceRequests.POST("", maintenanceRequestController.Create)
+			maintenanceRequests.GET("/:id/comments", maintenanceRequestController.GetComments)
+			maintenanceRequests.POST("/:id/comments", maintenanceRequestController.AddComment)
+			maintenanceRequests.GET("/:id/history", maintenanceRequestController.GetStatusHistory)
 			// Note: Update and Delete are admin-only, registered below
 		}
 
@@ -144,6 +432,51 @@ func StartHTTPServer() error {
 		// Register authenticated file upload routes (for regular users)
 		fileUploadController.RegisterAuthenticatedUploadRoutes(api)
 
+		// Register the reminder send-time preferences of the current user
+		reminderPreferenceController.RegisterRoutes(api)
+
+		// Register the notification preferences of the current user
+		notificationPreferenceController.RegisterRoutes(api)
+
+		// Register the digest subscription of the current manager
+		managerDigestController.RegisterRoutes(api)
+
+		// Register the read-only inventory of a property
+		inventoryController.RegisterRoutes(api)
+
+		// Register the promotional offer of a property listing
+		promotionController.RegisterRoutes(api)
+
+		// Register the afianzadora studies of the tenants
+		guaranteeController.RegisterRoutes(api)
+
+		// Register the manual de convivencia of the buildings and its acknowledgments
+		reglamentoController.RegisterRoutes(api)
+
+		// Callback URLs of managers notified of the signing events
+		webhookController.RegisterRoutes(api)
+
+		// Yearly payment certification of the current tenant
+		paymentStatementController.RegisterRoutes(api)
+
+		// Online payment of this month's rent by the current tenant
+		paymentCheckoutController.RegisterRoutes(api)
+
+		// Platform subscription of the current manager
+		subscriptionController.RegisterRoutes(api)
+
+		// Security deposits of the current tenant and the signature of their settlement
+		securityDepositController.RegisterRoutes(api)
+
+		// Dashboard counts of the current admin or manager
+		dashboardController.RegisterRoutes(api)
+
+		// Read-only GraphQL queries of the dashboard, scoped by the role of the current user
+		graphQLController.RegisterRoutes(api)
+
+		// Full-text search filtered by the role of the current user
+		searchController.RegisterRoutes(api)
+
 		// Admin-only routes
 		adminApi := api.Group("/admin")
 		adminApi.Use(middleware.AdminMiddleware())
@@ -153,6 +486,10 @@ func StartHTTPServer() error {
 			{
 				adminProperties.PUT("/:id", propertyController.Update)
 				adminProperties.DELETE("/:id", propertyController.Delete)
+				adminProperties.POST("/:id/photos", propertyController.UploadPhoto)
+				adminProperties.PUT("/:id/photos/order", propertyController.ReorderPhotos)
+				adminProperties.PUT("/:id/photos/:photoId", propertyController.UpdatePhoto)
+				adminProperties.DELETE("/:id/photos/:photoId", propertyController.DeletePhoto)
 			}
 
 			// Admin-only payment endpoints
@@ -180,8 +517,16 @@ func StartHTTPServer() error {
 			{
 				adminMaintenanceRequests.PUT("/:id", maintenanceRequestController.Update)
 				adminMaintenanceRequests.DELETE("/:id", maintenanceRequestController.Delete)
+				adminMaintenanceRequests.PUT("/:id/assign", maintenanceRequestController.AssignProvider)
+				adminMaintenanceRequests.DELETE("/:id/assign", maintenanceRequestController.UnassignProvider)
 			}
 
+			// Admin-only service provider endpoints
+			serviceProviderController.RegisterRoutes(adminApi)
+
+			// Admin-only organization and custom domain endpoints
+			organizationController.RegisterRoutes(adminApi)
+
 			// Admin-only Rental CUD routes
 			adminRentals := adminApi.Group("/rentals")
 			{
@@ -196,21 +541,98 @@ func StartHTTPServer() error {
 
 			// Admin-only Email routes
 			emailController.RegisterRoutes(adminApi)
+			emailTemplateController.RegisterRoutes(adminApi)
+
+			// Admin-only reminder send-time preferences of any person
+			reminderPreferenceController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only notification preferences of any person
+			notificationPreferenceController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only trigger of the manager digest job
+			managerDigestController.RegisterAdminRoutes(adminApi)
 
 			// Admin-only Contract routes
 			contractController.RegisterRoutes(adminApi)
 
+			// Admin-only editable contract templates
+			contractTemplateController.RegisterRoutes(adminApi)
+
+			// Admin-only property inventories printed as contract annexes
+			inventoryController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only listing promotions and their conversions
+			promotionController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only submission of tenants to the afianzadora
+			guaranteeController.RegisterAdminRoutes(adminApi)
+
 			// Admin-only Contract Signing routes that require authentication
 			contractSigningController.RegisterAuthRoutes(adminApi)
 
+			// Admin-only management of the certificate contracts are signed with
+			signingCertificateController.RegisterRoutes(adminApi)
+
+			// Admin-only notarial authentication of signed contracts and their dossier
+			notaryController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only cession of rentals to a new owner
+			contractCessionController.RegisterRoutes(adminApi)
+
+			// Admin-only security deposits, their deductions and refund at move-out
+			securityDepositController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only move-in and move-out inspections, their templates and comparison
+			inspectionController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only listings of vacant properties and their applications
+			listingController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only parties of the rental contracts (cosigners, witnesses and owner)
+			rentalPartyController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only buildings (copropiedades) with their cuota de administración and administrator
+			buildingController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only DIAN electronic invoices of the rent payments
+			einvoiceController.RegisterRoutes(adminApi)
+
+			// Admin-only platform subscriptions of the managers
+			subscriptionController.RegisterAdminRoutes(adminApi)
+
 			// Admin-only Manager Invitation routes - explicitly set up without using RegisterRoutes
 			adminApi.POST("/invitations/manager", managerInvitationController.SendInvitation)
 
+			// Admin-only signed invitations of tenants and co-managers
+			invitationController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only bulk activation, disabling and invitation resend of users
+			userBulkController.RegisterRoutes(adminApi)
+
 			// Admin-only File Upload routes (for generating upload links)
 			fileUploadController.RegisterRoutes(adminApi)
 
+			// Admin-only backups of the file bucket and restores from them
+			bucketBackupController.RegisterRoutes(adminApi)
+
+			// Admin-only recent login attempts, failed ones by default
+			userController.RegisterAdminRoutes(adminApi)
+
+			// Admin impersonation of users and the audit log of their requests
+			impersonationController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only runtime toggles of features and scheduled jobs
+			featureFlagController.RegisterAdminRoutes(adminApi)
+
+			// Admin-only usage metrics of deprecated legacy routes
+			adminApi.GET("/deprecated-routes", getDeprecatedRouteUsage)
+
 		}
 	}
+	authenticatedRoutes := routeKeys(router.Routes())
+	for key := range publicRoutes {
+		delete(authenticatedRoutes, key)
+	}
 
 	// Serve static files (frontend)
 	router.Static("/assets", "./static/assets")
@@ -220,25 +642,35 @@ func StartHTTPServer() error {
 		if !strings.HasPrefix(c.Request.URL.Path, "/api") {
 			c.File("./static/index.html")
 		} else {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API endpoint not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "APIEndpointNotFound"})
 		}
 	})
 
-	// Add health check endpoint
-	router.GET("/api/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	// Liveness and readiness probes with the status of the database, storage, email and Telegram
+	health := service.NewHealthService(c.Supabase, c.Config.Profile)
+	router.GET("/api/health", getHealth(health))
+	router.GET("/healthz", getHealth(health))
+	router.GET("/readyz", getReadiness(health))
+
+	// Prometheus metrics for the Grafana dashboards, protected by METRICS_TOKEN when it is set
+	router.GET("/metrics", gin.WrapH(metrics.Handler(c.Config.Server.MetricsToken)))
+
+	// Optional features of this deployment, file endpoints answer 503 when file storage is off
+	router.GET("/api/capabilities", getCapabilities(c.Config, virusScans))
 
 	// Legacy routes (temporary, should be migrated)
 	router.GET("/payers", getPayers)
-	router.GET("/validate_email", validateEmailHandler(repoFactory))
+	router.GET("/validate_email", validateEmailHandler(c, orgService, reminderScheduler))
 
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// OpenAPI 3 spec of every /api route and the Swagger UI rendering it, for the integrators
+	docs := NewAPIDocsController(router.Routes(), authenticatedRoutes)
+	docs.RegisterPublicRoutes(router)
 
-	return router.Run(":" + port)
+	return router, docs, jobs, nil
+}
+
+func getDeprecatedRouteUsage(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"routes": middleware.GetDeprecatedRouteUsage()})
 }
 
 func getPayers(c *gin.Context) {
@@ -246,17 +678,23 @@ func getPayers(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payers": payers})
 }
 
-func validateEmailHandler(repoFactory *storage.RepositoryFactory) gin.HandlerFunc {
+// validateEmailHandler triggers the reminder notifications. reminders is only used when
+// reminder send times are enabled, otherwise the reminders are sent right away.
+func validateEmailHandler(container *app.Container, orgService *service.OrganizationService, reminders *service.ReminderScheduler) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Println("ℹ️ [API] /validate_email endpoint triggered.")
-		personRepo := repoFactory.GetPersonRepository()
-		rentalRepo := repoFactory.GetRentalRepository()
-		propertyRepo := repoFactory.GetPropertyRepository()
-		userRepo := repoFactory.GetUserRepository()
-		pricingRepo := repoFactory.GetPricingRepository()
+		logging.FromContext(c).Info("/validate_email endpoint triggered")
+		personRepo := container.Persons
+		rentalRepo := container.Rentals
+		propertyRepo := container.Properties
+		userRepo := container.Users
+		pricingRepo := container.Pricing
+
+		if !reminders.Enabled() {
+			reminders = nil
+		}
 
 		// Run NotifyAll in a goroutine so it doesn't block the HTTP response
-		go service.NotifyAll(personRepo, rentalRepo, propertyRepo, userRepo, pricingRepo)
+		go service.NotifyAll(personRepo, rentalRepo, propertyRepo, userRepo, pricingRepo, orgService, reminders)
 
 		c.JSON(http.StatusOK, gin.H{"status": "Email notification process triggered in background."})
 	}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// defaultImpersonationTTL is how long an impersonation token lasts without IMPERSONATION_TTL
+const defaultImpersonationTTL = 30 * time.Minute
+
+// ImpersonationController lets the admins act as another user to reproduce their issues, and
+// lists the audit log the impersonated requests are recorded in
+type ImpersonationController struct {
+	users    storage.UserStore
+	sessions *service.SessionService
+	audit    *service.AuditService
+	ttl      time.Duration
+}
+
+// NewImpersonationController creates a new ImpersonationController with the token lifetime ttl
+// (IMPERSONATION_TTL), defaultImpersonationTTL when it is 0
+func NewImpersonationController(users storage.UserStore, sessions *service.SessionService, audit *service.AuditService, ttl time.Duration) *ImpersonationController {
+	if ttl <= 0 {
+		ttl = defaultImpersonationTTL
+	}
+	return &ImpersonationController{
+		users:    users,
+		sessions: sessions,
+		audit:    audit,
+		ttl:      ttl,
+	}
+}
+
+// RegisterAdminRoutes registers the impersonation and audit log routes on an admin-protected group
+func (c *ImpersonationController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.POST("/impersonate/:userId", c.Impersonate)
+	adminRouter.GET("/audit-log", c.GetAuditLog)
+}
+
+// Impersonate issues a token to act as another user
+// @Summary Impersonate a user
+// @Description Issues a short-lived token (IMPERSONATION_TTL, 30 minutes by default) that acts as the user. Every request made with it is recorded in the audit log with the user and the admin. Admins cannot be impersonated, and the token cannot change the password or manage the sessions of the user.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param request body object false "Reason for the impersonation"
+// @Success 201 {object} object
+// @Failure 403 {object} string "The user cannot be impersonated"
+// @Failure 404 {object} string "User not found"
+// @Router /admin/impersonate/{userId} [post]
+func (c *ImpersonationController) Impersonate(ctx *gin.Context) {
+	adminID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	user, err := c.users.GetByID(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
+		return
+	}
+	if user.Role == "admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminsCannotBeImpersonated"})
+		return
+	}
+	if user.Status == "disabled" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "DisabledUsersCannotBeImpersonated"})
+		return
+	}
+
+	now := time.Now()
+	session, token, err := c.sessions.StartImpersonation(ctx, user, adminID, ctx.ClientIP(), ctx.Request.UserAgent(), c.ttl, now)
+	if err != nil {
+		logging.FromContext(ctx).Error("error starting impersonation", "email", user.Email, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateToken"})
+		return
+	}
+
+	c.audit.Record(ctx, model.AuditLog{
+		Action:    "impersonate",
+		Entity:    "users",
+		EntityID:  user.ID,
+		ChangedBy: adminID,
+		Timestamp: now,
+		Details: gin.H{
+			"reason":     request.Reason,
+			"session_id": session.ID,
+			"expires_at": session.ExpiresAt,
+			"client_ip":  ctx.ClientIP(),
+		},
+	})
+	logging.FromContext(ctx).Info("impersonation started", "component", "impersonation", "admin_id", adminID, "user_id", user.ID, "email", user.Email, "expires_at", session.ExpiresAt.Format(time.RFC3339))
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"token":           token,
+		"expires_at":      session.ExpiresAt,
+		"session_id":      session.ID,
+		"impersonator_id": adminID,
+		"user": gin.H{
+			"id":        user.ID,
+			"email":     user.Email,
+			"role":      user.Role,
+			"person_id": user.PersonID,
+			"status":    user.Status,
+		},
+	})
+}
+
+// GetAuditLog lists the recent audit log entries for the admins
+// @Summary Recent audit log entries
+// @Description Entries of the last 24 hours by default, newest first. impersonated=true lists only the requests made while impersonating.
+// @Tags admin
+// @Produce json
+// @Param changed_by query string false "User the actions were made as"
+// @Param impersonated_by query string false "Admin who impersonated the user"
+// @Param impersonated query bool false "Only the impersonated actions"
+// @Param hours query int false "Hours back, 24 by default and 720 at most"
+// @Param limit query int false "Maximum entries, 100 by default and 500 at most"
+// @Success 200 {array} model.AuditLog
+// @Router /admin/audit-log [get]
+func (c *ImpersonationController) GetAuditLog(ctx *gin.Context) {
+	hours, err := strconv.Atoi(ctx.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > 720 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ImpersonationHoursOutOfRange"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "LimitOutOfRange500"})
+		return
+	}
+
+	filter := storage.AuditLogFilter{
+		ImpersonatedOnly: ctx.Query("impersonated") == "true",
+		Since:            time.Now().Add(-time.Duration(hours) * time.Hour),
+		Limit:            limit,
+	}
+	for param, target := range map[string]*uuid.UUID{"changed_by": &filter.ChangedBy, "impersonated_by": &filter.ImpersonatedBy} {
+		if value := ctx.Query(param); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidParameter", "params": gin.H{"Param": param}})
+				return
+			}
+			*target = id
+		}
+	}
+
+	entries, err := c.audit.List(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,705 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// InspectionController handles the move-in and move-out inspections of the rentals, their
+// checklist templates and their comparison
+type InspectionController struct {
+	repository    storage.InspectionStore
+	rentalRepo    storage.RentalStore
+	inventoryRepo storage.InventoryStore
+	signingRepo   storage.ContractSigningStore
+	inspections   *service.InspectionService
+	orgService    *service.OrganizationService
+	webhooks      *service.SigningWebhookDispatcher
+}
+
+// NewInspectionController creates a new InspectionController
+func NewInspectionController(
+	repository storage.InspectionStore,
+	rentalRepo storage.RentalStore,
+	inventoryRepo storage.InventoryStore,
+	signingRepo storage.ContractSigningStore,
+	inspections *service.InspectionService,
+	orgService *service.OrganizationService,
+	webhooks *service.SigningWebhookDispatcher,
+) *InspectionController {
+	return &InspectionController{
+		repository:    repository,
+		rentalRepo:    rentalRepo,
+		inventoryRepo: inventoryRepo,
+		signingRepo:   signingRepo,
+		inspections:   inspections,
+		orgService:    orgService,
+		webhooks:      webhooks,
+	}
+}
+
+// InspectionTemplateRequest defines a checklist of rooms and items
+type InspectionTemplateRequest struct {
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description"`
+	Rooms       []model.InventoryRoom `json:"rooms"`
+}
+
+// CreateInspectionRequest starts an inspection of a rental. Its checklist comes from the
+// template, or else from the move-in inspection for move-outs, the property inventory or the
+// default checklist.
+type CreateInspectionRequest struct {
+	Kind        string `json:"kind" binding:"required"` // move_in or move_out
+	TemplateID  string `json:"template_id"`
+	InspectedAt string `json:"inspected_at"` // YYYY-MM-DD, defaults to today
+	Notes       string `json:"notes"`
+}
+
+// UpdateInspectionRequest defines the rooms, conditions and notes of an inspection
+type UpdateInspectionRequest struct {
+	Rooms       []model.InventoryRoom `json:"rooms"`
+	Notes       string                `json:"notes"`
+	InspectedAt string                `json:"inspected_at"` // YYYY-MM-DD, unchanged if empty
+}
+
+// RegisterAdminRoutes registers the inspection routes on an admin-protected group
+func (c *InspectionController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	templates := adminRouter.Group("/inspection-templates")
+	{
+		templates.GET("", c.GetTemplates)
+		templates.POST("", c.CreateTemplate)
+		templates.PUT("/:id", c.UpdateTemplate)
+		templates.DELETE("/:id", c.DeleteTemplate)
+	}
+
+	adminRouter.GET("/contracts/:id/inspections", c.GetByRentalID)
+	adminRouter.POST("/contracts/:id/inspections", c.Create)
+	adminRouter.GET("/contracts/:id/inspections/comparison", c.GetComparison)
+	adminRouter.GET("/contracts/:id/inspections/comparison/pdf", c.ServeComparisonPDF)
+
+	inspections := adminRouter.Group("/inspections")
+	{
+		inspections.GET("/:id", c.GetByID)
+		inspections.PUT("/:id", c.Update)
+		inspections.DELETE("/:id", c.Delete)
+		inspections.POST("/:id/photos", c.UploadPhoto)
+		inspections.GET("/:id/pdf", c.ServePDF)
+		inspections.POST("/:id/acknowledgment", c.RequestAcknowledgment)
+	}
+}
+
+// GetTemplates lists the inspection checklist templates and the built-in default checklist
+func (c *InspectionController) GetTemplates(ctx *gin.Context) {
+	templates, err := c.repository.GetTemplates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"default":   service.DefaultInspectionTemplate(),
+	})
+}
+
+// CreateTemplate adds an inspection checklist template
+func (c *InspectionController) CreateTemplate(ctx *gin.Context) {
+	var req InspectionTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	c.saveTemplate(ctx, model.InspectionTemplate{}, req, http.StatusCreated)
+}
+
+// UpdateTemplate replaces the checklist of an inspection template
+func (c *InspectionController) UpdateTemplate(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var req InspectionTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	existing, err := c.repository.GetTemplateByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InspectionTemplateNotFound"})
+		return
+	}
+
+	c.saveTemplate(ctx, *existing, req, http.StatusOK)
+}
+
+// saveTemplate validates and saves a template with the checklist of a request
+func (c *InspectionController) saveTemplate(ctx *gin.Context, template model.InspectionTemplate, req InspectionTemplateRequest, status int) {
+	rooms := checklistRooms(req.Rooms)
+	if len(rooms) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InspectionTemplateNeedsRoom"})
+		return
+	}
+	if err := service.ValidateInspectionRooms(rooms, false); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	template.Description = strings.TrimSpace(req.Description)
+	template.Rooms = rooms
+
+	saved, err := c.repository.SaveTemplate(ctx, template)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveInspectionTemplate"})
+		return
+	}
+	ctx.JSON(status, saved)
+}
+
+// DeleteTemplate removes an inspection checklist template
+func (c *InspectionController) DeleteTemplate(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	if err := c.repository.DeleteTemplate(ctx, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetByRentalID lists the inspections of a rental, oldest first
+// @Summary List the inspections of a rental
+// @Tags inspections
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {array} model.Inspection
+// @Router /admin/contracts/{id}/inspections [get]
+func (c *InspectionController) GetByRentalID(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	inspections, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range inspections {
+		signInventoryPhotoURLs(&model.Inventory{Rooms: inspections[i].Rooms})
+	}
+
+	ctx.JSON(http.StatusOK, inspections)
+}
+
+// Create starts the move-in or move-out inspection of a rental with its checklist
+// @Summary Start an inspection of a rental
+// @Description The checklist comes from the chosen template, or else from the move-in inspection for move-outs, the property inventory or the default checklist
+// @Tags inspections
+// @Accept json
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Param inspection body CreateInspectionRequest true "Inspection"
+// @Success 201 {object} model.Inspection
+// @Router /admin/contracts/{id}/inspections [post]
+func (c *InspectionController) Create(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	var req CreateInspectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !model.IsValidInspectionKind(req.Kind) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInspectionKind"})
+		return
+	}
+	inspectedAt := time.Now()
+	if req.InspectedAt != "" {
+		inspectedAt, err = time.ParseInLocation("2006-01-02", req.InspectedAt, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInspectedAt"})
+			return
+		}
+	}
+
+	rental, err := c.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rental == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return
+	}
+
+	inspection := model.Inspection{
+		ID:          uuid.New(),
+		RentalID:    rental.ID,
+		Kind:        req.Kind,
+		InspectedAt: inspectedAt,
+		Notes:       strings.TrimSpace(req.Notes),
+		Status:      model.InspectionStatusDraft,
+	}
+	if authUser, exists := ctx.Get("user"); exists {
+		if user, ok := authUser.(*model.User); ok {
+			inspection.InspectorID = user.PersonID
+		}
+	}
+
+	rooms, ok := c.initialChecklist(ctx, req, rental)
+	if !ok {
+		return
+	}
+	if req.TemplateID != "" {
+		templateID := uuid.MustParse(req.TemplateID)
+		inspection.TemplateID = &templateID
+	}
+	inspection.Rooms = rooms
+
+	created, err := c.repository.Create(ctx, inspection)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveInspection"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("inspection started", "kind", created.Kind, "inspection_id", created.ID, "rental_id", rental.ID)
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// initialChecklist returns the rooms an inspection starts with, writing the error response
+// when the requested template cannot be used
+func (c *InspectionController) initialChecklist(ctx *gin.Context, req CreateInspectionRequest, rental *model.Rental) ([]model.InventoryRoom, bool) {
+	if req.TemplateID != "" {
+		templateID, err := uuid.Parse(req.TemplateID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidTemplateID"})
+			return nil, false
+		}
+		template, err := c.repository.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		if template == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "InspectionTemplateNotFound"})
+			return nil, false
+		}
+		return checklistRooms(template.Rooms), true
+	}
+
+	// The move-out reviews what was delivered, so both inspections can be compared item by item
+	if req.Kind == model.InspectionKindMoveOut {
+		if inspections, err := c.repository.GetByRentalID(ctx, rental.ID); err == nil {
+			for i := len(inspections) - 1; i >= 0; i-- {
+				if inspections[i].Kind == model.InspectionKindMoveIn {
+					return checklistRooms(inspections[i].Rooms), true
+				}
+			}
+		}
+	}
+
+	if inventory, err := c.inventoryRepo.GetByPropertyID(ctx, rental.PropertyID); err == nil && inventory != nil && len(inventory.Rooms) > 0 {
+		return checklistRooms(inventory.Rooms), true
+	}
+	return checklistRooms(service.DefaultInspectionTemplate().Rooms), true
+}
+
+// GetByID retrieves an inspection with fresh photo URLs
+func (c *InspectionController) GetByID(ctx *gin.Context) {
+	inspection, ok := c.loadInspection(ctx)
+	if !ok {
+		return
+	}
+	signInventoryPhotoURLs(&model.Inventory{Rooms: inspection.Rooms})
+
+	ctx.JSON(http.StatusOK, inspection)
+}
+
+// Update saves the rooms, conditions and notes of an inspection
+// @Summary Update an inspection
+// @Description Inspections can be edited until the tenant signs them. Editing one sent to sign is only possible after the tenant rejected it or the request expired.
+// @Tags inspections
+// @Accept json
+// @Produce json
+// @Param id path string true "Inspection ID"
+// @Param inspection body UpdateInspectionRequest true "Rooms and notes"
+// @Success 200 {object} model.Inspection
+// @Failure 409 {object} map[string]string "Inspection waiting for or with the tenant's signature"
+// @Router /admin/inspections/{id} [put]
+func (c *InspectionController) Update(ctx *gin.Context) {
+	inspection, ok := c.editableInspection(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateInspectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if err := service.ValidateInspectionRooms(req.Rooms, false); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.InspectedAt != "" {
+		inspectedAt, err := time.ParseInLocation("2006-01-02", req.InspectedAt, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInspectedAt"})
+			return
+		}
+		inspection.InspectedAt = inspectedAt
+	}
+
+	// Photo URLs are signed when read, only their paths are stored
+	for r := range req.Rooms {
+		room := &req.Rooms[r]
+		for p := range room.Photos {
+			room.Photos[p].URL = ""
+		}
+		for i := range room.Items {
+			for p := range room.Items[i].Photos {
+				room.Items[i].Photos[p].URL = ""
+			}
+		}
+	}
+	inspection.Rooms = req.Rooms
+	inspection.Notes = strings.TrimSpace(req.Notes)
+
+	updated, err := c.repository.Update(ctx, *inspection)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveInspection"})
+		return
+	}
+	signInventoryPhotoURLs(&model.Inventory{Rooms: updated.Rooms})
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete removes an inspection that was not signed by the tenant
+func (c *InspectionController) Delete(ctx *gin.Context) {
+	inspection, ok := c.editableInspection(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.repository.Delete(ctx, inspection.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadPhoto stores a photo of a room or item of an inspection. The returned photo is added
+// to the inspection by saving it with the photo in the room or item.
+func (c *InspectionController) UploadPhoto(ctx *gin.Context) {
+	inspection, ok := c.editableInspection(ctx)
+	if !ok {
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedPhotoType"})
+		return
+	}
+	if header.Size > maxInventoryPhotoSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoTooLarge"})
+		return
+	}
+
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadPhoto"})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	filePath := fmt.Sprintf("rentals/%s/inspecciones/%s/%d_%s%s", inspection.RentalID, inspection.ID, time.Now().Unix(), uuid.New().String()[:8], ext)
+	uploadResponse, err := storageService.UploadBytes(filePath, data, contentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("error uploading photo of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadPhoto"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.InventoryPhoto{
+		Path:    uploadResponse.Path,
+		URL:     uploadResponse.Link,
+		Caption: ctx.PostForm("caption"),
+	})
+}
+
+// ServePDF serves the acta of an inspection, the copy signed by the tenant once acknowledged
+func (c *InspectionController) ServePDF(ctx *gin.Context) {
+	inspection, ok := c.loadInspection(ctx)
+	if !ok {
+		return
+	}
+
+	var pdfData []byte
+	var err error
+	if inspection.SignedPDFPath != "" {
+		pdfData, err = loadStoredPDF(inspection.SignedPDFPath)
+	} else {
+		pdfData, err = c.inspections.RenderPDF(ctx, inspection.ID)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading acta of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateInspectionPDF"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=inspeccion_%s.pdf", inspection.Kind))
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// RequestAcknowledgment sends a complete inspection to the tenant of the rental to sign it
+// through the signing flow of the contracts
+// @Summary Send an inspection to the tenant to sign
+// @Description Every item must have a condition. The tenant receives the signing link by email, signs with a one-time code and the signed acta is stored with the rental files.
+// @Tags inspections
+// @Produce json
+// @Param id path string true "Inspection ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Inspection waiting for or with the tenant's signature"
+// @Router /admin/inspections/{id}/acknowledgment [post]
+func (c *InspectionController) RequestAcknowledgment(ctx *gin.Context) {
+	inspection, ok := c.editableInspection(ctx)
+	if !ok {
+		return
+	}
+	if len(inspection.Rooms) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InspectionHasNoRooms"})
+		return
+	}
+	if err := service.ValidateInspectionRooms(inspection.Rooms, true); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InspectionIncomplete", "detail": err.Error()})
+		return
+	}
+
+	data, err := c.inspections.DocumentData(ctx, *inspection)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading data of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadInspectionRental"})
+		return
+	}
+	if data.TenantEmail == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "TenantHasNoUserToSign"})
+		return
+	}
+
+	pdfData, err := service.GenerateInspectionPDF(*data)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating acta of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateInspectionPDF"})
+		return
+	}
+
+	org := c.orgService.ForPerson(ctx, data.Tenant.ID)
+	signingRequest, err := service.CreateSignatureRequest(model.ContractSigningInfo{
+		ContractID:     inspection.ID.String(),
+		RecipientID:    data.Tenant.ID.String(),
+		RecipientEmail: data.TenantEmail,
+		PDFData:        pdfData,
+		SignerName:     data.Tenant.FullName,
+		BaseURL:        service.OrganizationBaseURL(org),
+		RequestedBy:    requesterPersonID(ctx),
+		DocumentType:   model.SigningDocumentInspection,
+		Organization:   org,
+	}, 7)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating signature request of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSendInspectionToSign"})
+		return
+	}
+	if _, err := c.signingRepo.CreateSigningRequest(ctx, *signingRequest); err != nil {
+		logging.FromContext(ctx).Error("error saving signature request of inspection", "inspection_id", inspection.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveSignatureRequest"})
+		return
+	}
+	c.webhooks.Dispatch(model.WebhookEventSigningCreated, signingRequest.ID)
+
+	inspection.Status = model.InspectionStatusPendingAcknowledgment
+	inspection.SigningID = signingRequest.ID
+	if _, err := c.repository.Update(ctx, *inspection); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveInspection"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":            "Inspection sent to the tenant to sign",
+		"signing_id":         signingRequest.ID,
+		"expires_at":         signingRequest.ExpiresAt,
+		"expires_at_display": service.FormatDate(signingRequest.ExpiresAt),
+	})
+}
+
+// GetComparison compares the move-out inspection of a rental with its move-in inspection
+// @Summary Compare the move-in and move-out inspections of a rental
+// @Description Reports the items returned deteriorated or missing, which justify the deductions from the security deposit
+// @Tags inspections
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {object} service.InspectionComparison
+// @Failure 404 {object} map[string]string "The rental lacks a move-in or move-out inspection"
+// @Router /admin/contracts/{id}/inspections/comparison [get]
+func (c *InspectionController) GetComparison(ctx *gin.Context) {
+	comparison, ok := c.comparison(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, comparison)
+}
+
+// ServeComparisonPDF serves the comparison report of the inspections of a rental, to attach as
+// the receipt of the deposit deductions
+func (c *InspectionController) ServeComparisonPDF(ctx *gin.Context) {
+	comparison, ok := c.comparison(ctx)
+	if !ok {
+		return
+	}
+
+	data, err := c.inspections.DocumentData(ctx, *comparison.MoveOut)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading data of inspection", "move_out_id", comparison.MoveOut.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadInspectionsRental"})
+		return
+	}
+
+	pdfData, err := service.GenerateInspectionComparisonPDF(*comparison, *data)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating inspection comparison of rental", "rental_id", comparison.RentalID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateComparisonReport"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename=comparativo_inspecciones.pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// comparison compares the inspections of the rental of the :id path parameter, writing the
+// error response when it lacks one of them
+func (c *InspectionController) comparison(ctx *gin.Context) (*service.InspectionComparison, bool) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return nil, false
+	}
+
+	comparison, err := c.inspections.Comparison(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if comparison == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InspectionComparisonNeedsBoth"})
+		return nil, false
+	}
+	return comparison, true
+}
+
+// loadInspection loads the inspection of the :id path parameter, writing the error response
+// when it cannot be found
+func (c *InspectionController) loadInspection(ctx *gin.Context) (*model.Inspection, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	inspection, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if inspection == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InspectionNotFound"})
+		return nil, false
+	}
+	return inspection, true
+}
+
+// editableInspection loads an inspection that can still be changed: a draft, or one whose
+// signing request was rejected or expired, which goes back to draft
+func (c *InspectionController) editableInspection(ctx *gin.Context) (*model.Inspection, bool) {
+	inspection, ok := c.loadInspection(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	switch inspection.Status {
+	case model.InspectionStatusAcknowledged:
+		ctx.JSON(http.StatusConflict, gin.H{"error": "InspectionAlreadySigned"})
+		return nil, false
+	case model.InspectionStatusPendingAcknowledgment:
+		record, err := c.signingRepo.GetByID(ctx, inspection.SigningID)
+		if err == nil && record != nil && record.Status == string(model.StatusPending) && time.Now().Before(record.ExpiresAt) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "InspectionAwaitingTenantSignature", "signing_id": inspection.SigningID})
+			return nil, false
+		}
+		inspection.Status = model.InspectionStatusDraft
+		inspection.SigningID = ""
+	}
+	return inspection, true
+}
+
+// checklistRooms copies the rooms and items of a checklist without the conditions, notes and
+// photos of the inspection or inventory they come from
+func checklistRooms(rooms []model.InventoryRoom) []model.InventoryRoom {
+	checklist := make([]model.InventoryRoom, 0, len(rooms))
+	for _, room := range rooms {
+		items := make([]model.InventoryItem, 0, len(room.Items))
+		for _, item := range room.Items {
+			items = append(items, model.InventoryItem{Name: item.Name, Quantity: item.Quantity})
+		}
+		checklist = append(checklist, model.InventoryRoom{Name: room.Name, Items: items})
+	}
+	return checklist
+}
@@ -0,0 +1,313 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// maxInventoryPhotoSize is the largest photo accepted for an inventory (10 MB)
+const maxInventoryPhotoSize = 10 << 20
+
+// InventoryController handles HTTP requests for property inventories, printed as an
+// annex of the rental contracts
+type InventoryController struct {
+	repository   storage.InventoryStore
+	propertyRepo storage.PropertyStore
+}
+
+// NewInventoryController creates a new InventoryController
+func NewInventoryController(repository storage.InventoryStore, propertyRepo storage.PropertyStore) *InventoryController {
+	return &InventoryController{
+		repository:   repository,
+		propertyRepo: propertyRepo,
+	}
+}
+
+// UpdateInventoryRequest defines the rooms and notes of a property inventory
+type UpdateInventoryRequest struct {
+	Rooms []model.InventoryRoom `json:"rooms"`
+	Notes string                `json:"notes"`
+}
+
+// RegisterRoutes registers the inventory routes available to authenticated users
+func (c *InventoryController) RegisterRoutes(router *gin.RouterGroup) {
+	inventories := router.Group("/inventories")
+	{
+		inventories.GET("/property/:propertyId", c.GetByPropertyID)
+	}
+}
+
+// RegisterAdminRoutes registers the inventory routes on an admin-protected group
+func (c *InventoryController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	inventories := adminRouter.Group("/inventories")
+	{
+		inventories.GET("/conditions", c.GetConditions)
+		inventories.PUT("/property/:propertyId", c.Save)
+		inventories.DELETE("/property/:propertyId", c.Delete)
+		inventories.POST("/property/:propertyId/photos", c.UploadPhoto)
+		inventories.GET("/property/:propertyId/annex", c.PreviewAnnex)
+	}
+}
+
+// GetByPropertyID retrieves the inventory of a property
+func (c *InventoryController) GetByPropertyID(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	inventory, err := c.repository.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if inventory == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InventoryNotFound"})
+		return
+	}
+	signInventoryPhotoURLs(inventory)
+
+	ctx.JSON(http.StatusOK, inventory)
+}
+
+// signInventoryPhotoURLs replaces the stored photo URLs, which expire, with fresh signed ones
+func signInventoryPhotoURLs(inventory *model.Inventory) {
+	var photos []*model.InventoryPhoto
+	for r := range inventory.Rooms {
+		room := &inventory.Rooms[r]
+		for p := range room.Photos {
+			photos = append(photos, &room.Photos[p])
+		}
+		for i := range room.Items {
+			for p := range room.Items[i].Photos {
+				photos = append(photos, &room.Items[i].Photos[p])
+			}
+		}
+	}
+	signPhotoURLs(photos)
+}
+
+// signPhotoURLs replaces the URLs of the photos with signed URLs of their stored paths
+func signPhotoURLs(photos []*model.InventoryPhoto) {
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil || len(photos) == 0 {
+		return
+	}
+
+	paths := make([]string, len(photos))
+	for i, photo := range photos {
+		paths[i] = photo.Path
+	}
+	urls, err := storageService.SignedURLs(paths, storageService.URLTTL())
+	if err != nil {
+		slog.Error("error signing photo URLs", "error", err)
+		return
+	}
+	for _, photo := range photos {
+		photo.URL = urls[photo.Path]
+	}
+}
+
+// GetConditions lists the conditions an inventory item can be in
+func (c *InventoryController) GetConditions(ctx *gin.Context) {
+	conditions := make([]gin.H, 0, len(model.ItemConditions))
+	for _, condition := range model.ItemConditions {
+		conditions = append(conditions, gin.H{"value": condition, "label": service.ItemConditionLabel(condition)})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"conditions": conditions})
+}
+
+// Save creates or replaces the inventory of a property
+func (c *InventoryController) Save(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	var req UpdateInventoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	inventory := model.Inventory{
+		PropertyID: propertyID,
+		Rooms:      req.Rooms,
+		Notes:      req.Notes,
+	}
+	if inventory.Rooms == nil {
+		inventory.Rooms = []model.InventoryRoom{}
+	}
+	if err := service.ValidateInventory(&inventory); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := c.repository.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if existing == nil {
+		created, err := c.repository.Create(ctx, inventory)
+		if err != nil {
+			logging.FromContext(ctx).Error("error creating inventory for property", "property_id", propertyID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateInventory"})
+			return
+		}
+		ctx.JSON(http.StatusCreated, created)
+		return
+	}
+
+	updated, err := c.repository.Update(ctx, inventory)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete removes the inventory of a property
+func (c *InventoryController) Delete(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	if err := c.repository.DeleteByPropertyID(ctx, propertyID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadPhoto stores a photo of a room or item of the inventory of a property. The returned
+// photo is added to the inventory by saving it with the photo in the room or item.
+func (c *InventoryController) UploadPhoto(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedPhotoType"})
+		return
+	}
+	if header.Size > maxInventoryPhotoSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoTooLarge"})
+		return
+	}
+
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadPhoto"})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	filePath := fmt.Sprintf("inventory/%s/%d_%s%s", propertyID, time.Now().Unix(), uuid.New().String()[:8], ext)
+	uploadResponse, err := storageService.UploadBytes(filePath, data, contentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("error uploading inventory photo for property", "property_id", propertyID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadPhoto"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.InventoryPhoto{
+		Path:    uploadResponse.Path,
+		URL:     uploadResponse.Link,
+		Caption: ctx.PostForm("caption"),
+	})
+}
+
+// PreviewAnnex renders the inventory annex of a property without contract data
+func (c *InventoryController) PreviewAnnex(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	inventory, err := c.repository.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if inventory == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InventoryNotFound"})
+		return
+	}
+
+	pdfBytes, err := service.GenerateInventoryAnnexPDF(service.ContractPDF{
+		Property:     property,
+		CreationDate: time.Now(),
+		Inventory:    inventory,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error rendering inventory annex of property", "property_id", propertyID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRenderInventoryAnnex"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename=anexo_inventario.pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
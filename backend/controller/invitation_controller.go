@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// InvitationController handles the invitations of tenants and co-managers: the admins send
+// them and the invited people accept them from the emailed link
+type InvitationController struct {
+	invitations *service.InvitationService
+	persons     storage.PersonStore
+	properties  storage.PropertyStore
+	orgService  *service.OrganizationService
+}
+
+// NewInvitationController creates a new InvitationController
+func NewInvitationController(invitations *service.InvitationService, factory *storage.RepositoryFactory, orgService *service.OrganizationService) *InvitationController {
+	return &InvitationController{
+		invitations: invitations,
+		persons:     factory.GetPersonRepository(),
+		properties:  factory.GetPropertyRepository(),
+		orgService:  orgService,
+	}
+}
+
+// RegisterAdminRoutes registers the routes to send, list and revoke invitations on an
+// admin-protected group
+func (c *InvitationController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.POST("/invitations", c.Send)
+	adminRouter.GET("/invitations", c.GetAll)
+	adminRouter.DELETE("/invitations/:id", c.Revoke)
+}
+
+// RegisterPublicRoutes registers the routes of the invitation links, limited per client IP by
+// the Invitation limit (RATE_LIMIT_INVITATION)
+func (c *InvitationController) RegisterPublicRoutes(router *gin.RouterGroup, limits config.RateLimitConfig) {
+	limit := middleware.RateLimit("invitation", middleware.RateLimitOr(limits.Invitation, "20/1h"), middleware.ClientIPKey)
+
+	invitations := router.Group("/invitations", limit)
+	{
+		invitations.GET("/:token", c.GetByToken)
+		invitations.POST("/accept", c.Accept)
+	}
+}
+
+// Send invites a tenant or co-manager by email
+// @Summary Send an invitation
+// @Description Emails a signed link valid for INVITATION_TTL (7 days by default) to create an account with the role. Without person_id a person is created with name. Managers join the organization and manage the property; residents live in the property.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param invitation body service.InvitationInput true "Invitation"
+// @Success 201 {object} model.Invitation
+// @Failure 400 {object} string "Invalid invitation"
+// @Failure 409 {object} string "Email already registered"
+// @Router /admin/invitations [post]
+func (c *InvitationController) Send(ctx *gin.Context) {
+	adminID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var input service.InvitationInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := c.invitations.Send(ctx, input, adminID, time.Now())
+	switch {
+	case errors.Is(err, service.ErrInvalidInvitation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrInvitationEmailTaken):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logging.FromContext(ctx).Error("error sending invitation", "email", input.Email, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSendInvitation"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, invitation)
+}
+
+// invitationResponse is an invitation with its state
+type invitationResponse struct {
+	model.Invitation
+	Status string `json:"status"`
+}
+
+// GetAll lists the invitations
+// @Summary List invitations
+// @Description Every invitation with its status (pending, accepted, revoked or expired), newest first
+// @Tags admin
+// @Produce json
+// @Success 200 {array} invitationResponse
+// @Router /admin/invitations [get]
+func (c *InvitationController) GetAll(ctx *gin.Context) {
+	invitations, err := c.invitations.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	response := make([]invitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		response = append(response, invitationResponse{Invitation: invitation, Status: invitation.Status(now)})
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Revoke cancels a pending invitation
+// @Summary Revoke an invitation
+// @Tags admin
+// @Param id path string true "Invitation ID"
+// @Success 204
+// @Failure 404 {object} string "No pending invitation"
+// @Router /admin/invitations/{id} [delete]
+func (c *InvitationController) Revoke(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	err = c.invitations.Revoke(ctx, id, time.Now())
+	if errors.Is(err, service.ErrInvitationNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InvitationNotFound"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetByToken shows the invitation of a link before it is accepted
+// @Summary Open an invitation link
+// @Tags users
+// @Produce json
+// @Param token path string true "Token of the invitation link"
+// @Success 200 {object} object
+// @Failure 400 {object} string "Invalid or expired invitation"
+// @Router /invitations/{token} [get]
+func (c *InvitationController) GetByToken(ctx *gin.Context) {
+	invitation, err := c.invitations.Open(ctx, ctx.Param("token"), time.Now())
+	if errors.Is(err, service.ErrInvalidInvitationToken) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvitationInvalid"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"email":      invitation.Email,
+		"role":       invitation.Role,
+		"expires_at": invitation.ExpiresAt,
+	}
+	if person, err := c.persons.GetByID(ctx, invitation.PersonID); err == nil && person != nil {
+		response["name"] = person.FullName
+	}
+	if invitation.OrganizationID != nil {
+		if org := c.orgService.ByID(ctx, *invitation.OrganizationID); org != nil {
+			response["organization"] = org.Name
+		}
+	}
+	if invitation.PropertyID != nil {
+		if property, err := c.properties.GetByID(ctx, *invitation.PropertyID); err == nil && property != nil {
+			response["property_address"] = property.Address
+		}
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Accept creates the account of an invitation
+// @Summary Accept an invitation
+// @Description Creates the account with the password and links it to the organization and property of the invitation. The link cannot be used again.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body object true "Token of the invitation link and password"
+// @Success 201 {object} object
+// @Failure 400 {object} string "Invalid or expired invitation"
+// @Failure 409 {object} string "Email already registered"
+// @Router /invitations/accept [post]
+func (c *InvitationController) Accept(ctx *gin.Context) {
+	var request struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.invitations.Accept(ctx, request.Token, request.Password, time.Now())
+	switch {
+	case errors.Is(err, service.ErrInvalidInvitationToken):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvitationInvalid"})
+		return
+	case errors.Is(err, service.ErrInvitationEmailTaken):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "EmailAlreadyRegistered"})
+		return
+	case err != nil:
+		logging.FromContext(ctx).Error("error accepting invitation", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateUserAccount"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Cuenta creada. Ya puedes iniciar sesión",
+		"user": gin.H{
+			"id":        user.ID,
+			"email":     user.Email,
+			"role":      user.Role,
+			"person_id": user.PersonID,
+			"status":    user.Status,
+		},
+	})
+}
@@ -0,0 +1,647 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ListingController handles the listings of vacant properties, their public catalog and the
+// applications of interested prospects
+type ListingController struct {
+	repository   storage.ListingStore
+	propertyRepo storage.PropertyStore
+	personRepo   storage.PersonStore
+	userRepo     storage.UserStore
+	orgService   *service.OrganizationService
+}
+
+// NewListingController creates a new ListingController
+func NewListingController(
+	repository storage.ListingStore,
+	propertyRepo storage.PropertyStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+	orgService *service.OrganizationService,
+) *ListingController {
+	return &ListingController{
+		repository:   repository,
+		propertyRepo: propertyRepo,
+		personRepo:   personRepo,
+		userRepo:     userRepo,
+		orgService:   orgService,
+	}
+}
+
+// ListingRequest defines the rent terms and description of a listing
+type ListingRequest struct {
+	Title         string  `json:"title" binding:"required"`
+	Description   string  `json:"description"`
+	MonthlyRent   float64 `json:"monthly_rent" binding:"required,gt=0"`
+	AdminFee      float64 `json:"admin_fee" binding:"gte=0"`
+	DepositAmount float64 `json:"deposit_amount" binding:"gte=0"`
+	MinTermMonths int     `json:"min_term_months" binding:"gte=0"` // Defaults to 12
+	AvailableFrom string  `json:"available_from"`                  // YYYY-MM-DD, optional
+	Bedrooms      int     `json:"bedrooms" binding:"gte=0"`
+	Bathrooms     int     `json:"bathrooms" binding:"gte=0"`
+	AreaM2        float64 `json:"area_m2" binding:"gte=0"`
+	PetsAllowed   bool    `json:"pets_allowed"`
+}
+
+// ListingStatusRequest publishes, pauses or closes a listing
+type ListingStatusRequest struct {
+	Status string `json:"status" binding:"required"` // available, paused or rented
+}
+
+// ListingApplicationRequest is the application of a prospect to a listing
+type ListingApplicationRequest struct {
+	FullName       string  `json:"full_name" binding:"required"`
+	Email          string  `json:"email" binding:"required,email"`
+	Phone          string  `json:"phone" binding:"required"`
+	DocumentNumber string  `json:"document_number"`
+	Occupation     string  `json:"occupation"`
+	MonthlyIncome  float64 `json:"monthly_income" binding:"gte=0"`
+	Occupants      int     `json:"occupants" binding:"gte=0"` // Defaults to 1
+	DesiredMoveIn  string  `json:"desired_move_in"`           // YYYY-MM-DD, optional
+	Message        string  `json:"message"`
+}
+
+// UpdateApplicationRequest records the follow-up of an application
+type UpdateApplicationRequest struct {
+	Status       string `json:"status" binding:"required"` // new, contacted, approved or rejected
+	ManagerNotes string `json:"manager_notes"`
+}
+
+// RegisterAdminRoutes registers the listing routes on an admin-protected group
+func (c *ListingController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	listings := adminRouter.Group("/listings")
+	{
+		listings.GET("", c.GetAll)
+		listings.PUT("/property/:propertyId", c.Save)
+		listings.GET("/:id", c.GetByID)
+		listings.PUT("/:id/status", c.UpdateStatus)
+		listings.DELETE("/:id", c.Delete)
+		listings.POST("/:id/photos", c.UploadPhoto)
+		listings.DELETE("/:id/photos", c.DeletePhoto)
+		listings.GET("/:id/applications", c.GetApplications)
+	}
+
+	adminRouter.PUT("/listing-applications/:id", c.UpdateApplication)
+}
+
+// RegisterPublicRoutes registers the public catalog of available properties and its applications
+func (c *ListingController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/public/listings", c.GetPublicListings)
+	router.GET("/public/listings/:id", c.GetPublicListing)
+	router.POST("/public/listings/:id/applications", c.Apply)
+}
+
+// GetAll lists every listing with its status
+func (c *ListingController) GetAll(ctx *gin.Context) {
+	listings, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	listings = paginate(ctx, listings)
+	page := make([]*model.PropertyListing, len(listings))
+	for i := range listings {
+		page[i] = &listings[i]
+	}
+	signListingPhotoURLs(page...)
+
+	ctx.JSON(http.StatusOK, listings)
+}
+
+// GetByID retrieves a listing with fresh photo URLs
+func (c *ListingController) GetByID(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+	signListingPhotoURLs(listing)
+
+	ctx.JSON(http.StatusOK, listing)
+}
+
+// Save lists a property as available with its rent terms, or updates the terms of its listing
+// @Summary Create or update the listing of a property
+// @Description A new listing is published right away. The city is taken from the property.
+// @Tags listings
+// @Accept json
+// @Produce json
+// @Param propertyId path string true "Property ID"
+// @Param listing body ListingRequest true "Rent terms"
+// @Success 200 {object} model.PropertyListing
+// @Router /admin/listings/property/{propertyId} [put]
+func (c *ListingController) Save(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	var req ListingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	listing, err := c.repository.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if listing == nil {
+		now := time.Now()
+		listing = &model.PropertyListing{
+			PropertyID:  propertyID,
+			Photos:      []model.InventoryPhoto{},
+			Status:      model.ListingStatusAvailable,
+			PublishedAt: &now,
+		}
+	}
+
+	listing.AvailableFrom = nil
+	if req.AvailableFrom != "" {
+		availableFrom, err := time.ParseInLocation("2006-01-02", req.AvailableFrom, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidAvailableFrom"})
+			return
+		}
+		listing.AvailableFrom = &availableFrom
+	}
+	if req.MinTermMonths == 0 {
+		req.MinTermMonths = 12
+	}
+	// Characteristics not given are taken from the property
+	if req.Bedrooms == 0 {
+		req.Bedrooms = property.Bedrooms
+	}
+	if req.Bathrooms == 0 {
+		req.Bathrooms = property.Bathrooms
+	}
+	if req.AreaM2 == 0 {
+		req.AreaM2 = property.AreaM2
+	}
+
+	listing.City = strings.TrimSpace(property.City)
+	listing.Title = strings.TrimSpace(req.Title)
+	listing.Description = strings.TrimSpace(req.Description)
+	listing.MonthlyRent = req.MonthlyRent
+	listing.AdminFee = req.AdminFee
+	listing.DepositAmount = req.DepositAmount
+	listing.MinTermMonths = req.MinTermMonths
+	listing.Bedrooms = req.Bedrooms
+	listing.Bathrooms = req.Bathrooms
+	listing.AreaM2 = req.AreaM2
+	listing.PetsAllowed = req.PetsAllowed
+
+	saved, err := c.repository.Save(ctx, *listing)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveListing"})
+		return
+	}
+	signListingPhotoURLs(saved)
+
+	ctx.JSON(http.StatusOK, saved)
+}
+
+// UpdateStatus publishes, pauses or closes a listing
+// @Summary Change the status of a listing
+// @Description Only available listings are shown in the public catalog and accept applications
+// @Tags listings
+// @Accept json
+// @Produce json
+// @Param id path string true "Listing ID"
+// @Param status body ListingStatusRequest true "New status"
+// @Success 200 {object} model.PropertyListing
+// @Router /admin/listings/{id}/status [put]
+func (c *ListingController) UpdateStatus(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+
+	var req ListingStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if _, known := model.ListingStatusTranslations[req.Status]; !known {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidListingStatus"})
+		return
+	}
+
+	// Publishing again moves the listing to the top of the catalog
+	if req.Status == model.ListingStatusAvailable && listing.Status != model.ListingStatusAvailable {
+		now := time.Now()
+		listing.PublishedAt = &now
+	}
+	listing.Status = req.Status
+
+	saved, err := c.repository.Save(ctx, *listing)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveListing"})
+		return
+	}
+	signListingPhotoURLs(saved)
+
+	ctx.JSON(http.StatusOK, saved)
+}
+
+// Delete removes a listing and its applications
+func (c *ListingController) Delete(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.repository.Delete(ctx, listing.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadPhoto adds a photo to a listing
+func (c *ListingController) UploadPhoto(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedPhotoType"})
+		return
+	}
+	if header.Size > maxInventoryPhotoSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoTooLarge"})
+		return
+	}
+
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadPhoto"})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	filePath := fmt.Sprintf("properties/%s/publicacion/%d_%s%s", listing.PropertyID, time.Now().Unix(), uuid.New().String()[:8], ext)
+	uploadResponse, err := storageService.UploadBytes(filePath, data, contentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("error uploading photo of listing", "listing_id", listing.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadPhoto"})
+		return
+	}
+
+	listing.Photos = append(listing.Photos, model.InventoryPhoto{
+		Path:    uploadResponse.Path,
+		Caption: ctx.PostForm("caption"),
+	})
+	saved, err := c.repository.Save(ctx, *listing)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveListing"})
+		return
+	}
+	signListingPhotoURLs(saved)
+
+	ctx.JSON(http.StatusCreated, saved)
+}
+
+// DeletePhoto removes the photo with the path query parameter from a listing
+func (c *ListingController) DeletePhoto(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+
+	path := ctx.Query("path")
+	photos := make([]model.InventoryPhoto, 0, len(listing.Photos))
+	for _, photo := range listing.Photos {
+		if photo.Path != path {
+			photos = append(photos, photo)
+		}
+	}
+	if len(photos) == len(listing.Photos) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PhotoNotFound"})
+		return
+	}
+	listing.Photos = photos
+
+	saved, err := c.repository.Save(ctx, *listing)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveListing"})
+		return
+	}
+	signListingPhotoURLs(saved)
+
+	ctx.JSON(http.StatusOK, saved)
+}
+
+// GetApplications lists the applications to a listing, newest first
+func (c *ListingController) GetApplications(ctx *gin.Context) {
+	listing, ok := c.loadListing(ctx)
+	if !ok {
+		return
+	}
+
+	applications, err := c.repository.GetApplications(ctx, listing.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, paginate(ctx, applications))
+}
+
+// UpdateApplication records the follow-up of an application
+func (c *ListingController) UpdateApplication(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var req UpdateApplicationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if _, known := model.ApplicationStatusTranslations[req.Status]; !known {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidApplicationStatus"})
+		return
+	}
+
+	application, err := c.repository.GetApplicationByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if application == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ApplicationNotFound"})
+		return
+	}
+
+	application.Status = req.Status
+	application.ManagerNotes = strings.TrimSpace(req.ManagerNotes)
+
+	updated, err := c.repository.UpdateApplication(ctx, *application)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveApplication"})
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// GetPublicListings lists the available properties
+// @Summary List the available properties
+// @Description Public catalog of the listings, cheapest first. On the domain of an organization only its properties are listed.
+// @Tags listings
+// @Produce json
+// @Param city query string false "City, case insensitive"
+// @Param min_price query number false "Minimum monthly rent"
+// @Param max_price query number false "Maximum monthly rent"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} model.PublicListing
+// @Router /public/listings [get]
+func (c *ListingController) GetPublicListings(ctx *gin.Context) {
+	filter := storage.ListingFilter{City: strings.TrimSpace(ctx.Query("city"))}
+	for param, target := range map[string]*float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if value := ctx.Query(param); value != "" {
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "ParameterMustBePositive", "params": gin.H{"Param": param}})
+				return
+			}
+			*target = price
+		}
+	}
+
+	listings, err := c.repository.GetAvailable(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadListings"})
+		return
+	}
+
+	org := middleware.GetOrganization(ctx)
+	var page []*model.PropertyListing
+	for i := range listings {
+		if org == nil || c.belongsTo(ctx, listings[i], org) {
+			page = append(page, &listings[i])
+		}
+	}
+	page = paginate(ctx, page)
+	signListingPhotoURLs(page...)
+
+	public := make([]model.PublicListing, len(page))
+	for i, listing := range page {
+		public[i] = listing.Public()
+	}
+	ctx.JSON(http.StatusOK, public)
+}
+
+// GetPublicListing retrieves an available listing
+func (c *ListingController) GetPublicListing(ctx *gin.Context) {
+	listing, ok := c.loadPublicListing(ctx)
+	if !ok {
+		return
+	}
+
+	signListingPhotoURLs(listing)
+	ctx.JSON(http.StatusOK, listing.Public())
+}
+
+// Apply records the application of a prospect to an available listing and notifies the
+// managers of the property
+// @Summary Apply to rent an available property
+// @Tags listings
+// @Accept json
+// @Produce json
+// @Param id path string true "Listing ID"
+// @Param application body ListingApplicationRequest true "Prospect"
+// @Success 201 {object} map[string]interface{}
+// @Router /public/listings/{id}/applications [post]
+func (c *ListingController) Apply(ctx *gin.Context) {
+	listing, ok := c.loadPublicListing(ctx)
+	if !ok {
+		return
+	}
+
+	var req ListingApplicationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	application := model.ListingApplication{
+		ListingID:      listing.ID,
+		FullName:       strings.TrimSpace(req.FullName),
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		Phone:          strings.TrimSpace(req.Phone),
+		DocumentNumber: strings.TrimSpace(req.DocumentNumber),
+		Occupation:     strings.TrimSpace(req.Occupation),
+		MonthlyIncome:  req.MonthlyIncome,
+		Occupants:      req.Occupants,
+		Message:        strings.TrimSpace(req.Message),
+		Status:         model.ApplicationStatusNew,
+	}
+	if application.Occupants == 0 {
+		application.Occupants = 1
+	}
+	if req.DesiredMoveIn != "" {
+		desiredMoveIn, err := time.ParseInLocation("2006-01-02", req.DesiredMoveIn, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDesiredMoveIn"})
+			return
+		}
+		application.DesiredMoveIn = &desiredMoveIn
+	}
+
+	created, err := c.repository.CreateApplication(ctx, application)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveApplication"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("application received", "application_id", created.ID, "listing_id", listing.ID)
+	go c.notifyManagers(*listing, *created)
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Application received, the manager of the property will contact you",
+		"id":      created.ID,
+	})
+}
+
+// notifyManagers emails the managers of the listed property about a new application
+func (c *ListingController) notifyManagers(listing model.PropertyListing, application model.ListingApplication) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	managerIDs, err := c.propertyRepo.GetManagerIDsForProperty(ctx, listing.PropertyID)
+	if err != nil {
+		slog.Error("error loading managers of property", "property_id", listing.PropertyID, "error", err)
+		return
+	}
+
+	for _, managerID := range managerIDs {
+		user, err := c.userRepo.GetByPersonID(ctx, managerID)
+		if err != nil || user == nil || user.Email == "" {
+			continue
+		}
+		name := user.Email
+		if person, err := c.personRepo.GetByID(ctx, managerID); err == nil && person != nil {
+			name = person.FullName
+		}
+		if err := service.SendListingApplicationEmail(user.Email, name, listing.Title, application); err != nil {
+			slog.Error("error notifying application to manager", "application_id", application.ID, "manager_id", managerID, "error", err)
+		}
+	}
+}
+
+// belongsTo reports whether the listed property is managed by the organization
+func (c *ListingController) belongsTo(ctx *gin.Context, listing model.PropertyListing, org *model.Organization) bool {
+	owner := c.orgService.ForProperty(ctx, listing.PropertyID)
+	return owner != nil && owner.ID == org.ID
+}
+
+// loadListing loads the listing of the :id path parameter, writing the error response when it
+// cannot be found
+func (c *ListingController) loadListing(ctx *gin.Context) (*model.PropertyListing, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	listing, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if listing == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ListingNotFound"})
+		return nil, false
+	}
+	return listing, true
+}
+
+// loadPublicListing loads the listing of the :id path parameter when it is available, and on
+// the domain of an organization when it belongs to it
+func (c *ListingController) loadPublicListing(ctx *gin.Context) (*model.PropertyListing, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	listing, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadListing"})
+		return nil, false
+	}
+	if listing == nil || listing.Status != model.ListingStatusAvailable {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ListingNotFoundOrUnavailable"})
+		return nil, false
+	}
+	if org := middleware.GetOrganization(ctx); org != nil && !c.belongsTo(ctx, *listing, org) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ListingNotFoundOrUnavailable"})
+		return nil, false
+	}
+	return listing, true
+}
+
+// signListingPhotoURLs replaces the URLs of the photos of the listings with signed URLs
+func signListingPhotoURLs(listings ...*model.PropertyListing) {
+	var photos []*model.InventoryPhoto
+	for _, listing := range listings {
+		for p := range listing.Photos {
+			photos = append(photos, &listing.Photos[p])
+		}
+	}
+	signPhotoURLs(photos)
+}
@@ -1,34 +1,54 @@
 package controller
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
-	"log"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // MaintenanceRequestController handles HTTP requests for maintenance requests
 type MaintenanceRequestController struct {
-	repository         *storage.MaintenanceRequestRepository
-	propertyRepository *storage.PropertyRepository
-	rentalRepository   *storage.RentalRepository
+	repository              storage.MaintenanceRequestStore
+	propertyRepository      storage.PropertyStore
+	rentalRepository        storage.RentalStore
+	commentRepository       storage.MaintenanceCommentStore
+	statusHistoryRepository storage.MaintenanceStatusHistoryStore
+	personRepository        storage.PersonStore
+	userRepository          storage.UserStore
+	providerRepository      storage.ServiceProviderStore
 }
 
 // NewMaintenanceRequestController creates a new maintenance request controller
 func NewMaintenanceRequestController(
-	repository *storage.MaintenanceRequestRepository,
-	propertyRepo *storage.PropertyRepository,
-	rentalRepo *storage.RentalRepository,
+	repository storage.MaintenanceRequestStore,
+	propertyRepo storage.PropertyStore,
+	rentalRepo storage.RentalStore,
+	commentRepo storage.MaintenanceCommentStore,
+	statusHistoryRepo storage.MaintenanceStatusHistoryStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+	providerRepo storage.ServiceProviderStore,
 ) *MaintenanceRequestController {
 	return &MaintenanceRequestController{
-		repository:         repository,
-		propertyRepository: propertyRepo,
-		rentalRepository:   rentalRepo,
+		repository:              repository,
+		propertyRepository:      propertyRepo,
+		rentalRepository:        rentalRepo,
+		commentRepository:       commentRepo,
+		statusHistoryRepository: statusHistoryRepo,
+		personRepository:        personRepo,
+		userRepository:          userRepo,
+		providerRepository:      providerRepo,
 	}
 }
 
@@ -43,118 +63,138 @@ func (c *MaintenanceRequestController) RegisterRoutes(router *gin.RouterGroup) {
 		maintenance.GET("/renter/:renterId", c.GetByRenterID)
 		maintenance.GET("/status/:status", c.GetByStatus)
 		maintenance.POST("", c.Create)
+		maintenance.GET("/:id/comments", c.GetComments)
+		maintenance.POST("/:id/comments", c.AddComment)
+		maintenance.GET("/:id/history", c.GetStatusHistory)
 		// The following routes are registered in admin section of http_controller.go
 		// maintenance.PUT("/:id", c.Update)
 		// maintenance.DELETE("/:id", c.Delete)
+		// maintenance.PUT("/:id/assign", c.AssignProvider)
+		// maintenance.DELETE("/:id/assign", c.UnassignProvider)
 	}
 }
 
-// GetAll retrieves all maintenance requests
+// GetAll retrieves the maintenance requests with the limit, offset, sort and filter[column] query parameters
 func (c *MaintenanceRequestController) GetAll(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view all maintenance requests"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForAllMaintenanceRequests"})
 		return
 	}
 
-	requests, err := c.repository.GetAll()
+	opts, err := listOptionsFromQuery(ctx, storage.MaintenanceRequestListColumns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requests, total, err := c.repository.List(opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, requests)
+	respondPage(ctx, requests, total)
 }
 
 // GetByID retrieves a maintenance request by ID
 func (c *MaintenanceRequestController) GetByID(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	requestID := ctx.Param("id")
 	request, err := c.repository.GetByID(requestID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Maintenance request not found: " + err.Error()})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
 		return
 	}
 
-	if authUser.Role == "admin" {
-		ctx.JSON(http.StatusOK, request)
+	if !c.authorizeRequestAccess(ctx, authUser, request) {
 		return
 	}
 
+	ctx.JSON(http.StatusOK, request)
+}
+
+// authorizeRequestAccess checks whether the user may see a maintenance request.
+// Admins see everything, managers see requests on their properties and
+// residents see their own requests or those on properties they rent.
+// On denial the error response is written and false is returned.
+func (c *MaintenanceRequestController) authorizeRequestAccess(ctx *gin.Context, authUser *model.User, request *storage.MaintenanceRequest) bool {
+	if authUser.Role == "admin" {
+		return true
+	}
+
 	if authUser.Role == "manager" {
 		managedProperties, err := c.propertyRepository.GetPropertiesForManager(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify manager properties"})
-			return
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyManagerProperties"})
+			return false
 		}
 		for _, p := range managedProperties {
 			if p.ID.String() == request.PropertyID {
-				ctx.JSON(http.StatusOK, request)
-				return
+				return true
 			}
 		}
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Manager not authorized for this request"})
-		return
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "ManagerNotAuthorizedForRequest"})
+		return false
 	}
 
 	if authUser.Role == "resident" || authUser.Role == "user" {
 		if request.RenterID == authUser.PersonID.String() {
-			ctx.JSON(http.StatusOK, request)
-			return
+			return true
 		}
 		rentals, err := c.rentalRepository.GetByRenterID(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify resident rentals"})
-			return
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyResidentRentals"})
+			return false
 		}
 		for _, r := range rentals {
 			if r.PropertyID.String() == request.PropertyID {
-				ctx.JSON(http.StatusOK, request)
-				return
+				return true
 			}
 		}
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Resident/User not authorized for this request"})
-		return
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "ResidentNotAuthorizedForRequest"})
+		return false
 	}
 
-	ctx.JSON(http.StatusForbidden, gin.H{"error": "User role not authorized"})
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "UserRoleNotAuthorized"})
+	return false
 }
 
 // GetByPropertyIDs retrieves maintenance requests for a list of property IDs
 func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	var requestedPropertyIDs []string
 	if err := ctx.ShouldBindJSON(&requestedPropertyIDs); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list of property IDs: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDList", "detail": err.Error()})
 		return
 	}
 
@@ -169,7 +209,7 @@ func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 	} else if authUser.Role == "manager" {
 		managedProperties, err := c.propertyRepository.GetPropertiesForManager(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify manager properties"})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyManagerProperties"})
 			return
 		}
 		managedPropertyIDMap := make(map[string]bool)
@@ -183,14 +223,14 @@ func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 		}
 	} else if authUser.Role == "resident" || authUser.Role == "user" {
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "User PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "UserPersonIDMissing"})
 			return
 		}
 		userAssociatedPropertyIDs := make(map[string]bool)
 
 		rentals, err := c.rentalRepository.GetByRenterID(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify user rentals"})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyUserRentals"})
 			return
 		}
 		for _, r := range rentals {
@@ -199,7 +239,7 @@ func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 
 		residentProperties, err := c.propertyRepository.GetByResident(ctx, authUser.PersonID)
 		if err != nil {
-			log.Printf("Error fetching direct resident properties for user %s: %v", authUser.PersonID, err)
+			logging.FromContext(ctx).Error("error fetching direct resident properties for user", "person_id", authUser.PersonID, "error", err)
 		}
 		for _, p := range residentProperties {
 			userAssociatedPropertyIDs[p.ID.String()] = true
@@ -211,7 +251,7 @@ func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 			}
 		}
 	} else {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized for this operation"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForOperation"})
 		return
 	}
 
@@ -222,7 +262,7 @@ func (c *MaintenanceRequestController) GetByPropertyIDs(ctx *gin.Context) {
 
 	requests, err := c.repository.GetByPropertyIDs(authorizedPropertyIDs)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch maintenance requests: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchMaintenanceRequests", "detail": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, requests)
@@ -268,46 +308,46 @@ func (c *MaintenanceRequestController) GetByStatus(ctx *gin.Context) {
 func (c *MaintenanceRequestController) Create(ctx *gin.Context) {
 	var modelRequest model.MaintenanceRequest
 	if err := ctx.ShouldBindJSON(&modelRequest); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
 		return
 	}
 
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid in context"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalidInContext"})
 		return
 	}
 
 	if authUser.Role == "resident" {
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "User creating request does not have an associated PersonID"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "RequesterHasNoPersonID"})
 			return
 		}
 		modelRequest.RenterID = authUser.PersonID
 
 		if modelRequest.PropertyID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "PropertyID is required for resident maintenance requests"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ResidentRequestPropertyIDRequired"})
 			return
 		}
 
 	} else if authUser.Role == "manager" {
 		if modelRequest.PropertyID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "PropertyID is required for manager maintenance requests"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ManagerRequestPropertyIDRequired"})
 			return
 		}
 	}
 
 	if modelRequest.PropertyID == uuid.Nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PropertyID is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PropertyIDRequired"})
 		return
 	}
 	if modelRequest.Description == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Description is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DescriptionRequired"})
 		return
 	}
 
@@ -329,15 +369,21 @@ func (c *MaintenanceRequestController) Create(ctx *gin.Context) {
 
 	createdRequest, err := c.repository.Create(&storageRequest)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create maintenance request: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateMaintenanceRequest", "detail": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, createdRequest)
 }
 
-// Update updates an existing maintenance request
+// Update updates an existing maintenance request.
+// Status changes are recorded in the status history and the renter is notified by email.
 func (c *MaintenanceRequestController) Update(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
 	id := ctx.Param("id")
 
 	var request storage.MaintenanceRequest
@@ -346,15 +392,241 @@ func (c *MaintenanceRequestController) Update(ctx *gin.Context) {
 		return
 	}
 
+	existing, err := c.repository.GetByID(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
 	updatedRequest, err := c.repository.Update(id, &request)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if updatedRequest.Status != existing.Status {
+		change := storage.MaintenanceStatusChange{
+			RequestID:  id,
+			FromStatus: existing.Status,
+			ToStatus:   updatedRequest.Status,
+			ChangedBy:  authUser.PersonID.String(),
+			ChangedAt:  model.FlexibleTime(time.Now()),
+		}
+		if _, err := c.statusHistoryRepository.Create(&change); err != nil {
+			logging.FromContext(ctx).Warn("could not record status change for maintenance request", "id", id, "error", err)
+		}
+
+		go c.notifyRenterOfStatusChange(*updatedRequest, existing.Status)
+		service.GetNotifications().MaintenanceUpdated(*updatedRequest, existing.Status)
+	}
+
 	ctx.JSON(http.StatusOK, updatedRequest)
 }
 
+// notifyRenterOfStatusChange emails the renter who opened the request about its new status
+func (c *MaintenanceRequestController) notifyRenterOfStatusChange(request storage.MaintenanceRequest, previousStatus string) {
+	ctx := context.Background()
+
+	renterID, err := uuid.Parse(request.RenterID)
+	if err != nil || renterID == uuid.Nil {
+		slog.Info("maintenance request has no renter, skipping status notification", "maintenance_request_id", request.ID)
+		return
+	}
+
+	renterUser, err := c.userRepository.GetByPersonID(ctx, renterID)
+	if err != nil || renterUser == nil || renterUser.Email == "" {
+		slog.Warn("no email found for renter of maintenance request", "renter_id", renterID, "maintenance_request_id", request.ID)
+		return
+	}
+
+	renterName := renterUser.Email
+	if renter, err := c.personRepository.GetByID(ctx, renterID); err == nil && renter != nil && renter.FullName != "" {
+		renterName = renter.FullName
+	}
+
+	propertyAddress := ""
+	if propertyID, err := uuid.Parse(request.PropertyID); err == nil {
+		if property, err := c.propertyRepository.GetByID(ctx, propertyID); err == nil && property != nil {
+			propertyAddress = property.Address
+		}
+	}
+
+	if err := service.SendMaintenanceStatusEmail(renterUser.Email, renterName, propertyAddress, request.Description, previousStatus, request.Status); err != nil {
+		slog.Error("failed to send maintenance status email", "maintenance_request_id", request.ID, "error", err)
+	}
+}
+
+// GetComments retrieves the comment thread of a maintenance request
+func (c *MaintenanceRequestController) GetComments(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	request, err := c.repository.GetByID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
+	if !c.authorizeRequestAccess(ctx, authUser, request) {
+		return
+	}
+
+	comments, err := c.commentRepository.GetByRequestID(request.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comments)
+}
+
+// AddComment adds a comment to a maintenance request
+func (c *MaintenanceRequestController) AddComment(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Body string `json:"body" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
+		return
+	}
+
+	request, err := c.repository.GetByID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
+	if !c.authorizeRequestAccess(ctx, authUser, request) {
+		return
+	}
+
+	comment := storage.MaintenanceComment{
+		RequestID: request.ID,
+		AuthorID:  authUser.PersonID.String(),
+		Body:      input.Body,
+		CreatedAt: model.FlexibleTime(time.Now()),
+	}
+
+	createdComment, err := c.commentRepository.Create(&comment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateComment", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdComment)
+}
+
+// GetStatusHistory retrieves the status transitions of a maintenance request
+func (c *MaintenanceRequestController) GetStatusHistory(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	request, err := c.repository.GetByID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
+	if !c.authorizeRequestAccess(ctx, authUser, request) {
+		return
+	}
+
+	history, err := c.statusHistoryRepository.GetByRequestID(request.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// AssignProvider assigns a service provider to a maintenance request and emails
+// the provider the request details and property address
+func (c *MaintenanceRequestController) AssignProvider(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var input struct {
+		ProviderID string `json:"provider_id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRequestPayload", "detail": err.Error()})
+		return
+	}
+
+	providerID, err := uuid.Parse(input.ProviderID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidProviderIDFormat"})
+		return
+	}
+
+	provider, err := c.providerRepository.GetByID(ctx, providerID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if provider == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ServiceProviderNotFound"})
+		return
+	}
+
+	updatedRequest, err := c.repository.SetAssignedProvider(id, providerID.String())
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
+	go c.notifyProviderOfAssignment(*updatedRequest, *provider)
+
+	ctx.JSON(http.StatusOK, updatedRequest)
+}
+
+// UnassignProvider removes the service provider assigned to a maintenance request
+func (c *MaintenanceRequestController) UnassignProvider(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	updatedRequest, err := c.repository.SetAssignedProvider(id, "")
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "MaintenanceRequestNotFound", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedRequest)
+}
+
+// notifyProviderOfAssignment emails the provider the request details and the property address
+func (c *MaintenanceRequestController) notifyProviderOfAssignment(request storage.MaintenanceRequest, provider model.ServiceProvider) {
+	if provider.Email == "" {
+		slog.Info("service provider has no email, skipping assignment notification", "provider_id", provider.ID)
+		return
+	}
+
+	propertyAddress := ""
+	if propertyID, err := uuid.Parse(request.PropertyID); err == nil {
+		if property, err := c.propertyRepository.GetByID(context.Background(), propertyID); err == nil && property != nil {
+			propertyAddress = strings.TrimSpace(fmt.Sprintf("%s %s, %s", property.Address, property.AptNumber, property.City))
+		}
+	}
+
+	providerName := provider.ContactName
+	if providerName == "" {
+		providerName = provider.Name
+	}
+
+	requestDate := service.FormatDate(request.RequestDate.Time())
+	if err := service.SendMaintenanceAssignmentEmail(provider.Email, providerName, propertyAddress, request.Description, requestDate); err != nil {
+		slog.Error("failed to send assignment email to provider", "provider_id", provider.ID, "maintenance_request_id", request.ID, "error", err)
+	}
+}
+
 // Delete deletes a maintenance request
 func (c *MaintenanceRequestController) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ManagerDigestController handles the opt-in summary digest of managers
+type ManagerDigestController struct {
+	repository    storage.ManagerDigestStore
+	digestService *service.ManagerDigestService
+}
+
+// NewManagerDigestController creates a new ManagerDigestController
+func NewManagerDigestController(repository storage.ManagerDigestStore, digestService *service.ManagerDigestService) *ManagerDigestController {
+	return &ManagerDigestController{
+		repository:    repository,
+		digestService: digestService,
+	}
+}
+
+// DigestSubscriptionRequest defines the digest frequency chosen by a manager
+type DigestSubscriptionRequest struct {
+	Frequency string `json:"frequency"` // weekly, monthly or empty to unsubscribe
+}
+
+// RegisterRoutes registers the digest routes of the authenticated manager
+func (c *ManagerDigestController) RegisterRoutes(router *gin.RouterGroup) {
+	digest := router.Group("/digest-subscription")
+	{
+		digest.GET("/me", c.GetMine)
+		digest.PUT("/me", c.UpdateMine)
+		digest.GET("/me/preview", c.PreviewMine)
+	}
+}
+
+// RegisterAdminRoutes registers the route to run the digest job on demand
+func (c *ManagerDigestController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.POST("/digests/send", c.SendDue)
+}
+
+// GetMine returns the digest subscription of the authenticated manager
+func (c *ManagerDigestController) GetMine(ctx *gin.Context) {
+	personID, ok := currentManagerPersonID(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.repository.GetByPersonID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if subscription == nil {
+		subscription = &model.ManagerDigestSubscription{PersonID: personID}
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// UpdateMine subscribes the authenticated manager to the digest or cancels the subscription
+func (c *ManagerDigestController) UpdateMine(ctx *gin.Context) {
+	personID, ok := currentManagerPersonID(ctx)
+	if !ok {
+		return
+	}
+
+	var req DigestSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !model.IsValidDigestFrequency(req.Frequency) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDigestFrequencyOrEmpty"})
+		return
+	}
+
+	existing, err := c.repository.GetByPersonID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var subscription *model.ManagerDigestSubscription
+	if existing == nil {
+		subscription, err = c.repository.Create(ctx, model.ManagerDigestSubscription{
+			PersonID:  personID,
+			Frequency: req.Frequency,
+		})
+	} else {
+		subscription, err = c.repository.UpdateFrequency(ctx, personID, req.Frequency)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveDigestSubscription"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// PreviewMine returns the digest the authenticated manager would receive now, without sending it
+func (c *ManagerDigestController) PreviewMine(ctx *gin.Context) {
+	personID, ok := currentManagerPersonID(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.repository.GetByPersonID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	frequency := ctx.DefaultQuery("frequency", model.DigestFrequencyWeekly)
+	var lastSentAt *time.Time
+	var lastArrears map[string]int
+	if subscription != nil {
+		if subscription.Frequency != "" {
+			frequency = subscription.Frequency
+		}
+		lastSentAt = subscription.LastSentAt
+		lastArrears = subscription.LastArrears
+	}
+	if frequency == "" || !model.IsValidDigestFrequency(frequency) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDigestFrequency"})
+		return
+	}
+
+	now := time.Now()
+	digest, err := c.digestService.BuildDigest(ctx, personID, frequency, service.DigestPeriodStart(frequency, lastSentAt, now), now, lastArrears)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, digest)
+}
+
+// SendDue sends the digests due today, as the scheduled job does
+func (c *ManagerDigestController) SendDue(ctx *gin.Context) {
+	sent, err := c.digestService.SendDueDigests(ctx, time.Now(), nil)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"sent": sent})
+}
+
+// currentManagerPersonID returns the person of the authenticated manager or admin, writing the
+// error response otherwise
+func currentManagerPersonID(ctx *gin.Context) (uuid.UUID, bool) {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return uuid.Nil, false
+	}
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return uuid.Nil, false
+	}
+	if authUser.Role != "admin" && authUser.Role != "manager" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminOrManagerRequired"})
+		return uuid.Nil, false
+	}
+	return currentPersonID(ctx)
+}
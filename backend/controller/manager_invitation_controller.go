@@ -3,14 +3,13 @@ package controller
 import (
 	"encoding/base64"
 	"fmt"
-	"log"
 	"math/rand"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
@@ -65,16 +64,16 @@ func generateRandomNIT() string {
 // @Failure 500 {object} string "Internal server error"
 // @Router /admin/invitations/manager [post]
 func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
-	log.Printf("📨 Received manager invitation request on path: %s", ctx.FullPath())
+	logging.FromContext(ctx).Info("received manager invitation request on path", "full_path", ctx.FullPath())
 	var request InvitationRequest
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		log.Printf("❌ Error binding JSON: %v", err)
+		logging.FromContext(ctx).Error("error binding JSON", "error", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("📧 Processing invitation request for: %s <%s>", request.Name, request.Email)
+	logging.FromContext(ctx).Info("processing invitation request", "name", request.Name, "email", request.Email)
 
 	// Set default status if not provided
 	if request.Status == "" {
@@ -88,14 +87,14 @@ func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
 	// First check if a user with this email already exists
 	existingUser, err := userRepo.GetByEmail(ctx, request.Email)
 	if err != nil {
-		log.Printf("❌ Error checking for existing user: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for existing user"})
+		logging.FromContext(ctx).Error("error checking for existing user", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCheckExistingUser"})
 		return
 	}
 
 	if existingUser != nil {
-		log.Printf("⚠️ User with email %s already exists", request.Email)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "A user with this email already exists"})
+		logging.FromContext(ctx).Warn("user with email already exists", "email", request.Email)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UserEmailAlreadyExists"})
 		return
 	}
 
@@ -110,8 +109,8 @@ func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
 
 	_, err = personRepo.Create(ctx, person)
 	if err != nil {
-		log.Printf("❌ Error creating person record: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create person record"})
+		logging.FromContext(ctx).Error("error creating person record", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePersonRecord"})
 		return
 	}
 
@@ -131,8 +130,8 @@ func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
 
 	createdUser, err := userRepo.Create(ctx, user)
 	if err != nil {
-		log.Printf("❌ Error creating user record: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user account"})
+		logging.FromContext(ctx).Error("error creating user record", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateUserAccount"})
 		return
 	}
 
@@ -141,24 +140,13 @@ func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
 	if createdUser != nil {
 		userIDToUse = createdUser.ID // Use returned ID if available
 	} else {
-		log.Printf("⚠️ User was created but no user object was returned")
+		logging.FromContext(ctx).Warn("user was created but no user object was returned")
 	}
 
-	log.Printf("👤 Created new user (ID: %s) for %s <%s> with status '%s'",
-		userIDToUse, request.Name, request.Email, request.Status)
-
-	// Build login URL - prioritize environment variable over Origin header
-	frontendURL := os.Getenv("APP_BASE_URL")
-	if frontendURL == "" {
-		// Fallback to Origin header if environment variable not set
-		frontendURL = ctx.GetHeader("Origin")
-		if frontendURL == "" {
-			// Final fallback for development (but should be avoided in production)
-			frontendURL = "http://localhost:5173"
-			log.Printf("⚠️ Warning: Using hardcoded frontend URL. Set APP_BASE_URL environment variable.")
-		}
-	}
-	loginURL := fmt.Sprintf("%s/login", frontendURL)
+	logging.FromContext(ctx).Info("created new user with status", "user_id", userIDToUse, "name", request.Name, "email", request.Email, "status", request.Status)
+
+	// Build login URL from APP_BASE_URL, the Origin header could point anywhere
+	loginURL := fmt.Sprintf("%s/login", service.GetAppBaseURL())
 
 	// Send the invitation email
 	subject := "¡Has sido invitado como Administrador de Propiedades!"
@@ -167,7 +155,7 @@ func (c *ManagerInvitationController) SendInvitation(ctx *gin.Context) {
 
 	err = service.SendSimpleEmail(request.Email, subject, body)
 	if err != nil {
-		log.Printf("❌ Error sending invitation email to %s: %v", request.Email, err)
+		logging.FromContext(ctx).Error("error sending invitation email", "email", request.Email, "error", err)
 		// We still return success since the user was created, just log the error
 	}
 
@@ -2,13 +2,14 @@ package controller
 
 import (
 	"encoding/base64"
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
@@ -63,7 +64,7 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 
 	_, err := personRepo.Create(ctx, person)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating person: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePerson", "detail": err.Error()})
 		return
 	}
 
@@ -84,7 +85,7 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 
 	_, err = propertyRepo.Create(ctx, property)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating property: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateProperty", "detail": err.Error()})
 		return
 	}
 
@@ -103,7 +104,7 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 
 	_, err = bankAccountRepo.Create(ctx, bankAccount)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating bank account: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateBankAccount", "detail": err.Error()})
 		return
 	}
 
@@ -125,7 +126,7 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 
 	_, err = userRepo.Create(ctx, user)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating user: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateUser", "detail": err.Error()})
 		return
 	}
 
@@ -147,11 +148,15 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 		UnpaidMonths:  0,
 	}
 
-	_, err = rentalRepo.Create(ctx, rental)
+	createdRental, err := rentalRepo.Create(ctx, rental)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating rental: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateRental", "detail": err.Error()})
 		return
 	}
+	if createdRental != nil {
+		rental = *createdRental // The insert does not always return the row
+	}
+	service.GetRentalHistory().RentalCreated(rental)
 
 	// 6. Create Pricing
 	pricingID := uuid.New()
@@ -168,7 +173,7 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 
 	_, err = pricingRepo.Create(ctx, pricing)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating pricing: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePricing", "detail": err.Error()})
 		return
 	}
 
@@ -179,8 +184,8 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 	// Convert role string to uuid.UUID
 	roleID, err := uuid.Parse("a1f876db-4d06-448c-96c2-40a10cd54b46") // Example role ID
 	if err != nil {
-		log.Printf("Error parsing role ID: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error parsing role ID"})
+		logging.FromContext(ctx).Error("error parsing role ID", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToParseRoleID"})
 		return
 	}
 
@@ -193,11 +198,11 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 	personRoleRepo := c.repositoryFactory.GetPersonRoleRepository()
 	_, err = personRoleRepo.Create(ctx, personRole)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating person role: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePersonRole", "detail": err.Error()})
 		return
 	}
 
-	// 8. Return success response
+	// 8. Subscribe to the platform plan; paying the first invoice activates the account
 	response := model.ManagerRegistrationResponse{
 		Success:    true,
 		UserID:     userID,
@@ -206,6 +211,17 @@ func (c *ManagerRegistrationController) Register(ctx *gin.Context) {
 		Message:    "Manager registration successful. An administrator will review and approve your account.",
 	}
 
+	if subscriptions := service.GetSubscriptions(); subscriptions != nil {
+		subscription, err := subscriptions.Subscribe(ctx, userID)
+		if err != nil {
+			// The registration stands, an administrator can subscribe the manager later
+			logging.FromContext(ctx).Error("error subscribing manager", "email", registrationRequest.Email, "error", err)
+		} else if subscription.PaymentURL != "" {
+			response.PaymentURL = subscription.PaymentURL
+			response.Message = "Manager registration successful. Pay the first month of your plan to activate your account."
+		}
+	}
+
 	ctx.JSON(http.StatusCreated, response)
 }
 
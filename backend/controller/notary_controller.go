@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// NotaryController handles the notarial authentication (e-stamping) of signed contracts
+type NotaryController struct {
+	repository  storage.NotarizationStore
+	signingRepo storage.ContractSigningStore
+	eventRepo   storage.ContractSigningEventStore
+	personRepo  storage.PersonStore
+	notary      config.NotaryConfig
+}
+
+// NewNotaryController creates a new NotaryController
+func NewNotaryController(
+	repository storage.NotarizationStore,
+	signingRepo storage.ContractSigningStore,
+	eventRepo storage.ContractSigningEventStore,
+	personRepo storage.PersonStore,
+	notary config.NotaryConfig,
+) *NotaryController {
+	return &NotaryController{
+		repository:  repository,
+		signingRepo: signingRepo,
+		eventRepo:   eventRepo,
+		personRepo:  personRepo,
+		notary:      notary,
+	}
+}
+
+// RegisterPublicRoutes registers the webhook of the notary service, authenticated by its signature
+func (c *NotaryController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.POST("/public/notary/webhook", c.HandleWebhook)
+}
+
+// RegisterAdminRoutes registers the notarization routes on an admin-protected group
+func (c *NotaryController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.POST("/contract-signing/:id/notarize", c.Submit)
+	adminRouter.GET("/contract-signing/:id/dossier", c.GetDossier)
+	adminRouter.GET("/notarizations/:id/pdf", c.ServeStampedPDF)
+}
+
+// Submit sends the signed PDF of a signing request to the notary
+func (c *NotaryController) Submit(ctx *gin.Context) {
+	signingID := ctx.Param("id")
+	record, err := c.signingRepo.GetByID(ctx, signingID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+		return
+	}
+	if record.Status != string(model.StatusSigned) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "OnlySignedContractsNotarized"})
+		return
+	}
+
+	notarizations, err := c.repository.GetBySigningID(ctx, signingID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, existing := range notarizations {
+		if existing.Status != model.NotarizationStatusRejected {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "ContractAlreadySentToNotary", "notarization": notarizationResponse(existing)})
+			return
+		}
+	}
+
+	provider, err := service.GetNotaryProvider(c.notary)
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "NotaryNotConfigured", "detail": err.Error()})
+		return
+	}
+
+	signedPDFData, err := loadStoredPDF(record.SignedPDFPath)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading signed PDF of signing request", "signing_id", signingID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadSignedContract"})
+		return
+	}
+
+	signerName := record.RecipientEmail
+	if recipientID, err := uuid.Parse(record.RecipientID); err == nil {
+		if recipient, err := c.personRepo.GetByID(ctx, recipientID); err == nil && recipient != nil {
+			signerName = recipient.FullName
+		}
+	}
+
+	notarization := model.Notarization{
+		ID:          uuid.New(),
+		SigningID:   record.ID,
+		ContractID:  record.ContractID,
+		Provider:    provider.Name(),
+		Notary:      provider.Notary(),
+		Status:      model.NotarizationStatusPending,
+		RequestedAt: time.Now(),
+	}
+
+	result, err := provider.SubmitDocument(ctx, service.NotaryStampRequest{
+		NotarizationID: notarization.ID.String(),
+		ContractID:     record.ContractID,
+		DocumentName:   "Contrato de arrendamiento " + record.ContractID,
+		PDFData:        signedPDFData,
+		SignerName:     signerName,
+		SignerEmail:    record.RecipientEmail,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error submitting contract to the notary", "contract_id", record.ContractID, "error", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "FailedToSubmitToNotary", "detail": err.Error()})
+		return
+	}
+	notarization.ExternalID = result.ExternalID
+	notarization.Reference = result.Reference
+
+	created, err := c.repository.Create(ctx, notarization)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "NotarizationNotSaved"})
+		return
+	}
+
+	addSigningEvent(ctx, c.eventRepo, record.ID, storage.SigningEventNotarizationRequested, "", provider.Notary())
+	logging.FromContext(ctx).Info("contract sent for notarization", "contract_id", record.ContractID, "notary", provider.Notary(), "external_id", result.ExternalID)
+
+	ctx.JSON(http.StatusCreated, notarizationResponse(*created))
+}
+
+// HandleWebhook receives status callbacks from the notary. When a document is authenticated
+// the stamped PDF is downloaded and stored.
+func (c *NotaryController) HandleWebhook(ctx *gin.Context) {
+	provider, err := service.GetNotaryProvider(c.notary)
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "NotaryNotConfigured"})
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadWebhookBody"})
+		return
+	}
+
+	result, err := provider.ParseWebhook(ctx.Request.Header, body)
+	if err != nil {
+		logging.FromContext(ctx).Warn("rejected notary webhook", "error", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "InvalidWebhook"})
+		return
+	}
+
+	notarization, err := c.repository.GetByExternalID(ctx, provider.Name(), result.ExternalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetNotarization"})
+		return
+	}
+	if notarization == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NotarizationNotFound"})
+		return
+	}
+
+	// Notaries retry deliveries, so repeated events are acknowledged without changes
+	if notarization.IsFinal() || result.Status == model.NotarizationStatusPending {
+		ctx.JSON(http.StatusOK, gin.H{"id": notarization.ID, "status": notarization.Status})
+		return
+	}
+
+	now := time.Now()
+	notarization.Status = result.Status
+	notarization.Observations = result.Observations
+	notarization.CompletedAt = &now
+	if result.Reference != "" {
+		notarization.Reference = result.Reference
+	}
+
+	event := storage.SigningEventNotarizationRejected
+	if result.Status == model.NotarizationStatusStamped {
+		stampedPDFData, err := provider.DownloadStampedDocument(ctx, result.ExternalID)
+		if err != nil {
+			logging.FromContext(ctx).Error("error downloading stamped PDF from the notary", "error", err)
+			// A non-2xx answer makes the notary retry the delivery later
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": "FailedToDownloadStampedDocument"})
+			return
+		}
+
+		notarization.StampedPDFPath, err = storeNotarizedPDF(notarization, stampedPDFData)
+		if err != nil {
+			logging.FromContext(ctx).Error("error storing stamped PDF of notarization", "notarization_id", notarization.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToStoreStampedDocument"})
+			return
+		}
+		event = storage.SigningEventNotarized
+	}
+
+	updated, err := c.repository.UpdateResult(ctx, *notarization)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUpdateNotarization"})
+		return
+	}
+
+	addSigningEvent(ctx, c.eventRepo, notarization.SigningID, event, "", notarization.Reference)
+	logging.FromContext(ctx).Info("notarization of contract", "notarization_id", notarization.ID, "contract_id", notarization.ContractID, "status", notarization.Status)
+
+	ctx.JSON(http.StatusOK, gin.H{"id": updated.ID, "status": updated.Status})
+}
+
+// GetDossier lists the documents of a signing request: the original contract, the signed one
+// and the versions authenticated by a notary
+func (c *NotaryController) GetDossier(ctx *gin.Context) {
+	signingID := ctx.Param("id")
+	record, err := c.signingRepo.GetByID(ctx, signingID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSigningRequest"})
+		return
+	}
+	if record == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "SigningRequestNotFound"})
+		return
+	}
+
+	notarizations, err := c.repository.GetBySigningID(ctx, signingID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents := []gin.H{{
+		"type": "original",
+		"url":  "/api/public/contract-signing/pdf/" + record.ID,
+	}}
+	if record.Status == string(model.StatusSigned) {
+		documents = append(documents, gin.H{
+			"type":      "signed",
+			"url":       "/api/public/contract-signing/pdf/" + record.ID + "?signed=true",
+			"signed_at": record.SignedAt,
+		})
+	}
+
+	notarizationList := make([]gin.H, 0, len(notarizations))
+	for _, notarization := range notarizations {
+		notarizationList = append(notarizationList, notarizationResponse(notarization))
+		if notarization.Status == model.NotarizationStatusStamped {
+			documents = append(documents, gin.H{
+				"type":         "notarized",
+				"url":          "/api/admin/notarizations/" + notarization.ID.String() + "/pdf",
+				"notary":       notarization.Notary,
+				"reference":    notarization.Reference,
+				"completed_at": notarization.CompletedAt,
+			})
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"signing_id":    record.ID,
+		"contract_id":   record.ContractID,
+		"status":        record.Status,
+		"documents":     documents,
+		"notarizations": notarizationList,
+	})
+}
+
+// ServeStampedPDF serves the document authenticated by the notary
+func (c *NotaryController) ServeStampedPDF(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	notarization, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if notarization == nil || notarization.Status != model.NotarizationStatusStamped {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NotarizedDocumentNotFound"})
+		return
+	}
+
+	pdfData, err := loadStoredPDF(notarization.StampedPDFPath)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading stamped PDF of notarization", "notarization_id", notarization.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadNotarizedDocument"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename=contrato_autenticado.pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// notarizationResponse adds the Spanish status to a notarization
+func notarizationResponse(notarization model.Notarization) gin.H {
+	return gin.H{
+		"id":             notarization.ID,
+		"signing_id":     notarization.SigningID,
+		"contract_id":    notarization.ContractID,
+		"provider":       notarization.Provider,
+		"notary":         notarization.Notary,
+		"external_id":    notarization.ExternalID,
+		"status":         notarization.Status,
+		"status_spanish": model.NotarizationStatusTranslations[notarization.Status],
+		"reference":      notarization.Reference,
+		"observations":   notarization.Observations,
+		"requested_at":   notarization.RequestedAt,
+		"completed_at":   notarization.CompletedAt,
+	}
+}
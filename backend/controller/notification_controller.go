@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/service"
+)
+
+// NotificationController serves the in-app notification feed of the authenticated user
+type NotificationController struct {
+	notifications *service.NotificationService
+}
+
+// NewNotificationController creates a new NotificationController
+func NewNotificationController(notifications *service.NotificationService) *NotificationController {
+	return &NotificationController{notifications: notifications}
+}
+
+// RegisterRoutes registers the notification routes of the authenticated user
+func (c *NotificationController) RegisterRoutes(router *gin.RouterGroup) {
+	notifications := router.Group("/notifications")
+	{
+		notifications.GET("", c.GetMine)
+		notifications.GET("/unread-count", c.GetUnreadCount)
+		notifications.PUT("/read-all", c.MarkAllRead)
+		notifications.PUT("/:id/read", c.MarkRead)
+	}
+}
+
+// GetMine lists the notifications of the authenticated user
+// @Summary List my notifications
+// @Description Newest first, with the total in the X-Total-Count header. unread=true lists only the unread ones.
+// @Tags users
+// @Produce json
+// @Param unread query bool false "Only the unread notifications"
+// @Param limit query int false "Maximum notifications, 20 by default and 100 at most"
+// @Param offset query int false "Notifications to skip"
+// @Success 200 {array} model.Notification
+// @Router /notifications [get]
+func (c *NotificationController) GetMine(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "LimitOutOfRange100"})
+		return
+	}
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "OffsetMustBePositive"})
+		return
+	}
+
+	notifications, total, err := c.notifications.List(ctx, userID, ctx.Query("unread") == "true", limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	respondPage(ctx, notifications, total)
+}
+
+// GetUnreadCount returns how many notifications of the authenticated user are unread
+// @Summary Count my unread notifications
+// @Tags users
+// @Produce json
+// @Success 200 {object} object
+// @Router /notifications/unread-count [get]
+func (c *NotificationController) GetUnreadCount(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := c.notifications.UnreadCount(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkRead marks a notification of the authenticated user as read
+// @Summary Mark a notification as read
+// @Tags users
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 404 {object} string "No unread notification"
+// @Router /notifications/{id}/read [put]
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+	notificationID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	err = c.notifications.MarkRead(ctx, userID, notificationID)
+	if errors.Is(err, service.ErrNotificationNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UnreadNotificationNotFound"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// MarkAllRead marks every notification of the authenticated user as read
+// @Summary Mark all my notifications as read
+// @Tags users
+// @Produce json
+// @Success 200 {object} object
+// @Router /notifications/read-all [put]
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	marked, err := c.notifications.MarkAllRead(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// NotificationPreferenceController handles which notifications people receive and through
+// which channels
+type NotificationPreferenceController struct {
+	preferences *service.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceController creates a new NotificationPreferenceController
+func NewNotificationPreferenceController(preferences *service.NotificationPreferenceService) *NotificationPreferenceController {
+	return &NotificationPreferenceController{
+		preferences: preferences,
+	}
+}
+
+// NotificationPreferenceInput is the frequency of a notification type through a channel
+type NotificationPreferenceInput struct {
+	Channel   string `json:"channel" binding:"required"`   // email, sms
+	Type      string `json:"type" binding:"required"`      // rent_reminder, anniversary_reminder, renewal_reminder
+	Frequency string `json:"frequency" binding:"required"` // always, monthly, never
+}
+
+// NotificationPreferencesRequest updates the given notification preferences
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceInput `json:"preferences" binding:"required,dive"`
+}
+
+// UnsubscribeRequest selects the notification type to unsubscribe from, all of them when empty
+type UnsubscribeRequest struct {
+	Type string `json:"type"`
+}
+
+// RegisterRoutes registers the routes of the authenticated user preferences
+func (c *NotificationPreferenceController) RegisterRoutes(router *gin.RouterGroup) {
+	preferences := router.Group("/notification-preferences")
+	{
+		preferences.GET("/me", c.GetMine)
+		preferences.PUT("/me", c.UpdateMine)
+	}
+}
+
+// RegisterAdminRoutes registers the routes to manage the preferences of any person
+func (c *NotificationPreferenceController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	preferences := adminRouter.Group("/notification-preferences")
+	{
+		preferences.GET("/:personId", c.GetByPersonID)
+		preferences.PUT("/:personId", c.UpdateByPersonID)
+	}
+}
+
+// RegisterPublicRoutes registers the routes used from the links of the emails, authenticated
+// by the signed token of the link
+func (c *NotificationPreferenceController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	preferences := router.Group("/public/notification-preferences/:token")
+	{
+		preferences.GET("", c.GetByToken)
+		preferences.PUT("", c.UpdateByToken)
+		preferences.POST("/unsubscribe", c.UnsubscribeByToken)
+	}
+}
+
+// GetMine returns the notification preferences of the authenticated user
+// @Summary Get my notification preferences
+// @Description Returns the frequency of every notification type per channel for the current user
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /notification-preferences/me [get]
+func (c *NotificationPreferenceController) GetMine(ctx *gin.Context) {
+	personID, ok := currentPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.respond(ctx, personID)
+}
+
+// UpdateMine updates the notification preferences of the authenticated user
+// @Summary Update my notification preferences
+// @Description Saves the frequency (always, monthly, never) of notification types per channel (email, sms)
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} map[string]interface{}
+// @Router /notification-preferences/me [put]
+func (c *NotificationPreferenceController) UpdateMine(ctx *gin.Context) {
+	personID, ok := currentPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.save(ctx, personID)
+}
+
+// GetByPersonID returns the notification preferences of a person
+// @Summary Get notification preferences of a person
+// @Tags notifications
+// @Produce json
+// @Param personId path string true "Person ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/notification-preferences/{personId} [get]
+func (c *NotificationPreferenceController) GetByPersonID(ctx *gin.Context) {
+	personID, err := uuid.Parse(ctx.Param("personId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonIDFormat"})
+		return
+	}
+	c.respond(ctx, personID)
+}
+
+// UpdateByPersonID updates the notification preferences of a person
+// @Summary Update notification preferences of a person
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param personId path string true "Person ID"
+// @Param request body NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/notification-preferences/{personId} [put]
+func (c *NotificationPreferenceController) UpdateByPersonID(ctx *gin.Context) {
+	personID, err := uuid.Parse(ctx.Param("personId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonIDFormat"})
+		return
+	}
+	c.save(ctx, personID)
+}
+
+// GetByToken returns the notification preferences of the person of an email link
+// @Summary Get notification preferences from an email link
+// @Tags notifications
+// @Produce json
+// @Param token path string true "Token of the manage-preferences link"
+// @Success 200 {object} map[string]interface{}
+// @Router /public/notification-preferences/{token} [get]
+func (c *NotificationPreferenceController) GetByToken(ctx *gin.Context) {
+	personID, ok := c.tokenPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.respond(ctx, personID)
+}
+
+// UpdateByToken updates the notification preferences of the person of an email link
+// @Summary Update notification preferences from an email link
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param token path string true "Token of the manage-preferences link"
+// @Param request body NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} map[string]interface{}
+// @Router /public/notification-preferences/{token} [put]
+func (c *NotificationPreferenceController) UpdateByToken(ctx *gin.Context) {
+	personID, ok := c.tokenPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.save(ctx, personID)
+}
+
+// UnsubscribeByToken stops the emails of a type, or all notification emails, to the person of
+// an email link
+// @Summary Unsubscribe from notification emails
+// @Description Sets the email frequency of the given type, or of every type when empty, to never
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param token path string true "Token of the unsubscribe link"
+// @Param request body UnsubscribeRequest false "Notification type"
+// @Success 200 {object} map[string]interface{}
+// @Router /public/notification-preferences/{token}/unsubscribe [post]
+func (c *NotificationPreferenceController) UnsubscribeByToken(ctx *gin.Context) {
+	personID, ok := c.tokenPersonID(ctx)
+	if !ok {
+		return
+	}
+	var req UnsubscribeRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+			return
+		}
+	}
+
+	preferences, err := c.preferences.Unsubscribe(ctx, personID, req.Type)
+	if err != nil {
+		respondNotificationPreferenceError(ctx, err, "FailedToUnsubscribe")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":       "Unsubscribed",
+		"preferences":   preferences,
+		"sms_available": service.SMSEnabled(),
+	})
+}
+
+// save updates the preferences of a person from the request body
+func (c *NotificationPreferenceController) save(ctx *gin.Context, personID uuid.UUID) {
+	var req NotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(req.Preferences))
+	for _, input := range req.Preferences {
+		preferences = append(preferences, model.NotificationPreference{
+			Channel:   input.Channel,
+			Type:      input.Type,
+			Frequency: input.Frequency,
+		})
+	}
+
+	saved, err := c.preferences.Update(ctx, personID, preferences)
+	if err != nil {
+		respondNotificationPreferenceError(ctx, err, "FailedToSaveNotificationPreferences")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"preferences": saved, "sms_available": service.SMSEnabled()})
+}
+
+// respond writes the preferences of a person
+func (c *NotificationPreferenceController) respond(ctx *gin.Context, personID uuid.UUID) {
+	preferences, err := c.preferences.Preferences(ctx, personID)
+	if err != nil {
+		respondNotificationPreferenceError(ctx, err, "FailedToGetNotificationPreferences")
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"preferences": preferences, "sms_available": service.SMSEnabled()})
+}
+
+// tokenPersonID returns the person of the token of an email link, writing the error response when invalid
+func (c *NotificationPreferenceController) tokenPersonID(ctx *gin.Context) (uuid.UUID, bool) {
+	personID, err := c.preferences.ParseToken(ctx.Param("token"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "InvalidOrExpiredLink"})
+		return uuid.Nil, false
+	}
+	return personID, true
+}
+
+// respondNotificationPreferenceError answers with the status matching a notification preference error
+func respondNotificationPreferenceError(ctx *gin.Context, err error, messageID string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidNotificationPreference):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logging.FromContext(ctx).Error("request failed", "message_id", messageID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": messageID})
+	}
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// OrganizationController handles HTTP requests for organizations and their public domains
+type OrganizationController struct {
+	repository storage.OrganizationStore
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationController creates a new OrganizationController
+func NewOrganizationController(repository storage.OrganizationStore, orgService *service.OrganizationService) *OrganizationController {
+	return &OrganizationController{
+		repository: repository,
+		orgService: orgService,
+	}
+}
+
+// RegisterRoutes registers the organization routes on an admin-protected group
+func (c *OrganizationController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	organizations := adminRouter.Group("/organizations")
+	{
+		organizations.GET("", c.GetAll)
+		organizations.GET("/:id", c.GetByID)
+		organizations.POST("", c.Create)
+		organizations.PUT("/:id", c.Update)
+		organizations.DELETE("/:id", c.Delete)
+	}
+}
+
+// RegisterPublicRoutes registers the route used by the frontend to brand itself for the current domain
+func (c *OrganizationController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/public/organization", c.GetCurrent)
+}
+
+// GetCurrent returns the organization resolved from the request host
+func (c *OrganizationController) GetCurrent(ctx *gin.Context) {
+	org := middleware.GetOrganization(ctx)
+	if org == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NoOrganizationForDomain"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"id":       org.ID,
+		"name":     org.Name,
+		"base_url": service.OrganizationBaseURL(org),
+	})
+}
+
+// GetAll retrieves all organizations
+func (c *OrganizationController) GetAll(ctx *gin.Context) {
+	organizations, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if organizations == nil {
+		organizations = []model.Organization{}
+	}
+
+	ctx.JSON(http.StatusOK, organizations)
+}
+
+// GetByID retrieves an organization by ID
+func (c *OrganizationController) GetByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	org, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if org == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "OrganizationNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, org)
+}
+
+// Create adds a new organization
+func (c *OrganizationController) Create(ctx *gin.Context) {
+	var org model.Organization
+	if err := ctx.ShouldBindJSON(&org); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if messageID := validateOrganization(&org); messageID != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": messageID})
+		return
+	}
+
+	org.ID = uuid.New()
+
+	createdOrg, err := c.repository.Create(ctx, org)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating organization", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateOrganization"})
+		return
+	}
+	c.orgService.Invalidate()
+
+	ctx.JSON(http.StatusCreated, createdOrg)
+}
+
+// Update updates an existing organization
+func (c *OrganizationController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var org model.Organization
+	if err := ctx.ShouldBindJSON(&org); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if messageID := validateOrganization(&org); messageID != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": messageID})
+		return
+	}
+
+	existingOrg, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingOrg == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "OrganizationNotFound"})
+		return
+	}
+
+	org.ID = id
+
+	updatedOrg, err := c.repository.Update(ctx, org)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.orgService.Invalidate()
+
+	ctx.JSON(http.StatusOK, updatedOrg)
+}
+
+// Delete removes an organization
+func (c *OrganizationController) Delete(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	existingOrg, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingOrg == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "OrganizationNotFound"})
+		return
+	}
+
+	if err := c.repository.Delete(ctx, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.orgService.Invalidate()
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// validateOrganization normalizes the domain fields and returns the message ID of the error
+// when invalid
+func validateOrganization(org *model.Organization) string {
+	org.Name = strings.TrimSpace(org.Name)
+	org.BaseURL = strings.TrimSuffix(strings.TrimSpace(org.BaseURL), "/")
+	org.CustomDomain = strings.ToLower(strings.TrimSpace(org.CustomDomain))
+	org.Timezone = strings.TrimSpace(org.Timezone)
+	org.Locale = strings.TrimSpace(org.Locale)
+
+	if org.Name == "" {
+		return "NameRequired"
+	}
+	if org.BaseURL != "" && !strings.HasPrefix(org.BaseURL, "https://") && !strings.HasPrefix(org.BaseURL, "http://") {
+		return "InvalidBaseURL"
+	}
+	if strings.Contains(org.CustomDomain, "/") {
+		return "InvalidCustomDomain"
+	}
+	if org.Timezone != "" {
+		if _, err := time.LoadLocation(org.Timezone); err != nil {
+			return "InvalidTimezone"
+		}
+	}
+	if org.Locale != "" && !service.IsSupportedLocale(org.Locale) {
+		return "InvalidOrganizationLocale"
+	}
+	return service.ValidateBranding(org.Branding)
+}
+
+// organizationName returns the name of an organization, or "" when there is none
+func organizationName(org *model.Organization) string {
+	if org == nil {
+		return ""
+	}
+	return org.Name
+}
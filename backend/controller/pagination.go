@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/storage"
+)
+
+// totalCountHeader reports the size of a paginated list before limit and offset are applied
+const totalCountHeader = "X-Total-Count"
+
+// paginate applies the optional limit and offset query parameters to a list response and sets
+// the X-Total-Count header. Without a valid limit the whole list is returned, as before
+// pagination was supported, so existing clients are not affected.
+func paginate[T any](ctx *gin.Context, items []T) []T {
+	ctx.Header(totalCountHeader, strconv.Itoa(len(items)))
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		return items
+	}
+	offset, err := strconv.Atoi(ctx.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// listOptionsFromQuery reads the standard list query parameters: limit, offset, sort (a column,
+// prefixed with - for descending order) and filter[column]=value. columns are the columns of the
+// list that can be sorted and filtered.
+func listOptionsFromQuery(ctx *gin.Context, columns []string) (storage.ListOptions, error) {
+	var opts storage.ListOptions
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return opts, fmt.Errorf("limit must be a positive number")
+		}
+		opts.Limit = limit
+	}
+	if value := ctx.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return opts, fmt.Errorf("offset must be a positive number")
+		}
+		opts.Offset = offset
+	}
+
+	opts.Sort = strings.TrimSpace(ctx.Query("sort"))
+	if strings.HasPrefix(opts.Sort, "-") {
+		opts.Sort = strings.TrimPrefix(opts.Sort, "-")
+		opts.Descending = true
+	}
+	opts.Filters = ctx.QueryMap("filter")
+
+	return opts, opts.Validate(columns)
+}
+
+// respondPage writes a page fetched with the list options of the request and the total count
+// of the list in the X-Total-Count header
+func respondPage[T any](ctx *gin.Context, items []T, total int) {
+	if items == nil {
+		items = []T{}
+	}
+	ctx.Header(totalCountHeader, strconv.Itoa(total))
+	ctx.JSON(http.StatusOK, items)
+}
+
+// paginateList sorts, filters and paginates a list already in memory, such as the lists scoped
+// by the role of the user
+func paginateList[T any](ctx *gin.Context, items []T, opts storage.ListOptions) []T {
+	return paginate(ctx, storage.ApplyListOptions(items, opts))
+}
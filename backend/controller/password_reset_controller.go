@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/service"
+)
+
+// PasswordResetController handles the recovery of forgotten passwords through emailed links
+type PasswordResetController struct {
+	resets *service.PasswordResetService
+}
+
+// NewPasswordResetController creates a new PasswordResetController
+func NewPasswordResetController(resets *service.PasswordResetService) *PasswordResetController {
+	return &PasswordResetController{resets: resets}
+}
+
+// RegisterPublicRoutes registers the forgot and reset password routes, limited per client IP
+// by the PasswordReset limit (RATE_LIMIT_PASSWORD_RESET)
+func (c *PasswordResetController) RegisterPublicRoutes(router *gin.RouterGroup, limits config.RateLimitConfig) {
+	limit := middleware.RateLimit("password_reset", middleware.RateLimitOr(limits.PasswordReset, "5/15m"), middleware.ClientIPKey)
+
+	auth := router.Group("/auth", limit)
+	{
+		auth.POST("/forgot-password", c.ForgotPassword)
+		auth.POST("/reset-password", c.ResetPassword)
+	}
+}
+
+// ForgotPassword emails a reset link to the user of an email
+// @Summary Request a password reset
+// @Description Emails a one-time reset link valid for PASSWORD_RESET_TTL. The response is the same whether the email has an account or not.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body object true "Email of the account"
+// @Success 202 {object} object
+// @Failure 400 {object} string "Bad request"
+// @Router /auth/forgot-password [post]
+func (c *PasswordResetController) ForgotPassword(ctx *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.resets.RequestReset(ctx.Request.Context(), request.Email, ctx.ClientIP(), time.Now()); err != nil {
+		logging.FromContext(ctx).Error("error requesting password reset", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "RequestNotProcessed"})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Si el correo está registrado, recibirás un enlace para restablecer tu contraseña",
+	})
+}
+
+// ResetPassword sets a new password with the token of a reset link
+// @Summary Reset a forgotten password
+// @Description Sets the new password of the user of a reset token. The token stops working once used.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body object true "Token and new password"
+// @Success 200 {object} object
+// @Failure 400 {object} string "Invalid or expired token"
+// @Router /auth/reset-password [post]
+func (c *PasswordResetController) ResetPassword(ctx *gin.Context) {
+	var request struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,min=8"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.resets.ResetPassword(ctx.Request.Context(), request.Token, request.NewPassword, ctx.ClientIP(), time.Now())
+	if errors.Is(err, service.ErrInvalidPasswordResetToken) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "LinkInvalid"})
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error resetting password", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "PasswordResetFailed"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Contraseña actualizada exitosamente",
+		"user": gin.H{
+			"id":    user.ID,
+			"email": user.Email,
+		},
+	})
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// PaymentCheckoutController handles the online payment of the rent through the payment gateway
+type PaymentCheckoutController struct {
+	checkouts *service.PaymentCheckoutService
+}
+
+// NewPaymentCheckoutController creates a new PaymentCheckoutController. checkouts is nil when no
+// payment gateway is configured.
+func NewPaymentCheckoutController(checkouts *service.PaymentCheckoutService) *PaymentCheckoutController {
+	return &PaymentCheckoutController{
+		checkouts: checkouts,
+	}
+}
+
+// RegisterRoutes registers the checkout routes of the authenticated tenant
+func (c *PaymentCheckoutController) RegisterRoutes(router *gin.RouterGroup) {
+	checkouts := router.Group("/payment-checkouts")
+	{
+		checkouts.POST("", c.Create)
+		checkouts.GET("/:reference", c.GetByReference)
+	}
+}
+
+// RegisterPublicRoutes registers the webhook of the payment gateway, authenticated by its signature
+func (c *PaymentCheckoutController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.POST("/public/payment-gateway/webhook", c.HandleWebhook)
+}
+
+// Create starts the online payment of the rent of the current month
+// @Summary Pay this month's rent online
+// @Description Creates a checkout on the payment gateway (Wompi: cards, PSE, Nequi) for the rent of the current month of the authenticated tenant and returns the page to pay it
+// @Tags payments
+// @Produce json
+// @Success 201 {object} service.RentCheckout
+// @Failure 404 {object} map[string]string "No active rental"
+// @Failure 409 {object} map[string]string "Already paid"
+// @Failure 503 {object} map[string]string "Online payments not configured"
+// @Router /payment-checkouts [post]
+func (c *PaymentCheckoutController) Create(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	checkout, err := c.checkouts.CreateCheckout(ctx, authUser.PersonID, authUser.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoActiveRental):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "NoActiveRentalToPay"})
+		case errors.Is(err, service.ErrRentAlreadyPaid):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "RentAlreadyPaid"})
+		case errors.Is(err, service.ErrNoRentAmount):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "RentalHasNoMonthlyAmount"})
+		case errors.Is(err, service.ErrCheckoutCurrency):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "CheckoutCurrencyUnsupported"})
+		default:
+			logging.FromContext(ctx).Error("error creating checkout", "person_id", authUser.PersonID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToStartPayment"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, checkout)
+}
+
+// GetByReference returns the state of a checkout, shown when the gateway redirects the tenant back
+// @Summary Get the state of an online payment
+// @Tags payments
+// @Produce json
+// @Param reference path string true "Checkout reference"
+// @Success 200 {object} model.PaymentCheckout
+// @Router /payment-checkouts/{reference} [get]
+func (c *PaymentCheckoutController) GetByReference(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	checkout, err := c.checkouts.GetByReference(ctx, ctx.Param("reference"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPayment"})
+		return
+	}
+	if checkout == nil || (authUser.Role != "admin" && checkout.PersonID != authUser.PersonID) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PaymentNotFound"})
+		return
+	}
+	ctx.JSON(http.StatusOK, checkout)
+}
+
+// HandleWebhook receives the transaction events of the payment gateway
+// @Summary Payment gateway webhook
+// @Description Receives the signed transaction events of the gateway. Approved transactions register the rent payment.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid signature"
+// @Router /public/payment-gateway/webhook [post]
+func (c *PaymentCheckoutController) HandleWebhook(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, 1<<20))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadBody"})
+		return
+	}
+
+	if err := c.checkouts.HandleWebhook(ctx, ctx.Request.Header, body); err != nil {
+		logging.FromContext(ctx).Error("error processing payment gateway webhook", "error", err)
+		// Answering with an error makes the gateway retry, except for events we cannot trust
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidGatewayEvent) {
+			status = http.StatusUnauthorized
+		}
+		ctx.JSON(status, gin.H{"error": "WebhookNotProcessed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "ok"})
+}
+
+// enabled writes the 503 response when no payment gateway is configured
+func (c *PaymentCheckoutController) enabled(ctx *gin.Context) bool {
+	if c.checkouts == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityOnlinePayments, "OnlinePaymentsNotConfigured")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// PaymentStatementController issues the yearly payment certifications of the tenants and
+// verifies them from the QR code printed on the PDF
+type PaymentStatementController struct {
+	repository   storage.PaymentStatementStore
+	rentalRepo   storage.RentalStore
+	propertyRepo storage.PropertyStore
+	personRepo   storage.PersonStore
+	paymentRepo  storage.RentPaymentStore
+	orgService   *service.OrganizationService
+}
+
+// NewPaymentStatementController creates a new PaymentStatementController
+func NewPaymentStatementController(
+	repository storage.PaymentStatementStore,
+	rentalRepo storage.RentalStore,
+	propertyRepo storage.PropertyStore,
+	personRepo storage.PersonStore,
+	paymentRepo storage.RentPaymentStore,
+	orgService *service.OrganizationService,
+) *PaymentStatementController {
+	return &PaymentStatementController{
+		repository:   repository,
+		rentalRepo:   rentalRepo,
+		propertyRepo: propertyRepo,
+		personRepo:   personRepo,
+		paymentRepo:  paymentRepo,
+		orgService:   orgService,
+	}
+}
+
+// RegisterRoutes registers the statement of the authenticated tenant
+func (c *PaymentStatementController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/my/payments/statement", c.GetMyStatement)
+}
+
+// RegisterPublicRoutes registers the verification target of the QR code
+func (c *PaymentStatementController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/public/payment-statements/verify/:id", c.Verify)
+}
+
+// GetMyStatement produces the certification of the payments the authenticated tenant made in a
+// year (?year=, the previous year by default)
+func (c *PaymentStatementController) GetMyStatement(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	// Years are counted in the timezone of the organization of the tenant
+	org := c.orgService.ForPerson(ctx, authUser.PersonID)
+	loc := service.OrganizationLocation(org)
+	now := time.Now().In(loc)
+	year := now.Year() - 1
+	if yearParam := ctx.Query("year"); yearParam != "" {
+		parsed, err := strconv.Atoi(yearParam)
+		if err != nil || parsed < 2000 || parsed > now.Year() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "YearOutOfRange", "params": gin.H{"MaxYear": now.Year()}})
+			return
+		}
+		year = parsed
+	}
+
+	tenant, err := c.personRepo.GetByID(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPaymentStatementTenant"})
+		return
+	}
+	if tenant == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "TenantNotFound"})
+		return
+	}
+
+	rentals, err := c.rentalRepo.GetByRenterID(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentals"})
+		return
+	}
+
+	lines, total, err := c.statementLines(ctx, rentals, year, loc)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPayments"})
+		return
+	}
+	if len(lines) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NoPaymentsInYear", "params": gin.H{"Year": year}})
+		return
+	}
+
+	statement := model.PaymentStatement{
+		ID:           uuid.New(),
+		PersonID:     authUser.PersonID,
+		Year:         year,
+		PaymentCount: len(lines),
+		TotalPaid:    total,
+		IssuedAt:     time.Now(),
+	}
+	if org != nil {
+		statement.OrganizationID = &org.ID
+	}
+
+	pdfData, err := service.GeneratePaymentStatementPDF(service.PaymentStatementPDF{
+		Statement:    statement,
+		Tenant:       tenant,
+		Organization: org,
+		Lines:        lines,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating payment statement", "person_id", authUser.PersonID, "year", year, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGeneratePaymentStatement"})
+		return
+	}
+
+	statement.DocumentHash = service.PaymentStatementHash(pdfData)
+	if _, err := c.repository.Create(ctx, statement); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRegisterPaymentStatement"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("payment statement issued payments", "statement_id", statement.ID, "full_name", tenant.FullName, "year", year, "lines_count", len(lines))
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=certificado_pagos_%d.pdf", year))
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// statementLines lists the payments of the rentals made in a year in loc, oldest first, with
+// their total
+func (c *PaymentStatementController) statementLines(ctx *gin.Context, rentals []model.Rental, year int, loc *time.Location) ([]service.PaymentStatementLine, float64, error) {
+	if len(rentals) == 0 {
+		return nil, 0, nil
+	}
+
+	rentalIDs := make([]string, 0, len(rentals))
+	addresses := make(map[string]string, len(rentals))
+	for _, rental := range rentals {
+		rentalIDs = append(rentalIDs, rental.ID.String())
+
+		address := ""
+		if property, err := c.propertyRepo.GetByID(ctx, rental.PropertyID); err == nil && property != nil {
+			address = property.Address
+			if property.AptNumber != "" {
+				address += " Apto " + property.AptNumber
+			}
+			if property.City != "" {
+				address += ", " + property.City
+			}
+		}
+		addresses[rental.ID.String()] = address
+	}
+
+	payments, err := c.paymentRepo.GetByRentalIDs(rentalIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var lines []service.PaymentStatementLine
+	total := 0.0
+	for _, payment := range payments {
+		paidAt := payment.PaymentDate.Time().In(loc)
+		if paidAt.Year() != year {
+			continue
+		}
+		reference := payment.ReceiptNumber
+		if reference == "" {
+			reference = service.PaymentReference(payment.ID)
+		}
+		lines = append(lines, service.PaymentStatementLine{
+			Date:       paidAt,
+			Reference:  reference,
+			Property:   addresses[payment.RentalID],
+			Amount:     payment.AmountPaid,
+			PaidOnTime: payment.PaidOnTime,
+		})
+		total += payment.AmountPaid
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Date.Before(lines[j].Date)
+	})
+	return lines, total, nil
+}
+
+// Verify confirms the authenticity of a payment certification. It is the target of the QR code
+// printed on the PDF, so it only exposes what the certification already shows.
+func (c *PaymentStatementController) Verify(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	statement, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPaymentStatement"})
+		return
+	}
+	if statement == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"authentic": false,
+			"error":     "PaymentStatementNotFound",
+		})
+		return
+	}
+
+	tenantName := ""
+	if tenant, err := c.personRepo.GetByID(ctx, statement.PersonID); err == nil && tenant != nil {
+		tenantName = tenant.FullName
+	}
+
+	var org *model.Organization
+	if statement.OrganizationID != nil {
+		org = c.orgService.ByID(ctx, *statement.OrganizationID)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"authentic":         true,
+		"message":           "Este certificado de pagos fue expedido por la plataforma y es auténtico. Compare el valor total y la huella del documento con el PDF recibido.",
+		"id":                statement.ID,
+		"tenant_name":       tenantName,
+		"year":              statement.Year,
+		"payment_count":     statement.PaymentCount,
+		"total_paid":        statement.TotalPaid,
+		"total_display":     service.FormatMoney(statement.TotalPaid),
+		"organization":      organizationName(org),
+		"document_hash":     statement.DocumentHash,
+		"issued_at":         statement.IssuedAt,
+		"issued_at_display": service.FormatDateTime(statement.IssuedAt),
+	})
+}
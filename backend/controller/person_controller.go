@@ -1,27 +1,28 @@
 package controller
 
 import (
-	"log"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // PersonController handles HTTP requests for person entities
 type PersonController struct {
-	repository      *storage.PersonRepository
-	propertyRepo    *storage.PropertyRepository
-	rentalRepo      *storage.RentalRepository
-	bankAccountRepo *storage.BankAccountRepository
-	userRepo        *storage.UserRepository
+	repository      storage.PersonStore
+	propertyRepo    storage.PropertyStore
+	rentalRepo      storage.RentalStore
+	bankAccountRepo storage.BankAccountStore
+	userRepo        storage.UserStore
 }
 
 // NewPersonController creates a new PersonController
-func NewPersonController(repository *storage.PersonRepository, propertyRepo *storage.PropertyRepository, rentalRepo *storage.RentalRepository, bankAccountRepo *storage.BankAccountRepository, userRepo *storage.UserRepository) *PersonController {
+func NewPersonController(repository storage.PersonStore, propertyRepo storage.PropertyStore, rentalRepo storage.RentalStore, bankAccountRepo storage.BankAccountStore, userRepo storage.UserStore) *PersonController {
 	return &PersonController{
 		repository:      repository,
 		propertyRepo:    propertyRepo,
@@ -37,6 +38,11 @@ func NewPersonController(repository *storage.PersonRepository, propertyRepo *sto
 // @Tags persons
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size, the whole list without it"
+// @Param offset query int false "Rows skipped"
+// @Param sort query string false "Column to sort by, prefixed with - for descending order"
+// @Param filter[column] query string false "Exact value of a column"
+// @Header 200 {int} X-Total-Count "Size of the list before limit and offset"
 // @Success 200 {array} model.Person
 // @Failure 401 {object} string "Unauthorized"
 // @Failure 403 {object} string "Forbidden"
@@ -44,29 +50,41 @@ func NewPersonController(repository *storage.PersonRepository, propertyRepo *sto
 func (c *PersonController) GetAll(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return
+	}
+
+	opts, err := listOptionsFromQuery(ctx, storage.PersonListColumns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	var persons []model.Person
-	var err error
 
 	if authUser.Role == "admin" {
-		persons, err = c.repository.GetAll(ctx)
+		// The whole table is paginated, sorted and filtered by the database
+		page, total, err := c.repository.List(ctx, opts)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePersons", "detail": err.Error()})
+			return
+		}
+		respondPage(ctx, page, total)
+		return
 	} else if authUser.Role == "manager" {
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Manager PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ManagerPersonIDMissing"})
 			return
 		}
 		// Fetch properties managed by this manager
 		managedProperties, propErr := c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
 		if propErr != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch managed properties: " + propErr.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedProperties", "detail": propErr.Error()})
 			return
 		}
 		if len(managedProperties) == 0 {
@@ -77,25 +95,22 @@ func (c *PersonController) GetAll(ctx *gin.Context) {
 			} else {
 				persons = []model.Person{}
 			}
-			ctx.JSON(http.StatusOK, persons)
+			ctx.JSON(http.StatusOK, paginateList(ctx, persons, opts))
 			return
 		}
 
 		personIDsToFetch := make(map[uuid.UUID]bool)
 		personIDsToFetch[authUser.PersonID] = true // Include the manager themselves
 
-		for _, prop := range managedProperties {
-			// Fetch rentals for each property
-			rentalsOnProp, rentalErr := c.rentalRepo.GetByPropertyID(ctx, prop.ID)
-			if rentalErr != nil {
-				// Log and continue, or return error. For now, log and attempt to proceed.
-				log.Printf("Error fetching rentals for property %s: %v", prop.ID.String(), rentalErr)
-				continue
-			}
-			for _, rental := range rentalsOnProp {
-				if rental.RenterID != uuid.Nil {
-					personIDsToFetch[rental.RenterID] = true
-				}
+		// Fetch the rentals of every managed property at once
+		rentalsOnProps, rentalErr := c.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(managedProperties))
+		if rentalErr != nil {
+			// Log and attempt to proceed with the manager only
+			logging.FromContext(ctx).Error("error fetching rentals for the properties of manager", "person_id", authUser.PersonID, "error", rentalErr)
+		}
+		for _, rental := range rentalsOnProps {
+			if rental.RenterID != uuid.Nil {
+				personIDsToFetch[rental.RenterID] = true
 			}
 		}
 
@@ -111,18 +126,18 @@ func (c *PersonController) GetAll(ctx *gin.Context) {
 		}
 	} else {
 		// For other roles (e.g. resident), GET /persons is forbidden as per original logic
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view all persons"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForAllPersons"})
 		return
 	}
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve persons: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePersons", "detail": err.Error()})
 		return
 	}
 	if persons == nil {
 		persons = []model.Person{}
 	}
-	ctx.JSON(http.StatusOK, persons)
+	ctx.JSON(http.StatusOK, paginateList(ctx, persons, opts))
 }
 
 // GetByID retrieves a person by ID
@@ -140,19 +155,19 @@ func (c *PersonController) GetAll(ctx *gin.Context) {
 func (c *PersonController) GetByID(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	personIDStr := ctx.Param("id")
 	personID, err := uuid.Parse(personIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -165,7 +180,7 @@ func (c *PersonController) GetByID(ctx *gin.Context) {
 	}
 
 	if !isAllowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this person"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedToViewPerson"})
 		return
 	}
 
@@ -176,7 +191,7 @@ func (c *PersonController) GetByID(ctx *gin.Context) {
 	}
 
 	if person == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PersonNotFound"})
 		return
 	}
 
@@ -195,7 +210,7 @@ func (c *PersonController) GetByID(ctx *gin.Context) {
 func (c *PersonController) GetByRole(ctx *gin.Context) {
 	role := ctx.Param("role")
 	if role == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Role is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RoleRequired"})
 		return
 	}
 
@@ -223,17 +238,17 @@ func (c *PersonController) GetByRole(ctx *gin.Context) {
 func (c *PersonController) Create(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to create a person"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedToCreatePerson"})
 		return
 	}
 
@@ -264,27 +279,30 @@ func (c *PersonController) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Person ID"
 // @Param person body model.Person true "Person object"
+// @Param If-Match header string false "Version of the person, instead of the version of the body"
 // @Success 200 {object} model.Person
 // @Failure 400 {object} string "Invalid input or ID format"
 // @Failure 401 {object} string "Unauthorized"
 // @Failure 403 {object} string "Forbidden"
 // @Failure 404 {object} string "Person not found"
+// @Failure 409 {object} object "The person changed since its version was read"
+// @Failure 428 {object} string "The version of the person was not sent"
 // @Router /persons/{id} [put]
 func (c *PersonController) Update(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -300,7 +318,7 @@ func (c *PersonController) Update(ctx *gin.Context) {
 	}
 
 	if !isAuthorized {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to update this person"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedToUpdatePerson"})
 		return
 	}
 
@@ -311,12 +329,24 @@ func (c *PersonController) Update(ctx *gin.Context) {
 	}
 
 	person.ID = id
+	if !requireVersion(ctx, &person.Version) {
+		return
+	}
 
 	updatedPerson, err := c.repository.Update(ctx, person)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, _ := c.repository.GetByID(ctx, id)
+		respondVersionConflict(ctx, current)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if updatedPerson == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PersonNotFound"})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, updatedPerson)
 }
@@ -337,93 +367,93 @@ func (c *PersonController) Update(ctx *gin.Context) {
 func (c *PersonController) Delete(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to delete a person"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedToDeletePerson"})
 		return
 	}
 
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
 	// Check if person exists
 	existingPerson, err := c.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("Error checking if person exists: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking person existence"})
+		logging.FromContext(ctx).Error("error checking if person exists", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCheckPersonExistence"})
 		return
 	}
 	if existingPerson == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PersonNotFound"})
 		return
 	}
 
 	// First, delete all associated users (cascade delete)
-	log.Printf("Deleting users for person ID: %s", id.String())
+	logging.FromContext(ctx).Info("deleting users for person", "id", id.String())
 	associatedUser, err := c.userRepo.GetByPersonID(ctx, id)
 	if err != nil {
-		log.Printf("Error retrieving user for person %s: %v", id.String(), err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving associated user"})
+		logging.FromContext(ctx).Error("error retrieving user for person", "id", id.String(), "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPersonUser"})
 		return
 	}
 
 	// Delete the associated user if exists
 	if associatedUser != nil {
-		log.Printf("Deleting user ID: %s for person ID: %s", associatedUser.ID.String(), id.String())
+		logging.FromContext(ctx).Info("deleting user for person", "associated_user_id", associatedUser.ID.String(), "id", id.String())
 		err = c.userRepo.Delete(ctx, associatedUser.ID)
 		if err != nil {
-			log.Printf("Error deleting user %s for person %s: %v", associatedUser.ID.String(), id.String(), err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting associated user"})
+			logging.FromContext(ctx).Error("error deleting user for person", "associated_user_id", associatedUser.ID.String(), "id", id.String(), "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToDeletePersonUser"})
 			return
 		}
-		log.Printf("Successfully deleted user %s for person %s", associatedUser.ID.String(), id.String())
+		logging.FromContext(ctx).Info("successfully deleted user for person", "associated_user_id", associatedUser.ID.String(), "id", id.String())
 	} else {
-		log.Printf("No associated user found for person %s", id.String())
+		logging.FromContext(ctx).Info("no associated user found for person", "id", id.String())
 	}
 
 	// Second, delete all associated bank accounts (cascade delete)
-	log.Printf("Deleting bank accounts for person ID: %s", id.String())
+	logging.FromContext(ctx).Info("deleting bank accounts for person", "id", id.String())
 	bankAccounts, err := c.bankAccountRepo.GetByPersonID(ctx, id)
 	if err != nil {
-		log.Printf("Error retrieving bank accounts for person %s: %v", id.String(), err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving associated bank accounts"})
+		logging.FromContext(ctx).Error("error retrieving bank accounts for person", "id", id.String(), "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPersonBankAccounts"})
 		return
 	}
 
 	// Delete each bank account
 	for _, account := range bankAccounts {
-		log.Printf("Deleting bank account ID: %s for person ID: %s", account.ID.String(), id.String())
+		logging.FromContext(ctx).Info("deleting bank account for person", "account_id", account.ID.String(), "id", id.String())
 		err = c.bankAccountRepo.Delete(ctx, account.ID)
 		if err != nil {
-			log.Printf("Error deleting bank account %s for person %s: %v", account.ID.String(), id.String(), err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting associated bank account"})
+			logging.FromContext(ctx).Error("error deleting bank account for person", "account_id", account.ID.String(), "id", id.String(), "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToDeletePersonBankAccount"})
 			return
 		}
 	}
 
-	log.Printf("Successfully deleted %d bank account(s) for person %s", len(bankAccounts), id.String())
+	logging.FromContext(ctx).Info("successfully deleted bank account(s) for person", "bank_accounts_count", len(bankAccounts), "id", id.String())
 
 	// Now delete the person
-	log.Printf("Deleting person with ID: %s", id.String())
+	logging.FromContext(ctx).Info("deleting person", "id", id.String())
 	err = c.repository.Delete(ctx, id)
 	if err != nil {
-		log.Printf("Error deleting person %s: %v", id.String(), err)
+		logging.FromContext(ctx).Error("error deleting person", "id", id.String(), "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("Successfully deleted person %s and %d associated bank account(s)", id.String(), len(bankAccounts))
+	logging.FromContext(ctx).Info("successfully deleted person associated bank account(s)", "id", id.String(), "bank_accounts_count", len(bankAccounts))
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -1,22 +1,25 @@
 package controller
 
 import (
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // PricingController handles HTTP requests for pricing entities
 type PricingController struct {
-	repository *storage.PricingRepository
+	repository storage.PricingStore
 }
 
 // NewPricingController creates a new PricingController
-func NewPricingController(repository *storage.PricingRepository) *PricingController {
+func NewPricingController(repository storage.PricingStore) *PricingController {
 	return &PricingController{
 		repository: repository,
 	}
@@ -32,40 +35,58 @@ func (c *PricingController) RegisterRoutes(adminRouter *gin.RouterGroup) {
 
 	adminRouter.POST("", c.HandleCreatePricing)                        // POST /api/pricing
 	adminRouter.GET("", c.HandleGetAllPricing)                         // GET /api/pricing
+	adminRouter.GET("/summary", c.HandleGetPricingSummary)             // GET /api/pricing/summary
 	adminRouter.GET("/:id", c.HandleGetPricingByID)                    // GET /api/pricing/:id
+	adminRouter.GET("/:id/breakdown", c.HandleGetPricingBreakdown)     // GET /api/pricing/:id/breakdown
 	adminRouter.GET("/rental/:rentalId", c.HandleGetPricingByRentalID) // GET /api/pricing/rental/:rentalId
 	adminRouter.PUT("/:id", c.HandleUpdatePricing)                     // PUT /api/pricing/:id
 	adminRouter.DELETE("/:id", c.HandleDeletePricing)                  // DELETE /api/pricing/:id
 
-	log.Println("INFO: Registered admin pricing routes under /api/pricing")
+	slog.Info("registered admin pricing routes under /api/pricing")
 }
 
 // HandleCreatePricing creates new pricing information
 func (c *PricingController) HandleCreatePricing(ctx *gin.Context) {
 	var pricing model.Pricing
 	if err := ctx.ShouldBindJSON(&pricing); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
 		return
 	}
 
 	// Validate required fields (e.g., RentalID, MonthlyRent, DueDay)
 	if pricing.RentalID == uuid.Nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RentalID is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RentalIDRequired"})
 		return
 	}
 	if pricing.MonthlyRent <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "MonthlyRent must be positive"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "MonthlyRentMustBePositive"})
 		return
 	}
 	if pricing.DueDay < 1 || pricing.DueDay > 31 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DueDay must be between 1 and 31"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DueDayOutOfRange"})
+		return
+	}
+	if !model.IsValidIncreasePolicy(pricing.IncreasePolicy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIncreasePolicy"})
+		return
+	}
+	if pricing.IncreasePercentage < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "NegativeIncreasePercentage"})
+		return
+	}
+	if err := model.ValidatePricingComponents(pricing.Components); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidCurrency(pricing.Currency) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCurrency"})
 		return
 	}
 
 	createdPricing, err := c.repository.Create(ctx, pricing)
 	if err != nil {
-		log.Printf("Error creating pricing: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pricing record"})
+		logging.FromContext(ctx).Error("error creating pricing", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePricingRecord"})
 		return
 	}
 	ctx.JSON(http.StatusCreated, createdPricing)
@@ -75,8 +96,8 @@ func (c *PricingController) HandleCreatePricing(ctx *gin.Context) {
 func (c *PricingController) HandleGetAllPricing(ctx *gin.Context) {
 	pricingList, err := c.repository.GetAll(ctx)
 	if err != nil {
-		log.Printf("Error getting all pricing: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pricing records"})
+		logging.FromContext(ctx).Error("error getting all pricing", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePricingRecords"})
 		return
 	}
 	ctx.JSON(http.StatusOK, pricingList)
@@ -87,36 +108,123 @@ func (c *PricingController) HandleGetPricingByID(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Pricing ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPricingIDFormat"})
 		return
 	}
 
 	pricing, err := c.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("Error getting pricing by ID %s: %v", idStr, err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pricing record"})
+		logging.FromContext(ctx).Error("error getting pricing", "id", idStr, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePricingRecord"})
 		return
 	}
 	if pricing == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Pricing record not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PricingRecordNotFound"})
 		return
 	}
 	ctx.JSON(http.StatusOK, pricing)
 }
 
+// HandleGetPricingBreakdown itemizes the monthly charges of a pricing with their IVA and who pays them
+func (c *PricingController) HandleGetPricingBreakdown(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPricingIDFormat"})
+		return
+	}
+
+	pricing, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("error getting pricing", "id", idStr, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePricingRecord"})
+		return
+	}
+	if pricing == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PricingRecordNotFound"})
+		return
+	}
+	ctx.JSON(http.StatusOK, service.BreakdownPricing(*pricing))
+}
+
+// PricingSummaryLine aggregates a type of charge over all the pricing records
+type PricingSummaryLine struct {
+	Type        string  `json:"type"`
+	Label       string  `json:"label"`
+	Rentals     int     `json:"rentals"`
+	TenantTotal float64 `json:"tenant_total"`
+	OwnerTotal  float64 `json:"owner_total"`
+	IVA         float64 `json:"iva"`
+}
+
+// HandleGetPricingSummary aggregates the monthly charges of all pricing records by type, so
+// reports do not count administración or parking as canon
+func (c *PricingController) HandleGetPricingSummary(ctx *gin.Context) {
+	pricingList, err := c.repository.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error getting all pricing", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrievePricingRecords"})
+		return
+	}
+
+	types := []string{model.PricingComponentCanon, model.PricingComponentAdministracion, model.PricingComponentParqueadero, model.PricingComponentOtro}
+	lines := make(map[string]*PricingSummaryLine, len(types))
+	for _, componentType := range types {
+		lines[componentType] = &PricingSummaryLine{Type: componentType, Label: model.PricingComponentLabels[componentType]}
+	}
+
+	var tenantTotal, ownerTotal, ivaTotal float64
+	for _, pricing := range pricingList {
+		breakdown := service.BreakdownPricing(pricing)
+		counted := make(map[string]bool)
+		for _, charge := range breakdown.Lines {
+			line, ok := lines[charge.Type]
+			if !ok {
+				continue
+			}
+			if !counted[charge.Type] {
+				line.Rentals++
+				counted[charge.Type] = true
+			}
+			if charge.Responsibility == model.ResponsibilityOwner {
+				line.OwnerTotal += charge.Total
+			} else {
+				line.TenantTotal += charge.Amount
+				line.IVA += charge.IVA
+			}
+		}
+		tenantTotal += breakdown.TenantTotal
+		ownerTotal += breakdown.OwnerTotal
+		ivaTotal += breakdown.TenantIVA
+	}
+
+	summary := make([]PricingSummaryLine, 0, len(types))
+	for _, componentType := range types {
+		summary = append(summary, *lines[componentType])
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"components":   summary,
+		"tenant_total": tenantTotal,
+		"owner_total":  ownerTotal,
+		"iva_total":    ivaTotal,
+		"rentals":      len(pricingList),
+	})
+}
+
 // HandleGetPricingByRentalID retrieves pricing information for a specific rental ID
 func (c *PricingController) HandleGetPricingByRentalID(ctx *gin.Context) {
 	rentalIDStr := ctx.Param("rentalId")
 	rentalID, err := uuid.Parse(rentalIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Rental ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
 		return
 	}
 
 	pricing, err := c.repository.GetByRentalID(ctx, rentalID) // Assumes one pricing per rental
 	if err != nil {
-		log.Printf("Error getting pricing by RentalID %s: %v", rentalIDStr, err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pricing for rental"})
+		logging.FromContext(ctx).Error("error getting pricing by RentalID", "rental_id", rentalIDStr, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveRentalPricing"})
 		return
 	}
 	if pricing == nil {
@@ -132,43 +240,66 @@ func (c *PricingController) HandleUpdatePricing(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Pricing ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPricingIDFormat"})
 		return
 	}
 
 	var pricingUpdate model.Pricing
 	if err := ctx.ShouldBindJSON(&pricingUpdate); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
 		return
 	}
 
 	// Ensure the ID in the path matches the ID in the body, or set it.
 	pricingUpdate.ID = id
+	if !requireVersion(ctx, &pricingUpdate.Version) {
+		return
+	}
 
 	// Basic Validations
 	if pricingUpdate.RentalID == uuid.Nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RentalID is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "RentalIDRequired"})
 		return
 	}
 	if pricingUpdate.MonthlyRent <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "MonthlyRent must be positive"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "MonthlyRentMustBePositive"})
 		return
 	}
 	if pricingUpdate.DueDay < 1 || pricingUpdate.DueDay > 31 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DueDay must be between 1 and 31"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DueDayOutOfRange"})
+		return
+	}
+	if !model.IsValidIncreasePolicy(pricingUpdate.IncreasePolicy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIncreasePolicy"})
+		return
+	}
+	if pricingUpdate.IncreasePercentage < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "NegativeIncreasePercentage"})
+		return
+	}
+	if err := model.ValidatePricingComponents(pricingUpdate.Components); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidCurrency(pricingUpdate.Currency) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCurrency"})
 		return
 	}
 
 	updatedPricing, err := c.repository.Update(ctx, pricingUpdate)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, _ := c.repository.GetByID(ctx, id)
+		respondVersionConflict(ctx, current)
+		return
+	}
 	if err != nil {
-		log.Printf("Error updating pricing ID %s: %v", idStr, err)
+		logging.FromContext(ctx).Error("error updating pricing", "id", idStr, "error", err)
 		// Could be an actual DB error or record not found if Update doesn't distinguish
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pricing record"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUpdatePricingRecord"})
 		return
 	}
 	if updatedPricing == nil {
-		// This can happen if the Update method returns nil when record not found
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Pricing record not found or no changes made"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PricingRecordNotFound"})
 		return
 	}
 
@@ -180,21 +311,21 @@ func (c *PricingController) HandleDeletePricing(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Pricing ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPricingIDFormat"})
 		return
 	}
 
 	// Optional: Check if pricing record exists before attempting delete
 	// existing, _ := c.repository.GetByID(ctx, id)
 	// if existing == nil {
-	// 	ctx.JSON(http.StatusNotFound, gin.H{"error": "Pricing record not found"})
+	// 	ctx.JSON(http.StatusNotFound, gin.H{"error": "PricingRecordNotFound"})
 	// 	return
 	// }
 
 	err = c.repository.Delete(ctx, id)
 	if err != nil {
-		log.Printf("Error deleting pricing ID %s: %v", idStr, err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pricing record"})
+		logging.FromContext(ctx).Error("error deleting pricing", "id", idStr, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToDeletePricingRecord"})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Pricing record deleted successfully"})
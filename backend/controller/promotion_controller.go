@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// PromotionController handles HTTP requests for the promotions of property listings
+type PromotionController struct {
+	repository   storage.PromotionStore
+	propertyRepo storage.PropertyStore
+}
+
+// NewPromotionController creates a new PromotionController
+func NewPromotionController(repository storage.PromotionStore, propertyRepo storage.PropertyStore) *PromotionController {
+	return &PromotionController{
+		repository:   repository,
+		propertyRepo: propertyRepo,
+	}
+}
+
+// PromotionStats summarizes how many contracts were offered with a promotion and how many were signed
+type PromotionStats struct {
+	Promotion      model.Promotion `json:"promotion"`
+	Offered        int             `json:"offered"`
+	Converted      int             `json:"converted"`
+	ConversionRate float64         `json:"conversion_rate"` // Percentage of offered contracts that were signed
+}
+
+// RegisterRoutes registers the promotion routes available to authenticated users
+func (c *PromotionController) RegisterRoutes(router *gin.RouterGroup) {
+	promotions := router.Group("/promotions")
+	{
+		promotions.GET("/property/:propertyId/offer", c.GetOffer)
+	}
+}
+
+// RegisterAdminRoutes registers the promotion routes on an admin-protected group
+func (c *PromotionController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	promotions := adminRouter.Group("/promotions")
+	{
+		promotions.GET("", c.GetAll)
+		promotions.GET("/stats", c.GetStats)
+		promotions.GET("/:id", c.GetByID)
+		promotions.POST("", c.Create)
+		promotions.PUT("/:id", c.Update)
+		promotions.DELETE("/:id", c.Delete)
+	}
+}
+
+// GetAll retrieves all promotions
+func (c *PromotionController) GetAll(ctx *gin.Context) {
+	promotions, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if promotions == nil {
+		promotions = []model.Promotion{}
+	}
+
+	ctx.JSON(http.StatusOK, promotions)
+}
+
+// GetByID retrieves a promotion by ID
+func (c *PromotionController) GetByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	promotion, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if promotion == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PromotionNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, promotion)
+}
+
+// GetOffer returns the promotions of a property valid now and the resulting offer for the
+// monthly_rent and deposit query parameters
+func (c *PromotionController) GetOffer(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	monthlyRent, err := strconv.ParseFloat(ctx.DefaultQuery("monthly_rent", "0"), 64)
+	if err != nil || monthlyRent < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidMonthlyRent"})
+		return
+	}
+	deposit, err := strconv.ParseFloat(ctx.DefaultQuery("deposit", "0"), 64)
+	if err != nil || deposit < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDeposit"})
+		return
+	}
+
+	promotions, err := c.repository.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer := service.ApplyPromotions(monthlyRent, deposit, service.ActivePromotions(promotions, time.Now()))
+	ctx.JSON(http.StatusOK, gin.H{
+		"offer":                    offer,
+		"contract_clause":          service.PromotionClauseText(offer),
+		"first_month_rent_display": service.FormatMoney(offer.FirstMonthRent),
+		"deposit_display":          service.FormatMoney(offer.SecurityDeposit),
+	})
+}
+
+// GetStats reports how many contracts were offered with each promotion and how many converted
+func (c *PromotionController) GetStats(ctx *gin.Context) {
+	promotions, err := c.repository.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	redemptions, err := c.repository.GetRedemptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	byPromotion := make(map[uuid.UUID]*PromotionStats, len(promotions))
+	stats := make([]PromotionStats, len(promotions))
+	for i, promotion := range promotions {
+		stats[i].Promotion = promotion
+		byPromotion[promotion.ID] = &stats[i]
+	}
+	for _, redemption := range redemptions {
+		promotionStats, ok := byPromotion[redemption.PromotionID]
+		if !ok {
+			continue
+		}
+		promotionStats.Offered++
+		if redemption.ConvertedAt != nil {
+			promotionStats.Converted++
+		}
+	}
+	for i := range stats {
+		if stats[i].Offered > 0 {
+			stats[i].ConversionRate = float64(stats[i].Converted) * 100 / float64(stats[i].Offered)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// Create adds a new promotion to the listing of a property
+func (c *PromotionController) Create(ctx *gin.Context) {
+	var promotion model.Promotion
+	if err := ctx.ShouldBindJSON(&promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if err := service.ValidatePromotion(promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, promotion.PropertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	promotion.ID = uuid.New()
+	promotion.CreatedAt = time.Now()
+
+	createdPromotion, err := c.repository.Create(ctx, promotion)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating promotion", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePromotion"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdPromotion)
+}
+
+// Update updates an existing promotion
+func (c *PromotionController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var promotion model.Promotion
+	if err := ctx.ShouldBindJSON(&promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if err := service.ValidatePromotion(promotion); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existingPromotion, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingPromotion == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PromotionNotFound"})
+		return
+	}
+
+	promotion.ID = id
+	if promotion.PropertyID == uuid.Nil {
+		promotion.PropertyID = existingPromotion.PropertyID
+	}
+
+	updatedPromotion, err := c.repository.Update(ctx, promotion)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedPromotion)
+}
+
+// Delete removes a promotion
+func (c *PromotionController) Delete(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	existingPromotion, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingPromotion == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PromotionNotFound"})
+		return
+	}
+
+	if err := c.repository.Delete(ctx, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -1,35 +1,69 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
-	"log"
-
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // PropertyController handles HTTP requests for property entities
 type PropertyController struct {
-	repository *storage.PropertyRepository
+	repository storage.PropertyStore
+	photoRepo  storage.PropertyPhotoStore
 }
 
 // NewPropertyController creates a new PropertyController
-func NewPropertyController(repository *storage.PropertyRepository) *PropertyController {
+func NewPropertyController(repository storage.PropertyStore, photoRepo storage.PropertyPhotoStore) *PropertyController {
 	return &PropertyController{
 		repository: repository,
+		photoRepo:  photoRepo,
 	}
 }
 
+// ReorderPhotosRequest lists the photos of a property gallery in their new order
+type ReorderPhotosRequest struct {
+	PhotoIDs []string `json:"photo_ids" binding:"required"`
+}
+
+// UpdatePhotoRequest changes the caption of a property photo
+type UpdatePhotoRequest struct {
+	Caption string `json:"caption"`
+}
+
 // GetAll retrieves properties based on user role
 // @Summary Get properties (role-based)
 // @Description Get properties. Admins get all. Managers get their managed properties. Residents get their resident properties.
 // @Tags properties
 // @Accept json
 // @Produce json
+// @Param city query string false "City, case insensitive"
+// @Param min_bedrooms query int false "Minimum number of bedrooms"
+// @Param min_bathrooms query int false "Minimum number of bathrooms"
+// @Param min_area query number false "Minimum area in m²"
+// @Param max_area query number false "Maximum area in m²"
+// @Param estrato query int false "Socioeconomic stratum, 1 to 6"
+// @Param parking query bool false "With (true) or without (false) parking spots"
+// @Param furnished query bool false "Furnished"
+// @Param pets_allowed query bool false "Pets allowed"
+// @Param limit query int false "Page size, the whole list without it"
+// @Param offset query int false "Rows skipped"
+// @Param sort query string false "Column to sort by, prefixed with - for descending order"
+// @Param filter[column] query string false "Exact value of a column"
+// @Header 200 {int} X-Total-Count "Size of the list before limit and offset"
 // @Success 200 {array} model.Property
 // @Failure 401 {object} string "Unauthorized"
 // @Failure 403 {object} string "Forbidden"
@@ -37,52 +71,129 @@ func NewPropertyController(repository *storage.PropertyRepository) *PropertyCont
 func (c *PropertyController) GetAll(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return
+	}
+
+	filter, err := propertyFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := listOptionsFromQuery(ctx, storage.PropertyListColumns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	var properties []model.Property
-	var err error
 
 	switch authUser.Role {
 	case "admin":
-		properties, err = c.repository.GetAll(ctx)
+		// The whole table is paginated, sorted and filtered by the database
+		page, total, err := c.repository.List(ctx, filter, opts)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveProperties", "detail": err.Error()})
+			return
+		}
+		c.attachPhotos(ctx, page)
+		respondPage(ctx, page, total)
+		return
 	case "manager":
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Manager PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ManagerPersonIDMissing"})
 			return
 		}
 		properties, err = c.repository.GetPropertiesForManager(ctx, authUser.PersonID)
 	case "resident":
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Resident PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ResidentPersonIDMissing"})
 			return
 		}
 		// Assuming resident's properties are linked via property.resident_id which is authUser.PersonID
 		// If it's via rentals, this logic would need to use GetByUserID or similar
 		properties, err = c.repository.GetByResident(ctx, authUser.PersonID)
 	default:
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view these properties"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForProperties"})
 		return
 	}
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve properties: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveProperties", "detail": err.Error()})
 		return
 	}
 
-	if properties == nil { // Ensure properties is not nil, make it an empty slice if no results
-		properties = []model.Property{}
-	}
+	properties = filter.Filter(properties) // Never nil, empty when there are no results
+	properties = paginateList(ctx, properties, opts)
+	c.attachPhotos(ctx, properties)
 
 	ctx.JSON(http.StatusOK, properties)
 }
 
+// propertyFilterFromQuery reads the characteristics filter of the property lists
+func propertyFilterFromQuery(ctx *gin.Context) (storage.PropertyFilter, error) {
+	filter := storage.PropertyFilter{City: strings.TrimSpace(ctx.Query("city"))}
+
+	for param, target := range map[string]*int{
+		"min_bedrooms":  &filter.MinBedrooms,
+		"min_bathrooms": &filter.MinBathrooms,
+		"estrato":       &filter.Estrato,
+	} {
+		if value := ctx.Query(param); value != "" {
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 0 {
+				return filter, fmt.Errorf("%s must be a positive integer", param)
+			}
+			*target = number
+		}
+	}
+	if filter.Estrato > 6 {
+		return filter, fmt.Errorf("estrato must be between 1 and 6")
+	}
+
+	for param, target := range map[string]*float64{"min_area": &filter.MinAreaM2, "max_area": &filter.MaxAreaM2} {
+		if value := ctx.Query(param); value != "" {
+			area, err := strconv.ParseFloat(value, 64)
+			if err != nil || area < 0 {
+				return filter, fmt.Errorf("%s must be a positive number", param)
+			}
+			*target = area
+		}
+	}
+
+	for param, target := range map[string]**bool{
+		"parking":      &filter.Parking,
+		"furnished":    &filter.Furnished,
+		"pets_allowed": &filter.PetsAllowed,
+	} {
+		if value := ctx.Query(param); value != "" {
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be true or false", param)
+			}
+			*target = &flag
+		}
+	}
+
+	return filter, nil
+}
+
+// validatePropertyCharacteristics rejects negative characteristics and unknown strata
+func validatePropertyCharacteristics(property model.Property) error {
+	if property.Bedrooms < 0 || property.Bathrooms < 0 || property.ParkingSpots < 0 || property.AreaM2 < 0 {
+		return fmt.Errorf("bedrooms, bathrooms, parking_spots and area_m2 cannot be negative")
+	}
+	if property.Estrato < 0 || property.Estrato > 6 {
+		return fmt.Errorf("estrato must be between 1 and 6, or 0 when unknown")
+	}
+	return nil
+}
+
 // GetByID retrieves a property by ID
 // @Summary Get property by ID
 // @Description Get property by ID
@@ -96,7 +207,7 @@ func (c *PropertyController) GetAll(ctx *gin.Context) {
 func (c *PropertyController) GetByID(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -107,11 +218,13 @@ func (c *PropertyController) GetByID(ctx *gin.Context) {
 	}
 
 	if property == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Property not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
 		return
 	}
+	properties := []model.Property{*property}
+	c.attachPhotos(ctx, properties)
 
-	ctx.JSON(http.StatusOK, property)
+	ctx.JSON(http.StatusOK, properties[0])
 }
 
 // GetByResident retrieves properties by resident ID
@@ -126,19 +239,19 @@ func (c *PropertyController) GetByID(ctx *gin.Context) {
 func (c *PropertyController) GetByResident(ctx *gin.Context) {
 	residentID, err := uuid.Parse(ctx.Param("residentId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
 	// Authorization check
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
@@ -147,7 +260,7 @@ func (c *PropertyController) GetByResident(ctx *gin.Context) {
 	// Assuming "user" role is equivalent to "resident" for this check.
 	if authUser.Role != "admin" && authUser.Role != "manager" { // Managers should not use this endpoint directly for other residents
 		if authUser.PersonID != residentID {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access your own resident properties"})
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnResidentPropertiesOnly"})
 			return
 		}
 	}
@@ -158,6 +271,8 @@ func (c *PropertyController) GetByResident(ctx *gin.Context) {
 		return
 	}
 
+	c.attachPhotos(ctx, properties)
+
 	ctx.JSON(http.StatusOK, properties)
 }
 
@@ -173,24 +288,24 @@ func (c *PropertyController) GetByResident(ctx *gin.Context) {
 func (c *PropertyController) GetByManagerID(ctx *gin.Context) {
 	managerID, err := uuid.Parse(ctx.Param("managerId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
 	// Authorization: Check if the authenticated user is the manager or an admin
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" && authUser.PersonID != managerID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access properties you manage"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "ManagedPropertiesOnly"})
 		return
 	}
 
@@ -200,6 +315,8 @@ func (c *PropertyController) GetByManagerID(ctx *gin.Context) {
 		return
 	}
 
+	c.attachPhotos(ctx, properties)
+
 	ctx.JSON(http.StatusOK, properties)
 }
 
@@ -216,27 +333,27 @@ func (c *PropertyController) GetByUserID(ctx *gin.Context) {
 	// Extract userId from path parameter
 	userID, err := uuid.Parse(ctx.Param("userId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidUserIDFormat"})
 		return
 	}
 
 	// Get the user from the context
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	// If the user is an admin, they can see any user's properties
 	// If not, they can only see their own properties
 	if authUser.Role != "admin" && authUser.ID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only access your own properties"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OwnPropertiesOnly"})
 		return
 	}
 
@@ -247,6 +364,8 @@ func (c *PropertyController) GetByUserID(ctx *gin.Context) {
 		return
 	}
 
+	c.attachPhotos(ctx, properties)
+
 	ctx.JSON(http.StatusOK, properties)
 }
 
@@ -262,29 +381,32 @@ func (c *PropertyController) GetByUserID(ctx *gin.Context) {
 func (c *PropertyController) Create(ctx *gin.Context) {
 	var property model.Property
 	if err := ctx.ShouldBindJSON(&property); err != nil {
-		log.Printf("Error binding property JSON: %v", err)
+		logging.FromContext(ctx).Error("error binding property JSON", "error", err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePropertyCharacteristics(property); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Log the incoming property data
-	log.Printf("Creating property: %+v", property)
-	log.Printf("Manager IDs received: %v", property.ManagerIDs)
+	logging.FromContext(ctx).Info("creating property", "property", property)
+	logging.FromContext(ctx).Info("manager IDs received", "manager_ids", property.ManagerIDs)
 
 	// Authorization check
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
-	log.Printf("Auth user: %s (ID: %s, PersonID: %s, Role: %s)",
-		authUser.Email, authUser.ID, authUser.PersonID, authUser.Role)
+	logging.FromContext(ctx).Info("creating property", "email", authUser.Email, "auth_user_id", authUser.ID, "person_id", authUser.PersonID, "role", authUser.Role)
 
 	// If user is manager, they can only create properties they manage
 	if authUser.Role == "manager" {
@@ -298,33 +420,32 @@ func (c *PropertyController) Create(ctx *gin.Context) {
 		}
 
 		if !managerFound {
-			log.Printf("Manager %s (PersonID: %s) attempting to create property without themselves as manager",
-				authUser.Email, authUser.PersonID)
+			logging.FromContext(ctx).Info("adding the manager to the property", "email", authUser.Email, "person_id", authUser.PersonID)
 			// Add manager to ManagerIDs
 			property.ManagerIDs = append(property.ManagerIDs, authUser.PersonID)
-			log.Printf("Added authenticated manager's PersonID to ManagerIDs: %s", authUser.PersonID)
+			logging.FromContext(ctx).Info("added authenticated manager's PersonID to ManagerIDs", "person_id", authUser.PersonID)
 		}
 	} else if authUser.Role != "admin" {
 		// Only managers and admins can create properties
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only managers and administrators can create properties"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersCreateProperties"})
 		return
 	}
 
 	// Generate a new UUID if not provided
 	if property.ID == uuid.Nil {
 		property.ID = uuid.New()
-		log.Printf("Generated new property ID: %s", property.ID)
+		logging.FromContext(ctx).Info("generated new property", "property_id", property.ID)
 	}
 
 	createdProperty, err := c.repository.Create(ctx, property)
 	if err != nil {
-		log.Printf("Error creating property in repository: %v", err)
+		logging.FromContext(ctx).Error("error creating property in repository", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("Property created successfully: %+v", createdProperty)
-	log.Printf("Property manager IDs: %v", createdProperty.ManagerIDs)
+	logging.FromContext(ctx).Info("property created successfully", "property_id", createdProperty.ID)
+	logging.FromContext(ctx).Info("property manager IDs", "manager_ids", createdProperty.ManagerIDs)
 
 	ctx.JSON(http.StatusCreated, createdProperty)
 }
@@ -337,13 +458,16 @@ func (c *PropertyController) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Property ID"
 // @Param property body model.Property true "Property object"
+// @Param If-Match header string false "Version of the property, instead of the version of the body"
 // @Success 200 {object} model.Property
 // @Failure 404 {object} string "Property not found"
+// @Failure 409 {object} object "The property changed since its version was read"
+// @Failure 428 {object} string "The version of the property was not sent"
 // @Router /properties/{id} [put]
 func (c *PropertyController) Update(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -355,19 +479,19 @@ func (c *PropertyController) Update(ctx *gin.Context) {
 	}
 
 	if existingProperty == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Property not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
 		return
 	}
 
 	// Authorization check
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
@@ -382,14 +506,13 @@ func (c *PropertyController) Update(ctx *gin.Context) {
 		}
 
 		if !managerFound {
-			log.Printf("Manager %s (PersonID: %s) attempting to update property they don't manage",
-				authUser.Email, authUser.PersonID)
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only update properties you manage"})
+			logging.FromContext(ctx).Warn("manager does not manage the property", "email", authUser.Email, "person_id", authUser.PersonID)
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "UpdateManagedPropertiesOnly"})
 			return
 		}
 	} else if authUser.Role != "admin" && authUser.Role != "manager" {
 		// Only admins and managers can update properties
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Only managers and administrators can update properties"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersUpdateProperties"})
 		return
 	}
 
@@ -398,9 +521,16 @@ func (c *PropertyController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validatePropertyCharacteristics(property); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Ensure the ID in the URL matches the ID in the body
 	property.ID = id
+	if !requireVersion(ctx, &property.Version) {
+		return
+	}
 
 	// If manager, ensure they stay in the ManagerIDs list
 	if authUser.Role == "manager" {
@@ -418,16 +548,24 @@ func (c *PropertyController) Update(ctx *gin.Context) {
 		if !managerFound {
 			// Add manager to the list to prevent them from removing themselves
 			property.ManagerIDs = append(property.ManagerIDs, preserveManagerID)
-			log.Printf("Ensured manager %s remains in ManagerIDs for property %s",
-				preserveManagerID, property.ID)
+			logging.FromContext(ctx).Info("ensured manager remains in ManagerIDs for property", "preserve_manager_id", preserveManagerID, "property_id", property.ID)
 		}
 	}
 
 	updatedProperty, err := c.repository.Update(ctx, property)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, _ := c.repository.GetByID(ctx, id)
+		respondVersionConflict(ctx, current)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if updatedProperty == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, updatedProperty)
 }
@@ -444,7 +582,7 @@ func (c *PropertyController) Update(ctx *gin.Context) {
 func (c *PropertyController) Delete(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -469,5 +607,345 @@ func (c *PropertyController) RegisterRoutes(router *gin.RouterGroup) {
 		properties.POST("", c.Create)
 		properties.PUT("/:id", c.Update)
 		properties.DELETE("/:id", c.Delete)
+		properties.POST("/:id/photos", c.UploadPhoto)
+		properties.PUT("/:id/photos/order", c.ReorderPhotos)
+		properties.PUT("/:id/photos/:photoId", c.UpdatePhoto)
+		properties.DELETE("/:id/photos/:photoId", c.DeletePhoto)
+	}
+}
+
+// attachPhotos fills the galleries of the properties with signed photo URLs
+func (c *PropertyController) attachPhotos(ctx *gin.Context, properties []model.Property) {
+	if len(properties) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+	photos, err := c.photoRepo.GetByPropertyIDs(ctx, ids)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching property photos", "error", err)
+		return
+	}
+	signPropertyPhotoURLs(photos)
+
+	galleries := make(map[uuid.UUID][]model.PropertyPhoto)
+	for _, photo := range photos {
+		galleries[photo.PropertyID] = append(galleries[photo.PropertyID], photo)
+	}
+	for i := range properties {
+		properties[i].Photos = galleries[properties[i].ID]
+	}
+}
+
+// signPropertyPhotoURLs sets signed URLs of the stored paths of the photos
+func signPropertyPhotoURLs(photos []model.PropertyPhoto) {
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil || len(photos) == 0 {
+		return
+	}
+
+	paths := make([]string, len(photos))
+	for i, photo := range photos {
+		paths[i] = photo.Path
+	}
+	urls, err := storageService.SignedURLs(paths, storageService.URLTTL())
+	if err != nil {
+		slog.Error("error signing property photo URLs", "error", err)
+		return
+	}
+	for i := range photos {
+		photos[i].URL = urls[photos[i].Path]
+	}
+}
+
+// gallery returns the photos of a property in order with signed URLs
+func (c *PropertyController) gallery(ctx *gin.Context, propertyID uuid.UUID) ([]model.PropertyPhoto, error) {
+	photos, err := c.photoRepo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if photos == nil {
+		photos = []model.PropertyPhoto{}
+	}
+	signPropertyPhotoURLs(photos)
+	return photos, nil
+}
+
+// loadPhoto reads the photo in the photoId path parameter, responding with an error when it is
+// not a photo of the property in the id path parameter
+func (c *PropertyController) loadPhoto(ctx *gin.Context) (*model.PropertyPhoto, bool) {
+	propertyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+	photoID, err := uuid.Parse(ctx.Param("photoId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPhotoIDFormat"})
+		return nil, false
+	}
+
+	photo, err := c.photoRepo.GetByID(ctx, photoID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if photo == nil || photo.PropertyID != propertyID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PhotoNotFound"})
+		return nil, false
+	}
+	return photo, true
+}
+
+// UploadPhoto adds a photo at the end of the gallery of a property
+// @Summary Upload a property photo
+// @Description The photo is stored under property_<id>/ and returned with the property payload, in gallery order
+// @Tags properties
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Property ID"
+// @Param file formData file true "JPG, PNG or GIF photo up to 10 MB"
+// @Param caption formData string false "Caption of the photo"
+// @Success 201 {object} model.PropertyPhoto
+// @Router /admin/properties/{id}/photos [post]
+func (c *PropertyController) UploadPhoto(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	property, err := c.repository.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" && ext != ".gif" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedPhotoType"})
+		return
+	}
+	if header.Size > maxInventoryPhotoSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoTooLarge"})
+		return
+	}
+
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	existing, err := c.photoRepo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadPhoto"})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	filePath := fmt.Sprintf("property_%s/%d_%s%s", propertyID, time.Now().Unix(), uuid.New().String()[:8], ext)
+	uploadResponse, err := storageService.UploadBytes(filePath, data, contentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("error uploading photo of property", "property_id", propertyID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadPhoto"})
+		return
+	}
+
+	photo, err := c.photoRepo.Create(ctx, model.PropertyPhoto{
+		PropertyID: propertyID,
+		Path:       uploadResponse.Path,
+		Caption:    ctx.PostForm("caption"),
+		Position:   len(existing),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSavePhoto"})
+		return
 	}
+	photo.URL = uploadResponse.Link
+
+	ctx.JSON(http.StatusCreated, photo)
+}
+
+// ReorderPhotos sets the order of the gallery of a property
+// @Summary Reorder the photos of a property
+// @Description photo_ids must list every photo of the property once, in the new order
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Param id path string true "Property ID"
+// @Param order body ReorderPhotosRequest true "Photo IDs in order"
+// @Success 200 {array} model.PropertyPhoto
+// @Router /admin/properties/{id}/photos/order [put]
+func (c *PropertyController) ReorderPhotos(ctx *gin.Context) {
+	propertyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	photos, err := c.photoRepo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	positions := make(map[uuid.UUID]int, len(photos))
+	for _, photo := range photos {
+		positions[photo.ID] = photo.Position
+	}
+	if len(req.PhotoIDs) != len(photos) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoIDsIncomplete"})
+		return
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.PhotoIDs))
+	order := make([]uuid.UUID, 0, len(req.PhotoIDs))
+	for _, rawID := range req.PhotoIDs {
+		photoID, err := uuid.Parse(rawID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPhotoID", "detail": rawID})
+			return
+		}
+		if _, ok := positions[photoID]; !ok || seen[photoID] {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "PhotoIDsNotUnique"})
+			return
+		}
+		seen[photoID] = true
+		order = append(order, photoID)
+	}
+
+	for position, photoID := range order {
+		if positions[photoID] == position {
+			continue
+		}
+		if err := c.photoRepo.UpdatePosition(ctx, photoID, position); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToReorderPhotos"})
+			return
+		}
+	}
+
+	gallery, err := c.gallery(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gallery)
+}
+
+// UpdatePhoto changes the caption of a property photo
+// @Summary Caption a property photo
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Param id path string true "Property ID"
+// @Param photoId path string true "Photo ID"
+// @Param photo body UpdatePhotoRequest true "Caption"
+// @Success 200 {object} model.PropertyPhoto
+// @Router /admin/properties/{id}/photos/{photoId} [put]
+func (c *PropertyController) UpdatePhoto(ctx *gin.Context) {
+	photo, ok := c.loadPhoto(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdatePhotoRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	photo.Caption = strings.TrimSpace(req.Caption)
+	if err := c.photoRepo.UpdateCaption(ctx, photo.ID, photo.Caption); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUpdatePhoto"})
+		return
+	}
+
+	photos := []model.PropertyPhoto{*photo}
+	signPropertyPhotoURLs(photos)
+	ctx.JSON(http.StatusOK, photos[0])
+}
+
+// DeletePhoto removes a photo from the gallery of a property and from storage
+// @Summary Delete a property photo
+// @Tags properties
+// @Produce json
+// @Param id path string true "Property ID"
+// @Param photoId path string true "Photo ID"
+// @Success 200 {array} model.PropertyPhoto "The remaining gallery"
+// @Router /admin/properties/{id}/photos/{photoId} [delete]
+func (c *PropertyController) DeletePhoto(ctx *gin.Context) {
+	photo, ok := c.loadPhoto(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.photoRepo.Delete(ctx, photo.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		deletedBy := ""
+		if value, exists := ctx.Get("user"); exists {
+			if authUser, ok := value.(*model.User); ok {
+				deletedBy = authUser.Email
+			}
+		}
+		if _, err := storageService.DeleteFile(photo.Path, deletedBy); err != nil {
+			logging.FromContext(ctx).Error("error deleting file of property photo", "path", photo.Path, "photo_id", photo.ID, "error", err)
+		}
+	}
+
+	// Close the gap left in the gallery
+	photos, err := c.photoRepo.GetByPropertyID(ctx, photo.PropertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for position := range photos {
+		if photos[position].Position == position {
+			continue
+		}
+		if err := c.photoRepo.UpdatePosition(ctx, photos[position].ID, position); err != nil {
+			logging.FromContext(ctx).Error("error renumbering photo of property", "id", photos[position].ID, "property_id", photo.PropertyID, "error", err)
+		}
+		photos[position].Position = position
+	}
+	if photos == nil {
+		photos = []model.PropertyPhoto{}
+	}
+	signPropertyPhotoURLs(photos)
+
+	ctx.JSON(http.StatusOK, photos)
 }
@@ -0,0 +1,573 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const maxReglamentoSize = 20 << 20 // 20 MB
+
+// ReglamentoController handles the manual de convivencia of each building and the tenants'
+// acknowledgments of its current version
+type ReglamentoController struct {
+	repository   storage.ReglamentoStore
+	propertyRepo storage.PropertyStore
+	rentalRepo   storage.RentalStore
+	personRepo   storage.PersonStore
+	userRepo     storage.UserStore
+}
+
+// NewReglamentoController creates a new ReglamentoController
+func NewReglamentoController(
+	repository storage.ReglamentoStore,
+	propertyRepo storage.PropertyStore,
+	rentalRepo storage.RentalStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+) *ReglamentoController {
+	return &ReglamentoController{
+		repository:   repository,
+		propertyRepo: propertyRepo,
+		rentalRepo:   rentalRepo,
+		personRepo:   personRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// RegisterRoutes registers the reglamento routes available to authenticated users. Uploads and
+// the compliance report are limited to admins and the managers of the building.
+func (c *ReglamentoController) RegisterRoutes(router *gin.RouterGroup) {
+	reglamentos := router.Group("/reglamentos")
+	{
+		reglamentos.GET("/pending", c.GetPending)
+		reglamentos.GET("/compliance", c.GetCompliance)
+		reglamentos.POST("", c.Upload)
+		reglamentos.GET("/:id/pdf", c.ServePDF)
+		reglamentos.POST("/:id/acknowledge", c.Acknowledge)
+	}
+}
+
+// GetPending lists the current reglamentos of the buildings the user rents in that they have not
+// acknowledged yet
+func (c *ReglamentoController) GetPending(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	properties, err := c.tenantProperties(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	acknowledgments, err := c.repository.GetAcknowledgmentsByPerson(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	acknowledged := make(map[uuid.UUID]bool, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		acknowledged[acknowledgment.ReglamentoID] = true
+	}
+
+	pending := []gin.H{}
+	seen := make(map[string]bool)
+	for _, property := range properties {
+		key := property.BuildingKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		reglamento, err := c.repository.GetLatestByBuilding(ctx, key)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if reglamento == nil || acknowledged[reglamento.ID] {
+			continue
+		}
+
+		response := reglamentoResponse(*reglamento)
+		response["property_id"] = property.ID
+		pending = append(pending, response)
+	}
+
+	ctx.JSON(http.StatusOK, pending)
+}
+
+// ServePDF serves a version of the reglamento to the tenants, managers and admins of the building
+func (c *ReglamentoController) ServePDF(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	reglamento, ok := c.getReglamento(ctx)
+	if !ok {
+		return
+	}
+
+	allowed, err := c.canAccessBuilding(ctx, authUser, reglamento.BuildingKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NoBuildingAccess"})
+		return
+	}
+
+	pdfData, err := loadStoredPDF(reglamento.FilePath)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading reglamento", "reglamento_id", reglamento.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadReglamento"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=manual_de_convivencia_v%d.pdf", reglamento.Version))
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// Acknowledge records that the user read the reglamento, with the time, IP and user agent of
+// the request as evidence
+func (c *ReglamentoController) Acknowledge(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	reglamento, ok := c.getReglamento(ctx)
+	if !ok {
+		return
+	}
+
+	latest, err := c.repository.GetLatestByBuilding(ctx, reglamento.BuildingKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if latest != nil && latest.ID != reglamento.ID {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "NewerReglamentoPublished", "reglamento": reglamentoResponse(*latest)})
+		return
+	}
+
+	properties, err := c.tenantProperties(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var property *model.Property
+	for i := range properties {
+		if properties[i].BuildingKey() == reglamento.BuildingKey {
+			property = &properties[i]
+			break
+		}
+	}
+	if property == nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotRentingInBuilding"})
+		return
+	}
+
+	acknowledgments, err := c.repository.GetAcknowledgmentsByPerson(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, acknowledgment := range acknowledgments {
+		if acknowledgment.ReglamentoID == reglamento.ID {
+			ctx.JSON(http.StatusOK, acknowledgment)
+			return
+		}
+	}
+
+	created, err := c.repository.CreateAcknowledgment(ctx, model.ReglamentoAcknowledgment{
+		ReglamentoID:   reglamento.ID,
+		BuildingKey:    reglamento.BuildingKey,
+		Version:        reglamento.Version,
+		PersonID:       authUser.PersonID,
+		PropertyID:     property.ID,
+		AcknowledgedAt: time.Now(),
+		IPAddress:      ctx.ClientIP(),
+		UserAgent:      ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRecordAcknowledgment"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("manual de convivencia v aceptado", "version", reglamento.Version, "address", reglamento.Address, "email", authUser.Email)
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Upload publishes a new version of the reglamento of the building of a property and asks its
+// current tenants to acknowledge it
+func (c *ReglamentoController) Upload(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	if authUser.Role != "admin" && authUser.Role != "manager" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersUploadReglamentos"})
+		return
+	}
+
+	propertyID, err := uuid.Parse(ctx.PostForm("property_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyID"})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+	if authUser.Role == "manager" && !managesProperty(*property, authUser.PersonID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "PropertyNotManaged"})
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(header.Filename)) != ".pdf" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "OnlyPDFSupported"})
+		return
+	}
+	if header.Size > maxReglamentoSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileTooLarge20MB"})
+		return
+	}
+
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadFile"})
+		return
+	}
+
+	buildingKey := property.BuildingKey()
+	current, err := c.repository.GetLatestByBuilding(ctx, buildingKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	version := 1
+	if current != nil {
+		version = current.Version + 1
+	}
+
+	reglamento := model.Reglamento{
+		ID:          uuid.New(),
+		BuildingKey: buildingKey,
+		Address:     property.Address,
+		City:        property.City,
+		Version:     version,
+		FileName:    header.Filename,
+		UploadedBy:  authUser.PersonID,
+		UploadedAt:  time.Now(),
+	}
+
+	reglamento.FilePath = fmt.Sprintf("reglamentos/%s/v%d_%s.pdf", propertyID, version, reglamento.ID.String()[:8])
+	if _, err := storageService.UploadBytes(reglamento.FilePath, data, "application/pdf"); err != nil {
+		logging.FromContext(ctx).Error("error uploading reglamento of building", "building_key", buildingKey, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadReglamento"})
+		return
+	}
+
+	created, err := c.repository.Create(ctx, reglamento)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveReglamento"})
+		return
+	}
+
+	notified := c.notifyTenants(ctx, *created)
+	logging.FromContext(ctx).Info("manual de convivencia v publicado inquilinos notificados", "version", created.Version, "address", created.Address, "notified", notified)
+
+	response := reglamentoResponse(*created)
+	response["notified_tenants"] = notified
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// GetCompliance reports, for each building with a reglamento, which current tenants have
+// acknowledged its latest version. Managers only see the buildings of the properties they manage.
+func (c *ReglamentoController) GetCompliance(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var properties []model.Property
+	var err error
+	switch authUser.Role {
+	case "admin":
+		properties, err = c.propertyRepo.GetAll(ctx)
+	case "manager":
+		properties, err = c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
+	default:
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersSeeComplianceReport"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	latest, err := c.repository.GetLatest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantsByProperty, err := c.activeTenantsByProperty(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Group the apartments in scope by building, keeping the order of the properties
+	var buildingKeys []string
+	buildings := make(map[string][]model.Property)
+	for _, property := range properties {
+		key := property.BuildingKey()
+		if _, ok := latest[key]; !ok {
+			continue
+		}
+		if _, ok := buildings[key]; !ok {
+			buildingKeys = append(buildingKeys, key)
+		}
+		buildings[key] = append(buildings[key], property)
+	}
+
+	report := make([]gin.H, 0, len(buildingKeys))
+	for _, key := range buildingKeys {
+		reglamento := latest[key]
+		acknowledgments, err := c.repository.GetAcknowledgments(ctx, reglamento.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		acknowledgedAt := make(map[uuid.UUID]time.Time, len(acknowledgments))
+		for _, acknowledgment := range acknowledgments {
+			acknowledgedAt[acknowledgment.PersonID] = acknowledgment.AcknowledgedAt
+		}
+
+		tenants := []gin.H{}
+		acknowledgedCount := 0
+		for _, property := range buildings[key] {
+			for _, tenantID := range tenantsByProperty[property.ID] {
+				tenant := gin.H{
+					"person_id":    tenantID,
+					"property_id":  property.ID,
+					"apt_number":   property.AptNumber,
+					"acknowledged": false,
+				}
+				if person, err := c.personRepo.GetByID(ctx, tenantID); err == nil && person != nil {
+					tenant["full_name"] = person.FullName
+				}
+				if at, ok := acknowledgedAt[tenantID]; ok {
+					tenant["acknowledged"] = true
+					tenant["acknowledged_at"] = at
+					acknowledgedCount++
+				}
+				tenants = append(tenants, tenant)
+			}
+		}
+
+		compliance := 100.0
+		if len(tenants) > 0 {
+			compliance = float64(acknowledgedCount) * 100 / float64(len(tenants))
+		}
+
+		report = append(report, gin.H{
+			"reglamento":         reglamentoResponse(reglamento),
+			"tenants":            tenants,
+			"total_tenants":      len(tenants),
+			"acknowledged_count": acknowledgedCount,
+			"pending_count":      len(tenants) - acknowledgedCount,
+			"compliance_percent": compliance,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// getReglamento loads the reglamento of the :id parameter, writing the error response when it
+// is invalid or missing
+func (c *ReglamentoController) getReglamento(ctx *gin.Context) (*model.Reglamento, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	reglamento, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if reglamento == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ReglamentoNotFound"})
+		return nil, false
+	}
+	return reglamento, true
+}
+
+// canAccessBuilding reports whether the user administers, manages or rents in a building
+func (c *ReglamentoController) canAccessBuilding(ctx *gin.Context, authUser *model.User, buildingKey string) (bool, error) {
+	var properties []model.Property
+	var err error
+	switch authUser.Role {
+	case "admin":
+		return true, nil
+	case "manager":
+		properties, err = c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
+	default:
+		properties, err = c.tenantProperties(ctx, authUser.PersonID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, property := range properties {
+		if property.BuildingKey() == buildingKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// tenantProperties returns the properties a person currently rents
+func (c *ReglamentoController) tenantProperties(ctx *gin.Context, personID uuid.UUID) ([]model.Property, error) {
+	rentals, err := c.rentalRepo.GetByRenterID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var properties []model.Property
+	for _, rental := range rentals {
+		if rental.EndDate.Time().Before(now) {
+			continue
+		}
+		property, err := c.propertyRepo.GetByID(ctx, rental.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		if property != nil {
+			properties = append(properties, *property)
+		}
+	}
+	return properties, nil
+}
+
+// activeTenantsByProperty maps each property to the renters of its active rentals
+func (c *ReglamentoController) activeTenantsByProperty(ctx *gin.Context) (map[uuid.UUID][]uuid.UUID, error) {
+	rentals, err := c.rentalRepo.GetActiveRentals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make(map[uuid.UUID][]uuid.UUID)
+	for _, rental := range rentals {
+		tenants[rental.PropertyID] = append(tenants[rental.PropertyID], rental.RenterID)
+	}
+	return tenants, nil
+}
+
+// notifyTenants emails the current tenants of the building of a reglamento, returning how many
+// were notified. Failures are only logged, the tenants also see it pending on the platform.
+func (c *ReglamentoController) notifyTenants(ctx *gin.Context, reglamento model.Reglamento) int {
+	properties, err := c.propertyRepo.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading properties to notify reglamento", "reglamento_id", reglamento.ID, "error", err)
+		return 0
+	}
+	tenantsByProperty, err := c.activeTenantsByProperty(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading tenants to notify reglamento", "reglamento_id", reglamento.ID, "error", err)
+		return 0
+	}
+
+	notified := 0
+	seen := make(map[uuid.UUID]bool)
+	for _, property := range properties {
+		if property.BuildingKey() != reglamento.BuildingKey {
+			continue
+		}
+		for _, tenantID := range tenantsByProperty[property.ID] {
+			if seen[tenantID] {
+				continue
+			}
+			seen[tenantID] = true
+
+			user, err := c.userRepo.GetByPersonID(ctx, tenantID)
+			if err != nil || user == nil || user.Email == "" {
+				continue
+			}
+			tenantName := user.Email
+			if person, err := c.personRepo.GetByID(ctx, tenantID); err == nil && person != nil {
+				tenantName = person.FullName
+			}
+
+			if err := service.SendReglamentoAcknowledgmentEmail(user.Email, tenantName, reglamento.Address, reglamento.Version); err != nil {
+				logging.FromContext(ctx).Error("error notifying reglamento", "reglamento_id", reglamento.ID, "email", user.Email, "error", err)
+				continue
+			}
+			notified++
+		}
+	}
+	return notified
+}
+
+// managesProperty reports whether a manager is assigned to a property
+func managesProperty(property model.Property, managerPersonID uuid.UUID) bool {
+	for _, managerID := range property.ManagerIDs {
+		if managerID == managerPersonID {
+			return true
+		}
+	}
+	return false
+}
+
+// reglamentoResponse adds the download URL to a reglamento
+func reglamentoResponse(reglamento model.Reglamento) gin.H {
+	return gin.H{
+		"id":           reglamento.ID,
+		"building_key": reglamento.BuildingKey,
+		"address":      reglamento.Address,
+		"city":         reglamento.City,
+		"version":      reglamento.Version,
+		"file_name":    reglamento.FileName,
+		"uploaded_by":  reglamento.UploadedBy,
+		"uploaded_at":  reglamento.UploadedAt,
+		"pdf_url":      "/api/reglamentos/" + reglamento.ID.String() + "/pdf",
+	}
+}
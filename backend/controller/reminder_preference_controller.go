@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ReminderPreferenceController handles the reminder send-time preferences of people
+type ReminderPreferenceController struct {
+	repository storage.ReminderPreferenceStore
+	scheduler  *service.ReminderScheduler
+}
+
+// NewReminderPreferenceController creates a new ReminderPreferenceController
+func NewReminderPreferenceController(repository storage.ReminderPreferenceStore, scheduler *service.ReminderScheduler) *ReminderPreferenceController {
+	return &ReminderPreferenceController{
+		repository: repository,
+		scheduler:  scheduler,
+	}
+}
+
+// ReminderPreferenceRequest defines the editable fields of a reminder preference
+type ReminderPreferenceRequest struct {
+	SendHour         *int `json:"send_hour"` // 0-23 in the application timezone, null for the default hour
+	OptimizeSendTime bool `json:"optimize_send_time"`
+}
+
+// RegisterRoutes registers the routes of the authenticated user preferences
+func (c *ReminderPreferenceController) RegisterRoutes(router *gin.RouterGroup) {
+	preferences := router.Group("/reminder-preferences")
+	{
+		preferences.GET("/me", c.GetMine)
+		preferences.PUT("/me", c.UpdateMine)
+	}
+}
+
+// RegisterAdminRoutes registers the routes to manage the preferences of any person
+func (c *ReminderPreferenceController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	preferences := adminRouter.Group("/reminder-preferences")
+	{
+		preferences.GET("/:personId", c.GetByPersonID)
+		preferences.PUT("/:personId", c.UpdateByPersonID)
+	}
+}
+
+// GetMine returns the reminder preference of the authenticated user
+func (c *ReminderPreferenceController) GetMine(ctx *gin.Context) {
+	personID, ok := currentPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.respond(ctx, personID)
+}
+
+// UpdateMine updates the reminder preference of the authenticated user
+func (c *ReminderPreferenceController) UpdateMine(ctx *gin.Context) {
+	personID, ok := currentPersonID(ctx)
+	if !ok {
+		return
+	}
+	c.save(ctx, personID)
+}
+
+// GetByPersonID returns the reminder preference of a person
+func (c *ReminderPreferenceController) GetByPersonID(ctx *gin.Context) {
+	personID, err := uuid.Parse(ctx.Param("personId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonIDFormat"})
+		return
+	}
+	c.respond(ctx, personID)
+}
+
+// UpdateByPersonID updates the reminder preference of a person
+func (c *ReminderPreferenceController) UpdateByPersonID(ctx *gin.Context) {
+	personID, err := uuid.Parse(ctx.Param("personId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonIDFormat"})
+		return
+	}
+	c.save(ctx, personID)
+}
+
+// save creates or updates the preference of a person from the request body
+func (c *ReminderPreferenceController) save(ctx *gin.Context, personID uuid.UUID) {
+	var req ReminderPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if req.SendHour != nil && (*req.SendHour < 0 || *req.SendHour > 23) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "SendHourOutOfRange"})
+		return
+	}
+
+	existing, err := c.repository.GetByPersonID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	preference := model.ReminderPreference{
+		PersonID:         personID,
+		SendHour:         req.SendHour,
+		OptimizeSendTime: req.OptimizeSendTime,
+	}
+	if existing == nil {
+		_, err = c.repository.Create(ctx, preference)
+	} else {
+		_, err = c.repository.Update(ctx, preference)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveReminderPreference"})
+		return
+	}
+
+	c.respond(ctx, personID)
+}
+
+// respond writes the preference of a person with the hour their reminders are currently delivered at
+func (c *ReminderPreferenceController) respond(ctx *gin.Context, personID uuid.UUID) {
+	preference, err := c.repository.GetByPersonID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if preference == nil {
+		preference = &model.ReminderPreference{PersonID: personID}
+	}
+
+	hour, optimized := c.scheduler.SendHour(ctx, personID)
+	ctx.JSON(http.StatusOK, gin.H{
+		"preference":          preference,
+		"effective_send_hour": hour,
+		"optimized":           optimized,
+		"enabled":             c.scheduler.Enabled(),
+	})
+}
+
+// currentPersonID returns the person of the authenticated user, writing the error response when missing
+func currentPersonID(ctx *gin.Context) (uuid.UUID, bool) {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return uuid.Nil, false
+	}
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return uuid.Nil, false
+	}
+	if authUser.PersonID == uuid.Nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "UserNotLinkedToPerson"})
+		return uuid.Nil, false
+	}
+	return authUser.PersonID, true
+}
@@ -7,29 +7,73 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // RentPaymentController handles HTTP requests for rent payments
 type RentPaymentController struct {
-	repository         *storage.RentPaymentRepository
-	rentalRepository   *storage.RentalRepository
-	propertyRepository *storage.PropertyRepository
+	repository         storage.RentPaymentStore
+	rentalRepository   storage.RentalStore
+	propertyRepository storage.PropertyStore
+	pricingRepository  storage.PricingStore
+	orgService         *service.OrganizationService
 }
 
 // NewRentPaymentController creates a new rent payment controller
 func NewRentPaymentController(
-	repository *storage.RentPaymentRepository,
-	rentalRepo *storage.RentalRepository,
-	propertyRepo *storage.PropertyRepository,
+	repository storage.RentPaymentStore,
+	rentalRepo storage.RentalStore,
+	propertyRepo storage.PropertyStore,
+	pricingRepo storage.PricingStore,
+	orgService *service.OrganizationService,
 ) *RentPaymentController {
 	return &RentPaymentController{
 		repository:         repository,
 		rentalRepository:   rentalRepo,
 		propertyRepository: propertyRepo,
+		pricingRepository:  pricingRepo,
+		orgService:         orgService,
 	}
 }
 
+// rentPaymentRequest is the body of a rent payment. When paid_on_time is omitted it is computed
+// from the due day of the rental.
+type rentPaymentRequest struct {
+	storage.RentPayment
+	PaidOnTime *bool `json:"paid_on_time"`
+}
+
+// payment returns the payment of the request, deciding whether it was paid on time when the
+// client did not say
+func (c *RentPaymentController) payment(ctx *gin.Context, req rentPaymentRequest) storage.RentPayment {
+	payment := req.RentPayment
+	// The receipt number and the electronic invoice are only set by their services
+	payment.ReceiptNumber = ""
+	payment.RentPaymentInvoice = storage.RentPaymentInvoice{}
+	if req.PaidOnTime != nil {
+		payment.PaidOnTime = *req.PaidOnTime
+		return payment
+	}
+
+	payment.PaidOnTime = true
+	rentalID, err := uuid.Parse(payment.RentalID)
+	if err != nil || payment.PaymentDate.Time().IsZero() {
+		return payment
+	}
+	pricing, err := c.pricingRepository.GetByRentalID(ctx, rentalID)
+	if err != nil || pricing == nil || pricing.DueDay == 0 {
+		return payment
+	}
+	// The due day is counted in the timezone of the organization of the property
+	loc := service.AppLocation()
+	if rental, err := c.rentalRepository.GetByID(ctx, rentalID); err == nil && rental != nil {
+		loc = service.OrganizationLocation(c.orgService.ForProperty(ctx, rental.PropertyID))
+	}
+	payment.PaidOnTime = pricing.IsPaidOnTime(payment.PaymentDate.Time().In(loc))
+	return payment
+}
+
 // RegisterRoutes registers all routes for the rent payment controller
 func (c *RentPaymentController) RegisterRoutes(router *gin.RouterGroup) {
 	payments := router.Group("/payments")
@@ -59,17 +103,17 @@ func (c *RentPaymentController) RegisterRoutes(router *gin.RouterGroup) {
 func (c *RentPaymentController) GetAll(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view all payments"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForAllPayments"})
 		return
 	}
 
@@ -78,7 +122,7 @@ func (c *RentPaymentController) GetAll(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, payments)
+	ctx.JSON(http.StatusOK, paginate(ctx, payments))
 }
 
 // GetByID retrieves a rent payment by ID with authorization
@@ -96,19 +140,19 @@ func (c *RentPaymentController) GetAll(ctx *gin.Context) {
 func (c *RentPaymentController) GetByID(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	paymentID := ctx.Param("id")
 	payment, err := c.repository.GetByID(paymentID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Payment not found: " + err.Error()})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PaymentNotFound", "detail": err.Error()})
 		return
 	}
 
@@ -120,11 +164,11 @@ func (c *RentPaymentController) GetByID(ctx *gin.Context) {
 	// Check if the user (manager or resident) is associated with the rental of this payment
 	rental, err := c.rentalRepository.GetByID(ctx, uuid.MustParse(payment.RentalID)) // Assuming RentalID is UUID string
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve rental associated with payment"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetPaymentRental"})
 		return
 	}
 	if rental == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental associated with payment not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PaymentRentalNotFound"})
 		return
 	}
 
@@ -136,7 +180,7 @@ func (c *RentPaymentController) GetByID(ctx *gin.Context) {
 	if authUser.Role == "manager" {
 		property, err := c.propertyRepository.GetByID(ctx, rental.PropertyID)
 		if err != nil || property == nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify property for manager authorization"})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToVerifyManagerProperty"})
 			return
 		}
 		// Check if authUser.PersonID is one of the property.ManagerIDs
@@ -153,7 +197,7 @@ func (c *RentPaymentController) GetByID(ctx *gin.Context) {
 		}
 	}
 
-	ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this payment"})
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedToViewPayment"})
 }
 
 // GetByRentalIDs retrieves payments for a list of rental IDs (Admin/Manager)
@@ -172,18 +216,18 @@ func (c *RentPaymentController) GetByID(ctx *gin.Context) {
 func (c *RentPaymentController) GetByRentalIDs(ctx *gin.Context) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return
 	}
 
 	var requestedRentalIDs []string
 	if err := ctx.ShouldBindJSON(&requestedRentalIDs); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list of rental IDs: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDList", "detail": err.Error()})
 		return
 	}
 
@@ -197,13 +241,13 @@ func (c *RentPaymentController) GetByRentalIDs(ctx *gin.Context) {
 	// assuming the frontend logic correctly filters which IDs to request.
 	// More robust check: Fetch all rentals by IDs, then filter by manager's property ID.
 	if authUser.Role != "admin" && authUser.Role != "manager" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized for this operation"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForOperation"})
 		return
 	}
 
 	payments, err := c.repository.GetByRentalIDs(requestedRentalIDs)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payments: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchPayments", "detail": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, payments)
@@ -226,7 +270,7 @@ func (c *RentPaymentController) GetByDateRange(ctx *gin.Context) {
 	endDateStr := ctx.Query("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date query parameters are required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DateRangeRequired"})
 		return
 	}
 
@@ -239,7 +283,7 @@ func (c *RentPaymentController) GetByDateRange(ctx *gin.Context) {
 		// Try simple date format (YYYY-MM-DD)
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format. Use YYYY-MM-DD or RFC3339 format."})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidStartDateFormat"})
 			return
 		}
 	}
@@ -250,7 +294,7 @@ func (c *RentPaymentController) GetByDateRange(ctx *gin.Context) {
 		// Try simple date format (YYYY-MM-DD)
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format. Use YYYY-MM-DD or RFC3339 format."})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidEndDateFormat"})
 			return
 		}
 	}
@@ -276,27 +320,32 @@ func (c *RentPaymentController) GetLatePayments(ctx *gin.Context) {
 
 // Create creates a new rent payment
 func (c *RentPaymentController) Create(ctx *gin.Context) {
-	var payment storage.RentPayment
-	if err := ctx.ShouldBindJSON(&payment); err != nil {
+	var req rentPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	createdPayment, err := c.repository.Create(&payment)
+	payment := c.payment(ctx, req)
+	createdPayment, err := service.GetDocumentNumbering().CreatePayment(ctx, c.repository, &payment)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	service.GetEInvoices().AutoInvoice(createdPayment.ID)
+	service.GetNotifications().PaymentReceived(*createdPayment)
 	ctx.JSON(http.StatusCreated, createdPayment)
 }
 
 // Update updates an existing rent payment
 func (c *RentPaymentController) Update(ctx *gin.Context) {
 	id := ctx.Param("id")
-	var payment storage.RentPayment
-	if err := ctx.ShouldBindJSON(&payment); err != nil {
+	var req rentPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	payment := c.payment(ctx, req)
 	updatedPayment, err := c.repository.Update(id, &payment)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
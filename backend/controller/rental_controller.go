@@ -1,24 +1,25 @@
 package controller
 
 import (
-	"log"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // RentalController handles HTTP requests for rental entities
 type RentalController struct {
-	repository   *storage.RentalRepository
-	propertyRepo *storage.PropertyRepository // Added for manager logic
+	repository   storage.RentalStore
+	propertyRepo storage.PropertyStore // Added for manager logic
 }
 
 // NewRentalController creates a new RentalController
-func NewRentalController(repository *storage.RentalRepository, propertyRepo *storage.PropertyRepository) *RentalController {
+func NewRentalController(repository storage.RentalStore, propertyRepo storage.PropertyStore) *RentalController {
 	return &RentalController{
 		repository:   repository,
 		propertyRepo: propertyRepo,
@@ -49,12 +50,12 @@ func (c *RentalController) GetAll(ctx *gin.Context) {
 		rentals, err = c.repository.GetAll(ctx)
 	case "manager":
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Manager PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ManagerPersonIDMissing"})
 			return
 		}
 		managedProperties, propErr := c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
 		if propErr != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch managed properties: " + propErr.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedProperties", "detail": propErr.Error()})
 			return
 		}
 		if len(managedProperties) == 0 {
@@ -62,31 +63,21 @@ func (c *RentalController) GetAll(ctx *gin.Context) {
 			return
 		}
 
-		var allRentalsForManager []model.Rental
-		for _, prop := range managedProperties {
-			rentalsOnProp, rentalErr := c.repository.GetByPropertyID(ctx, prop.ID)
-			if rentalErr != nil {
-				log.Printf("Error fetching rentals for property %s: %v. Skipping.", prop.ID.String(), rentalErr)
-				// Decide if one error should fail all, or just skip this property's rentals
-				continue
-			}
-			allRentalsForManager = append(allRentalsForManager, rentalsOnProp...)
-		}
-		rentals = allRentalsForManager
+		rentals, err = c.repository.GetByPropertyIDs(ctx, model.PropertyIDs(managedProperties))
 	default: // Other roles, including residents, are forbidden from this specific GetAll endpoint.
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view all rentals via this endpoint."})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForAllRentals"})
 		return
 	}
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rentals: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveRentals", "detail": err.Error()})
 		return
 	}
 
 	if rentals == nil {
 		rentals = []model.Rental{}
 	}
-	ctx.JSON(http.StatusOK, rentals)
+	ctx.JSON(http.StatusOK, paginate(ctx, rentals))
 }
 
 // GetByID retrieves a rental by ID
@@ -102,7 +93,7 @@ func (c *RentalController) GetAll(ctx *gin.Context) {
 func (c *RentalController) GetByID(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -113,7 +104,7 @@ func (c *RentalController) GetByID(ctx *gin.Context) {
 	}
 
 	if rental == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
 		return
 	}
 
@@ -132,7 +123,7 @@ func (c *RentalController) GetByID(ctx *gin.Context) {
 func (c *RentalController) GetByPropertyID(ctx *gin.Context) {
 	propertyID, err := uuid.Parse(ctx.Param("propertyId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -157,7 +148,7 @@ func (c *RentalController) GetByPropertyID(ctx *gin.Context) {
 func (c *RentalController) GetByRenterID(ctx *gin.Context) {
 	renterID, err := uuid.Parse(ctx.Param("renterId"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -214,6 +205,10 @@ func (c *RentalController) Create(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if createdRental != nil {
+		rental = *createdRental // The insert does not always return the row
+	}
+	service.GetRentalHistory().RentalCreated(rental)
 
 	ctx.JSON(http.StatusCreated, createdRental)
 }
@@ -226,13 +221,16 @@ func (c *RentalController) Create(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Rental ID"
 // @Param rental body model.Rental true "Rental object"
+// @Param If-Match header string false "Version of the rental, instead of the version of the body"
 // @Success 200 {object} model.Rental
 // @Failure 404 {object} string "Rental not found"
+// @Failure 409 {object} object "The rental changed since its version was read"
+// @Failure 428 {object} string "The version of the rental was not sent"
 // @Router /rentals/{id} [put]
 func (c *RentalController) Update(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -244,17 +242,40 @@ func (c *RentalController) Update(ctx *gin.Context) {
 
 	// Ensure the ID in the URL matches the ID in the body
 	rental.ID = id
+	if !requireVersion(ctx, &rental.Version) {
+		return
+	}
+
+	// Moving the end date before the agreed one terminates the rental early
+	previous, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if previous == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return
+	}
 
 	updatedRental, err := c.repository.Update(ctx, rental)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		current, _ := c.repository.GetByID(ctx, id)
+		respondVersionConflict(ctx, current)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	if updatedRental == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
 		return
 	}
+	endDate, agreedEnd := updatedRental.EndDate.Time(), previous.EndDate.Time()
+	if !endDate.IsZero() && endDate.Before(agreedEnd) {
+		service.GetRentalHistory().RentalTerminated(*updatedRental, endDate, agreedEnd)
+	}
 
 	ctx.JSON(http.StatusOK, updatedRental)
 }
@@ -271,7 +292,7 @@ func (c *RentalController) Update(ctx *gin.Context) {
 func (c *RentalController) Delete(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -6,42 +6,50 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // RentalHistoryController handles HTTP requests for rental history
 type RentalHistoryController struct {
-	repository   *storage.RentalHistoryRepository
-	rentalRepo   *storage.RentalRepository
-	propertyRepo *storage.PropertyRepository
-	personRepo   *storage.PersonRepository // Added for fetching person details if needed
+	repository   storage.RentalHistoryStore
+	rentalRepo   storage.RentalStore
+	propertyRepo storage.PropertyStore
+	personRepo   storage.PersonStore // Added for fetching person details if needed
+	pricingRepo  storage.PricingStore
+	orgService   *service.OrganizationService
 }
 
 // NewRentalHistoryController creates a new rental history controller
 func NewRentalHistoryController(
-	repository *storage.RentalHistoryRepository,
-	rentalRepo *storage.RentalRepository,
-	propertyRepo *storage.PropertyRepository,
-	personRepo *storage.PersonRepository,
+	repository storage.RentalHistoryStore,
+	rentalRepo storage.RentalStore,
+	propertyRepo storage.PropertyStore,
+	personRepo storage.PersonStore,
+	pricingRepo storage.PricingStore,
+	orgService *service.OrganizationService,
 ) *RentalHistoryController {
 	return &RentalHistoryController{
 		repository:   repository,
 		rentalRepo:   rentalRepo,
 		propertyRepo: propertyRepo,
 		personRepo:   personRepo,
+		pricingRepo:  pricingRepo,
+		orgService:   orgService,
 	}
 }
 
 func getAuthenticatedUser(ctx *gin.Context) (*model.User, bool) {
 	userInterface, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 		return nil, false
 	}
 	authUser, ok := userInterface.(*model.User)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 		return nil, false
 	}
 	return authUser, true
@@ -72,14 +80,20 @@ func (c *RentalHistoryController) RegisterRoutes(router *gin.RouterGroup) {
 // Admins can filter by status or date_range query parameters.
 // Managers get history for rentals on their managed properties.
 // Residents get history for their own rentals.
+// Every list accepts the limit, offset, sort and filter[column] query parameters.
 func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 	authUser, ok := getAuthenticatedUser(ctx)
 	if !ok {
 		return
 	}
 
+	opts, err := listOptionsFromQuery(ctx, storage.RentalHistoryListColumns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var histories []storage.RentalHistory
-	var err error
 
 	switch authUser.Role {
 	case "admin":
@@ -100,26 +114,33 @@ func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 			}
 			startDate, err = parseAdminDate(startDateStr)
 			if err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format for admin filter."})
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidAdminStartDate"})
 				return
 			}
 			endDate, err = parseAdminDate(endDateStr)
 			if err != nil {
-				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format for admin filter."})
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidAdminEndDate"})
 				return
 			}
 			histories, err = c.repository.GetRentalHistoryByDateRange(startDate, endDate)
 		} else {
-			histories, err = c.repository.GetAll() // Admin gets all if no filters
+			// Admin gets all if no filters, paginated, sorted and filtered by the database
+			page, total, err := c.repository.List(opts)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveRentalHistories", "detail": err.Error()})
+				return
+			}
+			respondPage(ctx, page, total)
+			return
 		}
 	case "manager":
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Manager PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ManagerPersonIDMissing"})
 			return
 		}
 		managedProperties, propErr := c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
 		if propErr != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch managed properties: " + propErr.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedProperties", "detail": propErr.Error()})
 			return
 		}
 		if len(managedProperties) == 0 {
@@ -127,17 +148,14 @@ func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 			return
 		}
 
+		rentalsOnProps, rentalErr := c.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(managedProperties))
+		if rentalErr != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedPropertiesRentals", "detail": rentalErr.Error()})
+			return
+		}
 		var allRentalIDs []string
-		for _, prop := range managedProperties {
-			rentalsOnProp, rentalErr := c.rentalRepo.GetByPropertyID(ctx, prop.ID)
-			if rentalErr != nil {
-				// Log error and continue, or decide to fail all
-				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching rentals for property " + prop.ID.String() + ": " + rentalErr.Error()})
-				return
-			}
-			for _, rental := range rentalsOnProp {
-				allRentalIDs = append(allRentalIDs, rental.ID.String())
-			}
+		for _, rental := range rentalsOnProps {
+			allRentalIDs = append(allRentalIDs, rental.ID.String())
 		}
 
 		if len(allRentalIDs) == 0 {
@@ -147,12 +165,12 @@ func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 		histories, err = c.repository.GetByRentalIDs(allRentalIDs)
 	case "resident":
 		if authUser.PersonID == uuid.Nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Resident PersonID not found in token"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ResidentPersonIDMissing"})
 			return
 		}
 		userRentals, rentalErr := c.rentalRepo.GetByRenterID(ctx, authUser.PersonID)
 		if rentalErr != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user rentals: " + rentalErr.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchUserRentals", "detail": rentalErr.Error()})
 			return
 		}
 		if len(userRentals) == 0 {
@@ -165,12 +183,12 @@ func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 		}
 		histories, err = c.repository.GetByRentalIDs(userRentalIDs)
 	default:
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view rental history"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForRentalHistory"})
 		return
 	}
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rental histories: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRetrieveRentalHistories", "detail": err.Error()})
 		return
 	}
 
@@ -178,7 +196,7 @@ func (c *RentalHistoryController) GetAll(ctx *gin.Context) {
 		histories = []storage.RentalHistory{}
 	}
 
-	ctx.JSON(http.StatusOK, histories)
+	ctx.JSON(http.StatusOK, paginateList(ctx, histories, opts))
 }
 
 // GetByID retrieves a rental history record by ID
@@ -191,18 +209,18 @@ func (c *RentalHistoryController) GetByID(ctx *gin.Context) {
 	historyIDStr := ctx.Param("id")
 	// _, err := uuid.Parse(historyIDStr) // Validate if it's a UUID, though repo might handle non-UUID string IDs
 	// if err != nil {
-	// 	ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid history ID format"})
+	// 	ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidHistoryIDFormat"})
 	// 	return
 	// }
 
 	history, err := c.repository.GetByID(historyIDStr)
 	if err != nil {
 		// Distinguish between not found and other errors if repository supports it
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental history not found or error fetching: " + err.Error()})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "FailedToGetRentalHistoryRecord", "detail": err.Error()})
 		return
 	}
 	if history == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental history not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalHistoryNotFound"})
 		return
 	}
 
@@ -233,7 +251,7 @@ func (c *RentalHistoryController) GetByID(ctx *gin.Context) {
 	}
 
 	if !isAllowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this rental history record"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForRentalHistoryRecord"})
 		return
 	}
 
@@ -250,7 +268,7 @@ func (c *RentalHistoryController) GetByPersonID(ctx *gin.Context) {
 	targetPersonIDStr := ctx.Param("personId")
 	targetPersonID_uuid, err := uuid.Parse(targetPersonIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonIDFormat"})
 		return
 	}
 
@@ -268,7 +286,7 @@ func (c *RentalHistoryController) GetByPersonID(ctx *gin.Context) {
 	}
 
 	if !isAllowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view rental history for this person"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForPersonRentalHistory"})
 		return
 	}
 
@@ -290,7 +308,7 @@ func (c *RentalHistoryController) GetByRentalID(ctx *gin.Context) {
 	targetRentalIDStr := ctx.Param("rentalId")
 	targetRentalID_uuid, err := uuid.Parse(targetRentalIDStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rental ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
 		return
 	}
 
@@ -301,7 +319,7 @@ func (c *RentalHistoryController) GetByRentalID(ctx *gin.Context) {
 		// Fetch the rental to check its associations
 		rental, rentalErr := c.rentalRepo.GetByID(ctx, targetRentalID_uuid)
 		if rentalErr != nil || rental == nil {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "Rental not found or error fetching rental"})
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "FailedToGetRentalRecord"})
 			return
 		}
 
@@ -320,7 +338,7 @@ func (c *RentalHistoryController) GetByRentalID(ctx *gin.Context) {
 					}
 				}
 				if !isManagerForThisProperty {
-					ctx.JSON(http.StatusForbidden, gin.H{"error": "Manager not authorized for this rental's history"})
+					ctx.JSON(http.StatusForbidden, gin.H{"error": "ManagerNotAuthorizedForRentalHistory"})
 					return
 				}
 			}
@@ -328,7 +346,7 @@ func (c *RentalHistoryController) GetByRentalID(ctx *gin.Context) {
 	}
 
 	if !isAllowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view rental history for this rental"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "NotAuthorizedForRentalHistoryOfRental"})
 		return
 	}
 
@@ -354,7 +372,7 @@ func (c *RentalHistoryController) GetMultipleByRentalIDs(ctx *gin.Context) {
 
 	var input GetMultipleByRentalIDsInput
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
 		return
 	}
 
@@ -370,7 +388,7 @@ func (c *RentalHistoryController) GetMultipleByRentalIDs(ctx *gin.Context) {
 	} else if authUser.Role == "resident" {
 		userRentals, err := c.rentalRepo.GetByRenterID(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user rentals: " + err.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchUserRentals", "detail": err.Error()})
 			return
 		}
 		userRentalIDMap := make(map[string]bool)
@@ -385,20 +403,18 @@ func (c *RentalHistoryController) GetMultipleByRentalIDs(ctx *gin.Context) {
 	} else if authUser.Role == "manager" {
 		managedProperties, err := c.propertyRepo.GetPropertiesForManager(ctx, authUser.PersonID)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch managed properties: " + err.Error()})
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedProperties", "detail": err.Error()})
 			return
 		}
 
 		rentalsOnManagedPropertiesMap := make(map[string]bool)
-		for _, prop := range managedProperties {
-			rentals, err := c.rentalRepo.GetByPropertyID(ctx, prop.ID)
-			if err != nil {
-				// Log or handle error, maybe continue to next property
-				continue
-			}
-			for _, r := range rentals {
-				rentalsOnManagedPropertiesMap[r.ID.String()] = true
-			}
+		rentals, err := c.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(managedProperties))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchManagedRentals", "detail": err.Error()})
+			return
+		}
+		for _, r := range rentals {
+			rentalsOnManagedPropertiesMap[r.ID.String()] = true
 		}
 		for _, reqID := range input.RentalIDs {
 			if rentalsOnManagedPropertiesMap[reqID] {
@@ -414,7 +430,7 @@ func (c *RentalHistoryController) GetMultipleByRentalIDs(ctx *gin.Context) {
 
 	histories, err := c.repository.GetByRentalIDs(allowedRentalIDs)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rental histories: " + err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToFetchRentalHistories", "detail": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, histories)
@@ -427,7 +443,7 @@ func (c *RentalHistoryController) GetByStatus(ctx *gin.Context) {
 		return
 	}
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminAccessRequired"})
 		return
 	}
 
@@ -447,7 +463,7 @@ func (c *RentalHistoryController) GetByDateRange(ctx *gin.Context) {
 		return
 	}
 	if authUser.Role != "admin" {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "AdminAccessRequired"})
 		return
 	}
 
@@ -455,7 +471,7 @@ func (c *RentalHistoryController) GetByDateRange(ctx *gin.Context) {
 	endDateStr := ctx.Query("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date query parameters are required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DateRangeRequired"})
 		return
 	}
 
@@ -472,13 +488,13 @@ func (c *RentalHistoryController) GetByDateRange(ctx *gin.Context) {
 
 	startDate, err = parseDate(startDateStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format. Use YYYY-MM-DD or RFC3339 format."})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidStartDateFormat"})
 		return
 	}
 
 	endDate, err = parseDate(endDateStr)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format. Use YYYY-MM-DD or RFC3339 format."})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidEndDateFormat"})
 		return
 	}
 
@@ -502,6 +518,7 @@ func (c *RentalHistoryController) Create(ctx *gin.Context) {
 	// Add explicit admin check here if not solely relying on router middleware,
 	// or if this method is registered outside admin group.
 	// For now, assume http_controller.go handles admin restriction.
+	history.RecordedAt = nil // Set by the database
 
 	createdHistory, err := c.repository.Create(&history)
 	if err != nil {
@@ -522,6 +539,7 @@ func (c *RentalHistoryController) Update(ctx *gin.Context) {
 		return
 	}
 	// Add explicit admin check here if needed.
+	history.RecordedAt = nil // Keeps the time the record was written
 
 	updatedHistory, err := c.repository.Update(id, &history)
 	if err != nil {
@@ -544,3 +562,133 @@ func (c *RentalHistoryController) Delete(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Rental history record deleted successfully"})
 }
+
+// GetPropertyOccupancy returns the occupancy timeline of a unit: its rentals with tenant, dates
+// and canon, the vacant periods between them and the occupancy rate
+// @Summary Occupancy timeline of a property
+// @Description Rentals closed early (transfers) end on the date recorded in the rental history. The range defaults to the first rental until today.
+// @Tags rental-history
+// @Produce json
+// @Param id path string true "Property ID"
+// @Param from query string false "Start of the range (YYYY-MM-DD)"
+// @Param to query string false "End of the range (YYYY-MM-DD), today by default"
+// @Success 200 {object} service.OccupancyTimeline
+// @Router /properties/{id}/occupancy [get]
+func (c *RentalHistoryController) GetPropertyOccupancy(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	propertyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPropertyIDFormat"})
+		return
+	}
+
+	property, err := c.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if property == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PropertyNotFound"})
+		return
+	}
+	if authUser.Role != "admin" && (authUser.Role != "manager" || !managesProperty(*property, authUser.PersonID)) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersSeeOccupancy"})
+		return
+	}
+
+	// Days are counted in the timezone of the organization of the property
+	loc := service.OrganizationLocation(c.orgService.ForProperty(ctx, propertyID))
+	now := time.Now().In(loc)
+	var from, to time.Time
+	to = now
+	for param, target := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := ctx.Query(param); value != "" {
+			date, err := time.ParseInLocation("2006-01-02", value, loc)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDateParameter", "params": gin.H{"Param": param}})
+				return
+			}
+			*target = date
+		}
+	}
+
+	rentals, err := c.rentalRepo.GetByPropertyID(ctx, propertyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rentalIDs := make([]string, len(rentals))
+	for i, rental := range rentals {
+		rentalIDs[i] = rental.ID.String()
+	}
+	histories, err := c.repository.GetByRentalIDs(rentalIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching the rental history of property", "property_id", propertyID, "error", err)
+	}
+	closed := make(map[string]storage.RentalHistory, len(histories))
+	for _, history := range histories {
+		if history.Status != model.RentalHistoryActive {
+			closed[history.RentalID] = history // The last closing record of a rental closes it
+		}
+	}
+
+	// The tenants and rents of every rental are fetched at once
+	renterIDs := make([]uuid.UUID, len(rentals))
+	rentalUUIDs := make([]uuid.UUID, len(rentals))
+	for i, rental := range rentals {
+		renterIDs[i] = rental.RenterID
+		rentalUUIDs[i] = rental.ID
+	}
+	tenantNames := make(map[uuid.UUID]string)
+	renters, err := c.personRepo.GetByIDs(ctx, renterIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching the tenants of property", "property_id", propertyID, "error", err)
+	}
+	for _, renter := range renters {
+		tenantNames[renter.ID] = renter.FullName
+	}
+	monthlyRents := make(map[uuid.UUID]float64)
+	pricings, err := c.pricingRepo.GetByRentalIDs(ctx, rentalUUIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching the pricing of property", "property_id", propertyID, "error", err)
+	}
+	for _, pricing := range pricings {
+		monthlyRents[pricing.RentalID] = pricing.MonthlyRent
+	}
+
+	periods := make([]service.OccupancyPeriod, 0, len(rentals))
+	for _, rental := range rentals {
+		period := service.OccupancyPeriod{
+			RentalID:  rental.ID,
+			RenterID:  rental.RenterID,
+			StartDate: time.Time(rental.StartDate),
+			EndDate:   time.Time(rental.EndDate),
+			Status:    service.OccupancyStatus(time.Time(rental.StartDate), time.Time(rental.EndDate), now, loc),
+		}
+		if history, ok := closed[rental.ID.String()]; ok {
+			period.Status = history.Status
+			period.EndReason = history.EndReason
+			if endDate := time.Time(history.EndDate); !endDate.IsZero() && endDate.Before(period.EndDate) {
+				period.EndDate = endDate
+			}
+		}
+
+		period.TenantName = tenantNames[rental.RenterID]
+		period.MonthlyRent = monthlyRents[rental.ID]
+
+		periods = append(periods, period)
+		if ctx.Query("from") == "" && (from.IsZero() || period.StartDate.Before(from)) {
+			from = period.StartDate
+		}
+	}
+	if from.IsZero() {
+		from = to // No rentals, the range is the last day only
+	}
+
+	ctx.JSON(http.StatusOK, service.BuildOccupancyTimeline(propertyID, periods, from, to, loc))
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// RentalPartyController handles the parties of the rental contracts: additional renters,
+// cosigners, witnesses and the owner printed in the contracts and asked to sign them
+type RentalPartyController struct {
+	repository storage.RentalPartyStore
+	rentalRepo storage.RentalStore
+	personRepo storage.PersonStore
+	userRepo   storage.UserStore
+}
+
+// NewRentalPartyController creates a new RentalPartyController
+func NewRentalPartyController(
+	repository storage.RentalPartyStore,
+	rentalRepo storage.RentalStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+) *RentalPartyController {
+	return &RentalPartyController{
+		repository: repository,
+		rentalRepo: rentalRepo,
+		personRepo: personRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// RentalPartyRequest links a person to a rental with a role
+type RentalPartyRequest struct {
+	PersonID string `json:"person_id" binding:"required"`
+	Role     string `json:"role" binding:"required"` // renter, co_signer, witness or owner
+}
+
+// RentalPartyResponse is a party of a rental with the person and contact data printed in the contract
+type RentalPartyResponse struct {
+	model.RentalParty
+	RoleLabel string        `json:"role_label"`
+	Person    *model.Person `json:"person,omitempty"`
+	Email     string        `json:"email,omitempty"`
+}
+
+// RegisterAdminRoutes registers the rental party routes on an admin-protected group
+func (c *RentalPartyController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.GET("/contracts/:id/parties", c.GetByRentalID)
+	adminRouter.POST("/contracts/:id/parties", c.Create)
+	adminRouter.DELETE("/contracts/:id/parties/:partyId", c.Delete)
+}
+
+// GetByRentalID lists the parties of a rental
+// @Summary List the parties of a rental contract
+// @Tags rental-parties
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {array} RentalPartyResponse
+// @Router /admin/contracts/{id}/parties [get]
+func (c *RentalPartyController) GetByRentalID(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	parties, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]RentalPartyResponse, 0, len(parties))
+	for _, party := range parties {
+		response = append(response, c.partyResponse(ctx, party))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Create links a person to a rental with a role
+// @Summary Add a party to a rental contract
+// @Description The cosigner and witness are printed in the contracts of the rental and the cosigners are asked to sign its renewals. An afianzadora policy substitutes the cosigner.
+// @Tags rental-parties
+// @Accept json
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Param party body RentalPartyRequest true "Person and role"
+// @Success 201 {object} RentalPartyResponse
+// @Failure 409 {object} map[string]string "The person already has the role in the rental"
+// @Router /admin/contracts/{id}/parties [post]
+func (c *RentalPartyController) Create(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	var req RentalPartyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !model.IsValidRentalPartyRole(req.Role) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPartyRole"})
+		return
+	}
+	personID, err := uuid.Parse(req.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPersonID"})
+		return
+	}
+
+	rental, err := c.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rental == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return
+	}
+
+	person, err := c.personRepo.GetByID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if person == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "PersonNotFound"})
+		return
+	}
+
+	// The renter of the rental cannot guarantee or witness their own contract
+	if personID == rental.RenterID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "PartyIsRenter"})
+		return
+	}
+
+	parties, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, party := range parties {
+		if party.PersonID == personID && party.Role == req.Role {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "PartyRoleAlreadyAssigned"})
+			return
+		}
+		if req.Role == model.RentalPartyOwner && party.Role == model.RentalPartyOwner {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "RentalAlreadyHasOwner"})
+			return
+		}
+	}
+
+	created, err := c.repository.Create(ctx, model.RentalParty{
+		RentalID: rentalID,
+		PersonID: personID,
+		Role:     req.Role,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveRentalParty"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("added to rental", "role", created.Role, "person_id", personID, "rental_id", rentalID)
+	ctx.JSON(http.StatusCreated, c.partyResponse(ctx, *created))
+}
+
+// Delete removes a party from a rental. Contracts already signed keep the parties they were
+// signed with.
+func (c *RentalPartyController) Delete(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+	partyID, err := uuid.Parse(ctx.Param("partyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidPartyIDFormat"})
+		return
+	}
+
+	party, err := c.repository.GetByID(ctx, partyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if party == nil || party.RentalID != rentalID {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalPartyNotFound"})
+		return
+	}
+
+	if err := c.repository.Delete(ctx, partyID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// partyResponse adds the person, role label and email to a party
+func (c *RentalPartyController) partyResponse(ctx *gin.Context, party model.RentalParty) RentalPartyResponse {
+	response := RentalPartyResponse{
+		RentalParty: party,
+		RoleLabel:   model.RentalPartyRoleLabels[party.Role],
+	}
+	if person, err := c.personRepo.GetByID(ctx, party.PersonID); err == nil {
+		response.Person = person
+	}
+	if user, err := c.userRepo.GetByPersonID(ctx, party.PersonID); err == nil && user != nil {
+		response.Email = user.Email
+	}
+	return response
+}
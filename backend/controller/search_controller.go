@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultSearchLimit and maxSearchLimit bound the results returned by a search
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	// searchFetchLimit is how many matches are fetched when they are filtered afterwards by role or type
+	searchFetchLimit = 200
+)
+
+// SearchController handles the full-text search across persons, properties, rentals and files
+type SearchController struct {
+	repository   storage.SearchStore
+	propertyRepo storage.PropertyStore
+	rentalRepo   storage.RentalStore
+}
+
+// NewSearchController creates a new SearchController
+func NewSearchController(repository storage.SearchStore, propertyRepo storage.PropertyStore, rentalRepo storage.RentalStore) *SearchController {
+	return &SearchController{
+		repository:   repository,
+		propertyRepo: propertyRepo,
+		rentalRepo:   rentalRepo,
+	}
+}
+
+// searchScope holds the records a non-admin user can find
+type searchScope struct {
+	persons    map[uuid.UUID]bool
+	properties map[uuid.UUID]bool
+	rentals    map[uuid.UUID]bool
+}
+
+// RegisterRoutes registers the search route of the authenticated user
+func (c *SearchController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/search", c.Search)
+}
+
+// Search finds persons, properties, rentals and files by text
+// @Summary Full-text search
+// @Description Every word of q is matched as a prefix, ignoring case and accents. Admins search every record, managers the records of the properties they manage and the other users their own properties, rentals and files.
+// @Tags search
+// @Produce json
+// @Param q query string true "Text to search, at least 2 characters"
+// @Param types query string false "Comma-separated result types: person, property, rental, file"
+// @Param limit query int false "Maximum results, 20 by default and 50 at most"
+// @Success 200 {array} model.SearchResult
+// @Router /search [get]
+func (c *SearchController) Search(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(ctx.Query("q"))
+	if len([]rune(query)) < 2 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "SearchQueryTooShort"})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := ctx.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "LimitMustBePositive"})
+			return
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+
+	types := map[string]bool{}
+	if value := ctx.Query("types"); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			t = strings.TrimSpace(t)
+			if !model.IsValidSearchResultType(t) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidSearchTypes"})
+				return
+			}
+			types[t] = true
+		}
+	}
+
+	var scope *searchScope
+	if authUser.Role != "admin" {
+		var err error
+		if scope, err = c.userScope(ctx, authUser); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	fetch := limit
+	if scope != nil || len(types) > 0 {
+		fetch = searchFetchLimit
+	}
+	matches, err := c.repository.Search(ctx, query, fetch)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "SearchFailed"})
+		return
+	}
+
+	results := make([]model.SearchResult, 0, limit)
+	for _, result := range matches {
+		if len(results) == limit {
+			break
+		}
+		if len(types) > 0 && !types[result.Type] {
+			continue
+		}
+		if scope != nil && !scope.canSee(result) {
+			continue
+		}
+		results = append(results, result)
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// userScope collects the persons, properties and rentals a manager or tenant can find: the
+// managed properties with their rentals and renters, or the own properties and rentals
+func (c *SearchController) userScope(ctx *gin.Context, user *model.User) (*searchScope, error) {
+	scope := &searchScope{
+		persons:    map[uuid.UUID]bool{},
+		properties: map[uuid.UUID]bool{},
+		rentals:    map[uuid.UUID]bool{},
+	}
+	if user.PersonID == uuid.Nil {
+		return scope, nil
+	}
+	scope.persons[user.PersonID] = true
+
+	if user.Role == "manager" {
+		properties, err := c.propertyRepo.GetPropertiesForManager(ctx, user.PersonID)
+		if err != nil {
+			return nil, err
+		}
+		for _, property := range properties {
+			scope.properties[property.ID] = true
+			if property.ResidentID != uuid.Nil {
+				scope.persons[property.ResidentID] = true
+			}
+		}
+		rentals, err := c.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(properties))
+		if err != nil {
+			return nil, err
+		}
+		for _, rental := range rentals {
+			scope.rentals[rental.ID] = true
+			scope.persons[rental.RenterID] = true
+		}
+		return scope, nil
+	}
+
+	properties, err := c.propertyRepo.GetByResident(ctx, user.PersonID)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range properties {
+		scope.properties[property.ID] = true
+	}
+	rentals, err := c.rentalRepo.GetByRenterID(ctx, user.PersonID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rental := range rentals {
+		scope.rentals[rental.ID] = true
+		scope.properties[rental.PropertyID] = true
+	}
+	return scope, nil
+}
+
+// canSee reports whether a search result belongs to the scope. Files are only found through
+// the rental they are attached to.
+func (s *searchScope) canSee(result model.SearchResult) bool {
+	switch result.Type {
+	case model.SearchResultPerson:
+		return s.persons[result.ID]
+	case model.SearchResultProperty:
+		return s.properties[result.ID]
+	case model.SearchResultRental:
+		return s.rentals[result.ID]
+	case model.SearchResultFile:
+		return result.RentalID != nil && s.rentals[*result.RentalID]
+	}
+	return false
+}
@@ -0,0 +1,905 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// maxDeductionReceiptSize is the largest receipt accepted for a deposit deduction (10 MB)
+const maxDeductionReceiptSize = 10 << 20
+
+// SecurityDepositController handles the security deposits of the rentals, the deductions
+// withheld from them and their refund at move-out
+type SecurityDepositController struct {
+	repository      storage.SecurityDepositStore
+	rentalRepo      storage.RentalStore
+	propertyRepo    storage.PropertyStore
+	personRepo      storage.PersonStore
+	userRepo        storage.UserStore
+	pricingRepo     storage.PricingStore
+	bankAccountRepo storage.BankAccountStore
+}
+
+// NewSecurityDepositController creates a new SecurityDepositController
+func NewSecurityDepositController(
+	repository storage.SecurityDepositStore,
+	rentalRepo storage.RentalStore,
+	propertyRepo storage.PropertyStore,
+	personRepo storage.PersonStore,
+	userRepo storage.UserStore,
+	pricingRepo storage.PricingStore,
+	bankAccountRepo storage.BankAccountStore,
+) *SecurityDepositController {
+	return &SecurityDepositController{
+		repository:      repository,
+		rentalRepo:      rentalRepo,
+		propertyRepo:    propertyRepo,
+		personRepo:      personRepo,
+		userRepo:        userRepo,
+		pricingRepo:     pricingRepo,
+		bankAccountRepo: bankAccountRepo,
+	}
+}
+
+// SecurityDepositRequest defines the deposit received for a rental. The amount defaults to the
+// security deposit of the rental's pricing and the reception date to the start of the rental.
+type SecurityDepositRequest struct {
+	Amount        *float64 `json:"amount"`
+	HeldBy        string   `json:"held_by"`         // owner (default), manager or escrow
+	BankAccountID string   `json:"bank_account_id"` // Account the deposit is kept in
+	ReceivedAt    string   `json:"received_at"`     // YYYY-MM-DD
+	Notes         string   `json:"notes"`
+}
+
+// IssueSettlementRequest defines the date the tenant handed over the property
+type IssueSettlementRequest struct {
+	MoveOutDate string `json:"move_out_date" binding:"required"` // YYYY-MM-DD
+}
+
+// RefundDepositRequest records the transfer that returned the deposit to the tenant
+type RefundDepositRequest struct {
+	Reference  string `json:"reference" binding:"required"`
+	RefundedAt string `json:"refunded_at"` // YYYY-MM-DD, defaults to today
+}
+
+// RegisterRoutes registers the deposit routes of the tenants
+func (c *SecurityDepositController) RegisterRoutes(router *gin.RouterGroup) {
+	deposits := router.Group("/deposits")
+	{
+		deposits.GET("/me", c.GetMine)
+		deposits.GET("/:id/settlement", c.ServeSettlement)
+		deposits.POST("/:id/settlement/sign", c.SignSettlement)
+	}
+}
+
+// RegisterAdminRoutes registers the deposit management routes on an admin-protected group
+func (c *SecurityDepositController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.GET("/contracts/:id/deposit", c.GetByRentalID)
+	adminRouter.POST("/contracts/:id/deposit", c.Create)
+
+	deposits := adminRouter.Group("/deposits")
+	{
+		deposits.GET("/deduction-categories", c.GetDeductionCategories)
+		deposits.PUT("/:id", c.Update)
+		deposits.POST("/:id/deductions", c.AddDeduction)
+		deposits.DELETE("/:id/deductions/:deductionId", c.RemoveDeduction)
+		deposits.POST("/:id/settlement", c.IssueSettlement)
+		deposits.POST("/:id/refund", c.Refund)
+	}
+}
+
+// RegisterPublicRoutes registers the verification of signed settlements, opened from their QR code
+func (c *SecurityDepositController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/public/deposit-settlements/verify/:id", c.VerifySettlement)
+}
+
+// GetByRentalID retrieves the security deposit of a rental
+// @Summary Get the security deposit of a rental
+// @Tags deposits
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string "No deposit recorded"
+// @Router /admin/contracts/{id}/deposit [get]
+func (c *SecurityDepositController) GetByRentalID(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	deposit, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if deposit == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NoSecurityDepositRecorded"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, depositResponse(*deposit))
+}
+
+// Create records the security deposit received for a rental
+// @Summary Record the security deposit of a rental
+// @Tags deposits
+// @Accept json
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Param deposit body SecurityDepositRequest true "Deposit"
+// @Success 201 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Deposit already recorded"
+// @Router /admin/contracts/{id}/deposit [post]
+func (c *SecurityDepositController) Create(ctx *gin.Context) {
+	rentalID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRentalIDFormat"})
+		return
+	}
+
+	var req SecurityDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	rental, err := c.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rental == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "RentalNotFound"})
+		return
+	}
+
+	existing, err := c.repository.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing != nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "SecurityDepositAlreadyRecorded", "deposit": depositResponse(*existing)})
+		return
+	}
+
+	deposit := model.SecurityDeposit{
+		ID:         uuid.New(),
+		RentalID:   rental.ID,
+		HeldBy:     model.DepositHolderOwner,
+		ReceivedAt: rental.StartDate.Time(),
+		Deductions: []model.DepositDeduction{},
+		Status:     model.DepositStatusHeld,
+	}
+	if pricing, err := c.pricingRepo.GetByRentalID(ctx, rental.ID); err == nil && pricing != nil {
+		deposit.Amount = pricing.SecurityDeposit
+	}
+	if !c.applyDepositRequest(ctx, req, &deposit) {
+		return
+	}
+
+	created, err := c.repository.Create(ctx, deposit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveSecurityDeposit"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("security deposit recorded", "amount", service.FormatMoney(created.Amount), "rental_id", rental.ID)
+	ctx.JSON(http.StatusCreated, depositResponse(*created))
+}
+
+// Update edits the amount, holder, account or notes of a security deposit
+// @Summary Update a security deposit
+// @Description Editing a deposit whose settlement was already sent discards the settlement, which must be issued again
+// @Tags deposits
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param deposit body SecurityDepositRequest true "Deposit"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Settlement already signed"
+// @Router /admin/deposits/{id} [put]
+func (c *SecurityDepositController) Update(ctx *gin.Context) {
+	deposit, ok := c.editableDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	var req SecurityDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !c.applyDepositRequest(ctx, req, deposit) {
+		return
+	}
+
+	c.save(ctx, deposit, http.StatusOK)
+}
+
+// GetDeductionCategories lists the categories a deduction can be recorded under
+func (c *SecurityDepositController) GetDeductionCategories(ctx *gin.Context) {
+	categories := make([]gin.H, 0, len(model.DeductionCategories))
+	for _, category := range model.DeductionCategories {
+		categories = append(categories, gin.H{"value": category, "label": service.DeductionCategoryLabel(category)})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// AddDeduction withholds an amount from a security deposit, with its reason and an optional
+// receipt uploaded as the multipart field "file"
+// @Summary Record a deduction from a security deposit
+// @Tags deposits
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param category formData string true "damages, unpaid_rent, utilities, cleaning or other"
+// @Param reason formData string true "Reason of the deduction"
+// @Param amount formData number true "Amount withheld"
+// @Param file formData file false "Invoice or quote backing the deduction"
+// @Success 201 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Settlement already signed"
+// @Router /admin/deposits/{id}/deductions [post]
+func (c *SecurityDepositController) AddDeduction(ctx *gin.Context) {
+	deposit, ok := c.editableDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	category := ctx.PostForm("category")
+	if !model.IsValidDeductionCategory(category) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDeductionCategory", "detail": strings.Join(model.DeductionCategories, ", ")})
+		return
+	}
+	reason := strings.TrimSpace(ctx.PostForm("reason"))
+	if reason == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ReasonRequired"})
+		return
+	}
+	amount, err := strconv.ParseFloat(ctx.PostForm("amount"), 64)
+	if err != nil || amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "AmountMustBePositive"})
+		return
+	}
+
+	deduction := model.DepositDeduction{
+		ID:        uuid.New(),
+		Category:  category,
+		Reason:    reason,
+		Amount:    amount,
+		CreatedAt: time.Now(),
+	}
+	if authUser, exists := ctx.Get("user"); exists {
+		if user, ok := authUser.(*model.User); ok {
+			deduction.CreatedBy = user.PersonID
+		}
+	}
+
+	if file, header, err := ctx.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		if header.Size > maxDeductionReceiptSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ReceiptTooLarge"})
+			return
+		}
+		ext := strings.ToLower(filepath.Ext(header.Filename))
+		if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedReceiptType"})
+			return
+		}
+
+		storageService := service.GetSupabaseStorageService()
+		if storageService == nil {
+			respondCapabilityUnavailable(ctx, service.CapabilityFileStorage, "FileStorageUnavailable")
+			return
+		}
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadReceipt"})
+			return
+		}
+		contentType := header.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+
+		filePath := fmt.Sprintf("rentals/%s/deposito/%s%s", deposit.RentalID, deduction.ID, ext)
+		uploadResponse, err := storageService.UploadBytes(filePath, data, contentType)
+		if err != nil {
+			logging.FromContext(ctx).Error("error uploading deduction receipt for deposit", "deposit_id", deposit.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUploadReceipt"})
+			return
+		}
+		deduction.ReceiptPath = uploadResponse.Path
+	}
+
+	deposit.Deductions = append(deposit.Deductions, deduction)
+	c.save(ctx, deposit, http.StatusCreated)
+}
+
+// RemoveDeduction deletes a deduction recorded by mistake
+// @Summary Remove a deduction from a security deposit
+// @Tags deposits
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param deductionId path string true "Deduction ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/deposits/{id}/deductions/{deductionId} [delete]
+func (c *SecurityDepositController) RemoveDeduction(ctx *gin.Context) {
+	deposit, ok := c.editableDeposit(ctx)
+	if !ok {
+		return
+	}
+	deductionID, err := uuid.Parse(ctx.Param("deductionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDeductionIDFormat"})
+		return
+	}
+
+	deductions := make([]model.DepositDeduction, 0, len(deposit.Deductions))
+	for _, deduction := range deposit.Deductions {
+		if deduction.ID != deductionID {
+			deductions = append(deductions, deduction)
+		}
+	}
+	if len(deductions) == len(deposit.Deductions) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "DeductionNotFound"})
+		return
+	}
+	deposit.Deductions = deductions
+
+	c.save(ctx, deposit, http.StatusOK)
+}
+
+// IssueSettlement generates the move-out settlement of a deposit and sends it to the tenant to sign
+// @Summary Issue the move-out settlement of a deposit
+// @Description Generates the settlement PDF with the deductions and the balance to refund, stores it with the rental files and emails it to the tenant, who signs it from the platform
+// @Tags deposits
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param settlement body IssueSettlementRequest true "Move-out date"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/deposits/{id}/settlement [post]
+func (c *SecurityDepositController) IssueSettlement(ctx *gin.Context) {
+	deposit, ok := c.editableDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	var req IssueSettlementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	moveOutDate, err := time.ParseInLocation("2006-01-02", req.MoveOutDate, service.AppLocation())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidMoveOutDate"})
+		return
+	}
+
+	now := time.Now()
+	deposit.MoveOutDate = &moveOutDate
+	settlement, ok := c.settlementData(ctx, deposit, now)
+	if !ok {
+		return
+	}
+
+	settlementPDF, err := service.GenerateDepositSettlementPDF(*settlement)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating settlement of deposit", "deposit_id", deposit.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateSettlement"})
+		return
+	}
+	settlementPath, err := storeDepositSettlement(deposit, settlementPDF, "liquidacion")
+	if err != nil {
+		logging.FromContext(ctx).Error("error storing settlement of deposit", "deposit_id", deposit.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToStoreSettlement"})
+		return
+	}
+
+	deposit.Status = model.DepositStatusSettlementIssued
+	deposit.SettlementPath = settlementPath
+	deposit.SettlementIssuedAt = &now
+	updated, err := c.repository.Update(ctx, *deposit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveSecurityDeposit"})
+		return
+	}
+
+	if settlement.TenantEmail != "" {
+		address := settlement.Property.Address
+		if settlement.Property.AptNumber != "" {
+			address += " Apto " + settlement.Property.AptNumber
+		}
+		if err := service.SendDepositSettlementEmail(settlement.TenantEmail, settlement.Tenant.FullName, address, deposit.RefundAmount(), settlementPDF); err != nil {
+			logging.FromContext(ctx).Error("error sending settlement of deposit", "deposit_id", deposit.ID, "tenant_email", settlement.TenantEmail, "error", err)
+		}
+	}
+
+	logging.FromContext(ctx).Info("settlement of deposit issued", "deposit_id", deposit.ID, "refund", service.FormatMoney(deposit.RefundAmount()))
+	ctx.JSON(http.StatusOK, depositResponse(*updated))
+}
+
+// SignSettlement records the acceptance of the settlement by the tenant of the rental
+// @Summary Sign the settlement of my deposit
+// @Description The tenant accepts the settlement, which is re-issued with their electronic signature, the evidence of the signature and a verification QR code
+// @Tags deposits
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param location body SignerLocation false "Location of the signer"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string "Not the tenant of the rental"
+// @Failure 409 {object} map[string]string "No settlement waiting for a signature"
+// @Router /deposits/{id}/settlement/sign [post]
+func (c *SecurityDepositController) SignSettlement(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	deposit, ok := c.loadDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	var location SignerLocation
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&location); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+			return
+		}
+	}
+
+	now := time.Now()
+	settlement, ok := c.settlementData(ctx, deposit, now)
+	if !ok {
+		return
+	}
+	if settlement.Rental.RenterID != authUser.PersonID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyTenantSignsSettlement"})
+		return
+	}
+	if deposit.Status != model.DepositStatusSettlementIssued {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "NoSettlementAwaitingSignature", "status": deposit.Status})
+		return
+	}
+
+	evidence := signingEvidence(ctx, location)
+	if settlement.TenantEmail == "" {
+		settlement.TenantEmail = authUser.Email
+	}
+	if deposit.SettlementIssuedAt != nil {
+		settlement.IssuedAt = *deposit.SettlementIssuedAt
+	}
+	settlement.Signature = &service.DepositSettlementSignature{SignedAt: now, Evidence: evidence}
+
+	signedPDF, err := service.GenerateDepositSettlementPDF(*settlement)
+	if err != nil {
+		logging.FromContext(ctx).Error("error generating signed settlement of deposit", "deposit_id", deposit.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSignSettlement"})
+		return
+	}
+	signedPath, err := storeDepositSettlement(deposit, signedPDF, "liquidacion_firmada")
+	if err != nil {
+		logging.FromContext(ctx).Error("error storing signed settlement of deposit", "deposit_id", deposit.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToStoreSignedSettlement"})
+		return
+	}
+
+	deposit.Status = model.DepositStatusSigned
+	deposit.SignedPath = signedPath
+	deposit.SignedAt = &now
+	deposit.SignerIP = evidence.IPAddress
+	deposit.SignerUserAgent = evidence.UserAgent
+	updated, err := c.repository.Update(ctx, *deposit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveSecurityDeposit"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("settlement of deposit signed", "deposit_id", deposit.ID, "email", authUser.Email)
+	ctx.JSON(http.StatusOK, depositResponse(*updated))
+}
+
+// Refund records the transfer that returned the balance of a signed settlement to the tenant
+// @Summary Record the refund of a deposit
+// @Tags deposits
+// @Accept json
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Param refund body RefundDepositRequest true "Refund"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string "Settlement not signed"
+// @Router /admin/deposits/{id}/refund [post]
+func (c *SecurityDepositController) Refund(ctx *gin.Context) {
+	deposit, ok := c.loadDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	var req RefundDepositRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	refundedAt := time.Now()
+	if req.RefundedAt != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.RefundedAt, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidRefundedAt"})
+			return
+		}
+		refundedAt = date
+	}
+
+	// The refund is only made once the tenant accepted the deductions
+	if deposit.Status != model.DepositStatusSigned {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "SettlementSignatureRequiredForRefund", "status": deposit.Status})
+		return
+	}
+
+	deposit.Status = model.DepositStatusRefunded
+	deposit.RefundedAt = &refundedAt
+	deposit.RefundReference = strings.TrimSpace(req.Reference)
+	c.save(ctx, deposit, http.StatusOK)
+}
+
+// GetMine lists the security deposits of the rentals of the authenticated tenant
+// @Summary List my security deposits
+// @Tags deposits
+// @Produce json
+// @Success 200 {array} map[string]interface{}
+// @Router /deposits/me [get]
+func (c *SecurityDepositController) GetMine(ctx *gin.Context) {
+	personID, ok := currentPersonID(ctx)
+	if !ok {
+		return
+	}
+
+	rentals, err := c.rentalRepo.GetByRenterID(ctx, personID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rentalIDs := make([]uuid.UUID, len(rentals))
+	for i, rental := range rentals {
+		rentalIDs[i] = rental.ID
+	}
+
+	deposits, err := c.repository.GetByRentalIDs(ctx, rentalIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(deposits))
+	for _, deposit := range deposits {
+		response = append(response, depositResponse(deposit))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ServeSettlement serves the settlement of a deposit, the signed copy once the tenant signed it
+// @Summary Download the settlement of a deposit
+// @Tags deposits
+// @Produce application/pdf
+// @Param id path string true "Deposit ID"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string "Not the tenant of the rental"
+// @Router /deposits/{id}/settlement [get]
+func (c *SecurityDepositController) ServeSettlement(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+	deposit, ok := c.loadDeposit(ctx)
+	if !ok {
+		return
+	}
+
+	if authUser.Role != "admin" {
+		rental, err := c.rentalRepo.GetByID(ctx, deposit.RentalID)
+		if err != nil || rental == nil || rental.RenterID != authUser.PersonID {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "NoSettlementAccess"})
+			return
+		}
+	}
+
+	path, filename := deposit.SettlementPath, "liquidacion_deposito.pdf"
+	if deposit.SignedPath != "" {
+		path, filename = deposit.SignedPath, "liquidacion_deposito_firmada.pdf"
+	}
+	if path == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "SettlementNotIssued"})
+		return
+	}
+
+	pdfData, err := loadStoredPDF(path)
+	if err != nil {
+		logging.FromContext(ctx).Error("error loading settlement of deposit", "deposit_id", deposit.ID, "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadSettlement"})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "inline; filename="+filename)
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
+
+// VerifySettlement confirms that a signed deposit settlement was issued by the platform
+// @Summary Verify a signed deposit settlement
+// @Description Public endpoint opened from the QR code printed on the signed settlement
+// @Tags deposits
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{} "Settlement not found"
+// @Router /public/deposit-settlements/verify/{id} [get]
+func (c *SecurityDepositController) VerifySettlement(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	deposit, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSecurityDeposit"})
+		return
+	}
+	if deposit == nil || deposit.SignedAt == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"authentic": false,
+			"error":     "SignedSettlementNotFound",
+		})
+		return
+	}
+
+	tenantName := ""
+	if rental, err := c.rentalRepo.GetByID(ctx, deposit.RentalID); err == nil && rental != nil {
+		if tenant, err := c.personRepo.GetByID(ctx, rental.RenterID); err == nil && tenant != nil {
+			tenantName = tenant.FullName
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"authentic":         true,
+		"message":           "Esta liquidación del depósito fue expedida por la plataforma y firmada electrónicamente por el arrendatario. Compare los valores con el PDF recibido.",
+		"id":                deposit.ID,
+		"tenant_name":       tenantName,
+		"deposit_amount":    deposit.Amount,
+		"total_deductions":  deposit.TotalDeductions(),
+		"refund_amount":     deposit.RefundAmount(),
+		"refund_display":    service.FormatMoney(deposit.RefundAmount()),
+		"signed_at":         deposit.SignedAt,
+		"status":            deposit.Status,
+		"status_translated": model.DepositStatusTranslations[deposit.Status],
+	})
+}
+
+// loadDeposit loads the deposit of the :id path parameter, writing the error response when it
+// cannot be found
+func (c *SecurityDepositController) loadDeposit(ctx *gin.Context) (*model.SecurityDeposit, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, false
+	}
+
+	deposit, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if deposit == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "SecurityDepositNotFound"})
+		return nil, false
+	}
+	return deposit, true
+}
+
+// editableDeposit loads a deposit that can still be changed. A settlement already sent is
+// discarded, since it no longer matches the deposit, while a signed one locks it.
+func (c *SecurityDepositController) editableDeposit(ctx *gin.Context) (*model.SecurityDeposit, bool) {
+	deposit, ok := c.loadDeposit(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	switch deposit.Status {
+	case model.DepositStatusSigned, model.DepositStatusRefunded:
+		ctx.JSON(http.StatusConflict, gin.H{"error": "SettlementAlreadySigned", "status": deposit.Status})
+		return nil, false
+	case model.DepositStatusSettlementIssued:
+		deposit.Status = model.DepositStatusHeld
+		deposit.SettlementPath = ""
+		deposit.SettlementIssuedAt = nil
+	}
+	return deposit, true
+}
+
+// applyDepositRequest validates a deposit request and copies it to the deposit, writing the
+// error response when it is invalid
+func (c *SecurityDepositController) applyDepositRequest(ctx *gin.Context, req SecurityDepositRequest, deposit *model.SecurityDeposit) bool {
+	if req.Amount != nil {
+		deposit.Amount = *req.Amount
+	}
+	if deposit.Amount <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "DepositAmountRequired"})
+		return false
+	}
+	if req.HeldBy != "" {
+		if !model.IsValidDepositHolder(req.HeldBy) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidDepositHolder", "detail": strings.Join(model.DepositHolders, ", ")})
+			return false
+		}
+		deposit.HeldBy = req.HeldBy
+	}
+	if req.BankAccountID != "" {
+		accountID, err := uuid.Parse(req.BankAccountID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidBankAccountID"})
+			return false
+		}
+		account, err := c.bankAccountRepo.GetByID(ctx, accountID)
+		if err != nil || account == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "BankAccountNotFound"})
+			return false
+		}
+		deposit.BankAccountID = &account.ID
+	}
+	if req.ReceivedAt != "" {
+		receivedAt, err := time.ParseInLocation("2006-01-02", req.ReceivedAt, service.AppLocation())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidReceivedAt"})
+			return false
+		}
+		deposit.ReceivedAt = receivedAt
+	}
+	deposit.Notes = strings.TrimSpace(req.Notes)
+	return true
+}
+
+// settlementData loads the rental, parties and account printed on the settlement of a deposit,
+// writing the error response when the rental cannot be loaded
+func (c *SecurityDepositController) settlementData(ctx *gin.Context, deposit *model.SecurityDeposit, issuedAt time.Time) (*service.DepositSettlementPDF, bool) {
+	rental, err := c.rentalRepo.GetByID(ctx, deposit.RentalID)
+	if err != nil || rental == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetDepositRental"})
+		return nil, false
+	}
+	property, err := c.propertyRepo.GetByID(ctx, rental.PropertyID)
+	if err != nil || property == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetRentedProperty"})
+		return nil, false
+	}
+	tenant, err := c.personRepo.GetByID(ctx, rental.RenterID)
+	if err != nil || tenant == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetTenant"})
+		return nil, false
+	}
+
+	settlement := &service.DepositSettlementPDF{
+		Deposit:     *deposit,
+		Rental:      rental,
+		Property:    property,
+		Tenant:      tenant,
+		TenantEmail: c.personEmail(ctx, tenant.ID),
+		IssuedAt:    issuedAt,
+	}
+
+	// The manager returns the deposits they hold, the owner of the rent account the others
+	holderID := uuid.Nil
+	if deposit.HeldBy == model.DepositHolderManager && len(property.ManagerIDs) > 0 {
+		holderID = property.ManagerIDs[0]
+	} else if account, err := c.bankAccountRepo.GetByID(ctx, rental.BankAccountID); err == nil && account != nil {
+		holderID = account.PersonID
+	}
+	if holderID != uuid.Nil {
+		if holder, err := c.personRepo.GetByID(ctx, holderID); err == nil {
+			settlement.Holder = holder
+		}
+	}
+	if deposit.BankAccountID != nil {
+		if account, err := c.bankAccountRepo.GetByID(ctx, *deposit.BankAccountID); err == nil {
+			settlement.BankAccount = account
+		}
+	}
+
+	return settlement, true
+}
+
+// save updates a deposit and writes it as the response
+func (c *SecurityDepositController) save(ctx *gin.Context, deposit *model.SecurityDeposit, status int) {
+	updated, err := c.repository.Update(ctx, *deposit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToSaveSecurityDeposit"})
+		return
+	}
+	ctx.JSON(status, depositResponse(*updated))
+}
+
+// personEmail returns the email of the user of a person, empty when they have no user
+func (c *SecurityDepositController) personEmail(ctx *gin.Context, personID uuid.UUID) string {
+	if user, err := c.userRepo.GetByPersonID(ctx, personID); err == nil && user != nil {
+		return user.Email
+	}
+	return ""
+}
+
+// storeDepositSettlement saves a deposit settlement with the rental files and returns its path
+func storeDepositSettlement(deposit *model.SecurityDeposit, settlementPDF []byte, name string) (string, error) {
+	if storageService := service.GetSupabaseStorageService(); storageService != nil {
+		filePath := fmt.Sprintf("rentals/%s/deposito/%s_%s.pdf", deposit.RentalID, deposit.ID, name)
+		if _, err := storageService.UploadBytes(filePath, settlementPDF, "application/pdf"); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "deposits")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", err
+	}
+	settlementPath := filepath.Join(tempDir, fmt.Sprintf("%s_%s.pdf", deposit.ID, name))
+	if err := os.WriteFile(settlementPath, settlementPDF, 0644); err != nil {
+		return "", err
+	}
+	return settlementPath, nil
+}
+
+// depositResponse adds the totals, the translated status and fresh receipt URLs to a deposit
+func depositResponse(deposit model.SecurityDeposit) gin.H {
+	signDeductionReceiptURLs(&deposit)
+	return gin.H{
+		"deposit":           deposit,
+		"total_deductions":  deposit.TotalDeductions(),
+		"refund_amount":     deposit.RefundAmount(),
+		"balance_owed":      deposit.BalanceOwed(),
+		"status_translated": model.DepositStatusTranslations[deposit.Status],
+	}
+}
+
+// signDeductionReceiptURLs fills the signed URLs of the deduction receipts, which expire
+func signDeductionReceiptURLs(deposit *model.SecurityDeposit) {
+	storageService := service.GetSupabaseStorageService()
+	if storageService == nil {
+		return
+	}
+
+	var paths []string
+	for _, deduction := range deposit.Deductions {
+		if deduction.ReceiptPath != "" {
+			paths = append(paths, deduction.ReceiptPath)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	urls, err := storageService.SignedURLs(paths, storageService.URLTTL())
+	if err != nil {
+		slog.Error("error signing deduction receipt URLs of deposit", "deposit_id", deposit.ID, "error", err)
+		return
+	}
+	for i := range deposit.Deductions {
+		deposit.Deductions[i].ReceiptURL = urls[deposit.Deductions[i].ReceiptPath]
+	}
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// ServiceProviderController handles HTTP requests for service provider entities
+type ServiceProviderController struct {
+	repository storage.ServiceProviderStore
+}
+
+// NewServiceProviderController creates a new ServiceProviderController
+func NewServiceProviderController(repository storage.ServiceProviderStore) *ServiceProviderController {
+	return &ServiceProviderController{
+		repository: repository,
+	}
+}
+
+// RegisterRoutes registers the service provider routes on an admin-protected group
+func (c *ServiceProviderController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	providers := adminRouter.Group("/service-providers")
+	{
+		providers.GET("", c.GetAll)
+		providers.GET("/:id", c.GetByID)
+		providers.POST("", c.Create)
+		providers.PUT("/:id", c.Update)
+		providers.DELETE("/:id", c.Delete)
+	}
+}
+
+// GetAll retrieves all service providers, optionally filtered by the specialty query parameter
+func (c *ServiceProviderController) GetAll(ctx *gin.Context) {
+	var providers []model.ServiceProvider
+	var err error
+
+	if specialty := ctx.Query("specialty"); specialty != "" {
+		providers, err = c.repository.GetBySpecialty(ctx, specialty)
+	} else {
+		providers, err = c.repository.GetAll(ctx)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if providers == nil {
+		providers = []model.ServiceProvider{}
+	}
+
+	ctx.JSON(http.StatusOK, paginate(ctx, providers))
+}
+
+// GetByID retrieves a service provider by ID
+func (c *ServiceProviderController) GetByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	provider, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if provider == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ServiceProviderNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, provider)
+}
+
+// Create adds a new service provider
+func (c *ServiceProviderController) Create(ctx *gin.Context) {
+	var provider model.ServiceProvider
+	if err := ctx.ShouldBindJSON(&provider); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	if strings.TrimSpace(provider.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "NameRequired"})
+		return
+	}
+	if strings.TrimSpace(provider.Email) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ProviderEmailRequired"})
+		return
+	}
+
+	provider.ID = uuid.New()
+
+	createdProvider, err := c.repository.Create(ctx, provider)
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating service provider", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateServiceProvider"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createdProvider)
+}
+
+// Update updates an existing service provider
+func (c *ServiceProviderController) Update(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	var provider model.ServiceProvider
+	if err := ctx.ShouldBindJSON(&provider); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	existingProvider, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingProvider == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ServiceProviderNotFound"})
+		return
+	}
+
+	provider.ID = id
+
+	updatedProvider, err := c.repository.Update(ctx, provider)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedProvider)
+}
+
+// Delete removes a service provider
+func (c *ServiceProviderController) Delete(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	existingProvider, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existingProvider == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "ServiceProviderNotFound"})
+		return
+	}
+
+	if err := c.repository.Delete(ctx, id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
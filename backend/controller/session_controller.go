@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+)
+
+// SessionController lets the users list the devices they are logged in on and log them out
+type SessionController struct {
+	sessions *service.SessionService
+}
+
+// NewSessionController creates a new SessionController
+func NewSessionController(sessions *service.SessionService) *SessionController {
+	return &SessionController{sessions: sessions}
+}
+
+// RegisterRoutes registers the session routes of the authenticated user, which an admin
+// impersonating them cannot use
+func (c *SessionController) RegisterRoutes(router *gin.RouterGroup) {
+	sessions := router.Group("/users/me/sessions", middleware.ForbidImpersonation())
+	{
+		sessions.GET("", c.GetMine)
+		sessions.DELETE("/:id", c.RevokeMine)
+	}
+}
+
+// sessionResponse is a session with whether the request was made with it
+type sessionResponse struct {
+	model.UserSession
+	Current bool `json:"current"`
+}
+
+// GetMine lists the active sessions of the authenticated user
+// @Summary List my active sessions
+// @Description Sessions that are neither expired nor revoked, with the device and IP they were opened from, most recently used first. current marks the session of the request.
+// @Tags users
+// @Produce json
+// @Success 200 {array} sessionResponse
+// @Router /users/me/sessions [get]
+func (c *SessionController) GetMine(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	sessions, err := c.sessions.List(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := ctx.GetString("session_id")
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, sessionResponse{
+			UserSession: session,
+			Current:     session.ID.String() == current,
+		})
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RevokeMine revokes a session of the authenticated user, whose token stops working
+// @Summary Revoke one of my sessions
+// @Description Logs a device out. Revoking the current session logs the request's own device out.
+// @Tags users
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 404 {object} string "Session not found"
+// @Router /users/me/sessions/{id} [delete]
+func (c *SessionController) RevokeMine(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	err = c.sessions.Revoke(ctx, userID, sessionID)
+	if errors.Is(err, service.ErrSessionNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "SessionNotFound"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// currentUserID returns the ID of the authenticated user, or answers the request when there is none
+func currentUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	userInterface, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
+		return uuid.Nil, false
+	}
+	authUser, ok := userInterface.(*model.User)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
+		return uuid.Nil, false
+	}
+	return authUser.ID, true
+}
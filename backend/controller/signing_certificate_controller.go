@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/service"
+)
+
+// maxSigningCertificateSize limits the PKCS#12 files uploaded as signing certificate
+const maxSigningCertificateSize = 1 << 20
+
+// SigningCertificateController handles the certificate contracts are signed with
+type SigningCertificateController struct{}
+
+// NewSigningCertificateController creates a new SigningCertificateController
+func NewSigningCertificateController() *SigningCertificateController {
+	return &SigningCertificateController{}
+}
+
+// RegisterRoutes registers the signing certificate routes on an admin-protected group
+func (c *SigningCertificateController) RegisterRoutes(adminRouter *gin.RouterGroup) {
+	certificate := adminRouter.Group("/signing-certificate")
+	{
+		certificate.GET("", c.Get)
+		certificate.POST("", c.Upload)
+		certificate.DELETE("", c.Delete)
+		certificate.POST("/reload", c.Reload)
+	}
+}
+
+// Get returns the details of the active signing certificate
+func (c *SigningCertificateController) Get(ctx *gin.Context) {
+	cert, err := service.ActiveSigningCertificate()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadSigningCertificate", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cert.Info())
+}
+
+// Upload installs a PKCS#12 (.p12/.pfx) file, sent as multipart "file" with its "password",
+// as the signing certificate
+func (c *SigningCertificateController) Upload(ctx *gin.Context) {
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FileRequired", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxSigningCertificateSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CertificateTooLarge"})
+		return
+	}
+
+	p12Data, err := io.ReadAll(io.LimitReader(file, maxSigningCertificateSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadCertificate"})
+		return
+	}
+	if len(p12Data) > maxSigningCertificateSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CertificateTooLarge"})
+		return
+	}
+
+	cert, err := service.InstallSigningCertificate(p12Data, ctx.PostForm("password"))
+	if err != nil {
+		if errors.Is(err, service.ErrSigningCertificateFromEnv) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "SigningCertificateFromEnv"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidCertificate", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cert.Info())
+}
+
+// Reload reads the signing certificate again, to use a rotated certificate file without restarting
+func (c *SigningCertificateController) Reload(ctx *gin.Context) {
+	cert, err := service.ReloadSigningCertificate()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReloadSigningCertificate", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cert.Info())
+}
+
+// Delete removes the uploaded certificate, contracts are signed with the self-signed one again
+func (c *SigningCertificateController) Delete(ctx *gin.Context) {
+	if err := service.RemoveSigningCertificate(); err != nil {
+		if errors.Is(err, service.ErrSigningCertificateFromEnv) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "SigningCertificateFromEnv"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cert, err := service.ActiveSigningCertificate()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToLoadSigningCertificate", "detail": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cert.Info())
+}
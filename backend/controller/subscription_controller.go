@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/service"
+)
+
+// SubscriptionController handles the monthly platform plan paid by the managers
+type SubscriptionController struct {
+	subscriptions *service.SubscriptionService
+}
+
+// NewSubscriptionController creates a new SubscriptionController. subscriptions is nil when
+// subscription billing is not configured.
+func NewSubscriptionController(subscriptions *service.SubscriptionService) *SubscriptionController {
+	return &SubscriptionController{
+		subscriptions: subscriptions,
+	}
+}
+
+// RegisterRoutes registers the subscription route of the authenticated manager
+func (c *SubscriptionController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/subscription/me", c.GetMine)
+}
+
+// RegisterAdminRoutes registers the subscription management routes on an admin-protected group
+func (c *SubscriptionController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.GET("/subscriptions", c.GetAll)
+	adminRouter.POST("/users/:id/subscription", c.Subscribe)
+}
+
+// RegisterPublicRoutes registers the webhook of the billing provider, authenticated by its signature
+func (c *SubscriptionController) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.POST("/public/subscriptions/webhook", c.HandleWebhook)
+}
+
+// GetMine returns the platform subscription of the authenticated manager
+// @Summary Get my platform subscription
+// @Description Returns the monthly plan of the authenticated manager, with the page to pay the open invoice when one is owed
+// @Tags subscriptions
+// @Produce json
+// @Success 200 {object} model.PlatformSubscription
+// @Failure 404 {object} map[string]string "No subscription"
+// @Failure 503 {object} map[string]string "Subscription billing not configured"
+// @Router /subscription/me [get]
+func (c *SubscriptionController) GetMine(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.subscriptions.GetByUserID(ctx, authUser.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetSubscription"})
+		return
+	}
+	if subscription == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "NoPlatformSubscription"})
+		return
+	}
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// GetAll lists the platform subscriptions
+// @Summary List the platform subscriptions
+// @Tags subscriptions
+// @Produce json
+// @Success 200 {array} model.PlatformSubscription
+// @Router /admin/subscriptions [get]
+func (c *SubscriptionController) GetAll(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+
+	subscriptions, err := c.subscriptions.GetAll(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, paginate(ctx, subscriptions))
+}
+
+// Subscribe subscribes an existing manager to the platform plan
+// @Summary Subscribe a manager to the platform plan
+// @Description Creates the subscription of a manager registered before billing was enabled, or replaces an ended one. The user is activated when the first invoice is paid.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 201 {object} model.PlatformSubscription
+// @Failure 409 {object} map[string]string "Subscription in force"
+// @Failure 503 {object} map[string]string "Subscription billing not configured"
+// @Router /admin/users/{id}/subscription [post]
+func (c *SubscriptionController) Subscribe(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	subscription, err := c.subscriptions.Subscribe(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSubscriptionUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
+		case errors.Is(err, service.ErrSubscriptionNotManager):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "OnlyManagersSubscribe"})
+		case errors.Is(err, service.ErrSubscriptionActive):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "SubscriptionAlreadyActive"})
+		default:
+			logging.FromContext(ctx).Error("error subscribing user", "user_id", userID, "error", err)
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": "FailedToCreateSubscription"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, subscription)
+}
+
+// HandleWebhook receives the invoice and subscription events of the billing provider
+// @Summary Subscription billing webhook
+// @Description Receives the signed events of the billing provider. Paid invoices activate the manager, subscriptions left unpaid disable it.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid signature"
+// @Router /public/subscriptions/webhook [post]
+func (c *SubscriptionController) HandleWebhook(ctx *gin.Context) {
+	if !c.enabled(ctx) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, 1<<20))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "FailedToReadBody"})
+		return
+	}
+
+	if err := c.subscriptions.HandleWebhook(ctx, ctx.Request.Header, body); err != nil {
+		logging.FromContext(ctx).Error("error processing subscription billing webhook", "error", err)
+		// Answering with an error makes the provider retry, except for events we cannot trust
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidBillingEvent) {
+			status = http.StatusUnauthorized
+		}
+		ctx.JSON(status, gin.H{"error": "WebhookNotProcessed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "ok"})
+}
+
+// enabled writes the 503 response when subscription billing is not configured
+func (c *SubscriptionController) enabled(ctx *gin.Context) bool {
+	if c.subscriptions == nil {
+		respondCapabilityUnavailable(ctx, service.CapabilitySubscriptions, "SubscriptionsNotConfigured")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// recentUserBulkJobs is the number of jobs listed by ListJobs
+const recentUserBulkJobs = 50
+
+// UserBulkController lets admins activate, disable or resend the invitation to many users at
+// once. The operations run as background jobs with a per-user report.
+type UserBulkController struct {
+	repository storage.UserBulkJobStore
+	queue      *service.UserBulkJobQueue
+}
+
+// NewUserBulkController creates a new UserBulkController
+func NewUserBulkController(repository storage.UserBulkJobStore, queue *service.UserBulkJobQueue) *UserBulkController {
+	return &UserBulkController{
+		repository: repository,
+		queue:      queue,
+	}
+}
+
+// UserBulkRequest selects the users of a bulk operation. With DryRun the matching users are
+// returned without queuing the job.
+type UserBulkRequest struct {
+	Action string               `json:"action" binding:"required"`
+	Filter model.UserBulkFilter `json:"filter"`
+	DryRun bool                 `json:"dry_run"`
+}
+
+// RegisterRoutes registers the bulk user routes on the admin group
+func (c *UserBulkController) RegisterRoutes(router *gin.RouterGroup) {
+	bulk := router.Group("/users/bulk")
+	{
+		bulk.POST("", c.CreateJob)
+		bulk.GET("", c.ListJobs)
+		bulk.GET("/:id", c.GetJob)
+	}
+}
+
+// CreateJob queues a bulk operation on the users matching a filter
+// @Summary Bulk user operation
+// @Description Activate, disable or resend the invitation to the users matching a filter (status, role, organization, creation date)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body UserBulkRequest true "Action and filter"
+// @Success 202 {object} model.UserBulkJob
+// @Router /admin/users/bulk [post]
+func (c *UserBulkController) CreateJob(ctx *gin.Context) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req UserBulkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !model.IsValidUserBulkAction(req.Action) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidBulkUserAction"})
+		return
+	}
+	if req.Filter.CreatedFrom != nil && req.Filter.CreatedTo != nil && req.Filter.CreatedTo.Before(*req.Filter.CreatedFrom) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "CreatedToBeforeCreatedFrom"})
+		return
+	}
+
+	if req.DryRun {
+		users, err := c.queue.MatchingUsers(ctx, req.Filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetUsers"})
+			return
+		}
+		matching := make([]gin.H, 0, len(users))
+		for _, user := range users {
+			matching = append(matching, gin.H{
+				"id":     user.ID,
+				"email":  user.Email,
+				"role":   user.Role,
+				"status": user.Status,
+			})
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"action": req.Action,
+			"total":  len(users),
+			"users":  matching,
+		})
+		return
+	}
+
+	job, err := c.queue.Enqueue(ctx, req.Action, req.Filter, authUser.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToQueueBulkOperation"})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// ListJobs lists the latest bulk user jobs
+// @Summary List bulk user jobs
+// @Tags admin
+// @Produce json
+// @Success 200 {array} model.UserBulkJob
+// @Router /admin/users/bulk [get]
+func (c *UserBulkController) ListJobs(ctx *gin.Context) {
+	jobs, err := c.repository.GetRecent(ctx, recentUserBulkJobs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetBulkUserJobs"})
+		return
+	}
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+// GetJob returns the progress and per-user report of a bulk user job
+// @Summary Get bulk user job
+// @Tags admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} model.UserBulkJob
+// @Router /admin/users/bulk/{id} [get]
+func (c *UserBulkController) GetJob(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return
+	}
+
+	job, err := c.repository.GetByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGetBulkUserJob"})
+		return
+	}
+	if job == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "BulkUserJobNotFound"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
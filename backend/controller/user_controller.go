@@ -1,29 +1,38 @@
 package controller
 
 import (
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"encoding/base64"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
-	"log"
-
-	"github.com/nescool101/rentManager/auth"
+	"github.com/nescool101/rentManager/i18n"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
 	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
 	"github.com/nescool101/rentManager/storage"
 )
 
 // UserController handles HTTP requests for user entities
 type UserController struct {
-	repository *storage.UserRepository
+	repository    storage.UserStore
+	loginAttempts *service.LoginAttemptService
+	sessions      *service.SessionService
 }
 
 // NewUserController creates a new UserController
-func NewUserController(repository *storage.UserRepository) *UserController {
+func NewUserController(repository storage.UserStore, loginAttempts *service.LoginAttemptService, sessions *service.SessionService) *UserController {
 	return &UserController{
-		repository: repository,
+		repository:    repository,
+		loginAttempts: loginAttempts,
+		sessions:      sessions,
 	}
 }
 
@@ -33,15 +42,26 @@ func NewUserController(repository *storage.UserRepository) *UserController {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size, the whole list without it"
+// @Param offset query int false "Rows skipped"
+// @Param sort query string false "Column to sort by, prefixed with - for descending order"
+// @Param filter[column] query string false "Exact value of a column"
+// @Header 200 {int} X-Total-Count "Size of the list before limit and offset"
 // @Success 200 {array} model.User
 // @Router /users [get]
 func (c *UserController) GetAll(ctx *gin.Context) {
-	users, err := c.repository.GetAll(ctx)
+	opts, err := listOptionsFromQuery(ctx, storage.UserListColumns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, total, err := c.repository.List(ctx, opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, users)
+	respondPage(ctx, users, total)
 }
 
 // GetByID retrieves a user by ID
@@ -57,7 +77,7 @@ func (c *UserController) GetAll(ctx *gin.Context) {
 func (c *UserController) GetByID(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -68,7 +88,7 @@ func (c *UserController) GetByID(ctx *gin.Context) {
 	}
 
 	if user == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
 		return
 	}
 
@@ -88,7 +108,7 @@ func (c *UserController) GetByID(ctx *gin.Context) {
 func (c *UserController) GetByEmail(ctx *gin.Context) {
 	email := ctx.Query("email")
 	if email == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "EmailRequired"})
 		return
 	}
 
@@ -99,60 +119,105 @@ func (c *UserController) GetByEmail(ctx *gin.Context) {
 	}
 
 	if user == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, user)
 }
 
+// LoginRequest holds the credentials of a login, the password encoded in base64
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginUser is the user returned by a successful login
+type LoginUser struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	PersonID uuid.UUID `json:"person_id"`
+	Status   string    `json:"status"`
+}
+
+// LoginResponse is the answer of a successful login, with the JWT of the new session
+type LoginResponse struct {
+	Success bool      `json:"success"`
+	User    LoginUser `json:"user"`
+	Token   string    `json:"token"`
+}
+
 // Login authenticates a user
 // @Summary Login user
-// @Description Authenticate user by email and password
+// @Description Authenticate user by email and password. After LOGIN_CAPTCHA_THRESHOLD failures the responses carry captcha_required; after LOGIN_LOCKOUT_THRESHOLD failures of the email, or LOGIN_IP_LOCKOUT_THRESHOLD of the IP, logins are rejected until locked_until.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param credentials body object true "User credentials"
-// @Success 200 {object} object
+// @Param credentials body LoginRequest true "User credentials"
+// @Success 200 {object} LoginResponse
 // @Failure 401 {object} string "Authentication failed"
+// @Failure 429 {object} service.LoginStatus "Too many failed attempts"
 // @Router /users/login [post]
 func (c *UserController) Login(ctx *gin.Context) {
-	var credentials struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
-
+	var credentials LoginRequest
 	if err := ctx.ShouldBindJSON(&credentials); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("Login attempt for email: %s", credentials.Email)
+	logging.FromContext(ctx).Info("login attempt for email", "email", credentials.Email)
+
+	// Emails and IPs with too many failures in a row are locked out for a while
+	status := c.loginAttempts.Status(ctx, credentials.Email, ctx.ClientIP(), time.Now())
+	if status.Locked {
+		c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureLocked)
+		logging.FromContext(ctx).Warn("login rejected, locked out", "email", credentials.Email, "client_ip", ctx.ClientIP(), "locked_until", status.LockedUntil.Format(time.RFC3339))
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(*status.LockedUntil).Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"error":            "TooManyFailedLogins",
+			"locked_until":     status.LockedUntil,
+			"captcha_required": true,
+		})
+		return
+	}
 
 	// Get user by email
 	user, err := c.repository.GetByEmail(ctx, credentials.Email)
 	if err != nil {
-		log.Printf("Error fetching user: %v", err)
+		logging.FromContext(ctx).Error("error fetching user", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	if user == nil {
-		log.Printf("User not found: %s", credentials.Email)
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		logging.FromContext(ctx).Warn("user not found", "email", credentials.Email)
+		ctx.JSON(http.StatusUnauthorized, c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureUnknownEmail))
 		return
 	}
 
-	log.Printf("User found: %s, Status: %s", credentials.Email, user.Status)
+	logging.FromContext(ctx).Info("user found", "email", credentials.Email, "status", user.Status)
 
 	// Check user status - pending and disabled users cannot log in
 	if user.Status == "pending" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "EL ESTADO DE TU USUARIO ES INACTIVO, ESTAMOS ESPERANDO TU PAGO O APROBACION EN EL SISTEMA PARA QUE PUEDAS ACCEDER"})
+		c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureInactive)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "UserPendingApproval"})
 		return
 	}
 
 	if user.Status == "disabled" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Tu cuenta ha sido deshabilitada. Contacta a soporte para más información."})
+		c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureInactive)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "UserDisabled"})
+		return
+	}
+
+	// Self-registered users cannot log in until they verify their email
+	if user.EmailPendingVerification {
+		c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureInactive)
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":                       "EmailVerificationRequired",
+			"email_verification_required": true,
+		})
 		return
 	}
 
@@ -160,40 +225,92 @@ func (c *UserController) Login(ctx *gin.Context) {
 
 	// Password checking logic
 	// The frontend always sends base64 encoded passwords, so we compare directly with stored password
-	log.Printf("Comparing passwords - Received password: %s (length: %d)", credentials.Password, len(credentials.Password))
-	log.Printf("Stored password hash: %s (length: %d)", user.PasswordBase64, len(user.PasswordBase64))
+	logging.FromContext(ctx).Debug("comparing passwords", "received_length", len(credentials.Password), "stored_length", len(user.PasswordBase64))
 
 	passwordMatch := credentials.Password == user.PasswordBase64
 
 	if !passwordMatch {
 		// Just for debugging - can be removed in production
-		log.Printf("Password mismatch for user: %s - Comparison returned false", credentials.Email)
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		logging.FromContext(ctx).Warn("password mismatch", "email", credentials.Email)
+		ctx.JSON(http.StatusUnauthorized, c.recordLoginFailure(ctx, credentials.Email, model.LoginFailureWrongPassword))
 		return
 	}
 
-	// Generate JWT token
-	tokenString, err := auth.GenerateToken(user)
+	// Generate JWT token, recorded as a session the user can revoke
+	_, tokenString, err := c.sessions.Start(ctx, user, ctx.ClientIP(), ctx.Request.UserAgent(), time.Now())
 	if err != nil {
-		log.Printf("Failed to generate token: %v", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		logging.FromContext(ctx).Error("failed to generate token", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateToken"})
 		return
 	}
 
-	log.Printf("Login successful for user: %s with status: %s", credentials.Email, user.Status)
+	logging.FromContext(ctx).Info("login succeeded", "email", credentials.Email, "status", user.Status)
+	c.loginAttempts.RecordSuccess(ctx, credentials.Email, ctx.ClientIP(), ctx.Request.UserAgent(), time.Now())
 
 	// Return user data with success flag
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"user": gin.H{
-			"id":        user.ID,
-			"email":     user.Email,
-			"role":      user.Role,
-			"person_id": user.PersonID,
-			"status":    user.Status,
+	ctx.JSON(http.StatusOK, LoginResponse{
+		Success: true,
+		User: LoginUser{
+			ID:       user.ID,
+			Email:    user.Email,
+			Role:     user.Role,
+			PersonID: user.PersonID,
+			Status:   user.Status,
 		},
-		"token": tokenString,
+		Token: tokenString,
+	})
+}
+
+// recordLoginFailure records a failed login and returns the body of the 401 response: the
+// CAPTCHA flag and, when this failure locked the email or IP out, until when
+func (c *UserController) recordLoginFailure(ctx *gin.Context, email, reason string) gin.H {
+	status := c.loginAttempts.RecordFailure(ctx, email, ctx.ClientIP(), ctx.Request.UserAgent(), reason, time.Now())
+	response := gin.H{
+		"error":            "InvalidCredentials",
+		"captcha_required": status.CaptchaRequired,
+	}
+	if status.Locked {
+		response["locked_until"] = status.LockedUntil
+	}
+	return response
+}
+
+// GetLoginAttempts lists the recent login attempts for the admins
+// @Summary Recent login attempts
+// @Description Failed attempts of the last 24 hours by default, newest first. failed=false includes the successful ones.
+// @Tags users
+// @Produce json
+// @Param email query string false "Email of the attempts"
+// @Param ip query string false "IP address of the attempts"
+// @Param failed query bool false "Only the failed attempts, true by default"
+// @Param hours query int false "Hours back, 24 by default and 720 at most"
+// @Param limit query int false "Maximum attempts, 100 by default and 500 at most"
+// @Success 200 {array} model.LoginAttempt
+// @Router /admin/login-attempts [get]
+func (c *UserController) GetLoginAttempts(ctx *gin.Context) {
+	hours, err := strconv.Atoi(ctx.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > 720 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ImpersonationHoursOutOfRange"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "LimitOutOfRange500"})
+		return
+	}
+
+	attempts, err := c.loginAttempts.Recent(ctx, storage.LoginAttemptFilter{
+		Email:      ctx.Query("email"),
+		IPAddress:  ctx.Query("ip"),
+		FailedOnly: ctx.DefaultQuery("failed", "true") != "false",
+		Since:      time.Now().Add(-time.Duration(hours) * time.Hour),
+		Limit:      limit,
 	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, attempts)
 }
 
 // Helper function to check if a string is base64 encoded
@@ -201,14 +318,14 @@ func isBase64Encoded(s string) bool {
 	// First try to decode
 	_, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
-		log.Printf("String is not base64 encoded: %v", err)
+		slog.Info("string is not base64 encoded", "error", err)
 		return false
 	}
 
 	// Additional check: base64 encoded strings have a specific length pattern
 	// and only contain a limited set of characters
 	if len(s)%4 != 0 {
-		log.Printf("String length %d is not divisible by 4, not base64", len(s))
+		slog.Info("string length is not divisible by 4, not base64", "length", len(s))
 		return false
 	}
 
@@ -216,12 +333,12 @@ func isBase64Encoded(s string) bool {
 	for _, c := range s {
 		if !((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') ||
 			(c >= '0' && c <= '9') || c == '+' || c == '/' || c == '=') {
-			log.Printf("String contains invalid base64 character: %c", c)
+			slog.Warn("string contains invalid base64 character", "character", string(c))
 			return false
 		}
 	}
 
-	log.Printf("String appears to be valid base64")
+	slog.Info("string appears to be valid base64")
 	return true
 }
 
@@ -283,30 +400,30 @@ func (c *UserController) Create(ctx *gin.Context) {
 func (c *UserController) Update(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
-	log.Printf("Attempting to update user with ID: %s", id.String())
+	logging.FromContext(ctx).Debug("attempting to update user", "id", id.String())
 
 	// Check if user exists
 	existingUser, err := c.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("Error retrieving existing user: %v", err)
+		logging.FromContext(ctx).Error("error retrieving existing user", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	if existingUser == nil {
-		log.Printf("User not found with ID: %s", id.String())
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		logging.FromContext(ctx).Warn("user not found", "id", id.String())
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
 		return
 	}
 
 	// Bind new data
 	var user model.User
 	if err := ctx.ShouldBindJSON(&user); err != nil {
-		log.Printf("Invalid user data: %v", err)
+		logging.FromContext(ctx).Warn("invalid user data", "error", err)
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -316,36 +433,36 @@ func (c *UserController) Update(ctx *gin.Context) {
 
 	// Handle password - ensure it's properly base64 encoded
 	if user.PasswordBase64 != "" {
-		log.Printf("Password update requested for user %s", user.Email)
+		logging.FromContext(ctx).Info("password update requested for user", "email", user.Email)
 
 		// For new users or direct password updates, decode and re-encode to ensure proper format
 		decodedPassword, err := base64.StdEncoding.DecodeString(user.PasswordBase64)
 		if err != nil {
 			// If it's not valid base64, treat it as plain text and encode it
-			log.Printf("Password is not valid base64, treating as plain text and encoding")
+			logging.FromContext(ctx).Info("password is not valid base64, treating as plain text and encoding")
 			user.PasswordBase64 = base64.StdEncoding.EncodeToString([]byte(user.PasswordBase64))
 		} else {
 			// It's valid base64, but let's re-encode it to ensure consistency
-			log.Printf("Password is valid base64, re-encoding for consistency")
+			logging.FromContext(ctx).Info("password is valid base64, re-encoding for consistency")
 			user.PasswordBase64 = base64.StdEncoding.EncodeToString(decodedPassword)
 		}
 
-		log.Printf("Final password length after processing: %d", len(user.PasswordBase64))
+		logging.FromContext(ctx).Info("final password length after processing", "password_length", len(user.PasswordBase64))
 	} else {
 		// If no password provided, use the existing one
-		log.Printf("No password update requested, keeping existing password")
+		logging.FromContext(ctx).Info("no password update requested, keeping existing password")
 		user.PasswordBase64 = existingUser.PasswordBase64
 	}
 
 	// Update user
 	updatedUser, err := c.repository.Update(ctx, user)
 	if err != nil {
-		log.Printf("Error updating user: %v", err)
+		logging.FromContext(ctx).Error("error updating user", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("User %s updated successfully", user.Email)
+	logging.FromContext(ctx).Info("user updated successfully", "email", user.Email)
 	ctx.JSON(http.StatusOK, updatedUser)
 }
 
@@ -362,7 +479,7 @@ func (c *UserController) Update(ctx *gin.Context) {
 func (c *UserController) Delete(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -374,7 +491,7 @@ func (c *UserController) Delete(ctx *gin.Context) {
 	}
 
 	if existingUser == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
 		return
 	}
 
@@ -404,7 +521,7 @@ func (c *UserController) Delete(ctx *gin.Context) {
 func (c *UserController) ChangePassword(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
 		return
 	}
 
@@ -418,27 +535,27 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	log.Printf("Password change request for user ID: %s", id.String())
+	logging.FromContext(ctx).Info("password change request for user", "id", id.String())
 
 	// Get existing user
 	existingUser, err := c.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("Error retrieving user: %v", err)
+		logging.FromContext(ctx).Error("error retrieving user", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	if existingUser == nil {
-		log.Printf("User not found with ID: %s", id.String())
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		logging.FromContext(ctx).Warn("user not found", "id", id.String())
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
 		return
 	}
 
 	// Verify current password
 	currentPasswordEncoded := base64.StdEncoding.EncodeToString([]byte(passwordChangeRequest.CurrentPassword))
 	if currentPasswordEncoded != existingUser.PasswordBase64 {
-		log.Printf("Current password verification failed for user: %s", existingUser.Email)
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Contraseña actual incorrecta"})
+		logging.FromContext(ctx).Error("current password verification failed for user", "email", existingUser.Email)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "CurrentPasswordIncorrect"})
 		return
 	}
 
@@ -449,12 +566,12 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 	existingUser.PasswordBase64 = newPasswordEncoded
 	updatedUser, err := c.repository.Update(ctx, *existingUser)
 	if err != nil {
-		log.Printf("Error updating user password: %v", err)
+		logging.FromContext(ctx).Error("error updating user password", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("Password changed successfully for user: %s", existingUser.Email)
+	logging.FromContext(ctx).Info("password changed successfully for user", "email", existingUser.Email)
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Contraseña actualizada exitosamente",
@@ -466,16 +583,75 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 	})
 }
 
+// UpdateMyLocale sets the language the authenticated user receives emails and API errors in
+// @Summary Set my language
+// @Description Sets the locale of the emails and the API errors of the authenticated user, es-CO or en-US. An empty locale follows the Accept-Language header and the organization again. The API errors use it from the next login.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param locale body object true "Locale, e.g. {\"locale\": \"en-US\"}"
+// @Success 200 {object} object
+// @Failure 400 {object} string "Unsupported locale"
+// @Router /users/me/locale [put]
+func (c *UserController) UpdateMyLocale(ctx *gin.Context) {
+	userID, ok := currentUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Locale string `json:"locale"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+
+	// A single locale is a valid Accept-Language header
+	locale := ""
+	if request.Locale != "" {
+		locale = i18n.FromAcceptLanguage(request.Locale)
+		if locale == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnsupportedLocale"})
+			return
+		}
+	}
+
+	user, err := c.repository.GetByID(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "UserNotFound"})
+		return
+	}
+
+	user.Locale = locale
+	updatedUser, err := c.repository.Update(ctx, *user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"locale": updatedUser.Locale})
+}
+
 // RegisterRoutes sets up the user routes
 func (c *UserController) RegisterRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
 		users.GET("", c.GetAll)
+		users.PUT("/me/locale", c.UpdateMyLocale)
 		users.GET("/:id", c.GetByID)
 		users.GET("/email", c.GetByEmail)
 		users.POST("", c.Create)
 		users.PUT("/:id", c.Update)
-		users.PUT("/:id/change-password", c.ChangePassword)
+		users.PUT("/:id/change-password", middleware.ForbidImpersonation(), c.ChangePassword)
 		users.DELETE("/:id", c.Delete)
 	}
 }
+
+// RegisterAdminRoutes registers the login attempt routes on an admin-protected group
+func (c *UserController) RegisterAdminRoutes(adminRouter *gin.RouterGroup) {
+	adminRouter.GET("/login-attempts", c.GetLoginAttempts)
+}
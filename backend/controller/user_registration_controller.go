@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/middleware"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// UserRegistrationController handles the self-registration of new users and the verification
+// of their emails
+type UserRegistrationController struct {
+	userRepo      storage.UserStore
+	personRepo    storage.PersonStore
+	verifications *service.EmailVerificationService
+}
+
+// NewUserRegistrationController creates a new UserRegistrationController
+func NewUserRegistrationController(userRepo storage.UserStore, personRepo storage.PersonStore, verifications *service.EmailVerificationService) *UserRegistrationController {
+	return &UserRegistrationController{
+		userRepo:      userRepo,
+		personRepo:    personRepo,
+		verifications: verifications,
+	}
+}
+
+// RegisterPublicRoutes registers the self-registration routes, limited per client IP by the
+// Register limit (RATE_LIMIT_REGISTER)
+func (c *UserRegistrationController) RegisterPublicRoutes(router *gin.RouterGroup, limits config.RateLimitConfig) {
+	limit := middleware.RateLimit("register", middleware.RateLimitOr(limits.Register, "10/1h"), middleware.ClientIPKey)
+
+	auth := router.Group("/auth", limit)
+	{
+		auth.POST("/register", c.Register)
+		auth.POST("/verify-email", c.VerifyEmail)
+		auth.POST("/resend-verification", c.ResendVerification)
+	}
+}
+
+// Register creates a user in newuser status and emails them a verification link
+// @Summary Register a new user
+// @Description Creates a manager account in newuser status that cannot log in until its email is verified. Once verified, the admins are notified to approve it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param registration body object true "Name, email, password and optional phone"
+// @Success 201 {object} object
+// @Failure 400 {object} string "Bad request"
+// @Failure 409 {object} string "Email already registered"
+// @Router /auth/register [post]
+func (c *UserRegistrationController) Register(ctx *gin.Context) {
+	var request struct {
+		Name     string `json:"name" binding:"required"`
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required,min=8"`
+		Phone    string `json:"phone"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	email := strings.TrimSpace(request.Email)
+
+	existingUser, err := c.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		logging.FromContext(ctx).Error("error checking for existing user", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCheckExistingUser"})
+		return
+	}
+	if existingUser != nil {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "EmailAlreadyRegistered"})
+		return
+	}
+
+	// The NIT is completed by the user during onboarding
+	person := model.Person{
+		ID:       uuid.New(),
+		FullName: strings.TrimSpace(request.Name),
+		Phone:    request.Phone,
+		NIT:      generateRandomNIT(),
+	}
+	if _, err := c.personRepo.Create(ctx, person); err != nil {
+		logging.FromContext(ctx).Error("error creating person record", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreatePersonRecord"})
+		return
+	}
+
+	user := model.User{
+		ID:                       uuid.New(),
+		Email:                    email,
+		PasswordBase64:           base64.StdEncoding.EncodeToString([]byte(request.Password)),
+		Role:                     "manager",
+		PersonID:                 person.ID,
+		Status:                   "newuser",
+		EmailPendingVerification: true,
+	}
+	if _, err := c.userRepo.Create(ctx, user); err != nil {
+		logging.FromContext(ctx).Error("error creating user record", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToCreateUserAccount"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("user registered, awaiting email verification", "component", "registration", "email", user.Email)
+	c.verifications.SendVerification(&user, person.FullName, service.OrganizationBaseURL(middleware.GetOrganization(ctx)), time.Now())
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Te enviamos un email para verificar tu cuenta",
+		"user": gin.H{
+			"id":     user.ID,
+			"email":  user.Email,
+			"status": user.Status,
+		},
+	})
+}
+
+// VerifyEmail verifies the email of a registered user with the token of the emailed link
+// @Summary Verify the email of a registered user
+// @Description Lets the user log in and notifies the admins that the account awaits their approval
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body object true "Token of the verification link"
+// @Success 200 {object} object
+// @Failure 400 {object} string "Invalid or expired token"
+// @Router /auth/verify-email [post]
+func (c *UserRegistrationController) VerifyEmail(ctx *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.verifications.Verify(ctx, request.Token, time.Now())
+	if errors.Is(err, service.ErrInvalidEmailVerificationToken) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "LinkInvalid"})
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("error verifying email", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Email verificado. Ya puedes iniciar sesión",
+		"user": gin.H{
+			"id":     user.ID,
+			"email":  user.Email,
+			"status": user.Status,
+		},
+	})
+}
+
+// ResendVerification emails a new verification link to a registered user
+// @Summary Resend the verification email
+// @Description The response is the same whether the email is pending verification or not
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body object true "Email of the account"
+// @Success 202 {object} object
+// @Failure 400 {object} string "Bad request"
+// @Router /auth/resend-verification [post]
+func (c *UserRegistrationController) ResendVerification(ctx *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userRepo.GetByEmail(ctx, strings.TrimSpace(request.Email))
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching user", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "RequestNotProcessed"})
+		return
+	}
+	if user != nil && user.EmailPendingVerification {
+		name := ""
+		if person, err := c.personRepo.GetByID(ctx, user.PersonID); err == nil && person != nil {
+			name = person.FullName
+		}
+		c.verifications.SendVerification(user, name, service.OrganizationBaseURL(middleware.GetOrganization(ctx)), time.Now())
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Si la cuenta está pendiente de verificación, recibirás un nuevo enlace",
+	})
+}
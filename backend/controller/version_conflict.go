@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondVersionConflict answers an update rejected because the record changed since the client
+// read it (storage.ErrVersionConflict). The current record is returned, when it could be read,
+// so the client can show the other edit and retry with its version.
+func respondVersionConflict[T any](ctx *gin.Context, current *T) {
+	body := gin.H{"error": "VersionConflict"}
+	if current != nil {
+		body["current"] = current
+	}
+	ctx.JSON(http.StatusConflict, body)
+}
+
+// requireVersion makes an update apply to the version the client read, sent as the version of
+// the body or in the If-Match header (the header wins). Updates without one would overwrite the
+// edits made since, so they are answered 428 Precondition Required and false is returned.
+func requireVersion(ctx *gin.Context, version *int) bool {
+	if header := ctx.GetHeader("If-Match"); header != "" {
+		value, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+		if err != nil || value < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIfMatch"})
+			return false
+		}
+		*version = value
+	}
+	if *version < 1 {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "VersionRequired"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// webhookDeliveriesLimit is how many recent deliveries are listed for a webhook
+const webhookDeliveriesLimit = 50
+
+// WebhookController handles the callback URLs managers register to receive the signing events
+type WebhookController struct {
+	repository storage.WebhookStore
+	dispatcher *service.SigningWebhookDispatcher
+}
+
+// NewWebhookController creates a new WebhookController
+func NewWebhookController(repository storage.WebhookStore, dispatcher *service.SigningWebhookDispatcher) *WebhookController {
+	return &WebhookController{
+		repository: repository,
+		dispatcher: dispatcher,
+	}
+}
+
+// WebhookRequest defines a webhook registration. Omitted events subscribe to every event.
+type WebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	AllRequests bool     `json:"all_requests"` // Admins only, events of every signing request
+	Active      *bool    `json:"active"`       // Defaults to true
+}
+
+// RegisterRoutes registers the webhook routes, available to managers and admins
+func (c *WebhookController) RegisterRoutes(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.GET("", c.List)
+		webhooks.GET("/events", c.ListEvents)
+		webhooks.POST("", c.Create)
+		webhooks.PUT("/:id", c.Update)
+		webhooks.DELETE("/:id", c.Delete)
+		webhooks.GET("/:id/deliveries", c.GetDeliveries)
+		webhooks.POST("/:id/test", c.SendTest)
+	}
+}
+
+// List returns the webhooks of the user
+func (c *WebhookController) List(ctx *gin.Context) {
+	authUser, ok := webhookUser(ctx)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := c.repository.GetSubscriptionsByPersonID(ctx, authUser.PersonID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, webhookResponse(subscription))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListEvents returns the events a webhook can subscribe to
+func (c *WebhookController) ListEvents(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"events": model.WebhookEvents})
+}
+
+// Create registers a webhook. The secret the payloads are signed with is only returned here.
+func (c *WebhookController) Create(ctx *gin.Context) {
+	authUser, ok := webhookUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !validWebhookRequest(ctx, authUser, &req) {
+		return
+	}
+
+	secret, err := service.GenerateWebhookSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToGenerateWebhookSecret"})
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	created, err := c.repository.CreateSubscription(ctx, model.WebhookSubscription{
+		ID:          uuid.New(),
+		PersonID:    authUser.PersonID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		AllRequests: req.AllRequests,
+		Description: req.Description,
+		Active:      active,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToRegisterWebhook"})
+		return
+	}
+
+	logging.FromContext(ctx).Info("webhook registered", "subscription_id", created.ID, "email", authUser.Email, "url", created.URL)
+	response := webhookResponse(*created)
+	response["secret"] = created.Secret
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Update changes the URL, events or state of a webhook
+func (c *WebhookController) Update(ctx *gin.Context) {
+	authUser, subscription, ok := c.ownedSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidInput", "detail": err.Error()})
+		return
+	}
+	if !validWebhookRequest(ctx, authUser, &req) {
+		return
+	}
+
+	subscription.URL = req.URL
+	subscription.Events = req.Events
+	subscription.AllRequests = req.AllRequests
+	subscription.Description = req.Description
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	updated, err := c.repository.UpdateSubscription(ctx, *subscription)
+	if err != nil || updated == nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToUpdateWebhook"})
+		return
+	}
+	ctx.JSON(http.StatusOK, webhookResponse(*updated))
+}
+
+// Delete removes a webhook and its deliveries
+func (c *WebhookController) Delete(ctx *gin.Context) {
+	_, subscription, ok := c.ownedSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.repository.DeleteSubscription(ctx, subscription.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToDeleteWebhook"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetDeliveries lists the recent deliveries of a webhook with their result
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	_, subscription, ok := c.ownedSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.repository.GetDeliveriesBySubscriptionID(ctx, subscription.ID, webhookDeliveriesLimit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// SendTest posts a ping to a webhook and returns the result of the delivery
+func (c *WebhookController) SendTest(ctx *gin.Context) {
+	_, subscription, ok := c.ownedSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.dispatcher.SendTest(ctx, *subscription)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "FailedToQueueTestEvent"})
+		return
+	}
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// ownedSubscription loads the webhook of the path, writing the error response when it does not
+// exist or belongs to someone else
+func (c *WebhookController) ownedSubscription(ctx *gin.Context) (*model.User, *model.WebhookSubscription, bool) {
+	authUser, ok := webhookUser(ctx)
+	if !ok {
+		return nil, nil, false
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "InvalidIDFormat"})
+		return nil, nil, false
+	}
+
+	subscription, err := c.repository.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if subscription == nil || (subscription.PersonID != authUser.PersonID && authUser.Role != "admin") {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "WebhookNotFound"})
+		return nil, nil, false
+	}
+	return authUser, subscription, true
+}
+
+// webhookUser returns the authenticated user when they are a manager or an admin
+func webhookUser(ctx *gin.Context) (*model.User, bool) {
+	authUser, ok := getAuthenticatedUser(ctx)
+	if !ok {
+		return nil, false
+	}
+	if authUser.Role != "admin" && authUser.Role != "manager" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyManagersManageWebhooks"})
+		return nil, false
+	}
+	return authUser, true
+}
+
+// validWebhookRequest checks the URL and events of a webhook, writing the error response when
+// they are invalid
+func validWebhookRequest(ctx *gin.Context, authUser *model.User, req *WebhookRequest) bool {
+	req.URL = strings.TrimSpace(req.URL)
+	if err := service.ValidateWebhookURL(req.URL); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	for _, event := range req.Events {
+		if !model.IsValidWebhookEvent(event) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "UnknownEvent", "detail": event, "events": model.WebhookEvents})
+			return false
+		}
+	}
+	if req.AllRequests && authUser.Role != "admin" {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "OnlyAdminsReceiveAllSigningEvents"})
+		return false
+	}
+	return true
+}
+
+// webhookResponse returns a webhook without its secret
+func webhookResponse(subscription model.WebhookSubscription) gin.H {
+	return gin.H{
+		"id":           subscription.ID,
+		"person_id":    subscription.PersonID,
+		"url":          subscription.URL,
+		"events":       subscription.Events,
+		"all_requests": subscription.AllRequests,
+		"description":  subscription.Description,
+		"active":       subscription.Active,
+		"created_at":   subscription.CreatedAt,
+	}
+}
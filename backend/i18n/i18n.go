@@ -0,0 +1,123 @@
+// Package i18n translates the messages of the API, the JSON errors and the emails, to the
+// locales of the catalogs in locales/ (es-CO and en-US). Messages are keyed by an ID; the
+// locale of a request comes from the preference of the user or the Accept-Language header.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Locales of the catalogs
+const (
+	Spanish = "es-CO"
+	English = "en-US"
+)
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+var (
+	bundleOnce sync.Once
+	bundle     *goi18n.Bundle
+	matcher    language.Matcher
+
+	localizers sync.Map
+)
+
+// load parses the embedded catalogs once
+func load() {
+	bundleOnce.Do(func() {
+		bundle = goi18n.NewBundle(language.MustParse(Spanish))
+		bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+
+		for _, locale := range []string{Spanish, English} {
+			if _, err := bundle.LoadMessageFileFS(catalogs, "locales/"+locale+".json"); err != nil {
+				slog.Error("failed to load the translations", "locale", locale, "error", err)
+				os.Exit(1)
+			}
+		}
+		matcher = language.NewMatcher(bundle.LanguageTags())
+	})
+}
+
+// defaultLocale is the locale of the application, set at startup by SetDefaultLocale
+var defaultLocale atomic.Value
+
+// SetDefaultLocale sets the locale of the application (APP_LOCALE)
+func SetDefaultLocale(locale string) {
+	defaultLocale.Store(Normalize(locale))
+}
+
+// DefaultLocale returns the locale of the application, es-CO until SetDefaultLocale is called
+func DefaultLocale() string {
+	if locale, ok := defaultLocale.Load().(string); ok {
+		return locale
+	}
+	return Spanish
+}
+
+// Normalize returns the catalog locale closest to locale: en-US for English locales and
+// es-CO for every other one, including an empty locale
+func Normalize(locale string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(locale)), "en") {
+		return English
+	}
+	return Spanish
+}
+
+// FromAcceptLanguage returns the catalog locale that best matches an Accept-Language header,
+// or "" when the header is empty or does not parse
+func FromAcceptLanguage(header string) string {
+	if strings.TrimSpace(header) == "" {
+		return ""
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+
+	load()
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return ""
+	}
+	return bundle.LanguageTags()[index].String()
+}
+
+// localizer returns the localizer of a catalog locale
+func localizer(locale string) *goi18n.Localizer {
+	load()
+	locale = Normalize(locale)
+	if cached, ok := localizers.Load(locale); ok {
+		return cached.(*goi18n.Localizer)
+	}
+	l, _ := localizers.LoadOrStore(locale, goi18n.NewLocalizer(bundle, locale))
+	return l.(*goi18n.Localizer)
+}
+
+// Lookup returns the message id in locale, executed with data when the message is a template.
+// ok is false when no catalog has the message.
+func Lookup(locale, id string, data interface{}) (string, bool) {
+	message, err := localizer(locale).Localize(&goi18n.LocalizeConfig{MessageID: id, TemplateData: data})
+	if err != nil {
+		return "", false
+	}
+	return message, true
+}
+
+// T returns the message id in locale, or the id itself when no catalog has it
+func T(locale, id string, data interface{}) string {
+	if message, ok := Lookup(locale, id, data); ok {
+		return message
+	}
+	return id
+}
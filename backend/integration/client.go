@@ -0,0 +1,169 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+)
+
+// Client is a small JSON client for the API used by integration scenarios
+type Client struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// Response is the raw result of an API call
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode unmarshals the response body into v
+func (r *Response) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// NewClient creates a client for the API served at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Login authenticates with a plain text password and keeps the JWT for later calls.
+// The password is base64 encoded the same way the frontend does.
+func (c *Client) Login(email, password string) error {
+	resp, err := c.Post("/api/users/login", map[string]string{
+		"email":    email,
+		"password": base64.StdEncoding.EncodeToString([]byte(password)),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("login failed with status %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := resp.Decode(&body); err != nil {
+		return fmt.Errorf("parsing login response: %w", err)
+	}
+	if body.Token == "" {
+		return fmt.Errorf("login response without token: %s", resp.Body)
+	}
+
+	c.Token = body.Token
+	return nil
+}
+
+// Get performs a GET request
+func (c *Client) Get(path string) (*Response, error) {
+	return c.Do(http.MethodGet, path, nil)
+}
+
+// Post performs a POST request with a JSON body
+func (c *Client) Post(path string, body interface{}) (*Response, error) {
+	return c.Do(http.MethodPost, path, body)
+}
+
+// Put performs a PUT request with a JSON body
+func (c *Client) Put(path string, body interface{}) (*Response, error) {
+	return c.Do(http.MethodPut, path, body)
+}
+
+// Delete performs a DELETE request
+func (c *Client) Delete(path string) (*Response, error) {
+	return c.Do(http.MethodDelete, path, nil)
+}
+
+// Do sends a request with an optional JSON body
+func (c *Client) Do(method, path string, body interface{}) (*Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request body: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(req)
+}
+
+// Upload sends a multipart form with a single file plus optional extra fields
+func (c *Client) Upload(path, fieldName, fileName, contentType string, content []byte, fields map[string]string) (*Response, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	partHeader := make(textproto.MIMEHeader)
+	partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, fieldName, fileName))
+	partHeader.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(partHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+path, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.send(req)
+}
+
+func (c *Client) send(req *http.Request) (*Response, error) {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}, nil
+}
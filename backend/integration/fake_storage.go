@@ -0,0 +1,278 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// storedObject is a file kept in memory by FakeStorage
+type storedObject struct {
+	ID          string
+	Data        []byte
+	ContentType string
+	CreatedAt   time.Time
+}
+
+// FakeStorage is an in-memory implementation of the subset of the Supabase Storage
+// HTTP API used by service.SupabaseStorageService (buckets, upload, download, list, remove, sign).
+type FakeStorage struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]*storedObject
+}
+
+// NewFakeStorage creates an empty fake storage backend
+func NewFakeStorage() *FakeStorage {
+	return &FakeStorage{
+		buckets: make(map[string]map[string]*storedObject),
+	}
+}
+
+// Objects returns the paths stored in a bucket, sorted
+func (f *FakeStorage) Objects(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var paths []string
+	for path := range f.buckets[bucket] {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Object returns the content of a stored file
+func (f *FakeStorage) Object(bucket, path string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	obj, ok := f.buckets[bucket][path]
+	if !ok {
+		return nil, false
+	}
+	return obj.Data, true
+}
+
+// Reset removes every bucket and file
+func (f *FakeStorage) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.buckets = make(map[string]map[string]*storedObject)
+}
+
+// ServeHTTP handles requests whose path is relative to /storage/v1
+func (f *FakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case path == "bucket" && r.Method == http.MethodPost:
+		f.createBucket(w, r)
+	case strings.HasPrefix(path, "bucket/") && r.Method == http.MethodGet:
+		f.getBucket(w, strings.TrimPrefix(path, "bucket/"))
+	case strings.HasPrefix(path, "object/list/") && r.Method == http.MethodPost:
+		f.listObjects(w, r, strings.TrimPrefix(path, "object/list/"))
+	case strings.HasPrefix(path, "object/sign/") && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusOK, map[string]string{"signedURL": "/object/" + strings.TrimPrefix(path, "object/sign/") + "?token=fake"})
+	case strings.HasPrefix(path, "object/public/") && r.Method == http.MethodGet:
+		f.download(w, strings.TrimPrefix(path, "object/public/"))
+	case strings.HasPrefix(path, "object/") && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		f.upload(w, r, strings.TrimPrefix(path, "object/"))
+	case strings.HasPrefix(path, "object/") && r.Method == http.MethodGet:
+		f.download(w, strings.TrimPrefix(path, "object/"))
+	case strings.HasPrefix(path, "object/") && r.Method == http.MethodDelete:
+		f.remove(w, r, strings.TrimPrefix(path, "object/"))
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"statusCode": "404", "error": "not_found", "message": "unsupported fake storage route"})
+	}
+}
+
+func (f *FakeStorage) createBucket(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"statusCode": "400", "error": "invalid_body", "message": err.Error()})
+		return
+	}
+
+	name := body.ID
+	if name == "" {
+		name = body.Name
+	}
+
+	f.mu.Lock()
+	if _, exists := f.buckets[name]; !exists {
+		f.buckets[name] = make(map[string]*storedObject)
+	}
+	f.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{"name": name})
+}
+
+func (f *FakeStorage) getBucket(w http.ResponseWriter, name string) {
+	f.mu.Lock()
+	_, exists := f.buckets[name]
+	f.mu.Unlock()
+
+	if !exists {
+		writeJSON(w, http.StatusNotFound, map[string]string{"statusCode": "404", "error": "Bucket not found", "message": "Bucket not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": name, "name": name, "public": false})
+}
+
+func (f *FakeStorage) upload(w http.ResponseWriter, r *http.Request, fullPath string) {
+	bucket, objectPath, ok := strings.Cut(fullPath, "/")
+	if !ok || objectPath == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"statusCode": "400", "error": "invalid_path", "message": "object path required"})
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"statusCode": "400", "error": "invalid_body", "message": err.Error()})
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/json" {
+		contentType = http.DetectContentType(data)
+	}
+
+	f.mu.Lock()
+	if _, exists := f.buckets[bucket]; !exists {
+		f.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, map[string]string{"statusCode": "404", "error": "Bucket not found", "message": "Bucket not found"})
+		return
+	}
+	f.buckets[bucket][objectPath] = &storedObject{
+		ID:          uuid.New().String(),
+		Data:        data,
+		ContentType: contentType,
+		CreatedAt:   time.Now(),
+	}
+	f.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{"Key": fullPath})
+}
+
+func (f *FakeStorage) download(w http.ResponseWriter, fullPath string) {
+	bucket, objectPath, _ := strings.Cut(fullPath, "/")
+
+	data, ok := f.Object(bucket, objectPath)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"statusCode": "404", "error": "not_found", "message": "Object not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func (f *FakeStorage) remove(w http.ResponseWriter, r *http.Request, bucket string) {
+	var body struct {
+		Prefixes []string `json:"prefixes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"statusCode": "400", "error": "invalid_body", "message": err.Error()})
+		return
+	}
+
+	var removed []map[string]string
+	f.mu.Lock()
+	for _, p := range body.Prefixes {
+		if _, exists := f.buckets[bucket][p]; exists {
+			delete(f.buckets[bucket], p)
+			removed = append(removed, map[string]string{"Key": bucket + "/" + p})
+		}
+	}
+	f.mu.Unlock()
+
+	if removed == nil {
+		removed = []map[string]string{}
+	}
+	writeJSON(w, http.StatusOK, removed)
+}
+
+// listObjects mimics Supabase listing: only the direct children of the prefix are returned,
+// sub-folders appear as entries without id or metadata
+func (f *FakeStorage) listObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	var body struct {
+		Prefix string `json:"prefix"`
+		Limit  int    `json:"limit"`
+		Offset int    `json:"offset"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"statusCode": "400", "error": "invalid_body", "message": err.Error()})
+		return
+	}
+
+	prefix := strings.Trim(body.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	f.mu.Lock()
+	folders := make(map[string]bool)
+	var entries []map[string]interface{}
+	for path, obj := range f.buckets[bucket] {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(path, prefix)
+		if folder, _, nested := strings.Cut(rest, "/"); nested {
+			folders[folder] = true
+			continue
+		}
+		entries = append(entries, map[string]interface{}{
+			"name":       rest,
+			"id":         obj.ID,
+			"bucket_id":  bucket,
+			"created_at": obj.CreatedAt.Format(time.RFC3339),
+			"updated_at": obj.CreatedAt.Format(time.RFC3339),
+			"metadata": map[string]interface{}{
+				"size":     len(obj.Data),
+				"mimetype": obj.ContentType,
+			},
+		})
+	}
+	f.mu.Unlock()
+
+	for folder := range folders {
+		entries = append(entries, map[string]interface{}{"name": folder, "id": nil, "metadata": nil})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i]["name"].(string) < entries[j]["name"].(string)
+	})
+
+	if body.Offset > 0 && body.Offset < len(entries) {
+		entries = entries[body.Offset:]
+	} else if body.Offset >= len(entries) {
+		entries = nil
+	}
+	if body.Limit > 0 && len(entries) > body.Limit {
+		entries = entries[:body.Limit]
+	}
+	if entries == nil {
+		entries = []map[string]interface{}{}
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,165 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FixturePassword is the plain text password of every seeded user
+const FixturePassword = "password123"
+
+// SeededUser is a user created by Seed together with its person record
+type SeededUser struct {
+	UserID   uuid.UUID
+	PersonID uuid.UUID
+	Email    string
+	Role     string
+}
+
+// Fixtures holds the identifiers of the records created by Seed
+type Fixtures struct {
+	Admin         SeededUser
+	Manager       SeededUser
+	Resident      SeededUser
+	PropertyID    uuid.UUID
+	BankAccountID uuid.UUID
+	RentalID      uuid.UUID
+	PricingID     uuid.UUID
+}
+
+// Seed inserts a minimal but complete data set: one admin, one manager owning a
+// property and a resident renting it with bank account and pricing.
+// Rows are written straight to PostgREST so the fixtures do not depend on the
+// repositories under test.
+func (h *Harness) Seed() (*Fixtures, error) {
+	f := &Fixtures{
+		PropertyID:    uuid.New(),
+		BankAccountID: uuid.New(),
+		RentalID:      uuid.New(),
+		PricingID:     uuid.New(),
+	}
+
+	var err error
+	if f.Admin, err = h.seedUser("Admin Pruebas", "admin@integration.test", "admin"); err != nil {
+		return nil, err
+	}
+	if f.Manager, err = h.seedUser("Manager Pruebas", "manager@integration.test", "manager"); err != nil {
+		return nil, err
+	}
+	if f.Resident, err = h.seedUser("Residente Pruebas", "resident@integration.test", "resident"); err != nil {
+		return nil, err
+	}
+
+	if err := h.insert("property", map[string]interface{}{
+		"id":          f.PropertyID,
+		"address":     "Calle 123 #45-67",
+		"apt_number":  "201",
+		"city":        "Bogotá",
+		"state":       "Cundinamarca",
+		"zip_code":    "110111",
+		"type":        "apartment",
+		"resident_id": f.Resident.PersonID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := h.insert("property_managers", map[string]interface{}{
+		"property_id":       f.PropertyID,
+		"manager_person_id": f.Manager.PersonID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := h.insert("bank_account", map[string]interface{}{
+		"id":             f.BankAccountID,
+		"person_id":      f.Manager.PersonID,
+		"bank_name":      "Bancolombia",
+		"account_type":   "Ahorros",
+		"account_number": "0001234567",
+		"account_holder": "Manager Pruebas",
+	}); err != nil {
+		return nil, err
+	}
+
+	start := time.Now().AddDate(0, -1, 0).Truncate(24 * time.Hour)
+	if err := h.insert("rental", map[string]interface{}{
+		"id":              f.RentalID,
+		"property_id":     f.PropertyID,
+		"renter_id":       f.Resident.PersonID,
+		"bank_account_id": f.BankAccountID,
+		"start_date":      start,
+		"end_date":        start.AddDate(1, 0, 0),
+		"payment_terms":   "Mensual",
+		"unpaid_months":   0,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := h.insert("pricing", map[string]interface{}{
+		"id":                     f.PricingID,
+		"rental_id":              f.RentalID,
+		"monthly_rent":           1500000,
+		"security_deposit":       1500000,
+		"utilities_included":     []string{"agua"},
+		"tenant_responsible_for": []string{"energía", "gas"},
+		"late_fee":               50000,
+		"due_day":                5,
+	}); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// LoginAs returns a client authenticated as the given seeded user
+func (h *Harness) LoginAs(user SeededUser) (*Client, error) {
+	client := h.NewClient()
+	if err := client.Login(user.Email, FixturePassword); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (h *Harness) seedUser(fullName, email, role string) (SeededUser, error) {
+	user := SeededUser{
+		UserID:   uuid.New(),
+		PersonID: uuid.New(),
+		Email:    email,
+		Role:     role,
+	}
+
+	if err := h.insert("person", map[string]interface{}{
+		"id":        user.PersonID,
+		"full_name": fullName,
+		"phone":     "3000000000",
+		"nit":       "900123456",
+	}); err != nil {
+		return SeededUser{}, err
+	}
+
+	if err := h.insert("users", map[string]interface{}{
+		"id":              user.UserID,
+		"email":           email,
+		"password_base64": base64.StdEncoding.EncodeToString([]byte(FixturePassword)),
+		"role":            role,
+		"person_id":       user.PersonID,
+		"status":          "active",
+	}); err != nil {
+		return SeededUser{}, err
+	}
+
+	return user, nil
+}
+
+func (h *Harness) insert(table string, row map[string]interface{}) error {
+	_, _, err := h.Repos.GetClient().From(table).Insert(row, false, "", "minimal", "").Execute()
+	if err != nil {
+		return fmt.Errorf("seeding %s: %w", table, err)
+	}
+	return nil
+}
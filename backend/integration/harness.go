@@ -0,0 +1,189 @@
+//go:build integration
+
+// Package integration provides an end-to-end harness for the backend: the full Gin
+// router is served by httptest, database calls go to a throwaway PostgREST instance
+// (see docker-compose.yml and schema.sql) and file storage is replaced by FakeStorage.
+//
+// Everything in this package is behind the "integration" build tag so it never
+// becomes part of the production binary.
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nescool101/rentManager/app"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/controller"
+	"github.com/nescool101/rentManager/service"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DefaultPostgRESTURL is where docker-compose.yml exposes PostgREST
+const DefaultPostgRESTURL = "http://localhost:3001"
+
+// Harness runs the whole HTTP stack against an ephemeral database
+type Harness struct {
+	// Server serves the API exactly as controller.StartHTTPServer would
+	Server *httptest.Server
+	// Gateway impersonates the Supabase project: /rest/v1 is proxied to PostgREST
+	// and /storage/v1 is answered by Storage
+	Gateway *httptest.Server
+	Storage *FakeStorage
+	Repos   *storage.RepositoryFactory
+
+	cfg *config.Config
+}
+
+// Start boots the gateway and the API server.
+// The PostgREST URL is read from INTEGRATION_POSTGREST_URL (DefaultPostgRESTURL if unset).
+func Start() (*Harness, error) {
+	postgrestURL := os.Getenv("INTEGRATION_POSTGREST_URL")
+	if postgrestURL == "" {
+		postgrestURL = DefaultPostgRESTURL
+	}
+
+	target, err := url.Parse(postgrestURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTEGRATION_POSTGREST_URL: %w", err)
+	}
+
+	if err := waitForPostgREST(postgrestURL, 30*time.Second); err != nil {
+		return nil, err
+	}
+
+	fakeStorage := NewFakeStorage()
+	gateway := httptest.NewServer(newGatewayHandler(target, fakeStorage))
+
+	// Point the application at the gateway and keep side effects local
+	telegram := false
+	// Fixtures write the tables directly, the server must not keep stale copies
+	noCache := time.Duration(0)
+	cfg := &config.Config{
+		Profile: config.ProfileDev,
+		Server:  config.ServerConfig{Port: "8080"},
+		Supabase: config.SupabaseConfig{
+			URL:           gateway.URL,
+			Key:           "integration-test-key",
+			StorageBucket: "uploads",
+		},
+		Database: config.DatabaseConfig{ReferenceCacheTTL: &noCache},
+		Auth:     config.AuthConfig{JWTSecret: "integration-test-jwt-secret"},
+		Email: config.EmailConfig{
+			Driver:   config.EmailDriverSMTP,
+			FromName: "Integration Tests",
+			SMTP: config.SMTPConfig{
+				Username: "integration@localhost",
+				Password: "integration",
+				Host:     "127.0.0.1",
+				Port:     1, // Nothing listens here, emails fail fast and are only logged
+			},
+		},
+		Features: config.FeatureFlags{Telegram: &telegram},
+	}
+	if err := service.GenerateMissingSecrets(&cfg.Secrets); err != nil {
+		gateway.Close()
+		return nil, err
+	}
+	if err := service.InitializeEmail(cfg.Email); err != nil {
+		gateway.Close()
+		return nil, fmt.Errorf("initializing email: %w", err)
+	}
+	service.InitializeSMS(cfg.SMS)
+	service.InitializePublicURLs(cfg.URLs)
+	service.InitializeFormatter(cfg.Locale)
+	service.InitializeSigningCertificate(cfg.Signing)
+
+	if err := service.InitializeSupabaseStorageService(cfg); err != nil {
+		gateway.Close()
+		return nil, fmt.Errorf("initializing storage service: %w", err)
+	}
+
+	container, err := app.New(context.Background(), cfg)
+	if err != nil {
+		gateway.Close()
+		return nil, fmt.Errorf("initializing storage: %w", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router, err := controller.NewRouter(container)
+	if err != nil {
+		gateway.Close()
+		return nil, fmt.Errorf("building router: %w", err)
+	}
+
+	return &Harness{
+		Server:  httptest.NewServer(router),
+		Gateway: gateway,
+		Storage: fakeStorage,
+		Repos:   container.Repositories,
+		cfg:     cfg,
+	}, nil
+}
+
+// Close stops both servers
+func (h *Harness) Close() {
+	h.Server.Close()
+	h.Gateway.Close()
+}
+
+// Reset empties every table and the fake storage so scenarios do not leak into each other
+func (h *Harness) Reset() error {
+	response := h.Repos.GetClient().Rpc("reset_test_data", "", map[string]interface{}{})
+	if strings.Contains(response, `"message"`) {
+		return fmt.Errorf("reset_test_data failed: %s", response)
+	}
+	h.Storage.Reset()
+	// The storage service expects its bucket to exist
+	return service.InitializeSupabaseStorageService(h.cfg)
+}
+
+// NewClient returns an unauthenticated client for the API server
+func (h *Harness) NewClient() *Client {
+	return NewClient(h.Server.URL)
+}
+
+// newGatewayHandler routes Supabase URLs to PostgREST or the fake storage
+func newGatewayHandler(postgrest *url.URL, fakeStorage *FakeStorage) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(postgrest)
+	baseDirector := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		baseDirector(r)
+		// PostgREST runs without a JWT secret, so the anon key must not be forwarded
+		r.Header.Del("Authorization")
+		r.Header.Del("apikey")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/rest/v1/", http.StripPrefix("/rest/v1", proxy))
+	mux.Handle("/storage/v1/", http.StripPrefix("/storage/v1", fakeStorage))
+	return mux
+}
+
+// waitForPostgREST polls PostgREST until it answers or the timeout expires
+func waitForPostgREST(postgrestURL string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := http.Get(postgrestURL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("PostgREST not reachable at %s (run: docker compose -f integration/docker-compose.yml up -d): %v", postgrestURL, err)
+		}
+		log.Printf("Waiting for PostgREST at %s...", postgrestURL)
+		time.Sleep(time.Second)
+	}
+}
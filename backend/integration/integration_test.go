@@ -0,0 +1,224 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// harness serves the API of every test; each test resets the data before seeding its fixtures
+var harness *Harness
+
+func TestMain(m *testing.M) {
+	h, err := Start()
+	if err != nil {
+		log.Printf("Could not start the integration harness: %v", err)
+		os.Exit(1)
+	}
+	harness = h
+
+	code := m.Run()
+	h.Close()
+	os.Exit(code)
+}
+
+// setup empties the database and the storage and seeds the fixtures of a test
+func setup(t *testing.T) *Fixtures {
+	t.Helper()
+	if err := harness.Reset(); err != nil {
+		t.Fatalf("could not reset data: %v", err)
+	}
+	fixtures, err := harness.Seed()
+	if err != nil {
+		t.Fatalf("could not seed fixtures: %v", err)
+	}
+	return fixtures
+}
+
+// loginAs returns a client authenticated as a seeded user
+func loginAs(t *testing.T, user SeededUser) *Client {
+	t.Helper()
+	client, err := harness.LoginAs(user)
+	if err != nil {
+		t.Fatalf("could not log in as %s: %v", user.Email, err)
+	}
+	return client
+}
+
+// expectStatus fails the test when the call failed or answered another status
+func expectStatus(t *testing.T, resp *Response, err error, want int) *Response {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != want {
+		t.Fatalf("expected status %d, got %d: %s", want, resp.StatusCode, resp.Body)
+	}
+	return resp
+}
+
+// decode unmarshals the body of a response or fails the test
+func decode(t *testing.T, resp *Response, v interface{}) {
+	t.Helper()
+	if err := resp.Decode(v); err != nil {
+		t.Fatalf("could not parse response %s: %v", resp.Body, err)
+	}
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	f := setup(t)
+
+	if err := harness.NewClient().Login(f.Admin.Email, "wrong-password"); err == nil {
+		t.Fatal("login succeeded with a wrong password")
+	}
+}
+
+func TestAdminListsProperties(t *testing.T) {
+	f := setup(t)
+	admin := loginAs(t, f.Admin)
+
+	resp, err := admin.Get("/api/properties")
+	expectStatus(t, resp, err, http.StatusOK)
+
+	var properties []struct {
+		ID string `json:"id"`
+	}
+	decode(t, resp, &properties)
+	if len(properties) != 1 || properties[0].ID != f.PropertyID.String() {
+		t.Fatalf("expected the seeded property, got %s", resp.Body)
+	}
+}
+
+func TestResidentCannotUseAdminRoutes(t *testing.T) {
+	f := setup(t)
+	resident := loginAs(t, f.Resident)
+
+	resp, err := resident.Get("/api/admin/payments/late")
+	expectStatus(t, resp, err, http.StatusForbidden)
+}
+
+func TestGenerateContract(t *testing.T) {
+	f := setup(t)
+	admin := loginAs(t, f.Admin)
+
+	start := time.Now()
+	resp, err := admin.Post("/api/admin/contracts/generate", map[string]interface{}{
+		"renter_id":    f.Resident.PersonID,
+		"owner_id":     f.Manager.PersonID,
+		"property_id":  f.PropertyID,
+		"start_date":   start,
+		"end_date":     start.AddDate(1, 0, 0),
+		"monthly_rent": 1500000,
+	})
+	expectStatus(t, resp, err, http.StatusOK)
+	if ct := resp.Header.Get("Content-Type"); ct != "application/pdf" {
+		t.Fatalf("expected a PDF, got %q", ct)
+	}
+}
+
+func TestPaymentsLifecycle(t *testing.T) {
+	f := setup(t)
+	admin := loginAs(t, f.Admin)
+
+	resp, err := admin.Post("/api/admin/payments", map[string]interface{}{
+		"rental_id":    f.RentalID,
+		"payment_date": time.Now(),
+		"amount_paid":  1500000,
+		"paid_on_time": true,
+	})
+	expectStatus(t, resp, err, http.StatusCreated)
+
+	resident := loginAs(t, f.Resident)
+	resp, err = resident.Get("/api/payments/rental/" + f.RentalID.String())
+	expectStatus(t, resp, err, http.StatusOK)
+
+	var payments []map[string]interface{}
+	decode(t, resp, &payments)
+	if len(payments) != 1 {
+		t.Fatalf("expected 1 payment, got %d", len(payments))
+	}
+}
+
+func TestMaintenanceStatusHistory(t *testing.T) {
+	f := setup(t)
+	resident := loginAs(t, f.Resident)
+
+	resp, err := resident.Post("/api/maintenance-requests", map[string]interface{}{
+		"property_id": f.PropertyID,
+		"description": "Fuga en el lavaplatos",
+	})
+	expectStatus(t, resp, err, http.StatusCreated)
+
+	var request map[string]interface{}
+	decode(t, resp, &request)
+	request["status"] = "In Progress"
+
+	admin := loginAs(t, f.Admin)
+	requestID := fmt.Sprint(request["id"])
+	resp, err = admin.Put("/api/admin/maintenance-requests/"+requestID, request)
+	expectStatus(t, resp, err, http.StatusOK)
+
+	resp, err = resident.Get("/api/maintenance-requests/" + requestID + "/history")
+	expectStatus(t, resp, err, http.StatusOK)
+
+	var history []map[string]interface{}
+	decode(t, resp, &history)
+	if len(history) != 1 || history[0]["to_status"] != "In Progress" {
+		t.Fatalf("unexpected status history: %s", resp.Body)
+	}
+}
+
+// TestStaleRentalUpdateIsRejected saves two edits made from the same copy of a rental: the
+// second one must be rejected instead of overwriting the first
+func TestStaleRentalUpdateIsRejected(t *testing.T) {
+	f := setup(t)
+	admin := loginAs(t, f.Admin)
+
+	resp, err := admin.Get("/api/rentals/" + f.RentalID.String())
+	expectStatus(t, resp, err, http.StatusOK)
+	var rental map[string]interface{}
+	decode(t, resp, &rental)
+
+	rental["payment_terms"] = "Mes anticipado"
+	resp, err = admin.Put("/api/admin/rentals/"+f.RentalID.String(), rental)
+	expectStatus(t, resp, err, http.StatusOK)
+
+	rental["unpaid_months"] = 1
+	resp, err = admin.Put("/api/admin/rentals/"+f.RentalID.String(), rental)
+	expectStatus(t, resp, err, http.StatusConflict)
+}
+
+// TestUnversionedRentalUpdateIsRejected saves an edit without the version it was made from,
+// which could overwrite newer edits
+func TestUnversionedRentalUpdateIsRejected(t *testing.T) {
+	f := setup(t)
+	admin := loginAs(t, f.Admin)
+
+	resp, err := admin.Get("/api/rentals/" + f.RentalID.String())
+	expectStatus(t, resp, err, http.StatusOK)
+	var rental map[string]interface{}
+	decode(t, resp, &rental)
+
+	delete(rental, "version")
+	rental["payment_terms"] = "Mes anticipado"
+	resp, err = admin.Put("/api/admin/rentals/"+f.RentalID.String(), rental)
+	expectStatus(t, resp, err, http.StatusPreconditionRequired)
+}
+
+func TestAuthenticatedUploadLandsInStorage(t *testing.T) {
+	f := setup(t)
+	resident := loginAs(t, f.Resident)
+
+	content := []byte("%PDF-1.4\n% integration test document\n")
+	resp, err := resident.Upload("/api/upload/file-authenticated", "file", "cedula.pdf", "application/pdf", content, nil)
+	expectStatus(t, resp, err, http.StatusOK)
+
+	if objects := harness.Storage.Objects("uploads"); len(objects) != 1 {
+		t.Fatalf("expected 1 stored object, got %v", objects)
+	}
+}
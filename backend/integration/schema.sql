@@ -0,0 +1,1000 @@
+-- Esquema mínimo de la base de datos para la suite de integración.
+-- Refleja las tablas que usan los repositorios de backend/storage.
+-- No tiene llaves foráneas para permitir fixtures parciales.
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE person (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    full_name text NOT NULL DEFAULT '',
+    phone text NOT NULL DEFAULT '',
+    nit text NOT NULL DEFAULT '',
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE role (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    role_name text NOT NULL UNIQUE
+);
+
+CREATE TABLE person_role (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL,
+    role_id uuid NOT NULL
+);
+
+CREATE TABLE users (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    email text NOT NULL UNIQUE,
+    password_base64 text NOT NULL DEFAULT '',
+    role text NOT NULL DEFAULT 'user',
+    person_id uuid,
+    status text NOT NULL DEFAULT 'active',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    email_pending_verification boolean NOT NULL DEFAULT false,
+    locale text NOT NULL DEFAULT ''
+);
+
+CREATE TABLE property (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    address text NOT NULL DEFAULT '',
+    apt_number text NOT NULL DEFAULT '',
+    city text NOT NULL DEFAULT '',
+    state text NOT NULL DEFAULT '',
+    zip_code text NOT NULL DEFAULT '',
+    type text NOT NULL DEFAULT '',
+    resident_id uuid,
+    building_id uuid,
+    bedrooms integer NOT NULL DEFAULT 0,
+    bathrooms integer NOT NULL DEFAULT 0,
+    area_m2 numeric NOT NULL DEFAULT 0,
+    estrato integer NOT NULL DEFAULT 0,
+    parking_spots integer NOT NULL DEFAULT 0,
+    parking_details text NOT NULL DEFAULT '',
+    furnished boolean NOT NULL DEFAULT false,
+    pets_allowed boolean NOT NULL DEFAULT false,
+    boundaries text NOT NULL DEFAULT '',
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE property_managers (
+    property_id uuid NOT NULL,
+    manager_person_id uuid NOT NULL,
+    PRIMARY KEY (property_id, manager_person_id)
+);
+
+CREATE TABLE bank_account (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid,
+    bank_name text NOT NULL DEFAULT '',
+    account_type text NOT NULL DEFAULT '',
+    account_number text NOT NULL DEFAULT '',
+    account_holder text NOT NULL DEFAULT ''
+);
+
+CREATE TABLE rental (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid,
+    renter_id uuid,
+    bank_account_id uuid,
+    start_date timestamptz,
+    end_date timestamptz,
+    payment_terms text NOT NULL DEFAULT '',
+    unpaid_months integer NOT NULL DEFAULT 0,
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE pricing (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid,
+    monthly_rent double precision NOT NULL DEFAULT 0,
+    security_deposit double precision NOT NULL DEFAULT 0,
+    utilities_included text[] NOT NULL DEFAULT '{}',
+    tenant_responsible_for text[] NOT NULL DEFAULT '{}',
+    late_fee double precision NOT NULL DEFAULT 0,
+    due_day integer NOT NULL DEFAULT 1,
+    increase_policy text,
+    increase_spread double precision,
+    increase_percentage double precision,
+    canon_taxable boolean NOT NULL DEFAULT false,
+    components jsonb NOT NULL DEFAULT '[]',
+    currency text NOT NULL DEFAULT 'COP',
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE rent_payment (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid,
+    payment_date timestamptz,
+    amount_paid double precision NOT NULL DEFAULT 0,
+    paid_on_time boolean NOT NULL DEFAULT true,
+    receipt_number text UNIQUE,
+    invoice_sequence bigint UNIQUE,
+    invoice_number text,
+    invoice_cufe text,
+    invoice_status text,
+    invoice_message text,
+    invoice_provider text,
+    invoice_external_id text,
+    invoiced_at timestamptz
+);
+
+CREATE TABLE rental_history (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid,
+    rental_id uuid,
+    status text NOT NULL DEFAULT '',
+    end_reason text NOT NULL DEFAULT '',
+    end_date timestamptz,
+    rent_increase jsonb,
+    next_rental_id uuid,
+    deposit_transfer jsonb,
+    recorded_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE organization (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL DEFAULT '',
+    manager_ids uuid[] NOT NULL DEFAULT '{}',
+    base_url text,
+    custom_domain text,
+    branding jsonb,
+    timezone text,
+    locale text
+);
+
+CREATE TABLE contract_template (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL DEFAULT '',
+    manager_id uuid,
+    contract_type text NOT NULL DEFAULT '',
+    title text NOT NULL DEFAULT '',
+    clauses jsonb NOT NULL DEFAULT '[]',
+    closing_text text NOT NULL DEFAULT '',
+    signature_blocks jsonb,
+    variables jsonb,
+    is_default boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE inventory (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid NOT NULL UNIQUE,
+    rooms jsonb NOT NULL DEFAULT '[]',
+    notes text NOT NULL DEFAULT '',
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE promotion (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid NOT NULL,
+    name text NOT NULL DEFAULT '',
+    type text NOT NULL DEFAULT '',
+    discount_percentage numeric NOT NULL DEFAULT 0,
+    valid_from timestamptz NOT NULL,
+    valid_until timestamptz NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE promotion_redemption (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id uuid NOT NULL,
+    property_id uuid,
+    renter_id uuid,
+    contract_id text NOT NULL DEFAULT '',
+    offered_at timestamptz NOT NULL DEFAULT now(),
+    converted_at timestamptz
+);
+
+CREATE TABLE guarantee_study (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    renter_id uuid NOT NULL,
+    property_id uuid NOT NULL,
+    provider text NOT NULL DEFAULT '',
+    company text NOT NULL DEFAULT '',
+    external_id text NOT NULL DEFAULT '',
+    monthly_rent numeric NOT NULL DEFAULT 0,
+    status text NOT NULL DEFAULT 'pending',
+    policy_number text,
+    observations text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    decided_at timestamptz
+);
+
+CREATE TABLE reminder_preference (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL UNIQUE,
+    send_hour integer,
+    optimize_send_time boolean NOT NULL DEFAULT false
+);
+
+CREATE TABLE notification_preferences (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL,
+    channel text NOT NULL,
+    type text NOT NULL,
+    frequency text NOT NULL DEFAULT 'always',
+    last_sent_at timestamptz,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (person_id, channel, type)
+);
+
+CREATE TABLE email_outbox (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid,
+    to_email text NOT NULL DEFAULT '',
+    subject text NOT NULL DEFAULT '',
+    html_body text NOT NULL DEFAULT '',
+    kind text NOT NULL DEFAULT '',
+    send_at timestamptz NOT NULL DEFAULT now(),
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    sent_at timestamptz,
+    opened_at timestamptz,
+    attachment_paths text[] NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE billing_receipt (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id uuid,
+    year integer NOT NULL DEFAULT 0,
+    number bigint NOT NULL,
+    rental_id uuid NOT NULL,
+    person_id uuid NOT NULL,
+    period text NOT NULL,
+    total numeric NOT NULL DEFAULT 0,
+    file_path text,
+    issued_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE NULLS NOT DISTINCT (organization_id, year, number),
+    UNIQUE (rental_id, period)
+);
+
+-- document_sequence guarda el último consecutivo de cada tipo de documento por organización
+-- y año; los inmuebles sin organización usan el UUID nulo
+CREATE TABLE document_sequence (
+    organization_id uuid NOT NULL,
+    kind text NOT NULL,
+    year integer NOT NULL,
+    last_number bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, kind, year)
+);
+
+CREATE TABLE payment_checkout (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference text NOT NULL UNIQUE,
+    rental_id uuid NOT NULL,
+    person_id uuid NOT NULL,
+    period text NOT NULL,
+    amount_in_cents bigint NOT NULL,
+    currency text NOT NULL DEFAULT 'COP',
+    provider text NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    transaction_id text,
+    payment_method text,
+    rent_payment_id uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz
+);
+
+CREATE TABLE platform_subscription (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL UNIQUE,
+    provider text NOT NULL,
+    customer_id text NOT NULL,
+    subscription_id text NOT NULL DEFAULT '',
+    price_id text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'incomplete',
+    current_period_end timestamptz,
+    payment_url text,
+    past_due_since timestamptz,
+    suspended_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE security_deposit (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid NOT NULL UNIQUE,
+    amount numeric NOT NULL DEFAULT 0,
+    held_by text NOT NULL DEFAULT 'owner',
+    bank_account_id uuid,
+    received_at timestamptz NOT NULL DEFAULT now(),
+    notes text,
+    deductions jsonb NOT NULL DEFAULT '[]',
+    status text NOT NULL DEFAULT 'held',
+    move_out_date timestamptz,
+    settlement_path text,
+    settlement_issued_at timestamptz,
+    signed_path text,
+    signed_at timestamptz,
+    signer_ip text,
+    signer_user_agent text,
+    refunded_at timestamptz,
+    refund_reference text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE inspection_template (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    description text,
+    rooms jsonb NOT NULL DEFAULT '[]',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE inspection (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid NOT NULL,
+    kind text NOT NULL,
+    template_id uuid,
+    inspected_at timestamptz NOT NULL DEFAULT now(),
+    inspector_id uuid,
+    rooms jsonb NOT NULL DEFAULT '[]',
+    notes text,
+    status text NOT NULL DEFAULT 'draft',
+    signing_id text,
+    signed_pdf_path text,
+    acknowledged_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE property_listing (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid NOT NULL UNIQUE,
+    city text NOT NULL DEFAULT '',
+    title text NOT NULL,
+    description text,
+    monthly_rent numeric NOT NULL DEFAULT 0,
+    admin_fee numeric NOT NULL DEFAULT 0,
+    deposit_amount numeric NOT NULL DEFAULT 0,
+    min_term_months integer NOT NULL DEFAULT 12,
+    available_from timestamptz,
+    bedrooms integer NOT NULL DEFAULT 0,
+    bathrooms integer NOT NULL DEFAULT 0,
+    area_m2 numeric NOT NULL DEFAULT 0,
+    pets_allowed boolean NOT NULL DEFAULT false,
+    photos jsonb NOT NULL DEFAULT '[]',
+    status text NOT NULL DEFAULT 'available',
+    published_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE listing_application (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id uuid NOT NULL,
+    full_name text NOT NULL,
+    email text NOT NULL,
+    phone text NOT NULL,
+    document_number text,
+    occupation text,
+    monthly_income numeric NOT NULL DEFAULT 0,
+    occupants integer NOT NULL DEFAULT 1,
+    desired_move_in timestamptz,
+    message text,
+    status text NOT NULL DEFAULT 'new',
+    manager_notes text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE rental_parties (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid NOT NULL,
+    person_id uuid NOT NULL,
+    role text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (rental_id, person_id, role)
+);
+
+CREATE TABLE property_photo (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid NOT NULL REFERENCES property(id) ON DELETE CASCADE,
+    path text NOT NULL,
+    caption text NOT NULL DEFAULT '',
+    position integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE building (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    address text NOT NULL DEFAULT '',
+    city text NOT NULL DEFAULT '',
+    nit text NOT NULL DEFAULT '',
+    admin_fee numeric NOT NULL DEFAULT 0,
+    administrator_name text NOT NULL DEFAULT '',
+    administrator_phone text NOT NULL DEFAULT '',
+    administrator_email text NOT NULL DEFAULT '',
+    notes text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE login_attempt (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    email text NOT NULL,
+    ip_address text NOT NULL DEFAULT '',
+    success boolean NOT NULL DEFAULT false,
+    failure_reason text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_session (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    device text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    last_seen_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    impersonator_id uuid
+);
+
+CREATE TABLE audit_log (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    action text NOT NULL,
+    entity text NOT NULL DEFAULT '',
+    entity_id uuid,
+    changed_by uuid,
+    timestamp timestamptz NOT NULL DEFAULT now(),
+    details jsonb,
+    impersonated_by uuid
+);
+
+CREATE TABLE invitation (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    email text NOT NULL,
+    role text NOT NULL,
+    person_id uuid NOT NULL,
+    organization_id uuid,
+    property_id uuid,
+    message text NOT NULL DEFAULT '',
+    invited_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    accepted_at timestamptz,
+    revoked_at timestamptz,
+    user_id uuid
+);
+
+CREATE TABLE notification (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type text NOT NULL,
+    title text NOT NULL,
+    message text NOT NULL DEFAULT '',
+    entity_id text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    read_at timestamptz
+);
+
+CREATE TABLE manager_digest_subscription (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL UNIQUE,
+    frequency text NOT NULL DEFAULT '',
+    last_sent_at timestamptz,
+    last_arrears jsonb
+);
+
+CREATE TABLE service_provider (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL DEFAULT '',
+    specialty text NOT NULL DEFAULT '',
+    contact_name text NOT NULL DEFAULT '',
+    phone text NOT NULL DEFAULT '',
+    email text NOT NULL DEFAULT '',
+    notes text NOT NULL DEFAULT ''
+);
+
+CREATE TABLE maintenance_request (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    property_id uuid,
+    renter_id uuid,
+    description text NOT NULL DEFAULT '',
+    request_date timestamptz,
+    status text NOT NULL DEFAULT 'Pending',
+    assigned_provider_id uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE maintenance_comment (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id uuid NOT NULL,
+    author_id uuid,
+    body text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE maintenance_status_history (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id uuid NOT NULL,
+    from_status text NOT NULL DEFAULT '',
+    to_status text NOT NULL DEFAULT '',
+    changed_by uuid,
+    changed_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE contract_signatures (
+    id text PRIMARY KEY,
+    contract_id text NOT NULL DEFAULT '',
+    recipient_id text NOT NULL DEFAULT '',
+    recipient_email text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'pending',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz,
+    signed_at timestamptz,
+    rejected_at timestamptz,
+    signature_data text,
+    pdf_path text,
+    signed_pdf_path text,
+    provider text,
+    external_id text,
+    otp_hash text,
+    otp_channel text,
+    otp_sent_at timestamptz,
+    otp_expires_at timestamptz,
+    otp_attempts integer NOT NULL DEFAULT 0,
+    otp_verified_at timestamptz,
+    signer_ip text,
+    signer_user_agent text,
+    signer_latitude double precision,
+    signer_longitude double precision,
+    signer_geo_accuracy double precision,
+    requested_by text,
+    last_reminder_days integer,
+    viewed_at timestamptz,
+    document_type text NOT NULL DEFAULT 'contract'
+);
+
+CREATE TABLE contract_signing_event (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    signing_id text NOT NULL,
+    event text NOT NULL,
+    channel text NOT NULL DEFAULT '',
+    detail text NOT NULL DEFAULT '',
+    ip_address text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE notarization (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    signing_id text NOT NULL,
+    contract_id text NOT NULL DEFAULT '',
+    provider text NOT NULL DEFAULT '',
+    notary text NOT NULL DEFAULT '',
+    external_id text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'pending',
+    reference text,
+    observations text,
+    stamped_pdf_path text,
+    requested_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz
+);
+
+CREATE TABLE building_reglamento (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    building_key text NOT NULL,
+    address text NOT NULL DEFAULT '',
+    city text NOT NULL DEFAULT '',
+    version int NOT NULL,
+    file_name text NOT NULL DEFAULT '',
+    file_path text NOT NULL,
+    uploaded_by uuid,
+    uploaded_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (building_key, version)
+);
+
+CREATE TABLE reglamento_acknowledgment (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    reglamento_id uuid NOT NULL REFERENCES building_reglamento(id) ON DELETE CASCADE,
+    building_key text NOT NULL,
+    version int NOT NULL,
+    person_id uuid NOT NULL,
+    property_id uuid,
+    acknowledged_at timestamptz NOT NULL DEFAULT now(),
+    ip_address text,
+    user_agent text,
+    UNIQUE (reglamento_id, person_id)
+);
+
+CREATE TABLE contract_cession (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rental_id uuid NOT NULL REFERENCES rental(id) ON DELETE CASCADE,
+    previous_owner_id uuid NOT NULL,
+    new_owner_id uuid NOT NULL,
+    previous_bank_account_id uuid,
+    new_bank_account_id uuid NOT NULL,
+    reason text NOT NULL DEFAULT 'sale',
+    effective_date timestamptz NOT NULL,
+    letter_path text,
+    notified_at timestamptz,
+    applied_at timestamptz,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_subscription (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events text[] NOT NULL DEFAULT '{}',
+    all_requests boolean NOT NULL DEFAULT false,
+    description text,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_delivery (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id uuid NOT NULL REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+    event text NOT NULL,
+    signing_id text,
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    response_status integer,
+    last_error text,
+    delivered_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE payment_statement (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    person_id uuid NOT NULL,
+    organization_id uuid,
+    year integer NOT NULL,
+    payment_count integer NOT NULL DEFAULT 0,
+    total_paid double precision NOT NULL DEFAULT 0,
+    document_hash text NOT NULL DEFAULT '',
+    issued_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE upload_limit (
+    role text PRIMARY KEY,
+    max_file_size_mb bigint NOT NULL DEFAULT 0,
+    quota_mb bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz
+);
+
+CREATE TABLE user_bulk_job (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    action text NOT NULL,
+    filter jsonb NOT NULL DEFAULT '{}',
+    status text NOT NULL DEFAULT 'queued',
+    total integer NOT NULL DEFAULT 0,
+    succeeded integer NOT NULL DEFAULT 0,
+    skipped integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    results jsonb NOT NULL DEFAULT '[]',
+    error text,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    started_at timestamptz,
+    finished_at timestamptz
+);
+
+CREATE TABLE file_scan (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id text NOT NULL DEFAULT '',
+    file_name text NOT NULL DEFAULT '',
+    path text NOT NULL,
+    size bigint NOT NULL DEFAULT 0,
+    provider text NOT NULL,
+    result text NOT NULL,
+    signature text,
+    detail text,
+    scanned_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE file_metadata (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_name text NOT NULL UNIQUE,
+    path text NOT NULL,
+    original_name text NOT NULL DEFAULT '',
+    size bigint NOT NULL DEFAULT 0,
+    mime_type text,
+    user_id text NOT NULL DEFAULT '',
+    category text,
+    tags text[] NOT NULL DEFAULT '{}',
+    rental_id uuid,
+    contract_id text,
+    uploaded_by text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE trashed_file (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    path text NOT NULL,
+    trash_path text NOT NULL UNIQUE,
+    file_name text NOT NULL DEFAULT '',
+    deleted_by text NOT NULL DEFAULT '',
+    deleted_at timestamptz NOT NULL DEFAULT now(),
+    purge_at timestamptz NOT NULL
+);
+
+CREATE TABLE file_index (
+    path text PRIMARY KEY,
+    user_id text NOT NULL DEFAULT '',
+    name text NOT NULL DEFAULT '',
+    size bigint NOT NULL DEFAULT 0,
+    mime_type text NOT NULL DEFAULT '',
+    uploaded_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE bucket_backup (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    status text NOT NULL DEFAULT 'running',
+    mode text NOT NULL DEFAULT 'delta',
+    providers text[] NOT NULL DEFAULT '{}',
+    file_count integer NOT NULL DEFAULT 0,
+    copied integer NOT NULL DEFAULT 0,
+    failed integer NOT NULL DEFAULT 0,
+    total_size bigint NOT NULL DEFAULT 0,
+    manifest jsonb NOT NULL DEFAULT '[]',
+    manifest_locations jsonb,
+    error text,
+    started_at timestamptz NOT NULL DEFAULT now(),
+    finished_at timestamptz
+);
+
+CREATE TABLE email_template (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    key text NOT NULL,
+    version int NOT NULL,
+    subject text NOT NULL,
+    body text NOT NULL,
+    created_by uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (key, version)
+);
+
+CREATE TABLE feature_flag (
+    key text PRIMARY KEY,
+    enabled boolean NOT NULL,
+    updated_by uuid,
+    updated_at timestamptz
+);
+
+-- bump_version incrementa la versión en cada UPDATE; los repositorios solo actualizan la
+-- versión que leyó el cliente (control de concurrencia optimista)
+CREATE FUNCTION bump_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER person_version BEFORE UPDATE ON person FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER property_version BEFORE UPDATE ON property FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER rental_version BEFORE UPDATE ON rental FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER pricing_version BEFORE UPDATE ON pricing FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE FUNCTION get_persons_by_role(role_name text) RETURNS SETOF person
+LANGUAGE sql STABLE AS $$
+    SELECT p.*
+    FROM person p
+    JOIN person_role pr ON pr.person_id = p.id
+    JOIN role r ON r.id = pr.role_id
+    WHERE r.role_name = get_persons_by_role.role_name;
+$$;
+
+-- search_normalize pasa el texto a minúsculas y sin tildes para la búsqueda
+CREATE FUNCTION search_normalize(value text) RETURNS text
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT translate(lower(coalesce(value, '')), 'áéíóúüñ', 'aeiouun');
+$$;
+
+-- search_all busca por texto en personas, inmuebles, arriendos y archivos. Cada palabra de la
+-- consulta se busca como prefijo, así "carrera 18" encuentra "Carrera 18b".
+CREATE FUNCTION search_all(search_query text, max_results int DEFAULT 20)
+RETURNS TABLE (kind text, id uuid, title text, subtitle text, property_id uuid, rental_id uuid, person_id uuid, rank real)
+LANGUAGE sql STABLE AS $$
+    WITH q AS (
+        SELECT to_tsquery('simple', string_agg(word || ':*', ' & ')) AS query
+        FROM unnest(regexp_split_to_array(search_normalize(search_query), '[^[:alnum:]]+')) AS word
+        WHERE word <> ''
+    ),
+    documents AS (
+        SELECT 'person' AS kind, p.id, p.full_name AS title, nullif(p.nit, '') AS subtitle,
+            NULL::uuid AS property_id, NULL::uuid AS rental_id, p.id AS person_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', p.full_name, p.nit, p.phone, u.email))) AS document
+        FROM person p
+        LEFT JOIN users u ON u.person_id = p.id
+        UNION ALL
+        SELECT 'property', pr.id, concat_ws(' Apto ', pr.address, nullif(pr.apt_number, '')), pr.city,
+            pr.id, NULL, pr.resident_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', pr.address, pr.apt_number, pr.city, pr.state, pr.zip_code, pr.type)))
+        FROM property pr
+        UNION ALL
+        SELECT 'rental', r.id, concat_ws(' Apto ', pr.address, nullif(pr.apt_number, '')), p.full_name,
+            r.property_id, r.id, r.renter_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', pr.address, pr.apt_number, pr.city, p.full_name, p.nit, r.payment_terms)))
+        FROM rental r
+        LEFT JOIN property pr ON pr.id = r.property_id
+        LEFT JOIN person p ON p.id = r.renter_id
+        UNION ALL
+        SELECT 'file', f.id, coalesce(nullif(f.original_name, ''), f.file_name), f.category,
+            r.property_id, f.rental_id, NULL,
+            to_tsvector('simple', search_normalize(concat_ws(' ', f.original_name, f.file_name, f.category, array_to_string(f.tags, ' '))))
+        FROM file_metadata f
+        LEFT JOIN rental r ON r.id = f.rental_id
+    )
+    SELECT d.kind, d.id, d.title, d.subtitle, d.property_id, d.rental_id, d.person_id,
+        ts_rank(d.document, q.query) AS rank
+    FROM documents d, q
+    WHERE d.document @@ q.query
+    ORDER BY rank DESC, d.title
+    LIMIT max_results;
+$$;
+
+-- property_with_managers devuelve el inmueble como JSON con los IDs de sus administradores
+CREATE FUNCTION property_with_managers(saved property) RETURNS jsonb
+LANGUAGE sql STABLE AS $$
+    SELECT to_jsonb(saved) || jsonb_build_object('manager_ids', coalesce(
+        (SELECT jsonb_agg(pm.manager_person_id) FROM property_managers pm WHERE pm.property_id = saved.id),
+        '[]'::jsonb));
+$$;
+
+-- create_property inserta el inmueble y sus vínculos con administradores en una sola
+-- transacción: si algo falla no queda el inmueble sin administradores ni vínculos huérfanos
+CREATE FUNCTION create_property(data jsonb, managers uuid[]) RETURNS jsonb
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields property := jsonb_populate_record(NULL::property, data);
+    saved property;
+BEGIN
+    INSERT INTO property (id, address, apt_number, city, state, zip_code, type, resident_id, building_id,
+        bedrooms, bathrooms, area_m2, estrato, parking_spots, parking_details, furnished, pets_allowed, boundaries)
+    VALUES (coalesce(fields.id, gen_random_uuid()), fields.address, fields.apt_number, fields.city, fields.state,
+        fields.zip_code, fields.type, fields.resident_id, fields.building_id, fields.bedrooms, fields.bathrooms,
+        fields.area_m2, fields.estrato, fields.parking_spots, fields.parking_details, fields.furnished,
+        fields.pets_allowed, fields.boundaries)
+    RETURNING * INTO saved;
+
+    INSERT INTO property_managers (property_id, manager_person_id)
+    SELECT saved.id, m FROM unnest(managers) AS m
+    ON CONFLICT DO NOTHING;
+
+    RETURN property_with_managers(saved);
+END;
+$$;
+
+-- update_property actualiza el inmueble y reemplaza sus administradores en una sola
+-- transacción. Con expected_version solo actualiza esa versión y, si el inmueble cambió,
+-- falla con PT409 (409 en PostgREST). Devuelve NULL si el inmueble no existe.
+CREATE FUNCTION update_property(data jsonb, managers uuid[], expected_version integer DEFAULT NULL) RETURNS jsonb
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields property := jsonb_populate_record(NULL::property, data);
+    saved property;
+BEGIN
+    UPDATE property p SET address = fields.address, apt_number = fields.apt_number, city = fields.city,
+        state = fields.state, zip_code = fields.zip_code, type = fields.type, resident_id = fields.resident_id,
+        building_id = fields.building_id, bedrooms = fields.bedrooms, bathrooms = fields.bathrooms,
+        area_m2 = fields.area_m2, estrato = fields.estrato, parking_spots = fields.parking_spots,
+        parking_details = fields.parking_details, furnished = fields.furnished,
+        pets_allowed = fields.pets_allowed, boundaries = fields.boundaries
+    WHERE p.id = fields.id AND (expected_version IS NULL OR p.version = expected_version)
+    RETURNING p.* INTO saved;
+
+    IF NOT FOUND THEN
+        IF expected_version IS NOT NULL AND EXISTS (SELECT 1 FROM property WHERE id = fields.id) THEN
+            RAISE EXCEPTION 'property % changed since version %', fields.id, expected_version
+                USING ERRCODE = 'PT409';
+        END IF;
+        RETURN NULL;
+    END IF;
+
+    DELETE FROM property_managers pm
+    WHERE pm.property_id = saved.id AND pm.manager_person_id <> ALL (managers);
+    INSERT INTO property_managers (property_id, manager_person_id)
+    SELECT saved.id, m FROM unnest(managers) AS m
+    ON CONFLICT DO NOTHING;
+
+    RETURN property_with_managers(saved);
+END;
+$$;
+
+-- next_document_number reserva el siguiente consecutivo de un tipo de documento de una
+-- organización en un año. El INSERT ... ON CONFLICT bloquea la fila de la secuencia, así dos
+-- llamadas simultáneas nunca obtienen el mismo número.
+CREATE FUNCTION next_document_number(sequence_organization_id uuid, sequence_kind text, sequence_year integer) RETURNS bigint
+LANGUAGE sql AS $$
+    INSERT INTO document_sequence AS s (organization_id, kind, year, last_number)
+    VALUES (sequence_organization_id, sequence_kind, sequence_year, 1)
+    ON CONFLICT (organization_id, kind, year) DO UPDATE SET last_number = s.last_number + 1
+    RETURNING s.last_number;
+$$;
+
+-- create_numbered_rent_payment registra un pago con el siguiente número de recibo de la
+-- organización en el año, en una sola transacción: si el INSERT falla el número no se consume
+CREATE FUNCTION create_numbered_rent_payment(payment jsonb, sequence_organization_id uuid, sequence_year integer) RETURNS rent_payment
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields rent_payment := jsonb_populate_record(NULL::rent_payment, payment);
+    receipt bigint := next_document_number(sequence_organization_id, 'recibo_de_pago', sequence_year);
+    saved rent_payment;
+BEGIN
+    INSERT INTO rent_payment (id, rental_id, payment_date, amount_paid, paid_on_time, receipt_number)
+    VALUES (coalesce(fields.id, gen_random_uuid()), fields.rental_id, fields.payment_date,
+        coalesce(fields.amount_paid, 0), coalesce(fields.paid_on_time, true),
+        'RP-' || sequence_year || '-' || lpad(receipt::text, 6, '0'))
+    RETURNING * INTO saved;
+
+    RETURN saved;
+END;
+$$;
+
+-- register_checkout_payment registra el pago de un checkout aprobado y guarda el resultado del
+-- checkout en una sola transacción. La fila del checkout se bloquea primero: si el evento de la
+-- pasarela llega dos veces, aun a instancias distintas, la segunda llamada devuelve el pago ya
+-- registrado sin crear otro.
+CREATE FUNCTION register_checkout_payment(checkout_id uuid, result jsonb, payment jsonb, sequence_organization_id uuid, sequence_year integer) RETURNS rent_payment
+LANGUAGE plpgsql AS $$
+DECLARE
+    checkout payment_checkout;
+    saved rent_payment;
+BEGIN
+    SELECT * INTO checkout FROM payment_checkout WHERE id = checkout_id FOR UPDATE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'payment checkout % does not exist', checkout_id;
+    END IF;
+
+    IF checkout.rent_payment_id IS NOT NULL THEN
+        SELECT * INTO saved FROM rent_payment WHERE id = checkout.rent_payment_id;
+        RETURN saved;
+    END IF;
+
+    saved := create_numbered_rent_payment(payment, sequence_organization_id, sequence_year);
+    UPDATE payment_checkout SET
+        status = 'approved',
+        transaction_id = result->>'transaction_id',
+        payment_method = result->>'payment_method',
+        completed_at = (result->>'completed_at')::timestamptz,
+        rent_payment_id = saved.id
+    WHERE id = checkout_id;
+
+    RETURN saved;
+END;
+$$;
+
+-- reset_test_data vacía todas las tablas entre escenarios de prueba
+CREATE FUNCTION reset_test_data() RETURNS void
+LANGUAGE sql AS $$
+    TRUNCATE person, role, person_role, users, property, property_managers, bank_account,
+        rental, pricing, rent_payment, rental_history, service_provider, maintenance_request,
+        maintenance_comment, maintenance_status_history, contract_signatures, contract_signing_event,
+        notarization, organization,
+        contract_template, reminder_preference, email_outbox,
+        manager_digest_subscription, inventory, promotion, promotion_redemption,
+        guarantee_study, building_reglamento, reglamento_acknowledgment, contract_cession,
+        webhook_subscription, webhook_delivery, payment_statement,
+        upload_limit, user_bulk_job, file_scan, file_metadata, trashed_file,
+        file_index, bucket_backup, email_template, notification_preferences,
+        billing_receipt, document_sequence, payment_checkout, platform_subscription,
+        security_deposit, inspection_template, inspection,
+        property_listing, listing_application, rental_parties, property_photo, building,
+        login_attempt, user_session, audit_log, invitation, notification, feature_flag;
+$$;
+
+CREATE ROLE web_anon NOLOGIN;
+GRANT USAGE ON SCHEMA public TO web_anon;
+GRANT ALL ON ALL TABLES IN SCHEMA public TO web_anon;
+GRANT EXECUTE ON ALL FUNCTIONS IN SCHEMA public TO web_anon;
+
+CREATE ROLE authenticator NOINHERIT LOGIN PASSWORD 'authenticator';
+GRANT web_anon TO authenticator;
@@ -0,0 +1,77 @@
+// Package logging configures the structured logger of the API. Records are written as JSON
+// (or text in development) with a level, and the records of an HTTP request carry its ID.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	// RequestIDHeader is the header with the ID of a request, taken from the client or the proxy
+	// when present and returned in the response
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the key the ID of a request is kept under in its Gin context, so the
+	// handlers that pass the Gin context as context.Context carry it too
+	RequestIDKey = "request_id"
+)
+
+type requestIDKey struct{}
+
+// Init sets the default logger with level (debug, info, warn or error, info by default) and
+// format (json or text, json by default). The messages the dependencies write with the standard
+// log package go through the same logger, at info level.
+func Init(level, format string) {
+	slog.SetDefault(New(os.Stdout, level, format))
+}
+
+// New creates a logger writing to w with the given level and format
+func New(w io.Writer, level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: ParseLevel(level)}
+	if strings.EqualFold(strings.TrimSpace(format), "text") {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// ParseLevel reads a log level name, info when it is empty or unknown
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// WithRequestID returns a context carrying the ID of the request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request of the context, empty outside of a request
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	// A Gin context answers its string keys
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+// FromContext returns the default logger with the request ID of the context, if any
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestID(ctx); requestID != "" {
+		return slog.Default().With("request_id", requestID)
+	}
+	return slog.Default()
+}
@@ -1,45 +1,104 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"os"
 
 	// "github.com/gin-gonic/gin" // Not used directly if StartHTTPServer handles router setup
 	"github.com/joho/godotenv"
+	"github.com/nescool101/rentManager/app"
 	"github.com/nescool101/rentManager/config"
 	"github.com/nescool101/rentManager/controller"
+	"github.com/nescool101/rentManager/i18n"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/service"
 	// Keep for service.StartScheduler if un-commented
 	// storage package might not be needed directly in main anymore
 )
 
 func main() {
+	seed := flag.Bool("seed", false, "create the demo organization, users, properties and rental in an empty database and exit")
+	flag.Parse()
+
 	// Load environment variables
 	err := godotenv.Load()
 	if err != nil {
-		log.Println("No .env file found. Using default configurations.")
+		slog.Info("no .env file found, using the environment")
+	}
+
+	// Select the environment profile before reading the rest of the configuration
+	config.InitProfile()
+
+	// Read and validate the settings the server needs, reporting every missing one at once
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Structured logs with the level and format of LOG_LEVEL and LOG_FORMAT
+	logging.Init(cfg.Log.Level, cfg.Log.Format)
+	i18n.SetDefaultLocale(cfg.Locale.Locale)
+
+	// Outside the prod profile the unset signing secrets get random keys
+	if err := service.GenerateMissingSecrets(&cfg.Secrets); err != nil {
+		slog.Error("failed to generate the signing secrets", "error", err)
+		os.Exit(1)
 	}
 
-	// Initialize email configuration
-	config.InitEmailConfig()
+	// Process-wide settings of the emails, SMS, links, dates and signatures
+	if err := service.InitializeEmail(cfg.Email); err != nil {
+		slog.Error("failed to initialize email", "error", err)
+		os.Exit(1)
+	}
+	service.InitializeSMS(cfg.SMS)
+	service.InitializePublicURLs(cfg.URLs)
+	service.InitializeFormatter(cfg.Locale)
+	service.InitializeSigningCertificate(cfg.Signing)
+
+	// Initialize file storage (Supabase Storage or local disk). Without it the file endpoints
+	// answer 503 and the rest of the API keeps working
+	if err := service.InitializeSupabaseStorageService(cfg); err != nil {
+		slog.Error("error inicializando el almacenamiento de archivos", "error", err)
+		slog.Warn("las funciones de archivos quedan deshabilitadas")
+	}
+
+	// Database clients and repositories the controllers are built with
+	container, err := app.New(context.Background(), cfg)
+	if err != nil {
+		slog.Error("failed to initialize storage", "error", err)
+		os.Exit(1)
+	}
 
-	// Initialize Supabase Storage service for file uploads
-	if err := service.InitializeSupabaseStorageService(); err != nil {
-		log.Printf("❌ Error inicializando Supabase Storage: %v", err)
-		log.Fatal("No se puede continuar sin servicio de archivos")
+	// Local development: fill the database with demo data instead of serving the API
+	if *seed {
+		err := app.Seed(context.Background(), container)
+		container.Close()
+		if err != nil {
+			slog.Error("failed to seed the demo data", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
+	// Feature flags toggled by admins at runtime, falling back to the environment
+	service.InitializeFeatureFlags(container.FeatureFlags, cfg)
+
 	// Initialize Telegram service for file backup (only if enabled)
 	if service.IsTelegramEnabled() {
-		if err := service.InitializeTelegramService(); err != nil {
-			log.Printf("⚠️ Advertencia: Servicio de Telegram no disponible: %v", err)
-			log.Printf("ℹ️ Los archivos se eliminarán sin backup en Telegram")
+		if err := service.InitializeTelegramService(cfg.Telegram); err != nil {
+			slog.Warn("servicio de Telegram no disponible", "error", err)
+			slog.Info("los archivos se eliminarán sin backup en Telegram")
 		}
 	} else {
-		log.Printf("ℹ️ Integración de Telegram deshabilitada por feature flag")
-		log.Printf("💡 Para habilitar: establece TELEGRAM_ENABLED=true en .env")
+		slog.Info("integración de Telegram deshabilitada por feature flag")
+		slog.Info("para habilitar: establece TELEGRAM_ENABLED=true en .env o PUT /api/admin/feature-flags/telegram_backup")
 	}
 
-	// Supabase Storage handles file management automatically
+	// Initialize the backup providers that run before files are deleted (Telegram, S3, Google Drive)
+	service.InitializeFileBackups(cfg.Files.Backups)
 
 	// storage.InitializePayersFile() // Removed as per request
 
@@ -47,9 +106,9 @@ func main() {
 
 	// go service.StartScheduler() // Temporarily commented out. Uncomment and ensure logic is DB-based if used.
 
-	// Start HTTP server - Controllers and Repositories are initialized within this function
-	// This assumes StartHTTPServer initializes the Gin router and all routes.
-	if err := controller.StartHTTPServer(); err != nil {
-		log.Fatalf("Failed to start HTTP server: %v", err)
+	// Start HTTP server - Controllers are initialized within this function from the container
+	if err := controller.StartHTTPServer(container); err != nil {
+		slog.Error("failed to start HTTP server", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,159 @@
+// Package metrics exposes the Prometheus metrics of the API at /metrics: the requests served
+// per route, the emails sent, the bytes uploaded to the file storage, the signing requests, the
+// requests rejected by the rate limits and the outcome of the scheduled jobs.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Results of the email sends and scheduled jobs
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// unmatchedRoute labels the requests that did not match a route, such as the SPA pages, so
+// their paths do not create a series each
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests, by method and route.",
+		Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route"})
+
+	emailsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "emails_sent_total",
+		Help: "Emails delivered, by driver and result.",
+	}, []string{"driver", "result"})
+
+	storageUploadBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_upload_bytes_total",
+		Help: "Bytes uploaded to the file storage, by backend.",
+	}, []string{"backend"})
+
+	storageUploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_uploads_total",
+		Help: "Uploads to the file storage, by backend and result.",
+	}, []string{"backend", "result"})
+
+	signingRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "signing_requests_total",
+		Help: "Signing requests by the status they reached: pending when created, signed, rejected or expired.",
+	}, []string{"status"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected with 429 by a rate limit, by limit.",
+	}, []string{"limit"})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_job_runs_total",
+		Help: "Runs of the scheduled jobs, by job and result.",
+	}, []string{"job", "result"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduler_job_duration_seconds",
+		Help:    "Duration of the scheduled jobs, by job.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+	}, []string{"job"})
+
+	jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "scheduler_job_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful run of each scheduled job.",
+	}, []string{"job"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		httpRequests, httpRequestDuration,
+		emailsSent,
+		storageUploadBytes, storageUploads,
+		signingRequests,
+		rateLimited,
+		jobRuns, jobDuration, jobLastSuccess,
+	)
+}
+
+// ObserveRequest records a served request. route is the route pattern, empty when the request
+// matched none.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// EmailSent records an email delivered with driver, failed when err is not nil
+func EmailSent(driver string, err error) {
+	emailsSent.WithLabelValues(driver, result(err)).Inc()
+}
+
+// StorageUpload records an upload of size bytes to the file storage backend
+func StorageUpload(backend string, size int64, err error) {
+	storageUploads.WithLabelValues(backend, result(err)).Inc()
+	if err == nil {
+		storageUploadBytes.WithLabelValues(backend).Add(float64(size))
+	}
+}
+
+// SigningRequests records count signing requests reaching status
+func SigningRequests(status string, count int) {
+	if count > 0 {
+		signingRequests.WithLabelValues(status).Add(float64(count))
+	}
+}
+
+// RateLimited records a request rejected by the rate limit name
+func RateLimited(name string) {
+	rateLimited.WithLabelValues(name).Inc()
+}
+
+// JobRun records a run of the scheduled job that started at start, failed when err is not nil
+func JobRun(job string, start time.Time, err error) {
+	jobRuns.WithLabelValues(job, result(err)).Inc()
+	jobDuration.WithLabelValues(job).Observe(time.Since(start).Seconds())
+	if err == nil {
+		jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+// Handler serves the metrics in the Prometheus format. When token (METRICS_TOKEN) is set the
+// scraper must send it as a bearer token.
+func Handler(token string) http.Handler {
+	handler := promhttp.Handler()
+	if token == "" {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
@@ -1,30 +1,40 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
-	"log"
-
 	"github.com/gin-gonic/gin"
 	"github.com/nescool101/rentManager/auth"
+	"github.com/nescool101/rentManager/i18n"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
-// AuthMiddleware validates JWT tokens and adds user information to the request context
-func AuthMiddleware() gin.HandlerFunc {
+// SessionValidator validates the tokens and tells whether the session a token was issued for is
+// still active
+type SessionValidator interface {
+	ValidateToken(tokenString string) (*auth.CustomClaims, error)
+	IsSessionActive(ctx context.Context, sessionID string) bool
+}
+
+// AuthMiddleware validates JWT tokens and adds user information to the request context. The
+// tokens of revoked sessions are rejected. The session is stored as "session_id" and, on
+// impersonation tokens, the admin as "impersonator_id".
+func AuthMiddleware(sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "AuthorizationHeaderRequired"})
 			c.Abort()
 			return
 		}
 
 		// Check if the header has the Bearer prefix
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header format"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "InvalidAuthorizationHeader"})
 			c.Abort()
 			return
 		}
@@ -33,25 +43,46 @@ func AuthMiddleware() gin.HandlerFunc {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Validate the token
-		user, err := auth.ExtractUserFromToken(tokenString)
+		claims, err := sessions.ValidateToken(tokenString)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "InvalidOrExpiredToken"})
+			c.Abort()
+			return
+		}
+		user, err := claims.User()
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "InvalidOrExpiredToken"})
+			c.Abort()
+			return
+		}
+
+		// Tokens of sessions revoked by their user are no longer accepted
+		if !sessions.IsSessionActive(c.Request.Context(), claims.ID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "SessionRevoked"})
 			c.Abort()
 			return
 		}
 
 		// Check user status - only block disabled accounts
 		if user.Status == "disabled" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Your account is disabled. Please contact support."})
+			c.JSON(http.StatusForbidden, gin.H{"error": "AccountDisabled"})
 			c.Abort()
 			return
 		}
 
 		// Allow newuser status - they will be redirected by the frontend when appropriate
-		log.Printf("User authenticated: %s (Role: %s, Status: %s)", user.Email, user.Role, user.Status)
+		logging.FromContext(c.Request.Context()).Debug("user authenticated",
+			"user_id", user.ID.String(), "role", user.Role, "status", user.Status)
 
 		// Set the user in the context
 		c.Set("user", user)
+		c.Set("session_id", claims.ID)
+		if user.Locale != "" {
+			c.Set(localeKey, i18n.Normalize(user.Locale))
+		}
+		if claims.ImpersonatorID != "" {
+			c.Set("impersonator_id", claims.ImpersonatorID)
+		}
 
 		// Continue
 		c.Next()
@@ -64,21 +95,21 @@ func AdminMiddleware() gin.HandlerFunc {
 		// Get the user from the context (set by AuthMiddleware)
 		userInterface, exists := c.Get("user")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 			c.Abort()
 			return
 		}
 
 		user, ok := userInterface.(*model.User)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 			c.Abort()
 			return
 		}
 
 		// Check if the user has admin role
 		if user.Role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.JSON(http.StatusForbidden, gin.H{"error": "AdminAccessRequired"})
 			c.Abort()
 			return
 		}
@@ -95,21 +126,21 @@ func ManagerMiddleware() gin.HandlerFunc {
 		// Get the user from the context (set by AuthMiddleware)
 		userInterface, exists := c.Get("user")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "AuthenticationRequired"})
 			c.Abort()
 			return
 		}
 
 		user, ok := userInterface.(*model.User)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "User data invalid"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "UserDataInvalid"})
 			c.Abort()
 			return
 		}
 
 		// Check if the user has either admin or manager role
 		if user.Role != "admin" && user.Role != "manager" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Manager or admin access required"})
+			c.JSON(http.StatusForbidden, gin.H{"error": "ManagerOrAdminRequired"})
 			c.Abort()
 			return
 		}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/logging"
+)
+
+// DeprecatedRouteUsage holds usage metrics for a deprecated route
+type DeprecatedRouteUsage struct {
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Replacement string    `json:"replacement"`
+	Sunset      time.Time `json:"sunset"`
+	Hits        int64     `json:"hits"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	LastClient  string    `json:"last_client,omitempty"`
+}
+
+var (
+	deprecatedRoutesMu sync.Mutex
+	deprecatedRoutes   = make(map[string]*DeprecatedRouteUsage)
+)
+
+// DeprecatedRoute marks a legacy route as deprecated.
+// The route is tracked from registration time so unused routes show up with zero hits.
+// Responses carry the Deprecation, Sunset and Link headers pointing to the replacement path,
+// and every call is counted so the route can be removed once usage drops to zero.
+func DeprecatedRoute(method, path, replacement string, sunset time.Time) gin.HandlerFunc {
+	key := method + " " + path
+
+	deprecatedRoutesMu.Lock()
+	usage, exists := deprecatedRoutes[key]
+	if !exists {
+		usage = &DeprecatedRouteUsage{
+			Method:      method,
+			Path:        path,
+			Replacement: replacement,
+			Sunset:      sunset,
+		}
+		deprecatedRoutes[key] = usage
+	}
+	deprecatedRoutesMu.Unlock()
+
+	return func(c *gin.Context) {
+		deprecatedRoutesMu.Lock()
+		usage.Hits++
+		usage.LastUsedAt = time.Now()
+		usage.LastClient = c.ClientIP()
+		hits := usage.Hits
+		deprecatedRoutesMu.Unlock()
+
+		logging.FromContext(c.Request.Context()).Warn("deprecated route used",
+			"route", key, "client_ip", c.ClientIP(), "hits", hits, "replacement", replacement)
+
+		c.Header("Deprecation", "true")
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Header("Link", "<"+replacement+">; rel=\"successor-version\"")
+
+		c.Next()
+	}
+}
+
+// GetDeprecatedRouteUsage returns a snapshot of the usage metrics of all deprecated routes that were called
+func GetDeprecatedRouteUsage() []DeprecatedRouteUsage {
+	deprecatedRoutesMu.Lock()
+	defer deprecatedRoutesMu.Unlock()
+
+	usages := make([]DeprecatedRouteUsage, 0, len(deprecatedRoutes))
+	for _, usage := range deprecatedRoutes {
+		usages = append(usages, *usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Path == usages[j].Path {
+			return usages[i].Method < usages[j].Method
+		}
+		return usages[i].Path < usages[j].Path
+	})
+
+	return usages
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// AuditRecorder adds entries to the audit log
+type AuditRecorder interface {
+	Record(ctx context.Context, entry model.AuditLog)
+}
+
+// GetImpersonatorID returns the admin acting as the authenticated user, if the request was made
+// with an impersonation token
+func GetImpersonatorID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.GetString("impersonator_id"))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// AuditImpersonation records in the audit log every request made with an impersonation token,
+// with the impersonated user and the admin, once it has been served
+func AuditImpersonation(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		impersonator, impersonated := GetImpersonatorID(c)
+		if !impersonated {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		entry := model.AuditLog{
+			Action:         c.Request.Method,
+			Entity:         c.FullPath(),
+			Timestamp:      start,
+			ImpersonatedBy: &impersonator,
+			Details: gin.H{
+				"path":       c.Request.URL.Path,
+				"query":      c.Request.URL.RawQuery,
+				"status":     c.Writer.Status(),
+				"client_ip":  c.ClientIP(),
+				"request_id": logging.RequestID(c.Request.Context()),
+			},
+		}
+		if user, ok := c.Get("user"); ok {
+			if authUser, ok := user.(*model.User); ok {
+				entry.ChangedBy = authUser.ID
+			}
+		}
+		if id, err := uuid.Parse(c.Param("id")); err == nil {
+			entry.EntityID = id
+		}
+		recorder.Record(context.WithoutCancel(c.Request.Context()), entry)
+	}
+}
+
+// ForbidImpersonation rejects the impersonation tokens on routes only the user themself may use,
+// such as changing their password
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonated := GetImpersonatorID(c); impersonated {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "NotAllowedWhileImpersonating"})
+			return
+		}
+		c.Next()
+	}
+}
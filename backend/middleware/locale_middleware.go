@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/i18n"
+)
+
+// localeKey is the context key of the locale of the request
+const localeKey = "locale"
+
+// Locale stores the locale of the Accept-Language header as "locale". AuthMiddleware replaces
+// it with the preference of the authenticated user, when the user has one.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if locale := i18n.FromAcceptLanguage(c.GetHeader("Accept-Language")); locale != "" {
+			c.Set(localeKey, locale)
+		}
+		c.Next()
+	}
+}
+
+// RequestLocale returns the locale of the request, "" when neither the user nor the
+// Accept-Language header chose one
+func RequestLocale(c *gin.Context) string {
+	return c.GetString(localeKey)
+}
+
+// LocalizeErrors translates the JSON error responses to the locale of the request, or
+// APP_LOCALE when it has none. Handlers answer {"error": "<message ID>"}, with the data of the
+// message template in "params" and an untranslated "detail" that is appended after ": ". The
+// "error" becomes the text of the message and "message_id" keeps its ID; errors that are not
+// a message ID, such as the text of a Go error, are sent as is.
+func LocalizeErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &localizedErrorWriter{ResponseWriter: c.Writer, ctx: c}
+		c.Next()
+	}
+}
+
+// localizedErrorWriter rewrites the body of the JSON responses with an error status
+type localizedErrorWriter struct {
+	gin.ResponseWriter
+	ctx *gin.Context
+}
+
+func (w *localizedErrorWriter) Write(data []byte) (int, error) {
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(data)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	id, ok := body["error"].(string)
+	if !ok {
+		return w.ResponseWriter.Write(data)
+	}
+
+	locale := RequestLocale(w.ctx)
+	if locale == "" {
+		locale = i18n.DefaultLocale()
+	}
+	message, ok := i18n.Lookup(locale, id, body["params"])
+	if !ok {
+		return w.ResponseWriter.Write(data)
+	}
+	if detail, _ := body["detail"].(string); detail != "" {
+		message += ": " + detail
+	}
+
+	body["error"] = message
+	body["message_id"] = id
+	localized, err := json.Marshal(body)
+	if err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(localized); err != nil {
+		return 0, err
+	}
+	// The caller wrote data, whatever its translated length
+	return len(data), nil
+}
+
+func (w *localizedErrorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nescool101/rentManager/model"
+)
+
+// OrganizationResolver finds the organization serving a host
+type OrganizationResolver interface {
+	ResolveHost(ctx context.Context, host string) *model.Organization
+}
+
+// ResolveOrganization resolves the organization of public requests from the host they were sent to.
+// X-Forwarded-Host and Host cover custom domains proxied to the API, Origin covers a frontend
+// on a custom domain calling the API directly. The result is stored as "organization".
+func ResolveOrganization(resolver OrganizationResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		candidates := []string{c.GetHeader("X-Forwarded-Host"), c.Request.Host}
+		if origin, err := url.Parse(c.GetHeader("Origin")); err == nil {
+			candidates = append(candidates, origin.Host)
+		}
+
+		for _, host := range candidates {
+			if host == "" {
+				continue
+			}
+			if org := resolver.ResolveHost(c, host); org != nil {
+				c.Set("organization", org)
+				break
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// GetOrganization returns the organization resolved by ResolveOrganization, or nil
+func GetOrganization(c *gin.Context) *model.Organization {
+	value, exists := c.Get("organization")
+	if !exists {
+		return nil
+	}
+	org, _ := value.(*model.Organization)
+	return org
+}
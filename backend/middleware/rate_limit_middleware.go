@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+)
+
+// RateLimitConfig allows Requests per Period to each key, in bursts of up to Requests.
+// A config with no requests disables the limit.
+type RateLimitConfig struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit applies
+func (l RateLimitConfig) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// ParseRateLimit reads a limit written as requests/period, such as 10/1m, or off
+func ParseRateLimit(value string) (RateLimitConfig, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "off") || value == "0" {
+		return RateLimitConfig{}, nil
+	}
+	requests, period, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimitConfig{}, fmt.Errorf("rate limit %q must be requests/period, like 10/1m", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 0 {
+		return RateLimitConfig{}, fmt.Errorf("invalid request count in rate limit %q", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return RateLimitConfig{}, fmt.Errorf("invalid period in rate limit %q", value)
+	}
+	return RateLimitConfig{Requests: n, Period: d}, nil
+}
+
+// RateLimitOr parses the configured limit value, fallback when it is not set or invalid
+func RateLimitOr(value, fallback string) RateLimitConfig {
+	if value != "" {
+		limit, err := ParseRateLimit(value)
+		if err == nil {
+			return limit
+		}
+		slog.Warn("invalid rate limit, using the default", "limit", value, "error", err, "default", fallback)
+	}
+	limit, err := ParseRateLimit(fallback)
+	if err != nil {
+		panic(err)
+	}
+	return limit
+}
+
+// RateLimitKey picks the key a request is limited by, "" to let the request through
+type RateLimitKey func(c *gin.Context) string
+
+// ClientIPKey limits each client IP
+func ClientIPKey(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ParamKey limits each value of a path parameter, such as the ID of a signing request
+func ParamKey(name string) RateLimitKey {
+	return func(c *gin.Context) string {
+		return c.Param(name)
+	}
+}
+
+// rateLimitSweepInterval is how often the buckets of idle keys are dropped
+const rateLimitSweepInterval = time.Minute
+
+type rateBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per key. A bucket idle for a full period is refilled, so it
+// is dropped and created again on the next request.
+type rateLimiter struct {
+	limit RateLimitConfig
+
+	mu        sync.Mutex
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+// reserve takes a token of the bucket of key and returns 0, or how long until one is available
+func (l *rateLimiter) reserve(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		for k, bucket := range l.buckets {
+			if now.Sub(bucket.lastSeen) >= l.limit.Period {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		every := rate.Every(l.limit.Period / time.Duration(l.limit.Requests))
+		bucket = &rateBucket{limiter: rate.NewLimiter(every, l.limit.Requests)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastSeen = now
+
+	if bucket.limiter.AllowN(now, 1) {
+		return 0
+	}
+	reservation := bucket.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	return delay
+}
+
+// RateLimit answers 429 with a Retry-After header to the requests of a key beyond limit. name
+// identifies the limit in the logs and the metrics; each call has its own buckets.
+func RateLimit(name string, limit RateLimitConfig, key RateLimitKey) gin.HandlerFunc {
+	if !limit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := &rateLimiter{
+		limit:   limit,
+		buckets: make(map[string]*rateBucket),
+	}
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		delay := limiter.reserve(k, time.Now())
+		if delay == 0 {
+			c.Next()
+			return
+		}
+
+		retryAfter := int(math.Ceil(delay.Seconds()))
+		metrics.RateLimited(name)
+		logging.FromContext(c.Request.Context()).Warn("rate limit exceeded",
+			"limit", name, "client_ip", c.ClientIP(), "route", c.FullPath(), "retry_after_s", retryAfter)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":       "TooManyRequests",
+			"retry_after": retryAfter,
+		})
+	}
+}
@@ -0,0 +1,60 @@
+-- Numeración de cuentas de cobro y recibos de pago por organización y año.
+-- Ejecutar una vez en la base de datos de Supabase (SQL Editor) antes de desplegar la versión
+-- que numera los documentos; se puede volver a ejecutar sin efectos.
+BEGIN;
+
+-- document_sequence guarda el último consecutivo de cada tipo de documento por organización
+-- y año; los inmuebles sin organización usan el UUID nulo
+CREATE TABLE IF NOT EXISTS document_sequence (
+    organization_id uuid NOT NULL,
+    kind text NOT NULL,
+    year integer NOT NULL,
+    last_number bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, kind, year)
+);
+
+-- Las cuentas de cobro emitidas antes de la numeración por organización quedan sin
+-- organización y con año 0, así sus números no chocan con los nuevos consecutivos
+ALTER TABLE billing_receipt ADD COLUMN IF NOT EXISTS organization_id uuid;
+ALTER TABLE billing_receipt ADD COLUMN IF NOT EXISTS year integer NOT NULL DEFAULT 0;
+ALTER TABLE billing_receipt DROP CONSTRAINT IF EXISTS billing_receipt_number_key;
+ALTER TABLE billing_receipt DROP CONSTRAINT IF EXISTS billing_receipt_organization_id_year_number_key;
+ALTER TABLE billing_receipt ADD CONSTRAINT billing_receipt_organization_id_year_number_key
+    UNIQUE NULLS NOT DISTINCT (organization_id, year, number);
+
+ALTER TABLE rent_payment ADD COLUMN IF NOT EXISTS receipt_number text;
+ALTER TABLE rent_payment DROP CONSTRAINT IF EXISTS rent_payment_receipt_number_key;
+ALTER TABLE rent_payment ADD CONSTRAINT rent_payment_receipt_number_key UNIQUE (receipt_number);
+
+-- next_document_number reserva el siguiente consecutivo de un tipo de documento de una
+-- organización en un año. El INSERT ... ON CONFLICT bloquea la fila de la secuencia, así dos
+-- llamadas simultáneas nunca obtienen el mismo número.
+CREATE OR REPLACE FUNCTION next_document_number(sequence_organization_id uuid, sequence_kind text, sequence_year integer) RETURNS bigint
+LANGUAGE sql AS $$
+    INSERT INTO document_sequence AS s (organization_id, kind, year, last_number)
+    VALUES (sequence_organization_id, sequence_kind, sequence_year, 1)
+    ON CONFLICT (organization_id, kind, year) DO UPDATE SET last_number = s.last_number + 1
+    RETURNING s.last_number;
+$$;
+
+-- create_numbered_rent_payment registra un pago con el siguiente número de recibo de la
+-- organización en el año, en una sola transacción: si el INSERT falla el número no se consume.
+-- El número se imprime como model.DocumentNumber.String, p. ej. RP-2025-000001.
+CREATE OR REPLACE FUNCTION create_numbered_rent_payment(payment jsonb, sequence_organization_id uuid, sequence_year integer) RETURNS rent_payment
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields rent_payment := jsonb_populate_record(NULL::rent_payment, payment);
+    receipt bigint := next_document_number(sequence_organization_id, 'recibo_de_pago', sequence_year);
+    saved rent_payment;
+BEGIN
+    INSERT INTO rent_payment (id, rental_id, payment_date, amount_paid, paid_on_time, receipt_number)
+    VALUES (coalesce(fields.id, gen_random_uuid()), fields.rental_id, fields.payment_date,
+        coalesce(fields.amount_paid, 0), coalesce(fields.paid_on_time, true),
+        'RP-' || sequence_year || '-' || lpad(receipt::text, 6, '0'))
+    RETURNING * INTO saved;
+
+    RETURN saved;
+END;
+$$;
+
+COMMIT;
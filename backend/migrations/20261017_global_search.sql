@@ -0,0 +1,55 @@
+-- Búsqueda global de personas, inmuebles, arriendos y archivos (GET /api/search).
+-- Ejecutar una vez en la base de datos de Supabase (SQL Editor) antes de desplegar la versión
+-- con la búsqueda; se puede volver a ejecutar sin efectos.
+BEGIN;
+
+-- search_normalize pasa el texto a minúsculas y sin tildes para la búsqueda
+CREATE OR REPLACE FUNCTION search_normalize(value text) RETURNS text
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT translate(lower(coalesce(value, '')), 'áéíóúüñ', 'aeiouun');
+$$;
+
+-- search_all busca por texto en personas, inmuebles, arriendos y archivos. Cada palabra de la
+-- consulta se busca como prefijo, así "carrera 18" encuentra "Carrera 18b".
+CREATE OR REPLACE FUNCTION search_all(search_query text, max_results int DEFAULT 20)
+RETURNS TABLE (kind text, id uuid, title text, subtitle text, property_id uuid, rental_id uuid, person_id uuid, rank real)
+LANGUAGE sql STABLE AS $$
+    WITH q AS (
+        SELECT to_tsquery('simple', string_agg(word || ':*', ' & ')) AS query
+        FROM unnest(regexp_split_to_array(search_normalize(search_query), '[^[:alnum:]]+')) AS word
+        WHERE word <> ''
+    ),
+    documents AS (
+        SELECT 'person' AS kind, p.id, p.full_name AS title, nullif(p.nit, '') AS subtitle,
+            NULL::uuid AS property_id, NULL::uuid AS rental_id, p.id AS person_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', p.full_name, p.nit, p.phone, u.email))) AS document
+        FROM person p
+        LEFT JOIN users u ON u.person_id = p.id
+        UNION ALL
+        SELECT 'property', pr.id, concat_ws(' Apto ', pr.address, nullif(pr.apt_number, '')), pr.city,
+            pr.id, NULL, pr.resident_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', pr.address, pr.apt_number, pr.city, pr.state, pr.zip_code, pr.type)))
+        FROM property pr
+        UNION ALL
+        SELECT 'rental', r.id, concat_ws(' Apto ', pr.address, nullif(pr.apt_number, '')), p.full_name,
+            r.property_id, r.id, r.renter_id,
+            to_tsvector('simple', search_normalize(concat_ws(' ', pr.address, pr.apt_number, pr.city, p.full_name, p.nit, r.payment_terms)))
+        FROM rental r
+        LEFT JOIN property pr ON pr.id = r.property_id
+        LEFT JOIN person p ON p.id = r.renter_id
+        UNION ALL
+        SELECT 'file', f.id, coalesce(nullif(f.original_name, ''), f.file_name), f.category,
+            r.property_id, f.rental_id, NULL,
+            to_tsvector('simple', search_normalize(concat_ws(' ', f.original_name, f.file_name, f.category, array_to_string(f.tags, ' '))))
+        FROM file_metadata f
+        LEFT JOIN rental r ON r.id = f.rental_id
+    )
+    SELECT d.kind, d.id, d.title, d.subtitle, d.property_id, d.rental_id, d.person_id,
+        ts_rank(d.document, q.query) AS rank
+    FROM documents d, q
+    WHERE d.document @@ q.query
+    ORDER BY rank DESC, d.title
+    LIMIT max_results;
+$$;
+
+COMMIT;
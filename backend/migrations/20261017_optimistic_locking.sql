@@ -0,0 +1,30 @@
+-- Control de concurrencia optimista de personas, inmuebles, arriendos y pricing.
+-- Ejecutar una vez en la base de datos de Supabase (SQL Editor) antes de desplegar la versión
+-- que exige la versión en las actualizaciones; se puede volver a ejecutar sin efectos.
+BEGIN;
+
+ALTER TABLE person ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE rental ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE pricing ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+
+-- bump_version incrementa la versión en cada UPDATE; los repositorios solo actualizan la
+-- versión que leyó el cliente
+CREATE OR REPLACE FUNCTION bump_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS person_version ON person;
+CREATE TRIGGER person_version BEFORE UPDATE ON person FOR EACH ROW EXECUTE FUNCTION bump_version();
+DROP TRIGGER IF EXISTS property_version ON property;
+CREATE TRIGGER property_version BEFORE UPDATE ON property FOR EACH ROW EXECUTE FUNCTION bump_version();
+DROP TRIGGER IF EXISTS rental_version ON rental;
+CREATE TRIGGER rental_version BEFORE UPDATE ON rental FOR EACH ROW EXECUTE FUNCTION bump_version();
+DROP TRIGGER IF EXISTS pricing_version ON pricing;
+CREATE TRIGGER pricing_version BEFORE UPDATE ON pricing FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+COMMIT;
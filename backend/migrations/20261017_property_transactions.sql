@@ -0,0 +1,94 @@
+-- Alta y edición de inmuebles con sus administradores en una sola transacción.
+-- Ejecutar una vez en la base de datos de Supabase (SQL Editor), después de
+-- 20261017_optimistic_locking.sql, antes de desplegar la versión que crea y actualiza los
+-- inmuebles con estas funciones; se puede volver a ejecutar sin efectos.
+BEGIN;
+
+-- Columnas de la ficha del inmueble que escriben create_property y update_property
+ALTER TABLE property ADD COLUMN IF NOT EXISTS apt_number text NOT NULL DEFAULT '';
+ALTER TABLE property ADD COLUMN IF NOT EXISTS building_id uuid;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS bedrooms integer NOT NULL DEFAULT 0;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS bathrooms integer NOT NULL DEFAULT 0;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS area_m2 numeric NOT NULL DEFAULT 0;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS estrato integer NOT NULL DEFAULT 0;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS parking_spots integer NOT NULL DEFAULT 0;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS parking_details text NOT NULL DEFAULT '';
+ALTER TABLE property ADD COLUMN IF NOT EXISTS furnished boolean NOT NULL DEFAULT false;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS pets_allowed boolean NOT NULL DEFAULT false;
+ALTER TABLE property ADD COLUMN IF NOT EXISTS boundaries text NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS property_managers (
+    property_id uuid NOT NULL,
+    manager_person_id uuid NOT NULL,
+    PRIMARY KEY (property_id, manager_person_id)
+);
+
+-- property_with_managers devuelve el inmueble como JSON con los IDs de sus administradores
+CREATE OR REPLACE FUNCTION property_with_managers(saved property) RETURNS jsonb
+LANGUAGE sql STABLE AS $$
+    SELECT to_jsonb(saved) || jsonb_build_object('manager_ids', coalesce(
+        (SELECT jsonb_agg(pm.manager_person_id) FROM property_managers pm WHERE pm.property_id = saved.id),
+        '[]'::jsonb));
+$$;
+
+-- create_property inserta el inmueble y sus vínculos con administradores en una sola
+-- transacción: si algo falla no queda el inmueble sin administradores ni vínculos huérfanos
+CREATE OR REPLACE FUNCTION create_property(data jsonb, managers uuid[]) RETURNS jsonb
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields property := jsonb_populate_record(NULL::property, data);
+    saved property;
+BEGIN
+    INSERT INTO property (id, address, apt_number, city, state, zip_code, type, resident_id, building_id,
+        bedrooms, bathrooms, area_m2, estrato, parking_spots, parking_details, furnished, pets_allowed, boundaries)
+    VALUES (coalesce(fields.id, gen_random_uuid()), fields.address, fields.apt_number, fields.city, fields.state,
+        fields.zip_code, fields.type, fields.resident_id, fields.building_id, fields.bedrooms, fields.bathrooms,
+        fields.area_m2, fields.estrato, fields.parking_spots, fields.parking_details, fields.furnished,
+        fields.pets_allowed, fields.boundaries)
+    RETURNING * INTO saved;
+
+    INSERT INTO property_managers (property_id, manager_person_id)
+    SELECT saved.id, m FROM unnest(managers) AS m
+    ON CONFLICT DO NOTHING;
+
+    RETURN property_with_managers(saved);
+END;
+$$;
+
+-- update_property actualiza el inmueble y reemplaza sus administradores en una sola
+-- transacción. Con expected_version solo actualiza esa versión y, si el inmueble cambió,
+-- falla con PT409 (409 en PostgREST). Devuelve NULL si el inmueble no existe.
+CREATE OR REPLACE FUNCTION update_property(data jsonb, managers uuid[], expected_version integer DEFAULT NULL) RETURNS jsonb
+LANGUAGE plpgsql AS $$
+DECLARE
+    fields property := jsonb_populate_record(NULL::property, data);
+    saved property;
+BEGIN
+    UPDATE property p SET address = fields.address, apt_number = fields.apt_number, city = fields.city,
+        state = fields.state, zip_code = fields.zip_code, type = fields.type, resident_id = fields.resident_id,
+        building_id = fields.building_id, bedrooms = fields.bedrooms, bathrooms = fields.bathrooms,
+        area_m2 = fields.area_m2, estrato = fields.estrato, parking_spots = fields.parking_spots,
+        parking_details = fields.parking_details, furnished = fields.furnished,
+        pets_allowed = fields.pets_allowed, boundaries = fields.boundaries
+    WHERE p.id = fields.id AND (expected_version IS NULL OR p.version = expected_version)
+    RETURNING p.* INTO saved;
+
+    IF NOT FOUND THEN
+        IF expected_version IS NOT NULL AND EXISTS (SELECT 1 FROM property WHERE id = fields.id) THEN
+            RAISE EXCEPTION 'property % changed since version %', fields.id, expected_version
+                USING ERRCODE = 'PT409';
+        END IF;
+        RETURN NULL;
+    END IF;
+
+    DELETE FROM property_managers pm
+    WHERE pm.property_id = saved.id AND pm.manager_person_id <> ALL (managers);
+    INSERT INTO property_managers (property_id, manager_person_id)
+    SELECT saved.id, m FROM unnest(managers) AS m
+    ON CONFLICT DO NOTHING;
+
+    RETURN property_with_managers(saved);
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,39 @@
+-- Registro idempotente de los pagos en línea: el pago y el resultado del checkout se guardan en
+-- la misma transacción. Ejecutar una vez en la base de datos de Supabase (SQL Editor) después de
+-- 20261016_document_numbering.sql; se puede volver a ejecutar sin efectos.
+BEGIN;
+
+-- register_checkout_payment registra el pago de un checkout aprobado y guarda el resultado del
+-- checkout en una sola transacción. La fila del checkout se bloquea primero: si el evento de la
+-- pasarela llega dos veces, aun a instancias distintas, la segunda llamada devuelve el pago ya
+-- registrado sin crear otro.
+CREATE OR REPLACE FUNCTION register_checkout_payment(checkout_id uuid, result jsonb, payment jsonb, sequence_organization_id uuid, sequence_year integer) RETURNS rent_payment
+LANGUAGE plpgsql AS $$
+DECLARE
+    checkout payment_checkout;
+    saved rent_payment;
+BEGIN
+    SELECT * INTO checkout FROM payment_checkout WHERE id = checkout_id FOR UPDATE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'payment checkout % does not exist', checkout_id;
+    END IF;
+
+    IF checkout.rent_payment_id IS NOT NULL THEN
+        SELECT * INTO saved FROM rent_payment WHERE id = checkout.rent_payment_id;
+        RETURN saved;
+    END IF;
+
+    saved := create_numbered_rent_payment(payment, sequence_organization_id, sequence_year);
+    UPDATE payment_checkout SET
+        status = 'approved',
+        transaction_id = result->>'transaction_id',
+        payment_method = result->>'payment_method',
+        completed_at = (result->>'completed_at')::timestamptz,
+        rent_payment_id = saved.id
+    WHERE id = checkout_id;
+
+    RETURN saved;
+END;
+$$;
+
+COMMIT;
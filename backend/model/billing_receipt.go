@@ -0,0 +1,37 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingReceipt records a cuenta de cobro issued to a tenant. Numbers are consecutive within
+// the organization of the property and the year, and a rental gets at most one receipt per
+// billing period.
+type BillingReceipt struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"` // Nil for properties without organization
+	Year           int        `json:"year"`                      // Year of the numbering sequence, 0 for the receipts numbered before it
+	Number         int64      `json:"number"`
+	RentalID       uuid.UUID  `json:"rental_id"`
+	PersonID       uuid.UUID  `json:"person_id"` // Tenant the receipt was issued to
+	Period         string     `json:"period"`    // Billing month, YYYY-MM
+	Total          float64    `json:"total"`
+	FilePath       string     `json:"file_path,omitempty"` // PDF in the file storage, empty when it could not be stored
+	IssuedAt       time.Time  `json:"issued_at"`
+}
+
+// BillingPeriod returns the billing period of a date, YYYY-MM
+func BillingPeriod(date time.Time) string {
+	return date.Format("2006-01")
+}
+
+// FormattedNumber returns the number printed on the receipt, e.g. CC-2025-000001
+func (r BillingReceipt) FormattedNumber() string {
+	if r.Year == 0 {
+		return fmt.Sprintf("CC-%06d", r.Number)
+	}
+	return DocumentNumber{Kind: DocumentKindBillingReceipt, Year: r.Year, Number: r.Number}.String()
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// States of a bucket backup
+const (
+	BucketBackupRunning   = "running"
+	BucketBackupCompleted = "completed"
+	BucketBackupPartial   = "partial" // Some files could not be backed up
+	BucketBackupFailed    = "failed"
+)
+
+// Modes of a bucket backup
+const (
+	BucketBackupFull  = "full"  // Every file is copied
+	BucketBackupDelta = "delta" // Only the files that are new or changed since the last backup
+)
+
+// BucketBackupEntry is a file of the bucket as recorded in a backup manifest. Locations maps each
+// backup provider to where the copy is kept there; unchanged files keep the copy of a previous run.
+type BucketBackupEntry struct {
+	Path      string            `json:"path"`
+	Size      int64             `json:"size"`
+	SHA256    string            `json:"sha256"`
+	UpdatedAt string            `json:"updated_at"`
+	Locations map[string]string `json:"locations"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// BucketBackup is a run of the scheduled bucket backup. Manifest lists every file of the bucket
+// at the time of the run, so any run can be restored on its own; a copy of it is also stored with
+// the backup providers, at ManifestLocations.
+type BucketBackup struct {
+	ID                uuid.UUID           `json:"id"`
+	Status            string              `json:"status"`
+	Mode              string              `json:"mode"`
+	Providers         []string            `json:"providers"`
+	FileCount         int                 `json:"file_count"`
+	Copied            int                 `json:"copied"`
+	Failed            int                 `json:"failed"`
+	TotalSize         int64               `json:"total_size"`
+	Manifest          []BucketBackupEntry `json:"manifest,omitempty"`
+	ManifestLocations map[string]string   `json:"manifest_locations,omitempty"`
+	Error             string              `json:"error,omitempty"`
+	StartedAt         time.Time           `json:"started_at"`
+	FinishedAt        *time.Time          `json:"finished_at,omitempty"`
+}
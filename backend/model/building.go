@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Building is a copropiedad (edificio or conjunto) grouping properties under the same
+// administration. Its manual de convivencia is the reglamento of its BuildingKey.
+type Building struct {
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name"` // Printed in the contracts, e.g. "Edificio Torres del Parque P.H."
+	Address            string    `json:"address"`
+	City               string    `json:"city"`
+	NIT                string    `json:"nit,omitempty"`
+	AdminFee           float64   `json:"admin_fee"` // Cuota ordinaria mensual de administración of each unit
+	AdministratorName  string    `json:"administrator_name,omitempty"`
+	AdministratorPhone string    `json:"administrator_phone,omitempty"`
+	AdministratorEmail string    `json:"administrator_email,omitempty"`
+	Notes              string    `json:"notes,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// BuildingKey returns the key of the reglamentos of the building
+func (b Building) BuildingKey() string {
+	return BuildingKey(b.Address, b.City)
+}
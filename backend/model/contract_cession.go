@@ -0,0 +1,62 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons of a contract cession
+const (
+	CessionReasonSale    = "sale"    // The owner sold the property
+	CessionReasonCession = "cession" // The owner ceded the contract without selling
+)
+
+// ContractCession records the cession of the lessor's position in a rental contract to a new
+// owner (cesión de derechos del ARRENDADOR). From the effective date the rent is paid to the
+// bank account of the new owner; earlier payments stay attributed to the previous owner.
+type ContractCession struct {
+	ID                    uuid.UUID  `json:"id"`
+	RentalID              uuid.UUID  `json:"rental_id"`
+	PreviousOwnerID       uuid.UUID  `json:"previous_owner_id"`
+	NewOwnerID            uuid.UUID  `json:"new_owner_id"`
+	PreviousBankAccountID uuid.UUID  `json:"previous_bank_account_id"`
+	NewBankAccountID      uuid.UUID  `json:"new_bank_account_id"`
+	Reason                string     `json:"reason"`
+	EffectiveDate         time.Time  `json:"effective_date"`
+	LetterPath            string     `json:"letter_path,omitempty"` // Notification letter sent to the tenant
+	NotifiedAt            *time.Time `json:"notified_at,omitempty"`
+	AppliedAt             *time.Time `json:"applied_at,omitempty"` // When the rental was switched to the new bank account
+	CreatedBy             uuid.UUID  `json:"created_by"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// IsEffective reports whether the cession applies on the given date
+func (c ContractCession) IsEffective(at time.Time) bool {
+	return !at.Before(c.EffectiveDate)
+}
+
+// OwnerAt returns the owner of a rental on a date from its cessions: the new owner of the last
+// cession effective on that date, or the previous owner of the first one. It returns false
+// when the rental has no cessions.
+func OwnerAt(cessions []ContractCession, at time.Time) (uuid.UUID, bool) {
+	if len(cessions) == 0 {
+		return uuid.Nil, false
+	}
+
+	sorted := make([]ContractCession, len(cessions))
+	copy(sorted, cessions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveDate.Before(sorted[j].EffectiveDate)
+	})
+
+	owner := sorted[0].PreviousOwnerID
+	for _, cession := range sorted {
+		if !cession.IsEffective(at) {
+			break
+		}
+		owner = cession.NewOwnerID
+	}
+	return owner, true
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // SigningStatus represents the current state of a signature request
 type SigningStatus string
@@ -12,14 +15,25 @@ const (
 	StatusExpired  SigningStatus = "expired"
 )
 
+// Documents signed through the signing flow. The ContractID of a signing request holds the ID
+// of the signed document: the rental for contracts, the inspection for inspections.
+const (
+	SigningDocumentContract   = "contract"
+	SigningDocumentInspection = "inspection"
+)
+
 // ContractSigningInfo holds information for contract signing
 type ContractSigningInfo struct {
-	ContractID     string // UUID for the contract
-	RecipientID    string // Person ID of the recipient
-	RecipientEmail string // Email of the recipient
-	PDFData        []byte // PDF data
-	SignerName     string // Name of the signer
-	SignatureID    string // UUID for the signature
+	ContractID     string        // UUID for the contract
+	RecipientID    string        // Person ID of the recipient
+	RecipientEmail string        // Email of the recipient
+	PDFData        []byte        // PDF data
+	SignerName     string        // Name of the signer
+	SignatureID    string        // UUID for the signature
+	BaseURL        string        // Base URL for the signing link (organization domain), APP_BASE_URL if empty
+	RequestedBy    string        // Person ID of the admin or manager who requested the signature
+	DocumentType   string        // One of the SigningDocument constants, contract if empty
+	Organization   *Organization // Organization whose branding the signing email carries, nil for none
 }
 
 // ContractSigningRequest represents a request to sign a contract
@@ -33,6 +47,10 @@ type ContractSigningRequest struct {
 	ExpiresAt      time.Time     // When expires
 	SignedAt       *time.Time    // When signed (if signed)
 	SignatureData  []byte        // The signature data (if signed)
+	Provider       string        // E-sign provider (empty for the built-in signer)
+	ExternalID     string        // Envelope/document ID on the external provider
+	RequestedBy    string        // Person ID of the admin or manager who requested the signature
+	DocumentType   string        // One of the SigningDocument constants, contract if empty
 }
 
 // Spanish status translations for display purposes
@@ -42,3 +60,31 @@ var StatusTranslations = map[string]string{
 	string(StatusRejected): "Rechazado",
 	string(StatusExpired):  "Expirado",
 }
+
+// SigningEvidence identifies where a signing request was signed or rejected from
+type SigningEvidence struct {
+	IPAddress string
+	UserAgent string
+	Latitude  *float64 // Reported by the signer's browser, optional
+	Longitude *float64
+	Accuracy  *float64 // Radius in meters of the reported location
+}
+
+// HasLocation reports whether the signer shared a valid location
+func (e SigningEvidence) HasLocation() bool {
+	return e.Latitude != nil && e.Longitude != nil &&
+		*e.Latitude >= -90 && *e.Latitude <= 90 &&
+		*e.Longitude >= -180 && *e.Longitude <= 180
+}
+
+// Location returns the coordinates of the signer ("4.60971,-74.08175 (±20 m)"), empty when unknown
+func (e SigningEvidence) Location() string {
+	if !e.HasLocation() {
+		return ""
+	}
+	location := fmt.Sprintf("%.5f,%.5f", *e.Latitude, *e.Longitude)
+	if e.Accuracy != nil && *e.Accuracy > 0 {
+		location += fmt.Sprintf(" (±%.0f m)", *e.Accuracy)
+	}
+	return location
+}
@@ -0,0 +1,73 @@
+package model
+
+import "github.com/google/uuid"
+
+// ContractTemplate is an editable rental contract. Text may contain placeholders such as
+// {{arrendador}} or {{canon}} that are replaced with the contract data when rendering the PDF.
+type ContractTemplate struct {
+	ID              uuid.UUID                `json:"id"`
+	Name            string                   `json:"name"`
+	ManagerID       *uuid.UUID               `json:"manager_id,omitempty"` // Manager owning the template, nil when shared by all managers
+	ContractType    string                   `json:"contract_type"`        // One of the ContractType constants, empty means vivienda urbana
+	Title           string                   `json:"title"`
+	Clauses         []ContractClause         `json:"clauses"`
+	ClosingText     string                   `json:"closing_text"`
+	SignatureBlocks []ContractSignatureBlock `json:"signature_blocks,omitempty"` // Parties signing the contract, in order
+	Variables       map[string]string        `json:"variables,omitempty"`        // Custom placeholders (building name, notary deed, bank account...)
+	IsDefault       bool                     `json:"is_default"`                 // Used for its contract type when no template is selected
+}
+
+// ContractClause is a clause of a contract template. The title is written without its
+// ordinal (PRIMERA, SEGUNDA...), clauses are numbered in order when rendering.
+type ContractClause struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// ContractSignatureBlock is a signature box of the contract. Party selects whose data
+// (name, CC, email and phone) is printed in it.
+type ContractSignatureBlock struct {
+	Label string `json:"label"` // e.g. ARRENDADOR, REPRESENTANTE LEGAL
+	Party string `json:"party"` // One of the ContractParty constants
+}
+
+// Contract types, each with its own clause set and signature blocks
+const (
+	ContractTypeViviendaUrbana = "vivienda_urbana"
+	ContractTypeLocalComercial = "local_comercial"
+	ContractTypeHabitacion     = "habitacion"
+	ContractTypeParqueadero    = "parqueadero"
+)
+
+// ContractTypes lists the supported contract types
+var ContractTypes = []string{ContractTypeViviendaUrbana, ContractTypeLocalComercial, ContractTypeHabitacion, ContractTypeParqueadero}
+
+// IsValidContractType reports whether contractType is a known contract type (empty means vivienda urbana)
+func IsValidContractType(contractType string) bool {
+	if contractType == "" {
+		return true
+	}
+	for _, known := range ContractTypes {
+		if contractType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Parties of a contract for ContractSignatureBlock.Party
+const (
+	ContractPartyArrendador   = "arrendador"
+	ContractPartyArrendatario = "arrendatario"
+	ContractPartyCodeudor     = "codeudor"
+	ContractPartyTestigo      = "testigo"
+)
+
+// IsValidContractParty reports whether party is a known contract party
+func IsValidContractParty(party string) bool {
+	switch party {
+	case ContractPartyArrendador, ContractPartyArrendatario, ContractPartyCodeudor, ContractPartyTestigo:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest frequencies a manager can subscribe to
+const (
+	DigestFrequencyWeekly  = "weekly"
+	DigestFrequencyMonthly = "monthly"
+)
+
+// IsValidDigestFrequency reports whether frequency is a known digest frequency (empty unsubscribes)
+func IsValidDigestFrequency(frequency string) bool {
+	return frequency == "" || frequency == DigestFrequencyWeekly || frequency == DigestFrequencyMonthly
+}
+
+// ManagerDigestSubscription is the opt-in of a manager to the summary digest email
+type ManagerDigestSubscription struct {
+	ID          uuid.UUID      `json:"id"`
+	PersonID    uuid.UUID      `json:"person_id"`
+	Frequency   string         `json:"frequency"` // weekly, monthly or empty when unsubscribed
+	LastSentAt  *time.Time     `json:"last_sent_at,omitempty"`
+	LastArrears map[string]int `json:"last_arrears,omitempty"` // Unpaid months per rental ID when the last digest was sent
+}
@@ -0,0 +1,31 @@
+package model
+
+import "fmt"
+
+// DocumentKind is a kind of document numbered in its own sequence
+type DocumentKind string
+
+// Kinds of numbered documents
+const (
+	DocumentKindBillingReceipt DocumentKind = "cuenta_de_cobro" // Monthly billing receipts sent with the reminders
+	DocumentKindPaymentReceipt DocumentKind = "recibo_de_pago"  // Receipts of the registered rent payments
+)
+
+// documentPrefixes are printed before the year and consecutive of each kind of document
+var documentPrefixes = map[DocumentKind]string{
+	DocumentKindBillingReceipt: "CC",
+	DocumentKindPaymentReceipt: "RP",
+}
+
+// DocumentNumber is the consecutive of a document. Each organization numbers each kind of
+// document from 1 every year.
+type DocumentNumber struct {
+	Kind   DocumentKind `json:"kind"`
+	Year   int          `json:"year"`
+	Number int64        `json:"number"`
+}
+
+// String returns the number printed on the document, e.g. CC-2025-000001
+func (n DocumentNumber) String() string {
+	return fmt.Sprintf("%s-%d-%06d", documentPrefixes[n.Kind], n.Year, n.Number)
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Due days of 29 to 31 do not exist in every month: a rent due on the 30th is due on February
+// 28 (29 in leap years), and one due on the 31st on the 30th of April, June, September and
+// November. A due date on a weekend or a Colombian holiday moves to the next business day.
+// Every comparison against Pricing.DueDay goes through these helpers so reminders, late
+// payments and schedules agree on the date.
+
+// DaysInMonth returns the number of days of a month, February 29 included in leap years
+func DaysInMonth(year int, month time.Month) int {
+	// Day 0 of the next month is the last day of this one
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// NormalizeDueDay returns the day of the month a due day falls on: the day itself, or the last
+// day of the month when the month is shorter. Days below 1 are treated as 1.
+func NormalizeDueDay(dueDay, year int, month time.Month) int {
+	if dueDay < 1 {
+		return 1
+	}
+	if last := DaysInMonth(year, month); dueDay > last {
+		return last
+	}
+	return dueDay
+}
+
+// DueDateIn returns the due date of a month at midnight in loc
+func DueDateIn(dueDay, year int, month time.Month, loc *time.Location) time.Time {
+	return time.Date(year, month, NormalizeDueDay(dueDay, year, month), 0, 0, 0, 0, loc)
+}
+
+// BusinessDueDateIn returns the date the rent of a month is due at midnight in loc: its due
+// date, or the next business day when that is a weekend day or a holiday. It can fall in the
+// next month (a rent due on Saturday the 31st is due on Monday the 2nd).
+func BusinessDueDateIn(dueDay, year int, month time.Month, loc *time.Location) time.Time {
+	return NextBusinessDay(DueDateIn(dueDay, year, month, loc))
+}
+
+// IsDueDay reports whether date is the due day of its month
+func IsDueDay(date time.Time, dueDay int) bool {
+	return date.Day() == NormalizeDueDay(dueDay, date.Year(), date.Month())
+}
+
+// DueDate returns the date the rent of the month of date is due, moved to the next business
+// day when needed, in the location of date
+func (p Pricing) DueDate(date time.Time) time.Time {
+	return BusinessDueDateIn(p.DueDay, date.Year(), date.Month(), date.Location())
+}
+
+// IsDueOn reports whether the rent is due on date, counting the due dates moved past a weekend
+// or holiday: the one of the month of date, or the one of the previous month moved into it
+func (p Pricing) IsDueOn(date time.Time) bool {
+	previousMonth := time.Date(date.Year(), date.Month()-1, 1, 0, 0, 0, 0, date.Location())
+	for _, dueDate := range []time.Time{
+		p.DueDate(date),
+		BusinessDueDateIn(p.DueDay, previousMonth.Year(), previousMonth.Month(), date.Location()),
+	} {
+		if dueDate.Year() == date.Year() && dueDate.Month() == date.Month() && dueDate.Day() == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaidOnTime reports whether a payment made on paidAt is within the due date of its month,
+// moved to the next business day when needed
+func (p Pricing) IsPaidOnTime(paidAt time.Time) bool {
+	dueDate := p.DueDate(paidAt)
+	return paidAt.Before(dueDate.AddDate(0, 0, 1))
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeDueDay(t *testing.T) {
+	tests := []struct {
+		name   string
+		dueDay int
+		year   int
+		month  time.Month
+		want   int
+	}{
+		{"day within the month", 15, 2025, time.March, 15},
+		{"day below 1", 0, 2025, time.March, 1},
+		{"29 in February of a non-leap year", 29, 2025, time.February, 28},
+		{"30 in February of a non-leap year", 30, 2025, time.February, 28},
+		{"31 in February of a non-leap year", 31, 2025, time.February, 28},
+		{"29 in February of a leap year", 29, 2024, time.February, 29},
+		{"30 in February of a leap year", 30, 2024, time.February, 29},
+		{"31 in February of a leap year", 31, 2024, time.February, 29},
+		{"29 in February of a century non-leap year", 29, 2100, time.February, 28},
+		{"29 in February of a 400-year leap year", 29, 2000, time.February, 29},
+		{"31 in a 30-day month", 31, 2025, time.April, 30},
+		{"31 in a 31-day month", 31, 2025, time.December, 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeDueDay(tt.dueDay, tt.year, tt.month); got != tt.want {
+				t.Errorf("NormalizeDueDay(%d, %d, %s) = %d, want %d", tt.dueDay, tt.year, tt.month, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueDateIn(t *testing.T) {
+	bogota := time.FixedZone("America/Bogota", -5*60*60)
+	tests := []struct {
+		name   string
+		dueDay int
+		year   int
+		month  time.Month
+		want   time.Time
+	}{
+		{"31 in February of a non-leap year", 31, 2025, time.February, time.Date(2025, time.February, 28, 0, 0, 0, 0, bogota)},
+		{"30 in February of a leap year", 30, 2024, time.February, time.Date(2024, time.February, 29, 0, 0, 0, 0, bogota)},
+		{"31 in June", 31, 2025, time.June, time.Date(2025, time.June, 30, 0, 0, 0, 0, bogota)},
+		{"5 in January", 5, 2025, time.January, time.Date(2025, time.January, 5, 0, 0, 0, 0, bogota)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DueDateIn(tt.dueDay, tt.year, tt.month, bogota); !got.Equal(tt.want) {
+				t.Errorf("DueDateIn(%d, %d, %s) = %s, want %s", tt.dueDay, tt.year, tt.month, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDueDay(t *testing.T) {
+	tests := []struct {
+		name   string
+		date   time.Time
+		dueDay int
+		want   bool
+	}{
+		{"last day of February for a due day of 31", time.Date(2025, time.February, 28, 10, 0, 0, 0, time.UTC), 31, true},
+		{"last day of February for a due day of 29 in a leap year", time.Date(2024, time.February, 29, 10, 0, 0, 0, time.UTC), 29, true},
+		{"February 28 for a due day of 29 in a leap year", time.Date(2024, time.February, 28, 10, 0, 0, 0, time.UTC), 29, false},
+		{"last day of April for a due day of 31", time.Date(2025, time.April, 30, 10, 0, 0, 0, time.UTC), 31, true},
+		{"last day of a 31-day month for a due day of 30", time.Date(2025, time.March, 31, 10, 0, 0, 0, time.UTC), 30, false},
+		{"last day of a 31-day month for a due day of 31", time.Date(2025, time.March, 31, 10, 0, 0, 0, time.UTC), 31, true},
+		{"day before the due day", time.Date(2025, time.March, 4, 10, 0, 0, 0, time.UTC), 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDueDay(tt.date, tt.dueDay); got != tt.want {
+				t.Errorf("IsDueDay(%s, %d) = %t, want %t", tt.date.Format("2006-01-02"), tt.dueDay, got, tt.want)
+			}
+		})
+	}
+}
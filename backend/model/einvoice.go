@@ -0,0 +1,23 @@
+package model
+
+// Status of the DIAN electronic invoice of a rent payment
+const (
+	EInvoiceStatusPending  = "pending"  // Submitted, waiting for the validation of the DIAN
+	EInvoiceStatusAccepted = "accepted" // Validated by the DIAN
+	EInvoiceStatusRejected = "rejected" // Rejected by the DIAN, may be corrected and sent again
+	EInvoiceStatusFailed   = "failed"   // Could not be submitted to the provider
+)
+
+// EInvoiceStatusTranslations maps electronic invoice statuses to Spanish
+var EInvoiceStatusTranslations = map[string]string{
+	EInvoiceStatusPending:  "En validación",
+	EInvoiceStatusAccepted: "Aceptada por la DIAN",
+	EInvoiceStatusRejected: "Rechazada por la DIAN",
+	EInvoiceStatusFailed:   "Error de envío",
+}
+
+// EInvoiceResubmittable reports whether an invoice in the status may be submitted again with
+// the same number
+func EInvoiceResubmittable(status string) bool {
+	return status == "" || status == EInvoiceStatusRejected || status == EInvoiceStatusFailed
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplate is a version of an email customized by the admins. Subject and Body are Go
+// templates ({{.ArrendatarioNombre}}, ...) rendered with the data of the email identified by
+// Key. The latest version of a key is the one sent; without versions the embedded default is.
+type EmailTemplate struct {
+	ID        uuid.UUID  `json:"id"`
+	Key       string     `json:"key"`
+	Version   int        `json:"version"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"` // Person ID of the admin who saved it
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feature flags admins toggle at runtime, besides the one of each scheduled job
+// (FeatureFlagJobPrefix + the job name)
+const (
+	FeatureTelegramBackup     = "telegram_backup"
+	FeatureEInvoiceAutoSubmit = "einvoice_auto_submit"
+)
+
+// FeatureFlagJobPrefix is the prefix of the flags that pause a scheduled job
+const FeatureFlagJobPrefix = "job_"
+
+// FeatureFlag is a feature that can be turned on or off without a restart. The value saved
+// by an admin overrides the environment variable of the feature.
+type FeatureFlag struct {
+	Key         string     `json:"key"`
+	Enabled     bool       `json:"enabled"`
+	Description string     `json:"description,omitempty"` // Not stored
+	Source      string     `json:"source,omitempty"`      // "env", "default" or "admin", not stored
+	UpdatedBy   *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
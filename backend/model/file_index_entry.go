@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// FileIndexEntry is a file of the uploads bucket as recorded in the file_index table, so the
+// file listings do not have to scan every user folder of the storage backend
+type FileIndexEntry struct {
+	Path       string    `json:"path"`
+	UserID     string    `json:"user_id"` // Empty for files at the root of the bucket
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	MimeType   string    `json:"mime_type"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
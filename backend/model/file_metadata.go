@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document categories of uploaded files
+const (
+	FileCategoryCedula             = "cedula"
+	FileCategoryContrato           = "contrato"
+	FileCategoryComprobantePago    = "comprobante_pago"
+	FileCategoryCertificadoLaboral = "certificado_laboral"
+	FileCategoryOtro               = "otro"
+)
+
+const (
+	// MaxFileTags is the number of tags a file can have
+	MaxFileTags = 20
+	// MaxFileTagLength is the length of a tag, in characters
+	MaxFileTagLength = 50
+)
+
+// IsValidFileCategory reports whether category is a known document category
+func IsValidFileCategory(category string) bool {
+	switch category {
+	case FileCategoryCedula, FileCategoryContrato, FileCategoryComprobantePago,
+		FileCategoryCertificadoLaboral, FileCategoryOtro:
+		return true
+	}
+	return false
+}
+
+// NormalizeFileTags trims and lowercases tags, dropping empty and repeated ones. Each value
+// may hold several comma separated tags.
+func NormalizeFileTags(values []string) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// FileMetadata records the document category and tags of an uploaded file and the rental or
+// contract it belongs to. FileName is the name the file is stored under, unique across the bucket.
+type FileMetadata struct {
+	ID           uuid.UUID  `json:"id"`
+	FileName     string     `json:"file_name"`
+	Path         string     `json:"path"`
+	OriginalName string     `json:"original_name"`
+	Size         int64      `json:"size"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	UserID       string     `json:"user_id"`
+	Category     string     `json:"category,omitempty"`
+	Tags         []string   `json:"tags"`
+	RentalID     *uuid.UUID `json:"rental_id,omitempty"`
+	ContractID   string     `json:"contract_id,omitempty"`
+	UploadedBy   string     `json:"uploaded_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsEmpty reports whether there is nothing to record for the file
+func (m FileMetadata) IsEmpty() bool {
+	return m.Category == "" && len(m.Tags) == 0 && m.RentalID == nil && m.ContractID == ""
+}
+
+// FileMetadataFilter selects files by owner, category or tags. Files must have every tag.
+type FileMetadataFilter struct {
+	UserID   string
+	Category string
+	Tags     []string
+}
+
+// IsEmpty reports whether the filter selects every file of the owner
+func (f FileMetadataFilter) IsEmpty() bool {
+	return f.Category == "" && len(f.Tags) == 0
+}
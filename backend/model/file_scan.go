@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Results of a malware scan of an uploaded file
+const (
+	FileScanClean    = "clean"
+	FileScanInfected = "infected"
+	FileScanError    = "error"
+)
+
+// FileScan records the malware scan of an uploaded file. Infected files stay in the quarantine
+// bucket at Path; clean ones are promoted to the user folder.
+type FileScan struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    string    `json:"user_id"`
+	FileName  string    `json:"file_name"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Provider  string    `json:"provider"`
+	Result    string    `json:"result"`
+	Signature string    `json:"signature,omitempty"` // Malware detected
+	Detail    string    `json:"detail,omitempty"`    // Scanner error or promotion note
+	ScannedAt time.Time `json:"scanned_at"`
+}
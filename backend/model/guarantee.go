@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuaranteeStudy is the study of a tenant requested to a guarantee company (afianzadora). Once
+// approved, its policy substitutes the deudor solidario of the contract.
+type GuaranteeStudy struct {
+	ID           uuid.UUID  `json:"id"`
+	RenterID     uuid.UUID  `json:"renter_id"`
+	PropertyID   uuid.UUID  `json:"property_id"`
+	Provider     string     `json:"provider"`    // Identifier of the integration that submitted the study
+	Company      string     `json:"company"`     // Name of the afianzadora printed in the contract
+	ExternalID   string     `json:"external_id"` // Study ID on the afianzadora
+	MonthlyRent  float64    `json:"monthly_rent"`
+	Status       string     `json:"status"` // One of the GuaranteeStatus constants
+	PolicyNumber string     `json:"policy_number,omitempty"`
+	Observations string     `json:"observations,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+}
+
+// Status of a guarantee study
+const (
+	GuaranteeStatusPending  = "pending"
+	GuaranteeStatusApproved = "approved"
+	GuaranteeStatusRejected = "rejected"
+)
+
+// GuaranteeStatusTranslations maps guarantee study statuses to Spanish
+var GuaranteeStatusTranslations = map[string]string{
+	GuaranteeStatusPending:  "En estudio",
+	GuaranteeStatusApproved: "Aprobado",
+	GuaranteeStatusRejected: "Negado",
+}
+
+// HasPolicy reports whether the study was approved and the afianzadora issued its policy
+func (s GuaranteeStudy) HasPolicy() bool {
+	return s.Status == GuaranteeStatusApproved && s.PolicyNumber != ""
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Holiday is a Colombian public holiday (festivo)
+type Holiday struct {
+	Date time.Time `json:"date"`
+	Name string    `json:"name"`
+}
+
+// fixedHolidays are celebrated on their own date every year
+var fixedHolidays = []struct {
+	month time.Month
+	day   int
+	name  string
+}{
+	{time.January, 1, "Año Nuevo"},
+	{time.May, 1, "Día del Trabajo"},
+	{time.July, 20, "Día de la Independencia"},
+	{time.August, 7, "Batalla de Boyacá"},
+	{time.December, 8, "Inmaculada Concepción"},
+	{time.December, 25, "Navidad"},
+}
+
+// emilianiHolidays are moved to the next Monday when they fall on another day (Ley 51 de 1983,
+// "Ley Emiliani")
+var emilianiHolidays = []struct {
+	month time.Month
+	day   int
+	name  string
+}{
+	{time.January, 6, "Día de los Reyes Magos"},
+	{time.March, 19, "Día de San José"},
+	{time.June, 29, "San Pedro y San Pablo"},
+	{time.August, 15, "Asunción de la Virgen"},
+	{time.October, 12, "Día de la Raza"},
+	{time.November, 1, "Todos los Santos"},
+	{time.November, 11, "Independencia de Cartagena"},
+}
+
+// easterHolidays are counted in days from Easter Sunday; the ones after Easter already include
+// their move to Monday
+var easterHolidays = []struct {
+	days int
+	name string
+}{
+	{-3, "Jueves Santo"},
+	{-2, "Viernes Santo"},
+	{43, "Ascensión del Señor"},
+	{64, "Corpus Christi"},
+	{71, "Sagrado Corazón de Jesús"},
+}
+
+// easterSunday returns the date of Easter Sunday of a year (anonymous Gregorian algorithm)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// nextMonday returns date when it is a Monday, the following Monday otherwise
+func nextMonday(date time.Time) time.Time {
+	return date.AddDate(0, 0, (8-int(date.Weekday()))%7)
+}
+
+// holidaysByYear caches the holidays of the years already computed: every business day check
+// of a due date reads them, often for the same few years
+var (
+	holidaysMu     sync.RWMutex
+	holidaysByYear = map[int][]Holiday{}
+)
+
+// ColombianHolidays returns the holidays of a year in date order, at midnight UTC
+func ColombianHolidays(year int) []Holiday {
+	holidays := cachedHolidays(year)
+	return append([]Holiday(nil), holidays...)
+}
+
+// cachedHolidays returns the holidays of a year, computing them on the first call. The slice is
+// shared and must not be modified.
+func cachedHolidays(year int) []Holiday {
+	holidaysMu.RLock()
+	holidays, ok := holidaysByYear[year]
+	holidaysMu.RUnlock()
+	if ok {
+		return holidays
+	}
+
+	holidays = computeColombianHolidays(year)
+	holidaysMu.Lock()
+	holidaysByYear[year] = holidays
+	holidaysMu.Unlock()
+	return holidays
+}
+
+// computeColombianHolidays lists the holidays of a year in date order
+func computeColombianHolidays(year int) []Holiday {
+	holidays := make([]Holiday, 0, len(fixedHolidays)+len(emilianiHolidays)+len(easterHolidays))
+	for _, holiday := range fixedHolidays {
+		holidays = append(holidays, Holiday{Date: time.Date(year, holiday.month, holiday.day, 0, 0, 0, 0, time.UTC), Name: holiday.name})
+	}
+	for _, holiday := range emilianiHolidays {
+		holidays = append(holidays, Holiday{Date: nextMonday(time.Date(year, holiday.month, holiday.day, 0, 0, 0, 0, time.UTC)), Name: holiday.name})
+	}
+	easter := easterSunday(year)
+	for _, holiday := range easterHolidays {
+		holidays = append(holidays, Holiday{Date: easter.AddDate(0, 0, holiday.days), Name: holiday.name})
+	}
+
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays
+}
+
+// IsColombianHoliday reports whether the calendar day of date is a holiday
+func IsColombianHoliday(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for _, holiday := range cachedHolidays(date.Year()) {
+		if holiday.Date.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusinessDay reports whether date is neither a weekend day nor a holiday
+func IsBusinessDay(date time.Time) bool {
+	if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return !IsColombianHoliday(date)
+}
+
+// NextBusinessDay returns date when it is a business day, the first business day after it
+// otherwise, in the location of date
+func NextBusinessDay(date time.Time) time.Time {
+	for !IsBusinessDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
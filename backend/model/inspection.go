@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of inspection of a rented property
+const (
+	InspectionKindMoveIn  = "move_in"  // Delivery of the property to the tenant
+	InspectionKindMoveOut = "move_out" // Return of the property at the end of the rental
+)
+
+// InspectionKindLabels are the Spanish labels of the inspection kinds
+var InspectionKindLabels = map[string]string{
+	InspectionKindMoveIn:  "Acta de entrega del inmueble",
+	InspectionKindMoveOut: "Acta de restitución del inmueble",
+}
+
+// Status of an inspection
+const (
+	InspectionStatusDraft                 = "draft"                  // Being filled in, can be edited
+	InspectionStatusPendingAcknowledgment = "pending_acknowledgment" // Sent to the tenant to sign
+	InspectionStatusAcknowledged          = "acknowledged"           // Signed by the tenant
+)
+
+// InspectionStatusTranslations are the Spanish labels of the inspection statuses
+var InspectionStatusTranslations = map[string]string{
+	InspectionStatusDraft:                 "Borrador",
+	InspectionStatusPendingAcknowledgment: "Pendiente de firma",
+	InspectionStatusAcknowledged:          "Firmada por el arrendatario",
+}
+
+// InspectionTemplate is a reusable checklist of the rooms and items reviewed in an inspection.
+// The items of a template carry no condition, it is filled in during each inspection.
+type InspectionTemplate struct {
+	ID          uuid.UUID       `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Rooms       []InventoryRoom `json:"rooms"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Inspection is the move-in or move-out review of a rented property, room by room, with the
+// condition of each item and photos, acknowledged by the tenant through the signing flow
+type Inspection struct {
+	ID             uuid.UUID       `json:"id"`
+	RentalID       uuid.UUID       `json:"rental_id"`
+	Kind           string          `json:"kind"` // One of the InspectionKind constants
+	TemplateID     *uuid.UUID      `json:"template_id,omitempty"`
+	InspectedAt    time.Time       `json:"inspected_at"`
+	InspectorID    uuid.UUID       `json:"inspector_id"` // Person who made the inspection
+	Rooms          []InventoryRoom `json:"rooms"`
+	Notes          string          `json:"notes,omitempty"`
+	Status         string          `json:"status"`               // One of the InspectionStatus constants
+	SigningID      string          `json:"signing_id,omitempty"` // Signing request sent to the tenant
+	SignedPDFPath  string          `json:"signed_pdf_path,omitempty"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// IsValidInspectionKind reports whether kind is a known inspection kind
+func IsValidInspectionKind(kind string) bool {
+	return kind == InspectionKindMoveIn || kind == InspectionKindMoveOut
+}
+
+// ItemConditionRank returns the position of a condition in ItemConditions, higher is worse,
+// and -1 for unknown conditions
+func ItemConditionRank(condition string) int {
+	for i, known := range ItemConditions {
+		if condition == known {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Inventory is the inventory of a property, printed as an annex of its rental contracts.
+// The contract clauses refer to it as the "inventario que las partes firman por separado".
+type Inventory struct {
+	ID         uuid.UUID       `json:"id"`
+	PropertyID uuid.UUID       `json:"property_id"`
+	Rooms      []InventoryRoom `json:"rooms"`
+	Notes      string          `json:"notes,omitempty"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// InventoryRoom is a room (or area) of the property with the items it contains
+type InventoryRoom struct {
+	Name   string           `json:"name"`
+	Items  []InventoryItem  `json:"items"`
+	Photos []InventoryPhoto `json:"photos,omitempty"`
+}
+
+// InventoryItem is an item of a room and the condition it is delivered in
+type InventoryItem struct {
+	Name      string           `json:"name"`
+	Quantity  int              `json:"quantity"`
+	Condition string           `json:"condition"` // One of the ItemCondition constants
+	Notes     string           `json:"notes,omitempty"`
+	Photos    []InventoryPhoto `json:"photos,omitempty"`
+}
+
+// InventoryPhoto is a photo of a room or item stored in Supabase Storage
+type InventoryPhoto struct {
+	Path    string `json:"path"` // Path of the file in the storage bucket
+	URL     string `json:"url,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// Conditions of an inventory item
+const (
+	ItemConditionNuevo   = "nuevo"
+	ItemConditionBueno   = "bueno"
+	ItemConditionRegular = "regular"
+	ItemConditionMalo    = "malo"
+)
+
+// ItemConditions lists the supported item conditions, best first
+var ItemConditions = []string{ItemConditionNuevo, ItemConditionBueno, ItemConditionRegular, ItemConditionMalo}
+
+// IsValidItemCondition reports whether condition is a known item condition
+func IsValidItemCondition(condition string) bool {
+	for _, known := range ItemConditions {
+		if condition == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a person can be invited with
+const (
+	InvitationRoleManager  = "manager"  // Co-manager of the organization or property
+	InvitationRoleResident = "resident" // Tenant of the property
+)
+
+// States of an invitation
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation is an emailed link that lets a person create their account with a role, linked to
+// an organization and a property
+type Invitation struct {
+	ID             uuid.UUID  `json:"id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"` // One of the InvitationRole constants
+	PersonID       uuid.UUID  `json:"person_id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"` // Managers join it
+	PropertyID     *uuid.UUID `json:"property_id,omitempty"`     // Managers manage it, residents live in it
+	Message        string     `json:"message,omitempty"`
+	InvitedBy      uuid.UUID  `json:"invited_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	UserID         *uuid.UUID `json:"user_id,omitempty"` // Account created on acceptance
+}
+
+// Status returns the state of the invitation at now
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
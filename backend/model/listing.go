@@ -0,0 +1,126 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of the listing of a vacant property
+const (
+	ListingStatusAvailable = "available" // Published in the public listings
+	ListingStatusPaused    = "paused"    // Hidden, e.g. while showing it to an applicant
+	ListingStatusRented    = "rented"    // Hidden, the property was rented
+)
+
+// ListingStatusTranslations are the Spanish labels of the listing statuses
+var ListingStatusTranslations = map[string]string{
+	ListingStatusAvailable: "Disponible",
+	ListingStatusPaused:    "Pausado",
+	ListingStatusRented:    "Arrendado",
+}
+
+// PropertyListing publishes a vacant property with its photos and rent terms. The city is
+// copied from the property so the public listings can be filtered by it.
+type PropertyListing struct {
+	ID            uuid.UUID        `json:"id"`
+	PropertyID    uuid.UUID        `json:"property_id"`
+	City          string           `json:"city"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description,omitempty"`
+	MonthlyRent   float64          `json:"monthly_rent"`
+	AdminFee      float64          `json:"admin_fee"` // Monthly administration fee of the building, 0 if included
+	DepositAmount float64          `json:"deposit_amount"`
+	MinTermMonths int              `json:"min_term_months"`
+	AvailableFrom *time.Time       `json:"available_from,omitempty"`
+	Bedrooms      int              `json:"bedrooms"`
+	Bathrooms     int              `json:"bathrooms"`
+	AreaM2        float64          `json:"area_m2"`
+	PetsAllowed   bool             `json:"pets_allowed"`
+	Photos        []InventoryPhoto `json:"photos"`
+	Status        string           `json:"status"` // One of the ListingStatus constants
+	PublishedAt   *time.Time       `json:"published_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// PublicListing is the read-only view of an available listing, without the address of the
+// property, which is shared with the applicants by the manager
+type PublicListing struct {
+	ID            uuid.UUID        `json:"id"`
+	City          string           `json:"city"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description,omitempty"`
+	MonthlyRent   float64          `json:"monthly_rent"`
+	AdminFee      float64          `json:"admin_fee"`
+	DepositAmount float64          `json:"deposit_amount"`
+	MinTermMonths int              `json:"min_term_months"`
+	AvailableFrom *time.Time       `json:"available_from,omitempty"`
+	Bedrooms      int              `json:"bedrooms"`
+	Bathrooms     int              `json:"bathrooms"`
+	AreaM2        float64          `json:"area_m2"`
+	PetsAllowed   bool             `json:"pets_allowed"`
+	Photos        []InventoryPhoto `json:"photos"`
+	PublishedAt   *time.Time       `json:"published_at,omitempty"`
+}
+
+// Public returns the public view of the listing
+func (l PropertyListing) Public() PublicListing {
+	photos := make([]InventoryPhoto, 0, len(l.Photos))
+	for _, photo := range l.Photos {
+		photos = append(photos, InventoryPhoto{URL: photo.URL, Caption: photo.Caption})
+	}
+
+	return PublicListing{
+		ID:            l.ID,
+		City:          l.City,
+		Title:         l.Title,
+		Description:   l.Description,
+		MonthlyRent:   l.MonthlyRent,
+		AdminFee:      l.AdminFee,
+		DepositAmount: l.DepositAmount,
+		MinTermMonths: l.MinTermMonths,
+		AvailableFrom: l.AvailableFrom,
+		Bedrooms:      l.Bedrooms,
+		Bathrooms:     l.Bathrooms,
+		AreaM2:        l.AreaM2,
+		PetsAllowed:   l.PetsAllowed,
+		Photos:        photos,
+		PublishedAt:   l.PublishedAt,
+	}
+}
+
+// Status of an application to a listing
+const (
+	ApplicationStatusNew       = "new"
+	ApplicationStatusContacted = "contacted" // The manager contacted the prospect
+	ApplicationStatusApproved  = "approved"  // Selected to rent the property
+	ApplicationStatusRejected  = "rejected"
+)
+
+// ApplicationStatusTranslations are the Spanish labels of the application statuses
+var ApplicationStatusTranslations = map[string]string{
+	ApplicationStatusNew:       "Nueva",
+	ApplicationStatusContacted: "Contactado",
+	ApplicationStatusApproved:  "Aprobada",
+	ApplicationStatusRejected:  "Rechazada",
+}
+
+// ListingApplication is the application of a prospect interested in renting a listed property
+type ListingApplication struct {
+	ID             uuid.UUID  `json:"id"`
+	ListingID      uuid.UUID  `json:"listing_id"`
+	FullName       string     `json:"full_name"`
+	Email          string     `json:"email"`
+	Phone          string     `json:"phone"`
+	DocumentNumber string     `json:"document_number,omitempty"`
+	Occupation     string     `json:"occupation,omitempty"`
+	MonthlyIncome  float64    `json:"monthly_income"`
+	Occupants      int        `json:"occupants"`
+	DesiredMoveIn  *time.Time `json:"desired_move_in,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Status         string     `json:"status"`                  // One of the ApplicationStatus constants
+	ManagerNotes   string     `json:"manager_notes,omitempty"` // Internal, never shown to the prospect
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a login attempt failed
+const (
+	LoginFailureUnknownEmail  = "unknown_email"
+	LoginFailureWrongPassword = "wrong_password"
+	LoginFailureInactive      = "inactive" // Pending or disabled account
+	LoginFailureLocked        = "locked"   // Rejected during a lockout, the password was not checked
+)
+
+// LoginCredentialFailures are the failures that count towards a lockout: guessed emails and
+// passwords. The attempts rejected while locked do not extend the lockout.
+var LoginCredentialFailures = []string{LoginFailureUnknownEmail, LoginFailureWrongPassword}
+
+// LoginAttempt is a login with an email from an IP address, successful or not
+type LoginAttempt struct {
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"` // Lowercase, as typed when the account does not exist
+	IPAddress     string    `json:"ip_address"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"` // One of the LoginFailure constants
+	UserAgent     string    `json:"user_agent,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -50,6 +50,7 @@ type ManagerRegistrationResponse struct {
 	PersonID   uuid.UUID `json:"person_id"`
 	PropertyID uuid.UUID `json:"property_id"`
 	Message    string    `json:"message"`
+	PaymentURL string    `json:"payment_url,omitempty"` // Page to pay the first invoice of the platform plan
 }
 
 // ManagerRegistrationTemplate represents the data sent in response to a GET request to pre-fill the form
@@ -12,6 +12,7 @@ type Person struct {
 	FullName string    `json:"full_name"`
 	Phone    string    `json:"phone"`
 	NIT      string    `json:"nit"`
+	Version  int       `json:"version,omitempty"` // Incremented on every update, see storage.ErrVersionConflict
 }
 
 // Role represents a role in the system
@@ -29,15 +30,66 @@ type PersonRole struct {
 
 // Property represents a property in the system
 type Property struct {
-	ID         uuid.UUID   `json:"id"`
-	Address    string      `json:"address"`
-	AptNumber  string      `json:"apt_number"`
-	City       string      `json:"city"`
-	State      string      `json:"state"`
-	ZipCode    string      `json:"zip_code"`
-	Type       string      `json:"type"`
-	ResidentID uuid.UUID   `json:"resident_id"`
-	ManagerIDs []uuid.UUID `json:"manager_ids,omitempty"`
+	ID         uuid.UUID       `json:"id"`
+	Address    string          `json:"address"`
+	AptNumber  string          `json:"apt_number"`
+	City       string          `json:"city"`
+	State      string          `json:"state"`
+	ZipCode    string          `json:"zip_code"`
+	Type       string          `json:"type"`
+	ResidentID uuid.UUID       `json:"resident_id"`
+	ManagerIDs []uuid.UUID     `json:"manager_ids,omitempty"`
+	BuildingID *uuid.UUID      `json:"building_id,omitempty"` // Copropiedad the property belongs to, if any
+	Photos     []PropertyPhoto `json:"photos,omitempty"`      // Gallery of the property, in order
+
+	// Characteristics, printed in the contracts and used to filter the properties
+	Bedrooms       int     `json:"bedrooms"`
+	Bathrooms      int     `json:"bathrooms"`
+	AreaM2         float64 `json:"area_m2"`
+	Estrato        int     `json:"estrato"` // Socioeconomic stratum from 1 to 6, 0 when unknown
+	ParkingSpots   int     `json:"parking_spots"`
+	ParkingDetails string  `json:"parking_details,omitempty"` // Garaje number, whether it is covered, etc.
+	Furnished      bool    `json:"furnished"`
+	PetsAllowed    bool    `json:"pets_allowed"`
+	Boundaries     string  `json:"boundaries,omitempty"` // Linderos of the property
+
+	Version int `json:"version,omitempty"` // See Person.Version
+}
+
+// PropertyIDs returns the IDs of the properties, used to fetch their related records in batch
+func PropertyIDs(properties []Property) []uuid.UUID {
+	ids := make([]uuid.UUID, len(properties))
+	for i, property := range properties {
+		ids[i] = property.ID
+	}
+	return ids
+}
+
+// Organization groups the properties of one or more managers under its own
+// public base URL or custom domain (used for signing, upload and other public links)
+type Organization struct {
+	ID           uuid.UUID             `json:"id"`
+	Name         string                `json:"name"`
+	ManagerIDs   []uuid.UUID           `json:"manager_ids"`
+	BaseURL      string                `json:"base_url,omitempty"`      // e.g. https://arriendos.example.com
+	CustomDomain string                `json:"custom_domain,omitempty"` // Host serving the frontend, e.g. arriendos.example.com
+	Branding     *OrganizationBranding `json:"branding,omitempty"`
+	Timezone     string                `json:"timezone,omitempty"` // IANA name, e.g. America/Bogota; APP_TIMEZONE when empty
+	Locale       string                `json:"locale,omitempty"`   // e.g. es-CO or en-US; APP_LOCALE when empty
+}
+
+// OrganizationBranding is the company data and look printed on the PDFs and emails of an
+// organization, instead of the generic data of the platform
+type OrganizationBranding struct {
+	CompanyName    string `json:"company_name,omitempty"` // The organization name when empty
+	NIT            string `json:"nit,omitempty"`
+	Address        string `json:"address,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	Email          string `json:"email,omitempty"`
+	LogoURL        string `json:"logo_url,omitempty"`        // PNG or JPEG
+	PrimaryColor   string `json:"primary_color,omitempty"`   // Hex, e.g. #1d4ed8
+	SecondaryColor string `json:"secondary_color,omitempty"` // Hex
+	FooterText     string `json:"footer_text,omitempty"`
 }
 
 // BankAccount represents a bank account in the system
@@ -60,6 +112,7 @@ type Rental struct {
 	EndDate       FlexibleTime `json:"end_date"`
 	PaymentTerms  string       `json:"payment_terms"`
 	UnpaidMonths  int          `json:"unpaid_months"`
+	Version       int          `json:"version,omitempty"` // See Person.Version
 }
 
 // Pricing represents pricing information for a rental
@@ -72,6 +125,63 @@ type Pricing struct {
 	TenantResponsibleFor []string  `json:"tenant_responsible_for"`
 	LateFee              float64   `json:"late_fee"`
 	DueDay               int       `json:"due_day"`
+	IncreasePolicy       string    `json:"increase_policy,omitempty"`     // ipc (default), fixed or manual
+	IncreaseSpread       float64   `json:"increase_spread,omitempty"`     // Points added to the IPC (ipc policy)
+	IncreasePercentage   float64   `json:"increase_percentage,omitempty"` // Yearly increase (fixed policy)
+	// MonthlyRent is the canon; administración, parking and other monthly charges are itemized in
+	// Components
+	CanonTaxable bool               `json:"canon_taxable,omitempty"` // Canon subject to IVA (commercial premises)
+	Components   []PricingComponent `json:"components,omitempty"`
+	Currency     string             `json:"currency,omitempty"` // Currency of every amount of the pricing, COP if empty
+	Version      int                `json:"version,omitempty"`  // See Person.Version
+}
+
+// Money returns an amount of the pricing in its currency
+func (p Pricing) Money(amount float64) Money {
+	return Money{Amount: amount, Currency: p.CurrencyCode()}
+}
+
+// CurrencyCode returns the currency of the pricing, DefaultCurrency when it has none
+func (p Pricing) CurrencyCode() string {
+	return Money{Currency: p.Currency}.CurrencyCode()
+}
+
+// Rent increase policies for Pricing.IncreasePolicy
+const (
+	IncreasePolicyIPC    = "ipc"    // IPC of the previous year plus IncreaseSpread points
+	IncreasePolicyFixed  = "fixed"  // IncreasePercentage every renewal
+	IncreasePolicyManual = "manual" // New canon agreed by the parties on each renewal
+)
+
+// IsValidIncreasePolicy reports whether policy is a known increase policy (empty means ipc)
+func IsValidIncreasePolicy(policy string) bool {
+	switch policy {
+	case "", IncreasePolicyIPC, IncreasePolicyFixed, IncreasePolicyManual:
+		return true
+	}
+	return false
+}
+
+// RentIncreaseCalculation records how a renewed canon was computed (Art. 20 Ley 820 de 2003)
+type RentIncreaseCalculation struct {
+	Policy              string    `json:"policy"`
+	IPCPercentage       float64   `json:"ipc_percentage"`
+	Spread              float64   `json:"spread,omitempty"`
+	RequestedPercentage float64   `json:"requested_percentage"`
+	AppliedPercentage   float64   `json:"applied_percentage"`
+	CappedByLaw         bool      `json:"capped_by_law"`
+	PreviousRent        float64   `json:"previous_rent"`
+	NewRent             float64   `json:"new_rent"`
+	CalculatedAt        time.Time `json:"calculated_at"`
+}
+
+// DepositTransfer records how the deposit of a rental was carried over to the rental of
+// the property the tenant moved to
+type DepositTransfer struct {
+	PreviousDeposit float64 `json:"previous_deposit"`
+	Adjustment      float64 `json:"adjustment"` // Positive when paid by the tenant, negative when refunded
+	NewDeposit      float64 `json:"new_deposit"`
+	Reason          string  `json:"reason,omitempty"`
 }
 
 // PaymentSchedule represents a payment schedule for a rental
@@ -112,6 +222,15 @@ type RentalHistory struct {
 	PersonID uuid.UUID `json:"person_id"`
 }
 
+// Statuses of the rental history records. Every rental gets an active record when it is created
+// and a record closing it when it is renewed, transferred or terminated before its end date.
+const (
+	RentalHistoryActive      = "active"
+	RentalHistoryRenewed     = "renewed"
+	RentalHistoryTransferred = "transferred"
+	RentalHistoryTerminated  = "terminated"
+)
+
 // MaintenanceRequest represents a maintenance request for a property
 type MaintenanceRequest struct {
 	ID          uuid.UUID    `json:"id,omitempty"`
@@ -124,6 +243,18 @@ type MaintenanceRequest struct {
 	UpdatedAt   FlexibleTime `json:"updated_at,omitempty"`
 }
 
+// ServiceProvider represents an external provider (plumber, electrician, etc.)
+// that can be assigned to maintenance requests
+type ServiceProvider struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Specialty   string    `json:"specialty"` // e.g., plumber, electrician, locksmith
+	ContactName string    `json:"contact_name"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	Notes       string    `json:"notes"`
+}
+
 // AuditLog represents an audit log entry
 type AuditLog struct {
 	ID        uuid.UUID   `json:"id"`
@@ -133,14 +264,22 @@ type AuditLog struct {
 	ChangedBy uuid.UUID   `json:"changed_by"`
 	Timestamp time.Time   `json:"timestamp"`
 	Details   interface{} `json:"details"`
+	// ImpersonatedBy is the admin who acted as ChangedBy, when the action was impersonated
+	ImpersonatedBy *uuid.UUID `json:"impersonated_by,omitempty"`
 }
 
 // User represents a user in the system
 type User struct {
-	ID             uuid.UUID `json:"id"`
-	Email          string    `json:"email"`
-	PasswordBase64 string    `json:"password_base64"`
-	Role           string    `json:"role"`
-	PersonID       uuid.UUID `json:"person_id"`
-	Status         string    `json:"status"` // values: 'pending', 'active', 'disabled'
+	ID             uuid.UUID  `json:"id"`
+	Email          string     `json:"email"`
+	PasswordBase64 string     `json:"password_base64"`
+	Role           string     `json:"role"`
+	PersonID       uuid.UUID  `json:"person_id"`
+	Status         string     `json:"status"` // values: 'pending', 'active', 'disabled'
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	// EmailPendingVerification is set for self-registered users until they open the emailed link
+	EmailPendingVerification bool `json:"email_pending_verification"`
+	// Locale is the language the user receives emails and API errors in (es-CO or en-US), ""
+	// to follow the Accept-Language header and the organization
+	Locale string `json:"locale"`
 }
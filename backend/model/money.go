@@ -0,0 +1,47 @@
+package model
+
+// Currencies of the prices. Rentals are priced in Colombian pesos unless the owner charges
+// in US dollars (international owners).
+const (
+	CurrencyCOP = "COP"
+	CurrencyUSD = "USD"
+)
+
+// DefaultCurrency is the currency of the prices that do not set one
+const DefaultCurrency = CurrencyCOP
+
+// Money is an amount in a currency (an ISO 4217 code, DefaultCurrency when empty)
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// COP returns an amount in Colombian pesos
+func COP(amount float64) Money {
+	return Money{Amount: amount, Currency: CurrencyCOP}
+}
+
+// CurrencyCode returns the currency of the amount, DefaultCurrency when it has none
+func (m Money) CurrencyCode() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// IsValidCurrency reports whether currency is a supported currency (empty means DefaultCurrency)
+func IsValidCurrency(currency string) bool {
+	switch currency {
+	case "", CurrencyCOP, CurrencyUSD:
+		return true
+	}
+	return false
+}
+
+// CurrencyDecimals returns the decimals amounts are shown with: pesos are billed without cents
+func CurrencyDecimals(currency string) int {
+	if currency == CurrencyUSD {
+		return 2
+	}
+	return 0
+}
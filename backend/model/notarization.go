@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notarization is the notarial authentication (e-stamping) of a signed contract requested to a
+// digital notary service
+type Notarization struct {
+	ID             uuid.UUID  `json:"id"`
+	SigningID      string     `json:"signing_id"`
+	ContractID     string     `json:"contract_id"`
+	Provider       string     `json:"provider"`    // Identifier of the integration that submitted the document
+	Notary         string     `json:"notary"`      // Name of the notary printed in the dossier
+	ExternalID     string     `json:"external_id"` // Document ID on the notary service
+	Status         string     `json:"status"`      // One of the NotarizationStatus constants
+	Reference      string     `json:"reference,omitempty"`
+	Observations   string     `json:"observations,omitempty"`
+	StampedPDFPath string     `json:"stamped_pdf_path,omitempty"`
+	RequestedAt    time.Time  `json:"requested_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// Status of a notarization
+const (
+	NotarizationStatusPending  = "pending"
+	NotarizationStatusStamped  = "stamped"
+	NotarizationStatusRejected = "rejected"
+)
+
+// NotarizationStatusTranslations maps notarization statuses to Spanish
+var NotarizationStatusTranslations = map[string]string{
+	NotarizationStatusPending:  "En trámite",
+	NotarizationStatusStamped:  "Autenticado",
+	NotarizationStatusRejected: "Rechazado",
+}
+
+// IsFinal reports whether the notary already answered the request
+func (n Notarization) IsFinal() bool {
+	return n.Status == NotarizationStatusStamped || n.Status == NotarizationStatusRejected
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events that create an in-app notification
+const (
+	NotificationPaymentReceived    = "payment_received"    // A rent payment was registered
+	NotificationSignatureCompleted = "signature_completed" // A signing request was signed
+	NotificationMaintenanceUpdated = "maintenance_updated" // A maintenance request changed its status
+)
+
+// Notification is an entry of the in-app notification feed of a user, shown next to the emails
+// sent for the same event
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Type      string     `json:"type"` // One of the Notification event constants
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	EntityID  string     `json:"entity_id,omitempty"` // Payment, signing or maintenance request the event is about
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channels notifications are delivered through
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+)
+
+// Types of notifications sent to tenants. They match the Kind of the reminders queued in the
+// outbox.
+const (
+	NotificationTypeRentReminder        = "rent_reminder"
+	NotificationTypeAnniversaryReminder = "anniversary_reminder"
+	NotificationTypeRenewalReminder     = "renewal_reminder"
+)
+
+// Notification frequencies
+const (
+	NotificationFrequencyAlways  = "always"  // Every time the notification is generated
+	NotificationFrequencyMonthly = "monthly" // At most once per calendar month
+	NotificationFrequencyNever   = "never"   // Unsubscribed
+)
+
+// NotificationChannels lists the supported channels
+var NotificationChannels = []string{NotificationChannelEmail, NotificationChannelSMS}
+
+// NotificationTypes lists the notification types a person can configure
+var NotificationTypes = []string{NotificationTypeRentReminder, NotificationTypeAnniversaryReminder, NotificationTypeRenewalReminder}
+
+// NotificationFrequencies lists the supported frequencies
+var NotificationFrequencies = []string{NotificationFrequencyAlways, NotificationFrequencyMonthly, NotificationFrequencyNever}
+
+// NotificationPreference holds how often a person wants to receive a type of notification
+// through a channel. Without a stored preference emails are always sent and SMS never.
+type NotificationPreference struct {
+	ID         uuid.UUID  `json:"id"`
+	PersonID   uuid.UUID  `json:"person_id"`
+	Channel    string     `json:"channel"`   // email, sms
+	Type       string     `json:"type"`      // rent_reminder, anniversary_reminder, renewal_reminder
+	Frequency  string     `json:"frequency"` // always, monthly, never
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// DefaultNotificationFrequency returns the frequency of a channel for people without a stored preference
+func DefaultNotificationFrequency(channel string) string {
+	if channel == NotificationChannelEmail {
+		return NotificationFrequencyAlways
+	}
+	return NotificationFrequencyNever
+}
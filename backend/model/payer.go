@@ -7,6 +7,7 @@ type Payer struct {
 	Phone             string    `json:"phone"`               // Tenant's phone number
 	RentalEmail       string    `json:"rental_email"`        // Tenant's email for notifications
 	RentalDate        time.Time `json:"rental_date"`         // Date of rent start
+	DueDate           time.Time `json:"due_date"`            // Date the rent is due, a business day
 	RenterName        string    `json:"renter_name"`         // Name of the property owner/landlord
 	RenterEmail       string    `json:"renter_email"`        // Email of the landlord
 	NIT               string    `json:"nit"`                 // Tenant's tax ID or national ID
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentCheckout is an online payment of the monthly rent started by a tenant on a payment
+// gateway. A rent payment is registered when the gateway reports it approved.
+type PaymentCheckout struct {
+	ID            uuid.UUID  `json:"id"`
+	Reference     string     `json:"reference"` // Sent to the gateway, identifies the checkout in its events
+	RentalID      uuid.UUID  `json:"rental_id"`
+	PersonID      uuid.UUID  `json:"person_id"` // Tenant who started the payment
+	Period        string     `json:"period"`    // Billing month paid, YYYY-MM
+	AmountInCents int64      `json:"amount_in_cents"`
+	Currency      string     `json:"currency"`
+	Provider      string     `json:"provider"`
+	Status        string     `json:"status"`                   // One of the PaymentCheckoutStatus constants
+	TransactionID string     `json:"transaction_id,omitempty"` // Transaction of the gateway
+	PaymentMethod string     `json:"payment_method,omitempty"` // PSE, CARD, NEQUI...
+	RentPaymentID *string    `json:"rent_payment_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// Status of a payment checkout
+const (
+	PaymentCheckoutStatusPending  = "pending"
+	PaymentCheckoutStatusApproved = "approved"
+	PaymentCheckoutStatusDeclined = "declined"
+	PaymentCheckoutStatusVoided   = "voided"
+	PaymentCheckoutStatusError    = "error"
+)
+
+// Amount returns the amount of the checkout in pesos
+func (c PaymentCheckout) Amount() float64 {
+	return float64(c.AmountInCents) / 100
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentStatement records a yearly payment certification issued to a tenant, so the QR code
+// printed on the PDF can confirm that the document was issued by the platform and with which totals
+type PaymentStatement struct {
+	ID             uuid.UUID  `json:"id"`
+	PersonID       uuid.UUID  `json:"person_id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Year           int        `json:"year"`
+	PaymentCount   int        `json:"payment_count"`
+	TotalPaid      float64    `json:"total_paid"`
+	DocumentHash   string     `json:"document_hash"` // SHA-256 of the signed PDF
+	IssuedAt       time.Time  `json:"issued_at"`
+}
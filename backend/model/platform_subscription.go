@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlatformSubscription is the monthly plan a manager pays to use the platform. Its status
+// drives the status of the user: pending until the first invoice is paid, active while it is
+// paid and disabled when it stays unpaid.
+type PlatformSubscription struct {
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	Provider         string     `json:"provider"`
+	CustomerID       string     `json:"customer_id"`     // Customer on the billing provider
+	SubscriptionID   string     `json:"subscription_id"` // Subscription on the billing provider
+	PriceID          string     `json:"price_id"`        // Plan billed
+	Status           string     `json:"status"`          // One of the SubscriptionStatus constants
+	CurrentPeriodEnd *time.Time `json:"current_period_end,omitempty"`
+	PaymentURL       string     `json:"payment_url,omitempty"`    // Page to pay the open invoice, empty when nothing is owed
+	PastDueSince     *time.Time `json:"past_due_since,omitempty"` // First failed invoice not paid yet
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`   // When the user was disabled for non-payment
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Status of a platform subscription, as reported by the billing provider
+const (
+	SubscriptionStatusIncomplete        = "incomplete" // Waiting for the first payment
+	SubscriptionStatusIncompleteExpired = "incomplete_expired"
+	SubscriptionStatusActive            = "active"
+	SubscriptionStatusPastDue           = "past_due"
+	SubscriptionStatusUnpaid            = "unpaid"
+	SubscriptionStatusCanceled          = "canceled"
+)
+
+// SubscriptionEnded reports whether a subscription status no longer grants access to the platform
+func SubscriptionEnded(status string) bool {
+	switch status {
+	case SubscriptionStatusIncompleteExpired, SubscriptionStatusUnpaid, SubscriptionStatusCanceled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package model
+
+import "fmt"
+
+// Types of the monthly charges of a pricing
+const (
+	PricingComponentCanon          = "canon"
+	PricingComponentAdministracion = "administracion"
+	PricingComponentParqueadero    = "parqueadero"
+	PricingComponentOtro           = "otro"
+)
+
+// PricingComponentLabels holds the Spanish names of the charges printed in invoices and contracts
+var PricingComponentLabels = map[string]string{
+	PricingComponentCanon:          "Canon de arrendamiento",
+	PricingComponentAdministracion: "Cuota de administración",
+	PricingComponentParqueadero:    "Parqueadero",
+	PricingComponentOtro:           "Otro cargo",
+}
+
+// Who pays a charge of a pricing
+const (
+	ResponsibilityTenant = "tenant"
+	ResponsibilityOwner  = "owner"
+)
+
+// PricingComponent is a monthly charge billed besides the canon, e.g. the administración of the
+// building or a parking space
+type PricingComponent struct {
+	Type           string  `json:"type"` // administracion, parqueadero or otro
+	Description    string  `json:"description,omitempty"`
+	Amount         float64 `json:"amount"`
+	Responsibility string  `json:"responsibility,omitempty"` // tenant (default) or owner
+	Taxable        bool    `json:"taxable,omitempty"`        // Subject to IVA
+}
+
+// Label returns the name of the charge, its description for other charges
+func (c PricingComponent) Label() string {
+	if c.Type == PricingComponentOtro && c.Description != "" {
+		return c.Description
+	}
+	return PricingComponentLabels[c.Type]
+}
+
+// PaidByOwner reports whether the owner bears the charge instead of the tenant
+func (c PricingComponent) PaidByOwner() bool {
+	return c.Responsibility == ResponsibilityOwner
+}
+
+// ValidatePricingComponents checks the charges of a pricing. The canon is MonthlyRent, so it
+// cannot be listed as a component.
+func ValidatePricingComponents(components []PricingComponent) error {
+	for i, component := range components {
+		switch component.Type {
+		case PricingComponentAdministracion, PricingComponentParqueadero, PricingComponentOtro:
+		case PricingComponentCanon:
+			return fmt.Errorf("component %d: the canon is set with monthly_rent", i+1)
+		default:
+			return fmt.Errorf("component %d: type must be administracion, parqueadero or otro", i+1)
+		}
+		if component.Amount <= 0 {
+			return fmt.Errorf("component %d: amount must be positive", i+1)
+		}
+		if component.Responsibility != "" && component.Responsibility != ResponsibilityTenant && component.Responsibility != ResponsibilityOwner {
+			return fmt.Errorf("component %d: responsibility must be tenant or owner", i+1)
+		}
+		if component.Type == PricingComponentOtro && component.Description == "" {
+			return fmt.Errorf("component %d: other charges need a description", i+1)
+		}
+	}
+	return nil
+}
+
+// Items returns every monthly charge of the pricing, starting with the canon
+func (p Pricing) Items() []PricingComponent {
+	items := make([]PricingComponent, 0, len(p.Components)+1)
+	items = append(items, PricingComponent{
+		Type:           PricingComponentCanon,
+		Amount:         p.MonthlyRent,
+		Responsibility: ResponsibilityTenant,
+		Taxable:        p.CanonTaxable,
+	})
+	return append(items, p.Components...)
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Promotion is a promotional price attached to the listing of a property, applied to the
+// contracts generated while it is valid
+type Promotion struct {
+	ID                 uuid.UUID `json:"id"`
+	PropertyID         uuid.UUID `json:"property_id"`
+	Name               string    `json:"name"`
+	Type               string    `json:"type"`                          // One of the PromotionType constants
+	DiscountPercentage float64   `json:"discount_percentage,omitempty"` // Discount on the first month (first_month_discount)
+	ValidFrom          time.Time `json:"valid_from"`
+	ValidUntil         time.Time `json:"valid_until"`
+	Active             bool      `json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Types of promotion
+const (
+	PromotionTypeFirstMonthDiscount = "first_month_discount"
+	PromotionTypeNoDeposit          = "no_deposit"
+)
+
+// PromotionTypes lists the supported promotion types
+var PromotionTypes = []string{PromotionTypeFirstMonthDiscount, PromotionTypeNoDeposit}
+
+// IsValidPromotionType reports whether promotionType is a known promotion type
+func IsValidPromotionType(promotionType string) bool {
+	for _, known := range PromotionTypes {
+		if promotionType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAt reports whether the promotion is active and within its validity window at t
+func (p Promotion) IsValidAt(t time.Time) bool {
+	return p.Active && !t.Before(p.ValidFrom) && !t.After(p.ValidUntil)
+}
+
+// PromotionRedemption records a contract offered with a promotion. The promotion converted
+// when the contract is signed.
+type PromotionRedemption struct {
+	ID          uuid.UUID  `json:"id"`
+	PromotionID uuid.UUID  `json:"promotion_id"`
+	PropertyID  uuid.UUID  `json:"property_id"`
+	RenterID    uuid.UUID  `json:"renter_id"`
+	ContractID  string     `json:"contract_id"`
+	OfferedAt   time.Time  `json:"offered_at"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PropertyPhoto is a photo of the gallery of a property, stored under property_<id>/ in the
+// storage bucket. The URL is signed when the property is read and never stored.
+type PropertyPhoto struct {
+	ID         uuid.UUID `json:"id"`
+	PropertyID uuid.UUID `json:"property_id"`
+	Path       string    `json:"path"`
+	URL        string    `json:"url,omitempty"`
+	Caption    string    `json:"caption"`
+	Position   int       `json:"position"` // Order of the photo in the gallery, starting at 0
+	CreatedAt  time.Time `json:"created_at"`
+}
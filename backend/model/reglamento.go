@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reglamento is a version of the manual de convivencia of a building. A building groups the
+// properties (apartments) sharing address and city, identified by their BuildingKey.
+type Reglamento struct {
+	ID          uuid.UUID `json:"id"`
+	BuildingKey string    `json:"building_key"`
+	Address     string    `json:"address"`
+	City        string    `json:"city"`
+	Version     int       `json:"version"`
+	FileName    string    `json:"file_name"`
+	FilePath    string    `json:"file_path"`
+	UploadedBy  uuid.UUID `json:"uploaded_by"` // Person ID of the admin or manager
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// ReglamentoAcknowledgment records that a tenant read a version of the reglamento of a building
+type ReglamentoAcknowledgment struct {
+	ID             uuid.UUID `json:"id"`
+	ReglamentoID   uuid.UUID `json:"reglamento_id"`
+	BuildingKey    string    `json:"building_key"`
+	Version        int       `json:"version"`
+	PersonID       uuid.UUID `json:"person_id"`
+	PropertyID     uuid.UUID `json:"property_id"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+	IPAddress      string    `json:"ip_address"`
+	UserAgent      string    `json:"user_agent"`
+}
+
+// BuildingKey identifies the building of a property by its normalized address and city, so the
+// apartments of a building share it regardless of case and spacing
+func BuildingKey(address, city string) string {
+	normalize := func(value string) string {
+		return strings.ToLower(strings.Join(strings.Fields(value), " "))
+	}
+	return normalize(address) + "|" + normalize(city)
+}
+
+// BuildingKey returns the key of the building the property belongs to
+func (p Property) BuildingKey() string {
+	return BuildingKey(p.Address, p.City)
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderPreference holds when a person wants to receive reminder emails
+type ReminderPreference struct {
+	ID               uuid.UUID `json:"id"`
+	PersonID         uuid.UUID `json:"person_id"`
+	SendHour         *int      `json:"send_hour"`          // Local hour (0-23) to deliver reminders, nil to use the default hour
+	OptimizeSendTime bool      `json:"optimize_send_time"` // Deliver at the hour the person usually opens email, when there is enough history
+}
+
+// Outbox email statuses
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxEmail is an email queued for delivery at SendAt by the outbox scheduler
+type OutboxEmail struct {
+	ID              uuid.UUID  `json:"id"`
+	PersonID        *uuid.UUID `json:"person_id,omitempty"` // Recipient person, used to learn the hours they open email
+	ToEmail         string     `json:"to_email"`
+	Subject         string     `json:"subject"`
+	HTMLBody        string     `json:"html_body"`
+	Kind            string     `json:"kind"` // e.g. rent_reminder, anniversary_reminder
+	SendAt          time.Time  `json:"send_at"`
+	Status          string     `json:"status"`
+	Attempts        int        `json:"attempts"`
+	LastError       string     `json:"last_error,omitempty"`
+	SentAt          *time.Time `json:"sent_at,omitempty"`
+	OpenedAt        *time.Time `json:"opened_at,omitempty"`        // First time the tracking pixel was loaded
+	AttachmentPaths []string   `json:"attachment_paths,omitempty"` // Files of the file storage attached when the email is sent
+	CreatedAt       time.Time  `json:"created_at"`
+}
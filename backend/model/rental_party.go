@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of the parties of a rental contract
+const (
+	RentalPartyRenter   = "renter"    // Arrendatario, in addition to the renter of the rental
+	RentalPartyCoSigner = "co_signer" // Codeudor or deudor solidario
+	RentalPartyWitness  = "witness"   // Testigo
+	RentalPartyOwner    = "owner"     // Arrendador
+)
+
+// RentalPartyRoles lists the supported party roles
+var RentalPartyRoles = []string{RentalPartyRenter, RentalPartyCoSigner, RentalPartyWitness, RentalPartyOwner}
+
+// RentalPartyRoleLabels are the Spanish labels of the party roles, as printed in the contracts
+var RentalPartyRoleLabels = map[string]string{
+	RentalPartyRenter:   "Arrendatario",
+	RentalPartyCoSigner: "Deudor solidario",
+	RentalPartyWitness:  "Testigo",
+	RentalPartyOwner:    "Arrendador",
+}
+
+// RentalParty links a person to a rental with the role they have in its contract
+type RentalParty struct {
+	ID        uuid.UUID `json:"id"`
+	RentalID  uuid.UUID `json:"rental_id"`
+	PersonID  uuid.UUID `json:"person_id"`
+	Role      string    `json:"role"` // One of the RentalParty role constants
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsValidRentalPartyRole reports whether role is a known party role
+func IsValidRentalPartyRole(role string) bool {
+	_, ok := RentalPartyRoleLabels[role]
+	return ok
+}
+
+// FirstRentalParty returns the person of the first party with the role, uuid.Nil when there is none
+func FirstRentalParty(parties []RentalParty, role string) uuid.UUID {
+	for _, party := range parties {
+		if party.Role == role {
+			return party.PersonID
+		}
+	}
+	return uuid.Nil
+}
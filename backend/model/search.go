@@ -0,0 +1,37 @@
+package model
+
+import "github.com/google/uuid"
+
+// Types of the full-text search results
+const (
+	SearchResultPerson   = "person"
+	SearchResultProperty = "property"
+	SearchResultRental   = "rental"
+	SearchResultFile     = "file"
+)
+
+// SearchResultTypes lists the supported search result types
+var SearchResultTypes = []string{SearchResultPerson, SearchResultProperty, SearchResultRental, SearchResultFile}
+
+// SearchResult is a person, property, rental or file matching a full-text search. The property,
+// rental and person IDs relate the result to the records used to check who can see it.
+type SearchResult struct {
+	Type       string     `json:"type"` // One of the SearchResult type constants
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Subtitle   string     `json:"subtitle,omitempty"`
+	PropertyID *uuid.UUID `json:"property_id,omitempty"`
+	RentalID   *uuid.UUID `json:"rental_id,omitempty"`
+	PersonID   *uuid.UUID `json:"person_id,omitempty"`
+	Rank       float64    `json:"rank"`
+}
+
+// IsValidSearchResultType reports whether t is a known search result type
+func IsValidSearchResultType(t string) bool {
+	for _, known := range SearchResultTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Who holds the security deposit of a rental while the contract is in force
+const (
+	DepositHolderOwner   = "owner"   // The owner of the property
+	DepositHolderManager = "manager" // The manager or real-estate agency
+	DepositHolderEscrow  = "escrow"  // A third party, e.g. a fiduciary account
+)
+
+// DepositHolders lists the supported deposit holders
+var DepositHolders = []string{DepositHolderOwner, DepositHolderManager, DepositHolderEscrow}
+
+// Status of a security deposit
+const (
+	DepositStatusHeld             = "held"              // Received, deductions may be recorded
+	DepositStatusSettlementIssued = "settlement_issued" // Move-out settlement sent to the tenant to sign
+	DepositStatusSigned           = "signed"            // Settlement signed by the tenant, ready to refund
+	DepositStatusRefunded         = "refunded"
+)
+
+// DepositStatusTranslations are the Spanish labels of the deposit statuses
+var DepositStatusTranslations = map[string]string{
+	DepositStatusHeld:             "Retenido",
+	DepositStatusSettlementIssued: "Liquidación enviada",
+	DepositStatusSigned:           "Liquidación firmada",
+	DepositStatusRefunded:         "Reembolsado",
+}
+
+// Categories of the deductions from a deposit
+const (
+	DeductionCategoryDamages    = "damages"     // Repairs beyond normal wear
+	DeductionCategoryUnpaidRent = "unpaid_rent" // Rent or late fees owed
+	DeductionCategoryUtilities  = "utilities"   // Public services pending at move-out
+	DeductionCategoryCleaning   = "cleaning"
+	DeductionCategoryOther      = "other"
+)
+
+// DeductionCategories lists the supported deduction categories
+var DeductionCategories = []string{
+	DeductionCategoryDamages, DeductionCategoryUnpaidRent, DeductionCategoryUtilities,
+	DeductionCategoryCleaning, DeductionCategoryOther,
+}
+
+// DeductionCategoryLabels are the Spanish labels printed in the settlement
+var DeductionCategoryLabels = map[string]string{
+	DeductionCategoryDamages:    "Reparación de daños",
+	DeductionCategoryUnpaidRent: "Cánones o intereses pendientes",
+	DeductionCategoryUtilities:  "Servicios públicos pendientes",
+	DeductionCategoryCleaning:   "Aseo",
+	DeductionCategoryOther:      "Otro",
+}
+
+// DepositDeduction is an amount withheld from a deposit, with its reason and receipt
+type DepositDeduction struct {
+	ID          uuid.UUID `json:"id"`
+	Category    string    `json:"category"` // One of the DeductionCategory constants
+	Reason      string    `json:"reason"`
+	Amount      float64   `json:"amount"`
+	ReceiptPath string    `json:"receipt_path,omitempty"` // Invoice or quote backing the deduction
+	ReceiptURL  string    `json:"receipt_url,omitempty"`  // Signed URL, filled when read
+	CreatedBy   uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SecurityDeposit is the security deposit (depósito en garantía) paid by the tenant of a
+// rental, the deductions withheld from it and its refund at move-out
+type SecurityDeposit struct {
+	ID                 uuid.UUID          `json:"id"`
+	RentalID           uuid.UUID          `json:"rental_id"`
+	Amount             float64            `json:"amount"`
+	HeldBy             string             `json:"held_by"`                   // One of the DepositHolder constants
+	BankAccountID      *uuid.UUID         `json:"bank_account_id,omitempty"` // Account the deposit is kept in
+	ReceivedAt         time.Time          `json:"received_at"`
+	Notes              string             `json:"notes,omitempty"`
+	Deductions         []DepositDeduction `json:"deductions"`
+	Status             string             `json:"status"` // One of the DepositStatus constants
+	MoveOutDate        *time.Time         `json:"move_out_date,omitempty"`
+	SettlementPath     string             `json:"settlement_path,omitempty"`
+	SettlementIssuedAt *time.Time         `json:"settlement_issued_at,omitempty"`
+	SignedPath         string             `json:"signed_path,omitempty"`
+	SignedAt           *time.Time         `json:"signed_at,omitempty"`
+	SignerIP           string             `json:"signer_ip,omitempty"`
+	SignerUserAgent    string             `json:"signer_user_agent,omitempty"`
+	RefundedAt         *time.Time         `json:"refunded_at,omitempty"`
+	RefundReference    string             `json:"refund_reference,omitempty"` // Transfer or voucher of the refund
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// TotalDeductions returns the sum of the deductions
+func (d SecurityDeposit) TotalDeductions() float64 {
+	total := 0.0
+	for _, deduction := range d.Deductions {
+		total += deduction.Amount
+	}
+	return total
+}
+
+// RefundAmount returns what is returned to the tenant, zero when the deductions exceed the deposit
+func (d SecurityDeposit) RefundAmount() float64 {
+	if refund := d.Amount - d.TotalDeductions(); refund > 0 {
+		return refund
+	}
+	return 0
+}
+
+// BalanceOwed returns what the tenant still owes when the deductions exceed the deposit
+func (d SecurityDeposit) BalanceOwed() float64 {
+	if owed := d.TotalDeductions() - d.Amount; owed > 0 {
+		return owed
+	}
+	return 0
+}
+
+// IsValidDepositHolder reports whether holder is a supported deposit holder
+func IsValidDepositHolder(holder string) bool {
+	for _, h := range DepositHolders {
+		if h == holder {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDeductionCategory reports whether category is a supported deduction category
+func IsValidDeductionCategory(category string) bool {
+	for _, c := range DeductionCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
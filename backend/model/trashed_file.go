@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashedFile records a file an admin deleted. The file is kept at TrashPath, under the trash/
+// prefix of the bucket, until PurgeAt so it can be restored to Path.
+type TrashedFile struct {
+	ID        uuid.UUID `json:"id"`
+	Path      string    `json:"path"`
+	TrashPath string    `json:"trash_path"`
+	FileName  string    `json:"file_name"`
+	DeletedBy string    `json:"deleted_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// UploadLimitRoles are the user roles with their own upload limits
+var UploadLimitRoles = []string{"admin", "manager", "resident", "user"}
+
+// UploadLimit holds the maximum size of a single file and the storage quota of the users of a
+// role. Zero means no limit. Limits saved by an admin override the ones in the environment.
+type UploadLimit struct {
+	Role          string     `json:"role"`
+	MaxFileSizeMB int64      `json:"max_file_size_mb"`
+	QuotaMB       int64      `json:"quota_mb"`
+	Source        string     `json:"source,omitempty"` // "env" or "admin", not stored
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// MaxFileSize returns the maximum size of a file in bytes, 0 without limit
+func (l UploadLimit) MaxFileSize() int64 {
+	return l.MaxFileSizeMB * 1024 * 1024
+}
+
+// Quota returns the storage quota in bytes, 0 without limit
+func (l UploadLimit) Quota() int64 {
+	return l.QuotaMB * 1024 * 1024
+}
+
+// IsUploadLimitRole reports whether role is one of UploadLimitRoles
+func IsUploadLimitRole(role string) bool {
+	for _, r := range UploadLimitRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
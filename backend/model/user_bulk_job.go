@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions of a bulk user job
+const (
+	UserBulkActionActivate         = "activate"
+	UserBulkActionDisable          = "disable"
+	UserBulkActionResendInvitation = "resend_invitation"
+)
+
+// IsValidUserBulkAction reports whether action is a known bulk user action
+func IsValidUserBulkAction(action string) bool {
+	switch action {
+	case UserBulkActionActivate, UserBulkActionDisable, UserBulkActionResendInvitation:
+		return true
+	}
+	return false
+}
+
+// States of a bulk user job
+const (
+	UserBulkJobQueued    = "queued"
+	UserBulkJobRunning   = "running"
+	UserBulkJobCompleted = "completed"
+	UserBulkJobFailed    = "failed"
+)
+
+// Outcomes of a bulk user job for each user
+const (
+	UserBulkResultSucceeded = "succeeded"
+	UserBulkResultSkipped   = "skipped"
+	UserBulkResultFailed    = "failed"
+)
+
+// UserBulkFilter selects the users a bulk job applies to. Empty fields do not filter.
+type UserBulkFilter struct {
+	Status         string     `json:"status,omitempty"`
+	Role           string     `json:"role,omitempty"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	CreatedFrom    *time.Time `json:"created_from,omitempty"`
+	CreatedTo      *time.Time `json:"created_to,omitempty"`
+}
+
+// UserBulkResult is the outcome of a bulk job for one user
+type UserBulkResult struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	PreviousStatus string    `json:"previous_status"`
+	Outcome        string    `json:"outcome"`
+	Message        string    `json:"message,omitempty"`
+}
+
+// UserBulkJob changes the status of, or resends the invitation to, the users matching a filter.
+// Jobs run in the background and keep a per-user report.
+type UserBulkJob struct {
+	ID         uuid.UUID        `json:"id"`
+	Action     string           `json:"action"`
+	Filter     UserBulkFilter   `json:"filter"`
+	Status     string           `json:"status"`
+	Total      int              `json:"total"`
+	Succeeded  int              `json:"succeeded"`
+	Skipped    int              `json:"skipped"`
+	Failed     int              `json:"failed"`
+	Results    []UserBulkResult `json:"results"`
+	Error      string           `json:"error,omitempty"`
+	CreatedBy  uuid.UUID        `json:"created_by"`
+	CreatedAt  time.Time        `json:"created_at"`
+	StartedAt  *time.Time       `json:"started_at,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+// AddResult records the outcome for a user and updates the counters
+func (j *UserBulkJob) AddResult(result UserBulkResult) {
+	j.Results = append(j.Results, result)
+	switch result.Outcome {
+	case UserBulkResultSucceeded:
+		j.Succeeded++
+	case UserBulkResultSkipped:
+		j.Skipped++
+	default:
+		j.Failed++
+	}
+}
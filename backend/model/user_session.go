@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSession is a login of a user: the JWT issued to a device, identified by its jti claim,
+// until it expires or the user revokes it
+type UserSession struct {
+	ID         uuid.UUID  `json:"id"` // jti of the JWT
+	UserID     uuid.UUID  `json:"user_id"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	Device     string     `json:"device,omitempty"` // Browser and operating system read from the user agent
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// ImpersonatorID is the admin who opened the session to act as the user
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
+}
+
+// Active reports whether the session can still be used at now
+func (s *UserSession) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
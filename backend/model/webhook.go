@@ -0,0 +1,88 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signing lifecycle events posted to the webhooks
+const (
+	WebhookEventSigningCreated  = "signing.created"
+	WebhookEventSigningViewed   = "signing.viewed"
+	WebhookEventSigningSigned   = "signing.signed"
+	WebhookEventSigningRejected = "signing.rejected"
+	WebhookEventSigningExpired  = "signing.expired"
+	// WebhookEventPing is sent on demand to test a webhook
+	WebhookEventPing = "ping"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventSigningCreated,
+	WebhookEventSigningViewed,
+	WebhookEventSigningSigned,
+	WebhookEventSigningRejected,
+	WebhookEventSigningExpired,
+}
+
+// IsValidWebhookEvent reports whether event is a known signing event
+func IsValidWebhookEvent(event string) bool {
+	for _, known := range WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is a callback URL registered by a manager to receive the signing events of
+// the requests they create, or of every request when registered by an admin with AllRequests
+type WebhookSubscription struct {
+	ID          uuid.UUID `json:"id"`
+	PersonID    uuid.UUID `json:"person_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret"`       // Key of the HMAC signature of the payloads
+	Events      []string  `json:"events"`       // Empty subscribes to every event
+	AllRequests bool      `json:"all_requests"` // Every signing request instead of the ones of PersonID
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Subscribes reports whether the webhook receives an event
+func (s WebhookSubscription) Subscribes(event string) bool {
+	if event == WebhookEventPing || len(s.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range s.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Status of a webhook delivery
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is an event queued to be posted to a webhook, retried until it is accepted
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	Event          string          `json:"event"`
+	SigningID      string          `json:"signing_id,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,3455 @@
+// Code generated by sdk/gen from the spec served at /api/docs/openapi.json; DO NOT EDIT.
+
+package sdk
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditLog is the audit log schema of the API
+type AuditLog struct {
+	Action         string      `json:"action,omitempty"`
+	ChangedBy      string      `json:"changed_by,omitempty"`
+	Details        interface{} `json:"details,omitempty"`
+	Entity         string      `json:"entity,omitempty"`
+	EntityID       string      `json:"entity_id,omitempty"`
+	ID             string      `json:"id,omitempty"`
+	ImpersonatedBy string      `json:"impersonated_by,omitempty"`
+	Timestamp      time.Time   `json:"timestamp,omitempty"`
+}
+
+// BankAccount is the bank account schema of the API
+type BankAccount struct {
+	AccountHolder string `json:"account_holder,omitempty"`
+	AccountNumber string `json:"account_number,omitempty"`
+	AccountType   string `json:"account_type,omitempty"`
+	BankName      string `json:"bank_name,omitempty"`
+	ID            string `json:"id,omitempty"`
+	PersonID      string `json:"person_id,omitempty"`
+}
+
+// BucketBackup is the bucket backup schema of the API
+type BucketBackup struct {
+	Copied            int                 `json:"copied,omitempty"`
+	Error             string              `json:"error,omitempty"`
+	Failed            int                 `json:"failed,omitempty"`
+	FileCount         int                 `json:"file_count,omitempty"`
+	FinishedAt        time.Time           `json:"finished_at,omitempty"`
+	ID                string              `json:"id,omitempty"`
+	Manifest          []BucketBackupEntry `json:"manifest,omitempty"`
+	ManifestLocations map[string]string   `json:"manifest_locations,omitempty"`
+	Mode              string              `json:"mode,omitempty"`
+	Providers         []string            `json:"providers,omitempty"`
+	StartedAt         time.Time           `json:"started_at,omitempty"`
+	Status            string              `json:"status,omitempty"`
+	TotalSize         int                 `json:"total_size,omitempty"`
+}
+
+// BucketBackupEntry is the bucket backup entry schema of the API
+type BucketBackupEntry struct {
+	Error     string            `json:"error,omitempty"`
+	Locations map[string]string `json:"locations,omitempty"`
+	Path      string            `json:"path,omitempty"`
+	Sha256    string            `json:"sha256,omitempty"`
+	Size      int               `json:"size,omitempty"`
+	UpdatedAt string            `json:"updated_at,omitempty"`
+}
+
+// Building is the building schema of the API
+type Building struct {
+	Address            string    `json:"address,omitempty"`
+	AdminFee           float64   `json:"admin_fee,omitempty"`
+	AdministratorEmail string    `json:"administrator_email,omitempty"`
+	AdministratorName  string    `json:"administrator_name,omitempty"`
+	AdministratorPhone string    `json:"administrator_phone,omitempty"`
+	City               string    `json:"city,omitempty"`
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+	ID                 string    `json:"id,omitempty"`
+	Name               string    `json:"name,omitempty"`
+	NIT                string    `json:"nit,omitempty"`
+	Notes              string    `json:"notes,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`
+}
+
+// BuildingRequest is the building request schema of the API
+type BuildingRequest struct {
+	Address            string  `json:"address,omitempty"`
+	AdminFee           float64 `json:"admin_fee,omitempty"`
+	AdministratorEmail string  `json:"administrator_email,omitempty"`
+	AdministratorName  string  `json:"administrator_name,omitempty"`
+	AdministratorPhone string  `json:"administrator_phone,omitempty"`
+	City               string  `json:"city,omitempty"`
+	Name               string  `json:"name,omitempty"`
+	NIT                string  `json:"nit,omitempty"`
+	Notes              string  `json:"notes,omitempty"`
+}
+
+// BuildingResponse is the building response schema of the API
+type BuildingResponse struct {
+	Address            string     `json:"address,omitempty"`
+	AdminFee           float64    `json:"admin_fee,omitempty"`
+	AdministratorEmail string     `json:"administrator_email,omitempty"`
+	AdministratorName  string     `json:"administrator_name,omitempty"`
+	AdministratorPhone string     `json:"administrator_phone,omitempty"`
+	City               string     `json:"city,omitempty"`
+	CreatedAt          time.Time  `json:"created_at,omitempty"`
+	ID                 string     `json:"id,omitempty"`
+	Name               string     `json:"name,omitempty"`
+	NIT                string     `json:"nit,omitempty"`
+	Notes              string     `json:"notes,omitempty"`
+	Properties         []Property `json:"properties,omitempty"`
+	Reglamento         Reglamento `json:"reglamento,omitempty"`
+	UpdatedAt          time.Time  `json:"updated_at,omitempty"`
+}
+
+// Capabilities is the capabilities schema of the API
+type Capabilities struct {
+	Features           map[string]bool `json:"features,omitempty"`
+	FileStorageBackend string          `json:"file_storage_backend,omitempty"`
+}
+
+// CreateChunkedUploadRequest is the create chunked upload request schema of the API
+type CreateChunkedUploadRequest struct {
+	Category    string   `json:"category,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+	ContractID  string   `json:"contract_id,omitempty"`
+	FileName    string   `json:"file_name,omitempty"`
+	RentalID    string   `json:"rental_id,omitempty"`
+	Size        int      `json:"size,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Token       string   `json:"token,omitempty"`
+}
+
+// CreateInspectionRequest is the create inspection request schema of the API
+type CreateInspectionRequest struct {
+	InspectedAt string `json:"inspected_at,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	TemplateID  string `json:"template_id,omitempty"`
+}
+
+// DashboardSummary is the dashboard summary schema of the API
+type DashboardSummary struct {
+	ActiveRentals        int       `json:"active_rentals,omitempty"`
+	ExpirationWindowDays int       `json:"expiration_window_days,omitempty"`
+	ExpiringContracts    int       `json:"expiring_contracts,omitempty"`
+	GeneratedAt          time.Time `json:"generated_at,omitempty"`
+	OpenMaintenance      int       `json:"open_maintenance,omitempty"`
+	OverduePayments      int       `json:"overdue_payments,omitempty"`
+	PendingSignatures    int       `json:"pending_signatures,omitempty"`
+	Properties           int       `json:"properties,omitempty"`
+	Scope                string    `json:"scope,omitempty"`
+	Vacancies            int       `json:"vacancies,omitempty"`
+}
+
+// DependencyHealth is the dependency health schema of the API
+type DependencyHealth struct {
+	Critical  bool   `json:"critical,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int    `json:"latency_ms,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// EmailTemplate is the email template schema of the API
+type EmailTemplate struct {
+	Body      string    `json:"body,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Version   int       `json:"version,omitempty"`
+}
+
+// EmailTemplatePreviewRequest is the email template preview request schema of the API
+type EmailTemplatePreviewRequest struct {
+	Body    string `json:"body,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// EmailTemplateRequest is the email template request schema of the API
+type EmailTemplateRequest struct {
+	Body    string `json:"body,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// EmailTestRequest is the email test request schema of the API
+type EmailTestRequest struct {
+	Driver string `json:"driver,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// Error is the error schema of the API
+type Error struct {
+	Detail    string      `json:"detail,omitempty"`     // Untranslated detail appended to the message
+	Error     string      `json:"error,omitempty"`      // Message in the locale of the request
+	MessageID string      `json:"message_id,omitempty"` // ID of the message in the catalogs
+	Params    interface{} `json:"params,omitempty"`     // Data of the message
+}
+
+// FeatureFlag is the feature flag schema of the API
+type FeatureFlag struct {
+	Description string    `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled,omitempty"`
+	Key         string    `json:"key,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+}
+
+// FileMetadata is the file metadata schema of the API
+type FileMetadata struct {
+	Category     string    `json:"category,omitempty"`
+	ContractID   string    `json:"contract_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	FileName     string    `json:"file_name,omitempty"`
+	ID           string    `json:"id,omitempty"`
+	MimeType     string    `json:"mime_type,omitempty"`
+	OriginalName string    `json:"original_name,omitempty"`
+	Path         string    `json:"path,omitempty"`
+	RentalID     string    `json:"rental_id,omitempty"`
+	Size         int       `json:"size,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	UploadedBy   string    `json:"uploaded_by,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+}
+
+// FileScan is the file scan schema of the API
+type FileScan struct {
+	Detail    string    `json:"detail,omitempty"`
+	FileName  string    `json:"file_name,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Result    string    `json:"result,omitempty"`
+	ScannedAt time.Time `json:"scanned_at,omitempty"`
+	Signature string    `json:"signature,omitempty"`
+	Size      int       `json:"size,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+}
+
+// GenerateUploadLinkRequest is the generate upload link request schema of the API
+type GenerateUploadLinkRequest struct {
+	ExpirationDays int    `json:"expiration_days,omitempty"`
+	RecipientEmail string `json:"recipient_email,omitempty"`
+	RecipientName  string `json:"recipient_name,omitempty"`
+	SendEmail      bool   `json:"send_email,omitempty"`
+	UserID         string `json:"user_id,omitempty"`
+}
+
+// GraphQLError is the graph q l error schema of the API
+type GraphQLError struct {
+	Locations []Location    `json:"locations,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+// GraphQLRequest is the graph q l request schema of the API
+type GraphQLRequest struct {
+	OperationName string                 `json:"operationName,omitempty"`
+	Query         string                 `json:"query,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the graph q l response schema of the API
+type GraphQLResponse struct {
+	Data   interface{}    `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// HealthReport is the health report schema of the API
+type HealthReport struct {
+	CheckedAt time.Time                   `json:"checked_at,omitempty"`
+	Checks    map[string]DependencyHealth `json:"checks,omitempty"`
+	Profile   string                      `json:"profile,omitempty"`
+	Status    string                      `json:"status,omitempty"`
+}
+
+// Inspection is the inspection schema of the API
+type Inspection struct {
+	AcknowledgedAt time.Time       `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	InspectedAt    time.Time       `json:"inspected_at,omitempty"`
+	InspectorID    string          `json:"inspector_id,omitempty"`
+	Kind           string          `json:"kind,omitempty"`
+	Notes          string          `json:"notes,omitempty"`
+	RentalID       string          `json:"rental_id,omitempty"`
+	Rooms          []InventoryRoom `json:"rooms,omitempty"`
+	SignedPDFPath  string          `json:"signed_pdf_path,omitempty"`
+	SigningID      string          `json:"signing_id,omitempty"`
+	Status         string          `json:"status,omitempty"`
+	TemplateID     string          `json:"template_id,omitempty"`
+	UpdatedAt      time.Time       `json:"updated_at,omitempty"`
+}
+
+// InspectionComparison is the inspection comparison schema of the API
+type InspectionComparison struct {
+	Items         []InspectionItemComparison `json:"items,omitempty"`
+	MissingCount  int                        `json:"missing_count,omitempty"`
+	MoveIn        Inspection                 `json:"move_in,omitempty"`
+	MoveOut       Inspection                 `json:"move_out,omitempty"`
+	RentalID      string                     `json:"rental_id,omitempty"`
+	WorsenedCount int                        `json:"worsened_count,omitempty"`
+}
+
+// InspectionItemComparison is the inspection item comparison schema of the API
+type InspectionItemComparison struct {
+	Change           string           `json:"change,omitempty"`
+	Item             string           `json:"item,omitempty"`
+	MoveInCondition  string           `json:"move_in_condition,omitempty"`
+	MoveInNotes      string           `json:"move_in_notes,omitempty"`
+	MoveInQuantity   int              `json:"move_in_quantity,omitempty"`
+	MoveOutCondition string           `json:"move_out_condition,omitempty"`
+	MoveOutNotes     string           `json:"move_out_notes,omitempty"`
+	MoveOutPhotos    []InventoryPhoto `json:"move_out_photos,omitempty"`
+	MoveOutQuantity  int              `json:"move_out_quantity,omitempty"`
+	Room             string           `json:"room,omitempty"`
+}
+
+// InventoryItem is the inventory item schema of the API
+type InventoryItem struct {
+	Condition string           `json:"condition,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Notes     string           `json:"notes,omitempty"`
+	Photos    []InventoryPhoto `json:"photos,omitempty"`
+	Quantity  int              `json:"quantity,omitempty"`
+}
+
+// InventoryPhoto is the inventory photo schema of the API
+type InventoryPhoto struct {
+	Caption string `json:"caption,omitempty"`
+	Path    string `json:"path,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// InventoryRoom is the inventory room schema of the API
+type InventoryRoom struct {
+	Items  []InventoryItem  `json:"items,omitempty"`
+	Name   string           `json:"name,omitempty"`
+	Photos []InventoryPhoto `json:"photos,omitempty"`
+}
+
+// Invitation is the invitation schema of the API
+type Invitation struct {
+	AcceptedAt     time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	Email          string    `json:"email,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	InvitedBy      string    `json:"invited_by,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	OrganizationID string    `json:"organization_id,omitempty"`
+	PersonID       string    `json:"person_id,omitempty"`
+	PropertyID     string    `json:"property_id,omitempty"`
+	RevokedAt      time.Time `json:"revoked_at,omitempty"`
+	Role           string    `json:"role,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+}
+
+// InvitationInput is the invitation input schema of the API
+type InvitationInput struct {
+	Email          string `json:"email,omitempty"`
+	Message        string `json:"message,omitempty"`
+	Name           string `json:"name,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	PersonID       string `json:"person_id,omitempty"`
+	PropertyID     string `json:"property_id,omitempty"`
+	Role           string `json:"role,omitempty"`
+}
+
+// InvitationRequest is the invitation request schema of the API
+type InvitationRequest struct {
+	Email        string `json:"email,omitempty"`
+	Message      string `json:"message,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Status       string `json:"status,omitempty"`
+	TempPassword string `json:"tempPassword,omitempty"`
+}
+
+// IssueSettlementRequest is the issue settlement request schema of the API
+type IssueSettlementRequest struct {
+	MoveOutDate string `json:"move_out_date,omitempty"`
+}
+
+// ListingApplicationRequest is the listing application request schema of the API
+type ListingApplicationRequest struct {
+	DesiredMoveIn  string  `json:"desired_move_in,omitempty"`
+	DocumentNumber string  `json:"document_number,omitempty"`
+	Email          string  `json:"email,omitempty"`
+	FullName       string  `json:"full_name,omitempty"`
+	Message        string  `json:"message,omitempty"`
+	MonthlyIncome  float64 `json:"monthly_income,omitempty"`
+	Occupants      int     `json:"occupants,omitempty"`
+	Occupation     string  `json:"occupation,omitempty"`
+	Phone          string  `json:"phone,omitempty"`
+}
+
+// ListingRequest is the listing request schema of the API
+type ListingRequest struct {
+	AdminFee      float64 `json:"admin_fee,omitempty"`
+	AreaM2        float64 `json:"area_m2,omitempty"`
+	AvailableFrom string  `json:"available_from,omitempty"`
+	Bathrooms     int     `json:"bathrooms,omitempty"`
+	Bedrooms      int     `json:"bedrooms,omitempty"`
+	DepositAmount float64 `json:"deposit_amount,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	MinTermMonths int     `json:"min_term_months,omitempty"`
+	MonthlyRent   float64 `json:"monthly_rent,omitempty"`
+	PetsAllowed   bool    `json:"pets_allowed,omitempty"`
+	Title         string  `json:"title,omitempty"`
+}
+
+// ListingStatusRequest is the listing status request schema of the API
+type ListingStatusRequest struct {
+	Status string `json:"status,omitempty"`
+}
+
+// Location is the location schema of the API
+type Location struct {
+	Column int `json:"column,omitempty"`
+	Line   int `json:"line,omitempty"`
+}
+
+// LoginAttempt is the login attempt schema of the API
+type LoginAttempt struct {
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	Email         string    `json:"email,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	ID            string    `json:"id,omitempty"`
+	IpAddress     string    `json:"ip_address,omitempty"`
+	Success       bool      `json:"success,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+}
+
+// LoginRequest is the login request schema of the API
+type LoginRequest struct {
+	Email    string `json:"email,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// LoginResponse is the login response schema of the API
+type LoginResponse struct {
+	Success bool      `json:"success,omitempty"`
+	Token   string    `json:"token,omitempty"`
+	User    LoginUser `json:"user,omitempty"`
+}
+
+// LoginStatus is the login status schema of the API
+type LoginStatus struct {
+	CaptchaRequired bool      `json:"captcha_required,omitempty"`
+	FailedAttempts  int       `json:"failed_attempts,omitempty"`
+	Locked          bool      `json:"locked,omitempty"`
+	LockedUntil     time.Time `json:"locked_until,omitempty"`
+}
+
+// LoginUser is the login user schema of the API
+type LoginUser struct {
+	Email    string `json:"email,omitempty"`
+	ID       string `json:"id,omitempty"`
+	PersonID string `json:"person_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// ManagerRegistrationRequest is the manager registration request schema of the API
+type ManagerRegistrationRequest struct {
+	AccountHolder        string    `json:"account_holder,omitempty"`
+	AccountNumber        string    `json:"account_number,omitempty"`
+	AccountType          string    `json:"account_type,omitempty"`
+	BankName             string    `json:"bank_name,omitempty"`
+	DueDay               int       `json:"due_day,omitempty"`
+	Email                string    `json:"email,omitempty"`
+	EndDate              time.Time `json:"end_date,omitempty"`
+	FullName             string    `json:"full_name,omitempty"`
+	LateFee              float64   `json:"late_fee,omitempty"`
+	MonthlyRent          float64   `json:"monthly_rent,omitempty"`
+	NIT                  string    `json:"nit,omitempty"`
+	Password             string    `json:"password,omitempty"`
+	PaymentTerms         string    `json:"payment_terms,omitempty"`
+	Phone                string    `json:"phone,omitempty"`
+	PropertyAddress      string    `json:"property_address,omitempty"`
+	PropertyAptNumber    string    `json:"property_apt_number,omitempty"`
+	PropertyCity         string    `json:"property_city,omitempty"`
+	PropertyState        string    `json:"property_state,omitempty"`
+	PropertyType         string    `json:"property_type,omitempty"`
+	PropertyZipCode      string    `json:"property_zip_code,omitempty"`
+	SecurityDeposit      float64   `json:"security_deposit,omitempty"`
+	StartDate            time.Time `json:"start_date,omitempty"`
+	TenantResponsibleFor []string  `json:"tenant_responsible_for,omitempty"`
+	UtilitiesIncluded    []string  `json:"utilities_included,omitempty"`
+}
+
+// ManagerRegistrationResponse is the manager registration response schema of the API
+type ManagerRegistrationResponse struct {
+	Message    string `json:"message,omitempty"`
+	PaymentURL string `json:"payment_url,omitempty"`
+	PersonID   string `json:"person_id,omitempty"`
+	PropertyID string `json:"property_id,omitempty"`
+	Success    bool   `json:"success,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+}
+
+// Notification is the notification schema of the API
+type Notification struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	EntityID  string    `json:"entity_id,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	ReadAt    time.Time `json:"read_at,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+}
+
+// NotificationPreferenceInput is the notification preference input schema of the API
+type NotificationPreferenceInput struct {
+	Channel   string `json:"channel,omitempty"`
+	Frequency string `json:"frequency,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// NotificationPreferencesRequest is the notification preferences request schema of the API
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceInput `json:"preferences,omitempty"`
+}
+
+// OccupancyGap is the occupancy gap schema of the API
+type OccupancyGap struct {
+	Days      int       `json:"days,omitempty"`
+	EndDate   time.Time `json:"end_date,omitempty"`
+	StartDate time.Time `json:"start_date,omitempty"`
+}
+
+// OccupancyPeriod is the occupancy period schema of the API
+type OccupancyPeriod struct {
+	Days        int       `json:"days,omitempty"`
+	EndDate     time.Time `json:"end_date,omitempty"`
+	EndReason   string    `json:"end_reason,omitempty"`
+	MonthlyRent float64   `json:"monthly_rent,omitempty"`
+	RentalID    string    `json:"rental_id,omitempty"`
+	RenterID    string    `json:"renter_id,omitempty"`
+	StartDate   time.Time `json:"start_date,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	TenantName  string    `json:"tenant_name,omitempty"`
+}
+
+// OccupancyTimeline is the occupancy timeline schema of the API
+type OccupancyTimeline struct {
+	From          time.Time         `json:"from,omitempty"`
+	Gaps          []OccupancyGap    `json:"gaps,omitempty"`
+	OccupancyRate float64           `json:"occupancy_rate,omitempty"`
+	OccupiedDays  int               `json:"occupied_days,omitempty"`
+	Periods       []OccupancyPeriod `json:"periods,omitempty"`
+	PropertyID    string            `json:"property_id,omitempty"`
+	To            time.Time         `json:"to,omitempty"`
+	TotalDays     int               `json:"total_days,omitempty"`
+	VacantDays    int               `json:"vacant_days,omitempty"`
+}
+
+// PaymentCheckout is the payment checkout schema of the API
+type PaymentCheckout struct {
+	AmountInCents int       `json:"amount_in_cents,omitempty"`
+	CompletedAt   time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	Currency      string    `json:"currency,omitempty"`
+	ID            string    `json:"id,omitempty"`
+	PaymentMethod string    `json:"payment_method,omitempty"`
+	Period        string    `json:"period,omitempty"`
+	PersonID      string    `json:"person_id,omitempty"`
+	Provider      string    `json:"provider,omitempty"`
+	Reference     string    `json:"reference,omitempty"`
+	RentPaymentID string    `json:"rent_payment_id,omitempty"`
+	RentalID      string    `json:"rental_id,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+}
+
+// Person is the person schema of the API
+type Person struct {
+	FullName string `json:"full_name,omitempty"`
+	ID       string `json:"id,omitempty"`
+	NIT      string `json:"nit,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Version  int    `json:"version,omitempty"`
+}
+
+// PlatformSubscription is the platform subscription schema of the API
+type PlatformSubscription struct {
+	CreatedAt        time.Time `json:"created_at,omitempty"`
+	CurrentPeriodEnd time.Time `json:"current_period_end,omitempty"`
+	CustomerID       string    `json:"customer_id,omitempty"`
+	ID               string    `json:"id,omitempty"`
+	PastDueSince     time.Time `json:"past_due_since,omitempty"`
+	PaymentURL       string    `json:"payment_url,omitempty"`
+	PriceID          string    `json:"price_id,omitempty"`
+	Provider         string    `json:"provider,omitempty"`
+	Status           string    `json:"status,omitempty"`
+	SubscriptionID   string    `json:"subscription_id,omitempty"`
+	SuspendedAt      time.Time `json:"suspended_at,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+	UserID           string    `json:"user_id,omitempty"`
+}
+
+// Property is the property schema of the API
+type Property struct {
+	Address        string          `json:"address,omitempty"`
+	AptNumber      string          `json:"apt_number,omitempty"`
+	AreaM2         float64         `json:"area_m2,omitempty"`
+	Bathrooms      int             `json:"bathrooms,omitempty"`
+	Bedrooms       int             `json:"bedrooms,omitempty"`
+	Boundaries     string          `json:"boundaries,omitempty"`
+	BuildingID     string          `json:"building_id,omitempty"`
+	City           string          `json:"city,omitempty"`
+	Estrato        int             `json:"estrato,omitempty"`
+	Furnished      bool            `json:"furnished,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	ManagerIDs     []string        `json:"manager_ids,omitempty"`
+	ParkingDetails string          `json:"parking_details,omitempty"`
+	ParkingSpots   int             `json:"parking_spots,omitempty"`
+	PetsAllowed    bool            `json:"pets_allowed,omitempty"`
+	Photos         []PropertyPhoto `json:"photos,omitempty"`
+	ResidentID     string          `json:"resident_id,omitempty"`
+	State          string          `json:"state,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Version        int             `json:"version,omitempty"`
+	ZipCode        string          `json:"zip_code,omitempty"`
+}
+
+// PropertyListing is the property listing schema of the API
+type PropertyListing struct {
+	AdminFee      float64          `json:"admin_fee,omitempty"`
+	AreaM2        float64          `json:"area_m2,omitempty"`
+	AvailableFrom time.Time        `json:"available_from,omitempty"`
+	Bathrooms     int              `json:"bathrooms,omitempty"`
+	Bedrooms      int              `json:"bedrooms,omitempty"`
+	City          string           `json:"city,omitempty"`
+	CreatedAt     time.Time        `json:"created_at,omitempty"`
+	DepositAmount float64          `json:"deposit_amount,omitempty"`
+	Description   string           `json:"description,omitempty"`
+	ID            string           `json:"id,omitempty"`
+	MinTermMonths int              `json:"min_term_months,omitempty"`
+	MonthlyRent   float64          `json:"monthly_rent,omitempty"`
+	PetsAllowed   bool             `json:"pets_allowed,omitempty"`
+	Photos        []InventoryPhoto `json:"photos,omitempty"`
+	PropertyID    string           `json:"property_id,omitempty"`
+	PublishedAt   time.Time        `json:"published_at,omitempty"`
+	Status        string           `json:"status,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	UpdatedAt     time.Time        `json:"updated_at,omitempty"`
+}
+
+// PropertyPhoto is the property photo schema of the API
+type PropertyPhoto struct {
+	Caption    string    `json:"caption,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Position   int       `json:"position,omitempty"`
+	PropertyID string    `json:"property_id,omitempty"`
+	URL        string    `json:"url,omitempty"`
+}
+
+// PublicListing is the public listing schema of the API
+type PublicListing struct {
+	AdminFee      float64          `json:"admin_fee,omitempty"`
+	AreaM2        float64          `json:"area_m2,omitempty"`
+	AvailableFrom time.Time        `json:"available_from,omitempty"`
+	Bathrooms     int              `json:"bathrooms,omitempty"`
+	Bedrooms      int              `json:"bedrooms,omitempty"`
+	City          string           `json:"city,omitempty"`
+	DepositAmount float64          `json:"deposit_amount,omitempty"`
+	Description   string           `json:"description,omitempty"`
+	ID            string           `json:"id,omitempty"`
+	MinTermMonths int              `json:"min_term_months,omitempty"`
+	MonthlyRent   float64          `json:"monthly_rent,omitempty"`
+	PetsAllowed   bool             `json:"pets_allowed,omitempty"`
+	Photos        []InventoryPhoto `json:"photos,omitempty"`
+	PublishedAt   time.Time        `json:"published_at,omitempty"`
+	Title         string           `json:"title,omitempty"`
+}
+
+// RefundDepositRequest is the refund deposit request schema of the API
+type RefundDepositRequest struct {
+	Reference  string `json:"reference,omitempty"`
+	RefundedAt string `json:"refunded_at,omitempty"`
+}
+
+// Reglamento is the reglamento schema of the API
+type Reglamento struct {
+	Address     string    `json:"address,omitempty"`
+	BuildingKey string    `json:"building_key,omitempty"`
+	City        string    `json:"city,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	FilePath    string    `json:"file_path,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at,omitempty"`
+	UploadedBy  string    `json:"uploaded_by,omitempty"`
+	Version     int       `json:"version,omitempty"`
+}
+
+// RentCheckout is the rent checkout schema of the API
+type RentCheckout struct {
+	Checkout    PaymentCheckout `json:"checkout,omitempty"`
+	CheckoutURL string          `json:"checkout_url,omitempty"`
+}
+
+// RentPayment is the rent payment schema of the API
+type RentPayment struct {
+	AmountPaid        float64   `json:"amount_paid,omitempty"`
+	ID                string    `json:"id,omitempty"`
+	InvoiceCufe       string    `json:"invoice_cufe,omitempty"`
+	InvoiceExternalID string    `json:"invoice_external_id,omitempty"`
+	InvoiceMessage    string    `json:"invoice_message,omitempty"`
+	InvoiceNumber     string    `json:"invoice_number,omitempty"`
+	InvoiceProvider   string    `json:"invoice_provider,omitempty"`
+	InvoiceSequence   int       `json:"invoice_sequence,omitempty"`
+	InvoiceStatus     string    `json:"invoice_status,omitempty"`
+	InvoicedAt        time.Time `json:"invoiced_at,omitempty"`
+	PaidOnTime        bool      `json:"paid_on_time,omitempty"`
+	PaymentDate       string    `json:"payment_date,omitempty"`
+	ReceiptNumber     string    `json:"receipt_number,omitempty"`
+	RentalID          string    `json:"rental_id,omitempty"`
+}
+
+// Rental is the rental schema of the API
+type Rental struct {
+	BankAccountID string `json:"bank_account_id,omitempty"`
+	EndDate       string `json:"end_date,omitempty"`
+	ID            string `json:"id,omitempty"`
+	PaymentTerms  string `json:"payment_terms,omitempty"`
+	PropertyID    string `json:"property_id,omitempty"`
+	RenterID      string `json:"renter_id,omitempty"`
+	StartDate     string `json:"start_date,omitempty"`
+	UnpaidMonths  int    `json:"unpaid_months,omitempty"`
+	Version       int    `json:"version,omitempty"`
+}
+
+// RentalPartyRequest is the rental party request schema of the API
+type RentalPartyRequest struct {
+	PersonID string `json:"person_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+// RentalPartyResponse is the rental party response schema of the API
+type RentalPartyResponse struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Person    Person    `json:"person,omitempty"`
+	PersonID  string    `json:"person_id,omitempty"`
+	RentalID  string    `json:"rental_id,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	RoleLabel string    `json:"role_label,omitempty"`
+}
+
+// ReorderPhotosRequest is the reorder photos request schema of the API
+type ReorderPhotosRequest struct {
+	PhotoIDs []string `json:"photo_ids,omitempty"`
+}
+
+// RestoreBucketBackupRequest is the restore bucket backup request schema of the API
+type RestoreBucketBackupRequest struct {
+	Overwrite bool     `json:"overwrite,omitempty"`
+	Paths     []string `json:"paths,omitempty"`
+}
+
+// SearchResult is the search result schema of the API
+type SearchResult struct {
+	ID         string  `json:"id,omitempty"`
+	PersonID   string  `json:"person_id,omitempty"`
+	PropertyID string  `json:"property_id,omitempty"`
+	Rank       float64 `json:"rank,omitempty"`
+	RentalID   string  `json:"rental_id,omitempty"`
+	Subtitle   string  `json:"subtitle,omitempty"`
+	Title      string  `json:"title,omitempty"`
+	Type       string  `json:"type,omitempty"`
+}
+
+// SecurityDepositRequest is the security deposit request schema of the API
+type SecurityDepositRequest struct {
+	Amount        float64 `json:"amount,omitempty"`
+	BankAccountID string  `json:"bank_account_id,omitempty"`
+	HeldBy        string  `json:"held_by,omitempty"`
+	Notes         string  `json:"notes,omitempty"`
+	ReceivedAt    string  `json:"received_at,omitempty"`
+}
+
+// SignerLocation is the signer location schema of the API
+type SignerLocation struct {
+	Accuracy  float64 `json:"accuracy,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// StartBucketBackupRequest is the start bucket backup request schema of the API
+type StartBucketBackupRequest struct {
+	Full bool `json:"full,omitempty"`
+}
+
+// StorageUsage is the storage usage schema of the API
+type StorageUsage struct {
+	FileCount        int    `json:"file_count,omitempty"`
+	MaxFileSizeBytes int    `json:"max_file_size_bytes,omitempty"`
+	QuotaBytes       int    `json:"quota_bytes,omitempty"`
+	RemainingBytes   int    `json:"remaining_bytes,omitempty"`
+	Role             string `json:"role,omitempty"`
+	UsedBytes        int    `json:"used_bytes,omitempty"`
+	UserID           string `json:"user_id,omitempty"`
+}
+
+// SupabaseFileInfo is the supabase file info schema of the API
+type SupabaseFileInfo struct {
+	Category    string   `json:"category,omitempty"`
+	ContractID  string   `json:"contract_id,omitempty"`
+	DownloadURL string   `json:"download_url,omitempty"`
+	MimeType    string   `json:"mime_type,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	RentalID    string   `json:"rental_id,omitempty"`
+	Size        int      `json:"size,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	UploadedAt  string   `json:"uploaded_at,omitempty"`
+}
+
+// SupabaseUploadResponse is the supabase upload response schema of the API
+type SupabaseUploadResponse struct {
+	BucketName string   `json:"bucket_name,omitempty"`
+	Category   string   `json:"category,omitempty"`
+	ContractID string   `json:"contract_id,omitempty"`
+	Key        string   `json:"key,omitempty"`
+	Link       string   `json:"link,omitempty"`
+	MimeType   string   `json:"mime_type,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	RentalID   string   `json:"rental_id,omitempty"`
+	Size       int      `json:"size,omitempty"`
+	Success    bool     `json:"success,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	UploadedAt string   `json:"uploaded_at,omitempty"`
+	UploadedBy string   `json:"uploaded_by,omitempty"`
+}
+
+// UnsubscribeRequest is the unsubscribe request schema of the API
+type UnsubscribeRequest struct {
+	Type string `json:"type,omitempty"`
+}
+
+// UpdateFeatureFlagRequest is the update feature flag request schema of the API
+type UpdateFeatureFlagRequest struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// UpdateInspectionRequest is the update inspection request schema of the API
+type UpdateInspectionRequest struct {
+	InspectedAt string          `json:"inspected_at,omitempty"`
+	Notes       string          `json:"notes,omitempty"`
+	Rooms       []InventoryRoom `json:"rooms,omitempty"`
+}
+
+// UpdatePhotoRequest is the update photo request schema of the API
+type UpdatePhotoRequest struct {
+	Caption string `json:"caption,omitempty"`
+}
+
+// UpdateUploadLimitRequest is the update upload limit request schema of the API
+type UpdateUploadLimitRequest struct {
+	MaxFileSizeMb int `json:"max_file_size_mb,omitempty"`
+	QuotaMb       int `json:"quota_mb,omitempty"`
+}
+
+// UploadLimit is the upload limit schema of the API
+type UploadLimit struct {
+	MaxFileSizeMb int       `json:"max_file_size_mb,omitempty"`
+	QuotaMb       int       `json:"quota_mb,omitempty"`
+	Role          string    `json:"role,omitempty"`
+	Source        string    `json:"source,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// UploadToken is the upload token schema of the API
+type UploadToken struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	PersonID  string    `json:"person_id,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	Used      bool      `json:"used,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+}
+
+// User is the user schema of the API
+type User struct {
+	CreatedAt                time.Time `json:"created_at,omitempty"`
+	Email                    string    `json:"email,omitempty"`
+	EmailPendingVerification bool      `json:"email_pending_verification,omitempty"`
+	ID                       string    `json:"id,omitempty"`
+	Locale                   string    `json:"locale,omitempty"`
+	PasswordBase64           string    `json:"password_base64,omitempty"`
+	PersonID                 string    `json:"person_id,omitempty"`
+	Role                     string    `json:"role,omitempty"`
+	Status                   string    `json:"status,omitempty"`
+}
+
+// UserBulkFilter is the user bulk filter schema of the API
+type UserBulkFilter struct {
+	CreatedFrom    time.Time `json:"created_from,omitempty"`
+	CreatedTo      time.Time `json:"created_to,omitempty"`
+	OrganizationID string    `json:"organization_id,omitempty"`
+	Role           string    `json:"role,omitempty"`
+	Status         string    `json:"status,omitempty"`
+}
+
+// UserBulkJob is the user bulk job schema of the API
+type UserBulkJob struct {
+	Action     string           `json:"action,omitempty"`
+	CreatedAt  time.Time        `json:"created_at,omitempty"`
+	CreatedBy  string           `json:"created_by,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Failed     int              `json:"failed,omitempty"`
+	Filter     UserBulkFilter   `json:"filter,omitempty"`
+	FinishedAt time.Time        `json:"finished_at,omitempty"`
+	ID         string           `json:"id,omitempty"`
+	Results    []UserBulkResult `json:"results,omitempty"`
+	Skipped    int              `json:"skipped,omitempty"`
+	StartedAt  time.Time        `json:"started_at,omitempty"`
+	Status     string           `json:"status,omitempty"`
+	Succeeded  int              `json:"succeeded,omitempty"`
+	Total      int              `json:"total,omitempty"`
+}
+
+// UserBulkRequest is the user bulk request schema of the API
+type UserBulkRequest struct {
+	Action string         `json:"action,omitempty"`
+	DryRun bool           `json:"dry_run,omitempty"`
+	Filter UserBulkFilter `json:"filter,omitempty"`
+}
+
+// UserBulkResult is the user bulk result schema of the API
+type UserBulkResult struct {
+	Email          string `json:"email,omitempty"`
+	Message        string `json:"message,omitempty"`
+	Outcome        string `json:"outcome,omitempty"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	UserID         string `json:"user_id,omitempty"`
+}
+
+// invitationResponse is the invitation response schema of the API
+type invitationResponse struct {
+	AcceptedAt     time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	Email          string    `json:"email,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	InvitedBy      string    `json:"invited_by,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	OrganizationID string    `json:"organization_id,omitempty"`
+	PersonID       string    `json:"person_id,omitempty"`
+	PropertyID     string    `json:"property_id,omitempty"`
+	RevokedAt      time.Time `json:"revoked_at,omitempty"`
+	Role           string    `json:"role,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+}
+
+// sessionResponse is the session response schema of the API
+type sessionResponse struct {
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	Current        bool      `json:"current,omitempty"`
+	Device         string    `json:"device,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	ImpersonatorID string    `json:"impersonator_id,omitempty"`
+	IpAddress      string    `json:"ip_address,omitempty"`
+	LastSeenAt     time.Time `json:"last_seen_at,omitempty"`
+	RevokedAt      time.Time `json:"revoked_at,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+}
+
+// BankAccountCreate create a new bank account (POST /bank-accounts).
+func (c *Client) BankAccountCreate(ctx context.Context, body BankAccount) (*BankAccount, error) {
+	var out BankAccount
+	if err := c.do(ctx, http.MethodPost, "/bank-accounts", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BankAccountDelete delete a bank account (DELETE /bank-accounts/{id}).
+func (c *Client) BankAccountDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/bank-accounts/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// BankAccountGetAll get all bank accounts (GET /bank-accounts).
+func (c *Client) BankAccountGetAll(ctx context.Context) ([]BankAccount, error) {
+	var out []BankAccount
+	if err := c.do(ctx, http.MethodGet, "/bank-accounts", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankAccountGetByID get bank account by ID (GET /bank-accounts/{id}).
+func (c *Client) BankAccountGetByID(ctx context.Context, id string) (*BankAccount, error) {
+	var out BankAccount
+	if err := c.do(ctx, http.MethodGet, "/bank-accounts/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BankAccountGetByPersonID get bank accounts by person ID (GET /bank-accounts/person/{personId}).
+func (c *Client) BankAccountGetByPersonID(ctx context.Context, personID string) ([]BankAccount, error) {
+	var out []BankAccount
+	if err := c.do(ctx, http.MethodGet, "/bank-accounts/person/"+url.PathEscape(personID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankAccountUpdate update a bank account (PUT /bank-accounts/{id}).
+func (c *Client) BankAccountUpdate(ctx context.Context, id string, body BankAccount) (*BankAccount, error) {
+	var out BankAccount
+	if err := c.do(ctx, http.MethodPut, "/bank-accounts/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BucketBackupGetBackup consultar backup del bucket (GET /admin/file-backups/{id}).
+func (c *Client) BucketBackupGetBackup(ctx context.Context, id string) (*BucketBackup, error) {
+	var out BucketBackup
+	if err := c.do(ctx, http.MethodGet, "/admin/file-backups/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BucketBackupListBackups listar backups del bucket (GET /admin/file-backups).
+func (c *Client) BucketBackupListBackups(ctx context.Context) ([]BucketBackup, error) {
+	var out []BucketBackup
+	if err := c.do(ctx, http.MethodGet, "/admin/file-backups", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BucketBackupRestoreBackup restaurar archivos de un backup (POST /admin/file-backups/{id}/restore).
+func (c *Client) BucketBackupRestoreBackup(ctx context.Context, id string, body RestoreBucketBackupRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/file-backups/"+url.PathEscape(id)+"/restore", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BucketBackupStartBackup iniciar backup del bucket (POST /admin/file-backups).
+func (c *Client) BucketBackupStartBackup(ctx context.Context, body StartBucketBackupRequest) (*BucketBackup, error) {
+	var out BucketBackup
+	if err := c.do(ctx, http.MethodPost, "/admin/file-backups", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildingAddProperty  (PUT /admin/buildings/{id}/properties/{propertyId}).
+func (c *Client) BuildingAddProperty(ctx context.Context, id string, propertyID string) error {
+	return c.do(ctx, http.MethodPut, "/admin/buildings/"+url.PathEscape(id)+"/properties/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// BuildingCreate create a building (POST /admin/buildings).
+func (c *Client) BuildingCreate(ctx context.Context, body BuildingRequest) (*Building, error) {
+	var out Building
+	if err := c.do(ctx, http.MethodPost, "/admin/buildings", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildingDelete  (DELETE /admin/buildings/{id}).
+func (c *Client) BuildingDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/buildings/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// BuildingGetAll  (GET /admin/buildings).
+func (c *Client) BuildingGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/buildings", nil, nil, nil)
+}
+
+// BuildingGetByID get a building (GET /admin/buildings/{id}).
+func (c *Client) BuildingGetByID(ctx context.Context, id string) (*BuildingResponse, error) {
+	var out BuildingResponse
+	if err := c.do(ctx, http.MethodGet, "/admin/buildings/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildingRemoveProperty  (DELETE /admin/buildings/{id}/properties/{propertyId}).
+func (c *Client) BuildingRemoveProperty(ctx context.Context, id string, propertyID string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/buildings/"+url.PathEscape(id)+"/properties/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// BuildingUpdate  (PUT /admin/buildings/{id}).
+func (c *Client) BuildingUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/buildings/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractCessionCreate  (POST /admin/contracts/{id}/cession).
+func (c *Client) ContractCessionCreate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/cession", nil, nil, nil)
+}
+
+// ContractCessionGetByRentalID  (GET /admin/contracts/{id}/cessions).
+func (c *Client) ContractCessionGetByRentalID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/cessions", nil, nil, nil)
+}
+
+// ContractCessionGetPaymentsByOwner  (GET /admin/contracts/{id}/owner-payments).
+func (c *Client) ContractCessionGetPaymentsByOwner(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/owner-payments", nil, nil, nil)
+}
+
+// ContractCessionServeLetter  (GET /admin/contract-cessions/{id}/letter).
+func (c *Client) ContractCessionServeLetter(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-cessions/"+url.PathEscape(id)+"/letter", nil, nil, nil)
+}
+
+// ContractGenerateContract  (POST /admin/contracts/generate).
+func (c *Client) ContractGenerateContract(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/contracts/generate", nil, nil, nil)
+}
+
+// ContractGetRentalChain  (GET /admin/contracts/{id}/chain).
+func (c *Client) ContractGetRentalChain(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/chain", nil, nil, nil)
+}
+
+// ContractRenewContract  (POST /admin/contracts/{id}/renew).
+func (c *Client) ContractRenewContract(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/renew", nil, nil, nil)
+}
+
+// ContractSigningCreateSigningRequest  (POST /admin/contract-signing/request).
+func (c *Client) ContractSigningCreateSigningRequest(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/contract-signing/request", nil, nil, nil)
+}
+
+// ContractSigningGetSigningEvents  (GET /admin/contract-signing/{id}/events).
+func (c *Client) ContractSigningGetSigningEvents(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-signing/"+url.PathEscape(id)+"/events", nil, nil, nil)
+}
+
+// ContractSigningGetSigningStatus  (GET /contract-signing/status/{id}). It does not require authentication.
+func (c *Client) ContractSigningGetSigningStatus(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/contract-signing/status/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningGetSigningStatus2  (GET /public/contract-signing/status/{id}). It does not require authentication.
+func (c *Client) ContractSigningGetSigningStatus2(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/public/contract-signing/status/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningProviderWebhook  (POST /public/esign/webhook/{provider}). It does not require authentication.
+func (c *Client) ContractSigningProviderWebhook(ctx context.Context, provider string) error {
+	return c.do(ctx, http.MethodPost, "/public/esign/webhook/"+url.PathEscape(provider), nil, nil, nil)
+}
+
+// ContractSigningRejectContract  (POST /contract-signing/reject/{id}). It does not require authentication.
+func (c *Client) ContractSigningRejectContract(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/contract-signing/reject/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningRejectContract2  (POST /public/contract-signing/reject/{id}). It does not require authentication.
+func (c *Client) ContractSigningRejectContract2(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/public/contract-signing/reject/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningSendSigningOTP  (POST /public/contract-signing/otp/{id}). It does not require authentication.
+func (c *Client) ContractSigningSendSigningOTP(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/public/contract-signing/otp/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningServePDF  (GET /contract-signing/pdf/{id}). It does not require authentication.
+func (c *Client) ContractSigningServePDF(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/contract-signing/pdf/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningServePDF2  (GET /public/contract-signing/pdf/{id}). It does not require authentication.
+func (c *Client) ContractSigningServePDF2(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/public/contract-signing/pdf/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningSignContract  (POST /contract-signing/sign/{id}). It does not require authentication.
+func (c *Client) ContractSigningSignContract(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/contract-signing/sign/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningSignContract2  (POST /public/contract-signing/sign/{id}). It does not require authentication.
+func (c *Client) ContractSigningSignContract2(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/public/contract-signing/sign/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningVerifySignature  (GET /public/contract-signing/verify/{id}). It does not require authentication.
+func (c *Client) ContractSigningVerifySignature(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/public/contract-signing/verify/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractSigningVerifySignedPDF  (POST /public/contract-signing/verify). It does not require authentication.
+func (c *Client) ContractSigningVerifySignedPDF(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/public/contract-signing/verify", nil, nil, nil)
+}
+
+// ContractTemplateCreate  (POST /admin/contract-templates).
+func (c *Client) ContractTemplateCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/contract-templates", nil, nil, nil)
+}
+
+// ContractTemplateDelete  (DELETE /admin/contract-templates/{id}).
+func (c *Client) ContractTemplateDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/contract-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractTemplateGetAll  (GET /admin/contract-templates).
+func (c *Client) ContractTemplateGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-templates", nil, nil, nil)
+}
+
+// ContractTemplateGetByID  (GET /admin/contract-templates/{id}).
+func (c *Client) ContractTemplateGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractTemplateGetPlaceholders  (GET /admin/contract-templates/placeholders).
+func (c *Client) ContractTemplateGetPlaceholders(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-templates/placeholders", nil, nil, nil)
+}
+
+// ContractTemplatePreview  (GET /admin/contract-templates/{id}/preview).
+func (c *Client) ContractTemplatePreview(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-templates/"+url.PathEscape(id)+"/preview", nil, nil, nil)
+}
+
+// ContractTemplateUpdate  (PUT /admin/contract-templates/{id}).
+func (c *Client) ContractTemplateUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/contract-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ContractTransferContract  (POST /admin/contracts/{id}/transfer).
+func (c *Client) ContractTransferContract(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/transfer", nil, nil, nil)
+}
+
+// DashboardGetSummary dashboard summary (GET /dashboard/summary).
+func (c *Client) DashboardGetSummary(ctx context.Context) (*DashboardSummary, error) {
+	var out DashboardSummary
+	if err := c.do(ctx, http.MethodGet, "/dashboard/summary", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EInvoiceRefresh refresh the status of the electronic invoice of a payment (POST /admin/payments/{id}/einvoice/refresh).
+func (c *Client) EInvoiceRefresh(ctx context.Context, id string) (*RentPayment, error) {
+	var out RentPayment
+	if err := c.do(ctx, http.MethodPost, "/admin/payments/"+url.PathEscape(id)+"/einvoice/refresh", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EInvoiceSubmit issue the DIAN electronic invoice of a payment (POST /admin/payments/{id}/einvoice).
+func (c *Client) EInvoiceSubmit(ctx context.Context, id string) (*RentPayment, error) {
+	var out RentPayment
+	if err := c.do(ctx, http.MethodPost, "/admin/payments/"+url.PathEscape(id)+"/einvoice", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailEmailHealthParams are the query parameters of EmailEmailHealth
+type EmailEmailHealthParams struct {
+	Driver string // smtp, resend, ses or mailgun
+}
+
+// EmailEmailHealth email driver health (GET /admin/email/health).
+func (c *Client) EmailEmailHealth(ctx context.Context, params *EmailEmailHealthParams) (*interface{}, error) {
+	var out interface{}
+	query := url.Values{}
+	if params != nil {
+		if params.Driver != "" {
+			query.Set("driver", params.Driver)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/email/health", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailSendCustomEmail  (POST /admin/emails/custom).
+func (c *Client) EmailSendCustomEmail(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/emails/custom", nil, nil, nil)
+}
+
+// EmailSendTestEmail send test email (POST /admin/email/test).
+func (c *Client) EmailSendTestEmail(ctx context.Context, body EmailTestRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/email/test", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailTemplateGetTemplate get email template (GET /admin/email-templates/{key}).
+func (c *Client) EmailTemplateGetTemplate(ctx context.Context, key string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/admin/email-templates/"+url.PathEscape(key), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailTemplateListTemplates list email templates (GET /admin/email-templates).
+func (c *Client) EmailTemplateListTemplates(ctx context.Context) ([]interface{}, error) {
+	var out []interface{}
+	if err := c.do(ctx, http.MethodGet, "/admin/email-templates", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmailTemplatePreviewTemplate preview email template (POST /admin/email-templates/{key}/preview).
+func (c *Client) EmailTemplatePreviewTemplate(ctx context.Context, key string, body EmailTemplatePreviewRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/email-templates/"+url.PathEscape(key)+"/preview", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailTemplateResetTemplate reset email template (DELETE /admin/email-templates/{key}).
+func (c *Client) EmailTemplateResetTemplate(ctx context.Context, key string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodDelete, "/admin/email-templates/"+url.PathEscape(key), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailTemplateSaveTemplate save email template (PUT /admin/email-templates/{key}).
+func (c *Client) EmailTemplateSaveTemplate(ctx context.Context, key string, body EmailTemplateRequest) (*EmailTemplate, error) {
+	var out EmailTemplate
+	if err := c.do(ctx, http.MethodPut, "/admin/email-templates/"+url.PathEscape(key), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EmailTrackingTrackOpen  (GET /public/email/open/{file}). It does not require authentication.
+func (c *Client) EmailTrackingTrackOpen(ctx context.Context, file string) error {
+	return c.do(ctx, http.MethodGet, "/public/email/open/"+url.PathEscape(file), nil, nil, nil)
+}
+
+// EmailTriggerAnnualRenewalReminders  (POST /admin/emails/annual-renewal-reminders).
+func (c *Client) EmailTriggerAnnualRenewalReminders(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/emails/annual-renewal-reminders", nil, nil, nil)
+}
+
+// FeatureFlagListFeatureFlags list feature flags (GET /admin/feature-flags).
+func (c *Client) FeatureFlagListFeatureFlags(ctx context.Context) ([]FeatureFlag, error) {
+	var out []FeatureFlag
+	if err := c.do(ctx, http.MethodGet, "/admin/feature-flags", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FeatureFlagResetFeatureFlag reset a feature flag (DELETE /admin/feature-flags/{key}).
+func (c *Client) FeatureFlagResetFeatureFlag(ctx context.Context, key string) (*FeatureFlag, error) {
+	var out FeatureFlag
+	if err := c.do(ctx, http.MethodDelete, "/admin/feature-flags/"+url.PathEscape(key), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FeatureFlagUpdateFeatureFlag toggle a feature flag (PUT /admin/feature-flags/{key}).
+func (c *Client) FeatureFlagUpdateFeatureFlag(ctx context.Context, key string, body UpdateFeatureFlagRequest) (*FeatureFlag, error) {
+	var out FeatureFlag
+	if err := c.do(ctx, http.MethodPut, "/admin/feature-flags/"+url.PathEscape(key), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadAbortChunkedUpload cancelar subida por partes (DELETE /upload/chunked-authenticated/{id}).
+func (c *Client) FileUploadAbortChunkedUpload(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/upload/chunked-authenticated/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// FileUploadAbortChunkedUpload2 cancelar subida por partes (DELETE /upload/chunked/{id}). It does not require authentication.
+func (c *Client) FileUploadAbortChunkedUpload2(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/upload/chunked/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// FileUploadCompleteChunkedUpload completar subida por partes (POST /upload/chunked-authenticated/{id}/complete).
+func (c *Client) FileUploadCompleteChunkedUpload(ctx context.Context, id string) (*SupabaseUploadResponse, error) {
+	var out SupabaseUploadResponse
+	if err := c.do(ctx, http.MethodPost, "/upload/chunked-authenticated/"+url.PathEscape(id)+"/complete", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadCompleteChunkedUpload2 completar subida por partes (POST /upload/chunked/{id}/complete). It does not require authentication.
+func (c *Client) FileUploadCompleteChunkedUpload2(ctx context.Context, id string) (*SupabaseUploadResponse, error) {
+	var out SupabaseUploadResponse
+	if err := c.do(ctx, http.MethodPost, "/upload/chunked/"+url.PathEscape(id)+"/complete", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadCreateChunkedUpload iniciar subida por partes (POST /upload/chunked). It does not require authentication.
+func (c *Client) FileUploadCreateChunkedUpload(ctx context.Context, body CreateChunkedUploadRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/upload/chunked", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadCreateChunkedUpload2 iniciar subida por partes (POST /upload/chunked-authenticated).
+func (c *Client) FileUploadCreateChunkedUpload2(ctx context.Context, body CreateChunkedUploadRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/upload/chunked-authenticated", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadDeleteFile eliminar archivo (DELETE /admin/file-upload/files/{filePath}).
+func (c *Client) FileUploadDeleteFile(ctx context.Context, filePath string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodDelete, "/admin/file-upload/files/"+url.PathEscape(filePath), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadFileAction  (POST /admin/file-upload/files/{filePath}).
+func (c *Client) FileUploadFileAction(ctx context.Context, filePath string) error {
+	return c.do(ctx, http.MethodPost, "/admin/file-upload/files/"+url.PathEscape(filePath), nil, nil, nil)
+}
+
+// FileUploadGenerateUploadLink generar enlace de subida (POST /admin/file-upload/generate-link).
+func (c *Client) FileUploadGenerateUploadLink(ctx context.Context, body GenerateUploadLinkRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/file-upload/generate-link", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadGetChunkedUpload consultar subida por partes (GET /upload/chunked-authenticated/{id}).
+func (c *Client) FileUploadGetChunkedUpload(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/upload/chunked-authenticated/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadGetChunkedUpload2 consultar subida por partes (HEAD /upload/chunked-authenticated/{id}).
+func (c *Client) FileUploadGetChunkedUpload2(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodHead, "/upload/chunked-authenticated/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadGetChunkedUpload3 consultar subida por partes (GET /upload/chunked/{id}). It does not require authentication.
+func (c *Client) FileUploadGetChunkedUpload3(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/upload/chunked/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadGetChunkedUpload4 consultar subida por partes (HEAD /upload/chunked/{id}). It does not require authentication.
+func (c *Client) FileUploadGetChunkedUpload4(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodHead, "/upload/chunked/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadGetMyStorageUsage consultar uso de almacenamiento (GET /upload/usage).
+func (c *Client) FileUploadGetMyStorageUsage(ctx context.Context) (*StorageUsage, error) {
+	var out StorageUsage
+	if err := c.do(ctx, http.MethodGet, "/upload/usage", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadListFileScansParams are the query parameters of FileUploadListFileScans
+type FileUploadListFileScansParams struct {
+	Result string // clean, infected o error
+}
+
+// FileUploadListFileScans listar análisis de virus (GET /admin/file-upload/scans).
+func (c *Client) FileUploadListFileScans(ctx context.Context, params *FileUploadListFileScansParams) ([]FileScan, error) {
+	var out []FileScan
+	query := url.Values{}
+	if params != nil {
+		if params.Result != "" {
+			query.Set("result", params.Result)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/scans", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListRentalFiles listar documentos de un arriendo (GET /rentals/{id}/files).
+func (c *Client) FileUploadListRentalFiles(ctx context.Context, id string) ([]FileMetadata, error) {
+	var out []FileMetadata
+	if err := c.do(ctx, http.MethodGet, "/rentals/"+url.PathEscape(id)+"/files", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListTrash listar papelera (GET /admin/file-upload/trash).
+func (c *Client) FileUploadListTrash(ctx context.Context) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/trash", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadListUploadLimits listar límites de subida (GET /admin/file-upload/limits).
+func (c *Client) FileUploadListUploadLimits(ctx context.Context) ([]UploadLimit, error) {
+	var out []UploadLimit
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/limits", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListUploadTokens listar tokens de subida (GET /admin/file-upload/tokens).
+func (c *Client) FileUploadListUploadTokens(ctx context.Context) ([]UploadToken, error) {
+	var out []UploadToken
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/tokens", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListUploadedFilesParams are the query parameters of FileUploadListUploadedFiles
+type FileUploadListUploadedFilesParams struct {
+	Category string   // Categoría del documento
+	Tag      []string // Etiquetas que debe tener el archivo
+	Limit    int      // Cantidad máxima de archivos
+	Offset   int      // Archivos a omitir
+}
+
+// FileUploadListUploadedFiles listar archivos subidos (GET /admin/file-upload/files).
+func (c *Client) FileUploadListUploadedFiles(ctx context.Context, params *FileUploadListUploadedFilesParams) ([]SupabaseFileInfo, error) {
+	var out []SupabaseFileInfo
+	query := url.Values{}
+	if params != nil {
+		if params.Category != "" {
+			query.Set("category", params.Category)
+		}
+		for _, value := range params.Tag {
+			query.Add("tag", value)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/files", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListUploadedFilesIter iterates every item of FileUploadListUploadedFiles, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) FileUploadListUploadedFilesIter(ctx context.Context, params *FileUploadListUploadedFilesParams, pageSize int) iter.Seq2[SupabaseFileInfo, error] {
+	var page FileUploadListUploadedFilesParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]SupabaseFileInfo, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.FileUploadListUploadedFiles(ctx, &page)
+	})
+}
+
+// FileUploadListUserFilesParams are the query parameters of FileUploadListUserFiles
+type FileUploadListUserFilesParams struct {
+	Category string   // Categoría del documento
+	Tag      []string // Etiquetas que debe tener el archivo
+	Limit    int      // Cantidad máxima de archivos
+	Offset   int      // Archivos a omitir
+}
+
+// FileUploadListUserFiles listar archivos de un usuario (GET /admin/file-upload/files/{userID}).
+func (c *Client) FileUploadListUserFiles(ctx context.Context, userID string, params *FileUploadListUserFilesParams) ([]SupabaseFileInfo, error) {
+	var out []SupabaseFileInfo
+	query := url.Values{}
+	if params != nil {
+		if params.Category != "" {
+			query.Set("category", params.Category)
+		}
+		for _, value := range params.Tag {
+			query.Add("tag", value)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/file-upload/files/"+url.PathEscape(userID), query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileUploadListUserFilesIter iterates every item of FileUploadListUserFiles, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) FileUploadListUserFilesIter(ctx context.Context, userID string, params *FileUploadListUserFilesParams, pageSize int) iter.Seq2[SupabaseFileInfo, error] {
+	var page FileUploadListUserFilesParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]SupabaseFileInfo, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.FileUploadListUserFiles(ctx, userID, &page)
+	})
+}
+
+// FileUploadPurgeTrashedFile purgar archivo (DELETE /admin/file-upload/trash/{id}).
+func (c *Client) FileUploadPurgeTrashedFile(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodDelete, "/admin/file-upload/trash/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadResetUploadLimit restablecer límites de subida (DELETE /admin/file-upload/limits/{role}).
+func (c *Client) FileUploadResetUploadLimit(ctx context.Context, role string) (*UploadLimit, error) {
+	var out UploadLimit
+	if err := c.do(ctx, http.MethodDelete, "/admin/file-upload/limits/"+url.PathEscape(role), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadRestoreTrashedFile restaurar archivo (POST /admin/file-upload/trash/{id}/restore).
+func (c *Client) FileUploadRestoreTrashedFile(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/file-upload/trash/"+url.PathEscape(id)+"/restore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadUpdateUploadLimit definir límites de subida (PUT /admin/file-upload/limits/{role}).
+func (c *Client) FileUploadUpdateUploadLimit(ctx context.Context, role string, body UpdateUploadLimitRequest) (*UploadLimit, error) {
+	var out UploadLimit
+	if err := c.do(ctx, http.MethodPut, "/admin/file-upload/limits/"+url.PathEscape(role), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadUploadChunk subir parte (PATCH /upload/chunked-authenticated/{id}).
+func (c *Client) FileUploadUploadChunk(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPatch, "/upload/chunked-authenticated/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadUploadChunk2 subir parte (PATCH /upload/chunked/{id}). It does not require authentication.
+func (c *Client) FileUploadUploadChunk2(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPatch, "/upload/chunked/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FileUploadValidateToken validar token de subida (GET /upload/validate-token/{token}). It does not require authentication.
+func (c *Client) FileUploadValidateToken(ctx context.Context, token string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/upload/validate-token/"+url.PathEscape(token), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCapabilitiesFunc1 enabled capabilities (GET /capabilities). It does not require authentication.
+func (c *Client) GetCapabilitiesFunc1(ctx context.Context) (*Capabilities, error) {
+	var out Capabilities
+	if err := c.do(ctx, http.MethodGet, "/capabilities", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDeprecatedRouteUsage  (GET /admin/deprecated-routes).
+func (c *Client) GetDeprecatedRouteUsage(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/deprecated-routes", nil, nil, nil)
+}
+
+// GetHealthFunc1 liveness and dependency status (GET /health). It does not require authentication.
+func (c *Client) GetHealthFunc1(ctx context.Context) (*HealthReport, error) {
+	var out HealthReport
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GraphQLQuery graphQL query (POST /graphql).
+func (c *Client) GraphQLQuery(ctx context.Context, body GraphQLRequest) (*GraphQLResponse, error) {
+	var out GraphQLResponse
+	if err := c.do(ctx, http.MethodPost, "/graphql", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GuaranteeGetByID  (GET /guarantee-studies/{id}).
+func (c *Client) GuaranteeGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/guarantee-studies/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GuaranteeGetByRenterID  (GET /guarantee-studies/renter/{renterId}).
+func (c *Client) GuaranteeGetByRenterID(ctx context.Context, renterID string) error {
+	return c.do(ctx, http.MethodGet, "/guarantee-studies/renter/"+url.PathEscape(renterID), nil, nil, nil)
+}
+
+// GuaranteeRefresh  (POST /admin/guarantee-studies/{id}/refresh).
+func (c *Client) GuaranteeRefresh(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/guarantee-studies/"+url.PathEscape(id)+"/refresh", nil, nil, nil)
+}
+
+// GuaranteeSubmit  (POST /admin/guarantee-studies).
+func (c *Client) GuaranteeSubmit(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/guarantee-studies", nil, nil, nil)
+}
+
+// ImpersonationGetAuditLogParams are the query parameters of ImpersonationGetAuditLog
+type ImpersonationGetAuditLogParams struct {
+	ChangedBy      string // User the actions were made as
+	ImpersonatedBy string // Admin who impersonated the user
+	Impersonated   bool   // Only the impersonated actions
+	Hours          int    // Hours back, 24 by default and 720 at most
+	Limit          int    // Maximum entries, 100 by default and 500 at most
+}
+
+// ImpersonationGetAuditLog recent audit log entries (GET /admin/audit-log).
+func (c *Client) ImpersonationGetAuditLog(ctx context.Context, params *ImpersonationGetAuditLogParams) ([]AuditLog, error) {
+	var out []AuditLog
+	query := url.Values{}
+	if params != nil {
+		if params.ChangedBy != "" {
+			query.Set("changed_by", params.ChangedBy)
+		}
+		if params.ImpersonatedBy != "" {
+			query.Set("impersonated_by", params.ImpersonatedBy)
+		}
+		if params.Impersonated {
+			query.Set("impersonated", "true")
+		}
+		if params.Hours != 0 {
+			query.Set("hours", strconv.Itoa(params.Hours))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/audit-log", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImpersonationImpersonate impersonate a user (POST /admin/impersonate/{userId}).
+func (c *Client) ImpersonationImpersonate(ctx context.Context, userID string, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/impersonate/"+url.PathEscape(userID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InspectionCreate start an inspection of a rental (POST /admin/contracts/{id}/inspections).
+func (c *Client) InspectionCreate(ctx context.Context, id string, body CreateInspectionRequest) (*Inspection, error) {
+	var out Inspection
+	if err := c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/inspections", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InspectionCreateTemplate  (POST /admin/inspection-templates).
+func (c *Client) InspectionCreateTemplate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/inspection-templates", nil, nil, nil)
+}
+
+// InspectionDelete  (DELETE /admin/inspections/{id}).
+func (c *Client) InspectionDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/inspections/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// InspectionDeleteTemplate  (DELETE /admin/inspection-templates/{id}).
+func (c *Client) InspectionDeleteTemplate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/inspection-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// InspectionGetByID  (GET /admin/inspections/{id}).
+func (c *Client) InspectionGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/inspections/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// InspectionGetByRentalID list the inspections of a rental (GET /admin/contracts/{id}/inspections).
+func (c *Client) InspectionGetByRentalID(ctx context.Context, id string) ([]Inspection, error) {
+	var out []Inspection
+	if err := c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/inspections", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InspectionGetComparison compare the move-in and move-out inspections of a rental (GET /admin/contracts/{id}/inspections/comparison).
+func (c *Client) InspectionGetComparison(ctx context.Context, id string) (*InspectionComparison, error) {
+	var out InspectionComparison
+	if err := c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/inspections/comparison", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InspectionGetTemplates  (GET /admin/inspection-templates).
+func (c *Client) InspectionGetTemplates(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/inspection-templates", nil, nil, nil)
+}
+
+// InspectionRequestAcknowledgment send an inspection to the tenant to sign (POST /admin/inspections/{id}/acknowledgment).
+func (c *Client) InspectionRequestAcknowledgment(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/inspections/"+url.PathEscape(id)+"/acknowledgment", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InspectionServeComparisonPDF  (GET /admin/contracts/{id}/inspections/comparison/pdf).
+func (c *Client) InspectionServeComparisonPDF(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/inspections/comparison/pdf", nil, nil, nil)
+}
+
+// InspectionServePDF  (GET /admin/inspections/{id}/pdf).
+func (c *Client) InspectionServePDF(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/inspections/"+url.PathEscape(id)+"/pdf", nil, nil, nil)
+}
+
+// InspectionUpdate update an inspection (PUT /admin/inspections/{id}).
+func (c *Client) InspectionUpdate(ctx context.Context, id string, body UpdateInspectionRequest) (*Inspection, error) {
+	var out Inspection
+	if err := c.do(ctx, http.MethodPut, "/admin/inspections/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InspectionUpdateTemplate  (PUT /admin/inspection-templates/{id}).
+func (c *Client) InspectionUpdateTemplate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/inspection-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// InspectionUploadPhoto  (POST /admin/inspections/{id}/photos).
+func (c *Client) InspectionUploadPhoto(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/inspections/"+url.PathEscape(id)+"/photos", nil, nil, nil)
+}
+
+// InventoryDelete  (DELETE /admin/inventories/property/{propertyId}).
+func (c *Client) InventoryDelete(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/inventories/property/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// InventoryGetByPropertyID  (GET /inventories/property/{propertyId}).
+func (c *Client) InventoryGetByPropertyID(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodGet, "/inventories/property/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// InventoryGetConditions  (GET /admin/inventories/conditions).
+func (c *Client) InventoryGetConditions(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/inventories/conditions", nil, nil, nil)
+}
+
+// InventoryPreviewAnnex  (GET /admin/inventories/property/{propertyId}/annex).
+func (c *Client) InventoryPreviewAnnex(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodGet, "/admin/inventories/property/"+url.PathEscape(propertyID)+"/annex", nil, nil, nil)
+}
+
+// InventorySave  (PUT /admin/inventories/property/{propertyId}).
+func (c *Client) InventorySave(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodPut, "/admin/inventories/property/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// InventoryUploadPhoto  (POST /admin/inventories/property/{propertyId}/photos).
+func (c *Client) InventoryUploadPhoto(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodPost, "/admin/inventories/property/"+url.PathEscape(propertyID)+"/photos", nil, nil, nil)
+}
+
+// InvitationAccept accept an invitation (POST /invitations/accept). It does not require authentication.
+func (c *Client) InvitationAccept(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/invitations/accept", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InvitationGetAll list invitations (GET /admin/invitations).
+func (c *Client) InvitationGetAll(ctx context.Context) ([]invitationResponse, error) {
+	var out []invitationResponse
+	if err := c.do(ctx, http.MethodGet, "/admin/invitations", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvitationGetByToken open an invitation link (GET /invitations/{token}). It does not require authentication.
+func (c *Client) InvitationGetByToken(ctx context.Context, token string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/invitations/"+url.PathEscape(token), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InvitationRevoke revoke an invitation (DELETE /admin/invitations/{id}).
+func (c *Client) InvitationRevoke(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/invitations/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// InvitationSend send an invitation (POST /admin/invitations).
+func (c *Client) InvitationSend(ctx context.Context, body InvitationInput) (*Invitation, error) {
+	var out Invitation
+	if err := c.do(ctx, http.MethodPost, "/admin/invitations", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListingApply apply to rent an available property (POST /public/listings/{id}/applications). It does not require authentication.
+func (c *Client) ListingApply(ctx context.Context, id string, body ListingApplicationRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/public/listings/"+url.PathEscape(id)+"/applications", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListingDelete  (DELETE /admin/listings/{id}).
+func (c *Client) ListingDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/listings/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListingDeletePhoto  (DELETE /admin/listings/{id}/photos).
+func (c *Client) ListingDeletePhoto(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/listings/"+url.PathEscape(id)+"/photos", nil, nil, nil)
+}
+
+// ListingGetAll  (GET /admin/listings).
+func (c *Client) ListingGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/listings", nil, nil, nil)
+}
+
+// ListingGetApplications  (GET /admin/listings/{id}/applications).
+func (c *Client) ListingGetApplications(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/listings/"+url.PathEscape(id)+"/applications", nil, nil, nil)
+}
+
+// ListingGetByID  (GET /admin/listings/{id}).
+func (c *Client) ListingGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/listings/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListingGetPublicListing  (GET /public/listings/{id}). It does not require authentication.
+func (c *Client) ListingGetPublicListing(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/public/listings/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListingGetPublicListingsParams are the query parameters of ListingGetPublicListings
+type ListingGetPublicListingsParams struct {
+	City     string  // City, case insensitive
+	MinPrice float64 // Minimum monthly rent
+	MaxPrice float64 // Maximum monthly rent
+	Limit    int     // Page size
+	Offset   int     // Page offset
+}
+
+// ListingGetPublicListings list the available properties (GET /public/listings). It does not require authentication.
+func (c *Client) ListingGetPublicListings(ctx context.Context, params *ListingGetPublicListingsParams) ([]PublicListing, error) {
+	var out []PublicListing
+	query := url.Values{}
+	if params != nil {
+		if params.City != "" {
+			query.Set("city", params.City)
+		}
+		if params.MinPrice != 0 {
+			query.Set("min_price", strconv.FormatFloat(params.MinPrice, 'f', -1, 64))
+		}
+		if params.MaxPrice != 0 {
+			query.Set("max_price", strconv.FormatFloat(params.MaxPrice, 'f', -1, 64))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/public/listings", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListingGetPublicListingsIter iterates every item of ListingGetPublicListings, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) ListingGetPublicListingsIter(ctx context.Context, params *ListingGetPublicListingsParams, pageSize int) iter.Seq2[PublicListing, error] {
+	var page ListingGetPublicListingsParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]PublicListing, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.ListingGetPublicListings(ctx, &page)
+	})
+}
+
+// ListingSave create or update the listing of a property (PUT /admin/listings/property/{propertyId}).
+func (c *Client) ListingSave(ctx context.Context, propertyID string, body ListingRequest) (*PropertyListing, error) {
+	var out PropertyListing
+	if err := c.do(ctx, http.MethodPut, "/admin/listings/property/"+url.PathEscape(propertyID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListingUpdateApplication  (PUT /admin/listing-applications/{id}).
+func (c *Client) ListingUpdateApplication(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/listing-applications/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListingUpdateStatus change the status of a listing (PUT /admin/listings/{id}/status).
+func (c *Client) ListingUpdateStatus(ctx context.Context, id string, body ListingStatusRequest) (*PropertyListing, error) {
+	var out PropertyListing
+	if err := c.do(ctx, http.MethodPut, "/admin/listings/"+url.PathEscape(id)+"/status", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListingUploadPhoto  (POST /admin/listings/{id}/photos).
+func (c *Client) ListingUploadPhoto(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/listings/"+url.PathEscape(id)+"/photos", nil, nil, nil)
+}
+
+// MaintenanceRequestAddComment  (POST /maintenance-requests/{id}/comments).
+func (c *Client) MaintenanceRequestAddComment(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/maintenance-requests/"+url.PathEscape(id)+"/comments", nil, nil, nil)
+}
+
+// MaintenanceRequestAssignProvider  (PUT /admin/maintenance-requests/{id}/assign).
+func (c *Client) MaintenanceRequestAssignProvider(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/maintenance-requests/"+url.PathEscape(id)+"/assign", nil, nil, nil)
+}
+
+// MaintenanceRequestCreate  (POST /maintenance-requests).
+func (c *Client) MaintenanceRequestCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/maintenance-requests", nil, nil, nil)
+}
+
+// MaintenanceRequestDelete  (DELETE /admin/maintenance-requests/{id}).
+func (c *Client) MaintenanceRequestDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/maintenance-requests/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// MaintenanceRequestGetAll  (GET /maintenance-requests).
+func (c *Client) MaintenanceRequestGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests", nil, nil, nil)
+}
+
+// MaintenanceRequestGetByID  (GET /maintenance-requests/{id}).
+func (c *Client) MaintenanceRequestGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// MaintenanceRequestGetByPropertyID  (GET /maintenance-requests/property/{propertyId}).
+func (c *Client) MaintenanceRequestGetByPropertyID(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/property/"+url.PathEscape(propertyID), nil, nil, nil)
+}
+
+// MaintenanceRequestGetByPropertyIDs  (POST /maintenance-requests/property-ids).
+func (c *Client) MaintenanceRequestGetByPropertyIDs(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/maintenance-requests/property-ids", nil, nil, nil)
+}
+
+// MaintenanceRequestGetByRenterID  (GET /maintenance-requests/renter/{renterId}).
+func (c *Client) MaintenanceRequestGetByRenterID(ctx context.Context, renterID string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/renter/"+url.PathEscape(renterID), nil, nil, nil)
+}
+
+// MaintenanceRequestGetByStatus  (GET /maintenance-requests/status/{status}).
+func (c *Client) MaintenanceRequestGetByStatus(ctx context.Context, status string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/status/"+url.PathEscape(status), nil, nil, nil)
+}
+
+// MaintenanceRequestGetComments  (GET /maintenance-requests/{id}/comments).
+func (c *Client) MaintenanceRequestGetComments(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/"+url.PathEscape(id)+"/comments", nil, nil, nil)
+}
+
+// MaintenanceRequestGetStatusHistory  (GET /maintenance-requests/{id}/history).
+func (c *Client) MaintenanceRequestGetStatusHistory(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/maintenance-requests/"+url.PathEscape(id)+"/history", nil, nil, nil)
+}
+
+// MaintenanceRequestUnassignProvider  (DELETE /admin/maintenance-requests/{id}/assign).
+func (c *Client) MaintenanceRequestUnassignProvider(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/maintenance-requests/"+url.PathEscape(id)+"/assign", nil, nil, nil)
+}
+
+// MaintenanceRequestUpdate  (PUT /admin/maintenance-requests/{id}).
+func (c *Client) MaintenanceRequestUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/maintenance-requests/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ManagerDigestGetMine  (GET /digest-subscription/me).
+func (c *Client) ManagerDigestGetMine(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/digest-subscription/me", nil, nil, nil)
+}
+
+// ManagerDigestPreviewMine  (GET /digest-subscription/me/preview).
+func (c *Client) ManagerDigestPreviewMine(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/digest-subscription/me/preview", nil, nil, nil)
+}
+
+// ManagerDigestSendDue  (POST /admin/digests/send).
+func (c *Client) ManagerDigestSendDue(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/digests/send", nil, nil, nil)
+}
+
+// ManagerDigestUpdateMine  (PUT /digest-subscription/me).
+func (c *Client) ManagerDigestUpdateMine(ctx context.Context) error {
+	return c.do(ctx, http.MethodPut, "/digest-subscription/me", nil, nil, nil)
+}
+
+// ManagerInvitationSendInvitation send invitation to a manager (POST /admin/invitations/manager).
+func (c *Client) ManagerInvitationSendInvitation(ctx context.Context, body InvitationRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/invitations/manager", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ManagerRegistrationRegister register a new manager (POST /register/manager).
+func (c *Client) ManagerRegistrationRegister(ctx context.Context, body ManagerRegistrationRequest) (*ManagerRegistrationResponse, error) {
+	var out ManagerRegistrationResponse
+	if err := c.do(ctx, http.MethodPost, "/register/manager", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotaryGetDossier  (GET /admin/contract-signing/{id}/dossier).
+func (c *Client) NotaryGetDossier(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/contract-signing/"+url.PathEscape(id)+"/dossier", nil, nil, nil)
+}
+
+// NotaryServeStampedPDF  (GET /admin/notarizations/{id}/pdf).
+func (c *Client) NotaryServeStampedPDF(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/notarizations/"+url.PathEscape(id)+"/pdf", nil, nil, nil)
+}
+
+// NotarySubmit  (POST /admin/contract-signing/{id}/notarize).
+func (c *Client) NotarySubmit(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/contract-signing/"+url.PathEscape(id)+"/notarize", nil, nil, nil)
+}
+
+// NotaryWebhook  (POST /public/notary/webhook). It does not require authentication.
+func (c *Client) NotaryWebhook(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/public/notary/webhook", nil, nil, nil)
+}
+
+// NotificationGetMineParams are the query parameters of NotificationGetMine
+type NotificationGetMineParams struct {
+	Unread bool // Only the unread notifications
+	Limit  int  // Maximum notifications, 20 by default and 100 at most
+	Offset int  // Notifications to skip
+}
+
+// NotificationGetMine list my notifications (GET /notifications).
+func (c *Client) NotificationGetMine(ctx context.Context, params *NotificationGetMineParams) ([]Notification, error) {
+	var out []Notification
+	query := url.Values{}
+	if params != nil {
+		if params.Unread {
+			query.Set("unread", "true")
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/notifications", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationGetMineIter iterates every item of NotificationGetMine, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) NotificationGetMineIter(ctx context.Context, params *NotificationGetMineParams, pageSize int) iter.Seq2[Notification, error] {
+	var page NotificationGetMineParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]Notification, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.NotificationGetMine(ctx, &page)
+	})
+}
+
+// NotificationGetUnreadCount count my unread notifications (GET /notifications/unread-count).
+func (c *Client) NotificationGetUnreadCount(ctx context.Context) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/notifications/unread-count", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationMarkAllRead mark all my notifications as read (PUT /notifications/read-all).
+func (c *Client) NotificationMarkAllRead(ctx context.Context) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/notifications/read-all", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationMarkRead mark a notification as read (PUT /notifications/{id}/read).
+func (c *Client) NotificationMarkRead(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/notifications/"+url.PathEscape(id)+"/read", nil, nil, nil)
+}
+
+// NotificationPreferenceGetByPersonID get notification preferences of a person (GET /admin/notification-preferences/{personId}).
+func (c *Client) NotificationPreferenceGetByPersonID(ctx context.Context, personID string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/admin/notification-preferences/"+url.PathEscape(personID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceGetByToken get notification preferences from an email link (GET /public/notification-preferences/{token}). It does not require authentication.
+func (c *Client) NotificationPreferenceGetByToken(ctx context.Context, token string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/public/notification-preferences/"+url.PathEscape(token), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceGetMine get my notification preferences (GET /notification-preferences/me).
+func (c *Client) NotificationPreferenceGetMine(ctx context.Context) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/notification-preferences/me", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceUnsubscribeByToken unsubscribe from notification emails (POST /public/notification-preferences/{token}/unsubscribe). It does not require authentication.
+func (c *Client) NotificationPreferenceUnsubscribeByToken(ctx context.Context, token string, body UnsubscribeRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/public/notification-preferences/"+url.PathEscape(token)+"/unsubscribe", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceUpdateByPersonID update notification preferences of a person (PUT /admin/notification-preferences/{personId}).
+func (c *Client) NotificationPreferenceUpdateByPersonID(ctx context.Context, personID string, body NotificationPreferencesRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/admin/notification-preferences/"+url.PathEscape(personID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceUpdateByToken update notification preferences from an email link (PUT /public/notification-preferences/{token}). It does not require authentication.
+func (c *Client) NotificationPreferenceUpdateByToken(ctx context.Context, token string, body NotificationPreferencesRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/public/notification-preferences/"+url.PathEscape(token), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NotificationPreferenceUpdateMine update my notification preferences (PUT /notification-preferences/me).
+func (c *Client) NotificationPreferenceUpdateMine(ctx context.Context, body NotificationPreferencesRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/notification-preferences/me", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// OrganizationCreate  (POST /admin/organizations).
+func (c *Client) OrganizationCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/organizations", nil, nil, nil)
+}
+
+// OrganizationDelete  (DELETE /admin/organizations/{id}).
+func (c *Client) OrganizationDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/organizations/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// OrganizationGetAll  (GET /admin/organizations).
+func (c *Client) OrganizationGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/organizations", nil, nil, nil)
+}
+
+// OrganizationGetByID  (GET /admin/organizations/{id}).
+func (c *Client) OrganizationGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/organizations/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// OrganizationGetCurrent  (GET /public/organization). It does not require authentication.
+func (c *Client) OrganizationGetCurrent(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/public/organization", nil, nil, nil)
+}
+
+// OrganizationUpdate  (PUT /admin/organizations/{id}).
+func (c *Client) OrganizationUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/organizations/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PasswordResetForgotPassword request a password reset (POST /auth/forgot-password). It does not require authentication.
+func (c *Client) PasswordResetForgotPassword(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/auth/forgot-password", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PasswordResetResetPassword reset a forgotten password (POST /auth/reset-password). It does not require authentication.
+func (c *Client) PasswordResetResetPassword(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/auth/reset-password", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PaymentCheckoutCreate pay this month's rent online (POST /payment-checkouts).
+func (c *Client) PaymentCheckoutCreate(ctx context.Context) (*RentCheckout, error) {
+	var out RentCheckout
+	if err := c.do(ctx, http.MethodPost, "/payment-checkouts", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PaymentCheckoutGetByReference get the state of an online payment (GET /payment-checkouts/{reference}).
+func (c *Client) PaymentCheckoutGetByReference(ctx context.Context, reference string) (*PaymentCheckout, error) {
+	var out PaymentCheckout
+	if err := c.do(ctx, http.MethodGet, "/payment-checkouts/"+url.PathEscape(reference), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PaymentCheckoutWebhook payment gateway webhook (POST /public/payment-gateway/webhook). It does not require authentication.
+func (c *Client) PaymentCheckoutWebhook(ctx context.Context) (*map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodPost, "/public/payment-gateway/webhook", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PaymentStatementGetMyStatement  (GET /my/payments/statement).
+func (c *Client) PaymentStatementGetMyStatement(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/my/payments/statement", nil, nil, nil)
+}
+
+// PaymentStatementVerify  (GET /public/payment-statements/verify/{id}). It does not require authentication.
+func (c *Client) PaymentStatementVerify(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/public/payment-statements/verify/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PersonCreate create a new person (POST /persons).
+func (c *Client) PersonCreate(ctx context.Context, body Person) (*Person, error) {
+	var out Person
+	if err := c.do(ctx, http.MethodPost, "/persons", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PersonDelete delete a person (DELETE /persons/{id}).
+func (c *Client) PersonDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/persons/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PersonGetAllParams are the query parameters of PersonGetAll
+type PersonGetAllParams struct {
+	Limit  int    // Page size, the whole list without it
+	Offset int    // Rows skipped
+	Sort   string // Column to sort by, prefixed with - for descending order
+}
+
+// PersonGetAll get all persons (role-based) (GET /persons).
+func (c *Client) PersonGetAll(ctx context.Context, params *PersonGetAllParams) ([]Person, error) {
+	var out []Person
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Sort != "" {
+			query.Set("sort", params.Sort)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/persons", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PersonGetAllIter iterates every item of PersonGetAll, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) PersonGetAllIter(ctx context.Context, params *PersonGetAllParams, pageSize int) iter.Seq2[Person, error] {
+	var page PersonGetAllParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]Person, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.PersonGetAll(ctx, &page)
+	})
+}
+
+// PersonGetByID get person by ID (GET /persons/{id}).
+func (c *Client) PersonGetByID(ctx context.Context, id string) (*Person, error) {
+	var out Person
+	if err := c.do(ctx, http.MethodGet, "/persons/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PersonGetByRole get persons by role (GET /persons/role/{role}).
+func (c *Client) PersonGetByRole(ctx context.Context, role string) ([]Person, error) {
+	var out []Person
+	if err := c.do(ctx, http.MethodGet, "/persons/role/"+url.PathEscape(role), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PersonUpdate update a person (PUT /persons/{id}).
+func (c *Client) PersonUpdate(ctx context.Context, id string, body Person) (*Person, error) {
+	var out Person
+	if err := c.do(ctx, http.MethodPut, "/persons/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PricingCreatePricing  (POST /admin/pricing).
+func (c *Client) PricingCreatePricing(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/pricing", nil, nil, nil)
+}
+
+// PricingDeletePricing  (DELETE /admin/pricing/{id}).
+func (c *Client) PricingDeletePricing(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/pricing/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PricingGetAllPricing  (GET /admin/pricing).
+func (c *Client) PricingGetAllPricing(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/pricing", nil, nil, nil)
+}
+
+// PricingGetPricingBreakdown  (GET /admin/pricing/{id}/breakdown).
+func (c *Client) PricingGetPricingBreakdown(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/pricing/"+url.PathEscape(id)+"/breakdown", nil, nil, nil)
+}
+
+// PricingGetPricingByID  (GET /admin/pricing/{id}).
+func (c *Client) PricingGetPricingByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/pricing/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PricingGetPricingByRentalID  (GET /admin/pricing/rental/{rentalId}).
+func (c *Client) PricingGetPricingByRentalID(ctx context.Context, rentalID string) error {
+	return c.do(ctx, http.MethodGet, "/admin/pricing/rental/"+url.PathEscape(rentalID), nil, nil, nil)
+}
+
+// PricingGetPricingSummary  (GET /admin/pricing/summary).
+func (c *Client) PricingGetPricingSummary(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/pricing/summary", nil, nil, nil)
+}
+
+// PricingUpdatePricing  (PUT /admin/pricing/{id}).
+func (c *Client) PricingUpdatePricing(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/pricing/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PromotionCreate  (POST /admin/promotions).
+func (c *Client) PromotionCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/promotions", nil, nil, nil)
+}
+
+// PromotionDelete  (DELETE /admin/promotions/{id}).
+func (c *Client) PromotionDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/promotions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PromotionGetAll  (GET /admin/promotions).
+func (c *Client) PromotionGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/promotions", nil, nil, nil)
+}
+
+// PromotionGetByID  (GET /admin/promotions/{id}).
+func (c *Client) PromotionGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/promotions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PromotionGetOffer  (GET /promotions/property/{propertyId}/offer).
+func (c *Client) PromotionGetOffer(ctx context.Context, propertyID string) error {
+	return c.do(ctx, http.MethodGet, "/promotions/property/"+url.PathEscape(propertyID)+"/offer", nil, nil, nil)
+}
+
+// PromotionGetStats  (GET /admin/promotions/stats).
+func (c *Client) PromotionGetStats(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/promotions/stats", nil, nil, nil)
+}
+
+// PromotionUpdate  (PUT /admin/promotions/{id}).
+func (c *Client) PromotionUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/promotions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PropertyCreate create a new property (POST /properties).
+func (c *Client) PropertyCreate(ctx context.Context, body Property) (*Property, error) {
+	var out Property
+	if err := c.do(ctx, http.MethodPost, "/properties", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PropertyDelete delete a property (DELETE /admin/properties/{id}).
+func (c *Client) PropertyDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/properties/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PropertyDeletePhoto delete a property photo (DELETE /admin/properties/{id}/photos/{photoId}).
+func (c *Client) PropertyDeletePhoto(ctx context.Context, id string, photoID string) ([]PropertyPhoto, error) {
+	var out []PropertyPhoto
+	if err := c.do(ctx, http.MethodDelete, "/admin/properties/"+url.PathEscape(id)+"/photos/"+url.PathEscape(photoID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyGetAllParams are the query parameters of PropertyGetAll
+type PropertyGetAllParams struct {
+	City         string  // City, case insensitive
+	MinBedrooms  int     // Minimum number of bedrooms
+	MinBathrooms int     // Minimum number of bathrooms
+	MinArea      float64 // Minimum area in m²
+	MaxArea      float64 // Maximum area in m²
+	Estrato      int     // Socioeconomic stratum, 1 to 6
+	Parking      bool    // With (true) or without (false) parking spots
+	Furnished    bool    // Furnished
+	PetsAllowed  bool    // Pets allowed
+	Limit        int     // Page size, the whole list without it
+	Offset       int     // Rows skipped
+	Sort         string  // Column to sort by, prefixed with - for descending order
+}
+
+// PropertyGetAll get properties (role-based) (GET /properties).
+func (c *Client) PropertyGetAll(ctx context.Context, params *PropertyGetAllParams) ([]Property, error) {
+	var out []Property
+	query := url.Values{}
+	if params != nil {
+		if params.City != "" {
+			query.Set("city", params.City)
+		}
+		if params.MinBedrooms != 0 {
+			query.Set("min_bedrooms", strconv.Itoa(params.MinBedrooms))
+		}
+		if params.MinBathrooms != 0 {
+			query.Set("min_bathrooms", strconv.Itoa(params.MinBathrooms))
+		}
+		if params.MinArea != 0 {
+			query.Set("min_area", strconv.FormatFloat(params.MinArea, 'f', -1, 64))
+		}
+		if params.MaxArea != 0 {
+			query.Set("max_area", strconv.FormatFloat(params.MaxArea, 'f', -1, 64))
+		}
+		if params.Estrato != 0 {
+			query.Set("estrato", strconv.Itoa(params.Estrato))
+		}
+		if params.Parking {
+			query.Set("parking", "true")
+		}
+		if params.Furnished {
+			query.Set("furnished", "true")
+		}
+		if params.PetsAllowed {
+			query.Set("pets_allowed", "true")
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Sort != "" {
+			query.Set("sort", params.Sort)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/properties", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyGetAllIter iterates every item of PropertyGetAll, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) PropertyGetAllIter(ctx context.Context, params *PropertyGetAllParams, pageSize int) iter.Seq2[Property, error] {
+	var page PropertyGetAllParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]Property, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.PropertyGetAll(ctx, &page)
+	})
+}
+
+// PropertyGetByID get property by ID (GET /properties/{id}).
+func (c *Client) PropertyGetByID(ctx context.Context, id string) (*Property, error) {
+	var out Property
+	if err := c.do(ctx, http.MethodGet, "/properties/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PropertyGetByManagerID get properties by manager ID (GET /properties/manager/{managerId}).
+func (c *Client) PropertyGetByManagerID(ctx context.Context, managerID string) ([]Property, error) {
+	var out []Property
+	if err := c.do(ctx, http.MethodGet, "/properties/manager/"+url.PathEscape(managerID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyGetByResident get properties by resident ID (GET /properties/resident/{residentId}).
+func (c *Client) PropertyGetByResident(ctx context.Context, residentID string) ([]Property, error) {
+	var out []Property
+	if err := c.do(ctx, http.MethodGet, "/properties/resident/"+url.PathEscape(residentID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyGetByUserID get properties for a specific user (GET /properties/user/{userId}).
+func (c *Client) PropertyGetByUserID(ctx context.Context, userID string) ([]Property, error) {
+	var out []Property
+	if err := c.do(ctx, http.MethodGet, "/properties/user/"+url.PathEscape(userID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyReorderPhotos reorder the photos of a property (PUT /admin/properties/{id}/photos/order).
+func (c *Client) PropertyReorderPhotos(ctx context.Context, id string, body ReorderPhotosRequest) ([]PropertyPhoto, error) {
+	var out []PropertyPhoto
+	if err := c.do(ctx, http.MethodPut, "/admin/properties/"+url.PathEscape(id)+"/photos/order", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyUpdate update a property (PUT /admin/properties/{id}).
+func (c *Client) PropertyUpdate(ctx context.Context, id string, body Property) (*Property, error) {
+	var out Property
+	if err := c.do(ctx, http.MethodPut, "/admin/properties/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PropertyUpdatePhoto caption a property photo (PUT /admin/properties/{id}/photos/{photoId}).
+func (c *Client) PropertyUpdatePhoto(ctx context.Context, id string, photoID string, body UpdatePhotoRequest) (*PropertyPhoto, error) {
+	var out PropertyPhoto
+	if err := c.do(ctx, http.MethodPut, "/admin/properties/"+url.PathEscape(id)+"/photos/"+url.PathEscape(photoID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReglamentoAcknowledge  (POST /reglamentos/{id}/acknowledge).
+func (c *Client) ReglamentoAcknowledge(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/reglamentos/"+url.PathEscape(id)+"/acknowledge", nil, nil, nil)
+}
+
+// ReglamentoGetCompliance  (GET /reglamentos/compliance).
+func (c *Client) ReglamentoGetCompliance(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/reglamentos/compliance", nil, nil, nil)
+}
+
+// ReglamentoGetPending  (GET /reglamentos/pending).
+func (c *Client) ReglamentoGetPending(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/reglamentos/pending", nil, nil, nil)
+}
+
+// ReglamentoServePDF  (GET /reglamentos/{id}/pdf).
+func (c *Client) ReglamentoServePDF(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/reglamentos/"+url.PathEscape(id)+"/pdf", nil, nil, nil)
+}
+
+// ReglamentoUpload  (POST /reglamentos).
+func (c *Client) ReglamentoUpload(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/reglamentos", nil, nil, nil)
+}
+
+// ReminderPreferenceGetByPersonID  (GET /admin/reminder-preferences/{personId}).
+func (c *Client) ReminderPreferenceGetByPersonID(ctx context.Context, personID string) error {
+	return c.do(ctx, http.MethodGet, "/admin/reminder-preferences/"+url.PathEscape(personID), nil, nil, nil)
+}
+
+// ReminderPreferenceGetMine  (GET /reminder-preferences/me).
+func (c *Client) ReminderPreferenceGetMine(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/reminder-preferences/me", nil, nil, nil)
+}
+
+// ReminderPreferenceUpdateByPersonID  (PUT /admin/reminder-preferences/{personId}).
+func (c *Client) ReminderPreferenceUpdateByPersonID(ctx context.Context, personID string) error {
+	return c.do(ctx, http.MethodPut, "/admin/reminder-preferences/"+url.PathEscape(personID), nil, nil, nil)
+}
+
+// ReminderPreferenceUpdateMine  (PUT /reminder-preferences/me).
+func (c *Client) ReminderPreferenceUpdateMine(ctx context.Context) error {
+	return c.do(ctx, http.MethodPut, "/reminder-preferences/me", nil, nil, nil)
+}
+
+// RentPaymentCreate  (POST /admin/payments).
+func (c *Client) RentPaymentCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/payments", nil, nil, nil)
+}
+
+// RentPaymentDelete  (DELETE /admin/payments/{id}).
+func (c *Client) RentPaymentDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/payments/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentPaymentGetAll get all rent payments (Admin only) (GET /payments).
+func (c *Client) RentPaymentGetAll(ctx context.Context) ([]RentPayment, error) {
+	var out []RentPayment
+	if err := c.do(ctx, http.MethodGet, "/payments", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentPaymentGetByDateRange  (GET /admin/payments/date-range).
+func (c *Client) RentPaymentGetByDateRange(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/payments/date-range", nil, nil, nil)
+}
+
+// RentPaymentGetByID get rent payment by ID (GET /payments/{id}).
+func (c *Client) RentPaymentGetByID(ctx context.Context, id string) (*RentPayment, error) {
+	var out RentPayment
+	if err := c.do(ctx, http.MethodGet, "/payments/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RentPaymentGetByRentalID  (GET /payments/rental/{rentalId}).
+func (c *Client) RentPaymentGetByRentalID(ctx context.Context, rentalID string) error {
+	return c.do(ctx, http.MethodGet, "/payments/rental/"+url.PathEscape(rentalID), nil, nil, nil)
+}
+
+// RentPaymentGetByRentalIDs get payments by multiple rental IDs (GET /payments/rental-ids).
+func (c *Client) RentPaymentGetByRentalIDs(ctx context.Context, body []string) ([]RentPayment, error) {
+	var out []RentPayment
+	if err := c.do(ctx, http.MethodGet, "/payments/rental-ids", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentPaymentGetLatePayments  (GET /admin/payments/late).
+func (c *Client) RentPaymentGetLatePayments(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/payments/late", nil, nil, nil)
+}
+
+// RentPaymentUpdate  (PUT /admin/payments/{id}).
+func (c *Client) RentPaymentUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/payments/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentalCreate create a new rental (POST /admin/rentals).
+func (c *Client) RentalCreate(ctx context.Context, body Rental) (*Rental, error) {
+	var out Rental
+	if err := c.do(ctx, http.MethodPost, "/admin/rentals", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RentalDelete delete a rental (DELETE /admin/rentals/{id}).
+func (c *Client) RentalDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/rentals/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentalGetAll get rentals (role-based for admin/manager) (GET /rentals).
+func (c *Client) RentalGetAll(ctx context.Context) ([]Rental, error) {
+	var out []Rental
+	if err := c.do(ctx, http.MethodGet, "/rentals", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalGetByID get rental by ID (GET /rentals/{id}).
+func (c *Client) RentalGetByID(ctx context.Context, id string) (*Rental, error) {
+	var out Rental
+	if err := c.do(ctx, http.MethodGet, "/rentals/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RentalGetByPropertyID get rentals by property ID (GET /rentals/by-property/{property_id}).
+func (c *Client) RentalGetByPropertyID(ctx context.Context, propertyID string) ([]Rental, error) {
+	var out []Rental
+	if err := c.do(ctx, http.MethodGet, "/rentals/by-property/"+url.PathEscape(propertyID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalGetByRenterID get rentals by renter ID (GET /rentals/by-renter/{renter_id}).
+func (c *Client) RentalGetByRenterID(ctx context.Context, renterID string) ([]Rental, error) {
+	var out []Rental
+	if err := c.do(ctx, http.MethodGet, "/rentals/by-renter/"+url.PathEscape(renterID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalHistoryCreate  (POST /admin/rental-history).
+func (c *Client) RentalHistoryCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/rental-history", nil, nil, nil)
+}
+
+// RentalHistoryDelete  (DELETE /admin/rental-history/{id}).
+func (c *Client) RentalHistoryDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/rental-history/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentalHistoryGetAll  (GET /rental-history).
+func (c *Client) RentalHistoryGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/rental-history", nil, nil, nil)
+}
+
+// RentalHistoryGetByDateRange  (GET /rental-history/date-range).
+func (c *Client) RentalHistoryGetByDateRange(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/rental-history/date-range", nil, nil, nil)
+}
+
+// RentalHistoryGetByID  (GET /rental-history/{id}).
+func (c *Client) RentalHistoryGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/rental-history/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentalHistoryGetByPersonID  (GET /rental-history/person/{personId}).
+func (c *Client) RentalHistoryGetByPersonID(ctx context.Context, personID string) error {
+	return c.do(ctx, http.MethodGet, "/rental-history/person/"+url.PathEscape(personID), nil, nil, nil)
+}
+
+// RentalHistoryGetByRentalID  (GET /rental-history/rental/{rentalId}).
+func (c *Client) RentalHistoryGetByRentalID(ctx context.Context, rentalID string) error {
+	return c.do(ctx, http.MethodGet, "/rental-history/rental/"+url.PathEscape(rentalID), nil, nil, nil)
+}
+
+// RentalHistoryGetByStatus  (GET /rental-history/status/{status}).
+func (c *Client) RentalHistoryGetByStatus(ctx context.Context, status string) error {
+	return c.do(ctx, http.MethodGet, "/rental-history/status/"+url.PathEscape(status), nil, nil, nil)
+}
+
+// RentalHistoryGetMultipleByRentalIDs  (POST /rental-history/for-rentals).
+func (c *Client) RentalHistoryGetMultipleByRentalIDs(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/rental-history/for-rentals", nil, nil, nil)
+}
+
+// RentalHistoryGetPropertyOccupancyParams are the query parameters of RentalHistoryGetPropertyOccupancy
+type RentalHistoryGetPropertyOccupancyParams struct {
+	From string // Start of the range (YYYY-MM-DD)
+	To   string // End of the range (YYYY-MM-DD), today by default
+}
+
+// RentalHistoryGetPropertyOccupancy occupancy timeline of a property (GET /properties/{id}/occupancy).
+func (c *Client) RentalHistoryGetPropertyOccupancy(ctx context.Context, id string, params *RentalHistoryGetPropertyOccupancyParams) (*OccupancyTimeline, error) {
+	var out OccupancyTimeline
+	query := url.Values{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/properties/"+url.PathEscape(id)+"/occupancy", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RentalHistoryUpdate  (PUT /admin/rental-history/{id}).
+func (c *Client) RentalHistoryUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/rental-history/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RentalPartyCreate add a party to a rental contract (POST /admin/contracts/{id}/parties).
+func (c *Client) RentalPartyCreate(ctx context.Context, id string, body RentalPartyRequest) (*RentalPartyResponse, error) {
+	var out RentalPartyResponse
+	if err := c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/parties", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RentalPartyDelete  (DELETE /admin/contracts/{id}/parties/{partyId}).
+func (c *Client) RentalPartyDelete(ctx context.Context, id string, partyID string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/contracts/"+url.PathEscape(id)+"/parties/"+url.PathEscape(partyID), nil, nil, nil)
+}
+
+// RentalPartyGetByRentalID list the parties of a rental contract (GET /admin/contracts/{id}/parties).
+func (c *Client) RentalPartyGetByRentalID(ctx context.Context, id string) ([]RentalPartyResponse, error) {
+	var out []RentalPartyResponse
+	if err := c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/parties", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalUpdate update a rental (PUT /admin/rentals/{id}).
+func (c *Client) RentalUpdate(ctx context.Context, id string, body Rental) (*Rental, error) {
+	var out Rental
+	if err := c.do(ctx, http.MethodPut, "/admin/rentals/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchSearchParams are the query parameters of SearchSearch
+type SearchSearchParams struct {
+	Q     string // Text to search, at least 2 characters
+	Types string // Comma-separated result types: person, property, rental, file
+	Limit int    // Maximum results, 20 by default and 50 at most
+}
+
+// SearchSearch full-text search (GET /search).
+func (c *Client) SearchSearch(ctx context.Context, params *SearchSearchParams) ([]SearchResult, error) {
+	var out []SearchResult
+	query := url.Values{}
+	if params != nil {
+		if params.Q != "" {
+			query.Set("q", params.Q)
+		}
+		if params.Types != "" {
+			query.Set("types", params.Types)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/search", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SecurityDepositCreate record the security deposit of a rental (POST /admin/contracts/{id}/deposit).
+func (c *Client) SecurityDepositCreate(ctx context.Context, id string, body SecurityDepositRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/contracts/"+url.PathEscape(id)+"/deposit", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositGetByRentalID get the security deposit of a rental (GET /admin/contracts/{id}/deposit).
+func (c *Client) SecurityDepositGetByRentalID(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/admin/contracts/"+url.PathEscape(id)+"/deposit", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositGetDeductionCategories  (GET /admin/deposits/deduction-categories).
+func (c *Client) SecurityDepositGetDeductionCategories(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/deposits/deduction-categories", nil, nil, nil)
+}
+
+// SecurityDepositGetMine list my security deposits (GET /deposits/me).
+func (c *Client) SecurityDepositGetMine(ctx context.Context) ([]interface{}, error) {
+	var out []interface{}
+	if err := c.do(ctx, http.MethodGet, "/deposits/me", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SecurityDepositIssueSettlement issue the move-out settlement of a deposit (POST /admin/deposits/{id}/settlement).
+func (c *Client) SecurityDepositIssueSettlement(ctx context.Context, id string, body IssueSettlementRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/deposits/"+url.PathEscape(id)+"/settlement", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositRefund record the refund of a deposit (POST /admin/deposits/{id}/refund).
+func (c *Client) SecurityDepositRefund(ctx context.Context, id string, body RefundDepositRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/admin/deposits/"+url.PathEscape(id)+"/refund", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositRemoveDeduction remove a deduction from a security deposit (DELETE /admin/deposits/{id}/deductions/{deductionId}).
+func (c *Client) SecurityDepositRemoveDeduction(ctx context.Context, id string, deductionID string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodDelete, "/admin/deposits/"+url.PathEscape(id)+"/deductions/"+url.PathEscape(deductionID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositSignSettlement sign the settlement of my deposit (POST /deposits/{id}/settlement/sign).
+func (c *Client) SecurityDepositSignSettlement(ctx context.Context, id string, body SignerLocation) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/deposits/"+url.PathEscape(id)+"/settlement/sign", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositUpdate update a security deposit (PUT /admin/deposits/{id}).
+func (c *Client) SecurityDepositUpdate(ctx context.Context, id string, body SecurityDepositRequest) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/admin/deposits/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SecurityDepositVerifySettlement verify a signed deposit settlement (GET /public/deposit-settlements/verify/{id}). It does not require authentication.
+func (c *Client) SecurityDepositVerifySettlement(ctx context.Context, id string) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodGet, "/public/deposit-settlements/verify/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ServiceProviderCreate  (POST /admin/service-providers).
+func (c *Client) ServiceProviderCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/service-providers", nil, nil, nil)
+}
+
+// ServiceProviderDelete  (DELETE /admin/service-providers/{id}).
+func (c *Client) ServiceProviderDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/service-providers/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ServiceProviderGetAll  (GET /admin/service-providers).
+func (c *Client) ServiceProviderGetAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/service-providers", nil, nil, nil)
+}
+
+// ServiceProviderGetByID  (GET /admin/service-providers/{id}).
+func (c *Client) ServiceProviderGetByID(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/admin/service-providers/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ServiceProviderUpdate  (PUT /admin/service-providers/{id}).
+func (c *Client) ServiceProviderUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/admin/service-providers/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// SessionGetMine list my active sessions (GET /users/me/sessions).
+func (c *Client) SessionGetMine(ctx context.Context) ([]sessionResponse, error) {
+	var out []sessionResponse
+	if err := c.do(ctx, http.MethodGet, "/users/me/sessions", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionRevokeMine revoke one of my sessions (DELETE /users/me/sessions/{id}).
+func (c *Client) SessionRevokeMine(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/users/me/sessions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// SigningCertificateDelete  (DELETE /admin/signing-certificate).
+func (c *Client) SigningCertificateDelete(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/admin/signing-certificate", nil, nil, nil)
+}
+
+// SigningCertificateGet  (GET /admin/signing-certificate).
+func (c *Client) SigningCertificateGet(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/admin/signing-certificate", nil, nil, nil)
+}
+
+// SigningCertificateReload  (POST /admin/signing-certificate/reload).
+func (c *Client) SigningCertificateReload(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/signing-certificate/reload", nil, nil, nil)
+}
+
+// SigningCertificateUpload  (POST /admin/signing-certificate).
+func (c *Client) SigningCertificateUpload(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/admin/signing-certificate", nil, nil, nil)
+}
+
+// SubscriptionGetAll list the platform subscriptions (GET /admin/subscriptions).
+func (c *Client) SubscriptionGetAll(ctx context.Context) ([]PlatformSubscription, error) {
+	var out []PlatformSubscription
+	if err := c.do(ctx, http.MethodGet, "/admin/subscriptions", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubscriptionGetMine get my platform subscription (GET /subscription/me).
+func (c *Client) SubscriptionGetMine(ctx context.Context) (*PlatformSubscription, error) {
+	var out PlatformSubscription
+	if err := c.do(ctx, http.MethodGet, "/subscription/me", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SubscriptionSubscribe subscribe a manager to the platform plan (POST /admin/users/{id}/subscription).
+func (c *Client) SubscriptionSubscribe(ctx context.Context, id string) (*PlatformSubscription, error) {
+	var out PlatformSubscription
+	if err := c.do(ctx, http.MethodPost, "/admin/users/"+url.PathEscape(id)+"/subscription", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SubscriptionWebhook subscription billing webhook (POST /public/subscriptions/webhook). It does not require authentication.
+func (c *Client) SubscriptionWebhook(ctx context.Context) (*map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodPost, "/public/subscriptions/webhook", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserBulkCreateJob bulk user operation (POST /admin/users/bulk).
+func (c *Client) UserBulkCreateJob(ctx context.Context, body UserBulkRequest) (*UserBulkJob, error) {
+	var out UserBulkJob
+	if err := c.do(ctx, http.MethodPost, "/admin/users/bulk", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserBulkGetJob get bulk user job (GET /admin/users/bulk/{id}).
+func (c *Client) UserBulkGetJob(ctx context.Context, id string) (*UserBulkJob, error) {
+	var out UserBulkJob
+	if err := c.do(ctx, http.MethodGet, "/admin/users/bulk/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserBulkListJobs list bulk user jobs (GET /admin/users/bulk).
+func (c *Client) UserBulkListJobs(ctx context.Context) ([]UserBulkJob, error) {
+	var out []UserBulkJob
+	if err := c.do(ctx, http.MethodGet, "/admin/users/bulk", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserChangePassword change user password (PUT /users/{id}/change-password).
+func (c *Client) UserChangePassword(ctx context.Context, id string, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(id)+"/change-password", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserCreate create a new user (POST /users).
+func (c *Client) UserCreate(ctx context.Context, body User) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodPost, "/users", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserDelete delete a user (DELETE /users/{id}).
+func (c *Client) UserDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// UserGetAllParams are the query parameters of UserGetAll
+type UserGetAllParams struct {
+	Limit  int    // Page size, the whole list without it
+	Offset int    // Rows skipped
+	Sort   string // Column to sort by, prefixed with - for descending order
+}
+
+// UserGetAll get all users (GET /users).
+func (c *Client) UserGetAll(ctx context.Context, params *UserGetAllParams) ([]User, error) {
+	var out []User
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Sort != "" {
+			query.Set("sort", params.Sort)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/users", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserGetAllIter iterates every item of UserGetAll, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.
+func (c *Client) UserGetAllIter(ctx context.Context, params *UserGetAllParams, pageSize int) iter.Seq2[User, error] {
+	var page UserGetAllParams
+	if params != nil {
+		page = *params
+	}
+	return paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]User, error) {
+		page.Limit, page.Offset = limit, offset
+		return c.UserGetAll(ctx, &page)
+	})
+}
+
+// UserGetByEmailParams are the query parameters of UserGetByEmail
+type UserGetByEmailParams struct {
+	Email string // Email address
+}
+
+// UserGetByEmail get user by email (GET /users/email).
+func (c *Client) UserGetByEmail(ctx context.Context, params *UserGetByEmailParams) (*User, error) {
+	var out User
+	query := url.Values{}
+	if params != nil {
+		if params.Email != "" {
+			query.Set("email", params.Email)
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/users/email", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserGetByID get user by ID (GET /users/{id}).
+func (c *Client) UserGetByID(ctx context.Context, id string) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserGetLoginAttemptsParams are the query parameters of UserGetLoginAttempts
+type UserGetLoginAttemptsParams struct {
+	Email  string // Email of the attempts
+	Ip     string // IP address of the attempts
+	Failed bool   // Only the failed attempts, true by default
+	Hours  int    // Hours back, 24 by default and 720 at most
+	Limit  int    // Maximum attempts, 100 by default and 500 at most
+}
+
+// UserGetLoginAttempts recent login attempts (GET /admin/login-attempts).
+func (c *Client) UserGetLoginAttempts(ctx context.Context, params *UserGetLoginAttemptsParams) ([]LoginAttempt, error) {
+	var out []LoginAttempt
+	query := url.Values{}
+	if params != nil {
+		if params.Email != "" {
+			query.Set("email", params.Email)
+		}
+		if params.Ip != "" {
+			query.Set("ip", params.Ip)
+		}
+		if params.Failed {
+			query.Set("failed", "true")
+		}
+		if params.Hours != 0 {
+			query.Set("hours", strconv.Itoa(params.Hours))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/login-attempts", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserLogin login user (POST /users/login). It does not require authentication.
+func (c *Client) UserLogin(ctx context.Context, body LoginRequest) (*LoginResponse, error) {
+	var out LoginResponse
+	if err := c.do(ctx, http.MethodPost, "/users/login", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserRegistrationRegister register a new user (POST /auth/register). It does not require authentication.
+func (c *Client) UserRegistrationRegister(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/auth/register", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserRegistrationResendVerification resend the verification email (POST /auth/resend-verification). It does not require authentication.
+func (c *Client) UserRegistrationResendVerification(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/auth/resend-verification", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserRegistrationVerifyEmail verify the email of a registered user (POST /auth/verify-email). It does not require authentication.
+func (c *Client) UserRegistrationVerifyEmail(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPost, "/auth/verify-email", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserUpdate update a user (PUT /users/{id}).
+func (c *Client) UserUpdate(ctx context.Context, id string, body User) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserUpdateMyLocale set my language (PUT /users/me/locale).
+func (c *Client) UserUpdateMyLocale(ctx context.Context, body interface{}) (*interface{}, error) {
+	var out interface{}
+	if err := c.do(ctx, http.MethodPut, "/users/me/locale", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// WebhookCreate  (POST /webhooks).
+func (c *Client) WebhookCreate(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/webhooks", nil, nil, nil)
+}
+
+// WebhookDelete  (DELETE /webhooks/{id}).
+func (c *Client) WebhookDelete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// WebhookGetDeliveries  (GET /webhooks/{id}/deliveries).
+func (c *Client) WebhookGetDeliveries(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/webhooks/"+url.PathEscape(id)+"/deliveries", nil, nil, nil)
+}
+
+// WebhookList  (GET /webhooks).
+func (c *Client) WebhookList(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/webhooks", nil, nil, nil)
+}
+
+// WebhookListEvents  (GET /webhooks/events).
+func (c *Client) WebhookListEvents(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/webhooks/events", nil, nil, nil)
+}
+
+// WebhookSendTest  (POST /webhooks/{id}/test).
+func (c *Client) WebhookSendTest(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/webhooks/"+url.PathEscape(id)+"/test", nil, nil, nil)
+}
+
+// WebhookUpdate  (PUT /webhooks/{id}).
+func (c *Client) WebhookUpdate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPut, "/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
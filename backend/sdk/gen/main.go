@@ -0,0 +1,669 @@
+// Command gen generates the typed Go and TypeScript API clients from the OpenAPI spec served at
+// /api/docs/openapi.json, built from the routes of this tree without a database.
+// Run it through `go generate ./sdk` from the backend directory.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nescool101/rentManager/app"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/controller"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// spec is the subset of OpenAPI 3 used by the generated clients
+type spec struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Paginated   bool                 `json:"x-paginated"`
+	Security    *[]json.RawMessage   `json:"security"`
+	Parameters  []*parameter         `json:"parameters"`
+	RequestBody *requestBody         `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+
+	name   string // Go name of the operation, from operationId
+	method string
+	path   string
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+type response struct {
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Nullable             bool               `json:"nullable"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+}
+
+// initialisms are written in upper case in Go identifiers
+var initialisms = map[string]bool{"id": true, "ids": true, "url": true, "nit": true, "pdf": true}
+
+func main() {
+	goOut := flag.String("go", "client.gen.go", "generated Go client")
+	tsOut := flag.String("ts", "", "generated TypeScript client, skipped when empty")
+	flag.Parse()
+
+	s, err := servedSpec()
+	if err != nil {
+		log.Fatalf("building spec: %v", err)
+	}
+
+	ops, err := s.operations()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := format.Source(generateGo(s, ops))
+	if err != nil {
+		log.Fatalf("formatting Go client: %v", err)
+	}
+	if err := os.WriteFile(*goOut, source, 0644); err != nil {
+		log.Fatalf("writing Go client: %v", err)
+	}
+
+	if *tsOut != "" {
+		if err := os.MkdirAll(filepath.Dir(*tsOut), 0755); err != nil {
+			log.Fatalf("creating TypeScript directory: %v", err)
+		}
+		if err := os.WriteFile(*tsOut, generateTS(s, ops), 0644); err != nil {
+			log.Fatalf("writing TypeScript client: %v", err)
+		}
+	}
+}
+
+// servedSpec returns the spec the API serves at /api/docs/openapi.json. The router is built with
+// an empty configuration and no database, which the spec does not depend on.
+func servedSpec() (*spec, error) {
+	gin.SetMode(gin.ReleaseMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Building the router writes some files relative to the working directory, such as the
+	// self-signed signing certificate, which are left in a temporary directory instead
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "sdk-gen")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+	defer os.Chdir(wd)
+
+	container := app.NewWithRepositories(&config.Config{}, storage.NewRepositoryFactory(nil), nil, nil)
+	served, err := controller.OpenAPISpec(container)
+	if err != nil {
+		return nil, err
+	}
+
+	// The spec is a tree of maps, decoded again into the typed subset the generators read
+	data, err := json.Marshal(served)
+	if err != nil {
+		return nil, err
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// operations returns the JSON operations of the spec sorted by name. File uploads and downloads
+// are left out, as are placeholder parameters such as filter[column].
+func (s *spec) operations() ([]*operation, error) {
+	var ops []*operation
+	for path, methods := range s.Paths {
+		for method, op := range methods {
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId", strings.ToUpper(method), path)
+			}
+			if !op.json() {
+				continue
+			}
+			op.name = operationName(op.OperationID)
+			op.method, op.path = strings.ToUpper(method), path
+
+			params := op.Parameters[:0]
+			for _, param := range op.Parameters {
+				if !strings.ContainsAny(param.Name, "[]") {
+					params = append(params, param)
+				}
+			}
+			op.Parameters = params
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].name < ops[j].name })
+	return ops, nil
+}
+
+// operationName returns the Go name of an operationId ("PersonController.GetAll" ->
+// "PersonGetAll", "ContractController.HandleGenerateContract" -> "ContractGenerateContract")
+func operationName(operationID string) string {
+	receiver, method, found := strings.Cut(operationID, ".")
+	if !found {
+		return goName(operationID)
+	}
+	method = strings.TrimPrefix(method, "Handle")
+	return goName(strings.TrimSuffix(receiver, "Controller") + "_" + method)
+}
+
+// json reports whether the request and the successful responses of the operation, if any, are
+// JSON
+func (op *operation) json() bool {
+	if op.RequestBody != nil {
+		if _, ok := op.RequestBody.Content["application/json"]; !ok {
+			return false
+		}
+	}
+	for code, resp := range op.Responses {
+		if !strings.HasPrefix(code, "2") || len(resp.Content) == 0 {
+			continue
+		}
+		if _, ok := resp.Content["application/json"]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// requestSchema returns the JSON request body schema of an operation, nil without body
+func (op *operation) requestSchema() *schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Content["application/json"].Schema
+}
+
+// responseSchema returns the JSON schema of the first 2xx response, nil without body
+func (s *spec) responseSchema(op *operation) *schema {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		return op.Responses[code].Content["application/json"].Schema
+	}
+	return nil
+}
+
+// params returns the parameters of an operation located in "path" or "query"
+func (op *operation) params(in string) []*parameter {
+	var params []*parameter
+	for _, param := range op.Parameters {
+		if param.In == in {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// public reports whether the operation is called without authentication
+func (op *operation) public() bool {
+	return op.Security != nil && len(*op.Security) == 0
+}
+
+// generateGo writes the Go types and Client methods
+func generateGo(s *spec, ops []*operation) []byte {
+	var b bytes.Buffer
+
+	for _, key := range s.schemaKeys() {
+		sc, name := s.Components.Schemas[key], typeName(key)
+		writeComment(&b, "", name+" "+lowerFirst(describe(sc, "is the "+splitWords(name)+" schema of the API")))
+		fmt.Fprintf(&b, "type %s struct {\n", name)
+		for _, prop := range sortedKeys(sc.Properties) {
+			field := sc.Properties[prop]
+			tag := prop
+			if !contains(sc.Required, prop) {
+				tag += ",omitempty"
+			}
+			comment := ""
+			if field.Description != "" {
+				comment = " // " + field.Description
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`%s\n", goName(prop), goType(field), tag, comment)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, op := range ops {
+		name := op.name
+		query := op.params("query")
+		if len(query) > 0 {
+			writeComment(&b, "", name+"Params are the query parameters of "+name)
+			fmt.Fprintf(&b, "type %sParams struct {\n", name)
+			for _, param := range query {
+				comment := ""
+				if param.Description != "" {
+					comment = " // " + param.Description
+				}
+				fmt.Fprintf(&b, "\t%s %s%s\n", goName(param.Name), goType(param.Schema), comment)
+			}
+			b.WriteString("}\n\n")
+		}
+
+		args := []string{"ctx context.Context"}
+		for _, param := range op.params("path") {
+			args = append(args, goArg(param.Name)+" string")
+		}
+		if body := op.requestSchema(); body != nil {
+			args = append(args, "body "+goType(body))
+		}
+		if len(query) > 0 {
+			args = append(args, "params *"+name+"Params")
+		}
+
+		result := s.responseSchema(op)
+		returns, outExpr, retExpr := "error", "nil", ""
+		if result != nil {
+			// Lists are returned by value, objects by pointer
+			resultType := goType(result)
+			outExpr, retExpr = "&out", "&out"
+			returns = "(*" + resultType + ", error)"
+			if strings.HasPrefix(resultType, "[]") {
+				retExpr = "out"
+				returns = "(" + resultType + ", error)"
+			}
+		}
+
+		auth := ""
+		if op.public() {
+			auth = " It does not require authentication."
+		}
+		writeComment(&b, "", fmt.Sprintf("%s %s (%s %s).%s", name, lowerFirst(op.Summary), op.method, op.path, auth))
+		fmt.Fprintf(&b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+		if result != nil {
+			fmt.Fprintf(&b, "\tvar out %s\n", goType(result))
+		}
+
+		path := "\"" + op.path + "\""
+		for _, param := range op.params("path") {
+			path = strings.Replace(path, "{"+param.Name+"}", "\" + url.PathEscape("+goArg(param.Name)+") + \"", 1)
+		}
+		path = strings.TrimSuffix(path, " + \"\"")
+
+		queryExpr := "nil"
+		if len(query) > 0 {
+			queryExpr = "query"
+			b.WriteString("\tquery := url.Values{}\n\tif params != nil {\n")
+			for _, param := range query {
+				field := "params." + goName(param.Name)
+				switch goType(param.Schema) {
+				case "int":
+					fmt.Fprintf(&b, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.Itoa(%s))\n\t\t}\n", field, param.Name, field)
+				case "float64":
+					fmt.Fprintf(&b, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.FormatFloat(%s, 'f', -1, 64))\n\t\t}\n", field, param.Name, field)
+				case "bool":
+					fmt.Fprintf(&b, "\t\tif %s {\n\t\t\tquery.Set(%q, \"true\")\n\t\t}\n", field, param.Name)
+				case "[]string":
+					fmt.Fprintf(&b, "\t\tfor _, value := range %s {\n\t\t\tquery.Add(%q, value)\n\t\t}\n", field, param.Name)
+				default:
+					fmt.Fprintf(&b, "\t\tif %s != \"\" {\n\t\t\tquery.Set(%q, %s)\n\t\t}\n", field, param.Name, field)
+				}
+			}
+			b.WriteString("\t}\n")
+		}
+
+		bodyExpr := "nil"
+		if op.requestSchema() != nil {
+			bodyExpr = "body"
+		}
+		call := fmt.Sprintf("c.do(ctx, http.Method%s, %s, %s, %s, %s)", methodConst(op.method), path, queryExpr, bodyExpr, outExpr)
+		if result == nil {
+			fmt.Fprintf(&b, "\treturn %s\n}\n\n", call)
+		} else {
+			fmt.Fprintf(&b, "\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n\treturn %s, nil\n}\n\n", call, retExpr)
+		}
+
+		if op.Paginated && result != nil && result.Items != nil && len(query) > 0 {
+			item := goType(result.Items)
+			writeComment(&b, "", fmt.Sprintf("%sIter iterates every item of %s, requesting pages of pageSize items (DefaultPageSize when 0). Limit and Offset of params are ignored.", name, name))
+			fmt.Fprintf(&b, "func (c *Client) %sIter(%s, pageSize int) iter.Seq2[%s, error] {\n", name, strings.Join(args, ", "), item)
+			pathArgs := []string{"ctx"}
+			for _, param := range op.params("path") {
+				pathArgs = append(pathArgs, goArg(param.Name))
+			}
+			fmt.Fprintf(&b, "\tvar page %sParams\n\tif params != nil {\n\t\tpage = *params\n\t}\n", name)
+			fmt.Fprintf(&b, "\treturn paginate(ctx, pageSize, func(ctx context.Context, limit, offset int) ([]%s, error) {\n", item)
+			b.WriteString("\t\tpage.Limit, page.Offset = limit, offset\n")
+			fmt.Fprintf(&b, "\t\treturn c.%s(%s, &page)\n\t})\n}\n\n", name, strings.Join(pathArgs, ", "))
+		}
+	}
+
+	// Import only the packages the generated code uses
+	var imports []string
+	for _, pkg := range []string{"context", "iter", "net/http", "net/url", "strconv", "time"} {
+		if bytes.Contains(b.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			imports = append(imports, fmt.Sprintf("\t%q\n", pkg))
+		}
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by sdk/gen from the spec served at /api/docs/openapi.json; DO NOT EDIT.\n\n")
+	file.WriteString("package sdk\n\n")
+	file.WriteString("import (\n" + strings.Join(imports, "") + ")\n\n")
+	file.Write(b.Bytes())
+	return file.Bytes()
+}
+
+// generateTS writes the TypeScript types and ApiClient methods
+func generateTS(s *spec, ops []*operation) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by backend/sdk/gen from the spec served at /api/docs/openapi.json; DO NOT EDIT.\n\n")
+	b.WriteString("import { ApiClientBase, paginate } from '../sdkRuntime';\n\n")
+
+	for _, key := range s.schemaKeys() {
+		sc, name := s.Components.Schemas[key], typeName(key)
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, prop := range sortedKeys(sc.Properties) {
+			field := sc.Properties[prop]
+			optional := "?"
+			if contains(sc.Required, prop) {
+				optional = ""
+			}
+			if field.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", field.Description)
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", prop, optional, tsType(field))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	for _, op := range ops {
+		if query := op.params("query"); len(query) > 0 {
+			fmt.Fprintf(&b, "export interface %sParams {\n", op.name)
+			for _, param := range query {
+				fmt.Fprintf(&b, "  %s?: %s;\n", param.Name, tsType(param.Schema))
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	b.WriteString("export class ApiClient extends ApiClientBase {\n")
+	for i, op := range ops {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		name, method := op.name, lowerFirst(op.name)
+		query := op.params("query")
+
+		var args, pathArgs []string
+		for _, param := range op.params("path") {
+			args = append(args, param.Name+": string")
+			pathArgs = append(pathArgs, param.Name)
+		}
+		if body := op.requestSchema(); body != nil {
+			args = append(args, "body: "+tsType(body))
+		}
+		if len(query) > 0 {
+			args = append(args, "params: "+name+"Params = {}")
+		}
+
+		result := "void"
+		if sc := s.responseSchema(op); sc != nil {
+			result = tsType(sc)
+		}
+
+		path := op.path
+		for _, param := range op.params("path") {
+			path = strings.Replace(path, "{"+param.Name+"}", "${encodeURIComponent("+param.Name+")}", 1)
+		}
+		var options []string
+		if len(query) > 0 {
+			options = append(options, "query: { ...params }")
+		}
+		if op.requestSchema() != nil {
+			options = append(options, "body")
+		}
+		optionsExpr := ""
+		if len(options) > 0 {
+			optionsExpr = ", { " + strings.Join(options, ", ") + " }"
+		}
+
+		fmt.Fprintf(&b, "  /** %s (%s %s) */\n", op.Summary, op.method, op.path)
+		fmt.Fprintf(&b, "  %s(%s): Promise<%s> {\n", method, strings.Join(args, ", "), result)
+		fmt.Fprintf(&b, "    return this.request<%s>('%s', `%s`%s);\n  }\n", result, op.method, path, optionsExpr)
+
+		sc := s.responseSchema(op)
+		if op.Paginated && sc != nil && sc.Items != nil && len(query) > 0 {
+			item := tsType(sc.Items)
+			iterArgs := append([]string{}, args[:len(args)-1]...)
+			iterArgs = append(iterArgs, "params: Omit<"+name+"Params, 'limit' | 'offset'> = {}", "pageSize?: number")
+			callArgs := append(append([]string{}, pathArgs...), "{ ...params, limit, offset }")
+			fmt.Fprintf(&b, "\n  /** Iterates every item of %s, requesting pages of pageSize items */\n", method)
+			fmt.Fprintf(&b, "  %sIter(%s): AsyncGenerator<%s> {\n", method, strings.Join(iterArgs, ", "), item)
+			fmt.Fprintf(&b, "    return paginate(pageSize, (limit, offset) => this.%s(%s));\n  }\n", method, strings.Join(callArgs, ", "))
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// goType returns the Go type of a schema
+func goType(sc *schema) string {
+	if sc == nil {
+		return "interface{}"
+	}
+	if sc.Ref != "" {
+		return refName(sc.Ref)
+	}
+	switch sc.Type {
+	case "string":
+		if sc.Format == "date-time" {
+			if sc.Nullable {
+				return "*time.Time"
+			}
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(sc.Items)
+	case "object":
+		if sc.AdditionalProperties != nil {
+			return "map[string]" + goType(sc.AdditionalProperties)
+		}
+	}
+	return "interface{}"
+}
+
+// tsType returns the TypeScript type of a schema
+func tsType(sc *schema) string {
+	if sc == nil {
+		return "unknown"
+	}
+	if sc.Ref != "" {
+		return refName(sc.Ref)
+	}
+	var t string
+	switch sc.Type {
+	case "string":
+		t = "string"
+	case "integer", "number":
+		t = "number"
+	case "boolean":
+		t = "boolean"
+	case "array":
+		t = tsType(sc.Items) + "[]"
+	case "object":
+		t = "unknown"
+		if sc.AdditionalProperties != nil {
+			t = "Record<string, " + tsType(sc.AdditionalProperties) + ">"
+		}
+	default:
+		t = "unknown"
+	}
+	if sc.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+// goName converts a snake_case or camelCase name into an exported Go identifier
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		if initialisms[strings.ToLower(word)] {
+			if strings.ToLower(word) == "ids" {
+				b.WriteString("IDs")
+			} else {
+				b.WriteString(strings.ToUpper(word))
+			}
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// goArg converts a parameter name into an unexported Go identifier ("id" -> "id", "rental_id" -> "rentalID")
+func goArg(name string) string {
+	parts := words(name)
+	return strings.ToLower(parts[0]) + goName(strings.Join(parts[1:], "_"))
+}
+
+// words splits a snake_case, kebab-case or camelCase name into its words
+func words(name string) []string {
+	var result []string
+	var current []rune
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-':
+			if len(current) > 0 {
+				result = append(result, string(current))
+			}
+			current = nil
+		case unicode.IsUpper(r) && len(current) > 0:
+			result = append(result, string(current))
+			current = []rune{r}
+		default:
+			current = append(current, r)
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, string(current))
+	}
+	return result
+}
+
+// splitWords returns a CamelCase name as lower case words ("RentPayment" -> "rent payment")
+func splitWords(name string) string {
+	return strings.ToLower(strings.Join(words(name), " "))
+}
+
+// describe returns the description of a schema, fallback when it has none
+func describe(sc *schema, fallback string) string {
+	if sc.Description != "" {
+		return sc.Description
+	}
+	return fallback
+}
+
+// writeComment writes a doc comment
+func writeComment(b *bytes.Buffer, indent, text string) {
+	fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(text))
+}
+
+// methodConst returns the net/http constant suffix of a method ("GET" -> "Get")
+func methodConst(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// refName returns the type name of a schema reference ("#/components/schemas/model.Person" -> "Person")
+func refName(ref string) string {
+	return typeName(ref[strings.LastIndex(ref, "/")+1:])
+}
+
+// typeName returns the name of a schema without the Go package of its type ("model.Person" -> "Person")
+func typeName(key string) string {
+	return key[strings.LastIndex(key, ".")+1:]
+}
+
+// schemaKeys returns the keys of the schemas sorted by type name
+func (s *spec) schemaKeys() []string {
+	keys := sortedKeys(s.Components.Schemas)
+	sort.SliceStable(keys, func(i, j int) bool { return typeName(keys[i]) < typeName(keys[j]) })
+	return keys
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
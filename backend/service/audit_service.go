@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// AuditService records the actions kept in the audit log and lists them for the admins
+type AuditService struct {
+	repo storage.AuditLogStore
+}
+
+// NewAuditService creates an AuditService
+func NewAuditService(repo storage.AuditLogStore) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record adds an entry to the audit log. A failure is logged with the entry, so the action is
+// still traceable in the logs.
+func (s *AuditService) Record(ctx context.Context, entry model.AuditLog) {
+	if _, err := s.repo.Create(ctx, entry); err != nil {
+		impersonatedBy := ""
+		if entry.ImpersonatedBy != nil {
+			impersonatedBy = entry.ImpersonatedBy.String()
+		}
+		logging.FromContext(ctx).Error("could not record", "component", "audit", "action", entry.Action, "entity", entry.Entity, "changed_by", entry.ChangedBy, "impersonated_by", impersonatedBy, "error", err)
+	}
+}
+
+// List returns the entries matching filter, newest first
+func (s *AuditService) List(ctx context.Context, filter storage.AuditLogFilter) ([]model.AuditLog, error) {
+	return s.repo.List(ctx, filter)
+}
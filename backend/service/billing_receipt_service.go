@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// BillingReceiptService issues the numbered PDF receipts (cuentas de cobro) attached to the
+// monthly rent reminders and stores them with the files of the rental
+type BillingReceiptService struct {
+	repo             storage.BillingReceiptStore
+	fileMetadataRepo storage.FileMetadataStore
+	numbering        *DocumentNumberingService
+	mu               sync.Mutex // Serializes the issuing so a period never gets two receipts
+}
+
+var billingReceipts *BillingReceiptService
+
+// InitializeBillingReceipts creates the service issuing the receipts of the rent reminders,
+// numbered by numbering
+func InitializeBillingReceipts(repoFactory *storage.RepositoryFactory, numbering *DocumentNumberingService) *BillingReceiptService {
+	billingReceipts = &BillingReceiptService{
+		repo:             repoFactory.GetBillingReceiptRepository(),
+		fileMetadataRepo: repoFactory.GetFileMetadataRepository(),
+		numbering:        numbering,
+	}
+	return billingReceipts
+}
+
+// GetBillingReceipts returns the billing receipt service, nil when reminders are sent without receipts
+func GetBillingReceipts() *BillingReceiptService {
+	return billingReceipts
+}
+
+// IssuedBillingReceipt is a receipt with its PDF
+type IssuedBillingReceipt struct {
+	Receipt model.BillingReceipt
+	PDF     []byte
+}
+
+// BillingReceiptFileName returns the name the PDF of a receipt is stored and attached under
+func BillingReceiptFileName(receipt model.BillingReceipt) string {
+	return fmt.Sprintf("cuenta_de_cobro_%s.pdf", receipt.FormattedNumber())
+}
+
+// attachment returns the receipt as an attachment of a reminder
+func (r *IssuedBillingReceipt) attachment() reminderAttachment {
+	return reminderAttachment{
+		Path: r.Receipt.FilePath,
+		EmailAttachment: EmailAttachment{
+			Name:        BillingReceiptFileName(r.Receipt),
+			ContentType: "application/pdf",
+			Data:        r.PDF,
+		},
+	}
+}
+
+// Issue returns the receipt of a rental for the billing period of issuedAt, issuing it with the
+// next number of the organization and year when the period has none yet. data is the billing
+// email the receipt reproduces; its NumeroCuenta is set to the receipt number. Returns nil when
+// s is nil.
+func (s *BillingReceiptService) Issue(ctx context.Context, rentalID, personID uuid.UUID, issuedAt time.Time, data *BillingEmailData, total float64) (*IssuedBillingReceipt, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	period := model.BillingPeriod(issuedAt.In(OrganizationLocation(data.organization)))
+	receipt, err := s.repo.GetByRentalAndPeriod(ctx, rentalID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt != nil {
+		// Already issued, e.g. the job ran twice: send the same document again
+		data.NumeroCuenta = receipt.FormattedNumber()
+		if storageService := GetSupabaseStorageService(); storageService != nil && receipt.FilePath != "" {
+			pdf, err := storageService.DownloadFile(receipt.FilePath)
+			if err == nil {
+				return &IssuedBillingReceipt{Receipt: *receipt, PDF: pdf}, nil
+			}
+			logging.FromContext(ctx).Warn("could not load receipt, generating it again", "component", "receipts", "formatted_number", receipt.FormattedNumber(), "error", err)
+		}
+	} else {
+		number, err := s.numbering.Next(ctx, data.organization, model.DocumentKindBillingReceipt, issuedAt)
+		if err != nil {
+			return nil, err
+		}
+		var organizationID *uuid.UUID
+		if data.organization != nil {
+			organizationID = &data.organization.ID
+		}
+		receipt, err = s.repo.Create(ctx, model.BillingReceipt{
+			OrganizationID: organizationID,
+			Year:           number.Year,
+			Number:         number.Number,
+			RentalID:       rentalID,
+			PersonID:       personID,
+			Period:         period,
+			Total:          total,
+			IssuedAt:       issuedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.NumeroCuenta = receipt.FormattedNumber()
+		logging.FromContext(ctx).Info("issued receipt for rental", "component", "receipts", "formatted_number", receipt.FormattedNumber(), "rental_id", rentalID, "period", period)
+	}
+
+	pdf, err := GenerateBillingReceiptPDF(*receipt, *data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate receipt %s: %w", receipt.FormattedNumber(), err)
+	}
+
+	issued := &IssuedBillingReceipt{Receipt: *receipt, PDF: pdf}
+	if filePath, err := s.store(ctx, *receipt, pdf); err != nil {
+		logging.FromContext(ctx).Warn("receipt could not be stored with the files of rental", "component", "receipts", "formatted_number", receipt.FormattedNumber(), "rental_id", rentalID, "error", err)
+	} else {
+		issued.Receipt.FilePath = filePath
+	}
+	return issued, nil
+}
+
+// store saves the PDF of a receipt with the files of its rental and returns its path
+func (s *BillingReceiptService) store(ctx context.Context, receipt model.BillingReceipt, pdf []byte) (string, error) {
+	storageService := GetSupabaseStorageService()
+	if storageService == nil {
+		return "", fmt.Errorf("file storage is not available")
+	}
+
+	fileName := BillingReceiptFileName(receipt)
+	filePath := fmt.Sprintf("rentals/%s/cuentas_de_cobro/%s", receipt.RentalID, fileName)
+	if _, err := storageService.UploadBytes(filePath, pdf, "application/pdf"); err != nil {
+		return "", err
+	}
+
+	rentalID := receipt.RentalID
+	if _, err := s.fileMetadataRepo.Save(ctx, model.FileMetadata{
+		FileName:     fileName,
+		Path:         filePath,
+		OriginalName: fileName,
+		Size:         int64(len(pdf)),
+		MimeType:     "application/pdf",
+		UserID:       "system",
+		Category:     model.FileCategoryOtro,
+		Tags:         []string{"cuenta de cobro", receipt.Period},
+		RentalID:     &rentalID,
+		UploadedBy:   "system",
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return "", err
+	}
+
+	if err := s.repo.UpdateFilePath(ctx, receipt.ID, filePath); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// GenerateBillingReceiptPDF renders the cuenta de cobro of a receipt with the same data as the
+// monthly billing email
+func GenerateBillingReceiptPDF(receipt model.BillingReceipt, data BillingEmailData) ([]byte, error) {
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	if !addPDFBranding(pdf, data.organization) {
+		pdf.SetFont(pdfFontFamily, "B", 12)
+		pdf.MultiCell(0, 6, strings.ToUpper(data.EmisorNombre), "", "C", false)
+		if contact := brandingContactLine(model.OrganizationBranding{NIT: data.EmisorNIT, Address: data.EmisorDireccion, Phone: data.EmisorTelefono, Email: data.EmisorEmail}); contact != "" {
+			pdf.SetFont(pdfFontFamily, "", 9)
+			pdf.MultiCell(0, 5, contact, "", "C", false)
+		}
+		pdf.Ln(6)
+	}
+
+	pdf.SetFont(pdfFontFamily, "B", 13)
+	pdf.MultiCell(0, 7, fmt.Sprintf("CUENTA DE COBRO ARRENDAMIENTO N° %s", receipt.FormattedNumber()), "", "C", false)
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 6, fmt.Sprintf("Fecha de emisión: %s · Periodo: %s", FormatterFor(data.organization).Date(receipt.IssuedAt), receipt.Period), "", "C", false)
+	pdf.Ln(4)
+
+	receiptSection(pdf, "ARRENDATARIO")
+	receiptField(pdf, "Nombre", data.ArrendatarioNombre)
+	receiptField(pdf, "NIT/Cédula", data.ArrendatarioNIT)
+	receiptField(pdf, "Inmueble", data.InmuebleDireccion)
+	receiptField(pdf, "Tipo de inmueble", data.TipoInmueble)
+	receiptField(pdf, "Vigencia del contrato", fmt.Sprintf("%s a %s", data.FechaInicio, data.FechaFinal))
+	pdf.Ln(3)
+
+	receiptSection(pdf, "DETALLE DEL COBRO")
+	widths := []float64{80, 30, 30, 30}
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	for i, header := range []string{"CONCEPTO", "VALOR", "IVA", "TOTAL"} {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(7)
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	for _, concept := range data.Conceptos {
+		pdf.CellFormat(widths[0], 6, truncatePDFText(pdf, concept.Concepto, widths[0]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, concept.Valor, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, concept.IVA, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, concept.Total, "1", 0, "R", false, 0, "")
+		pdf.Ln(6)
+	}
+
+	labelWidth := widths[0] + widths[1] + widths[2]
+	for _, total := range []struct{ label, value string }{
+		{"SUBTOTAL", data.Subtotal},
+		{"IVA", data.IVA},
+		{"TOTAL A PAGAR", data.TotalPagar},
+	} {
+		pdf.SetFont(pdfFontFamily, "B", 9)
+		pdf.CellFormat(labelWidth, 7, total.label, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, total.value, "1", 0, "R", false, 0, "")
+		pdf.Ln(7)
+	}
+	pdf.Ln(3)
+
+	if data.UnpaidMonths > 0 {
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.SetTextColor(180, 0, 0)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Pagos atrasados: %d meses sin pagar. Monto total adeudado: %s", data.UnpaidMonths, data.TotalDue), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(3)
+	}
+
+	receiptSection(pdf, "CONDICIONES Y DATOS DE PAGO")
+	receiptField(pdf, "Condiciones", data.CondicionesPago)
+	if data.FechaLimitePago != "" {
+		receiptField(pdf, "Fecha límite de pago", data.FechaLimitePago)
+	}
+	receiptField(pdf, "Banco", data.Banco)
+	receiptField(pdf, "Tipo de cuenta", data.TipoCuenta)
+	receiptField(pdf, "Número de cuenta", data.NumeroCuentaBancaria)
+	receiptField(pdf, "Titular", data.TitularCuenta)
+	if data.Observaciones != "" {
+		receiptField(pdf, "Observaciones", data.Observaciones)
+	}
+	pdf.Ln(12)
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.CellFormat(70, 5, "", "B", 1, "L", false, 0, "")
+	pdf.MultiCell(0, 5, data.ArrendadorNombre, "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptSection prints the title of a section of a receipt
+func receiptSection(pdf *gofpdf.Fpdf, title string) {
+	pdf.SetFont(pdfFontFamily, "B", 10)
+	pdf.CellFormat(0, 7, title, "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+}
+
+// receiptField prints a labeled value of a receipt, wrapping long values
+func receiptField(pdf *gofpdf.Fpdf, label, value string) {
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.CellFormat(45, 5, label+":", "", 0, "L", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.MultiCell(0, 5, value, "", "L", false)
+}
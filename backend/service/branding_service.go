@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// Branding defaults for the parts an organization leaves empty
+const (
+	defaultBrandPrimaryColor   = "#1f2937"
+	defaultBrandSecondaryColor = "#6b7280"
+	brandLogoMaxBytes          = 2 << 20
+	brandLogoCacheTTL          = time.Hour
+	pdfLogoHeight              = 16.0 // mm
+)
+
+// brandColorPattern matches the hex colors of a branding
+var brandColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ValidateBranding normalizes a branding and returns the message ID of the error when it is
+// invalid
+func ValidateBranding(branding *model.OrganizationBranding) string {
+	if branding == nil {
+		return ""
+	}
+	for _, field := range []*string{&branding.CompanyName, &branding.NIT, &branding.Address, &branding.Phone, &branding.Email, &branding.LogoURL, &branding.PrimaryColor, &branding.SecondaryColor, &branding.FooterText} {
+		*field = strings.TrimSpace(*field)
+	}
+
+	if branding.LogoURL != "" {
+		parsed, err := url.Parse(branding.LogoURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "InvalidBrandingLogoURL"
+		}
+	}
+	if branding.PrimaryColor != "" && !brandColorPattern.MatchString(branding.PrimaryColor) {
+		return "InvalidBrandingPrimaryColor"
+	}
+	if branding.SecondaryColor != "" && !brandColorPattern.MatchString(branding.SecondaryColor) {
+		return "InvalidBrandingSecondaryColor"
+	}
+	return ""
+}
+
+// BrandingFor returns the branding of an organization with its name as the company name when
+// none is set. ok is false for a nil organization or one without branding, whose documents
+// keep the look of the platform.
+func BrandingFor(org *model.Organization) (branding model.OrganizationBranding, ok bool) {
+	if org == nil || org.Branding == nil {
+		return model.OrganizationBranding{}, false
+	}
+	branding = *org.Branding
+	if branding.CompanyName == "" {
+		branding.CompanyName = org.Name
+	}
+	if branding.PrimaryColor == "" {
+		branding.PrimaryColor = defaultBrandPrimaryColor
+	}
+	if branding.SecondaryColor == "" {
+		branding.SecondaryColor = defaultBrandSecondaryColor
+	}
+	return branding, true
+}
+
+// brandingContactLine joins the NIT and contact data of a branding in one line
+func brandingContactLine(branding model.OrganizationBranding) string {
+	var parts []string
+	if branding.NIT != "" {
+		parts = append(parts, "NIT "+branding.NIT)
+	}
+	if branding.Address != "" {
+		parts = append(parts, branding.Address)
+	}
+	if branding.Phone != "" {
+		parts = append(parts, "Tel. "+branding.Phone)
+	}
+	if branding.Email != "" {
+		parts = append(parts, branding.Email)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// brandEmail adds the header with the logo or company name and the footer with the contact
+// data of an organization to the HTML body of an email. Bodies of organizations without
+// branding are returned unchanged.
+func brandEmail(body string, org *model.Organization) string {
+	branding, ok := BrandingFor(org)
+	if !ok {
+		return body
+	}
+
+	title := html.EscapeString(branding.CompanyName)
+	heading := fmt.Sprintf(`<span style="color:#ffffff;font-size:20px;font-weight:bold;font-family:Arial,sans-serif">%s</span>`, title)
+	if branding.LogoURL != "" {
+		heading = fmt.Sprintf(`<img src="%s" alt="%s" style="max-height:60px;max-width:240px">`, html.EscapeString(branding.LogoURL), title)
+	}
+	header := fmt.Sprintf(`<div style="background-color:%s;padding:16px;text-align:center">%s</div>`, branding.PrimaryColor, heading)
+
+	footerLines := []string{"<strong>" + title + "</strong>"}
+	if contact := brandingContactLine(branding); contact != "" {
+		footerLines = append(footerLines, html.EscapeString(contact))
+	}
+	if branding.FooterText != "" {
+		footerLines = append(footerLines, html.EscapeString(branding.FooterText))
+	}
+	footer := fmt.Sprintf(`<div style="border-top:3px solid %s;margin-top:24px;padding:12px;text-align:center;font-family:Arial,sans-serif;font-size:12px;color:%s">%s</div>`,
+		branding.PrimaryColor, branding.SecondaryColor, strings.Join(footerLines, "<br>"))
+
+	return insertBeforeBodyClose(insertAfterBodyTag(body, header), footer)
+}
+
+// insertAfterBodyTag inserts content at the start of the <body> of an HTML document, or at
+// its start when it has no body tag
+func insertAfterBodyTag(document, content string) string {
+	start := strings.Index(strings.ToLower(document), "<body")
+	if start < 0 {
+		return content + document
+	}
+	end := strings.Index(document[start:], ">")
+	if end < 0 {
+		return content + document
+	}
+	end += start + 1
+	return document[:end] + content + document[end:]
+}
+
+// insertBeforeBodyClose inserts content at the end of the <body> of an HTML document, or at
+// its end when it has no closing body tag
+func insertBeforeBodyClose(document, content string) string {
+	end := strings.LastIndex(strings.ToLower(document), "</body>")
+	if end < 0 {
+		return document + content
+	}
+	return document[:end] + content + document[end:]
+}
+
+// brandLogo is a downloaded logo, cached so every receipt of a run does not download it again
+type brandLogo struct {
+	data      []byte
+	imageType string
+	loadedAt  time.Time
+}
+
+var (
+	brandLogosMu sync.Mutex
+	brandLogos   = map[string]brandLogo{}
+)
+
+// loadBrandLogo downloads a PNG or JPEG logo
+func loadBrandLogo(logoURL string) (brandLogo, error) {
+	brandLogosMu.Lock()
+	cached, ok := brandLogos[logoURL]
+	brandLogosMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < brandLogoCacheTTL {
+		return cached, nil
+	}
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Get(logoURL)
+	if err != nil {
+		return brandLogo{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return brandLogo{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, brandLogoMaxBytes+1))
+	if err != nil {
+		return brandLogo{}, err
+	}
+	if len(data) > brandLogoMaxBytes {
+		return brandLogo{}, fmt.Errorf("the logo is larger than %d bytes", brandLogoMaxBytes)
+	}
+
+	logo := brandLogo{data: data, loadedAt: time.Now()}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		logo.imageType = "png"
+	case "image/jpeg":
+		logo.imageType = "jpg"
+	default:
+		return brandLogo{}, fmt.Errorf("the logo is not a PNG or JPEG image")
+	}
+
+	brandLogosMu.Lock()
+	brandLogos[logoURL] = logo
+	brandLogosMu.Unlock()
+	return logo, nil
+}
+
+// pdfColor returns the RGB components of a hex color
+func pdfColor(hex string) (int, int, int) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return 0, 0, 0
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+// addPDFBranding prints the logo, company name and contact data of an organization at the top
+// of the current page, and its footer text at the bottom of every page. Returns false, printing
+// nothing, for organizations without branding. A logo that cannot be loaded is left out.
+func addPDFBranding(pdf *gofpdf.Fpdf, org *model.Organization) bool {
+	branding, ok := BrandingFor(org)
+	if !ok {
+		return false
+	}
+
+	if branding.LogoURL != "" {
+		if logo, err := loadBrandLogo(branding.LogoURL); err != nil {
+			slog.Warn("logo left out of the PDF", "component", "branding", "company_name", branding.CompanyName, "error", err)
+		} else {
+			options := gofpdf.ImageOptions{ImageType: logo.imageType}
+			info := pdf.RegisterImageOptionsReader(branding.LogoURL, options, bytes.NewReader(logo.data))
+			if pdf.Err() || info == nil || info.Height() == 0 {
+				slog.Warn("logo is not a valid image", "component", "branding", "company_name", branding.CompanyName, "error", pdf.Error())
+				pdf.ClearError()
+			} else {
+				pageWidth, _ := pdf.GetPageSize()
+				width := pdfLogoHeight * info.Width() / info.Height()
+				pdf.ImageOptions(branding.LogoURL, (pageWidth-width)/2, pdf.GetY(), width, pdfLogoHeight, false, options, 0, "")
+				pdf.SetY(pdf.GetY() + pdfLogoHeight + 2)
+			}
+		}
+	}
+
+	r, g, b := pdfColor(branding.PrimaryColor)
+	pdf.SetTextColor(r, g, b)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 6, strings.ToUpper(branding.CompanyName), "", "C", false)
+	pdf.SetTextColor(0, 0, 0)
+	if contact := brandingContactLine(branding); contact != "" {
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.MultiCell(0, 5, contact, "", "C", false)
+	}
+	pdf.SetDrawColor(r, g, b)
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	pdf.Line(left, pdf.GetY()+2, pageWidth-right, pdf.GetY()+2)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(6)
+
+	if branding.FooterText != "" {
+		sr, sg, sb := pdfColor(branding.SecondaryColor)
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-15)
+			pdf.SetFont(pdfFontFamily, "I", 7)
+			pdf.SetTextColor(sr, sg, sb)
+			pdf.MultiCell(0, 3.5, branding.FooterText, "", "C", false)
+			pdf.SetTextColor(0, 0, 0)
+		})
+	}
+	return true
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultBucketBackupSchedule respalda el bucket todas las noches a las 2:00
+	defaultBucketBackupSchedule = "0 2 * * *"
+	// recentBucketBackups es la cantidad de backups que se listan
+	recentBucketBackups = 50
+	// bucketBackupManifestPrefix es la carpeta de los manifiestos en los proveedores de backup
+	bucketBackupManifestPrefix = "manifests"
+)
+
+var (
+	// ErrBucketBackupRunning indica que ya hay un backup del bucket en curso
+	ErrBucketBackupRunning = errors.New("ya hay un backup del bucket en curso")
+	// ErrBucketBackupNotFound indica que el backup no existe
+	ErrBucketBackupNotFound = errors.New("backup no encontrado")
+	// ErrBucketBackupDisabled indica que no hay almacenamiento de archivos o proveedores de backup
+	ErrBucketBackupDisabled = errors.New("no hay proveedores de backup configurados (FILE_BACKUP_PROVIDERS)")
+)
+
+// BucketRestoreResult es el resultado de restaurar un archivo de un backup del bucket
+type BucketRestoreResult struct {
+	Path     string `json:"path"`
+	Success  bool   `json:"success"`
+	Provider string `json:"provider,omitempty"` // Proveedor del que se recuperó
+	Error    string `json:"error,omitempty"`
+}
+
+// BucketBackupService copia todo el bucket de archivos a los proveedores de backup de
+// FILE_BACKUP_PROVIDERS con la programación de BUCKET_BACKUP_SCHEDULE (todas las noches por
+// defecto). El primer backup copia todos los archivos y los siguientes solo los nuevos o
+// modificados desde el anterior. Cada backup guarda un manifiesto con la ruta, el tamaño y el
+// hash de cada archivo y dónde está su copia, con el que se pueden restaurar.
+type BucketBackupService struct {
+	repo     storage.BucketBackupStore
+	schedule string
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewBucketBackupService crea el servicio de backups del bucket, que corre con schedule
+// (BUCKET_BACKUP_SCHEDULE)
+func NewBucketBackupService(factory *storage.RepositoryFactory, schedule string) *BucketBackupService {
+	return &BucketBackupService{
+		repo:     factory.GetBucketBackupRepository(),
+		schedule: schedule,
+	}
+}
+
+// Start programa el backup nocturno. BUCKET_BACKUP_SCHEDULE=off lo deshabilita.
+func (b *BucketBackupService) Start() error {
+	schedule := b.schedule
+	if schedule == "" {
+		schedule = defaultBucketBackupSchedule
+	}
+	if schedule == "off" {
+		slog.Info("backup programado del bucket deshabilitado (BUCKET_BACKUP_SCHEDULE=off)")
+		return nil
+	}
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("bucket_backup") {
+			return
+		}
+		start := time.Now()
+		_, err := b.Trigger(model.BucketBackupDelta)
+		if errors.Is(err, ErrBucketBackupDisabled) {
+			return
+		}
+		metrics.JobRun("bucket_backup", start, err)
+		if err != nil {
+			slog.Error("error iniciando el backup del bucket", "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("programación inválida del backup del bucket: %w", err)
+	}
+	c.Start()
+	stopCronOnShutdown("bucket backup", c)
+	slog.Info("backup del bucket programado", "schedule", schedule)
+	return nil
+}
+
+// List devuelve los últimos backups, sin sus manifiestos
+func (b *BucketBackupService) List(ctx context.Context) ([]model.BucketBackup, error) {
+	return b.repo.GetRecent(ctx, recentBucketBackups)
+}
+
+// Get devuelve un backup con su manifiesto
+func (b *BucketBackupService) Get(ctx context.Context, id uuid.UUID) (*model.BucketBackup, error) {
+	backup, err := b.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if backup == nil {
+		return nil, ErrBucketBackupNotFound
+	}
+	return backup, nil
+}
+
+// Trigger inicia un backup del bucket en segundo plano y devuelve su registro. Con el modo delta
+// se copian solo los archivos nuevos o modificados desde el último backup; si no hay uno
+// anterior se copian todos.
+func (b *BucketBackupService) Trigger(mode string) (*model.BucketBackup, error) {
+	s := GetSupabaseStorageService()
+	backups := GetFileBackups()
+	if s == nil || !backups.Enabled() {
+		return nil, ErrBucketBackupDisabled
+	}
+
+	b.mu.Lock()
+	if b.running {
+		b.mu.Unlock()
+		return nil, ErrBucketBackupRunning
+	}
+	b.running = true
+	b.mu.Unlock()
+
+	ctx := context.Background()
+	var previous *model.BucketBackup
+	if mode != model.BucketBackupFull {
+		mode = model.BucketBackupDelta
+		latest, err := b.repo.GetLatestFinished(ctx)
+		if err != nil {
+			b.finish()
+			return nil, err
+		}
+		if latest == nil {
+			mode = model.BucketBackupFull
+		}
+		previous = latest
+	}
+
+	backup, err := b.repo.Create(ctx, model.BucketBackup{
+		Status:    model.BucketBackupRunning,
+		Mode:      mode,
+		Providers: backups.Providers(),
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		b.finish()
+		return nil, err
+	}
+
+	runInBackground(func() {
+		defer b.finish()
+		b.run(ctx, s, backups, backup, previous)
+	})
+	return backup, nil
+}
+
+func (b *BucketBackupService) finish() {
+	b.mu.Lock()
+	b.running = false
+	b.mu.Unlock()
+}
+
+// run copia los archivos del bucket y guarda el manifiesto del backup
+func (b *BucketBackupService) run(ctx context.Context, s *SupabaseStorageService, backups *FileBackupRegistry, backup *model.BucketBackup, previous *model.BucketBackup) {
+	logging.FromContext(ctx).Info("iniciando backup del bucket", "mode", backup.Mode, "bucket_name", s.bucketName)
+
+	copied := make(map[string]model.BucketBackupEntry)
+	if previous != nil {
+		for _, entry := range previous.Manifest {
+			copied[entry.Path] = entry
+		}
+	}
+
+	paths, files, err := s.walkBucket("")
+	if err != nil {
+		backup.Status = model.BucketBackupFailed
+		backup.Error = err.Error()
+		b.save(ctx, backup)
+		return
+	}
+
+	backup.Manifest = make([]model.BucketBackupEntry, 0, len(paths))
+	for i, path := range paths {
+		file := files[i]
+		entry := model.BucketBackupEntry{
+			Path:      path,
+			Size:      file.Size,
+			UpdatedAt: file.UpdatedAt,
+			Locations: make(map[string]string),
+		}
+		if entry.UpdatedAt == "" {
+			entry.UpdatedAt = file.CreatedAt
+		}
+
+		previousEntry, found := copied[path]
+		if found && previousEntry.Size == entry.Size && previousEntry.UpdatedAt == entry.UpdatedAt && hasBackupLocations(previousEntry, backups) {
+			backup.Manifest = append(backup.Manifest, previousEntry)
+			backup.FileCount++
+			backup.TotalSize += entry.Size
+			continue
+		}
+
+		data, err := s.files.Download(s.bucketName, path)
+		if err != nil {
+			entry.Error = fmt.Sprintf("error descargando archivo: %v", err)
+		} else {
+			entry.SHA256 = sha256Hex(data)
+			// Un archivo que solo cambió de fecha conserva las copias anteriores
+			if found && previousEntry.SHA256 == entry.SHA256 && hasBackupLocations(previousEntry, backups) {
+				entry.Locations = previousEntry.Locations
+			} else {
+				entry.Locations, err = backups.backupTo(ctx, BackupFile{
+					Data:     data,
+					FileName: filepath.Base(path),
+					Path:     path,
+					UserID:   s.extractUserIDFromPath(path),
+				})
+				if err != nil {
+					entry.Error = err.Error()
+				}
+				if len(entry.Locations) > 0 {
+					backup.Copied++
+				}
+			}
+		}
+		if entry.Error != "" {
+			backup.Failed++
+		}
+		backup.Manifest = append(backup.Manifest, entry)
+		backup.FileCount++
+		backup.TotalSize += entry.Size
+	}
+
+	// El manifiesto también se guarda con los proveedores, para poder restaurar sin la base de datos
+	manifest, err := json.MarshalIndent(backup, "", "  ")
+	if err == nil {
+		name := fmt.Sprintf("%s_%s.json", backup.StartedAt.UTC().Format("20060102T150405Z"), backup.ID)
+		backup.ManifestLocations, err = backups.backupTo(ctx, BackupFile{
+			Data:     manifest,
+			FileName: name,
+			Path:     bucketBackupManifestPrefix + "/" + name,
+		})
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("error guardando el manifiesto del backup", "backup_id", backup.ID, "error", err)
+		backup.Error = fmt.Sprintf("error guardando el manifiesto: %v", err)
+	}
+
+	backup.Status = model.BucketBackupCompleted
+	if backup.Failed > 0 || backup.Error != "" {
+		backup.Status = model.BucketBackupPartial
+	}
+	b.save(ctx, backup)
+	logging.FromContext(ctx).Info("backup del bucket terminado: archivos, copiados, con error", "file_count", backup.FileCount, "copied", backup.Copied, "failed", backup.Failed)
+}
+
+func (b *BucketBackupService) save(ctx context.Context, backup *model.BucketBackup) {
+	now := time.Now()
+	backup.FinishedAt = &now
+	if err := b.repo.SaveResult(ctx, *backup); err != nil {
+		logging.FromContext(ctx).Error("error guardando el backup del bucket", "backup_id", backup.ID, "error", err)
+	}
+}
+
+// Restore recupera archivos de un backup a su ruta original del bucket. Sin paths se restauran
+// todos los archivos del manifiesto. Sin overwrite los archivos que ya existen no se reemplazan.
+// El contenido recuperado se verifica con el hash del manifiesto.
+func (b *BucketBackupService) Restore(ctx context.Context, id uuid.UUID, paths []string, overwrite bool) ([]BucketRestoreResult, error) {
+	s := GetSupabaseStorageService()
+	backups := GetFileBackups()
+	if s == nil || !backups.Enabled() {
+		return nil, ErrBucketBackupDisabled
+	}
+	backup, err := b.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]model.BucketBackupEntry, len(backup.Manifest))
+	for _, entry := range backup.Manifest {
+		entries[entry.Path] = entry
+	}
+	if len(paths) == 0 {
+		for _, entry := range backup.Manifest {
+			paths = append(paths, entry.Path)
+		}
+	}
+
+	results := make([]BucketRestoreResult, 0, len(paths))
+	for _, path := range paths {
+		result := BucketRestoreResult{Path: path}
+		entry, ok := entries[path]
+		switch {
+		case !ok:
+			result.Error = "el archivo no está en el backup"
+		case len(entry.Locations) == 0:
+			result.Error = "el archivo no se respaldó en este backup"
+		default:
+			result.Provider, err = b.restoreEntry(ctx, s, backups, entry, overwrite)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Success = true
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// restoreEntry recupera un archivo del primer proveedor que lo tenga y lo sube al bucket
+func (b *BucketBackupService) restoreEntry(ctx context.Context, s *SupabaseStorageService, backups *FileBackupRegistry, entry model.BucketBackupEntry, overwrite bool) (string, error) {
+	var errs []error
+	for _, provider := range backups.providers {
+		location, ok := entry.Locations[provider.Name()]
+		if !ok {
+			continue
+		}
+		data, err := provider.Restore(ctx, location)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if entry.SHA256 != "" && sha256Hex(data) != entry.SHA256 {
+			errs = append(errs, fmt.Errorf("%s: el contenido no coincide con el hash del manifiesto", provider.Name()))
+			continue
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(entry.Path))
+		if err := s.files.Upload(s.bucketName, entry.Path, bytes.NewReader(data), contentType, overwrite); err != nil {
+			return "", fmt.Errorf("error subiendo archivo: %v", err)
+		}
+		s.index.add(ctx, entry.Path, int64(len(data)), contentType, time.Now())
+		logging.FromContext(ctx).Info("archivo restaurado", "path", entry.Path, "name", provider.Name())
+		return provider.Name(), nil
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("ningún proveedor configurado tiene una copia del archivo")
+	}
+	return "", errors.Join(errs...)
+}
+
+// hasBackupLocations indica si un archivo ya tiene copia en todos los proveedores habilitados
+func hasBackupLocations(entry model.BucketBackupEntry, backups *FileBackupRegistry) bool {
+	if entry.Error != "" {
+		return false
+	}
+	for _, provider := range backups.providers {
+		if entry.Locations[provider.Name()] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// walkBucket lista recursivamente los archivos del bucket bajo prefix, sin la papelera, y
+// devuelve sus rutas completas
+func (s *SupabaseStorageService) walkBucket(prefix string) ([]string, []StoredFile, error) {
+	items, err := s.listFolder(prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listando %q: %v", prefix, err)
+	}
+
+	var paths []string
+	var files []StoredFile
+	for _, item := range items {
+		path := filepath.Base(item.Name)
+		if prefix != "" {
+			path = prefix + "/" + path
+		}
+		if !item.Folder {
+			paths = append(paths, path)
+			files = append(files, item)
+			continue
+		}
+		if path == fileTrashPrefix {
+			continue
+		}
+		subPaths, subFiles, err := s.walkBucket(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		paths = append(paths, subPaths...)
+		files = append(files, subFiles...)
+	}
+	return paths, files, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import "github.com/nescool101/rentManager/config"
+
+// Capability names reported by /api/capabilities and in the 503 responses of disabled features
+const (
+	CapabilityFileStorage    = "file_storage"
+	CapabilityChunkedUploads = "chunked_uploads"
+	CapabilityVirusScan      = "virus_scan"
+	CapabilityFileEncryption = "file_encryption"
+	CapabilityTelegramBackup = "telegram_backup"
+	CapabilityFileBackup     = "file_backup"
+	CapabilitySMS            = "sms"
+	CapabilityGuarantee      = "guarantee"
+	CapabilityNotary         = "notary"
+	CapabilityEInvoice       = "einvoice"
+	CapabilityOnlinePayments = "online_payments"
+	CapabilitySubscriptions  = "subscription_billing"
+)
+
+// Capabilities lists the optional features enabled in this deployment, so the frontend can
+// hide what a self-hosted instance does not provide
+type Capabilities struct {
+	Features           map[string]bool `json:"features"`
+	FileStorageBackend string          `json:"file_storage_backend"`
+}
+
+// CurrentCapabilities reports the optional features that are configured in cfg. virusScans may
+// be nil when the scanner was not created.
+func CurrentCapabilities(cfg *config.Config, virusScans *VirusScanService) Capabilities {
+	backend := FileStorageNone
+	fileStorage := GetSupabaseStorageService()
+	if fileStorage != nil {
+		backend = fileStorage.Backend()
+	}
+
+	_, guaranteeErr := GetGuaranteeProvider(cfg.Guarantee)
+	_, notaryErr := GetNotaryProvider(cfg.Notary)
+	_, gatewayErr := GetPaymentGateway(cfg.Wompi)
+
+	return Capabilities{
+		Features: map[string]bool{
+			CapabilityFileStorage:    fileStorage != nil,
+			CapabilityChunkedUploads: fileStorage != nil,
+			CapabilityVirusScan:      fileStorage != nil && virusScans.Enabled(),
+			CapabilityFileEncryption: fileStorage != nil && fileStorage.Encrypted(),
+			CapabilityTelegramBackup: IsTelegramEnabled() && GetTelegramService() != nil,
+			CapabilityFileBackup:     GetFileBackups().Enabled(),
+			CapabilitySMS:            SMSEnabled(),
+			CapabilityGuarantee:      guaranteeErr == nil,
+			CapabilityNotary:         notaryErr == nil,
+			CapabilityEInvoice:       GetEInvoices() != nil,
+			CapabilityOnlinePayments: gatewayErr == nil,
+			CapabilitySubscriptions:  GetSubscriptions() != nil,
+		},
+		FileStorageBackend: backend,
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultCertificateCheckSchedule checks the signing certificate every day at 8:00 when
+	// CERT_EXPIRY_CHECK_SCHEDULE is not set
+	defaultCertificateCheckSchedule = "0 8 * * *"
+	// defaultCertificateWarningDays is how many days before expiry admins start being warned
+	defaultCertificateWarningDays = 30
+	// certificateRotationCheck is how often the certificate file is checked for a rotation
+	certificateRotationCheck = "@every 1m"
+)
+
+// certificateWarningSteps are the days left at which admins are warned again after the first
+// warning, so they are not emailed every day
+var certificateWarningSteps = []int{14, 7, 3, 1, 0}
+
+// CertificateMonitor warns admins by email before the signing certificate expires and picks up
+// rotated certificates without restarting the server
+type CertificateMonitor struct {
+	userRepo    storage.UserStore
+	warningDays int
+	schedule    string
+
+	mu sync.Mutex
+	// notified is the last warning step emailed for each certificate serial number
+	notified map[string]int
+}
+
+// NewCertificateMonitor creates a CertificateMonitor first warning the admins warningDays before
+// expiry (CERT_EXPIRY_WARNING_DAYS, 30 when unset), checking on schedule
+// (CERT_EXPIRY_CHECK_SCHEDULE, every day at 8:00 when empty)
+func NewCertificateMonitor(userRepo storage.UserStore, warningDays int, schedule string) *CertificateMonitor {
+	return &CertificateMonitor{
+		userRepo:    userRepo,
+		warningDays: orDefault(warningDays, defaultCertificateWarningDays),
+		schedule:    orDefault(schedule, defaultCertificateCheckSchedule),
+		notified:    make(map[string]int),
+	}
+}
+
+// CertificateWarningStep returns the warning step reached with the given days left, -1 when
+// the certificate is not expiring yet. Steps decrease, an expired certificate is step 0.
+func CertificateWarningStep(daysLeft, warningDays int) int {
+	if daysLeft > warningDays {
+		return -1
+	}
+	step := warningDays
+	for _, s := range certificateWarningSteps {
+		if s < warningDays && daysLeft <= s {
+			step = s
+		}
+	}
+	return step
+}
+
+// CheckExpiry reloads a rotated certificate and emails the admins when the signing certificate
+// reaches a new warning step. It returns the days left until the certificate expires.
+func (m *CertificateMonitor) CheckExpiry(ctx context.Context, now time.Time) (int, error) {
+	if _, err := ReloadSigningCertificateIfChanged(); err != nil {
+		logging.FromContext(ctx).Warn("error reloading the rotated signing certificate", "component", "cert", "error", err)
+	}
+
+	cert, err := ActiveSigningCertificate()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load signing certificate: %w", err)
+	}
+
+	daysLeft := cert.DaysUntilExpiry(now)
+	step := CertificateWarningStep(daysLeft, m.warningDays)
+	if step < 0 {
+		return daysLeft, nil
+	}
+
+	serial := fmt.Sprintf("%X", cert.Certificate.SerialNumber)
+	m.mu.Lock()
+	last, warned := m.notified[serial]
+	m.mu.Unlock()
+	if warned && last <= step {
+		return daysLeft, nil
+	}
+
+	if err := m.notifyAdmins(ctx, cert, daysLeft); err != nil {
+		return daysLeft, err
+	}
+
+	m.mu.Lock()
+	m.notified[serial] = step
+	m.mu.Unlock()
+	return daysLeft, nil
+}
+
+// Start schedules the daily expiry check, checks the certificate file for rotations every
+// minute and reloads the certificate on SIGHUP
+func (m *CertificateMonitor) Start() error {
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(m.schedule, func() {
+		if !JobEnabled("certificate_expiry") {
+			return
+		}
+		start := time.Now()
+		daysLeft, err := m.CheckExpiry(context.Background(), start)
+		metrics.JobRun("certificate_expiry", start, err)
+		if err != nil {
+			slog.Error("error checking signing certificate expiry", "component", "cert", "error", err)
+		} else {
+			slog.Info("signing certificate expires days", "component", "cert", "days_left", daysLeft)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid CERT_EXPIRY_CHECK_SCHEDULE %q: %w", m.schedule, err)
+	}
+	if _, err := c.AddFunc(certificateRotationCheck, func() {
+		if _, err := ReloadSigningCertificateIfChanged(); err != nil {
+			slog.Warn("error reloading the rotated signing certificate", "component", "cert", "error", err)
+		}
+	}); err != nil {
+		return err
+	}
+	c.Start()
+	stopCronOnShutdown("certificate monitor", c)
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			if _, err := ReloadSigningCertificate(); err != nil {
+				slog.Warn("error reloading the signing certificate on SIGHUP", "component", "cert", "error", err)
+			}
+		}
+	}()
+
+	slog.Info("signing certificate monitor started", "component", "cert", "schedule", m.schedule)
+	return nil
+}
+
+// notifyAdmins emails every active admin that the signing certificate is about to expire
+func (m *CertificateMonitor) notifyAdmins(ctx context.Context, cert *SigningCertificate, daysLeft int) error {
+	users, err := m.userRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	subject := fmt.Sprintf("El certificado de firma vence en %d días", daysLeft)
+	if daysLeft < 0 {
+		subject = "El certificado de firma está vencido"
+	}
+	body := fmt.Sprintf(`
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Certificado de Firma</title>
+	</head>
+	<body style="font-family: Arial, sans-serif; color: #333;">
+		<h2>%s</h2>
+		<p>El certificado con el que se firman los contratos vence el <strong>%s</strong>.</p>
+		<ul>
+			<li>Titular: %s</li>
+			<li>Emisor: %s</li>
+			<li>Serial: %X</li>
+			<li>Origen: %s</li>
+		</ul>
+		<p>Para renovarlo, reemplace el archivo configurado en SIGNING_CERT_P12_PATH o suba el nuevo
+		certificado en POST /api/admin/signing-certificate. El servidor lo usa sin reiniciarse.</p>
+		<p>Sistema de Administración de Propiedades</p>
+	</body>
+	</html>
+	`, subject, FormatDate(cert.Certificate.NotAfter), cert.Certificate.Subject.CommonName,
+		cert.Certificate.Issuer.CommonName, cert.Certificate.SerialNumber, cert.Source)
+
+	sent := 0
+	for _, user := range users {
+		if user.Role != "admin" || user.Status == "disabled" || user.Email == "" {
+			continue
+		}
+		if err := SendSimpleEmail(user.Email, subject, body); err != nil {
+			logging.FromContext(ctx).Error("error sending certificate expiry warning", "component", "cert", "email", user.Email, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	logging.FromContext(ctx).Warn("signing certificate expires days, admins notified", "component", "cert", "days_left", daysLeft, "sent", sent)
+	return nil
+}
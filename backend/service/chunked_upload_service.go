@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// Errores de las subidas por partes
+var (
+	ErrChunkedUploadNotFound   = errors.New("sesión de subida no encontrada o expirada")
+	ErrChunkedUploadOffset     = errors.New("el offset no coincide con los bytes recibidos")
+	ErrChunkedUploadTooLarge   = errors.New("el archivo supera el tamaño máximo permitido")
+	ErrChunkedUploadIncomplete = errors.New("la subida aún no está completa")
+	ErrChunkedUploadBusy       = errors.New("otra parte de esta subida se está recibiendo")
+)
+
+// ChunkedUploadSessionTTL es el tiempo que una subida sin actividad se conserva para reanudarla
+const ChunkedUploadSessionTTL = 24 * time.Hour
+
+// ChunkedUpload es una subida por partes en curso. Las partes se agregan en orden a un archivo
+// temporal; el cliente consulta Offset para reanudar después de un corte.
+type ChunkedUpload struct {
+	ID          string    `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Offset      int64     `json:"offset"`
+	UserID      string    `json:"-"`
+	UserName    string    `json:"-"`
+	UserRole    string    `json:"-"`
+	Owner       string    `json:"-"` // Token o usuario que puede continuar la subida
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	// Categoría y arriendo o contrato que se registran al completarse
+	Metadata *model.FileMetadata `json:"-"`
+
+	busy bool
+}
+
+// Complete indica si ya se recibieron todos los bytes del archivo
+func (u *ChunkedUpload) Complete() bool {
+	return u.Offset == u.Size
+}
+
+// ChunkedUploadService recibe archivos grandes por partes, los ensambla en disco y los
+// transmite a Supabase Storage al completarse
+type ChunkedUploadService struct {
+	mu           sync.Mutex
+	uploads      map[string]*ChunkedUpload
+	dir          string
+	maxSize      int64
+	maxChunkSize int64
+}
+
+// NewChunkedUploadService crea el servicio con cfg. CHUNKED_UPLOAD_DIR define la carpeta
+// temporal, CHUNKED_UPLOAD_MAX_SIZE_MB el tamaño máximo de archivo (2048 por defecto) y
+// CHUNKED_UPLOAD_CHUNK_SIZE_MB el tamaño máximo de cada parte (8 por defecto)
+func NewChunkedUploadService(cfg config.ChunkedUploadConfig) *ChunkedUploadService {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "rentmanager_uploads")
+	}
+
+	return &ChunkedUploadService{
+		uploads:      make(map[string]*ChunkedUpload),
+		dir:          dir,
+		maxSize:      orDefault(cfg.MaxSizeMB, 2048) * 1024 * 1024,
+		maxChunkSize: orDefault(cfg.ChunkSizeMB, 8) * 1024 * 1024,
+	}
+}
+
+// MaxSize devuelve el tamaño máximo de archivo aceptado
+func (s *ChunkedUploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// MaxChunkSize devuelve el tamaño máximo de cada parte
+func (s *ChunkedUploadService) MaxChunkSize() int64 {
+	return s.maxChunkSize
+}
+
+// Create inicia una subida por partes de un archivo de size bytes
+func (s *ChunkedUploadService) Create(fileName, contentType string, size int64, userID, userName, userRole, owner string) (*ChunkedUpload, error) {
+	if size <= 0 {
+		return nil, errors.New("el tamaño del archivo debe ser mayor que cero")
+	}
+	if size > s.maxSize {
+		return nil, ErrChunkedUploadTooLarge
+	}
+	// Rechazar desde el inicio los archivos que superan el límite del rol o la cuota
+	if storageService := GetSupabaseStorageService(); storageService != nil {
+		if err := storageService.CheckUploadAllowed(userID, userRole, size); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creando carpeta temporal: %v", err)
+	}
+
+	s.removeExpired()
+
+	now := time.Now()
+	upload := &ChunkedUpload{
+		ID:          uuid.New().String(),
+		FileName:    filepath.Base(fileName),
+		ContentType: contentType,
+		Size:        size,
+		UserID:      userID,
+		UserName:    userName,
+		UserRole:    userRole,
+		Owner:       owner,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ChunkedUploadSessionTTL),
+	}
+
+	file, err := os.OpenFile(s.partPath(upload.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error creando archivo temporal: %v", err)
+	}
+	file.Close()
+
+	s.mu.Lock()
+	s.uploads[upload.ID] = upload
+	s.mu.Unlock()
+
+	slog.Info("subida por partes iniciada", "upload_id", upload.ID, "file_name", upload.FileName, "size_mb", float64(size)/1024/1024)
+	return upload, nil
+}
+
+// Get devuelve una copia del estado de una subida
+func (s *ChunkedUploadService) Get(id string) (*ChunkedUpload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	upload, ok := s.uploads[id]
+	if !ok || time.Now().After(upload.ExpiresAt) {
+		return nil, ErrChunkedUploadNotFound
+	}
+	snapshot := *upload
+	return &snapshot, nil
+}
+
+// SetMetadata guarda la categoría, las etiquetas y el arriendo o contrato que se registran al
+// completar la subida
+func (s *ChunkedUploadService) SetMetadata(id string, metadata *model.FileMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	upload, ok := s.uploads[id]
+	if !ok {
+		return ErrChunkedUploadNotFound
+	}
+	upload.Metadata = metadata
+	return nil
+}
+
+// WriteChunk agrega una parte que empieza en offset. Si la conexión se corta a mitad de la parte
+// se conservan los bytes recibidos y el nuevo offset permite reanudar desde ahí.
+func (s *ChunkedUploadService) WriteChunk(id string, offset int64, chunk io.Reader) (*ChunkedUpload, error) {
+	s.mu.Lock()
+	upload, ok := s.uploads[id]
+	if !ok || time.Now().After(upload.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadNotFound
+	}
+	if upload.busy {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadBusy
+	}
+	if offset != upload.Offset {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadOffset
+	}
+	upload.busy = true
+	s.mu.Unlock()
+
+	written, writeErr := s.appendChunk(upload, chunk)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload.busy = false
+	upload.Offset += written
+	upload.ExpiresAt = time.Now().Add(ChunkedUploadSessionTTL)
+	snapshot := *upload
+
+	return &snapshot, writeErr
+}
+
+// appendChunk escribe la parte al final del archivo temporal sin superar el tamaño declarado
+// ni el tamaño máximo de parte
+func (s *ChunkedUploadService) appendChunk(upload *ChunkedUpload, chunk io.Reader) (int64, error) {
+	file, err := os.OpenFile(s.partPath(upload.ID), os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("error abriendo archivo temporal: %v", err)
+	}
+	defer file.Close()
+
+	limit := upload.Size - upload.Offset
+	if limit > s.maxChunkSize {
+		limit = s.maxChunkSize
+	}
+
+	written, err := io.Copy(file, io.LimitReader(chunk, limit))
+	if err != nil {
+		return written, fmt.Errorf("error recibiendo parte: %v", err)
+	}
+
+	// Bytes de más indican una parte mayor a lo permitido; lo recibido hasta el límite se conserva
+	var extra [1]byte
+	if n, _ := chunk.Read(extra[:]); n > 0 {
+		return written, ErrChunkedUploadTooLarge
+	}
+	return written, nil
+}
+
+// Finish transmite el archivo ensamblado a Supabase Storage y elimina la subida temporal
+func (s *ChunkedUploadService) Finish(id string) (*SupabaseUploadResponse, error) {
+	s.mu.Lock()
+	upload, ok := s.uploads[id]
+	if !ok || time.Now().After(upload.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadNotFound
+	}
+	if upload.busy {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadBusy
+	}
+	if !upload.Complete() {
+		s.mu.Unlock()
+		return nil, ErrChunkedUploadIncomplete
+	}
+	upload.busy = true
+	s.mu.Unlock()
+
+	response, err := s.uploadAssembled(upload)
+
+	s.mu.Lock()
+	upload.busy = false
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.Abort(id)
+	slog.Info("subida por partes completada", "id", id, "path", response.Path)
+	return response, nil
+}
+
+func (s *ChunkedUploadService) uploadAssembled(upload *ChunkedUpload) (*SupabaseUploadResponse, error) {
+	storageService := GetSupabaseStorageService()
+	if storageService == nil {
+		return nil, errors.New("servicio de archivos no disponible")
+	}
+
+	file, err := os.Open(s.partPath(upload.ID))
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo archivo ensamblado: %v", err)
+	}
+	defer file.Close()
+
+	return storageService.UploadStream(file, upload.FileName, upload.Size, upload.ContentType, upload.UserID, upload.UserName, upload.UserRole)
+}
+
+// Abort cancela una subida y elimina su archivo temporal
+func (s *ChunkedUploadService) Abort(id string) {
+	s.mu.Lock()
+	delete(s.uploads, id)
+	s.mu.Unlock()
+
+	if err := os.Remove(s.partPath(id)); err != nil && !os.IsNotExist(err) {
+		slog.Warn("error eliminando archivo temporal de la subida", "id", id, "error", err)
+	}
+}
+
+// removeExpired elimina las subidas abandonadas
+func (s *ChunkedUploadService) removeExpired() {
+	now := time.Now()
+	var expired []string
+
+	s.mu.Lock()
+	for id, upload := range s.uploads {
+		if !upload.busy && now.After(upload.ExpiresAt) {
+			expired = append(expired, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range expired {
+		slog.Info("eliminando subida por partes expirada", "id", id)
+		s.Abort(id)
+	}
+}
+
+func (s *ChunkedUploadService) partPath(id string) string {
+	return filepath.Join(s.dir, id+".part")
+}
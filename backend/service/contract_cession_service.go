@@ -0,0 +1,274 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// defaultContractCessionSchedule applies the cessions that became effective every night when
+// CONTRACT_CESSION_SCHEDULE is not set
+const defaultContractCessionSchedule = "0 1 * * *"
+
+// ContractCessionLetter holds the data of the letter notifying the tenant of a cession
+type ContractCessionLetter struct {
+	Cession            model.ContractCession
+	Rental             *model.Rental
+	Property           *model.Property
+	Tenant             *model.Person
+	PreviousOwner      *model.Person
+	PreviousOwnerEmail string
+	NewOwner           *model.Person
+	NewOwnerEmail      string
+	BankAccount        *model.BankAccount // Account of the new owner the rent is paid to
+	IssuedAt           time.Time
+}
+
+// CessionClauseOrdinal returns the ordinal of the cession clause of the default contract
+// (e.g. DÉCIMA CUARTA), empty when the template has none
+func CessionClauseOrdinal() string {
+	for i, clause := range DefaultContractTemplate().Clauses {
+		if strings.Contains(strings.ToUpper(clause.Title), "CESIÓN") {
+			return ClauseOrdinal(i + 1)
+		}
+	}
+	return ""
+}
+
+// GenerateCessionLetterPDF creates the letter notifying the tenant that the lessor's position
+// was ceded to a new owner, with the account the rent must be paid to from the effective date
+func GenerateCessionLetterPDF(letter ContractCessionLetter) ([]byte, error) {
+	if letter.Rental == nil || letter.Property == nil || letter.Tenant == nil || letter.PreviousOwner == nil || letter.NewOwner == nil {
+		return nil, fmt.Errorf("incomplete cession letter data")
+	}
+
+	propertyAddress := letter.Property.Address
+	if letter.Property.AptNumber != "" {
+		propertyAddress += " Apto " + letter.Property.AptNumber
+	}
+
+	reason := "la cesión del contrato"
+	if letter.Cession.Reason == model.CessionReasonSale {
+		reason = "la venta del inmueble"
+	}
+
+	clause := "la cláusula de cesión de derechos"
+	if ordinal := CessionClauseOrdinal(); ordinal != "" {
+		clause = fmt.Sprintf("la cláusula %s (CESIÓN DE DERECHOS)", ordinal)
+	}
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 5, fmt.Sprintf("%s, %s", letter.Property.City, FormatDate(letter.IssuedAt)), "", "L", false)
+	pdf.Ln(6)
+	pdf.MultiCell(0, 5, fmt.Sprintf("Señor(a)\n%s\nARRENDATARIO(A)\n%s\n%s", letter.Tenant.FullName, propertyAddress, letter.Property.City), "", "L", false)
+	pdf.Ln(6)
+
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 7, "NOTIFICACIÓN DE CESIÓN DEL CONTRATO DE ARRENDAMIENTO", "", "C", false)
+	pdf.Ln(4)
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	paragraphs := []string{
+		fmt.Sprintf("De conformidad con %s del contrato de arrendamiento del inmueble ubicado en %s de la ciudad de %s, iniciado el %s, le comunicamos que con motivo de %s el señor(a) %s, identificado(a) con CC/NIT %s, cedió su posición de ARRENDADOR al señor(a) %s, identificado(a) con CC/NIT %s.",
+			clause, propertyAddress, letter.Property.City, FormatDate(letter.Rental.StartDate.Time()), reason,
+			letter.PreviousOwner.FullName, letter.PreviousOwner.NIT, letter.NewOwner.FullName, letter.NewOwner.NIT),
+		fmt.Sprintf("La cesión produce efectos respecto de usted a partir del %s. Desde esa fecha los cánones de arrendamiento y demás sumas a cargo del ARRENDATARIO deberán pagarse al nuevo ARRENDADOR. Los pagos realizados antes de esa fecha se entienden hechos al arrendador anterior.",
+			FormatDate(letter.Cession.EffectiveDate)),
+	}
+	for _, paragraph := range paragraphs {
+		pdf.MultiCell(0, 5, paragraph, "", "J", false)
+		pdf.Ln(3)
+	}
+
+	if letter.BankAccount != nil {
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.MultiCell(0, 6, "CUENTA PARA EL PAGO DEL CANON", "", "L", false)
+		pdf.SetFont(pdfFontFamily, "", 10)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Banco: %s\nTipo de cuenta: %s\nNúmero de cuenta: %s\nTitular: %s",
+			letter.BankAccount.BankName, letter.BankAccount.AccountType, letter.BankAccount.AccountNumber, letter.BankAccount.AccountHolder), "", "L", false)
+		pdf.Ln(3)
+	}
+
+	pdf.MultiCell(0, 5, "Las demás condiciones del contrato de arrendamiento se mantienen sin modificación. Para cualquier notificación relacionada con el contrato puede dirigirse al nuevo ARRENDADOR al correo "+emailOrBlank(letter.NewOwnerEmail)+".", "", "J", false)
+
+	values := map[string]string{}
+	setParty := func(prefix string, person *model.Person, email string) {
+		values[prefix] = strings.ToUpper(person.FullName)
+		values[prefix+"_cc"] = person.NIT
+		values[prefix+"_telefono"] = person.Phone
+		values[prefix+"_email"] = email
+	}
+	setParty("cedente", letter.PreviousOwner, letter.PreviousOwnerEmail)
+	setParty("cesionario", letter.NewOwner, letter.NewOwnerEmail)
+	addSignatureTables(pdf, []model.ContractSignatureBlock{
+		{Label: "ARRENDADOR CEDENTE", Party: "cedente"},
+		{Label: "ARRENDADOR CESIONARIO", Party: "cesionario"},
+	}, values)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// emailOrBlank returns the email or a blank line to be filled in by hand
+func emailOrBlank(email string) string {
+	if email == "" {
+		return blankField
+	}
+	return email
+}
+
+// SendCessionLetterEmail sends the tenant the cession notification letter
+func SendCessionLetterEmail(to, tenantName, newOwnerName string, effectiveDate time.Time, letterPDF []byte) error {
+	subject := "Notificación de cesión de su contrato de arrendamiento"
+	body := fmt.Sprintf(`
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Cesión del Contrato</title>
+		<style>
+			body { font-family: Arial, sans-serif; margin: 0; padding: 20px; color: #333; }
+			.container { max-width: 600px; margin: 0 auto; }
+			.header { background-color: #f8f9fa; padding: 20px; text-align: center; }
+			.content { padding: 20px; }
+			.footer { margin-top: 20px; font-size: 12px; color: #6c757d; }
+		</style>
+	</head>
+	<body>
+		<div class="container">
+			<div class="header">
+				<h2>Cesión del Contrato de Arrendamiento</h2>
+			</div>
+			<div class="content">
+				<p>Estimado(a) %s,</p>
+				<p>Le informamos que a partir del <strong>%s</strong> el nuevo arrendador de su contrato es <strong>%s</strong>.</p>
+				<p>Adjunto encontrará la carta de notificación de la cesión con la cuenta a la que debe pagar el canon desde esa fecha.</p>
+				<p>Atentamente,<br>Sistema de Administración de Propiedades</p>
+			</div>
+			<div class="footer">
+				<p>Este es un mensaje automático. Por favor no responda directamente a este correo.</p>
+			</div>
+		</div>
+	</body>
+	</html>
+	`, tenantName, FormatDate(effectiveDate), newOwnerName)
+
+	tempFile, err := os.CreateTemp("", "cesion_contrato_*.pdf")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if _, err := tempFile.Write(letterPDF); err != nil {
+		return fmt.Errorf("error writing to temporary file: %w", err)
+	}
+
+	return SendEmailWithAttachment(to, subject, body, tempFile.Name(), "notificacion_cesion.pdf")
+}
+
+// ContractCessionService switches rentals to the bank account of the new owner once their
+// cession becomes effective
+type ContractCessionService struct {
+	cessionRepo storage.ContractCessionStore
+	rentalRepo  storage.RentalStore
+	schedule    string
+}
+
+// NewContractCessionService creates a ContractCessionService applying the cessions on schedule
+// (CONTRACT_CESSION_SCHEDULE)
+func NewContractCessionService(repoFactory *storage.RepositoryFactory, schedule string) *ContractCessionService {
+	return &ContractCessionService{
+		cessionRepo: repoFactory.GetContractCessionRepository(),
+		rentalRepo:  repoFactory.GetRentalRepository(),
+		schedule:    orDefault(schedule, defaultContractCessionSchedule),
+	}
+}
+
+// ApplyDueCessions points the rentals of the cessions effective at now to the new owner's bank
+// account, so billing uses it from then on. It returns the number of cessions applied.
+func (s *ContractCessionService) ApplyDueCessions(ctx context.Context, now time.Time) (int, error) {
+	cessions, err := s.cessionRepo.GetPendingApplication(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending cessions: %w", err)
+	}
+
+	applied := 0
+	for _, cession := range cessions {
+		if err := s.Apply(ctx, cession, now); err != nil {
+			logging.FromContext(ctx).Error("error applying cession of rental", "component", "cession", "cession_id", cession.ID, "rental_id", cession.RentalID, "error", err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// Apply switches the rental of an effective cession to the bank account of the new owner
+func (s *ContractCessionService) Apply(ctx context.Context, cession model.ContractCession, now time.Time) error {
+	rental, err := s.rentalRepo.GetByID(ctx, cession.RentalID)
+	if err != nil {
+		return err
+	}
+	if rental == nil {
+		return fmt.Errorf("rental not found")
+	}
+
+	rental.BankAccountID = cession.NewBankAccountID
+	if _, err := s.rentalRepo.Update(ctx, *rental); err != nil {
+		return err
+	}
+	if err := s.cessionRepo.MarkApplied(ctx, cession.ID, now); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("rental now paid to the account of owner", "component", "cession", "rental_id", rental.ID, "new_owner_id", cession.NewOwnerID)
+	return nil
+}
+
+// Start schedules the job applying the cessions that became effective
+func (s *ContractCessionService) Start() error {
+	schedule := s.schedule
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("contract_cession") {
+			return
+		}
+		start := time.Now()
+		applied, err := s.ApplyDueCessions(context.Background(), start)
+		metrics.JobRun("contract_cession", start, err)
+		if err != nil {
+			slog.Error("error applying contract cessions", "component", "cession", "error", err)
+		} else if applied > 0 {
+			slog.Info("contract cessions applied", "component", "cession", "applied", applied)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid CONTRACT_CESSION_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("contract cession", c)
+
+	slog.Info("contract cession scheduler started", "component", "cession", "schedule", schedule)
+	return nil
+}
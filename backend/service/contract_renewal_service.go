@@ -0,0 +1,58 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultRenewalIncreasePercentage is the yearly increase applied on renewal when
+// RENEWAL_IPC_PERCENTAGE is not set (IPC of the previous year, Art. 20 Ley 820 de 2003)
+const DefaultRenewalIncreasePercentage = 5.2
+
+// DefaultRenewalTermMonths is used when the previous term length cannot be determined
+const DefaultRenewalTermMonths = 12
+
+// ContractRenewal describes the amendment printed on a renewed contract
+type ContractRenewal struct {
+	PreviousRentalID   string
+	PreviousStartDate  time.Time
+	PreviousEndDate    time.Time
+	PreviousRent       float64
+	IncreasePercentage float64
+	NewRent            float64
+}
+
+// RenewalIncreasePercentage returns the yearly increase (IPC) percentage of the renewals,
+// configured (RENEWAL_IPC_PERCENTAGE) or DefaultRenewalIncreasePercentage when it is nil
+func RenewalIncreasePercentage(configured *float64) float64 {
+	if configured != nil {
+		return *configured
+	}
+	return DefaultRenewalIncreasePercentage
+}
+
+// ApplyRentIncrease returns the monthly rent increased by the given percentage, rounded to whole pesos
+func ApplyRentIncrease(monthlyRent, percentage float64) float64 {
+	return math.Round(monthlyRent * (1 + percentage/100))
+}
+
+// NextRentalTerm returns the dates of the term that follows a contract ending on previousEnd.
+// The new term starts the day after the previous one ends and lasts the given number of months.
+func NextRentalTerm(previousEnd time.Time, months int) (time.Time, time.Time) {
+	if months <= 0 {
+		months = DefaultRenewalTermMonths
+	}
+
+	start := previousEnd.AddDate(0, 0, 1)
+	return start, TermEndDate(start, months)
+}
+
+// TermLengthInMonths returns the number of whole months covered by a term
+// (Jun 6 - Dec 5 counts as 6 months). Falls back to DefaultRenewalTermMonths.
+func TermLengthInMonths(start, end time.Time) int {
+	months := CalendarTerm(start, end).Months
+	if months < 1 {
+		return DefaultRenewalTermMonths
+	}
+	return months
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// DefaultContractTemplate returns the built-in contract for urban housing, used when no
+// template is stored in the database
+func DefaultContractTemplate() model.ContractTemplate {
+	return viviendaUrbanaTemplate()
+}
+
+// BuiltinContractTemplate returns the built-in template of a contract type, vivienda urbana
+// for unknown types
+func BuiltinContractTemplate(contractType string) model.ContractTemplate {
+	switch contractType {
+	case model.ContractTypeLocalComercial:
+		return localComercialTemplate()
+	case model.ContractTypeHabitacion:
+		return habitacionTemplate()
+	case model.ContractTypeParqueadero:
+		return parqueaderoTemplate()
+	default:
+		return viviendaUrbanaTemplate()
+	}
+}
+
+// ContractTypeForProperty guesses the contract type from the free-text type of a property
+func ContractTypeForProperty(propertyType string) string {
+	normalized := strings.ToLower(propertyType)
+	switch {
+	case strings.Contains(normalized, "parqueadero"), strings.Contains(normalized, "garaje"),
+		strings.Contains(normalized, "parking"):
+		return model.ContractTypeParqueadero
+	case strings.Contains(normalized, "habitaci"), strings.Contains(normalized, "room"):
+		return model.ContractTypeHabitacion
+	case IsCommercialPropertyType(propertyType):
+		return model.ContractTypeLocalComercial
+	default:
+		return model.ContractTypeViviendaUrbana
+	}
+}
+
+// DefaultSignatureBlocks are the signature blocks of templates that do not define their own
+func DefaultSignatureBlocks() []model.ContractSignatureBlock {
+	return []model.ContractSignatureBlock{
+		{Label: "ARRENDADOR", Party: model.ContractPartyArrendador},
+		{Label: "ARRENDATARIO", Party: model.ContractPartyArrendatario},
+		{Label: "TESTIGO", Party: model.ContractPartyTestigo},
+		{Label: "CODEUDOR SOLIDARIO", Party: model.ContractPartyCodeudor},
+	}
+}
+
+// viviendaUrbanaTemplate is the contract for urban housing (Ley 820 de 2003)
+func viviendaUrbanaTemplate() model.ContractTemplate {
+	return model.ContractTemplate{
+		Name:            "Vivienda urbana",
+		ContractType:    model.ContractTypeViviendaUrbana,
+		SignatureBlocks: DefaultSignatureBlocks(),
+		Title:           "CONTRATO DE ARRENDAMIENTO DE INMUEBLE PARA VIVIENDA URBANA",
+		Variables: map[string]string{
+			"destinacion":            "vivienda",
+			"matricula_inmobiliaria": blankField,
+			"cuenta_bancaria":        "la cuenta que el ARRENDADOR indique por escrito",
+		},
+		Clauses: []model.ContractClause{
+			{Title: "OBJETO DEL CONTRATO", Body: "Mediante el presente contrato el ARRENDADOR concede al ARRENDATARIO el goce del inmueble que adelante se identifica por su dirección y linderos, de acuerdo con el inventario que las partes firman por separado, el cual forma parte integral de este mismo contrato de arrendamiento."},
+			{Title: "DIRECCIÓN DEL INMUEBLE", Body: "Apartamento {{apartamento}} ubicado en la {{direccion}} de la ciudad de {{ciudad}}, identificado con el folio de matrícula inmobiliaria {{matricula_inmobiliaria}} de la oficina de Registro de Instrumentos Públicos, cuyos linderos se encuentran en el certificado de tradición del inmueble. {{caracteristicas_inmueble}}"},
+			{Title: "DESTINACIÓN", Body: "El ARRENDATARIO se compromete a destinar este inmueble exclusivamente para {{destinacion}}."},
+			{Title: "PRECIO DEL ARRENDAMIENTO", Body: "El canon mensual de arrendamiento será la suma de {{canon_letras}} ({{canon}}), pagaderos al ARRENDADOR o a su orden dentro de los primeros {{dia_pago}} días de cada mes. PARAGRAFO: El ARRENDATARIO pagará las sumas arriba indicadas al ARRENDADOR mediante consignación o transferencia a {{cuenta_bancaria}}."},
+			{Title: "VIGENCIA DEL CONTRATO", Body: "La vigencia del presente contrato será de {{duracion}}, a partir del {{fecha_inicio_letras}} y hasta la fecha de entrega del inmueble que deberá ser el día {{fecha_fin}}, salvo lo acordado en la cláusula de PRÓRROGAS."},
+			{Title: "CUOTAS DE ADMINISTRACIÓN", Body: "La cuota ordinaria mensual de administración será cancelada por el ARRENDADOR directamente a la Copropiedad dentro de los plazos fijados por la Copropiedad en la respectiva factura. PARAGRAFO: EL ARRENDATARIO se compromete a cumplir y respetar cabalmente todas y cada una de las normas establecidas por el Reglamento de Propiedad Horizontal y el Manual de Convivencia de la Copropiedad. {{copropiedad}}"},
+			{Title: "INCREMENTOS DEL PRECIO", Body: "Vencidos los doce (12) meses de vigencia de este contrato y así sucesivamente cada doce (12) mensualidades, en caso de prórroga tácita o expresa, en forma automática y sin necesidad de requerimiento alguno entre las partes, el canon mensual del arrendamiento se incrementará en una proporción que no será superior al ciento por ciento (100%) del incremento que haya tenido el índice de precios al consumidor en el año calendario inmediatamente anterior, de acuerdo con lo establecido en el Artículo 20 de la Ley 820 de 2003."},
+			{Title: "PRÓRROGAS", Body: "Vencido el término pactado, si no existiere anuncio previo por las partes, el Contrato se entenderá prorrogado por un término igual al inicialmente pactado. Si alguna de las partes no desea prorrogar el presente Contrato, tendrá que avisar a la otra con tres (3) meses de antelación al vencimiento del Contrato."},
+			{Title: "SERVICIOS", Body: "Estarán a cargo del ARRENDATARIO el pago oportuno de los servicios públicos de Energía Eléctrica, Acueducto y Alcantarillado y Gas, incluidos los servicios adicionales instalados bajo autorización y responsabilidad del ARRENDATARIO, previa autorización del ARRENDADOR. Los servicios de carácter privado, tales como televisión satelital o por cable e Internet, serán responsabilidad directa y exclusiva del ARRENDATARIO. PARAGRAFO PRIMERO. Las reclamaciones que tengan que ver con la prestación o facturación de los servicios públicos serán tramitadas directamente por el ARRENDATARIO ante las respectivas empresas prestadoras del servicio. PARÁGRAFO SEGUNDO. Si el ARRENDATARIO no paga oportunamente los servicios públicos, este hecho se tendrá como incumplimiento del contrato, pudiendo el ARRENDADOR darlo por terminado unilateralmente sin necesidad de los requerimientos privados y judiciales previstos en la Ley. PARAGRAFO TERCERO. El presente documento junto con los recibos cancelados por el ARRENDADOR constituye título ejecutivo para cobrar judicialmente al ARRENDATARIO y sus garantes los servicios que dejaren de pagar, siempre que tales montos correspondan al período en que éstos tuvieron en su poder el inmueble."},
+			{Title: "COSAS O USOS CONEXOS", Body: "Además del inmueble identificado y descrito anteriormente, tendrá el ARRENDATARIO derecho de goce de las zonas comunales de acuerdo con el Manual de Convivencia y el Reglamento de Propiedad Horizontal. PARAGRAFO: Dentro del inmueble objeto del presente contrato está prohibido el uso, almacenamiento o consumo de sustancias prohibidas por la Ley."},
+			{Title: "CLÁUSULA PENAL", Body: "El incumplimiento por parte del ARRENDATARIO de cualquiera de las cláusulas de este contrato, y aún el simple retardo en el pago de una o más mensualidades, lo constituirá en deudor del ARRENDADOR por una suma equivalente a dos (2) veces el canon mensual del arrendamiento vigente en el momento del incumplimiento, a título de pena, que será exigible inmediatamente sin necesidad de requerimiento de ninguna clase y sin perjuicio de los demás derechos del ARRENDADOR para hacer cesar el arrendamiento y exigir judicialmente la entrega del inmueble. El pago de la pena no extingue la obligación principal. PARÁGRAFO. Si el ARRENDATARIO desea dar por terminado el contrato en forma unilateral antes del vencimiento inicial del mismo, deberá pagar la indemnización prevista en el numeral 5 del artículo 24 de la Ley 820 de 2003."},
+			{Title: "REQUERIMIENTOS", Body: "El ARRENDATARIO renuncia expresamente a los requerimientos de que tratan los artículos 2007 y 2035 del Código Civil, relativos a la constitución en mora."},
+			{Title: "PREAVISO PARA LA ENTREGA", Body: "Las partes se obligan a dar el correspondiente preaviso para la entrega con tres (3) meses de anticipación al vencimiento del contrato. En todo caso, este preaviso deberá darse por escrito y a través de correo certificado o personalmente."},
+			{Title: "CESIÓN DE DERECHOS", Body: "Podrá el ARRENDADOR ceder libremente los derechos que emanan de este contrato y tal cesión producirá efectos respecto del ARRENDATARIO a partir de la fecha de la comunicación certificada en que se le comunique."},
+			{Title: "CAUSALES DE TERMINACIÓN", Body: "A favor del ARRENDADOR serán las siguientes: a) La cesión del contrato o el subarriendo total o parcial del inmueble, b) El cambio de destinación del inmueble, c) El no pago del precio dentro del término previsto en este contrato, d) La destinación del inmueble para fines ilícitos o que afecten la tranquilidad de los vecinos, e) La realización de mejoras, cambios o ampliaciones del inmueble sin expresa autorización del ARRENDADOR, f) La no cancelación de los servicios públicos a cargo del ARRENDATARIO, g) Las demás previstas en la ley y en las cláusulas del presente contrato. A favor del ARRENDATARIO: a) La suspensión de la prestación de los servicios públicos al inmueble por acción o mora del ARRENDADOR, b) Los actos del ARRENDADOR que afecten gravemente el goce del bien arrendado, c) El desconocimiento por parte del ARRENDADOR de los derechos reconocidos al ARRENDATARIO por la Ley o el contrato."},
+			{Title: "RECIBO Y ESTADO", Body: "El ARRENDATARIO declara que ha recibido el inmueble objeto de este contrato en buen estado, conforme al inventario que hace parte del mismo, y que en el mismo estado lo restituirá al ARRENDADOR a la terminación del contrato, salvo el deterioro proveniente del tiempo y uso legítimo. PARAGRAFO. El ARRENDATARIO está obligado a efectuar las reparaciones locativas conforme a los Artículos 2029 y 2030 del Código Civil. Los daños al inmueble derivados del mal trato o descuido por parte del ARRENDATARIO serán de su cargo."},
+			{Title: "RESTITUCIÓN DEL INMUEBLE", Body: "Terminado el presente contrato, el ARRENDATARIO deberá entregar el inmueble al ARRENDADOR en forma personal o a quien éste autorice para recibirlo, conforme al inventario inicial, obligándose a presentar los recibos de servicios públicos debidamente pagados. Los servicios públicos pendientes de facturar se garantizarán mediante provisión equivalente al promedio de los tres (3) últimos consumos."},
+			{Title: "DEUDORES SOLIDARIOS", Body: "El suscrito, {{codeudor}} con CC {{codeudor_cc}}, por medio del presente documento se declara deudor del ARRENDADOR en forma solidaria e indivisible junto con el ARRENDATARIO de todas las cargas y obligaciones contenidas en el presente contrato, tanto durante el término inicialmente pactado como durante sus prórrogas o renovaciones expresas o tácitas y hasta la restitución real del inmueble al ARRENDADOR, sin que por razón de esta solidaridad asuma el carácter de fiador ni ARRENDATARIO del inmueble, pues tal calidad la asume exclusivamente {{arrendatario}}."},
+			{Title: "MEJORAS", Body: "No podrá el ARRENDATARIO ejecutar en el inmueble mejoras de ninguna especie sin permiso escrito del ARRENDADOR, y si éstas se ejecutaren accederán al propietario del inmueble sin indemnización para quien las efectuó."},
+			{Title: "MÉRITO EJECUTIVO DEL CONTRATO", Body: "Las partes acuerdan que el presente contrato presta mérito ejecutivo para efectos extrajudiciales y judiciales, con relación a todas las obligaciones que de éste se deriven, aún después de la restitución del inmueble y hasta el cumplimiento total de las obligaciones a cargo del ARRENDATARIO y del Deudor Solidario."},
+			{Title: "VISITAS DE INSPECCIÓN", Body: "El ARRENDADOR o su representante debidamente autorizado podrá visitar el inmueble, acordando cita previa, con la finalidad de constatar la destinación, el estado y conservación del inmueble u otras circunstancias relacionadas con el contrato de arrendamiento."},
+			{Title: "NOTIFICACIONES", Body: "En atención al artículo 103 del Código General del Proceso, las partes convienen que las notificaciones judiciales y extrajudiciales relacionadas con el contrato de arrendamiento serán remitidas a los siguientes correos electrónicos: El arrendador: {{arrendador_email}}. El arrendatario: {{arrendatario_email}}. Testigo: {{testigo_email}}. Deudor solidario: {{codeudor_email}}."},
+		},
+		ClosingText: "Para constancia firmamos las partes y ante testigo el día {{fecha_contrato}} y declara el ARRENDATARIO que ha recibido la respectiva copia del presente contrato. Para efecto de recibir notificaciones judiciales y extrajudiciales, las partes en cumplimiento del Art. 12 de la Ley 820 de 2003 indican a continuación sus respectivos datos.",
+	}
+}
+
+// localComercialTemplate is the lease of commercial premises (Código de Comercio, arts. 518 a 524)
+func localComercialTemplate() model.ContractTemplate {
+	return model.ContractTemplate{
+		Name:         "Local comercial",
+		ContractType: model.ContractTypeLocalComercial,
+		Title:        "CONTRATO DE ARRENDAMIENTO DE LOCAL COMERCIAL",
+		SignatureBlocks: []model.ContractSignatureBlock{
+			{Label: "ARRENDADOR", Party: model.ContractPartyArrendador},
+			{Label: "ARRENDATARIO", Party: model.ContractPartyArrendatario},
+			{Label: "CODEUDOR SOLIDARIO", Party: model.ContractPartyCodeudor},
+		},
+		Variables: map[string]string{
+			"actividad_comercial":    blankField,
+			"matricula_inmobiliaria": blankField,
+			"cuenta_bancaria":        "la cuenta que el ARRENDADOR indique por escrito",
+			"incremento_anual":       "el índice de precios al consumidor del año anterior más dos (2) puntos",
+		},
+		Clauses: []model.ContractClause{
+			{Title: "OBJETO DEL CONTRATO", Body: "Mediante el presente contrato el ARRENDADOR concede al ARRENDATARIO el uso y goce del local comercial que adelante se identifica, de acuerdo con el inventario que las partes firman por separado, el cual forma parte integral de este contrato."},
+			{Title: "IDENTIFICACIÓN DEL LOCAL", Body: "Local {{apartamento}} ubicado en la {{direccion}} de la ciudad de {{ciudad}}, identificado con el folio de matrícula inmobiliaria {{matricula_inmobiliaria}}. {{caracteristicas_inmueble}}"},
+			{Title: "DESTINACIÓN", Body: "El ARRENDATARIO destinará el local exclusivamente al desarrollo de la actividad comercial de {{actividad_comercial}}, y no podrá cambiar dicha destinación sin autorización previa y escrita del ARRENDADOR. El ARRENDATARIO se obliga a obtener y mantener vigentes los permisos, licencias y registros que exija la actividad."},
+			{Title: "PRECIO DEL ARRENDAMIENTO", Body: "El canon mensual de arrendamiento será la suma de {{canon_letras}} ({{canon}}), más el impuesto al valor agregado cuando haya lugar a él, pagaderos al ARRENDADOR dentro de los primeros {{dia_pago}} días de cada mes mediante consignación o transferencia a {{cuenta_bancaria}}."},
+			{Title: "VIGENCIA DEL CONTRATO", Body: "La vigencia del presente contrato será de {{duracion}}, a partir del {{fecha_inicio_letras}} y hasta el {{fecha_fin}}."},
+			{Title: "INCREMENTOS DEL PRECIO", Body: "Cada doce (12) meses de ejecución del contrato, el canon se incrementará en {{incremento_anual}}, sin necesidad de requerimiento alguno entre las partes."},
+			{Title: "DERECHO DE RENOVACIÓN", Body: "Cumplidos dos (2) años consecutivos de ocupación del local con un mismo establecimiento de comercio, el ARRENDATARIO tendrá derecho a la renovación del contrato en los términos del artículo 518 del Código de Comercio, salvo las excepciones allí previstas. El ARRENDADOR que requiera el local por alguna de dichas causales deberá desahuciar al ARRENDATARIO con no menos de seis (6) meses de anticipación, conforme al artículo 520 del mismo código."},
+			{Title: "SUBARRIENDO Y CESIÓN", Body: "El ARRENDATARIO no podrá subarrendar total ni parcialmente el local, ni ceder el contrato, sin autorización expresa del ARRENDADOR, salvo la cesión que se produzca como consecuencia de la enajenación del establecimiento de comercio en los términos del artículo 523 del Código de Comercio."},
+			{Title: "SERVICIOS Y ADMINISTRACIÓN", Body: "Estarán a cargo del ARRENDATARIO el pago oportuno de los servicios públicos del local y de la cuota de administración de la copropiedad, cuando la hubiere. El no pago oportuno de estos conceptos se tendrá como incumplimiento del contrato. {{copropiedad}}"},
+			{Title: "MEJORAS Y AVISOS", Body: "El ARRENDATARIO no podrá realizar mejoras, adecuaciones ni instalar avisos sin autorización escrita del ARRENDADOR y de la copropiedad. Las mejoras autorizadas quedarán en beneficio del inmueble sin indemnización, salvo pacto escrito en contrario."},
+			{Title: "CLÁUSULA PENAL", Body: "El incumplimiento por parte del ARRENDATARIO de cualquiera de las obligaciones de este contrato lo constituirá en deudor del ARRENDADOR por una suma equivalente a tres (3) cánones mensuales vigentes a título de pena, sin perjuicio del cobro de los perjuicios y de los cánones adeudados."},
+			{Title: "RESTITUCIÓN DEL LOCAL", Body: "Terminado el contrato, el ARRENDATARIO restituirá el local en el estado en que lo recibió conforme al inventario, salvo el deterioro natural, con los servicios públicos y la administración a paz y salvo."},
+			{Title: "DEUDORES SOLIDARIOS", Body: "El suscrito, {{codeudor}} con CC {{codeudor_cc}}, se declara deudor del ARRENDADOR en forma solidaria e indivisible junto con el ARRENDATARIO de todas las obligaciones contenidas en el presente contrato, durante su vigencia, prórrogas y renovaciones y hasta la restitución real del local."},
+			{Title: "MÉRITO EJECUTIVO", Body: "El presente contrato presta mérito ejecutivo para el cobro de todas las obligaciones que de él se deriven, aún después de la restitución del local."},
+			{Title: "NOTIFICACIONES", Body: "Las partes recibirán notificaciones judiciales y extrajudiciales relacionadas con el contrato en los siguientes correos electrónicos: El arrendador: {{arrendador_email}}. El arrendatario: {{arrendatario_email}}. Deudor solidario: {{codeudor_email}}."},
+		},
+		ClosingText: "Para constancia se firma en la ciudad de {{ciudad}} el día {{fecha_contrato}}, en dos ejemplares del mismo tenor, uno para cada una de las partes.",
+	}
+}
+
+// habitacionTemplate is the lease of a room inside a shared dwelling (Ley 820 de 2003)
+func habitacionTemplate() model.ContractTemplate {
+	return model.ContractTemplate{
+		Name:         "Habitación",
+		ContractType: model.ContractTypeHabitacion,
+		Title:        "CONTRATO DE ARRENDAMIENTO DE HABITACIÓN",
+		SignatureBlocks: []model.ContractSignatureBlock{
+			{Label: "ARRENDADOR", Party: model.ContractPartyArrendador},
+			{Label: "ARRENDATARIO", Party: model.ContractPartyArrendatario},
+		},
+		Variables: map[string]string{
+			"habitacion":          blankField,
+			"servicios_incluidos": "energía, acueducto, gas e internet",
+			"zonas_comunes":       "cocina, sala, comedor y zona de lavandería",
+			"cuenta_bancaria":     "la cuenta que el ARRENDADOR indique por escrito",
+			"horario_visitas":     "entre las 8:00 a.m. y las 9:00 p.m.",
+		},
+		Clauses: []model.ContractClause{
+			{Title: "OBJETO DEL CONTRATO", Body: "Mediante el presente contrato el ARRENDADOR concede al ARRENDATARIO el uso y goce de la habitación {{habitacion}} del inmueble ubicado en la {{direccion}} Apto {{apartamento}} de la ciudad de {{ciudad}}, amoblada conforme al inventario que las partes firman por separado."},
+			{Title: "ZONAS COMUNES", Body: "El ARRENDATARIO podrá usar las siguientes zonas comunes del inmueble: {{zonas_comunes}}, en igualdad de condiciones con los demás ocupantes y manteniéndolas limpias y en buen estado."},
+			{Title: "DESTINACIÓN", Body: "La habitación se destinará exclusivamente a vivienda del ARRENDATARIO, quien no podrá alojar a otras personas de manera permanente ni subarrendarla."},
+			{Title: "PRECIO DEL ARRENDAMIENTO", Body: "El canon mensual será la suma de {{canon_letras}} ({{canon}}), que incluye los servicios de {{servicios_incluidos}}, pagaderos por anticipado dentro de los primeros {{dia_pago}} días de cada mes mediante consignación o transferencia a {{cuenta_bancaria}}."},
+			{Title: "VIGENCIA DEL CONTRATO", Body: "La vigencia del presente contrato será de {{duracion}}, a partir del {{fecha_inicio_letras}} y hasta el {{fecha_fin}}, prorrogable por periodos iguales si ninguna de las partes manifiesta lo contrario."},
+			{Title: "INCREMENTOS DEL PRECIO", Body: "Cada doce (12) meses de ejecución del contrato el canon podrá incrementarse en una proporción que no supere el ciento por ciento (100%) del incremento del índice de precios al consumidor del año anterior, conforme al Artículo 20 de la Ley 820 de 2003."},
+			{Title: "NORMAS DE CONVIVENCIA", Body: "El ARRENDATARIO se obliga a respetar la tranquilidad de los demás ocupantes, a recibir visitas únicamente {{horario_visitas}} y a cumplir el reglamento de la copropiedad. Está prohibido el consumo de sustancias ilícitas dentro del inmueble."},
+			{Title: "TERMINACIÓN", Body: "Cualquiera de las partes podrá dar por terminado el contrato con un preaviso escrito de un (1) mes. El incumplimiento de las normas de convivencia o la mora en el pago del canon facultan al ARRENDADOR para terminar el contrato de manera inmediata."},
+			{Title: "RESTITUCIÓN DE LA HABITACIÓN", Body: "Terminado el contrato, el ARRENDATARIO entregará la habitación y el mobiliario en el estado en que los recibió conforme al inventario, salvo el deterioro natural por el uso legítimo."},
+			{Title: "NOTIFICACIONES", Body: "Las partes recibirán notificaciones relacionadas con el contrato en los siguientes correos electrónicos: El arrendador: {{arrendador_email}}. El arrendatario: {{arrendatario_email}}."},
+		},
+		ClosingText: "Para constancia se firma en la ciudad de {{ciudad}} el día {{fecha_contrato}}.",
+	}
+}
+
+// parqueaderoTemplate is the lease of a parking space
+func parqueaderoTemplate() model.ContractTemplate {
+	return model.ContractTemplate{
+		Name:         "Parqueadero",
+		ContractType: model.ContractTypeParqueadero,
+		Title:        "CONTRATO DE ARRENDAMIENTO DE PARQUEADERO",
+		SignatureBlocks: []model.ContractSignatureBlock{
+			{Label: "ARRENDADOR", Party: model.ContractPartyArrendador},
+			{Label: "ARRENDATARIO", Party: model.ContractPartyArrendatario},
+		},
+		Variables: map[string]string{
+			"vehiculo":        blankField,
+			"placa":           blankField,
+			"cuenta_bancaria": "la cuenta que el ARRENDADOR indique por escrito",
+		},
+		Clauses: []model.ContractClause{
+			{Title: "OBJETO DEL CONTRATO", Body: "Mediante el presente contrato el ARRENDADOR concede al ARRENDATARIO el uso del parqueadero número {{apartamento}} ubicado en la {{direccion}} de la ciudad de {{ciudad}}."},
+			{Title: "VEHÍCULO AUTORIZADO", Body: "El parqueadero se destinará exclusivamente al estacionamiento del vehículo {{vehiculo}} de placa {{placa}}. Cualquier cambio de vehículo deberá informarse previamente y por escrito al ARRENDADOR y a la administración."},
+			{Title: "PRECIO DEL ARRENDAMIENTO", Body: "El canon mensual será la suma de {{canon_letras}} ({{canon}}), pagaderos por anticipado dentro de los primeros {{dia_pago}} días de cada mes mediante consignación o transferencia a {{cuenta_bancaria}}."},
+			{Title: "VIGENCIA DEL CONTRATO", Body: "La vigencia del presente contrato será de {{duracion}}, a partir del {{fecha_inicio_letras}} y hasta el {{fecha_fin}}, prorrogable por periodos iguales si ninguna de las partes manifiesta lo contrario."},
+			{Title: "USO DEL PARQUEADERO", Body: "El ARRENDATARIO no podrá usar el parqueadero como depósito, ni realizar en él reparaciones o lavado del vehículo, ni subarrendarlo. Se obliga a cumplir el reglamento de la copropiedad sobre circulación y uso de zonas comunes."},
+			{Title: "RESPONSABILIDAD", Body: "El presente contrato no constituye contrato de depósito ni de vigilancia. El ARRENDADOR no responde por hurto, daños o pérdida del vehículo o de los objetos dejados en él, salvo culpa comprobada."},
+			{Title: "TERMINACIÓN", Body: "Cualquiera de las partes podrá dar por terminado el contrato con un preaviso escrito de treinta (30) días. La mora en el pago del canon faculta al ARRENDADOR para terminar el contrato de manera inmediata."},
+			{Title: "NOTIFICACIONES", Body: "Las partes recibirán notificaciones relacionadas con el contrato en los siguientes correos electrónicos: El arrendador: {{arrendador_email}}. El arrendatario: {{arrendatario_email}}."},
+		},
+		ClosingText: "Para constancia se firma en la ciudad de {{ciudad}} el día {{fecha_contrato}}.",
+	}
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// blankField is printed for contract data that is not available yet, so it can be filled by hand
+const blankField = "________________"
+
+// maxTemplateClauses keeps room for the renewal clause within the numbered ordinals
+const maxTemplateClauses = 38
+
+// placeholderPattern matches {{name}} placeholders, allowing spaces inside the braces
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z0-9_]+)\s*\}\}`)
+
+// ContractPlaceholders documents the placeholders filled from the contract data.
+// Templates may define more placeholders through their variables.
+var ContractPlaceholders = map[string]string{
+	"fecha_contrato":           "Fecha de elaboración del contrato",
+	"ciudad":                   "Ciudad del inmueble",
+	"direccion":                "Dirección del inmueble",
+	"apartamento":              "Número de apartamento o unidad",
+	"tipo_inmueble":            "Tipo de inmueble",
+	"arrendador":               "Nombre del arrendador",
+	"arrendador_cc":            "Cédula del arrendador",
+	"arrendador_email":         "Email del arrendador",
+	"arrendador_telefono":      "Teléfono del arrendador",
+	"arrendatario":             "Nombre del arrendatario",
+	"arrendatario_cc":          "Cédula del arrendatario",
+	"arrendatario_email":       "Email del arrendatario",
+	"arrendatario_telefono":    "Teléfono del arrendatario",
+	"codeudor":                 "Nombre del deudor solidario",
+	"codeudor_cc":              "Cédula del deudor solidario",
+	"codeudor_email":           "Email del deudor solidario",
+	"codeudor_telefono":        "Teléfono del deudor solidario",
+	"testigo":                  "Nombre del testigo",
+	"testigo_cc":               "Cédula del testigo",
+	"testigo_email":            "Email del testigo",
+	"testigo_telefono":         "Teléfono del testigo",
+	"canon":                    "Canon mensual ($1.600.000)",
+	"canon_letras":             "Canon mensual en letras",
+	"dia_pago":                 "Día límite de pago de cada mes",
+	"deposito":                 "Condiciones del depósito",
+	"fecha_inicio":             "Fecha de iniciación",
+	"fecha_inicio_letras":      "Fecha de iniciación con el día en letras",
+	"fecha_fin":                "Fecha de terminación",
+	"duracion":                 "Duración del contrato en años, meses y días",
+	"informacion_adicional":    "Información adicional del contrato",
+	"promocion":                "Parágrafo de las promociones aplicadas, vacío sin promociones",
+	"administracion":           "Cuota de administración a cargo del arrendatario",
+	"parqueadero":              "Valor mensual del parqueadero a cargo del arrendatario",
+	"total_mensual":            "Total mensual a cargo del arrendatario, con cargos adicionales e IVA",
+	"cargos_adicionales":       "Parágrafo de la administración y demás cargos, vacío sin cargos adicionales",
+	"habitaciones":             "Número de habitaciones del inmueble",
+	"banos":                    "Número de baños del inmueble",
+	"area":                     "Área del inmueble en m²",
+	"estrato":                  "Estrato socioeconómico del inmueble",
+	"garaje":                   "Parqueaderos o garaje del inmueble",
+	"linderos":                 "Linderos del inmueble",
+	"caracteristicas_inmueble": "Parágrafo con las características y linderos del inmueble, vacío sin características",
+	"edificio":                 "Nombre de la copropiedad del inmueble",
+	"cuota_administracion":     "Cuota ordinaria mensual de administración de la copropiedad",
+	"administrador_edificio":   "Nombre del administrador de la copropiedad",
+	"administrador_telefono":   "Teléfono del administrador de la copropiedad",
+	"administrador_email":      "Email del administrador de la copropiedad",
+	"copropiedad":              "Parágrafo de la copropiedad, su cuota de administración y su administrador, vacío sin copropiedad",
+}
+
+// ContractTemplateValues builds the placeholder values of a contract. Template variables are
+// used as defaults for values not available in the contract data.
+func ContractTemplateValues(data ContractPDF, template model.ContractTemplate) map[string]string {
+	values := make(map[string]string, len(ContractPlaceholders)+len(template.Variables))
+	for name, value := range template.Variables {
+		values[name] = value
+	}
+
+	set := func(name, value string) {
+		if strings.TrimSpace(value) != "" {
+			values[name] = value
+		} else if _, ok := values[name]; !ok {
+			values[name] = blankField
+		}
+	}
+
+	formatter := contractFormatter(data)
+	set("fecha_contrato", formatter.Date(data.CreationDate))
+
+	property := data.Property
+	if property == nil {
+		property = &model.Property{}
+	}
+	set("ciudad", property.City)
+	set("direccion", property.Address)
+	set("apartamento", property.AptNumber)
+	set("tipo_inmueble", property.Type)
+	set("habitaciones", countText(property.Bedrooms))
+	set("banos", countText(property.Bathrooms))
+	if property.AreaM2 > 0 {
+		set("area", formatArea(property.AreaM2))
+	} else {
+		set("area", "")
+	}
+	set("estrato", countText(property.Estrato))
+	set("garaje", parkingText(*property))
+	set("linderos", property.Boundaries)
+	// Empty without characteristics, the clauses then read as usual
+	values["caracteristicas_inmueble"] = PropertyCharacteristicsText(*property)
+
+	building := data.Building
+	if building == nil {
+		building = &model.Building{}
+	}
+	set("edificio", building.Name)
+	set("cuota_administracion", formatCharge(model.COP(building.AdminFee)))
+	set("administrador_edificio", building.AdministratorName)
+	set("administrador_telefono", building.AdministratorPhone)
+	set("administrador_email", building.AdministratorEmail)
+	// Empty without copropiedad
+	values["copropiedad"] = ""
+	if data.Building != nil {
+		values["copropiedad"] = BuildingClauseText(*data.Building)
+	}
+
+	setPerson := func(prefix string, person *model.Person, email string) {
+		if person == nil {
+			person = &model.Person{}
+		}
+		set(prefix, strings.ToUpper(person.FullName))
+		set(prefix+"_cc", person.NIT)
+		set(prefix+"_telefono", person.Phone)
+		set(prefix+"_email", email)
+	}
+	setPerson("arrendador", data.Owner, data.OwnerEmail)
+	setPerson("arrendatario", data.Renter, data.RenterEmail)
+	setPerson("codeudor", data.CoSigner, data.CoSignerEmail)
+	setPerson("testigo", data.Witness, data.WitnessEmail)
+
+	if data.Pricing != nil && data.Pricing.MonthlyRent > 0 {
+		set("canon", formatter.Amount(data.Pricing.Money(data.Pricing.MonthlyRent)))
+		set("canon_letras", AmountWithCurrencyInWords(data.Pricing.MonthlyRent, data.Pricing.CurrencyCode()))
+	} else {
+		set("canon", "")
+		set("canon_letras", "")
+	}
+	dueDay := 5 // Same default as the payment conditions of the billing emails
+	if data.Pricing != nil && data.Pricing.DueDay > 0 {
+		dueDay = data.Pricing.DueDay
+	}
+	set("dia_pago", fmt.Sprintf("%s (%d)", NumberToWords(dueDay), dueDay))
+
+	set("deposito", data.DepositText)
+
+	// Empty without promotions, the price clause then reads as usual
+	monthlyRent, securityDeposit := 0.0, 0.0
+	if data.Pricing != nil {
+		monthlyRent, securityDeposit = data.Pricing.MonthlyRent, data.Pricing.SecurityDeposit
+	}
+	values["promocion"] = PromotionClauseText(ApplyPromotions(monthlyRent, securityDeposit, data.Promotions))
+
+	// Empty without administración, parking or other charges
+	values["cargos_adicionales"] = ""
+	if data.Pricing != nil {
+		values["cargos_adicionales"] = PricingChargesClauseText(*data.Pricing)
+		breakdown := BreakdownPricing(*data.Pricing)
+		charges := make(map[string]float64)
+		for _, line := range breakdown.TenantLines() {
+			charges[line.Type] += line.Amount
+		}
+		set("administracion", formatCharge(breakdown.Money(charges[model.PricingComponentAdministracion])))
+		set("parqueadero", formatCharge(breakdown.Money(charges[model.PricingComponentParqueadero])))
+		set("total_mensual", formatCharge(breakdown.Money(breakdown.TenantTotal)))
+	} else {
+		set("administracion", "")
+		set("parqueadero", "")
+		set("total_mensual", "")
+	}
+	set("informacion_adicional", data.AdditionalInfo)
+
+	if !data.StartDate.IsZero() {
+		set("fecha_inicio", formatter.Date(data.StartDate))
+		set("fecha_inicio_letras", FormatSpanishDateWithDay(data.StartDate, formatter.Location()))
+	} else {
+		set("fecha_inicio", "")
+		set("fecha_inicio_letras", "")
+	}
+	if !data.EndDate.IsZero() {
+		set("fecha_fin", formatter.Date(data.EndDate))
+	} else {
+		set("fecha_fin", "")
+	}
+	if !data.StartDate.IsZero() && !data.EndDate.IsZero() {
+		set("duracion", CalendarTerm(data.StartDate.In(formatter.Location()), data.EndDate.In(formatter.Location())).Words())
+	} else {
+		set("duracion", "")
+	}
+
+	return values
+}
+
+// RenderTemplateText replaces the {{name}} placeholders of a text. Unknown placeholders are
+// kept as they are so a misspelled placeholder is visible in the generated contract.
+func RenderTemplateText(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ValidateContractTemplate checks that a template can be rendered
+func ValidateContractTemplate(template model.ContractTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(template.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if !model.IsValidContractType(template.ContractType) {
+		return fmt.Errorf("invalid contract type %q, use one of %s", template.ContractType, strings.Join(model.ContractTypes, ", "))
+	}
+	if len(template.Clauses) == 0 {
+		return fmt.Errorf("at least one clause is required")
+	}
+	if len(template.Clauses) > maxTemplateClauses {
+		return fmt.Errorf("a template cannot have more than %d clauses", maxTemplateClauses)
+	}
+	for i, clause := range template.Clauses {
+		if strings.TrimSpace(clause.Title) == "" || strings.TrimSpace(clause.Body) == "" {
+			return fmt.Errorf("clause %d needs a title and a body", i+1)
+		}
+	}
+	for i, block := range template.SignatureBlocks {
+		if strings.TrimSpace(block.Label) == "" {
+			return fmt.Errorf("signature block %d needs a label", i+1)
+		}
+		if !model.IsValidContractParty(block.Party) {
+			return fmt.Errorf("signature block %d has an invalid party %q", i+1, block.Party)
+		}
+	}
+	for name := range template.Variables {
+		if !placeholderPattern.MatchString("{{" + name + "}}") {
+			return fmt.Errorf("invalid variable name %q, use lowercase letters, digits and underscores", name)
+		}
+	}
+	return nil
+}
+
+var (
+	clauseOrdinalUnits = []string{"", "PRIMERA", "SEGUNDA", "TERCERA", "CUARTA", "QUINTA", "SEXTA", "SÉPTIMA", "OCTAVA", "NOVENA"}
+	clauseOrdinalTens  = []string{"", "DÉCIMA", "VIGÉSIMA", "TRIGÉSIMA"}
+)
+
+// ClauseOrdinal returns the Spanish ordinal used to number contract clauses (1 -> PRIMERA, 21 -> VIGÉSIMA PRIMERA)
+func ClauseOrdinal(n int) string {
+	if n <= 0 || n >= len(clauseOrdinalTens)*10 {
+		return fmt.Sprintf("%d.", n)
+	}
+	tens, units := clauseOrdinalTens[n/10], clauseOrdinalUnits[n%10]
+	return strings.TrimSpace(tens + " " + units)
+}
+
+// PropertyCharacteristicsText is the paragraph describing the area, rooms, stratum, furniture,
+// parking and boundaries of the property, empty when none of them is known
+func PropertyCharacteristicsText(property model.Property) string {
+	var features []string
+	if property.AreaM2 > 0 {
+		features = append(features, "un área aproximada de "+formatArea(property.AreaM2))
+	}
+	if property.Bedrooms > 0 {
+		features = append(features, pluralCount(property.Bedrooms, "habitación", "habitaciones"))
+	}
+	if property.Bathrooms > 0 {
+		features = append(features, pluralCount(property.Bathrooms, "baño", "baños"))
+	}
+	if parking := parkingText(property); parking != "" {
+		features = append(features, parking)
+	}
+
+	var sentences []string
+	if len(features) > 0 {
+		sentences = append(sentences, "El inmueble cuenta con "+joinSpanishList(features)+".")
+	}
+	if property.Estrato > 0 {
+		sentences = append(sentences, fmt.Sprintf("Se encuentra clasificado en el estrato %d.", property.Estrato))
+	}
+	if property.Furnished {
+		sentences = append(sentences, "Se entrega amoblado, conforme al inventario.")
+	}
+	if boundaries := strings.TrimSpace(property.Boundaries); boundaries != "" {
+		sentences = append(sentences, "Sus linderos son: "+strings.TrimRight(boundaries, ".")+".")
+	}
+
+	if len(sentences) == 0 {
+		return ""
+	}
+	return "PARÁGRAFO: " + strings.Join(sentences, " ")
+}
+
+// parkingText describes the parking spots of a property, empty without parking
+func parkingText(property model.Property) string {
+	if property.ParkingSpots <= 0 {
+		return ""
+	}
+	text := pluralCount(property.ParkingSpots, "parqueadero", "parqueaderos")
+	if details := strings.TrimSpace(property.ParkingDetails); details != "" {
+		text += " (" + details + ")"
+	}
+	return text
+}
+
+// pluralCount writes a count with its noun, e.g. "3 habitaciones"
+func pluralCount(count int, singular, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// countText writes a positive count, empty for 0
+func countText(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", count)
+}
+
+// formatArea writes an area in m² with a decimal comma, e.g. "72,5 m²"
+func formatArea(area float64) string {
+	text := strconv.FormatFloat(area, 'f', -1, 64)
+	return strings.Replace(text, ".", ",", 1) + " m²"
+}
+
+// BuildingClauseText is the paragraph naming the copropiedad of the property with its cuota de
+// administración and administrator
+func BuildingClauseText(building model.Building) string {
+	text := "PARÁGRAFO: El inmueble hace parte de " + strings.ToUpper(strings.TrimSpace(building.Name))
+	if building.NIT != "" {
+		text += ", identificada con NIT " + building.NIT
+	}
+	text += "."
+	if building.AdminFee > 0 {
+		text += fmt.Sprintf(" La cuota ordinaria mensual de administración vigente es de %s (%s).", FormatMoney(building.AdminFee), AmountWithCurrencyInWords(building.AdminFee, model.CurrencyCOP))
+	}
+	if building.AdministratorName != "" {
+		contact := []string{}
+		if building.AdministratorPhone != "" {
+			contact = append(contact, "teléfono "+building.AdministratorPhone)
+		}
+		if building.AdministratorEmail != "" {
+			contact = append(contact, "correo electrónico "+building.AdministratorEmail)
+		}
+		text += " La administración de la copropiedad está a cargo de " + building.AdministratorName
+		if len(contact) > 0 {
+			text += ", " + joinSpanishList(contact)
+		}
+		text += "."
+	}
+	return text
+}
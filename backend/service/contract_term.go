@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContractTerm is the length of a contract in calendar months and the days left over
+type ContractTerm struct {
+	Months int `json:"months"`
+	Days   int `json:"days"`
+}
+
+// civilDate returns the calendar day of t, at midnight UTC so day arithmetic ignores DST
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// addMonthsClamped adds months to a date keeping its day, or the last day of the target month
+// when that month is shorter (January 31 plus one month is February 28). clamped reports it.
+func addMonthsClamped(date time.Time, months int) (result time.Time, clamped bool) {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := date.Day()
+	if day > lastDay {
+		day, clamped = lastDay, true
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, time.UTC), clamped
+}
+
+// TermEndDate returns the last day of a term of months starting on start: the day before the
+// same day months later (March 5 for 12 months ends on March 4 of the next year), or the last
+// day of the final month when it has no such day (January 31 for one month ends on February 28).
+// The time of day and location of start are kept.
+func TermEndDate(start time.Time, months int) time.Time {
+	end, clamped := addMonthsClamped(civilDate(start), months)
+	if !clamped {
+		end = end.AddDate(0, 0, -1)
+	}
+	return time.Date(end.Year(), end.Month(), end.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// CalendarTerm returns the length of a contract from start to end, both days included, in
+// whole calendar months and the days after the last whole month: March 5 to February 4 is 11
+// months, March 5 to March 19 of the next year is 12 months and 15 days
+func CalendarTerm(start, end time.Time) ContractTerm {
+	startDay, endDay := civilDate(start), civilDate(end)
+	if endDay.Before(startDay) {
+		return ContractTerm{}
+	}
+
+	months := (endDay.Year()-startDay.Year())*12 + int(endDay.Month()-startDay.Month()) + 1
+	for months > 0 && civilDate(TermEndDate(startDay, months)).After(endDay) {
+		months--
+	}
+
+	lastMonthEnd := startDay.AddDate(0, 0, -1)
+	if months > 0 {
+		lastMonthEnd = civilDate(TermEndDate(startDay, months))
+	}
+	days := int(endDay.Sub(lastMonthEnd).Hours() / 24)
+	return ContractTerm{Months: months, Days: days}
+}
+
+// Words writes the term as contracts print it: whole years as "UN (1) AÑO", other lengths in
+// months as "ONCE (11) MESES" or "DIECIOCHO (18) MESES", and the days left over as "Y QUINCE
+// (15) DÍAS"
+func (t ContractTerm) Words() string {
+	var parts []string
+	switch {
+	case t.Months > 0 && t.Months%12 == 0:
+		parts = append(parts, countInWords(t.Months/12, "AÑO", "AÑOS"))
+	case t.Months > 0:
+		parts = append(parts, countInWords(t.Months, "MES", "MESES"))
+	}
+	if t.Days > 0 {
+		parts = append(parts, countInWords(t.Days, "DÍA", "DÍAS"))
+	}
+	return strings.Join(parts, " Y ")
+}
+
+// countInWords writes a count in words and digits followed by its noun: "UN (1) AÑO", "ONCE
+// (11) MESES"
+func countInWords(count int, singular, plural string) string {
+	noun := plural
+	if count == 1 {
+		noun = singular
+	}
+	return fmt.Sprintf("%s (%d) %s", wholeAmountInWords(float64(count)), count, noun)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// ContractTransfer describes the clause printed on the contract of a tenant moving from
+// another property of the same portfolio
+type ContractTransfer struct {
+	PreviousRentalID        string
+	PreviousPropertyAddress string
+	TransferDate            time.Time
+	Deposit                 model.DepositTransfer
+}
+
+// CalculateDepositTransfer carries the deposit of the previous rental over to the new one.
+// A positive adjustment is paid by the tenant, a negative one is refunded.
+func CalculateDepositTransfer(previousDeposit, adjustment float64, reason string) (*model.DepositTransfer, error) {
+	newDeposit := math.Round(previousDeposit + adjustment)
+	if newDeposit < 0 {
+		return nil, fmt.Errorf("the adjustment of %s exceeds the previous deposit of %s", FormatMoney(adjustment), FormatMoney(previousDeposit))
+	}
+
+	return &model.DepositTransfer{
+		PreviousDeposit: previousDeposit,
+		Adjustment:      adjustment,
+		NewDeposit:      newDeposit,
+		Reason:          reason,
+	}, nil
+}
+
+// TransferEndDate returns the last day of the previous rental for a transfer starting on start
+func TransferEndDate(start time.Time) time.Time {
+	return start.AddDate(0, 0, -1)
+}
+
+// RentalsOverlap reports whether a rental overlaps the term between start and end
+func RentalsOverlap(rental model.Rental, start, end time.Time) bool {
+	return !rental.StartDate.Time().After(end) && !rental.EndDate.Time().Before(start)
+}
+
+// DescribeDepositTransfer returns a human readable (Spanish) summary of a deposit transfer,
+// used in the rental history and the transfer clause
+func DescribeDepositTransfer(deposit model.DepositTransfer) string {
+	description := fmt.Sprintf("Depósito trasladado: %s", FormatMoney(deposit.PreviousDeposit))
+	switch {
+	case deposit.Adjustment > 0:
+		description += fmt.Sprintf(", ajuste a cargo del arrendatario de %s", FormatMoney(deposit.Adjustment))
+	case deposit.Adjustment < 0:
+		description += fmt.Sprintf(", reembolso al arrendatario de %s", FormatMoney(-deposit.Adjustment))
+	}
+	description += fmt.Sprintf(", nuevo depósito: %s.", FormatMoney(deposit.NewDeposit))
+	if deposit.Reason != "" {
+		description += " Motivo: " + deposit.Reason + "."
+	}
+	return description
+}
+
+// transferClauseText returns the body of the transfer clause of a contract
+func transferClauseText(transfer ContractTransfer) string {
+	return fmt.Sprintf("El presente contrato se celebra con ocasión del traslado del ARRENDATARIO desde el inmueble ubicado en %s, cuyo contrato de arrendamiento termina de mutuo acuerdo el %s. %s Las partes declaran que el depósito trasladado queda afecto a las obligaciones del presente contrato.", transfer.PreviousPropertyAddress, FormatDate(TransferEndDate(transfer.TransferDate)), DescribeDepositTransfer(transfer.Deposit))
+}
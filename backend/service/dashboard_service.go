@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// dashboardExpirationWindow is how far ahead expiring contracts are counted in the dashboard
+const dashboardExpirationWindow = 60 * 24 * time.Hour
+
+// Scopes of the dashboard summary
+const (
+	DashboardScopeAll     = "all"     // Every property, for admins
+	DashboardScopeManaged = "managed" // The properties managed by the user
+)
+
+// DashboardSummary aggregates the counts shown in the dashboard of admins and managers
+type DashboardSummary struct {
+	Scope                string    `json:"scope"` // One of the DashboardScope constants
+	Properties           int       `json:"properties"`
+	ActiveRentals        int       `json:"active_rentals"`
+	Vacancies            int       `json:"vacancies"`          // Properties without an active rental
+	PendingSignatures    int       `json:"pending_signatures"` // Signing requests not signed nor expired
+	OverduePayments      int       `json:"overdue_payments"`   // Active rentals with unpaid months
+	OpenMaintenance      int       `json:"open_maintenance"`
+	ExpiringContracts    int       `json:"expiring_contracts"` // Active rentals ending within 60 days
+	ExpirationWindowDays int       `json:"expiration_window_days"`
+	GeneratedAt          time.Time `json:"generated_at"`
+}
+
+// DashboardService computes the dashboard summary of a user in a single pass over its properties
+type DashboardService struct {
+	propertyRepo    storage.PropertyStore
+	rentalRepo      storage.RentalStore
+	signingRepo     storage.ContractSigningStore
+	maintenanceRepo storage.MaintenanceRequestStore
+	orgService      *OrganizationService
+}
+
+// NewDashboardService creates a new DashboardService
+func NewDashboardService(repoFactory *storage.RepositoryFactory, orgService *OrganizationService) *DashboardService {
+	return &DashboardService{
+		propertyRepo:    repoFactory.GetPropertyRepository(),
+		rentalRepo:      repoFactory.GetRentalRepository(),
+		signingRepo:     repoFactory.GetContractSigningRepository(),
+		maintenanceRepo: repoFactory.GetMaintenanceRequestRepository(),
+		orgService:      orgService,
+	}
+}
+
+// BuildSummary summarizes every property for admins and the managed properties for managers
+func (s *DashboardService) BuildSummary(ctx context.Context, user *model.User, now time.Time) (*DashboardSummary, error) {
+	summary := &DashboardSummary{
+		Scope:                DashboardScopeManaged,
+		ExpirationWindowDays: int(dashboardExpirationWindow.Hours() / 24),
+		GeneratedAt:          now,
+	}
+
+	var properties []model.Property
+	var rentals []model.Rental
+	var err error
+	if user.Role == "admin" {
+		summary.Scope = DashboardScopeAll
+		if properties, err = s.propertyRepo.GetAll(ctx); err != nil {
+			return nil, fmt.Errorf("failed to fetch properties: %w", err)
+		}
+		if rentals, err = s.rentalRepo.GetAll(ctx); err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals: %w", err)
+		}
+	} else {
+		if properties, err = s.propertyRepo.GetPropertiesForManager(ctx, user.PersonID); err != nil {
+			return nil, fmt.Errorf("failed to fetch properties of manager %s: %w", user.PersonID, err)
+		}
+		if rentals, err = s.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(properties)); err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals of manager %s: %w", user.PersonID, err)
+		}
+	}
+	summary.Properties = len(properties)
+
+	inScope := make(map[uuid.UUID]bool, len(properties))
+	propertyIDs := make([]string, 0, len(properties))
+	for _, property := range properties {
+		inScope[property.ID] = true
+		propertyIDs = append(propertyIDs, property.ID.String())
+	}
+
+	// The rentals are active on the days of the timezone of the organization of the user
+	var org *model.Organization
+	if s.orgService != nil {
+		org = s.orgService.ForPerson(ctx, user.PersonID)
+	}
+	loc := OrganizationLocation(org)
+
+	// Rentals: active, in arrears and about to expire
+	occupied := make(map[uuid.UUID]bool)
+	rentalIDs := make(map[string]bool, len(rentals))
+	for _, rental := range rentals {
+		if !inScope[rental.PropertyID] {
+			continue
+		}
+		rentalIDs[rental.ID.String()] = true
+		if OccupancyStatus(rental.StartDate.Time(), rental.EndDate.Time(), now, loc) != OccupancyActive {
+			continue
+		}
+		summary.ActiveRentals++
+		occupied[rental.PropertyID] = true
+		if rental.UnpaidMonths > 0 {
+			summary.OverduePayments++
+		}
+		if rental.EndDate.Time().Before(now.Add(dashboardExpirationWindow)) {
+			summary.ExpiringContracts++
+		}
+	}
+	summary.Vacancies = len(properties) - len(occupied)
+
+	// Signing requests still waiting for the signature
+	if len(rentalIDs) > 0 {
+		pending, err := s.signingRepo.GetPendingRequests(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pending signing requests: %w", err)
+		}
+		for _, record := range pending {
+			if rentalIDs[record.ContractID] {
+				summary.PendingSignatures++
+			}
+		}
+	}
+
+	// Maintenance requests still open
+	if len(propertyIDs) > 0 {
+		var requests []storage.MaintenanceRequest
+		if summary.Scope == DashboardScopeAll {
+			requests, err = s.maintenanceRepo.GetAll()
+		} else {
+			requests, err = s.maintenanceRepo.GetByPropertyIDs(propertyIDs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
+		}
+		for _, request := range requests {
+			if IsOpenMaintenanceStatus(request.Status) {
+				summary.OpenMaintenance++
+			}
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DocumentNumberingService hands out the consecutive numbers of the documents issued to the
+// tenants (cuentas de cobro and payment receipts). Each organization numbers each kind of
+// document from 1 every year; the sequences are kept in the database.
+type DocumentNumberingService struct {
+	repo       storage.DocumentSequenceStore
+	rentalRepo storage.RentalStore
+	orgService *OrganizationService
+}
+
+var documentNumbering *DocumentNumberingService
+
+// InitializeDocumentNumbering creates the service numbering the documents of the organizations
+func InitializeDocumentNumbering(repoFactory *storage.RepositoryFactory, orgService *OrganizationService) *DocumentNumberingService {
+	documentNumbering = &DocumentNumberingService{
+		repo:       repoFactory.GetDocumentSequenceRepository(),
+		rentalRepo: repoFactory.GetRentalRepository(),
+		orgService: orgService,
+	}
+	return documentNumbering
+}
+
+// GetDocumentNumbering returns the document numbering service, nil when it is not initialized
+func GetDocumentNumbering() *DocumentNumberingService {
+	return documentNumbering
+}
+
+// Next reserves the next number of a kind of document issued by org (nil for none) at
+// issuedAt, in the sequence of the year of issuedAt in the timezone of the organization
+func (s *DocumentNumberingService) Next(ctx context.Context, org *model.Organization, kind model.DocumentKind, issuedAt time.Time) (model.DocumentNumber, error) {
+	organizationID := uuid.Nil
+	if org != nil {
+		organizationID = org.ID
+	}
+
+	year := issuedAt.In(OrganizationLocation(org)).Year()
+	number, err := s.repo.Next(ctx, organizationID, kind, year)
+	if err != nil {
+		return model.DocumentNumber{}, fmt.Errorf("failed to reserve the next %s number: %w", kind, err)
+	}
+	return model.DocumentNumber{Kind: kind, Year: year, Number: number}, nil
+}
+
+// CreatePayment registers a payment in payments with the next receipt number of the
+// organization of the rented property in the year of the payment, reserved in the same
+// transaction as the insert. When s is nil the payment is registered without a number.
+func (s *DocumentNumberingService) CreatePayment(ctx context.Context, payments storage.RentPaymentStore, payment *storage.RentPayment) (*storage.RentPayment, error) {
+	if s == nil {
+		return payments.Create(payment)
+	}
+
+	organizationID, year := s.ReceiptSequence(ctx, *payment)
+	created, err := payments.CreateNumbered(payment, organizationID, year)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("receipt assigned to the payment of rental", "component", "numbering", "receipt_number", created.ReceiptNumber, "rental_id", created.RentalID)
+	return created, nil
+}
+
+// ReceiptSequence returns the organization (uuid.Nil for none) and the year whose sequence
+// numbers the receipt of a payment: the organization of the rented property and the year of the
+// payment in its timezone
+func (s *DocumentNumberingService) ReceiptSequence(ctx context.Context, payment storage.RentPayment) (uuid.UUID, int) {
+	var org *model.Organization
+	if rentalID, err := uuid.Parse(payment.RentalID); err == nil && s != nil && s.orgService != nil {
+		if rental, err := s.rentalRepo.GetByID(ctx, rentalID); err == nil && rental != nil {
+			org = s.orgService.ForProperty(ctx, rental.PropertyID)
+		}
+	}
+
+	paidAt := payment.PaymentDate.Time()
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+	organizationID := uuid.Nil
+	if org != nil {
+		organizationID = org.ID
+	}
+	return organizationID, paidAt.In(OrganizationLocation(org)).Year()
+}
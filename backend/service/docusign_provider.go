@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// DocuSignProvider implements ESignProvider for the DocuSign eSignature REST API.
+// Webhooks are DocuSign Connect notifications (JSON, HMAC signed).
+type DocuSignProvider struct {
+	baseURL     string // e.g. https://demo.docusign.net/restapi
+	accountID   string
+	accessToken string
+	hmacKey     string
+	httpClient  *http.Client
+}
+
+// NewDocuSignProvider creates a DocuSign provider from the DOCUSIGN_* settings
+func NewDocuSignProvider(cfg config.DocuSignConfig) (*DocuSignProvider, error) {
+	provider := &DocuSignProvider{
+		baseURL:     cfg.BaseURL,
+		accountID:   cfg.AccountID,
+		accessToken: cfg.AccessToken,
+		hmacKey:     cfg.ConnectHMACKey,
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+	}
+
+	if provider.baseURL == "" || provider.accountID == "" || provider.accessToken == "" {
+		return nil, errors.New("DOCUSIGN_BASE_URL, DOCUSIGN_ACCOUNT_ID and DOCUSIGN_ACCESS_TOKEN must be configured")
+	}
+	if provider.hmacKey == "" {
+		return nil, errors.New("DOCUSIGN_CONNECT_HMAC_KEY is not configured")
+	}
+
+	return provider, nil
+}
+
+// Name returns the provider identifier
+func (p *DocuSignProvider) Name() string {
+	return "docusign"
+}
+
+// CreateEnvelope creates and sends an envelope with the contract and a single signer
+func (p *DocuSignProvider) CreateEnvelope(ctx context.Context, req ESignEnvelopeRequest) (*ESignEnvelope, error) {
+	payload := map[string]interface{}{
+		"emailSubject": "Contrato listo para firma",
+		"status":       "sent",
+		"documents": []map[string]interface{}{
+			{
+				"documentBase64": base64.StdEncoding.EncodeToString(req.PDFData),
+				"name":           req.DocumentName,
+				"fileExtension":  "pdf",
+				"documentId":     "1",
+			},
+		},
+		"recipients": map[string]interface{}{
+			"signers": []map[string]interface{}{
+				{
+					"email":        req.SignerEmail,
+					"name":         req.SignerName,
+					"recipientId":  "1",
+					"routingOrder": "1",
+					"tabs": map[string]interface{}{
+						"signHereTabs": []map[string]interface{}{
+							{"anchorString": "ARRENDATARIO", "anchorYOffset": "20", "anchorUnits": "pixels"},
+						},
+					},
+				},
+			},
+		},
+		"customFields": map[string]interface{}{
+			"textCustomFields": []map[string]interface{}{
+				{"name": "signing_id", "value": req.SigningID, "show": "false"},
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding DocuSign envelope: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.accountURL()+"/envelopes", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating DocuSign request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	respBody, err := p.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope struct {
+		EnvelopeID string `json:"envelopeId"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return nil, fmt.Errorf("error parsing DocuSign response: %w", err)
+	}
+
+	// DocuSign emails the signer itself, there is no direct link for remote signers
+	return &ESignEnvelope{ExternalID: envelope.EnvelopeID}, nil
+}
+
+// ParseWebhook verifies the Connect HMAC signature and reads the envelope event
+func (p *DocuSignProvider) ParseWebhook(header http.Header, body []byte) (*ESignWebhookEvent, error) {
+	mac := hmac.New(sha256.New, []byte(p.hmacKey))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(header.Get("X-DocuSign-Signature-1")), []byte(expected)) {
+		return nil, errors.New("invalid DocuSign Connect signature")
+	}
+
+	var payload struct {
+		Event string `json:"event"`
+		Data  struct {
+			EnvelopeID string `json:"envelopeId"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("error parsing DocuSign webhook: %w", err)
+	}
+	if payload.Data.EnvelopeID == "" {
+		return nil, errors.New("DocuSign webhook without envelope ID")
+	}
+
+	event := &ESignWebhookEvent{ExternalID: payload.Data.EnvelopeID, RawStatus: payload.Event, Type: ESignEventOther}
+	switch payload.Event {
+	case "envelope-completed":
+		event.Type = ESignEventSigned
+	case "envelope-declined", "envelope-voided", "recipient-declined":
+		event.Type = ESignEventRejected
+	}
+	return event, nil
+}
+
+// DownloadSignedDocument downloads the combined signed PDF of an envelope
+func (p *DocuSignProvider) DownloadSignedDocument(ctx context.Context, externalID string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.accountURL()+"/envelopes/"+externalID+"/documents/combined", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DocuSign request: %w", err)
+	}
+	return p.do(httpReq)
+}
+
+func (p *DocuSignProvider) accountURL() string {
+	return p.baseURL + "/v2.1/accounts/" + p.accountID
+}
+
+// do sends an authenticated request and returns the response body
+func (p *DocuSignProvider) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+p.accessToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling DocuSign: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading DocuSign response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("DocuSign API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
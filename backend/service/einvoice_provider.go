@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// EInvoiceSubmission is an electronic invoice sent to the authorized provider
+type EInvoiceSubmission struct {
+	PaymentID     string // Our payment ID, sent as external reference
+	Number        string
+	CUFE          string
+	Environment   string
+	XML           []byte // Unsigned UBL 2.1 invoice
+	CustomerEmail string
+}
+
+// EInvoiceResult is the state of an invoice on the provider
+type EInvoiceResult struct {
+	ExternalID string
+	Status     string // Normalized to one of the model.EInvoiceStatus constants
+	CUFE       string // As validated by the DIAN, empty when the provider does not return it
+	Message    string // Observations or rejection reasons of the DIAN
+}
+
+// EInvoiceProvider submits electronic invoices to the DIAN through an authorized technology
+// provider (proveedor tecnológico), which signs them and delivers them to the customer
+type EInvoiceProvider interface {
+	// Name returns the identifier stored with the invoices
+	Name() string
+	// SubmitInvoice sends an invoice to be signed and validated. The DIAN may answer later, the
+	// result is then pending.
+	SubmitInvoice(ctx context.Context, req EInvoiceSubmission) (*EInvoiceResult, error)
+	// InvoiceStatus fetches the validation state of a submitted invoice
+	InvoiceStatus(ctx context.Context, externalID string) (*EInvoiceResult, error)
+}
+
+// GetEInvoiceProvider returns the electronic invoicing provider configured in cfg
+func GetEInvoiceProvider(cfg config.EInvoiceConfig) (EInvoiceProvider, error) {
+	return NewEInvoiceAPIProvider(cfg)
+}
+
+// EInvoiceAPIProvider implements EInvoiceProvider for providers exposing a JSON invoice API
+// (POST /invoices, GET /invoices/:id) authenticated with a bearer token, such as the REST
+// gateways of Facturatech and similar operators
+type EInvoiceAPIProvider struct {
+	name       string
+	apiURL     string
+	apiToken   string
+	httpClient *http.Client
+}
+
+// NewEInvoiceAPIProvider creates an electronic invoicing provider from the EINVOICE_API_*
+// settings
+func NewEInvoiceAPIProvider(cfg config.EInvoiceConfig) (*EInvoiceAPIProvider, error) {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		return nil, errors.New("EINVOICE_API_URL is not configured")
+	}
+
+	apiToken := cfg.APIToken
+	if apiToken == "" {
+		return nil, errors.New("EINVOICE_API_TOKEN is not configured")
+	}
+
+	name := cfg.ProviderName
+	if name == "" {
+		name = "einvoice"
+	}
+
+	return &EInvoiceAPIProvider{
+		name:       name,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		apiToken:   apiToken,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *EInvoiceAPIProvider) Name() string {
+	return p.name
+}
+
+type einvoiceDocument struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	CUFE    string `json:"cufe"`
+	Message string `json:"message"`
+}
+
+// SubmitInvoice uploads the UBL invoice to be signed and sent to the DIAN
+func (p *EInvoiceAPIProvider) SubmitInvoice(ctx context.Context, req EInvoiceSubmission) (*EInvoiceResult, error) {
+	payload := map[string]interface{}{
+		"external_reference": req.PaymentID,
+		"number":             req.Number,
+		"cufe":               req.CUFE,
+		"environment":        req.Environment,
+		"document_type":      "01",
+		"xml_base64":         base64.StdEncoding.EncodeToString(req.XML),
+	}
+	if req.CustomerEmail != "" {
+		payload["customer_email"] = req.CustomerEmail
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding invoice request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/invoices", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating invoice request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return p.document(httpReq)
+}
+
+// InvoiceStatus fetches the state of an invoice
+func (p *EInvoiceAPIProvider) InvoiceStatus(ctx context.Context, externalID string) (*EInvoiceResult, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/invoices/"+url.PathEscape(externalID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating invoice request: %w", err)
+	}
+
+	return p.document(httpReq)
+}
+
+// document sends a request answered with an invoice
+func (p *EInvoiceAPIProvider) document(req *http.Request) (*EInvoiceResult, error) {
+	respBody, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var document einvoiceDocument
+	if err := json.Unmarshal(respBody, &document); err != nil {
+		return nil, fmt.Errorf("error parsing invoice response: %w", err)
+	}
+	if document.ID == "" {
+		return nil, errors.New("invoice response without document ID")
+	}
+	return document.result(), nil
+}
+
+// result normalizes the invoice status, providers answer in Spanish or English
+func (d einvoiceDocument) result() *EInvoiceResult {
+	status := model.EInvoiceStatusPending
+	switch strings.ToLower(d.Status) {
+	case "accepted", "validated", "approved", "aceptada", "aceptado", "validada", "aprobada", "exitosa":
+		status = model.EInvoiceStatusAccepted
+	case "rejected", "invalid", "rechazada", "rechazado", "invalida", "inválida":
+		status = model.EInvoiceStatusRejected
+	}
+
+	return &EInvoiceResult{
+		ExternalID: d.ID,
+		Status:     status,
+		CUFE:       d.CUFE,
+		Message:    d.Message,
+	}
+}
+
+// do sends an authenticated request and returns the response body
+func (p *EInvoiceAPIProvider) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling invoicing provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading invoicing provider response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("invoicing provider API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// defaultEInvoiceStatusSchedule checks the invoices waiting for the DIAN every 30 minutes when
+// EINVOICE_STATUS_SCHEDULE is not set
+const defaultEInvoiceStatusSchedule = "*/30 * * * *"
+
+var (
+	// ErrEInvoicePaymentNotFound is returned when the payment to invoice does not exist
+	ErrEInvoicePaymentNotFound = errors.New("rent payment not found")
+	// ErrEInvoiceAlreadyIssued is returned when the payment already has an invoice pending or accepted
+	ErrEInvoiceAlreadyIssued = errors.New("the payment already has an electronic invoice")
+	// ErrEInvoiceNotSubmitted is returned when refreshing a payment that was never invoiced
+	ErrEInvoiceNotSubmitted = errors.New("the payment has no electronic invoice submitted")
+	// ErrInvalidEInvoice is returned when the payment lacks data the invoice requires
+	ErrInvalidEInvoice = errors.New("the payment cannot be invoiced")
+	// ErrEInvoiceRangeExhausted is returned when the numbering resolution has no numbers left
+	ErrEInvoiceRangeExhausted = errors.New("the authorized invoice numbering range is exhausted")
+)
+
+// EInvoiceService issues the DIAN electronic invoices of the rent payments and keeps their
+// acceptance status on the payment
+type EInvoiceService struct {
+	config       *EInvoiceConfig
+	schedule     string
+	provider     EInvoiceProvider
+	paymentRepo  storage.RentPaymentStore
+	rentalRepo   storage.RentalStore
+	personRepo   storage.PersonStore
+	propertyRepo storage.PropertyStore
+	pricingRepo  storage.PricingStore
+	userRepo     storage.UserStore
+	orgService   *OrganizationService
+	mu           sync.Mutex // Serializes the numbering so two invoices never get the same number
+}
+
+var einvoices *EInvoiceService
+
+// InitializeEInvoices creates the electronic invoicing service when the issuer and the provider
+// are configured in cfg, returning nil otherwise. The invoices waiting for the DIAN are checked
+// on schedule (EINVOICE_STATUS_SCHEDULE).
+func InitializeEInvoices(repoFactory *storage.RepositoryFactory, orgService *OrganizationService, cfg config.EInvoiceConfig, schedule string) *EInvoiceService {
+	issuer, err := NewEInvoiceConfig(cfg)
+	if err != nil {
+		slog.Warn("facturación electrónica no configurada", "error", err)
+		return nil
+	}
+	provider, err := GetEInvoiceProvider(cfg)
+	if err != nil {
+		slog.Warn("facturación electrónica no configurada", "error", err)
+		return nil
+	}
+
+	einvoices = &EInvoiceService{
+		config:       issuer,
+		schedule:     schedule,
+		provider:     provider,
+		paymentRepo:  repoFactory.GetRentPaymentRepository(),
+		rentalRepo:   repoFactory.GetRentalRepository(),
+		personRepo:   repoFactory.GetPersonRepository(),
+		propertyRepo: repoFactory.GetPropertyRepository(),
+		pricingRepo:  repoFactory.GetPricingRepository(),
+		userRepo:     repoFactory.GetUserRepository(),
+		orgService:   orgService,
+	}
+	return einvoices
+}
+
+// GetEInvoices returns the electronic invoicing service, nil when it is not configured
+func GetEInvoices() *EInvoiceService {
+	return einvoices
+}
+
+// EInvoiceAutoSubmit reports whether payments are invoiced as soon as they are registered
+// (EINVOICE_AUTO_SUBMIT=true, or the flag set by an admin)
+func EInvoiceAutoSubmit() bool {
+	return FeatureEnabled(model.FeatureEInvoiceAutoSubmit)
+}
+
+// AutoInvoice invoices a newly registered payment in the background when EInvoiceAutoSubmit. It does nothing when s is nil.
+func (s *EInvoiceService) AutoInvoice(paymentID string) {
+	if s == nil || !EInvoiceAutoSubmit() {
+		return
+	}
+
+	runInBackground(func() {
+		if _, err := s.InvoicePayment(context.Background(), paymentID); err != nil {
+			slog.Warn("payment was not invoiced automatically", "component", "einvoice", "payment_id", paymentID, "error", err)
+		}
+	})
+}
+
+// InvoicePayment issues the electronic invoice of a payment and submits it to the provider. An
+// invoice rejected by the DIAN or that could not be sent is submitted again with its number.
+func (s *EInvoiceService) InvoicePayment(ctx context.Context, paymentID string) (*storage.RentPayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, err := s.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEInvoicePaymentNotFound, err)
+	}
+	if !model.EInvoiceResubmittable(payment.InvoiceStatus) {
+		return nil, ErrEInvoiceAlreadyIssued
+	}
+	if payment.AmountPaid <= 0 {
+		return nil, fmt.Errorf("%w: the amount paid must be positive", ErrInvalidEInvoice)
+	}
+
+	sequence := payment.InvoiceSequence
+	if sequence == 0 {
+		last, err := s.paymentRepo.GetLastInvoiceSequence()
+		if err != nil {
+			return nil, err
+		}
+		sequence = max(last+1, s.config.RangeFrom)
+		if sequence > s.config.RangeTo {
+			return nil, ErrEInvoiceRangeExhausted
+		}
+	}
+
+	doc, customerEmail, err := s.document(ctx, payment, sequence, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	xmlData, cufe, err := s.config.BuildUBLInvoice(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEInvoice, err)
+	}
+
+	invoicedAt := doc.IssuedAt
+	invoice := storage.RentPaymentInvoice{
+		InvoiceSequence: sequence,
+		InvoiceNumber:   s.config.InvoiceNumber(sequence),
+		InvoiceCUFE:     cufe,
+		InvoiceProvider: s.provider.Name(),
+		InvoicedAt:      &invoicedAt,
+	}
+
+	result, submitErr := s.provider.SubmitInvoice(ctx, EInvoiceSubmission{
+		PaymentID:     payment.ID,
+		Number:        invoice.InvoiceNumber,
+		CUFE:          cufe,
+		Environment:   s.config.Environment,
+		XML:           xmlData,
+		CustomerEmail: customerEmail,
+	})
+	if submitErr != nil {
+		// The number stays with the payment so the retry reuses it
+		invoice.InvoiceStatus = model.EInvoiceStatusFailed
+		invoice.InvoiceMessage = submitErr.Error()
+	} else {
+		invoice.InvoiceStatus = result.Status
+		invoice.InvoiceMessage = result.Message
+		invoice.InvoiceExternalID = result.ExternalID
+		if result.CUFE != "" {
+			invoice.InvoiceCUFE = result.CUFE
+		}
+	}
+
+	if err := s.paymentRepo.UpdateInvoice(payment.ID, invoice); err != nil {
+		return nil, err
+	}
+	payment.RentPaymentInvoice = invoice
+
+	if submitErr != nil {
+		logging.FromContext(ctx).Error("invoice of payment could not be submitted", "component", "einvoice", "invoice_number", invoice.InvoiceNumber, "payment_id", payment.ID, "error", submitErr)
+		return payment, fmt.Errorf("failed to submit invoice %s: %w", invoice.InvoiceNumber, submitErr)
+	}
+	logging.FromContext(ctx).Info("invoice of payment submitted", "component", "einvoice", "invoice_number", invoice.InvoiceNumber, "payment_id", payment.ID, "invoice_status", invoice.InvoiceStatus)
+	return payment, nil
+}
+
+// RefreshStatus updates the payment with the validation state of its invoice on the provider
+func (s *EInvoiceService) RefreshStatus(ctx context.Context, paymentID string) (*storage.RentPayment, error) {
+	payment, err := s.paymentRepo.GetByID(paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEInvoicePaymentNotFound, err)
+	}
+	return s.refresh(ctx, payment)
+}
+
+// RefreshPending updates the invoices still waiting for the DIAN and returns how many got an answer
+func (s *EInvoiceService) RefreshPending(ctx context.Context) (int, error) {
+	payments, err := s.paymentRepo.GetByInvoiceStatus(model.EInvoiceStatusPending)
+	if err != nil {
+		return 0, err
+	}
+
+	answered := 0
+	for i := range payments {
+		updated, err := s.refresh(ctx, &payments[i])
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not check invoice", "component", "einvoice", "invoice_number", payments[i].InvoiceNumber, "error", err)
+			continue
+		}
+		if updated.InvoiceStatus != model.EInvoiceStatusPending {
+			answered++
+		}
+	}
+	return answered, nil
+}
+
+// refresh fetches the state of the invoice of a payment and stores it when it changed
+func (s *EInvoiceService) refresh(ctx context.Context, payment *storage.RentPayment) (*storage.RentPayment, error) {
+	if payment.InvoiceExternalID == "" {
+		return nil, ErrEInvoiceNotSubmitted
+	}
+
+	result, err := s.provider.InvoiceStatus(ctx, payment.InvoiceExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := payment.RentPaymentInvoice
+	if result.Status == invoice.InvoiceStatus && result.Message == invoice.InvoiceMessage {
+		return payment, nil
+	}
+	invoice.InvoiceStatus = result.Status
+	invoice.InvoiceMessage = result.Message
+	if result.CUFE != "" {
+		invoice.InvoiceCUFE = result.CUFE
+	}
+	if err := s.paymentRepo.UpdateInvoice(payment.ID, invoice); err != nil {
+		return nil, err
+	}
+	payment.RentPaymentInvoice = invoice
+
+	logging.FromContext(ctx).Info("invoice of payment is now", "component", "einvoice", "invoice_number", invoice.InvoiceNumber, "payment_id", payment.ID, "invoice_status", invoice.InvoiceStatus)
+	return payment, nil
+}
+
+// document gathers the tenant, property and charges of a payment into an invoice. It also
+// returns the email the provider delivers the invoice to.
+func (s *EInvoiceService) document(ctx context.Context, payment *storage.RentPayment, sequence int64, issuedAt time.Time) (EInvoiceDocument, string, error) {
+	rentalID, err := uuid.Parse(payment.RentalID)
+	if err != nil {
+		return EInvoiceDocument{}, "", fmt.Errorf("%w: invalid rental ID", ErrInvalidEInvoice)
+	}
+	rental, err := s.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil {
+		return EInvoiceDocument{}, "", err
+	}
+	if rental == nil {
+		return EInvoiceDocument{}, "", fmt.Errorf("%w: rental %s not found", ErrInvalidEInvoice, rentalID)
+	}
+
+	tenant, err := s.personRepo.GetByID(ctx, rental.RenterID)
+	if err != nil {
+		return EInvoiceDocument{}, "", err
+	}
+	if tenant == nil || onlyDigits(tenant.NIT) == "" {
+		return EInvoiceDocument{}, "", fmt.Errorf("%w: the tenant has no NIT or cédula", ErrInvalidEInvoice)
+	}
+
+	customer := EInvoiceCustomer{Name: tenant.FullName, ID: tenant.NIT}
+	if user, err := s.userRepo.GetByPersonID(ctx, tenant.ID); err == nil && user != nil {
+		customer.Email = user.Email
+	}
+	address := ""
+	if property, err := s.propertyRepo.GetByID(ctx, rental.PropertyID); err == nil && property != nil {
+		address = property.Address
+		if property.AptNumber != "" {
+			address += " " + property.AptNumber
+		}
+		customer.Address = address
+		customer.City = property.City
+	}
+
+	// The period billed is the month of the payment in the timezone of the organization
+	var org *model.Organization
+	if s.orgService != nil {
+		org = s.orgService.ForProperty(ctx, rental.PropertyID)
+	}
+	loc := OrganizationLocation(org)
+	paidAt := payment.PaymentDate.Time().In(loc)
+	if paidAt.IsZero() {
+		paidAt = issuedAt.In(loc)
+	}
+	periodStart := time.Date(paidAt.Year(), paidAt.Month(), 1, 0, 0, 0, 0, loc)
+	periodEnd := periodStart.AddDate(0, 1, -1)
+	period := fmt.Sprintf("%s de %d", spanishMonthNames[paidAt.Month()-1], paidAt.Year())
+
+	pricing, err := s.pricingRepo.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not load the pricing of rental, invoicing the payment as canon", "component", "einvoice", "rental_id", rentalID, "error", err)
+		pricing = nil
+	}
+
+	doc := EInvoiceDocument{
+		Sequence:    sequence,
+		IssuedAt:    issuedAt,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Customer:    customer,
+		Lines:       einvoiceLines(pricing, payment.AmountPaid, period),
+	}
+	if address != "" {
+		doc.Note = "Arrendamiento del inmueble ubicado en " + address
+	}
+	return doc, customer.Email, nil
+}
+
+// einvoiceLines itemizes the charges of the pricing when the payment covers them exactly, and
+// otherwise invoices the amount paid as canon. The canon of housing is excluded from IVA.
+func einvoiceLines(pricing *model.Pricing, amountPaid float64, period string) []EInvoiceLine {
+	if pricing != nil {
+		breakdown := BreakdownPricing(*pricing)
+		if math.Abs(breakdown.TenantTotal-amountPaid) < 1 {
+			var lines []EInvoiceLine
+			for _, line := range breakdown.TenantLines() {
+				invoiceLine := EInvoiceLine{
+					Code:        line.Type,
+					Description: line.Label + " " + period,
+					Amount:      line.Amount,
+					IVA:         line.IVA,
+				}
+				if line.Taxable {
+					invoiceLine.IVAPercent = IVAPercentage
+				}
+				lines = append(lines, invoiceLine)
+			}
+			if len(lines) > 0 {
+				return lines
+			}
+		}
+	}
+
+	return []EInvoiceLine{{
+		Code:        model.PricingComponentCanon,
+		Description: model.PricingComponentLabels[model.PricingComponentCanon] + " " + period,
+		Amount:      amountPaid,
+	}}
+}
+
+// Start schedules the job checking the invoices waiting for the DIAN
+func (s *EInvoiceService) Start() error {
+	schedule := s.schedule
+	if schedule == "" {
+		schedule = defaultEInvoiceStatusSchedule
+	}
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("einvoice_status") {
+			return
+		}
+		start := time.Now()
+		answered, err := s.RefreshPending(context.Background())
+		metrics.JobRun("einvoice_status", start, err)
+		if err != nil {
+			slog.Error("error checking pending invoices", "component", "einvoice", "error", err)
+		} else if answered > 0 {
+			slog.Info("invoices answered by the DIAN", "component", "einvoice", "answered", answered)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid EINVOICE_STATUS_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("einvoice status checker", c)
+
+	slog.Info("electronic invoice status checker started", "component", "einvoice", "schedule", schedule)
+	return nil
+}
@@ -0,0 +1,620 @@
+package service
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// DIAN environments of the electronic invoices
+const (
+	EInvoiceEnvironmentProduction = "1"
+	EInvoiceEnvironmentTesting    = "2"
+)
+
+// EInvoiceConfig holds the issuer and the numbering resolution the DIAN authorized for the
+// electronic invoices of the rent
+type EInvoiceConfig struct {
+	Environment      string // 1 producción, 2 habilitación (pruebas)
+	IssuerNIT        string // Without check digit
+	IssuerDV         string
+	IssuerPersonType string // 1 persona jurídica, 2 persona natural
+	IssuerName       string
+	IssuerAddress    string
+	IssuerCity       string
+	IssuerEmail      string
+	IssuerTaxLevel   string // Responsabilidad fiscal, e.g. R-99-PN
+	Resolution       string
+	ResolutionStart  time.Time
+	ResolutionEnd    time.Time
+	Prefix           string
+	RangeFrom        int64
+	RangeTo          int64
+	TechnicalKey     string
+	SoftwareID       string
+	SoftwarePIN      string
+	SoftwareProvider string // NIT of the software provider, the issuer when it uses its own software
+}
+
+// NewEInvoiceConfig checks the issuer and numbering resolution of the EINVOICE_* settings
+func NewEInvoiceConfig(cfg config.EInvoiceConfig) (*EInvoiceConfig, error) {
+	required := func(name, value string) (string, error) {
+		if value == "" {
+			return "", fmt.Errorf("%s is not configured", name)
+		}
+		return value, nil
+	}
+
+	ec := &EInvoiceConfig{
+		Environment:      cfg.Environment,
+		IssuerPersonType: cfg.IssuerPersonType,
+		IssuerAddress:    cfg.IssuerAddress,
+		IssuerCity:       cfg.IssuerCity,
+		IssuerEmail:      cfg.IssuerEmail,
+		IssuerTaxLevel:   cfg.IssuerTaxLevel,
+		Prefix:           cfg.Prefix,
+	}
+	if ec.Environment == "" {
+		ec.Environment = EInvoiceEnvironmentTesting
+	}
+	if ec.Environment != EInvoiceEnvironmentProduction && ec.Environment != EInvoiceEnvironmentTesting {
+		return nil, fmt.Errorf("invalid EINVOICE_ENVIRONMENT %q, use 1 (production) or 2 (testing)", ec.Environment)
+	}
+	if ec.IssuerPersonType == "" {
+		ec.IssuerPersonType = "1"
+	}
+	if ec.IssuerPersonType != "1" && ec.IssuerPersonType != "2" {
+		return nil, fmt.Errorf("invalid EINVOICE_ISSUER_PERSON_TYPE %q, use 1 (persona jurídica) or 2 (persona natural)", ec.IssuerPersonType)
+	}
+	if ec.IssuerTaxLevel == "" {
+		ec.IssuerTaxLevel = "R-99-PN"
+	}
+
+	nit, err := required("EINVOICE_ISSUER_NIT", cfg.IssuerNIT)
+	if err != nil {
+		return nil, err
+	}
+	ec.IssuerNIT, ec.IssuerDV = splitNIT(nit)
+	if ec.IssuerName, err = required("EINVOICE_ISSUER_NAME", cfg.IssuerName); err != nil {
+		return nil, err
+	}
+	if ec.Resolution, err = required("EINVOICE_RESOLUTION", cfg.Resolution); err != nil {
+		return nil, err
+	}
+	if ec.TechnicalKey, err = required("EINVOICE_TECHNICAL_KEY", cfg.TechnicalKey); err != nil {
+		return nil, err
+	}
+	if ec.SoftwareID, err = required("EINVOICE_SOFTWARE_ID", cfg.SoftwareID); err != nil {
+		return nil, err
+	}
+	if ec.SoftwarePIN, err = required("EINVOICE_SOFTWARE_PIN", cfg.SoftwarePIN); err != nil {
+		return nil, err
+	}
+	ec.SoftwareProvider, _ = splitNIT(cfg.SoftwareProviderNIT)
+	if ec.SoftwareProvider == "" {
+		ec.SoftwareProvider = ec.IssuerNIT
+	}
+
+	for _, date := range []struct {
+		name   string
+		value  string
+		target *time.Time
+	}{
+		{"EINVOICE_RESOLUTION_START", cfg.ResolutionStart, &ec.ResolutionStart},
+		{"EINVOICE_RESOLUTION_END", cfg.ResolutionEnd, &ec.ResolutionEnd},
+	} {
+		value, err := required(date.name, date.value)
+		if err != nil {
+			return nil, err
+		}
+		if *date.target, err = time.ParseInLocation("2006-01-02", value, AppLocation()); err != nil {
+			return nil, fmt.Errorf("invalid %s %q, use YYYY-MM-DD", date.name, value)
+		}
+	}
+
+	for _, number := range []struct {
+		name   string
+		value  string
+		target *int64
+	}{
+		{"EINVOICE_RANGE_FROM", cfg.RangeFrom, &ec.RangeFrom},
+		{"EINVOICE_RANGE_TO", cfg.RangeTo, &ec.RangeTo},
+	} {
+		value, err := required(number.name, number.value)
+		if err != nil {
+			return nil, err
+		}
+		if *number.target, err = strconv.ParseInt(value, 10, 64); err != nil || *number.target <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", number.name, value)
+		}
+	}
+	if ec.RangeFrom > ec.RangeTo {
+		return nil, errors.New("EINVOICE_RANGE_FROM is greater than EINVOICE_RANGE_TO")
+	}
+
+	return ec, nil
+}
+
+// InvoiceNumber returns the number of an invoice, the prefix followed by its consecutive
+func (c *EInvoiceConfig) InvoiceNumber(sequence int64) string {
+	return fmt.Sprintf("%s%d", c.Prefix, sequence)
+}
+
+// splitNIT separates the check digit of a NIT written as 900123456-7, computing it when missing
+func splitNIT(nit string) (number, dv string) {
+	nit = strings.TrimSpace(nit)
+	if before, after, ok := strings.Cut(nit, "-"); ok {
+		return onlyDigits(before), onlyDigits(after)
+	}
+	number = onlyDigits(nit)
+	if number == "" {
+		return "", ""
+	}
+	return number, nitCheckDigit(number)
+}
+
+// nitCheckDigit computes the DIAN check digit (dígito de verificación) of a NIT
+func nitCheckDigit(nit string) string {
+	weights := []int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43, 47, 53, 59, 67, 71}
+	sum := 0
+	for i := 0; i < len(nit) && i < len(weights); i++ {
+		sum += int(nit[len(nit)-1-i]-'0') * weights[i]
+	}
+	remainder := sum % 11
+	if remainder > 1 {
+		return strconv.Itoa(11 - remainder)
+	}
+	return strconv.Itoa(remainder)
+}
+
+// onlyDigits removes everything but the digits of an identification number
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// EInvoiceLine is a charge of an electronic invoice
+type EInvoiceLine struct {
+	Code        string
+	Description string
+	Amount      float64 // Before taxes
+	IVA         float64
+	IVAPercent  float64 // 0 for charges excluded from IVA
+}
+
+// EInvoiceCustomer is the tenant the invoice is issued to
+type EInvoiceCustomer struct {
+	Name    string
+	ID      string // Cédula, or NIT with check digit as 900123456-7
+	Email   string
+	Address string
+	City    string
+}
+
+// EInvoiceDocument holds the data of an electronic sales invoice
+type EInvoiceDocument struct {
+	Sequence    int64
+	IssuedAt    time.Time
+	DueDate     time.Time
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Customer    EInvoiceCustomer
+	Lines       []EInvoiceLine
+	Note        string
+}
+
+// Totals returns the amount before taxes, the IVA and the payable amount of the invoice
+func (d EInvoiceDocument) Totals() (subtotal, iva, total float64) {
+	for _, line := range d.Lines {
+		subtotal += line.Amount
+		iva += line.IVA
+	}
+	return subtotal, iva, subtotal + iva
+}
+
+// ublAmount formats amounts with two decimals and a dot, as the DIAN requires
+func ublAmount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// ublIssueTime returns the date and time of an invoice in Colombian time (UTC-5)
+func ublIssueTime(t time.Time) (date, hour string) {
+	colombia := t.In(time.FixedZone("COT", -5*60*60))
+	return colombia.Format("2006-01-02"), colombia.Format("15:04:05") + "-05:00"
+}
+
+// CUFE computes the Código Único de Factura Electrónica, the SHA-384 of the invoice number,
+// date, amounts, taxes, issuer, customer, technical key and environment
+func (c *EInvoiceConfig) CUFE(doc EInvoiceDocument) string {
+	date, hour := ublIssueTime(doc.IssuedAt)
+	subtotal, iva, total := doc.Totals()
+	customerID, _ := splitNIT(doc.Customer.ID)
+
+	data := c.InvoiceNumber(doc.Sequence) + date + hour + ublAmount(subtotal) +
+		"01" + ublAmount(iva) + // IVA
+		"04" + ublAmount(0) + // Impuesto nacional al consumo
+		"03" + ublAmount(0) + // ICA
+		ublAmount(total) + c.IssuerNIT + customerID + c.TechnicalKey + c.Environment
+
+	sum := sha512.Sum384([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// softwareSecurityCode is the SHA-384 of the software ID, its PIN and the invoice number
+func (c *EInvoiceConfig) softwareSecurityCode(number string) string {
+	sum := sha512.Sum384([]byte(c.SoftwareID + c.SoftwarePIN + number))
+	return hex.EncodeToString(sum[:])
+}
+
+// qrCode returns the content of the QR code printed on the invoice
+func (c *EInvoiceConfig) qrCode(doc EInvoiceDocument, cufe string) string {
+	date, hour := ublIssueTime(doc.IssuedAt)
+	subtotal, iva, total := doc.Totals()
+	customerID, _ := splitNIT(doc.Customer.ID)
+
+	host := "catalogo-vpfe.dian.gov.co"
+	if c.Environment == EInvoiceEnvironmentTesting {
+		host = "catalogo-vpfe-hab.dian.gov.co"
+	}
+	return strings.Join([]string{
+		"NumFac=" + c.InvoiceNumber(doc.Sequence),
+		"FecFac=" + date,
+		"HorFac=" + hour,
+		"NitFac=" + c.IssuerNIT,
+		"DocAdq=" + customerID,
+		"ValFac=" + ublAmount(subtotal),
+		"ValIva=" + ublAmount(iva),
+		"ValOtroIm=" + ublAmount(0),
+		"ValTolFac=" + ublAmount(total),
+		"CUFE=" + cufe,
+		"QRCode=https://" + host + "/document/searchqr?documentkey=" + cufe,
+	}, "\n")
+}
+
+// UBL 2.1 elements of the DIAN invoice. encoding/xml writes the prefixes of the tags as they
+// are, the namespaces are declared on the root element.
+
+type ublText struct {
+	Value            string `xml:",chardata"`
+	SchemeID         string `xml:"schemeID,attr,omitempty"`
+	SchemeName       string `xml:"schemeName,attr,omitempty"`
+	SchemeAgencyID   string `xml:"schemeAgencyID,attr,omitempty"`
+	SchemeAgencyName string `xml:"schemeAgencyName,attr,omitempty"`
+	ListName         string `xml:"listName,attr,omitempty"`
+	UnitCode         string `xml:"unitCode,attr,omitempty"`
+}
+
+type ublMoney struct {
+	Value      string `xml:",chardata"`
+	CurrencyID string `xml:"currencyID,attr"`
+}
+
+func copAmount(value float64) ublMoney {
+	return ublMoney{Value: ublAmount(value), CurrencyID: "COP"}
+}
+
+type ublInvoice struct {
+	XMLName            xml.Name         `xml:"Invoice"`
+	Xmlns              string           `xml:"xmlns,attr"`
+	XmlnsCac           string           `xml:"xmlns:cac,attr"`
+	XmlnsCbc           string           `xml:"xmlns:cbc,attr"`
+	XmlnsExt           string           `xml:"xmlns:ext,attr"`
+	XmlnsSts           string           `xml:"xmlns:sts,attr"`
+	XmlnsDs            string           `xml:"xmlns:ds,attr"`
+	Extensions         []ublExtension   `xml:"ext:UBLExtensions>ext:UBLExtension"`
+	UBLVersionID       string           `xml:"cbc:UBLVersionID"`
+	CustomizationID    string           `xml:"cbc:CustomizationID"`
+	ProfileID          string           `xml:"cbc:ProfileID"`
+	ProfileExecutionID string           `xml:"cbc:ProfileExecutionID"`
+	ID                 string           `xml:"cbc:ID"`
+	UUID               ublText          `xml:"cbc:UUID"`
+	IssueDate          string           `xml:"cbc:IssueDate"`
+	IssueTime          string           `xml:"cbc:IssueTime"`
+	DueDate            string           `xml:"cbc:DueDate,omitempty"`
+	InvoiceTypeCode    string           `xml:"cbc:InvoiceTypeCode"`
+	Note               string           `xml:"cbc:Note,omitempty"`
+	DocumentCurrency   string           `xml:"cbc:DocumentCurrencyCode"`
+	LineCountNumeric   int              `xml:"cbc:LineCountNumeric"`
+	InvoicePeriod      *ublPeriod       `xml:"cac:InvoicePeriod,omitempty"`
+	Supplier           ublParty         `xml:"cac:AccountingSupplierParty"`
+	Customer           ublParty         `xml:"cac:AccountingCustomerParty"`
+	PaymentMeans       ublPaymentMeans  `xml:"cac:PaymentMeans"`
+	TaxTotals          []ublTaxTotal    `xml:"cac:TaxTotal"`
+	MonetaryTotal      ublMonetaryTotal `xml:"cac:LegalMonetaryTotal"`
+	Lines              []ublInvoiceLine `xml:"cac:InvoiceLine"`
+}
+
+type ublExtension struct {
+	Content ublExtensionContent `xml:"ext:ExtensionContent"`
+}
+
+// ublExtensionContent holds the DIAN extensions in the first extension. The second one is left
+// empty for the XAdES signature the provider adds.
+type ublExtensionContent struct {
+	DianExtensions *ublDianExtensions `xml:"sts:DianExtensions,omitempty"`
+}
+
+type ublDianExtensions struct {
+	Control struct {
+		Authorization string    `xml:"sts:InvoiceAuthorization"`
+		Period        ublPeriod `xml:"sts:AuthorizationPeriod"`
+		Authorized    struct {
+			Prefix string `xml:"sts:Prefix,omitempty"`
+			From   int64  `xml:"sts:From"`
+			To     int64  `xml:"sts:To"`
+		} `xml:"sts:AuthorizedInvoices"`
+	} `xml:"sts:InvoiceControl"`
+	Source struct {
+		Country ublText `xml:"cbc:IdentificationCode"`
+	} `xml:"sts:InvoiceSource"`
+	SoftwareProvider struct {
+		ProviderID ublText `xml:"sts:ProviderID"`
+		SoftwareID ublText `xml:"sts:SoftwareID"`
+	} `xml:"sts:SoftwareProvider"`
+	SoftwareSecurityCode  ublText `xml:"sts:SoftwareSecurityCode"`
+	AuthorizationProvider struct {
+		ID ublText `xml:"sts:AuthorizationProviderID"`
+	} `xml:"sts:AuthorizationProvider"`
+	QRCode string `xml:"sts:QRCode"`
+}
+
+type ublPeriod struct {
+	StartDate string `xml:"cbc:StartDate"`
+	EndDate   string `xml:"cbc:EndDate"`
+}
+
+type ublParty struct {
+	AdditionalAccountID string `xml:"cbc:AdditionalAccountID"` // 1 persona jurídica, 2 persona natural
+	Party               struct {
+		Name        string            `xml:"cac:PartyName>cbc:Name"`
+		Address     *ublAddress       `xml:"cac:PhysicalLocation>cac:Address,omitempty"`
+		TaxScheme   ublPartyTaxScheme `xml:"cac:PartyTaxScheme"`
+		LegalEntity struct {
+			RegistrationName string  `xml:"cbc:RegistrationName"`
+			CompanyID        ublText `xml:"cbc:CompanyID"`
+		} `xml:"cac:PartyLegalEntity"`
+		Contact *ublContact `xml:"cac:Contact,omitempty"`
+	} `xml:"cac:Party"`
+}
+
+type ublContact struct {
+	Email string `xml:"cbc:ElectronicMail"`
+}
+
+type ublAddress struct {
+	CityName string `xml:"cbc:CityName,omitempty"`
+	Line     string `xml:"cac:AddressLine>cbc:Line"`
+	Country  string `xml:"cac:Country>cbc:IdentificationCode"`
+}
+
+type ublPartyTaxScheme struct {
+	RegistrationName string    `xml:"cbc:RegistrationName"`
+	CompanyID        ublText   `xml:"cbc:CompanyID"`
+	TaxLevelCode     string    `xml:"cbc:TaxLevelCode"`
+	TaxScheme        ublScheme `xml:"cac:TaxScheme"`
+}
+
+type ublScheme struct {
+	ID   string `xml:"cbc:ID"`
+	Name string `xml:"cbc:Name"`
+}
+
+type ublPaymentMeans struct {
+	ID      string `xml:"cbc:ID"`               // 1 contado, 2 crédito
+	Code    string `xml:"cbc:PaymentMeansCode"` // 10 efectivo, 31 transferencia...
+	DueDate string `xml:"cbc:PaymentDueDate,omitempty"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublMoney         `xml:"cbc:TaxAmount"`
+	Subtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublMoney `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublMoney `xml:"cbc:TaxAmount"`
+	Category      struct {
+		Percent   string    `xml:"cbc:Percent"`
+		TaxScheme ublScheme `xml:"cac:TaxScheme"`
+	} `xml:"cac:TaxCategory"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount ublMoney `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount  ublMoney `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount  ublMoney `xml:"cbc:TaxInclusiveAmount"`
+	PayableAmount       ublMoney `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID                  int          `xml:"cbc:ID"`
+	InvoicedQuantity    ublText      `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount ublMoney     `xml:"cbc:LineExtensionAmount"`
+	TaxTotal            *ublTaxTotal `xml:"cac:TaxTotal,omitempty"`
+	Item                struct {
+		Description string  `xml:"cbc:Description"`
+		Code        ublText `xml:"cac:StandardItemIdentification>cbc:ID"`
+	} `xml:"cac:Item"`
+	Price struct {
+		Amount       ublMoney `xml:"cbc:PriceAmount"`
+		BaseQuantity ublText  `xml:"cbc:BaseQuantity"`
+	} `xml:"cac:Price"`
+}
+
+var ublIVAScheme = ublScheme{ID: "01", Name: "IVA"}
+
+// ublCompanyID returns the identification of a party with its DIAN document type: NIT (31) with
+// its check digit, or cédula de ciudadanía (13)
+func ublCompanyID(id string) ublText {
+	if strings.Contains(id, "-") {
+		number, dv := splitNIT(id)
+		return ublText{Value: number, SchemeID: dv, SchemeName: "31", SchemeAgencyID: "195", SchemeAgencyName: "CO, DIAN (Dirección de Impuestos y Aduanas Nacionales)"}
+	}
+	return ublText{Value: onlyDigits(id), SchemeName: "13", SchemeAgencyID: "195", SchemeAgencyName: "CO, DIAN (Dirección de Impuestos y Aduanas Nacionales)"}
+}
+
+// ublTaxes groups the IVA of lines by rate
+func ublTaxes(lines []EInvoiceLine) ublTaxTotal {
+	var total ublTaxTotal
+	var amount float64
+	byPercent := map[float64]*[2]float64{}
+	var percents []float64
+	for _, line := range lines {
+		if line.IVAPercent <= 0 {
+			continue
+		}
+		if _, ok := byPercent[line.IVAPercent]; !ok {
+			byPercent[line.IVAPercent] = &[2]float64{}
+			percents = append(percents, line.IVAPercent)
+		}
+		byPercent[line.IVAPercent][0] += line.Amount
+		byPercent[line.IVAPercent][1] += line.IVA
+		amount += line.IVA
+	}
+
+	total.TaxAmount = copAmount(amount)
+	for _, percent := range percents {
+		subtotal := ublTaxSubtotal{
+			TaxableAmount: copAmount(byPercent[percent][0]),
+			TaxAmount:     copAmount(byPercent[percent][1]),
+		}
+		subtotal.Category.Percent = ublAmount(percent)
+		subtotal.Category.TaxScheme = ublIVAScheme
+		total.Subtotals = append(total.Subtotals, subtotal)
+	}
+	return total
+}
+
+// BuildUBLInvoice returns the UBL 2.1 XML of a DIAN electronic sales invoice and its CUFE. The
+// XML is unsigned, the authorized provider signs it before sending it to the DIAN.
+func (c *EInvoiceConfig) BuildUBLInvoice(doc EInvoiceDocument) ([]byte, string, error) {
+	if len(doc.Lines) == 0 {
+		return nil, "", errors.New("invoice without lines")
+	}
+	if doc.Sequence < c.RangeFrom || doc.Sequence > c.RangeTo {
+		return nil, "", fmt.Errorf("invoice number %d is outside the authorized range %d-%d", doc.Sequence, c.RangeFrom, c.RangeTo)
+	}
+	if onlyDigits(doc.Customer.ID) == "" {
+		return nil, "", errors.New("the customer has no identification number")
+	}
+
+	number := c.InvoiceNumber(doc.Sequence)
+	cufe := c.CUFE(doc)
+	date, hour := ublIssueTime(doc.IssuedAt)
+	subtotal, _, total := doc.Totals()
+
+	dian := &ublDianExtensions{}
+	dian.Control.Authorization = c.Resolution
+	dian.Control.Period = ublPeriod{StartDate: c.ResolutionStart.Format("2006-01-02"), EndDate: c.ResolutionEnd.Format("2006-01-02")}
+	dian.Control.Authorized.Prefix = c.Prefix
+	dian.Control.Authorized.From = c.RangeFrom
+	dian.Control.Authorized.To = c.RangeTo
+	dian.Source.Country = ublText{Value: "CO", ListName: "CountryIdentificationCode-2.1"}
+	dianAgency := "CO, DIAN (Dirección de Impuestos y Aduanas Nacionales)"
+	dian.SoftwareProvider.ProviderID = ublText{Value: c.SoftwareProvider, SchemeID: nitCheckDigit(c.SoftwareProvider), SchemeName: "31", SchemeAgencyID: "195", SchemeAgencyName: dianAgency}
+	dian.SoftwareProvider.SoftwareID = ublText{Value: c.SoftwareID, SchemeAgencyID: "195", SchemeAgencyName: dianAgency}
+	dian.SoftwareSecurityCode = ublText{Value: c.softwareSecurityCode(number), SchemeAgencyID: "195", SchemeAgencyName: dianAgency}
+	dian.AuthorizationProvider.ID = ublText{Value: "800197268", SchemeID: "4", SchemeName: "31", SchemeAgencyID: "195", SchemeAgencyName: dianAgency}
+	dian.QRCode = c.qrCode(doc, cufe)
+
+	invoice := ublInvoice{
+		Xmlns:              "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2",
+		XmlnsCac:           "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		XmlnsCbc:           "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		XmlnsExt:           "urn:oasis:names:specification:ubl:schema:xsd:CommonExtensionComponents-2",
+		XmlnsSts:           "dian:gov:co:facturaelectronica:Structures-2-1",
+		XmlnsDs:            "http://www.w3.org/2000/09/xmldsig#",
+		Extensions:         []ublExtension{{Content: ublExtensionContent{DianExtensions: dian}}, {}},
+		UBLVersionID:       "UBL 2.1",
+		CustomizationID:    "10", // Operación estándar
+		ProfileID:          "DIAN 2.1: Factura Electrónica de Venta",
+		ProfileExecutionID: c.Environment,
+		ID:                 number,
+		UUID:               ublText{Value: cufe, SchemeID: c.Environment, SchemeName: "CUFE-SHA384"},
+		IssueDate:          date,
+		IssueTime:          hour,
+		InvoiceTypeCode:    "01", // Factura de venta
+		Note:               doc.Note,
+		DocumentCurrency:   "COP",
+		LineCountNumeric:   len(doc.Lines),
+		PaymentMeans:       ublPaymentMeans{ID: "1", Code: "31"},
+		TaxTotals:          []ublTaxTotal{ublTaxes(doc.Lines)},
+		MonetaryTotal: ublMonetaryTotal{
+			LineExtensionAmount: copAmount(subtotal),
+			TaxExclusiveAmount:  copAmount(subtotal),
+			TaxInclusiveAmount:  copAmount(total),
+			PayableAmount:       copAmount(total),
+		},
+	}
+	if !doc.DueDate.IsZero() {
+		invoice.DueDate = doc.DueDate.Format("2006-01-02")
+		invoice.PaymentMeans.DueDate = invoice.DueDate
+	}
+	if !doc.PeriodStart.IsZero() && !doc.PeriodEnd.IsZero() {
+		invoice.InvoicePeriod = &ublPeriod{StartDate: doc.PeriodStart.Format("2006-01-02"), EndDate: doc.PeriodEnd.Format("2006-01-02")}
+	}
+
+	invoice.Supplier.AdditionalAccountID = c.IssuerPersonType
+	issuerID := ublCompanyID(c.IssuerNIT + "-" + c.IssuerDV)
+	invoice.Supplier.Party.Name = c.IssuerName
+	if c.IssuerAddress != "" {
+		invoice.Supplier.Party.Address = &ublAddress{CityName: c.IssuerCity, Line: c.IssuerAddress, Country: "CO"}
+	}
+	invoice.Supplier.Party.TaxScheme = ublPartyTaxScheme{RegistrationName: c.IssuerName, CompanyID: issuerID, TaxLevelCode: c.IssuerTaxLevel, TaxScheme: ublIVAScheme}
+	invoice.Supplier.Party.LegalEntity.RegistrationName = c.IssuerName
+	invoice.Supplier.Party.LegalEntity.CompanyID = issuerID
+	if c.IssuerEmail != "" {
+		invoice.Supplier.Party.Contact = &ublContact{Email: c.IssuerEmail}
+	}
+
+	customerID := ublCompanyID(doc.Customer.ID)
+	invoice.Customer.AdditionalAccountID = "2"
+	if customerID.SchemeName == "31" {
+		invoice.Customer.AdditionalAccountID = "1"
+	}
+	invoice.Customer.Party.Name = doc.Customer.Name
+	if doc.Customer.Address != "" {
+		invoice.Customer.Party.Address = &ublAddress{CityName: doc.Customer.City, Line: doc.Customer.Address, Country: "CO"}
+	}
+	invoice.Customer.Party.TaxScheme = ublPartyTaxScheme{RegistrationName: doc.Customer.Name, CompanyID: customerID, TaxLevelCode: "R-99-PN", TaxScheme: ublScheme{ID: "ZZ", Name: "No aplica"}}
+	invoice.Customer.Party.LegalEntity.RegistrationName = doc.Customer.Name
+	invoice.Customer.Party.LegalEntity.CompanyID = customerID
+	if doc.Customer.Email != "" {
+		invoice.Customer.Party.Contact = &ublContact{Email: doc.Customer.Email}
+	}
+
+	for i, line := range doc.Lines {
+		invoiceLine := ublInvoiceLine{
+			ID:                  i + 1,
+			InvoicedQuantity:    ublText{Value: "1", UnitCode: "94"}, // Unidad
+			LineExtensionAmount: copAmount(line.Amount),
+		}
+		if line.IVAPercent > 0 {
+			lineTax := ublTaxes([]EInvoiceLine{line})
+			invoiceLine.TaxTotal = &lineTax
+		}
+		invoiceLine.Item.Description = line.Description
+		invoiceLine.Item.Code = ublText{Value: line.Code, SchemeID: "999"} // Estándar de adopción del contribuyente
+		invoiceLine.Price.Amount = copAmount(line.Amount)
+		invoiceLine.Price.BaseQuantity = ublText{Value: "1", UnitCode: "94"}
+		invoice.Lines = append(invoice.Lines, invoiceLine)
+	}
+
+	body, err := xml.MarshalIndent(invoice, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding UBL invoice: %w", err)
+	}
+	return append([]byte(xml.Header), body...), cufe, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultOutboxSchedule is how often due emails are sent when EMAIL_OUTBOX_SCHEDULE is not set
+	defaultOutboxSchedule = "@every 5m"
+	// outboxBatchSize is the maximum number of emails sent on each run
+	outboxBatchSize = 50
+	// outboxMaxAttempts is the number of deliveries tried before an email is marked as failed
+	outboxMaxAttempts = 3
+	// outboxRetryDelay is the wait before retrying a failed delivery
+	outboxRetryDelay = 15 * time.Minute
+)
+
+// EmailOutbox queues emails to be delivered at a given time. A cron job sends the due
+// emails in batches, retrying failed deliveries.
+type EmailOutbox struct {
+	repo     storage.EmailOutboxStore
+	schedule string
+	mu       sync.Mutex // Prevents overlapping runs from sending the same email twice
+}
+
+// NewEmailOutbox creates an EmailOutbox sending the due emails on schedule (EMAIL_OUTBOX_SCHEDULE)
+func NewEmailOutbox(repo storage.EmailOutboxStore, schedule string) *EmailOutbox {
+	return &EmailOutbox{
+		repo:     repo,
+		schedule: schedule,
+	}
+}
+
+// Enqueue queues an email. Emails with a recipient person get a tracking pixel so the hours
+// the person opens email can be learned.
+func (o *EmailOutbox) Enqueue(ctx context.Context, email model.OutboxEmail) (*model.OutboxEmail, error) {
+	email.ID = uuid.New()
+	email.Status = model.OutboxStatusPending
+	if email.SendAt.IsZero() {
+		email.SendAt = time.Now()
+	}
+
+	if baseURL := GetAPIBaseURL(); baseURL != "" && email.PersonID != nil {
+		pixel := fmt.Sprintf(`<img src="%s/api/public/email/open/%s.gif" width="1" height="1" alt="" style="display:none">`, baseURL, email.ID)
+		if strings.Contains(email.HTMLBody, "</body>") {
+			email.HTMLBody = strings.Replace(email.HTMLBody, "</body>", pixel+"</body>", 1)
+		} else {
+			email.HTMLBody += pixel
+		}
+	}
+
+	return o.repo.Create(ctx, email)
+}
+
+// ProcessDue sends the emails whose send time has been reached and returns how many were sent
+func (o *EmailOutbox) ProcessDue(ctx context.Context) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	emails, err := o.repo.GetDue(ctx, now, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		attempts := email.Attempts + 1
+		if err := sendOutboxEmail(email); err != nil {
+			status := model.OutboxStatusPending
+			if attempts >= outboxMaxAttempts {
+				status = model.OutboxStatusFailed
+			}
+			logging.FromContext(ctx).Error("email failed", "component", "outbox", "email_id", email.ID, "to_email", email.ToEmail, "attempts", attempts, "outbox_max_attempts", outboxMaxAttempts, "error", err)
+			if markErr := o.repo.MarkFailed(ctx, email.ID, status, attempts, err.Error(), now.Add(outboxRetryDelay)); markErr != nil {
+				logging.FromContext(ctx).Warn("could not record the failure of email", "component", "outbox", "email_id", email.ID, "error", markErr)
+			}
+			continue
+		}
+
+		if err := o.repo.MarkSent(ctx, email.ID, attempts, time.Now()); err != nil {
+			logging.FromContext(ctx).Warn("email sent but could not be marked as sent", "component", "outbox", "email_id", email.ID, "error", err)
+		}
+		sent++
+	}
+
+	if len(emails) > 0 {
+		logging.FromContext(ctx).Info("sent due emails", "component", "outbox", "sent", sent, "emails_count", len(emails))
+	}
+	return sent, nil
+}
+
+// sendOutboxEmail delivers a queued email, loading its attachments from the file storage
+func sendOutboxEmail(email model.OutboxEmail) error {
+	if len(email.AttachmentPaths) == 0 {
+		return SendProtonMailEmail(email.ToEmail, email.Subject, email.HTMLBody)
+	}
+
+	storageService := GetSupabaseStorageService()
+	if storageService == nil {
+		return fmt.Errorf("file storage is not available to load the attachments")
+	}
+	attachments := make([]EmailAttachment, 0, len(email.AttachmentPaths))
+	for _, attachmentPath := range email.AttachmentPaths {
+		data, err := storageService.DownloadFile(attachmentPath)
+		if err != nil {
+			return fmt.Errorf("failed to load attachment %s: %w", attachmentPath, err)
+		}
+		attachments = append(attachments, EmailAttachment{Name: path.Base(attachmentPath), Data: data})
+	}
+	return SendEmailWithAttachments(email.ToEmail, email.Subject, email.HTMLBody, attachments)
+}
+
+// RecordOpen records that an email was opened
+func (o *EmailOutbox) RecordOpen(ctx context.Context, id uuid.UUID) error {
+	return o.repo.MarkOpened(ctx, id, time.Now())
+}
+
+// Start registers the cron job sending due emails (EMAIL_OUTBOX_SCHEDULE, every 5 minutes by default)
+func (o *EmailOutbox) Start() error {
+	schedule := o.schedule
+	if schedule == "" {
+		schedule = defaultOutboxSchedule
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("email_outbox") {
+			return
+		}
+		start := time.Now()
+		_, err := o.ProcessDue(context.Background())
+		metrics.JobRun("email_outbox", start, err)
+		if err != nil {
+			slog.Error("error processing due emails", "component", "outbox", "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid EMAIL_OUTBOX_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("email outbox", c)
+
+	slog.Info("email outbox scheduler started", "component", "outbox", "schedule", schedule)
+	return nil
+}
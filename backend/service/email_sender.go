@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+)
+
+// emailSendTimeout bounds a single send or health check against the email provider
+const emailSendTimeout = 30 * time.Second
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	Name        string
+	ContentType string // Guessed from the file name when empty
+	Data        []byte
+}
+
+// EmailMessage is an HTML email ready to be delivered
+type EmailMessage struct {
+	To          []string
+	Subject     string
+	HTMLBody    string
+	Attachments []EmailAttachment
+}
+
+// EmailSender delivers emails through a provider
+type EmailSender interface {
+	// Name returns the driver name used in EMAIL_DRIVER
+	Name() string
+	// Send delivers the message to all its recipients
+	Send(ctx context.Context, msg EmailMessage) error
+	// HealthCheck verifies the credentials and that the provider is reachable, without
+	// sending anything
+	HealthCheck(ctx context.Context) error
+}
+
+// emailSender is the driver used by the Send* helpers. SMTP with DefaultProtonMailConfig until
+// InitializeEmail selects another one.
+var emailSender EmailSender = &SMTPEmailSender{}
+
+// emailConfig holds the email settings InitializeEmail was called with
+var emailConfig config.EmailConfig
+
+// InitializeEmail sets up the driver of the email settings (EMAIL_DRIVER: smtp by default,
+// resend, ses or mailgun). Sandboxed environments write emails to disk, so a driver that is
+// not configured is only reported there; elsewhere its error is returned.
+func InitializeEmail(cfg config.EmailConfig) error {
+	emailConfig = cfg
+	DefaultProtonMailConfig.FromName = cfg.FromName
+
+	if cfg.Driver == config.EmailDriverSMTP || cfg.Driver == "" {
+		if cfg.SMTP.Host == "" || cfg.SMTP.Username == "" || cfg.SMTP.Password == "" || cfg.SMTP.Port == 0 {
+			slog.Info("configuración SMTP incompleta, los emails se guardan en el sandbox", "sandbox_dir", cfg.SandboxDir)
+			return nil
+		}
+		DefaultProtonMailConfig = ProtonMailConfig{
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			FromName: cfg.FromName,
+		}
+		slog.Info("email configuration loaded: @", "username", cfg.SMTP.Username, "host", cfg.SMTP.Host, "port", cfg.SMTP.Port)
+		return nil
+	}
+
+	sender, err := NewEmailSender(cfg.Driver)
+	if err != nil {
+		if cfg.SandboxDir != "" {
+			slog.Info("email driver not available, emails are written to the sandbox", "driver", cfg.Driver, "error", err, "sandbox_dir", cfg.SandboxDir)
+			return nil
+		}
+		return fmt.Errorf("email driver %s: %w", cfg.Driver, err)
+	}
+	emailSender = sender
+	slog.Info("email driver loaded", "driver", cfg.Driver)
+	return nil
+}
+
+// GetEmailSender returns the driver emails are sent with
+func GetEmailSender() EmailSender {
+	return emailSender
+}
+
+// EmailSandboxDir returns the directory emails are written to instead of being sent
+// (EMAIL_SANDBOX_DIR), "" when emails are sent with the email driver
+func EmailSandboxDir() string {
+	return emailConfig.SandboxDir
+}
+
+// NewEmailSender creates the driver with the given name from the email settings
+func NewEmailSender(name string) (EmailSender, error) {
+	switch strings.ToLower(name) {
+	case config.EmailDriverSMTP:
+		return &SMTPEmailSender{}, nil
+	case config.EmailDriverResend:
+		return NewResendEmailSender(emailConfig.Resend, emailConfig.From)
+	case config.EmailDriverSES:
+		return NewSESEmailSender(emailConfig.SES, emailConfig.From)
+	case config.EmailDriverMailgun:
+		return NewMailgunEmailSender(emailConfig.Mailgun, emailConfig.From)
+	default:
+		return nil, fmt.Errorf("unknown email driver %q", name)
+	}
+}
+
+// emailFromAddress returns the From header for the HTTP drivers: EMAIL_FROM with
+// EMAIL_FROM_NAME, falling back to the SMTP user
+func emailFromAddress() string {
+	address := emailConfig.From
+	if address == "" {
+		address = DefaultProtonMailConfig.Username
+	}
+	return (&mail.Address{Name: DefaultProtonMailConfig.FromName, Address: address}).String()
+}
+
+// deliverEmail sends the message with the configured driver, or writes it to the email
+// sandbox in environments that have one
+func deliverEmail(msg EmailMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+	defer cancel()
+	return deliverEmailWith(ctx, GetEmailSender(), msg)
+}
+
+func deliverEmailWith(ctx context.Context, sender EmailSender, msg EmailMessage) error {
+	to := strings.Join(msg.To, ", ")
+	if dir := EmailSandboxDir(); dir != "" {
+		message, err := buildMIMEMessage(emailFromAddress(), msg)
+		if err != nil {
+			return err
+		}
+		return writeSandboxEmail(dir, to, message)
+	}
+
+	err := sender.Send(ctx, msg)
+	metrics.EmailSent(sender.Name(), err)
+	if err != nil {
+		logging.FromContext(ctx).Error("email error", "to", to, "name", sender.Name(), "error", err)
+		return err
+	}
+
+	logging.FromContext(ctx).Info("email sent via", "to", to, "name", sender.Name())
+	return nil
+}
+
+// SendTestEmail sends a short message with the given driver so admins can verify its
+// configuration. The email sandbox still applies.
+func SendTestEmail(ctx context.Context, sender EmailSender, to string) error {
+	return deliverEmailWith(ctx, sender, EmailMessage{
+		To:      []string{to},
+		Subject: "✅ Email de prueba - Rental Manager",
+		HTMLBody: fmt.Sprintf(`<p>Este es un email de prueba enviado con el proveedor <strong>%s</strong>.</p>
+<p>Si lo recibiste, la configuración de email funciona correctamente.</p>
+<p>Enviado: %s</p>`, sender.Name(), time.Now().Format(time.RFC1123)),
+	})
+}
+
+// splitRecipients turns a comma separated recipient list into addresses
+func splitRecipients(to string) []string {
+	var recipients []string
+	for _, recipient := range strings.Split(to, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// attachmentContentType returns the MIME type of an attachment
+func attachmentContentType(attachment EmailAttachment) string {
+	if attachment.ContentType != "" {
+		return attachment.ContentType
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(attachment.Name)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// buildMIMEMessage renders the message as RFC 5322 text, used by SMTP, SES raw emails and the
+// email sandbox
+func buildMIMEMessage(from string, msg EmailMessage) ([]byte, error) {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(msg.Attachments) == 0 {
+		message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&message, msg.HTMLBody); err != nil {
+			return nil, err
+		}
+		return message.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fmt.Fprintf(&message, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build email body: %w", err)
+	}
+	if err := writeQuotedPrintable(htmlPart, msg.HTMLBody); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range msg.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachmentContentType(attachment), map[string]string{"name": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build attachment %s: %w", attachment.Name, err)
+		}
+		// Split base64 data into lines of 76 characters as per RFC
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for i := 0; i < len(encoded); i += 76 {
+			end := min(i+76, len(encoded))
+			io.WriteString(part, encoded[i:end]+"\r\n")
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qp, text); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return nil
+}
+
+// doEmailProviderRequest executes a request against an HTTP email API and returns the body of
+// a successful answer
+func doEmailProviderRequest(client *http.Client, req *http.Request, provider string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, fmt.Errorf("%s returned %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
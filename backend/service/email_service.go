@@ -1,14 +1,14 @@
 package service
 
 import (
-	"bytes"
-	"crypto/tls"
-	"encoding/base64"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"net/smtp"
-	"strings"
+	"html"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
 )
 
 // ProtonMailConfig holds the configuration for ProtonMail SMTP service
@@ -30,257 +30,91 @@ var DefaultProtonMailConfig = ProtonMailConfig{
 	FromName: "Rental Management System",
 }
 
-// SendProtonMailEmail sends an email using ProtonMail SMTP server
+// SendProtonMailEmail sends an email with the configured email driver (EMAIL_DRIVER)
 func SendProtonMailEmail(to, subject, htmlBody string) error {
-	// Use the default ProtonMail configuration
-	return SendProtonMailEmailWithConfig(to, subject, htmlBody, DefaultProtonMailConfig)
+	return deliverEmail(EmailMessage{
+		To:       splitRecipients(to),
+		Subject:  subject,
+		HTMLBody: htmlBody,
+	})
 }
 
-// SendProtonMailEmailWithConfig sends an email using ProtonMail SMTP with custom configuration
+// SendProtonMailEmailWithConfig sends an email through SMTP with a custom configuration,
+// regardless of the configured driver
 func SendProtonMailEmailWithConfig(to, subject, htmlBody string, config ProtonMailConfig) error {
-	// Prepare email headers
-	headers := make(map[string]string)
-	headers["From"] = fmt.Sprintf("%s <%s>", config.FromName, config.Username)
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	// Build the message
-	var message string
-	for key, value := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", key, value)
-	}
-	message += "\r\n" + htmlBody
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
-
-	// Connect to the server, set up TLS
-	serverAddr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-
-	// Initialize TLS config
-	tlsConfig := &tls.Config{
-		ServerName: config.Host,
-	}
-
-	// Connect to the SMTP server
-	client, err := smtp.Dial(serverAddr)
-	if err != nil {
-		log.Printf("❌ [EMAIL DIAL ERROR] %s - Error: %v", to, err)
-		return err
-	}
-	defer client.Close()
-
-	// Start TLS
-	if err = client.StartTLS(tlsConfig); err != nil {
-		log.Printf("❌ [EMAIL TLS ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	// Authenticate
-	if err = client.Auth(auth); err != nil {
-		log.Printf("❌ [EMAIL AUTH ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	// Set the sender and recipient
-	if err = client.Mail(config.Username); err != nil {
-		log.Printf("❌ [EMAIL SENDER ERROR] %s - Error: %v", to, err)
+	msg := EmailMessage{To: splitRecipients(to), Subject: subject, HTMLBody: htmlBody}
+	if err := NewSMTPEmailSender(config).Send(context.Background(), msg); err != nil {
+		slog.Error("email error error", "to", to, "error", err)
 		return err
 	}
-
-	// Add recipients
-	for _, recipient := range strings.Split(to, ",") {
-		recipient = strings.TrimSpace(recipient)
-		if err = client.Rcpt(recipient); err != nil {
-			log.Printf("❌ [EMAIL RECIPIENT ERROR] %s - Error: %v", recipient, err)
-			return err
-		}
-	}
-
-	// Send the email body
-	w, err := client.Data()
-	if err != nil {
-		log.Printf("❌ [EMAIL DATA ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	_, err = w.Write([]byte(message))
-	if err != nil {
-		log.Printf("❌ [EMAIL WRITE ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	err = w.Close()
-	if err != nil {
-		log.Printf("❌ [EMAIL CLOSE ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	// Send the QUIT command and close the connection
-	err = client.Quit()
-	if err != nil {
-		log.Printf("❌ [EMAIL QUIT ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	log.Printf("✅ [EMAIL SENT] %s", to)
+	slog.Info("email sent", "to", to)
 	return nil
 }
 
-// SendEmailWithAttachment sends an email with a file attachment
+// SendEmailWithAttachment sends an email with a file attachment with the configured email driver
 func SendEmailWithAttachment(to, subject, htmlBody, attachmentPath, attachmentName string) error {
-	// Use the default ProtonMail configuration
-	return SendEmailWithAttachmentAndConfig(to, subject, htmlBody, attachmentPath, attachmentName, DefaultProtonMailConfig)
-}
-
-// SendEmailWithAttachmentAndConfig sends an email with a file attachment using custom config
-func SendEmailWithAttachmentAndConfig(to, subject, htmlBody, attachmentPath, attachmentName string, config ProtonMailConfig) error {
-	// Read the attachment file
-	attachmentData, err := ioutil.ReadFile(attachmentPath)
+	msg, err := attachmentEmailMessage(to, subject, htmlBody, attachmentPath, attachmentName)
 	if err != nil {
-		return fmt.Errorf("failed to read attachment file: %w", err)
-	}
-
-	// Create a unique boundary for MIME parts
-	boundary := "==BOUNDARY_FOR_EMAIL_WITH_ATTACHMENT=="
-
-	// Set up headers
-	from := fmt.Sprintf("%s <%s>", config.FromName, config.Username)
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = fmt.Sprintf("multipart/mixed; boundary=%s", boundary)
-
-	// Start building the message
-	var message bytes.Buffer
-	for key, value := range headers {
-		message.WriteString(fmt.Sprintf("%s: %s\r\n", key, value))
-	}
-	message.WriteString("\r\n")
-
-	// Add HTML part
-	message.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
-	message.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
-	message.WriteString(htmlBody)
-	message.WriteString("\r\n\r\n")
-
-	// Add attachment part
-	message.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	message.WriteString(fmt.Sprintf("Content-Type: application/pdf; name=\"%s\"\r\n", attachmentName))
-	message.WriteString("Content-Transfer-Encoding: base64\r\n")
-	message.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=\"%s\"\r\n\r\n", attachmentName))
-
-	// Encode attachment data in base64
-	encodedData := base64.StdEncoding.EncodeToString(attachmentData)
-	// Split base64 data into lines of 76 characters as per RFC
-	for i := 0; i < len(encodedData); i += 76 {
-		end := i + 76
-		if end > len(encodedData) {
-			end = len(encodedData)
-		}
-		message.WriteString(encodedData[i:end] + "\r\n")
-	}
-
-	// Close boundary
-	message.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
-
-	// Connect to the server, set up TLS
-	serverAddr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-
-	// Initialize TLS config
-	tlsConfig := &tls.Config{
-		ServerName: config.Host,
-	}
-
-	// Connect to the SMTP server
-	client, err := smtp.Dial(serverAddr)
-	if err != nil {
-		log.Printf("❌ [EMAIL DIAL ERROR] %s - Error: %v", to, err)
-		return err
-	}
-	defer client.Close()
-
-	// Start TLS
-	if err = client.StartTLS(tlsConfig); err != nil {
-		log.Printf("❌ [EMAIL TLS ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	// Authenticate
-	if err = client.Auth(auth); err != nil {
-		log.Printf("❌ [EMAIL AUTH ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	// Set the sender and recipient
-	if err = client.Mail(config.Username); err != nil {
-		log.Printf("❌ [EMAIL SENDER ERROR] %s - Error: %v", to, err)
 		return err
 	}
+	return deliverEmail(msg)
+}
 
-	// Add recipients
-	for _, recipient := range strings.Split(to, ",") {
-		recipient = strings.TrimSpace(recipient)
-		if err = client.Rcpt(recipient); err != nil {
-			log.Printf("❌ [EMAIL RECIPIENT ERROR] %s - Error: %v", recipient, err)
-			return err
-		}
-	}
+// SendEmailWithAttachments sends an email with in-memory attachments using the configured email driver
+func SendEmailWithAttachments(to, subject, htmlBody string, attachments []EmailAttachment) error {
+	return deliverEmail(EmailMessage{
+		To:          splitRecipients(to),
+		Subject:     subject,
+		HTMLBody:    htmlBody,
+		Attachments: attachments,
+	})
+}
 
-	// Send the email body
-	w, err := client.Data()
+// SendEmailWithAttachmentAndConfig sends an email with a file attachment through SMTP with a
+// custom configuration
+func SendEmailWithAttachmentAndConfig(to, subject, htmlBody, attachmentPath, attachmentName string, config ProtonMailConfig) error {
+	msg, err := attachmentEmailMessage(to, subject, htmlBody, attachmentPath, attachmentName)
 	if err != nil {
-		log.Printf("❌ [EMAIL DATA ERROR] %s - Error: %v", to, err)
 		return err
 	}
-
-	_, err = w.Write(message.Bytes())
-	if err != nil {
-		log.Printf("❌ [EMAIL WRITE ERROR] %s - Error: %v", to, err)
-		return err
-	}
-
-	err = w.Close()
-	if err != nil {
-		log.Printf("❌ [EMAIL CLOSE ERROR] %s - Error: %v", to, err)
+	if err := NewSMTPEmailSender(config).Send(context.Background(), msg); err != nil {
+		slog.Error("email error error", "to", to, "error", err)
 		return err
 	}
+	slog.Info("email with attachment sent", "to", to, "attachment_name", attachmentName)
+	return nil
+}
 
-	// Send the QUIT command and close the connection
-	err = client.Quit()
+// attachmentEmailMessage builds a message attaching the file at attachmentPath
+func attachmentEmailMessage(to, subject, htmlBody, attachmentPath, attachmentName string) (EmailMessage, error) {
+	attachmentData, err := os.ReadFile(attachmentPath)
 	if err != nil {
-		log.Printf("❌ [EMAIL QUIT ERROR] %s - Error: %v", to, err)
-		return err
+		return EmailMessage{}, fmt.Errorf("failed to read attachment file: %w", err)
 	}
-
-	log.Printf("✅ [EMAIL WITH ATTACHMENT SENT] %s - %s", to, attachmentName)
-	return nil
+	return EmailMessage{
+		To:          splitRecipients(to),
+		Subject:     subject,
+		HTMLBody:    htmlBody,
+		Attachments: []EmailAttachment{{Name: attachmentName, Data: attachmentData}},
+	}, nil
 }
 
-// SendGmailEmail sends an email using Gmail SMTP server
+// SendGmailEmail sends an email with the configured email driver
 func SendGmailEmail(to, subject, htmlBody string) error {
 	return SendProtonMailEmail(to, subject, htmlBody)
 }
 
 // SendSimpleEmail is a wrapper for backward compatibility
-// This now uses Gmail SMTP instead of Resend
 func SendSimpleEmail(to, subject, htmlBody string) error {
 	return SendProtonMailEmail(to, subject, htmlBody)
 }
 
-// SendUploadLinkEmail envía un email con enlace de subida de archivos
-func SendUploadLinkEmail(to, name, token string) error {
+// SendUploadLinkEmail envía un email con enlace de subida de archivos.
+// baseURL es el dominio de la organización (vea OrganizationBaseURL)
+func SendUploadLinkEmail(to, name, token, baseURL string) error {
 	subject := "📁 Enlace para Subir Archivos - Rental Manager"
 
-	uploadURL := fmt.Sprintf("http://localhost:5173/file-upload?token=%s", token)
+	uploadURL := fmt.Sprintf("%s/file-upload?token=%s", baseURL, token)
 
 	htmlBody := fmt.Sprintf(`
 <!DOCTYPE html>
@@ -351,3 +185,363 @@ func SendUploadLinkEmail(to, name, token string) error {
 
 	return SendProtonMailEmail(to, subject, htmlBody)
 }
+
+// SendPasswordResetEmail envía el enlace para restablecer una contraseña olvidada
+func SendPasswordResetEmail(to, resetURL string, ttl time.Duration) error {
+	subject := "🔑 Restablece tu contraseña - Rental Manager"
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Restablecer Contraseña</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f8fafc; padding: 30px; border-radius: 0 0 8px 8px; }
+        .button { display: inline-block; background: #16a34a; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; font-weight: bold; margin: 20px 0; }
+        .info { background: #dbeafe; padding: 15px; border-radius: 6px; margin: 20px 0; }
+        .footer { text-align: center; margin-top: 30px; color: #6b7280; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 Restablecer Contraseña</h1>
+            <p>Rental Manager</p>
+        </div>
+
+        <div class="content">
+            <p>Recibimos una solicitud para restablecer la contraseña de tu cuenta.</p>
+
+            <div style="text-align: center;">
+                <a href="%s" class="button">Elegir una nueva contraseña</a>
+            </div>
+
+            <div class="info">
+                <strong>⚠️ Importante:</strong>
+                <ul>
+                    <li>Este enlace expira en %s</li>
+                    <li>Solo se puede usar una vez</li>
+                </ul>
+            </div>
+
+            <p>Saludos,<br>
+            <strong>Equipo de Rental Manager</strong></p>
+        </div>
+
+        <div class="footer">
+            <p>Este es un email automático, por favor no respondas a este mensaje.</p>
+            <p>Si no solicitaste este cambio, puedes ignorar este email: tu contraseña no cambiará.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(resetURL), formatLinkTTL(ttl))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// formatLinkTTL escribe la vigencia de un enlace en minutos, horas o días
+func formatLinkTTL(ttl time.Duration) string {
+	if ttl < time.Hour || ttl%time.Hour != 0 {
+		return fmt.Sprintf("%d minutos", int(ttl.Minutes()))
+	}
+	day := 24 * time.Hour
+	switch {
+	case ttl == time.Hour:
+		return "1 hora"
+	case ttl == day:
+		return "1 día"
+	case ttl%day == 0:
+		return fmt.Sprintf("%d días", int(ttl/day))
+	}
+	return fmt.Sprintf("%d horas", int(ttl.Hours()))
+}
+
+// SendEmailVerificationEmail envía el enlace que verifica el email de una cuenta nueva
+func SendEmailVerificationEmail(to, name, verifyURL string, ttl time.Duration) error {
+	subject := "✉️ Verifica tu email - Rental Manager"
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Verificar Email</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2>Hola %s,</h2>
+        <p>Gracias por registrarte en Rental Manager. Para continuar, confirma que este es tu email:</p>
+        <p style="text-align: center;">
+            <a href="%s" style="display: inline-block; background: #16a34a; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; font-weight: bold;">Verificar mi email</a>
+        </p>
+        <p>El enlace expira en %s. Después de verificarlo, un administrador revisará y activará tu cuenta.</p>
+        <p style="color: #6b7280; font-size: 14px;">Si no creaste esta cuenta, puedes ignorar este email.</p>
+        <p>Saludos,<br><strong>Equipo de Rental Manager</strong></p>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(name), html.EscapeString(verifyURL), formatLinkTTL(ttl))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// SendRegistrationApprovalEmail avisa a un administrador de que una cuenta registrada verificó su
+// email y espera su aprobación
+func SendRegistrationApprovalEmail(to, userEmail, status string) error {
+	subject := "👤 Nueva cuenta pendiente de aprobación"
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Nueva Cuenta</title>
+</head>
+<body style="font-family: Arial, sans-serif; color: #333;">
+    <h2>Nueva cuenta pendiente de aprobación</h2>
+    <p>El usuario <strong>%s</strong> se registró y verificó su email. Su estado actual es <strong>%s</strong>.</p>
+    <p>Revise la cuenta y actívela desde la administración de usuarios:</p>
+    <p><a href="%s">%s</a></p>
+    <p>Sistema de Administración de Propiedades</p>
+</body>
+</html>
+	`, html.EscapeString(userEmail), html.EscapeString(status), html.EscapeString(GetAppBaseURL()+"/admin/users"), html.EscapeString(GetAppBaseURL()+"/admin/users"))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// SendInvitationEmail envía el enlace con el que una persona invitada crea su cuenta
+func SendInvitationEmail(to, name, role, organizationName, propertyAddress, message, acceptURL string, ttl time.Duration) error {
+	subject := "🏠 Tienes una invitación - Rental Manager"
+
+	invitedAs := "arrendatario"
+	if role == model.InvitationRoleManager {
+		invitedAs = "administrador de propiedades"
+	}
+	details := ""
+	if organizationName != "" {
+		details += fmt.Sprintf("<li>Organización: %s</li>", html.EscapeString(organizationName))
+	}
+	if propertyAddress != "" {
+		details += fmt.Sprintf("<li>Propiedad: %s</li>", html.EscapeString(propertyAddress))
+	}
+	if details != "" {
+		details = "<ul>" + details + "</ul>"
+	}
+	note := ""
+	if message != "" {
+		note = fmt.Sprintf(`<p style="background: #f3f4f6; padding: 12px; border-radius: 6px;">%s</p>`, html.EscapeString(message))
+	}
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Invitación</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2>Hola %s,</h2>
+        <p>Has sido invitado a Rental Manager como <strong>%s</strong>.</p>
+        %s
+        %s
+        <p style="text-align: center;">
+            <a href="%s" style="display: inline-block; background: #16a34a; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; font-weight: bold;">Aceptar invitación</a>
+        </p>
+        <p>Al aceptarla elegirás tu contraseña. El enlace expira en %s y solo se puede usar una vez.</p>
+        <p style="color: #6b7280; font-size: 14px;">Si no esperabas esta invitación, puedes ignorar este email.</p>
+        <p>Saludos,<br><strong>Equipo de Rental Manager</strong></p>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(name), invitedAs, details, note, html.EscapeString(acceptURL), formatLinkTTL(ttl))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// SendMaintenanceStatusEmail notifica al arrendatario el cambio de estado de su solicitud de mantenimiento
+func SendMaintenanceStatusEmail(to, name, propertyAddress, description, previousStatus, newStatus string) error {
+	subject := "🔧 Actualización de su Solicitud de Mantenimiento"
+
+	if previousStatus == "" {
+		previousStatus = "-"
+	}
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Actualización de Solicitud de Mantenimiento</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f8fafc; padding: 30px; border-radius: 0 0 8px 8px; }
+        .info { background: #dbeafe; padding: 15px; border-radius: 6px; margin: 20px 0; }
+        .status { font-weight: bold; color: #16a34a; }
+        .footer { text-align: center; margin-top: 30px; color: #6b7280; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔧 Solicitud de Mantenimiento</h1>
+            <p>Rental Manager</p>
+        </div>
+
+        <div class="content">
+            <h2>Hola %s,</h2>
+
+            <p>El estado de su solicitud de mantenimiento ha cambiado.</p>
+
+            <div class="info">
+                <p><strong>🏠 Propiedad:</strong> %s</p>
+                <p><strong>📋 Descripción:</strong> %s</p>
+                <p><strong>Estado anterior:</strong> %s</p>
+                <p><strong>Nuevo estado:</strong> <span class="status">%s</span></p>
+            </div>
+
+            <p>Puede consultar los comentarios y el historial de la solicitud en la plataforma.</p>
+
+            <p>Saludos,<br>
+            <strong>Equipo de Rental Manager</strong></p>
+        </div>
+
+        <div class="footer">
+            <p>Este es un email automático, por favor no respondas a este mensaje.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(name), html.EscapeString(propertyAddress), html.EscapeString(description), previousStatus, newStatus)
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// SendMaintenanceAssignmentEmail envía al proveedor de servicios los detalles de la solicitud de mantenimiento asignada
+func SendMaintenanceAssignmentEmail(to, providerName, propertyAddress, description, requestDate string) error {
+	subject := "🛠️ Nueva Solicitud de Mantenimiento Asignada"
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Solicitud de Mantenimiento Asignada</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f8fafc; padding: 30px; border-radius: 0 0 8px 8px; }
+        .info { background: #dbeafe; padding: 15px; border-radius: 6px; margin: 20px 0; }
+        .footer { text-align: center; margin-top: 30px; color: #6b7280; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🛠️ Solicitud de Mantenimiento</h1>
+            <p>Rental Manager</p>
+        </div>
+
+        <div class="content">
+            <h2>Hola %s,</h2>
+
+            <p>Se le ha asignado una nueva solicitud de mantenimiento.</p>
+
+            <div class="info">
+                <p><strong>🏠 Dirección:</strong> %s</p>
+                <p><strong>📅 Fecha de la solicitud:</strong> %s</p>
+                <p><strong>📋 Descripción:</strong> %s</p>
+            </div>
+
+            <p>Por favor, póngase en contacto con la administración para coordinar la visita.</p>
+
+            <p>Saludos,<br>
+            <strong>Equipo de Rental Manager</strong></p>
+        </div>
+
+        <div class="footer">
+            <p>Este es un email automático, por favor no respondas a este mensaje.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(providerName), html.EscapeString(propertyAddress), requestDate, html.EscapeString(description))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
+
+// SendListingApplicationEmail avisa al administrador de un inmueble publicado que un interesado
+// envió una solicitud para arrendarlo
+func SendListingApplicationEmail(to, managerName, listingTitle string, application model.ListingApplication) error {
+	subject := "🏠 Nueva Solicitud para " + listingTitle
+
+	moveIn := "-"
+	if application.DesiredMoveIn != nil {
+		moveIn = FormatDate(*application.DesiredMoveIn)
+	}
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Nueva Solicitud de Arrendamiento</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f8fafc; padding: 30px; border-radius: 0 0 8px 8px; }
+        .info { background: #dbeafe; padding: 15px; border-radius: 6px; margin: 20px 0; }
+        .footer { text-align: center; margin-top: 30px; color: #6b7280; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🏠 Nueva Solicitud de Arrendamiento</h1>
+            <p>Rental Manager</p>
+        </div>
+
+        <div class="content">
+            <h2>Hola %s,</h2>
+
+            <p>Un interesado envió una solicitud para arrendar <strong>%s</strong>.</p>
+
+            <div class="info">
+                <p><strong>👤 Nombre:</strong> %s</p>
+                <p><strong>📧 Email:</strong> %s</p>
+                <p><strong>📞 Teléfono:</strong> %s</p>
+                <p><strong>💼 Ocupación:</strong> %s</p>
+                <p><strong>💰 Ingresos mensuales:</strong> %s</p>
+                <p><strong>👪 Ocupantes:</strong> %d</p>
+                <p><strong>📅 Fecha deseada de ingreso:</strong> %s</p>
+                <p><strong>💬 Mensaje:</strong> %s</p>
+            </div>
+
+            <p>Puede revisar y dar seguimiento a las solicitudes del inmueble en la plataforma.</p>
+
+            <p>Saludos,<br>
+            <strong>Equipo de Rental Manager</strong></p>
+        </div>
+
+        <div class="footer">
+            <p>Este es un email automático, por favor no respondas a este mensaje.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, html.EscapeString(managerName), html.EscapeString(listingTitle), html.EscapeString(application.FullName),
+		html.EscapeString(application.Email), html.EscapeString(application.Phone), html.EscapeString(application.Occupation),
+		FormatMoney(application.MonthlyIncome), application.Occupants, moveIn, html.EscapeString(application.Message))
+
+	return SendProtonMailEmail(to, subject, htmlBody)
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/i18n"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// Keys of the emails admins can customize
+const (
+	EmailTemplateRentReminder    = "rent_reminder"
+	EmailTemplateRentAnniversary = "rent_anniversary"
+	EmailTemplateRentRenewal     = "rent_renewal"
+	EmailTemplateSigningRequest  = "signing_request"
+	EmailTemplateSigningReminder = "signing_reminder"
+	EmailTemplateSigningExpired  = "signing_expired"
+	EmailTemplateSignedContract  = "signed_contract"
+	EmailTemplateSigningOTP      = "signing_otp"
+)
+
+// emailTemplateCacheTTL is how long the customized templates are kept in memory, so other
+// instances pick up an edit within this time
+const emailTemplateCacheTTL = time.Minute
+
+// Default bodies of the emails, used while no customized version exists. The Spanish bodies
+// are in email_templates/ and their translations in a directory per locale.
+//
+//go:embed email_templates/*.html email_templates/en-US/*.html
+var defaultEmailTemplates embed.FS
+
+// emailSubjectMessages are the catalog IDs of the default subjects, in the i18n catalogs
+var emailSubjectMessages = map[string]string{
+	EmailTemplateRentReminder:    "EmailSubjectRentReminder",
+	EmailTemplateRentAnniversary: "EmailSubjectRentAnniversary",
+	EmailTemplateRentRenewal:     "EmailSubjectRentRenewal",
+	EmailTemplateSigningRequest:  "EmailSubjectSigningRequest",
+	EmailTemplateSigningReminder: "EmailSubjectSigningReminder",
+	EmailTemplateSigningExpired:  "EmailSubjectSigningExpired",
+	EmailTemplateSignedContract:  "EmailSubjectSignedContract",
+	EmailTemplateSigningOTP:      "EmailSubjectSigningOTP",
+}
+
+var (
+	// ErrEmailTemplateNotFound is returned for keys that are not in the catalog
+	ErrEmailTemplateNotFound = errors.New("email template not found")
+	// ErrInvalidEmailTemplate is returned for templates that do not parse or use fields the
+	// email does not provide
+	ErrInvalidEmailTemplate = errors.New("invalid email template")
+)
+
+// RentAnniversaryEmailData is the data of the rental anniversary email
+type RentAnniversaryEmailData struct {
+	ArrendatarioNombre string
+	InmuebleDireccion  string
+	Remitente          string
+}
+
+// RentRenewalEmailData is the data of the contract renewal reminder
+type RentRenewalEmailData struct {
+	ArrendatarioNombre string
+	InmuebleDireccion  string
+	FechaFinal         string
+	MensajeAdicional   string // Optional message written by the admin who triggered the reminders
+	Remitente          string
+}
+
+// SigningEmailData is the data of the signing request, reminder and expiry emails
+type SigningEmailData struct {
+	FirmanteNombre   string
+	FirmanteEmail    string
+	ContratoID       string
+	EnlaceFirma      string
+	FechaVencimiento string
+}
+
+// SignedContractEmailData is the data of the email carrying the signed contract
+type SignedContractEmailData struct {
+	FechaFirma string
+}
+
+// SigningOTPEmailData is the data of the signing verification code email
+type SigningOTPEmailData struct {
+	Codigo          string
+	MinutosVigencia int
+}
+
+// EmailTemplateDefinition describes an email admins can customize. The sample data renders
+// previews and validates edits, so a template can only use the fields the email provides.
+type EmailTemplateDefinition struct {
+	Key            string      `json:"key"`
+	Name           string      `json:"name"`
+	Description    string      `json:"description"`
+	DefaultSubject string      `json:"default_subject"`
+	DefaultBody    string      `json:"default_body"`
+	Placeholders   []string    `json:"placeholders"`
+	Sample         interface{} `json:"sample"`
+}
+
+var emailTemplateDefinitions = []EmailTemplateDefinition{
+	newEmailTemplateDefinition(EmailTemplateRentReminder, "Cuenta de cobro mensual",
+		"Enviada a los arrendatarios el día de pago con el detalle del canon", billingEmailSubject,
+		BillingEmailData{
+			NumeroCuenta:         "CC-2025-000001",
+			FechaEmision:         "5 de enero de 2025",
+			FechaLimitePago:      "7 de enero de 2025",
+			ArrendatarioNombre:   "María Pérez",
+			ArrendatarioNIT:      "1020304050",
+			InmuebleDireccion:    "Calle 10 # 20-30, Apto 501",
+			TipoInmueble:         "Apartamento",
+			FechaInicio:          "5 de enero de 2024",
+			FechaFinal:           "4 de enero de 2026",
+			ValorMensual:         "$1.500.000",
+			Subtotal:             "$1.700.000",
+			IVA:                  "$0",
+			TotalPagar:           "$1.700.000",
+			Conceptos:            []BillingConcept{{Concepto: "Canon", Valor: "$1.500.000", IVA: "$0", Total: "$1.500.000"}, {Concepto: "Administración", Valor: "$200.000", IVA: "$0", Total: "$200.000"}},
+			CondicionesPago:      "Pago antes del 5 de cada mes",
+			Banco:                "Bancolombia",
+			TipoCuenta:           "Ahorros",
+			NumeroCuentaBancaria: "123-456789-01",
+			TitularCuenta:        "Juan Gómez",
+			ArrendadorNombre:     "Juan Gómez",
+			UnpaidMonths:         1,
+			TotalDue:             "$1.700.000 COP",
+		}),
+	newEmailTemplateDefinition(EmailTemplateRentAnniversary, "Aniversario de arrendamiento",
+		"Enviado al arrendatario cuando se cumple un año más de su contrato", "🏡 Aniversario de Arrendamiento",
+		RentAnniversaryEmailData{ArrendatarioNombre: "María Pérez", InmuebleDireccion: "Calle 10 # 20-30, Apto 501", Remitente: "Juan Gómez"}),
+	newEmailTemplateDefinition(EmailTemplateRentRenewal, "Recordatorio de renovación",
+		"Enviado a los arrendatarios cuyo contrato termina en aproximadamente un mes",
+		"Recordatorio: su contrato de arrendamiento de {{.InmuebleDireccion}} está por terminar",
+		RentRenewalEmailData{ArrendatarioNombre: "María Pérez", InmuebleDireccion: "Calle 10 # 20-30, Apto 501", FechaFinal: "4 de enero de 2026", MensajeAdicional: "Tenemos nuevas condiciones para la renovación.", Remitente: "Juan Gómez"}),
+	newEmailTemplateDefinition(EmailTemplateSigningRequest, "Solicitud de firma",
+		"Enviada al firmante con el enlace para revisar y firmar el contrato", "Contrato Listo para Firma",
+		sampleSigningEmailData),
+	newEmailTemplateDefinition(EmailTemplateSigningReminder, "Recordatorio de firma",
+		"Enviado al firmante antes de que venza una solicitud de firma pendiente", "⏰ Recordatorio: tiene un contrato pendiente de firma",
+		sampleSigningEmailData),
+	newEmailTemplateDefinition(EmailTemplateSigningExpired, "Solicitud de firma expirada",
+		"Enviada a quien solicitó la firma cuando la solicitud vence sin firmarse", "La solicitud de firma de {{.FirmanteNombre}} expiró",
+		sampleSigningEmailData),
+	newEmailTemplateDefinition(EmailTemplateSignedContract, "Contrato firmado",
+		"Enviado al firmante con la copia firmada del contrato adjunta", "Contrato Firmado - Copia para sus Registros",
+		SignedContractEmailData{FechaFirma: "5 de enero de 2025"}),
+	newEmailTemplateDefinition(EmailTemplateSigningOTP, "Código de verificación de firma",
+		"Enviado al firmante con el código que confirma su firma", "🔐 Código de verificación para firmar su contrato",
+		SigningOTPEmailData{Codigo: "123456", MinutosVigencia: 10}),
+}
+
+var sampleSigningEmailData = SigningEmailData{
+	FirmanteNombre:   "María Pérez",
+	FirmanteEmail:    "maria@example.com",
+	ContratoID:       "3f2a9c1e-contrato",
+	EnlaceFirma:      "https://app.example.com/sign/3f2a9c1e",
+	FechaVencimiento: "12 de enero de 2025",
+}
+
+// newEmailTemplateDefinition builds a catalog entry with its embedded default body and the
+// placeholders of its data
+func newEmailTemplateDefinition(key, name, description, subject string, sample interface{}) EmailTemplateDefinition {
+	body, err := defaultEmailTemplates.ReadFile("email_templates/" + key + ".html")
+	if err != nil {
+		panic(fmt.Sprintf("missing default email template %s: %v", key, err))
+	}
+
+	var placeholders []string
+	sampleType := reflect.TypeOf(sample)
+	for i := 0; i < sampleType.NumField(); i++ {
+		placeholders = append(placeholders, "{{."+sampleType.Field(i).Name+"}}")
+	}
+
+	return EmailTemplateDefinition{
+		Key:            key,
+		Name:           name,
+		Description:    description,
+		DefaultSubject: subject,
+		DefaultBody:    string(body),
+		Placeholders:   placeholders,
+		Sample:         sample,
+	}
+}
+
+// EmailTemplateDefinitions lists the emails admins can customize
+func EmailTemplateDefinitions() []EmailTemplateDefinition {
+	return emailTemplateDefinitions
+}
+
+// GetEmailTemplateDefinition returns the catalog entry of an email
+func GetEmailTemplateDefinition(key string) (*EmailTemplateDefinition, error) {
+	for i := range emailTemplateDefinitions {
+		if emailTemplateDefinitions[i].Key == key {
+			return &emailTemplateDefinitions[i], nil
+		}
+	}
+	return nil, ErrEmailTemplateNotFound
+}
+
+// EmailTemplateService stores the versions of the emails customized by the admins and renders
+// emails with the latest one, or with the embedded default
+type EmailTemplateService struct {
+	repo storage.EmailTemplateStore
+
+	mu       sync.Mutex
+	cache    map[string]model.EmailTemplate
+	cachedAt time.Time
+}
+
+var emailTemplates *EmailTemplateService
+
+// InitializeEmailTemplates creates the service rendering the emails with the customized
+// templates of the database
+func InitializeEmailTemplates(repoFactory *storage.RepositoryFactory) *EmailTemplateService {
+	emailTemplates = &EmailTemplateService{repo: repoFactory.GetEmailTemplateRepository()}
+	return emailTemplates
+}
+
+// GetEmailTemplates returns the email template service, nil when emails are rendered with the
+// defaults only
+func GetEmailTemplates() *EmailTemplateService {
+	return emailTemplates
+}
+
+// Current returns the customized version of an email that is sent, nil when the default is
+func (s *EmailTemplateService) Current(ctx context.Context, key string) (*model.EmailTemplate, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cache == nil || time.Since(s.cachedAt) > emailTemplateCacheTTL {
+		latest, err := s.repo.GetLatest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.cache = latest
+		s.cachedAt = time.Now()
+	}
+
+	if template, ok := s.cache[key]; ok {
+		return &template, nil
+	}
+	return nil, nil
+}
+
+// Versions returns the customized versions of an email, newest first
+func (s *EmailTemplateService) Versions(ctx context.Context, key string) ([]model.EmailTemplate, error) {
+	if _, err := GetEmailTemplateDefinition(key); err != nil {
+		return nil, err
+	}
+	return s.repo.GetVersions(ctx, key)
+}
+
+// Save validates a template against the data of the email and stores it as its new version
+func (s *EmailTemplateService) Save(ctx context.Context, key, subject, body string, createdBy *uuid.UUID) (*model.EmailTemplate, error) {
+	definition, err := GetEmailTemplateDefinition(key)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(subject) == "" || strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%w: subject and body are required", ErrInvalidEmailTemplate)
+	}
+	if _, _, err := renderEmailTemplate(key, subject, body, definition.Sample); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.GetVersions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[0].Version + 1
+	}
+
+	created, err := s.repo.Create(ctx, model.EmailTemplate{
+		Key:       key,
+		Version:   version,
+		Subject:   subject,
+		Body:      body,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	logging.FromContext(ctx).Info("email template saved as version", "key", key, "version", version)
+	return created, nil
+}
+
+// Reset deletes the customized versions of an email so the default is sent again
+func (s *EmailTemplateService) Reset(ctx context.Context, key string) error {
+	if _, err := GetEmailTemplateDefinition(key); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteByKey(ctx, key); err != nil {
+		return err
+	}
+	s.invalidate()
+	logging.FromContext(ctx).Info("email template reset to the default", "key", key)
+	return nil
+}
+
+// Preview renders an email with its sample data. Empty subject or body use the version that
+// is currently sent, so unsaved edits can be previewed field by field.
+func (s *EmailTemplateService) Preview(ctx context.Context, key, subject, body string) (string, string, error) {
+	definition, err := GetEmailTemplateDefinition(key)
+	if err != nil {
+		return "", "", err
+	}
+	currentSubject, currentBody := definition.DefaultSubject, definition.DefaultBody
+	current, err := s.Current(ctx, key)
+	if err != nil {
+		return "", "", err
+	}
+	if current != nil {
+		currentSubject, currentBody = current.Subject, current.Body
+	}
+	if subject == "" {
+		subject = currentSubject
+	}
+	if body == "" {
+		body = currentBody
+	}
+	return renderEmailTemplate(key, subject, body, definition.Sample)
+}
+
+func (s *EmailTemplateService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+// EmailLocale returns the catalog locale an email is written in: locale when it is set (the
+// preference of the recipient or the locale of the request), else the locale of org, else
+// APP_LOCALE
+func EmailLocale(locale string, org *model.Organization) string {
+	if locale == "" && org != nil {
+		locale = org.Locale
+	}
+	if locale == "" {
+		return i18n.DefaultLocale()
+	}
+	return i18n.Normalize(locale)
+}
+
+// emailFormatter returns the formatter of the dates and amounts of an email written in the
+// locale EmailLocale picks, with the timezone of org
+func emailFormatter(locale string, org *model.Organization) *Formatter {
+	return FormatterFor(org).WithLocale(EmailLocale(locale, org))
+}
+
+// defaultEmailContent returns the embedded subject and body of an email in a locale, the
+// Spanish ones for the locales without a translation
+func defaultEmailContent(definition *EmailTemplateDefinition, locale string) (string, string) {
+	if locale == i18n.Spanish {
+		return definition.DefaultSubject, definition.DefaultBody
+	}
+
+	body, err := defaultEmailTemplates.ReadFile("email_templates/" + locale + "/" + definition.Key + ".html")
+	if err != nil {
+		return definition.DefaultSubject, definition.DefaultBody
+	}
+	subject, ok := i18n.Lookup(locale, emailSubjectMessages[definition.Key], nil)
+	if !ok {
+		subject = definition.DefaultSubject
+	}
+	return subject, string(body)
+}
+
+// renderEmail renders the subject and HTML body of an email with the customized template of
+// the admins. A template that cannot be loaded or rendered is logged and the embedded default
+// is used, so a bad edit never stops an email. The default is written in the locale chosen by
+// EmailLocale; a customized template is sent in every locale. The body carries the branding
+// of org, when it is not nil and has one.
+func renderEmail(key string, org *model.Organization, locale string, data interface{}) (string, string, error) {
+	definition, err := GetEmailTemplateDefinition(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	current, err := GetEmailTemplates().Current(ctx, key)
+	if err != nil {
+		slog.Warn("could not load email template, using the default", "key", key, "error", err)
+	}
+	if current != nil {
+		subject, body, err := renderEmailTemplate(key, current.Subject, current.Body, data)
+		if err == nil {
+			return subject, brandEmail(body, org), nil
+		}
+		slog.Warn("email template version failed to render, using the default", "key", key, "version", current.Version, "error", err)
+	}
+
+	defaultSubject, defaultBody := defaultEmailContent(definition, EmailLocale(locale, org))
+	subject, body, err := renderEmailTemplate(key, defaultSubject, defaultBody, data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, brandEmail(body, org), nil
+}
+
+// renderEmailTemplate executes a subject (plain text) and an HTML body with the email data
+func renderEmailTemplate(key, subject, body string, data interface{}) (string, string, error) {
+	subjectTemplate, err := texttemplate.New(key + "_subject").Option("missingkey=error").Parse(subject)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: subject: %v", ErrInvalidEmailTemplate, err)
+	}
+	var renderedSubject bytes.Buffer
+	if err := subjectTemplate.Execute(&renderedSubject, data); err != nil {
+		return "", "", fmt.Errorf("%w: subject: %v", ErrInvalidEmailTemplate, err)
+	}
+
+	bodyTemplate, err := htmltemplate.New(key).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: body: %v", ErrInvalidEmailTemplate, err)
+	}
+	var renderedBody bytes.Buffer
+	if err := bodyTemplate.Execute(&renderedBody, data); err != nil {
+		return "", "", fmt.Errorf("%w: body: %v", ErrInvalidEmailTemplate, err)
+	}
+
+	// Subjects are a single header line
+	return strings.Join(strings.Fields(renderedSubject.String()), " "), renderedBody.String(), nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DefaultEmailVerificationTTL is how long a verification link is valid by default
+const DefaultEmailVerificationTTL = 48 * time.Hour
+
+// ErrInvalidEmailVerificationToken is returned for verification tokens that are malformed,
+// expired, not issued by this server or issued for an email the user no longer has
+var ErrInvalidEmailVerificationToken = errors.New("invalid or expired email verification token")
+
+// signature signs a token with the email it verifies, so the links sent to a previous address
+// stop working when the email changes
+func (s *EmailVerificationService) signature(userID, expires, email string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("email-verification:" + userID + "." + expires + "." + strings.ToLower(email)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// EmailVerificationService verifies the emails of the self-registered users and tells the
+// admins when an account is ready to be approved
+type EmailVerificationService struct {
+	users  storage.UserStore
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewEmailVerificationService creates an EmailVerificationService signing the links with secret
+// (EMAIL_VERIFICATION_SECRET), valid for ttl (EMAIL_VERIFICATION_TTL,
+// DefaultEmailVerificationTTL when unset)
+func NewEmailVerificationService(users storage.UserStore, secret string, ttl time.Duration) *EmailVerificationService {
+	return &EmailVerificationService{users: users, secret: []byte(secret), ttl: orDefault(ttl, DefaultEmailVerificationTTL)}
+}
+
+// Token returns a verification token of the email of user valid until expiresAt
+func (s *EmailVerificationService) Token(user *model.User, expiresAt time.Time) string {
+	userID := user.ID.String()
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return userID + "." + expires + "." + s.signature(userID, expires, user.Email)
+}
+
+// SendVerification emails user a link to the frontend at baseURL (see OrganizationBaseURL)
+// that verifies their email. The email is sent in the background.
+func (s *EmailVerificationService) SendVerification(user *model.User, name, baseURL string, now time.Time) {
+	link := baseURL + "/verify-email?" + url.Values{"token": {s.Token(user, now.Add(s.ttl))}}.Encode()
+	runInBackground(func() {
+		if err := SendEmailVerificationEmail(user.Email, name, link, s.ttl); err != nil {
+			slog.Error("error sending the verification email", "component", "registration", "email", user.Email, "error", err)
+		}
+	})
+}
+
+// Verify marks the email of the user of a valid token as verified and notifies the admins that
+// the account awaits their approval. Verifying an email twice is not an error.
+func (s *EmailVerificationService) Verify(ctx context.Context, token string, now time.Time) (*model.User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidEmailVerificationToken
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, ErrInvalidEmailVerificationToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return nil, ErrInvalidEmailVerificationToken
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !hmac.Equal([]byte(s.signature(parts[0], parts[1], user.Email)), []byte(parts[2])) {
+		return nil, ErrInvalidEmailVerificationToken
+	}
+	if !user.EmailPendingVerification {
+		return user, nil
+	}
+
+	user.EmailPendingVerification = false
+	updated, err := s.users.Update(ctx, *user)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("email verified", "component", "registration", "email", user.Email)
+
+	admins := s.adminEmails(ctx)
+	runInBackground(func() {
+		for _, admin := range admins {
+			if err := SendRegistrationApprovalEmail(admin, user.Email, user.Status); err != nil {
+				logging.FromContext(ctx).Error("error notifying of the registration", "component", "registration", "admin", admin, "email", user.Email, "error", err)
+			}
+		}
+	})
+	return updated, nil
+}
+
+// adminEmails returns the emails of the active admins
+func (s *EmailVerificationService) adminEmails(ctx context.Context) []string {
+	users, err := s.users.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not get the admins to notify", "component", "registration", "error", err)
+		return nil
+	}
+	var emails []string
+	for _, user := range users {
+		if user.Role == "admin" && user.Status != "disabled" && user.Email != "" {
+			emails = append(emails, user.Email)
+		}
+	}
+	return emails
+}
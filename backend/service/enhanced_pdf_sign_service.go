@@ -10,7 +10,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -117,7 +117,7 @@ func GenerateECDSACertificate(outputDir string) (*ecdsa.PrivateKey, *x509.Certif
 		return nil, nil, fmt.Errorf("failed to encode private key: %w", err)
 	}
 
-	log.Printf("Created ECDSA certificate at %s and private key at %s", certPath, keyPath)
+	slog.Info("created ECDSA certificate and private key", "cert_path", certPath, "key_path", keyPath)
 	return privateKey, cert, nil
 }
 
@@ -137,12 +137,12 @@ func LoadOrGenerateECDSACertificate(certsDir string) (*ecdsa.PrivateKey, *x509.C
 	}
 
 	if !keyExists || !certExists {
-		log.Printf("ECDSA certificate or key not found, generating new ones in: %s", certsDir)
+		slog.Warn("ECDSA certificate or key not found, generating new ones", "certs_dir", certsDir)
 		return GenerateECDSACertificate(certsDir)
 	}
 
 	// Files exist, load them
-	log.Printf("Loading existing ECDSA certificate and key from: %s", certsDir)
+	slog.Debug("loading existing ECDSA certificate and key", "certs_dir", certsDir)
 
 	// Load certificate
 	certPEM, err := ioutil.ReadFile(certPath)
@@ -181,11 +181,12 @@ func LoadOrGenerateECDSACertificate(certsDir string) (*ecdsa.PrivateKey, *x509.C
 		return nil, nil, fmt.Errorf("private key is not an ECDSA key")
 	}
 
-	log.Printf("Successfully loaded ECDSA certificate and key")
+	slog.Info("successfully loaded ECDSA certificate and key")
 	return privateKey, cert, nil
 }
 
-// SignPDFWithECDSA signs a PDF using ECDSA certificate
+// SignPDFWithECDSA signs a PDF with the active signing certificate, ECDSA or RSA depending on
+// its key
 func SignPDFWithECDSA(inputPDFPath, outputPDFPath string, metadata *ECDSASignatureMetadata) error {
 	// Check if input file exists
 	if _, err := os.Stat(inputPDFPath); os.IsNotExist(err) {
@@ -197,15 +198,10 @@ func SignPDFWithECDSA(inputPDFPath, outputPDFPath string, metadata *ECDSASignatu
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Get or generate certificates
-	certsDir := "./certs"
-	if err := os.MkdirAll(certsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create certificates directory: %w", err)
-	}
-
-	privateKey, cert, err := LoadOrGenerateECDSACertificate(certsDir)
+	// Configured certificate, or the self-signed ECDSA one when none is configured
+	signingCert, err := ActiveSigningCertificate()
 	if err != nil {
-		return fmt.Errorf("failed to load or generate certificate: %w", err)
+		return fmt.Errorf("failed to load signing certificate: %w", err)
 	}
 
 	// Open input PDF file
@@ -251,13 +247,6 @@ func SignPDFWithECDSA(inputPDFPath, outputPDFPath string, metadata *ECDSASignatu
 	contactInfo := fmt.Sprintf("SignID: %s | SignedBy: %s | TimeSigned: %s",
 		metadata.SignID, metadata.SignedBy, metadata.TimeSigned)
 
-	// Try to get certificate chains (but proceed even if we can't)
-	certChains, err := cert.Verify(x509.VerifyOptions{})
-	if err != nil {
-		log.Printf("Warning: Certificate verification error: %v", err)
-		certChains = [][]*x509.Certificate{}
-	}
-
 	// Sign the PDF
 	err = sign.Sign(inFile, outFile, reader, size, sign.SignData{
 		Signature: sign.SignDataSignature{
@@ -271,16 +260,16 @@ func SignPDFWithECDSA(inputPDFPath, outputPDFPath string, metadata *ECDSASignatu
 			CertType:   sign.CertificationSignature,
 			DocMDPPerm: sign.AllowFillingExistingFormFieldsAndSignaturesPerms,
 		},
-		Signer:            privateKey,
+		Signer:            signingCert.Signer,
 		DigestAlgorithm:   crypto.SHA256,
-		Certificate:       cert,
-		CertificateChains: certChains,
+		Certificate:       signingCert.Certificate,
+		CertificateChains: SigningCertificateChains(signingCert),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to sign PDF: %w", err)
 	}
 
-	log.Printf("PDF signed successfully with ECDSA certificate. Output: %s", outputPDFPath)
+	slog.Info("PDF signed successfully", "key_algorithm", signingCert.KeyAlgorithm(), "output_pdf_path", outputPDFPath)
 	return nil
 }
 
@@ -296,7 +285,9 @@ func SignExistingPDFWithECDSA(contractData ContractPDF, signerName, signerEmail
 	inputPDFPath := filepath.Join(tempDir, signingID+"_input.pdf")
 	outputPDFPath := filepath.Join(tempDir, signingID+"_signed.pdf")
 
-	// Generate the contract PDF using the proper Colombian template first
+	// Generate the contract PDF using the proper Colombian template first, with the visible
+	// signature stamp so that it is covered by the cryptographic signature
+	contractData.Stamp = NewSignatureStamp(signerName, signerEmail, signingID)
 	pdfData, err := GenerateContractPDF(contractData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate contract PDF: %w", err)
@@ -418,6 +409,6 @@ func createProperPDF(outputPath string, contractID string) error {
 		return fmt.Errorf("failed to write PDF file: %w", err)
 	}
 
-	log.Printf("Created proper PDF at %s for contract ID %s", outputPath, contractID)
+	slog.Info("created proper PDF for contract", "output_path", outputPath, "contract_id", contractID)
 	return nil
 }
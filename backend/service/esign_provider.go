@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// ESignProviderBuiltin signs the contract in-app with the digitorus signer
+const ESignProviderBuiltin = "builtin"
+
+// ESignEventType is the normalized outcome reported by an external provider webhook
+type ESignEventType string
+
+const (
+	ESignEventSigned   ESignEventType = "signed"
+	ESignEventRejected ESignEventType = "rejected"
+	ESignEventOther    ESignEventType = "other" // Intermediate events (viewed, sent, ...) are ignored
+)
+
+// ESignEnvelopeRequest holds what an external provider needs to collect a signature
+type ESignEnvelopeRequest struct {
+	SigningID    string // Our contract_signatures ID, sent as external reference
+	ContractID   string
+	DocumentName string
+	PDFData      []byte
+	SignerName   string
+	SignerEmail  string
+}
+
+// ESignEnvelope is the document created on the provider side
+type ESignEnvelope struct {
+	ExternalID string // Envelope/document ID on the provider
+	SigningURL string // Direct signing link when the provider returns one
+}
+
+// ESignWebhookEvent is a verified webhook callback
+type ESignWebhookEvent struct {
+	ExternalID string
+	Type       ESignEventType
+	RawStatus  string
+}
+
+// ESignProvider routes the signing flow of a contract to an external e-signature service
+type ESignProvider interface {
+	// Name returns the identifier used in requests and webhook URLs
+	Name() string
+	// CreateEnvelope uploads the contract and asks the signer to sign it
+	CreateEnvelope(ctx context.Context, req ESignEnvelopeRequest) (*ESignEnvelope, error)
+	// ParseWebhook authenticates a webhook call and extracts the event
+	ParseWebhook(header http.Header, body []byte) (*ESignWebhookEvent, error)
+	// DownloadSignedDocument fetches the final signed PDF
+	DownloadSignedDocument(ctx context.Context, externalID string) ([]byte, error)
+}
+
+// GetESignProvider returns the external provider with the given name, configured from cfg
+func GetESignProvider(name string, cfg config.SigningConfig) (ESignProvider, error) {
+	switch strings.ToLower(name) {
+	case "zapsign":
+		return NewZapSignProvider(cfg.ZapSign)
+	case "docusign":
+		return NewDocuSignProvider(cfg.DocuSign)
+	default:
+		return nil, fmt.Errorf("unknown e-sign provider %q", name)
+	}
+}
+
+// IsExternalESignProvider reports whether the name refers to an external provider
+func IsExternalESignProvider(name string) bool {
+	return name != "" && !strings.EqualFold(name, ESignProviderBuiltin)
+}
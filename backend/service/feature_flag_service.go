@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// defaultFeatureFlagRefresh is how long the flags saved by admins are cached before they are
+// read again, so a change made on another instance is picked up; FEATURE_FLAG_REFRESH overrides it
+const defaultFeatureFlagRefresh = 30 * time.Second
+
+// Errors of FeatureFlagService
+var (
+	ErrUnknownFeatureFlag = errors.New("feature flag desconocido")
+	ErrFeatureUnavailable = errors.New("la función no está configurada")
+)
+
+// featureFlagDefinition is a known flag with the setting it falls back to
+type featureFlagDefinition struct {
+	key          string
+	setting      func(config.FeatureFlags) *bool // nil when only admins set it
+	defaultValue bool
+	description  string
+}
+
+// featureFlagDefinitions are the flags admins can toggle, in the order they are listed
+var featureFlagDefinitions = []featureFlagDefinition{
+	{key: model.FeatureTelegramBackup, setting: func(f config.FeatureFlags) *bool { return f.Telegram }, description: "Backup en Telegram de los archivos eliminados y alertas de archivos infectados"},
+	{key: model.FeatureEInvoiceAutoSubmit, setting: func(f config.FeatureFlags) *bool { return f.EInvoiceAutoSubmit }, description: "Facturar electrónicamente cada pago de arriendo al registrarlo"},
+	jobFlag("bucket_backup", "Backup periódico del bucket de archivos"),
+	jobFlag("certificate_expiry", "Aviso de vencimiento del certificado de firma"),
+	jobFlag("contract_cession", "Aplicación de las cesiones de contrato programadas"),
+	jobFlag("einvoice_status", "Consulta del estado de las facturas electrónicas pendientes"),
+	jobFlag("email_outbox", "Envío de los emails programados del outbox"),
+	jobFlag("file_trash_purge", "Eliminación de los archivos vencidos de la papelera"),
+	jobFlag("manager_digest", "Resumen semanal y mensual de los administradores"),
+	jobFlag("signing_reminders", "Recordatorios y vencimiento de las solicitudes de firma"),
+	jobFlag("subscription_check", "Suspensión de las suscripciones vencidas"),
+	jobFlag("webhook_delivery", "Reintentos de las entregas de webhooks"),
+}
+
+// jobFlag defines the flag that pauses a scheduled job, enabled unless an admin turns it off
+func jobFlag(job, description string) featureFlagDefinition {
+	return featureFlagDefinition{key: model.FeatureFlagJobPrefix + job, defaultValue: true, description: "Tarea programada: " + description}
+}
+
+// findFeatureFlag returns the definition of a known flag
+func findFeatureFlag(key string) (featureFlagDefinition, bool) {
+	for _, definition := range featureFlagDefinitions {
+		if definition.key == key {
+			return definition, true
+		}
+	}
+	return featureFlagDefinition{}, false
+}
+
+// envValue returns the value of the flag in env, before any admin changed it
+func (d featureFlagDefinition) envValue(env config.FeatureFlags) model.FeatureFlag {
+	flag := model.FeatureFlag{Key: d.key, Enabled: d.defaultValue, Description: d.description, Source: "default"}
+	if d.setting == nil {
+		return flag
+	}
+	if enabled := d.setting(env); enabled != nil {
+		flag.Enabled = *enabled
+		flag.Source = "env"
+	}
+	return flag
+}
+
+// FeatureFlagService resolves the feature flags: the values saved by admins or, when there is
+// none, the environment. The saved values are cached and refreshed in the background of the
+// reads, so toggling a flag takes effect without a restart.
+type FeatureFlagService struct {
+	repo    storage.FeatureFlagStore
+	env     config.FeatureFlags
+	refresh time.Duration
+	// telegram and backups re-create the Telegram bot and the file backups when an admin
+	// toggles the Telegram backups
+	telegram config.TelegramConfig
+	backups  config.FileBackupConfig
+
+	mu        sync.RWMutex
+	overrides map[string]model.FeatureFlag
+	loadedAt  time.Time
+}
+
+var featureFlags *FeatureFlagService
+
+// InitializeFeatureFlags creates the service the flags are read from. When it fails to read the
+// saved flags every flag takes the value of the environment in cfg; before it is called, its
+// default value.
+func InitializeFeatureFlags(repo storage.FeatureFlagStore, cfg *config.Config) *FeatureFlagService {
+	featureFlags = &FeatureFlagService{
+		repo:     repo,
+		env:      cfg.Features,
+		refresh:  orDefault(cfg.Features.Refresh, defaultFeatureFlagRefresh),
+		telegram: cfg.Telegram,
+		backups:  cfg.Files.Backups,
+	}
+	return featureFlags
+}
+
+// GetFeatureFlags returns the feature flag service, nil when it was not initialized
+func GetFeatureFlags() *FeatureFlagService {
+	return featureFlags
+}
+
+// FeatureEnabled reports whether a feature is turned on. Unknown flags are off.
+func FeatureEnabled(key string) bool {
+	definition, ok := findFeatureFlag(key)
+	if !ok {
+		return false
+	}
+	if featureFlags == nil {
+		return definition.envValue(config.FeatureFlags{}).Enabled
+	}
+	return featureFlags.resolve(context.Background(), definition).Enabled
+}
+
+// JobEnabled reports whether a scheduled job runs, the jobs skip their runs while an admin has
+// them turned off
+func JobEnabled(job string) bool {
+	return FeatureEnabled(model.FeatureFlagJobPrefix + job)
+}
+
+// All returns the current value of every flag
+func (s *FeatureFlagService) All(ctx context.Context) []model.FeatureFlag {
+	flags := make([]model.FeatureFlag, 0, len(featureFlagDefinitions))
+	for _, definition := range featureFlagDefinitions {
+		flags = append(flags, s.resolve(ctx, definition))
+	}
+	return flags
+}
+
+// Set turns a feature on or off for every instance. Turning Telegram on connects to the bot
+// when it was not connected at startup, and fails when its credentials are not configured.
+func (s *FeatureFlagService) Set(ctx context.Context, key string, enabled bool, updatedBy uuid.UUID) (*model.FeatureFlag, error) {
+	definition, ok := findFeatureFlag(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFeatureFlag, key)
+	}
+	if key == model.FeatureTelegramBackup && enabled && GetTelegramService() == nil {
+		if err := InitializeTelegramService(s.telegram); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFeatureUnavailable, err)
+		}
+	}
+
+	saved, err := s.repo.Save(ctx, model.FeatureFlag{Key: key, Enabled: enabled, UpdatedBy: &updatedBy})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	s.applied(key)
+
+	saved.Description = definition.description
+	saved.Source = "admin"
+	logging.FromContext(ctx).Info("feature flag set", "key", key, "enabled", enabled, "updated_by", updatedBy)
+	return saved, nil
+}
+
+// Reset removes the value saved for a flag, which goes back to the environment
+func (s *FeatureFlagService) Reset(ctx context.Context, key string) (model.FeatureFlag, error) {
+	definition, ok := findFeatureFlag(key)
+	if !ok {
+		return model.FeatureFlag{}, fmt.Errorf("%w: %s", ErrUnknownFeatureFlag, key)
+	}
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return model.FeatureFlag{}, err
+	}
+	s.invalidate()
+	s.applied(key)
+	return definition.envValue(s.env), nil
+}
+
+// applied updates what depends on a flag after it changed on this instance
+func (s *FeatureFlagService) applied(key string) {
+	if key != model.FeatureTelegramBackup {
+		return
+	}
+	if IsTelegramEnabled() && GetTelegramService() == nil {
+		if err := InitializeTelegramService(s.telegram); err != nil {
+			slog.Warn("telegram habilitado pero no disponible", "error", err)
+		}
+	}
+	// The file backups include Telegram only while the flag is on
+	InitializeFileBackups(s.backups)
+}
+
+// resolve returns the value saved by an admin for a flag, or the one of the environment
+func (s *FeatureFlagService) resolve(ctx context.Context, definition featureFlagDefinition) model.FeatureFlag {
+	if saved, ok := s.loadOverrides(ctx)[definition.key]; ok {
+		saved.Description = definition.description
+		saved.Source = "admin"
+		return saved
+	}
+	return definition.envValue(s.env)
+}
+
+func (s *FeatureFlagService) invalidate() {
+	s.mu.Lock()
+	s.overrides = nil
+	s.mu.Unlock()
+}
+
+// loadOverrides returns the saved flags, reading them again when the cache expired. When the
+// read fails the previous values are kept until the next refresh.
+func (s *FeatureFlagService) loadOverrides(ctx context.Context) map[string]model.FeatureFlag {
+	s.mu.RLock()
+	if s.overrides != nil && time.Since(s.loadedAt) < s.refresh {
+		overrides := s.overrides
+		s.mu.RUnlock()
+		return overrides
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another reader may have refreshed them while this one waited for the lock
+	if s.overrides != nil && time.Since(s.loadedAt) < s.refresh {
+		return s.overrides
+	}
+
+	flags, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("no se pudieron cargar los feature flags", "error", err)
+		if s.overrides == nil {
+			s.overrides = map[string]model.FeatureFlag{}
+		}
+		s.loadedAt = time.Now()
+		return s.overrides
+	}
+
+	overrides := make(map[string]model.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		overrides[flag.Key] = flag
+	}
+	s.overrides = overrides
+	s.loadedAt = time.Now()
+	return overrides
+}
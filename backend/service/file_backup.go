@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+)
+
+// Proveedores de backup que se pueden elegir en FILE_BACKUP_PROVIDERS
+const (
+	BackupProviderTelegram    = "telegram"
+	BackupProviderS3          = "s3"
+	BackupProviderGoogleDrive = "gdrive"
+)
+
+// BackupFile es un archivo que se respalda antes de eliminarlo del almacenamiento
+type BackupFile struct {
+	Data     []byte
+	FileName string
+	Path     string // Ruta original en el bucket
+	UserID   string
+}
+
+// BackupProvider respalda archivos en un destino externo
+type BackupProvider interface {
+	// Name devuelve el nombre del proveedor en FILE_BACKUP_PROVIDERS
+	Name() string
+	// Backup guarda una copia del archivo y devuelve dónde quedó (ID o ruta en el destino)
+	Backup(ctx context.Context, file BackupFile) (string, error)
+	// Restore devuelve el contenido de la copia guardada en location
+	Restore(ctx context.Context, location string) ([]byte, error)
+}
+
+// FileBackupRegistry ejecuta todos los proveedores de backup habilitados
+type FileBackupRegistry struct {
+	providers []BackupProvider
+	required  bool
+}
+
+var fileBackups = &FileBackupRegistry{}
+
+// InitializeFileBackups crea los proveedores de FILE_BACKUP_PROVIDERS (telegram, s3, gdrive,
+// separados por comas). Sin configurar se usa Telegram si TELEGRAM_ENABLED=true, como antes de
+// poder elegir el destino. Un proveedor mal configurado se omite y se registra el error.
+// Con FILE_BACKUP_REQUIRED=true los archivos no se eliminan si algún backup falla.
+func InitializeFileBackups(cfg config.FileBackupConfig) {
+	names := cfg.Providers
+	if names == "" && IsTelegramEnabled() {
+		names = BackupProviderTelegram
+	}
+
+	registry := &FileBackupRegistry{required: cfg.Required}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		provider, err := newBackupProvider(name, cfg)
+		if err != nil {
+			slog.Warn("proveedor de backup no disponible", "name", name, "error", err)
+			continue
+		}
+		registry.providers = append(registry.providers, provider)
+		slog.Info("backup de archivos habilitado", "name", name)
+	}
+
+	if len(registry.providers) == 0 {
+		slog.Info("sin proveedores de backup, los archivos se eliminarán sin respaldo")
+	}
+	fileBackups = registry
+}
+
+// GetFileBackups obtiene los proveedores de backup configurados
+func GetFileBackups() *FileBackupRegistry {
+	return fileBackups
+}
+
+func newBackupProvider(name string, cfg config.FileBackupConfig) (BackupProvider, error) {
+	switch name {
+	case BackupProviderTelegram:
+		if !IsTelegramEnabled() {
+			return nil, fmt.Errorf("deshabilitado por feature flag")
+		}
+		telegram := GetTelegramService()
+		if telegram == nil {
+			return nil, fmt.Errorf("servicio de Telegram no inicializado (TELEGRAM_ENABLED, TELEGRAM_BOT_TOKEN, TELEGRAM_CHAT_ID)")
+		}
+		return &telegramBackupProvider{telegram: telegram}, nil
+	case BackupProviderS3:
+		return NewS3BackupProvider(cfg.S3)
+	case BackupProviderGoogleDrive:
+		return NewGoogleDriveBackupProvider(cfg.GoogleDrive)
+	default:
+		return nil, fmt.Errorf("proveedor desconocido")
+	}
+}
+
+// Enabled indica si hay algún proveedor de backup configurado
+func (r *FileBackupRegistry) Enabled() bool {
+	return len(r.providers) > 0
+}
+
+// Required indica si un archivo solo se puede eliminar cuando todos sus backups se completaron
+func (r *FileBackupRegistry) Required() bool {
+	return r.required
+}
+
+// Providers devuelve los nombres de los proveedores habilitados
+func (r *FileBackupRegistry) Providers() []string {
+	names := make([]string, 0, len(r.providers))
+	for _, provider := range r.providers {
+		names = append(names, provider.Name())
+	}
+	return names
+}
+
+// Run respalda un archivo en todos los proveedores habilitados, aunque alguno falle, y devuelve
+// los errores de los que fallaron
+func (r *FileBackupRegistry) Run(ctx context.Context, file BackupFile) error {
+	_, err := r.backupTo(ctx, file)
+	return err
+}
+
+// backupTo respalda un archivo en todos los proveedores habilitados y devuelve dónde quedó la
+// copia en cada uno, junto con los errores de los que fallaron
+func (r *FileBackupRegistry) backupTo(ctx context.Context, file BackupFile) (map[string]string, error) {
+	locations := make(map[string]string, len(r.providers))
+	var errs []error
+	for _, provider := range r.providers {
+		location, err := provider.Backup(ctx, file)
+		if err != nil {
+			logging.FromContext(ctx).Warn("error respaldando", "path", file.Path, "name", provider.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		locations[provider.Name()] = location
+		logging.FromContext(ctx).Info("archivo respaldado", "path", file.Path, "name", provider.Name(), "location", location)
+	}
+	return locations, errors.Join(errs...)
+}
+
+// telegramBackupProvider respalda archivos enviándolos al chat de Telegram configurado
+type telegramBackupProvider struct {
+	telegram *TelegramService
+}
+
+func (p *telegramBackupProvider) Name() string {
+	return BackupProviderTelegram
+}
+
+// Backup envía el archivo a Telegram y avisa en el chat si se respaldó o si falló
+func (p *telegramBackupProvider) Backup(ctx context.Context, file BackupFile) (string, error) {
+	backup, err := p.telegram.BackupFileToTelegram(file.Data, file.FileName, file.Path, file.UserID)
+	if err != nil {
+		p.telegram.SendBackupError(file.FileName, file.UserID, err.Error())
+		return "", err
+	}
+	p.telegram.SendBackupNotification(file.FileName, file.UserID, backup.FileSize)
+	return backup.FileID, nil
+}
+
+// Restore descarga el archivo enviado a Telegram con su file_id
+func (p *telegramBackupProvider) Restore(ctx context.Context, location string) ([]byte, error) {
+	return p.telegram.GetFileFromTelegram(location)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// Formato de los archivos cifrados: la cabecera (fileEncryptionMagic, el identificador de la
+// clave y el prefijo del nonce) seguida del contenido en segmentos de fileEncryptionSegmentSize
+// cifrados con AES-256-GCM. El nonce de cada segmento es el prefijo, el número de segmento y una
+// marca del último segmento, de modo que no se pueden reordenar ni truncar sin que se detecte.
+// Los archivos se cifran y descifran por segmentos, sin cargarlos completos en memoria.
+const (
+	fileEncryptionMagic       = "RMENC1"
+	fileEncryptionKeyIDSize   = 8
+	fileEncryptionPrefixSize  = 7
+	fileEncryptionSegmentSize = 64 << 10
+	fileEncryptionHeaderSize  = len(fileEncryptionMagic) + fileEncryptionKeyIDSize + fileEncryptionPrefixSize
+)
+
+// ErrFileDecryption indica que un archivo cifrado no se pudo descifrar: falta su clave o fue alterado
+var ErrFileDecryption = errors.New("no se pudo descifrar el archivo")
+
+// fileEncryptionKeys son las claves de cifrado de archivos: la actual, con la que se cifran los
+// archivos nuevos, y las anteriores, que solo se usan para descifrar tras una rotación
+type fileEncryptionKeys struct {
+	currentID string
+	aeads     map[string]cipher.AEAD
+}
+
+// loadFileEncryptionKeys obtiene las claves configuradas. Devuelve nil sin error si el cifrado
+// no está habilitado.
+//
+// La clave actual se toma de FILE_ENCRYPTION_KEY (32 bytes en base64) o, con
+// FILE_ENCRYPTION_KMS=vault, se descifra al iniciar con el motor transit de Vault
+// (VAULT_ADDR, VAULT_TOKEN, FILE_ENCRYPTION_VAULT_KEY y FILE_ENCRYPTION_WRAPPED_KEY). Las claves
+// anteriores van en FILE_ENCRYPTION_PREVIOUS_KEYS, separadas por comas.
+func loadFileEncryptionKeys(cfg config.FileEncryptionConfig) (*fileEncryptionKeys, error) {
+	var current []byte
+	switch kms := cfg.KMS; kms {
+	case "":
+		encoded := cfg.Key
+		if encoded == "" {
+			return nil, nil
+		}
+		key, err := decodeFileEncryptionKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("FILE_ENCRYPTION_KEY inválida: %v", err)
+		}
+		current = key
+	case "vault":
+		key, err := unwrapVaultFileEncryptionKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo la clave de cifrado de Vault: %v", err)
+		}
+		current = key
+	default:
+		return nil, fmt.Errorf("FILE_ENCRYPTION_KMS desconocido: %s", kms)
+	}
+
+	keys := &fileEncryptionKeys{aeads: make(map[string]cipher.AEAD)}
+	id, err := keys.add(current)
+	if err != nil {
+		return nil, err
+	}
+	keys.currentID = id
+
+	for _, encoded := range strings.Split(cfg.PreviousKeys, ",") {
+		if encoded = strings.TrimSpace(encoded); encoded == "" {
+			continue
+		}
+		key, err := decodeFileEncryptionKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("FILE_ENCRYPTION_PREVIOUS_KEYS inválida: %v", err)
+		}
+		if _, err := keys.add(key); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+func decodeFileEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("la clave debe tener 32 bytes, tiene %d", len(key))
+	}
+	return key, nil
+}
+
+// add registra una clave y devuelve su identificador, derivado de la clave
+func (k *fileEncryptionKeys) add(key []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(key)
+	id := string(sum[:fileEncryptionKeyIDSize])
+	k.aeads[id] = aead
+	return id, nil
+}
+
+// unwrapVaultFileEncryptionKey descifra la clave de archivos con el motor transit de Vault
+func unwrapVaultFileEncryptionKey(cfg config.FileEncryptionConfig) ([]byte, error) {
+	addr := strings.TrimSuffix(cfg.VaultAddr, "/")
+	token := cfg.VaultToken
+	keyName := cfg.VaultKey
+	wrapped := cfg.WrappedKey
+	if addr == "" || token == "" || keyName == "" || wrapped == "" {
+		return nil, fmt.Errorf("VAULT_ADDR, VAULT_TOKEN, FILE_ENCRYPTION_VAULT_KEY y FILE_ENCRYPTION_WRAPPED_KEY son obligatorias")
+	}
+
+	body, err := json.Marshal(map[string]string{"ciphertext": wrapped})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, addr+"/v1/transit/decrypt/"+keyName, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Vault respondió HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("respuesta de Vault inválida: %v", err)
+	}
+	return decodeFileEncryptionKey(result.Data.Plaintext)
+}
+
+// encryptedFileStorage cifra los archivos antes de guardarlos en otro FileStorage y los descifra
+// al leerlos. Los archivos guardados antes de habilitar el cifrado se leen sin cambios.
+type encryptedFileStorage struct {
+	FileStorage
+	keys *fileEncryptionKeys
+}
+
+// withFileEncryption envuelve el almacenamiento con el cifrado si hay una clave configurada
+func withFileEncryption(files FileStorage, cfg config.FileEncryptionConfig) (FileStorage, error) {
+	keys, err := loadFileEncryptionKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return files, nil
+	}
+	slog.Info("cifrado de archivos habilitado (AES-256-GCM)")
+	return &encryptedFileStorage{FileStorage: files, keys: keys}, nil
+}
+
+func (f *encryptedFileStorage) Upload(bucket, path string, content io.Reader, contentType string, upsert bool) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(f.keys.encrypt(writer, content))
+	}()
+	err := f.FileStorage.Upload(bucket, path, reader, contentType, upsert)
+	reader.CloseWithError(io.ErrClosedPipe)
+	return err
+}
+
+func (f *encryptedFileStorage) Download(bucket, path string) ([]byte, error) {
+	data, err := f.FileStorage.Download(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f.keys.decrypt(bytes.NewReader(data)))
+}
+
+func (f *encryptedFileStorage) Open(bucket, path string) (io.ReadCloser, error) {
+	content, err := f.FileStorage.Open(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{f.keys.decrypt(content), content}, nil
+}
+
+// SignedURLs no firma nada: las URLs del almacenamiento entregarían el archivo cifrado, así que
+// se usan los enlaces firmados de la API, que lo descifran
+func (f *encryptedFileStorage) SignedURLs(bucket string, paths []string, ttl time.Duration) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+// segmentNonce devuelve el nonce de un segmento
+func segmentNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, fileEncryptionPrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encrypt escribe en dst el contenido de src cifrado con la clave actual
+func (k *fileEncryptionKeys) encrypt(dst io.Writer, src io.Reader) error {
+	aead := k.aeads[k.currentID]
+	prefix := make([]byte, fileEncryptionPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return err
+	}
+
+	header := append([]byte(fileEncryptionMagic+k.currentID), prefix...)
+	if _, err := dst.Write(header); err != nil {
+		return err
+	}
+
+	in := bufio.NewReaderSize(src, fileEncryptionSegmentSize)
+	segment := make([]byte, fileEncryptionSegmentSize)
+	sealed := make([]byte, 0, fileEncryptionSegmentSize+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(in, segment)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		// Es el último segmento si no se llenó o si no queda nada después
+		last := n < fileEncryptionSegmentSize
+		if !last {
+			if _, peekErr := in.Peek(1); peekErr == io.EOF {
+				last = true
+			}
+		}
+
+		sealed = aead.Seal(sealed[:0], segmentNonce(prefix, counter, last), segment[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if counter == ^uint32(0) {
+			return fmt.Errorf("archivo demasiado grande para cifrar")
+		}
+	}
+}
+
+// decrypt devuelve un lector del contenido descifrado de src. Si src no está cifrado se lee tal cual.
+func (k *fileEncryptionKeys) decrypt(src io.Reader) io.Reader {
+	in := bufio.NewReaderSize(src, fileEncryptionSegmentSize+64)
+	magic, err := in.Peek(len(fileEncryptionMagic))
+	if err != nil || string(magic) != fileEncryptionMagic {
+		// Archivo guardado antes de habilitar el cifrado
+		return in
+	}
+	return &decryptingReader{keys: k, in: in}
+}
+
+// decryptingReader descifra un archivo segmento por segmento
+type decryptingReader struct {
+	keys    *fileEncryptionKeys
+	in      *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	plain   []byte
+	done    bool
+	err     error
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next descifra el siguiente segmento
+func (r *decryptingReader) next() error {
+	if r.aead == nil {
+		header := make([]byte, fileEncryptionHeaderSize)
+		if _, err := io.ReadFull(r.in, header); err != nil {
+			return ErrFileDecryption
+		}
+		keyID := string(header[len(fileEncryptionMagic) : len(fileEncryptionMagic)+fileEncryptionKeyIDSize])
+		aead, ok := r.keys.aeads[keyID]
+		if !ok {
+			slog.Warn("archivo cifrado con una clave que no está configurada")
+			return ErrFileDecryption
+		}
+		r.aead = aead
+		r.prefix = header[len(fileEncryptionMagic)+fileEncryptionKeyIDSize:]
+	}
+
+	segment := make([]byte, fileEncryptionSegmentSize+r.aead.Overhead())
+	n, err := io.ReadFull(r.in, segment)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ErrFileDecryption
+	}
+	last := n < len(segment)
+	if !last {
+		if _, peekErr := r.in.Peek(1); peekErr == io.EOF {
+			last = true
+		}
+	}
+
+	plain, err := r.aead.Open(segment[:0], segmentNonce(r.prefix, r.counter, last), segment[:n], nil)
+	if err != nil {
+		return ErrFileDecryption
+	}
+	r.plain = plain
+	r.counter++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DefaultFileListCacheTTL es la vigencia por defecto de los listados de archivos en memoria
+const DefaultFileListCacheTTL = 30 * time.Second
+
+var (
+	// errFileIndexNotReady indica que el índice todavía no refleja el contenido del bucket
+	errFileIndexNotReady = errors.New("el índice de archivos no está listo")
+	// ErrFileReindexRunning indica que ya hay una reindexación en curso
+	ErrFileReindexRunning = errors.New("ya hay una reindexación de archivos en curso")
+)
+
+// FileIndexService mantiene la tabla file_index con los archivos que se listan (las carpetas
+// user_* y la raíz del bucket), actualizada en cada subida y eliminación, para que los listados
+// no tengan que recorrer las carpetas del almacenamiento una por una. Los listados se guardan
+// además en memoria durante FILE_LIST_CACHE_TTL (30s por defecto).
+type FileIndexService struct {
+	repo storage.FileIndexStore
+	ttl  time.Duration
+
+	mu         sync.Mutex
+	ready      bool
+	reindexing bool
+	cache      map[string]cachedFileList
+}
+
+type cachedFileList struct {
+	files   []SupabaseFileInfo
+	expires time.Time
+}
+
+// NewFileIndexService crea el servicio del índice de archivos
+func NewFileIndexService(factory *storage.RepositoryFactory, ttl time.Duration) *FileIndexService {
+	return &FileIndexService{
+		repo:  factory.GetFileIndexRepository(),
+		ttl:   configuredTTL("FILE_LIST_CACHE_TTL", ttl, DefaultFileListCacheTTL),
+		cache: make(map[string]cachedFileList),
+	}
+}
+
+// Start reindexa el bucket en segundo plano si el índice está vacío, p. ej. la primera vez que
+// se usa. Mientras tanto, o si no se puede leer el índice, los listados recorren las carpetas
+// del almacenamiento.
+func (i *FileIndexService) Start(s *SupabaseStorageService) {
+	entries, err := i.repo.List(context.Background(), "")
+	if err != nil {
+		slog.Warn("error leyendo el índice de archivos, los listados recorrerán el almacenamiento", "error", err)
+		return
+	}
+	if len(entries) > 0 {
+		i.mu.Lock()
+		i.ready = true
+		i.mu.Unlock()
+		slog.Info("file index loaded", "entries_count", len(entries))
+		return
+	}
+
+	go func() {
+		if _, err := i.Reindex(context.Background(), s); err != nil {
+			slog.Warn("error reindexando archivos", "error", err)
+		}
+	}()
+}
+
+// Reindex recorre las carpetas del almacenamiento y deja el índice igual a su contenido.
+// Devuelve la cantidad de archivos indexados.
+func (i *FileIndexService) Reindex(ctx context.Context, s *SupabaseStorageService) (int, error) {
+	i.mu.Lock()
+	if i.reindexing {
+		i.mu.Unlock()
+		return 0, ErrFileReindexRunning
+	}
+	i.reindexing = true
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		i.reindexing = false
+		i.mu.Unlock()
+	}()
+
+	logging.FromContext(ctx).Info("reindexando archivos del bucket", "bucket_name", s.bucketName)
+	files, err := s.scanAllFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	entries := make([]model.FileIndexEntry, 0, len(files))
+	found := make(map[string]bool, len(files))
+	for _, file := range files {
+		uploadedAt, _ := time.Parse(time.RFC3339, file.UploadedAt)
+		entries = append(entries, newFileIndexEntry(file.Path, file.Size, file.MimeType, uploadedAt))
+		found[file.Path] = true
+	}
+	if err := i.repo.Upsert(ctx, entries); err != nil {
+		return 0, err
+	}
+
+	indexed, err := i.repo.List(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	var stale []string
+	for _, entry := range indexed {
+		if !found[entry.Path] {
+			stale = append(stale, entry.Path)
+		}
+	}
+	if err := i.repo.Delete(ctx, stale); err != nil {
+		return 0, err
+	}
+
+	i.mu.Lock()
+	i.ready = true
+	i.mu.Unlock()
+	i.invalidate()
+
+	logging.FromContext(ctx).Info("índice de archivos actualizado: archivos, eliminados", "entries_count", len(entries), "stale_count", len(stale))
+	return len(entries), nil
+}
+
+// list devuelve los archivos indexados de un usuario, o de todo el bucket con userID vacío
+func (i *FileIndexService) list(ctx context.Context, userID string) ([]SupabaseFileInfo, error) {
+	if i == nil {
+		return nil, errFileIndexNotReady
+	}
+	i.mu.Lock()
+	ready := i.ready
+	i.mu.Unlock()
+	if !ready {
+		return nil, errFileIndexNotReady
+	}
+
+	entries, err := i.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var files []SupabaseFileInfo
+	for _, entry := range entries {
+		files = append(files, SupabaseFileInfo{
+			Name:       entry.Name,
+			Size:       entry.Size,
+			Path:       entry.Path,
+			MimeType:   entry.MimeType,
+			UploadedAt: entry.UploadedAt.Format(time.RFC3339),
+		})
+	}
+	return files, nil
+}
+
+// add agrega un archivo al índice si está en una carpeta que se lista
+func (i *FileIndexService) add(ctx context.Context, filePath string, size int64, mimeType string, uploadedAt time.Time) {
+	if i == nil || !isIndexedPath(filePath) {
+		return
+	}
+	defer i.invalidate()
+
+	entry := newFileIndexEntry(filePath, size, mimeType, uploadedAt)
+	if err := i.repo.Upsert(ctx, []model.FileIndexEntry{entry}); err != nil {
+		logging.FromContext(ctx).Warn("error indexando archivo", "file_path", filePath, "error", err)
+	}
+}
+
+// addStored agrega al índice un archivo que ya está en el almacenamiento, p. ej. uno restaurado
+// desde la papelera, con el tamaño y el tipo que informa el almacenamiento
+func (i *FileIndexService) addStored(ctx context.Context, s *SupabaseStorageService, filePath string) {
+	if i == nil || !isIndexedPath(filePath) {
+		return
+	}
+
+	folder := filepath.Dir(filePath)
+	if folder == "." {
+		folder = ""
+	}
+	files, err := s.listFolder(folder)
+	if err != nil {
+		logging.FromContext(ctx).Warn("error indexando archivo", "file_path", filePath, "error", err)
+		return
+	}
+	for _, file := range files {
+		if filepath.Base(file.Name) == filepath.Base(filePath) {
+			uploadedAt, _ := time.Parse(time.RFC3339, file.CreatedAt)
+			i.add(ctx, filePath, file.Size, file.MimeType, uploadedAt)
+			return
+		}
+	}
+}
+
+// remove quita un archivo del índice
+func (i *FileIndexService) remove(ctx context.Context, filePath string) {
+	if i == nil {
+		return
+	}
+	defer i.invalidate()
+
+	if err := i.repo.Delete(ctx, []string{filePath}); err != nil {
+		logging.FromContext(ctx).Warn("error quitando archivo del índice", "file_path", filePath, "error", err)
+	}
+}
+
+// cached devuelve el listado guardado en memoria para key, si sigue vigente
+func (i *FileIndexService) cached(key string) ([]SupabaseFileInfo, bool) {
+	if i == nil {
+		return nil, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	entry, ok := i.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.files, true
+}
+
+// store guarda un listado en memoria
+func (i *FileIndexService) store(key string, files []SupabaseFileInfo) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cache[key] = cachedFileList{files: files, expires: time.Now().Add(i.ttl)}
+}
+
+// invalidate descarta los listados guardados en memoria
+func (i *FileIndexService) invalidate() {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cache = make(map[string]cachedFileList)
+}
+
+func newFileIndexEntry(filePath string, size int64, mimeType string, uploadedAt time.Time) model.FileIndexEntry {
+	userID := ""
+	if folder := filepath.Dir(filePath); strings.HasPrefix(folder, "user_") {
+		userID = strings.TrimPrefix(folder, "user_")
+	}
+	if uploadedAt.IsZero() {
+		uploadedAt = time.Now()
+	}
+	return model.FileIndexEntry{
+		Path:       filePath,
+		UserID:     userID,
+		Name:       filepath.Base(filePath),
+		Size:       size,
+		MimeType:   mimeType,
+		UploadedAt: uploadedAt,
+	}
+}
+
+// isIndexedPath indica si un archivo aparece en los listados: los de las carpetas de usuario y
+// los de la raíz del bucket
+func isIndexedPath(filePath string) bool {
+	folder := filepath.Dir(filePath)
+	if folder == "." {
+		return strings.Contains(filePath, ".")
+	}
+	return strings.HasPrefix(folder, "user_") && !strings.Contains(folder, "/")
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultFileURLTTL es la vigencia por defecto de los enlaces de descarga y vista previa
+	DefaultFileURLTTL = time.Hour
+	// DefaultFileShareTTL es la vigencia por defecto de un enlace compartido
+	DefaultFileShareTTL = 15 * time.Minute
+	// MaxFileShareTTL es la vigencia máxima que se puede pedir para un enlace compartido
+	MaxFileShareTTL = 7 * 24 * time.Hour
+)
+
+// ErrInvalidFileShareToken indica que un enlace compartido no es válido o ya caducó
+var ErrInvalidFileShareToken = errors.New("enlace inválido o caducado")
+
+// URLTTL devuelve la vigencia de los enlaces de descarga (FILE_URL_TTL, p. ej. 30m o 2h)
+func (s *SupabaseStorageService) URLTTL() time.Duration {
+	return s.urlTTL
+}
+
+// ShareTTL devuelve la vigencia por defecto de los enlaces compartidos (FILE_SHARE_TTL)
+func (s *SupabaseStorageService) ShareTTL() time.Duration {
+	return s.shareTTL
+}
+
+// configuredTTL devuelve la vigencia configurada en name, o fallback si no se configuró o supera
+// MaxFileShareTTL
+func configuredTTL(name string, ttl, fallback time.Duration) time.Duration {
+	if ttl <= 0 {
+		return fallback
+	}
+	if ttl > MaxFileShareTTL {
+		slog.Warn("supera el máximo, se usa", "name", name, "max_file_share_ttl", MaxFileShareTTL, "fallback", fallback)
+		return fallback
+	}
+	return ttl
+}
+
+func (s *SupabaseStorageService) shareSignature(encodedPath, expires string) string {
+	mac := hmac.New(sha256.New, s.shareSecret)
+	mac.Write([]byte(encodedPath + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignShareToken devuelve un token que permite descargar filePath hasta expiresAt
+func (s *SupabaseStorageService) SignShareToken(filePath string, expiresAt time.Time) string {
+	encodedPath := base64.RawURLEncoding.EncodeToString([]byte(filePath))
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return encodedPath + "." + expires + "." + s.shareSignature(encodedPath, expires)
+}
+
+// ParseShareToken devuelve la ruta del archivo de un token vigente
+func (s *SupabaseStorageService) ParseShareToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidFileShareToken
+	}
+	if !hmac.Equal([]byte(s.shareSignature(parts[0], parts[1])), []byte(parts[2])) {
+		return "", ErrInvalidFileShareToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", ErrInvalidFileShareToken
+	}
+	filePath, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidFileShareToken
+	}
+	return string(filePath), nil
+}
+
+// fileShareURL devuelve el enlace de la API que sirve un token firmado. Apunta a este backend
+// (API_BASE_URL), o a APP_BASE_URL cuando la API se sirve detrás de él.
+func fileShareURL(token string) string {
+	baseURL := GetAPIBaseURL()
+	if baseURL == "" {
+		baseURL = GetAppBaseURL()
+	}
+	return fmt.Sprintf("%s/api/files/shared/%s", baseURL, token)
+}
+
+// SignedURL devuelve un enlace de descarga de filePath que caduca tras ttl. Usa la URL firmada
+// del backend de almacenamiento y, si no la tiene, un enlace firmado de la API.
+func (s *SupabaseStorageService) SignedURL(filePath string, ttl time.Duration) (string, error) {
+	urls, err := s.SignedURLs([]string{filePath}, ttl)
+	if err != nil {
+		return "", err
+	}
+	return urls[filePath], nil
+}
+
+// SignedURLs firma varios enlaces a la vez, como SignedURL
+func (s *SupabaseStorageService) SignedURLs(paths []string, ttl time.Duration) (map[string]string, error) {
+	urls, err := s.files.SignedURLs(s.bucketName, paths, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("error firmando enlaces: %v", err)
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	for _, path := range paths {
+		if _, ok := urls[path]; !ok {
+			urls[path] = fileShareURL(s.SignShareToken(path, expiresAt))
+		}
+	}
+	return urls, nil
+}
+
+// downloadURL firma el enlace de descarga de un archivo con la vigencia de FILE_URL_TTL. Un
+// error se registra y deja el enlace vacío.
+func (s *SupabaseStorageService) downloadURL(filePath string) string {
+	url, err := s.SignedURL(filePath, s.urlTTL)
+	if err != nil {
+		slog.Warn("error firmando enlace", "file_path", filePath, "error", err)
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	storage_go "github.com/supabase-community/storage-go"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/metrics"
+)
+
+// Backends de almacenamiento de archivos, seleccionados con FILE_STORAGE_BACKEND
+const (
+	FileStorageSupabase = "supabase"
+	FileStorageLocal    = "local"
+	FileStorageNone     = "none"
+)
+
+// FileStorage es el almacenamiento donde se guardan los archivos subidos y generados. Los
+// archivos se organizan en buckets y se identifican por su ruta dentro del bucket.
+type FileStorage interface {
+	// Name devuelve el nombre del backend (supabase, local)
+	Name() string
+	// EnsureBucket crea el bucket si no existe
+	EnsureBucket(bucket string) error
+	// Upload guarda el contenido en la ruta; sin upsert falla si el archivo ya existe
+	Upload(bucket, path string, content io.Reader, contentType string, upsert bool) error
+	// Download devuelve el contenido completo de un archivo
+	Download(bucket, path string) ([]byte, error)
+	// Open abre un archivo para leerlo sin cargarlo en memoria
+	Open(bucket, path string) (io.ReadCloser, error)
+	// Remove elimina archivos del bucket
+	Remove(bucket string, paths []string) error
+	// List devuelve los elementos directamente dentro de prefix, con nombres relativos a prefix
+	List(bucket, prefix string, limit, offset int) ([]StoredFile, error)
+	// Move pasa un archivo a otro bucket conservando su ruta
+	Move(sourceBucket, path, destinationBucket string) error
+	// Rename cambia la ruta de un archivo dentro del bucket; falla si el destino ya existe
+	Rename(bucket, from, to string) error
+	// SignedURLs devuelve URLs de descarga que caducan tras ttl, por ruta. Las rutas que el
+	// backend no puede firmar no aparecen en el resultado.
+	SignedURLs(bucket string, paths []string, ttl time.Duration) (map[string]string, error)
+}
+
+// StoredFile es un elemento listado de un bucket: un archivo o una carpeta
+type StoredFile struct {
+	Name      string
+	Size      int64
+	MimeType  string
+	CreatedAt string
+	UpdatedAt string
+	Folder    bool
+}
+
+// meteredFileStorage cuenta las subidas y los bytes subidos al backend para las métricas
+type meteredFileStorage struct {
+	FileStorage
+}
+
+func (f meteredFileStorage) Upload(bucket, path string, content io.Reader, contentType string, upsert bool) error {
+	counter := &countingReader{reader: content}
+	err := f.FileStorage.Upload(bucket, path, counter, contentType, upsert)
+	metrics.StorageUpload(f.Name(), counter.count, err)
+	return err
+}
+
+// countingReader cuenta los bytes leídos del contenido de una subida
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// fileStorageBackend devuelve el backend configurado en FILE_STORAGE_BACKEND. Sin configurar
+// se usa Supabase Storage cuando hay credenciales de Supabase y el disco local en otro caso.
+func fileStorageBackend(files config.FilesConfig, supabase config.SupabaseConfig) string {
+	if backend := files.Backend; backend != "" {
+		return backend
+	}
+	if supabase.URL != "" && (supabase.ServiceRoleKey != "" || supabase.Key != "") {
+		return FileStorageSupabase
+	}
+	return FileStorageLocal
+}
+
+// supabaseFileStorage guarda los archivos en Supabase Storage
+type supabaseFileStorage struct {
+	client     *storage_go.Client
+	projectURL string
+}
+
+// newSupabaseFileStorage crea el backend de Supabase Storage con SUPABASE_URL y
+// SUPABASE_SERVICE_ROLE_KEY, o SUPABASE_KEY como alternativa
+func newSupabaseFileStorage(cfg config.SupabaseConfig) (*supabaseFileStorage, error) {
+	projectURL := cfg.URL
+	if projectURL == "" {
+		return nil, fmt.Errorf("SUPABASE_URL no está configurada")
+	}
+
+	// Intentar usar service role key primero (si está disponible), luego anon key como fallback
+	apiKey := cfg.ServiceRoleKey
+	if apiKey == "" {
+		apiKey = cfg.Key
+		if apiKey == "" {
+			return nil, fmt.Errorf("SUPABASE_SERVICE_ROLE_KEY o SUPABASE_KEY debe estar configurada")
+		}
+		slog.Warn("usando SUPABASE_KEY (anon key) - Asegúrate de que las políticas de storage estén configuradas")
+	} else {
+		slog.Info("usando SUPABASE_SERVICE_ROLE_KEY (service role key)")
+	}
+
+	storageURL := fmt.Sprintf("%s/storage/v1", projectURL)
+	slog.Info("storage de Supabase", "storage_url", storageURL)
+
+	return &supabaseFileStorage{
+		client:     storage_go.NewClient(storageURL, apiKey, nil),
+		projectURL: projectURL,
+	}, nil
+}
+
+func (f *supabaseFileStorage) Name() string {
+	return FileStorageSupabase
+}
+
+func (f *supabaseFileStorage) EnsureBucket(bucket string) error {
+	// Intentar obtener el bucket
+	if _, err := f.client.GetBucket(bucket); err == nil {
+		return nil
+	}
+
+	// Si el bucket no existe, crearlo
+	slog.Info("creando bucket", "bucket", bucket)
+	_, err := f.client.CreateBucket(bucket, storage_go.BucketOptions{
+		Public: false, // Bucket privado por seguridad
+	})
+	if err != nil {
+		return fmt.Errorf("error creando bucket: %v", err)
+	}
+	slog.Info("bucket creado exitosamente", "bucket", bucket)
+	return nil
+}
+
+func (f *supabaseFileStorage) Upload(bucket, path string, content io.Reader, contentType string, upsert bool) error {
+	_, err := f.client.UploadFile(bucket, path, content, storage_go.FileOptions{
+		ContentType: &contentType,
+		Upsert:      &upsert,
+	})
+	return err
+}
+
+func (f *supabaseFileStorage) Download(bucket, path string) ([]byte, error) {
+	return f.client.DownloadFile(bucket, path)
+}
+
+func (f *supabaseFileStorage) Open(bucket, path string) (io.ReadCloser, error) {
+	signed, err := f.client.CreateSignedUrl(bucket, path, 300)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(signed.SignedURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %d descargando %s", resp.StatusCode, path)
+	}
+	return resp.Body, nil
+}
+
+func (f *supabaseFileStorage) Remove(bucket string, paths []string) error {
+	_, err := f.client.RemoveFile(bucket, paths)
+	return err
+}
+
+func (f *supabaseFileStorage) List(bucket, prefix string, limit, offset int) ([]StoredFile, error) {
+	objects, err := f.client.ListFiles(bucket, prefix, storage_go.FileSearchOptions{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]StoredFile, 0, len(objects))
+	for _, object := range objects {
+		file := StoredFile{
+			Name:      object.Name,
+			CreatedAt: object.CreatedAt,
+			UpdatedAt: object.UpdatedAt,
+			Folder:    object.Id == "", // Las carpetas no tienen ID
+		}
+		// Tamaño y tipo MIME desde metadata
+		if metadata, ok := object.Metadata.(map[string]interface{}); ok {
+			if size, ok := metadata["size"].(float64); ok {
+				file.Size = int64(size)
+			}
+			if mimeType, ok := metadata["mimetype"].(string); ok {
+				file.MimeType = mimeType
+			}
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (f *supabaseFileStorage) Move(sourceBucket, path, destinationBucket string) error {
+	req, err := f.client.NewRequest(http.MethodPost, f.projectURL+"/storage/v1/object/move", map[string]string{
+		"bucketId":          sourceBucket,
+		"sourceKey":         path,
+		"destinationBucket": destinationBucket,
+		"destinationKey":    path,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req, nil)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func (f *supabaseFileStorage) Rename(bucket, from, to string) error {
+	_, err := f.client.MoveFile(bucket, from, to)
+	return err
+}
+
+// SignedURLs firma todas las rutas en una sola petición al endpoint de firmas de Supabase Storage
+func (f *supabaseFileStorage) SignedURLs(bucket string, paths []string, ttl time.Duration) (map[string]string, error) {
+	urls := make(map[string]string, len(paths))
+	if len(paths) == 0 {
+		return urls, nil
+	}
+
+	req, err := f.client.NewRequest(http.MethodPost, f.projectURL+"/storage/v1/object/sign/"+bucket, map[string]interface{}{
+		"expiresIn": int(ttl.Seconds()),
+		"paths":     paths,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var signed []struct {
+		Path      string  `json:"path"`
+		SignedURL string  `json:"signedURL"`
+		Error     *string `json:"error"`
+	}
+	resp, err := f.client.Do(req, &signed)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range signed {
+		if s.Error != nil || s.SignedURL == "" {
+			continue
+		}
+		urls[s.Path] = f.projectURL + "/storage/v1" + s.SignedURL
+	}
+	return urls, nil
+}
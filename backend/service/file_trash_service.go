@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// fileTrashPrefix es la carpeta del bucket donde quedan los archivos eliminados
+	fileTrashPrefix = "trash"
+	// defaultFileTrashRetentionDays son los días que un archivo eliminado se puede restaurar
+	defaultFileTrashRetentionDays = 30
+	// defaultFileTrashPurgeSchedule purga la papelera todos los días a las 3:00
+	defaultFileTrashPurgeSchedule = "0 3 * * *"
+)
+
+var (
+	// ErrTrashedFileNotFound indica que el archivo no está en la papelera
+	ErrTrashedFileNotFound = errors.New("archivo no encontrado en la papelera")
+	// ErrFileTrashDisabled indica que los archivos se eliminan sin pasar por la papelera
+	ErrFileTrashDisabled = errors.New("la papelera de archivos está deshabilitada")
+)
+
+// FileTrashService guarda los archivos que eliminan los admins en la carpeta trash/ del bucket
+// durante FILE_TRASH_RETENTION_DAYS días (30 por defecto), para poder restaurarlos, y los purga
+// después. Con 0 días los archivos se eliminan de inmediato.
+type FileTrashService struct {
+	repo         storage.TrashedFileStore
+	metadataRepo storage.FileMetadataStore
+	retention    time.Duration
+	schedule     string
+}
+
+// NewFileTrashService crea el servicio de la papelera, que guarda los archivos retentionDays
+// días (FILE_TRASH_RETENTION_DAYS, 30 sin configurar) y los purga con schedule
+// (FILE_TRASH_PURGE_SCHEDULE)
+func NewFileTrashService(factory *storage.RepositoryFactory, retentionDays *int, schedule string) *FileTrashService {
+	days := defaultFileTrashRetentionDays
+	if retentionDays != nil {
+		days = *retentionDays
+	}
+
+	return &FileTrashService{
+		repo:         factory.GetTrashedFileRepository(),
+		metadataRepo: factory.GetFileMetadataRepository(),
+		retention:    time.Duration(days) * 24 * time.Hour,
+		schedule:     schedule,
+	}
+}
+
+// Enabled indica si los archivos eliminados pasan por la papelera
+func (t *FileTrashService) Enabled() bool {
+	return t != nil && t.retention > 0
+}
+
+// Retention devuelve cuánto tiempo se conservan los archivos eliminados
+func (t *FileTrashService) Retention() time.Duration {
+	return t.retention
+}
+
+// List devuelve los archivos de la papelera, los eliminados más recientemente primero
+func (t *FileTrashService) List(ctx context.Context) ([]model.TrashedFile, error) {
+	return t.repo.GetAll(ctx)
+}
+
+// trash mueve un archivo a la papelera
+func (t *FileTrashService) trash(ctx context.Context, s *SupabaseStorageService, filePath, deletedBy string) (*model.TrashedFile, error) {
+	id := uuid.New()
+	trashPath := fmt.Sprintf("%s/%s/%s", fileTrashPrefix, id, filePath)
+	if err := s.files.Rename(s.bucketName, filePath, trashPath); err != nil {
+		return nil, fmt.Errorf("error moviendo archivo a la papelera: %v", err)
+	}
+
+	now := time.Now()
+	trashed, err := t.repo.Create(ctx, model.TrashedFile{
+		ID:        id,
+		Path:      filePath,
+		TrashPath: trashPath,
+		FileName:  filepath.Base(filePath),
+		DeletedBy: deletedBy,
+		DeletedAt: now,
+		PurgeAt:   now.Add(t.retention),
+	})
+	if err != nil {
+		// Sin el registro el archivo no se podría restaurar ni purgar: devolverlo a su lugar
+		if restoreErr := s.files.Rename(s.bucketName, trashPath, filePath); restoreErr != nil {
+			logging.FromContext(ctx).Warn("error devolviendo desde la papelera", "file_path", filePath, "error", restoreErr)
+		}
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("archivo movido a la papelera", "file_path", filePath, "purge_at", trashed.PurgeAt.Format("2006-01-02"))
+	return trashed, nil
+}
+
+// get obtiene un archivo de la papelera
+func (t *FileTrashService) get(ctx context.Context, id uuid.UUID) (*model.TrashedFile, error) {
+	trashed, err := t.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trashed == nil {
+		return nil, ErrTrashedFileNotFound
+	}
+	return trashed, nil
+}
+
+// Restore devuelve un archivo de la papelera a su ruta original
+func (t *FileTrashService) Restore(ctx context.Context, id uuid.UUID) (*model.TrashedFile, error) {
+	s := GetSupabaseStorageService()
+	if s == nil {
+		return nil, fmt.Errorf("servicio de archivos no disponible")
+	}
+	trashed, err := t.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.files.Rename(s.bucketName, trashed.TrashPath, trashed.Path); err != nil {
+		return nil, fmt.Errorf("error restaurando archivo: %v", err)
+	}
+	if err := t.repo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	s.index.addStored(ctx, s, trashed.Path)
+
+	logging.FromContext(ctx).Info("archivo restaurado desde la papelera", "path", trashed.Path)
+	return trashed, nil
+}
+
+// Purge elimina definitivamente un archivo de la papelera junto con sus datos
+func (t *FileTrashService) Purge(ctx context.Context, id uuid.UUID) error {
+	trashed, err := t.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return t.purge(ctx, trashed)
+}
+
+func (t *FileTrashService) purge(ctx context.Context, trashed *model.TrashedFile) error {
+	s := GetSupabaseStorageService()
+	if s == nil {
+		return fmt.Errorf("servicio de archivos no disponible")
+	}
+
+	if err := s.files.Remove(s.bucketName, []string{trashed.TrashPath}); err != nil {
+		return fmt.Errorf("error eliminando archivo: %v", err)
+	}
+	if err := t.metadataRepo.DeleteByFileName(ctx, trashed.FileName); err != nil {
+		logging.FromContext(ctx).Warn("error eliminando los datos del archivo", "path", trashed.Path, "error", err)
+	}
+	if err := t.repo.Delete(ctx, trashed.ID); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("archivo purgado de la papelera", "path", trashed.Path)
+	return nil
+}
+
+// PurgeExpired elimina los archivos cuya retención terminó y devuelve cuántos se purgaron
+func (t *FileTrashService) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	expired, err := t.repo.GetExpired(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo archivos vencidos de la papelera: %v", err)
+	}
+
+	purged := 0
+	for i := range expired {
+		if err := t.purge(ctx, &expired[i]); err != nil {
+			logging.FromContext(ctx).Error("error purgando de la papelera", "path", expired[i].Path, "error", err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Start programa la purga de la papelera (FILE_TRASH_PURGE_SCHEDULE, todos los días a las 3:00
+// por defecto)
+func (t *FileTrashService) Start() error {
+	if !t.Enabled() {
+		return nil
+	}
+
+	schedule := t.schedule
+	if schedule == "" {
+		schedule = defaultFileTrashPurgeSchedule
+	}
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if GetSupabaseStorageService() == nil || !JobEnabled("file_trash_purge") {
+			return
+		}
+		start := time.Now()
+		purged, err := t.PurgeExpired(context.Background(), start)
+		metrics.JobRun("file_trash_purge", start, err)
+		if err != nil {
+			slog.Error("error purgando la papelera de archivos", "error", err)
+			return
+		}
+		if purged > 0 {
+			slog.Info("archivos purgados de la papelera", "purged", purged)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("programación inválida de la purga de la papelera: %w", err)
+	}
+	c.Start()
+	stopCronOnShutdown("file trash purge", c)
+	slog.Info("purga de la papelera programada, retención", "schedule", schedule, "retention", t.retention)
+	return nil
+}
+
+// isTrashPath indica si una ruta está dentro de la papelera
+func isTrashPath(filePath string) bool {
+	return filePath == fileTrashPrefix || strings.HasPrefix(filePath, fileTrashPrefix+"/")
+}
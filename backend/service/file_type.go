@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLength es la cantidad de bytes que http.DetectContentType analiza
+const sniffLength = 512
+
+// oleSignature es la firma de los documentos OLE de Office (.doc), que DetectContentType no reconoce
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// allowedFileType describe el contenido esperado para una extensión permitida
+type allowedFileType struct {
+	detected []string // Tipos que puede detectar el análisis del contenido
+	mimeType string   // Tipo MIME que se guarda; vacío usa el detectado
+}
+
+// allowedUploadTypes son las extensiones que se pueden subir y el contenido que deben tener
+var allowedUploadTypes = map[string]allowedFileType{
+	".pdf":  {detected: []string{"application/pdf"}},
+	".doc":  {detected: []string{"application/msword"}},
+	".docx": {detected: []string{"application/zip"}, mimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	".jpg":  {detected: []string{"image/jpeg"}},
+	".jpeg": {detected: []string{"image/jpeg"}},
+	".png":  {detected: []string{"image/png"}},
+	".gif":  {detected: []string{"image/gif"}},
+	".txt":  {detected: []string{"text/plain"}},
+	".zip":  {detected: []string{"application/zip"}},
+	".rar":  {detected: []string{"application/x-rar-compressed"}},
+}
+
+// FileTypeError indica que el contenido de un archivo no corresponde a su extensión
+type FileTypeError struct {
+	FileName  string
+	Extension string
+	Detected  string
+}
+
+func (e *FileTypeError) Error() string {
+	if e.Detected == "" {
+		return fmt.Sprintf("tipo de archivo no permitido: %s", e.Extension)
+	}
+	return fmt.Sprintf("el contenido del archivo %s (%s) no corresponde a la extensión %s", e.FileName, e.Detected, e.Extension)
+}
+
+// IsAllowedFileExtension indica si la extensión del archivo se puede subir
+func IsAllowedFileExtension(fileName string) bool {
+	_, ok := allowedUploadTypes[strings.ToLower(filepath.Ext(fileName))]
+	return ok
+}
+
+// DetectContentType identifica el tipo MIME de un archivo por sus primeros bytes
+func DetectContentType(head []byte) string {
+	if bytes.HasPrefix(head, oleSignature) {
+		return "application/msword"
+	}
+	return http.DetectContentType(head)
+}
+
+// VerifyFileType lee los primeros bytes del contenido y verifica que correspondan a la extensión
+// del archivo, de modo que un .exe renombrado a .pdf sea rechazado. Devuelve el tipo MIME
+// verificado y un reader con el contenido completo, incluidos los bytes ya leídos.
+func VerifyFileType(fileName string, content io.Reader) (string, io.Reader, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	allowed, ok := allowedUploadTypes[ext]
+	if !ok {
+		return "", nil, &FileTypeError{FileName: fileName, Extension: ext}
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", nil, fmt.Errorf("error leyendo archivo: %v", err)
+	}
+	head = head[:n]
+
+	detected := DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		mediaType = detected
+	}
+
+	for _, expected := range allowed.detected {
+		if mediaType == expected {
+			mimeType := allowed.mimeType
+			if mimeType == "" {
+				mimeType = detected
+			}
+			return mimeType, io.MultiReader(bytes.NewReader(head), content), nil
+		}
+	}
+	return "", nil, &FileTypeError{FileName: fileName, Extension: ext, Detected: mediaType}
+}
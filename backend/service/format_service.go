@@ -0,0 +1,265 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// DefaultLocale and DefaultTimezone are used when APP_LOCALE / APP_TIMEZONE are not set
+const (
+	DefaultLocale   = "es-CO"
+	DefaultTimezone = "America/Bogota"
+)
+
+var spanishMonthNames = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+	"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+
+// Formatter renders dates and amounts for a locale and timezone, so emails, PDFs and API
+// responses show the same "5 de marzo de 2025" and "$1.600.000" to tenants
+type Formatter struct {
+	locale   string
+	location *time.Location
+}
+
+var (
+	// defaultFormatter uses APP_LOCALE and APP_TIMEZONE once InitializeFormatter runs
+	defaultFormatter = NewFormatter(DefaultLocale, DefaultTimezone)
+
+	// organizationFormatters caches the formatters of the organizations by locale and timezone
+	organizationFormatters sync.Map
+)
+
+// NewFormatter creates a Formatter. Unknown locales fall back to Spanish and an unknown
+// timezone falls back to the server local time.
+func NewFormatter(locale, timezone string) *Formatter {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		slog.Warn("unknown timezone, using server local time", "timezone", timezone, "error", err)
+		location = time.Local
+	}
+	return &Formatter{
+		locale:   strings.ToLower(locale),
+		location: location,
+	}
+}
+
+// InitializeFormatter configures the default Formatter with APP_LOCALE and APP_TIMEZONE
+func InitializeFormatter(cfg config.LocaleConfig) {
+	defaultFormatter = NewFormatter(
+		orDefault(cfg.Locale, DefaultLocale),
+		orDefault(cfg.Timezone, DefaultTimezone),
+	)
+}
+
+// DefaultFormatter returns the Formatter configured with APP_LOCALE and APP_TIMEZONE
+func DefaultFormatter() *Formatter {
+	return defaultFormatter
+}
+
+// localePattern matches the language-REGION locales the formatter is configured with
+var localePattern = regexp.MustCompile(`^(?i)(es|en)(-[a-z]{2})?$`)
+
+// IsSupportedLocale reports whether the formatter renders a locale, a Spanish or English one
+// such as es-CO or en-US
+func IsSupportedLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// FormatterFor returns the Formatter of an organization, with its locale and timezone. The
+// settings it leaves empty, and a nil organization, use the default formatter.
+func FormatterFor(org *model.Organization) *Formatter {
+	if org == nil || (org.Locale == "" && org.Timezone == "") {
+		return DefaultFormatter()
+	}
+
+	defaults := DefaultFormatter()
+	locale, timezone := org.Locale, org.Timezone
+	if locale == "" {
+		locale = defaults.locale
+	}
+	if timezone == "" {
+		timezone = defaults.location.String()
+	}
+
+	key := strings.ToLower(locale) + "|" + timezone
+	if cached, ok := organizationFormatters.Load(key); ok {
+		return cached.(*Formatter)
+	}
+	formatter, _ := organizationFormatters.LoadOrStore(key, NewFormatter(locale, timezone))
+	return formatter.(*Formatter)
+}
+
+// OrganizationLocation returns the timezone of an organization, APP_TIMEZONE when it has none
+func OrganizationLocation(org *model.Organization) *time.Location {
+	return FormatterFor(org).Location()
+}
+
+// WithLocale returns a Formatter with the timezone of f and another locale
+func (f *Formatter) WithLocale(locale string) *Formatter {
+	return &Formatter{locale: strings.ToLower(locale), location: f.location}
+}
+
+// Location returns the timezone of the formatter
+func (f *Formatter) Location() *time.Location {
+	return f.location
+}
+
+// isEnglish reports whether the locale is English; every other locale uses Spanish
+func (f *Formatter) isEnglish() bool {
+	return strings.HasPrefix(f.locale, "en")
+}
+
+// Date formats a date in long form: "5 de marzo de 2025" ("March 5, 2025" in English)
+func (f *Formatter) Date(date time.Time) string {
+	if date.IsZero() {
+		if f.isEnglish() {
+			return "Date not specified"
+		}
+		return "Fecha no especificada"
+	}
+
+	date = date.In(f.location)
+	if f.isEnglish() {
+		return date.Format("January 2, 2006")
+	}
+	return fmt.Sprintf("%d de %s de %d", date.Day(), spanishMonthNames[date.Month()-1], date.Year())
+}
+
+// DateTime formats a date with the local time: "5 de marzo de 2025, 14:30"
+func (f *Formatter) DateTime(date time.Time) string {
+	if date.IsZero() {
+		return f.Date(date)
+	}
+
+	local := date.In(f.location)
+	if f.isEnglish() {
+		return f.Date(local) + " " + local.Format("3:04 PM")
+	}
+	return f.Date(local) + ", " + local.Format("15:04")
+}
+
+// ShortDate formats a date as "05/03/2025" ("03/05/2025" in English)
+func (f *Formatter) ShortDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	date = date.In(f.location)
+	if f.isEnglish() {
+		return date.Format("01/02/2006")
+	}
+	return date.Format("02/01/2006")
+}
+
+// currencySymbols are the symbols amounts are written with; dollars are told apart from pesos
+var currencySymbols = map[string]string{
+	model.CurrencyCOP: "$",
+	model.CurrencyUSD: "US$",
+}
+
+// Money formats an amount in pesos, without cents: "$1.600.000" ("$1,600,000" in English)
+func (f *Formatter) Money(amount float64) string {
+	return f.Amount(model.COP(amount))
+}
+
+// Amount formats an amount in its currency with the separators of the locale: pesos without
+// cents ("$1.600.000") and dollars with them ("US$1.250,50", "US$1,250.50" in English)
+func (f *Formatter) Amount(money model.Money) string {
+	currency := money.CurrencyCode()
+	decimals := model.CurrencyDecimals(currency)
+	amount := roundTo(money.Amount, decimals)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency + " "
+	}
+	return sign + symbol + f.Number(amount, decimals)
+}
+
+// roundTo rounds an amount half away from zero to a number of decimals
+func roundTo(amount float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(amount*scale) / scale
+}
+
+// Number formats a number with the thousands and decimal separators of the locale
+func (f *Formatter) Number(value float64, decimals int) string {
+	thousandsSep, decimalSep := ".", ","
+	if f.isEnglish() {
+		thousandsSep, decimalSep = ",", "."
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	formatted := fmt.Sprintf("%.*f", decimals, value)
+	intPart, fracPart, _ := strings.Cut(formatted, ".")
+
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			grouped.WriteString(thousandsSep)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if fracPart != "" {
+		return sign + grouped.String() + decimalSep + fracPart
+	}
+	return sign + grouped.String()
+}
+
+// AppLocation returns the configured application timezone (APP_TIMEZONE)
+func AppLocation() *time.Location {
+	return DefaultFormatter().Location()
+}
+
+// FormatDate formats a date in long form with the default formatter
+func FormatDate(date time.Time) string {
+	return DefaultFormatter().Date(date)
+}
+
+// FormatDateTime formats a date and time with the default formatter
+func FormatDateTime(date time.Time) string {
+	return DefaultFormatter().DateTime(date)
+}
+
+// FormatShortDate formats a date as dd/mm/yyyy with the default formatter
+func FormatShortDate(date time.Time) string {
+	return DefaultFormatter().ShortDate(date)
+}
+
+// FormatMoney formats an amount in pesos with the default formatter
+func FormatMoney(amount float64) string {
+	return DefaultFormatter().Money(amount)
+}
+
+// FormatAmount formats an amount in its currency with the default formatter
+func FormatAmount(money model.Money) string {
+	return DefaultFormatter().Amount(money)
+}
+
+// CurrencyWords returns how contracts write the currency after an amount in words
+func CurrencyWords(currency string) string {
+	if currency == model.CurrencyUSD {
+		return "DÓLARES DE LOS ESTADOS UNIDOS DE AMÉRICA"
+	}
+	return "PESOS MONEDA LEGAL"
+}
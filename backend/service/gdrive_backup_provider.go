@@ -0,0 +1,224 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+const (
+	googleDriveScope     = "https://www.googleapis.com/auth/drive.file"
+	googleDriveUploadURL = "https://www.googleapis.com/upload/drive/v3/files?uploadType=multipart&supportsAllDrives=true"
+	googleDriveFilesURL  = "https://www.googleapis.com/drive/v3/files"
+)
+
+// googleServiceAccount son los campos usados del JSON de credenciales de un Service Account
+type googleServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// GoogleDriveBackupProvider respalda archivos en una carpeta de Google Drive con un Service
+// Account. La carpeta debe estar compartida con el correo del Service Account; como estas
+// cuentas no tienen cuota propia, conviene que esté en una unidad compartida.
+type GoogleDriveBackupProvider struct {
+	account  googleServiceAccount
+	folderID string
+	client   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewGoogleDriveBackupProvider crea el proveedor con las credenciales de
+// GOOGLE_SERVICE_ACCOUNT_PATH (./google_service_account.json por defecto) y la carpeta
+// GOOGLE_DRIVE_BACKUP_FOLDER_ID
+func NewGoogleDriveBackupProvider(cfg config.GoogleDriveBackupConfig) (*GoogleDriveBackupProvider, error) {
+	folderID := cfg.FolderID
+	if folderID == "" {
+		return nil, fmt.Errorf("GOOGLE_DRIVE_BACKUP_FOLDER_ID no está configurada")
+	}
+
+	path := cfg.ServiceAccountPath
+	if path == "" {
+		path = "google_service_account.json"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo las credenciales del Service Account: %v", err)
+	}
+	var account googleServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("error parseando las credenciales del Service Account: %v", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("las credenciales del Service Account no tienen client_email o private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	return &GoogleDriveBackupProvider{
+		account:  account,
+		folderID: folderID,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name devuelve el nombre del proveedor
+func (p *GoogleDriveBackupProvider) Name() string {
+	return BackupProviderGoogleDrive
+}
+
+// Backup sube el archivo a la carpeta configurada y devuelve su ID en Drive
+func (p *GoogleDriveBackupProvider) Backup(ctx context.Context, file BackupFile) (string, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"name":        file.FileName,
+		"parents":     []string{p.folderID},
+		"description": fmt.Sprintf("Backup de %s (usuario %s)", file.Path, file.UserID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	metadataPart, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return "", fmt.Errorf("error armando la petición: %v", err)
+	}
+	metadataPart.Write(metadata)
+	contentPart, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/octet-stream"}})
+	if err != nil {
+		return "", fmt.Errorf("error armando la petición: %v", err)
+	}
+	contentPart.Write(file.Data)
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleDriveUploadURL, &body)
+	if err != nil {
+		return "", fmt.Errorf("error creando request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "multipart/related; boundary="+writer.Boundary())
+
+	var uploaded struct {
+		ID string `json:"id"`
+	}
+	if err := p.doJSON(req, &uploaded); err != nil {
+		return "", err
+	}
+	return uploaded.ID, nil
+}
+
+// Restore descarga el contenido del archivo de Drive con el ID location
+func (p *GoogleDriveBackupProvider) Restore(ctx context.Context, location string) ([]byte, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fileURL := fmt.Sprintf("%s/%s?alt=media&supportsAllDrives=true", googleDriveFilesURL, url.PathEscape(location))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return p.do(req)
+}
+
+// token devuelve un access token vigente, pidiendo uno nuevo con una aserción JWT firmada con
+// la clave del Service Account cuando el anterior está por vencer
+func (p *GoogleDriveBackupProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.accessToken != "" && time.Now().Before(p.expiresAt.Add(-time.Minute)) {
+		return p.accessToken, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(p.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("clave privada del Service Account inválida: %v", err)
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": googleDriveScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("error firmando la aserción: %v", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creando request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := p.doJSON(req, &tokenResp); err != nil {
+		return "", fmt.Errorf("error obteniendo access token de Google: %v", err)
+	}
+	p.accessToken = tokenResp.AccessToken
+	p.expiresAt = now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
+
+func (p *GoogleDriveBackupProvider) do(req *http.Request) ([]byte, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error enviando petición: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error de Google: %d - %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+func (p *GoogleDriveBackupProvider) doJSON(req *http.Request, out interface{}) error {
+	body, err := p.do(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parseando respuesta: %v", err)
+	}
+	return nil
+}
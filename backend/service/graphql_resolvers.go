@@ -0,0 +1,479 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// graphBatch loads a field for every item of a list the first time one of the items asks for
+// it, so the renters of n rentals come in one query instead of n. The fields of a list are
+// resolved concurrently; the first one loads and the others wait for it.
+type graphBatch[V any] struct {
+	once  sync.Once
+	load  func(ctx context.Context) (V, error)
+	value V
+	err   error
+}
+
+func newGraphBatch[V any](load func(ctx context.Context) (V, error)) *graphBatch[V] {
+	return &graphBatch[V]{load: load}
+}
+
+func (b *graphBatch[V]) get(ctx context.Context) (V, error) {
+	b.once.Do(func() { b.value, b.err = b.load(ctx) })
+	return b.value, b.err
+}
+
+func graphID(id uuid.UUID) graphql.ID { return graphql.ID(id.String()) }
+
+func nullableGraphID(id string) *graphql.ID {
+	if id == "" || id == uuid.Nil.String() {
+		return nil
+	}
+	value := graphql.ID(id)
+	return &value
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// uniqueIDs returns the IDs of the items without duplicates nor nil UUIDs
+func uniqueIDs[T any](items []T, ids func(T) []uuid.UUID) []uuid.UUID {
+	var unique []uuid.UUID
+	seen := map[uuid.UUID]bool{uuid.Nil: true}
+	for _, item := range items {
+		for _, id := range ids(item) {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+	}
+	return unique
+}
+
+// personBatch fetches the persons with the IDs for a list, keyed by ID
+func (g *graphRequest) personBatch(ids []uuid.UUID) *graphBatch[map[uuid.UUID]*graphPerson] {
+	return newGraphBatch(func(ctx context.Context) (map[uuid.UUID]*graphPerson, error) {
+		persons, err := g.service.personRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch persons: %w", err)
+		}
+		byID := make(map[uuid.UUID]*graphPerson, len(persons))
+		for _, person := range g.persons(persons) {
+			byID[person.person.ID] = person
+		}
+		return byID, nil
+	})
+}
+
+// propertyBatch fetches the properties with the IDs for a list, keyed by ID
+func (g *graphRequest) propertyBatch(ids []uuid.UUID) *graphBatch[map[uuid.UUID]*graphProperty] {
+	return newGraphBatch(func(ctx context.Context) (map[uuid.UUID]*graphProperty, error) {
+		properties, err := g.service.propertyRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch properties: %w", err)
+		}
+		byID := make(map[uuid.UUID]*graphProperty, len(properties))
+		for _, property := range g.properties(properties) {
+			byID[property.property.ID] = property
+		}
+		return byID, nil
+	})
+}
+
+// rentalBatch fetches the rentals matching the keys for a list, grouped by the key of each
+// rental. The rentals the user cannot see are left out.
+func (g *graphRequest) rentalBatch(fetch func(ctx context.Context) ([]model.Rental, error), key func(model.Rental) uuid.UUID) *graphBatch[map[uuid.UUID][]*graphRental] {
+	return newGraphBatch(func(ctx context.Context) (map[uuid.UUID][]*graphRental, error) {
+		scope, err := g.scopeOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rentals, err := fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals: %w", err)
+		}
+		rentals = slices.DeleteFunc(rentals, func(r model.Rental) bool { return !scope.canSeeRental(r.ID) })
+
+		grouped := map[uuid.UUID][]*graphRental{}
+		for _, rental := range g.rentals(rentals) {
+			grouped[key(rental.rental)] = append(grouped[key(rental.rental)], rental)
+		}
+		return grouped, nil
+	})
+}
+
+// graphPerson resolves a Person
+type graphPerson struct {
+	g       *graphRequest
+	person  model.Person
+	rentals *graphBatch[map[uuid.UUID][]*graphRental]
+}
+
+// persons wraps the persons of a list, which share their batches
+func (g *graphRequest) persons(persons []model.Person) []*graphPerson {
+	ids := uniqueIDs(persons, func(p model.Person) []uuid.UUID { return []uuid.UUID{p.ID} })
+	rentals := g.rentalBatch(func(ctx context.Context) ([]model.Rental, error) {
+		return g.service.rentalRepo.GetByRenterIDs(ctx, ids)
+	}, func(r model.Rental) uuid.UUID { return r.RenterID })
+
+	resolvers := make([]*graphPerson, len(persons))
+	for i, person := range persons {
+		resolvers[i] = &graphPerson{g: g, person: person, rentals: rentals}
+	}
+	return resolvers
+}
+
+func (p *graphPerson) ID() graphql.ID   { return graphID(p.person.ID) }
+func (p *graphPerson) FullName() string { return p.person.FullName }
+func (p *graphPerson) Phone() string    { return p.person.Phone }
+func (p *graphPerson) Nit() string      { return p.person.NIT }
+
+func (p *graphPerson) Rentals(ctx context.Context) ([]*graphRental, error) {
+	byRenter, err := p.rentals.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return orEmpty(byRenter[p.person.ID]), nil
+}
+
+// graphProperty resolves a Property
+type graphProperty struct {
+	g           *graphRequest
+	property    model.Property
+	persons     *graphBatch[map[uuid.UUID]*graphPerson] // Residents and managers
+	rentals     *graphBatch[map[uuid.UUID][]*graphRental]
+	maintenance *graphBatch[map[string][]*graphMaintenance]
+}
+
+// properties wraps the properties of a list, which share their batches
+func (g *graphRequest) properties(properties []model.Property) []*graphProperty {
+	ids := model.PropertyIDs(properties)
+	persons := g.personBatch(uniqueIDs(properties, func(p model.Property) []uuid.UUID {
+		return append([]uuid.UUID{p.ResidentID}, p.ManagerIDs...)
+	}))
+	rentals := g.rentalBatch(func(ctx context.Context) ([]model.Rental, error) {
+		return g.service.rentalRepo.GetByPropertyIDs(ctx, ids)
+	}, func(r model.Rental) uuid.UUID { return r.PropertyID })
+	maintenance := newGraphBatch(func(ctx context.Context) (map[string][]*graphMaintenance, error) {
+		scope, err := g.scopeOf(ctx)
+		if err != nil {
+			return nil, err
+		}
+		propertyIDs := make([]string, len(ids))
+		for i, id := range ids {
+			propertyIDs[i] = id.String()
+		}
+		requests, err := g.service.maintenanceRepo.GetByPropertyIDs(propertyIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
+		}
+		requests = slices.DeleteFunc(requests, func(m storage.MaintenanceRequest) bool { return !scope.canSeeMaintenance(m) })
+
+		grouped := map[string][]*graphMaintenance{}
+		for _, request := range g.maintenanceRequests(requests) {
+			grouped[request.request.PropertyID] = append(grouped[request.request.PropertyID], request)
+		}
+		return grouped, nil
+	})
+
+	resolvers := make([]*graphProperty, len(properties))
+	for i, property := range properties {
+		resolvers[i] = &graphProperty{g: g, property: property, persons: persons, rentals: rentals, maintenance: maintenance}
+	}
+	return resolvers
+}
+
+func (p *graphProperty) ID() graphql.ID    { return graphID(p.property.ID) }
+func (p *graphProperty) Address() string   { return p.property.Address }
+func (p *graphProperty) AptNumber() string { return p.property.AptNumber }
+func (p *graphProperty) City() string      { return p.property.City }
+func (p *graphProperty) State() string     { return p.property.State }
+func (p *graphProperty) ZipCode() string   { return p.property.ZipCode }
+func (p *graphProperty) Type() string      { return p.property.Type }
+func (p *graphProperty) Bedrooms() int32   { return int32(p.property.Bedrooms) }
+func (p *graphProperty) Bathrooms() int32  { return int32(p.property.Bathrooms) }
+func (p *graphProperty) AreaM2() float64   { return p.property.AreaM2 }
+func (p *graphProperty) Estrato() int32    { return int32(p.property.Estrato) }
+func (p *graphProperty) Furnished() bool   { return p.property.Furnished }
+func (p *graphProperty) ResidentID() *graphql.ID {
+	return nullableGraphID(p.property.ResidentID.String())
+}
+
+func (p *graphProperty) Resident(ctx context.Context) (*graphPerson, error) {
+	if p.property.ResidentID == uuid.Nil {
+		return nil, nil
+	}
+	persons, err := p.persons.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return persons[p.property.ResidentID], nil
+}
+
+func (p *graphProperty) Managers(ctx context.Context) ([]*graphPerson, error) {
+	persons, err := p.persons.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	managers := []*graphPerson{}
+	for _, managerID := range p.property.ManagerIDs {
+		if manager := persons[managerID]; manager != nil {
+			managers = append(managers, manager)
+		}
+	}
+	return managers, nil
+}
+
+func (p *graphProperty) Rentals(ctx context.Context, args struct{ Active *bool }) ([]*graphRental, error) {
+	byProperty, err := p.rentals.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rentals := []*graphRental{}
+	for _, rental := range byProperty[p.property.ID] {
+		if args.Active == nil || p.g.isActiveRental(rental.rental) == *args.Active {
+			rentals = append(rentals, rental)
+		}
+	}
+	return rentals, nil
+}
+
+func (p *graphProperty) MaintenanceRequests(ctx context.Context, args struct{ Status *string }) ([]*graphMaintenance, error) {
+	byProperty, err := p.maintenance.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requests := []*graphMaintenance{}
+	for _, request := range byProperty[p.property.ID.String()] {
+		if args.Status == nil || request.request.Status == *args.Status {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// graphRental resolves a Rental
+type graphRental struct {
+	g          *graphRequest
+	rental     model.Rental
+	properties *graphBatch[map[uuid.UUID]*graphProperty]
+	renters    *graphBatch[map[uuid.UUID]*graphPerson]
+	payments   *graphBatch[map[string][]*graphPayment]
+}
+
+// rentals wraps the rentals of a list, which share their batches
+func (g *graphRequest) rentals(rentals []model.Rental) []*graphRental {
+	rentalIDs := make([]string, len(rentals))
+	for i, rental := range rentals {
+		rentalIDs[i] = rental.ID.String()
+	}
+	properties := g.propertyBatch(uniqueIDs(rentals, func(r model.Rental) []uuid.UUID { return []uuid.UUID{r.PropertyID} }))
+	renters := g.personBatch(uniqueIDs(rentals, func(r model.Rental) []uuid.UUID { return []uuid.UUID{r.RenterID} }))
+	payments := newGraphBatch(func(ctx context.Context) (map[string][]*graphPayment, error) {
+		payments, err := g.service.paymentRepo.GetByRentalIDs(rentalIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch payments: %w", err)
+		}
+		// Newest payment first, so lastPayment is the first of a rental
+		slices.SortStableFunc(payments, func(a, b storage.RentPayment) int {
+			return b.PaymentDate.Time().Compare(a.PaymentDate.Time())
+		})
+
+		grouped := map[string][]*graphPayment{}
+		for _, payment := range g.payments(payments) {
+			grouped[payment.payment.RentalID] = append(grouped[payment.payment.RentalID], payment)
+		}
+		return grouped, nil
+	})
+
+	resolvers := make([]*graphRental, len(rentals))
+	for i, rental := range rentals {
+		resolvers[i] = &graphRental{g: g, rental: rental, properties: properties, renters: renters, payments: payments}
+	}
+	return resolvers
+}
+
+func (r *graphRental) ID() graphql.ID         { return graphID(r.rental.ID) }
+func (r *graphRental) PropertyID() graphql.ID { return graphID(r.rental.PropertyID) }
+func (r *graphRental) RenterID() graphql.ID   { return graphID(r.rental.RenterID) }
+func (r *graphRental) StartDate() string      { return r.rental.StartDate.String() }
+func (r *graphRental) EndDate() string        { return r.rental.EndDate.String() }
+func (r *graphRental) PaymentTerms() string   { return r.rental.PaymentTerms }
+func (r *graphRental) UnpaidMonths() int32    { return int32(r.rental.UnpaidMonths) }
+func (r *graphRental) Active() bool           { return r.g.isActiveRental(r.rental) }
+
+func (r *graphRental) Property(ctx context.Context) (*graphProperty, error) {
+	properties, err := r.properties.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return properties[r.rental.PropertyID], nil
+}
+
+func (r *graphRental) Renter(ctx context.Context) (*graphPerson, error) {
+	renters, err := r.renters.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return renters[r.rental.RenterID], nil
+}
+
+func (r *graphRental) Payments(ctx context.Context) ([]*graphPayment, error) {
+	byRental, err := r.payments.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return orEmpty(byRental[r.rental.ID.String()]), nil
+}
+
+func (r *graphRental) LastPayment(ctx context.Context) (*graphPayment, error) {
+	payments, err := r.Payments(ctx)
+	if err != nil || len(payments) == 0 {
+		return nil, err
+	}
+	return payments[0], nil
+}
+
+// graphPayment resolves a Payment
+type graphPayment struct {
+	g       *graphRequest
+	payment storage.RentPayment
+	rentals *graphBatch[map[uuid.UUID]*graphRental]
+}
+
+// payments wraps the payments of a list, which share their batches
+func (g *graphRequest) payments(payments []storage.RentPayment) []*graphPayment {
+	var rentalIDs []uuid.UUID
+	for _, payment := range payments {
+		if id, err := uuid.Parse(payment.RentalID); err == nil && !slices.Contains(rentalIDs, id) {
+			rentalIDs = append(rentalIDs, id)
+		}
+	}
+	rentals := newGraphBatch(func(ctx context.Context) (map[uuid.UUID]*graphRental, error) {
+		rentals, err := g.service.rentalRepo.GetByIDs(ctx, rentalIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals: %w", err)
+		}
+		byID := make(map[uuid.UUID]*graphRental, len(rentals))
+		for _, rental := range g.rentals(rentals) {
+			byID[rental.rental.ID] = rental
+		}
+		return byID, nil
+	})
+
+	resolvers := make([]*graphPayment, len(payments))
+	for i, payment := range payments {
+		resolvers[i] = &graphPayment{g: g, payment: payment, rentals: rentals}
+	}
+	return resolvers
+}
+
+func (p *graphPayment) ID() graphql.ID         { return graphql.ID(p.payment.ID) }
+func (p *graphPayment) RentalID() graphql.ID   { return graphql.ID(p.payment.RentalID) }
+func (p *graphPayment) PaymentDate() string    { return p.payment.PaymentDate.String() }
+func (p *graphPayment) AmountPaid() float64    { return p.payment.AmountPaid }
+func (p *graphPayment) PaidOnTime() bool       { return p.payment.PaidOnTime }
+func (p *graphPayment) ReceiptNumber() *string { return nullableString(p.payment.ReceiptNumber) }
+func (p *graphPayment) InvoiceNumber() *string { return nullableString(p.payment.InvoiceNumber) }
+
+func (p *graphPayment) Rental(ctx context.Context) (*graphRental, error) {
+	id, err := uuid.Parse(p.payment.RentalID)
+	if err != nil {
+		return nil, nil
+	}
+	rentals, err := p.rentals.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rentals[id], nil
+}
+
+// graphMaintenance resolves a MaintenanceRequest
+type graphMaintenance struct {
+	g          *graphRequest
+	request    storage.MaintenanceRequest
+	properties *graphBatch[map[uuid.UUID]*graphProperty]
+	renters    *graphBatch[map[uuid.UUID]*graphPerson]
+}
+
+// maintenanceRequests wraps the maintenance requests of a list, which share their batches
+func (g *graphRequest) maintenanceRequests(requests []storage.MaintenanceRequest) []*graphMaintenance {
+	var propertyIDs, renterIDs []uuid.UUID
+	for _, request := range requests {
+		if id, err := uuid.Parse(request.PropertyID); err == nil && !slices.Contains(propertyIDs, id) {
+			propertyIDs = append(propertyIDs, id)
+		}
+		if id, err := uuid.Parse(request.RenterID); err == nil && !slices.Contains(renterIDs, id) {
+			renterIDs = append(renterIDs, id)
+		}
+	}
+	properties := g.propertyBatch(propertyIDs)
+	renters := g.personBatch(renterIDs)
+
+	resolvers := make([]*graphMaintenance, len(requests))
+	for i, request := range requests {
+		resolvers[i] = &graphMaintenance{g: g, request: request, properties: properties, renters: renters}
+	}
+	return resolvers
+}
+
+func (m *graphMaintenance) ID() graphql.ID         { return graphql.ID(m.request.ID) }
+func (m *graphMaintenance) PropertyID() graphql.ID { return graphql.ID(m.request.PropertyID) }
+func (m *graphMaintenance) RenterID() *graphql.ID  { return nullableGraphID(m.request.RenterID) }
+func (m *graphMaintenance) Description() string    { return m.request.Description }
+func (m *graphMaintenance) RequestDate() string    { return m.request.RequestDate.String() }
+func (m *graphMaintenance) Status() string         { return m.request.Status }
+
+func (m *graphMaintenance) Property(ctx context.Context) (*graphProperty, error) {
+	id, err := uuid.Parse(m.request.PropertyID)
+	if err != nil {
+		return nil, nil
+	}
+	scope, err := m.g.scopeOf(ctx)
+	if err != nil || !scope.canSeeProperty(id) {
+		return nil, err
+	}
+	properties, err := m.properties.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return properties[id], nil
+}
+
+func (m *graphMaintenance) Renter(ctx context.Context) (*graphPerson, error) {
+	id, err := uuid.Parse(m.request.RenterID)
+	if err != nil {
+		return nil, nil
+	}
+	scope, err := m.g.scopeOf(ctx)
+	if err != nil || !scope.canSeePerson(id) {
+		return nil, err
+	}
+	renters, err := m.renters.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return renters[id], nil
+}
+
+// orEmpty returns an empty list for a nil one, the lists of the schema are never null
+func orEmpty[T any](items []*T) []*T {
+	if items == nil {
+		return []*T{}
+	}
+	return items
+}
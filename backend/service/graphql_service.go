@@ -0,0 +1,504 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+//go:embed graphql_schema.graphqls
+var graphQLSchema string
+
+const (
+	// graphQLMaxDepth bounds the nesting of the queries, deep enough for
+	// property → rentals → renter → rentals → lastPayment
+	graphQLMaxDepth = 8
+	// graphQLMaxLimit bounds the pages of the lists of Query
+	graphQLMaxLimit = 100
+)
+
+// GraphQLService runs the read-only GraphQL queries of the dashboard. Nothing is loaded up
+// front: each field queries what it needs when it is selected, and the fields of the items of a
+// list are fetched for the whole list at once, so rentals { renter } costs two queries whatever
+// the number of rentals.
+type GraphQLService struct {
+	personRepo      storage.PersonStore
+	propertyRepo    storage.PropertyStore
+	rentalRepo      storage.RentalStore
+	paymentRepo     storage.RentPaymentStore
+	maintenanceRepo storage.MaintenanceRequestStore
+	orgService      *OrganizationService
+	schema          *graphql.Schema
+}
+
+// GraphQLRequest is a GraphQL query with its operation name and variables
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the result of a query. Data is null when the query could not be executed.
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+// GraphQLError is an error of a query, located in the query and, for the errors of a field, in
+// the result
+type GraphQLError struct {
+	Message   string               `json:"message"`
+	Locations []gqlerrors.Location `json:"locations,omitempty"`
+	Path      []any                `json:"path,omitempty"`
+}
+
+// NewGraphQLService creates a new GraphQLService
+func NewGraphQLService(repoFactory *storage.RepositoryFactory, orgService *OrganizationService) *GraphQLService {
+	return &GraphQLService{
+		personRepo:      repoFactory.GetPersonRepository(),
+		propertyRepo:    repoFactory.GetPropertyRepository(),
+		rentalRepo:      repoFactory.GetRentalRepository(),
+		paymentRepo:     repoFactory.GetRentPaymentRepository(),
+		maintenanceRepo: repoFactory.GetMaintenanceRequestRepository(),
+		orgService:      orgService,
+		schema: graphql.MustParseSchema(graphQLSchema, &graphQuery{},
+			graphql.UseStringDescriptions(), graphql.MaxDepth(graphQLMaxDepth)),
+	}
+}
+
+// Execute runs a query with the scope of the user: admins see everything, managers the
+// properties they manage with their rentals and renters, and the other users their own
+// rentals and the properties they live in. Rentals are active on the days of the timezone of
+// the organization of the user.
+func (s *GraphQLService) Execute(ctx context.Context, user *model.User, request GraphQLRequest) *GraphQLResponse {
+	var org *model.Organization
+	if s.orgService != nil {
+		org = s.orgService.ForPerson(ctx, user.PersonID)
+	}
+	g := &graphRequest{service: s, user: user, location: OrganizationLocation(org)}
+	result := s.schema.Exec(context.WithValue(ctx, graphRequestKey{}, g), request.Query, request.OperationName, request.Variables)
+
+	response := &GraphQLResponse{Data: result.Data}
+	for _, err := range result.Errors {
+		response.Errors = append(response.Errors, GraphQLError{Message: err.Message, Locations: err.Locations, Path: err.Path})
+	}
+	return response
+}
+
+type graphRequestKey struct{}
+
+// graphRequest is the state of one query: the user and, for the users who are not admins, the
+// records they can see, loaded at the first field that checks them
+type graphRequest struct {
+	service  *GraphQLService
+	user     *model.User
+	location *time.Location
+
+	scopeOnce sync.Once
+	scope     *graphScope
+	scopeErr  error
+}
+
+func graphRequestOf(ctx context.Context) *graphRequest {
+	return ctx.Value(graphRequestKey{}).(*graphRequest)
+}
+
+// graphScope holds the IDs a user who is not an admin can see. It is bounded by the records of
+// the user, never the whole database. A nil scope, the one of the admins, sees everything.
+type graphScope struct {
+	properties []model.Property
+	rentals    []model.Rental
+
+	propertyIDs map[uuid.UUID]bool
+	rentalIDs   map[uuid.UUID]bool
+	personIDs   map[uuid.UUID]bool
+
+	personID           uuid.UUID
+	managesMaintenance bool // Managers see the maintenance requests of their properties, the others only theirs
+}
+
+func (s *graphScope) canSeeProperty(id uuid.UUID) bool { return s == nil || s.propertyIDs[id] }
+func (s *graphScope) canSeeRental(id uuid.UUID) bool   { return s == nil || s.rentalIDs[id] }
+func (s *graphScope) canSeePerson(id uuid.UUID) bool   { return s == nil || s.personIDs[id] }
+
+func (s *graphScope) canSeeMaintenance(request storage.MaintenanceRequest) bool {
+	if s == nil {
+		return true
+	}
+	if s.managesMaintenance {
+		propertyID, err := uuid.Parse(request.PropertyID)
+		return err == nil && s.propertyIDs[propertyID]
+	}
+	return s.personID != uuid.Nil && request.RenterID == s.personID.String()
+}
+
+// scopeOf returns the scope of the user, nil for admins
+func (g *graphRequest) scopeOf(ctx context.Context) (*graphScope, error) {
+	if g.user.Role == "admin" {
+		return nil, nil
+	}
+	g.scopeOnce.Do(func() { g.scope, g.scopeErr = g.loadScope(ctx) })
+	return g.scope, g.scopeErr
+}
+
+func (g *graphRequest) loadScope(ctx context.Context) (*graphScope, error) {
+	s := g.service
+	scope := &graphScope{personID: g.user.PersonID, managesMaintenance: g.user.Role == "manager"}
+	var err error
+
+	switch {
+	case g.user.Role == "manager":
+		if scope.properties, err = s.propertyRepo.GetPropertiesForManager(ctx, g.user.PersonID); err != nil {
+			return nil, fmt.Errorf("failed to fetch properties of manager %s: %w", g.user.PersonID, err)
+		}
+		if scope.rentals, err = s.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(scope.properties)); err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals of manager %s: %w", g.user.PersonID, err)
+		}
+	case g.user.PersonID != uuid.Nil:
+		if scope.properties, err = s.propertyRepo.GetByResident(ctx, g.user.PersonID); err != nil {
+			return nil, fmt.Errorf("failed to fetch properties of resident %s: %w", g.user.PersonID, err)
+		}
+		if scope.rentals, err = s.rentalRepo.GetByRenterID(ctx, g.user.PersonID); err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals of renter %s: %w", g.user.PersonID, err)
+		}
+		// The properties of the rentals the user does not live in, in one query
+		var missing []uuid.UUID
+		for _, rental := range scope.rentals {
+			if !slices.ContainsFunc(scope.properties, func(p model.Property) bool { return p.ID == rental.PropertyID }) &&
+				!slices.Contains(missing, rental.PropertyID) {
+				missing = append(missing, rental.PropertyID)
+			}
+		}
+		rented, err := s.propertyRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch properties of renter %s: %w", g.user.PersonID, err)
+		}
+		scope.properties = append(scope.properties, rented...)
+	}
+
+	// The persons related to the scope: the user, the renters, the residents and the managers
+	scope.propertyIDs = make(map[uuid.UUID]bool, len(scope.properties))
+	scope.rentalIDs = make(map[uuid.UUID]bool, len(scope.rentals))
+	scope.personIDs = map[uuid.UUID]bool{}
+	if g.user.PersonID != uuid.Nil {
+		scope.personIDs[g.user.PersonID] = true
+	}
+	for _, property := range scope.properties {
+		scope.propertyIDs[property.ID] = true
+		if property.ResidentID != uuid.Nil {
+			scope.personIDs[property.ResidentID] = true
+		}
+		for _, managerID := range property.ManagerIDs {
+			scope.personIDs[managerID] = true
+		}
+	}
+	for _, rental := range scope.rentals {
+		scope.rentalIDs[rental.ID] = true
+		scope.personIDs[rental.RenterID] = true
+	}
+	return scope, nil
+}
+
+// graphQuery resolves the fields of Query. The state of the request comes in the context.
+type graphQuery struct{}
+
+// pageOptions checks the limit and offset of a list
+func pageOptions(limit, offset int32) (storage.ListOptions, error) {
+	if limit < 1 || limit > graphQLMaxLimit {
+		return storage.ListOptions{}, fmt.Errorf("limit must be between 1 and %d", graphQLMaxLimit)
+	}
+	if offset < 0 {
+		return storage.ListOptions{}, fmt.Errorf("offset cannot be negative")
+	}
+	return storage.ListOptions{Limit: int(limit), Offset: int(offset)}, nil
+}
+
+// page returns the page of a list scoped in memory
+func page[T any](items []T, opts storage.ListOptions) []T {
+	if opts.Offset >= len(items) {
+		return []T{}
+	}
+	return items[opts.Offset:min(opts.Offset+opts.Limit, len(items))]
+}
+
+func parseGraphID(id graphql.ID) (uuid.UUID, bool) {
+	parsed, err := uuid.Parse(string(id))
+	return parsed, err == nil
+}
+
+func (q *graphQuery) Me(ctx context.Context) (*graphPerson, error) {
+	g := graphRequestOf(ctx)
+	return g.person(ctx, g.user.PersonID)
+}
+
+func (q *graphQuery) Persons(ctx context.Context, args struct{ Limit, Offset int32 }) ([]*graphPerson, error) {
+	g := graphRequestOf(ctx)
+	opts, err := pageOptions(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope == nil {
+		persons, _, err := g.service.personRepo.List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch persons: %w", err)
+		}
+		return g.persons(persons), nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(scope.personIDs))
+	for id := range scope.personIDs {
+		ids = append(ids, id)
+	}
+	persons, err := g.service.personRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch persons: %w", err)
+	}
+	slices.SortFunc(persons, func(a, b model.Person) int {
+		return cmp.Or(cmp.Compare(a.FullName, b.FullName), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return g.persons(page(persons, opts)), nil
+}
+
+func (q *graphQuery) Person(ctx context.Context, args struct{ ID graphql.ID }) (*graphPerson, error) {
+	g := graphRequestOf(ctx)
+	id, ok := parseGraphID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil || !scope.canSeePerson(id) {
+		return nil, err
+	}
+	return g.person(ctx, id)
+}
+
+func (q *graphQuery) Properties(ctx context.Context, args struct {
+	City          *string
+	Limit, Offset int32
+}) ([]*graphProperty, error) {
+	g := graphRequestOf(ctx)
+	opts, err := pageOptions(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter storage.PropertyFilter
+	if args.City != nil {
+		filter.City = *args.City
+	}
+	if scope == nil {
+		properties, _, err := g.service.propertyRepo.List(ctx, filter, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch properties: %w", err)
+		}
+		return g.properties(properties), nil
+	}
+
+	properties := filter.Filter(scope.properties)
+	slices.SortFunc(properties, func(a, b model.Property) int {
+		return cmp.Or(cmp.Compare(a.Address, b.Address), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return g.properties(page(properties, opts)), nil
+}
+
+func (q *graphQuery) Property(ctx context.Context, args struct{ ID graphql.ID }) (*graphProperty, error) {
+	g := graphRequestOf(ctx)
+	id, ok := parseGraphID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil || !scope.canSeeProperty(id) {
+		return nil, err
+	}
+
+	property, err := g.service.propertyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch property %s: %w", id, err)
+	}
+	if property == nil {
+		return nil, nil
+	}
+	return g.properties([]model.Property{*property})[0], nil
+}
+
+func (q *graphQuery) Rentals(ctx context.Context, args struct {
+	Active        *bool
+	Limit, Offset int32
+}) ([]*graphRental, error) {
+	g := graphRequestOf(ctx)
+	opts, err := pageOptions(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope == nil {
+		filter := storage.RentalFilter{Active: args.Active, Today: calendarDayIn(time.Now(), g.location)}
+		rentals, _, err := g.service.rentalRepo.List(ctx, filter, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch rentals: %w", err)
+		}
+		return g.rentals(rentals), nil
+	}
+
+	rentals := slices.DeleteFunc(slices.Clone(scope.rentals), func(r model.Rental) bool {
+		return args.Active != nil && g.isActiveRental(r) != *args.Active
+	})
+	slices.SortFunc(rentals, func(a, b model.Rental) int {
+		return cmp.Or(a.StartDate.Time().Compare(b.StartDate.Time()), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return g.rentals(page(rentals, opts)), nil
+}
+
+func (q *graphQuery) Rental(ctx context.Context, args struct{ ID graphql.ID }) (*graphRental, error) {
+	g := graphRequestOf(ctx)
+	id, ok := parseGraphID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil || !scope.canSeeRental(id) {
+		return nil, err
+	}
+
+	rental, err := g.service.rentalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rental %s: %w", id, err)
+	}
+	if rental == nil {
+		return nil, nil
+	}
+	return g.rentals([]model.Rental{*rental})[0], nil
+}
+
+func (q *graphQuery) Payments(ctx context.Context, args struct {
+	RentalID      *graphql.ID
+	Limit, Offset int32
+}) ([]*graphPayment, error) {
+	g := graphRequestOf(ctx)
+	opts, err := pageOptions(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	opts.Descending = true // Newest first
+	scope, err := g.scopeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter storage.RentPaymentFilter
+	switch {
+	case args.RentalID != nil:
+		rentalID, ok := parseGraphID(*args.RentalID)
+		if !ok || !scope.canSeeRental(rentalID) {
+			return []*graphPayment{}, nil
+		}
+		filter.RentalIDs = []string{rentalID.String()}
+	case scope != nil:
+		filter.RentalIDs = []string{}
+		for id := range scope.rentalIDs {
+			filter.RentalIDs = append(filter.RentalIDs, id.String())
+		}
+	}
+
+	payments, _, err := g.service.paymentRepo.List(filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+	return g.payments(payments), nil
+}
+
+func (q *graphQuery) MaintenanceRequests(ctx context.Context, args struct {
+	Status        *string
+	Limit, Offset int32
+}) ([]*graphMaintenance, error) {
+	g := graphRequestOf(ctx)
+	opts, err := pageOptions(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := g.scopeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope == nil {
+		if args.Status != nil {
+			opts.Filters = map[string]string{"status": *args.Status}
+		}
+		requests, _, err := g.service.maintenanceRepo.List(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
+		}
+		return g.maintenanceRequests(requests), nil
+	}
+
+	var requests []storage.MaintenanceRequest
+	switch {
+	case scope.managesMaintenance:
+		propertyIDs := make([]string, 0, len(scope.propertyIDs))
+		for id := range scope.propertyIDs {
+			propertyIDs = append(propertyIDs, id.String())
+		}
+		if len(propertyIDs) > 0 {
+			requests, err = g.service.maintenanceRepo.GetByPropertyIDs(propertyIDs)
+		}
+	case scope.personID != uuid.Nil:
+		requests, err = g.service.maintenanceRepo.GetByRenterID(scope.personID.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
+	}
+
+	requests = slices.DeleteFunc(requests, func(m storage.MaintenanceRequest) bool {
+		return args.Status != nil && m.Status != *args.Status
+	})
+	slices.SortFunc(requests, func(a, b storage.MaintenanceRequest) int {
+		return cmp.Or(a.RequestDate.Time().Compare(b.RequestDate.Time()), cmp.Compare(a.ID, b.ID))
+	})
+	return g.maintenanceRequests(page(requests, opts)), nil
+}
+
+// person fetches a person on its own, nil when it does not exist
+func (g *graphRequest) person(ctx context.Context, id uuid.UUID) (*graphPerson, error) {
+	if id == uuid.Nil {
+		return nil, nil
+	}
+	person, err := g.service.personRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch person %s: %w", id, err)
+	}
+	if person == nil {
+		return nil, nil
+	}
+	return g.persons([]model.Person{*person})[0], nil
+}
+
+func (g *graphRequest) isActiveRental(rental model.Rental) bool {
+	return OccupancyStatus(rental.StartDate.Time(), rental.EndDate.Time(), time.Now(), g.location) == OccupancyActive
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// codeudorMentions are references to the deudor solidario without placeholders in the catalog
+// templates, removed when a guarantee policy substitutes it
+var codeudorMentions = strings.NewReplacer(
+	" y del Deudor Solidario", "",
+	" y del deudor solidario", "",
+)
+
+// WithGuaranteePolicy adapts a template to a contract guaranteed by an afianzadora policy: the
+// clause of the deudor solidario is replaced by the policy clause, sentences referring to the
+// codeudor placeholders are removed and the codeudor does not sign
+func WithGuaranteePolicy(template model.ContractTemplate, study model.GuaranteeStudy, monthlyRent float64) model.ContractTemplate {
+	policyClause := model.ContractClause{
+		Title: "GARANTÍA MEDIANTE PÓLIZA DE ARRENDAMIENTO",
+		Body:  guaranteeClauseText(study, monthlyRent),
+	}
+
+	clauses := make([]model.ContractClause, 0, len(template.Clauses)+1)
+	replaced := false
+	for _, clause := range template.Clauses {
+		if referencesPlaceholder(clause.Body, "codeudor") {
+			if !replaced {
+				clauses = append(clauses, policyClause)
+				replaced = true
+			}
+			continue
+		}
+		clause.Body = withoutCodeudorSentences(clause.Body)
+		clauses = append(clauses, clause)
+	}
+	if !replaced {
+		clauses = append(clauses, policyClause)
+	}
+
+	blocks := make([]model.ContractSignatureBlock, 0, len(template.SignatureBlocks))
+	for _, block := range contractSignatureBlocks(template) {
+		if block.Party != model.ContractPartyCodeudor {
+			blocks = append(blocks, block)
+		}
+	}
+
+	template.Clauses = clauses
+	template.SignatureBlocks = blocks
+	return template
+}
+
+// guaranteeClauseText returns the clause of the policy substituting the deudor solidario
+func guaranteeClauseText(study model.GuaranteeStudy, monthlyRent float64) string {
+	company := study.Company
+	if company == "" {
+		company = "la compañía afianzadora"
+	}
+
+	rent := ""
+	if monthlyRent > 0 {
+		rent = fmt.Sprintf(" de %s", FormatMoney(monthlyRent))
+	}
+
+	return fmt.Sprintf("Las obligaciones del ARRENDATARIO derivadas del presente contrato se encuentran garantizadas mediante la póliza de arrendamiento No. %s expedida por %s, la cual sustituye al deudor solidario. La póliza ampara el pago del canon mensual%s y sus reajustes durante la vigencia del contrato, sus prórrogas y renovaciones, en los términos y con las exclusiones de sus condiciones generales. El ARRENDATARIO autoriza al ARRENDADOR para suministrar a %s la información relativa a la ejecución de este contrato y al cumplimiento de sus obligaciones.",
+		study.PolicyNumber, company, rent, company)
+}
+
+// referencesPlaceholder reports whether text has the {{name}} placeholder
+func referencesPlaceholder(text, name string) bool {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		if match[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutCodeudorSentences removes the sentences of a clause referring to the deudor solidario
+func withoutCodeudorSentences(body string) string {
+	sentences := strings.Split(body, ". ")
+	kept := make([]string, 0, len(sentences))
+	for _, sentence := range sentences {
+		hasCodeudor := false
+		for _, match := range placeholderPattern.FindAllStringSubmatch(sentence, -1) {
+			if strings.HasPrefix(match[1], "codeudor") {
+				hasCodeudor = true
+				break
+			}
+		}
+		if !hasCodeudor {
+			kept = append(kept, sentence)
+		}
+	}
+
+	result := strings.Join(kept, ". ")
+	if len(kept) < len(sentences) && result != "" && !strings.HasSuffix(result, ".") {
+		result += "."
+	}
+	return codeudorMentions.Replace(result)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// GuaranteeStudyRequest holds the tenant data an afianzadora needs to study a rental
+type GuaranteeStudyRequest struct {
+	StudyID         string // Our guarantee_study ID, sent as external reference
+	Applicant       *model.Person
+	ApplicantEmail  string
+	PropertyAddress string
+	PropertyCity    string
+	MonthlyRent     float64
+	TermMonths      int
+}
+
+// GuaranteeStudyResult is the state of a study on the afianzadora
+type GuaranteeStudyResult struct {
+	ExternalID   string
+	Status       string // Normalized to one of the model.GuaranteeStatus constants
+	PolicyNumber string
+	Observations string
+}
+
+// GuaranteeProvider submits tenants to a guarantee company (afianzadora) for approval
+type GuaranteeProvider interface {
+	// Name returns the identifier stored with the studies
+	Name() string
+	// Company returns the name of the afianzadora printed in the contracts
+	Company() string
+	// SubmitStudy sends the tenant data for approval. Most afianzadoras answer later, the
+	// result is then pending.
+	SubmitStudy(ctx context.Context, req GuaranteeStudyRequest) (*GuaranteeStudyResult, error)
+	// GetStudy fetches the current result of a submitted study
+	GetStudy(ctx context.Context, externalID string) (*GuaranteeStudyResult, error)
+}
+
+// GetGuaranteeProvider returns the afianzadora integration configured in cfg
+func GetGuaranteeProvider(cfg config.GuaranteeConfig) (GuaranteeProvider, error) {
+	return NewAfianzadoraProvider(cfg)
+}
+
+// AfianzadoraProvider implements GuaranteeProvider for afianzadoras exposing a JSON study API
+// (POST /studies, GET /studies/:id) authenticated with a bearer token
+type AfianzadoraProvider struct {
+	apiURL     string
+	apiToken   string
+	company    string
+	httpClient *http.Client
+}
+
+// NewAfianzadoraProvider creates an afianzadora provider from the AFIANZADORA_* settings
+func NewAfianzadoraProvider(cfg config.GuaranteeConfig) (*AfianzadoraProvider, error) {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		return nil, errors.New("AFIANZADORA_API_URL is not configured")
+	}
+
+	apiToken := cfg.APIToken
+	if apiToken == "" {
+		return nil, errors.New("AFIANZADORA_API_TOKEN is not configured")
+	}
+
+	company := cfg.Name
+	if company == "" {
+		company = "la compañía afianzadora"
+	}
+
+	return &AfianzadoraProvider{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		apiToken:   apiToken,
+		company:    company,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *AfianzadoraProvider) Name() string {
+	return "afianzadora"
+}
+
+// Company returns the name of the afianzadora
+func (p *AfianzadoraProvider) Company() string {
+	return p.company
+}
+
+type afianzadoraApplicant struct {
+	Name     string `json:"name"`
+	Document string `json:"document"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+}
+
+type afianzadoraStudy struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	PolicyNumber string `json:"policy_number"`
+	Observations string `json:"observations"`
+}
+
+// SubmitStudy creates a study for the applicant
+func (p *AfianzadoraProvider) SubmitStudy(ctx context.Context, req GuaranteeStudyRequest) (*GuaranteeStudyResult, error) {
+	if req.Applicant == nil {
+		return nil, errors.New("the applicant is required")
+	}
+
+	payload := map[string]interface{}{
+		"external_reference": req.StudyID,
+		"applicant": afianzadoraApplicant{
+			Name:     req.Applicant.FullName,
+			Document: req.Applicant.NIT,
+			Email:    req.ApplicantEmail,
+			Phone:    req.Applicant.Phone,
+		},
+		"property": map[string]string{
+			"address": req.PropertyAddress,
+			"city":    req.PropertyCity,
+		},
+		"monthly_rent": req.MonthlyRent,
+		"term_months":  req.TermMonths,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding afianzadora request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/studies", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating afianzadora request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	var study afianzadoraStudy
+	if err := p.do(httpReq, &study); err != nil {
+		return nil, err
+	}
+	if study.ID == "" {
+		return nil, errors.New("afianzadora response without study ID")
+	}
+	return study.result(), nil
+}
+
+// GetStudy fetches the result of a study
+func (p *AfianzadoraProvider) GetStudy(ctx context.Context, externalID string) (*GuaranteeStudyResult, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/studies/"+externalID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating afianzadora request: %w", err)
+	}
+
+	var study afianzadoraStudy
+	if err := p.do(httpReq, &study); err != nil {
+		return nil, err
+	}
+	if study.ID == "" {
+		study.ID = externalID
+	}
+	return study.result(), nil
+}
+
+// result normalizes the study status, afianzadoras answer in Spanish or English
+func (s afianzadoraStudy) result() *GuaranteeStudyResult {
+	status := model.GuaranteeStatusPending
+	switch strings.ToLower(s.Status) {
+	case "approved", "aprobado", "aprobada":
+		status = model.GuaranteeStatusApproved
+	case "rejected", "negado", "negada", "rechazado", "rechazada":
+		status = model.GuaranteeStatusRejected
+	}
+
+	return &GuaranteeStudyResult{
+		ExternalID:   s.ID,
+		Status:       status,
+		PolicyNumber: s.PolicyNumber,
+		Observations: s.Observations,
+	}
+}
+
+// do sends an authenticated request and decodes the JSON response
+func (p *AfianzadoraProvider) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling afianzadora: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading afianzadora response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("afianzadora API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error parsing afianzadora response: %w", err)
+	}
+	return nil
+}
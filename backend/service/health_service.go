@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// Statuses of a dependency in the health report
+const (
+	DependencyUp       = "up"
+	DependencyDown     = "down"
+	DependencyDisabled = "disabled" // Not configured in this deployment
+)
+
+// Overall statuses of the health report
+const (
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"    // An optional dependency is down
+	HealthUnavailable = "unavailable" // A critical dependency is down, the API cannot serve requests
+)
+
+const (
+	// healthCheckTimeout bounds each dependency check, they run in parallel
+	healthCheckTimeout = 3 * time.Second
+	// healthCacheTTL keeps the last report so frequent probes do not log in to SMTP or call
+	// Telegram every time
+	healthCacheTTL = 15 * time.Second
+)
+
+// DependencyHealth is the result of checking a dependency
+type DependencyHealth struct {
+	Status    string `json:"status"`           // One of the Dependency constants
+	Critical  bool   `json:"critical"`         // The API is not ready when it is down
+	Detail    string `json:"detail,omitempty"` // Backend or driver in use
+	Error     string `json:"error,omitempty"`  // Why it is down
+	LatencyMS int64  `json:"latency_ms"`       // Duration of the check
+}
+
+// HealthReport is the status of the API and of each of its dependencies
+type HealthReport struct {
+	Status    string                      `json:"status"` // One of the Health constants
+	Profile   string                      `json:"profile"`
+	CheckedAt time.Time                   `json:"checked_at"`
+	Checks    map[string]DependencyHealth `json:"checks"`
+}
+
+// Ready reports whether every critical dependency is up
+func (r *HealthReport) Ready() bool {
+	return r.Status != HealthUnavailable
+}
+
+// dependencyCheck checks a dependency. It returns disabled when the dependency is not
+// configured and the detail shown in the report.
+type dependencyCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) (status, detail string, err error)
+}
+
+// HealthService checks the database, the file storage, the email driver and Telegram for the
+// health and readiness endpoints
+type HealthService struct {
+	checks  []dependencyCheck
+	profile string
+
+	mu     sync.Mutex
+	last   *HealthReport
+	expiry time.Time
+}
+
+// NewHealthService creates the health checks of the dependencies of the API, reported with the
+// environment profile the server runs with
+func NewHealthService(client *supa.Client, profile string) *HealthService {
+	return &HealthService{profile: orDefault(profile, config.DefaultProfile), checks: []dependencyCheck{
+		{name: "database", critical: true, check: func(ctx context.Context) (string, string, error) {
+			return DependencyUp, "supabase", runWithContext(ctx, func() error {
+				_, _, err := client.From("users").Select("id", "", false).Limit(1, "").Execute()
+				return err
+			})
+		}},
+		{name: "storage", critical: true, check: func(ctx context.Context) (string, string, error) {
+			files := GetSupabaseStorageService()
+			if files == nil {
+				return DependencyDisabled, "", nil
+			}
+			return DependencyUp, files.Backend(), runWithContext(ctx, files.CheckAccess)
+		}},
+		{name: "email", check: func(ctx context.Context) (string, string, error) {
+			sender := GetEmailSender()
+			if EmailSandboxDir() != "" {
+				return DependencyDisabled, "sandbox", nil
+			}
+			return DependencyUp, sender.Name(), sender.HealthCheck(ctx)
+		}},
+		{name: "telegram", check: func(ctx context.Context) (string, string, error) {
+			if !IsTelegramEnabled() {
+				return DependencyDisabled, "", nil
+			}
+			telegram := GetTelegramService()
+			if telegram == nil {
+				return DependencyDown, "", fmt.Errorf("the Telegram service failed to initialize")
+			}
+			return DependencyUp, "", telegram.HealthCheck(ctx)
+		}},
+	}}
+}
+
+// Check runs the dependency checks, or returns the last report while it is recent. The report
+// is shared by the callers, so the checks do not stop when the request that ran them is canceled.
+func (s *HealthService) Check(ctx context.Context) *HealthReport {
+	ctx = context.WithoutCancel(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.last != nil && now.Before(s.expiry) {
+		return s.last
+	}
+
+	report := &HealthReport{
+		Status:    HealthOK,
+		Profile:   s.profile,
+		CheckedAt: now,
+		Checks:    make(map[string]DependencyHealth, len(s.checks)),
+	}
+	results := make([]DependencyHealth, len(s.checks))
+	var wg sync.WaitGroup
+	for i, dependency := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runDependencyCheck(ctx, dependency)
+		}()
+	}
+	wg.Wait()
+
+	for i, dependency := range s.checks {
+		result := results[i]
+		report.Checks[dependency.name] = result
+		if result.Status != DependencyDown {
+			continue
+		}
+		if result.Critical {
+			report.Status = HealthUnavailable
+		} else if report.Status == HealthOK {
+			report.Status = HealthDegraded
+		}
+	}
+
+	s.last = report
+	s.expiry = now.Add(healthCacheTTL)
+	return report
+}
+
+// runDependencyCheck runs a check with its timeout and measures it
+func runDependencyCheck(ctx context.Context, dependency dependencyCheck) DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, detail, err := dependency.check(ctx)
+	result := DependencyHealth{
+		Status:    status,
+		Critical:  dependency.critical,
+		Detail:    detail,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = DependencyDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// runWithContext runs a call that takes no context and gives up when ctx is done; the call
+// keeps running in the background until it returns
+func runWithContext(ctx context.Context, call func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", healthCheckTimeout)
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// GenerateMissingSecrets replaces the unset secrets the tokens of a feature (emailed codes, links
+// of shares, invitations, password resets...) are signed with by random keys. The prod profile
+// requires every secret; elsewhere the links already sent stop working after a restart.
+func GenerateMissingSecrets(secrets *config.SecretsConfig) error {
+	for _, secret := range []struct {
+		name  string
+		value *string
+	}{
+		{"SIGNING_OTP_SECRET", &secrets.SigningOTP},
+		{"FILE_SHARE_SECRET", &secrets.FileShare},
+		{"NOTIFICATION_TOKEN_SECRET", &secrets.NotificationToken},
+		{"PASSWORD_RESET_SECRET", &secrets.PasswordReset},
+		{"EMAIL_VERIFICATION_SECRET", &secrets.EmailVerification},
+		{"INVITATION_SECRET", &secrets.Invitation},
+	} {
+		if *secret.value != "" {
+			continue
+		}
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("generating a key for %s: %w", secret.name, err)
+		}
+		*secret.value = string(key)
+		slog.Warn("secret not configured, signing with a random key: the links and codes sent stop working after a restart", "name", secret.name)
+	}
+	return nil
+}
@@ -0,0 +1,626 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// Results of comparing an item between the move-in and move-out inspections
+const (
+	InspectionChangeUnchanged = "unchanged"
+	InspectionChangeImproved  = "improved"
+	InspectionChangeWorsened  = "worsened" // Returned in a worse condition than delivered
+	InspectionChangeMissing   = "missing"  // Delivered but not returned, or fewer units returned
+	InspectionChangeAdded     = "added"    // Only found at move-out
+)
+
+// inspectionChangeLabels are the labels printed in the comparison report
+var inspectionChangeLabels = map[string]string{
+	InspectionChangeUnchanged: "Sin cambios",
+	InspectionChangeImproved:  "Mejoró",
+	InspectionChangeWorsened:  "Deterioro",
+	InspectionChangeMissing:   "Faltante",
+	InspectionChangeAdded:     "Nuevo",
+}
+
+// InspectionPDF holds the data printed on an inspection
+type InspectionPDF struct {
+	Inspection  model.Inspection
+	Rental      *model.Rental
+	Property    *model.Property
+	Tenant      *model.Person
+	TenantEmail string
+	Owner       *model.Person // Holder of the rent account
+	Inspector   *model.Person
+}
+
+// InspectionItemComparison is the condition of an item at move-in and at move-out
+type InspectionItemComparison struct {
+	Room             string                 `json:"room"`
+	Item             string                 `json:"item"`
+	MoveInQuantity   int                    `json:"move_in_quantity"`
+	MoveOutQuantity  int                    `json:"move_out_quantity"`
+	MoveInCondition  string                 `json:"move_in_condition,omitempty"`
+	MoveOutCondition string                 `json:"move_out_condition,omitempty"`
+	MoveInNotes      string                 `json:"move_in_notes,omitempty"`
+	MoveOutNotes     string                 `json:"move_out_notes,omitempty"`
+	MoveOutPhotos    []model.InventoryPhoto `json:"move_out_photos,omitempty"`
+	Change           string                 `json:"change"` // One of the InspectionChange constants
+}
+
+// InspectionComparison compares the move-out inspection of a rental with its move-in
+// inspection, justifying the deductions from the security deposit
+type InspectionComparison struct {
+	RentalID      uuid.UUID                  `json:"rental_id"`
+	MoveIn        *model.Inspection          `json:"move_in"`
+	MoveOut       *model.Inspection          `json:"move_out"`
+	Items         []InspectionItemComparison `json:"items"`
+	WorsenedCount int                        `json:"worsened_count"`
+	MissingCount  int                        `json:"missing_count"`
+}
+
+// DefaultInspectionTemplate returns the checklist used when no template is chosen and the
+// property has no inventory
+func DefaultInspectionTemplate() model.InspectionTemplate {
+	room := func(name string, items ...string) model.InventoryRoom {
+		room := model.InventoryRoom{Name: name}
+		for _, item := range items {
+			room.Items = append(room.Items, model.InventoryItem{Name: item, Quantity: 1})
+		}
+		return room
+	}
+
+	return model.InspectionTemplate{
+		Name: "Apartamento",
+		Rooms: []model.InventoryRoom{
+			room("Sala - comedor", "Paredes y techo", "Pisos", "Puertas y cerraduras", "Ventanas y vidrios", "Tomas e interruptores", "Lámparas"),
+			room("Cocina", "Paredes y techo", "Pisos", "Mesón", "Estufa", "Campana extractora", "Lavaplatos y grifería", "Gabinetes"),
+			room("Habitación principal", "Paredes y techo", "Pisos", "Puertas y cerraduras", "Ventanas y vidrios", "Closet", "Tomas e interruptores"),
+			room("Baño", "Paredes y enchape", "Sanitario", "Lavamanos y grifería", "Ducha y división", "Espejo", "Accesorios"),
+			room("Zona de ropas", "Lavadero", "Calentador", "Llaves de paso"),
+		},
+	}
+}
+
+// ValidateInspectionRooms checks the rooms of an inspection or template. Items must have a known
+// condition only when complete is set, so drafts and templates can leave them blank.
+func ValidateInspectionRooms(rooms []model.InventoryRoom, complete bool) error {
+	if complete {
+		return ValidateInventory(&model.Inventory{Rooms: rooms})
+	}
+
+	for i := range rooms {
+		room := &rooms[i]
+		if strings.TrimSpace(room.Name) == "" {
+			return fmt.Errorf("room %d has no name", i+1)
+		}
+		for j := range room.Items {
+			item := &room.Items[j]
+			if strings.TrimSpace(item.Name) == "" {
+				return fmt.Errorf("item %d of room %q has no name", j+1, room.Name)
+			}
+			if item.Quantity < 0 {
+				return fmt.Errorf("item %q of room %q has a negative quantity", item.Name, room.Name)
+			}
+			if item.Quantity == 0 {
+				item.Quantity = 1
+			}
+			if item.Condition != "" && !model.IsValidItemCondition(item.Condition) {
+				return fmt.Errorf("item %q of room %q has an invalid condition, use one of %s", item.Name, room.Name, strings.Join(model.ItemConditions, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// InspectionChangeLabel returns the printed label of a comparison result
+func InspectionChangeLabel(change string) string {
+	if label, ok := inspectionChangeLabels[change]; ok {
+		return label
+	}
+	return change
+}
+
+// CompareInspections matches the items of both inspections by room and name and reports how
+// each one was returned
+func CompareInspections(moveIn, moveOut model.Inspection) InspectionComparison {
+	comparison := InspectionComparison{
+		RentalID: moveIn.RentalID,
+		MoveIn:   &moveIn,
+		MoveOut:  &moveOut,
+		Items:    []InspectionItemComparison{},
+	}
+
+	key := func(room, item string) string {
+		return strings.ToLower(strings.TrimSpace(room)) + "\x00" + strings.ToLower(strings.TrimSpace(item))
+	}
+	returned := make(map[string]model.InventoryItem)
+	for _, room := range moveOut.Rooms {
+		for _, item := range room.Items {
+			returned[key(room.Name, item.Name)] = item
+		}
+	}
+
+	delivered := make(map[string]bool)
+	for _, room := range moveIn.Rooms {
+		for _, item := range room.Items {
+			k := key(room.Name, item.Name)
+			delivered[k] = true
+
+			entry := InspectionItemComparison{
+				Room:            room.Name,
+				Item:            item.Name,
+				MoveInQuantity:  item.Quantity,
+				MoveInCondition: item.Condition,
+				MoveInNotes:     item.Notes,
+				Change:          InspectionChangeMissing,
+			}
+			if out, ok := returned[k]; ok {
+				entry.MoveOutQuantity = out.Quantity
+				entry.MoveOutCondition = out.Condition
+				entry.MoveOutNotes = out.Notes
+				entry.MoveOutPhotos = out.Photos
+
+				inRank, outRank := model.ItemConditionRank(item.Condition), model.ItemConditionRank(out.Condition)
+				switch {
+				case out.Quantity < item.Quantity:
+					entry.Change = InspectionChangeMissing
+				case inRank >= 0 && outRank > inRank:
+					entry.Change = InspectionChangeWorsened
+				case outRank >= 0 && outRank < inRank:
+					entry.Change = InspectionChangeImproved
+				default:
+					entry.Change = InspectionChangeUnchanged
+				}
+			}
+
+			switch entry.Change {
+			case InspectionChangeWorsened:
+				comparison.WorsenedCount++
+			case InspectionChangeMissing:
+				comparison.MissingCount++
+			}
+			comparison.Items = append(comparison.Items, entry)
+		}
+	}
+
+	for _, room := range moveOut.Rooms {
+		for _, item := range room.Items {
+			if delivered[key(room.Name, item.Name)] {
+				continue
+			}
+			comparison.Items = append(comparison.Items, InspectionItemComparison{
+				Room:             room.Name,
+				Item:             item.Name,
+				MoveOutQuantity:  item.Quantity,
+				MoveOutCondition: item.Condition,
+				MoveOutNotes:     item.Notes,
+				MoveOutPhotos:    item.Photos,
+				Change:           InspectionChangeAdded,
+			})
+		}
+	}
+
+	return comparison
+}
+
+// GenerateInspectionPDF creates the acta of a move-in or move-out inspection, room by room with
+// the condition of each item and its photos
+func GenerateInspectionPDF(data InspectionPDF) ([]byte, error) {
+	pdf, err := inspectionPDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SignInspectionPDF creates the acta of an inspection acknowledged by the tenant, with the
+// visible signature, its evidence and the cryptographic signature
+func SignInspectionPDF(data InspectionPDF, signerName, signerEmail, signingID string, evidence *model.SigningEvidence) ([]byte, error) {
+	signingCert, err := ActiveSigningCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing certificate: %w", err)
+	}
+
+	pdf, err := inspectionPDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verifiable through the contract signing verification, like the contracts
+	stamp := NewSignatureStamp(signerName, signerEmail, signingID)
+	addSignatureStamp(pdf, stamp)
+	addSigningCertificatePage(pdf, stamp, evidence, signingCert)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	signedBytes, err := SignPDF(buf.Bytes(), signerName, SignPDFOptions{
+		SignatureReason:   "Inspection acknowledgment",
+		SignatureLocation: "Digital Signature",
+		SignatureContact: fmt.Sprintf("SignID: %s | SignedBy: %s | TimeSigned: %s%s",
+			signingID, signerEmail, stamp.SignedAt.Format(time.RFC3339), signingEvidenceContact(evidence)),
+	})
+	if err != nil {
+		slog.Warn("error signing inspection, proceeding with the unsigned PDF", "inspection_id", data.Inspection.ID, "error", err)
+		return buf.Bytes(), nil
+	}
+
+	return signedBytes, nil
+}
+
+// inspectionPDF renders the acta of an inspection up to the signature tables
+func inspectionPDF(data InspectionPDF) (*gofpdf.Fpdf, error) {
+	if data.Rental == nil || data.Property == nil || data.Tenant == nil {
+		return nil, fmt.Errorf("incomplete inspection data")
+	}
+	inspection := data.Inspection
+
+	propertyAddress := data.Property.Address
+	if data.Property.AptNumber != "" {
+		propertyAddress += " Apto " + data.Property.AptNumber
+	}
+	ownerName := blankField
+	if data.Owner != nil {
+		ownerName = data.Owner.FullName
+	}
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFontFamily, "B", 14)
+	pdf.MultiCell(0, 8, strings.ToUpper(model.InspectionKindLabels[inspection.Kind]), "", "C", false)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 6, propertyAddress, "", "C", false)
+	pdf.Ln(4)
+
+	receiptField(pdf, "Ciudad", data.Property.City)
+	receiptField(pdf, "Arrendatario", fmt.Sprintf("%s (CC/NIT %s)", data.Tenant.FullName, data.Tenant.NIT))
+	receiptField(pdf, "Fecha de la inspección", FormatDate(inspection.InspectedAt))
+	if data.Inspector != nil {
+		receiptField(pdf, "Realizada por", data.Inspector.FullName)
+	}
+	pdf.Ln(3)
+
+	intro := fmt.Sprintf("En la fecha indicada se realizó la inspección del inmueble ubicado en %s de la ciudad de %s, objeto del contrato de arrendamiento celebrado entre %s como ARRENDADOR y %s como ARRENDATARIO. Las partes declaran que los elementos relacionados se entregan en el estado que se indica.",
+		propertyAddress, data.Property.City, ownerName, data.Tenant.FullName)
+	if inspection.Kind == model.InspectionKindMoveOut {
+		intro = fmt.Sprintf("En la fecha indicada se realizó la inspección del inmueble ubicado en %s de la ciudad de %s, restituido por %s como ARRENDATARIO al terminar el contrato de arrendamiento. Las partes declaran que los elementos relacionados se restituyen en el estado que se indica.",
+			propertyAddress, data.Property.City, data.Tenant.FullName)
+	}
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.MultiCell(0, 5, intro, "", "J", false)
+	pdf.Ln(5)
+
+	for _, room := range inspection.Rooms {
+		addInventoryRoom(pdf, room)
+	}
+
+	if inspection.Notes != "" {
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.CellFormat(0, 7, "OBSERVACIONES GENERALES:", "0", 0, "L", false, 0, "")
+		pdf.Ln(7)
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.MultiCell(0, 5, inspection.Notes, "", "J", false)
+	}
+
+	values := map[string]string{
+		"arrendatario":          strings.ToUpper(data.Tenant.FullName),
+		"arrendatario_cc":       data.Tenant.NIT,
+		"arrendatario_telefono": data.Tenant.Phone,
+		"arrendatario_email":    data.TenantEmail,
+	}
+	if data.Owner != nil {
+		values["arrendador"] = strings.ToUpper(data.Owner.FullName)
+		values["arrendador_cc"] = data.Owner.NIT
+		values["arrendador_telefono"] = data.Owner.Phone
+	}
+	addSignatureTables(pdf, []model.ContractSignatureBlock{
+		{Label: "ARRENDADOR", Party: model.ContractPartyArrendador},
+		{Label: "ARRENDATARIO", Party: model.ContractPartyArrendatario},
+	}, values)
+
+	return pdf, nil
+}
+
+// GenerateInspectionComparisonPDF creates the report comparing the move-in and move-out
+// inspections of a rental, with the photos of the items returned deteriorated or missing
+func GenerateInspectionComparisonPDF(comparison InspectionComparison, data InspectionPDF) ([]byte, error) {
+	if comparison.MoveIn == nil || comparison.MoveOut == nil || data.Property == nil || data.Tenant == nil {
+		return nil, fmt.Errorf("incomplete inspection comparison data")
+	}
+
+	propertyAddress := data.Property.Address
+	if data.Property.AptNumber != "" {
+		propertyAddress += " Apto " + data.Property.AptNumber
+	}
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFontFamily, "B", 14)
+	pdf.MultiCell(0, 8, "INFORME COMPARATIVO DE INSPECCIONES", "", "C", false)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 6, propertyAddress, "", "C", false)
+	pdf.Ln(4)
+
+	receiptField(pdf, "Arrendatario", fmt.Sprintf("%s (CC/NIT %s)", data.Tenant.FullName, data.Tenant.NIT))
+	receiptField(pdf, "Entrega", inspectionDateLabel(*comparison.MoveIn))
+	receiptField(pdf, "Restitución", inspectionDateLabel(*comparison.MoveOut))
+	receiptField(pdf, "Deterioros", fmt.Sprintf("%d", comparison.WorsenedCount))
+	receiptField(pdf, "Faltantes", fmt.Sprintf("%d", comparison.MissingCount))
+	pdf.Ln(4)
+
+	widths := []float64{35, 45, 30, 30, 30}
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	for i, header := range []string{"AMBIENTE", "ELEMENTO", "ENTREGA", "RESTITUCIÓN", "RESULTADO"} {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(7)
+
+	pdf.SetFont(pdfFontFamily, "", 8)
+	for _, item := range comparison.Items {
+		highlight := item.Change == InspectionChangeWorsened || item.Change == InspectionChangeMissing
+		if highlight {
+			pdf.SetFillColor(253, 226, 226)
+		}
+		pdf.CellFormat(widths[0], 6, truncatePDFText(pdf, item.Room, widths[0]-2), "1", 0, "L", highlight, 0, "")
+		pdf.CellFormat(widths[1], 6, truncatePDFText(pdf, item.Item, widths[1]-2), "1", 0, "L", highlight, 0, "")
+		pdf.CellFormat(widths[2], 6, comparedCondition(item.MoveInCondition, item.MoveInQuantity), "1", 0, "C", highlight, 0, "")
+		pdf.CellFormat(widths[3], 6, comparedCondition(item.MoveOutCondition, item.MoveOutQuantity), "1", 0, "C", highlight, 0, "")
+		pdf.CellFormat(widths[4], 6, InspectionChangeLabel(item.Change), "1", 0, "C", highlight, 0, "")
+		pdf.Ln(6)
+	}
+	pdf.Ln(4)
+
+	// Detail of what justifies a deduction, with the photos taken at move-out
+	var findings []InspectionItemComparison
+	for _, item := range comparison.Items {
+		if item.Change == InspectionChangeWorsened || item.Change == InspectionChangeMissing {
+			findings = append(findings, item)
+		}
+	}
+	if len(findings) > 0 {
+		pdf.SetFont(pdfFontFamily, "B", 11)
+		pdf.CellFormat(0, 8, "DETALLE DE DETERIOROS Y FALTANTES", "0", 0, "L", false, 0, "")
+		pdf.Ln(8)
+		for _, item := range findings {
+			pdf.SetFont(pdfFontFamily, "B", 9)
+			pdf.MultiCell(0, 5, fmt.Sprintf("%s - %s: %s", item.Room, item.Item, InspectionChangeLabel(item.Change)), "", "L", false)
+			pdf.SetFont(pdfFontFamily, "", 9)
+			if item.MoveInNotes != "" {
+				pdf.MultiCell(0, 5, "Al ingreso: "+item.MoveInNotes, "", "L", false)
+			}
+			if item.MoveOutNotes != "" {
+				pdf.MultiCell(0, 5, "A la salida: "+item.MoveOutNotes, "", "L", false)
+			}
+			pdf.Ln(2)
+			addInventoryPhotos(pdf, item.MoveOutPhotos)
+			pdf.Ln(3)
+		}
+	}
+
+	pdf.SetFont(pdfFontFamily, "I", 8)
+	pdf.MultiCell(0, 4, "Los deterioros relacionados, distintos del desgaste natural por el uso legítimo del inmueble, y los elementos faltantes soportan los descuentos del depósito en garantía. Ambas inspecciones fueron reconocidas por el arrendatario con su firma.", "", "J", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inspectionDateLabel returns the date of an inspection and whether the tenant signed it
+func inspectionDateLabel(inspection model.Inspection) string {
+	label := FormatDate(inspection.InspectedAt)
+	if inspection.AcknowledgedAt != nil {
+		label += " (firmada el " + FormatDate(*inspection.AcknowledgedAt) + ")"
+	} else {
+		label += " (sin firma del arrendatario)"
+	}
+	return label
+}
+
+// comparedCondition returns the condition printed in the comparison, with the quantity when
+// more than one unit was inspected
+func comparedCondition(condition string, quantity int) string {
+	if condition == "" && quantity == 0 {
+		return "-"
+	}
+	label := ItemConditionLabel(condition)
+	if quantity > 1 {
+		label = fmt.Sprintf("%s (%d)", label, quantity)
+	}
+	return label
+}
+
+// InspectionService loads the data printed on the inspections and records their
+// acknowledgment by the tenant through the signing flow
+type InspectionService struct {
+	inspectionRepo  storage.InspectionStore
+	rentalRepo      storage.RentalStore
+	propertyRepo    storage.PropertyStore
+	personRepo      storage.PersonStore
+	userRepo        storage.UserStore
+	bankAccountRepo storage.BankAccountStore
+}
+
+// NewInspectionService creates a new InspectionService
+func NewInspectionService(repoFactory *storage.RepositoryFactory) *InspectionService {
+	return &InspectionService{
+		inspectionRepo:  repoFactory.GetInspectionRepository(),
+		rentalRepo:      repoFactory.GetRentalRepository(),
+		propertyRepo:    repoFactory.GetPropertyRepository(),
+		personRepo:      repoFactory.GetPersonRepository(),
+		userRepo:        repoFactory.GetUserRepository(),
+		bankAccountRepo: repoFactory.GetBankAccountRepository(),
+	}
+}
+
+// GetByID retrieves an inspection, nil when it does not exist
+func (s *InspectionService) GetByID(ctx context.Context, id uuid.UUID) (*model.Inspection, error) {
+	return s.inspectionRepo.GetByID(ctx, id)
+}
+
+// DocumentData loads an inspection with the rental, parties and property printed on it
+func (s *InspectionService) DocumentData(ctx context.Context, inspection model.Inspection) (*InspectionPDF, error) {
+	rental, err := s.rentalRepo.GetByID(ctx, inspection.RentalID)
+	if err != nil || rental == nil {
+		return nil, fmt.Errorf("failed to get rental %s of inspection %s: %v", inspection.RentalID, inspection.ID, err)
+	}
+	property, err := s.propertyRepo.GetByID(ctx, rental.PropertyID)
+	if err != nil || property == nil {
+		return nil, fmt.Errorf("failed to get property %s: %v", rental.PropertyID, err)
+	}
+	tenant, err := s.personRepo.GetByID(ctx, rental.RenterID)
+	if err != nil || tenant == nil {
+		return nil, fmt.Errorf("failed to get tenant %s: %v", rental.RenterID, err)
+	}
+
+	data := &InspectionPDF{
+		Inspection: inspection,
+		Rental:     rental,
+		Property:   property,
+		Tenant:     tenant,
+	}
+	if user, err := s.userRepo.GetByPersonID(ctx, tenant.ID); err == nil && user != nil {
+		data.TenantEmail = user.Email
+	}
+	if account, err := s.bankAccountRepo.GetByID(ctx, rental.BankAccountID); err == nil && account != nil {
+		if owner, err := s.personRepo.GetByID(ctx, account.PersonID); err == nil {
+			data.Owner = owner
+		}
+	}
+	if inspection.InspectorID != uuid.Nil {
+		if inspector, err := s.personRepo.GetByID(ctx, inspection.InspectorID); err == nil {
+			data.Inspector = inspector
+		}
+	}
+	return data, nil
+}
+
+// RenderPDF renders the unsigned acta of an inspection
+func (s *InspectionService) RenderPDF(ctx context.Context, inspectionID uuid.UUID) ([]byte, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection == nil {
+		return nil, fmt.Errorf("inspection %s not found", inspectionID)
+	}
+
+	data, err := s.DocumentData(ctx, *inspection)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateInspectionPDF(*data)
+}
+
+// Acknowledge signs the acta of an inspection for its signing request, stores it with the
+// rental files and marks the inspection as acknowledged. It returns the signed PDF.
+func (s *InspectionService) Acknowledge(ctx context.Context, inspectionID uuid.UUID, signerName, signerEmail, signingID string, evidence *model.SigningEvidence) ([]byte, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection == nil {
+		return nil, fmt.Errorf("inspection %s not found", inspectionID)
+	}
+
+	data, err := s.DocumentData(ctx, *inspection)
+	if err != nil {
+		return nil, err
+	}
+	signedPDF, err := SignInspectionPDF(*data, signerName, signerEmail, signingID, evidence)
+	if err != nil {
+		return nil, err
+	}
+
+	signedPath, err := storeInspectionPDF(*inspection, signedPDF)
+	if err != nil {
+		logging.FromContext(ctx).Warn("error storing signed inspection", "component", "inspection", "inspection_id", inspection.ID, "error", err)
+	}
+
+	now := time.Now()
+	inspection.Status = model.InspectionStatusAcknowledged
+	inspection.SignedPDFPath = signedPath
+	inspection.AcknowledgedAt = &now
+	if _, err := s.inspectionRepo.Update(ctx, *inspection); err != nil {
+		return nil, fmt.Errorf("failed to mark inspection %s as acknowledged: %w", inspection.ID, err)
+	}
+
+	logging.FromContext(ctx).Info("inspection of rental acknowledged", "component", "inspection", "inspection_id", inspection.ID, "rental_id", inspection.RentalID, "signer_email", signerEmail)
+	return signedPDF, nil
+}
+
+// Comparison compares the latest move-out inspection of a rental with its latest move-in
+// inspection. It returns nil when the rental lacks one of them.
+func (s *InspectionService) Comparison(ctx context.Context, rentalID uuid.UUID) (*InspectionComparison, error) {
+	inspections, err := s.inspectionRepo.GetByRentalID(ctx, rentalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var moveIn, moveOut *model.Inspection
+	for i := range inspections {
+		switch inspections[i].Kind {
+		case model.InspectionKindMoveIn:
+			moveIn = &inspections[i]
+		case model.InspectionKindMoveOut:
+			moveOut = &inspections[i]
+		}
+	}
+	if moveIn == nil || moveOut == nil {
+		return nil, nil
+	}
+
+	comparison := CompareInspections(*moveIn, *moveOut)
+	return &comparison, nil
+}
+
+// storeInspectionPDF saves the signed acta of an inspection with the rental files and returns
+// its path
+func storeInspectionPDF(inspection model.Inspection, signedPDF []byte) (string, error) {
+	if storageService := GetSupabaseStorageService(); storageService != nil {
+		filePath := fmt.Sprintf("rentals/%s/inspecciones/%s_firmada.pdf", inspection.RentalID, inspection.ID)
+		if _, err := storageService.UploadBytes(filePath, signedPDF, "application/pdf"); err != nil {
+			return "", err
+		}
+		return filePath, nil
+	}
+
+	tempDir := filepath.Join(os.TempDir(), "inspections")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return "", err
+	}
+	signedPath := filepath.Join(tempDir, inspection.ID.String()+"_firmada.pdf")
+	if err := os.WriteFile(signedPath, signedPDF, 0644); err != nil {
+		return "", err
+	}
+	return signedPath, nil
+}
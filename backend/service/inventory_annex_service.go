@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/nescool101/rentManager/model"
+)
+
+const (
+	// inventoryPhotoWidth is the width in mm of the photos printed in the inventory annex
+	inventoryPhotoWidth = 40.0
+	// inventoryPhotosPerRow is the number of photos printed side by side
+	inventoryPhotosPerRow = 4
+)
+
+// itemConditionLabels are the labels printed for each item condition
+var itemConditionLabels = map[string]string{
+	model.ItemConditionNuevo:   "Nuevo",
+	model.ItemConditionBueno:   "Bueno",
+	model.ItemConditionRegular: "Regular",
+	model.ItemConditionMalo:    "Malo",
+}
+
+// inventoryPhotoLoader downloads the photos printed in the annex. Photos are skipped when
+// file storage is not configured.
+var inventoryPhotoLoader = func(path string) ([]byte, error) {
+	storageService := GetSupabaseStorageService()
+	if storageService == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+	return storageService.DownloadFile(path)
+}
+
+// ValidateInventory checks that every room and item has a name and that items have a
+// known condition. Items without a quantity count as one.
+func ValidateInventory(inventory *model.Inventory) error {
+	for i := range inventory.Rooms {
+		room := &inventory.Rooms[i]
+		if strings.TrimSpace(room.Name) == "" {
+			return fmt.Errorf("room %d has no name", i+1)
+		}
+		for j := range room.Items {
+			item := &room.Items[j]
+			if strings.TrimSpace(item.Name) == "" {
+				return fmt.Errorf("item %d of room %q has no name", j+1, room.Name)
+			}
+			if item.Quantity < 0 {
+				return fmt.Errorf("item %q of room %q has a negative quantity", item.Name, room.Name)
+			}
+			if item.Quantity == 0 {
+				item.Quantity = 1
+			}
+			if !model.IsValidItemCondition(item.Condition) {
+				return fmt.Errorf("item %q of room %q has an invalid condition, use one of %s", item.Name, room.Name, strings.Join(model.ItemConditions, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// ItemConditionLabel returns the printed label of an item condition
+func ItemConditionLabel(condition string) string {
+	if label, ok := itemConditionLabels[condition]; ok {
+		return label
+	}
+	return condition
+}
+
+// GenerateInventoryAnnexPDF creates the inventory annex of a contract as a standalone PDF
+func GenerateInventoryAnnexPDF(data ContractPDF) ([]byte, error) {
+	if data.Inventory == nil {
+		return nil, fmt.Errorf("the contract has no inventory")
+	}
+
+	template := DefaultContractTemplate()
+	if data.Template != nil {
+		template = *data.Template
+	}
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+
+	addInventoryAnnex(pdf, data.Inventory, ContractTemplateValues(data, template), contractSignatureBlocks(template))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// addInventoryAnnex appends the inventory annex on a new page: the items of each room with
+// their condition and photos, signed by the lessor and the lessee
+func addInventoryAnnex(pdf *gofpdf.Fpdf, inventory *model.Inventory, values map[string]string, blocks []model.ContractSignatureBlock) {
+	propertyAddress := values["direccion"]
+	if values["apartamento"] != blankField {
+		propertyAddress += " Apto " + values["apartamento"]
+	}
+
+	pdf.AddPage()
+	pdf.SetFont(pdfFontFamily, "B", 14)
+	pdf.MultiCell(0, 8, "ANEXO: INVENTARIO DEL INMUEBLE", "", "C", false)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 6, propertyAddress, "", "C", false)
+	pdf.Ln(6)
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.MultiCell(0, 5, fmt.Sprintf("El presente inventario hace parte integral del contrato de arrendamiento del inmueble ubicado en %s de la ciudad de %s, celebrado entre %s como ARRENDADOR y %s como ARRENDATARIO. Las partes declaran que los elementos relacionados se entregan en el estado que se indica y que el ARRENDATARIO los restituirá en el mismo estado, salvo el deterioro natural por el uso legítimo.", propertyAddress, values["ciudad"], values["arrendador"], values["arrendatario"]), "", "J", false)
+	pdf.Ln(5)
+
+	for _, room := range inventory.Rooms {
+		addInventoryRoom(pdf, room)
+	}
+
+	if inventory.Notes != "" {
+		pdf.SetFont(pdfFontFamily, "B", 10)
+		pdf.CellFormat(0, 7, "OBSERVACIONES GENERALES:", "0", 0, "L", false, 0, "")
+		pdf.Ln(7)
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.MultiCell(0, 5, inventory.Notes, "", "J", false)
+	}
+
+	// The inventory is signed by the lessor and the lessee only
+	var annexBlocks []model.ContractSignatureBlock
+	for _, block := range blocks {
+		if block.Party == model.ContractPartyArrendador || block.Party == model.ContractPartyArrendatario {
+			annexBlocks = append(annexBlocks, block)
+		}
+	}
+	addSignatureTables(pdf, annexBlocks, values)
+}
+
+// addInventoryRoom writes the table of items of a room followed by its photos
+func addInventoryRoom(pdf *gofpdf.Fpdf, room model.InventoryRoom) {
+	widths := []float64{70, 15, 25, 60}
+
+	pdf.SetFont(pdfFontFamily, "B", 11)
+	pdf.CellFormat(0, 8, strings.ToUpper(room.Name), "0", 0, "L", false, 0, "")
+	pdf.Ln(8)
+
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	for i, header := range []string{"ELEMENTO", "CANT.", "ESTADO", "OBSERVACIONES"} {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(7)
+
+	var photos []model.InventoryPhoto
+	photos = append(photos, room.Photos...)
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	if len(room.Items) == 0 {
+		pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3], 7, "Sin elementos", "1", 0, "C", false, 0, "")
+		pdf.Ln(7)
+	}
+	for _, item := range room.Items {
+		pdf.CellFormat(widths[0], 7, item.Name, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, strconv.Itoa(item.Quantity), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[2], 7, ItemConditionLabel(item.Condition), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[3], 7, item.Notes, "1", 0, "L", false, 0, "")
+		pdf.Ln(7)
+
+		for _, photo := range item.Photos {
+			if photo.Caption == "" {
+				photo.Caption = item.Name
+			}
+			photos = append(photos, photo)
+		}
+	}
+	pdf.Ln(3)
+
+	addInventoryPhotos(pdf, photos)
+	pdf.Ln(4)
+}
+
+// addInventoryPhotos prints the photos of a room in rows with their captions. Photos that
+// cannot be downloaded or are not JPEG, PNG or GIF images are left out of the annex.
+func addInventoryPhotos(pdf *gofpdf.Fpdf, photos []model.InventoryPhoto) {
+	_, pageHeight := pdf.GetPageSize()
+	left, _, _, bottomMargin := pdf.GetMargins()
+	gap := 2.5
+
+	column := 0
+	rowHeight := 0.0
+	rowY := pdf.GetY()
+	for _, photo := range photos {
+		imageType := strings.TrimPrefix(strings.ToLower(filepath.Ext(photo.Path)), ".")
+		if imageType == "jpeg" {
+			imageType = "jpg"
+		}
+		if imageType != "jpg" && imageType != "png" && imageType != "gif" {
+			continue
+		}
+
+		data, err := inventoryPhotoLoader(photo.Path)
+		if err != nil {
+			slog.Warn("photo left out of the annex", "component", "inventory", "path", photo.Path, "error", err)
+			continue
+		}
+
+		options := gofpdf.ImageOptions{ImageType: imageType}
+		info := pdf.RegisterImageOptionsReader(photo.Path, options, bytes.NewReader(data))
+		if pdf.Err() || info == nil || info.Width() == 0 {
+			slog.Warn("photo is not a valid image", "component", "inventory", "path", photo.Path, "error", pdf.Error())
+			pdf.ClearError()
+			continue
+		}
+		height := inventoryPhotoWidth * info.Height() / info.Width()
+
+		if column == inventoryPhotosPerRow {
+			column = 0
+			rowY += rowHeight
+			rowHeight = 0
+		}
+		if rowY+height+8 > pageHeight-bottomMargin {
+			pdf.AddPage()
+			column = 0
+			rowHeight = 0
+			rowY = pdf.GetY()
+		}
+
+		x := left + float64(column)*(inventoryPhotoWidth+gap)
+		pdf.ImageOptions(photo.Path, x, rowY, inventoryPhotoWidth, height, false, options, 0, "")
+		pdf.SetFont(pdfFontFamily, "I", 7)
+		pdf.SetXY(x, rowY+height+1)
+		pdf.MultiCell(inventoryPhotoWidth, 3, photo.Caption, "", "C", false)
+
+		if height+8 > rowHeight {
+			rowHeight = height + 8
+		}
+		column++
+	}
+
+	if rowHeight > 0 {
+		pdf.SetXY(left, rowY+rowHeight)
+	}
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DefaultInvitationTTL is how long an invitation link is valid by default
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidInvitation is returned for invitations with an unknown role, person, organization
+	// or property
+	ErrInvalidInvitation = errors.New("invalid invitation")
+	// ErrInvitationEmailTaken is returned when the invited email already has an account
+	ErrInvitationEmailTaken = errors.New("a user with this email already exists")
+	// ErrInvalidInvitationToken is returned for invitation links that are malformed, expired,
+	// revoked, already accepted or not issued by this server
+	ErrInvalidInvitationToken = errors.New("invalid or expired invitation")
+	// ErrInvitationNotFound is returned when revoking an invitation that is not pending
+	ErrInvitationNotFound = errors.New("invitation not found")
+)
+
+// InvitationInput is an invitation to send. Without PersonID a person is created with Name.
+type InvitationInput struct {
+	Email          string     `json:"email" binding:"required,email"`
+	Name           string     `json:"name"`
+	Role           string     `json:"role" binding:"required"`
+	PersonID       *uuid.UUID `json:"person_id"`
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	PropertyID     *uuid.UUID `json:"property_id"`
+	Message        string     `json:"message"`
+}
+
+// InvitationService invites tenants and co-managers by email and creates their accounts, linked
+// to their organization and property, when they accept
+type InvitationService struct {
+	invitations storage.InvitationStore
+	users       storage.UserStore
+	persons     storage.PersonStore
+	properties  storage.PropertyStore
+	orgs        storage.OrganizationStore
+	orgService  *OrganizationService
+	secret      []byte
+	ttl         time.Duration
+}
+
+// NewInvitationService creates an InvitationService signing the links with secret
+// (INVITATION_SECRET), valid for ttl (INVITATION_TTL, DefaultInvitationTTL when unset)
+func NewInvitationService(factory *storage.RepositoryFactory, orgService *OrganizationService, secret string, ttl time.Duration) *InvitationService {
+	return &InvitationService{
+		invitations: factory.GetInvitationRepository(),
+		users:       factory.GetUserRepository(),
+		persons:     factory.GetPersonRepository(),
+		properties:  factory.GetPropertyRepository(),
+		orgs:        factory.GetOrganizationRepository(),
+		orgService:  orgService,
+		secret:      []byte(secret),
+		ttl:         orDefault(ttl, DefaultInvitationTTL),
+	}
+}
+
+func (s *InvitationService) signature(id, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("invitation:" + id + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Token returns the token of the link of an invitation
+func (s *InvitationService) Token(invitation *model.Invitation) string {
+	id := invitation.ID.String()
+	expires := strconv.FormatInt(invitation.ExpiresAt.Unix(), 10)
+	return id + "." + expires + "." + s.signature(id, expires)
+}
+
+// Send records an invitation and emails its link
+func (s *InvitationService) Send(ctx context.Context, input InvitationInput, invitedBy uuid.UUID, now time.Time) (*model.Invitation, error) {
+	input.Email = strings.TrimSpace(input.Email)
+	if !slices.Contains([]string{model.InvitationRoleManager, model.InvitationRoleResident}, input.Role) {
+		return nil, fmt.Errorf("%w: role must be %s or %s", ErrInvalidInvitation, model.InvitationRoleManager, model.InvitationRoleResident)
+	}
+
+	existing, err := s.users.GetByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrInvitationEmailTaken
+	}
+
+	var org *model.Organization
+	if input.OrganizationID != nil {
+		if org = s.orgService.ByID(ctx, *input.OrganizationID); org == nil {
+			return nil, fmt.Errorf("%w: organization not found", ErrInvalidInvitation)
+		}
+	}
+	var property *model.Property
+	if input.PropertyID != nil {
+		if property, err = s.properties.GetByID(ctx, *input.PropertyID); err != nil {
+			return nil, err
+		}
+		if property == nil {
+			return nil, fmt.Errorf("%w: property not found", ErrInvalidInvitation)
+		}
+		if org == nil {
+			org = s.orgService.ForProperty(ctx, property.ID)
+		}
+	}
+
+	person, err := s.invitedPerson(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitations.Create(ctx, model.Invitation{
+		ID:             uuid.New(),
+		Email:          input.Email,
+		Role:           input.Role,
+		PersonID:       person.ID,
+		OrganizationID: input.OrganizationID,
+		PropertyID:     input.PropertyID,
+		Message:        input.Message,
+		InvitedBy:      invitedBy,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := OrganizationBaseURL(org) + "/accept-invitation?" + url.Values{"token": {s.Token(invitation)}}.Encode()
+	orgName := ""
+	if org != nil {
+		orgName = org.Name
+	}
+	propertyAddress := ""
+	if property != nil {
+		propertyAddress = property.Address
+	}
+	runInBackground(func() {
+		if err := SendInvitationEmail(invitation.Email, person.FullName, invitation.Role, orgName, propertyAddress, invitation.Message, link, s.ttl); err != nil {
+			logging.FromContext(ctx).Error("error sending the invitation", "component", "invitation", "email", invitation.Email, "error", err)
+		}
+	})
+
+	logging.FromContext(ctx).Info("user invited", "component", "invitation", "email", invitation.Email, "role", invitation.Role, "invited_by", invitedBy)
+	return invitation, nil
+}
+
+// invitedPerson returns the person of input, creating it when no person is given
+func (s *InvitationService) invitedPerson(ctx context.Context, input InvitationInput) (*model.Person, error) {
+	if input.PersonID != nil {
+		person, err := s.persons.GetByID(ctx, *input.PersonID)
+		if err != nil {
+			return nil, err
+		}
+		if person == nil {
+			return nil, fmt.Errorf("%w: person not found", ErrInvalidInvitation)
+		}
+		if linked, err := s.users.GetByPersonID(ctx, person.ID); err != nil {
+			return nil, err
+		} else if linked != nil {
+			return nil, fmt.Errorf("%w: the person already has an account", ErrInvalidInvitation)
+		}
+		return person, nil
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required without person_id", ErrInvalidInvitation)
+	}
+	// The NIT is completed by the person during onboarding
+	person, err := s.persons.Create(ctx, model.Person{
+		ID:       uuid.New(),
+		FullName: name,
+		NIT:      fmt.Sprintf("TEMP-%d-%s", time.Now().UnixNano(), uuid.NewString()[:6]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, fmt.Errorf("failed to create the invited person")
+	}
+	return person, nil
+}
+
+// Open returns the pending invitation of a token
+func (s *InvitationService) Open(ctx context.Context, token string, now time.Time) (*model.Invitation, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !hmac.Equal([]byte(s.signature(parts[0], parts[1])), []byte(parts[2])) {
+		return nil, ErrInvalidInvitationToken
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, ErrInvalidInvitationToken
+	}
+
+	invitation, err := s.invitations.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.Status(now) != model.InvitationPending {
+		return nil, ErrInvalidInvitationToken
+	}
+	return invitation, nil
+}
+
+// Accept creates the account of the invitation of a token with password and links its person
+// to the organization and property of the invitation
+func (s *InvitationService) Accept(ctx context.Context, token, password string, now time.Time) (*model.User, error) {
+	invitation, err := s.Open(ctx, token, now)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.users.GetByEmail(ctx, invitation.Email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrInvitationEmailTaken
+	}
+
+	// Managers go through the onboarding stepper, as the invited managers always have
+	status := "active"
+	if invitation.Role == model.InvitationRoleManager {
+		status = "newuser"
+	}
+	user := model.User{
+		ID:             uuid.New(),
+		Email:          invitation.Email,
+		PasswordBase64: base64.StdEncoding.EncodeToString([]byte(password)),
+		Role:           invitation.Role,
+		PersonID:       invitation.PersonID,
+		Status:         status,
+	}
+	if _, err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	accepted, err := s.invitations.MarkAccepted(ctx, invitation.ID, user.ID, now)
+	if err == nil && !accepted {
+		err = ErrInvalidInvitationToken
+	}
+	if err != nil {
+		// Accepted or revoked meanwhile, the account is not kept
+		if deleteErr := s.users.Delete(ctx, user.ID); deleteErr != nil {
+			logging.FromContext(ctx).Error("error deleting the account after a failed acceptance", "component", "invitation", "email", user.Email, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	s.link(ctx, invitation)
+	logging.FromContext(ctx).Info("invitation accepted", "component", "invitation", "email", user.Email, "role", user.Role)
+	return &user, nil
+}
+
+// link adds the person of an accepted invitation to its organization and property. The account
+// is kept when a link fails, the admins can complete it.
+func (s *InvitationService) link(ctx context.Context, invitation *model.Invitation) {
+	if invitation.PropertyID != nil {
+		var err error
+		if invitation.Role == model.InvitationRoleManager {
+			err = s.properties.AddManagerToProperty(ctx, *invitation.PropertyID, invitation.PersonID)
+		} else {
+			err = s.moveIn(ctx, *invitation.PropertyID, invitation.PersonID)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not link to property", "component", "invitation", "email", invitation.Email, "property_id", *invitation.PropertyID, "error", err)
+		}
+	}
+
+	// Residents belong to the organization of their property
+	if invitation.OrganizationID != nil && invitation.Role == model.InvitationRoleManager {
+		if err := s.joinOrganization(ctx, *invitation.OrganizationID, invitation.PersonID); err != nil {
+			logging.FromContext(ctx).Warn("could not add to organization", "component", "invitation", "email", invitation.Email, "organization_id", *invitation.OrganizationID, "error", err)
+		}
+	}
+}
+
+// moveIn makes a person the resident of a property
+func (s *InvitationService) moveIn(ctx context.Context, propertyID, personID uuid.UUID) error {
+	property, err := s.properties.GetByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return fmt.Errorf("property not found")
+	}
+	property.ResidentID = personID
+	_, err = s.properties.Update(ctx, *property)
+	return err
+}
+
+// joinOrganization adds a person to the managers of an organization
+func (s *InvitationService) joinOrganization(ctx context.Context, orgID, personID uuid.UUID) error {
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		return fmt.Errorf("organization not found")
+	}
+	if slices.Contains(org.ManagerIDs, personID) {
+		return nil
+	}
+	org.ManagerIDs = append(org.ManagerIDs, personID)
+	if _, err := s.orgs.Update(ctx, *org); err != nil {
+		return err
+	}
+	s.orgService.Invalidate()
+	return nil
+}
+
+// List returns every invitation, newest first
+func (s *InvitationService) List(ctx context.Context) ([]model.Invitation, error) {
+	return s.invitations.GetAll(ctx)
+}
+
+// Revoke cancels a pending invitation, whose link stops working
+func (s *InvitationService) Revoke(ctx context.Context, id uuid.UUID, now time.Time) error {
+	revoked, err := s.invitations.Revoke(ctx, id, now)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
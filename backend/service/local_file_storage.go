@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localTempPrefix marca los archivos que se están escribiendo, que no se listan
+const localTempPrefix = ".upload-"
+
+// localFileStorage guarda los archivos en disco, un directorio por bucket. Es el backend por
+// defecto de las instalaciones propias que no usan Supabase Storage. Los archivos no tienen
+// URL pública: se descargan a través de la API.
+type localFileStorage struct {
+	root string
+}
+
+// newLocalFileStorage crea el backend en disco con raíz en dir, creándolo si no existe
+func newLocalFileStorage(dir string) (*localFileStorage, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("ruta de almacenamiento inválida: %v", err)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("error creando carpeta de almacenamiento: %v", err)
+	}
+	return &localFileStorage{root: root}, nil
+}
+
+func (f *localFileStorage) Name() string {
+	return FileStorageLocal
+}
+
+// resolve convierte una ruta del bucket en una ruta del disco, rechazando las que salen del bucket
+func (f *localFileStorage) resolve(bucket, path string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || bucket == "." || bucket == ".." {
+		return "", fmt.Errorf("bucket inválido: %s", bucket)
+	}
+	base := filepath.Join(f.root, bucket)
+	full := filepath.Join(base, filepath.FromSlash(path))
+	if full != base && !strings.HasPrefix(full, base+string(os.PathSeparator)) {
+		return "", fmt.Errorf("ruta inválida: %s", path)
+	}
+	return full, nil
+}
+
+func (f *localFileStorage) EnsureBucket(bucket string) error {
+	dir, err := f.resolve(bucket, "")
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0o750)
+}
+
+// Upload escribe el contenido en un archivo temporal y lo renombra al terminar, de modo que
+// una subida interrumpida no deja un archivo a medias
+func (f *localFileStorage) Upload(bucket, path string, content io.Reader, contentType string, upsert bool) error {
+	full, err := f.resolve(bucket, path)
+	if err != nil {
+		return err
+	}
+	if !upsert {
+		if _, err := os.Stat(full); err == nil {
+			return fmt.Errorf("el archivo ya existe: %s", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o750); err != nil {
+		return fmt.Errorf("error creando carpeta: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(full), localTempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("error creando archivo temporal: %v", err)
+	}
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error escribiendo archivo: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error escribiendo archivo: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), full); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error guardando archivo: %v", err)
+	}
+	return nil
+}
+
+func (f *localFileStorage) Download(bucket, path string) ([]byte, error) {
+	full, err := f.resolve(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(full)
+}
+
+func (f *localFileStorage) Open(bucket, path string) (io.ReadCloser, error) {
+	full, err := f.resolve(bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(full)
+}
+
+func (f *localFileStorage) Remove(bucket string, paths []string) error {
+	for _, path := range paths {
+		full, err := f.resolve(bucket, path)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *localFileStorage) List(bucket, prefix string, limit, offset int) ([]StoredFile, error) {
+	dir, err := f.resolve(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []StoredFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]StoredFile, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), localTempPrefix) {
+			continue
+		}
+		file := StoredFile{Name: entry.Name(), Folder: entry.IsDir()}
+		if info, err := entry.Info(); err == nil {
+			file.CreatedAt = info.ModTime().Format(time.RFC3339)
+			file.UpdatedAt = file.CreatedAt
+			if !entry.IsDir() {
+				file.Size = info.Size()
+				file.MimeType = mime.TypeByExtension(filepath.Ext(entry.Name()))
+			}
+		}
+		files = append(files, file)
+	}
+
+	// Paginar igual que Supabase Storage
+	if offset >= len(files) {
+		return []StoredFile{}, nil
+	}
+	files = files[offset:]
+	if limit > 0 && len(files) > limit {
+		files = files[:limit]
+	}
+	return files, nil
+}
+
+func (f *localFileStorage) Move(sourceBucket, path, destinationBucket string) error {
+	source, err := f.resolve(sourceBucket, path)
+	if err != nil {
+		return err
+	}
+	destination, err := f.resolve(destinationBucket, path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), 0o750); err != nil {
+		return fmt.Errorf("error creando carpeta: %v", err)
+	}
+	return os.Rename(source, destination)
+}
+
+func (f *localFileStorage) Rename(bucket, from, to string) error {
+	source, err := f.resolve(bucket, from)
+	if err != nil {
+		return err
+	}
+	destination, err := f.resolve(bucket, to)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(destination); err == nil {
+		return fmt.Errorf("el archivo ya existe: %s", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), 0o750); err != nil {
+		return fmt.Errorf("error creando carpeta: %v", err)
+	}
+	return os.Rename(source, destination)
+}
+
+// SignedURLs no firma nada: los archivos en disco se comparten con enlaces firmados de la API
+func (f *localFileStorage) SignedURLs(bucket string, paths []string, ttl time.Duration) (map[string]string, error) {
+	return map[string]string{}, nil
+}
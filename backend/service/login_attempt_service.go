@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultLoginLockoutThreshold is how many consecutive failures lock an email out
+	defaultLoginLockoutThreshold = 5
+	// defaultLoginIPLockoutThreshold is how many failures lock an IP out, whatever the emails
+	defaultLoginIPLockoutThreshold = 20
+	// defaultLoginCaptchaThreshold is how many failures ask the client for a CAPTCHA
+	defaultLoginCaptchaThreshold = 3
+	// defaultLoginLockoutDuration is how long a lockout lasts after the last failure, and the
+	// window the failures are counted in
+	defaultLoginLockoutDuration = 15 * time.Minute
+	// loginAttemptRetention is how long the attempts are kept for the admins
+	loginAttemptRetention = 30 * 24 * time.Hour
+	// loginAttemptPruneInterval is how often the attempts past the retention are deleted
+	loginAttemptPruneInterval = time.Hour
+)
+
+// LoginStatus is the lockout state of an email and an IP before checking a password
+type LoginStatus struct {
+	Locked          bool       `json:"locked"`
+	LockedUntil     *time.Time `json:"locked_until,omitempty"`
+	FailedAttempts  int        `json:"failed_attempts"` // Consecutive failures of the email in the window
+	CaptchaRequired bool       `json:"captcha_required"`
+}
+
+// LoginAttemptService records the logins and locks out the emails and IPs with too many
+// failures in a row, LOGIN_LOCKOUT_THRESHOLD per email and LOGIN_IP_LOCKOUT_THRESHOLD per IP,
+// for LOGIN_LOCKOUT_DURATION after the last one. From LOGIN_CAPTCHA_THRESHOLD failures the
+// responses ask the client for a CAPTCHA.
+type LoginAttemptService struct {
+	repo storage.LoginAttemptStore
+
+	threshold        int
+	ipThreshold      int
+	captchaThreshold int
+	duration         time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewLoginAttemptService creates a LoginAttemptService with the thresholds of auth
+func NewLoginAttemptService(repo storage.LoginAttemptStore, auth config.AuthConfig) *LoginAttemptService {
+	return &LoginAttemptService{
+		repo:             repo,
+		threshold:        orDefault(auth.LoginLockoutThreshold, defaultLoginLockoutThreshold),
+		ipThreshold:      orDefault(auth.LoginIPLockoutThreshold, defaultLoginIPLockoutThreshold),
+		captchaThreshold: orDefault(auth.LoginCaptchaThreshold, defaultLoginCaptchaThreshold),
+		duration:         orDefault(auth.LoginLockoutDuration, defaultLoginLockoutDuration),
+	}
+}
+
+// NormalizeLoginEmail is the form the emails of the attempts are stored and counted in
+func NormalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Status reports whether email or ip are locked out and whether a CAPTCHA is required. The
+// logins are allowed when the attempts cannot be read.
+func (s *LoginAttemptService) Status(ctx context.Context, email, ip string, now time.Time) LoginStatus {
+	since := now.Add(-s.duration)
+	var status LoginStatus
+
+	emailFailures, lastEmailFailure := s.failuresSince(ctx, "email", NormalizeLoginEmail(email), since)
+	status.FailedAttempts = emailFailures
+	if emailFailures >= s.threshold {
+		s.lock(&status, lastEmailFailure.Add(s.duration))
+	}
+
+	if ip != "" {
+		ipFailures, lastIPFailure := s.failuresSince(ctx, "ip_address", ip, since)
+		if ipFailures >= s.ipThreshold {
+			s.lock(&status, lastIPFailure.Add(s.duration))
+		}
+		if ipFailures >= s.captchaThreshold {
+			status.CaptchaRequired = true
+		}
+	}
+
+	if emailFailures >= s.captchaThreshold {
+		status.CaptchaRequired = true
+	}
+	return status
+}
+
+func (s *LoginAttemptService) lock(status *LoginStatus, until time.Time) {
+	status.Locked = true
+	status.CaptchaRequired = true
+	if status.LockedUntil == nil || until.After(*status.LockedUntil) {
+		status.LockedUntil = &until
+	}
+}
+
+// failuresSince counts the credential failures of column since the later of since and the last
+// success, and returns the time of the last one
+func (s *LoginAttemptService) failuresSince(ctx context.Context, column, value string, since time.Time) (int, time.Time) {
+	attempts, err := s.repo.GetSince(ctx, column, value, since)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not read the login attempts, lockout not applied", "component", "login", column, value, "error", err)
+		return 0, time.Time{}
+	}
+
+	count := 0
+	var last time.Time
+	for _, attempt := range attempts {
+		// Newest first: a success resets the count, but an IP is shared by several accounts
+		if attempt.Success {
+			if column == "email" {
+				break
+			}
+			continue
+		}
+		if !slices.Contains(model.LoginCredentialFailures, attempt.FailureReason) {
+			continue
+		}
+		if count == 0 {
+			last = attempt.CreatedAt
+		}
+		count++
+	}
+	return count, last
+}
+
+// RecordFailure records a failed login and returns the status after it
+func (s *LoginAttemptService) RecordFailure(ctx context.Context, email, ip, userAgent, reason string, now time.Time) LoginStatus {
+	s.record(ctx, model.LoginAttempt{
+		Email:         NormalizeLoginEmail(email),
+		IPAddress:     ip,
+		FailureReason: reason,
+		UserAgent:     userAgent,
+		CreatedAt:     now,
+	})
+	return s.Status(ctx, email, ip, now)
+}
+
+// RecordSuccess records a successful login, which resets the failures of the email
+func (s *LoginAttemptService) RecordSuccess(ctx context.Context, email, ip, userAgent string, now time.Time) {
+	s.record(ctx, model.LoginAttempt{
+		Email:     NormalizeLoginEmail(email),
+		IPAddress: ip,
+		Success:   true,
+		UserAgent: userAgent,
+		CreatedAt: now,
+	})
+}
+
+func (s *LoginAttemptService) record(ctx context.Context, attempt model.LoginAttempt) {
+	if _, err := s.repo.Create(ctx, attempt); err != nil {
+		logging.FromContext(ctx).Warn("could not record the login attempt", "component", "login", "email", attempt.Email, "error", err)
+	}
+
+	s.mu.Lock()
+	prune := attempt.CreatedAt.Sub(s.lastPrune) >= loginAttemptPruneInterval
+	if prune {
+		s.lastPrune = attempt.CreatedAt
+	}
+	s.mu.Unlock()
+	if prune {
+		if err := s.repo.DeleteBefore(ctx, attempt.CreatedAt.Add(-loginAttemptRetention)); err != nil {
+			logging.FromContext(ctx).Warn("could not delete the old login attempts", "component", "login", "error", err)
+		}
+	}
+}
+
+// Recent lists the attempts matching filter for the admins, newest first
+func (s *LoginAttemptService) Recent(ctx context.Context, filter storage.LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	filter.Email = NormalizeLoginEmail(filter.Email)
+	return s.repo.List(ctx, filter)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// MailgunEmailSender sends emails through the Mailgun HTTP API
+type MailgunEmailSender struct {
+	apiBase    string
+	domain     string
+	apiKey     string
+	from       string
+	httpClient *http.Client
+}
+
+// NewMailgunEmailSender creates a Mailgun driver with MAILGUN_API_KEY, MAILGUN_DOMAIN and
+// EMAIL_FROM (from). MAILGUN_API_BASE selects the region (https://api.eu.mailgun.net for EU domains).
+func NewMailgunEmailSender(cfg config.MailgunConfig, from string) (*MailgunEmailSender, error) {
+	apiKey := cfg.APIKey
+	domain := cfg.Domain
+	if apiKey == "" || domain == "" {
+		return nil, errors.New("MAILGUN_API_KEY and MAILGUN_DOMAIN are required")
+	}
+	if from == "" {
+		return nil, errors.New("EMAIL_FROM is required by the mailgun driver")
+	}
+
+	apiBase := strings.TrimSuffix(cfg.APIBase, "/")
+	if apiBase == "" {
+		apiBase = "https://api.mailgun.net"
+	}
+	if _, err := url.Parse(apiBase); err != nil {
+		return nil, fmt.Errorf("invalid MAILGUN_API_BASE: %w", err)
+	}
+
+	return &MailgunEmailSender{
+		apiBase:    apiBase,
+		domain:     domain,
+		apiKey:     apiKey,
+		from:       emailFromAddress(),
+		httpClient: &http.Client{Timeout: emailSendTimeout},
+	}, nil
+}
+
+// Name returns the driver name
+func (m *MailgunEmailSender) Name() string {
+	return config.EmailDriverMailgun
+}
+
+// Send delivers the message with POST /v3/{domain}/messages
+func (m *MailgunEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("from", m.from)
+	for _, recipient := range msg.To {
+		writer.WriteField("to", recipient)
+	}
+	writer.WriteField("subject", msg.Subject)
+	writer.WriteField("html", msg.HTMLBody)
+	for _, attachment := range msg.Attachments {
+		part, err := writer.CreateFormFile("attachment", attachment.Name)
+		if err != nil {
+			return fmt.Errorf("failed to build attachment %s: %w", attachment.Name, err)
+		}
+		part.Write(attachment.Data)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to build mailgun request: %w", err)
+	}
+
+	req, err := m.newRequest(ctx, http.MethodPost, "/v3/"+url.PathEscape(m.domain)+"/messages", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	_, err = doEmailProviderRequest(m.httpClient, req, m.Name())
+	return err
+}
+
+// HealthCheck validates the API key and the sending domain with GET /v3/domains/{domain}
+func (m *MailgunEmailSender) HealthCheck(ctx context.Context) error {
+	req, err := m.newRequest(ctx, http.MethodGet, "/v3/domains/"+url.PathEscape(m.domain), nil)
+	if err != nil {
+		return err
+	}
+	_, err = doEmailProviderRequest(m.httpClient, req, m.Name())
+	return err
+}
+
+func (m *MailgunEmailSender) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, m.apiBase+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailgun request: %w", err)
+	}
+	req.SetBasicAuth("api", m.apiKey)
+	return req, nil
+}
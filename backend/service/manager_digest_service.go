@@ -0,0 +1,574 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultDigestSchedule runs the digest job every day at 7:00 when MANAGER_DIGEST_SCHEDULE is not set.
+	// Weekly digests go out on Mondays and monthly digests on the first day of the month.
+	defaultDigestSchedule = "0 7 * * *"
+	// digestExpirationWindow is how far ahead contract expirations are listed
+	digestExpirationWindow = 60 * 24 * time.Hour
+)
+
+// ManagerDigest summarizes the activity of the properties of a manager during a period
+type ManagerDigest struct {
+	ManagerID           uuid.UUID            `json:"manager_id"`
+	Frequency           string               `json:"frequency"`
+	From                time.Time            `json:"from"`
+	To                  time.Time            `json:"to"`
+	Payments            []DigestPayment      `json:"payments"`
+	PaymentsTotal       float64              `json:"payments_total"`
+	ArrearsChanges      []DigestArrearChange `json:"arrears_changes"`
+	ContractsSigned     []DigestRental       `json:"contracts_signed"`
+	UpcomingExpirations []DigestRental       `json:"upcoming_expirations"`
+	OpenMaintenance     []DigestMaintenance  `json:"open_maintenance"`
+	Arrears             map[string]int       `json:"-"` // Unpaid months per rental, stored to compute the next changes
+}
+
+// DigestPayment is a payment received during the digest period
+type DigestPayment struct {
+	RentalID        uuid.UUID `json:"rental_id"`
+	PropertyAddress string    `json:"property_address"`
+	Amount          float64   `json:"amount"`
+	PaymentDate     time.Time `json:"payment_date"`
+	PaidOnTime      bool      `json:"paid_on_time"`
+}
+
+// DigestArrearChange is a change in the unpaid months of a rental since the previous digest
+type DigestArrearChange struct {
+	RentalID          uuid.UUID `json:"rental_id"`
+	PropertyAddress   string    `json:"property_address"`
+	PreviousUnpaid    int       `json:"previous_unpaid_months"`
+	UnpaidMonths      int       `json:"unpaid_months"`
+	ChangeDescription string    `json:"change"`
+}
+
+// DigestRental is a rental listed in the digest (signed contract or upcoming expiration)
+type DigestRental struct {
+	RentalID        uuid.UUID `json:"rental_id"`
+	PropertyAddress string    `json:"property_address"`
+	Date            time.Time `json:"date"`
+}
+
+// DigestMaintenance is a maintenance request that is still open
+type DigestMaintenance struct {
+	ID              string    `json:"id"`
+	PropertyAddress string    `json:"property_address"`
+	Description     string    `json:"description"`
+	Status          string    `json:"status"`
+	RequestDate     time.Time `json:"request_date"`
+}
+
+// IsEmpty reports whether there is nothing to report in the digest
+func (d *ManagerDigest) IsEmpty() bool {
+	return len(d.Payments) == 0 && len(d.ArrearsChanges) == 0 && len(d.ContractsSigned) == 0 &&
+		len(d.UpcomingExpirations) == 0 && len(d.OpenMaintenance) == 0
+}
+
+// ManagerDigestService builds and sends the opt-in summary digest of each manager
+type ManagerDigestService struct {
+	digestRepo      storage.ManagerDigestStore
+	personRepo      storage.PersonStore
+	userRepo        storage.UserStore
+	propertyRepo    storage.PropertyStore
+	rentalRepo      storage.RentalStore
+	paymentRepo     storage.RentPaymentStore
+	signingRepo     storage.ContractSigningStore
+	maintenanceRepo storage.MaintenanceRequestStore
+	orgService      *OrganizationService
+	schedule        string
+}
+
+// NewManagerDigestService creates a ManagerDigestService sending the digests on schedule
+// (MANAGER_DIGEST_SCHEDULE)
+func NewManagerDigestService(repoFactory *storage.RepositoryFactory, orgService *OrganizationService, schedule string) *ManagerDigestService {
+	return &ManagerDigestService{
+		digestRepo:      repoFactory.GetManagerDigestRepository(),
+		personRepo:      repoFactory.GetPersonRepository(),
+		userRepo:        repoFactory.GetUserRepository(),
+		propertyRepo:    repoFactory.GetPropertyRepository(),
+		rentalRepo:      repoFactory.GetRentalRepository(),
+		paymentRepo:     repoFactory.GetRentPaymentRepository(),
+		signingRepo:     repoFactory.GetContractSigningRepository(),
+		maintenanceRepo: repoFactory.GetMaintenanceRequestRepository(),
+		orgService:      orgService,
+		schedule:        schedule,
+	}
+}
+
+// IsDigestDue reports whether a digest of the given frequency is sent on the day of now in loc.
+// lastSentAt prevents sending twice when the job runs more than once on the same day.
+func IsDigestDue(frequency string, lastSentAt *time.Time, now time.Time, loc *time.Location) bool {
+	local := now.In(loc)
+	var minGap time.Duration
+	switch frequency {
+	case model.DigestFrequencyWeekly:
+		if local.Weekday() != time.Monday {
+			return false
+		}
+		minGap = 6 * 24 * time.Hour
+	case model.DigestFrequencyMonthly:
+		if local.Day() != 1 {
+			return false
+		}
+		minGap = 27 * 24 * time.Hour
+	default:
+		return false
+	}
+	return lastSentAt == nil || now.Sub(*lastSentAt) >= minGap
+}
+
+// DigestPeriodStart returns where the period of a digest starts: the last digest sent or one
+// period before now
+func DigestPeriodStart(frequency string, lastSentAt *time.Time, now time.Time) time.Time {
+	if lastSentAt != nil {
+		return *lastSentAt
+	}
+	if frequency == model.DigestFrequencyMonthly {
+		return now.AddDate(0, -1, 0)
+	}
+	return now.AddDate(0, 0, -7)
+}
+
+// BuildDigest summarizes the properties of a manager between from and to. previousArrears are
+// the unpaid months reported by the previous digest.
+func (s *ManagerDigestService) BuildDigest(ctx context.Context, managerID uuid.UUID, frequency string, from, to time.Time, previousArrears map[string]int) (*ManagerDigest, error) {
+	digest := &ManagerDigest{
+		ManagerID:           managerID,
+		Frequency:           frequency,
+		From:                from,
+		To:                  to,
+		Payments:            []DigestPayment{},
+		ArrearsChanges:      []DigestArrearChange{},
+		ContractsSigned:     []DigestRental{},
+		UpcomingExpirations: []DigestRental{},
+		OpenMaintenance:     []DigestMaintenance{},
+		Arrears:             map[string]int{},
+	}
+
+	properties, err := s.propertyRepo.GetPropertiesForManager(ctx, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch properties of manager %s: %w", managerID, err)
+	}
+
+	addresses := make(map[string]string)     // Property ID -> address
+	rentalAddress := make(map[string]string) // Rental ID -> address
+	var propertyIDs, rentalIDs []string
+	for _, property := range properties {
+		address := property.Address
+		if property.AptNumber != "" {
+			address += " Apto " + property.AptNumber
+		}
+		addresses[property.ID.String()] = address
+		propertyIDs = append(propertyIDs, property.ID.String())
+	}
+
+	rentals, err := s.rentalRepo.GetByPropertyIDs(ctx, model.PropertyIDs(properties))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rentals of manager %s: %w", managerID, err)
+	}
+	for _, rental := range rentals {
+		address := addresses[rental.PropertyID.String()]
+		rentalAddress[rental.ID.String()] = address
+		rentalIDs = append(rentalIDs, rental.ID.String())
+
+		endDate := rental.EndDate.Time()
+		active := !endDate.Before(to)
+		if active && rental.UnpaidMonths > 0 {
+			digest.Arrears[rental.ID.String()] = rental.UnpaidMonths
+		}
+		if active && endDate.Before(to.Add(digestExpirationWindow)) {
+			digest.UpcomingExpirations = append(digest.UpcomingExpirations, DigestRental{
+				RentalID: rental.ID, PropertyAddress: address, Date: endDate,
+			})
+		}
+	}
+
+	// Payments received in the period
+	if len(rentalIDs) > 0 {
+		payments, err := s.paymentRepo.GetByRentalIDs(rentalIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch payments: %w", err)
+		}
+		for _, payment := range payments {
+			paymentDate := payment.PaymentDate.Time()
+			if paymentDate.Before(from) || !paymentDate.Before(to) {
+				continue
+			}
+			rentalID, _ := uuid.Parse(payment.RentalID)
+			digest.Payments = append(digest.Payments, DigestPayment{
+				RentalID:        rentalID,
+				PropertyAddress: rentalAddress[payment.RentalID],
+				Amount:          payment.AmountPaid,
+				PaymentDate:     paymentDate,
+				PaidOnTime:      payment.PaidOnTime,
+			})
+			digest.PaymentsTotal += payment.AmountPaid
+		}
+	}
+
+	// Arrears changes since the previous digest
+	for rentalID, unpaid := range digest.Arrears {
+		if previous := previousArrears[rentalID]; previous != unpaid {
+			digest.ArrearsChanges = append(digest.ArrearsChanges, newArrearChange(rentalID, rentalAddress[rentalID], previous, unpaid))
+		}
+	}
+	for rentalID, previous := range previousArrears {
+		if _, stillOwed := digest.Arrears[rentalID]; !stillOwed && previous > 0 {
+			digest.ArrearsChanges = append(digest.ArrearsChanges, newArrearChange(rentalID, rentalAddress[rentalID], previous, 0))
+		}
+	}
+
+	// Contracts signed in the period
+	if len(rentalIDs) > 0 {
+		signed, err := s.signingRepo.GetSignedBetween(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch signed contracts: %w", err)
+		}
+		for _, record := range signed {
+			address, ok := rentalAddress[record.ContractID]
+			if !ok || record.SignedAt == nil {
+				continue
+			}
+			rentalID, _ := uuid.Parse(record.ContractID)
+			digest.ContractsSigned = append(digest.ContractsSigned, DigestRental{
+				RentalID: rentalID, PropertyAddress: address, Date: *record.SignedAt,
+			})
+		}
+	}
+
+	// Maintenance requests still open
+	if len(propertyIDs) > 0 {
+		requests, err := s.maintenanceRepo.GetByPropertyIDs(propertyIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
+		}
+		for _, request := range requests {
+			if !IsOpenMaintenanceStatus(request.Status) {
+				continue
+			}
+			digest.OpenMaintenance = append(digest.OpenMaintenance, DigestMaintenance{
+				ID:              request.ID,
+				PropertyAddress: addresses[request.PropertyID],
+				Description:     request.Description,
+				Status:          request.Status,
+				RequestDate:     request.RequestDate.Time(),
+			})
+		}
+	}
+
+	sort.Slice(digest.Payments, func(i, j int) bool { return digest.Payments[i].PaymentDate.Before(digest.Payments[j].PaymentDate) })
+	sort.Slice(digest.ArrearsChanges, func(i, j int) bool {
+		return digest.ArrearsChanges[i].PropertyAddress < digest.ArrearsChanges[j].PropertyAddress
+	})
+	sort.Slice(digest.ContractsSigned, func(i, j int) bool { return digest.ContractsSigned[i].Date.Before(digest.ContractsSigned[j].Date) })
+	sort.Slice(digest.UpcomingExpirations, func(i, j int) bool {
+		return digest.UpcomingExpirations[i].Date.Before(digest.UpcomingExpirations[j].Date)
+	})
+	sort.Slice(digest.OpenMaintenance, func(i, j int) bool {
+		return digest.OpenMaintenance[i].RequestDate.Before(digest.OpenMaintenance[j].RequestDate)
+	})
+
+	return digest, nil
+}
+
+// newArrearChange describes the change of the unpaid months of a rental
+func newArrearChange(rentalID, address string, previous, unpaid int) DigestArrearChange {
+	id, _ := uuid.Parse(rentalID)
+	change := DigestArrearChange{RentalID: id, PropertyAddress: address, PreviousUnpaid: previous, UnpaidMonths: unpaid}
+	switch {
+	case previous == 0:
+		change.ChangeDescription = fmt.Sprintf("Entró en mora (%d meses)", unpaid)
+	case unpaid == 0:
+		change.ChangeDescription = "Se puso al día"
+	case unpaid > previous:
+		change.ChangeDescription = fmt.Sprintf("Aumentó la mora de %d a %d meses", previous, unpaid)
+	default:
+		change.ChangeDescription = fmt.Sprintf("Redujo la mora de %d a %d meses", previous, unpaid)
+	}
+	return change
+}
+
+// IsOpenMaintenanceStatus reports whether a maintenance request still needs attention
+func IsOpenMaintenanceStatus(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "completed", "cancelled":
+		return false
+	}
+	return true
+}
+
+// SendDueDigests sends the digests due on the local day of now, in the timezone of the
+// organization of each manager, and returns how many were sent. A non-nil loc limits them to
+// the managers whose organization is in that timezone.
+func (s *ManagerDigestService) SendDueDigests(ctx context.Context, now time.Time, loc *time.Location) (int, error) {
+	subscriptions, err := s.digestRepo.GetSubscribed(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, subscription := range subscriptions {
+		managerLoc := OrganizationLocation(s.managerOrganization(ctx, subscription.PersonID))
+		if loc != nil && managerLoc.String() != loc.String() {
+			continue
+		}
+		if !IsDigestDue(subscription.Frequency, subscription.LastSentAt, now, managerLoc) {
+			continue
+		}
+		if err := s.SendDigest(ctx, subscription, now); err != nil {
+			logging.FromContext(ctx).Error("digest for manager not sent", "component", "digest", "person_id", subscription.PersonID, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	logging.FromContext(ctx).Info("manager digests sent", "component", "digest", "sent", sent)
+	return sent, nil
+}
+
+// SendDigest builds and emails the digest of a subscription, then records it as sent
+func (s *ManagerDigestService) SendDigest(ctx context.Context, subscription model.ManagerDigestSubscription, now time.Time) error {
+	manager, err := s.personRepo.GetByID(ctx, subscription.PersonID)
+	if err != nil {
+		return err
+	}
+	if manager == nil {
+		return fmt.Errorf("manager %s not found", subscription.PersonID)
+	}
+	user, err := s.userRepo.GetByPersonID(ctx, subscription.PersonID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Email == "" {
+		return fmt.Errorf("manager %s has no email", subscription.PersonID)
+	}
+
+	from := DigestPeriodStart(subscription.Frequency, subscription.LastSentAt, now)
+	digest, err := s.BuildDigest(ctx, subscription.PersonID, subscription.Frequency, from, now, subscription.LastArrears)
+	if err != nil {
+		return err
+	}
+
+	subject, body, err := RenderDigestEmail(manager.FullName, digest, FormatterFor(s.managerOrganization(ctx, subscription.PersonID)))
+	if err != nil {
+		return err
+	}
+	if err := SendProtonMailEmail(user.Email, subject, body); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("digest sent", "component", "digest", "frequency", subscription.Frequency, "full_name", manager.FullName, "email", user.Email)
+
+	return s.digestRepo.MarkSent(ctx, subscription.PersonID, now, digest.Arrears)
+}
+
+// managerOrganization returns the organization of a manager, or nil
+func (s *ManagerDigestService) managerOrganization(ctx context.Context, managerID uuid.UUID) *model.Organization {
+	if s.orgService == nil {
+		return nil
+	}
+	return s.orgService.ForManager(ctx, managerID)
+}
+
+// locations returns the timezones of the organizations, APP_TIMEZONE included
+func (s *ManagerDigestService) locations() []*time.Location {
+	if s.orgService == nil {
+		return []*time.Location{AppLocation()}
+	}
+	return s.orgService.Locations(context.Background())
+}
+
+// Start registers the daily digest job (MANAGER_DIGEST_SCHEDULE, every day at 7:00 by default),
+// run at that local time in the timezone of each organization
+func (s *ManagerDigestService) Start() error {
+	schedule := s.schedule
+	if schedule == "" {
+		schedule = defaultDigestSchedule
+	}
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	err := addLocalJob(c, schedule, s.locations, func(loc *time.Location) {
+		if !JobEnabled("manager_digest") {
+			return
+		}
+		start := time.Now()
+		_, err := s.SendDueDigests(context.Background(), start, loc)
+		metrics.JobRun("manager_digest", start, err)
+		if err != nil {
+			slog.Error("error sending manager digests", "component", "digest", "timezone", loc.String(), "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid MANAGER_DIGEST_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("manager digest", c)
+
+	slog.Info("manager digest scheduler started", "component", "digest", "schedule", schedule)
+	return nil
+}
+
+// digestEmailData is the data of the digest email template
+type digestEmailData struct {
+	ManagerName         string
+	PeriodLabel         string
+	From                string
+	To                  string
+	Empty               bool
+	PaymentsTotal       string
+	Payments            []digestEmailRow
+	ArrearsChanges      []digestEmailRow
+	ContractsSigned     []digestEmailRow
+	UpcomingExpirations []digestEmailRow
+	OpenMaintenance     []digestEmailRow
+}
+
+// digestEmailRow is a line of a digest section
+type digestEmailRow struct {
+	Address string
+	Detail  string
+}
+
+// RenderDigestEmail returns the subject and HTML body of a digest email, with the dates and
+// amounts of formatter
+func RenderDigestEmail(managerName string, digest *ManagerDigest, formatter *Formatter) (string, string, error) {
+	periodLabel := "semanal"
+	if digest.Frequency == model.DigestFrequencyMonthly {
+		periodLabel = "mensual"
+	}
+
+	data := digestEmailData{
+		ManagerName:   managerName,
+		PeriodLabel:   periodLabel,
+		From:          formatter.Date(digest.From),
+		To:            formatter.Date(digest.To),
+		Empty:         digest.IsEmpty(),
+		PaymentsTotal: formatter.Money(digest.PaymentsTotal),
+	}
+	for _, payment := range digest.Payments {
+		detail := formatter.Money(payment.Amount) + " el " + formatter.Date(payment.PaymentDate)
+		if !payment.PaidOnTime {
+			detail += " (pago tardío)"
+		}
+		data.Payments = append(data.Payments, digestEmailRow{payment.PropertyAddress, detail})
+	}
+	for _, change := range digest.ArrearsChanges {
+		data.ArrearsChanges = append(data.ArrearsChanges, digestEmailRow{change.PropertyAddress, change.ChangeDescription})
+	}
+	for _, contract := range digest.ContractsSigned {
+		data.ContractsSigned = append(data.ContractsSigned, digestEmailRow{contract.PropertyAddress, "Firmado el " + formatter.Date(contract.Date)})
+	}
+	for _, expiration := range digest.UpcomingExpirations {
+		data.UpcomingExpirations = append(data.UpcomingExpirations, digestEmailRow{expiration.PropertyAddress, "Vence el " + formatter.Date(expiration.Date)})
+	}
+	for _, request := range digest.OpenMaintenance {
+		data.OpenMaintenance = append(data.OpenMaintenance, digestEmailRow{request.PropertyAddress, request.Description + " (" + request.Status + ")"})
+	}
+
+	tmpl, err := template.New("digest").Parse(digestEmailHTML)
+	if err != nil {
+		return "", "", err
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	subject := fmt.Sprintf("📊 Resumen %s de sus propiedades (%s - %s)", periodLabel, data.From, data.To)
+	return subject, body.String(), nil
+}
+
+const digestEmailHTML = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Resumen {{.PeriodLabel}}</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f8fafc; padding: 30px; border-radius: 0 0 8px 8px; }
+        .info { background: #dbeafe; padding: 15px; border-radius: 6px; margin: 20px 0; }
+        .footer { text-align: center; margin-top: 30px; color: #6b7280; font-size: 14px; }
+        ul { padding-left: 20px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📊 Resumen {{.PeriodLabel}}</h1>
+            <p>{{.From}} - {{.To}}</p>
+        </div>
+
+        <div class="content">
+            <h2>Hola {{.ManagerName}},</h2>
+            {{if .Empty}}
+            <p>No hubo novedades en sus propiedades durante este periodo.</p>
+            {{else}}
+            <p>Este es el resumen de la actividad de sus propiedades.</p>
+
+            {{if .Payments}}
+            <div class="info">
+                <h3>💰 Pagos recibidos ({{.PaymentsTotal}})</h3>
+                <ul>{{range .Payments}}<li><strong>{{.Address}}</strong>: {{.Detail}}</li>{{end}}</ul>
+            </div>
+            {{end}}
+
+            {{if .ArrearsChanges}}
+            <div class="info">
+                <h3>⚠️ Cambios en la mora</h3>
+                <ul>{{range .ArrearsChanges}}<li><strong>{{.Address}}</strong>: {{.Detail}}</li>{{end}}</ul>
+            </div>
+            {{end}}
+
+            {{if .ContractsSigned}}
+            <div class="info">
+                <h3>✍️ Contratos firmados</h3>
+                <ul>{{range .ContractsSigned}}<li><strong>{{.Address}}</strong>: {{.Detail}}</li>{{end}}</ul>
+            </div>
+            {{end}}
+
+            {{if .UpcomingExpirations}}
+            <div class="info">
+                <h3>📅 Contratos por vencer (próximos 60 días)</h3>
+                <ul>{{range .UpcomingExpirations}}<li><strong>{{.Address}}</strong>: {{.Detail}}</li>{{end}}</ul>
+            </div>
+            {{end}}
+
+            {{if .OpenMaintenance}}
+            <div class="info">
+                <h3>🛠️ Mantenimiento abierto</h3>
+                <ul>{{range .OpenMaintenance}}<li><strong>{{.Address}}</strong>: {{.Detail}}</li>{{end}}</ul>
+            </div>
+            {{end}}
+            {{end}}
+
+            <p>Saludos,<br>
+            <strong>Equipo de Rental Manager</strong></p>
+        </div>
+
+        <div class="footer">
+            <p>Puede cancelar este resumen desde su perfil en la plataforma.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
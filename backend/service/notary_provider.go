@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// NotaryStampRequest holds the signed contract submitted for notarial authentication
+type NotaryStampRequest struct {
+	NotarizationID string // Our notarization ID, sent as external reference
+	ContractID     string
+	DocumentName   string
+	PDFData        []byte
+	SignerName     string
+	SignerEmail    string
+}
+
+// NotaryStampResult is the state of a document on the notary service
+type NotaryStampResult struct {
+	ExternalID   string
+	Status       string // Normalized to one of the model.NotarizationStatus constants
+	Reference    string // Number of the notarial act
+	Observations string
+}
+
+// NotaryProvider submits signed contracts to a digital notary service for e-stamping
+type NotaryProvider interface {
+	// Name returns the identifier stored with the notarizations
+	Name() string
+	// Notary returns the name of the notary shown in the dossier
+	Notary() string
+	// SubmitDocument sends a signed PDF to be authenticated. The notary answers later through
+	// its webhook, the result is then pending.
+	SubmitDocument(ctx context.Context, req NotaryStampRequest) (*NotaryStampResult, error)
+	// ParseWebhook authenticates a webhook call and extracts the new state of a document
+	ParseWebhook(header http.Header, body []byte) (*NotaryStampResult, error)
+	// DownloadStampedDocument fetches the authenticated PDF
+	DownloadStampedDocument(ctx context.Context, externalID string) ([]byte, error)
+}
+
+// GetNotaryProvider returns the digital notary integration configured in cfg
+func GetNotaryProvider(cfg config.NotaryConfig) (NotaryProvider, error) {
+	return NewNotaryAPIProvider(cfg)
+}
+
+// NotaryAPIProvider implements NotaryProvider for notary services exposing a JSON document API
+// (POST /documents, GET /documents/:id/stamped) authenticated with a bearer token, whose
+// webhooks are signed with an HMAC-SHA256 of the body
+type NotaryAPIProvider struct {
+	apiURL        string
+	apiToken      string
+	webhookSecret string
+	notary        string
+	httpClient    *http.Client
+}
+
+// NewNotaryAPIProvider creates a notary provider from the NOTARY_* settings
+func NewNotaryAPIProvider(cfg config.NotaryConfig) (*NotaryAPIProvider, error) {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		return nil, errors.New("NOTARY_API_URL is not configured")
+	}
+
+	apiToken := cfg.APIToken
+	if apiToken == "" {
+		return nil, errors.New("NOTARY_API_TOKEN is not configured")
+	}
+
+	webhookSecret := cfg.WebhookSecret
+	if webhookSecret == "" {
+		return nil, errors.New("NOTARY_WEBHOOK_SECRET is not configured")
+	}
+
+	notary := cfg.Name
+	if notary == "" {
+		notary = "la notaría digital"
+	}
+
+	return &NotaryAPIProvider{
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		apiToken:      apiToken,
+		webhookSecret: webhookSecret,
+		notary:        notary,
+		httpClient:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *NotaryAPIProvider) Name() string {
+	return "notary"
+}
+
+// Notary returns the name of the notary
+func (p *NotaryAPIProvider) Notary() string {
+	return p.notary
+}
+
+type notaryDocument struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Reference    string `json:"reference"`
+	Observations string `json:"observations"`
+}
+
+// SubmitDocument uploads the signed contract for authentication
+func (p *NotaryAPIProvider) SubmitDocument(ctx context.Context, req NotaryStampRequest) (*NotaryStampResult, error) {
+	payload := map[string]interface{}{
+		"external_reference": req.NotarizationID,
+		"document_name":      req.DocumentName,
+		"pdf_base64":         base64.StdEncoding.EncodeToString(req.PDFData),
+		"signers": []map[string]string{
+			{"name": req.SignerName, "email": req.SignerEmail},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding notary request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/documents", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating notary request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	respBody, err := p.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var document notaryDocument
+	if err := json.Unmarshal(respBody, &document); err != nil {
+		return nil, fmt.Errorf("error parsing notary response: %w", err)
+	}
+	if document.ID == "" {
+		return nil, errors.New("notary response without document ID")
+	}
+	return document.result(), nil
+}
+
+// ParseWebhook checks the X-Notary-Signature header, the hex HMAC-SHA256 of the body
+func (p *NotaryAPIProvider) ParseWebhook(header http.Header, body []byte) (*NotaryStampResult, error) {
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(strings.ToLower(header.Get("X-Notary-Signature"))), []byte(expected)) {
+		return nil, errors.New("invalid notary webhook signature")
+	}
+
+	var document notaryDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("error parsing notary webhook: %w", err)
+	}
+	if document.ID == "" {
+		return nil, errors.New("notary webhook without document ID")
+	}
+	return document.result(), nil
+}
+
+// DownloadStampedDocument downloads the authenticated PDF of a document
+func (p *NotaryAPIProvider) DownloadStampedDocument(ctx context.Context, externalID string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/documents/"+externalID+"/stamped", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating notary request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/pdf")
+
+	return p.do(httpReq)
+}
+
+// result normalizes the document status, notaries answer in Spanish or English
+func (d notaryDocument) result() *NotaryStampResult {
+	status := model.NotarizationStatusPending
+	switch strings.ToLower(d.Status) {
+	case "stamped", "authenticated", "completed", "autenticado", "autenticada":
+		status = model.NotarizationStatusStamped
+	case "rejected", "rechazado", "rechazada":
+		status = model.NotarizationStatusRejected
+	}
+
+	return &NotaryStampResult{
+		ExternalID:   d.ID,
+		Status:       status,
+		Reference:    d.Reference,
+		Observations: d.Observations,
+	}
+}
+
+// do sends an authenticated request and returns the response body
+func (p *NotaryAPIProvider) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling notary: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading notary response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("notary API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+var (
+	// ErrInvalidNotificationPreference is returned for unknown channels, types or frequencies
+	ErrInvalidNotificationPreference = errors.New("invalid notification preference")
+	// ErrInvalidNotificationToken is returned for manage-preferences tokens that were not issued by this server
+	ErrInvalidNotificationToken = errors.New("invalid notification preferences token")
+)
+
+func (s *NotificationPreferenceService) signature(personID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("notification-preferences:" + personID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Token returns the token that lets a person manage their notification preferences without
+// logging in. It does not expire so unsubscribe links keep working.
+func (s *NotificationPreferenceService) Token(personID uuid.UUID) string {
+	return personID.String() + "." + s.signature(personID.String())
+}
+
+// ParseToken returns the person of a manage-preferences token
+func (s *NotificationPreferenceService) ParseToken(token string) (uuid.UUID, error) {
+	personID, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(s.signature(personID)), []byte(signature)) {
+		return uuid.Nil, ErrInvalidNotificationToken
+	}
+	id, err := uuid.Parse(personID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidNotificationToken
+	}
+	return id, nil
+}
+
+// URL returns the frontend page where a person manages their notifications. With a
+// notification type the page offers to unsubscribe from it.
+func (s *NotificationPreferenceService) URL(personID uuid.UUID, unsubscribeType string) string {
+	query := url.Values{"token": {s.Token(personID)}}
+	if unsubscribeType != "" {
+		query.Set("unsubscribe", unsubscribeType)
+	}
+	return GetAppBaseURL() + "/notification-preferences?" + query.Encode()
+}
+
+// withLinks adds the unsubscribe and manage-preferences links to the footer of a notification
+// email. They are added after rendering so customized templates cannot drop them. Without the
+// service there is no key to sign them and the body is returned as is.
+func (s *NotificationPreferenceService) withLinks(body string, personID uuid.UUID, notificationType string) string {
+	if s == nil {
+		return body
+	}
+	footer := fmt.Sprintf(`<p style="font-size:12px;color:#888;text-align:center;margin-top:24px">`+
+		`<a href="%s" style="color:#888">Dejar de recibir estos correos</a> · `+
+		`<a href="%s" style="color:#888">Gestionar preferencias de notificación</a></p>`,
+		html.EscapeString(s.URL(personID, notificationType)),
+		html.EscapeString(s.URL(personID, "")))
+
+	if strings.Contains(body, "</body>") {
+		return strings.Replace(body, "</body>", footer+"</body>", 1)
+	}
+	return body + footer
+}
+
+// NotificationPreferenceService decides which notifications a person receives and through
+// which channels
+type NotificationPreferenceService struct {
+	repo       storage.NotificationPreferenceStore
+	orgService *OrganizationService
+	secret     []byte
+}
+
+var notificationPreferences *NotificationPreferenceService
+
+// InitializeNotificationPreferences creates the service honoring the notification preferences
+// of the database. secret signs the tokens of the manage-preferences links.
+func InitializeNotificationPreferences(repoFactory *storage.RepositoryFactory, orgService *OrganizationService, secret string) *NotificationPreferenceService {
+	notificationPreferences = &NotificationPreferenceService{
+		repo:       repoFactory.GetNotificationPreferenceRepository(),
+		orgService: orgService,
+		secret:     []byte(secret),
+	}
+	return notificationPreferences
+}
+
+// location returns the timezone of the organization of a person, APP_TIMEZONE when they have none
+func (s *NotificationPreferenceService) location(ctx context.Context, personID uuid.UUID) *time.Location {
+	var org *model.Organization
+	if s != nil && s.orgService != nil {
+		org = s.orgService.ForPerson(ctx, personID)
+	}
+	return OrganizationLocation(org)
+}
+
+// GetNotificationPreferences returns the notification preference service, nil when every
+// person gets the default preferences
+func GetNotificationPreferences() *NotificationPreferenceService {
+	return notificationPreferences
+}
+
+// Preferences returns the preference of a person for every type and channel, with the
+// defaults for the ones they never changed
+func (s *NotificationPreferenceService) Preferences(ctx context.Context, personID uuid.UUID) ([]model.NotificationPreference, error) {
+	stored, err := s.repo.GetByPersonID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(model.NotificationTypes)*len(model.NotificationChannels))
+	for _, notificationType := range model.NotificationTypes {
+		for _, channel := range model.NotificationChannels {
+			preferences = append(preferences, findNotificationPreference(stored, personID, channel, notificationType))
+		}
+	}
+	return preferences, nil
+}
+
+// Update validates and saves preferences of a person. Types and channels not included keep
+// their current preference.
+func (s *NotificationPreferenceService) Update(ctx context.Context, personID uuid.UUID, preferences []model.NotificationPreference) ([]model.NotificationPreference, error) {
+	for i := range preferences {
+		preference := &preferences[i]
+		preference.PersonID = personID
+		if !slices.Contains(model.NotificationChannels, preference.Channel) {
+			return nil, fmt.Errorf("%w: unknown channel %q", ErrInvalidNotificationPreference, preference.Channel)
+		}
+		if !slices.Contains(model.NotificationTypes, preference.Type) {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidNotificationPreference, preference.Type)
+		}
+		if !slices.Contains(model.NotificationFrequencies, preference.Frequency) {
+			return nil, fmt.Errorf("%w: unknown frequency %q", ErrInvalidNotificationPreference, preference.Frequency)
+		}
+	}
+
+	if err := s.repo.Save(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return s.Preferences(ctx, personID)
+}
+
+// Unsubscribe stops the emails of a type to a person, or all their notification emails when
+// notificationType is empty
+func (s *NotificationPreferenceService) Unsubscribe(ctx context.Context, personID uuid.UUID, notificationType string) ([]model.NotificationPreference, error) {
+	types := model.NotificationTypes
+	if notificationType != "" {
+		types = []string{notificationType}
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(types))
+	for _, t := range types {
+		preferences = append(preferences, model.NotificationPreference{
+			Channel:   model.NotificationChannelEmail,
+			Type:      t,
+			Frequency: model.NotificationFrequencyNever,
+		})
+	}
+	return s.Update(ctx, personID, preferences)
+}
+
+// Notify calls send when the preference of the person allows a notification of the type
+// through the channel, and records the delivery for the monthly frequency. sent is false when
+// the person opted out. If the preferences cannot be loaded the default of the channel applies.
+func (s *NotificationPreferenceService) Notify(ctx context.Context, personID uuid.UUID, channel, notificationType string, send func() error) (sent bool, err error) {
+	var stored []model.NotificationPreference
+	if s != nil {
+		stored, err = s.repo.GetByPersonID(ctx, personID)
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not load the preferences of person, using the defaults", "component", "notifications", "person_id", personID, "error", err)
+			stored = nil
+		}
+	}
+
+	now := time.Now()
+	preference := findNotificationPreference(stored, personID, channel, notificationType)
+	if preference.Frequency == model.NotificationFrequencyMonthly {
+		// The months are those of the timezone of the organization of the person
+		now = now.In(s.location(ctx, personID))
+	}
+	if !notificationAllowed(preference, now) {
+		logging.FromContext(ctx).Info("skipped for person", "component", "notifications", "notification_type", notificationType, "channel", channel, "person_id", personID, "frequency", preference.Frequency)
+		return false, nil
+	}
+
+	if err := send(); err != nil {
+		return false, err
+	}
+
+	if s != nil && preference.ID != uuid.Nil {
+		if err := s.repo.MarkSent(ctx, preference.ID, now); err != nil {
+			logging.FromContext(ctx).Warn("could not record the delivery to person", "component", "notifications", "notification_type", notificationType, "person_id", personID, "error", err)
+		}
+	}
+	return true, nil
+}
+
+// findNotificationPreference returns the stored preference for a type and channel, or the default one
+func findNotificationPreference(stored []model.NotificationPreference, personID uuid.UUID, channel, notificationType string) model.NotificationPreference {
+	for _, preference := range stored {
+		if preference.Channel == channel && preference.Type == notificationType {
+			return preference
+		}
+	}
+	return model.NotificationPreference{
+		PersonID:  personID,
+		Channel:   channel,
+		Type:      notificationType,
+		Frequency: model.DefaultNotificationFrequency(channel),
+	}
+}
+
+// notificationAllowed reports whether a preference allows a notification at now, whose location
+// is the one of the months of the monthly frequency
+func notificationAllowed(preference model.NotificationPreference, now time.Time) bool {
+	switch preference.Frequency {
+	case model.NotificationFrequencyAlways:
+		return true
+	case model.NotificationFrequencyMonthly:
+		if preference.LastSentAt == nil {
+			return true
+		}
+		last := preference.LastSentAt.In(now.Location())
+		return last.Year() != now.Year() || last.Month() != now.Month()
+	default:
+		return false
+	}
+}
+
+// sendNotificationSMS texts a notification to a person who opted in to SMS for its type. It
+// does nothing when no SMS gateway is configured or the person has no phone.
+func sendNotificationSMS(ctx context.Context, person *model.Person, notificationType, message string) {
+	if !SMSEnabled() || person.Phone == "" {
+		return
+	}
+
+	sent, err := GetNotificationPreferences().Notify(ctx, person.ID, model.NotificationChannelSMS, notificationType, func() error {
+		return SendSMS(person.Phone, message)
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("SMS not sent", "component", "notifications", "notification_type", notificationType, "full_name", person.FullName, "phone", person.Phone, "error", err)
+	} else if sent {
+		logging.FromContext(ctx).Info("SMS sent", "component", "notifications", "notification_type", notificationType, "full_name", person.FullName, "phone", person.Phone)
+	}
+}
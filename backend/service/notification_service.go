@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// notificationRetention is how long the notifications are kept once read
+	notificationRetention = 90 * 24 * time.Hour
+	// notificationPruneInterval is how often the old read notifications are deleted
+	notificationPruneInterval = 24 * time.Hour
+)
+
+// ErrNotificationNotFound is returned when marking as read a notification that is not an
+// unread notification of the user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationService fills the in-app notification feed of the users with the events of the
+// platform: registered payments, completed signatures and maintenance status changes. The
+// events are recorded in the background and a failure never affects the request behind them.
+type NotificationService struct {
+	repo         storage.NotificationStore
+	userRepo     storage.UserStore
+	rentalRepo   storage.RentalStore
+	propertyRepo storage.PropertyStore
+	signingRepo  storage.ContractSigningStore
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+var notifications *NotificationService
+
+// InitializeNotifications creates the service recording the in-app notifications
+func InitializeNotifications(repoFactory *storage.RepositoryFactory) *NotificationService {
+	notifications = &NotificationService{
+		repo:         repoFactory.GetNotificationRepository(),
+		userRepo:     repoFactory.GetUserRepository(),
+		rentalRepo:   repoFactory.GetRentalRepository(),
+		propertyRepo: repoFactory.GetPropertyRepository(),
+		signingRepo:  repoFactory.GetContractSigningRepository(),
+	}
+	return notifications
+}
+
+// GetNotifications returns the in-app notification service, nil when it was not initialized
+func GetNotifications() *NotificationService {
+	return notifications
+}
+
+// List returns a page of the notifications of a user, newest first, with their total
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]model.Notification, int, error) {
+	return s.repo.GetByUser(ctx, userID, unreadOnly, limit, offset)
+}
+
+// UnreadCount returns how many notifications of a user have not been read, for the bell icon
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks a notification of a user as read
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	read, err := s.repo.MarkRead(ctx, notificationID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !read {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of a user as read and returns how many were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID, time.Now())
+}
+
+// PaymentReceived notifies a registered rent payment to the tenant who paid it and to the
+// managers of the property. It does nothing when s is nil.
+func (s *NotificationService) PaymentReceived(payment storage.RentPayment) {
+	if s == nil {
+		return
+	}
+
+	runInBackground(func() {
+		ctx := context.Background()
+		rentalID, err := uuid.Parse(payment.RentalID)
+		if err != nil {
+			return
+		}
+		rental, err := s.rentalRepo.GetByID(ctx, rentalID)
+		if err != nil || rental == nil {
+			slog.Warn("rental of payment not found", "component", "notifications", "rental_id", payment.RentalID, "payment_id", payment.ID, "error", err)
+			return
+		}
+
+		address := ""
+		if property, err := s.propertyRepo.GetByID(ctx, rental.PropertyID); err == nil && property != nil {
+			address = property.Address
+		}
+		amount := FormatMoney(payment.AmountPaid)
+
+		s.notifyPersons(ctx, []uuid.UUID{rental.RenterID}, model.Notification{
+			Type:     model.NotificationPaymentReceived,
+			Title:    "Pago registrado",
+			Message:  fmt.Sprintf("Registramos tu pago de %s del arriendo de %s.", amount, address),
+			EntityID: payment.ID,
+		})
+
+		managerIDs, err := s.propertyRepo.GetManagerIDsForProperty(ctx, rental.PropertyID)
+		if err != nil {
+			slog.Warn("could not load the managers of property", "component", "notifications", "property_id", rental.PropertyID, "error", err)
+			return
+		}
+		s.notifyPersons(ctx, managerIDs, model.Notification{
+			Type:     model.NotificationPaymentReceived,
+			Title:    "Pago recibido",
+			Message:  fmt.Sprintf("Se recibió un pago de %s del arriendo de %s.", amount, address),
+			EntityID: payment.ID,
+		})
+	})
+}
+
+// SignatureCompleted notifies the admin or manager who requested a signature that it was
+// signed. It does nothing when s is nil.
+func (s *NotificationService) SignatureCompleted(signingID string) {
+	if s == nil {
+		return
+	}
+
+	runInBackground(func() {
+		ctx := context.Background()
+		record, err := s.signingRepo.GetByID(ctx, signingID)
+		if err != nil || record == nil {
+			slog.Warn("signing request not found", "component", "notifications", "signing_id", signingID, "error", err)
+			return
+		}
+		requesterID, err := uuid.Parse(record.RequestedBy)
+		if err != nil {
+			return
+		}
+
+		document := "el contrato"
+		if record.IsInspection() {
+			document = "el acta de inspección"
+		}
+		s.notifyPersons(ctx, []uuid.UUID{requesterID}, model.Notification{
+			Type:     model.NotificationSignatureCompleted,
+			Title:    "Firma completada",
+			Message:  fmt.Sprintf("%s firmó %s.", record.RecipientEmail, document),
+			EntityID: record.ID,
+		})
+	})
+}
+
+// MaintenanceUpdated notifies the tenant who opened a maintenance request that its status
+// changed. It does nothing when s is nil.
+func (s *NotificationService) MaintenanceUpdated(request storage.MaintenanceRequest, previousStatus string) {
+	if s == nil {
+		return
+	}
+
+	runInBackground(func() {
+		renterID, err := uuid.Parse(request.RenterID)
+		if err != nil || renterID == uuid.Nil {
+			return
+		}
+		s.notifyPersons(context.Background(), []uuid.UUID{renterID}, model.Notification{
+			Type:     model.NotificationMaintenanceUpdated,
+			Title:    "Solicitud de mantenimiento actualizada",
+			Message:  fmt.Sprintf("Tu solicitud \"%s\" pasó de %s a %s.", request.Description, previousStatus, request.Status),
+			EntityID: request.ID,
+		})
+	})
+}
+
+// notifyPersons records a copy of notification for the user of each person. Persons without a
+// user cannot see the feed and are skipped.
+func (s *NotificationService) notifyPersons(ctx context.Context, personIDs []uuid.UUID, notification model.Notification) {
+	now := time.Now()
+	batch := make([]model.Notification, 0, len(personIDs))
+	for _, personID := range personIDs {
+		user, err := s.userRepo.GetByPersonID(ctx, personID)
+		if err != nil || user == nil {
+			continue
+		}
+		entry := notification
+		entry.ID = uuid.New()
+		entry.UserID = user.ID
+		entry.CreatedAt = now
+		batch = append(batch, entry)
+	}
+
+	if err := s.repo.Create(ctx, batch); err != nil {
+		logging.FromContext(ctx).Warn("could not record", "component", "notifications", "type", notification.Type, "error", err)
+		return
+	}
+	s.pruneRead(ctx, now)
+}
+
+// pruneRead deletes the notifications read more than notificationRetention ago, once per
+// notificationPruneInterval
+func (s *NotificationService) pruneRead(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < notificationPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	if err := s.repo.DeleteReadBefore(ctx, now.Add(-notificationRetention)); err != nil {
+		logging.FromContext(ctx).Warn("could not delete the old read notifications", "component", "notifications", "error", err)
+	}
+}
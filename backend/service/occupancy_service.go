@@ -0,0 +1,135 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of a rental in the occupancy timeline of a unit. Rentals closed in the rental history
+// take the status recorded there (renewed, transferred...).
+const (
+	OccupancyActive   = "active"
+	OccupancyUpcoming = "upcoming"
+	OccupancyEnded    = "ended"
+)
+
+// OccupancyPeriod is a rental of a unit in its occupancy timeline. Dates are whole days and the
+// end date is the last occupied day.
+type OccupancyPeriod struct {
+	RentalID    uuid.UUID `json:"rental_id"`
+	RenterID    uuid.UUID `json:"renter_id"`
+	TenantName  string    `json:"tenant_name"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	MonthlyRent float64   `json:"monthly_rent"`
+	Status      string    `json:"status"`
+	EndReason   string    `json:"end_reason,omitempty"`
+	Days        int       `json:"days"`
+}
+
+// OccupancyGap is a period without rental between the rentals of a unit
+type OccupancyGap struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Days      int       `json:"days"`
+}
+
+// OccupancyTimeline is the rentals and vacant periods of a unit within a date range
+type OccupancyTimeline struct {
+	PropertyID    uuid.UUID         `json:"property_id"`
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	Periods       []OccupancyPeriod `json:"periods"`
+	Gaps          []OccupancyGap    `json:"gaps"`
+	TotalDays     int               `json:"total_days"`
+	OccupiedDays  int               `json:"occupied_days"`
+	VacantDays    int               `json:"vacant_days"`
+	OccupancyRate float64           `json:"occupancy_rate"` // Percentage of the days of the range with a rental
+}
+
+// BuildOccupancyTimeline sorts the rentals of a unit and computes its vacant periods and
+// occupancy rate between from and to, both included, counting the days in loc, the timezone of
+// the organization of the unit. Overlapping rentals count once.
+func BuildOccupancyTimeline(propertyID uuid.UUID, periods []OccupancyPeriod, from, to time.Time, loc *time.Location) OccupancyTimeline {
+	from, to = calendarDayIn(from, loc), calendarDayIn(to, loc)
+	timeline := OccupancyTimeline{
+		PropertyID: propertyID,
+		From:       from,
+		To:         to,
+		Periods:    []OccupancyPeriod{},
+		Gaps:       []OccupancyGap{},
+	}
+	if to.Before(from) {
+		return timeline
+	}
+	timeline.TotalDays = daysBetween(from, to)
+
+	sorted := make([]OccupancyPeriod, len(periods))
+	copy(sorted, periods)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartDate.Before(sorted[j].StartDate) })
+
+	// Days of the range not covered by a rental yet
+	cursor := from
+	for _, period := range sorted {
+		period.StartDate, period.EndDate = calendarDayIn(period.StartDate, loc), calendarDayIn(period.EndDate, loc)
+		if period.EndDate.Before(period.StartDate) {
+			period.EndDate = period.StartDate
+		}
+		period.Days = daysBetween(period.StartDate, period.EndDate)
+		if period.EndDate.Before(from) || period.StartDate.After(to) {
+			continue
+		}
+		timeline.Periods = append(timeline.Periods, period)
+
+		start, end := period.StartDate, period.EndDate
+		if start.Before(cursor) {
+			start = cursor
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.Before(start) {
+			continue // Within a rental already counted
+		}
+		if start.After(cursor) {
+			gapEnd := start.AddDate(0, 0, -1)
+			timeline.Gaps = append(timeline.Gaps, OccupancyGap{StartDate: cursor, EndDate: gapEnd, Days: daysBetween(cursor, gapEnd)})
+		}
+		timeline.OccupiedDays += daysBetween(start, end)
+		cursor = end.AddDate(0, 0, 1)
+	}
+	if !cursor.After(to) {
+		timeline.Gaps = append(timeline.Gaps, OccupancyGap{StartDate: cursor, EndDate: to, Days: daysBetween(cursor, to)})
+	}
+
+	timeline.VacantDays = timeline.TotalDays - timeline.OccupiedDays
+	timeline.OccupancyRate = float64(int(float64(timeline.OccupiedDays)/float64(timeline.TotalDays)*10000+0.5)) / 100
+	return timeline
+}
+
+// OccupancyStatus is the status of a rental that has no closing record in the rental history,
+// with the days counted in loc, the timezone of the organization of the rental
+func OccupancyStatus(start, end, today time.Time, loc *time.Location) string {
+	today = calendarDayIn(today, loc)
+	switch {
+	case calendarDayIn(start, loc).After(today):
+		return OccupancyUpcoming
+	case calendarDayIn(end, loc).Before(today):
+		return OccupancyEnded
+	default:
+		return OccupancyActive
+	}
+}
+
+// calendarDayIn truncates a time to the start of its day in a time zone
+func calendarDayIn(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// daysBetween counts the days from start to end, both included
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24+0.5) + 1
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// organizationCacheTTL is how long the organization list is kept before reloading it
+const organizationCacheTTL = 5 * time.Minute
+
+// OrganizationService resolves the organization behind a host, property or person and
+// builds public links with the organization base URL. Organizations are cached in memory
+// because they are read on every public request.
+type OrganizationService struct {
+	orgRepo      storage.OrganizationStore
+	propertyRepo storage.PropertyStore
+	rentalRepo   storage.RentalStore
+
+	mu            sync.RWMutex
+	organizations []model.Organization
+	loadedAt      time.Time
+}
+
+// NewOrganizationService creates a new OrganizationService
+func NewOrganizationService(orgRepo storage.OrganizationStore, propertyRepo storage.PropertyStore, rentalRepo storage.RentalStore) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:      orgRepo,
+		propertyRepo: propertyRepo,
+		rentalRepo:   rentalRepo,
+	}
+}
+
+// publicURLs holds the base URLs InitializePublicURLs was called with
+var publicURLs config.URLConfig
+
+// InitializePublicURLs sets the base URLs of the frontend and of this backend the emails, PDFs
+// and public links point to
+func InitializePublicURLs(cfg config.URLConfig) {
+	publicURLs = cfg
+}
+
+// GetAppBaseURL returns the global base URL of the frontend (APP_BASE_URL)
+func GetAppBaseURL() string {
+	baseURL := publicURLs.AppBaseURL
+	if baseURL == "" {
+		return config.DefaultAppBaseURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// GetAPIBaseURL returns the public URL of this backend (API_BASE_URL), used for the email
+// open tracking pixel. Returns "" when not configured, in which case opens are not tracked.
+func GetAPIBaseURL() string {
+	return strings.TrimSuffix(publicURLs.APIBaseURL, "/")
+}
+
+// OrganizationBaseURL returns the base URL for the public links of an organization,
+// falling back to APP_BASE_URL when the organization is nil or has no domain configured
+func OrganizationBaseURL(org *model.Organization) string {
+	if org != nil {
+		if org.BaseURL != "" {
+			return strings.TrimSuffix(org.BaseURL, "/")
+		}
+		if org.CustomDomain != "" {
+			return "https://" + org.CustomDomain
+		}
+	}
+	return GetAppBaseURL()
+}
+
+// PublicLink builds an absolute public link (path must start with "/") for an organization
+func PublicLink(org *model.Organization, path string) string {
+	return OrganizationBaseURL(org) + path
+}
+
+// Invalidate drops the cached organizations, called after they are modified
+func (s *OrganizationService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.organizations = nil
+	s.loadedAt = time.Time{}
+}
+
+// list returns the cached organizations, reloading them when stale
+func (s *OrganizationService) list(ctx context.Context) []model.Organization {
+	s.mu.RLock()
+	if s.organizations != nil && time.Since(s.loadedAt) < organizationCacheTTL {
+		organizations := s.organizations
+		s.mu.RUnlock()
+		return organizations
+	}
+	s.mu.RUnlock()
+
+	organizations, err := s.orgRepo.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not load organizations, using global base URL", "error", err)
+		return nil
+	}
+	if organizations == nil {
+		organizations = []model.Organization{}
+	}
+
+	s.mu.Lock()
+	s.organizations = organizations
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return organizations
+}
+
+// ResolveHost returns the organization whose custom domain or base URL matches the host, or nil
+func (s *OrganizationService) ResolveHost(ctx context.Context, host string) *model.Organization {
+	host = normalizeHost(host)
+	if host == "" {
+		return nil
+	}
+
+	for _, org := range s.list(ctx) {
+		if host == normalizeHost(org.CustomDomain) || host == hostOfURL(org.BaseURL) {
+			found := org
+			return &found
+		}
+	}
+	return nil
+}
+
+// IsAllowedOrigin reports whether a CORS origin belongs to a registered organization domain
+func (s *OrganizationService) IsAllowedOrigin(origin string) bool {
+	return s.ResolveHost(context.Background(), hostOfURL(origin)) != nil
+}
+
+// ByID returns a registered organization, or nil
+func (s *OrganizationService) ByID(ctx context.Context, id uuid.UUID) *model.Organization {
+	for _, org := range s.list(ctx) {
+		if org.ID == id {
+			found := org
+			return &found
+		}
+	}
+	return nil
+}
+
+// ForManager returns the organization a manager belongs to, or nil
+func (s *OrganizationService) ForManager(ctx context.Context, managerID uuid.UUID) *model.Organization {
+	for _, org := range s.list(ctx) {
+		for _, id := range org.ManagerIDs {
+			if id == managerID {
+				found := org
+				return &found
+			}
+		}
+	}
+	return nil
+}
+
+// ForProperty returns the organization of the managers of a property, or nil
+func (s *OrganizationService) ForProperty(ctx context.Context, propertyID uuid.UUID) *model.Organization {
+	property, err := s.propertyRepo.GetByID(ctx, propertyID)
+	if err != nil || property == nil {
+		return nil
+	}
+
+	for _, managerID := range property.ManagerIDs {
+		if org := s.ForManager(ctx, managerID); org != nil {
+			return org
+		}
+	}
+	return nil
+}
+
+// ForRental returns the organization of the property of a rental, or nil
+func (s *OrganizationService) ForRental(ctx context.Context, rentalID uuid.UUID) *model.Organization {
+	rental, err := s.rentalRepo.GetByID(ctx, rentalID)
+	if err != nil || rental == nil {
+		return nil
+	}
+	return s.ForProperty(ctx, rental.PropertyID)
+}
+
+// Locations returns the timezones in use: APP_TIMEZONE and those of the organizations
+func (s *OrganizationService) Locations(ctx context.Context) []*time.Location {
+	locations := []*time.Location{AppLocation()}
+	seen := map[string]bool{AppLocation().String(): true}
+	organizations := s.list(ctx)
+	for i := range organizations {
+		loc := OrganizationLocation(&organizations[i])
+		if !seen[loc.String()] {
+			seen[loc.String()] = true
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
+
+// ForPerson returns the organization of a manager, or of the properties a renter rents, or nil
+func (s *OrganizationService) ForPerson(ctx context.Context, personID uuid.UUID) *model.Organization {
+	if org := s.ForManager(ctx, personID); org != nil {
+		return org
+	}
+
+	rentals, err := s.rentalRepo.GetByRenterID(ctx, personID)
+	if err != nil {
+		return nil
+	}
+	for _, rental := range rentals {
+		if org := s.ForProperty(ctx, rental.PropertyID); org != nil {
+			return org
+		}
+	}
+	return nil
+}
+
+// normalizeHost lowercases a host and strips its port
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// hostOfURL returns the normalized host of a URL, or "" if it cannot be parsed
+func hostOfURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return normalizeHost(parsed.Host)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// DefaultPasswordResetTTL is how long a reset link is valid by default
+const DefaultPasswordResetTTL = time.Hour
+
+// ErrInvalidPasswordResetToken is returned for reset tokens that are malformed, expired, not
+// issued by this server or already used
+var ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+
+// signature signs a token with the stored credential of the user, so a token stops working once
+// the password changes: it can be used once, and only for the latest credential
+func (s *PasswordResetService) signature(userID, expires, credential string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("password-reset:" + userID + "." + expires + "." + credential))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PasswordResetService issues the emailed reset links of forgotten passwords and sets the new
+// password of a valid link, signing the user out of every session. Every request and reset is
+// written to the audit log.
+type PasswordResetService struct {
+	users    storage.UserStore
+	orgs     *OrganizationService
+	sessions *SessionService
+	secret   []byte
+	ttl      time.Duration
+}
+
+// NewPasswordResetService creates a PasswordResetService signing the links with secret
+// (PASSWORD_RESET_SECRET), valid for ttl (PASSWORD_RESET_TTL, DefaultPasswordResetTTL when
+// unset). The links point to the frontend of the organization of each user (see
+// OrganizationBaseURL).
+func NewPasswordResetService(users storage.UserStore, orgs *OrganizationService, sessions *SessionService, secret string, ttl time.Duration) *PasswordResetService {
+	return &PasswordResetService{
+		users:    users,
+		orgs:     orgs,
+		sessions: sessions,
+		secret:   []byte(secret),
+		ttl:      orDefault(ttl, DefaultPasswordResetTTL),
+	}
+}
+
+// baseURL returns the frontend the reset link of user points to, the one of their organization
+// and never one taken from the request
+func (s *PasswordResetService) baseURL(ctx context.Context, user *model.User) string {
+	var org *model.Organization
+	if s.orgs != nil {
+		org = s.orgs.ForPerson(ctx, user.PersonID)
+	}
+	return OrganizationBaseURL(org)
+}
+
+// Token returns a reset token of user valid until expiresAt
+func (s *PasswordResetService) Token(user *model.User, expiresAt time.Time) string {
+	userID := user.ID.String()
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return userID + "." + expires + "." + s.signature(userID, expires, user.PasswordBase64)
+}
+
+// userOfToken returns the user a token was issued to, while it is valid
+func (s *PasswordResetService) userOfToken(ctx context.Context, token string, now time.Time) (*model.User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidPasswordResetToken
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, ErrInvalidPasswordResetToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return nil, ErrInvalidPasswordResetToken
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status == "disabled" {
+		return nil, ErrInvalidPasswordResetToken
+	}
+	if !hmac.Equal([]byte(s.signature(parts[0], parts[1], user.PasswordBase64)), []byte(parts[2])) {
+		return nil, ErrInvalidPasswordResetToken
+	}
+	return user, nil
+}
+
+// RequestReset emails the user of email a reset link to the frontend of their organization.
+// Unknown and disabled emails are only audited, so the callers cannot tell which emails have an
+// account. The email is sent in the background for the same reason.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email, ip string, now time.Time) error {
+	audit := logging.FromContext(ctx).With("audit", "password_reset", "email", NormalizeLoginEmail(email), "client_ip", ip)
+
+	user, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		audit.Info("password reset requested for an unknown email")
+		return nil
+	}
+	if user.Status == "disabled" {
+		audit.Info("password reset requested for a disabled user", "user_id", user.ID)
+		return nil
+	}
+
+	expiresAt := now.Add(s.ttl)
+	link := s.baseURL(ctx, user) + "/reset-password?" + url.Values{"token": {s.Token(user, expiresAt)}}.Encode()
+	audit.Info("password reset link issued", "user_id", user.ID, "expires_at", expiresAt)
+
+	runInBackground(func() {
+		if err := SendPasswordResetEmail(user.Email, link, s.ttl); err != nil {
+			audit.Error("could not send the password reset email", "user_id", user.ID, "error", err)
+		}
+	})
+	return nil
+}
+
+// ResetPassword sets the new password of the user of a valid token, which cannot be used again,
+// and revokes the sessions of the user so a stolen session does not survive the reset
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword, ip string, now time.Time) (*model.User, error) {
+	audit := logging.FromContext(ctx).With("audit", "password_reset", "client_ip", ip)
+
+	user, err := s.userOfToken(ctx, token, now)
+	if errors.Is(err, ErrInvalidPasswordResetToken) {
+		audit.Warn("password reset rejected: invalid or expired token")
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored the way the frontend sends it on login, as ChangePassword does
+	user.PasswordBase64 = base64.StdEncoding.EncodeToString([]byte(newPassword))
+	updated, err := s.users.Update(ctx, *user)
+	if err != nil {
+		audit.Error("password reset failed", "user_id", user.ID, "email", user.Email, "error", err)
+		return nil, err
+	}
+
+	audit.Info("password reset completed", "user_id", user.ID, "email", user.Email)
+
+	if s.sessions != nil {
+		revoked, err := s.sessions.RevokeAll(ctx, user.ID)
+		if err != nil {
+			audit.Error("could not revoke the sessions after the password reset", "user_id", user.ID, "error", err)
+		} else {
+			audit.Info("sessions revoked after the password reset", "user_id", user.ID, "revoked", revoked)
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+var (
+	// ErrNoActiveRental is returned when the tenant has no rental in force to pay
+	ErrNoActiveRental = errors.New("no active rental to pay")
+	// ErrRentAlreadyPaid is returned when the rent of the current month is already registered
+	ErrRentAlreadyPaid = errors.New("the rent of this month is already paid")
+	// ErrNoRentAmount is returned when the rental has no pricing to charge
+	ErrNoRentAmount = errors.New("the rental has no monthly amount to pay")
+	// ErrCheckoutCurrency is returned for rentals priced in a currency the gateway does not charge
+	ErrCheckoutCurrency = errors.New("online payments are only available for rents in COP")
+)
+
+// RentCheckout is a started checkout with the page where the tenant pays it
+type RentCheckout struct {
+	Checkout    model.PaymentCheckout `json:"checkout"`
+	CheckoutURL string                `json:"checkout_url"`
+}
+
+// PaymentCheckoutService lets tenants pay the rent of the month through the payment gateway and
+// registers the rent payments the gateway confirms
+type PaymentCheckoutService struct {
+	gateway      PaymentGateway
+	checkoutRepo storage.PaymentCheckoutStore
+	rentalRepo   storage.RentalStore
+	pricingRepo  storage.PricingStore
+	paymentRepo  storage.RentPaymentStore
+	orgService   *OrganizationService
+}
+
+// NewPaymentCheckoutService creates the online rent payment service for a gateway
+func NewPaymentCheckoutService(repoFactory *storage.RepositoryFactory, gateway PaymentGateway, orgService *OrganizationService) *PaymentCheckoutService {
+	return &PaymentCheckoutService{
+		gateway:      gateway,
+		checkoutRepo: repoFactory.GetPaymentCheckoutRepository(),
+		rentalRepo:   repoFactory.GetRentalRepository(),
+		pricingRepo:  repoFactory.GetPricingRepository(),
+		paymentRepo:  repoFactory.GetRentPaymentRepository(),
+		orgService:   orgService,
+	}
+}
+
+// location returns the timezone of the organization of a property, where the billing periods
+// and due days of its rentals are counted
+func (s *PaymentCheckoutService) location(ctx context.Context, propertyID uuid.UUID) *time.Location {
+	var org *model.Organization
+	if s.orgService != nil {
+		org = s.orgService.ForProperty(ctx, propertyID)
+	}
+	return OrganizationLocation(org)
+}
+
+// CreateCheckout starts the payment of the rent of the current month of a tenant
+func (s *PaymentCheckoutService) CreateCheckout(ctx context.Context, personID uuid.UUID, email string) (*RentCheckout, error) {
+	now := time.Now()
+	rental, err := s.currentRental(ctx, personID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := s.location(ctx, rental.PropertyID)
+	period := model.BillingPeriod(now.In(loc))
+	if paid, err := s.periodPaid(ctx, rental.ID, period, loc); err != nil {
+		return nil, err
+	} else if paid {
+		return nil, ErrRentAlreadyPaid
+	}
+
+	pricing, err := s.pricingRepo.GetByRentalID(ctx, rental.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pricing == nil {
+		return nil, ErrNoRentAmount
+	}
+	breakdown := BreakdownPricing(*pricing)
+	total := breakdown.TenantTotal
+	if total <= 0 {
+		return nil, ErrNoRentAmount
+	}
+	// The gateway charges Colombian accounts in pesos; rents in dollars are paid by transfer
+	if breakdown.Currency != model.CurrencyCOP {
+		return nil, ErrCheckoutCurrency
+	}
+
+	checkout, err := s.checkoutRepo.Create(ctx, model.PaymentCheckout{
+		Reference:     fmt.Sprintf("ARR-%s-%s", strings.ReplaceAll(period, "-", ""), strings.ToUpper(uuid.NewString()[:8])),
+		RentalID:      rental.ID,
+		PersonID:      personID,
+		Period:        period,
+		AmountInCents: int64(math.Round(total * 100)),
+		Currency:      breakdown.Currency,
+		Provider:      s.gateway.Name(),
+		Status:        model.PaymentCheckoutStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	redirectURL := GetAppBaseURL() + "/payments?" + url.Values{"checkout": {checkout.Reference}}.Encode()
+	checkoutURL, err := s.gateway.CheckoutURL(*checkout, email, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("checkout started", "component", "checkout", "reference", checkout.Reference, "rental_id", rental.ID, "period", period, "total", FormatMoney(total))
+	return &RentCheckout{Checkout: *checkout, CheckoutURL: checkoutURL}, nil
+}
+
+// GetByReference returns a checkout, nil when there is none
+func (s *PaymentCheckoutService) GetByReference(ctx context.Context, reference string) (*model.PaymentCheckout, error) {
+	return s.checkoutRepo.GetByReference(ctx, reference)
+}
+
+// HandleWebhook applies a transaction reported by the gateway to its checkout and registers the
+// rent payment when it was approved. Events of unknown checkouts are ignored.
+func (s *PaymentCheckoutService) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	event, err := s.gateway.ParseWebhook(header, body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGatewayEvent, err)
+	}
+	if event == nil {
+		return nil
+	}
+
+	checkout, err := s.checkoutRepo.GetByReference(ctx, event.Reference)
+	if err != nil {
+		return err
+	}
+	if checkout == nil {
+		logging.FromContext(ctx).Warn("event of unknown reference ignored", "component", "checkout", "reference", event.Reference)
+		return nil
+	}
+	if checkout.RentPaymentID != nil {
+		// Already registered, the gateway retried the event
+		return nil
+	}
+
+	status := event.Status
+	if status == model.PaymentCheckoutStatusApproved && (event.AmountInCents != checkout.AmountInCents || event.Currency != checkout.Currency) {
+		logging.FromContext(ctx).Error("checkout approved for a different amount, the payment is not registered", "component", "checkout", "reference", checkout.Reference, "amount_in_cents", event.AmountInCents, "currency", event.Currency, "expected_amount_in_cents", checkout.AmountInCents, "expected_currency", checkout.Currency)
+		status = model.PaymentCheckoutStatusError
+	}
+
+	checkout.Status = status
+	checkout.TransactionID = event.TransactionID
+	checkout.PaymentMethod = event.PaymentMethod
+	if status != model.PaymentCheckoutStatusPending {
+		completedAt := time.Now()
+		checkout.CompletedAt = &completedAt
+	}
+
+	if status != model.PaymentCheckoutStatusApproved {
+		logging.FromContext(ctx).Info("checkout status changed", "component", "checkout", "reference", checkout.Reference, "status", status)
+		return s.checkoutRepo.UpdateResult(ctx, *checkout)
+	}
+
+	payment, registered, err := s.registerPayment(ctx, *checkout)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		// Another delivery of the event registered the payment first
+		return nil
+	}
+	GetEInvoices().AutoInvoice(payment.ID)
+	GetNotifications().PaymentReceived(*payment)
+	logging.FromContext(ctx).Info("approved, rent payment registered", "component", "checkout", "reference", checkout.Reference, "payment_method", checkout.PaymentMethod, "payment_id", payment.ID)
+	return nil
+}
+
+// registerPayment creates the rent payment of an approved checkout and records the result of
+// the checkout in the same transaction. registered is false when the checkout already had a
+// payment, the one returned, because the event was delivered more than once.
+func (s *PaymentCheckoutService) registerPayment(ctx context.Context, checkout model.PaymentCheckout) (payment *storage.RentPayment, registered bool, err error) {
+	// The due day is counted in the timezone of the organization of the property
+	loc := AppLocation()
+	if rental, err := s.rentalRepo.GetByID(ctx, checkout.RentalID); err == nil && rental != nil {
+		loc = s.location(ctx, rental.PropertyID)
+	}
+	paidAt := time.Now().In(loc)
+	newPayment := storage.RentPayment{
+		ID:          uuid.New().String(),
+		RentalID:    checkout.RentalID.String(),
+		PaymentDate: model.FlexibleTime(paidAt),
+		AmountPaid:  checkout.Amount(),
+		PaidOnTime:  true,
+	}
+	if pricing, err := s.pricingRepo.GetByRentalID(ctx, checkout.RentalID); err == nil && pricing != nil && pricing.DueDay != 0 {
+		newPayment.PaidOnTime = pricing.IsPaidOnTime(paidAt)
+	}
+
+	organizationID, year := GetDocumentNumbering().ReceiptSequence(ctx, newPayment)
+	payment, err = s.checkoutRepo.RegisterPayment(ctx, checkout, &newPayment, organizationID, year)
+	if err != nil {
+		return nil, false, err
+	}
+	return payment, payment.ID == newPayment.ID, nil
+}
+
+// currentRental returns the rental of a tenant in force at now, the latest one when several are
+func (s *PaymentCheckoutService) currentRental(ctx context.Context, personID uuid.UUID, now time.Time) (*model.Rental, error) {
+	rentals, err := s.rentalRepo.GetByRenterID(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *model.Rental
+	for i := range rentals {
+		rental := &rentals[i]
+		start, end := rental.StartDate.Time(), rental.EndDate.Time()
+		if now.Before(start) || (!end.IsZero() && now.After(end)) {
+			continue
+		}
+		if current == nil || start.After(current.StartDate.Time()) {
+			current = rental
+		}
+	}
+	if current == nil {
+		return nil, ErrNoActiveRental
+	}
+	return current, nil
+}
+
+// periodPaid reports whether a rental already paid a billing period, online or registered by
+// the administration. The periods of the payments are counted in loc.
+func (s *PaymentCheckoutService) periodPaid(ctx context.Context, rentalID uuid.UUID, period string, loc *time.Location) (bool, error) {
+	approved, err := s.checkoutRepo.GetApprovedByRentalAndPeriod(ctx, rentalID, period)
+	if err != nil {
+		return false, err
+	}
+	if approved != nil {
+		return true, nil
+	}
+
+	payments, err := s.paymentRepo.GetByRentalID(rentalID.String())
+	if err != nil {
+		return false, err
+	}
+	for _, payment := range payments {
+		if model.BillingPeriod(payment.PaymentDate.Time().In(loc)) == period {
+			return true, nil
+		}
+	}
+	return false, nil
+}
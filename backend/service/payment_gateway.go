@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ErrInvalidGatewayEvent is returned for webhook calls that are malformed or not signed by the gateway
+var ErrInvalidGatewayEvent = errors.New("invalid payment gateway event")
+
+// PaymentGatewayEvent is the state of a transaction reported by a payment gateway
+type PaymentGatewayEvent struct {
+	Reference     string
+	TransactionID string
+	Status        string // Normalized to one of the model.PaymentCheckoutStatus constants
+	AmountInCents int64
+	Currency      string
+	PaymentMethod string
+}
+
+// PaymentGateway collects the rent online (cards, PSE bank transfers, wallets) through a hosted
+// checkout and reports the result through signed webhooks
+type PaymentGateway interface {
+	// Name returns the identifier stored with the checkouts
+	Name() string
+	// CheckoutURL returns the page where the tenant pays a checkout, redirected afterwards to redirectURL
+	CheckoutURL(checkout model.PaymentCheckout, customerEmail, redirectURL string) (string, error)
+	// ParseWebhook authenticates a webhook call and extracts the transaction it reports. It
+	// returns nil for events that are not about transactions.
+	ParseWebhook(header http.Header, body []byte) (*PaymentGatewayEvent, error)
+}
+
+// GetPaymentGateway returns the payment gateway configured in cfg
+func GetPaymentGateway(cfg config.WompiConfig) (PaymentGateway, error) {
+	return NewWompiGateway(cfg)
+}
+
+// defaultWompiCheckoutURL is the Web Checkout of Wompi, the same for sandbox and production keys
+const defaultWompiCheckoutURL = "https://checkout.wompi.co/p/"
+
+// WompiGateway implements PaymentGateway with the Web Checkout of Wompi, which offers cards,
+// PSE, Nequi and Bancolombia transfers
+type WompiGateway struct {
+	publicKey       string
+	integritySecret string
+	eventsSecret    string
+	checkoutURL     string
+}
+
+// NewWompiGateway creates the Wompi gateway from the WOMPI_* settings
+func NewWompiGateway(cfg config.WompiConfig) (*WompiGateway, error) {
+	publicKey := cfg.PublicKey
+	if publicKey == "" {
+		return nil, errors.New("WOMPI_PUBLIC_KEY is not configured")
+	}
+
+	integritySecret := cfg.IntegritySecret
+	if integritySecret == "" {
+		return nil, errors.New("WOMPI_INTEGRITY_SECRET is not configured")
+	}
+
+	eventsSecret := cfg.EventsSecret
+	if eventsSecret == "" {
+		return nil, errors.New("WOMPI_EVENTS_SECRET is not configured")
+	}
+
+	checkoutURL := cfg.CheckoutURL
+	if checkoutURL == "" {
+		checkoutURL = defaultWompiCheckoutURL
+	}
+
+	return &WompiGateway{
+		publicKey:       publicKey,
+		integritySecret: integritySecret,
+		eventsSecret:    eventsSecret,
+		checkoutURL:     checkoutURL,
+	}, nil
+}
+
+// Name returns the gateway identifier
+func (g *WompiGateway) Name() string {
+	return "wompi"
+}
+
+// CheckoutURL returns the Web Checkout URL of a checkout, with the integrity signature (SHA-256
+// of reference, amount, currency and integrity secret) that keeps the amount from being altered
+func (g *WompiGateway) CheckoutURL(checkout model.PaymentCheckout, customerEmail, redirectURL string) (string, error) {
+	amount := strconv.FormatInt(checkout.AmountInCents, 10)
+	sum := sha256.Sum256([]byte(checkout.Reference + amount + checkout.Currency + g.integritySecret))
+
+	query := url.Values{
+		"public-key":          {g.publicKey},
+		"currency":            {checkout.Currency},
+		"amount-in-cents":     {amount},
+		"reference":           {checkout.Reference},
+		"signature:integrity": {hex.EncodeToString(sum[:])},
+	}
+	if redirectURL != "" {
+		query.Set("redirect-url", redirectURL)
+	}
+	if customerEmail != "" {
+		query.Set("customer-data:email", customerEmail)
+	}
+	return g.checkoutURL + "?" + query.Encode(), nil
+}
+
+type wompiEvent struct {
+	Event string `json:"event"`
+	Data  struct {
+		Transaction map[string]interface{} `json:"transaction"`
+	} `json:"data"`
+	Signature struct {
+		Properties []string `json:"properties"`
+		Checksum   string   `json:"checksum"`
+	} `json:"signature"`
+	Timestamp json.Number `json:"timestamp"`
+}
+
+// ParseWebhook checks the checksum of an event, the SHA-256 of the values of the signed
+// properties, the timestamp and the events secret
+func (g *WompiGateway) ParseWebhook(header http.Header, body []byte) (*PaymentGatewayEvent, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var event wompiEvent
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("error parsing Wompi event: %w", err)
+	}
+
+	checksum := event.Signature.Checksum
+	if checksum == "" {
+		checksum = header.Get("X-Event-Checksum")
+	}
+	if checksum == "" || len(event.Signature.Properties) == 0 {
+		return nil, errors.New("Wompi event without signature")
+	}
+
+	var signed strings.Builder
+	for _, property := range event.Signature.Properties {
+		path, found := strings.CutPrefix(property, "transaction.")
+		if !found {
+			return nil, fmt.Errorf("unsupported signed property %q", property)
+		}
+		signed.WriteString(wompiValue(event.Data.Transaction[path]))
+	}
+	signed.WriteString(event.Timestamp.String())
+	signed.WriteString(g.eventsSecret)
+	sum := sha256.Sum256([]byte(signed.String()))
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), checksum) {
+		return nil, errors.New("invalid Wompi event checksum")
+	}
+
+	if event.Event != "transaction.updated" || event.Data.Transaction == nil {
+		return nil, nil
+	}
+
+	transaction := event.Data.Transaction
+	amount, err := strconv.ParseInt(wompiValue(transaction["amount_in_cents"]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount in Wompi event: %w", err)
+	}
+
+	status := model.PaymentCheckoutStatusPending
+	switch strings.ToUpper(wompiValue(transaction["status"])) {
+	case "APPROVED":
+		status = model.PaymentCheckoutStatusApproved
+	case "DECLINED":
+		status = model.PaymentCheckoutStatusDeclined
+	case "VOIDED":
+		status = model.PaymentCheckoutStatusVoided
+	case "ERROR":
+		status = model.PaymentCheckoutStatusError
+	}
+
+	return &PaymentGatewayEvent{
+		Reference:     wompiValue(transaction["reference"]),
+		TransactionID: wompiValue(transaction["id"]),
+		Status:        status,
+		AmountInCents: amount,
+		Currency:      wompiValue(transaction["currency"]),
+		PaymentMethod: wompiValue(transaction["payment_method_type"]),
+	}, nil
+}
+
+// wompiValue returns a value of an event as it is concatenated in its checksum
+func wompiValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// PaymentStatementLine is a payment listed in a yearly payment certification
+type PaymentStatementLine struct {
+	Date       time.Time
+	Reference  string
+	Property   string
+	Amount     float64
+	PaidOnTime bool
+}
+
+// PaymentStatementPDF holds the data of the yearly payment certification of a tenant
+type PaymentStatementPDF struct {
+	Statement    model.PaymentStatement
+	Tenant       *model.Person
+	Organization *model.Organization
+	Lines        []PaymentStatementLine
+}
+
+// PaymentReference returns the short reference printed for a payment, the start of its ID
+func PaymentReference(paymentID string) string {
+	reference := strings.ToUpper(strings.ReplaceAll(paymentID, "-", ""))
+	if len(reference) > 10 {
+		reference = reference[:10]
+	}
+	return reference
+}
+
+// PaymentStatementVerificationURL returns the public URL confirming the authenticity of a
+// payment certification, printed as a QR code on the PDF
+func PaymentStatementVerificationURL(statementID string) string {
+	baseURL := GetAPIBaseURL()
+	if baseURL == "" {
+		baseURL = GetAppBaseURL()
+	}
+	return fmt.Sprintf("%s/api/public/payment-statements/verify/%s", baseURL, statementID)
+}
+
+// PaymentStatementHash returns the hex SHA-256 of a certification, recorded when it is issued
+func PaymentStatementHash(pdfData []byte) string {
+	sum := sha256.Sum256(pdfData)
+	return hex.EncodeToString(sum[:])
+}
+
+// GeneratePaymentStatementPDF creates the certification of the payments a tenant made in a
+// year, useful for tax deductions and housing subsidies. It carries a QR code to verify it and
+// is signed with the signing certificate on behalf of the organization.
+func GeneratePaymentStatementPDF(data PaymentStatementPDF) ([]byte, error) {
+	if data.Tenant == nil {
+		return nil, fmt.Errorf("incomplete payment statement data")
+	}
+
+	issuer := "El administrador del inmueble"
+	if branding, ok := BrandingFor(data.Organization); ok && branding.CompanyName != "" {
+		issuer = branding.CompanyName
+	} else if data.Organization != nil && data.Organization.Name != "" {
+		issuer = data.Organization.Name
+	}
+	year := data.Statement.Year
+	// Certifications are drafted in Spanish, in the timezone of the organization
+	formatter := FormatterFor(data.Organization).WithLocale(DefaultLocale)
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	if !addPDFBranding(pdf, data.Organization) {
+		pdf.SetFont(pdfFontFamily, "B", 12)
+		pdf.MultiCell(0, 6, strings.ToUpper(issuer), "", "C", false)
+		pdf.Ln(6)
+	}
+	pdf.SetFont(pdfFontFamily, "B", 13)
+	pdf.MultiCell(0, 7, fmt.Sprintf("CERTIFICADO DE PAGOS DE ARRENDAMIENTO\nAÑO %d", year), "", "C", false)
+	pdf.Ln(6)
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 5, fmt.Sprintf("%s certifica que el(la) señor(a) %s, identificado(a) con CC/NIT %s, realizó entre el 1 de enero y el 31 de diciembre de %d los siguientes pagos por concepto de arrendamiento:",
+		issuer, data.Tenant.FullName, data.Tenant.NIT, year), "", "J", false)
+	pdf.Ln(4)
+
+	widths := []float64{28, 28, 74, 30, 10}
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	for i, header := range []string{"FECHA", "REFERENCIA", "INMUEBLE", "VALOR", "A T."} {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(7)
+
+	pdf.SetFont(pdfFontFamily, "", 8)
+	for _, line := range data.Lines {
+		onTime := "No"
+		if line.PaidOnTime {
+			onTime = "Sí"
+		}
+		pdf.CellFormat(widths[0], 6, formatter.ShortDate(line.Date), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 6, line.Reference, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[2], 6, truncatePDFText(pdf, line.Property, widths[2]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 6, formatter.Money(line.Amount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, onTime, "1", 0, "C", false, 0, "")
+		pdf.Ln(6)
+	}
+
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, fmt.Sprintf("TOTAL PAGADO (%d pagos)", data.Statement.PaymentCount), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 7, formatter.Money(data.Statement.TotalPaid), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 7, "", "1", 0, "C", false, 0, "")
+	pdf.Ln(10)
+
+	pdf.SetFont(pdfFontFamily, "I", 8)
+	pdf.MultiCell(0, 4, "A T.: pago realizado dentro del plazo pactado en el contrato.", "", "L", false)
+	pdf.Ln(3)
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 5, fmt.Sprintf("La presente certificación se expide a solicitud del interesado el %s, con destino a quien interese (declaración de renta, postulación a subsidios de vivienda u otros trámites). Código de verificación: %s.",
+		formatter.Date(data.Statement.IssuedAt), data.Statement.ID), "", "J", false)
+
+	// Visible signature with the QR code to verify the certification
+	stamp := &SignatureStamp{
+		SignerName: issuer,
+		SignedAt:   data.Statement.IssuedAt,
+		SigningID:  data.Statement.ID.String(),
+		VerifyURL:  PaymentStatementVerificationURL(data.Statement.ID.String()),
+	}
+	addSignatureStamp(pdf, stamp)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	signedBytes, err := SignPDF(buf.Bytes(), issuer, SignPDFOptions{
+		SignatureReason:   "Certificado de pagos de arrendamiento",
+		SignatureLocation: "Digital Signature",
+		SignatureContact: fmt.Sprintf("StatementID: %s | Year: %d | IssuedAt: %s",
+			data.Statement.ID, year, data.Statement.IssuedAt.Format(time.RFC3339)),
+	})
+	if err != nil {
+		slog.Warn("error signing payment statement, proceeding with the unsigned PDF", "statement_id", data.Statement.ID, "error", err)
+		return buf.Bytes(), nil
+	}
+
+	return signedBytes, nil
+}
+
+// truncatePDFText shortens text with an ellipsis so it fits in a table cell of the given width
+func truncatePDFText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	_ "embed"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// pdfFontFamily is the UTF-8 font used by the generated PDFs. The core PDF fonts (Arial,
+// Helvetica) only cover cp1252 through gofpdf, so accents and ñ were lost.
+const pdfFontFamily = "DejaVu"
+
+// DejaVu Sans Condensed (Bitstream Vera license, free to embed and redistribute)
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	dejaVuRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	dejaVuBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	dejaVuItalic []byte
+	//go:embed fonts/DejaVuSansCondensed-BoldOblique.ttf
+	dejaVuBoldItalic []byte
+)
+
+// newPDF creates an A4 portrait document with the UTF-8 font family registered
+func newPDF() *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", dejaVuRegular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", dejaVuBold)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "I", dejaVuItalic)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "BI", dejaVuBoldItalic)
+	return pdf
+}
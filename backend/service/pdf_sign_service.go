@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -9,7 +10,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -96,6 +97,8 @@ func CreateSignatureRequest(contractInfo model.ContractSigningInfo, expirationDa
 		Status:         model.StatusPending,
 		CreatedAt:      now,
 		ExpiresAt:      expiresAt,
+		RequestedBy:    contractInfo.RequestedBy,
+		DocumentType:   contractInfo.DocumentType,
 	}
 
 	// Save the contract to disk temporarily
@@ -103,151 +106,101 @@ func CreateSignatureRequest(contractInfo model.ContractSigningInfo, expirationDa
 		return nil, fmt.Errorf("error saving temporary PDF: %w", err)
 	}
 
-	// Get base URL from environment variable or use localhost as fallback
-	baseURL := os.Getenv("APP_BASE_URL")
+	// Use the organization domain when provided, APP_BASE_URL otherwise
+	baseURL := contractInfo.BaseURL
 	if baseURL == "" {
-		// Default to localhost in development
-		baseURL = "http://localhost:5173"
+		baseURL = GetAppBaseURL()
 	}
 
 	// Generate signing URL
 	signingURL := fmt.Sprintf("%s/sign/%s", baseURL, request.ID)
 
-	// Format date in Spanish
-	spanishMonths := map[time.Month]string{
-		time.January:   "enero",
-		time.February:  "febrero",
-		time.March:     "marzo",
-		time.April:     "abril",
-		time.May:       "mayo",
-		time.June:      "junio",
-		time.July:      "julio",
-		time.August:    "agosto",
-		time.September: "septiembre",
-		time.October:   "octubre",
-		time.November:  "noviembre",
-		time.December:  "diciembre",
-	}
-
-	expiryDay := expiresAt.Day()
-	expiryMonth := spanishMonths[expiresAt.Month()]
-	expiryYear := expiresAt.Year()
-	formattedDate := fmt.Sprintf("%d de %s de %d", expiryDay, expiryMonth, expiryYear)
-
 	// Send email with signing link
-	subject := "Contrato Listo para Firma"
-	body := fmt.Sprintf(`
-	<!DOCTYPE html>
-	<html>
-	<head>
-		<meta charset="UTF-8">
-		<title>Solicitud de Firma de Contrato</title>
-		<style>
-			body { font-family: Arial, sans-serif; margin: 0; padding: 20px; color: #333; }
-			.container { max-width: 600px; margin: 0 auto; }
-			.header { background-color: #f8f9fa; padding: 20px; text-align: center; }
-			.content { padding: 20px; }
-			.button { display: inline-block; background-color: #007bff; color: white; padding: 10px 20px; 
-					text-decoration: none; border-radius: 4px; margin-top: 20px; }
-			.footer { margin-top: 20px; font-size: 12px; color: #6c757d; }
-		</style>
-	</head>
-	<body>
-		<div class="container">
-			<div class="header">
-				<h2>Contrato Listo para su Firma</h2>
-			</div>
-			<div class="content">
-				<p>Estimado(a) %s,</p>
-				<p>Un contrato está listo para su revisión y firma. Por favor haga clic en el botón a continuación para ver y firmar el documento:</p>
-				<p><a href="%s" class="button">Revisar y Firmar Contrato</a></p>
-				<p>Esta solicitud de firma expirará el %s.</p>
-				<p>Si tiene alguna pregunta sobre este documento, por favor contáctenos directamente.</p>
-				<p>Gracias,<br>Sistema de Administración de Propiedades</p>
-			</div>
-			<div class="footer">
-				<p>Este es un mensaje automático. Por favor no responda directamente a este correo.</p>
-			</div>
-		</div>
-	</body>
-	</html>
-	`, contractInfo.SignerName, signingURL, formattedDate)
+	subject, body, err := renderEmail(EmailTemplateSigningRequest, contractInfo.Organization, "", SigningEmailData{
+		FirmanteNombre:   contractInfo.SignerName,
+		FirmanteEmail:    contractInfo.RecipientEmail,
+		ContratoID:       contractInfo.ContractID,
+		EnlaceFirma:      signingURL,
+		FechaVencimiento: FormatterFor(contractInfo.Organization).Date(expiresAt),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rendering signature request email: %w", err)
+	}
 
 	// Send the email
-	err := SendSimpleEmail(contractInfo.RecipientEmail, subject, body)
+	err = SendSimpleEmail(contractInfo.RecipientEmail, subject, body)
 	if err != nil {
-		log.Printf("Error sending signature request email: %v", err)
+		slog.Error("error sending signature request email", "error", err)
 		return nil, fmt.Errorf("error sending signature request email: %w", err)
 	}
 
 	// In a real implementation, you would save this request to a database
-	log.Printf("Signature request created with ID: %s for contract: %s, sent to: %s",
-		request.ID, request.ContractID, request.RecipientEmail)
+	slog.Info("signature request created", "signing_request_id", request.ID, "contract_id", request.ContractID, "recipient_email", request.RecipientEmail)
 
 	return request, nil
 }
 
-// SignPDF signs a PDF document with the provided certificate and private key
+// SignPDF signs a PDF document with the provided certificate and private key, or with the
+// active signing certificate when no certificate path is provided
 func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte, error) {
-	// Use default options if not provided
 	if options.CertificatePath == "" {
-		options = DefaultSignOptions
+		return signPDFWithActiveCertificate(pdfData, signerName, options)
 	}
 
 	// In development mode, return the original PDF if the certificate files don't exist
 	if _, err := os.Stat(options.CertificatePath); os.IsNotExist(err) {
-		log.Printf("Certificate file not found at %s, skipping signing in development mode", options.CertificatePath)
+		slog.Warn("certificate file not found, skipping signing in development mode", "certificate_path", options.CertificatePath)
 		return pdfData, nil
 	}
 	if _, err := os.Stat(options.PrivateKeyPath); os.IsNotExist(err) {
-		log.Printf("Private key file not found at %s, skipping signing in development mode", options.PrivateKeyPath)
+		slog.Warn("private key file not found, skipping signing in development mode", "private_key_path", options.PrivateKeyPath)
 		return pdfData, nil
 	}
 
 	// Read certificate
 	certData, err := ioutil.ReadFile(options.CertificatePath)
 	if err != nil {
-		log.Printf("Warning: Could not read certificate: %v", err)
+		slog.Warn("could not read certificate", "error", err)
 		return pdfData, nil
 	}
 
 	// Parse certificate
 	block, _ := pem.Decode(certData)
 	if block == nil {
-		log.Printf("Warning: Failed to parse certificate PEM")
+		slog.Warn("failed to parse certificate PEM")
 		return pdfData, nil
 	}
 
 	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		log.Printf("Warning: Failed to parse certificate: %v", err)
+		slog.Warn("failed to parse certificate", "error", err)
 		return pdfData, nil
 	}
 
 	// Read private key
 	keyData, err := ioutil.ReadFile(options.PrivateKeyPath)
 	if err != nil {
-		log.Printf("Warning: Could not read private key: %v", err)
+		slog.Warn("could not read private key", "error", err)
 		return pdfData, nil
 	}
 
 	// Parse private key
 	keyBlock, _ := pem.Decode(keyData)
 	if keyBlock == nil {
-		log.Printf("Warning: Failed to parse private key PEM")
+		slog.Warn("failed to parse private key PEM")
 		return pdfData, nil
 	}
 
 	privateKey, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
 	if err != nil {
-		log.Printf("Warning: Failed to parse private key: %v", err)
+		slog.Warn("failed to parse private key", "error", err)
 		return pdfData, nil
 	}
 
 	// Create temporary input and output files
 	tempDir := filepath.Join(os.TempDir(), "pdf_signing")
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		log.Printf("Error creating temp directory: %v", err)
+		slog.Error("error creating temp directory", "error", err)
 		return pdfData, nil
 	}
 
@@ -256,7 +209,7 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 
 	// Write input PDF
 	if err := ioutil.WriteFile(inputPath, pdfData, 0644); err != nil {
-		log.Printf("Error writing input PDF: %v", err)
+		slog.Error("error writing input PDF", "error", err)
 		return pdfData, nil
 	}
 	defer os.Remove(inputPath) // Clean up input file
@@ -264,14 +217,14 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 	// Open input and output files
 	inputFile, err := os.Open(inputPath)
 	if err != nil {
-		log.Printf("Error opening input file: %v", err)
+		slog.Error("error opening input file", "error", err)
 		return pdfData, nil
 	}
 	defer inputFile.Close()
 
 	outputFile, err := os.Create(outputPath)
 	if err != nil {
-		log.Printf("Error creating output file: %v", err)
+		slog.Error("error creating output file", "error", err)
 		return pdfData, nil
 	}
 	defer outputFile.Close()
@@ -279,7 +232,7 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 	// Get file info for size
 	fileInfo, err := inputFile.Stat()
 	if err != nil {
-		log.Printf("Error getting file info: %v", err)
+		slog.Error("error getting file info", "error", err)
 		return pdfData, nil
 	}
 	fileSize := fileInfo.Size()
@@ -287,14 +240,14 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 	// Read the PDF to get its structure
 	pdfReader, err := pdf.NewReader(inputFile, fileSize)
 	if err != nil {
-		log.Printf("Error creating PDF reader: %v", err)
+		slog.Error("error creating PDF reader", "error", err)
 		return pdfData, nil
 	}
 
 	// Reset file position to the beginning
 	_, err = inputFile.Seek(0, 0)
 	if err != nil {
-		log.Printf("Error seeking file: %v", err)
+		slog.Error("error seeking file", "error", err)
 		return pdfData, nil
 	}
 
@@ -317,8 +270,8 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 	})
 
 	if err != nil {
-		log.Printf("Warning: Error signing PDF: %v", err)
-		log.Printf("Proceeding with unsigned PDF in development mode")
+		slog.Warn("error signing PDF", "error", err)
+		slog.Info("proceeding with unsigned PDF in development mode")
 		return pdfData, nil
 	}
 
@@ -329,26 +282,64 @@ func SignPDF(pdfData []byte, signerName string, options SignPDFOptions) ([]byte,
 	// Read the signed PDF
 	signedPDFData, err := ioutil.ReadFile(outputPath)
 	if err != nil {
-		log.Printf("Error reading signed PDF: %v", err)
+		slog.Error("error reading signed PDF", "error", err)
 		return pdfData, nil
 	}
 
 	// Clean up the output file
 	defer os.Remove(outputPath)
 
-	log.Printf("PDF successfully signed with certificate: %s", cert.Subject.CommonName)
+	slog.Info("PDF successfully signed with certificate", "common_name", cert.Subject.CommonName)
 	return signedPDFData, nil
 }
 
-// VerifyPDFSignature verifies a signed PDF
-// This is a simplified version that doesn't actually verify the signature
-func VerifyPDFSignature(signedPDFData []byte) (bool, error) {
-	// In a real implementation, this would use pdfsign to verify the signature
-	// For development purposes, we're just logging that we would verify it
-	log.Printf("Would verify PDF signature on a %d byte PDF", len(signedPDFData))
+// signPDFWithActiveCertificate signs a PDF with the active signing certificate, ECDSA or RSA
+func signPDFWithActiveCertificate(pdfData []byte, signerName string, options SignPDFOptions) ([]byte, error) {
+	if options.SignatureReason == "" {
+		options.SignatureReason = DefaultSignOptions.SignatureReason
+	}
+	if options.SignatureLocation == "" {
+		options.SignatureLocation = DefaultSignOptions.SignatureLocation
+	}
+	if options.SignatureContact == "" {
+		options.SignatureContact = DefaultSignOptions.SignatureContact
+	}
+
+	signingCert, err := ActiveSigningCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing certificate: %w", err)
+	}
+
+	var output bytes.Buffer
+	reader := bytes.NewReader(pdfData)
+	pdfReader, err := pdf.NewReader(reader, int64(len(pdfData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	err = sign.Sign(reader, &output, pdfReader, int64(len(pdfData)), sign.SignData{
+		Signature: sign.SignDataSignature{
+			Info: sign.SignDataSignatureInfo{
+				Name:        signerName,
+				Location:    options.SignatureLocation,
+				Reason:      options.SignatureReason,
+				ContactInfo: options.SignatureContact,
+				Date:        time.Now().Local(),
+			},
+			CertType:   sign.CertificationSignature,
+			DocMDPPerm: sign.AllowFillingExistingFormFieldsAndSignaturesPerms,
+		},
+		Signer:            signingCert.Signer,
+		DigestAlgorithm:   crypto.SHA256,
+		Certificate:       signingCert.Certificate,
+		CertificateChains: SigningCertificateChains(signingCert),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign PDF: %w", err)
+	}
 
-	// Since we're not actually signing PDFs yet, just return true
-	return true, nil
+	slog.Info("PDF successfully signed", "key_algorithm", signingCert.KeyAlgorithm(), "common_name", signingCert.Certificate.Subject.CommonName)
+	return output.Bytes(), nil
 }
 
 // Helper function to temporarily save a PDF
@@ -461,69 +452,21 @@ func GenerateSelfSignedCert(outputDir string) error {
 		return fmt.Errorf("failed to encode private key: %w", err)
 	}
 
-	log.Printf("Created self-signed certificate at %s and private key at %s", certPath, keyPath)
-	log.Printf("Note: These are self-signed certificates for development purposes only!")
+	slog.Info("created self-signed certificate and private key", "cert_path", certPath, "key_path", keyPath)
+	slog.Info("note: These are self-signed certificates for development purposes only")
 
 	return nil
 }
 
-// SendSignedPDFByEmail sends the signed PDF to the recipient
-func SendSignedPDFByEmail(signingInfo *model.ContractSigningRequest, signedPDFData []byte) error {
-	// Format current date in Spanish for the email
-	now := time.Now()
-	spanishMonths := map[time.Month]string{
-		time.January:   "enero",
-		time.February:  "febrero",
-		time.March:     "marzo",
-		time.April:     "abril",
-		time.May:       "mayo",
-		time.June:      "junio",
-		time.July:      "julio",
-		time.August:    "agosto",
-		time.September: "septiembre",
-		time.October:   "octubre",
-		time.November:  "noviembre",
-		time.December:  "diciembre",
-	}
-	currentDay := now.Day()
-	currentMonth := spanishMonths[now.Month()]
-	currentYear := now.Year()
-	formattedDate := fmt.Sprintf("%d de %s de %d", currentDay, currentMonth, currentYear)
-
-	subject := "Contrato Firmado - Copia para sus Registros"
-	body := fmt.Sprintf(`
-	<!DOCTYPE html>
-	<html>
-	<head>
-		<meta charset="UTF-8">
-		<title>Contrato Firmado</title>
-		<style>
-			body { font-family: Arial, sans-serif; margin: 0; padding: 20px; color: #333; }
-			.container { max-width: 600px; margin: 0 auto; }
-			.header { background-color: #f8f9fa; padding: 20px; text-align: center; }
-			.content { padding: 20px; }
-			.footer { margin-top: 20px; font-size: 12px; color: #6c757d; }
-		</style>
-	</head>
-	<body>
-		<div class="container">
-			<div class="header">
-				<h2>Contrato Firmado</h2>
-			</div>
-			<div class="content">
-				<p>Estimado(a),</p>
-				<p>Adjunto a este correo encontrará una copia del contrato firmado para sus registros.</p>
-				<p>Este documento ha sido firmado digitalmente el %s y tiene validez legal.</p>
-				<p>Gracias por usar nuestro sistema de firma digital.</p>
-				<p>Atentamente,<br>Sistema de Administración de Propiedades</p>
-			</div>
-			<div class="footer">
-				<p>Este es un mensaje automático. Por favor no responda directamente a este correo.</p>
-			</div>
-		</div>
-	</body>
-	</html>
-	`, formattedDate)
+// SendSignedPDFByEmail sends the signed PDF to the recipient, in locale ("" for the locale of
+// org) and with the branding of org when it is not nil
+func SendSignedPDFByEmail(signingInfo *model.ContractSigningRequest, signedPDFData []byte, org *model.Organization, locale string) error {
+	subject, body, err := renderEmail(EmailTemplateSignedContract, org, locale, SignedContractEmailData{
+		FechaFirma: emailFormatter(locale, org).Date(time.Now()),
+	})
+	if err != nil {
+		return fmt.Errorf("error rendering signed contract email: %w", err)
+	}
 
 	// Create temporary file for attachment
 	tempFile, err := ioutil.TempFile("", "signed_contract_*.pdf")
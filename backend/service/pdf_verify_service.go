@@ -0,0 +1,224 @@
+package service
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digitorus/pdfsign/verify"
+)
+
+// platformCertsDir holds the certificates this backend signs contracts with
+const platformCertsDir = "./certs"
+
+// byteRangePattern matches the /ByteRange of a signature dictionary
+var byteRangePattern = regexp.MustCompile(`/ByteRange\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+
+// signingDatePattern matches the /M signing date of a signature dictionary (D:YYYYMMDDHHmmSS)
+var signingDatePattern = regexp.MustCompile(`/M\s*\(D:(\d{14})`)
+
+// PDFSignatureVerification is the result of verifying the signatures embedded in a PDF
+type PDFSignatureVerification struct {
+	Signed   bool        `json:"signed"`   // The PDF has at least one digital signature
+	Valid    bool        `json:"valid"`    // Every signature is cryptographically valid and the document was not modified
+	Tampered bool        `json:"tampered"` // The signed bytes were modified or content was added after the last signature
+	Trusted  bool        `json:"trusted"`  // Every signer certificate chains to the platform or a system root
+	Signers  []PDFSigner `json:"signers"`
+	Errors   []string    `json:"errors,omitempty"`
+	Pages    int         `json:"pages,omitempty"`
+}
+
+// PDFSigner is a signature embedded in a PDF
+type PDFSigner struct {
+	Name              string     `json:"name"`
+	SigningID         string     `json:"signing_id,omitempty"` // Our contract_signatures ID, for PDFs signed by this platform
+	SignedBy          string     `json:"signed_by,omitempty"`
+	Reason            string     `json:"reason,omitempty"`
+	Location          string     `json:"location,omitempty"`
+	SignedAt          *time.Time `json:"signed_at,omitempty"`
+	ValidSignature    bool       `json:"valid_signature"`
+	Trusted           bool       `json:"trusted"`
+	Revoked           bool       `json:"revoked"`
+	Certificate       string     `json:"certificate,omitempty"` // Subject of the signer certificate
+	CertificateIssuer string     `json:"certificate_issuer,omitempty"`
+	CertificateSerial string     `json:"certificate_serial,omitempty"`
+	ValidFrom         *time.Time `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time `json:"valid_until,omitempty"`
+	ChainError        string     `json:"chain_error,omitempty"`
+}
+
+// VerifyPDFSignature verifies the digital signatures embedded in a PDF: the signature over the
+// signed byte ranges, that no bytes were added after the last signature and the certificate
+// chain of each signer. A PDF without signatures is reported as not signed, not as an error.
+func VerifyPDFSignature(signedPDFData []byte) (*PDFSignatureVerification, error) {
+	if len(signedPDFData) == 0 {
+		return nil, errors.New("empty PDF")
+	}
+	if !bytes.HasPrefix(bytes.TrimLeft(signedPDFData, "\x00\t\r\n "), []byte("%PDF")) {
+		return nil, errors.New("the file is not a PDF")
+	}
+
+	result := &PDFSignatureVerification{Signers: []PDFSigner{}}
+
+	response, err := verify.Reader(bytes.NewReader(signedPDFData), int64(len(signedPDFData)))
+	if err != nil {
+		if strings.Contains(err.Error(), "No digital signature") {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to read PDF signatures: %w", err)
+	}
+	if response.Error != "" {
+		result.Errors = append(result.Errors, strings.TrimSpace(response.Error))
+	}
+	result.Pages = response.DocumentInfo.Pages
+
+	roots := platformCertPool()
+	signingDates := signingDatePattern.FindAllSubmatch(signedPDFData, -1)
+
+	result.Signed = len(response.Signers) > 0
+	result.Valid = result.Signed
+	result.Trusted = result.Signed
+	for i, signer := range response.Signers {
+		verified := PDFSigner{
+			Name:           signer.Name,
+			Reason:         signer.Reason,
+			Location:       signer.Location,
+			ValidSignature: signer.ValidSignature,
+			Revoked:        signer.RevokedCertificate,
+		}
+		verified.SigningID, verified.SignedBy, verified.SignedAt = parseSignatureContactInfo(signer.ContactInfo)
+		if signer.TimeStamp != nil {
+			signedAt := signer.TimeStamp.Time
+			verified.SignedAt = &signedAt
+		}
+		if verified.SignedAt == nil && i < len(signingDates) {
+			if signedAt, err := time.Parse("20060102150405", string(signingDates[i][1])); err == nil {
+				verified.SignedAt = &signedAt
+			}
+		}
+
+		if len(signer.Certificates) > 0 {
+			leaf := signer.Certificates[0].Certificate
+			verified.Certificate = leaf.Subject.String()
+			verified.CertificateIssuer = leaf.Issuer.String()
+			verified.CertificateSerial = fmt.Sprintf("%X", leaf.SerialNumber)
+			validFrom, validUntil := leaf.NotBefore, leaf.NotAfter
+			verified.ValidFrom, verified.ValidUntil = &validFrom, &validUntil
+
+			intermediates := x509.NewCertPool()
+			for _, cert := range signer.Certificates[1:] {
+				intermediates.AddCert(cert.Certificate)
+			}
+			verifyAt := leaf.NotBefore
+			if verified.SignedAt != nil {
+				verifyAt = *verified.SignedAt
+			}
+			if _, err := leaf.Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				CurrentTime:   verifyAt,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			}); err != nil {
+				verified.ChainError = err.Error()
+			} else {
+				verified.Trusted = true
+			}
+		}
+
+		result.Valid = result.Valid && verified.ValidSignature && !verified.Revoked
+		result.Trusted = result.Trusted && verified.Trusted
+		result.Signers = append(result.Signers, verified)
+	}
+
+	// The last signature must cover the whole file except its own contents
+	ranges := byteRangePattern.FindAllSubmatch(signedPDFData, -1)
+	if result.Signed {
+		if len(ranges) == 0 {
+			result.Tampered = true
+			result.Errors = append(result.Errors, "signature without byte range")
+		} else {
+			last := ranges[len(ranges)-1]
+			start, _ := strconv.ParseInt(string(last[3]), 10, 64)
+			length, _ := strconv.ParseInt(string(last[4]), 10, 64)
+			if end := start + length; end < int64(len(bytes.TrimRight(signedPDFData, "\x00\t\r\n "))) {
+				result.Tampered = true
+				result.Errors = append(result.Errors, fmt.Sprintf("%d bytes were added after the last signature", int64(len(signedPDFData))-end))
+			}
+		}
+		for _, signer := range result.Signers {
+			if !signer.ValidSignature {
+				result.Tampered = true
+			}
+		}
+	}
+	result.Valid = result.Valid && !result.Tampered
+
+	return result, nil
+}
+
+// parseSignatureContactInfo reads the metadata this platform writes in the contact info of its
+// signatures ("SignID: <id> | SignedBy: <email> | TimeSigned: <RFC3339>"). The IP, UserAgent
+// and Geo evidence that follows is printed on the certificate page of the contract.
+func parseSignatureContactInfo(contactInfo string) (signingID, signedBy string, signedAt *time.Time) {
+	for _, part := range strings.Split(contactInfo, "|") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "SignID":
+			signingID = value
+		case "SignedBy":
+			signedBy = value
+		case "TimeSigned":
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				signedAt = &t
+			}
+		}
+	}
+	return signingID, signedBy, signedAt
+}
+
+// platformCertPool returns the system roots plus the certificates this platform signs with
+func platformCertPool() *x509.CertPool {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, name := range []string{"ecdsa_certificate.crt", "certificate.crt"} {
+		certPEM, err := os.ReadFile(filepath.Join(platformCertsDir, name))
+		if err != nil {
+			continue
+		}
+		block, _ := pem.Decode(certPEM)
+		if block == nil || block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			slog.Warn("could not parse the platform certificate", "name", name, "error", err)
+			continue
+		}
+		pool.AddCert(cert)
+	}
+
+	// Configured certificates are trusted with the CA chain of their PKCS#12 file
+	if signingCert, err := ActiveSigningCertificate(); err == nil && signingCert.Source != SigningCertificateSourceSelfSigned {
+		pool.AddCert(signingCert.Certificate)
+		for _, cert := range signingCert.Chain {
+			pool.AddCert(cert)
+		}
+	}
+	return pool
+}
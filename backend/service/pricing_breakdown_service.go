@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// IVAPercentage is the general IVA rate applied to the taxable charges of a pricing
+const IVAPercentage = 19.0
+
+// PricingBreakdownLine is a charge of a pricing with its IVA
+type PricingBreakdownLine struct {
+	Type           string  `json:"type"`
+	Label          string  `json:"label"`
+	Amount         float64 `json:"amount"`
+	IVA            float64 `json:"iva"`
+	Total          float64 `json:"total"`
+	Responsibility string  `json:"responsibility"`
+	Taxable        bool    `json:"taxable"`
+}
+
+// PricingBreakdown itemizes the monthly charges of a pricing. Only the charges of the tenant
+// are billed, the ones of the owner are listed for the owner's statements.
+type PricingBreakdown struct {
+	Lines          []PricingBreakdownLine `json:"lines"`
+	TenantSubtotal float64                `json:"tenant_subtotal"`
+	TenantIVA      float64                `json:"tenant_iva"`
+	TenantTotal    float64                `json:"tenant_total"`
+	OwnerTotal     float64                `json:"owner_total"`
+	Currency       string                 `json:"currency"`
+}
+
+// Money returns an amount of the breakdown in its currency
+func (b PricingBreakdown) Money(amount float64) model.Money {
+	return model.Money{Amount: amount, Currency: b.Currency}
+}
+
+// TenantLines returns the charges billed to the tenant
+func (b PricingBreakdown) TenantLines() []PricingBreakdownLine {
+	lines := make([]PricingBreakdownLine, 0, len(b.Lines))
+	for _, line := range b.Lines {
+		if line.Responsibility != model.ResponsibilityOwner {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// BreakdownPricing itemizes the monthly charges of a pricing, computing the IVA of the taxable ones
+func BreakdownPricing(pricing model.Pricing) PricingBreakdown {
+	breakdown := PricingBreakdown{Currency: pricing.CurrencyCode()}
+	for _, item := range pricing.Items() {
+		responsibility := item.Responsibility
+		if responsibility == "" {
+			responsibility = model.ResponsibilityTenant
+		}
+
+		line := PricingBreakdownLine{
+			Type:           item.Type,
+			Label:          item.Label(),
+			Amount:         item.Amount,
+			Responsibility: responsibility,
+			Taxable:        item.Taxable,
+		}
+		if item.Taxable {
+			line.IVA = math.Round(item.Amount * IVAPercentage / 100)
+		}
+		line.Total = line.Amount + line.IVA
+		breakdown.Lines = append(breakdown.Lines, line)
+
+		if item.PaidByOwner() {
+			breakdown.OwnerTotal += line.Total
+			continue
+		}
+		breakdown.TenantSubtotal += line.Amount
+		breakdown.TenantIVA += line.IVA
+	}
+	breakdown.TenantTotal = breakdown.TenantSubtotal + breakdown.TenantIVA
+	return breakdown
+}
+
+// PricingChargesClauseText returns the paragraph of the price clause listing the charges paid by
+// the tenant besides the canon, "" when there are none
+func PricingChargesClauseText(pricing model.Pricing) string {
+	breakdown := BreakdownPricing(pricing)
+
+	var charges []string
+	for _, line := range breakdown.TenantLines() {
+		if line.Type == model.PricingComponentCanon {
+			continue
+		}
+		charge := fmt.Sprintf("%s por %s", strings.ToLower(line.Label), FormatAmount(breakdown.Money(line.Amount)))
+		if line.Taxable {
+			charge += " más IVA"
+		}
+		charges = append(charges, charge)
+	}
+	if len(charges) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("PARÁGRAFO: Además del canon, el ARRENDATARIO pagará mensualmente %s, para un total mensual de %s (%s).",
+		joinSpanishList(charges), FormatAmount(breakdown.Money(breakdown.TenantTotal)), AmountWithCurrencyInWords(breakdown.TenantTotal, breakdown.Currency))
+}
+
+// joinSpanishList joins items as "a, b y c"
+func joinSpanishList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " y " + items[len(items)-1]
+}
+
+// withPricePlaceholder returns the clauses of a template with a placeholder appended to the
+// price clause (the first one mentioning {{canon}}) when no clause has it, so templates written
+// before the placeholder existed also print it
+func withPricePlaceholder(clauses []model.ContractClause, name string) []model.ContractClause {
+	placeholder := "{{" + name + "}}"
+	for _, clause := range clauses {
+		if strings.Contains(strings.ReplaceAll(clause.Body, " ", ""), placeholder) {
+			return clauses
+		}
+	}
+
+	for i, clause := range clauses {
+		if strings.Contains(strings.ReplaceAll(clause.Body, " ", ""), "{{canon}}") {
+			updated := make([]model.ContractClause, len(clauses))
+			copy(updated, clauses)
+			updated[i].Body = strings.TrimSpace(clause.Body) + " " + placeholder
+			return updated
+		}
+	}
+	return clauses
+}
+
+// formatCharge formats the amount of a charge, "" when there is none
+func formatCharge(money model.Money) string {
+	if money.Amount <= 0 {
+		return ""
+	}
+	return FormatAmount(money)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sandboxFileNamePattern matches the characters not allowed in sandboxed email file names
+var sandboxFileNamePattern = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+// orDefault returns value, or fallback when the setting is unset
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
+
+// writeSandboxEmail writes a MIME message to the sandbox directory as an .eml file instead of
+// sending it, so environments other than production never email real people
+func writeSandboxEmail(dir, to string, message []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create email sandbox directory: %w", err)
+	}
+
+	recipient := sandboxFileNamePattern.ReplaceAllString(strings.TrimSpace(strings.Split(to, ",")[0]), "_")
+	fileName := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), recipient)
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, message, 0644); err != nil {
+		return fmt.Errorf("failed to write sandboxed email: %w", err)
+	}
+
+	slog.Info("email sandbox", "to", to, "path", path)
+	return nil
+}
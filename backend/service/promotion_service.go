@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// PromotionOffer is the price offered for a property once its promotions are applied
+type PromotionOffer struct {
+	MonthlyRent     float64           `json:"monthly_rent"`
+	FirstMonthRent  float64           `json:"first_month_rent"`
+	SecurityDeposit float64           `json:"security_deposit"`
+	Promotions      []model.Promotion `json:"promotions"`
+}
+
+// ValidatePromotion checks the type, discount and validity window of a promotion
+func ValidatePromotion(promotion model.Promotion) error {
+	if strings.TrimSpace(promotion.Name) == "" {
+		return fmt.Errorf("promotion name is required")
+	}
+	if !model.IsValidPromotionType(promotion.Type) {
+		return fmt.Errorf("invalid promotion type, use one of %s", strings.Join(model.PromotionTypes, ", "))
+	}
+	if promotion.Type == model.PromotionTypeFirstMonthDiscount &&
+		(promotion.DiscountPercentage <= 0 || promotion.DiscountPercentage > 100) {
+		return fmt.Errorf("discount percentage must be greater than 0 and at most 100")
+	}
+	if promotion.ValidFrom.IsZero() || promotion.ValidUntil.IsZero() {
+		return fmt.Errorf("valid_from and valid_until are required")
+	}
+	if promotion.ValidUntil.Before(promotion.ValidFrom) {
+		return fmt.Errorf("valid_until must be after valid_from")
+	}
+	return nil
+}
+
+// ActivePromotions returns the promotions valid at a given time. Promotions of the same type
+// do not stack: only the one with the largest discount is kept.
+func ActivePromotions(promotions []model.Promotion, at time.Time) []model.Promotion {
+	best := map[string]model.Promotion{}
+	for _, promotion := range promotions {
+		if !promotion.IsValidAt(at) {
+			continue
+		}
+		current, ok := best[promotion.Type]
+		if !ok || promotion.DiscountPercentage > current.DiscountPercentage {
+			best[promotion.Type] = promotion
+		}
+	}
+
+	active := make([]model.Promotion, 0, len(best))
+	for _, promotionType := range model.PromotionTypes {
+		if promotion, ok := best[promotionType]; ok {
+			active = append(active, promotion)
+		}
+	}
+	return active
+}
+
+// ApplyPromotions returns the offer for a monthly rent and deposit with the given promotions
+func ApplyPromotions(monthlyRent, securityDeposit float64, promotions []model.Promotion) PromotionOffer {
+	offer := PromotionOffer{
+		MonthlyRent:     monthlyRent,
+		FirstMonthRent:  monthlyRent,
+		SecurityDeposit: securityDeposit,
+		Promotions:      promotions,
+	}
+
+	for _, promotion := range promotions {
+		switch promotion.Type {
+		case model.PromotionTypeFirstMonthDiscount:
+			offer.FirstMonthRent = math.Round(monthlyRent * (1 - promotion.DiscountPercentage/100))
+		case model.PromotionTypeNoDeposit:
+			offer.SecurityDeposit = 0
+		}
+	}
+	return offer
+}
+
+// PromotionClauseText returns the paragraph added to the price clause of a contract offered
+// with promotions, "" when the offer has none
+func PromotionClauseText(offer PromotionOffer) string {
+	var paragraphs []string
+	for _, promotion := range offer.Promotions {
+		switch promotion.Type {
+		case model.PromotionTypeFirstMonthDiscount:
+			paragraphs = append(paragraphs, fmt.Sprintf("En virtud de la promoción «%s», el canon correspondiente al primer mes de arrendamiento será la suma de %s (%s), con un descuento del %s%% sobre el canon mensual pactado, el cual regirá sin descuento a partir del segundo mes.",
+				promotion.Name, AmountWithCurrencyInWords(offer.FirstMonthRent, model.CurrencyCOP), FormatMoney(offer.FirstMonthRent), formatPercentage(promotion.DiscountPercentage)))
+		case model.PromotionTypeNoDeposit:
+			paragraphs = append(paragraphs, fmt.Sprintf("En virtud de la promoción «%s», el ARRENDATARIO queda eximido de constituir depósito.", promotion.Name))
+		}
+	}
+	if len(paragraphs) == 0 {
+		return ""
+	}
+	return "PARÁGRAFO PROMOCIONAL: " + strings.Join(paragraphs, " ")
+}
+
+// PromotionDepositText returns the deposit conditions of an offer exempted from the deposit by
+// a promotion, "" when no promotion waives it
+func PromotionDepositText(offer PromotionOffer) string {
+	for _, promotion := range offer.Promotions {
+		if promotion.Type == model.PromotionTypeNoDeposit {
+			return fmt.Sprintf("No se constituye depósito en virtud de la promoción «%s».", promotion.Name)
+		}
+	}
+	return ""
+}
+
+// formatPercentage prints a percentage without trailing decimals (10, 12.5)
+func formatPercentage(percentage float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", percentage), "0"), ".")
+}
+
+// withPromotionPlaceholder returns the clauses of a template with the {{promocion}} placeholder
+// in the price clause, so templates written before promotions existed also print them
+func withPromotionPlaceholder(clauses []model.ContractClause) []model.ContractClause {
+	return withPricePlaceholder(clauses, "promocion")
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Minimal QR code encoder (ISO/IEC 18004) for the verification links printed on signed
+// contracts: byte mode, error correction level M, versions 1 to 10 (up to 213 bytes).
+
+// qrVersionBlocks describes the error correction blocks of a version at level M
+type qrVersionBlocks struct {
+	ecPerBlock   int
+	group1Blocks int
+	group1Data   int
+	group2Blocks int
+	group2Data   int
+}
+
+// qrLevelM holds the block structure of versions 1 to 10 at error correction level M
+var qrLevelM = []qrVersionBlocks{
+	{10, 1, 16, 0, 0},
+	{16, 1, 28, 0, 0},
+	{26, 1, 44, 0, 0},
+	{18, 2, 32, 0, 0},
+	{24, 2, 43, 0, 0},
+	{16, 4, 27, 0, 0},
+	{18, 4, 31, 0, 0},
+	{22, 2, 38, 2, 39},
+	{22, 3, 36, 2, 37},
+	{26, 4, 43, 1, 44},
+}
+
+// qrAlignmentPositions holds the alignment pattern centers of versions 1 to 10
+var qrAlignmentPositions = [][]int{
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// qrCode is the module matrix of an encoded QR code, modules[y][x] is true for dark modules
+type qrCode struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// EncodeQR encodes text as a QR code and returns its modules, true for dark modules
+func EncodeQR(text string) ([][]bool, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= len(qrLevelM); v++ {
+		if 4+qrCountBits(v)+8*len(data) <= qrDataCodewords(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("text of %d bytes is too long for a QR code", len(data))
+	}
+
+	// Byte mode segment, terminator and padding
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>uint(i))&1 == 1)
+		}
+	}
+	appendBits(0x4, 4)
+	appendBits(len(data), qrCountBits(version))
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capacity := qrDataCodewords(version) * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	appendBits(0, terminator)
+	if rem := len(bits) % 8; rem != 0 {
+		appendBits(0, 8-rem)
+	}
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+
+	qr := newQRCode(version)
+	qr.drawCodewords(qrInterleave(version, codewords))
+
+	// Keep the mask with the lowest penalty
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // Masks are their own inverse
+	}
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+
+	return qr.modules, nil
+}
+
+// drawQRCode draws a QR code of text at x, y with the given side length (including the quiet zone)
+func drawQRCode(pdf *gofpdf.Fpdf, text string, x, y, side float64) error {
+	modules, err := EncodeQR(text)
+	if err != nil {
+		return err
+	}
+
+	const quietZone = 4
+	moduleSize := side / float64(len(modules)+2*quietZone)
+	pdf.SetFillColor(0, 0, 0)
+	for row, line := range modules {
+		for col, dark := range line {
+			if dark {
+				pdf.Rect(x+float64(col+quietZone)*moduleSize, y+float64(row+quietZone)*moduleSize, moduleSize, moduleSize, "F")
+			}
+		}
+	}
+	pdf.SetFillColor(255, 255, 255)
+	return nil
+}
+
+// qrCountBits returns the length of the character count of byte mode segments
+func qrCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// qrDataCodewords returns the number of data codewords of a version at level M
+func qrDataCodewords(version int) int {
+	blocks := qrLevelM[version-1]
+	return blocks.group1Blocks*blocks.group1Data + blocks.group2Blocks*blocks.group2Data
+}
+
+// qrInterleave splits the data codewords in blocks, adds the error correction codewords of
+// each block and interleaves them
+func qrInterleave(version int, data []byte) []byte {
+	blocks := qrLevelM[version-1]
+	divisor := qrReedSolomonDivisor(blocks.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < blocks.group1Blocks+blocks.group2Blocks; i++ {
+		length := blocks.group1Data
+		if i >= blocks.group1Blocks {
+			length = blocks.group2Data
+		}
+		block := data[offset : offset+length]
+		offset += length
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, qrReedSolomonRemainder(block, divisor))
+	}
+
+	var result []byte
+	maxData := blocks.group1Data
+	if blocks.group2Data > maxData {
+		maxData = blocks.group2Data
+	}
+	for i := 0; i < maxData; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < blocks.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// qrMultiply multiplies two elements of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func qrMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrReedSolomonDivisor returns the generator polynomial of the given degree, without its leading term
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+// qrReedSolomonRemainder returns the error correction codewords of a block
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= qrMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// newQRCode returns the matrix of a version with its function patterns drawn
+func newQRCode(version int) *qrCode {
+	size := 17 + 4*version
+	qr := &qrCode{size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, size)
+		qr.isFunction[i] = make([]bool, size)
+	}
+
+	// Timing patterns
+	for i := 0; i < size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	qr.drawFinder(3, 3)
+	qr.drawFinder(size-4, 3)
+	qr.drawFinder(3, size-4)
+
+	// Alignment patterns, except where they would overlap the finders
+	positions := qrAlignmentPositions[version-1]
+	last := len(positions) - 1
+	for i, y := range positions {
+		for j, x := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.setFunction(x+dx, y+dy, qrMax(qrAbs(dx), qrAbs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas, drawn once the mask is chosen
+	qr.drawFormatBits(0)
+
+	// Version information
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			bit := (bits>>uint(i))&1 == 1
+			a := size - 11 + i%3
+			b := i / 3
+			qr.setFunction(a, b, bit)
+			qr.setFunction(b, a, bit)
+		}
+	}
+
+	return qr
+}
+
+// setFunction sets a module of a function pattern
+func (qr *qrCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+// drawFinder draws a finder pattern centered at x, y together with its separator
+func (qr *qrCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= qr.size || yy < 0 || yy >= qr.size {
+				continue
+			}
+			distance := qrMax(qrAbs(dx), qrAbs(dy))
+			qr.setFunction(xx, yy, distance != 2 && distance != 4)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information of level M with a mask
+func (qr *qrCode) drawFormatBits(mask int) {
+	data := 0<<3 | mask // Level M is encoded as 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(i))
+	}
+	qr.setFunction(8, 7, bit(6))
+	qr.setFunction(8, 8, bit(7))
+	qr.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.size-15+i, bit(i))
+	}
+	qr.setFunction(8, qr.size-8, true) // Dark module
+}
+
+// drawCodewords places the codewords in the zigzag order, skipping the function patterns
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < qr.size; vert++ {
+			y := vert
+			if upward {
+				y = qr.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i/8]>>uint(7-i%8))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by a mask pattern
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the readability of the matrix, lower is better
+func (qr *qrCode) penalty() int {
+	result := 0
+	line := make([]bool, qr.size)
+
+	for horizontal := 0; horizontal < 2; horizontal++ {
+		for i := 0; i < qr.size; i++ {
+			for j := 0; j < qr.size; j++ {
+				if horizontal == 0 {
+					line[j] = qr.modules[i][j]
+				} else {
+					line[j] = qr.modules[j][i]
+				}
+			}
+
+			// Runs of five or more modules of the same color
+			run := 1
+			for j := 1; j <= qr.size; j++ {
+				if j < qr.size && line[j] == line[j-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// Patterns looking like a finder
+			for j := 0; j+7 <= qr.size; j++ {
+				if !(line[j] && !line[j+1] && line[j+2] && line[j+3] && line[j+4] && !line[j+5] && line[j+6]) {
+					continue
+				}
+				if qrLightRun(line, j-4, j) || qrLightRun(line, j+7, j+11) {
+					result += 40
+				}
+			}
+		}
+	}
+
+	// Blocks of 2x2 modules of the same color
+	dark := 0
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x+1 < qr.size && y+1 < qr.size {
+				color := qr.modules[y][x]
+				if color == qr.modules[y][x+1] && color == qr.modules[y+1][x] && color == qr.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	// Balance of dark and light modules
+	total := qr.size * qr.size
+	k := (qrAbs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+
+	return result
+}
+
+// qrLightRun reports whether the modules from start to end (exclusive) are light, counting
+// the modules outside the matrix as light
+func qrLightRun(line []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func qrMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"html"
+)
+
+// SendReglamentoAcknowledgmentEmail asks a tenant to read and acknowledge a new version of the
+// manual de convivencia of their building
+func SendReglamentoAcknowledgmentEmail(to, tenantName, buildingAddress string, version int) error {
+	subject := "📘 Nuevo manual de convivencia de su edificio"
+	body := fmt.Sprintf(`
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Manual de convivencia</title>
+		<style>
+			body { font-family: Arial, sans-serif; margin: 0; padding: 20px; color: #333; }
+			.container { max-width: 600px; margin: 0 auto; }
+			.header { background-color: #f8f9fa; padding: 20px; text-align: center; }
+			.content { padding: 20px; }
+			.button { display: inline-block; padding: 10px 20px; background-color: #228be6; color: #fff; text-decoration: none; border-radius: 4px; }
+			.footer { margin-top: 20px; font-size: 12px; color: #6c757d; }
+		</style>
+	</head>
+	<body>
+		<div class="container">
+			<div class="header">
+				<h2>Manual de convivencia</h2>
+			</div>
+			<div class="content">
+				<p>Estimado(a) %s,</p>
+				<p>La administración del edificio ubicado en <strong>%s</strong> publicó la versión %d del manual de convivencia.</p>
+				<p>Por favor ingrese a la plataforma, lea el documento y confirme que lo conoce y acepta.</p>
+				<p><a class="button" href="%s/dashboard">Leer el manual de convivencia</a></p>
+				<p>Atentamente,<br>Sistema de Administración de Propiedades</p>
+			</div>
+			<div class="footer">
+				<p>Este es un mensaje automático. Por favor no responda directamente a este correo.</p>
+			</div>
+		</div>
+	</body>
+	</html>
+	`, html.EscapeString(tenantName), html.EscapeString(buildingAddress), version, GetAppBaseURL())
+
+	return SendSimpleEmail(to, subject, body)
+}
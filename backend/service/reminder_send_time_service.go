@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultReminderSendHour is the local hour reminders are delivered at when the recipient
+	// has no preference and REMINDER_DEFAULT_SEND_HOUR is not set
+	defaultReminderSendHour = 8
+	// minOpensForOptimization is the number of tracked opens needed to trust the optimized hour
+	minOpensForOptimization = 3
+	// openHistorySize is the number of recent opens considered by the optimizer
+	openHistorySize = 50
+)
+
+// OptimalSendHour returns the local hour at which most of the given opens happened, the
+// earliest one on ties. ok is false when there are not enough opens to decide.
+func OptimalSendHour(opens []time.Time, location *time.Location) (hour int, ok bool) {
+	if len(opens) < minOpensForOptimization {
+		return 0, false
+	}
+
+	var counts [24]int
+	for _, openedAt := range opens {
+		counts[openedAt.In(location).Hour()]++
+	}
+
+	best := 0
+	for h := 1; h < len(counts); h++ {
+		if counts[h] > counts[best] {
+			best = h
+		}
+	}
+	return best, true
+}
+
+// ReminderScheduler queues reminder emails in the outbox at the hour each recipient prefers
+// or, when the optimizer is enabled, at the hour they usually open email. Hours are local to the
+// timezone of the organization of each recipient.
+type ReminderScheduler struct {
+	preferenceRepo storage.ReminderPreferenceStore
+	outboxRepo     storage.EmailOutboxStore
+	outbox         *EmailOutbox
+	orgService     *OrganizationService
+	enabled        bool
+	defaultHour    int
+}
+
+// NewReminderScheduler creates a new ReminderScheduler. enabled is REMINDER_SEND_TIME_ENABLED
+// and defaultHour REMINDER_DEFAULT_SEND_HOUR, 8 when nil.
+func NewReminderScheduler(preferenceRepo storage.ReminderPreferenceStore, outboxRepo storage.EmailOutboxStore, outbox *EmailOutbox, orgService *OrganizationService, enabled bool, defaultHour *int) *ReminderScheduler {
+	hour := defaultReminderSendHour
+	if defaultHour != nil {
+		hour = *defaultHour
+	}
+	return &ReminderScheduler{
+		preferenceRepo: preferenceRepo,
+		outboxRepo:     outboxRepo,
+		outbox:         outbox,
+		orgService:     orgService,
+		enabled:        enabled,
+		defaultHour:    hour,
+	}
+}
+
+// Enabled reports whether reminders are scheduled through the outbox at the hour of each
+// recipient (REMINDER_SEND_TIME_ENABLED) instead of being sent when the job runs
+func (s *ReminderScheduler) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// location returns the timezone of the organization of a person, APP_TIMEZONE when they have none
+func (s *ReminderScheduler) location(ctx context.Context, personID uuid.UUID) *time.Location {
+	if s.orgService == nil {
+		return AppLocation()
+	}
+	return OrganizationLocation(s.orgService.ForPerson(ctx, personID))
+}
+
+// SendHour returns the local hour reminders are delivered to a person and whether it comes
+// from the optimizer
+func (s *ReminderScheduler) SendHour(ctx context.Context, personID uuid.UUID) (hour int, optimized bool) {
+	preference, err := s.preferenceRepo.GetByPersonID(ctx, personID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not load the reminder preference of person, using the default hour", "component", "reminders", "person_id", personID, "error", err)
+		return s.defaultHour, false
+	}
+	if preference == nil {
+		return s.defaultHour, false
+	}
+
+	if preference.OptimizeSendTime {
+		opened, err := s.outboxRepo.GetOpenedByPersonID(ctx, personID, openHistorySize)
+		if err != nil {
+			logging.FromContext(ctx).Warn("could not load the email opens of person", "component", "reminders", "person_id", personID, "error", err)
+		} else {
+			opens := make([]time.Time, 0, len(opened))
+			for _, email := range opened {
+				if email.OpenedAt != nil {
+					opens = append(opens, *email.OpenedAt)
+				}
+			}
+			if hour, ok := OptimalSendHour(opens, s.location(ctx, personID)); ok {
+				return hour, true
+			}
+		}
+	}
+
+	if preference.SendHour != nil {
+		return *preference.SendHour, false
+	}
+	return s.defaultHour, false
+}
+
+// SendTime returns when a reminder generated at now is delivered to a person: today at their
+// send hour, or right away when that hour has already passed so the reminder is not delayed a day
+func (s *ReminderScheduler) SendTime(ctx context.Context, personID uuid.UUID, now time.Time) time.Time {
+	hour, _ := s.SendHour(ctx, personID)
+	local := now.In(s.location(ctx, personID))
+	sendAt := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, local.Location())
+	if sendAt.Before(now) {
+		return now
+	}
+	return sendAt
+}
+
+// Schedule queues a reminder email for a person at their send time. attachmentPaths are files
+// of the file storage attached when the email is sent.
+func (s *ReminderScheduler) Schedule(ctx context.Context, personID uuid.UUID, to, subject, htmlBody, kind string, attachmentPaths ...string) error {
+	_, err := s.outbox.Enqueue(ctx, model.OutboxEmail{
+		PersonID:        &personID,
+		ToEmail:         to,
+		Subject:         subject,
+		HTMLBody:        htmlBody,
+		Kind:            kind,
+		SendAt:          s.SendTime(ctx, personID, time.Now()),
+		AttachmentPaths: attachmentPaths,
+	})
+	return err
+}
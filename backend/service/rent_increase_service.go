@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// commercialPropertyTypes are property types not covered by the Ley 820 cap
+// (commercial leases are governed by the Código de Comercio)
+var commercialPropertyTypes = []string{"comercial", "commercial", "local", "oficina", "office", "bodega"}
+
+// CalculateRentIncrease computes the canon of the next term according to the pricing policy.
+// manualRent is required for the manual policy. For housing the increase is capped at ipc, the
+// IPC of the previous year (Art. 20 Ley 820 de 2003), and the cap is recorded in the result.
+func CalculateRentIncrease(pricing model.Pricing, propertyType string, manualRent *float64, ipc float64) (*model.RentIncreaseCalculation, error) {
+	if pricing.MonthlyRent <= 0 {
+		return nil, errors.New("pricing has no monthly rent")
+	}
+
+	calculation := &model.RentIncreaseCalculation{
+		Policy:        pricing.IncreasePolicy,
+		IPCPercentage: ipc,
+		PreviousRent:  pricing.MonthlyRent,
+		CalculatedAt:  time.Now(),
+	}
+	if calculation.Policy == "" {
+		calculation.Policy = model.IncreasePolicyIPC
+	}
+
+	switch calculation.Policy {
+	case model.IncreasePolicyIPC:
+		calculation.Spread = pricing.IncreaseSpread
+		calculation.RequestedPercentage = ipc + pricing.IncreaseSpread
+	case model.IncreasePolicyFixed:
+		calculation.RequestedPercentage = pricing.IncreasePercentage
+	case model.IncreasePolicyManual:
+		if manualRent == nil || *manualRent <= 0 {
+			return nil, errors.New("the manual increase policy requires the new monthly rent")
+		}
+		calculation.RequestedPercentage = roundPercentage((*manualRent/pricing.MonthlyRent - 1) * 100)
+	default:
+		return nil, fmt.Errorf("unknown increase policy %q", calculation.Policy)
+	}
+
+	if calculation.RequestedPercentage < 0 && calculation.Policy != model.IncreasePolicyManual {
+		return nil, errors.New("increase percentage cannot be negative")
+	}
+
+	calculation.AppliedPercentage = calculation.RequestedPercentage
+	if !IsCommercialPropertyType(propertyType) && calculation.RequestedPercentage > ipc {
+		calculation.AppliedPercentage = ipc
+		calculation.CappedByLaw = true
+	}
+
+	if calculation.Policy == model.IncreasePolicyManual && !calculation.CappedByLaw {
+		calculation.NewRent = math.Round(*manualRent)
+	} else {
+		calculation.NewRent = ApplyRentIncrease(pricing.MonthlyRent, calculation.AppliedPercentage)
+	}
+
+	return calculation, nil
+}
+
+// IsCommercialPropertyType reports whether a property type is a commercial lease
+func IsCommercialPropertyType(propertyType string) bool {
+	normalized := strings.ToLower(propertyType)
+	for _, commercialType := range commercialPropertyTypes {
+		if strings.Contains(normalized, commercialType) {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeRentIncrease returns a Spanish summary of the calculation for the rental history
+func DescribeRentIncrease(calculation *model.RentIncreaseCalculation) string {
+	var policy string
+	switch calculation.Policy {
+	case model.IncreasePolicyIPC:
+		policy = fmt.Sprintf("IPC %.2f%% + %.2f puntos", calculation.IPCPercentage, calculation.Spread)
+	case model.IncreasePolicyFixed:
+		policy = fmt.Sprintf("porcentaje fijo %.2f%%", calculation.RequestedPercentage)
+	default:
+		policy = "canon acordado manualmente"
+	}
+
+	summary := fmt.Sprintf("Reajuste del canon (%s): %s -> %s, incremento aplicado %.2f%%",
+		policy, FormatMoney(calculation.PreviousRent), FormatMoney(calculation.NewRent), calculation.AppliedPercentage)
+	if calculation.CappedByLaw {
+		summary += fmt.Sprintf(". Limitado al IPC del año anterior (%.2f%%) según el Art. 20 de la Ley 820 de 2003; solicitado %.2f%%",
+			calculation.IPCPercentage, calculation.RequestedPercentage)
+	}
+	return summary
+}
+
+func roundPercentage(percentage float64) float64 {
+	return math.Round(percentage*100) / 100
+}
@@ -1,16 +1,15 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"html/template"
-	"log"
-	"strconv"
+	"log/slog"
 	"time"
 
-	// "github.com/nescool101/rentManager/storage" // No longer directly using storage.GetPayers
+	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
+	// "github.com/nescool101/rentManager/storage" // No longer directly using storage.GetPayers
 	"github.com/nescool101/rentManager/storage" // Added back for repository types
 )
 
@@ -19,77 +18,78 @@ import (
 // GetAllPayers returns all payers from memory
 // This function is obsolete as payers are now in the database.
 func GetAllPayers() []model.Payer {
-	log.Println("⚠️ [WARNING] GetAllPayers called - this function is obsolete.")
+	slog.Warn("getAllPayers called - this function is obsolete")
 	return []model.Payer{} // Return empty slice
 }
 
 // LoadPayers was removed as payers are now in the database.
 
 // NotifyAll fetches active rentals from the database and sends notifications.
-// TODO: This function will require UserRepository access to fetch renter emails.
-func NotifyAll(personRepo *storage.PersonRepository, rentalRepo *storage.RentalRepository, propertyRepo *storage.PropertyRepository, userRepo *storage.UserRepository, pricingRepo *storage.PricingRepository) {
+// When scheduler is not nil the reminders are queued in the outbox at the send time of each
+// renter instead of being sent right away. The emails and receipts carry the branding of the
+// organization of each property, resolved through orgService when it is not nil.
+func NotifyAll(personRepo storage.PersonStore, rentalRepo storage.RentalStore, propertyRepo storage.PropertyStore, userRepo storage.UserStore, pricingRepo storage.PricingStore, orgService *OrganizationService, scheduler *ReminderScheduler) {
 	ctx := context.Background()
-	loc, _ := time.LoadLocation("America/New_York") // Consider making timezone configurable
-	today := time.Now().In(loc)
+	now := time.Now()
 
-	log.Println("ℹ️ [INFO] NotifyAll: Starting notification process...")
+	slog.Info("notifyAll: Starting notification process")
 
 	activeRentals, err := rentalRepo.GetActiveRentals(ctx) // Assuming GetActiveRentals doesn't need a specific user context for a system-wide job
 	if err != nil {
-		log.Printf("❌ [ERROR] NotifyAll: Failed to fetch active rentals: %v", err)
+		slog.Error("notifyAll: Failed to fetch active rentals", "error", err)
 		return
 	}
 
 	if len(activeRentals) == 0 {
-		log.Println("ℹ️ [INFO] NotifyAll: No active rentals found to process.")
+		slog.Info("notifyAll: No active rentals found to process")
 		return
 	}
 
-	log.Printf("ℹ️ [INFO] NotifyAll: Found %d active rentals to process.", len(activeRentals))
+	slog.Info("notifyAll: Found active rentals to process", "active_rentals_count", len(activeRentals))
 
 	for _, rental := range activeRentals {
 		renter, err := personRepo.GetByID(ctx, rental.RenterID)
 		if err != nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Failed to fetch renter (person_id: %s) for rental_id %s: %v. Skipping rental.", rental.RenterID, rental.ID, err)
+			slog.Warn("notifyAll: Failed to fetch renter", "renter_id", rental.RenterID, "rental_id", rental.ID, "error", err)
 			continue
 		}
 		if renter == nil || renter.FullName == "" {
-			log.Printf("⚠️ [WARNING] NotifyAll: Renter (person_id: %s) not found or has no name for rental_id %s. Skipping rental.", rental.RenterID, rental.ID)
+			slog.Warn("notifyAll: Renter not found", "renter_id", rental.RenterID, "rental_id", rental.ID)
 			continue
 		}
 
 		// Fetch user email via UserRepository
 		renterUser, userErr := userRepo.GetByPersonID(ctx, renter.ID)
 		if userErr != nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Error fetching user record for renter (person_id: %s): %v. Skipping rental.", renter.ID, userErr)
+			slog.Warn("notifyAll: Error fetching user record for renter", "renter_id", renter.ID, "error", userErr)
 			continue
 		}
 		if renterUser == nil || renterUser.Email == "" {
-			log.Printf("⚠️ [WARNING] NotifyAll: User record not found or email is missing for renter (person_id: %s). Skipping rental.", renter.ID)
+			slog.Warn("notifyAll: User record not found or email is missing for renter", "renter_id", renter.ID)
 			continue
 		}
 		renterEmail := renterUser.Email
 
-		log.Printf("ℹ️ [INFO] NotifyAll: Processing for renter %s (PersonID: %s, Email: %s)", renter.FullName, renter.ID, renterEmail)
+		slog.Info("notifyAll: Processing for renter", "full_name", renter.FullName, "renter_id", renter.ID, "renter_email", renterEmail)
 
 		property, err := propertyRepo.GetByID(ctx, rental.PropertyID)
 		if err != nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Failed to fetch property (property_id: %s) for rental_id %s: %v. Skipping rental.", rental.PropertyID, rental.ID, err)
+			slog.Warn("notifyAll: Failed to fetch property", "property_id", rental.PropertyID, "rental_id", rental.ID, "error", err)
 			continue
 		}
 		if property == nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Property (property_id: %s) not found for rental_id %s. Skipping rental.", rental.PropertyID, rental.ID)
+			slog.Warn("notifyAll: Property not found", "property_id", rental.PropertyID, "rental_id", rental.ID)
 			continue
 		}
 
 		// Fetch pricing information for the rental
 		pricing, pricingErr := pricingRepo.GetByRentalID(ctx, rental.ID)
 		if pricingErr != nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Error fetching pricing for rental_id %s: %v. Skipping rental.", rental.ID, pricingErr)
+			slog.Warn("notifyAll: Error fetching pricing , skipping rental", "rental_id", rental.ID, "error", pricingErr)
 			continue
 		}
 		if pricing == nil {
-			log.Printf("⚠️ [WARNING] NotifyAll: Pricing information not found for rental_id %s. Skipping rental.", rental.ID)
+			slog.Warn("notifyAll: Pricing information not found , skipping rental", "rental_id", rental.ID)
 			continue
 		}
 
@@ -99,22 +99,28 @@ func NotifyAll(personRepo *storage.PersonRepository, rentalRepo *storage.RentalR
 			if mErr == nil && firstManager != nil {
 				senderName = firstManager.FullName
 			} else {
-				log.Printf("⚠️ [WARNING] NotifyAll: Could not fetch manager details for property %s. Using default sender.", property.ID)
+				slog.Warn("notifyAll: Could not fetch manager details, using default sender", "property_id", property.ID)
 			}
 		}
 
+		var org *model.Organization
+		if orgService != nil {
+			org = orgService.ForProperty(ctx, property.ID)
+		}
+		// Due days and anniversaries fall on the local day of the organization
+		today := now.In(OrganizationLocation(org))
+
 		rentalStartDate := rental.StartDate.Time() // Use .Time() method of FlexibleTime
 
 		rentalDay := rentalStartDate.Day()
 		rentalMonth := rentalStartDate.Month()
 		rentalYear := rentalStartDate.Year()
 
-		log.Printf("Processing rental %s for renter %s, property %s. Start Date: %s (Day: %d, Month: %s, Year: %d)",
-			rental.ID, renterEmail, property.Address, rental.StartDate.Time().Format(time.RFC3339), rentalDay, rentalMonth.String(), rentalYear)
+		slog.Debug("processing rental for renter, property. Start Date", "rental_id", rental.ID, "renter_email", renterEmail, "address", property.Address, "time", rental.StartDate.Time().Format(time.RFC3339), "rental_day", rentalDay, "rental_month", rentalMonth.String(), "rental_year", rentalYear)
 
 		// Call refactored reminder functions
-		sendSameMonthReminderEmail(today, pricing.DueDay, &rental, renter, property, senderName, renterEmail, pricing)
-		sendSameYearReminderEmail(today, rentalDay, rentalMonth, rentalYear, renter, property, senderName, renterEmail)
+		sendSameMonthReminderEmail(ctx, scheduler, today, &rental, renter, property, org, renterUser.Locale, senderName, renterEmail, pricing)
+		sendSameYearReminderEmail(ctx, scheduler, today, rentalDay, rentalMonth, rentalYear, renter, property, org, renterUser.Locale, senderName, renterEmail)
 
 		// _ = today             // Suppress unused error for now
 		// _ = senderName        // Suppress unused error for now
@@ -123,57 +129,41 @@ func NotifyAll(personRepo *storage.PersonRepository, rentalRepo *storage.RentalR
 
 // Send one-year rental anniversary reminder
 // TODO: Refactor this function to accept model.Rental, model.Person (renter), model.Property, senderName string
-func sendSameYearReminderEmail(today time.Time, rentalDay int, rentalMonth time.Month, rentalYear int, renter *model.Person, property *model.Property, senderName string, renterEmail string) {
-	if today.Day() == rentalDay && today.Month() == rentalMonth && today.Year() != rentalYear {
-		log.Printf("📩 [1-YEAR ANNIVERSARY] Preparing for: Renter %s (%s), Property %s",
-			renter.FullName, renterEmail, property.Address)
-
-		subject := "🏡 Aniversario de Arrendamiento"
-		body := fmt.Sprintf(`
-		<!DOCTYPE html>
-		<html>
-		<head>
-		    <meta charset="UTF-8">
-		    <title>Aniversario de Arrendamiento</title>
-		    <style>
-		        body { font-family: Arial, sans-serif; }
-		        .container { padding: 20px; }
-		        .highlight { font-weight: bold; color: #007BFF; }
-		    </style>
-		</head>
-		<body>
-		    <div class="container">
-		        <h2>🏡 ¡Feliz Aniversario de Arrendamiento, %s!</h2>
-		        <p>Hoy se cumple un año desde que inició su contrato de arrendamiento para la propiedad en:</p>
-		        <p class="highlight">%s</p>
-		        <p>Le agradecemos su confianza y esperamos que su experiencia haya sido excelente.</p>
-		        <p>¿Desea renovar su contrato de arrendamiento?</p>
-		        <p>Por favor, comuníquese con nosotros para discutir las opciones de renovación.</p>
-		        <hr>
-		        <p>Atentamente,</p>
-		        <p><strong>%s</strong></p>
-		    </div>
-		</body>
-		</html>
-		`, renter.FullName, property.Address, senderName)
+func sendSameYearReminderEmail(ctx context.Context, scheduler *ReminderScheduler, today time.Time, rentalDay int, rentalMonth time.Month, rentalYear int, renter *model.Person, property *model.Property, org *model.Organization, locale string, senderName string, renterEmail string) {
+	// Contracts started on February 29 celebrate on February 28 in non-leap years
+	if today.Month() == rentalMonth && model.IsDueDay(today, rentalDay) && today.Year() != rentalYear {
+		logging.FromContext(ctx).Info("[1-YEAR ANNIVERSARY] Preparing for: Renter, Property", "full_name", renter.FullName, "renter_email", renterEmail, "address", property.Address)
+
+		subject, body, err := renderEmail(EmailTemplateRentAnniversary, org, locale, RentAnniversaryEmailData{
+			ArrendatarioNombre: renter.FullName,
+			InmuebleDireccion:  property.Address,
+			Remitente:          senderName,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("1-year anniversary email not rendered", "component", "reminders", "full_name", renter.FullName, "renter_email", renterEmail, "error", err)
+			return
+		}
 
 		// Send email to Tenant (Renter)
-		err := SendSimpleEmail(renterEmail, subject, body)
+		sent, err := deliverNotificationEmail(ctx, scheduler, renter.ID, renterEmail, subject, body, model.NotificationTypeAnniversaryReminder)
 		if err != nil {
-			log.Printf("❌ [FAILED] 1-Year Anniversary Email NOT sent to Renter: %s (%s) - Error: %v",
-				renter.FullName, renterEmail, err)
-		} else {
-			log.Printf("✅ [SENT] 1-Year Anniversary Email sent to Renter: %s (%s)",
-				renter.FullName, renterEmail)
+			logging.FromContext(ctx).Error("1-year anniversary email not sent", "component", "reminders", "full_name", renter.FullName, "renter_email", renterEmail, "error", err)
+		} else if sent {
+			logging.FromContext(ctx).Info("1-year anniversary email sent", "component", "reminders", "full_name", renter.FullName, "renter_email", renterEmail)
 		}
+
+		sendNotificationSMS(ctx, renter, model.NotificationTypeAnniversaryReminder,
+			fmt.Sprintf("Hoy se cumple un año más de su contrato de arrendamiento de %s. ¡Gracias por su confianza! - %s", property.Address, senderName))
 	}
 }
 
 // Send one-month rental reminder
 // TODO: Refactor this function to accept model.Rental, model.Person (renter), model.Property, senderName string
-func sendSameMonthReminderEmail(today time.Time, dueDay int, rental *model.Rental, renter *model.Person, property *model.Property, senderName string, renterEmail string, pricing *model.Pricing) {
-	if today.Day() == dueDay { // Use dueDay from pricing
-		log.Printf("📩 [MONTHLY RENT REMINDER] Preparing for: Renter %s (%s), Property %s", renter.FullName, renterEmail, property.Address)
+func sendSameMonthReminderEmail(ctx context.Context, scheduler *ReminderScheduler, today time.Time, rental *model.Rental, renter *model.Person, property *model.Property, org *model.Organization, locale string, senderName string, renterEmail string, pricing *model.Pricing) {
+	// Due days of 29-31 fall on the last day of shorter months, and the ones on a weekend or
+	// holiday on the next business day
+	if pricing.IsDueOn(today) {
+		logging.FromContext(ctx).Info("monthly rent reminder preparing for: Renter, Property", "full_name", renter.FullName, "renter_email", renterEmail, "address", property.Address)
 
 		// Construct Payer-like object for template, or adapt template directly
 		// For now, let's adapt key fields for sendEmail which expects model.Payer
@@ -182,7 +172,8 @@ func sendSameMonthReminderEmail(today time.Time, dueDay int, rental *model.Renta
 			RentalEmail:     renterEmail,
 			PropertyAddress: property.Address,
 			MonthlyRent:     int(pricing.MonthlyRent), // Use MonthlyRent from pricing
-			// DueDate: rental.StartDate.Time().Format("January 2, 2006"), // TODO: Construct actual due date for current month using pricing.DueDay
+			DueDate:         today,                    // The reminder is sent on the due date, a business day
+			// Sender and contract details
 			RenterName:   senderName,              // This is the email sender, effectively
 			RentalDate:   rental.StartDate.Time(), // Pass rental start date
 			NIT:          renter.NIT,              // Pass renter's NIT
@@ -193,27 +184,49 @@ func sendSameMonthReminderEmail(today time.Time, dueDay int, rental *model.Renta
 			UnpaidMonths: rental.UnpaidMonths, // This comes from Rental model
 		}
 
-		err := sendEmail(renterEmail, payerForEmail) // sendEmail still expects a model.Payer
+		breakdown := BreakdownPricing(*pricing)
+		sendNotificationSMS(ctx, renter, model.NotificationTypeRentReminder,
+			fmt.Sprintf("Recordatorio: hoy vence el pago del arriendo de %s por %s. - %s", property.Address, FormatterFor(org).Amount(breakdown.Money(breakdown.TenantTotal)), senderName))
+
+		// The numbered PDF receipt is issued and stored with the rental files even when the
+		// tenant opted out of the email
+		data := newBillingEmailData(payerForEmail, breakdown, org)
+		var attachments []reminderAttachment
+		receipt, err := GetBillingReceipts().Issue(ctx, rental.ID, renter.ID, today, &data, breakdown.TenantTotal)
 		if err != nil {
-			log.Printf("❌ [FAILED] Monthly Rent Reminder NOT sent to %s (%s) - Error: %v", renter.FullName, renterEmail, err)
+			logging.FromContext(ctx).Warn("receipt not issued, sending the reminder without it", "rental_id", rental.ID, "error", err)
+		} else if receipt != nil {
+			attachments = append(attachments, receipt.attachment())
+		}
+
+		subject, body, err := renderEmail(EmailTemplateRentReminder, org, locale, data)
+		sent := false
+		if err == nil {
+			sent, err = deliverNotificationEmail(ctx, scheduler, renter.ID, renterEmail, subject, body, model.NotificationTypeRentReminder, attachments...)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("monthly rent reminder not sent", "component", "reminders", "full_name", renter.FullName, "renter_email", renterEmail, "error", err)
 			return
 		}
-		log.Printf("✅ [SENT] Monthly Rent Reminder sent to: %s (%s) for property %s", renter.FullName, renterEmail, property.Address)
+		if sent {
+			logging.FromContext(ctx).Info("monthly rent reminder sent", "component", "reminders", "full_name", renter.FullName, "renter_email", renterEmail, "address", property.Address)
+		}
 	} else {
 		// This log might be too verbose if NotifyAll runs daily. Consider removing or reducing its frequency.
 		// log.Printf("Skipping monthly reminder for %s (%s) - Day %d != %d", renter.FullName, renterEmail, today.Day(), rentalDay)
 	}
 }
 
-// EmailTemplate represents the structure of the email data
-type EmailTemplate struct {
+// BillingEmailData is the data of the monthly billing email (EmailTemplateRentReminder)
+type BillingEmailData struct {
 	EmisorNombre         string
 	EmisorNIT            string
 	EmisorDireccion      string
 	EmisorTelefono       string
 	EmisorEmail          string
-	NumeroCuenta         int
+	NumeroCuenta         string // Number of the billing receipt, empty when none was issued
 	FechaEmision         string
+	FechaLimitePago      string // Due date, moved to the next business day when needed
 	ArrendatarioNombre   string
 	ArrendatarioNIT      string
 	InmuebleDireccion    string
@@ -232,94 +245,117 @@ type EmailTemplate struct {
 	ArrendadorNombre     string
 	UnpaidMonths         int
 	TotalDue             string
+	Conceptos            []BillingConcept
+	IVA                  string
+
+	// organization is the issuer whose logo and colors the PDF receipt carries, nil for none
+	organization *model.Organization
+}
+
+// BillingConcept is a charge of the billing email
+type BillingConcept struct {
+	Concepto string
+	Valor    string
+	IVA      string
+	Total    string
+}
+
+// billingEmailSubject is the default subject of the monthly billing email
+const billingEmailSubject = "Cuenta de Cobro Arrendamiento"
+
+// reminderAttachment is a file attached to a reminder. Reminders queued in the outbox keep only
+// Path and load the file from the file storage when they are sent.
+type reminderAttachment struct {
+	Path string // Location in the file storage, empty when the file could not be stored
+	EmailAttachment
+}
+
+// deliverReminder queues a reminder through the scheduler, or sends it right away when
+// scheduler is nil. Reminders with attachments missing from the file storage are sent right
+// away too, since the outbox could not attach them later.
+func deliverReminder(ctx context.Context, scheduler *ReminderScheduler, personID uuid.UUID, to, subject, body, kind string, attachments ...reminderAttachment) error {
+	attachmentPaths := make([]string, 0, len(attachments))
+	emailAttachments := make([]EmailAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachment.Path == "" && scheduler != nil {
+			logging.FromContext(ctx).Warn("has an attachment outside the file storage, sending it right away", "kind", kind, "to", to)
+			scheduler = nil
+		}
+		attachmentPaths = append(attachmentPaths, attachment.Path)
+		emailAttachments = append(emailAttachments, attachment.EmailAttachment)
+	}
+
+	if scheduler == nil {
+		if len(emailAttachments) > 0 {
+			return SendEmailWithAttachments(to, subject, body, emailAttachments)
+		}
+		return SendSimpleEmail(to, subject, body)
+	}
+	if err := scheduler.Schedule(ctx, personID, to, subject, body, kind, attachmentPaths...); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("email queued in the outbox", "component", "outbox", "kind", kind, "to", to)
+	return nil
+}
+
+// deliverNotificationEmail delivers a reminder when the notification preferences of the person
+// allow it, with the unsubscribe and manage-preferences links. sent is false when the person
+// opted out of the notification type.
+func deliverNotificationEmail(ctx context.Context, scheduler *ReminderScheduler, personID uuid.UUID, to, subject, body, notificationType string, attachments ...reminderAttachment) (bool, error) {
+	preferences := GetNotificationPreferences()
+	return preferences.Notify(ctx, personID, model.NotificationChannelEmail, notificationType, func() error {
+		return deliverReminder(ctx, scheduler, personID, to, subject, preferences.withLinks(body, personID, notificationType), notificationType, attachments...)
+	})
 }
 
-// Email template in HTML format
-const emailTemplateHTML = `
-<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <title>Cuenta de Cobro</title>
-</head>
-<body>
-    <hr>
-    <h3>CUENTA DE COBRO ARRENDAMIENTO N° {{.NumeroCuenta}}</h3>
-    <p>Fecha: {{.FechaEmision}}</p>
-    <h4>Informacion de arrendatario:</h4>
-    <p>Nombre del Arrendatario: {{.ArrendatarioNombre}}</p>
-    <p>NIT/Cédula del Arrendatario: {{.ArrendatarioNIT}}</p>
-    <p>Dirección del Inmueble Arrendado: {{.InmuebleDireccion}}</p>
-    <hr>
-    <h3>Descripción del Arrendamiento:</h3>
-    <table border="1">
-        <tr>
-            <th>Tipo de Inmueble</th>
-            <th>Fecha Inicio</th>
-            <th>Fecha Final</th>
-            <th>Valor Mensual</th>
-            <th>Subtotal</th>
-        </tr>
-        <tr>
-            <td>{{.TipoInmueble}}</td>
-            <td>{{.FechaInicio}}</td>
-            <td>{{.FechaFinal}}</td>
-            <td>{{.ValorMensual}}</td>
-            <td>{{.Subtotal}}</td>
-        </tr>
-    </table>
-    <h3>Total a Pagar: {{.TotalPagar}}</h3>
-    {{if gt .UnpaidMonths 0}}
-        <div class="highlight">
-            <h3 class="warning">⚠️ Pagos Atrasados</h3>
-            <p>El arrendatario tiene <strong>{{.UnpaidMonths}} meses</strong> sin pagar.</p>
-            <p>Monto total adeudado: <strong>{{.TotalDue}}</strong></p>
-            <p>Por favor, realice el pago lo antes posible para evitar sanciones.</p>
-        </div>
-        <hr>
-    {{end}}
-
-    <hr>
-    <h4>Condiciones de Pago:</h4>
-    <p>{{.CondicionesPago}}</p>
-    <h4>Datos Bancarios para Transferencias:</h4>
-    <p>Banco: {{.Banco}}</p>
-    <p>Tipo de Cuenta: {{.TipoCuenta}}</p>
-    <p>Número de Cuenta: {{.NumeroCuentaBancaria}}</p>
-    <p>Titular de la Cuenta: {{.TitularCuenta}}</p>
-    <h4>Observaciones Adicionales:</h4>
-    <p>{{.Observaciones}}</p>
-    <hr>
-    <p>Atentamente,</p>
-    <p>{{.ArrendadorNombre}}</p>
-</body>
-</html>
-`
-
-func sendEmail(to string, payer model.Payer) error {
-	// Convert MonthlyRent to an integer (removing "USD" or currency text)
-	totalDue := 0
+// newBillingEmailData builds the data of the billing email and PDF receipt of a payer,
+// itemizing the charges of the tenant in the breakdown of their pricing. The issuer is the
+// branding of org; without one it is the sender of the reminder (payer.RenterName). Dates and
+// amounts use the locale and timezone of org, the amounts in the currency of the breakdown.
+func newBillingEmailData(payer model.Payer, breakdown PricingBreakdown, org *model.Organization) BillingEmailData {
+	formatter := FormatterFor(org)
+	money := func(amount float64) string {
+		return formatter.Amount(breakdown.Money(amount))
+	}
+	totalDue := 0.0
 	if payer.UnpaidMonths > 0 {
-		totalDue = payer.MonthlyRent * payer.UnpaidMonths
+		totalDue = breakdown.TenantTotal * float64(payer.UnpaidMonths)
+	}
+
+	concepts := make([]BillingConcept, 0, len(breakdown.Lines))
+	for _, line := range breakdown.TenantLines() {
+		concepts = append(concepts, BillingConcept{
+			Concepto: line.Label,
+			Valor:    money(line.Amount),
+			IVA:      money(line.IVA),
+			Total:    money(line.Total),
+		})
 	}
 
-	data := EmailTemplate{
-		EmisorNombre:         "Mi Empresa S.A.",
-		EmisorNIT:            "123456789",
-		EmisorDireccion:      "Calle 123, Ciudad",
-		EmisorTelefono:       "555-1234",
-		EmisorEmail:          "empresa@example.com",
-		NumeroCuenta:         rentalDateToInt(payer.RentalDate),
-		FechaEmision:         payer.RentalDate.Format("02/01/2006"),
+	issuer, ok := BrandingFor(org)
+	if !ok {
+		issuer = model.OrganizationBranding{CompanyName: payer.RenterName}
+	}
+
+	return BillingEmailData{
+		EmisorNombre:         issuer.CompanyName,
+		EmisorNIT:            issuer.NIT,
+		EmisorDireccion:      issuer.Address,
+		EmisorTelefono:       issuer.Phone,
+		EmisorEmail:          issuer.Email,
+		FechaEmision:         formatter.Date(payer.RentalDate),
+		FechaLimitePago:      formatter.Date(payer.DueDate),
 		ArrendatarioNombre:   payer.Name,
 		ArrendatarioNIT:      payer.NIT,
 		InmuebleDireccion:    payer.PropertyAddress,
 		TipoInmueble:         payer.PropertyType,
-		FechaInicio:          payer.RentalStart.Format("02/01/2006"),
-		FechaFinal:           payer.RentalEnd.Format("02/01/2006"),
-		ValorMensual:         strconv.Itoa(payer.MonthlyRent),
-		Subtotal:             strconv.Itoa(payer.MonthlyRent),
-		TotalPagar:           strconv.Itoa(payer.MonthlyRent),
+		FechaInicio:          formatter.Date(payer.RentalStart),
+		FechaFinal:           formatter.Date(payer.RentalEnd),
+		ValorMensual:         money(float64(payer.MonthlyRent)),
+		Subtotal:             money(breakdown.TenantSubtotal),
+		IVA:                  money(breakdown.TenantIVA),
+		TotalPagar:           money(breakdown.TenantTotal),
+		Conceptos:            concepts,
 		CondicionesPago:      "Pago antes del 5 de cada mes",
 		Banco:                payer.BankName,
 		TipoCuenta:           payer.AccountType,
@@ -328,82 +364,67 @@ func sendEmail(to string, payer model.Payer) error {
 		Observaciones:        payer.AdditionalNotes,
 		ArrendadorNombre:     payer.RenterName,
 		UnpaidMonths:         payer.UnpaidMonths,
-		TotalDue:             strconv.Itoa(totalDue) + " COP",
-	}
-
-	// Parse and execute the HTML template
-	tmpl, err := template.New("email").Parse(emailTemplateHTML)
-	if err != nil {
-		log.Println("Error parsing template:", err)
-		return err
-	}
-
-	var body bytes.Buffer
-	if err := tmpl.Execute(&body, data); err != nil {
-		log.Println("Error executing template:", err)
-		return err
+		TotalDue:             money(totalDue) + " " + breakdown.Currency,
+		organization:         org,
 	}
-
-	// Use our new ProtonMail implementation instead of Resend
-	err = SendProtonMailEmail(to, "Cuenta de Cobro Arrendamiento", body.String())
-	if err != nil {
-		log.Printf("❌ [EMAIL NOT SENT] %s (%s) - Error: %v", payer.Name, to, err)
-		return err
-	}
-
-	log.Printf("✅ [EMAIL SENT] %s (%s)", payer.Name, to)
-	return nil
 }
 
-func rentalDateToInt(date time.Time) int {
-	return date.Year()*10000 + int(date.Month())*100 + date.Day()
+// renewalReminderWindow returns the end dates of the contracts reminded of their renewal at now:
+// approximately one month from the local day of now, with a small window (28 to 32 days)
+func renewalReminderWindow(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	today := calendarDayIn(now, loc)
+	return today.AddDate(0, 1, -2), today.AddDate(0, 1, 2)
 }
 
 // SendAnnualRenewalReminders sends reminders to tenants whose contracts are ending in approximately one month.
-func SendAnnualRenewalReminders(ctx context.Context, personRepo *storage.PersonRepository, rentalRepo *storage.RentalRepository, propertyRepo *storage.PropertyRepository, userRepo *storage.UserRepository, optionalMessage string) (int, error) {
-	loc, _ := time.LoadLocation("America/New_York")      // Consider making timezone configurable
-	today := time.Now().In(loc).Truncate(24 * time.Hour) // Truncate to just the date part
-	targetEndDateLowerBound := today.AddDate(0, 1, -2)   // Approx 1 month from today, with a small window (e.g., 28 days)
-	targetEndDateUpperBound := today.AddDate(0, 1, 2)    // Approx 1 month from today, with a small window (e.g., 32 days)
-
-	log.Printf("ℹ️ [ANNUAL REMINDER] Starting process. Target EndDate window: %s to %s", targetEndDateLowerBound.Format("2006-01-02"), targetEndDateUpperBound.Format("2006-01-02"))
+// The emails carry the branding of the organization of each property when orgService is not nil,
+// and the window of each rental is computed in the timezone of its organization.
+func SendAnnualRenewalReminders(ctx context.Context, personRepo storage.PersonStore, rentalRepo storage.RentalStore, propertyRepo storage.PropertyStore, userRepo storage.UserStore, orgService *OrganizationService, optionalMessage string) (int, error) {
+	now := time.Now()
+	logging.FromContext(ctx).Info("annual reminder starting", "now", now.Format(time.RFC3339))
 
 	activeRentals, err := rentalRepo.GetActiveRentals(ctx)
 	if err != nil {
-		log.Printf("❌ [ERROR] SendAnnualRenewalReminders: Failed to fetch active rentals: %v", err)
+		logging.FromContext(ctx).Error("sendAnnualRenewalReminders: Failed to fetch active rentals", "error", err)
 		return 0, fmt.Errorf("failed to fetch active rentals: %w", err)
 	}
 
 	if len(activeRentals) == 0 {
-		log.Println("ℹ️ [INFO] SendAnnualRenewalReminders: No active rentals found.")
+		logging.FromContext(ctx).Info("sendAnnualRenewalReminders: No active rentals found")
 		return 0, nil
 	}
 
 	emailsSent := 0
 	for _, rental := range activeRentals {
-		rentalEndDate := rental.EndDate.Time().In(loc).Truncate(24 * time.Hour)
+		var org *model.Organization
+		if orgService != nil {
+			org = orgService.ForProperty(ctx, rental.PropertyID)
+		}
+		loc := OrganizationLocation(org)
+		targetEndDateLowerBound, targetEndDateUpperBound := renewalReminderWindow(now, loc)
+		rentalEndDate := calendarDayIn(rental.EndDate.Time(), loc)
 
 		// Check if the rental end date is within our target window (approx. 1 month from now)
 		if (rentalEndDate.After(targetEndDateLowerBound) || rentalEndDate.Equal(targetEndDateLowerBound)) &&
 			(rentalEndDate.Before(targetEndDateUpperBound) || rentalEndDate.Equal(targetEndDateUpperBound)) {
 
-			log.Printf("Processing rental %s ending on %s for annual renewal reminder.", rental.ID, rentalEndDate.Format("2006-01-02"))
+			logging.FromContext(ctx).Debug("processing rental ending for annual renewal reminder", "rental_id", rental.ID, "rental_end_date", rentalEndDate.Format("2006-01-02"))
 
 			renter, pErr := personRepo.GetByID(ctx, rental.RenterID)
 			if pErr != nil || renter == nil {
-				log.Printf("⚠️ [WARNING] SendAnnualRenewalReminders: Failed to fetch renter for rental_id %s: %v. Skipping.", rental.ID, pErr)
+				logging.FromContext(ctx).Warn("annual reminder skipped, could not fetch the renter", "rental_id", rental.ID, "error", pErr)
 				continue
 			}
 
 			renterUser, uErr := userRepo.GetByPersonID(ctx, renter.ID)
 			if uErr != nil || renterUser == nil || renterUser.Email == "" {
-				log.Printf("⚠️ [WARNING] SendAnnualRenewalReminders: User/email not found for renter %s (PersonID: %s). Skipping.", renter.FullName, renter.ID)
+				logging.FromContext(ctx).Warn("sendAnnualRenewalReminders: User/email not found for renter", "full_name", renter.FullName, "renter_id", renter.ID)
 				continue
 			}
 
 			property, propErr := propertyRepo.GetByID(ctx, rental.PropertyID)
 			if propErr != nil || property == nil {
-				log.Printf("⚠️ [WARNING] SendAnnualRenewalReminders: Property not found for rental_id %s. Skipping.", rental.ID)
+				logging.FromContext(ctx).Warn("annual reminder skipped, property not found", "rental_id", rental.ID)
 				continue
 			}
 
@@ -415,28 +436,32 @@ func SendAnnualRenewalReminders(ctx context.Context, personRepo *storage.PersonR
 				}
 			}
 
-			subject := fmt.Sprintf("Reminder: Your Lease for %s is Ending Soon", property.Address)
-			bodyText := fmt.Sprintf(
-				`<!DOCTYPE html><html><head><title>%s</title></head><body>
-				<p>Dear %s,</p>
-				<p>This is a friendly reminder that your lease agreement for the property at <strong>%s</strong> is scheduled to end on <strong>%s</strong>.</p>
-				<p>We value you as a tenant and would like to invite you to discuss renewal options. Please contact us at your earliest convenience to explore continuing your stay.</p>`,
-				subject, renter.FullName, property.Address, rental.EndDate.Time().Format("January 2, 2006"))
-
-			if optionalMessage != "" {
-				bodyText += fmt.Sprintf("<p><strong>Additional message from administration:</strong><br>%s</p>", optionalMessage)
+			formatter := FormatterFor(org)
+			subject, bodyText, err := renderEmail(EmailTemplateRentRenewal, org, renterUser.Locale, RentRenewalEmailData{
+				ArrendatarioNombre: renter.FullName,
+				InmuebleDireccion:  property.Address,
+				FechaFinal:         emailFormatter(renterUser.Locale, org).Date(rental.EndDate.Time()),
+				MensajeAdicional:   optionalMessage,
+				Remitente:          senderName,
+			})
+			if err != nil {
+				logging.FromContext(ctx).Error("annual reminder failed", "email", renterUser.Email, "address", property.Address, "error", err)
+				continue
 			}
-			bodyText += fmt.Sprintf("<p>Sincerely,</p><p>%s</p></body></html>", senderName)
 
-			if err := SendSimpleEmail(renterUser.Email, subject, bodyText); err == nil {
-				log.Printf("✅ [ANNUAL REMINDER SENT] To: %s for property %s", renterUser.Email, property.Address)
+			sendNotificationSMS(ctx, renter, model.NotificationTypeRenewalReminder,
+				fmt.Sprintf("Su contrato de arrendamiento de %s termina el %s. Contáctenos para su renovación. - %s", property.Address, formatter.Date(rental.EndDate.Time()), senderName))
+
+			sent, err := deliverNotificationEmail(ctx, nil, renter.ID, renterUser.Email, subject, bodyText, model.NotificationTypeRenewalReminder)
+			if err != nil {
+				logging.FromContext(ctx).Error("annual reminder failed", "email", renterUser.Email, "address", property.Address, "error", err)
+			} else if sent {
+				logging.FromContext(ctx).Info("annual reminder sent for property", "email", renterUser.Email, "address", property.Address)
 				emailsSent++
-			} else {
-				log.Printf("❌ [ANNUAL REMINDER FAILED] To: %s for property %s - Error: %v", renterUser.Email, property.Address, err)
 			}
 		} // end if rental end date in window
 	} // end for rental
 
-	log.Printf("ℹ️ [ANNUAL REMINDER] Process finished. %d emails sent.", emailsSent)
+	logging.FromContext(ctx).Info("annual reminder process finished. emails sent", "emails_sent", emailsSent)
 	return emailsSent, nil
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// RentalHistoryService writes the rental history of the tenants as their rentals are created,
+// renewed, transferred or terminated, so the history used to screen them is not typed by hand
+type RentalHistoryService struct {
+	repo storage.RentalHistoryStore
+}
+
+var rentalHistory *RentalHistoryService
+
+// InitializeRentalHistory creates the service recording the lifecycle events of the rentals
+func InitializeRentalHistory(repo storage.RentalHistoryStore) *RentalHistoryService {
+	rentalHistory = &RentalHistoryService{repo: repo}
+	return rentalHistory
+}
+
+// GetRentalHistory returns the rental history service, nil when the events are not recorded
+func GetRentalHistory() *RentalHistoryService {
+	return rentalHistory
+}
+
+// RentalCreated records a new rental as active until its agreed end date
+func (s *RentalHistoryService) RentalCreated(rental model.Rental) {
+	s.record(&storage.RentalHistory{
+		PersonID: rental.RenterID.String(),
+		RentalID: rental.ID.String(),
+		Status:   model.RentalHistoryActive,
+		EndDate:  rental.EndDate,
+	})
+}
+
+// RentalRenewed closes a rental continued by next, keeping how the canon of next was computed
+func (s *RentalHistoryService) RentalRenewed(rental, next model.Rental, increase *model.RentIncreaseCalculation) {
+	s.record(&storage.RentalHistory{
+		PersonID:     rental.RenterID.String(),
+		RentalID:     rental.ID.String(),
+		Status:       model.RentalHistoryRenewed,
+		EndReason:    "Renovado con el contrato " + next.ID.String() + ". " + DescribeRentIncrease(increase),
+		EndDate:      rental.EndDate,
+		RentIncrease: increase,
+		NextRentalID: next.ID.String(),
+	})
+	s.RentalCreated(next)
+}
+
+// RentalTransferred closes a rental on endDate, the day before the tenant moved to next at
+// address, keeping the deposit carried over
+func (s *RentalHistoryService) RentalTransferred(rental, next model.Rental, endDate time.Time, address string, deposit *model.DepositTransfer) {
+	endReason := "Trasladado al inmueble " + address + " con el contrato " + next.ID.String() + "."
+	if deposit != nil {
+		endReason += " " + DescribeDepositTransfer(*deposit)
+	}
+	s.record(&storage.RentalHistory{
+		PersonID:        rental.RenterID.String(),
+		RentalID:        rental.ID.String(),
+		Status:          model.RentalHistoryTransferred,
+		EndReason:       endReason,
+		EndDate:         model.FlexibleTime(endDate),
+		NextRentalID:    next.ID.String(),
+		DepositTransfer: deposit,
+	})
+	s.RentalCreated(next)
+}
+
+// RentalTerminated closes a rental ended on endDate, before the agreedEnd of its contract. A
+// rental already closed in the history (moving its end date again) is not recorded twice.
+func (s *RentalHistoryService) RentalTerminated(rental model.Rental, endDate, agreedEnd time.Time) {
+	if s.isClosed(rental) {
+		return
+	}
+	s.record(&storage.RentalHistory{
+		PersonID:  rental.RenterID.String(),
+		RentalID:  rental.ID.String(),
+		Status:    model.RentalHistoryTerminated,
+		EndReason: fmt.Sprintf("Terminado antes de la fecha pactada (%s)", agreedEnd.Format("2006-01-02")),
+		EndDate:   model.FlexibleTime(endDate),
+	})
+}
+
+// isClosed reports whether the history of a rental already has the record that closes it
+func (s *RentalHistoryService) isClosed(rental model.Rental) bool {
+	if s == nil {
+		return false
+	}
+	records, err := s.repo.GetByRentalID(rental.ID.String())
+	if err != nil {
+		slog.Warn("failed to read the rental history", "component", "history", "rental_id", rental.ID, "error", err)
+		return false
+	}
+	for _, record := range records {
+		switch record.Status {
+		case model.RentalHistoryTerminated, model.RentalHistoryRenewed, model.RentalHistoryTransferred:
+			return true
+		}
+	}
+	return false
+}
+
+// record writes a history record. The event already happened, so a failure is only logged.
+// Does nothing when s is nil.
+func (s *RentalHistoryService) record(history *storage.RentalHistory) {
+	if s == nil {
+		return
+	}
+	if _, err := s.repo.Create(history); err != nil {
+		slog.Warn("failed to record the rental in the rental history", "component", "history", "status", history.Status, "rental_id", history.RentalID, "error", err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+const resendAPIURL = "https://api.resend.com"
+
+// ResendEmailSender sends emails through the Resend HTTP API
+type ResendEmailSender struct {
+	apiKey     string
+	from       string
+	httpClient *http.Client
+}
+
+// NewResendEmailSender creates a Resend driver with RESEND_API_KEY. EMAIL_FROM (from) must belong
+// to a domain verified in Resend.
+func NewResendEmailSender(cfg config.ResendConfig, from string) (*ResendEmailSender, error) {
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		return nil, errors.New("RESEND_API_KEY is not configured")
+	}
+	if from == "" {
+		return nil, errors.New("EMAIL_FROM is required by the resend driver")
+	}
+
+	return &ResendEmailSender{
+		apiKey:     apiKey,
+		from:       emailFromAddress(),
+		httpClient: &http.Client{Timeout: emailSendTimeout},
+	}, nil
+}
+
+// Name returns the driver name
+func (r *ResendEmailSender) Name() string {
+	return config.EmailDriverResend
+}
+
+// Send delivers the message with POST /emails
+func (r *ResendEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	type resendAttachment struct {
+		Filename string `json:"filename"`
+		Content  string `json:"content"`
+	}
+	payload := struct {
+		From        string             `json:"from"`
+		To          []string           `json:"to"`
+		Subject     string             `json:"subject"`
+		HTML        string             `json:"html"`
+		Attachments []resendAttachment `json:"attachments,omitempty"`
+	}{
+		From:    r.from,
+		To:      msg.To,
+		Subject: msg.Subject,
+		HTML:    msg.HTMLBody,
+	}
+	for _, attachment := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, resendAttachment{
+			Filename: attachment.Name,
+			Content:  base64.StdEncoding.EncodeToString(attachment.Data),
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode resend request: %w", err)
+	}
+	req, err := r.newRequest(ctx, http.MethodPost, "/emails", body)
+	if err != nil {
+		return err
+	}
+	_, err = doEmailProviderRequest(r.httpClient, req, r.Name())
+	return err
+}
+
+// HealthCheck validates the API key listing the domains. Keys restricted to sending cannot
+// list them, Resend then answers restricted_api_key, which still proves the key is valid.
+func (r *ResendEmailSender) HealthCheck(ctx context.Context) error {
+	req, err := r.newRequest(ctx, http.MethodGet, "/domains", nil)
+	if err != nil {
+		return err
+	}
+	body, err := doEmailProviderRequest(r.httpClient, req, r.Name())
+	if err != nil && strings.Contains(string(body), "restricted_api_key") {
+		return nil
+	}
+	return err
+}
+
+func (r *ResendEmailSender) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, resendAPIURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resend request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
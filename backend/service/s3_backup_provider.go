@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// S3BackupProvider respalda archivos en un bucket de Amazon S3 o de un servicio compatible
+// (MinIO, Cloudflare R2, ...). Las peticiones se firman con AWS Signature Version 4.
+type S3BackupProvider struct {
+	endpoint string
+	bucket   string
+	prefix   string
+	signer   awsSigner
+	client   *http.Client
+}
+
+// NewS3BackupProvider crea el proveedor con BACKUP_S3_BUCKET, BACKUP_S3_ACCESS_KEY_ID y
+// BACKUP_S3_SECRET_ACCESS_KEY. BACKUP_S3_REGION es us-east-1 por defecto, BACKUP_S3_ENDPOINT
+// permite usar un servicio compatible con S3 y BACKUP_S3_PREFIX es la carpeta de los backups.
+func NewS3BackupProvider(cfg config.S3BackupConfig) (*S3BackupProvider, error) {
+	bucket := cfg.Bucket
+	accessKeyID := cfg.AccessKeyID
+	secretAccessKey := cfg.SecretAccessKey
+	if bucket == "" || accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("BACKUP_S3_BUCKET, BACKUP_S3_ACCESS_KEY_ID y BACKUP_S3_SECRET_ACCESS_KEY son requeridas")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("BACKUP_S3_ENDPOINT inválido: %v", err)
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = "backups"
+	}
+
+	return &S3BackupProvider{
+		endpoint: endpoint,
+		bucket:   bucket,
+		prefix:   strings.Trim(prefix, "/"),
+		signer: awsSigner{
+			accessKeyID:     accessKeyID,
+			secretAccessKey: secretAccessKey,
+			region:          region,
+			service:         "s3",
+		},
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name devuelve el nombre del proveedor
+func (p *S3BackupProvider) Name() string {
+	return BackupProviderS3
+}
+
+// Backup sube el archivo a {prefijo}/{fecha}/{ruta original}
+func (p *S3BackupProvider) Backup(ctx context.Context, file BackupFile) (string, error) {
+	key := fmt.Sprintf("%s/%s/%s", p.prefix, time.Now().Format("2006/01/02"), strings.TrimPrefix(file.Path, "/"))
+	target, err := url.Parse(fmt.Sprintf("%s/%s/%s", p.endpoint, p.bucket, s3EscapePath(key)))
+	if err != nil {
+		return "", fmt.Errorf("error armando la URL del objeto: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(file.Data))
+	if err != nil {
+		return "", fmt.Errorf("error creando request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	p.signer.sign(req, file.Data, time.Now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error enviando archivo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("error de S3: %d - %s", resp.StatusCode, string(body))
+	}
+	return fmt.Sprintf("s3://%s/%s", p.bucket, key), nil
+}
+
+// Restore descarga el objeto de location (s3://bucket/clave)
+func (p *S3BackupProvider) Restore(ctx context.Context, location string) ([]byte, error) {
+	bucketAndKey, ok := strings.CutPrefix(location, "s3://")
+	bucket, key, found := strings.Cut(bucketAndKey, "/")
+	if !ok || !found || bucket == "" || key == "" {
+		return nil, fmt.Errorf("ubicación de S3 inválida: %s", location)
+	}
+	target, err := url.Parse(fmt.Sprintf("%s/%s/%s", p.endpoint, bucket, s3EscapePath(key)))
+	if err != nil {
+		return nil, fmt.Errorf("error armando la URL del objeto: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando request: %v", err)
+	}
+	p.signer.sign(req, nil, time.Now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error descargando archivo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error de S3: %d - %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// s3EscapePath codifica cada segmento de la clave como exige la firma de S3: todo salvo las
+// letras, los dígitos y -._~
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		var escaped strings.Builder
+		for _, b := range []byte(segment) {
+			switch {
+			case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+				b == '-', b == '.', b == '_', b == '~':
+				escaped.WriteByte(b)
+			default:
+				fmt.Fprintf(&escaped, "%%%02X", b)
+			}
+		}
+		segments[i] = escaped.String()
+	}
+	return strings.Join(segments, "/")
+}
@@ -1,23 +1,58 @@
 package service
 
 import (
-	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/nescool101/rentManager/storage"
 	"github.com/robfig/cron/v3"
 )
 
+// addLocalJob registers job to run at schedule in each of the timezones locations returns. It
+// checks the schedules every minute, so the timezones of organizations created later are
+// scheduled too.
+func addLocalJob(c *cron.Cron, schedule string, locations func() []*time.Location, job func(loc *time.Location)) error {
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	last := time.Now()
+	_, err := c.AddFunc("* * * * *", func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		for _, loc := range locations() {
+			local, err := cron.ParseStandard("CRON_TZ=" + loc.String() + " " + schedule)
+			if err != nil {
+				slog.Error("invalid schedule for timezone", "component", "scheduler", "schedule", schedule, "timezone", loc.String(), "error", err)
+				continue
+			}
+			if !local.Next(last).After(now) {
+				job(loc)
+			}
+		}
+		last = now
+	})
+	return err
+}
+
 // StartScheduler initializes and starts the cron scheduler.
-func StartScheduler(personRepo *storage.PersonRepository, rentalRepo *storage.RentalRepository, propertyRepo *storage.PropertyRepository, userRepo *storage.UserRepository, pricingRepo *storage.PricingRepository) {
+// reminders may be nil to send the reminders when the job runs.
+func StartScheduler(personRepo storage.PersonStore, rentalRepo storage.RentalStore, propertyRepo storage.PropertyStore, userRepo storage.UserStore, pricingRepo storage.PricingStore, orgService *OrganizationService, reminders *ReminderScheduler) {
 	c := cron.New()
 	_, err := c.AddFunc("@monthly", func() { // You can change the schedule as needed, e.g., "0 0 1 * *" for 1st of every month
-		log.Println("🗓️ [SCHEDULER] Running monthly notification job via cron...")
-		NotifyAll(personRepo, rentalRepo, propertyRepo, userRepo, pricingRepo)
+		slog.Info("running monthly notification job via cron", "component", "scheduler")
+		NotifyAll(personRepo, rentalRepo, propertyRepo, userRepo, pricingRepo, orgService, reminders)
 	})
 	if err != nil {
-		log.Fatalf("❌ [CRITICAL] Error adding cron job to scheduler: %v", err)
+		slog.Error("error adding cron job to scheduler", "component", "scheduler", "error", err)
+		os.Exit(1)
 	}
-	log.Println("ℹ️ [SCHEDULER] Cron scheduler started. Monthly notification job registered.")
+	slog.Info("cron scheduler started. Monthly notification job registered", "component", "scheduler")
 	c.Start()
 
 	// Keep the scheduler running in the background if this function is run as a goroutine.
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// DepositSettlementPDF holds the data of the move-out settlement of a security deposit
+type DepositSettlementPDF struct {
+	Deposit     model.SecurityDeposit
+	Rental      *model.Rental
+	Property    *model.Property
+	Tenant      *model.Person
+	TenantEmail string
+	Holder      *model.Person      // Who returns the deposit, the owner or the manager
+	BankAccount *model.BankAccount // Account the deposit was kept in
+	IssuedAt    time.Time
+	Signature   *DepositSettlementSignature // Nil for the copy sent to the tenant to sign
+}
+
+// DepositSettlementSignature is the acceptance of a settlement by the tenant
+type DepositSettlementSignature struct {
+	SignedAt time.Time
+	Evidence *model.SigningEvidence
+}
+
+// DepositSettlementVerificationURL returns the public URL confirming the authenticity of a
+// signed deposit settlement, printed as a QR code on the PDF
+func DepositSettlementVerificationURL(depositID string) string {
+	baseURL := GetAPIBaseURL()
+	if baseURL == "" {
+		baseURL = GetAppBaseURL()
+	}
+	return fmt.Sprintf("%s/api/public/deposit-settlements/verify/%s", baseURL, depositID)
+}
+
+// DeductionCategoryLabel returns the printed label of a deduction category
+func DeductionCategoryLabel(category string) string {
+	if label, ok := model.DeductionCategoryLabels[category]; ok {
+		return label
+	}
+	return category
+}
+
+// GenerateDepositSettlementPDF creates the liquidación of the security deposit at move-out: the
+// amount received, each deduction with its reason and the balance refunded to the tenant. The
+// signed copy carries the electronic signature of the tenant and its evidence.
+func GenerateDepositSettlementPDF(data DepositSettlementPDF) ([]byte, error) {
+	if data.Rental == nil || data.Property == nil || data.Tenant == nil {
+		return nil, fmt.Errorf("incomplete deposit settlement data")
+	}
+	deposit := data.Deposit
+
+	propertyAddress := data.Property.Address
+	if data.Property.AptNumber != "" {
+		propertyAddress += " Apto " + data.Property.AptNumber
+	}
+	holderName := blankField
+	if data.Holder != nil {
+		holderName = data.Holder.FullName
+	}
+
+	pdf := newPDF()
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFontFamily, "B", 13)
+	pdf.MultiCell(0, 7, "LIQUIDACIÓN DEL DEPÓSITO EN GARANTÍA", "", "C", false)
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 6, fmt.Sprintf("%s, %s", data.Property.City, FormatDate(data.IssuedAt)), "", "C", false)
+	pdf.Ln(4)
+
+	receiptSection(pdf, "CONTRATO")
+	receiptField(pdf, "Arrendatario", fmt.Sprintf("%s (CC/NIT %s)", data.Tenant.FullName, data.Tenant.NIT))
+	receiptField(pdf, "Inmueble", fmt.Sprintf("%s, %s", propertyAddress, data.Property.City))
+	receiptField(pdf, "Vigencia", fmt.Sprintf("%s a %s", FormatDate(data.Rental.StartDate.Time()), FormatDate(data.Rental.EndDate.Time())))
+	if deposit.MoveOutDate != nil {
+		receiptField(pdf, "Entrega del inmueble", FormatDate(*deposit.MoveOutDate))
+	}
+	pdf.Ln(3)
+
+	receiptSection(pdf, "DEPÓSITO")
+	receiptField(pdf, "Recibido el", FormatDate(deposit.ReceivedAt))
+	receiptField(pdf, "En poder de", holderName)
+	if data.BankAccount != nil {
+		receiptField(pdf, "Cuenta", fmt.Sprintf("%s %s N° %s", data.BankAccount.BankName, data.BankAccount.AccountType, data.BankAccount.AccountNumber))
+	}
+	pdf.Ln(3)
+
+	receiptSection(pdf, "DESCUENTOS")
+	widths := []float64{45, 95, 30}
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	pdf.SetFillColor(220, 220, 220)
+	for i, header := range []string{"CONCEPTO", "MOTIVO", "VALOR"} {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(7)
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	if len(deposit.Deductions) == 0 {
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 6, "Sin descuentos", "1", 0, "C", false, 0, "")
+		pdf.Ln(6)
+	}
+	for _, deduction := range deposit.Deductions {
+		reason := deduction.Reason
+		if deduction.ReceiptPath != "" {
+			reason += " (con soporte)"
+		}
+		pdf.CellFormat(widths[0], 6, truncatePDFText(pdf, DeductionCategoryLabel(deduction.Category), widths[0]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, truncatePDFText(pdf, reason, widths[1]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 6, FormatMoney(deduction.Amount), "1", 0, "R", false, 0, "")
+		pdf.Ln(6)
+	}
+
+	labelWidth := widths[0] + widths[1]
+	totals := []struct{ label, value string }{
+		{"DEPÓSITO RECIBIDO", FormatMoney(deposit.Amount)},
+		{"TOTAL DESCUENTOS", FormatMoney(deposit.TotalDeductions())},
+		{"SALDO A REEMBOLSAR AL ARRENDATARIO", FormatMoney(deposit.RefundAmount())},
+	}
+	if owed := deposit.BalanceOwed(); owed > 0 {
+		totals = append(totals, struct{ label, value string }{"SALDO A CARGO DEL ARRENDATARIO", FormatMoney(owed)})
+	}
+	pdf.SetFont(pdfFontFamily, "B", 9)
+	for _, total := range totals {
+		pdf.CellFormat(labelWidth, 7, total.label, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, total.value, "1", 0, "R", false, 0, "")
+		pdf.Ln(7)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont(pdfFontFamily, "", 10)
+	closing := fmt.Sprintf("Con la firma de este documento el ARRENDATARIO acepta la presente liquidación del depósito entregado como garantía del contrato de arrendamiento. El saldo a su favor de %s le será reembolsado por %s.",
+		FormatMoney(deposit.RefundAmount()), holderName)
+	if owed := deposit.BalanceOwed(); owed > 0 {
+		closing = fmt.Sprintf("Con la firma de este documento el ARRENDATARIO acepta la presente liquidación del depósito entregado como garantía del contrato de arrendamiento y reconoce adeudar la suma de %s, que los descuentos exceden al depósito.",
+			FormatMoney(owed))
+	}
+	pdf.MultiCell(0, 5, closing, "", "J", false)
+
+	values := map[string]string{
+		"arrendatario":          strings.ToUpper(data.Tenant.FullName),
+		"arrendatario_cc":       data.Tenant.NIT,
+		"arrendatario_telefono": data.Tenant.Phone,
+		"arrendatario_email":    data.TenantEmail,
+	}
+	if data.Holder != nil {
+		values["arrendador"] = strings.ToUpper(data.Holder.FullName)
+		values["arrendador_cc"] = data.Holder.NIT
+		values["arrendador_telefono"] = data.Holder.Phone
+	}
+	addSignatureTables(pdf, []model.ContractSignatureBlock{
+		{Label: "ARRENDADOR", Party: "arrendador"},
+		{Label: "ARRENDATARIO", Party: "arrendatario"},
+	}, values)
+
+	if data.Signature == nil {
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	// Acceptance of the tenant, verifiable through the QR code, with its evidence on its own page
+	signingCert, err := ActiveSigningCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing certificate: %w", err)
+	}
+	stamp := &SignatureStamp{
+		SignerName:  data.Tenant.FullName,
+		SignerEmail: data.TenantEmail,
+		SignedAt:    data.Signature.SignedAt,
+		SigningID:   deposit.ID.String(),
+		VerifyURL:   DepositSettlementVerificationURL(deposit.ID.String()),
+	}
+	addSignatureStamp(pdf, stamp)
+	addSigningCertificatePage(pdf, stamp, data.Signature.Evidence, signingCert)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	signedBytes, err := SignPDF(buf.Bytes(), data.Tenant.FullName, SignPDFOptions{
+		SignatureReason:   "Liquidación del depósito en garantía",
+		SignatureLocation: "Digital Signature",
+		SignatureContact: fmt.Sprintf("DepositID: %s | SignedBy: %s | TimeSigned: %s%s",
+			deposit.ID, data.TenantEmail, stamp.SignedAt.Format(time.RFC3339), signingEvidenceContact(data.Signature.Evidence)),
+	})
+	if err != nil {
+		slog.Warn("error signing deposit settlement, proceeding with the unsigned PDF", "deposit_id", deposit.ID, "error", err)
+		return buf.Bytes(), nil
+	}
+
+	return signedBytes, nil
+}
+
+// SendDepositSettlementEmail sends the tenant the settlement of their deposit with the link to
+// review and sign it
+func SendDepositSettlementEmail(to, tenantName, propertyAddress string, refund float64, settlementPDF []byte) error {
+	subject := "Liquidación de su depósito en garantía"
+	body := fmt.Sprintf(`
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Liquidación del Depósito</title>
+		<style>
+			body { font-family: Arial, sans-serif; margin: 0; padding: 20px; color: #333; }
+			.container { max-width: 600px; margin: 0 auto; }
+			.header { background-color: #f8f9fa; padding: 20px; text-align: center; }
+			.content { padding: 20px; }
+			.button { display: inline-block; padding: 10px 20px; background-color: #228be6; color: #fff; text-decoration: none; border-radius: 4px; }
+			.footer { margin-top: 20px; font-size: 12px; color: #6c757d; }
+		</style>
+	</head>
+	<body>
+		<div class="container">
+			<div class="header">
+				<h2>Liquidación del Depósito en Garantía</h2>
+			</div>
+			<div class="content">
+				<p>Estimado(a) %s,</p>
+				<p>Adjunto encontrará la liquidación del depósito que entregó como garantía del arrendamiento del inmueble ubicado en %s, con los descuentos aplicados y su soporte.</p>
+				<p>Saldo a reembolsar: <strong>%s</strong></p>
+				<p>Revise la liquidación y fírmela en la plataforma para que podamos realizar el reembolso:</p>
+				<p><a class="button" href="%s">Revisar y firmar</a></p>
+				<p>Atentamente,<br>Sistema de Administración de Propiedades</p>
+			</div>
+			<div class="footer">
+				<p>Este es un mensaje automático. Por favor no responda directamente a este correo.</p>
+			</div>
+		</div>
+	</body>
+	</html>
+	`, tenantName, propertyAddress, FormatMoney(refund), GetAppBaseURL()+"/deposits")
+
+	tempFile, err := os.CreateTemp("", "liquidacion_deposito_*.pdf")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if _, err := tempFile.Write(settlementPDF); err != nil {
+		return fmt.Errorf("error writing to temporary file: %w", err)
+	}
+
+	return SendEmailWithAttachment(to, subject, body, tempFile.Name(), "liquidacion_deposito.pdf")
+}
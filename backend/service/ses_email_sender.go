@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// SESEmailSender sends emails through the Amazon SES v2 API. Messages are sent raw, so
+// attachments need no special handling, and requests are signed with AWS Signature Version 4.
+type SESEmailSender struct {
+	endpoint   string
+	from       string
+	signer     awsSigner
+	httpClient *http.Client
+}
+
+// NewSESEmailSender creates an SES driver with SES_ACCESS_KEY_ID, SES_SECRET_ACCESS_KEY and
+// SES_REGION (us-east-1 by default). EMAIL_FROM must be a verified identity. SES_ENDPOINT
+// overrides the regional endpoint.
+func NewSESEmailSender(cfg config.SESConfig, from string) (*SESEmailSender, error) {
+	accessKeyID := cfg.AccessKeyID
+	secretAccessKey := cfg.SecretAccessKey
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("SES_ACCESS_KEY_ID and SES_SECRET_ACCESS_KEY are required")
+	}
+	if from == "" {
+		return nil, errors.New("EMAIL_FROM is required by the ses driver")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", region)
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid SES_ENDPOINT: %w", err)
+	}
+
+	return &SESEmailSender{
+		endpoint: endpoint,
+		from:     emailFromAddress(),
+		signer: awsSigner{
+			accessKeyID:     accessKeyID,
+			secretAccessKey: secretAccessKey,
+			region:          region,
+			service:         "ses",
+		},
+		httpClient: &http.Client{Timeout: emailSendTimeout},
+	}, nil
+}
+
+// Name returns the driver name
+func (s *SESEmailSender) Name() string {
+	return config.EmailDriverSES
+}
+
+// Send delivers the message as a raw email with POST /v2/email/outbound-emails
+func (s *SESEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	raw, err := buildMIMEMessage(s.from, msg)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"FromEmailAddress": s.from,
+		"Destination":      map[string]interface{}{"ToAddresses": msg.To},
+		"Content":          map[string]interface{}{"Raw": map[string]interface{}{"Data": raw}},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode ses request: %w", err)
+	}
+	req, err := s.newRequest(ctx, http.MethodPost, "/v2/email/outbound-emails", body)
+	if err != nil {
+		return err
+	}
+	_, err = doEmailProviderRequest(s.httpClient, req, s.Name())
+	return err
+}
+
+// HealthCheck reads the account with GET /v2/email/account and fails when sending is paused
+func (s *SESEmailSender) HealthCheck(ctx context.Context) error {
+	req, err := s.newRequest(ctx, http.MethodGet, "/v2/email/account", nil)
+	if err != nil {
+		return err
+	}
+	body, err := doEmailProviderRequest(s.httpClient, req, s.Name())
+	if err != nil {
+		return err
+	}
+
+	var account struct {
+		SendingEnabled bool `json:"SendingEnabled"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return fmt.Errorf("failed to parse ses account: %w", err)
+	}
+	if !account.SendingEnabled {
+		return errors.New("sending is disabled for this ses account")
+	}
+	return nil
+}
+
+func (s *SESEmailSender) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ses request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.signer.sign(req, body, time.Now().UTC())
+	return req, nil
+}
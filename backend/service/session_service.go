@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/auth"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// sessionCheckInterval is how long the result of checking a session is reused, so a
+	// revocation reaches the other instances within it
+	sessionCheckInterval = 30 * time.Second
+	// sessionTouchInterval is how often the last use of a session is written
+	sessionTouchInterval = 5 * time.Minute
+	// sessionPruneInterval is how often the expired sessions are deleted
+	sessionPruneInterval = time.Hour
+)
+
+// ErrSessionNotFound is returned when revoking a session that is not an active session of the user
+var ErrSessionNotFound = errors.New("session not found")
+
+type sessionCheck struct {
+	active    bool
+	checkedAt time.Time
+}
+
+// SessionService records the JWTs issued at login as sessions, which their users can list and
+// revoke, and rejects the tokens of revoked sessions
+type SessionService struct {
+	repo      storage.UserSessionStore
+	jwtSecret []byte
+	failOpen  bool
+
+	mu        sync.Mutex
+	checks    map[uuid.UUID]sessionCheck
+	lastPrune time.Time
+}
+
+// NewSessionService creates a SessionService signing the tokens with the JWT_SECRET of cfg, and
+// accepting them when the sessions cannot be read only with SESSION_FAIL_OPEN
+func NewSessionService(repo storage.UserSessionStore, cfg config.AuthConfig) *SessionService {
+	return &SessionService{
+		repo:      repo,
+		jwtSecret: []byte(cfg.JWTSecret),
+		failOpen:  cfg.SessionFailOpen,
+		checks:    make(map[uuid.UUID]sessionCheck),
+	}
+}
+
+// Start records a new session of user and returns it with its token
+func (s *SessionService) Start(ctx context.Context, user *model.User, ip, userAgent string, now time.Time) (*model.UserSession, string, error) {
+	session, err := s.repo.Create(ctx, model.UserSession{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		IPAddress:  ip,
+		UserAgent:  userAgent,
+		Device:     DescribeDevice(userAgent),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(auth.TokenTTL),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := auth.GenerateToken(s.jwtSecret, user, session.ID.String(), session.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.pruneExpired(ctx, now)
+	return session, token, nil
+}
+
+// StartImpersonation records a session of user opened by the admin impersonator and returns it
+// with its token. The session lasts ttl and is listed to the user like their own.
+func (s *SessionService) StartImpersonation(ctx context.Context, user *model.User, impersonator uuid.UUID, ip, userAgent string, ttl time.Duration, now time.Time) (*model.UserSession, string, error) {
+	session, err := s.repo.Create(ctx, model.UserSession{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		Device:         DescribeDevice(userAgent),
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(ttl),
+		ImpersonatorID: &impersonator,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := auth.GenerateImpersonationToken(s.jwtSecret, user, impersonator, session.ID.String(), session.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// ValidateToken validates a token issued by Start or StartImpersonation and returns its claims
+func (s *SessionService) ValidateToken(tokenString string) (*auth.CustomClaims, error) {
+	return auth.ValidateToken(s.jwtSecret, tokenString)
+}
+
+// IsSessionActive reports whether the session of a token can still be used. Every token is
+// issued with its session, so tokens without one are rejected. When the sessions cannot be read
+// the tokens are rejected too, unless SESSION_FAIL_OPEN is set.
+func (s *SessionService) IsSessionActive(ctx context.Context, sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	check, ok := s.checks[id]
+	s.mu.Unlock()
+	if ok && now.Sub(check.checkedAt) < sessionCheckInterval {
+		return check.active
+	}
+
+	session, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if s.failOpen {
+			logging.FromContext(ctx).Warn("could not read session, token accepted (SESSION_FAIL_OPEN)", "component", "session", "id", id, "error", err)
+			return true
+		}
+		logging.FromContext(ctx).Error("could not read session, token rejected", "component", "session", "id", id, "error", err)
+		return false
+	}
+	active := session != nil && session.Active(now)
+	if active && now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.repo.Touch(ctx, id, now); err != nil {
+			logging.FromContext(ctx).Warn("could not record the use of session", "component", "session", "id", id, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.checks[id] = sessionCheck{active: active, checkedAt: now}
+	s.mu.Unlock()
+	return active
+}
+
+// List returns the active sessions of a user, most recently used first
+func (s *SessionService) List(ctx context.Context, userID uuid.UUID) ([]model.UserSession, error) {
+	return s.repo.GetActiveByUser(ctx, userID, time.Now())
+}
+
+// Revoke revokes a session of a user, whose token is rejected from then on
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	now := time.Now()
+	revoked, err := s.repo.Revoke(ctx, sessionID, userID, now)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	s.mu.Lock()
+	s.checks[sessionID] = sessionCheck{active: false, checkedAt: now}
+	s.mu.Unlock()
+	logging.FromContext(ctx).Info("session of user revoked", "component", "session", "session_id", sessionID, "user_id", userID)
+	return nil
+}
+
+// RevokeAll revokes every active session of a user, as after a password reset, and returns how
+// many were revoked
+func (s *SessionService) RevokeAll(ctx context.Context, userID uuid.UUID) (int, error) {
+	now := time.Now()
+	sessions, err := s.repo.GetActiveByUser(ctx, userID, now)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		ok, err := s.repo.Revoke(ctx, session.ID, userID, now)
+		if err != nil {
+			return revoked, err
+		}
+		s.mu.Lock()
+		s.checks[session.ID] = sessionCheck{active: false, checkedAt: now}
+		s.mu.Unlock()
+		if ok {
+			revoked++
+		}
+	}
+	if revoked > 0 {
+		logging.FromContext(ctx).Info("sessions of user revoked", "component", "session", "user_id", userID, "count", revoked)
+	}
+	return revoked, nil
+}
+
+// pruneExpired deletes the expired sessions and forgets their checks, once per
+// sessionPruneInterval
+func (s *SessionService) pruneExpired(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < sessionPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	for id, check := range s.checks {
+		if now.Sub(check.checkedAt) >= sessionCheckInterval {
+			delete(s.checks, id)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.repo.DeleteExpiredBefore(ctx, now); err != nil {
+		logging.FromContext(ctx).Warn("could not delete the expired sessions", "component", "session", "error", err)
+	}
+}
+
+// DescribeDevice returns the browser and operating system of a user agent, such as
+// "Chrome en Windows", for the list of sessions
+func DescribeDevice(userAgent string) string {
+	if userAgent == "" {
+		return ""
+	}
+
+	browser := "Navegador desconocido"
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"), strings.Contains(userAgent, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	system := ""
+	switch {
+	case strings.Contains(userAgent, "Android"):
+		system = "Android"
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		system = "iOS"
+	case strings.Contains(userAgent, "Windows"):
+		system = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		system = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		system = "Linux"
+	}
+
+	if system == "" {
+		return browser
+	}
+	return browser + " en " + system
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+)
+
+// shutdownHook stops a background service when the server shuts down
+type shutdownHook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+var (
+	shutdownMu    sync.Mutex
+	shutdownHooks []shutdownHook
+
+	// backgroundTasks tracks the work started by requests that outlives them, such as the
+	// webhook deliveries and the automatic invoices, so shutdown waits for it
+	backgroundTasks sync.WaitGroup
+)
+
+// OnShutdown registers a hook that Shutdown runs once the HTTP server stopped taking requests
+func OnShutdown(name string, stop func(ctx context.Context) error) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	shutdownHooks = append(shutdownHooks, shutdownHook{name: name, stop: stop})
+}
+
+// stopCronOnShutdown stops a scheduler on shutdown, waiting for the jobs it is running
+func stopCronOnShutdown(name string, c *cron.Cron) {
+	OnShutdown(name, func(ctx context.Context) error {
+		select {
+		case <-c.Stop().Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// runInBackground runs task in a goroutine that Shutdown waits for
+func runInBackground(task func()) {
+	backgroundTasks.Add(1)
+	go func() {
+		defer backgroundTasks.Done()
+		task()
+	}()
+}
+
+// Shutdown stops the schedulers in the reverse order they started and waits for the
+// background tasks, until ctx is done. Jobs still running when ctx ends are abandoned.
+func Shutdown(ctx context.Context) error {
+	shutdownMu.Lock()
+	hooks := shutdownHooks
+	shutdownHooks = nil
+	shutdownMu.Unlock()
+
+	var firstErr error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].stop(ctx); err != nil {
+			logging.FromContext(ctx).Warn("did not stop cleanly", "component", "shutdown", "name", hooks[i].name, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		backgroundTasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logging.FromContext(ctx).Warn("background tasks still running after the drain timeout", "component", "shutdown")
+		if firstErr == nil {
+			firstErr = ctx.Err()
+		}
+	}
+	return firstErr
+}
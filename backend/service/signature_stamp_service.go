@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// SignatureStamp is the visible signature block printed at the end of a signed contract. Its QR
+// code links to the public verification endpoint of the signing.
+type SignatureStamp struct {
+	SignerName  string
+	SignerEmail string
+	SignedAt    time.Time
+	SigningID   string
+	VerifyURL   string
+}
+
+// NewSignatureStamp returns the stamp of a signing completed now
+func NewSignatureStamp(signerName, signerEmail, signingID string) *SignatureStamp {
+	return &SignatureStamp{
+		SignerName:  signerName,
+		SignerEmail: signerEmail,
+		SignedAt:    time.Now(),
+		SigningID:   signingID,
+		VerifyURL:   SignatureVerificationURL(signingID),
+	}
+}
+
+// SignatureVerificationURL returns the public URL confirming the authenticity of a signing. It
+// points to this backend (API_BASE_URL), or to APP_BASE_URL when the API is served behind it.
+func SignatureVerificationURL(signingID string) string {
+	baseURL := GetAPIBaseURL()
+	if baseURL == "" {
+		baseURL = GetAppBaseURL()
+	}
+	return fmt.Sprintf("%s/api/public/contract-signing/verify/%s", baseURL, signingID)
+}
+
+// addSignatureStamp draws the signature stamp at the end of the document, on a new page when
+// it does not fit on the last one
+func addSignatureStamp(pdf *gofpdf.Fpdf, stamp *SignatureStamp) {
+	const (
+		boxHeight = 45.0
+		qrSide    = 41.0
+	)
+
+	left, _, right, bottomMargin := pdf.GetMargins()
+	pageWidth, pageHeight := pdf.GetPageSize()
+	boxWidth := pageWidth - left - right
+
+	pdf.Ln(8)
+	if pdf.GetY()+boxHeight > pageHeight-bottomMargin {
+		pdf.AddPage()
+	}
+	top := pdf.GetY()
+
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.4)
+	pdf.Rect(left, top, boxWidth, boxHeight, "D")
+	pdf.SetLineWidth(0.2)
+
+	if err := drawQRCode(pdf, stamp.VerifyURL, left+boxWidth-qrSide-2, top+2, qrSide); err != nil {
+		slog.Warn("could not draw the verification QR code of signing", "signing_id", stamp.SigningID, "error", err)
+	}
+
+	textWidth := boxWidth - qrSide - 10
+	pdf.SetXY(left+4, top+4)
+	pdf.SetFont(pdfFontFamily, "B", 11)
+	pdf.MultiCell(textWidth, 6, "FIRMADO ELECTRÓNICAMENTE", "", "L", false)
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	for _, line := range []string{
+		"Firmante: " + stamp.SignerName,
+		"Fecha y hora: " + FormatDateTime(stamp.SignedAt),
+		"ID de firma: " + stamp.SigningID,
+	} {
+		pdf.SetX(left + 4)
+		pdf.MultiCell(textWidth, 5, line, "", "L", false)
+	}
+
+	pdf.Ln(1)
+	pdf.SetFont(pdfFontFamily, "I", 7)
+	pdf.SetX(left + 4)
+	pdf.MultiCell(textWidth, 4, "Escanee el código QR o visite "+stamp.VerifyURL+" para verificar la autenticidad de esta firma.", "", "L", false)
+
+	pdf.SetY(top + boxHeight)
+}
+
+// addSigningCertificatePage appends the page recording the evidence of the signature: who
+// signed, when, from which IP address, browser and location, and with which certificate
+func addSigningCertificatePage(pdf *gofpdf.Fpdf, stamp *SignatureStamp, evidence *model.SigningEvidence, cert *SigningCertificate) {
+	const labelWidth = 50.0
+
+	pdf.AddPage()
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	valueWidth := pageWidth - left - right - labelWidth
+
+	pdf.SetFont(pdfFontFamily, "B", 14)
+	pdf.MultiCell(0, 8, "CERTIFICADO DE FIRMA ELECTRÓNICA", "", "C", false)
+	pdf.Ln(3)
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.MultiCell(0, 5, "Este certificado hace parte integral del documento y registra la evidencia de su firma electrónica.", "", "L", false)
+	pdf.Ln(5)
+
+	if evidence == nil {
+		evidence = &model.SigningEvidence{}
+	}
+	location := evidence.Location()
+	if location == "" {
+		location = "No compartida por el firmante"
+	}
+
+	rows := [][2]string{
+		{"ID de firma", stamp.SigningID},
+		{"Firmante", stamp.SignerName},
+		{"Correo electrónico", stamp.SignerEmail},
+		{"Fecha y hora", FormatDateTime(stamp.SignedAt)},
+		{"Dirección IP", valueOrUnknown(evidence.IPAddress)},
+		{"Navegador", valueOrUnknown(evidence.UserAgent)},
+		{"Geolocalización", location},
+	}
+	if cert != nil {
+		subject := cert.Certificate.Subject.CommonName
+		if subject == "" {
+			subject = cert.Certificate.Subject.String()
+		}
+		rows = append(rows,
+			[2]string{"Certificado de firma", subject},
+			[2]string{"Número de serie", fmt.Sprintf("%X", cert.Certificate.SerialNumber)},
+		)
+	}
+	rows = append(rows, [2]string{"Verificación", stamp.VerifyURL})
+
+	for _, row := range rows {
+		pdf.SetX(left)
+		pdf.SetFont(pdfFontFamily, "B", 9)
+		pdf.CellFormat(labelWidth, 6, row[0]+":", "", 0, "L", false, 0, "")
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.MultiCell(valueWidth, 6, row[1], "", "L", false)
+	}
+}
+
+// valueOrUnknown prints "No registrado" for evidence that was not captured
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "No registrado"
+	}
+	return value
+}
+
+// signingEvidenceContact returns the evidence appended to the contact info of a signature
+// (" | IP: <ip> | UserAgent: <ua> | Geo: <lat,lon>")
+func signingEvidenceContact(evidence *model.SigningEvidence) string {
+	if evidence == nil {
+		return ""
+	}
+
+	// The contact info fields are separated by "|"
+	clean := func(value string) string {
+		return strings.TrimSpace(strings.ReplaceAll(value, "|", "/"))
+	}
+
+	var contact string
+	if evidence.IPAddress != "" {
+		contact += " | IP: " + clean(evidence.IPAddress)
+	}
+	if evidence.UserAgent != "" {
+		contact += " | UserAgent: " + clean(evidence.UserAgent)
+	}
+	if location := evidence.Location(); location != "" {
+		contact += " | Geo: " + location
+	}
+	return contact
+}
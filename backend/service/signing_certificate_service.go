@@ -0,0 +1,416 @@
+package service
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// Sources of the certificate contracts are signed with
+const (
+	SigningCertificateSourceEnv        = "env"         // SIGNING_CERT_P12_PATH or SIGNING_CERT_P12_BASE64
+	SigningCertificateSourceUploaded   = "uploaded"    // Uploaded by an admin, stored in ./certs
+	SigningCertificateSourceSelfSigned = "self-signed" // Generated in ./certs for development
+)
+
+// Key algorithms of the signing certificate
+const (
+	SigningKeyECDSA = "ECDSA"
+	SigningKeyRSA   = "RSA"
+)
+
+// Files of the certificate uploaded through the admin endpoint
+const (
+	uploadedSigningCertFile     = "signing.p12"
+	uploadedSigningPasswordFile = "signing.p12.password"
+)
+
+// ErrSigningCertificateFromEnv is returned when an admin tries to replace a certificate that
+// the operator configured through the environment
+var ErrSigningCertificateFromEnv = errors.New("the signing certificate is configured through the environment")
+
+// SigningCertificate is the certificate and key contracts are signed with
+type SigningCertificate struct {
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate // Intermediate certificates included in the PKCS#12 file
+	Signer      crypto.Signer
+	Source      string
+}
+
+// SigningCertificateInfo describes the active signing certificate, without its key
+type SigningCertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	KeyAlgorithm string    `json:"key_algorithm"`
+	Source       string    `json:"source"`
+	SelfSigned   bool      `json:"self_signed"`
+	ChainLength  int       `json:"chain_length"`
+	ExpiresIn    int       `json:"expires_in_days"`
+}
+
+var (
+	signingCertMu sync.Mutex
+	// signingCertConfig holds the certificate configured by the operator, set by
+	// InitializeSigningCertificate
+	signingCertConfig config.SigningConfig
+	activeSigningCert *SigningCertificate
+	// activeSigningCertModTime is the modification time of the file the active certificate was
+	// read from, used to detect a rotated file
+	activeSigningCertModTime time.Time
+)
+
+// KeyAlgorithm returns the algorithm of the signing key, ECDSA or RSA
+func (s *SigningCertificate) KeyAlgorithm() string {
+	if _, ok := s.Signer.(*rsa.PrivateKey); ok {
+		return SigningKeyRSA
+	}
+	return SigningKeyECDSA
+}
+
+// Info returns the public details of the signing certificate
+func (s *SigningCertificate) Info() SigningCertificateInfo {
+	cert := s.Certificate
+	return SigningCertificateInfo{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: fmt.Sprintf("%X", cert.SerialNumber),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		KeyAlgorithm: s.KeyAlgorithm(),
+		Source:       s.Source,
+		SelfSigned:   bytes.Equal(cert.RawIssuer, cert.RawSubject),
+		ChainLength:  len(s.Chain),
+		ExpiresIn:    s.DaysUntilExpiry(time.Now()),
+	}
+}
+
+// DaysUntilExpiry returns the whole days left until the certificate expires, negative once expired
+func (s *SigningCertificate) DaysUntilExpiry(now time.Time) int {
+	return int(math.Floor(s.Certificate.NotAfter.Sub(now).Hours() / 24))
+}
+
+// InitializeSigningCertificate sets the certificate configured by the operator, read on the
+// next use of the signing certificate
+func InitializeSigningCertificate(cfg config.SigningConfig) {
+	signingCertMu.Lock()
+	defer signingCertMu.Unlock()
+	signingCertConfig = cfg
+	activeSigningCert, activeSigningCertModTime = nil, time.Time{}
+}
+
+// ActiveSigningCertificate returns the certificate contracts are signed with. A PKCS#12 file
+// configured in the environment (SIGNING_CERT_P12_PATH or SIGNING_CERT_P12_BASE64, with
+// SIGNING_CERT_PASSWORD) has priority, then the one uploaded by an admin; without either a
+// self-signed ECDSA certificate is loaded or generated in ./certs.
+func ActiveSigningCertificate() (*SigningCertificate, error) {
+	signingCertMu.Lock()
+	defer signingCertMu.Unlock()
+
+	if activeSigningCert != nil {
+		return activeSigningCert, nil
+	}
+
+	modTime := signingCertificateModTime()
+	cert, err := loadSigningCertificate()
+	if err != nil {
+		return nil, err
+	}
+	activeSigningCert, activeSigningCertModTime = cert, modTime
+	slog.Info("signing certificate loaded", "common_name", cert.Certificate.Subject.CommonName, "key_algorithm", cert.KeyAlgorithm(), "source", cert.Source, "not_after", cert.Certificate.NotAfter.Format("2006-01-02"))
+	return cert, nil
+}
+
+// ReloadSigningCertificate reads the signing certificate again so a rotated certificate is used
+// without restarting the server. The current certificate is kept when the new one is invalid.
+func ReloadSigningCertificate() (*SigningCertificate, error) {
+	signingCertMu.Lock()
+	defer signingCertMu.Unlock()
+
+	modTime := signingCertificateModTime()
+	cert, err := loadSigningCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	previous := activeSigningCert
+	activeSigningCert, activeSigningCertModTime = cert, modTime
+	if previous == nil || !previous.Certificate.Equal(cert.Certificate) {
+		slog.Info("signing certificate rotated", "common_name", cert.Certificate.Subject.CommonName, "key_algorithm", cert.KeyAlgorithm(), "source", cert.Source, "not_after", cert.Certificate.NotAfter.Format("2006-01-02"))
+	}
+	return cert, nil
+}
+
+// ReloadSigningCertificateIfChanged reloads the signing certificate when the file it was read
+// from was replaced, reporting whether it was reloaded
+func ReloadSigningCertificateIfChanged() (bool, error) {
+	signingCertMu.Lock()
+	changed := activeSigningCert != nil && !signingCertificateModTime().Equal(activeSigningCertModTime)
+	signingCertMu.Unlock()
+
+	if !changed {
+		return false, nil
+	}
+	if _, err := ReloadSigningCertificate(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// InstallSigningCertificate validates a PKCS#12 (.p12/.pfx) file and stores it in ./certs as
+// the signing certificate, replacing the self-signed one
+func InstallSigningCertificate(p12Data []byte, password string) (*SigningCertificate, error) {
+	if signingCertificateFromEnv() {
+		return nil, ErrSigningCertificateFromEnv
+	}
+
+	cert, err := ParseSigningCertificate(p12Data, password)
+	if err != nil {
+		return nil, err
+	}
+	cert.Source = SigningCertificateSourceUploaded
+
+	if err := os.MkdirAll(platformCertsDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create certificates directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(platformCertsDir, uploadedSigningCertFile), p12Data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to save signing certificate: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(platformCertsDir, uploadedSigningPasswordFile), []byte(password), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save signing certificate password: %w", err)
+	}
+
+	signingCertMu.Lock()
+	activeSigningCert, activeSigningCertModTime = cert, signingCertificateModTime()
+	signingCertMu.Unlock()
+
+	slog.Info("nuevo certificado de firma instalado", "common_name", cert.Certificate.Subject.CommonName, "key_algorithm", cert.KeyAlgorithm())
+	return cert, nil
+}
+
+// RemoveSigningCertificate deletes the uploaded certificate, contracts are signed again with the
+// self-signed certificate
+func RemoveSigningCertificate() error {
+	if signingCertificateFromEnv() {
+		return ErrSigningCertificateFromEnv
+	}
+
+	for _, name := range []string{uploadedSigningCertFile, uploadedSigningPasswordFile} {
+		if err := os.Remove(filepath.Join(platformCertsDir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+
+	signingCertMu.Lock()
+	activeSigningCert = nil
+	signingCertMu.Unlock()
+	return nil
+}
+
+// ParseSigningCertificate decodes a PKCS#12 file and selects the signer from its key, ECDSA
+// or RSA. The certificate must be valid now and allow digital signatures. Files exported by
+// OpenSSL 3 must use the -legacy flag (3DES), AES encrypted files are not supported.
+func ParseSigningCertificate(p12Data []byte, password string) (*SigningCertificate, error) {
+	blocks, err := pkcs12.ToPEM(p12Data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PKCS#12 file (wrong password or unsupported encryption): %w", err)
+	}
+
+	var signer crypto.Signer
+	var certs []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			certs = append(certs, cert)
+		default:
+			key, err := parseSigningKey(block)
+			if err != nil {
+				return nil, err
+			}
+			signer = key
+		}
+	}
+	if signer == nil {
+		return nil, errors.New("the PKCS#12 file does not contain a private key")
+	}
+
+	// The leaf is the certificate of the private key, the rest is its chain
+	var leaf *x509.Certificate
+	var chain []*x509.Certificate
+	for _, cert := range certs {
+		if leaf == nil && publicKeyMatches(cert.PublicKey, signer.Public()) {
+			leaf = cert
+			continue
+		}
+		chain = append(chain, cert)
+	}
+	if leaf == nil {
+		return nil, errors.New("the PKCS#12 file does not contain the certificate of its private key")
+	}
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) {
+		return nil, fmt.Errorf("the certificate is not valid until %s", leaf.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return nil, fmt.Errorf("the certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	}
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&(x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment) == 0 {
+		return nil, errors.New("the certificate does not allow digital signatures")
+	}
+
+	return &SigningCertificate{Certificate: leaf, Chain: chain, Signer: signer}, nil
+}
+
+// SigningCertificateChains returns the chains of the signing certificate to embed in signatures,
+// built with its PKCS#12 intermediates. The certificate alone when it does not chain to a root.
+func SigningCertificateChains(cert *SigningCertificate) [][]*x509.Certificate {
+	intermediates := x509.NewCertPool()
+	for _, c := range cert.Chain {
+		intermediates.AddCert(c)
+	}
+	chains, err := cert.Certificate.Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		if cert.Source != SigningCertificateSourceSelfSigned {
+			slog.Warn("signing certificate verification error", "error", err)
+		}
+		return [][]*x509.Certificate{append([]*x509.Certificate{cert.Certificate}, cert.Chain...)}
+	}
+	return chains
+}
+
+// loadSigningCertificate reads the signing certificate configured by the operator, the uploaded file
+// or the self-signed certificate, in that order
+func loadSigningCertificate() (*SigningCertificate, error) {
+	if signingCertificateFromEnv() {
+		p12Data, err := signingCertificateEnvData()
+		if err != nil {
+			return nil, err
+		}
+		cert, err := ParseSigningCertificate(p12Data, signingCertConfig.CertPassword)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing certificate in the environment: %w", err)
+		}
+		cert.Source = SigningCertificateSourceEnv
+		return cert, nil
+	}
+
+	uploadedPath := filepath.Join(platformCertsDir, uploadedSigningCertFile)
+	if p12Data, err := os.ReadFile(uploadedPath); err == nil {
+		password, err := os.ReadFile(filepath.Join(platformCertsDir, uploadedSigningPasswordFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read signing certificate password: %w", err)
+		}
+		cert, err := ParseSigningCertificate(p12Data, string(password))
+		if err == nil {
+			cert.Source = SigningCertificateSourceUploaded
+			return cert, nil
+		}
+		slog.Warn("el certificado de firma subido no es válido, se usa el autofirmado", "error", err)
+	}
+
+	privateKey, cert, err := LoadOrGenerateECDSACertificate(platformCertsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or generate certificate: %w", err)
+	}
+	return &SigningCertificate{Certificate: cert, Signer: privateKey, Source: SigningCertificateSourceSelfSigned}, nil
+}
+
+// signingCertificateFromEnv reports whether the operator configured the certificate in the
+// environment (SIGNING_CERT_P12_PATH or SIGNING_CERT_P12_BASE64)
+func signingCertificateFromEnv() bool {
+	return signingCertConfig.CertP12Path != "" || signingCertConfig.CertP12Base64 != ""
+}
+
+// signingCertificateModTime returns the modification time of the file the signing certificate
+// is read from, zero when it is not read from a file
+func signingCertificateModTime() time.Time {
+	path := signingCertConfig.CertP12Path
+	if !signingCertificateFromEnv() {
+		path = filepath.Join(platformCertsDir, uploadedSigningCertFile)
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(platformCertsDir, "ecdsa_certificate.crt")
+		}
+	}
+	if path == "" {
+		return time.Time{}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// signingCertificateEnvData reads the PKCS#12 file configured in the environment
+func signingCertificateEnvData() ([]byte, error) {
+	if path := signingCertConfig.CertP12Path; path != "" {
+		p12Data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SIGNING_CERT_P12_PATH: %w", err)
+		}
+		return p12Data, nil
+	}
+
+	encoded := strings.Join(strings.Fields(signingCertConfig.CertP12Base64), "")
+	p12Data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode SIGNING_CERT_P12_BASE64: %w", err)
+	}
+	return p12Data, nil
+}
+
+// parseSigningKey parses the private key of a PKCS#12 file, supporting ECDSA and RSA keys
+func parseSigningKey(block *pem.Block) (crypto.Signer, error) {
+	var key interface{}
+	var err error
+	if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+			if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("failed to parse private key: %w", err)
+			}
+		}
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T, only ECDSA and RSA keys can sign", key)
+	}
+}
+
+// publicKeyMatches reports whether a certificate public key belongs to the signing key
+func publicKeyMatches(certKey, signerKey crypto.PublicKey) bool {
+	key, ok := certKey.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(signerKey)
+}
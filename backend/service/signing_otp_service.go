@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/i18n"
+	"github.com/nescool101/rentManager/model"
+)
+
+const (
+	// SigningOTPTTL is how long a one-time signing code is valid
+	SigningOTPTTL = 10 * time.Minute
+	// SigningOTPMaxAttempts is how many wrong codes are accepted before a new one must be requested
+	SigningOTPMaxAttempts = 5
+	// SigningOTPResendInterval is the minimum time between two codes of the same signing request
+	SigningOTPResendInterval = time.Minute
+)
+
+// Channels a one-time signing code is sent through
+const (
+	OTPChannelEmail = "email"
+	OTPChannelSMS   = "sms"
+)
+
+// GenerateSigningOTP returns a random 6-digit signing code
+func GenerateSigningOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signing code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// HashSigningOTP returns the HMAC stored for the code of a signing request, keyed with secret
+// (SIGNING_OTP_SECRET), so a leaked record does not reveal the code
+func HashSigningOTP(secret []byte, signingID, code string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingID + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckSigningOTP reports whether code matches the stored hash of a signing request
+func CheckSigningOTP(secret []byte, signingID, code, otpHash string) bool {
+	if code == "" || otpHash == "" {
+		return false
+	}
+	return hmac.Equal([]byte(HashSigningOTP(secret, signingID, code)), []byte(otpHash))
+}
+
+// SendSigningOTPEmail emails the code the recipient must enter to sign a contract, in locale
+// ("" for the locale of org) and with the branding of org when it is not nil
+func SendSigningOTPEmail(to, code string, org *model.Organization, locale string) error {
+	subject, body, err := renderEmail(EmailTemplateSigningOTP, org, locale, SigningOTPEmailData{
+		Codigo:          code,
+		MinutosVigencia: int(SigningOTPTTL.Minutes()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return SendSimpleEmail(to, subject, body)
+}
+
+// SendSigningOTPSMS sends the code the recipient must enter to sign a contract by SMS, in
+// locale ("" for the locale of org)
+func SendSigningOTPSMS(to, code string, org *model.Organization, locale string) error {
+	message := i18n.T(EmailLocale(locale, org), "SigningOTPSMS", map[string]interface{}{
+		"Code":    code,
+		"Minutes": int(SigningOTPTTL.Minutes()),
+	})
+	return SendSMS(to, message)
+}
+
+// smsConfig holds the gateway InitializeSMS was called with, none until then
+var smsConfig config.SMSConfig
+
+// InitializeSMS sets the gateway the signing codes and notifications are sent by SMS through
+func InitializeSMS(cfg config.SMSConfig) {
+	smsConfig = cfg
+}
+
+// SMSEnabled reports whether an SMS gateway is configured (SMS_GATEWAY_URL)
+func SMSEnabled() bool {
+	return smsConfig.GatewayURL != ""
+}
+
+// SendSMS posts {"to", "message"} to the SMS gateway at SMS_GATEWAY_URL, authenticated with
+// SMS_GATEWAY_TOKEN as bearer token when set
+func SendSMS(to, message string) error {
+	gatewayURL := smsConfig.GatewayURL
+	if gatewayURL == "" {
+		return errors.New("SMS_GATEWAY_URL is not configured")
+	}
+
+	payload, err := json.Marshal(map[string]string{"to": to, "message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, gatewayURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := smsConfig.GatewayToken; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms gateway request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sms gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
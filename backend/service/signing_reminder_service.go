@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultSigningReminderSchedule runs the reminder and expiry job every hour when
+	// SIGNING_REMINDER_SCHEDULE is not set, so expired requests are marked promptly
+	defaultSigningReminderSchedule = "0 * * * *"
+)
+
+// defaultSigningReminderDays are the days before expiry the recipient is reminded at when
+// SIGNING_REMINDER_DAYS is not set
+var defaultSigningReminderDays = []int{3, 1}
+
+// SigningReminderService reminds recipients of pending signing requests before they expire,
+// marks the expired ones and notifies whoever requested them
+type SigningReminderService struct {
+	signingRepo storage.ContractSigningStore
+	eventRepo   storage.ContractSigningEventStore
+	personRepo  storage.PersonStore
+	userRepo    storage.UserStore
+	orgService  *OrganizationService
+	webhooks    *SigningWebhookDispatcher
+	// reminderDays are the days before expiry reminders are sent at, largest first
+	reminderDays []int
+	schedule     string
+}
+
+// SigningReminderResult summarizes a run of the job
+type SigningReminderResult struct {
+	RemindersSent int `json:"reminders_sent"`
+	Expired       int `json:"expired"`
+}
+
+// NewSigningReminderService creates a SigningReminderService sending the reminders reminderDays
+// before expiry (SIGNING_REMINDER_DAYS), on schedule (SIGNING_REMINDER_SCHEDULE)
+func NewSigningReminderService(repoFactory *storage.RepositoryFactory, orgService *OrganizationService, webhooks *SigningWebhookDispatcher, reminderDays []int, schedule string) *SigningReminderService {
+	return &SigningReminderService{
+		signingRepo:  repoFactory.GetContractSigningRepository(),
+		eventRepo:    repoFactory.GetContractSigningEventRepository(),
+		personRepo:   repoFactory.GetPersonRepository(),
+		userRepo:     repoFactory.GetUserRepository(),
+		orgService:   orgService,
+		webhooks:     webhooks,
+		reminderDays: SigningReminderDays(reminderDays),
+		schedule:     orDefault(schedule, defaultSigningReminderSchedule),
+	}
+}
+
+// SigningReminderDays returns the configured days before expiry reminders are sent at, largest
+// first ("3,1" when none are configured)
+func SigningReminderDays(days []int) []int {
+	if len(days) == 0 {
+		days = defaultSigningReminderDays
+	}
+
+	days = slices.Clone(days)
+	sort.Sort(sort.Reverse(sort.IntSlice(days)))
+	return days
+}
+
+// DueSigningReminder returns the reminder interval (in days before expiry) reached by a request
+// that has not been reminded for it yet, 0 when no reminder is due. Intervals longer than the
+// request's validity are skipped, the signing email was sent within them.
+func DueSigningReminder(createdAt, expiresAt, now time.Time, lastReminderDays *int, reminderDays []int) int {
+	due := 0
+	for _, days := range reminderDays {
+		reminderAt := expiresAt.Add(-time.Duration(days) * 24 * time.Hour)
+		if now.Before(reminderAt) || !createdAt.Before(reminderAt) {
+			continue
+		}
+		if due == 0 || days < due {
+			due = days
+		}
+	}
+	if due == 0 || (lastReminderDays != nil && *lastReminderDays <= due) {
+		return 0
+	}
+	return due
+}
+
+// Run sends the due reminders and expires the requests past their expiry date
+func (s *SigningReminderService) Run(ctx context.Context, now time.Time) (*SigningReminderResult, error) {
+	result := &SigningReminderResult{}
+
+	expired, err := s.signingRepo.ExpirePendingRequests(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to expire signing requests: %w", err)
+	}
+	for _, record := range expired {
+		result.Expired++
+		s.recordEvent(ctx, record.ID, storage.SigningEventExpired, "")
+		s.webhooks.Dispatch(model.WebhookEventSigningExpired, record.ID)
+		if err := s.notifyRequester(ctx, record); err != nil {
+			logging.FromContext(ctx).Error("error notifying expiry of signing request", "component", "signing", "record_id", record.ID, "error", err)
+		}
+	}
+
+	pending, err := s.signingRepo.GetPendingRequests(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to get pending signing requests: %w", err)
+	}
+
+	reminderDays := s.reminderDays
+	for _, record := range pending {
+		// External providers send their own reminders through their signing flow
+		if record.Provider != "" {
+			continue
+		}
+
+		days := DueSigningReminder(record.CreatedAt, record.ExpiresAt, now, record.LastReminderDays, reminderDays)
+		if days == 0 {
+			continue
+		}
+
+		if err := s.sendReminder(ctx, record); err != nil {
+			logging.FromContext(ctx).Error("error sending reminder of signing request", "component", "signing", "record_id", record.ID, "error", err)
+			continue
+		}
+		if err := s.signingRepo.SetLastReminder(ctx, record.ID, days); err != nil {
+			continue
+		}
+		s.recordEvent(ctx, record.ID, storage.SigningEventReminderSent, fmt.Sprintf("%d days before expiry", days))
+		result.RemindersSent++
+	}
+
+	return result, nil
+}
+
+// Start schedules the reminder and expiry job
+func (s *SigningReminderService) Start() error {
+	schedule := s.schedule
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("signing_reminders") {
+			return
+		}
+		start := time.Now()
+		result, err := s.Run(context.Background(), start)
+		metrics.JobRun("signing_reminders", start, err)
+		if err != nil {
+			slog.Error("error running signing reminders", "component", "signing", "error", err)
+			return
+		}
+		if result.RemindersSent > 0 || result.Expired > 0 {
+			slog.Info("reminders sent, signing requests expired", "component", "signing", "reminders_sent", result.RemindersSent, "expired", result.Expired)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid SIGNING_REMINDER_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("signing reminders", c)
+
+	slog.Info("signing reminder scheduler started", "component", "signing", "schedule", schedule, "days_before_expiry", s.reminderDays)
+	return nil
+}
+
+// sendReminder emails the recipient the signing link again, in their language when they have
+// a user with one
+func (s *SigningReminderService) sendReminder(ctx context.Context, record storage.ContractSigningRecord) error {
+	signerName := record.RecipientEmail
+	var org *model.Organization
+	locale := ""
+	if recipientID, err := uuid.Parse(record.RecipientID); err == nil {
+		if recipient, err := s.personRepo.GetByID(ctx, recipientID); err == nil && recipient != nil {
+			signerName = recipient.FullName
+		}
+		if user, err := s.userRepo.GetByPersonID(ctx, recipientID); err == nil && user != nil {
+			locale = user.Locale
+		}
+		if s.orgService != nil {
+			org = s.orgService.ForPerson(ctx, recipientID)
+		}
+	}
+
+	signingURL := fmt.Sprintf("%s/sign/%s", OrganizationBaseURL(org), record.ID)
+	subject, body, err := renderEmail(EmailTemplateSigningReminder, org, locale, SigningEmailData{
+		FirmanteNombre:   signerName,
+		FirmanteEmail:    record.RecipientEmail,
+		ContratoID:       record.ContractID,
+		EnlaceFirma:      signingURL,
+		FechaVencimiento: emailFormatter(locale, org).Date(record.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	return SendSimpleEmail(record.RecipientEmail, subject, body)
+}
+
+// notifyRequester emails the admin or manager who requested an expired signature, in their
+// language. Requests created before the requester was recorded notify the admins instead.
+func (s *SigningReminderService) notifyRequester(ctx context.Context, record storage.ContractSigningRecord) error {
+	var recipients []string
+	locale := ""
+	if requesterID, err := uuid.Parse(record.RequestedBy); err == nil {
+		if user, err := s.userRepo.GetByPersonID(ctx, requesterID); err == nil && user != nil && user.Email != "" {
+			recipients = append(recipients, user.Email)
+			locale = user.Locale
+		}
+	}
+	if len(recipients) == 0 {
+		users, err := s.userRepo.GetAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get admins: %w", err)
+		}
+		for _, user := range users {
+			if user.Role == "admin" && user.Status != "disabled" && user.Email != "" {
+				recipients = append(recipients, user.Email)
+			}
+		}
+	}
+
+	signerName := record.RecipientEmail
+	var org *model.Organization
+	if recipientID, err := uuid.Parse(record.RecipientID); err == nil {
+		if recipient, err := s.personRepo.GetByID(ctx, recipientID); err == nil && recipient != nil {
+			signerName = recipient.FullName
+		}
+		if s.orgService != nil {
+			org = s.orgService.ForPerson(ctx, recipientID)
+		}
+	}
+
+	subject, body, err := renderEmail(EmailTemplateSigningExpired, org, locale, SigningEmailData{
+		FirmanteNombre:   signerName,
+		FirmanteEmail:    record.RecipientEmail,
+		ContratoID:       record.ContractID,
+		FechaVencimiento: emailFormatter(locale, org).Date(record.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, to := range recipients {
+		if err := SendSimpleEmail(to, subject, body); err != nil {
+			logging.FromContext(ctx).Error("error sending expiry notice", "component", "signing", "record_id", record.ID, "to", to, "error", err)
+		}
+	}
+	return nil
+}
+
+// recordEvent adds the job's actions to the audit trail of the signing request
+func (s *SigningReminderService) recordEvent(ctx context.Context, signingID, event, detail string) {
+	_, err := s.eventRepo.Create(ctx, &storage.ContractSigningEvent{
+		SigningID: signingID,
+		Event:     event,
+		Detail:    detail,
+		CreatedAt: model.FlexibleTime(time.Now()),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error recording event of signing request", "event", event, "signing_id", signingID, "error", err)
+	}
+}
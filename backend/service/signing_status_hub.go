@@ -0,0 +1,71 @@
+package service
+
+import (
+	"sync"
+)
+
+// SigningStatusHub wakes the status streams of the signing requests whose status changed on
+// this instance. Streams also poll the database, so changes made on other instances reach
+// them as well, only later.
+type SigningStatusHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+var signingStatusHub = &SigningStatusHub{
+	subscribers: make(map[string]map[chan struct{}]struct{}),
+	closed:      make(chan struct{}),
+}
+
+// GetSigningStatusHub returns the hub shared by the signing status streams
+func GetSigningStatusHub() *SigningStatusHub {
+	return signingStatusHub
+}
+
+// Subscribe returns a channel that receives a value when the status of a signing request may
+// have changed, and the function that stops the subscription
+func (h *SigningStatusHub) Subscribe(signingID string) (<-chan struct{}, func()) {
+	updates := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subscribers[signingID] == nil {
+		h.subscribers[signingID] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[signingID][updates] = struct{}{}
+	h.mu.Unlock()
+
+	return updates, func() {
+		h.mu.Lock()
+		delete(h.subscribers[signingID], updates)
+		if len(h.subscribers[signingID]) == 0 {
+			delete(h.subscribers, signingID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish wakes the streams of a signing request. A stream that was not listening yet gets a
+// single pending wake-up however many changes happened.
+func (h *SigningStatusHub) Publish(signingID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for updates := range h.subscribers[signingID] {
+		select {
+		case updates <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Closed is closed when the server shuts down, so the open streams end and do not hold the
+// drain of the requests in flight
+func (h *SigningStatusHub) Closed() <-chan struct{} {
+	return h.closed
+}
+
+// Close ends every open stream
+func (h *SigningStatusHub) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultWebhookSchedule is how often failed deliveries are retried when WEBHOOK_DELIVERY_SCHEDULE is not set
+	defaultWebhookSchedule = "@every 1m"
+	// webhookBatchSize is the maximum number of deliveries posted on each run
+	webhookBatchSize = 50
+	// webhookTimeout limits how long a webhook may take to answer
+	webhookTimeout = 10 * time.Second
+)
+
+// webhookRetryDelays are the waits before each retry of a failed delivery. A delivery is marked
+// as failed after the last one.
+var webhookRetryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+// SigningWebhookPayload is the JSON body posted to the webhooks
+type SigningWebhookPayload struct {
+	ID        string              `json:"id"` // Delivery ID, repeated on retries so receivers can deduplicate
+	Event     string              `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      *SigningWebhookData `json:"data,omitempty"`
+}
+
+// SigningWebhookData describes the signing request an event is about
+type SigningWebhookData struct {
+	SigningID      string     `json:"signing_id"`
+	ContractID     string     `json:"contract_id"`
+	RecipientID    string     `json:"recipient_id"`
+	RecipientEmail string     `json:"recipient_email"`
+	Status         string     `json:"status"`
+	Provider       string     `json:"provider,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	ViewedAt       *time.Time `json:"viewed_at,omitempty"`
+	SignedAt       *time.Time `json:"signed_at,omitempty"`
+	RejectedAt     *time.Time `json:"rejected_at,omitempty"`
+}
+
+// SigningWebhookDispatcher posts the signing lifecycle events to the webhooks registered by the
+// managers. Deliveries are queued and retried with backoff until the webhook answers 2xx.
+type SigningWebhookDispatcher struct {
+	repo        storage.WebhookStore
+	signingRepo storage.ContractSigningStore
+	httpClient  *http.Client
+	schedule    string
+	mu          sync.Mutex // Prevents overlapping runs from posting the same delivery twice
+}
+
+// NewSigningWebhookDispatcher creates a SigningWebhookDispatcher retrying the failed deliveries
+// on schedule (WEBHOOK_DELIVERY_SCHEDULE)
+func NewSigningWebhookDispatcher(repoFactory *storage.RepositoryFactory, schedule string) *SigningWebhookDispatcher {
+	return &SigningWebhookDispatcher{
+		repo:        repoFactory.GetWebhookRepository(),
+		signingRepo: repoFactory.GetContractSigningRepository(),
+		httpClient:  &http.Client{Timeout: webhookTimeout},
+		schedule:    orDefault(schedule, defaultWebhookSchedule),
+	}
+}
+
+// GenerateWebhookSecret returns a random key for the signatures of a webhook
+func GenerateWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature of a body: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" with the secret of the webhook
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhookURL checks that a callback URL is absolute and uses HTTPS. Plain HTTP is only
+// accepted for localhost, to test integrations.
+func ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return errors.New("url must be an absolute URL")
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if host := parsed.Hostname(); host == "localhost" || host == "127.0.0.1" {
+			return nil
+		}
+	}
+	return errors.New("url must use https")
+}
+
+// Dispatch queues an event of a signing request for the webhooks subscribed to it: the ones of
+// the person who requested the signature and the ones registered for every request. Runs in
+// the background so the signing flow never waits for the webhooks. The status streams of the
+// request are woken as well, webhooks or not.
+func (d *SigningWebhookDispatcher) Dispatch(event, signingID string) {
+	GetSigningStatusHub().Publish(signingID)
+	if d == nil {
+		return
+	}
+
+	runInBackground(func() {
+		ctx := context.Background()
+		queued, err := d.enqueue(ctx, event, signingID)
+		if err != nil {
+			slog.Error("error queuing of signing request", "component", "webhook", "event", event, "signing_id", signingID, "error", err)
+			return
+		}
+		if queued > 0 {
+			if _, err := d.ProcessDue(ctx); err != nil {
+				slog.Error("error posting due deliveries", "component", "webhook", "error", err)
+			}
+		}
+	})
+}
+
+// enqueue creates a delivery of an event for each subscribed webhook and returns how many were queued
+func (d *SigningWebhookDispatcher) enqueue(ctx context.Context, event, signingID string) (int, error) {
+	record, err := d.signingRepo.GetByID(ctx, signingID)
+	if err != nil {
+		return 0, err
+	}
+	if record == nil {
+		return 0, fmt.Errorf("signing request %s not found", signingID)
+	}
+
+	subscriptions, err := d.repo.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	data := signingWebhookData(*record)
+	queued := 0
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(event) {
+			continue
+		}
+		if !subscription.AllRequests && subscription.PersonID.String() != record.RequestedBy {
+			continue
+		}
+
+		if _, err := d.queue(ctx, subscription, event, record.ID, data); err != nil {
+			logging.FromContext(ctx).Warn("could not queue for webhook", "component", "webhook", "event", event, "subscription_id", subscription.ID, "error", err)
+			continue
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// SendTest queues a ping for a webhook and posts it right away
+func (d *SigningWebhookDispatcher) SendTest(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookDelivery, error) {
+	delivery, err := d.queue(ctx, subscription, model.WebhookEventPing, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deliver(ctx, subscription, delivery)
+	return delivery, nil
+}
+
+// queue stores a delivery of an event for a webhook
+func (d *SigningWebhookDispatcher) queue(ctx context.Context, subscription model.WebhookSubscription, event, signingID string, data *SigningWebhookData) (*model.WebhookDelivery, error) {
+	deliveryID := uuid.New()
+	now := time.Now()
+	payload, err := json.Marshal(SigningWebhookPayload{
+		ID:        deliveryID.String(),
+		Event:     event,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.repo.CreateDelivery(ctx, model.WebhookDelivery{
+		ID:             deliveryID,
+		SubscriptionID: subscription.ID,
+		Event:          event,
+		SigningID:      signingID,
+		Payload:        payload,
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+}
+
+// ProcessDue posts the deliveries whose next attempt has been reached and returns how many
+// were accepted
+func (d *SigningWebhookDispatcher) ProcessDue(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deliveries, err := d.repo.GetDueDeliveries(ctx, time.Now(), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uuid.UUID]*model.WebhookSubscription)
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, loaded := subscriptions[delivery.SubscriptionID]
+		if !loaded {
+			subscription, err = d.repo.GetSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				logging.FromContext(ctx).Warn("could not load webhook", "component", "webhook", "subscription_id", delivery.SubscriptionID, "error", err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		// Deliveries of disabled webhooks are dropped instead of retried
+		if subscription == nil || !subscription.Active {
+			if err := d.repo.MarkDeliveryFailed(ctx, delivery.ID, model.WebhookDeliveryFailed, delivery.Attempts, 0, "webhook disabled", time.Now()); err != nil {
+				logging.FromContext(ctx).Warn("could not record the failure of delivery", "component", "webhook", "delivery_id", delivery.ID, "error", err)
+			}
+			continue
+		}
+
+		if d.deliver(ctx, *subscription, delivery) {
+			delivered++
+		}
+	}
+
+	if len(deliveries) > 0 {
+		logging.FromContext(ctx).Info("delivered due webhook events", "component", "webhook", "delivered", delivered, "deliveries_count", len(deliveries))
+	}
+	return delivered, nil
+}
+
+// deliver posts a delivery and records the result, scheduling the next retry on failure
+func (d *SigningWebhookDispatcher) deliver(ctx context.Context, subscription model.WebhookSubscription, delivery *model.WebhookDelivery) bool {
+	attempts := delivery.Attempts + 1
+	responseStatus, err := d.post(ctx, subscription, *delivery)
+	if err == nil {
+		now := time.Now()
+		if err := d.repo.MarkDelivered(ctx, delivery.ID, attempts, responseStatus, now); err != nil {
+			logging.FromContext(ctx).Warn("delivery accepted but could not be marked as delivered", "component", "webhook", "delivery_id", delivery.ID, "error", err)
+		}
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.Attempts = attempts
+		delivery.ResponseStatus = responseStatus
+		delivery.DeliveredAt = &now
+		return true
+	}
+
+	status := model.WebhookDeliveryPending
+	nextAttemptAt := time.Now()
+	if attempts > len(webhookRetryDelays) {
+		status = model.WebhookDeliveryFailed
+	} else {
+		nextAttemptAt = nextAttemptAt.Add(webhookRetryDelays[attempts-1])
+	}
+	logging.FromContext(ctx).Error("webhook delivery failed", "component", "webhook", "event", delivery.Event, "url", subscription.URL, "attempt", attempts, "max_attempts", len(webhookRetryDelays)+1, "error", err)
+	if markErr := d.repo.MarkDeliveryFailed(ctx, delivery.ID, status, attempts, responseStatus, err.Error(), nextAttemptAt); markErr != nil {
+		logging.FromContext(ctx).Warn("could not record the failure of delivery", "component", "webhook", "delivery_id", delivery.ID, "error", markErr)
+	}
+	delivery.Status = status
+	delivery.Attempts = attempts
+	delivery.ResponseStatus = responseStatus
+	delivery.LastError = err.Error()
+	delivery.NextAttemptAt = nextAttemptAt
+	return false
+}
+
+// post sends the signed payload of a delivery and returns the response status
+func (d *SigningWebhookDispatcher) post(ctx context.Context, subscription model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RentManager-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(subscription.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Only the status matters, a short read lets the connection be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook answered status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Start registers the cron job retrying the failed deliveries (WEBHOOK_DELIVERY_SCHEDULE, every
+// minute by default)
+func (d *SigningWebhookDispatcher) Start() error {
+	schedule := d.schedule
+
+	c := cron.New()
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("webhook_delivery") {
+			return
+		}
+		start := time.Now()
+		_, err := d.ProcessDue(context.Background())
+		metrics.JobRun("webhook_delivery", start, err)
+		if err != nil {
+			slog.Error("error posting due deliveries", "component", "webhook", "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid WEBHOOK_DELIVERY_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("webhook delivery", c)
+
+	slog.Info("webhook delivery scheduler started", "component", "webhook", "schedule", schedule)
+	return nil
+}
+
+// signingWebhookData builds the data of the events of a signing request
+func signingWebhookData(record storage.ContractSigningRecord) *SigningWebhookData {
+	return &SigningWebhookData{
+		SigningID:      record.ID,
+		ContractID:     record.ContractID,
+		RecipientID:    record.RecipientID,
+		RecipientEmail: record.RecipientEmail,
+		Status:         record.Status,
+		Provider:       record.Provider,
+		CreatedAt:      record.CreatedAt,
+		ExpiresAt:      record.ExpiresAt,
+		ViewedAt:       record.ViewedAt,
+		SignedAt:       record.SignedAt,
+		RejectedAt:     record.RejectedAt,
+	}
+}
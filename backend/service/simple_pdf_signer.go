@@ -1,184 +1,116 @@
 package service
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/x509"
-	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
-	"math/big"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"encoding/base64"
+	"log/slog"
 
-	"github.com/jung-kurt/gofpdf"
+	"github.com/nescool101/rentManager/model"
 )
 
-// SimpleSignPDF creates a signed PDF file for the contract with embedded signature information
-func SimpleSignPDF(contractData ContractPDF, signerName, signerEmail, signingID string) ([]byte, error) {
-	// Generate a new ECDSA private key
-	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+// SimpleSignPDF creates a signed PDF file for the contract with embedded signature information.
+// The evidence of the signer (IP, user agent and location) is printed on a certificate page
+// appended to the contract and embedded in the signature.
+func SimpleSignPDF(contractData ContractPDF, signerName, signerEmail, signingID string, evidence *model.SigningEvidence) ([]byte, error) {
+	// Configured signing certificate, or the self-signed one when none is configured
+	signingCert, err := ActiveSigningCertificate()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate ECDSA key: %w", err)
-	}
-
-	// Create a self-signed certificate
-	tmpl := x509.Certificate{
-		SerialNumber: big.NewInt(1),
-		Subject:      pkix.Name{CommonName: signerName},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(1, 0, 0),
-		KeyUsage:     x509.KeyUsageDigitalSignature,
-	}
-
-	certDER, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &priv.PublicKey, priv)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create certificate: %w", err)
-	}
-
-	cert, err := x509.ParseCertificate(certDER)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		return nil, fmt.Errorf("failed to load signing certificate: %w", err)
 	}
+	cert := signingCert.Certificate
 
 	// Encode certificate to PEM format
 	certPEM := pem.EncodeToMemory(&pem.Block{
 		Type:  "CERTIFICATE",
-		Bytes: certDER,
+		Bytes: cert.Raw,
 	})
 
 	// Generate the contract using the proper Colombian template
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf := newPDF()
 	pdf.AddPage()
 
 	// Set up basic formatting
 	pdf.SetMargins(20, 20, 20)
 	pdf.SetAutoPageBreak(true, 20)
 
-	// Extract data with defaults (same as contract_pdf_service.go)
-	currentDate := FormatSpanishDate(contractData.CreationDate)
-	propertyAddress := getPropertyAddress(contractData.Property)
-	garageNumber := getGarageNumber(contractData.Property)
-	buildingName := getBuildingName(contractData.Property)
-
-	arrendadorName := "MARIA VICTORIA JIMENEZ DE ROSAS"
-	arrendadorCC := "41.350.115"
-	if contractData.Owner != nil && contractData.Owner.FullName != "" {
-		arrendadorName = strings.ToUpper(contractData.Owner.FullName)
-		arrendadorCC = contractData.Owner.NIT
-	}
-
-	arrendatarioName := "SEBASTIÁN MOTAVITA MEDELLÍN"
-	arrendatarioCC := "1.026.281.306"
-	if contractData.Renter != nil && contractData.Renter.FullName != "" {
-		arrendatarioName = strings.ToUpper(contractData.Renter.FullName)
-		arrendatarioCC = contractData.Renter.NIT
-	}
-
-	testigoName := "NA"
-	testigoCC := "NA"
-	if contractData.Witness != nil && contractData.Witness.FullName != "" {
-		testigoName = strings.ToUpper(contractData.Witness.FullName)
-		testigoCC = contractData.Witness.NIT
-	}
-
-	codeudorName := "NÉSTOR FERNANDO ÁLVAREZ"
-	codeudorCC := "1.015.398.879"
-	if contractData.CoSigner != nil && contractData.CoSigner.FullName != "" {
-		codeudorName = strings.ToUpper(contractData.CoSigner.FullName)
-		codeudorCC = contractData.CoSigner.NIT
-	}
-
-	canonMensual := "$1,600,000.00"
-	canonIncluido := "INCLUÍDA LA ADMINISTRACIÓN"
-	if contractData.Pricing != nil && contractData.Pricing.MonthlyRent > 0 {
-		canonMensual = FormatMoney(contractData.Pricing.MonthlyRent)
-	}
-
-	fechaIniciacion := "Junio 6 de 2022"
-	fechaTerminacion := "Diciembre 5 de 2022"
-	if !contractData.StartDate.IsZero() {
-		fechaIniciacion = FormatSpanishDate(contractData.StartDate)
+	template := DefaultContractTemplate()
+	if contractData.Template != nil {
+		template = *contractData.Template
 	}
-	if !contractData.EndDate.IsZero() {
-		fechaTerminacion = FormatSpanishDate(contractData.EndDate)
+	if contractData.Guarantee != nil {
+		template = WithGuaranteePolicy(template, *contractData.Guarantee, contractMonthlyRent(contractData))
 	}
+	values := ContractTemplateValues(contractData, template)
+	blocks := contractSignatureBlocks(template)
 
-	// Title
-	pdf.SetFont("Arial", "B", 14)
-	pdf.MultiCell(0, 8, fixSpanishChars("CONTRATO DE ARRENDAMIENTO DE INMUEBLE PARA VIVIENDA URBANA"), "", "C", false)
-	pdf.Ln(2)
-	pdf.SetFont("Arial", "B", 12)
-	pdf.MultiCell(0, 6, fixSpanishChars(propertyAddress), "", "C", false)
-	pdf.Ln(10)
-
-	// Header information
-	pdf.SetFont("Arial", "B", 10)
-	addInfoLine(pdf, "LUGAR Y FECHA DEL CONTRATO:", "Bogotá, D. C., "+currentDate)
-	addInfoLine(pdf, "DIRECCION DEL INMUEBLE:", propertyAddress+",")
-	addInfoLine(pdf, "", "Garaje # "+garageNumber+", Edificio "+buildingName)
-	addInfoLine(pdf, "ARRENDADOR:", arrendadorName+", CC "+arrendadorCC)
-	addInfoLine(pdf, "ARRENDATARIO:", arrendatarioName+", CC "+arrendatarioCC)
-	addInfoLine(pdf, "TESTIGO:", testigoName+", CC "+testigoCC)
-	addInfoLine(pdf, "CODEUDOR:", codeudorName+", CC "+codeudorCC)
-	addInfoLine(pdf, "CANON MENSUAL:", canonMensual+" "+canonIncluido)
-	addInfoLine(pdf, "FECHA INICIACION:", fechaIniciacion)
-	addInfoLine(pdf, "FECHA TERMINACION:", fechaTerminacion)
-
-	pdf.Ln(10)
+	addContractHeader(pdf, RenderTemplateText(template.Title, values), blocks, values, contractData.Renewal != nil)
 
 	// Main content title
-	pdf.SetFont("Arial", "B", 12)
-	pdf.MultiCell(0, 8, fixSpanishChars("CONDICIONES GENERALES"), "", "C", false)
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.MultiCell(0, 8, "CONDICIONES GENERALES", "", "C", false)
 	pdf.Ln(5)
 
-	// Add first few clauses (abbreviated for space)
-	addClause(pdf, "PRIMERA: OBJETO DEL CONTRATO:",
-		"Mediante el presente contrato el ARRENDADOR concede al ARRENDATARIO el goce de los inmuebles que adelante se identifican por su dirección y linderos, de acuerdo con el inventario que las partes firman por separado, el cual forma parte integral de este mismo contrato de arrendamiento.")
+	// Add the first clause only (abbreviated for space)
+	if len(template.Clauses) > 0 {
+		clause := template.Clauses[0]
+		addClause(pdf, ClauseOrdinal(1)+": "+RenderTemplateText(clause.Title, values)+":", RenderTemplateText(clause.Body, values))
+	}
 
 	// Digital signature banner
 	pdf.SetFillColor(220, 220, 220) // Light gray background
 	pdf.Rect(20, pdf.GetY(), 170, 30, "F")
-	pdf.SetFont("Arial", "B", 12)
+	pdf.SetFont(pdfFontFamily, "B", 12)
 	pdf.SetXY(20, pdf.GetY()+5)
-	pdf.MultiCell(170, 8, fixSpanishChars("CERTIFICADO DE FIRMA DIGITAL"), "", "C", false)
+	pdf.MultiCell(170, 8, "CERTIFICADO DE FIRMA DIGITAL", "", "C", false)
 
 	// Signature details
-	pdf.SetFont("Arial", "", 10)
+	pdf.SetFont(pdfFontFamily, "", 10)
 	pdf.SetXY(30, pdf.GetY())
-	pdf.MultiCell(150, 6, fixSpanishChars(fmt.Sprintf("Firmado por: %s (%s)", signerName, signerEmail)), "", "L", false)
+	pdf.MultiCell(150, 6, fmt.Sprintf("Firmado por: %s (%s)", signerName, signerEmail), "", "L", false)
 	pdf.SetX(30)
-	pdf.MultiCell(150, 6, fixSpanishChars(fmt.Sprintf("Fecha y hora: %s", time.Now().Format("02/01/2006 15:04:05"))), "", "L", false)
+	pdf.MultiCell(150, 6, fmt.Sprintf("Fecha y hora: %s", FormatDateTime(time.Now())), "", "L", false)
 	pdf.SetX(30)
-	pdf.MultiCell(150, 6, fixSpanishChars(fmt.Sprintf("ID de Firma: %s", signingID)), "", "L", false)
+	pdf.MultiCell(150, 6, fmt.Sprintf("ID de Firma: %s", signingID), "", "L", false)
 	pdf.Ln(5)
 
 	// Fingerprint data
 	fingerprint := fmt.Sprintf("%X", cert.SerialNumber)
-	pdf.SetFont("Arial", "", 8)
-	pdf.MultiCell(0, 5, fixSpanishChars(fmt.Sprintf("Huella digital del certificado: %s", fingerprint)), "", "L", false)
+	pdf.SetFont(pdfFontFamily, "", 8)
+	pdf.MultiCell(0, 5, fmt.Sprintf("Huella digital del certificado: %s", fingerprint), "", "L", false)
 
 	// Validation text
-	pdf.SetFont("Arial", "I", 8)
-	pdf.MultiCell(0, 5, fixSpanishChars("Este documento ha sido firmado digitalmente utilizando tecnología ECDSA (Elliptic Curve Digital Signature Algorithm) y está legalmente vinculado a la identidad del firmante."), "", "L", false)
+	pdf.SetFont(pdfFontFamily, "I", 8)
+	pdf.MultiCell(0, 5, fmt.Sprintf("Este documento ha sido firmado digitalmente utilizando tecnología %s y está legalmente vinculado a la identidad del firmante.", signingKeyDescription(signingCert)), "", "L", false)
 
 	// Add signature tables
-	addSignatureTables(pdf, arrendadorName, arrendadorCC, arrendatarioName, arrendatarioCC, testigoName, testigoCC, codeudorName, codeudorCC)
+	addSignatureTables(pdf, blocks, values)
 
 	// Add condensed certificate data at the bottom
 	pdf.Ln(5)
-	pdf.SetFont("Arial", "", 6)
+	pdf.SetFont(pdfFontFamily, "", 6)
 	certString := base64.StdEncoding.EncodeToString(certPEM)
 	if len(certString) > 300 {
 		certString = certString[:300] + "..."
 	}
 	pdf.MultiCell(0, 4, fmt.Sprintf("Datos del certificado: %s", certString), "", "L", false)
 
+	// The inventory annex is signed together with the contract
+	if contractData.Inventory != nil {
+		addInventoryAnnex(pdf, contractData.Inventory, values, blocks)
+	}
+
+	// Visible signature with the QR code to verify it, on the last page
+	stamp := NewSignatureStamp(signerName, signerEmail, signingID)
+	addSignatureStamp(pdf, stamp)
+
+	// Evidence of the signature on its own page
+	addSigningCertificatePage(pdf, stamp, evidence, signingCert)
+
 	// Create temp directory if it doesn't exist
 	tempDir := filepath.Join(os.TempDir(), "contracts")
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
@@ -198,105 +130,61 @@ func SimpleSignPDF(contractData ContractPDF, signerName, signerEmail, signingID
 		return nil, fmt.Errorf("failed to read PDF from file: %w", err)
 	}
 
-	return pdfBytes, nil
+	// Embed the cryptographic signature, with the metadata read back by the verification endpoint
+	signedBytes, err := SignPDF(pdfBytes, signerName, SignPDFOptions{
+		SignatureReason:   "Contract signing",
+		SignatureLocation: "Digital Signature",
+		SignatureContact: fmt.Sprintf("SignID: %s | SignedBy: %s | TimeSigned: %s%s",
+			signingID, signerEmail, stamp.SignedAt.Format(time.RFC3339), signingEvidenceContact(evidence)),
+	})
+	if err != nil {
+		slog.Warn("error signing PDF, proceeding with the unsigned PDF", "error", err)
+		return pdfBytes, nil
+	}
+
+	return signedBytes, nil
+}
+
+// signingKeyDescription returns the name of the signature algorithm printed in the contract
+func signingKeyDescription(cert *SigningCertificate) string {
+	if cert.KeyAlgorithm() == SigningKeyRSA {
+		return "RSA"
+	}
+	return "ECDSA (Elliptic Curve Digital Signature Algorithm)"
 }
 
 // CreateSimpleContractPDF creates a basic contract PDF without digital signature
 func CreateSimpleContractPDF(contractID string, propertyAddress string, renterName string) ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf := newPDF()
 	pdf.AddPage()
 
 	// Set up basic formatting
 	pdf.SetMargins(20, 20, 20)
 	pdf.SetAutoPageBreak(true, 20)
 
-	// Title
-	pdf.SetFont("Arial", "B", 12)
-	pdf.MultiCell(0, 8, "CONTRATO DE ARRENDAMIENTO DE INMUEBLE PARA VIVIENDA URBANA", "", "C", false)
-	pdf.Ln(2)
-	pdf.SetFont("Arial", "", 11)
-	pdf.MultiCell(0, 6, "Carrera 18b No 145 - 08 Apto 201", "", "C", false)
-	pdf.Ln(10)
-
-	// Date and location
-	pdf.SetFont("Arial", "B", 10)
-	currentDate := time.Now().Format("2 de January de 2006")
-	currentDate = strings.Replace(currentDate, "January", "enero", 1)
-	pdf.MultiCell(0, 6, "LUGAR Y FECHA DEL CONTRATO: Bogotá, D. C., "+currentDate, "", "L", false)
-	pdf.Ln(5)
-
-	// Contract data - two columns layout
-	leftColWidth := float64(60)
-	rightColWidth := float64(110)
-
-	// Direccion del inmueble
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "DIRECCION DEL INMUEBLE:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.MultiCell(rightColWidth, 6, propertyAddress+" ", "", "L", false)
-	pdf.Ln(1)
-
-	// Arrendador
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "ARRENDADOR:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "MARIA VICTORIA JIMENEZ DE ROSAS, CC 41.350.115")
-	pdf.Ln(8)
-
-	// Arrendatario (nombre del arrendatario)
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "ARRENDATARIO:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, renterName)
-	pdf.Ln(8)
-
-	// Testigo y Codeudor
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "TESTIGO:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "LAURA CAMILA CORREA, CC 1.020.841.781")
-	pdf.Ln(8)
-
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "CODEUDOR:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "NÉSTOR FERNANDO ÁLVAREZ, CC 1.015.398.879")
-	pdf.Ln(8)
-
-	// Canon mensual
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "CANON MENSUAL:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "$1,600,000.00 INCLUÍDA LA ADMINISTRACIÓN")
-	pdf.Ln(8)
-
-	// Fechas
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "FECHA INCIACION:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "Junio 6 de 2022")
-	pdf.Ln(8)
-
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(leftColWidth, 6, "FECHA TERMINACION:")
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(rightColWidth, 6, "Diciembre 5 de 2022")
-	pdf.Ln(15)
+	// Only the address and renter are known here, the other parties are left blank
+	template := DefaultContractTemplate()
+	values := ContractTemplateValues(ContractPDF{
+		Renter:       &model.Person{FullName: renterName},
+		Property:     &model.Property{Address: propertyAddress},
+		CreationDate: time.Now(),
+	}, template)
+	addContractHeader(pdf, template.Title, template.SignatureBlocks, values, false)
 
 	// Condiciones generales
-	pdf.SetFont("Arial", "B", 12)
+	pdf.SetFont(pdfFontFamily, "B", 12)
 	pdf.MultiCell(0, 8, "CONDICIONES GENERALES", "", "C", false)
 	pdf.Ln(3)
 
 	// Pendiente de firma - banner
 	pdf.SetFillColor(255, 240, 240) // Light red background
 	pdf.Rect(20, pdf.GetY(), 170, 20, "F")
-	pdf.SetFont("Arial", "B", 12)
+	pdf.SetFont(pdfFontFamily, "B", 12)
 	pdf.SetXY(20, pdf.GetY()+5)
 	pdf.MultiCell(170, 8, "PENDIENTE DE FIRMA", "", "C", false)
 
 	// Pendiente de firma - texto
-	pdf.SetFont("Arial", "", 10)
+	pdf.SetFont(pdfFontFamily, "", 10)
 	pdf.Ln(20)
 	pdf.MultiCell(0, 6, "Este contrato está pendiente de firma digital. Una vez firmado, se generará una versión firmada digitalmente con validez legal.", "", "L", false)
 
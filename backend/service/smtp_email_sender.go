@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// SMTPEmailSender sends emails through an SMTP server with STARTTLS (ProtonMail, Gmail, ...)
+type SMTPEmailSender struct {
+	config *ProtonMailConfig // nil uses DefaultProtonMailConfig at send time
+}
+
+// NewSMTPEmailSender creates an SMTP driver with an explicit configuration
+func NewSMTPEmailSender(config ProtonMailConfig) *SMTPEmailSender {
+	return &SMTPEmailSender{config: &config}
+}
+
+// Name returns the driver name
+func (s *SMTPEmailSender) Name() string {
+	return config.EmailDriverSMTP
+}
+
+func (s *SMTPEmailSender) currentConfig() ProtonMailConfig {
+	if s.config != nil {
+		return *s.config
+	}
+	return DefaultProtonMailConfig
+}
+
+// Send delivers the message to every recipient
+func (s *SMTPEmailSender) Send(ctx context.Context, msg EmailMessage) error {
+	config := s.currentConfig()
+	from := (&mail.Address{Name: config.FromName, Address: config.Username}).String()
+	message, err := buildMIMEMessage(from, msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.connect(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Set the sender and recipients
+	if err := client.Mail(config.Username); err != nil {
+		return fmt.Errorf("smtp sender: %w", err)
+	}
+	for _, recipient := range msg.To {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("smtp recipient %s: %w", recipient, err)
+		}
+	}
+
+	// Send the email body
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp close: %w", err)
+	}
+
+	// Send the QUIT command and close the connection
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("smtp quit: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck connects to the server, starts TLS and authenticates
+func (s *SMTPEmailSender) HealthCheck(ctx context.Context) error {
+	client, err := s.connect(ctx, s.currentConfig())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
+
+// connect opens an authenticated STARTTLS session with the server
+func (s *SMTPEmailSender) connect(ctx context.Context, config ProtonMailConfig) (*smtp.Client, error) {
+	serverAddr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp dial: %w", err)
+	}
+	if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("smtp tls: %w", err)
+	}
+	if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("smtp auth: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+var (
+	spanishUnits = []string{"", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+		"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete", "dieciocho", "diecinueve",
+		"veinte", "veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve"}
+	spanishTens     = []string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+	spanishHundreds = []string{"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos",
+		"seiscientos", "setecientos", "ochocientos", "novecientos"}
+
+	// spanishScales are the long-scale powers of a million, largest first
+	spanishScales = []struct {
+		value            uint64
+		singular, plural string
+	}{
+		{1e18, "trillón", "trillones"},
+		{1e12, "billón", "billones"},
+		{1e6, "millón", "millones"},
+	}
+)
+
+// NumberToWords writes a number as a Spanish cardinal: 21 is "veintiuno", 1.500.000 is "un
+// millón quinientos mil"
+func NumberToWords(n int) string {
+	switch {
+	case n == 0:
+		return "cero"
+	case n < 0:
+		// -(n+1) does not overflow for math.MinInt, whose negation is not an int
+		return "menos " + spanishCardinal(uint64(-(n+1))+1, false)
+	}
+	return spanishCardinal(uint64(n), false)
+}
+
+// AmountInWords writes an amount in Spanish upper case as contracts print it before the
+// currency: 1.521.000 is "UN MILLÓN QUINIENTOS VEINTIÚN MIL". Amounts of currencies with
+// decimals end with their cents: 10,50 dollars is "DIEZ CON CINCUENTA CENTAVOS".
+func AmountInWords(amount float64, currency string) string {
+	units, cents := amountPartsInWords(amount, currency)
+	if cents == "" {
+		return units
+	}
+	return units + " CON " + cents
+}
+
+// AmountWithCurrencyInWords writes an amount in words followed by its currency, with the "DE"
+// of the exact millions and the cents after the currency: "DOS MILLONES DE PESOS MONEDA LEGAL",
+// "DIEZ DÓLARES DE LOS ESTADOS UNIDOS DE AMÉRICA CON CINCUENTA CENTAVOS"
+func AmountWithCurrencyInWords(amount float64, currency string) string {
+	units, cents := amountPartsInWords(amount, currency)
+	if strings.HasSuffix(units, "LLÓN") || strings.HasSuffix(units, "LLONES") {
+		units += " DE"
+	}
+	words := units + " " + CurrencyWords(currency)
+	if cents == "" {
+		return words
+	}
+	return words + " CON " + cents
+}
+
+// amountPartsInWords writes the whole units of an amount and its cents, rounded to the decimals
+// of the currency. The cents are empty when there are none.
+func amountPartsInWords(amount float64, currency string) (units, cents string) {
+	if model.CurrencyDecimals(currency) == 0 {
+		return wholeAmountInWords(amount), ""
+	}
+
+	totalCents := math.Round(math.Abs(amount) * 100)
+	whole := math.Floor(totalCents / 100)
+	if amount < 0 {
+		whole = -whole
+	}
+	units = wholeAmountInWords(whole)
+	switch centsCount := uint64(math.Mod(totalCents, 100)); centsCount {
+	case 0:
+	case 1:
+		cents = "UN CENTAVO"
+	default:
+		cents = strings.ToUpper(spanishCardinal(centsCount, true)) + " CENTAVOS"
+	}
+	if units == "CERO" && cents != "" && amount < 0 {
+		units = "MENOS CERO"
+	}
+	return units, cents
+}
+
+// wholeAmountInWords writes an amount rounded to whole units in Spanish upper case, with the
+// apocope of the nouns that follow it: 21 is "VEINTIÚN"
+func wholeAmountInWords(amount float64) string {
+	rounded := math.Round(math.Abs(amount))
+	if rounded == 0 {
+		return "CERO"
+	}
+	if rounded >= math.MaxUint64 {
+		return fmt.Sprintf("%.0f", amount)
+	}
+
+	words := strings.ToUpper(spanishCardinal(uint64(rounded), true))
+	if amount < 0 {
+		return "MENOS " + words
+	}
+	return words
+}
+
+// spanishCardinal writes n (greater than zero) in words. With apocope "uno" is shortened to
+// "un" as before a noun ("veintiún pesos"); it always is before "mil" and the millions.
+func spanishCardinal(n uint64, apocope bool) string {
+	for _, scale := range spanishScales {
+		if n < scale.value {
+			continue
+		}
+
+		count, rest := n/scale.value, n%scale.value
+		words := "un " + scale.singular
+		if count > 1 {
+			words = spanishCardinal(count, true) + " " + scale.plural
+		}
+		if rest == 0 {
+			return words
+		}
+		return words + " " + spanishCardinal(rest, apocope)
+	}
+
+	if n >= 1000 {
+		thousands, rest := n/1000, n%1000
+		words := "mil"
+		if thousands > 1 {
+			words = spanishHundredsInWords(thousands, true) + " mil"
+		}
+		if rest == 0 {
+			return words
+		}
+		return words + " " + spanishHundredsInWords(rest, apocope)
+	}
+	return spanishHundredsInWords(n, apocope)
+}
+
+// spanishHundredsInWords writes a number from 1 to 999: "cien" alone, "ciento" before the tens
+func spanishHundredsInWords(n uint64, apocope bool) string {
+	if n == 100 {
+		return "cien"
+	}
+
+	hundreds, rest := n/100, n%100
+	if hundreds == 0 {
+		return spanishTensInWords(rest, apocope)
+	}
+	if rest == 0 {
+		return spanishHundreds[hundreds]
+	}
+	return spanishHundreds[hundreds] + " " + spanishTensInWords(rest, apocope)
+}
+
+// spanishTensInWords writes a number from 1 to 99: one word up to 29 ("veintitrés"), "y"
+// between the tens and the units from 31 on ("treinta y uno")
+func spanishTensInWords(n uint64, apocope bool) string {
+	if n < 30 {
+		switch {
+		case apocope && n == 1:
+			return "un"
+		case apocope && n == 21:
+			return "veintiún"
+		}
+		return spanishUnits[n]
+	}
+
+	tens, units := n/10, n%10
+	if units == 0 {
+		return spanishTens[tens]
+	}
+	unit := spanishUnits[units]
+	if apocope && units == 1 {
+		unit = "un"
+	}
+	return spanishTens[tens] + " y " + unit
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+func TestNumberToWords(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "cero"},
+		{1, "uno"},
+		{21, "veintiuno"},
+		{31, "treinta y uno"},
+		{100, "cien"},
+		{101, "ciento uno"},
+		{121, "ciento veintiuno"},
+		{1000, "mil"},
+		{21000, "veintiún mil"},
+		{100000, "cien mil"},
+		{101000, "ciento un mil"},
+		{1000000, "un millón"},
+		{1500000, "un millón quinientos mil"},
+		{21000000, "veintiún millones"},
+		{-5, "menos cinco"},
+		{-21, "menos veintiuno"},
+		{math.MinInt, "menos nueve trillones doscientos veintitrés mil trescientos setenta y dos billones treinta y seis mil ochocientos cincuenta y cuatro millones setecientos setenta y cinco mil ochocientos ocho"},
+	}
+	for _, tt := range tests {
+		if got := NumberToWords(tt.n); got != tt.want {
+			t.Errorf("NumberToWords(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAmountInWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     string
+	}{
+		{"zero", 0, model.CurrencyCOP, "CERO"},
+		{"apocope of one", 1, model.CurrencyCOP, "UN"},
+		{"apocope of twenty-one", 21, model.CurrencyCOP, "VEINTIÚN"},
+		{"apocope of thirty-one", 31, model.CurrencyCOP, "TREINTA Y UN"},
+		{"apocope before the thousands", 1521000, model.CurrencyCOP, "UN MILLÓN QUINIENTOS VEINTIÚN MIL"},
+		{"cien alone", 100, model.CurrencyCOP, "CIEN"},
+		{"ciento before the tens", 150, model.CurrencyCOP, "CIENTO CINCUENTA"},
+		{"cien thousand", 100000, model.CurrencyCOP, "CIEN MIL"},
+		{"negative", -2500, model.CurrencyCOP, "MENOS DOS MIL QUINIENTOS"},
+		{"rounded without decimals", 999.6, model.CurrencyCOP, "MIL"},
+		{"cents", 10.5, model.CurrencyUSD, "DIEZ CON CINCUENTA CENTAVOS"},
+		{"one cent", 3.01, model.CurrencyUSD, "TRES CON UN CENTAVO"},
+		{"twenty-one cents", 1.21, model.CurrencyUSD, "UN CON VEINTIÚN CENTAVOS"},
+		{"no cents", 20, model.CurrencyUSD, "VEINTE"},
+		{"only cents", 0.75, model.CurrencyUSD, "CERO CON SETENTA Y CINCO CENTAVOS"},
+		{"negative cents", -10.5, model.CurrencyUSD, "MENOS DIEZ CON CINCUENTA CENTAVOS"},
+		{"cents rounded up to a unit", 9.999, model.CurrencyUSD, "DIEZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AmountInWords(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("AmountInWords(%v, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountWithCurrencyInWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     string
+	}{
+		{"exact million", 1000000, model.CurrencyCOP, "UN MILLÓN DE PESOS MONEDA LEGAL"},
+		{"exact millions", 2000000, model.CurrencyCOP, "DOS MILLONES DE PESOS MONEDA LEGAL"},
+		{"millions with thousands", 2500000, model.CurrencyCOP, "DOS MILLONES QUINIENTOS MIL PESOS MONEDA LEGAL"},
+		{"apocope before the currency", 21, model.CurrencyCOP, "VEINTIÚN PESOS MONEDA LEGAL"},
+		{"zero", 0, model.CurrencyCOP, "CERO PESOS MONEDA LEGAL"},
+		{"cents after the currency", 1000000.5, model.CurrencyUSD, "UN MILLÓN DE DÓLARES DE LOS ESTADOS UNIDOS DE AMÉRICA CON CINCUENTA CENTAVOS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AmountWithCurrencyInWords(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("AmountWithCurrencyInWords(%v, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
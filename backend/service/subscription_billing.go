@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// ErrInvalidBillingEvent is returned for webhook calls that are malformed or not signed by the
+// billing provider
+var ErrInvalidBillingEvent = errors.New("invalid billing event")
+
+// Kinds of billing events applied to the platform subscriptions
+const (
+	BillingEventInvoicePaid         = "invoice_paid"
+	BillingEventInvoiceFailed       = "invoice_failed"
+	BillingEventSubscriptionUpdated = "subscription_updated"
+)
+
+// SubscriptionSignup is a manager subscribed to the platform plan
+type SubscriptionSignup struct {
+	UserID     string
+	Email      string
+	Name       string
+	CustomerID string // Existing customer of the provider, empty to create one
+}
+
+// BillingSubscription is a subscription as created on the billing provider
+type BillingSubscription struct {
+	CustomerID       string
+	SubscriptionID   string
+	PriceID          string
+	Status           string // One of the model.SubscriptionStatus constants
+	CurrentPeriodEnd *time.Time
+	PaymentURL       string // Page to pay the first invoice
+}
+
+// BillingEvent is an invoice or subscription change reported by the billing provider
+type BillingEvent struct {
+	Kind             string // One of the BillingEvent constants
+	CustomerID       string
+	SubscriptionID   string
+	Status           string // Status of the subscription, only for subscription updates
+	CurrentPeriodEnd *time.Time
+	PaymentURL       string // Page to pay a failed invoice
+}
+
+// SubscriptionBillingProvider charges the monthly plan of the managers and reports invoice
+// payments and subscription changes through signed webhooks
+type SubscriptionBillingProvider interface {
+	// Name returns the identifier stored with the subscriptions
+	Name() string
+	// CreateSubscription subscribes a manager to the plan. The subscription stays incomplete until
+	// the first invoice is paid on its payment page.
+	CreateSubscription(ctx context.Context, signup SubscriptionSignup) (*BillingSubscription, error)
+	// ParseWebhook authenticates a webhook call and extracts the change it reports. It returns
+	// nil for events that do not affect the subscriptions.
+	ParseWebhook(header http.Header, body []byte) (*BillingEvent, error)
+}
+
+// GetSubscriptionBillingProvider returns the subscription billing provider configured in cfg
+func GetSubscriptionBillingProvider(cfg config.StripeConfig) (SubscriptionBillingProvider, error) {
+	return NewStripeBilling(cfg)
+}
+
+const (
+	// defaultStripeAPIURL is the Stripe API, the same for test and live keys
+	defaultStripeAPIURL = "https://api.stripe.com/v1"
+	// stripeSignatureTolerance is the maximum age of a signed webhook, against replays
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeBilling implements SubscriptionBillingProvider with Stripe Billing, which collects
+// cards from international managers and retries failed renewals
+type StripeBilling struct {
+	secretKey     string
+	priceID       string
+	webhookSecret string
+	apiURL        string
+	httpClient    *http.Client
+}
+
+// NewStripeBilling creates the Stripe billing provider from the STRIPE_* settings
+func NewStripeBilling(cfg config.StripeConfig) (*StripeBilling, error) {
+	secretKey := cfg.SecretKey
+	if secretKey == "" {
+		return nil, errors.New("STRIPE_SECRET_KEY is not configured")
+	}
+
+	priceID := cfg.PriceID
+	if priceID == "" {
+		return nil, errors.New("STRIPE_PRICE_ID is not configured")
+	}
+
+	webhookSecret := cfg.WebhookSecret
+	if webhookSecret == "" {
+		return nil, errors.New("STRIPE_WEBHOOK_SECRET is not configured")
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultStripeAPIURL
+	}
+
+	return &StripeBilling{
+		secretKey:     secretKey,
+		priceID:       priceID,
+		webhookSecret: webhookSecret,
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Name returns the provider identifier
+func (b *StripeBilling) Name() string {
+	return "stripe"
+}
+
+type stripeSubscription struct {
+	ID               string `json:"id"`
+	Customer         string `json:"customer"`
+	Status           string `json:"status"`
+	CurrentPeriodEnd int64  `json:"current_period_end"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+	LatestInvoice struct {
+		HostedInvoiceURL string `json:"hosted_invoice_url"`
+	} `json:"latest_invoice"`
+}
+
+// periodEnd returns the end of the paid period, moved to the items in recent API versions
+func (s stripeSubscription) periodEnd() *time.Time {
+	end := s.CurrentPeriodEnd
+	if end == 0 && len(s.Items.Data) > 0 {
+		end = s.Items.Data[0].CurrentPeriodEnd
+	}
+	if end == 0 {
+		return nil
+	}
+	t := time.Unix(end, 0)
+	return &t
+}
+
+// CreateSubscription creates the customer when needed and its subscription to the plan, whose
+// card is saved on the first payment to charge the renewals
+func (b *StripeBilling) CreateSubscription(ctx context.Context, signup SubscriptionSignup) (*BillingSubscription, error) {
+	customerID := signup.CustomerID
+	if customerID == "" {
+		var customer struct {
+			ID string `json:"id"`
+		}
+		form := url.Values{
+			"email":             {signup.Email},
+			"name":              {signup.Name},
+			"metadata[user_id]": {signup.UserID},
+		}
+		if err := b.post(ctx, "/customers", form, "customer-"+signup.UserID, &customer); err != nil {
+			return nil, err
+		}
+		customerID = customer.ID
+	}
+
+	var subscription stripeSubscription
+	form := url.Values{
+		"customer":          {customerID},
+		"items[0][price]":   {b.priceID},
+		"payment_behavior":  {"default_incomplete"},
+		"expand[]":          {"latest_invoice"},
+		"metadata[user_id]": {signup.UserID},
+		"payment_settings[save_default_payment_method]": {"on_subscription"},
+	}
+	idempotencyKey := fmt.Sprintf("subscription-%s-%d", signup.UserID, time.Now().Unix())
+	if err := b.post(ctx, "/subscriptions", form, idempotencyKey, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &BillingSubscription{
+		CustomerID:       customerID,
+		SubscriptionID:   subscription.ID,
+		PriceID:          b.priceID,
+		Status:           subscription.Status,
+		CurrentPeriodEnd: subscription.periodEnd(),
+		PaymentURL:       subscription.LatestInvoice.HostedInvoiceURL,
+	}, nil
+}
+
+type stripeEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeInvoice struct {
+	Customer         string `json:"customer"`
+	Subscription     string `json:"subscription"`
+	HostedInvoiceURL string `json:"hosted_invoice_url"`
+	Parent           struct {
+		SubscriptionDetails struct {
+			Subscription string `json:"subscription"`
+		} `json:"subscription_details"`
+	} `json:"parent"`
+}
+
+// ParseWebhook checks the Stripe-Signature header, an HMAC-SHA256 of the timestamp and the body
+// with the endpoint secret, and extracts invoice payments and subscription changes
+func (b *StripeBilling) ParseWebhook(header http.Header, body []byte) (*BillingEvent, error) {
+	if err := b.verifySignature(header.Get("Stripe-Signature"), body, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("error parsing Stripe event: %w", err)
+	}
+
+	switch event.Type {
+	case "invoice.paid", "invoice.payment_failed":
+		var invoice stripeInvoice
+		if err := json.Unmarshal(event.Data.Object, &invoice); err != nil {
+			return nil, fmt.Errorf("error parsing Stripe invoice: %w", err)
+		}
+		subscriptionID := invoice.Subscription
+		if subscriptionID == "" {
+			subscriptionID = invoice.Parent.SubscriptionDetails.Subscription
+		}
+		if subscriptionID == "" {
+			// One-off invoices are not about the plan
+			return nil, nil
+		}
+
+		kind := BillingEventInvoicePaid
+		if event.Type == "invoice.payment_failed" {
+			kind = BillingEventInvoiceFailed
+		}
+		return &BillingEvent{
+			Kind:           kind,
+			CustomerID:     invoice.Customer,
+			SubscriptionID: subscriptionID,
+			PaymentURL:     invoice.HostedInvoiceURL,
+		}, nil
+
+	case "customer.subscription.updated", "customer.subscription.deleted":
+		var subscription stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &subscription); err != nil {
+			return nil, fmt.Errorf("error parsing Stripe subscription: %w", err)
+		}
+		return &BillingEvent{
+			Kind:             BillingEventSubscriptionUpdated,
+			CustomerID:       subscription.Customer,
+			SubscriptionID:   subscription.ID,
+			Status:           subscription.Status,
+			CurrentPeriodEnd: subscription.periodEnd(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// verifySignature checks a Stripe-Signature header (t=timestamp,v1=signature,...)
+func (b *StripeBilling) verifySignature(signature string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return errors.New("Stripe event without signature")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Stripe signature timestamp: %w", err)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return errors.New("Stripe event signature is too old")
+	}
+
+	mac := hmac.New(sha256.New, []byte(b.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for _, candidate := range signatures {
+		decoded, err := hex.DecodeString(candidate)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return errors.New("invalid Stripe event signature")
+}
+
+// post sends a form-encoded request to the Stripe API and decodes its answer into out. The
+// idempotency key keeps a retried request from creating a second object.
+func (b *StripeBilling) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating Stripe request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading Stripe response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("Stripe API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error parsing Stripe response: %w", err)
+	}
+	return nil
+}
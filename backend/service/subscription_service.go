@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultSubscriptionCheckSchedule suspends the managers with overdue invoices every day at 6 AM
+	// when SUBSCRIPTION_CHECK_SCHEDULE is not set
+	defaultSubscriptionCheckSchedule = "0 6 * * *"
+	// defaultSubscriptionGraceDays is how long a failed invoice may stay unpaid before the manager
+	// is disabled, when SUBSCRIPTION_GRACE_DAYS is not set
+	defaultSubscriptionGraceDays = 7
+)
+
+var (
+	// ErrSubscriptionUserNotFound is returned when subscribing a user that does not exist
+	ErrSubscriptionUserNotFound = errors.New("user not found")
+	// ErrSubscriptionNotManager is returned when subscribing a user that does not manage properties
+	ErrSubscriptionNotManager = errors.New("only managers pay a platform subscription")
+	// ErrSubscriptionActive is returned when the user already has a subscription in force
+	ErrSubscriptionActive = errors.New("the user already has an active subscription")
+)
+
+// SubscriptionService bills the monthly plan of the managers and moves their users between
+// pending, active and disabled as the invoices are paid or left unpaid
+type SubscriptionService struct {
+	provider         SubscriptionBillingProvider
+	subscriptionRepo storage.PlatformSubscriptionStore
+	userRepo         storage.UserStore
+	personRepo       storage.PersonStore
+	graceDays        int
+	schedule         string
+	mu               sync.Mutex // Serializes the webhooks so retried events apply once
+}
+
+var subscriptions *SubscriptionService
+
+// InitializeSubscriptions creates the subscription billing service when the billing provider is
+// configured in cfg, returning nil otherwise. The overdue managers are suspended on schedule
+// (SUBSCRIPTION_CHECK_SCHEDULE).
+func InitializeSubscriptions(repoFactory *storage.RepositoryFactory, cfg config.SubscriptionConfig, schedule string) *SubscriptionService {
+	provider, err := GetSubscriptionBillingProvider(cfg.Stripe)
+	if err != nil {
+		slog.Warn("suscripciones de la plataforma no configuradas", "error", err)
+		return nil
+	}
+
+	graceDays := defaultSubscriptionGraceDays
+	if days := cfg.GraceDays; days != nil {
+		graceDays = *days
+	}
+
+	subscriptions = &SubscriptionService{
+		provider:         provider,
+		subscriptionRepo: repoFactory.GetPlatformSubscriptionRepository(),
+		userRepo:         repoFactory.GetUserRepository(),
+		personRepo:       repoFactory.GetPersonRepository(),
+		graceDays:        graceDays,
+		schedule:         schedule,
+	}
+	return subscriptions
+}
+
+// GetSubscriptions returns the subscription billing service, nil when it is not configured
+func GetSubscriptions() *SubscriptionService {
+	return subscriptions
+}
+
+// Subscribe subscribes a manager to the platform plan. The user stays pending until the first
+// invoice is paid on the returned payment page. An ended subscription is replaced, reusing the
+// customer of the provider.
+func (s *SubscriptionService) Subscribe(ctx context.Context, userID uuid.UUID) (*model.PlatformSubscription, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrSubscriptionUserNotFound
+	}
+	if user.Role != "manager" {
+		return nil, ErrSubscriptionNotManager
+	}
+
+	existing, err := s.subscriptionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	signup := SubscriptionSignup{UserID: userID.String(), Email: user.Email}
+	if existing != nil {
+		if !model.SubscriptionEnded(existing.Status) {
+			return nil, ErrSubscriptionActive
+		}
+		signup.CustomerID = existing.CustomerID
+	}
+	if person, err := s.personRepo.GetByID(ctx, user.PersonID); err == nil && person != nil {
+		signup.Name = person.FullName
+	}
+
+	created, err := s.provider.CreateSubscription(ctx, signup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the subscription: %w", err)
+	}
+
+	subscription := model.PlatformSubscription{
+		UserID:           userID,
+		Provider:         s.provider.Name(),
+		CustomerID:       created.CustomerID,
+		SubscriptionID:   created.SubscriptionID,
+		PriceID:          created.PriceID,
+		Status:           created.Status,
+		CurrentPeriodEnd: created.CurrentPeriodEnd,
+		PaymentURL:       created.PaymentURL,
+	}
+	if existing != nil {
+		subscription.ID = existing.ID
+		subscription.CreatedAt = existing.CreatedAt
+		// The suspension is kept so paying the new subscription enables the user again
+		subscription.SuspendedAt = existing.SuspendedAt
+	}
+
+	saved, err := s.subscriptionRepo.Save(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("subscribed", "component", "subscription", "email", user.Email, "subscription_id", saved.SubscriptionID, "status", saved.Status)
+	return saved, nil
+}
+
+// GetByUserID returns the subscription of a user, nil when there is none
+func (s *SubscriptionService) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.PlatformSubscription, error) {
+	return s.subscriptionRepo.GetByUserID(ctx, userID)
+}
+
+// GetAll returns all the platform subscriptions
+func (s *SubscriptionService) GetAll(ctx context.Context) ([]model.PlatformSubscription, error) {
+	return s.subscriptionRepo.GetAll(ctx)
+}
+
+// HandleWebhook applies an event of the billing provider to the subscription of its customer
+// and to the status of the user. Events of unknown customers are ignored.
+func (s *SubscriptionService) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBillingEvent, err)
+	}
+	if event == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, err := s.subscriptionRepo.GetByCustomerID(ctx, event.CustomerID)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		logging.FromContext(ctx).Warn("event of unknown customer ignored", "component", "subscription", "customer_id", event.CustomerID)
+		return nil
+	}
+	if event.SubscriptionID != subscription.SubscriptionID {
+		// Events of a replaced subscription of the same customer
+		logging.FromContext(ctx).Info("event of old subscription ignored", "component", "subscription", "subscription_id", event.SubscriptionID)
+		return nil
+	}
+
+	switch event.Kind {
+	case BillingEventInvoicePaid:
+		subscription.Status = model.SubscriptionStatusActive
+		subscription.PaymentURL = ""
+		subscription.PastDueSince = nil
+		if err := s.activateUser(ctx, subscription); err != nil {
+			return err
+		}
+
+	case BillingEventInvoiceFailed:
+		subscription.PaymentURL = event.PaymentURL
+		if subscription.PastDueSince == nil {
+			now := time.Now()
+			subscription.PastDueSince = &now
+		}
+		logging.FromContext(ctx).Warn("invoice of user failed", "component", "subscription", "user_id", subscription.UserID)
+
+	case BillingEventSubscriptionUpdated:
+		subscription.Status = event.Status
+		if event.CurrentPeriodEnd != nil {
+			subscription.CurrentPeriodEnd = event.CurrentPeriodEnd
+		}
+		if model.SubscriptionEnded(event.Status) {
+			if err := s.suspendUser(ctx, subscription); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.subscriptionRepo.Update(ctx, *subscription)
+}
+
+// SuspendOverdue disables the managers whose failed invoice stayed unpaid longer than the grace
+// period, returning how many were suspended
+func (s *SubscriptionService) SuspendOverdue(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pastDue, err := s.subscriptionRepo.GetPastDue(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deadline := time.Now().AddDate(0, 0, -s.graceDays)
+	suspended := 0
+	for i := range pastDue {
+		subscription := &pastDue[i]
+		if subscription.PastDueSince.After(deadline) {
+			continue
+		}
+		if err := s.suspendUser(ctx, subscription); err != nil {
+			logging.FromContext(ctx).Error("error suspending user", "component", "subscription", "user_id", subscription.UserID, "error", err)
+			continue
+		}
+		if err := s.subscriptionRepo.Update(ctx, *subscription); err != nil {
+			logging.FromContext(ctx).Error("error updating subscription of user", "component", "subscription", "user_id", subscription.UserID, "error", err)
+			continue
+		}
+		suspended++
+	}
+	return suspended, nil
+}
+
+// activateUser enables the user of a paid subscription when it was waiting for the first payment
+// or disabled for non-payment. Users disabled by an administrator stay disabled.
+func (s *SubscriptionService) activateUser(ctx context.Context, subscription *model.PlatformSubscription) error {
+	user, err := s.userRepo.GetByID(ctx, subscription.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	wasSuspended := subscription.SuspendedAt != nil
+	subscription.SuspendedAt = nil
+	if user.Status != "pending" && !(user.Status == "disabled" && wasSuspended) {
+		return nil
+	}
+
+	user.Status = "active"
+	if _, err := s.userRepo.Update(ctx, *user); err != nil {
+		return fmt.Errorf("failed to activate user %s: %w", user.Email, err)
+	}
+	logging.FromContext(ctx).Info("user activated after paying the subscription", "component", "subscription", "email", user.Email)
+	return nil
+}
+
+// suspendUser disables the active user of an unpaid subscription. Users that never paid are
+// already pending and stay so.
+func (s *SubscriptionService) suspendUser(ctx context.Context, subscription *model.PlatformSubscription) error {
+	if subscription.SuspendedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	subscription.SuspendedAt = &now
+
+	user, err := s.userRepo.GetByID(ctx, subscription.UserID)
+	if err != nil || user == nil || user.Status != "active" {
+		return err
+	}
+
+	user.Status = "disabled"
+	if _, err := s.userRepo.Update(ctx, *user); err != nil {
+		return fmt.Errorf("failed to disable user %s: %w", user.Email, err)
+	}
+	logging.FromContext(ctx).Info("user disabled for non-payment", "component", "subscription", "email", user.Email)
+	return nil
+}
+
+// Start schedules the daily suspension of the managers with overdue invoices
+// (SUBSCRIPTION_CHECK_SCHEDULE)
+func (s *SubscriptionService) Start() error {
+	schedule := s.schedule
+	if schedule == "" {
+		schedule = defaultSubscriptionCheckSchedule
+	}
+
+	c := cron.New(cron.WithLocation(AppLocation()))
+	_, err := c.AddFunc(schedule, func() {
+		if !JobEnabled("subscription_check") {
+			return
+		}
+		start := time.Now()
+		suspended, err := s.SuspendOverdue(context.Background())
+		metrics.JobRun("subscription_check", start, err)
+		if err != nil {
+			slog.Error("error checking overdue subscriptions", "component", "subscription", "error", err)
+		} else if suspended > 0 {
+			slog.Info("managers suspended for non-payment", "component", "subscription", "suspended", suspended)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid SUBSCRIPTION_CHECK_SCHEDULE %q: %w", schedule, err)
+	}
+	c.Start()
+	stopCronOnShutdown("subscription checker", c)
+
+	slog.Info("overdue subscription checker started, days of grace", "component", "subscription", "schedule", schedule, "grace_days", s.graceDays)
+	return nil
+}
@@ -2,202 +2,473 @@ package service
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
-	storage_go "github.com/supabase-community/storage-go"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
 )
 
-// SupabaseStorageService maneja almacenamiento de archivos en Supabase
+// SupabaseStorageService maneja el almacenamiento de archivos de los usuarios. Los archivos se
+// guardan en el FileStorage configurado: Supabase Storage o el disco local.
 type SupabaseStorageService struct {
-	client     *storage_go.Client
+	files      FileStorage
 	bucketName string
-	projectURL string
+	limits     *UploadLimitService
+	scanner    *VirusScanService
+	trash      *FileTrashService
+	index      *FileIndexService
+
+	urlTTL      time.Duration
+	shareTTL    time.Duration
+	shareSecret []byte
 }
 
 // SupabaseUploadResponse respuesta de subida a Supabase Storage
 type SupabaseUploadResponse struct {
-	Success    bool   `json:"success"`
-	Key        string `json:"key"`  // Nombre del archivo en Supabase
-	Link       string `json:"link"` // URL pública del archivo
-	Name       string `json:"name"` // Nombre original del archivo
-	Path       string `json:"path"` // Ruta completa en el bucket
-	Size       int64  `json:"size"` // Tamaño del archivo
-	UploadedBy string `json:"uploaded_by"`
-	UploadedAt string `json:"uploaded_at"`
-	BucketName string `json:"bucket_name"`
+	Success    bool     `json:"success"`
+	Key        string   `json:"key"`  // Nombre del archivo en Supabase
+	Link       string   `json:"link"` // URL pública del archivo
+	Name       string   `json:"name"` // Nombre original del archivo
+	Path       string   `json:"path"` // Ruta completa en el bucket
+	Size       int64    `json:"size"` // Tamaño del archivo
+	MimeType   string   `json:"mime_type"`
+	UploadedBy string   `json:"uploaded_by"`
+	UploadedAt string   `json:"uploaded_at"`
+	BucketName string   `json:"bucket_name"`
+	Category   string   `json:"category,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	RentalID   string   `json:"rental_id,omitempty"` // Arriendo al que se vinculó el archivo
+	ContractID string   `json:"contract_id,omitempty"`
 }
 
 // SupabaseFileInfo información de un archivo en Supabase
 type SupabaseFileInfo struct {
-	Name        string `json:"name"`
-	Size        int64  `json:"size"`
-	Path        string `json:"path"`
-	MimeType    string `json:"mime_type"`
-	UploadedAt  string `json:"uploaded_at"`
-	DownloadURL string `json:"download_url"`
+	Name        string   `json:"name"`
+	Size        int64    `json:"size"`
+	Path        string   `json:"path"`
+	MimeType    string   `json:"mime_type"`
+	UploadedAt  string   `json:"uploaded_at"`
+	DownloadURL string   `json:"download_url"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	RentalID    string   `json:"rental_id,omitempty"`
+	ContractID  string   `json:"contract_id,omitempty"`
 }
 
 var supabaseStorageService *SupabaseStorageService
 
-// InitializeSupabaseStorageService inicializa el servicio de Supabase Storage
-func InitializeSupabaseStorageService() error {
-	// Obtener configuración desde variables de entorno
-	projectURL := os.Getenv("SUPABASE_URL")
-	if projectURL == "" {
-		return fmt.Errorf("SUPABASE_URL no está configurada")
+// InitializeSupabaseStorageService inicializa el servicio de archivos con el backend de
+// FILE_STORAGE_BACKEND: supabase, local (FILE_STORAGE_DIR, ./data/files por defecto) o none
+// para deshabilitar las funciones de archivos. Sin configurar se usa Supabase Storage si hay
+// credenciales de Supabase y el disco local en otro caso.
+func InitializeSupabaseStorageService(cfg *config.Config) error {
+	supabaseStorageService = nil
+
+	bucketName := cfg.Supabase.StorageBucket
+	if bucketName == "" {
+		bucketName = "uploads" // Bucket por defecto
 	}
 
-	// Intentar usar service role key primero (si está disponible), luego anon key como fallback
-	apiKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	if apiKey == "" {
-		apiKey = os.Getenv("SUPABASE_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("SUPABASE_SERVICE_ROLE_KEY o SUPABASE_KEY debe estar configurada")
+	var files FileStorage
+	switch backend := fileStorageBackend(cfg.Files, cfg.Supabase); backend {
+	case FileStorageNone:
+		slog.Info("almacenamiento de archivos deshabilitado (FILE_STORAGE_BACKEND=none)")
+		return nil
+	case FileStorageLocal:
+		dir := cfg.Files.Dir
+		if dir == "" {
+			dir = filepath.Join("data", "files")
 		}
-		log.Printf("⚠️ Usando SUPABASE_KEY (anon key) - Asegúrate de que las políticas de storage estén configuradas")
-	} else {
-		log.Printf("✅ Usando SUPABASE_SERVICE_ROLE_KEY (service role key)")
+		local, err := newLocalFileStorage(dir)
+		if err != nil {
+			return err
+		}
+		slog.Info("almacenamiento local", "root", local.root)
+		files = local
+	case FileStorageSupabase:
+		supabase, err := newSupabaseFileStorage(cfg.Supabase)
+		if err != nil {
+			return err
+		}
+		files = supabase
+	default:
+		return fmt.Errorf("FILE_STORAGE_BACKEND desconocido: %s", backend)
 	}
 
-	bucketName := os.Getenv("SUPABASE_STORAGE_BUCKET")
-	if bucketName == "" {
-		bucketName = "uploads" // Bucket por defecto
+	// Las métricas cuentan los bytes que llegan al backend, cifrados si el cifrado está habilitado
+	files, err := withFileEncryption(meteredFileStorage{FileStorage: files}, cfg.Files.Encryption)
+	if err != nil {
+		return err
 	}
 
-	// Inicializar cliente de Supabase Storage
-	storageURL := fmt.Sprintf("%s/storage/v1", projectURL)
-	client := storage_go.NewClient(storageURL, apiKey, nil)
-
 	// Verificar si el bucket existe, si no, crearlo
-	if err := ensureBucketExists(client, bucketName); err != nil {
+	if err := files.EnsureBucket(bucketName); err != nil {
 		return fmt.Errorf("error configurando bucket: %v", err)
 	}
 
 	supabaseStorageService = &SupabaseStorageService{
-		client:     client,
-		bucketName: bucketName,
-		projectURL: projectURL,
+		files:       files,
+		bucketName:  bucketName,
+		urlTTL:      configuredTTL("FILE_URL_TTL", cfg.Files.URLTTL, DefaultFileURLTTL),
+		shareTTL:    configuredTTL("FILE_SHARE_TTL", cfg.Files.ShareTTL, DefaultFileShareTTL),
+		shareSecret: []byte(cfg.Secrets.FileShare),
 	}
 
-	log.Printf("✅ Servicio de Supabase Storage inicializado")
-	log.Printf("📦 Bucket: %s", bucketName)
-	log.Printf("🌐 Storage URL: %s", storageURL)
+	slog.Info("servicio de archivos inicializado", "name", files.Name(), "bucket", bucketName)
 
 	return nil
 }
 
-// GetSupabaseStorageService obtiene la instancia del servicio
+// GetSupabaseStorageService obtiene la instancia del servicio, nil si el almacenamiento de
+// archivos no está disponible
 func GetSupabaseStorageService() *SupabaseStorageService {
 	return supabaseStorageService
 }
 
-// ensureBucketExists verifica si el bucket existe, si no lo crea
-func ensureBucketExists(client *storage_go.Client, bucketName string) error {
-	// Intentar obtener el bucket
-	_, err := client.GetBucket(bucketName)
-	if err != nil {
-		// Si el bucket no existe, crearlo
-		log.Printf("📦 Creando bucket: %s", bucketName)
-		_, err = client.CreateBucket(bucketName, storage_go.BucketOptions{
-			Public: false, // Bucket privado por seguridad
-		})
+// Backend devuelve el nombre del backend de almacenamiento en uso
+func (s *SupabaseStorageService) Backend() string {
+	return s.files.Name()
+}
+
+// CheckAccess verifica que el bucket se puede leer listando su primer elemento
+func (s *SupabaseStorageService) CheckAccess() error {
+	_, err := s.files.List(s.bucketName, "", 1, 0)
+	return err
+}
+
+// Encrypted indica si los archivos se cifran antes de guardarlos
+func (s *SupabaseStorageService) Encrypted() bool {
+	_, ok := s.files.(*encryptedFileStorage)
+	return ok
+}
+
+// SetFileTrash define la papelera a la que van los archivos eliminados. Sin ella, o con la
+// papelera deshabilitada, los archivos se eliminan de inmediato.
+func (s *SupabaseStorageService) SetFileTrash(trash *FileTrashService) {
+	s.trash = trash
+}
+
+// FileTrash devuelve la papelera de archivos, nil si no está configurada
+func (s *SupabaseStorageService) FileTrash() *FileTrashService {
+	return s.trash
+}
+
+// SetFileIndex define el índice que usan los listados de archivos. Sin él los listados recorren
+// las carpetas del almacenamiento en cada petición.
+func (s *SupabaseStorageService) SetFileIndex(index *FileIndexService) {
+	s.index = index
+}
+
+// FileIndex devuelve el índice de archivos, nil si no está configurado
+func (s *SupabaseStorageService) FileIndex() *FileIndexService {
+	return s.index
+}
+
+// SetUploadLimits define el servicio con los límites de subida configurados por un admin.
+// Sin él se aplican los límites de las variables de entorno.
+func (s *SupabaseStorageService) SetUploadLimits(limits *UploadLimitService) {
+	s.limits = limits
+}
+
+// SetVirusScanner define el servicio que analiza los archivos subidos antes de publicarlos y
+// crea el bucket de cuarentena si no existe
+func (s *SupabaseStorageService) SetVirusScanner(scanner *VirusScanService) error {
+	s.scanner = scanner
+	if !scanner.Enabled() {
+		return nil
+	}
+	if err := s.files.EnsureBucket(scanner.QuarantineBucket()); err != nil {
+		return fmt.Errorf("error configurando bucket de cuarentena: %v", err)
+	}
+	slog.Info("análisis de virus habilitado, cuarentena en el bucket", "name", scanner.scanner.Name(), "quarantine_bucket", scanner.QuarantineBucket())
+	return nil
+}
+
+// UploadLimitFor devuelve el límite de subida vigente para un rol
+func (s *SupabaseStorageService) UploadLimitFor(role string) model.UploadLimit {
+	return s.limits.LimitFor(context.Background(), role)
+}
+
+// UserStorageUsage suma el tamaño y la cantidad de los archivos de la carpeta de un usuario
+func (s *SupabaseStorageService) UserStorageUsage(userID string) (int64, int, error) {
+	userFolder := fmt.Sprintf("user_%s", userID)
+
+	const pageSize = 100
+	used := int64(0)
+	count := 0
+	for offset := 0; ; offset += pageSize {
+		files, err := s.files.List(s.bucketName, userFolder, pageSize, offset)
 		if err != nil {
-			return fmt.Errorf("error creando bucket: %v", err)
+			return 0, 0, fmt.Errorf("error listando archivos: %v", err)
+		}
+		for _, file := range files {
+			used += file.Size
+			count++
 		}
-		log.Printf("✅ Bucket creado exitosamente: %s", bucketName)
+		if len(files) < pageSize {
+			break
+		}
+	}
+	return used, count, nil
+}
+
+// Usage devuelve el espacio usado por un usuario junto con los límites de su rol
+func (s *SupabaseStorageService) Usage(userID, role string) (*StorageUsage, error) {
+	used, count, err := s.UserStorageUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+	return newStorageUsage(userID, s.UploadLimitFor(role), used, count), nil
+}
+
+// UsageByUser agrupa por usuario el espacio usado en un listado de archivos del bucket.
+// roles asocia el ID de cada usuario con su rol para aplicar sus límites.
+func (s *SupabaseStorageService) UsageByUser(files []SupabaseFileInfo, roles map[string]string) []*StorageUsage {
+	type totals struct {
+		used  int64
+		count int
+	}
+	byUser := make(map[string]*totals)
+	var userIDs []string
+	for _, file := range files {
+		userID := s.extractUserIDFromPath(file.Path)
+		if userID == "unknown" {
+			continue
+		}
+		if byUser[userID] == nil {
+			byUser[userID] = &totals{}
+			userIDs = append(userIDs, userID)
+		}
+		byUser[userID].used += file.Size
+		byUser[userID].count++
+	}
+
+	usage := make([]*StorageUsage, 0, len(userIDs))
+	for _, userID := range userIDs {
+		usage = append(usage, newStorageUsage(userID, s.UploadLimitFor(roles[userID]), byUser[userID].used, byUser[userID].count))
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].UsedBytes > usage[j].UsedBytes
+	})
+	return usage
+}
+
+// CheckUploadAllowed verifica que un archivo de size bytes no supere el tamaño máximo del rol
+// ni la cuota del usuario. Devuelve un *UploadLimitError cuando la subida no está permitida.
+func (s *SupabaseStorageService) CheckUploadAllowed(userID, role string, size int64) error {
+	limit := s.UploadLimitFor(role)
+
+	if maxSize := limit.MaxFileSize(); maxSize > 0 && size > maxSize {
+		return &UploadLimitError{Code: UploadLimitFileTooLarge, Limit: maxSize, Size: size}
+	}
+
+	quota := limit.Quota()
+	if quota <= 0 {
+		return nil
+	}
+	used, _, err := s.UserStorageUsage(userID)
+	if err != nil {
+		return fmt.Errorf("error calculando el espacio usado: %v", err)
+	}
+	if used+size > quota {
+		return &UploadLimitError{Code: UploadLimitQuotaExceeded, Limit: quota, Used: used, Size: size}
 	}
 	return nil
 }
 
-// UploadFile sube un archivo a Supabase Storage
-func (s *SupabaseStorageService) UploadFile(file multipart.File, header *multipart.FileHeader, userID, userName string) (*SupabaseUploadResponse, error) {
-	log.Printf("📤 Subiendo archivo a Supabase: %s (%.2f KB)", header.Filename, float64(header.Size)/1024)
+// UploadFile sube un archivo al almacenamiento
+func (s *SupabaseStorageService) UploadFile(file multipart.File, header *multipart.FileHeader, userID, userName, userRole string) (*SupabaseUploadResponse, error) {
+	// El archivo se envía directamente sin cargarlo completo en memoria
+	return s.UploadStream(file, header.Filename, header.Size, header.Header.Get("Content-Type"), userID, userName, userRole)
+}
+
+// UploadStream sube el contenido de un reader a la carpeta del usuario en el almacenamiento.
+// El contenido se transmite a medida que se lee, sin cargarlo en memoria.
+// Antes de subirlo se aplican el tamaño máximo y la cuota del rol del usuario. Con el análisis
+// de virus habilitado el archivo se sube al bucket de cuarentena y solo pasa a la carpeta del
+// usuario si está limpio. El tipo MIME se verifica con los primeros bytes del contenido y es el
+// que se guarda, en lugar del declarado por el cliente.
+func (s *SupabaseStorageService) UploadStream(reader io.Reader, originalName string, size int64, contentType, userID, userName, userRole string) (*SupabaseUploadResponse, error) {
+	slog.Info("subiendo archivo", "original_name", originalName, "size_kb", float64(size)/1024)
+
+	if err := s.CheckUploadAllowed(userID, userRole, size); err != nil {
+		return nil, err
+	}
+
+	declaredType := contentType
+	contentType, reader, err := VerifyFileType(originalName, reader)
+	if err != nil {
+		return nil, err
+	}
+	if declaredType != "" && declaredType != contentType {
+		slog.Info("tipo declarado, tipo verificado", "declared_type", declaredType, "content_type", contentType, "original_name", originalName)
+	}
 
 	// Crear ruta del archivo en el bucket
-	fileName := fmt.Sprintf("%s_%d_%s", userID, time.Now().Unix(), header.Filename)
+	fileName := fmt.Sprintf("%s_%d_%s", userID, time.Now().Unix(), originalName)
 	filePath := fmt.Sprintf("user_%s/%s", userID, fileName)
 
-	// Leer el contenido del archivo
-	fileContent, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("error leyendo archivo: %v", err)
+	bucket := s.bucketName
+	if s.scanner.Enabled() {
+		bucket = s.scanner.QuarantineBucket()
 	}
 
-	// Convertir []byte a io.Reader
-	fileReader := bytes.NewReader(fileContent)
+	// Subir archivo al almacenamiento
+	if err := s.files.Upload(bucket, filePath, reader, contentType, false); err != nil {
+		return nil, fmt.Errorf("error subiendo archivo: %v", err)
+	}
 
-	// Subir archivo a Supabase Storage
-	uploadResult, err := s.client.UploadFile(s.bucketName, filePath, fileReader)
-	if err != nil {
-		return nil, fmt.Errorf("error subiendo archivo a Supabase: %v", err)
+	if s.scanner.Enabled() {
+		if err := s.scanQuarantined(filePath, originalName, size, userID, userName); err != nil {
+			return nil, err
+		}
 	}
 
-	// Generar URL pública para descargar el archivo
-	publicURL := s.client.GetPublicUrl(s.bucketName, filePath)
+	s.index.add(context.Background(), filePath, size, contentType, time.Now())
 
 	// Crear respuesta
 	response := &SupabaseUploadResponse{
 		Success:    true,
-		Key:        uploadResult.Key,
-		Link:       publicURL.SignedURL,
-		Name:       header.Filename,
+		Key:        fmt.Sprintf("%s/%s", s.bucketName, filePath),
+		Link:       s.downloadURL(filePath),
+		Name:       originalName,
 		Path:       filePath,
-		Size:       header.Size,
+		Size:       size,
+		MimeType:   contentType,
 		UploadedBy: userName,
 		UploadedAt: time.Now().Format(time.RFC3339),
 		BucketName: s.bucketName,
 	}
 
-	log.Printf("✅ Archivo subido exitosamente a Supabase: %s", filePath)
-	log.Printf("📤 UPLOAD DETAILS: Key='%s', Path='%s', Name='%s'", response.Key, response.Path, response.Name)
+	slog.Info("archivo subido exitosamente", "file_path", filePath)
+	slog.Debug("archivo subido", "key", response.Key, "path", response.Path, "name", response.Name)
 	return response, nil
 }
 
-// DownloadFile descarga un archivo de Supabase Storage
+// scanQuarantined analiza un archivo del bucket de cuarentena. Si está limpio lo pasa a la
+// carpeta del usuario; si está infectado lo deja en cuarentena y avisa a los administradores.
+// Cuando el análisis falla el archivo se descarta, salvo con VIRUS_SCAN_FAIL_OPEN.
+func (s *SupabaseStorageService) scanQuarantined(filePath, originalName string, size int64, userID, userName string) error {
+	ctx := context.Background()
+	quarantine := s.scanner.QuarantineBucket()
+
+	scan := model.FileScan{
+		UserID:   userID,
+		FileName: originalName,
+		Path:     filePath,
+		Size:     size,
+	}
+
+	content, err := s.files.Open(quarantine, filePath)
+	if err == nil {
+		scan, err = s.scanner.scan(ctx, content, scan)
+		content.Close()
+	} else {
+		slog.Warn("error leyendo de cuarentena para analizarlo", "file_path", filePath, "error", err)
+	}
+
+	switch {
+	case err != nil && !s.scanner.failOpen:
+		if removeErr := s.files.Remove(quarantine, []string{filePath}); removeErr != nil {
+			slog.Warn("error eliminando de cuarentena", "file_path", filePath, "error", removeErr)
+		}
+		return ErrVirusScanUnavailable
+	case err != nil:
+		slog.Warn("publicando sin análisis (VIRUS_SCAN_FAIL_OPEN)", "file_path", filePath)
+	case scan.Result == model.FileScanInfected:
+		go s.scanner.notifyRejected(ctx, scan, userName)
+		return &FileRejectedError{FileName: originalName, Signature: scan.Signature}
+	}
+
+	if err := s.files.Move(quarantine, filePath, s.bucketName); err != nil {
+		return fmt.Errorf("error publicando archivo analizado: %v", err)
+	}
+	return nil
+}
+
+// UploadBytes sube contenido generado por el sistema (p. ej. contratos firmados) a una ruta fija del bucket
+func (s *SupabaseStorageService) UploadBytes(filePath string, data []byte, contentType string) (*SupabaseUploadResponse, error) {
+	slog.Info("subiendo archivo generado", "file_path", filePath, "size_kb", float64(len(data))/1024)
+
+	if err := s.files.Upload(s.bucketName, filePath, bytes.NewReader(data), contentType, true); err != nil {
+		return nil, fmt.Errorf("error subiendo archivo: %v", err)
+	}
+	s.index.add(context.Background(), filePath, int64(len(data)), contentType, time.Now())
+
+	slog.Info("archivo generado subido exitosamente", "file_path", filePath)
+	return &SupabaseUploadResponse{
+		Success:    true,
+		Key:        fmt.Sprintf("%s/%s", s.bucketName, filePath),
+		Link:       s.downloadURL(filePath),
+		Name:       filepath.Base(filePath),
+		Path:       filePath,
+		Size:       int64(len(data)),
+		UploadedBy: "system",
+		UploadedAt: time.Now().Format(time.RFC3339),
+		BucketName: s.bucketName,
+	}, nil
+}
+
+// DownloadFile descarga un archivo del almacenamiento
 func (s *SupabaseStorageService) DownloadFile(filePath string) ([]byte, error) {
-	log.Printf("📥 Descargando archivo de Supabase: %s", filePath)
+	slog.Info("descargando archivo", "file_path", filePath)
 
 	// Si no contiene un slash, intentar resolver la ruta completa
 	if !strings.Contains(filePath, "/") {
-		log.Printf("🔍 Ruta sin carpeta detectada, buscando archivo: %s", filePath)
+		slog.Debug("ruta sin carpeta detectada, buscando archivo", "file_path", filePath)
 		resolvedPath, err := s.resolveFilePath(filePath)
 		if err != nil {
 			return nil, fmt.Errorf("error resolviendo ruta del archivo: %v", err)
 		}
 		filePath = resolvedPath
-		log.Printf("✅ Ruta resuelta: %s", filePath)
+		slog.Info("ruta resuelta", "file_path", filePath)
 	}
 
 	// Descargar archivo
-	fileData, err := s.client.DownloadFile(s.bucketName, filePath)
+	fileData, err := s.files.Download(s.bucketName, filePath)
 	if err != nil {
 		return nil, fmt.Errorf("error descargando archivo: %v", err)
 	}
 
-	log.Printf("✅ Archivo descargado exitosamente: %s", filePath)
+	slog.Info("archivo descargado exitosamente", "file_path", filePath)
 	return fileData, nil
 }
 
-// DownloadAndDeleteFile descarga un archivo, lo respalda en Telegram y luego lo elimina (para admin)
-func (s *SupabaseStorageService) DownloadAndDeleteFile(filePath string) ([]byte, error) {
-	log.Printf("📥🗑️ Descargando, respaldando y eliminando archivo: %s", filePath)
+// OpenFile abre un archivo del almacenamiento para leerlo sin cargarlo en memoria
+func (s *SupabaseStorageService) OpenFile(filePath string) (io.ReadCloser, error) {
+	if !strings.Contains(filePath, "/") {
+		resolvedPath, err := s.resolveFilePath(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error resolviendo ruta del archivo: %v", err)
+		}
+		filePath = resolvedPath
+	}
+	return s.files.Open(s.bucketName, filePath)
+}
+
+// DownloadAndDeleteFile descarga un archivo, lo respalda en los proveedores de backup
+// configurados y luego lo elimina o lo mueve a la papelera (para admin)
+func (s *SupabaseStorageService) DownloadAndDeleteFile(filePath, deletedBy string) ([]byte, error) {
+	slog.Info("descargando, respaldando y eliminando archivo", "file_path", filePath)
 
 	// Si no contiene un slash, intentar resolver la ruta completa
 	if !strings.Contains(filePath, "/") {
-		log.Printf("🔍 Ruta sin carpeta detectada, buscando archivo: %s", filePath)
+		slog.Debug("ruta sin carpeta detectada, buscando archivo", "file_path", filePath)
 		resolvedPath, err := s.resolveFilePath(filePath)
 		if err != nil {
 			return nil, fmt.Errorf("error resolviendo ruta del archivo: %v", err)
 		}
 		filePath = resolvedPath
-		log.Printf("✅ Ruta resuelta: %s", filePath)
+		slog.Info("ruta resuelta", "file_path", filePath)
 	}
 
 	// Primero descargar el archivo
@@ -210,35 +481,30 @@ func (s *SupabaseStorageService) DownloadAndDeleteFile(filePath string) ([]byte,
 	fileName := filepath.Base(filePath)
 	userID := s.extractUserIDFromPath(filePath)
 
-	// Intentar respaldar en Telegram antes de eliminar (solo si está habilitado)
-	if IsTelegramEnabled() {
-		telegramService := GetTelegramService()
-		if telegramService != nil {
-			log.Printf("📤 Respaldando archivo en Telegram antes de eliminar...")
-			backup, err := telegramService.BackupFileToTelegram(fileData, fileName, filePath, userID)
-			if err != nil {
-				log.Printf("⚠️ Error respaldando archivo en Telegram: %v", err)
-				// Enviar notificación de error
-				telegramService.SendBackupError(fileName, userID, err.Error())
-			} else {
-				log.Printf("✅ Archivo respaldado exitosamente en Telegram (File ID: %s)", backup.FileID)
-				// Enviar notificación de éxito
-				telegramService.SendBackupNotification(fileName, userID, backup.FileSize)
-			}
-		} else {
-			log.Printf("⚠️ Servicio de Telegram no disponible, continuando sin backup")
+	// Respaldar el archivo en los proveedores de backup configurados antes de eliminarlo
+	backups := GetFileBackups()
+	if backups.Enabled() {
+		err := backups.Run(context.Background(), BackupFile{
+			Data:     fileData,
+			FileName: fileName,
+			Path:     filePath,
+			UserID:   userID,
+		})
+		if err != nil && backups.Required() {
+			slog.Warn("el archivo no se elimina porque no se pudo respaldar (FILE_BACKUP_REQUIRED)", "file_path", filePath)
+			return fileData, nil
 		}
 	} else {
-		log.Printf("ℹ️ Backup de Telegram deshabilitado por feature flag, continuando sin backup")
+		slog.Info("sin proveedores de backup, continuando sin backup")
 	}
 
-	// Luego eliminar el archivo de Supabase
-	err = s.DeleteFile(filePath)
+	// Luego eliminar el archivo del almacenamiento
+	_, err = s.DeleteFile(filePath, deletedBy)
 	if err != nil {
-		log.Printf("⚠️ Error eliminando archivo después de descarga: %v", err)
+		slog.Warn("error eliminando archivo después de descarga", "error", err)
 		// No retornar error aquí ya que la descarga fue exitosa
 	} else {
-		log.Printf("🗑️ Archivo eliminado exitosamente de Supabase después de descarga: %s", filePath)
+		slog.Info("archivo eliminado exitosamente después de descarga", "file_path", filePath)
 	}
 
 	return fileData, nil
@@ -259,265 +525,237 @@ func (s *SupabaseStorageService) extractUserIDFromPath(filePath string) string {
 	return "unknown"
 }
 
-// DeleteFile elimina un archivo de Supabase Storage
-func (s *SupabaseStorageService) DeleteFile(filePath string) error {
-	log.Printf("🗑️ Eliminando archivo de Supabase: %s", filePath)
+// DeleteFile elimina un archivo del almacenamiento. Con la papelera habilitada el archivo se
+// mueve a ella y se devuelve su registro; si no, se elimina y se devuelve nil.
+func (s *SupabaseStorageService) DeleteFile(filePath, deletedBy string) (*model.TrashedFile, error) {
+	slog.Info("eliminando archivo", "file_path", filePath)
 
 	// Si no contiene un slash, intentar resolver la ruta completa
 	if !strings.Contains(filePath, "/") {
-		log.Printf("🔍 Ruta sin carpeta detectada, buscando archivo: %s", filePath)
+		slog.Debug("ruta sin carpeta detectada, buscando archivo", "file_path", filePath)
 		resolvedPath, err := s.resolveFilePath(filePath)
 		if err != nil {
-			return fmt.Errorf("error resolviendo ruta del archivo: %v", err)
+			return nil, fmt.Errorf("error resolviendo ruta del archivo: %v", err)
 		}
 		filePath = resolvedPath
-		log.Printf("✅ Ruta resuelta: %s", filePath)
+		slog.Info("ruta resuelta", "file_path", filePath)
+	}
+
+	if s.trash.Enabled() && !isTrashPath(filePath) {
+		trashed, err := s.trash.trash(context.Background(), s, filePath, deletedBy)
+		if err == nil {
+			s.index.remove(context.Background(), filePath)
+		}
+		return trashed, err
 	}
 
 	// Eliminar archivo
-	_, err := s.client.RemoveFile(s.bucketName, []string{filePath})
-	if err != nil {
-		return fmt.Errorf("error eliminando archivo: %v", err)
+	if err := s.files.Remove(s.bucketName, []string{filePath}); err != nil {
+		return nil, fmt.Errorf("error eliminando archivo: %v", err)
 	}
+	s.index.remove(context.Background(), filePath)
 
-	log.Printf("✅ Archivo eliminado exitosamente: %s", filePath)
-	return nil
+	slog.Info("archivo eliminado exitosamente", "file_path", filePath)
+	return nil, nil
 }
 
 // ListUserFiles lista archivos de un usuario específico
 func (s *SupabaseStorageService) ListUserFiles(userID string) ([]SupabaseFileInfo, error) {
-	log.Printf("📋 Listando archivos del usuario: %s", userID)
+	slog.Info("listando archivos del usuario", "user_id", userID)
 
-	// Listar archivos en la carpeta del usuario
-	userFolder := fmt.Sprintf("user_%s", userID)
-	files, err := s.client.ListFiles(s.bucketName, userFolder, storage_go.FileSearchOptions{
-		Limit:  100,
-		Offset: 0,
+	fileInfos, err := s.listFiles(userID, func() ([]SupabaseFileInfo, error) {
+		return s.scanUserFiles(userID)
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error listando archivos: %v", err)
+		return nil, err
 	}
 
-	var fileInfos []SupabaseFileInfo
-	for _, file := range files {
-		// Generar URL de descarga para cada archivo
-		publicURL := s.client.GetPublicUrl(s.bucketName, file.Name)
-
-		// Obtener tamaño del archivo desde metadata
-		size := int64(0)
-		if file.Metadata != nil {
-			if metadata, ok := file.Metadata.(map[string]interface{}); ok {
-				if sizeValue, exists := metadata["size"]; exists {
-					if sizeFloat, ok := sizeValue.(float64); ok {
-						size = int64(sizeFloat)
-					}
-				}
-			}
-		}
+	slog.Info("encontrados archivos para el usuario", "file_infos_count", len(fileInfos), "user_id", userID)
+	return fileInfos, nil
+}
 
-		// Obtener tipo MIME desde metadata
-		mimeType := ""
-		if file.Metadata != nil {
-			if metadata, ok := file.Metadata.(map[string]interface{}); ok {
-				if typeValue, exists := metadata["mimetype"]; exists {
-					if typeStr, ok := typeValue.(string); ok {
-						mimeType = typeStr
-					}
-				}
+// ListAllFiles lista todos los archivos en el bucket (solo para admins)
+func (s *SupabaseStorageService) ListAllFiles() ([]SupabaseFileInfo, error) {
+	slog.Info("listando todos los archivos del bucket", "bucket_name", s.bucketName)
+	return s.listFiles("", s.scanAllFiles)
+}
+
+// listFiles devuelve los archivos de un usuario, o de todo el bucket con userID vacío, con sus
+// enlaces de descarga. Se toman de memoria si el listado sigue vigente, si no del índice, y solo
+// mientras el índice no está disponible se recorren las carpetas del almacenamiento con scan.
+func (s *SupabaseStorageService) listFiles(userID string, scan func() ([]SupabaseFileInfo, error)) ([]SupabaseFileInfo, error) {
+	files, ok := s.index.cached(userID)
+	if !ok {
+		var err error
+		files, err = s.index.list(context.Background(), userID)
+		if err != nil {
+			if s.index != nil && !errors.Is(err, errFileIndexNotReady) {
+				slog.Warn("error leyendo el índice de archivos, se recorre el almacenamiento", "error", err)
+			}
+			if files, err = scan(); err != nil {
+				return nil, err
 			}
 		}
+		s.index.store(userID, files)
+	}
 
-		fileInfo := SupabaseFileInfo{
-			Name:        filepath.Base(file.Name),
-			Size:        size,
-			Path:        file.Name,
-			MimeType:    mimeType,
-			UploadedAt:  file.CreatedAt,
-			DownloadURL: publicURL.SignedURL,
-		}
-		fileInfos = append(fileInfos, fileInfo)
+	// Los enlaces se firman en cada listado para que no venzan mientras el listado está en memoria
+	signed := append([]SupabaseFileInfo(nil), files...)
+	s.signFileInfos(signed)
+	return signed, nil
+}
+
+// scanUserFiles recorre la carpeta de un usuario, sin enlaces de descarga
+func (s *SupabaseStorageService) scanUserFiles(userID string) ([]SupabaseFileInfo, error) {
+	userFolder := fmt.Sprintf("user_%s", userID)
+	files, err := s.listFolder(userFolder)
+	if err != nil {
+		return nil, fmt.Errorf("error listando archivos: %v", err)
 	}
 
-	log.Printf("📋 Encontrados %d archivos para el usuario %s", len(fileInfos), userID)
+	var fileInfos []SupabaseFileInfo
+	for _, file := range files {
+		fileInfos = append(fileInfos, s.createFileInfo(userFolder, file))
+	}
 	return fileInfos, nil
 }
 
-// ListAllFiles lista todos los archivos en el bucket (solo para admins)
-func (s *SupabaseStorageService) ListAllFiles() ([]SupabaseFileInfo, error) {
-	log.Printf("📋 Listando todos los archivos del bucket: %s", s.bucketName)
+// listFolder lista todos los archivos de una carpeta del bucket, por páginas
+func (s *SupabaseStorageService) listFolder(folder string) ([]StoredFile, error) {
+	const pageSize = 100
+	var all []StoredFile
+	for offset := 0; ; offset += pageSize {
+		files, err := s.files.List(s.bucketName, folder, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, files...)
+		if len(files) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// scanAllFiles recorre las carpetas de usuario y la raíz del bucket, sin enlaces de descarga
+func (s *SupabaseStorageService) scanAllFiles() ([]SupabaseFileInfo, error) {
+	slog.Debug("recorriendo las carpetas del bucket", "bucket_name", s.bucketName)
 
 	// Primero listar carpetas/directorios
-	folders, err := s.client.ListFiles(s.bucketName, "", storage_go.FileSearchOptions{
-		Limit:  1000,
-		Offset: 0,
-	})
+	folders, err := s.files.List(s.bucketName, "", 1000, 0)
 	if err != nil {
 		return nil, fmt.Errorf("error listando carpetas: %v", err)
 	}
 
-	log.Printf("🔍 DEBUG: Encontrados %d elementos en el bucket", len(folders))
+	slog.Debug("encontrados elementos en el bucket", "folders_count", len(folders))
 
 	var allFileInfos []SupabaseFileInfo
 
 	// Procesar cada elemento encontrado
 	for _, folder := range folders {
-		log.Printf("📁 DEBUG ListAllFiles: Name='%s', ID='%s'", folder.Name, folder.Id)
+		slog.Debug("listando carpeta", "name", folder.Name)
 
 		// Si es una carpeta (user_xxx), listar archivos dentro de ella
 		if strings.HasPrefix(folder.Name, "user_") && !strings.Contains(folder.Name, ".") {
-			log.Printf("📂 Explorando carpeta de usuario: %s", folder.Name)
+			slog.Info("explorando carpeta de usuario", "name", folder.Name)
 
 			// Listar archivos dentro de esta carpeta de usuario
-			userFiles, err := s.client.ListFiles(s.bucketName, folder.Name, storage_go.FileSearchOptions{
-				Limit:  100,
-				Offset: 0,
-			})
+			userFiles, err := s.listFolder(folder.Name)
 			if err != nil {
-				log.Printf("⚠️ Error listando archivos en carpeta %s: %v", folder.Name, err)
+				slog.Warn("error listando archivos en carpeta", "name", folder.Name, "error", err)
 				continue
 			}
 
-			log.Printf("📄 Encontrados %d archivos en carpeta %s", len(userFiles), folder.Name)
+			slog.Info("encontrados archivos en carpeta", "user_files_count", len(userFiles), "name", folder.Name)
 
 			// Procesar archivos de esta carpeta
 			for _, file := range userFiles {
-				log.Printf("📄 DEBUG UserFile: Name='%s', ID='%s'", file.Name, file.Id)
+				slog.Debug("archivo de usuario", "name", file.Name)
 
 				// Solo procesar archivos reales (que tengan extensión)
 				if !strings.Contains(file.Name, ".") {
-					log.Printf("⏭️ Saltando elemento sin extensión: %s", file.Name)
+					slog.Info("saltando elemento sin extensión", "name", file.Name)
 					continue
 				}
 
 				// Crear FileInfo para este archivo
-				fileInfo := s.createFileInfo(file)
+				fileInfo := s.createFileInfo(folder.Name, file)
 				allFileInfos = append(allFileInfos, fileInfo)
 			}
 		} else if strings.Contains(folder.Name, ".") {
 			// Es un archivo directo en la raíz
-			log.Printf("📄 Archivo en raíz: %s", folder.Name)
-			fileInfo := s.createFileInfo(folder)
+			slog.Info("archivo en raíz", "name", folder.Name)
+			fileInfo := s.createFileInfo("", folder)
 			allFileInfos = append(allFileInfos, fileInfo)
 		} else {
-			log.Printf("⏭️ Saltando elemento: %s", folder.Name)
+			slog.Info("saltando elemento", "name", folder.Name)
 		}
 	}
 
-	log.Printf("📋 Encontrados %d archivos totales en el bucket", len(allFileInfos))
+	slog.Info("encontrados archivos totales en el bucket", "all_file_infos_count", len(allFileInfos))
 	return allFileInfos, nil
 }
 
-// createFileInfo crea un SupabaseFileInfo desde un FileObject
-func (s *SupabaseStorageService) createFileInfo(file storage_go.FileObject) SupabaseFileInfo {
-	// Generar URL de descarga para cada archivo
-	publicURL := s.client.GetPublicUrl(s.bucketName, file.Name)
-
-	// Obtener tamaño del archivo desde metadata
-	size := int64(0)
-	if file.Metadata != nil {
-		if metadata, ok := file.Metadata.(map[string]interface{}); ok {
-			if sizeValue, exists := metadata["size"]; exists {
-				if sizeFloat, ok := sizeValue.(float64); ok {
-					size = int64(sizeFloat)
-				}
-			}
-		}
+// createFileInfo crea un SupabaseFileInfo desde un archivo listado en folder, sin enlace de descarga
+func (s *SupabaseStorageService) createFileInfo(folder string, file StoredFile) SupabaseFileInfo {
+	filePath := file.Name
+	if folder != "" && folder != "." {
+		filePath = folder + "/" + file.Name
 	}
-
-	// Obtener tipo MIME desde metadata
-	mimeType := ""
-	if file.Metadata != nil {
-		if metadata, ok := file.Metadata.(map[string]interface{}); ok {
-			if typeValue, exists := metadata["mimetype"]; exists {
-				if typeStr, ok := typeValue.(string); ok {
-					mimeType = typeStr
-				}
-			}
-		}
+	return SupabaseFileInfo{
+		Name:       filepath.Base(file.Name),
+		Size:       file.Size,
+		Path:       filePath,
+		MimeType:   file.MimeType,
+		UploadedAt: file.CreatedAt,
 	}
+}
 
-	return SupabaseFileInfo{
-		Name:        filepath.Base(file.Name),
-		Size:        size,
-		Path:        file.Name,
-		MimeType:    mimeType,
-		UploadedAt:  file.CreatedAt,
-		DownloadURL: publicURL.SignedURL,
+// signFileInfos agrega a los archivos listados sus enlaces de descarga firmados, en una sola
+// petición al almacenamiento. Un error se registra y deja los enlaces vacíos.
+func (s *SupabaseStorageService) signFileInfos(files []SupabaseFileInfo) {
+	if len(files) == 0 {
+		return
+	}
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	urls, err := s.SignedURLs(paths, s.urlTTL)
+	if err != nil {
+		slog.Warn("error firmando enlaces de descarga", "error", err)
+		return
+	}
+	for i := range files {
+		files[i].DownloadURL = urls[files[i].Path]
 	}
 }
 
 // GetFileInfo obtiene información de un archivo específico
 func (s *SupabaseStorageService) GetFileInfo(filePath string) (*SupabaseFileInfo, error) {
 	// Intentar obtener la información del archivo
-	files, err := s.client.ListFiles(s.bucketName, filepath.Dir(filePath), storage_go.FileSearchOptions{
-		Limit:  1,
-		Offset: 0,
-	})
+	files, err := s.files.List(s.bucketName, filepath.Dir(filePath), 1, 0)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo información del archivo: %v", err)
 	}
 
 	// Buscar el archivo específico
-	var targetFile *storage_go.FileObject
 	for _, file := range files {
 		if file.Name == filePath {
-			targetFile = &file
-			break
-		}
-	}
-
-	if targetFile == nil {
-		return nil, fmt.Errorf("archivo no encontrado: %s", filePath)
-	}
-
-	// Generar URL de descarga
-	publicURL := s.client.GetPublicUrl(s.bucketName, targetFile.Name)
-
-	// Obtener tamaño del archivo desde metadata
-	size := int64(0)
-	if targetFile.Metadata != nil {
-		if metadata, ok := targetFile.Metadata.(map[string]interface{}); ok {
-			if sizeValue, exists := metadata["size"]; exists {
-				if sizeFloat, ok := sizeValue.(float64); ok {
-					size = int64(sizeFloat)
-				}
-			}
-		}
-	}
-
-	// Obtener tipo MIME desde metadata
-	mimeType := ""
-	if targetFile.Metadata != nil {
-		if metadata, ok := targetFile.Metadata.(map[string]interface{}); ok {
-			if typeValue, exists := metadata["mimetype"]; exists {
-				if typeStr, ok := typeValue.(string); ok {
-					mimeType = typeStr
-				}
-			}
+			fileInfo := s.createFileInfo(filepath.Dir(filePath), file)
+			fileInfo.DownloadURL = s.downloadURL(fileInfo.Path)
+			return &fileInfo, nil
 		}
 	}
 
-	fileInfo := &SupabaseFileInfo{
-		Name:        filepath.Base(targetFile.Name),
-		Size:        size,
-		Path:        targetFile.Name,
-		MimeType:    mimeType,
-		UploadedAt:  targetFile.CreatedAt,
-		DownloadURL: publicURL.SignedURL,
-	}
-
-	return fileInfo, nil
+	return nil, fmt.Errorf("archivo no encontrado: %s", filePath)
 }
 
 // resolveFilePath busca la ruta completa de un archivo basado en su nombre
 func (s *SupabaseStorageService) resolveFilePath(fileName string) (string, error) {
-	log.Printf("🔍 Resolviendo ruta para archivo: %s", fileName)
+	slog.Debug("resolviendo ruta para archivo", "file_name", fileName)
 
 	// Listar todas las carpetas/directorios
-	folders, err := s.client.ListFiles(s.bucketName, "", storage_go.FileSearchOptions{
-		Limit:  1000,
-		Offset: 0,
-	})
+	folders, err := s.files.List(s.bucketName, "", 1000, 0)
 	if err != nil {
 		return "", fmt.Errorf("error listando carpetas: %v", err)
 	}
@@ -526,28 +764,25 @@ func (s *SupabaseStorageService) resolveFilePath(fileName string) (string, error
 	for _, folder := range folders {
 		// Solo buscar en carpetas de usuario
 		if strings.HasPrefix(folder.Name, "user_") && !strings.Contains(folder.Name, ".") {
-			log.Printf("🔍 Buscando en carpeta: %s", folder.Name)
+			slog.Debug("buscando en carpeta", "name", folder.Name)
 
 			// Listar archivos dentro de esta carpeta
-			userFiles, err := s.client.ListFiles(s.bucketName, folder.Name, storage_go.FileSearchOptions{
-				Limit:  100,
-				Offset: 0,
-			})
+			userFiles, err := s.files.List(s.bucketName, folder.Name, 100, 0)
 			if err != nil {
-				log.Printf("⚠️ Error listando archivos en carpeta %s: %v", folder.Name, err)
+				slog.Warn("error listando archivos en carpeta", "name", folder.Name, "error", err)
 				continue
 			}
 
 			// Buscar el archivo específico
 			for _, file := range userFiles {
 				if filepath.Base(file.Name) == fileName || file.Name == fileName {
-					log.Printf("✅ Archivo encontrado: %s", file.Name)
+					slog.Info("archivo encontrado", "name", file.Name)
 					// Retornar la ruta completa con la carpeta del usuario
 					fullPath := file.Name
 					if !strings.HasPrefix(fullPath, folder.Name+"/") {
 						fullPath = folder.Name + "/" + filepath.Base(file.Name)
 					}
-					log.Printf("✅ Ruta completa resuelta: %s", fullPath)
+					slog.Info("ruta completa resuelta", "full_path", fullPath)
 					return fullPath, nil
 				}
 			}
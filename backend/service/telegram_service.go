@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/model"
 	"github.com/nescool101/rentManager/storage"
 )
 
@@ -56,20 +59,15 @@ type TelegramFileBackup struct {
 
 var telegramService *TelegramService
 
-// InitializeTelegramService inicializa el servicio de Telegram
-func InitializeTelegramService() error {
-	// Verificar si el feature flag de Telegram está habilitado
-	telegramEnabled := os.Getenv("TELEGRAM_ENABLED")
-	if telegramEnabled != "true" {
-		return fmt.Errorf("integración de Telegram deshabilitada por feature flag")
-	}
-
-	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
+// InitializeTelegramService inicializa el servicio de Telegram con el bot de cfg. Se llama al
+// arrancar con el feature flag habilitado, o al habilitarlo un admin
+func InitializeTelegramService(cfg config.TelegramConfig) error {
+	botToken := cfg.BotToken
 	if botToken == "" {
 		return fmt.Errorf("TELEGRAM_BOT_TOKEN no está configurada")
 	}
 
-	chatID := os.Getenv("TELEGRAM_CHAT_ID")
+	chatID := cfg.ChatID
 	if chatID == "" {
 		return fmt.Errorf("TELEGRAM_CHAT_ID no está configurada")
 	}
@@ -80,9 +78,7 @@ func InitializeTelegramService() error {
 		baseURL:  fmt.Sprintf("https://api.telegram.org/bot%s", botToken),
 	}
 
-	log.Printf("✅ Servicio de Telegram inicializado")
-	log.Printf("🤖 Bot: @bescao_bot")
-	log.Printf("💬 Chat ID: %s", chatID)
+	slog.Info("servicio de Telegram inicializado", "bot", "@bescao_bot", "chat_id", chatID)
 
 	// Probar conexión
 	if err := telegramService.testConnection(); err != nil {
@@ -97,16 +93,35 @@ func GetTelegramService() *TelegramService {
 	return telegramService
 }
 
-// IsTelegramEnabled verifica si el feature flag de Telegram está habilitado
+// IsTelegramEnabled verifica si el feature flag de Telegram está habilitado (TELEGRAM_ENABLED
+// o el valor definido por un admin)
 func IsTelegramEnabled() bool {
-	return os.Getenv("TELEGRAM_ENABLED") == "true"
+	return FeatureEnabled(model.FeatureTelegramBackup)
 }
 
 // testConnection prueba la conexión con Telegram
 func (t *TelegramService) testConnection() error {
-	url := fmt.Sprintf("%s/getMe", t.baseURL)
-	resp, err := http.Get(url)
+	if err := t.HealthCheck(context.Background()); err != nil {
+		return err
+	}
+
+	slog.Info("conexión con Telegram establecida exitosamente")
+	return nil
+}
+
+// HealthCheck verifica con getMe que Telegram responde y que el token del bot es válido
+func (t *TelegramService) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/getMe", t.baseURL), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		// La URL lleva el token del bot, el error se devuelve sin ella
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
 		return err
 	}
 	defer resp.Body.Close()
@@ -114,8 +129,6 @@ func (t *TelegramService) testConnection() error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("error de conexión: %d", resp.StatusCode)
 	}
-
-	log.Printf("🔗 Conexión con Telegram establecida exitosamente")
 	return nil
 }
 
@@ -124,14 +137,14 @@ func (t *TelegramService) getUserName(userID string) string {
 	// Convertir userID string a UUID
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
-		log.Printf("Error parseando userID %s: %v", userID, err)
+		slog.Error("error parseando userID", "user_id", userID, "error", err)
 		return "Usuario desconocido"
 	}
 
-	// Crear cliente Supabase directamente
-	supabaseClient, err := storage.InitializeSupabaseClient()
-	if err != nil {
-		log.Printf("Error inicializando cliente Supabase: %v", err)
+	// Cliente Supabase creado al arrancar
+	supabaseClient := storage.GetSupabaseClient()
+	if supabaseClient == nil {
+		slog.Info("cliente Supabase no inicializado")
 		return "Usuario desconocido"
 	}
 
@@ -144,14 +157,14 @@ func (t *TelegramService) getUserName(userID string) string {
 	ctx := context.Background()
 	user, err := userRepo.GetByID(ctx, userUUID)
 	if err != nil || user == nil {
-		log.Printf("Error obteniendo usuario %s: %v", userID, err)
+		slog.Error("error obteniendo usuario", "user_id", userID, "error", err)
 		return "Usuario desconocido"
 	}
 
 	// Buscar persona asociada
 	person, err := personRepo.GetByID(ctx, user.PersonID)
 	if err != nil || person == nil {
-		log.Printf("Error obteniendo persona para usuario %s: %v", userID, err)
+		slog.Error("error obteniendo persona para usuario", "user_id", userID, "error", err)
 		return "Usuario desconocido"
 	}
 
@@ -160,7 +173,7 @@ func (t *TelegramService) getUserName(userID string) string {
 
 // BackupFileToTelegram respalda un archivo en Telegram antes de eliminarlo
 func (t *TelegramService) BackupFileToTelegram(fileData []byte, fileName, originalPath, userID string) (*TelegramFileBackup, error) {
-	log.Printf("📤 Respaldando archivo en Telegram: %s (%.2f KB)", fileName, float64(len(fileData))/1024)
+	slog.Info("respaldando archivo en Telegram", "file_name", fileName, "size_kb", float64(len(fileData))/1024)
 
 	// Obtener nombre del usuario
 	userName := t.getUserName(userID)
@@ -248,9 +261,7 @@ func (t *TelegramService) BackupFileToTelegram(fileData []byte, fileName, origin
 		OriginalURL: originalPath,
 	}
 
-	log.Printf("✅ Archivo respaldado exitosamente en Telegram")
-	log.Printf("🆔 File ID: %s", backup.FileID)
-	log.Printf("💌 Message ID: %d", backup.MessageID)
+	slog.Info("archivo respaldado exitosamente en Telegram", "file_id", backup.FileID, "message_id", backup.MessageID)
 
 	return backup, nil
 }
@@ -295,6 +306,27 @@ func (t *TelegramService) SendBackupError(fileName, userID, errorMsg string) err
 	return t.sendMessage(message)
 }
 
+// SendScanAlert avisa que un archivo subido fue rechazado por contener malware
+func (t *TelegramService) SendScanAlert(fileName, userID, signature, quarantinePath string) error {
+	userName := t.getUserName(userID)
+
+	message := fmt.Sprintf("🦠 Archivo rechazado por malware\n\n"+
+		"📄 Archivo: %s\n"+
+		"👤 Usuario: %s (%s)\n"+
+		"🚨 Detección: %s\n"+
+		"📦 Cuarentena: %s\n"+
+		"🕐 Fecha: %s\n\n"+
+		"⚠️ El archivo NO fue publicado.",
+		fileName,
+		userName,
+		userID,
+		signature,
+		quarantinePath,
+		time.Now().Format("2006-01-02 15:04:05"))
+
+	return t.sendMessage(message)
+}
+
 // sendMessage envía un mensaje de texto a Telegram
 func (t *TelegramService) sendMessage(text string) error {
 	url := fmt.Sprintf("%s/sendMessage", t.baseURL)
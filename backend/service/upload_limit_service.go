@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+// Límites por defecto cuando no hay variables de entorno ni límites guardados por un admin.
+// Los administradores no tienen límite.
+const (
+	defaultUploadMaxFileSizeMB = 500
+	defaultUploadQuotaMB       = 5120
+	uploadLimitCacheTTL        = time.Minute
+)
+
+// Códigos de UploadLimitError
+const (
+	UploadLimitFileTooLarge  = "file_too_large"
+	UploadLimitQuotaExceeded = "quota_exceeded"
+)
+
+// UploadLimitError indica que una subida supera el tamaño máximo de archivo o la cuota del usuario
+type UploadLimitError struct {
+	Code  string
+	Limit int64 // Bytes permitidos
+	Used  int64 // Bytes ya usados (cuota)
+	Size  int64 // Tamaño del archivo rechazado
+}
+
+func (e *UploadLimitError) Error() string {
+	if e.Code == UploadLimitFileTooLarge {
+		return fmt.Sprintf("el archivo (%.2f MB) supera el tamaño máximo permitido de %.2f MB", megabytes(e.Size), megabytes(e.Limit))
+	}
+	return fmt.Sprintf("el archivo (%.2f MB) supera la cuota de almacenamiento: %.2f MB usados de %.2f MB", megabytes(e.Size), megabytes(e.Used), megabytes(e.Limit))
+}
+
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / 1024 / 1024
+}
+
+// StorageUsage es el espacio usado por un usuario y sus límites
+type StorageUsage struct {
+	UserID           string `json:"user_id"`
+	Role             string `json:"role"`
+	UsedBytes        int64  `json:"used_bytes"`
+	FileCount        int    `json:"file_count"`
+	QuotaBytes       int64  `json:"quota_bytes"`         // 0: sin límite
+	MaxFileSizeBytes int64  `json:"max_file_size_bytes"` // 0: sin límite
+	RemainingBytes   *int64 `json:"remaining_bytes"`     // nil sin cuota
+}
+
+// newStorageUsage calcula el espacio restante de un usuario según su límite
+func newStorageUsage(userID string, limit model.UploadLimit, used int64, count int) *StorageUsage {
+	usage := &StorageUsage{
+		UserID:           userID,
+		Role:             limit.Role,
+		UsedBytes:        used,
+		FileCount:        count,
+		QuotaBytes:       limit.Quota(),
+		MaxFileSizeBytes: limit.MaxFileSize(),
+	}
+	if quota := limit.Quota(); quota > 0 {
+		remaining := quota - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.RemainingBytes = &remaining
+	}
+	return usage
+}
+
+// UploadLimitService resuelve los límites de subida de cada rol: los guardados por un admin o,
+// en su defecto, los de las variables de entorno
+type UploadLimitService struct {
+	repo storage.UploadLimitStore
+	env  config.UploadLimitConfig
+
+	mu        sync.RWMutex
+	overrides map[string]model.UploadLimit
+	loadedAt  time.Time
+}
+
+// NewUploadLimitService crea el servicio de límites de subida
+func NewUploadLimitService(repo storage.UploadLimitStore, env config.UploadLimitConfig) *UploadLimitService {
+	return &UploadLimitService{repo: repo, env: env}
+}
+
+// EnvUploadLimit devuelve el límite de un rol según UPLOAD_MAX_FILE_SIZE_MB_<ROL> y
+// UPLOAD_QUOTA_MB_<ROL>, o UPLOAD_MAX_FILE_SIZE_MB y UPLOAD_QUOTA_MB para todos los roles.
+// Los administradores solo tienen límite si se define con su sufijo.
+func EnvUploadLimit(cfg config.UploadLimitConfig, role string) model.UploadLimit {
+	limit := model.UploadLimit{
+		Role:          role,
+		MaxFileSizeMB: defaultUploadMaxFileSizeMB,
+		QuotaMB:       defaultUploadQuotaMB,
+		Source:        "env",
+	}
+	if role == "admin" {
+		limit.MaxFileSizeMB, limit.QuotaMB = 0, 0
+	}
+
+	shared := role != "admin"
+	limit.MaxFileSizeMB = configuredUploadLimit(cfg.RoleMaxFileSizeMB, cfg.MaxFileSizeMB, role, shared, limit.MaxFileSizeMB)
+	limit.QuotaMB = configuredUploadLimit(cfg.RoleQuotaMB, cfg.QuotaMB, role, shared, limit.QuotaMB)
+	return limit
+}
+
+// configuredUploadLimit devuelve el límite del rol, luego el general (si shared) y si no el valor por defecto
+func configuredUploadLimit(byRole map[string]int64, general *int64, role string, shared bool, defaultMB int64) int64 {
+	if mb, ok := byRole[strings.ToLower(role)]; ok {
+		return mb
+	}
+	if shared && general != nil {
+		return *general
+	}
+	return defaultMB
+}
+
+// LimitFor devuelve el límite vigente de un rol. Los roles desconocidos usan el de "user".
+func (s *UploadLimitService) LimitFor(ctx context.Context, role string) model.UploadLimit {
+	if !model.IsUploadLimitRole(role) {
+		role = "user"
+	}
+	if s == nil {
+		return EnvUploadLimit(config.UploadLimitConfig{}, role)
+	}
+	if limit, ok := s.loadOverrides(ctx)[role]; ok {
+		limit.Source = "admin"
+		return limit
+	}
+	return EnvUploadLimit(s.env, role)
+}
+
+// All devuelve el límite vigente de cada rol
+func (s *UploadLimitService) All(ctx context.Context) []model.UploadLimit {
+	limits := make([]model.UploadLimit, 0, len(model.UploadLimitRoles))
+	for _, role := range model.UploadLimitRoles {
+		limits = append(limits, s.LimitFor(ctx, role))
+	}
+	return limits
+}
+
+// Save guarda el límite de un rol definido por un admin
+func (s *UploadLimitService) Save(ctx context.Context, limit model.UploadLimit) (*model.UploadLimit, error) {
+	if !model.IsUploadLimitRole(limit.Role) {
+		return nil, fmt.Errorf("rol desconocido: %s", limit.Role)
+	}
+	if limit.MaxFileSizeMB < 0 || limit.QuotaMB < 0 {
+		return nil, fmt.Errorf("los límites no pueden ser negativos")
+	}
+
+	saved, err := s.repo.Save(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	saved.Source = "admin"
+	return saved, nil
+}
+
+// Reset elimina el límite guardado de un rol, que vuelve a los valores del entorno
+func (s *UploadLimitService) Reset(ctx context.Context, role string) (model.UploadLimit, error) {
+	if err := s.repo.Delete(ctx, role); err != nil {
+		return model.UploadLimit{}, err
+	}
+	s.invalidate()
+	return EnvUploadLimit(s.env, role), nil
+}
+
+func (s *UploadLimitService) invalidate() {
+	s.mu.Lock()
+	s.overrides = nil
+	s.mu.Unlock()
+}
+
+// loadOverrides devuelve los límites guardados, recargándolos cuando la caché venció
+func (s *UploadLimitService) loadOverrides(ctx context.Context) map[string]model.UploadLimit {
+	s.mu.RLock()
+	if s.overrides != nil && time.Since(s.loadedAt) < uploadLimitCacheTTL {
+		overrides := s.overrides
+		s.mu.RUnlock()
+		return overrides
+	}
+	s.mu.RUnlock()
+
+	limits, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("no se pudieron cargar los límites de subida, se usan los del entorno", "error", err)
+		return nil
+	}
+
+	overrides := make(map[string]model.UploadLimit, len(limits))
+	for _, limit := range limits {
+		overrides[limit.Role] = limit
+	}
+
+	s.mu.Lock()
+	s.overrides = overrides
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	return overrides
+}
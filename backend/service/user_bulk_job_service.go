@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// userBulkQueueSize is the number of jobs that can wait for the worker without blocking
+	userBulkQueueSize = 32
+	// userBulkProgressEvery is how many users are processed between saves of the job progress
+	userBulkProgressEvery = 25
+)
+
+// UserBulkJobQueue runs the bulk user jobs of the admins one at a time in a background worker.
+// Jobs are stored before they run, so the ones interrupted by a restart are resumed on Start.
+type UserBulkJobQueue struct {
+	repo       storage.UserBulkJobStore
+	userRepo   storage.UserStore
+	personRepo storage.PersonStore
+	orgService *OrganizationService
+	jobs       chan uuid.UUID
+}
+
+// NewUserBulkJobQueue creates a new UserBulkJobQueue
+func NewUserBulkJobQueue(factory *storage.RepositoryFactory, orgService *OrganizationService) *UserBulkJobQueue {
+	return &UserBulkJobQueue{
+		repo:       factory.GetUserBulkJobRepository(),
+		userRepo:   factory.GetUserRepository(),
+		personRepo: factory.GetPersonRepository(),
+		orgService: orgService,
+		jobs:       make(chan uuid.UUID, userBulkQueueSize),
+	}
+}
+
+// Start launches the worker and queues again the jobs left unfinished by a restart
+func (q *UserBulkJobQueue) Start() error {
+	go q.work()
+
+	unfinished, err := q.repo.GetUnfinished(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to load unfinished bulk user jobs: %w", err)
+	}
+	for _, job := range unfinished {
+		q.push(job.ID)
+	}
+
+	slog.Info("bulk users bulk user job worker started jobs resumed", "unfinished_count", len(unfinished))
+	return nil
+}
+
+// Enqueue stores a job for the users matching filter and queues it
+func (q *UserBulkJobQueue) Enqueue(ctx context.Context, action string, filter model.UserBulkFilter, createdBy uuid.UUID) (*model.UserBulkJob, error) {
+	if !model.IsValidUserBulkAction(action) {
+		return nil, fmt.Errorf("unknown bulk action: %s", action)
+	}
+
+	job, err := q.repo.Create(ctx, model.UserBulkJob{
+		ID:        uuid.New(),
+		Action:    action,
+		Filter:    filter,
+		Status:    model.UserBulkJobQueued,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q.push(job.ID)
+	logging.FromContext(ctx).Info("bulk users job queued", "job_id", job.ID, "action", action)
+	return job, nil
+}
+
+// MatchingUsers returns the users a filter selects
+func (q *UserBulkJobQueue) MatchingUsers(ctx context.Context, filter model.UserBulkFilter) ([]model.User, error) {
+	users, err := q.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []model.User
+	for _, user := range users {
+		if filter.Status != "" && user.Status != filter.Status {
+			continue
+		}
+		if filter.Role != "" && user.Role != filter.Role {
+			continue
+		}
+		if filter.CreatedFrom != nil || filter.CreatedTo != nil {
+			if user.CreatedAt == nil {
+				continue
+			}
+			if filter.CreatedFrom != nil && user.CreatedAt.Before(*filter.CreatedFrom) {
+				continue
+			}
+			if filter.CreatedTo != nil && user.CreatedAt.After(*filter.CreatedTo) {
+				continue
+			}
+		}
+		if filter.OrganizationID != nil {
+			org := q.orgService.ForPerson(ctx, user.PersonID)
+			if org == nil || org.ID != *filter.OrganizationID {
+				continue
+			}
+		}
+		matching = append(matching, user)
+	}
+	return matching, nil
+}
+
+// push hands a job to the worker without blocking the caller when the queue is full
+func (q *UserBulkJobQueue) push(id uuid.UUID) {
+	select {
+	case q.jobs <- id:
+	default:
+		go func() { q.jobs <- id }()
+	}
+}
+
+func (q *UserBulkJobQueue) work() {
+	for id := range q.jobs {
+		q.run(context.Background(), id)
+	}
+}
+
+// run applies a job to every matching user, saving the report as it goes. A job interrupted
+// by a restart starts over; activating and disabling skip the users already updated.
+func (q *UserBulkJobQueue) run(ctx context.Context, id uuid.UUID) {
+	job, err := q.repo.GetByID(ctx, id)
+	if err != nil || job == nil {
+		logging.FromContext(ctx).Error("bulk users job not found", "id", id, "error", err)
+		return
+	}
+	if job.Status == model.UserBulkJobCompleted || job.Status == model.UserBulkJobFailed {
+		return
+	}
+
+	startedAt := time.Now()
+	job.Status = model.UserBulkJobRunning
+	job.StartedAt = &startedAt
+	job.Results = nil
+	job.Succeeded, job.Skipped, job.Failed = 0, 0, 0
+
+	users, err := q.MatchingUsers(ctx, job.Filter)
+	if err != nil {
+		q.finish(ctx, job, fmt.Errorf("failed to get users: %w", err))
+		return
+	}
+	job.Total = len(users)
+	if err := q.repo.SaveProgress(ctx, *job); err != nil {
+		logging.FromContext(ctx).Warn("bulk users could not save the start of job", "job_id", job.ID, "error", err)
+	}
+
+	for i, user := range users {
+		job.AddResult(q.apply(ctx, job, user))
+		if (i+1)%userBulkProgressEvery == 0 {
+			if err := q.repo.SaveProgress(ctx, *job); err != nil {
+				logging.FromContext(ctx).Warn("bulk users could not save the progress of job", "job_id", job.ID, "error", err)
+			}
+		}
+	}
+
+	q.finish(ctx, job, nil)
+}
+
+// finish records the end of a job, failed when err is not nil
+func (q *UserBulkJobQueue) finish(ctx context.Context, job *model.UserBulkJob, err error) {
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = model.UserBulkJobCompleted
+	if err != nil {
+		job.Status = model.UserBulkJobFailed
+		job.Error = err.Error()
+		logging.FromContext(ctx).Error("bulk users job failed", "job_id", job.ID, "error", err)
+	} else {
+		logging.FromContext(ctx).Info("bulk users job finished: succeeded, skipped, failed", "job_id", job.ID, "action", job.Action, "succeeded", job.Succeeded, "skipped", job.Skipped, "failed", job.Failed)
+	}
+
+	if err := q.repo.SaveProgress(ctx, *job); err != nil {
+		logging.FromContext(ctx).Error("bulk users could not save the result of job", "job_id", job.ID, "error", err)
+	}
+}
+
+// apply runs the action of a job on one user
+func (q *UserBulkJobQueue) apply(ctx context.Context, job *model.UserBulkJob, user model.User) model.UserBulkResult {
+	result := model.UserBulkResult{
+		UserID:         user.ID,
+		Email:          user.Email,
+		PreviousStatus: user.Status,
+	}
+	skip := func(message string) model.UserBulkResult {
+		result.Outcome = model.UserBulkResultSkipped
+		result.Message = message
+		return result
+	}
+
+	var err error
+	switch job.Action {
+	case model.UserBulkActionActivate:
+		if user.Status == "active" {
+			return skip("User is already active")
+		}
+		user.Status = "active"
+		_, err = q.userRepo.Update(ctx, user)
+	case model.UserBulkActionDisable:
+		if user.ID == job.CreatedBy {
+			return skip("Admins cannot disable their own account")
+		}
+		if user.Status == "disabled" {
+			return skip("User is already disabled")
+		}
+		user.Status = "disabled"
+		_, err = q.userRepo.Update(ctx, user)
+	case model.UserBulkActionResendInvitation:
+		if user.Status == "active" || user.Status == "disabled" {
+			return skip(fmt.Sprintf("User is %s, invitations are only resent to users who have not joined", user.Status))
+		}
+		err = q.resendInvitation(ctx, user)
+	}
+
+	if err != nil {
+		result.Outcome = model.UserBulkResultFailed
+		result.Message = err.Error()
+		return result
+	}
+	result.Outcome = model.UserBulkResultSucceeded
+	return result
+}
+
+// resendInvitation gives a user who has not joined yet a new temporary password and emails it
+// with the login link of their organization
+func (q *UserBulkJobQueue) resendInvitation(ctx context.Context, user model.User) error {
+	passwordBytes := make([]byte, 6)
+	if _, err := rand.Read(passwordBytes); err != nil {
+		return fmt.Errorf("failed to generate temporary password: %w", err)
+	}
+	tempPassword := hex.EncodeToString(passwordBytes)
+
+	user.PasswordBase64 = base64.StdEncoding.EncodeToString([]byte(tempPassword))
+	if _, err := q.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to reset temporary password: %w", err)
+	}
+
+	name := user.Email
+	if person, err := q.personRepo.GetByID(ctx, user.PersonID); err == nil && person != nil && person.FullName != "" {
+		name = person.FullName
+	}
+	loginURL := OrganizationBaseURL(q.orgService.ForPerson(ctx, user.PersonID)) + "/login"
+
+	subject := "Recordatorio: tienes una invitación pendiente"
+	body := fmt.Sprintf("Hola %s,\n\nTe recordamos que tienes una invitación pendiente para acceder a la plataforma de administración de propiedades.\n\nPara iniciar sesión, utiliza los siguientes datos:\nEmail: %s\nContraseña temporal: %s\n\nURL de inicio de sesión: %s\n\nDeberás cambiar tu contraseña en el primer inicio de sesión.\n\nGracias,\nEquipo de Administración",
+		name, user.Email, tempPassword, loginURL)
+
+	if err := SendSimpleEmail(user.Email, subject, body); err != nil {
+		return fmt.Errorf("failed to send invitation email: %w", err)
+	}
+	return nil
+}
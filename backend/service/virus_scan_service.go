@@ -0,0 +1,400 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	"github.com/nescool101/rentManager/storage"
+)
+
+const (
+	// defaultQuarantineBucket es el bucket donde esperan los archivos mientras se analizan
+	defaultQuarantineBucket = "quarantine"
+	// clamAVChunkSize es el tamaño de cada bloque enviado a clamd
+	clamAVChunkSize = 64 * 1024
+	// virusTotalMaxSize es el tamaño máximo aceptado por el endpoint /files de VirusTotal
+	virusTotalMaxSize = 32 * 1024 * 1024
+	// virusTotalPollInterval es la espera entre consultas del resultado de un análisis
+	virusTotalPollInterval = 5 * time.Second
+)
+
+// ErrVirusScanUnavailable indica que el archivo no se pudo analizar y se rechazó por seguridad
+var ErrVirusScanUnavailable = errors.New("no se pudo analizar el archivo en busca de virus, intente más tarde")
+
+// FileRejectedError indica que el análisis detectó malware en el archivo
+type FileRejectedError struct {
+	FileName  string
+	Signature string
+}
+
+func (e *FileRejectedError) Error() string {
+	return fmt.Sprintf("el archivo %s fue rechazado: se detectó %s", e.FileName, e.Signature)
+}
+
+// ScanVerdict es el resultado del análisis de un archivo
+type ScanVerdict struct {
+	Infected  bool
+	Signature string // Nombre del malware detectado
+}
+
+// VirusScanner analiza el contenido de un archivo en busca de malware
+type VirusScanner interface {
+	Name() string
+	Scan(ctx context.Context, content io.Reader, fileName string) (*ScanVerdict, error)
+}
+
+// ClamAVScanner analiza archivos con un servidor clamd usando el comando INSTREAM
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner crea un analizador para el clamd de address (host:puerto o unix:/ruta/socket)
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{address: address, timeout: timeout}
+}
+
+// Name devuelve el nombre del proveedor
+func (s *ClamAVScanner) Name() string {
+	return "clamav"
+}
+
+// Scan envía el contenido a clamd en bloques y lee el veredicto
+func (s *ClamAVScanner) Scan(ctx context.Context, content io.Reader, fileName string) (*ScanVerdict, error) {
+	network, address := "tcp", s.address
+	if strings.HasPrefix(address, "unix:") {
+		network, address = "unix", strings.TrimPrefix(address, "unix:")
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("error conectando con clamd: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("error iniciando análisis en clamd: %v", err)
+	}
+
+	buf := make([]byte, clamAVChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, fmt.Errorf("error enviando archivo a clamd: %v", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, fmt.Errorf("error enviando archivo a clamd: %v", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("error leyendo archivo: %v", readErr)
+		}
+	}
+	// Un bloque de tamaño cero indica el fin del archivo
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("error finalizando análisis en clamd: %v", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return nil, fmt.Errorf("error leyendo respuesta de clamd: %v", err)
+	}
+	reply = strings.TrimSpace(strings.TrimSuffix(reply, "\x00"))
+
+	// Respuestas: "stream: OK", "stream: <firma> FOUND" o "<mensaje> ERROR"
+	switch {
+	case strings.HasSuffix(reply, "FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return &ScanVerdict{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, "OK"):
+		return &ScanVerdict{}, nil
+	default:
+		return nil, fmt.Errorf("respuesta de clamd: %s", reply)
+	}
+}
+
+// VirusTotalScanner analiza archivos con la API v3 de VirusTotal
+type VirusTotalScanner struct {
+	apiKey  string
+	baseURL string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewVirusTotalScanner crea un analizador de VirusTotal que espera el resultado hasta timeout
+func NewVirusTotalScanner(apiKey string, timeout time.Duration) *VirusTotalScanner {
+	return &VirusTotalScanner{
+		apiKey:  apiKey,
+		baseURL: "https://www.virustotal.com/api/v3",
+		timeout: timeout,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Name devuelve el nombre del proveedor
+func (s *VirusTotalScanner) Name() string {
+	return "virustotal"
+}
+
+// Scan sube el archivo a VirusTotal y consulta el análisis hasta que termina
+func (s *VirusTotalScanner) Scan(ctx context.Context, content io.Reader, fileName string) (*ScanVerdict, error) {
+	data, err := io.ReadAll(io.LimitReader(content, virusTotalMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo archivo: %v", err)
+	}
+	if len(data) > virusTotalMaxSize {
+		return nil, fmt.Errorf("el archivo supera los %d MB que acepta VirusTotal", virusTotalMaxSize/1024/1024)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	writer.Close()
+
+	var upload struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := s.do(ctx, http.MethodPost, s.baseURL+"/files", &body, writer.FormDataContentType(), &upload); err != nil {
+		return nil, fmt.Errorf("error subiendo archivo a VirusTotal: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for {
+		var analysis struct {
+			Data struct {
+				Attributes struct {
+					Status string `json:"status"`
+					Stats  struct {
+						Malicious int `json:"malicious"`
+					} `json:"stats"`
+					Results map[string]struct {
+						Category string `json:"category"`
+						Result   string `json:"result"`
+					} `json:"results"`
+				} `json:"attributes"`
+			} `json:"data"`
+		}
+		if err := s.do(ctx, http.MethodGet, s.baseURL+"/analyses/"+upload.Data.ID, nil, "", &analysis); err != nil {
+			return nil, fmt.Errorf("error consultando análisis de VirusTotal: %v", err)
+		}
+
+		attributes := analysis.Data.Attributes
+		if attributes.Status == "completed" {
+			if attributes.Stats.Malicious == 0 {
+				return &ScanVerdict{}, nil
+			}
+			signature := fmt.Sprintf("malware (%d motores)", attributes.Stats.Malicious)
+			for engine, result := range attributes.Results {
+				if result.Category == "malicious" && result.Result != "" {
+					signature = fmt.Sprintf("%s (%s, %d motores)", result.Result, engine, attributes.Stats.Malicious)
+					break
+				}
+			}
+			return &ScanVerdict{Infected: true, Signature: signature}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("el análisis de VirusTotal no terminó a tiempo")
+		case <-time.After(virusTotalPollInterval):
+		}
+	}
+}
+
+// do envía una petición a la API de VirusTotal y decodifica la respuesta en v
+func (s *VirusTotalScanner) do(ctx context.Context, method, url string, body io.Reader, contentType string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-apikey", s.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// VirusScanService analiza los archivos subidos antes de publicarlos: los archivos esperan en el
+// bucket de cuarentena, los limpios pasan a la carpeta del usuario y los infectados se quedan en
+// cuarentena, se registran y se avisa a los administradores.
+type VirusScanService struct {
+	scanner          VirusScanner
+	repo             storage.FileScanStore
+	userRepo         storage.UserStore
+	quarantineBucket string
+	failOpen         bool
+}
+
+// NewVirusScanService crea el servicio según VIRUS_SCAN_PROVIDER ("clamav" con CLAMAV_ADDRESS o
+// "virustotal" con VIRUSTOTAL_API_KEY). Sin proveedor los archivos se publican sin análisis.
+// VIRUS_SCAN_FAIL_OPEN=true publica los archivos cuando el analizador no responde.
+func NewVirusScanService(factory *storage.RepositoryFactory, cfg config.VirusScanConfig, quarantineBucket string) *VirusScanService {
+	timeout := orDefault(cfg.Timeout, 2*time.Minute)
+
+	var scanner VirusScanner
+	switch provider := cfg.Provider; provider {
+	case "":
+	case "clamav":
+		address := cfg.ClamAVAddress
+		if address == "" {
+			address = "localhost:3310"
+		}
+		scanner = NewClamAVScanner(address, timeout)
+	case "virustotal":
+		if apiKey := cfg.VirusTotalAPIKey; apiKey != "" {
+			scanner = NewVirusTotalScanner(apiKey, timeout)
+		} else {
+			slog.Warn("VIRUS_SCAN_PROVIDER=virustotal sin VIRUSTOTAL_API_KEY, análisis deshabilitado")
+		}
+	default:
+		slog.Warn("VIRUS_SCAN_PROVIDER desconocido, análisis deshabilitado", "provider", provider)
+	}
+
+	if quarantineBucket == "" {
+		quarantineBucket = defaultQuarantineBucket
+	}
+
+	return &VirusScanService{
+		scanner:          scanner,
+		repo:             factory.GetFileScanRepository(),
+		userRepo:         factory.GetUserRepository(),
+		quarantineBucket: quarantineBucket,
+		failOpen:         cfg.FailOpen,
+	}
+}
+
+// Enabled indica si los archivos subidos se analizan
+func (s *VirusScanService) Enabled() bool {
+	return s != nil && s.scanner != nil
+}
+
+// QuarantineBucket devuelve el bucket donde esperan los archivos sin analizar o infectados
+func (s *VirusScanService) QuarantineBucket() string {
+	return s.quarantineBucket
+}
+
+// Recent devuelve los últimos análisis, opcionalmente solo los de un resultado
+func (s *VirusScanService) Recent(ctx context.Context, result string, limit int) ([]model.FileScan, error) {
+	return s.repo.GetRecent(ctx, result, limit)
+}
+
+// scan analiza un archivo en cuarentena y registra el resultado
+func (s *VirusScanService) scan(ctx context.Context, content io.Reader, scan model.FileScan) (model.FileScan, error) {
+	scan.Provider = s.scanner.Name()
+
+	verdict, err := s.scanner.Scan(ctx, content, scan.FileName)
+	scan.ScannedAt = time.Now()
+	switch {
+	case err != nil:
+		scan.Result = model.FileScanError
+		scan.Detail = err.Error()
+		logging.FromContext(ctx).Warn("file scan failed", "path", scan.Path, "provider", scan.Provider, "error", err)
+	case verdict.Infected:
+		scan.Result = model.FileScanInfected
+		scan.Signature = verdict.Signature
+		logging.FromContext(ctx).Info("file scan found malware", "path", scan.Path, "user_id", scan.UserID, "signature", verdict.Signature)
+	default:
+		scan.Result = model.FileScanClean
+		logging.FromContext(ctx).Info("file scan clean", "path", scan.Path, "provider", scan.Provider)
+	}
+
+	if _, recordErr := s.repo.Create(ctx, scan); recordErr != nil {
+		logging.FromContext(ctx).Warn("file scan not recorded", "path", scan.Path, "error", recordErr)
+	}
+	return scan, err
+}
+
+// notifyRejected avisa a los administradores por email y Telegram de un archivo rechazado
+func (s *VirusScanService) notifyRejected(ctx context.Context, scan model.FileScan, uploadedBy string) {
+	subject := fmt.Sprintf("Archivo rechazado por malware: %s", scan.FileName)
+	body := fmt.Sprintf(`
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Archivo Rechazado</title>
+	</head>
+	<body style="font-family: Arial, sans-serif; color: #333;">
+		<h2>%s</h2>
+		<p>El análisis de %s detectó malware en un archivo subido a la plataforma. El archivo no se
+		publicó y quedó en el bucket de cuarentena <strong>%s</strong>.</p>
+		<ul>
+			<li>Archivo: %s</li>
+			<li>Subido por: %s (usuario %s)</li>
+			<li>Tamaño: %.2f KB</li>
+			<li>Detección: %s</li>
+			<li>Ruta en cuarentena: %s</li>
+			<li>Fecha: %s</li>
+		</ul>
+		<p>Sistema de Administración de Propiedades</p>
+	</body>
+	</html>
+	`, subject, scan.Provider, s.quarantineBucket, scan.FileName, uploadedBy, scan.UserID,
+		float64(scan.Size)/1024, scan.Signature, scan.Path, FormatDateTime(scan.ScannedAt))
+
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error obteniendo administradores para avisar del archivo rechazado", "error", err)
+	}
+	for _, user := range users {
+		if user.Role != "admin" || user.Status == "disabled" || user.Email == "" {
+			continue
+		}
+		if err := SendSimpleEmail(user.Email, subject, body); err != nil {
+			logging.FromContext(ctx).Error("error avisando del archivo rechazado", "email", user.Email, "error", err)
+		}
+	}
+
+	if IsTelegramEnabled() {
+		if telegramService := GetTelegramService(); telegramService != nil {
+			if err := telegramService.SendScanAlert(scan.FileName, scan.UserID, scan.Signature, scan.Path); err != nil {
+				logging.FromContext(ctx).Warn("error enviando alerta de malware a Telegram", "error", err)
+			}
+		}
+	}
+}
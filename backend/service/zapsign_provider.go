@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nescool101/rentManager/config"
+)
+
+// DefaultZapSignAPIURL is the production ZapSign API
+const DefaultZapSignAPIURL = "https://api.zapsign.com.br/api/v1"
+
+// ZapSignProvider implements ESignProvider for ZapSign
+type ZapSignProvider struct {
+	apiURL        string
+	apiToken      string
+	webhookSecret string
+	httpClient    *http.Client
+}
+
+// NewZapSignProvider creates a ZapSign provider from the ZAPSIGN_* settings
+func NewZapSignProvider(cfg config.ZapSignConfig) (*ZapSignProvider, error) {
+	apiToken := cfg.APIToken
+	if apiToken == "" {
+		return nil, errors.New("ZAPSIGN_API_TOKEN is not configured")
+	}
+
+	webhookSecret := cfg.WebhookSecret
+	if webhookSecret == "" {
+		return nil, errors.New("ZAPSIGN_WEBHOOK_SECRET is not configured")
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = DefaultZapSignAPIURL
+	}
+
+	return &ZapSignProvider{
+		apiURL:        apiURL,
+		apiToken:      apiToken,
+		webhookSecret: webhookSecret,
+		httpClient:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *ZapSignProvider) Name() string {
+	return "zapsign"
+}
+
+type zapSignSigner struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	SignURL string `json:"sign_url,omitempty"`
+}
+
+type zapSignDoc struct {
+	Token      string          `json:"token"`
+	Status     string          `json:"status"`
+	SignedFile string          `json:"signed_file"`
+	Signers    []zapSignSigner `json:"signers"`
+}
+
+// CreateEnvelope creates a ZapSign document with a single signer
+func (p *ZapSignProvider) CreateEnvelope(ctx context.Context, req ESignEnvelopeRequest) (*ESignEnvelope, error) {
+	payload := map[string]interface{}{
+		"name":        req.DocumentName,
+		"base64_pdf":  base64.StdEncoding.EncodeToString(req.PDFData),
+		"external_id": req.SigningID,
+		"lang":        "es",
+		"signers": []zapSignSigner{
+			{Name: req.SignerName, Email: req.SignerEmail},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding ZapSign request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/docs/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating ZapSign request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	var doc zapSignDoc
+	if err := p.do(httpReq, &doc); err != nil {
+		return nil, err
+	}
+
+	envelope := &ESignEnvelope{ExternalID: doc.Token}
+	if len(doc.Signers) > 0 {
+		envelope.SigningURL = doc.Signers[0].SignURL
+	}
+	return envelope, nil
+}
+
+// ParseWebhook validates the shared secret ZapSign sends as a custom header and reads the event
+func (p *ZapSignProvider) ParseWebhook(header http.Header, body []byte) (*ESignWebhookEvent, error) {
+	secret := header.Get("X-Webhook-Secret")
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(p.webhookSecret)) != 1 {
+		return nil, errors.New("invalid ZapSign webhook secret")
+	}
+
+	var payload struct {
+		EventType string `json:"event_type"`
+		Token     string `json:"token"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("error parsing ZapSign webhook: %w", err)
+	}
+	if payload.Token == "" {
+		return nil, errors.New("ZapSign webhook without document token")
+	}
+
+	event := &ESignWebhookEvent{ExternalID: payload.Token, RawStatus: payload.EventType, Type: ESignEventOther}
+	switch {
+	case payload.EventType == "doc_signed" || payload.Status == "signed":
+		event.Type = ESignEventSigned
+	case payload.EventType == "doc_refused" || payload.Status == "refused":
+		event.Type = ESignEventRejected
+	}
+	return event, nil
+}
+
+// DownloadSignedDocument fetches the document details and downloads the signed file
+func (p *ZapSignProvider) DownloadSignedDocument(ctx context.Context, externalID string) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/docs/"+externalID+"/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ZapSign request: %w", err)
+	}
+
+	var doc zapSignDoc
+	if err := p.do(httpReq, &doc); err != nil {
+		return nil, err
+	}
+	if doc.SignedFile == "" {
+		return nil, fmt.Errorf("ZapSign document %s has no signed file yet (status %s)", externalID, doc.Status)
+	}
+
+	// The signed file is a pre-signed URL, no authorization header
+	fileReq, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.SignedFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating signed file request: %w", err)
+	}
+	resp, err := p.httpClient.Do(fileReq)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading ZapSign signed file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ZapSign signed file download failed with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// do sends an authenticated request and decodes the JSON response
+func (p *ZapSignProvider) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling ZapSign: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading ZapSign response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("ZapSign API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error parsing ZapSign response: %w", err)
+	}
+	return nil
+}
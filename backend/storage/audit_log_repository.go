@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// AuditLogRepository provides methods to interact with the audit_log table in Supabase
+type AuditLogRepository struct {
+	client *supa.Client
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository
+func NewAuditLogRepository(client *supa.Client) *AuditLogRepository {
+	return &AuditLogRepository{
+		client: client,
+	}
+}
+
+// Create records an audit log entry
+func (r *AuditLogRepository) Create(ctx context.Context, entry model.AuditLog) (*model.AuditLog, error) {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	data, _, err := r.client.From("audit_log").Insert(entry, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error recording audit log entry", "action", entry.Action, "error", err)
+		return nil, fmt.Errorf("failed to record audit log entry: %w", err)
+	}
+
+	var created []model.AuditLog
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created audit log data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created audit log entry, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// AuditLogFilter selects the entries listed to the admins
+type AuditLogFilter struct {
+	ChangedBy        uuid.UUID
+	ImpersonatedBy   uuid.UUID
+	ImpersonatedOnly bool
+	Since            time.Time
+	Limit            int
+}
+
+// List retrieves the entries matching the filter, newest first
+func (r *AuditLogRepository) List(ctx context.Context, filter AuditLogFilter) ([]model.AuditLog, error) {
+	query := r.client.From("audit_log").Select("*", "exact", false).
+		Gte("timestamp", filter.Since.UTC().Format(time.RFC3339Nano))
+	if filter.ChangedBy != uuid.Nil {
+		query = query.Eq("changed_by", filter.ChangedBy.String())
+	}
+	if filter.ImpersonatedBy != uuid.Nil {
+		query = query.Eq("impersonated_by", filter.ImpersonatedBy.String())
+	} else if filter.ImpersonatedOnly {
+		query = query.Not("impersonated_by", "is", "null")
+	}
+	query = query.Order("timestamp", &postgrest.OrderOpts{Ascending: false})
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit, "")
+	}
+
+	data, _, err := query.Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing audit log", "error", err)
+		return nil, err
+	}
+
+	var entries []model.AuditLog
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logging.FromContext(ctx).Error("error parsing audit log data", "error", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
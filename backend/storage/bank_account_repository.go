@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
 	supa "github.com/supabase-community/supabase-go"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
@@ -30,15 +30,15 @@ func (r *BankAccountRepository) GetAll(ctx context.Context) ([]model.BankAccount
 
 	data, count, err := r.client.From("bank_account").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching bank accounts: %v", err)
+		logging.FromContext(ctx).Error("error fetching bank accounts", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d bank accounts", count)
+	logging.FromContext(ctx).Debug("retrieved bank accounts", "count", count)
 
 	err = json.Unmarshal([]byte(data), &accounts)
 	if err != nil {
-		log.Printf("Error parsing bank account data: %v", err)
+		logging.FromContext(ctx).Error("error parsing bank account data", "error", err)
 		return nil, err
 	}
 
@@ -50,7 +50,7 @@ func (r *BankAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	data, count, err := r.client.From("bank_account").Select("*", "exact", false).
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching bank account by ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error fetching bank account", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -61,7 +61,7 @@ func (r *BankAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	var accounts []model.BankAccount
 	err = json.Unmarshal([]byte(data), &accounts)
 	if err != nil {
-		log.Printf("Error parsing bank account data: %v", err)
+		logging.FromContext(ctx).Error("error parsing bank account data", "error", err)
 		return nil, err
 	}
 
@@ -77,7 +77,7 @@ func (r *BankAccountRepository) GetByPersonID(ctx context.Context, personID uuid
 	data, count, err := r.client.From("bank_account").Select("*", "exact", false).
 		Eq("person_id", personID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching bank accounts by person ID %s: %v", personID, err)
+		logging.FromContext(ctx).Error("error fetching bank accounts by person", "person_id", personID, "error", err)
 		return nil, err
 	}
 
@@ -88,7 +88,7 @@ func (r *BankAccountRepository) GetByPersonID(ctx context.Context, personID uuid
 	var accounts []model.BankAccount
 	err = json.Unmarshal([]byte(data), &accounts)
 	if err != nil {
-		log.Printf("Error parsing bank account data: %v", err)
+		logging.FromContext(ctx).Error("error parsing bank account data", "error", err)
 		return nil, err
 	}
 
@@ -108,14 +108,14 @@ func (r *BankAccountRepository) Create(ctx context.Context, account model.BankAc
 
 	data, _, err := r.client.From("bank_account").Insert(accountData, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating bank account: %v", err)
+		logging.FromContext(ctx).Error("error creating bank account", "error", err)
 		return nil, fmt.Errorf("failed to create bank account: %w", err)
 	}
 
 	var createdAccounts []model.BankAccount
 	err = json.Unmarshal(data, &createdAccounts)
 	if err != nil {
-		log.Printf("Error parsing created bank account data: %v", err)
+		logging.FromContext(ctx).Error("error parsing created bank account data", "error", err)
 		return nil, err
 	}
 
@@ -139,7 +139,7 @@ func (r *BankAccountRepository) Update(ctx context.Context, account model.BankAc
 	data, count, err := r.client.From("bank_account").Update(accountData, "exact", "").
 		Eq("id", account.ID.String()).Execute()
 	if err != nil {
-		log.Printf("Error updating bank account %s: %v", account.ID, err)
+		logging.FromContext(ctx).Error("error updating bank account", "account_id", account.ID, "error", err)
 		return nil, err
 	}
 
@@ -150,7 +150,7 @@ func (r *BankAccountRepository) Update(ctx context.Context, account model.BankAc
 	var updatedAccounts []model.BankAccount
 	err = json.Unmarshal(data, &updatedAccounts)
 	if err != nil {
-		log.Printf("Error parsing updated bank account data: %v", err)
+		logging.FromContext(ctx).Error("error parsing updated bank account data", "error", err)
 		return nil, err
 	}
 
@@ -166,7 +166,7 @@ func (r *BankAccountRepository) Delete(ctx context.Context, id uuid.UUID) error
 	_, _, err := r.client.From("bank_account").Delete("minimal", "").
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error deleting bank account %s: %v", id, err)
+		logging.FromContext(ctx).Error("error deleting bank account", "id", id, "error", err)
 		return err
 	}
 
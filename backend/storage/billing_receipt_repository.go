@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// BillingReceiptRepository provides methods to interact with the billing_receipt table in Supabase
+type BillingReceiptRepository struct {
+	client *supa.Client
+}
+
+// NewBillingReceiptRepository creates a new BillingReceiptRepository
+func NewBillingReceiptRepository(client *supa.Client) *BillingReceiptRepository {
+	return &BillingReceiptRepository{
+		client: client,
+	}
+}
+
+// GetByRentalAndPeriod retrieves the receipt issued to a rental for a billing period, nil when
+// none was issued
+func (r *BillingReceiptRepository) GetByRentalAndPeriod(ctx context.Context, rentalID uuid.UUID, period string) (*model.BillingReceipt, error) {
+	data, _, err := r.client.From("billing_receipt").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Eq("period", period).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching receipt of rental", "rental_id", rentalID, "period", period, "error", err)
+		return nil, err
+	}
+
+	var receipts []model.BillingReceipt
+	err = json.Unmarshal(data, &receipts)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing billing receipt data", "error", err)
+		return nil, err
+	}
+
+	if len(receipts) == 0 {
+		return nil, nil
+	}
+
+	return &receipts[0], nil
+}
+
+// GetByRental retrieves the receipts issued to a rental, newest first
+func (r *BillingReceiptRepository) GetByRental(ctx context.Context, rentalID uuid.UUID) ([]model.BillingReceipt, error) {
+	data, _, err := r.client.From("billing_receipt").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Order("number", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching receipts of rental", "rental_id", rentalID, "error", err)
+		return nil, err
+	}
+
+	var receipts []model.BillingReceipt
+	err = json.Unmarshal(data, &receipts)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing billing receipt data", "error", err)
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// Create records an issued receipt
+func (r *BillingReceiptRepository) Create(ctx context.Context, receipt model.BillingReceipt) (*model.BillingReceipt, error) {
+	if receipt.ID == uuid.Nil {
+		receipt.ID = uuid.New()
+	}
+	if receipt.IssuedAt.IsZero() {
+		receipt.IssuedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("billing_receipt").Insert(receipt, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating billing receipt", "number", receipt.Number, "error", err)
+		return nil, fmt.Errorf("failed to create billing receipt: %w", err)
+	}
+
+	var created []model.BillingReceipt
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created billing receipt data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created billing receipt, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateFilePath records where the PDF of a receipt was stored
+func (r *BillingReceiptRepository) UpdateFilePath(ctx context.Context, id uuid.UUID, filePath string) error {
+	_, _, err := r.client.From("billing_receipt").Update(map[string]interface{}{
+		"file_path": filePath,
+	}, "minimal", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating the file of billing receipt", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
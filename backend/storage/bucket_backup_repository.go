@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// bucketBackupSummaryColumns are the columns of a bucket backup without its manifest
+const bucketBackupSummaryColumns = "id,status,mode,providers,file_count,copied,failed,total_size,manifest_locations,error,started_at,finished_at"
+
+// BucketBackupRepository provides methods to interact with the bucket_backup table in Supabase
+type BucketBackupRepository struct {
+	client *supa.Client
+}
+
+// NewBucketBackupRepository creates a new BucketBackupRepository
+func NewBucketBackupRepository(client *supa.Client) *BucketBackupRepository {
+	return &BucketBackupRepository{
+		client: client,
+	}
+}
+
+// GetRecent retrieves the latest bucket backups without their manifests, newest first
+func (r *BucketBackupRepository) GetRecent(ctx context.Context, limit int) ([]model.BucketBackup, error) {
+	data, _, err := r.client.From("bucket_backup").Select(bucketBackupSummaryColumns, "exact", false).
+		Order("started_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching bucket backups", "error", err)
+		return nil, err
+	}
+
+	var backups []model.BucketBackup
+	err = json.Unmarshal(data, &backups)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bucket backup data", "error", err)
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// GetByID retrieves a bucket backup with its manifest, nil if it does not exist
+func (r *BucketBackupRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.BucketBackup, error) {
+	data, _, err := r.client.From("bucket_backup").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching bucket backup", "id", id, "error", err)
+		return nil, err
+	}
+
+	var backups []model.BucketBackup
+	err = json.Unmarshal(data, &backups)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bucket backup data", "error", err)
+		return nil, err
+	}
+
+	if len(backups) == 0 {
+		return nil, nil
+	}
+	return &backups[0], nil
+}
+
+// GetLatestFinished retrieves the most recent completed or partial backup with its manifest,
+// nil if there is none
+func (r *BucketBackupRepository) GetLatestFinished(ctx context.Context) (*model.BucketBackup, error) {
+	data, _, err := r.client.From("bucket_backup").Select("*", "exact", false).
+		In("status", []string{model.BucketBackupCompleted, model.BucketBackupPartial}).
+		Order("started_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(1, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching latest bucket backup", "error", err)
+		return nil, err
+	}
+
+	var backups []model.BucketBackup
+	err = json.Unmarshal(data, &backups)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bucket backup data", "error", err)
+		return nil, err
+	}
+
+	if len(backups) == 0 {
+		return nil, nil
+	}
+	return &backups[0], nil
+}
+
+// Create records a bucket backup that is starting
+func (r *BucketBackupRepository) Create(ctx context.Context, backup model.BucketBackup) (*model.BucketBackup, error) {
+	if backup.ID == uuid.Nil {
+		backup.ID = uuid.New()
+	}
+	if backup.Manifest == nil {
+		backup.Manifest = []model.BucketBackupEntry{}
+	}
+
+	data, _, err := r.client.From("bucket_backup").Insert(backup, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating bucket backup", "error", err)
+		return nil, fmt.Errorf("failed to create bucket backup: %w", err)
+	}
+
+	var created []model.BucketBackup
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created bucket backup data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created bucket backup, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// SaveResult saves the state, counters and manifest of a bucket backup
+func (r *BucketBackupRepository) SaveResult(ctx context.Context, backup model.BucketBackup) error {
+	if backup.Manifest == nil {
+		backup.Manifest = []model.BucketBackupEntry{}
+	}
+
+	_, _, err := r.client.From("bucket_backup").Update(map[string]interface{}{
+		"status":             backup.Status,
+		"file_count":         backup.FileCount,
+		"copied":             backup.Copied,
+		"failed":             backup.Failed,
+		"total_size":         backup.TotalSize,
+		"manifest":           backup.Manifest,
+		"manifest_locations": backup.ManifestLocations,
+		"error":              backup.Error,
+		"finished_at":        backup.FinishedAt,
+	}, "", "").Eq("id", backup.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving bucket backup", "backup_id", backup.ID, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// BuildingRepository provides methods to interact with the building table in Supabase
+type BuildingRepository struct {
+	client *supa.Client
+}
+
+// NewBuildingRepository creates a new BuildingRepository
+func NewBuildingRepository(client *supa.Client) *BuildingRepository {
+	return &BuildingRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves the buildings sorted by name
+func (r *BuildingRepository) GetAll(ctx context.Context) ([]model.Building, error) {
+	data, _, err := r.client.From("building").Select("*", "exact", false).
+		Order("name", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching buildings", "error", err)
+		return nil, err
+	}
+
+	var buildings []model.Building
+	if err := json.Unmarshal(data, &buildings); err != nil {
+		logging.FromContext(ctx).Error("error parsing building data", "error", err)
+		return nil, err
+	}
+
+	return buildings, nil
+}
+
+// GetByID retrieves a building, nil when it does not exist
+func (r *BuildingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Building, error) {
+	data, _, err := r.client.From("building").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching building", "id", id, "error", err)
+		return nil, err
+	}
+
+	var buildings []model.Building
+	if err := json.Unmarshal(data, &buildings); err != nil {
+		logging.FromContext(ctx).Error("error parsing building data", "error", err)
+		return nil, err
+	}
+
+	if len(buildings) == 0 {
+		return nil, nil
+	}
+
+	return &buildings[0], nil
+}
+
+// Create adds a building
+func (r *BuildingRepository) Create(ctx context.Context, building model.Building) (*model.Building, error) {
+	if building.ID == uuid.Nil {
+		building.ID = uuid.New()
+	}
+	building.CreatedAt = time.Now()
+	building.UpdatedAt = building.CreatedAt
+
+	data, _, err := r.client.From("building").Insert(building, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating building", "name", building.Name, "error", err)
+		return nil, fmt.Errorf("failed to create building: %w", err)
+	}
+
+	var created []model.Building
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created building data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created building, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// Update replaces the data of a building
+func (r *BuildingRepository) Update(ctx context.Context, building model.Building) (*model.Building, error) {
+	building.UpdatedAt = time.Now()
+
+	buildingData := map[string]interface{}{
+		"name":                building.Name,
+		"address":             building.Address,
+		"city":                building.City,
+		"nit":                 building.NIT,
+		"admin_fee":           building.AdminFee,
+		"administrator_name":  building.AdministratorName,
+		"administrator_phone": building.AdministratorPhone,
+		"administrator_email": building.AdministratorEmail,
+		"notes":               building.Notes,
+		"updated_at":          building.UpdatedAt,
+	}
+
+	_, _, err := r.client.From("building").Update(buildingData, "minimal", "").
+		Eq("id", building.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating building", "building_id", building.ID, "error", err)
+		return nil, fmt.Errorf("failed to update building: %w", err)
+	}
+
+	return r.GetByID(ctx, building.ID)
+}
+
+// Delete removes a building. Its properties stay without building.
+func (r *BuildingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, _, err := r.client.From("property").Update(map[string]interface{}{"building_id": nil}, "minimal", "").
+		Eq("building_id", id.String()).Execute(); err != nil {
+		logging.FromContext(ctx).Error("error detaching properties of building", "id", id, "error", err)
+		return err
+	}
+
+	_, _, err := r.client.From("building").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting building", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// defaultReferenceCacheTTL is how long rarely changing records read by ID, such as persons
+	// and properties, are kept in memory. REFERENCE_CACHE_TTL overrides it and 0 disables it,
+	// see RepositoryFactory.UseReferenceCacheTTL.
+	defaultReferenceCacheTTL = time.Minute
+	// maxCacheEntries bounds the memory of each cache, it is emptied when full
+	maxCacheEntries = 10000
+)
+
+// ttlCache keeps values in memory for a while. The repositories invalidate the entries they
+// write, other instances of the API see the change when the entry expires. A nil cache never
+// stores anything.
+type ttlCache[K comparable, V any] struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[K]ttlCacheEntry[V]
+}
+
+type ttlCacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// newTTLCache creates a cache, nil when ttl is 0
+func newTTLCache[K comparable, V any](ttl time.Duration) *ttlCache[K, V] {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ttlCache[K, V]{ttl: ttl, entries: make(map[K]ttlCacheEntry[V])}
+}
+
+// Get returns the value of key if it is cached and not expired
+func (c *ttlCache[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set caches the value of key for the TTL of the cache
+func (c *ttlCache[K, V]) Set(key K, value V) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCacheEntries {
+		c.entries = make(map[K]ttlCacheEntry[V])
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+// Invalidate drops the given keys, or every entry when no key is given
+func (c *ttlCache[K, V]) Invalidate(keys ...K) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(keys) == 0 {
+		c.entries = make(map[K]ttlCacheEntry[V])
+		return
+	}
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
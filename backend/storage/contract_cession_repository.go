@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ContractCessionRepository provides methods to interact with the contract_cession table in Supabase
+type ContractCessionRepository struct {
+	client *supa.Client
+}
+
+// NewContractCessionRepository creates a new ContractCessionRepository
+func NewContractCessionRepository(client *supa.Client) *ContractCessionRepository {
+	return &ContractCessionRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a contract cession by ID
+func (r *ContractCessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.ContractCession, error) {
+	data, _, err := r.client.From("contract_cession").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching contract cession", "id", id, "error", err)
+		return nil, err
+	}
+
+	var cessions []model.ContractCession
+	err = json.Unmarshal(data, &cessions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract cession data", "error", err)
+		return nil, err
+	}
+
+	if len(cessions) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &cessions[0], nil
+}
+
+// GetByRentalID retrieves the cessions of a rental, oldest effective date first
+func (r *ContractCessionRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.ContractCession, error) {
+	data, _, err := r.client.From("contract_cession").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Order("effective_date", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching cessions of rental", "rental_id", rentalID, "error", err)
+		return nil, err
+	}
+
+	var cessions []model.ContractCession
+	err = json.Unmarshal(data, &cessions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract cession data", "error", err)
+		return nil, err
+	}
+
+	return cessions, nil
+}
+
+// GetPendingApplication retrieves the cessions effective at the given time whose rental still
+// points to the previous bank account
+func (r *ContractCessionRepository) GetPendingApplication(ctx context.Context, at time.Time) ([]model.ContractCession, error) {
+	data, _, err := r.client.From("contract_cession").Select("*", "exact", false).
+		Is("applied_at", "null").
+		Lte("effective_date", at.Format(time.RFC3339)).
+		Order("effective_date", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching pending contract cessions", "error", err)
+		return nil, err
+	}
+
+	var cessions []model.ContractCession
+	err = json.Unmarshal(data, &cessions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract cession data", "error", err)
+		return nil, err
+	}
+
+	return cessions, nil
+}
+
+// Create adds a new contract cession
+func (r *ContractCessionRepository) Create(ctx context.Context, cession model.ContractCession) (*model.ContractCession, error) {
+	if cession.ID == uuid.Nil {
+		cession.ID = uuid.New()
+	}
+	if cession.CreatedAt.IsZero() {
+		cession.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("contract_cession").Insert(cession, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating contract cession", "error", err)
+		return nil, fmt.Errorf("failed to create contract cession: %w", err)
+	}
+
+	var created []model.ContractCession
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created contract cession data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created contract cession, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// SetLetter records the notification letter sent to the tenant
+func (r *ContractCessionRepository) SetLetter(ctx context.Context, id uuid.UUID, letterPath string, notifiedAt *time.Time) error {
+	_, _, err := r.client.From("contract_cession").Update(map[string]interface{}{
+		"letter_path": letterPath,
+		"notified_at": notifiedAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving letter of contract cession", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkApplied records that the rental was switched to the bank account of the new owner
+func (r *ContractCessionRepository) MarkApplied(ctx context.Context, id uuid.UUID, appliedAt time.Time) error {
+	_, _, err := r.client.From("contract_cession").Update(map[string]interface{}{
+		"applied_at": appliedAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking contract cession as applied", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// Events of the contract signing audit trail
+const (
+	SigningEventOTPSent     = "otp_sent"
+	SigningEventOTPFailed   = "otp_failed"
+	SigningEventOTPVerified = "otp_verified"
+	SigningEventSigned      = "signed"
+	SigningEventRejected    = "rejected"
+
+	SigningEventReminderSent = "reminder_sent"
+	SigningEventExpired      = "expired"
+
+	SigningEventNotarizationRequested = "notarization_requested"
+	SigningEventNotarized             = "notarized"
+	SigningEventNotarizationRejected  = "notarization_rejected"
+)
+
+// ContractSigningEvent is an entry of the audit trail of a signing request
+type ContractSigningEvent struct {
+	ID        string             `json:"id"`
+	SigningID string             `json:"signing_id"`
+	Event     string             `json:"event"`
+	Channel   string             `json:"channel"`
+	Detail    string             `json:"detail"`
+	IPAddress string             `json:"ip_address"`
+	UserAgent string             `json:"user_agent"`
+	CreatedAt model.FlexibleTime `json:"created_at"`
+}
+
+// ContractSigningEventRepository interfaces with the contract_signing_event table
+type ContractSigningEventRepository struct {
+	client *supa.Client
+}
+
+// NewContractSigningEventRepository creates a new contract signing event repository
+func NewContractSigningEventRepository(client *supa.Client) *ContractSigningEventRepository {
+	return &ContractSigningEventRepository{
+		client: client,
+	}
+}
+
+// GetBySigningID retrieves the audit trail of a signing request, oldest first
+func (r *ContractSigningEventRepository) GetBySigningID(ctx context.Context, signingID string) ([]ContractSigningEvent, error) {
+	data, _, err := r.client.From("contract_signing_event").Select("*", "exact", false).
+		Eq("signing_id", signingID).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching audit trail of signing request", "signing_id", signingID, "error", err)
+		return nil, fmt.Errorf("failed to fetch contract signing events: %w", err)
+	}
+
+	var events []ContractSigningEvent
+	err = json.Unmarshal(data, &events)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract signing event data", "error", err)
+		return nil, fmt.Errorf("failed to parse contract signing event data: %w", err)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Time().Before(events[j].CreatedAt.Time())
+	})
+
+	return events, nil
+}
+
+// Create records a new event of a signing request
+func (r *ContractSigningEventRepository) Create(ctx context.Context, event *ContractSigningEvent) (*ContractSigningEvent, error) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	data, _, err := r.client.From("contract_signing_event").Insert(*event, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating contract signing event", "error", err)
+		return nil, fmt.Errorf("failed to create contract signing event: %w", err)
+	}
+
+	var created []ContractSigningEvent
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract signing event data", "error", err)
+		return nil, fmt.Errorf("failed to parse contract signing event data: %w", err)
+	}
+
+	if len(created) == 0 {
+		return nil, errors.New("no contract signing event was created")
+	}
+
+	return &created[0], nil
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/metrics"
 	"github.com/nescool101/rentManager/model"
 	supa "github.com/supabase-community/supabase-go"
 )
@@ -37,6 +38,59 @@ type ContractSigningRecord struct {
 	SignatureData  []byte     `json:"signature_data,omitempty"`
 	PDFPath        string     `json:"pdf_path,omitempty"`
 	SignedPDFPath  string     `json:"signed_pdf_path,omitempty"`
+	Provider       string     `json:"provider,omitempty"`
+	ExternalID     string     `json:"external_id,omitempty"`
+	// One-time code the recipient must enter before signing, stored as an HMAC
+	OTPHash       string     `json:"otp_hash,omitempty"`
+	OTPChannel    string     `json:"otp_channel,omitempty"`
+	OTPSentAt     *time.Time `json:"otp_sent_at,omitempty"`
+	OTPExpiresAt  *time.Time `json:"otp_expires_at,omitempty"`
+	OTPAttempts   int        `json:"otp_attempts"`
+	OTPVerifiedAt *time.Time `json:"otp_verified_at,omitempty"`
+	// Evidence of where the request was signed or rejected from
+	SignerIP          string   `json:"signer_ip,omitempty"`
+	SignerUserAgent   string   `json:"signer_user_agent,omitempty"`
+	SignerLatitude    *float64 `json:"signer_latitude,omitempty"`
+	SignerLongitude   *float64 `json:"signer_longitude,omitempty"`
+	SignerGeoAccuracy *float64 `json:"signer_geo_accuracy,omitempty"`
+	// Person ID of the admin or manager who requested the signature, notified when it expires
+	RequestedBy string `json:"requested_by,omitempty"`
+	// Smallest number of days before expiry a reminder was sent for
+	LastReminderDays *int `json:"last_reminder_days,omitempty"`
+	// First time the recipient opened the signing page
+	ViewedAt *time.Time `json:"viewed_at,omitempty"`
+	// Signed document, one of the model.SigningDocument constants
+	DocumentType string `json:"document_type,omitempty"`
+}
+
+// IsInspection reports whether the request signs an inspection instead of a contract
+func (r ContractSigningRecord) IsInspection() bool {
+	return r.DocumentType == model.SigningDocumentInspection
+}
+
+// Evidence returns the IP, user agent and location the request was signed or rejected from
+func (r ContractSigningRecord) Evidence() *model.SigningEvidence {
+	return &model.SigningEvidence{
+		IPAddress: r.SignerIP,
+		UserAgent: r.SignerUserAgent,
+		Latitude:  r.SignerLatitude,
+		Longitude: r.SignerLongitude,
+		Accuracy:  r.SignerGeoAccuracy,
+	}
+}
+
+// setEvidence stores the evidence of the signer in the record, a nil evidence leaves it unchanged
+func (r *ContractSigningRecord) setEvidence(evidence *model.SigningEvidence) {
+	if evidence == nil {
+		return
+	}
+	r.SignerIP = evidence.IPAddress
+	r.SignerUserAgent = evidence.UserAgent
+	if evidence.HasLocation() {
+		r.SignerLatitude = evidence.Latitude
+		r.SignerLongitude = evidence.Longitude
+		r.SignerGeoAccuracy = evidence.Accuracy
+	}
 }
 
 // CreateSigningRequest creates a new contract signing request
@@ -50,11 +104,18 @@ func (r *ContractSigningRepository) CreateSigningRequest(ctx context.Context, re
 		CreatedAt:      request.CreatedAt,
 		ExpiresAt:      request.ExpiresAt,
 		SignedAt:       request.SignedAt,
+		Provider:       request.Provider,
+		ExternalID:     request.ExternalID,
+		RequestedBy:    request.RequestedBy,
+		DocumentType:   request.DocumentType,
+	}
+	if record.DocumentType == "" {
+		record.DocumentType = model.SigningDocumentContract
 	}
 
 	data, count, err := r.client.From("contract_signatures").Insert(record, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating contract signature request: %v", err)
+		logging.FromContext(ctx).Error("error creating contract signature request", "error", err)
 		return nil, err
 	}
 
@@ -65,7 +126,7 @@ func (r *ContractSigningRepository) CreateSigningRequest(ctx context.Context, re
 	var createdRecords []ContractSigningRecord
 	err = json.Unmarshal(data, &createdRecords)
 	if err != nil {
-		log.Printf("Error parsing created contract signature data: %v", err)
+		logging.FromContext(ctx).Error("error parsing created contract signature data", "error", err)
 		return nil, err
 	}
 
@@ -73,6 +134,7 @@ func (r *ContractSigningRepository) CreateSigningRequest(ctx context.Context, re
 		return nil, fmt.Errorf("no record returned after creation")
 	}
 
+	metrics.SigningRequests(createdRecords[0].Status, 1)
 	return &createdRecords[0], nil
 }
 
@@ -83,7 +145,7 @@ func (r *ContractSigningRepository) GetByID(ctx context.Context, id string) (*Co
 		Eq("id", id).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching contract signature by ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error fetching contract signature", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -93,7 +155,7 @@ func (r *ContractSigningRepository) GetByID(ctx context.Context, id string) (*Co
 
 	err = json.Unmarshal(data, &records)
 	if err != nil {
-		log.Printf("Error parsing contract signature data for ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error parsing contract signature data", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -111,7 +173,7 @@ func (r *ContractSigningRepository) GetByContractID(ctx context.Context, contrac
 		Eq("contract_id", contractID).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching contract signatures by contract_id %s: %v", contractID, err)
+		logging.FromContext(ctx).Error("error fetching contract signatures by contract_id", "contract_id", contractID, "error", err)
 		return nil, err
 	}
 
@@ -121,13 +183,43 @@ func (r *ContractSigningRepository) GetByContractID(ctx context.Context, contrac
 
 	err = json.Unmarshal(data, &records)
 	if err != nil {
-		log.Printf("Error parsing contract signature data for contract_id %s: %v", contractID, err)
+		logging.FromContext(ctx).Error("error parsing contract signature data for contract_id", "contract_id", contractID, "error", err)
 		return nil, err
 	}
 
 	return records, nil
 }
 
+// GetByExternalID retrieves a contract signing request by the ID assigned by an external e-sign provider
+func (r *ContractSigningRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*ContractSigningRecord, error) {
+	var records []ContractSigningRecord
+	data, count, err := r.client.From("contract_signatures").Select("*", "exact", false).
+		Eq("provider", provider).
+		Eq("external_id", externalID).
+		Execute()
+
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching contract signature", "provider", provider, "external_id", externalID, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract signature data", "provider", provider, "external_id", externalID, "error", err)
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	return &records[0], nil
+}
+
 // GetByRecipientID retrieves contract signing requests by recipient ID
 func (r *ContractSigningRepository) GetByRecipientID(ctx context.Context, recipientID string) ([]ContractSigningRecord, error) {
 	var records []ContractSigningRecord
@@ -135,7 +227,7 @@ func (r *ContractSigningRepository) GetByRecipientID(ctx context.Context, recipi
 		Eq("recipient_id", recipientID).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching contract signatures by recipient_id %s: %v", recipientID, err)
+		logging.FromContext(ctx).Error("error fetching contract signatures by recipient_id", "recipient_id", recipientID, "error", err)
 		return nil, err
 	}
 
@@ -145,7 +237,7 @@ func (r *ContractSigningRepository) GetByRecipientID(ctx context.Context, recipi
 
 	err = json.Unmarshal(data, &records)
 	if err != nil {
-		log.Printf("Error parsing contract signature data for recipient_id %s: %v", recipientID, err)
+		logging.FromContext(ctx).Error("error parsing contract signature data for recipient_id", "recipient_id", recipientID, "error", err)
 		return nil, err
 	}
 
@@ -161,7 +253,7 @@ func (r *ContractSigningRepository) GetPendingRequests(ctx context.Context) ([]C
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching pending contract signatures: %v", err)
+		logging.FromContext(ctx).Error("error fetching pending contract signatures", "error", err)
 		return nil, err
 	}
 
@@ -171,15 +263,38 @@ func (r *ContractSigningRepository) GetPendingRequests(ctx context.Context) ([]C
 
 	err = json.Unmarshal(data, &records)
 	if err != nil {
-		log.Printf("Error parsing pending contract signature data: %v", err)
+		logging.FromContext(ctx).Error("error parsing pending contract signature data", "error", err)
 		return nil, err
 	}
 
 	return records, nil
 }
 
-// MarkAsSigned marks a contract signing request as signed
-func (r *ContractSigningRepository) MarkAsSigned(ctx context.Context, id string, signedPDFPath string) error {
+// GetSignedBetween retrieves the contract signing requests signed within a period
+func (r *ContractSigningRepository) GetSignedBetween(ctx context.Context, from, to time.Time) ([]ContractSigningRecord, error) {
+	var records []ContractSigningRecord
+	data, _, err := r.client.From("contract_signatures").Select("*", "exact", false).
+		Eq("status", "signed").
+		Gte("signed_at", from.UTC().Format(time.RFC3339)).
+		Lt("signed_at", to.UTC().Format(time.RFC3339)).
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching signed contract signatures", "error", err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing signed contract signature data", "error", err)
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// MarkAsSigned marks a contract signing request as signed. The evidence of the signer is nil
+// when the signature was collected by an external provider.
+func (r *ContractSigningRepository) MarkAsSigned(ctx context.Context, id string, signedPDFPath string, evidence *model.SigningEvidence) error {
 	record, err := r.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -194,20 +309,67 @@ func (r *ContractSigningRepository) MarkAsSigned(ctx context.Context, id string,
 	record.Status = string(model.StatusSigned)
 	record.SignedAt = &now
 	record.SignedPDFPath = signedPDFPath
+	record.setEvidence(evidence)
 
 	_, _, err = r.client.From("contract_signatures").Update(*record, "exact", "").
 		Eq("id", id).Execute()
 
 	if err != nil {
-		log.Printf("Error marking contract signature as signed for ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error marking contract signature as signed", "id", id, "error", err)
+		return err
+	}
+
+	metrics.SigningRequests(string(model.StatusSigned), 1)
+	return nil
+}
+
+// SaveOTP stores a new one-time signing code, replacing the previous one and its failed attempts
+func (r *ContractSigningRepository) SaveOTP(ctx context.Context, id, otpHash, channel string, sentAt, expiresAt time.Time) error {
+	_, _, err := r.client.From("contract_signatures").Update(map[string]interface{}{
+		"otp_hash":       otpHash,
+		"otp_channel":    channel,
+		"otp_sent_at":    sentAt,
+		"otp_expires_at": expiresAt,
+		"otp_attempts":   0,
+	}, "", "").Eq("id", id).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving signing code", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetOTPAttempts records the failed attempts of the current one-time signing code
+func (r *ContractSigningRepository) SetOTPAttempts(ctx context.Context, id string, attempts int) error {
+	_, _, err := r.client.From("contract_signatures").Update(map[string]interface{}{
+		"otp_attempts": attempts,
+	}, "", "").Eq("id", id).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating signing code attempts", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkOTPVerified consumes the one-time signing code so it cannot be used again
+func (r *ContractSigningRepository) MarkOTPVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	_, _, err := r.client.From("contract_signatures").Update(map[string]interface{}{
+		"otp_hash":        "",
+		"otp_verified_at": verifiedAt,
+	}, "", "").Eq("id", id).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking signing code as verified", "id", id, "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// MarkAsRejected marks a contract signing request as rejected
-func (r *ContractSigningRepository) MarkAsRejected(ctx context.Context, id string) error {
+// MarkAsRejected marks a contract signing request as rejected. The evidence of the signer is nil
+// when the rejection was reported by an external provider.
+func (r *ContractSigningRepository) MarkAsRejected(ctx context.Context, id string, evidence *model.SigningEvidence) error {
 	record, err := r.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -221,12 +383,45 @@ func (r *ContractSigningRepository) MarkAsRejected(ctx context.Context, id strin
 	now := time.Now()
 	record.Status = string(model.StatusRejected)
 	record.RejectedAt = &now
+	record.setEvidence(evidence)
 
 	_, _, err = r.client.From("contract_signatures").Update(*record, "exact", "").
 		Eq("id", id).Execute()
 
 	if err != nil {
-		log.Printf("Error marking contract signature as rejected for ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error marking contract signature as rejected", "id", id, "error", err)
+		return err
+	}
+
+	metrics.SigningRequests(string(model.StatusRejected), 1)
+	return nil
+}
+
+// MarkViewed records the first time the recipient opened a signing request and reports whether
+// this was it, later views are ignored
+func (r *ContractSigningRepository) MarkViewed(ctx context.Context, id string, viewedAt time.Time) (bool, error) {
+	data, _, err := r.client.From("contract_signatures").Update(map[string]interface{}{
+		"viewed_at": viewedAt,
+	}, "", "").Eq("id", id).Is("viewed_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking signing request as viewed", "id", id, "error", err)
+		return false, err
+	}
+
+	var updated []ContractSigningRecord
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+// SetLastReminder records the days before expiry of the last reminder sent to the recipient
+func (r *ContractSigningRepository) SetLastReminder(ctx context.Context, id string, days int) error {
+	_, _, err := r.client.From("contract_signatures").Update(map[string]interface{}{
+		"last_reminder_days": days,
+	}, "", "").Eq("id", id).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving signing reminder", "id", id, "error", err)
 		return err
 	}
 
@@ -235,6 +430,13 @@ func (r *ContractSigningRepository) MarkAsRejected(ctx context.Context, id strin
 
 // UpdateExpiredStatuses updates statuses for expired signing requests
 func (r *ContractSigningRepository) UpdateExpiredStatuses(ctx context.Context) (int, error) {
+	expired, err := r.ExpirePendingRequests(ctx)
+	return len(expired), err
+}
+
+// ExpirePendingRequests marks the pending requests past their expiry date as expired and
+// returns the ones updated
+func (r *ContractSigningRepository) ExpirePendingRequests(ctx context.Context) ([]ContractSigningRecord, error) {
 	// Find expired pending requests
 	var records []ContractSigningRecord
 	data, count, err := r.client.From("contract_signatures").Select("*", "exact", false).
@@ -243,33 +445,34 @@ func (r *ContractSigningRepository) UpdateExpiredStatuses(ctx context.Context) (
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching expired contract signatures: %v", err)
-		return 0, err
+		logging.FromContext(ctx).Error("error fetching expired contract signatures", "error", err)
+		return nil, err
 	}
 
 	if count == 0 {
-		return 0, nil // No expired requests
+		return nil, nil // No expired requests
 	}
 
 	err = json.Unmarshal(data, &records)
 	if err != nil {
-		log.Printf("Error parsing expired contract signature data: %v", err)
-		return 0, err
+		logging.FromContext(ctx).Error("error parsing expired contract signature data", "error", err)
+		return nil, err
 	}
 
 	// Update each expired record
-	updatedCount := 0
+	var expired []ContractSigningRecord
 	for _, record := range records {
 		record.Status = string(model.StatusExpired)
 		_, _, err = r.client.From("contract_signatures").Update(record, "exact", "").
 			Eq("id", record.ID).Execute()
 
 		if err != nil {
-			log.Printf("Error updating expired contract signature for ID %s: %v", record.ID, err)
+			logging.FromContext(ctx).Error("error updating expired contract signature", "record_id", record.ID, "error", err)
 			continue
 		}
-		updatedCount++
+		expired = append(expired, record)
 	}
 
-	return updatedCount, nil
+	metrics.SigningRequests(string(model.StatusExpired), len(expired))
+	return expired, nil
 }
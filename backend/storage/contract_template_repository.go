@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ContractTemplateRepository provides methods to interact with the contract_template table in Supabase
+type ContractTemplateRepository struct {
+	client *supa.Client
+}
+
+// NewContractTemplateRepository creates a new ContractTemplateRepository
+func NewContractTemplateRepository(client *supa.Client) *ContractTemplateRepository {
+	return &ContractTemplateRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves all contract templates
+func (r *ContractTemplateRepository) GetAll(ctx context.Context) ([]model.ContractTemplate, error) {
+	data, count, err := r.client.From("contract_template").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching contract templates", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("retrieved contract templates", "count", count)
+
+	var templates []model.ContractTemplate
+	err = json.Unmarshal([]byte(data), &templates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract template data", "error", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetByID retrieves a contract template by ID
+func (r *ContractTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.ContractTemplate, error) {
+	data, count, err := r.client.From("contract_template").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching contract template", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var templates []model.ContractTemplate
+	err = json.Unmarshal([]byte(data), &templates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract template data", "error", err)
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &templates[0], nil
+}
+
+// GetDefault retrieves the default template of a manager for a contract type, falling back to
+// the shared default template of that type. Templates without a contract type are vivienda
+// urbana templates. Returns nil when no default template is stored.
+func (r *ContractTemplateRepository) GetDefault(ctx context.Context, managerIDs []uuid.UUID, contractType string) (*model.ContractTemplate, error) {
+	data, _, err := r.client.From("contract_template").Select("*", "exact", false).
+		Eq("is_default", "true").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching default contract templates", "error", err)
+		return nil, err
+	}
+
+	var templates []model.ContractTemplate
+	err = json.Unmarshal([]byte(data), &templates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing contract template data", "error", err)
+		return nil, err
+	}
+
+	if contractType == "" {
+		contractType = model.ContractTypeViviendaUrbana
+	}
+	ofType := templates[:0]
+	for _, template := range templates {
+		if template.ContractType == contractType ||
+			(template.ContractType == "" && contractType == model.ContractTypeViviendaUrbana) {
+			ofType = append(ofType, template)
+		}
+	}
+	templates = ofType
+
+	for _, managerID := range managerIDs {
+		for i := range templates {
+			if templates[i].ManagerID != nil && *templates[i].ManagerID == managerID {
+				return &templates[i], nil
+			}
+		}
+	}
+	for i := range templates {
+		if templates[i].ManagerID == nil {
+			return &templates[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Create adds a new contract template
+func (r *ContractTemplateRepository) Create(ctx context.Context, template model.ContractTemplate) (*model.ContractTemplate, error) {
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("contract_template").Insert(template, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating contract template", "error", err)
+		return nil, fmt.Errorf("failed to create contract template: %w", err)
+	}
+
+	var createdTemplates []model.ContractTemplate
+	err = json.Unmarshal(data, &createdTemplates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created contract template data", "error", err)
+		return nil, err
+	}
+
+	if len(createdTemplates) == 0 {
+		return nil, fmt.Errorf("failed to parse created contract template, empty result set")
+	}
+
+	return &createdTemplates[0], nil
+}
+
+// Update updates an existing contract template
+func (r *ContractTemplateRepository) Update(ctx context.Context, template model.ContractTemplate) (*model.ContractTemplate, error) {
+	templateData := map[string]interface{}{
+		"name":             template.Name,
+		"manager_id":       template.ManagerID,
+		"contract_type":    template.ContractType,
+		"title":            template.Title,
+		"clauses":          template.Clauses,
+		"closing_text":     template.ClosingText,
+		"signature_blocks": template.SignatureBlocks,
+		"variables":        template.Variables,
+		"is_default":       template.IsDefault,
+	}
+
+	data, count, err := r.client.From("contract_template").Update(templateData, "exact", "").
+		Eq("id", template.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating contract template", "template_id", template.ID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(ctx, template.ID)
+	}
+
+	var updatedTemplates []model.ContractTemplate
+	err = json.Unmarshal(data, &updatedTemplates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated contract template data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedTemplates) == 0 {
+		return r.GetByID(ctx, template.ID)
+	}
+
+	return &updatedTemplates[0], nil
+}
+
+// Delete removes a contract template
+func (r *ContractTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("contract_template").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting contract template", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
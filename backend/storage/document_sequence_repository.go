@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// DocumentSequenceRepository provides methods to interact with the document_sequence table in Supabase
+type DocumentSequenceRepository struct {
+	client *supa.Client
+}
+
+// NewDocumentSequenceRepository creates a new DocumentSequenceRepository
+func NewDocumentSequenceRepository(client *supa.Client) *DocumentSequenceRepository {
+	return &DocumentSequenceRepository{
+		client: client,
+	}
+}
+
+// Next reserves and returns the next consecutive of a kind of document of an organization
+// (uuid.Nil for the documents of properties without one) in a year, starting from 1. The
+// database increments the sequence in a single statement, so concurrent calls never get the
+// same number.
+func (r *DocumentSequenceRepository) Next(ctx context.Context, organizationID uuid.UUID, kind model.DocumentKind, year int) (int64, error) {
+	type Request struct {
+		OrganizationID uuid.UUID          `json:"sequence_organization_id"`
+		Kind           model.DocumentKind `json:"sequence_kind"`
+		Year           int                `json:"sequence_year"`
+	}
+
+	var number int64
+	err := callTransaction(r.client, "next_document_number", Request{OrganizationID: organizationID, Kind: kind, Year: year}, &number)
+	if err != nil {
+		logging.FromContext(ctx).Error("error reserving the next number of organization", "kind", kind, "organization_id", organizationID, "year", year, "error", err)
+		return 0, err
+	}
+	return number, nil
+}
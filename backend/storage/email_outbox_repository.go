@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// EmailOutboxRepository provides methods to interact with the email_outbox table in Supabase
+type EmailOutboxRepository struct {
+	client *supa.Client
+}
+
+// NewEmailOutboxRepository creates a new EmailOutboxRepository
+func NewEmailOutboxRepository(client *supa.Client) *EmailOutboxRepository {
+	return &EmailOutboxRepository{
+		client: client,
+	}
+}
+
+// Create queues an email
+func (r *EmailOutboxRepository) Create(ctx context.Context, email model.OutboxEmail) (*model.OutboxEmail, error) {
+	if email.ID == uuid.Nil {
+		email.ID = uuid.New()
+	}
+	if email.Status == "" {
+		email.Status = model.OutboxStatusPending
+	}
+	if email.CreatedAt.IsZero() {
+		email.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("email_outbox").Insert(email, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error queuing email", "to_email", email.ToEmail, "error", err)
+		return nil, fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	var createdEmails []model.OutboxEmail
+	err = json.Unmarshal(data, &createdEmails)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing queued email data", "error", err)
+		return nil, err
+	}
+
+	if len(createdEmails) == 0 {
+		return nil, fmt.Errorf("failed to parse queued email, empty result set")
+	}
+
+	return &createdEmails[0], nil
+}
+
+// GetDue retrieves up to limit pending emails whose send time has been reached, oldest first
+func (r *EmailOutboxRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEmail, error) {
+	data, _, err := r.client.From("email_outbox").Select("*", "exact", false).
+		Eq("status", model.OutboxStatusPending).
+		Lte("send_at", now.UTC().Format(time.RFC3339)).
+		Order("send_at", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching due outbox emails", "error", err)
+		return nil, err
+	}
+
+	var emails []model.OutboxEmail
+	err = json.Unmarshal([]byte(data), &emails)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing outbox email data", "error", err)
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// GetOpenedByPersonID retrieves the most recent emails opened by a person, up to limit
+func (r *EmailOutboxRepository) GetOpenedByPersonID(ctx context.Context, personID uuid.UUID, limit int) ([]model.OutboxEmail, error) {
+	data, _, err := r.client.From("email_outbox").Select("*", "exact", false).
+		Eq("person_id", personID.String()).
+		Not("opened_at", "is", "null").
+		Order("opened_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching opened emails for person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	var emails []model.OutboxEmail
+	err = json.Unmarshal([]byte(data), &emails)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing outbox email data", "error", err)
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// MarkSent records the delivery of an email
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, attempts int, sentAt time.Time) error {
+	_, _, err := r.client.From("email_outbox").Update(map[string]interface{}{
+		"status":     model.OutboxStatusSent,
+		"attempts":   attempts,
+		"last_error": "",
+		"sent_at":    sentAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking outbox email as sent", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery. The email is retried at retryAt while status is pending.
+func (r *EmailOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, status string, attempts int, lastError string, retryAt time.Time) error {
+	_, _, err := r.client.From("email_outbox").Update(map[string]interface{}{
+		"status":     status,
+		"attempts":   attempts,
+		"last_error": lastError,
+		"send_at":    retryAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking outbox email as failed", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkOpened records the first time an email was opened, later opens are ignored
+func (r *EmailOutboxRepository) MarkOpened(ctx context.Context, id uuid.UUID, openedAt time.Time) error {
+	_, _, err := r.client.From("email_outbox").Update(map[string]interface{}{
+		"opened_at": openedAt,
+	}, "", "").Eq("id", id.String()).Is("opened_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking outbox email as opened", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
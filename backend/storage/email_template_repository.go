@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// EmailTemplateRepository provides methods to interact with the email_template table in Supabase
+type EmailTemplateRepository struct {
+	client *supa.Client
+}
+
+// NewEmailTemplateRepository creates a new EmailTemplateRepository
+func NewEmailTemplateRepository(client *supa.Client) *EmailTemplateRepository {
+	return &EmailTemplateRepository{
+		client: client,
+	}
+}
+
+// GetLatest retrieves the current version of every customized email template
+func (r *EmailTemplateRepository) GetLatest(ctx context.Context) (map[string]model.EmailTemplate, error) {
+	data, _, err := r.client.From("email_template").Select("*", "exact", false).
+		Order("version", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching email templates", "error", err)
+		return nil, err
+	}
+
+	var templates []model.EmailTemplate
+	err = json.Unmarshal(data, &templates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing email template data", "error", err)
+		return nil, err
+	}
+
+	latest := make(map[string]model.EmailTemplate)
+	for _, template := range templates {
+		if current, ok := latest[template.Key]; !ok || template.Version > current.Version {
+			latest[template.Key] = template
+		}
+	}
+	return latest, nil
+}
+
+// GetVersions retrieves the versions of an email template, newest first
+func (r *EmailTemplateRepository) GetVersions(ctx context.Context, key string) ([]model.EmailTemplate, error) {
+	data, _, err := r.client.From("email_template").Select("*", "exact", false).
+		Eq("key", key).
+		Order("version", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching versions of email template", "key", key, "error", err)
+		return nil, err
+	}
+
+	var templates []model.EmailTemplate
+	err = json.Unmarshal(data, &templates)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing email template data", "error", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Create adds a new version of an email template
+func (r *EmailTemplateRepository) Create(ctx context.Context, template model.EmailTemplate) (*model.EmailTemplate, error) {
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("email_template").Insert(template, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating email template", "error", err)
+		return nil, fmt.Errorf("failed to create email template: %w", err)
+	}
+
+	var created []model.EmailTemplate
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created email template data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created email template, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// DeleteByKey removes every version of an email template, so the default is sent again
+func (r *EmailTemplateRepository) DeleteByKey(ctx context.Context, key string) error {
+	_, _, err := r.client.From("email_template").Delete("minimal", "").
+		Eq("key", key).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting email template", "key", key, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// FeatureFlagRepository provides methods to interact with the feature_flag table in Supabase
+type FeatureFlagRepository struct {
+	client *supa.Client
+}
+
+// NewFeatureFlagRepository creates a new FeatureFlagRepository
+func NewFeatureFlagRepository(client *supa.Client) *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		client: client,
+	}
+}
+
+// featureFlagRow is the stored part of a feature flag
+type featureFlagRow struct {
+	Key       string     `json:"key"`
+	Enabled   bool       `json:"enabled"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// GetAll retrieves the feature flags saved by admins
+func (r *FeatureFlagRepository) GetAll(ctx context.Context) ([]model.FeatureFlag, error) {
+	data, _, err := r.client.From("feature_flag").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching feature flags", "error", err)
+		return nil, err
+	}
+
+	var flags []model.FeatureFlag
+	err = json.Unmarshal(data, &flags)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing feature flag data", "error", err)
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// Save creates or replaces the value of a feature flag
+func (r *FeatureFlagRepository) Save(ctx context.Context, flag model.FeatureFlag) (*model.FeatureFlag, error) {
+	now := time.Now()
+	row := featureFlagRow{
+		Key:       flag.Key,
+		Enabled:   flag.Enabled,
+		UpdatedBy: flag.UpdatedBy,
+		UpdatedAt: &now,
+	}
+
+	data, _, err := r.client.From("feature_flag").Upsert(row, "key", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving feature flag", "key", flag.Key, "error", err)
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	var saved []model.FeatureFlag
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing saved feature flag data", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved feature flag, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// Delete removes the saved value of a feature flag, which falls back to the environment
+func (r *FeatureFlagRepository) Delete(ctx context.Context, key string) error {
+	_, _, err := r.client.From("feature_flag").Delete("minimal", "").Eq("key", key).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting feature flag", "key", key, "error", err)
+		return err
+	}
+
+	return nil
+}
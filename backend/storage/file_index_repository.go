@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// FileIndexRepository provides methods to interact with the file_index table in Supabase
+type FileIndexRepository struct {
+	client *supa.Client
+}
+
+// NewFileIndexRepository creates a new FileIndexRepository
+func NewFileIndexRepository(client *supa.Client) *FileIndexRepository {
+	return &FileIndexRepository{
+		client: client,
+	}
+}
+
+// List retrieves the indexed files of a user, or of the whole bucket when userID is empty,
+// sorted by path
+func (r *FileIndexRepository) List(ctx context.Context, userID string) ([]model.FileIndexEntry, error) {
+	query := r.client.From("file_index").Select("*", "exact", false)
+	if userID != "" {
+		query = query.Eq("user_id", userID)
+	}
+
+	data, _, err := query.Order("path", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching file index", "error", err)
+		return nil, err
+	}
+
+	var entries []model.FileIndexEntry
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing file index data", "error", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Upsert records files in the index, replacing the entries with the same path
+func (r *FileIndexRepository) Upsert(ctx context.Context, entries []model.FileIndexEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("file_index").Upsert(entries, "path", "minimal", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error upserting file index entries", "entries_count", len(entries), "error", err)
+		return fmt.Errorf("failed to upsert file index: %w", err)
+	}
+	return nil
+}
+
+// Delete removes files from the index
+func (r *FileIndexRepository) Delete(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("file_index").Delete("", "").In("path", paths).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting file index entries", "paths_count", len(paths), "error", err)
+		return fmt.Errorf("failed to delete file index entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// FileMetadataRepository provides methods to interact with the file_metadata table in Supabase
+type FileMetadataRepository struct {
+	client *supa.Client
+}
+
+// NewFileMetadataRepository creates a new FileMetadataRepository
+func NewFileMetadataRepository(client *supa.Client) *FileMetadataRepository {
+	return &FileMetadataRepository{
+		client: client,
+	}
+}
+
+// Find retrieves the metadata of the files matching a filter
+func (r *FileMetadataRepository) Find(ctx context.Context, filter model.FileMetadataFilter) ([]model.FileMetadata, error) {
+	query := r.client.From("file_metadata").Select("*", "exact", false)
+	if filter.UserID != "" {
+		query = query.Eq("user_id", filter.UserID)
+	}
+	if filter.Category != "" {
+		query = query.Eq("category", filter.Category)
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Contains("tags", filter.Tags)
+	}
+
+	data, _, err := query.Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching file metadata", "error", err)
+		return nil, err
+	}
+
+	var metadata []model.FileMetadata
+	err = json.Unmarshal(data, &metadata)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing file metadata", "error", err)
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// GetByRental retrieves the metadata of the files linked to a rental or to its contract, whose
+// ID is the rental ID, newest first
+func (r *FileMetadataRepository) GetByRental(ctx context.Context, rentalID uuid.UUID) ([]model.FileMetadata, error) {
+	id := rentalID.String()
+	data, _, err := r.client.From("file_metadata").Select("*", "exact", false).
+		Or(fmt.Sprintf("rental_id.eq.%s,contract_id.eq.%s", id, id), "").
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching files of rental", "id", id, "error", err)
+		return nil, err
+	}
+
+	var metadata []model.FileMetadata
+	err = json.Unmarshal(data, &metadata)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing file metadata", "error", err)
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// Save creates or replaces the metadata of a file
+func (r *FileMetadataRepository) Save(ctx context.Context, metadata model.FileMetadata) (*model.FileMetadata, error) {
+	if metadata.ID == uuid.Nil {
+		metadata.ID = uuid.New()
+	}
+	if metadata.Tags == nil {
+		metadata.Tags = []string{}
+	}
+
+	data, _, err := r.client.From("file_metadata").Upsert(metadata, "file_name", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving metadata of file", "file_name", metadata.FileName, "error", err)
+		return nil, fmt.Errorf("failed to save file metadata: %w", err)
+	}
+
+	var saved []model.FileMetadata
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing saved file metadata", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved file metadata, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// DeleteByFileName removes the metadata of a deleted file
+func (r *FileMetadataRepository) DeleteByFileName(ctx context.Context, fileName string) error {
+	_, _, err := r.client.From("file_metadata").Delete("minimal", "").Eq("file_name", fileName).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting metadata of file", "file_name", fileName, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// FileScanRepository provides methods to interact with the file_scan table in Supabase
+type FileScanRepository struct {
+	client *supa.Client
+}
+
+// NewFileScanRepository creates a new FileScanRepository
+func NewFileScanRepository(client *supa.Client) *FileScanRepository {
+	return &FileScanRepository{
+		client: client,
+	}
+}
+
+// GetRecent retrieves the latest scans, newest first, optionally only the ones with a result
+func (r *FileScanRepository) GetRecent(ctx context.Context, result string, limit int) ([]model.FileScan, error) {
+	query := r.client.From("file_scan").Select("*", "exact", false)
+	if result != "" {
+		query = query.Eq("result", result)
+	}
+	data, _, err := query.Order("scanned_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching file scans", "error", err)
+		return nil, err
+	}
+
+	var scans []model.FileScan
+	err = json.Unmarshal(data, &scans)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing file scan data", "error", err)
+		return nil, err
+	}
+
+	return scans, nil
+}
+
+// Create records a file scan
+func (r *FileScanRepository) Create(ctx context.Context, scan model.FileScan) (*model.FileScan, error) {
+	if scan.ID == uuid.Nil {
+		scan.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("file_scan").Insert(scan, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating file scan", "path", scan.Path, "error", err)
+		return nil, fmt.Errorf("failed to create file scan: %w", err)
+	}
+
+	var created []model.FileScan
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created file scan data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created file scan, empty result set")
+	}
+
+	return &created[0], nil
+}
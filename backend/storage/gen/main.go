@@ -0,0 +1,230 @@
+// Command gen declares an interface with the exported methods of every repository of the
+// storage package, so controllers and services can take the interface and be given an
+// alternate implementation. Run it through `go generate ./storage` from the backend directory
+// after changing the methods of a repository.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// repositorySuffix is the suffix of the repository types; the interface of XRepository is XStore
+const repositorySuffix = "Repository"
+
+// modulePath is the module of the backend, whose imports go in the last group
+const modulePath = "github.com/nescool101/rentManager"
+
+type method struct {
+	Doc       []string
+	Signature string
+}
+
+type repository struct {
+	Name    string
+	Methods []method
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the storage package")
+	out := flag.String("out", "interfaces.gen.go", "generated file")
+	flag.Parse()
+
+	repositories, imports, err := parseRepositories(*dir, filepath.Base(*out))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := format.Source(generate(repositories, imports))
+	if err != nil {
+		log.Fatalf("formatting generated interfaces: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), source, 0644); err != nil {
+		log.Fatalf("writing generated interfaces: %v", err)
+	}
+}
+
+// parseRepositories reads the exported methods of the repository structs, in source order,
+// and the imports their signatures may need by package name
+func parseRepositories(dir, generated string) ([]repository, map[string]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return info.Name() != generated && !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, ok := pkgs["storage"]
+	if !ok {
+		return nil, nil, fmt.Errorf("no storage package in %s", dir)
+	}
+
+	var files []string
+	for name := range pkg.Files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	structs := map[string]bool{}
+	methods := map[string][]method{}
+	imports := map[string]string{}
+	for _, name := range files {
+		file := pkg.Files[name]
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			alias := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				alias = spec.Name.Name
+			}
+			imports[alias] = path
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if _, ok := typeSpec.Type.(*ast.StructType); ok && strings.HasSuffix(typeSpec.Name.Name, repositorySuffix) {
+						structs[typeSpec.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				receiver := receiverName(decl)
+				if receiver == "" || !decl.Name.IsExported() {
+					continue
+				}
+				var signature bytes.Buffer
+				if err := printer.Fprint(&signature, fset, decl.Type); err != nil {
+					return nil, nil, err
+				}
+				var doc []string
+				if decl.Doc != nil {
+					for _, comment := range decl.Doc.List {
+						doc = append(doc, comment.Text)
+					}
+				}
+				methods[receiver] = append(methods[receiver], method{
+					Doc:       doc,
+					Signature: decl.Name.Name + strings.TrimPrefix(signature.String(), "func"),
+				})
+			}
+		}
+	}
+
+	var repositories []repository
+	for name := range structs {
+		if len(methods[name]) > 0 {
+			repositories = append(repositories, repository{Name: name, Methods: methods[name]})
+		}
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].Name < repositories[j].Name })
+	return repositories, imports, nil
+}
+
+// receiverName is the type of a pointer receiver, empty for functions and value receivers
+func receiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	star, ok := decl.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := star.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+// storeName is the name of the interface of a repository
+func storeName(repository string) string {
+	return strings.TrimSuffix(repository, repositorySuffix) + "Store"
+}
+
+func generate(repositories []repository, imports map[string]string) []byte {
+	var body bytes.Buffer
+	for _, repo := range repositories {
+		store := storeName(repo.Name)
+		fmt.Fprintf(&body, "\n// %s is the interface of %s\n", store, repo.Name)
+		fmt.Fprintf(&body, "type %s interface {\n", store)
+		for i, m := range repo.Methods {
+			if i > 0 && len(m.Doc) > 0 {
+				body.WriteString("\n")
+			}
+			for _, line := range m.Doc {
+				body.WriteString(line + "\n")
+			}
+			body.WriteString(m.Signature + "\n")
+		}
+		body.WriteString("}\n")
+	}
+
+	body.WriteString("\n// The repositories implement their interfaces\nvar (\n")
+	for _, repo := range repositories {
+		fmt.Fprintf(&body, "_ %s = (*%s)(nil)\n", storeName(repo.Name), repo.Name)
+	}
+	body.WriteString(")\n")
+
+	// Only the packages the signatures reference are imported
+	var used []string
+	for alias, path := range imports {
+		if strings.Contains(body.String(), alias+".") {
+			if path[strings.LastIndex(path, "/")+1:] != alias {
+				used = append(used, alias+" "+strconv.Quote(path))
+			} else {
+				used = append(used, strconv.Quote(path))
+			}
+		}
+	}
+	sort.Slice(used, func(i, j int) bool { return importPath(used[i]) < importPath(used[j]) })
+
+	// Standard library, third party and module imports in separate groups
+	groups := make([][]string, 3)
+	for _, spec := range used {
+		path, _ := strconv.Unquote(importPath(spec))
+		switch {
+		case strings.HasPrefix(path, modulePath+"/"):
+			groups[2] = append(groups[2], spec)
+		case strings.Contains(strings.Split(path, "/")[0], "."):
+			groups[1] = append(groups[1], spec)
+		default:
+			groups[0] = append(groups[0], spec)
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by storage/gen from the methods of the repositories; DO NOT EDIT.\n\npackage storage\n\nimport (\n")
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		for _, spec := range group {
+			src.WriteString(spec + "\n")
+		}
+		src.WriteString("\n")
+	}
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+	return src.Bytes()
+}
+
+// importPath is the path of an import spec with an optional alias
+func importPath(spec string) string {
+	return spec[strings.Index(spec, `"`):]
+}
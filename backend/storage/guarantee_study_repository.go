@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// GuaranteeStudyRepository provides methods to interact with the guarantee_study table in Supabase
+type GuaranteeStudyRepository struct {
+	client *supa.Client
+}
+
+// NewGuaranteeStudyRepository creates a new GuaranteeStudyRepository
+func NewGuaranteeStudyRepository(client *supa.Client) *GuaranteeStudyRepository {
+	return &GuaranteeStudyRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a guarantee study by ID
+func (r *GuaranteeStudyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.GuaranteeStudy, error) {
+	data, count, err := r.client.From("guarantee_study").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching guarantee study", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var studies []model.GuaranteeStudy
+	err = json.Unmarshal([]byte(data), &studies)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing guarantee study data", "error", err)
+		return nil, err
+	}
+
+	if len(studies) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &studies[0], nil
+}
+
+// GetByRenterID retrieves the guarantee studies of a renter, newest first
+func (r *GuaranteeStudyRepository) GetByRenterID(ctx context.Context, renterID uuid.UUID) ([]model.GuaranteeStudy, error) {
+	data, _, err := r.client.From("guarantee_study").Select("*", "exact", false).
+		Eq("renter_id", renterID.String()).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching guarantee studies for renter", "renter_id", renterID, "error", err)
+		return nil, err
+	}
+
+	var studies []model.GuaranteeStudy
+	err = json.Unmarshal([]byte(data), &studies)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing guarantee study data", "error", err)
+		return nil, err
+	}
+
+	return studies, nil
+}
+
+// GetApproved retrieves the latest approved study with a policy for a renter and property,
+// nil when there is none
+func (r *GuaranteeStudyRepository) GetApproved(ctx context.Context, renterID, propertyID uuid.UUID) (*model.GuaranteeStudy, error) {
+	data, _, err := r.client.From("guarantee_study").Select("*", "exact", false).
+		Eq("renter_id", renterID.String()).
+		Eq("property_id", propertyID.String()).
+		Eq("status", model.GuaranteeStatusApproved).
+		Order("decided_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching approved guarantee study for renter and property", "renter_id", renterID, "property_id", propertyID, "error", err)
+		return nil, err
+	}
+
+	var studies []model.GuaranteeStudy
+	err = json.Unmarshal([]byte(data), &studies)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing guarantee study data", "error", err)
+		return nil, err
+	}
+
+	for i := range studies {
+		if studies[i].HasPolicy() {
+			return &studies[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Create adds a new guarantee study
+func (r *GuaranteeStudyRepository) Create(ctx context.Context, study model.GuaranteeStudy) (*model.GuaranteeStudy, error) {
+	if study.ID == uuid.Nil {
+		study.ID = uuid.New()
+	}
+	if study.CreatedAt.IsZero() {
+		study.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("guarantee_study").Insert(study, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating guarantee study", "error", err)
+		return nil, fmt.Errorf("failed to create guarantee study: %w", err)
+	}
+
+	var createdStudies []model.GuaranteeStudy
+	err = json.Unmarshal(data, &createdStudies)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created guarantee study data", "error", err)
+		return nil, err
+	}
+
+	if len(createdStudies) == 0 {
+		return nil, fmt.Errorf("failed to parse created guarantee study, empty result set")
+	}
+
+	return &createdStudies[0], nil
+}
+
+// UpdateResult stores the result of the study reported by the afianzadora
+func (r *GuaranteeStudyRepository) UpdateResult(ctx context.Context, study model.GuaranteeStudy) (*model.GuaranteeStudy, error) {
+	studyData := map[string]interface{}{
+		"status":        study.Status,
+		"policy_number": study.PolicyNumber,
+		"observations":  study.Observations,
+		"decided_at":    study.DecidedAt,
+	}
+
+	data, count, err := r.client.From("guarantee_study").Update(studyData, "exact", "").
+		Eq("id", study.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating guarantee study", "study_id", study.ID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(ctx, study.ID)
+	}
+
+	var updatedStudies []model.GuaranteeStudy
+	err = json.Unmarshal(data, &updatedStudies)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated guarantee study data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedStudies) == 0 {
+		return r.GetByID(ctx, study.ID)
+	}
+
+	return &updatedStudies[0], nil
+}
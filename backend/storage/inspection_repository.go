@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// InspectionRepository provides methods to interact with the inspection and
+// inspection_template tables in Supabase
+type InspectionRepository struct {
+	client *supa.Client
+}
+
+// NewInspectionRepository creates a new InspectionRepository
+func NewInspectionRepository(client *supa.Client) *InspectionRepository {
+	return &InspectionRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves an inspection, nil when it does not exist
+func (r *InspectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Inspection, error) {
+	data, _, err := r.client.From("inspection").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching inspection", "id", id, "error", err)
+		return nil, err
+	}
+
+	var inspections []model.Inspection
+	if err := json.Unmarshal(data, &inspections); err != nil {
+		logging.FromContext(ctx).Error("error parsing inspection data", "error", err)
+		return nil, err
+	}
+
+	if len(inspections) == 0 {
+		return nil, nil
+	}
+
+	return &inspections[0], nil
+}
+
+// GetByRentalID retrieves the inspections of a rental, oldest first
+func (r *InspectionRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.Inspection, error) {
+	data, _, err := r.client.From("inspection").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Order("inspected_at", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching inspections of rental", "rental_id", rentalID, "error", err)
+		return nil, err
+	}
+
+	var inspections []model.Inspection
+	if err := json.Unmarshal(data, &inspections); err != nil {
+		logging.FromContext(ctx).Error("error parsing inspection data", "error", err)
+		return nil, err
+	}
+
+	return inspections, nil
+}
+
+// Create records an inspection
+func (r *InspectionRepository) Create(ctx context.Context, inspection model.Inspection) (*model.Inspection, error) {
+	if inspection.ID == uuid.Nil {
+		inspection.ID = uuid.New()
+	}
+	now := time.Now()
+	inspection.CreatedAt = now
+	inspection.UpdatedAt = now
+
+	data, _, err := r.client.From("inspection").Insert(inspection, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating inspection of rental", "rental_id", inspection.RentalID, "error", err)
+		return nil, fmt.Errorf("failed to create inspection: %w", err)
+	}
+
+	var created []model.Inspection
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created inspection data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created inspection, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// Update saves an inspection with its rooms, status and acknowledgment
+func (r *InspectionRepository) Update(ctx context.Context, inspection model.Inspection) (*model.Inspection, error) {
+	inspection.UpdatedAt = time.Now()
+
+	data, _, err := r.client.From("inspection").Update(inspection, "representation", "").
+		Eq("id", inspection.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating inspection", "inspection_id", inspection.ID, "error", err)
+		return nil, fmt.Errorf("failed to update inspection: %w", err)
+	}
+
+	var updated []model.Inspection
+	if err := json.Unmarshal(data, &updated); err != nil {
+		logging.FromContext(ctx).Error("error parsing updated inspection data", "error", err)
+		return nil, err
+	}
+
+	if len(updated) == 0 {
+		return nil, fmt.Errorf("inspection %s not found", inspection.ID)
+	}
+
+	return &updated[0], nil
+}
+
+// Delete removes an inspection
+func (r *InspectionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("inspection").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting inspection", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTemplates retrieves the inspection checklist templates sorted by name
+func (r *InspectionRepository) GetTemplates(ctx context.Context) ([]model.InspectionTemplate, error) {
+	data, _, err := r.client.From("inspection_template").Select("*", "exact", false).
+		Order("name", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching inspection templates", "error", err)
+		return nil, err
+	}
+
+	var templates []model.InspectionTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		logging.FromContext(ctx).Error("error parsing inspection template data", "error", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetTemplateByID retrieves an inspection checklist template, nil when it does not exist
+func (r *InspectionRepository) GetTemplateByID(ctx context.Context, id uuid.UUID) (*model.InspectionTemplate, error) {
+	data, _, err := r.client.From("inspection_template").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching inspection template", "id", id, "error", err)
+		return nil, err
+	}
+
+	var templates []model.InspectionTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		logging.FromContext(ctx).Error("error parsing inspection template data", "error", err)
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return nil, nil
+	}
+
+	return &templates[0], nil
+}
+
+// SaveTemplate creates or replaces an inspection checklist template
+func (r *InspectionRepository) SaveTemplate(ctx context.Context, template model.InspectionTemplate) (*model.InspectionTemplate, error) {
+	now := time.Now()
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+		template.CreatedAt = now
+	}
+	template.UpdatedAt = now
+
+	data, _, err := r.client.From("inspection_template").Upsert(template, "id", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving inspection template", "template_id", template.ID, "error", err)
+		return nil, fmt.Errorf("failed to save inspection template: %w", err)
+	}
+
+	var saved []model.InspectionTemplate
+	if err := json.Unmarshal(data, &saved); err != nil {
+		logging.FromContext(ctx).Error("error parsing saved inspection template data", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved inspection template, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// DeleteTemplate removes an inspection checklist template. Inspections made from it keep
+// their rooms.
+func (r *InspectionRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("inspection_template").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting inspection template", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,1232 @@
+// Code generated by storage/gen from the methods of the repositories; DO NOT EDIT.
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nescool101/rentManager/model"
+)
+
+// AuditLogStore is the interface of AuditLogRepository
+type AuditLogStore interface {
+	// Create records an audit log entry
+	Create(ctx context.Context, entry model.AuditLog) (*model.AuditLog, error)
+
+	// List retrieves the entries matching the filter, newest first
+	List(ctx context.Context, filter AuditLogFilter) ([]model.AuditLog, error)
+}
+
+// BankAccountStore is the interface of BankAccountRepository
+type BankAccountStore interface {
+	// GetAll retrieves all bank accounts
+	GetAll(ctx context.Context) ([]model.BankAccount, error)
+
+	// GetByID retrieves a bank account by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.BankAccount, error)
+
+	// GetByPersonID retrieves bank accounts by person ID
+	GetByPersonID(ctx context.Context, personID uuid.UUID) ([]model.BankAccount, error)
+
+	// Create adds a new bank account
+	Create(ctx context.Context, account model.BankAccount) (*model.BankAccount, error)
+
+	// Update updates an existing bank account
+	Update(ctx context.Context, account model.BankAccount) (*model.BankAccount, error)
+
+	// Delete removes a bank account
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// BillingReceiptStore is the interface of BillingReceiptRepository
+type BillingReceiptStore interface {
+	// GetByRentalAndPeriod retrieves the receipt issued to a rental for a billing period, nil when
+	// none was issued
+	GetByRentalAndPeriod(ctx context.Context, rentalID uuid.UUID, period string) (*model.BillingReceipt, error)
+
+	// GetByRental retrieves the receipts issued to a rental, newest first
+	GetByRental(ctx context.Context, rentalID uuid.UUID) ([]model.BillingReceipt, error)
+
+	// Create records an issued receipt
+	Create(ctx context.Context, receipt model.BillingReceipt) (*model.BillingReceipt, error)
+
+	// UpdateFilePath records where the PDF of a receipt was stored
+	UpdateFilePath(ctx context.Context, id uuid.UUID, filePath string) error
+}
+
+// BucketBackupStore is the interface of BucketBackupRepository
+type BucketBackupStore interface {
+	// GetRecent retrieves the latest bucket backups without their manifests, newest first
+	GetRecent(ctx context.Context, limit int) ([]model.BucketBackup, error)
+
+	// GetByID retrieves a bucket backup with its manifest, nil if it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.BucketBackup, error)
+
+	// GetLatestFinished retrieves the most recent completed or partial backup with its manifest,
+	// nil if there is none
+	GetLatestFinished(ctx context.Context) (*model.BucketBackup, error)
+
+	// Create records a bucket backup that is starting
+	Create(ctx context.Context, backup model.BucketBackup) (*model.BucketBackup, error)
+
+	// SaveResult saves the state, counters and manifest of a bucket backup
+	SaveResult(ctx context.Context, backup model.BucketBackup) error
+}
+
+// BuildingStore is the interface of BuildingRepository
+type BuildingStore interface {
+	// GetAll retrieves the buildings sorted by name
+	GetAll(ctx context.Context) ([]model.Building, error)
+
+	// GetByID retrieves a building, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Building, error)
+
+	// Create adds a building
+	Create(ctx context.Context, building model.Building) (*model.Building, error)
+
+	// Update replaces the data of a building
+	Update(ctx context.Context, building model.Building) (*model.Building, error)
+
+	// Delete removes a building. Its properties stay without building.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ContractCessionStore is the interface of ContractCessionRepository
+type ContractCessionStore interface {
+	// GetByID retrieves a contract cession by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.ContractCession, error)
+
+	// GetByRentalID retrieves the cessions of a rental, oldest effective date first
+	GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.ContractCession, error)
+
+	// GetPendingApplication retrieves the cessions effective at the given time whose rental still
+	// points to the previous bank account
+	GetPendingApplication(ctx context.Context, at time.Time) ([]model.ContractCession, error)
+
+	// Create adds a new contract cession
+	Create(ctx context.Context, cession model.ContractCession) (*model.ContractCession, error)
+
+	// SetLetter records the notification letter sent to the tenant
+	SetLetter(ctx context.Context, id uuid.UUID, letterPath string, notifiedAt *time.Time) error
+
+	// MarkApplied records that the rental was switched to the bank account of the new owner
+	MarkApplied(ctx context.Context, id uuid.UUID, appliedAt time.Time) error
+}
+
+// ContractSigningEventStore is the interface of ContractSigningEventRepository
+type ContractSigningEventStore interface {
+	// GetBySigningID retrieves the audit trail of a signing request, oldest first
+	GetBySigningID(ctx context.Context, signingID string) ([]ContractSigningEvent, error)
+
+	// Create records a new event of a signing request
+	Create(ctx context.Context, event *ContractSigningEvent) (*ContractSigningEvent, error)
+}
+
+// ContractSigningStore is the interface of ContractSigningRepository
+type ContractSigningStore interface {
+	// CreateSigningRequest creates a new contract signing request
+	CreateSigningRequest(ctx context.Context, request model.ContractSigningRequest) (*ContractSigningRecord, error)
+
+	// GetByID retrieves a contract signing request by ID
+	GetByID(ctx context.Context, id string) (*ContractSigningRecord, error)
+
+	// GetByContractID retrieves contract signing requests by contract ID
+	GetByContractID(ctx context.Context, contractID string) ([]ContractSigningRecord, error)
+
+	// GetByExternalID retrieves a contract signing request by the ID assigned by an external e-sign provider
+	GetByExternalID(ctx context.Context, provider, externalID string) (*ContractSigningRecord, error)
+
+	// GetByRecipientID retrieves contract signing requests by recipient ID
+	GetByRecipientID(ctx context.Context, recipientID string) ([]ContractSigningRecord, error)
+
+	// GetPendingRequests retrieves contract signing requests that are pending and not expired
+	GetPendingRequests(ctx context.Context) ([]ContractSigningRecord, error)
+
+	// GetSignedBetween retrieves the contract signing requests signed within a period
+	GetSignedBetween(ctx context.Context, from, to time.Time) ([]ContractSigningRecord, error)
+
+	// MarkAsSigned marks a contract signing request as signed. The evidence of the signer is nil
+	// when the signature was collected by an external provider.
+	MarkAsSigned(ctx context.Context, id string, signedPDFPath string, evidence *model.SigningEvidence) error
+
+	// SaveOTP stores a new one-time signing code, replacing the previous one and its failed attempts
+	SaveOTP(ctx context.Context, id, otpHash, channel string, sentAt, expiresAt time.Time) error
+
+	// SetOTPAttempts records the failed attempts of the current one-time signing code
+	SetOTPAttempts(ctx context.Context, id string, attempts int) error
+
+	// MarkOTPVerified consumes the one-time signing code so it cannot be used again
+	MarkOTPVerified(ctx context.Context, id string, verifiedAt time.Time) error
+
+	// MarkAsRejected marks a contract signing request as rejected. The evidence of the signer is nil
+	// when the rejection was reported by an external provider.
+	MarkAsRejected(ctx context.Context, id string, evidence *model.SigningEvidence) error
+
+	// MarkViewed records the first time the recipient opened a signing request and reports whether
+	// this was it, later views are ignored
+	MarkViewed(ctx context.Context, id string, viewedAt time.Time) (bool, error)
+
+	// SetLastReminder records the days before expiry of the last reminder sent to the recipient
+	SetLastReminder(ctx context.Context, id string, days int) error
+
+	// UpdateExpiredStatuses updates statuses for expired signing requests
+	UpdateExpiredStatuses(ctx context.Context) (int, error)
+
+	// ExpirePendingRequests marks the pending requests past their expiry date as expired and
+	// returns the ones updated
+	ExpirePendingRequests(ctx context.Context) ([]ContractSigningRecord, error)
+}
+
+// ContractTemplateStore is the interface of ContractTemplateRepository
+type ContractTemplateStore interface {
+	// GetAll retrieves all contract templates
+	GetAll(ctx context.Context) ([]model.ContractTemplate, error)
+
+	// GetByID retrieves a contract template by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.ContractTemplate, error)
+
+	// GetDefault retrieves the default template of a manager for a contract type, falling back to
+	// the shared default template of that type. Templates without a contract type are vivienda
+	// urbana templates. Returns nil when no default template is stored.
+	GetDefault(ctx context.Context, managerIDs []uuid.UUID, contractType string) (*model.ContractTemplate, error)
+
+	// Create adds a new contract template
+	Create(ctx context.Context, template model.ContractTemplate) (*model.ContractTemplate, error)
+
+	// Update updates an existing contract template
+	Update(ctx context.Context, template model.ContractTemplate) (*model.ContractTemplate, error)
+
+	// Delete removes a contract template
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DocumentSequenceStore is the interface of DocumentSequenceRepository
+type DocumentSequenceStore interface {
+	// Next reserves and returns the next consecutive of a kind of document of an organization
+	// (uuid.Nil for the documents of properties without one) in a year, starting from 1. The
+	// database increments the sequence in a single statement, so concurrent calls never get the
+	// same number.
+	Next(ctx context.Context, organizationID uuid.UUID, kind model.DocumentKind, year int) (int64, error)
+}
+
+// EmailOutboxStore is the interface of EmailOutboxRepository
+type EmailOutboxStore interface {
+	// Create queues an email
+	Create(ctx context.Context, email model.OutboxEmail) (*model.OutboxEmail, error)
+
+	// GetDue retrieves up to limit pending emails whose send time has been reached, oldest first
+	GetDue(ctx context.Context, now time.Time, limit int) ([]model.OutboxEmail, error)
+
+	// GetOpenedByPersonID retrieves the most recent emails opened by a person, up to limit
+	GetOpenedByPersonID(ctx context.Context, personID uuid.UUID, limit int) ([]model.OutboxEmail, error)
+
+	// MarkSent records the delivery of an email
+	MarkSent(ctx context.Context, id uuid.UUID, attempts int, sentAt time.Time) error
+
+	// MarkFailed records a failed delivery. The email is retried at retryAt while status is pending.
+	MarkFailed(ctx context.Context, id uuid.UUID, status string, attempts int, lastError string, retryAt time.Time) error
+
+	// MarkOpened records the first time an email was opened, later opens are ignored
+	MarkOpened(ctx context.Context, id uuid.UUID, openedAt time.Time) error
+}
+
+// EmailTemplateStore is the interface of EmailTemplateRepository
+type EmailTemplateStore interface {
+	// GetLatest retrieves the current version of every customized email template
+	GetLatest(ctx context.Context) (map[string]model.EmailTemplate, error)
+
+	// GetVersions retrieves the versions of an email template, newest first
+	GetVersions(ctx context.Context, key string) ([]model.EmailTemplate, error)
+
+	// Create adds a new version of an email template
+	Create(ctx context.Context, template model.EmailTemplate) (*model.EmailTemplate, error)
+
+	// DeleteByKey removes every version of an email template, so the default is sent again
+	DeleteByKey(ctx context.Context, key string) error
+}
+
+// FeatureFlagStore is the interface of FeatureFlagRepository
+type FeatureFlagStore interface {
+	// GetAll retrieves the feature flags saved by admins
+	GetAll(ctx context.Context) ([]model.FeatureFlag, error)
+
+	// Save creates or replaces the value of a feature flag
+	Save(ctx context.Context, flag model.FeatureFlag) (*model.FeatureFlag, error)
+
+	// Delete removes the saved value of a feature flag, which falls back to the environment
+	Delete(ctx context.Context, key string) error
+}
+
+// FileIndexStore is the interface of FileIndexRepository
+type FileIndexStore interface {
+	// List retrieves the indexed files of a user, or of the whole bucket when userID is empty,
+	// sorted by path
+	List(ctx context.Context, userID string) ([]model.FileIndexEntry, error)
+
+	// Upsert records files in the index, replacing the entries with the same path
+	Upsert(ctx context.Context, entries []model.FileIndexEntry) error
+
+	// Delete removes files from the index
+	Delete(ctx context.Context, paths []string) error
+}
+
+// FileMetadataStore is the interface of FileMetadataRepository
+type FileMetadataStore interface {
+	// Find retrieves the metadata of the files matching a filter
+	Find(ctx context.Context, filter model.FileMetadataFilter) ([]model.FileMetadata, error)
+
+	// GetByRental retrieves the metadata of the files linked to a rental or to its contract, whose
+	// ID is the rental ID, newest first
+	GetByRental(ctx context.Context, rentalID uuid.UUID) ([]model.FileMetadata, error)
+
+	// Save creates or replaces the metadata of a file
+	Save(ctx context.Context, metadata model.FileMetadata) (*model.FileMetadata, error)
+
+	// DeleteByFileName removes the metadata of a deleted file
+	DeleteByFileName(ctx context.Context, fileName string) error
+}
+
+// FileScanStore is the interface of FileScanRepository
+type FileScanStore interface {
+	// GetRecent retrieves the latest scans, newest first, optionally only the ones with a result
+	GetRecent(ctx context.Context, result string, limit int) ([]model.FileScan, error)
+
+	// Create records a file scan
+	Create(ctx context.Context, scan model.FileScan) (*model.FileScan, error)
+}
+
+// GuaranteeStudyStore is the interface of GuaranteeStudyRepository
+type GuaranteeStudyStore interface {
+	// GetByID retrieves a guarantee study by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.GuaranteeStudy, error)
+
+	// GetByRenterID retrieves the guarantee studies of a renter, newest first
+	GetByRenterID(ctx context.Context, renterID uuid.UUID) ([]model.GuaranteeStudy, error)
+
+	// GetApproved retrieves the latest approved study with a policy for a renter and property,
+	// nil when there is none
+	GetApproved(ctx context.Context, renterID, propertyID uuid.UUID) (*model.GuaranteeStudy, error)
+
+	// Create adds a new guarantee study
+	Create(ctx context.Context, study model.GuaranteeStudy) (*model.GuaranteeStudy, error)
+
+	// UpdateResult stores the result of the study reported by the afianzadora
+	UpdateResult(ctx context.Context, study model.GuaranteeStudy) (*model.GuaranteeStudy, error)
+}
+
+// InspectionStore is the interface of InspectionRepository
+type InspectionStore interface {
+	// GetByID retrieves an inspection, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Inspection, error)
+
+	// GetByRentalID retrieves the inspections of a rental, oldest first
+	GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.Inspection, error)
+
+	// Create records an inspection
+	Create(ctx context.Context, inspection model.Inspection) (*model.Inspection, error)
+
+	// Update saves an inspection with its rooms, status and acknowledgment
+	Update(ctx context.Context, inspection model.Inspection) (*model.Inspection, error)
+
+	// Delete removes an inspection
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetTemplates retrieves the inspection checklist templates sorted by name
+	GetTemplates(ctx context.Context) ([]model.InspectionTemplate, error)
+
+	// GetTemplateByID retrieves an inspection checklist template, nil when it does not exist
+	GetTemplateByID(ctx context.Context, id uuid.UUID) (*model.InspectionTemplate, error)
+
+	// SaveTemplate creates or replaces an inspection checklist template
+	SaveTemplate(ctx context.Context, template model.InspectionTemplate) (*model.InspectionTemplate, error)
+
+	// DeleteTemplate removes an inspection checklist template. Inspections made from it keep
+	// their rooms.
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+}
+
+// InventoryStore is the interface of InventoryRepository
+type InventoryStore interface {
+	// GetByPropertyID retrieves the inventory of a property, nil when the property has none
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) (*model.Inventory, error)
+
+	// Create adds the inventory of a property
+	Create(ctx context.Context, inventory model.Inventory) (*model.Inventory, error)
+
+	// Update replaces the rooms and notes of the inventory of a property
+	Update(ctx context.Context, inventory model.Inventory) (*model.Inventory, error)
+
+	// DeleteByPropertyID removes the inventory of a property
+	DeleteByPropertyID(ctx context.Context, propertyID uuid.UUID) error
+}
+
+// InvitationStore is the interface of InvitationRepository
+type InvitationStore interface {
+	// Create records an invitation
+	Create(ctx context.Context, invitation model.Invitation) (*model.Invitation, error)
+
+	// GetByID retrieves an invitation by its ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error)
+
+	// GetAll retrieves the invitations, newest first
+	GetAll(ctx context.Context) ([]model.Invitation, error)
+
+	// MarkAccepted records that an open invitation was accepted by creating userID and reports
+	// whether it was still open; accepted and revoked invitations are not touched
+	MarkAccepted(ctx context.Context, id, userID uuid.UUID, acceptedAt time.Time) (bool, error)
+
+	// Revoke marks an open invitation as revoked and reports whether it was still open
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) (bool, error)
+}
+
+// ListingStore is the interface of ListingRepository
+type ListingStore interface {
+	// GetAll retrieves every listing, most recently updated first
+	GetAll(ctx context.Context) ([]model.PropertyListing, error)
+
+	// GetAvailable retrieves the available listings matching the filter, cheapest first
+	GetAvailable(ctx context.Context, filter ListingFilter) ([]model.PropertyListing, error)
+
+	// GetByID retrieves a listing, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PropertyListing, error)
+
+	// GetByPropertyID retrieves the listing of a property, nil when it was never listed
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) (*model.PropertyListing, error)
+
+	// Save creates or replaces the listing of a property
+	Save(ctx context.Context, listing model.PropertyListing) (*model.PropertyListing, error)
+
+	// Delete removes a listing with its applications
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetApplications retrieves the applications to a listing, newest first
+	GetApplications(ctx context.Context, listingID uuid.UUID) ([]model.ListingApplication, error)
+
+	// GetApplicationByID retrieves an application, nil when it does not exist
+	GetApplicationByID(ctx context.Context, id uuid.UUID) (*model.ListingApplication, error)
+
+	// CreateApplication records the application of a prospect
+	CreateApplication(ctx context.Context, application model.ListingApplication) (*model.ListingApplication, error)
+
+	// UpdateApplication saves the status and notes of an application
+	UpdateApplication(ctx context.Context, application model.ListingApplication) (*model.ListingApplication, error)
+}
+
+// LoginAttemptStore is the interface of LoginAttemptRepository
+type LoginAttemptStore interface {
+	// Create records a login attempt
+	Create(ctx context.Context, attempt model.LoginAttempt) (*model.LoginAttempt, error)
+
+	// GetSince retrieves the attempts whose column (email or ip_address) is value made after since,
+	// newest first
+	GetSince(ctx context.Context, column, value string, since time.Time) ([]model.LoginAttempt, error)
+
+	// List retrieves the attempts matching the filter, newest first
+	List(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error)
+
+	// DeleteBefore removes the attempts older than before, they are only kept for the lockouts and
+	// the recent history
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// MaintenanceCommentStore is the interface of MaintenanceCommentRepository
+type MaintenanceCommentStore interface {
+	// GetByRequestID retrieves all comments for a maintenance request, oldest first
+	GetByRequestID(requestID string) ([]MaintenanceComment, error)
+
+	// Create adds a new comment to a maintenance request
+	Create(comment *MaintenanceComment) (*MaintenanceComment, error)
+
+	// Delete removes a comment from a maintenance request
+	Delete(id string) error
+}
+
+// MaintenanceRequestStore is the interface of MaintenanceRequestRepository
+type MaintenanceRequestStore interface {
+	// GetAll retrieves all maintenance requests
+	GetAll() ([]MaintenanceRequest, error)
+
+	// List retrieves a page of maintenance requests with the total count of requests matching the filters
+	List(opts ListOptions) ([]MaintenanceRequest, int, error)
+
+	// GetByID retrieves a maintenance request by ID
+	GetByID(id string) (*MaintenanceRequest, error)
+
+	// GetByPropertyID retrieves all maintenance requests for a property
+	GetByPropertyID(propertyID string) ([]MaintenanceRequest, error)
+
+	// GetByPropertyIDs retrieves all maintenance requests for a list of property IDs
+	GetByPropertyIDs(propertyIDs []string) ([]MaintenanceRequest, error)
+
+	// GetByRenterID retrieves all maintenance requests from a renter
+	GetByRenterID(renterID string) ([]MaintenanceRequest, error)
+
+	// GetByStatus retrieves all maintenance requests with a specific status
+	GetByStatus(status string) ([]MaintenanceRequest, error)
+
+	// Create creates a new maintenance request
+	Create(request *MaintenanceRequest) (*MaintenanceRequest, error)
+
+	// Update updates an existing maintenance request
+	Update(id string, request *MaintenanceRequest) (*MaintenanceRequest, error)
+
+	// SetAssignedProvider assigns a service provider to a maintenance request.
+	// An empty providerID clears the assignment.
+	SetAssignedProvider(id string, providerID string) (*MaintenanceRequest, error)
+
+	// Delete deletes a maintenance request
+	Delete(id string) error
+}
+
+// MaintenanceStatusHistoryStore is the interface of MaintenanceStatusHistoryRepository
+type MaintenanceStatusHistoryStore interface {
+	// GetByRequestID retrieves the status transitions of a maintenance request, oldest first
+	GetByRequestID(requestID string) ([]MaintenanceStatusChange, error)
+
+	// Create records a new status transition
+	Create(change *MaintenanceStatusChange) (*MaintenanceStatusChange, error)
+}
+
+// ManagerDigestStore is the interface of ManagerDigestRepository
+type ManagerDigestStore interface {
+	// GetSubscribed retrieves the subscriptions with a digest frequency
+	GetSubscribed(ctx context.Context) ([]model.ManagerDigestSubscription, error)
+
+	// GetByPersonID retrieves the digest subscription of a manager, nil when the manager has none
+	GetByPersonID(ctx context.Context, personID uuid.UUID) (*model.ManagerDigestSubscription, error)
+
+	// Create adds the digest subscription of a manager
+	Create(ctx context.Context, subscription model.ManagerDigestSubscription) (*model.ManagerDigestSubscription, error)
+
+	// UpdateFrequency changes the digest frequency of a manager
+	UpdateFrequency(ctx context.Context, personID uuid.UUID, frequency string) (*model.ManagerDigestSubscription, error)
+
+	// MarkSent records a sent digest and the arrears it reported, used to compute the next changes
+	MarkSent(ctx context.Context, personID uuid.UUID, sentAt time.Time, arrears map[string]int) error
+}
+
+// NotarizationStore is the interface of NotarizationRepository
+type NotarizationStore interface {
+	// GetByID retrieves a notarization by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Notarization, error)
+
+	// GetBySigningID retrieves the notarizations of a signed contract, newest first
+	GetBySigningID(ctx context.Context, signingID string) ([]model.Notarization, error)
+
+	// GetByExternalID retrieves a notarization by the ID assigned by the notary service
+	GetByExternalID(ctx context.Context, provider, externalID string) (*model.Notarization, error)
+
+	// Create adds a new notarization
+	Create(ctx context.Context, notarization model.Notarization) (*model.Notarization, error)
+
+	// UpdateResult stores the status reported by the notary and the authenticated document
+	UpdateResult(ctx context.Context, notarization model.Notarization) (*model.Notarization, error)
+}
+
+// NotificationPreferenceStore is the interface of NotificationPreferenceRepository
+type NotificationPreferenceStore interface {
+	// GetByPersonID retrieves the stored notification preferences of a person
+	GetByPersonID(ctx context.Context, personID uuid.UUID) ([]model.NotificationPreference, error)
+
+	// Save creates or replaces the given preferences of a person, keyed by channel and type
+	Save(ctx context.Context, preferences []model.NotificationPreference) error
+
+	// MarkSent records when a notification was last delivered under a stored preference
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+}
+
+// NotificationStore is the interface of NotificationRepository
+type NotificationStore interface {
+	// Create stores notifications, one per recipient of an event
+	Create(ctx context.Context, notifications []model.Notification) error
+
+	// GetByUser retrieves a page of the notifications of a user, newest first, with the total
+	// number of them. unreadOnly leaves out the ones already read.
+	GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]model.Notification, int, error)
+
+	// CountUnread returns how many notifications of a user have not been read
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// MarkRead marks a notification of a user as read and reports whether it was unread; the
+	// notifications of other users are not touched
+	MarkRead(ctx context.Context, id, userID uuid.UUID, readAt time.Time) (bool, error)
+
+	// MarkAllRead marks every unread notification of a user as read and returns how many were
+	MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int, error)
+
+	// DeleteReadBefore removes the notifications read before before
+	DeleteReadBefore(ctx context.Context, before time.Time) error
+}
+
+// OrganizationStore is the interface of OrganizationRepository
+type OrganizationStore interface {
+	// GetAll retrieves all organizations
+	GetAll(ctx context.Context) ([]model.Organization, error)
+
+	// GetByID retrieves an organization by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Organization, error)
+
+	// Create adds a new organization
+	Create(ctx context.Context, organization model.Organization) (*model.Organization, error)
+
+	// Update updates an existing organization
+	Update(ctx context.Context, organization model.Organization) (*model.Organization, error)
+
+	// Delete removes an organization
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PaymentCheckoutStore is the interface of PaymentCheckoutRepository
+type PaymentCheckoutStore interface {
+	// GetByReference retrieves the checkout with a gateway reference, nil when there is none
+	GetByReference(ctx context.Context, reference string) (*model.PaymentCheckout, error)
+
+	// GetApprovedByRentalAndPeriod retrieves the approved checkout paying a billing period of a
+	// rental, nil when it was not paid online
+	GetApprovedByRentalAndPeriod(ctx context.Context, rentalID uuid.UUID, period string) (*model.PaymentCheckout, error)
+
+	// Create records a started checkout
+	Create(ctx context.Context, checkout model.PaymentCheckout) (*model.PaymentCheckout, error)
+
+	// UpdateResult records the state of the transaction of a checkout and the rent payment it
+	// registered
+	UpdateResult(ctx context.Context, checkout model.PaymentCheckout) error
+
+	// RegisterPayment records the result of an approved checkout and creates its rent payment,
+	// numbered as in RentPaymentRepository.CreateNumbered, in one transaction. The checkout row is
+	// locked first, so when the payment was already registered, by a retried event or by another
+	// instance, the existing payment is returned and none is created: compare its ID with the one
+	// of payment to tell them apart.
+	RegisterPayment(ctx context.Context, checkout model.PaymentCheckout, payment *RentPayment, organizationID uuid.UUID, year int) (*RentPayment, error)
+}
+
+// PaymentStatementStore is the interface of PaymentStatementRepository
+type PaymentStatementStore interface {
+	// GetByID retrieves an issued payment statement by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PaymentStatement, error)
+
+	// Create records an issued payment statement
+	Create(ctx context.Context, statement model.PaymentStatement) (*model.PaymentStatement, error)
+}
+
+// PersonStore is the interface of PersonRepository
+type PersonStore interface {
+	// InvalidateCache drops the given persons from the cache, or every person when none is given
+	InvalidateCache(ids ...uuid.UUID)
+
+	// GetAll retrieves all persons from the database
+	GetAll(ctx context.Context) ([]model.Person, error)
+
+	// List retrieves a page of persons with the total count of persons matching the filters
+	List(ctx context.Context, opts ListOptions) ([]model.Person, int, error)
+
+	// GetByID retrieves a person by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error)
+
+	// GetByRole retrieves persons by role
+	GetByRole(ctx context.Context, roleName string) ([]model.Person, error)
+
+	// Create adds a new person to the database
+	Create(ctx context.Context, person model.Person) (*model.Person, error)
+
+	// Update updates an existing person. It returns ErrVersionConflict when person.Version is set
+	// and the person was updated since, and nil when the person does not exist.
+	Update(ctx context.Context, person model.Person) (*model.Person, error)
+
+	// Delete removes a person from the database
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByIDs retrieves multiple persons by their IDs
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Person, error)
+}
+
+// PersonRoleStore is the interface of PersonRoleRepository
+type PersonRoleStore interface {
+	// Create adds a new person_role to the database
+	Create(ctx context.Context, personRole model.PersonRole) (*model.PersonRole, error)
+}
+
+// PlatformSubscriptionStore is the interface of PlatformSubscriptionRepository
+type PlatformSubscriptionStore interface {
+	// GetAll retrieves all the platform subscriptions
+	GetAll(ctx context.Context) ([]model.PlatformSubscription, error)
+
+	// GetByUserID retrieves the subscription of a user, nil when there is none
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*model.PlatformSubscription, error)
+
+	// GetByCustomerID retrieves the subscription of a customer of the billing provider, nil when
+	// there is none
+	GetByCustomerID(ctx context.Context, customerID string) (*model.PlatformSubscription, error)
+
+	// GetPastDue retrieves the subscriptions with an unpaid invoice whose users are not suspended yet
+	GetPastDue(ctx context.Context) ([]model.PlatformSubscription, error)
+
+	// Save creates or replaces the subscription of a user
+	Save(ctx context.Context, subscription model.PlatformSubscription) (*model.PlatformSubscription, error)
+
+	// Update stores the billing state of a subscription
+	Update(ctx context.Context, subscription model.PlatformSubscription) error
+}
+
+// PricingStore is the interface of PricingRepository
+type PricingStore interface {
+	// GetByRentalID retrieves pricing information for a specific rental ID
+	GetByRentalID(ctx context.Context, rentalID uuid.UUID) (*model.Pricing, error)
+
+	// GetByRentalIDs retrieves the pricing information of several rentals in a single query
+	GetByRentalIDs(ctx context.Context, rentalIDs []uuid.UUID) ([]model.Pricing, error)
+
+	// Create adds new pricing information to the database
+	Create(ctx context.Context, pricing model.Pricing) (*model.Pricing, error)
+
+	// GetByID retrieves pricing information by its ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Pricing, error)
+
+	// GetAll retrieves all pricing records from the database
+	GetAll(ctx context.Context) ([]model.Pricing, error)
+
+	// Update modifies existing pricing information in the database. It returns ErrVersionConflict
+	// when pricing.Version is set and the pricing was updated since, and nil when it does not exist.
+	Update(ctx context.Context, pricing model.Pricing) (*model.Pricing, error)
+
+	// Delete removes pricing information from the database by its ID
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByPropertyID retrieves pricing information for a specific property ID
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.Pricing, error)
+}
+
+// PromotionStore is the interface of PromotionRepository
+type PromotionStore interface {
+	// GetAll retrieves all promotions, newest first
+	GetAll(ctx context.Context) ([]model.Promotion, error)
+
+	// GetByID retrieves a promotion by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Promotion, error)
+
+	// GetByPropertyID retrieves the promotions of a property
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.Promotion, error)
+
+	// Create adds a new promotion
+	Create(ctx context.Context, promotion model.Promotion) (*model.Promotion, error)
+
+	// Update updates an existing promotion
+	Update(ctx context.Context, promotion model.Promotion) (*model.Promotion, error)
+
+	// Delete removes a promotion
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// CreateRedemption records a contract offered with a promotion
+	CreateRedemption(ctx context.Context, redemption model.PromotionRedemption) (*model.PromotionRedemption, error)
+
+	// GetRedemptions retrieves all promotion redemptions
+	GetRedemptions(ctx context.Context) ([]model.PromotionRedemption, error)
+
+	// MarkConverted records that the contract offered with promotions was signed. Redemptions
+	// already converted keep their first conversion time.
+	MarkConverted(ctx context.Context, contractID string, convertedAt time.Time) error
+}
+
+// PropertyPhotoStore is the interface of PropertyPhotoRepository
+type PropertyPhotoStore interface {
+	// GetByPropertyID retrieves the gallery of a property in order
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.PropertyPhoto, error)
+
+	// GetByPropertyIDs retrieves the galleries of several properties, each one in order
+	GetByPropertyIDs(ctx context.Context, propertyIDs []uuid.UUID) ([]model.PropertyPhoto, error)
+
+	// GetByID retrieves a property photo, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PropertyPhoto, error)
+
+	// Create adds a photo to the gallery of a property
+	Create(ctx context.Context, photo model.PropertyPhoto) (*model.PropertyPhoto, error)
+
+	// UpdateCaption changes the caption of a property photo
+	UpdateCaption(ctx context.Context, id uuid.UUID, caption string) error
+
+	// UpdatePosition moves a property photo to a position of its gallery
+	UpdatePosition(ctx context.Context, id uuid.UUID, position int) error
+
+	// Delete removes a photo from the gallery of its property
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PropertyStore is the interface of PropertyRepository
+type PropertyStore interface {
+	// InvalidateCache drops the given properties from the cache, or every property when none is
+	// given, e.g. after writing the property table from another repository
+	InvalidateCache(ids ...uuid.UUID)
+
+	// GetManagerIDsForProperty retrieves all manager person IDs for a given property ID.
+	GetManagerIDsForProperty(ctx context.Context, propertyID uuid.UUID) ([]uuid.UUID, error)
+
+	// GetManagerIDsForProperties retrieves the manager person IDs of several properties in a single
+	// query, keyed by property ID. Properties without managers are not in the map.
+	GetManagerIDsForProperties(ctx context.Context, propertyIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+
+	// GetAll retrieves all properties from the database
+	GetAll(ctx context.Context) ([]model.Property, error)
+
+	// List retrieves a page of properties passing the filter with the total count of matching properties
+	List(ctx context.Context, filter PropertyFilter, opts ListOptions) ([]model.Property, int, error)
+
+	// GetByID retrieves a property by ID and populates its ManagerIDs.
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Property, error)
+
+	// GetByIDs retrieves several properties by their IDs in a single query, with their ManagerIDs
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Property, error)
+
+	// GetByBuildingID retrieves the properties of a building
+	GetByBuildingID(ctx context.Context, buildingID uuid.UUID) ([]model.Property, error)
+
+	// GetByResident retrieves properties by resident ID
+	GetByResident(ctx context.Context, residentID uuid.UUID) ([]model.Property, error)
+
+	// GetPropertiesForManager retrieves properties associated with the given manager_person_id.
+	GetPropertiesForManager(ctx context.Context, managerPersonID uuid.UUID) ([]model.Property, error)
+
+	// AddManagerToProperty creates a link between a property and a manager.
+	AddManagerToProperty(ctx context.Context, propertyID uuid.UUID, managerPersonID uuid.UUID) error
+
+	// RemoveManagerFromProperty removes a link between a property and a manager.
+	RemoveManagerFromProperty(ctx context.Context, propertyID uuid.UUID, managerPersonID uuid.UUID) error
+
+	// Create adds a new property to the database and links its managers. Both run in the
+	// create_property function, so a failure leaves neither the property nor any link behind.
+	Create(ctx context.Context, property model.Property) (*model.Property, error)
+
+	// Update updates an existing property and replaces its manager links in the update_property
+	// function, so a failure leaves the property and its links as they were. It returns
+	// ErrVersionConflict when property.Version is set and the property was updated since, and nil
+	// when it does not exist.
+	Update(ctx context.Context, property model.Property) (*model.Property, error)
+
+	// SetBuilding assigns a property to a building, or detaches it when buildingID is nil
+	SetBuilding(ctx context.Context, propertyID uuid.UUID, buildingID *uuid.UUID) error
+
+	// Delete removes a property from the database
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByUserID retrieves properties that a user is renting
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.Property, error)
+}
+
+// ReglamentoStore is the interface of ReglamentoRepository
+type ReglamentoStore interface {
+	// GetByID retrieves a reglamento version by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Reglamento, error)
+
+	// GetLatest retrieves the current version of the reglamento of every building
+	GetLatest(ctx context.Context) (map[string]model.Reglamento, error)
+
+	// GetLatestByBuilding retrieves the current version of the reglamento of a building, nil when
+	// none was uploaded
+	GetLatestByBuilding(ctx context.Context, buildingKey string) (*model.Reglamento, error)
+
+	// Create adds a new version of the reglamento of a building
+	Create(ctx context.Context, reglamento model.Reglamento) (*model.Reglamento, error)
+
+	// GetAcknowledgments retrieves the acknowledgments of a reglamento version
+	GetAcknowledgments(ctx context.Context, reglamentoID uuid.UUID) ([]model.ReglamentoAcknowledgment, error)
+
+	// GetAcknowledgmentsByPerson retrieves the reglamento versions acknowledged by a tenant
+	GetAcknowledgmentsByPerson(ctx context.Context, personID uuid.UUID) ([]model.ReglamentoAcknowledgment, error)
+
+	// CreateAcknowledgment records that a tenant acknowledged a reglamento version
+	CreateAcknowledgment(ctx context.Context, acknowledgment model.ReglamentoAcknowledgment) (*model.ReglamentoAcknowledgment, error)
+}
+
+// ReminderPreferenceStore is the interface of ReminderPreferenceRepository
+type ReminderPreferenceStore interface {
+	// GetByPersonID retrieves the reminder preference of a person, nil when the person has none
+	GetByPersonID(ctx context.Context, personID uuid.UUID) (*model.ReminderPreference, error)
+
+	// Create adds the reminder preference of a person
+	Create(ctx context.Context, preference model.ReminderPreference) (*model.ReminderPreference, error)
+
+	// Update updates the reminder preference of a person
+	Update(ctx context.Context, preference model.ReminderPreference) (*model.ReminderPreference, error)
+}
+
+// RentPaymentStore is the interface of RentPaymentRepository
+type RentPaymentStore interface {
+	// GetAll retrieves all rent payments
+	GetAll() ([]RentPayment, error)
+
+	// GetByID retrieves a rent payment by ID
+	GetByID(id string) (*RentPayment, error)
+
+	// GetByRentalID retrieves all payments for a specific rental
+	GetByRentalID(rentalID string) ([]RentPayment, error)
+
+	// List retrieves a page of payments passing the filter with the total count of matching payments
+	List(filter RentPaymentFilter, opts ListOptions) ([]RentPayment, int, error)
+
+	// GetByRentalIDs retrieves all payments for a list of rental IDs
+	GetByRentalIDs(rentalIDs []string) ([]RentPayment, error)
+
+	// Create creates a new rent payment
+	Create(payment *RentPayment) (*RentPayment, error)
+
+	// CreateNumbered creates a new rent payment with the next payment receipt number of an
+	// organization (uuid.Nil for none) in a year. The number is reserved in the same transaction as
+	// the insert, so a payment that fails to be created does not use up a number.
+	CreateNumbered(payment *RentPayment, organizationID uuid.UUID, year int) (*RentPayment, error)
+
+	// Update updates an existing rent payment
+	Update(id string, payment *RentPayment) (*RentPayment, error)
+
+	// Delete deletes a rent payment
+	Delete(id string) error
+
+	// GetPaymentsByDateRange retrieves all payments within a specific date range
+	GetPaymentsByDateRange(startDate, endDate time.Time) ([]RentPayment, error)
+
+	// GetLatePayments retrieves all payments that were not paid on time
+	GetLatePayments() ([]RentPayment, error)
+
+	// UpdateInvoice stores the electronic invoice of a payment
+	UpdateInvoice(id string, invoice RentPaymentInvoice) error
+
+	// GetLastInvoiceSequence returns the highest invoice consecutive used, 0 when no payment was invoiced
+	GetLastInvoiceSequence() (int64, error)
+
+	// GetByInvoiceStatus retrieves the payments whose electronic invoice is in a status
+	GetByInvoiceStatus(status string) ([]RentPayment, error)
+}
+
+// RentalHistoryStore is the interface of RentalHistoryRepository
+type RentalHistoryStore interface {
+	// GetAll retrieves all rental history records
+	GetAll() ([]RentalHistory, error)
+
+	// List retrieves a page of rental history records with the total count of records matching the filters
+	List(opts ListOptions) ([]RentalHistory, int, error)
+
+	// GetByID retrieves a rental history record by ID
+	GetByID(id string) (*RentalHistory, error)
+
+	// GetByPersonID retrieves all rental history records for a specific person
+	GetByPersonID(personID string) ([]RentalHistory, error)
+
+	// GetByRentalID retrieves all rental history records for a specific rental
+	GetByRentalID(rentalID string) ([]RentalHistory, error)
+
+	// GetByNextRentalID retrieves the rental history records of the rentals continued by a rental
+	GetByNextRentalID(nextRentalID string) ([]RentalHistory, error)
+
+	// GetByRentalIDs retrieves all rental history records for a list of rental IDs
+	GetByRentalIDs(rentalIDs []string) ([]RentalHistory, error)
+
+	// GetByStatus retrieves all rental history records with a specific status
+	GetByStatus(status string) ([]RentalHistory, error)
+
+	// Create creates a new rental history record
+	Create(history *RentalHistory) (*RentalHistory, error)
+
+	// Update updates an existing rental history record
+	Update(id string, history *RentalHistory) (*RentalHistory, error)
+
+	// Delete deletes a rental history record
+	Delete(id string) error
+
+	// GetRentalHistoryByDateRange retrieves all rental history records with end dates in a specific range
+	GetRentalHistoryByDateRange(startDate, endDate time.Time) ([]RentalHistory, error)
+}
+
+// RentalPartyStore is the interface of RentalPartyRepository
+type RentalPartyStore interface {
+	// GetByRentalID retrieves the parties of a rental in the order they were added
+	GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.RentalParty, error)
+
+	// GetByID retrieves a rental party, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.RentalParty, error)
+
+	// Create links a person to a rental with a role
+	Create(ctx context.Context, party model.RentalParty) (*model.RentalParty, error)
+
+	// Delete removes a party from its rental
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// RentalStore is the interface of RentalRepository
+type RentalStore interface {
+	// GetAll retrieves all rentals from the database
+	GetAll(ctx context.Context) ([]model.Rental, error)
+
+	// GetByID retrieves a rental by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Rental, error)
+
+	// GetByPropertyID retrieves rentals by property ID
+	GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.Rental, error)
+
+	// GetByPropertyIDs retrieves the rentals of several properties in a single query
+	GetByPropertyIDs(ctx context.Context, propertyIDs []uuid.UUID) ([]model.Rental, error)
+
+	// GetByIDs retrieves several rentals by their IDs in a single query
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Rental, error)
+
+	// GetByRenterIDs retrieves the rentals of several renters in a single query
+	GetByRenterIDs(ctx context.Context, renterIDs []uuid.UUID) ([]model.Rental, error)
+
+	// GetByRenterID retrieves rentals by renter ID
+	GetByRenterID(ctx context.Context, renterID uuid.UUID) ([]model.Rental, error)
+
+	// List retrieves a page of rentals passing the filter with the total count of matching rentals
+	List(ctx context.Context, filter RentalFilter, opts ListOptions) ([]model.Rental, int, error)
+
+	// GetActiveRentals retrieves all active rentals (where end_date is in the future)
+	GetActiveRentals(ctx context.Context) ([]model.Rental, error)
+
+	// Create adds a new rental to the database
+	Create(ctx context.Context, rental model.Rental) (*model.Rental, error)
+
+	// Update updates an existing rental. It returns ErrVersionConflict when rental.Version is set
+	// and the rental was updated since, and nil when the rental does not exist.
+	Update(ctx context.Context, rental model.Rental) (*model.Rental, error)
+
+	// Delete removes a rental from the database
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// SearchStore is the interface of SearchRepository
+type SearchStore interface {
+	// Search returns up to limit results matching every word of query, best matches first
+	Search(ctx context.Context, query string, limit int) ([]model.SearchResult, error)
+}
+
+// SecurityDepositStore is the interface of SecurityDepositRepository
+type SecurityDepositStore interface {
+	// GetByID retrieves a security deposit, nil when it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.SecurityDeposit, error)
+
+	// GetByRentalID retrieves the security deposit of a rental, nil when it has none
+	GetByRentalID(ctx context.Context, rentalID uuid.UUID) (*model.SecurityDeposit, error)
+
+	// GetByRentalIDs retrieves the security deposits of several rentals
+	GetByRentalIDs(ctx context.Context, rentalIDs []uuid.UUID) ([]model.SecurityDeposit, error)
+
+	// Create records the security deposit of a rental
+	Create(ctx context.Context, deposit model.SecurityDeposit) (*model.SecurityDeposit, error)
+
+	// Update saves a security deposit with its deductions and settlement
+	Update(ctx context.Context, deposit model.SecurityDeposit) (*model.SecurityDeposit, error)
+}
+
+// ServiceProviderStore is the interface of ServiceProviderRepository
+type ServiceProviderStore interface {
+	// GetAll retrieves all service providers
+	GetAll(ctx context.Context) ([]model.ServiceProvider, error)
+
+	// GetByID retrieves a service provider by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.ServiceProvider, error)
+
+	// GetBySpecialty retrieves service providers with a specific specialty
+	GetBySpecialty(ctx context.Context, specialty string) ([]model.ServiceProvider, error)
+
+	// Create adds a new service provider
+	Create(ctx context.Context, provider model.ServiceProvider) (*model.ServiceProvider, error)
+
+	// Update updates an existing service provider
+	Update(ctx context.Context, provider model.ServiceProvider) (*model.ServiceProvider, error)
+
+	// Delete removes a service provider
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TrashedFileStore is the interface of TrashedFileRepository
+type TrashedFileStore interface {
+	// GetAll retrieves the files in the trash, most recently deleted first
+	GetAll(ctx context.Context) ([]model.TrashedFile, error)
+
+	// GetByID retrieves a trashed file, nil if it does not exist
+	GetByID(ctx context.Context, id uuid.UUID) (*model.TrashedFile, error)
+
+	// GetExpired retrieves the trashed files whose retention ended before the given time
+	GetExpired(ctx context.Context, before time.Time) ([]model.TrashedFile, error)
+
+	// Create records a file moved to the trash
+	Create(ctx context.Context, file model.TrashedFile) (*model.TrashedFile, error)
+
+	// Delete removes the record of a trashed file, once restored or purged
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// UploadLimitStore is the interface of UploadLimitRepository
+type UploadLimitStore interface {
+	// GetAll retrieves the upload limits saved by admins
+	GetAll(ctx context.Context) ([]model.UploadLimit, error)
+
+	// Save creates or replaces the upload limit of a role
+	Save(ctx context.Context, limit model.UploadLimit) (*model.UploadLimit, error)
+
+	// Delete removes the upload limit of a role, which falls back to the environment
+	Delete(ctx context.Context, role string) error
+}
+
+// UserBulkJobStore is the interface of UserBulkJobRepository
+type UserBulkJobStore interface {
+	// GetByID retrieves a bulk user job by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.UserBulkJob, error)
+
+	// GetRecent retrieves the latest bulk user jobs, newest first
+	GetRecent(ctx context.Context, limit int) ([]model.UserBulkJob, error)
+
+	// GetUnfinished retrieves the queued and running jobs, oldest first, to resume them after a restart
+	GetUnfinished(ctx context.Context) ([]model.UserBulkJob, error)
+
+	// Create adds a bulk user job
+	Create(ctx context.Context, job model.UserBulkJob) (*model.UserBulkJob, error)
+
+	// SaveProgress saves the state, counters and report of a job
+	SaveProgress(ctx context.Context, job model.UserBulkJob) error
+}
+
+// UserStore is the interface of UserRepository
+type UserStore interface {
+	// GetAll retrieves all users from the database
+	GetAll(ctx context.Context) ([]model.User, error)
+
+	// List retrieves a page of users with the total count of users matching the filters
+	List(ctx context.Context, opts ListOptions) ([]model.User, int, error)
+
+	// GetByID retrieves a user by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+
+	// GetByEmail retrieves a user by email
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+
+	// GetByPersonID retrieves a user by PersonID
+	GetByPersonID(ctx context.Context, personID uuid.UUID) (*model.User, error)
+
+	// Create adds a new user to the database
+	Create(ctx context.Context, user model.User) (*model.User, error)
+
+	// Update updates an existing user
+	Update(ctx context.Context, user model.User) (*model.User, error)
+
+	// Delete removes a user from the database
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// UserSessionStore is the interface of UserSessionRepository
+type UserSessionStore interface {
+	// Create records a session
+	Create(ctx context.Context, session model.UserSession) (*model.UserSession, error)
+
+	// GetByID retrieves a session by its ID, the jti of its token
+	GetByID(ctx context.Context, id uuid.UUID) (*model.UserSession, error)
+
+	// GetActiveByUser retrieves the sessions of a user that are neither revoked nor expired at now,
+	// most recently used first
+	GetActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.UserSession, error)
+
+	// Revoke marks a session of a user as revoked and reports whether it was active; the sessions
+	// of other users and the ones already revoked are not touched
+	Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) (bool, error)
+
+	// Touch records that a session was used at lastSeenAt
+	Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error
+
+	// DeleteExpiredBefore removes the sessions that expired before before, revoked or not
+	DeleteExpiredBefore(ctx context.Context, before time.Time) error
+}
+
+// WebhookStore is the interface of WebhookRepository
+type WebhookStore interface {
+	// GetSubscriptionByID retrieves a webhook subscription by ID
+	GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error)
+
+	// GetSubscriptionsByPersonID retrieves the webhooks registered by a person, oldest first
+	GetSubscriptionsByPersonID(ctx context.Context, personID uuid.UUID) ([]model.WebhookSubscription, error)
+
+	// GetActiveSubscriptions retrieves every active webhook
+	GetActiveSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+
+	// CreateSubscription registers a webhook
+	CreateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error)
+
+	// UpdateSubscription saves the URL, events, description and state of a webhook
+	UpdateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error)
+
+	// DeleteSubscription removes a webhook and its deliveries
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	// CreateDelivery queues an event for a webhook
+	CreateDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error)
+
+	// GetDueDeliveries retrieves up to limit pending deliveries whose next attempt has been reached,
+	// oldest first
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+
+	// GetDeliveriesBySubscriptionID retrieves the most recent deliveries of a webhook, up to limit
+	GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]model.WebhookDelivery, error)
+
+	// MarkDelivered records that a webhook accepted a delivery
+	MarkDelivered(ctx context.Context, id uuid.UUID, attempts, responseStatus int, deliveredAt time.Time) error
+
+	// MarkDeliveryFailed records a failed attempt. The delivery is retried at nextAttemptAt while
+	// status is pending.
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, status string, attempts, responseStatus int, lastError string, nextAttemptAt time.Time) error
+}
+
+// The repositories implement their interfaces
+var (
+	_ AuditLogStore                 = (*AuditLogRepository)(nil)
+	_ BankAccountStore              = (*BankAccountRepository)(nil)
+	_ BillingReceiptStore           = (*BillingReceiptRepository)(nil)
+	_ BucketBackupStore             = (*BucketBackupRepository)(nil)
+	_ BuildingStore                 = (*BuildingRepository)(nil)
+	_ ContractCessionStore          = (*ContractCessionRepository)(nil)
+	_ ContractSigningEventStore     = (*ContractSigningEventRepository)(nil)
+	_ ContractSigningStore          = (*ContractSigningRepository)(nil)
+	_ ContractTemplateStore         = (*ContractTemplateRepository)(nil)
+	_ DocumentSequenceStore         = (*DocumentSequenceRepository)(nil)
+	_ EmailOutboxStore              = (*EmailOutboxRepository)(nil)
+	_ EmailTemplateStore            = (*EmailTemplateRepository)(nil)
+	_ FeatureFlagStore              = (*FeatureFlagRepository)(nil)
+	_ FileIndexStore                = (*FileIndexRepository)(nil)
+	_ FileMetadataStore             = (*FileMetadataRepository)(nil)
+	_ FileScanStore                 = (*FileScanRepository)(nil)
+	_ GuaranteeStudyStore           = (*GuaranteeStudyRepository)(nil)
+	_ InspectionStore               = (*InspectionRepository)(nil)
+	_ InventoryStore                = (*InventoryRepository)(nil)
+	_ InvitationStore               = (*InvitationRepository)(nil)
+	_ ListingStore                  = (*ListingRepository)(nil)
+	_ LoginAttemptStore             = (*LoginAttemptRepository)(nil)
+	_ MaintenanceCommentStore       = (*MaintenanceCommentRepository)(nil)
+	_ MaintenanceRequestStore       = (*MaintenanceRequestRepository)(nil)
+	_ MaintenanceStatusHistoryStore = (*MaintenanceStatusHistoryRepository)(nil)
+	_ ManagerDigestStore            = (*ManagerDigestRepository)(nil)
+	_ NotarizationStore             = (*NotarizationRepository)(nil)
+	_ NotificationPreferenceStore   = (*NotificationPreferenceRepository)(nil)
+	_ NotificationStore             = (*NotificationRepository)(nil)
+	_ OrganizationStore             = (*OrganizationRepository)(nil)
+	_ PaymentCheckoutStore          = (*PaymentCheckoutRepository)(nil)
+	_ PaymentStatementStore         = (*PaymentStatementRepository)(nil)
+	_ PersonStore                   = (*PersonRepository)(nil)
+	_ PersonRoleStore               = (*PersonRoleRepository)(nil)
+	_ PlatformSubscriptionStore     = (*PlatformSubscriptionRepository)(nil)
+	_ PricingStore                  = (*PricingRepository)(nil)
+	_ PromotionStore                = (*PromotionRepository)(nil)
+	_ PropertyPhotoStore            = (*PropertyPhotoRepository)(nil)
+	_ PropertyStore                 = (*PropertyRepository)(nil)
+	_ ReglamentoStore               = (*ReglamentoRepository)(nil)
+	_ ReminderPreferenceStore       = (*ReminderPreferenceRepository)(nil)
+	_ RentPaymentStore              = (*RentPaymentRepository)(nil)
+	_ RentalHistoryStore            = (*RentalHistoryRepository)(nil)
+	_ RentalPartyStore              = (*RentalPartyRepository)(nil)
+	_ RentalStore                   = (*RentalRepository)(nil)
+	_ SearchStore                   = (*SearchRepository)(nil)
+	_ SecurityDepositStore          = (*SecurityDepositRepository)(nil)
+	_ ServiceProviderStore          = (*ServiceProviderRepository)(nil)
+	_ TrashedFileStore              = (*TrashedFileRepository)(nil)
+	_ UploadLimitStore              = (*UploadLimitRepository)(nil)
+	_ UserBulkJobStore              = (*UserBulkJobRepository)(nil)
+	_ UserStore                     = (*UserRepository)(nil)
+	_ UserSessionStore              = (*UserSessionRepository)(nil)
+	_ WebhookStore                  = (*WebhookRepository)(nil)
+)
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// InventoryRepository provides methods to interact with the inventory table in Supabase
+type InventoryRepository struct {
+	client *supa.Client
+}
+
+// NewInventoryRepository creates a new InventoryRepository
+func NewInventoryRepository(client *supa.Client) *InventoryRepository {
+	return &InventoryRepository{
+		client: client,
+	}
+}
+
+// GetByPropertyID retrieves the inventory of a property, nil when the property has none
+func (r *InventoryRepository) GetByPropertyID(ctx context.Context, propertyID uuid.UUID) (*model.Inventory, error) {
+	data, count, err := r.client.From("inventory").Select("*", "exact", false).
+		Eq("property_id", propertyID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching inventory for property", "property_id", propertyID, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var inventories []model.Inventory
+	err = json.Unmarshal([]byte(data), &inventories)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing inventory data", "error", err)
+		return nil, err
+	}
+
+	if len(inventories) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &inventories[0], nil
+}
+
+// Create adds the inventory of a property
+func (r *InventoryRepository) Create(ctx context.Context, inventory model.Inventory) (*model.Inventory, error) {
+	if inventory.ID == uuid.Nil {
+		inventory.ID = uuid.New()
+	}
+	inventory.UpdatedAt = time.Now()
+
+	data, _, err := r.client.From("inventory").Insert(inventory, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating inventory", "error", err)
+		return nil, fmt.Errorf("failed to create inventory: %w", err)
+	}
+
+	var createdInventories []model.Inventory
+	err = json.Unmarshal(data, &createdInventories)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created inventory data", "error", err)
+		return nil, err
+	}
+
+	if len(createdInventories) == 0 {
+		return nil, fmt.Errorf("failed to parse created inventory, empty result set")
+	}
+
+	return &createdInventories[0], nil
+}
+
+// Update replaces the rooms and notes of the inventory of a property
+func (r *InventoryRepository) Update(ctx context.Context, inventory model.Inventory) (*model.Inventory, error) {
+	inventoryData := map[string]interface{}{
+		"rooms":      inventory.Rooms,
+		"notes":      inventory.Notes,
+		"updated_at": time.Now(),
+	}
+
+	data, count, err := r.client.From("inventory").Update(inventoryData, "exact", "").
+		Eq("property_id", inventory.PropertyID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating inventory for property", "property_id", inventory.PropertyID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByPropertyID(ctx, inventory.PropertyID)
+	}
+
+	var updatedInventories []model.Inventory
+	err = json.Unmarshal(data, &updatedInventories)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated inventory data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedInventories) == 0 {
+		return r.GetByPropertyID(ctx, inventory.PropertyID)
+	}
+
+	return &updatedInventories[0], nil
+}
+
+// DeleteByPropertyID removes the inventory of a property
+func (r *InventoryRepository) DeleteByPropertyID(ctx context.Context, propertyID uuid.UUID) error {
+	_, _, err := r.client.From("inventory").Delete("minimal", "").
+		Eq("property_id", propertyID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting inventory for property", "property_id", propertyID, "error", err)
+		return err
+	}
+
+	return nil
+}
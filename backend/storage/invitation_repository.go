@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// InvitationRepository provides methods to interact with the invitation table in Supabase
+type InvitationRepository struct {
+	client *supa.Client
+}
+
+// NewInvitationRepository creates a new InvitationRepository
+func NewInvitationRepository(client *supa.Client) *InvitationRepository {
+	return &InvitationRepository{
+		client: client,
+	}
+}
+
+// Create records an invitation
+func (r *InvitationRepository) Create(ctx context.Context, invitation model.Invitation) (*model.Invitation, error) {
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("invitation").Insert(invitation, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating invitation", "email", invitation.Email, "error", err)
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	var created []model.Invitation
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created invitation data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created invitation, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// GetByID retrieves an invitation by its ID
+func (r *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error) {
+	data, count, err := r.client.From("invitation").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching invitation", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	var invitations []model.Invitation
+	if err := json.Unmarshal(data, &invitations); err != nil {
+		logging.FromContext(ctx).Error("error parsing invitation data", "error", err)
+		return nil, err
+	}
+
+	if len(invitations) == 0 {
+		return nil, nil
+	}
+
+	return &invitations[0], nil
+}
+
+// GetAll retrieves the invitations, newest first
+func (r *InvitationRepository) GetAll(ctx context.Context) ([]model.Invitation, error) {
+	data, _, err := r.client.From("invitation").Select("*", "exact", false).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching invitations", "error", err)
+		return nil, err
+	}
+
+	var invitations []model.Invitation
+	if err := json.Unmarshal(data, &invitations); err != nil {
+		logging.FromContext(ctx).Error("error parsing invitation data", "error", err)
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// MarkAccepted records that an open invitation was accepted by creating userID and reports
+// whether it was still open; accepted and revoked invitations are not touched
+func (r *InvitationRepository) MarkAccepted(ctx context.Context, id, userID uuid.UUID, acceptedAt time.Time) (bool, error) {
+	data, _, err := r.client.From("invitation").Update(map[string]interface{}{
+		"accepted_at": acceptedAt,
+		"user_id":     userID,
+	}, "", "").Eq("id", id.String()).Is("accepted_at", "null").Is("revoked_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking invitation as accepted", "id", id, "error", err)
+		return false, err
+	}
+
+	var updated []model.Invitation
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+// Revoke marks an open invitation as revoked and reports whether it was still open
+func (r *InvitationRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) (bool, error) {
+	data, _, err := r.client.From("invitation").Update(map[string]interface{}{
+		"revoked_at": revokedAt,
+	}, "", "").Eq("id", id.String()).Is("accepted_at", "null").Is("revoked_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error revoking invitation", "id", id, "error", err)
+		return false, err
+	}
+
+	var updated []model.Invitation
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
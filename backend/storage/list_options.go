@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+// ListOptions are the pagination, sorting and filtering of a list endpoint. Filters match the
+// columns exactly, Sort is a column name and a Limit of 0 returns every row from Offset.
+type ListOptions struct {
+	Limit      int
+	Offset     int
+	Sort       string
+	Descending bool
+	Filters    map[string]string
+}
+
+// Columns that can be sorted and filtered in each list, checked before they reach PostgREST
+var (
+	UserListColumns               = []string{"id", "email", "role", "person_id", "status", "created_at"}
+	PersonListColumns             = []string{"id", "full_name", "phone", "nit"}
+	PropertyListColumns           = []string{"id", "address", "apt_number", "city", "state", "zip_code", "type", "resident_id", "building_id", "bedrooms", "bathrooms", "area_m2", "estrato", "furnished", "pets_allowed"}
+	RentalHistoryListColumns      = []string{"id", "person_id", "rental_id", "status", "end_reason", "end_date"}
+	MaintenanceRequestListColumns = []string{"id", "property_id", "renter_id", "request_date", "status", "assigned_provider_id"}
+)
+
+// Validate checks that the sort and filter columns are in columns
+func (o ListOptions) Validate(columns []string) error {
+	if o.Sort != "" && !slices.Contains(columns, o.Sort) {
+		return fmt.Errorf("cannot sort by %q, use one of: %s", o.Sort, strings.Join(columns, ", "))
+	}
+	for column := range o.Filters {
+		if !slices.Contains(columns, column) {
+			return fmt.Errorf("cannot filter by %q, use one of: %s", column, strings.Join(columns, ", "))
+		}
+	}
+	return nil
+}
+
+// apply adds the filters, the order and the range of the options to a query. defaultSort keeps
+// the pages stable when no sort is requested; the id breaks ties.
+func (o ListOptions) apply(query *postgrest.FilterBuilder, defaultSort string) *postgrest.FilterBuilder {
+	for column, value := range o.Filters {
+		query = query.Eq(column, value)
+	}
+
+	sortColumn := o.Sort
+	if sortColumn == "" {
+		sortColumn = defaultSort
+	}
+	query = query.Order(sortColumn, &postgrest.OrderOpts{Ascending: !o.Descending})
+	if sortColumn != "id" {
+		query = query.Order("id", &postgrest.OrderOpts{Ascending: true})
+	}
+
+	if o.Limit > 0 {
+		query = query.Range(o.Offset, o.Offset+o.Limit-1, "")
+	} else if o.Offset > 0 {
+		query = query.Range(o.Offset, o.Offset+maxListRows-1, "")
+	}
+	return query
+}
+
+// maxListRows bounds a list requested with an offset but no limit
+const maxListRows = 10000
+
+// ApplyListOptions filters and sorts a list already in memory, such as the lists scoped by the
+// role of the user, by the JSON fields of its items. The limit and offset are not applied.
+func ApplyListOptions[T any](items []T, opts ListOptions) []T {
+	if len(opts.Filters) == 0 && opts.Sort == "" {
+		return items
+	}
+
+	type row struct {
+		item   T
+		fields map[string]any
+	}
+	rows := make([]row, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(data, &fields); err != nil {
+			continue
+		}
+
+		matches := true
+		for column, value := range opts.Filters {
+			if listValueString(fields[column]) != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			rows = append(rows, row{item: item, fields: fields})
+		}
+	}
+
+	if opts.Sort != "" {
+		sort.SliceStable(rows, func(i, j int) bool {
+			c := compareListValues(rows[i].fields[opts.Sort], rows[j].fields[opts.Sort])
+			if opts.Descending {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	result := make([]T, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.item)
+	}
+	return result
+}
+
+// listValueString formats a JSON value as it is written in a filter query parameter
+func listValueString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compareListValues orders numbers numerically and everything else as case-insensitive text
+func compareListValues(a, b any) int {
+	af, aIsNumber := a.(float64)
+	bf, bIsNumber := b.(float64)
+	if aIsNumber && bIsNumber {
+		return cmp.Compare(af, bf)
+	}
+	return strings.Compare(strings.ToLower(listValueString(a)), strings.ToLower(listValueString(b)))
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ListingFilter narrows the available listings. Zero values do not filter.
+type ListingFilter struct {
+	City     string
+	MinPrice float64
+	MaxPrice float64
+}
+
+// ListingRepository provides methods to interact with the property_listing and
+// listing_application tables in Supabase
+type ListingRepository struct {
+	client *supa.Client
+}
+
+// NewListingRepository creates a new ListingRepository
+func NewListingRepository(client *supa.Client) *ListingRepository {
+	return &ListingRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves every listing, most recently updated first
+func (r *ListingRepository) GetAll(ctx context.Context) ([]model.PropertyListing, error) {
+	data, _, err := r.client.From("property_listing").Select("*", "exact", false).
+		Order("updated_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching listings", "error", err)
+		return nil, err
+	}
+
+	var listings []model.PropertyListing
+	if err := json.Unmarshal(data, &listings); err != nil {
+		logging.FromContext(ctx).Error("error parsing listing data", "error", err)
+		return nil, err
+	}
+
+	return listings, nil
+}
+
+// GetAvailable retrieves the available listings matching the filter, cheapest first
+func (r *ListingRepository) GetAvailable(ctx context.Context, filter ListingFilter) ([]model.PropertyListing, error) {
+	query := r.client.From("property_listing").Select("*", "exact", false).
+		Eq("status", model.ListingStatusAvailable)
+	if filter.City != "" {
+		query = query.Ilike("city", filter.City)
+	}
+	if filter.MinPrice > 0 {
+		query = query.Gte("monthly_rent", strconv.FormatFloat(filter.MinPrice, 'f', -1, 64))
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Lte("monthly_rent", strconv.FormatFloat(filter.MaxPrice, 'f', -1, 64))
+	}
+
+	data, _, err := query.Order("monthly_rent", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching available listings", "error", err)
+		return nil, err
+	}
+
+	var listings []model.PropertyListing
+	if err := json.Unmarshal(data, &listings); err != nil {
+		logging.FromContext(ctx).Error("error parsing listing data", "error", err)
+		return nil, err
+	}
+
+	return listings, nil
+}
+
+// GetByID retrieves a listing, nil when it does not exist
+func (r *ListingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PropertyListing, error) {
+	return r.getOne("id", id)
+}
+
+// GetByPropertyID retrieves the listing of a property, nil when it was never listed
+func (r *ListingRepository) GetByPropertyID(ctx context.Context, propertyID uuid.UUID) (*model.PropertyListing, error) {
+	return r.getOne("property_id", propertyID)
+}
+
+func (r *ListingRepository) getOne(column string, id uuid.UUID) (*model.PropertyListing, error) {
+	data, _, err := r.client.From("property_listing").Select("*", "exact", false).
+		Eq(column, id.String()).Execute()
+	if err != nil {
+		slog.Error("error fetching listing", "column", column, "id", id, "error", err)
+		return nil, err
+	}
+
+	var listings []model.PropertyListing
+	if err := json.Unmarshal(data, &listings); err != nil {
+		slog.Error("error parsing listing data", "error", err)
+		return nil, err
+	}
+
+	if len(listings) == 0 {
+		return nil, nil
+	}
+
+	return &listings[0], nil
+}
+
+// Save creates or replaces the listing of a property
+func (r *ListingRepository) Save(ctx context.Context, listing model.PropertyListing) (*model.PropertyListing, error) {
+	now := time.Now()
+	if listing.ID == uuid.Nil {
+		listing.ID = uuid.New()
+		listing.CreatedAt = now
+	}
+	listing.UpdatedAt = now
+
+	data, _, err := r.client.From("property_listing").Upsert(listing, "property_id", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving listing of property", "property_id", listing.PropertyID, "error", err)
+		return nil, fmt.Errorf("failed to save listing: %w", err)
+	}
+
+	var saved []model.PropertyListing
+	if err := json.Unmarshal(data, &saved); err != nil {
+		logging.FromContext(ctx).Error("error parsing saved listing data", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved listing, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// Delete removes a listing with its applications
+func (r *ListingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, _, err := r.client.From("listing_application").Delete("minimal", "").
+		Eq("listing_id", id.String()).Execute(); err != nil {
+		logging.FromContext(ctx).Error("error deleting applications of listing", "id", id, "error", err)
+		return err
+	}
+
+	_, _, err := r.client.From("property_listing").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting listing", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetApplications retrieves the applications to a listing, newest first
+func (r *ListingRepository) GetApplications(ctx context.Context, listingID uuid.UUID) ([]model.ListingApplication, error) {
+	data, _, err := r.client.From("listing_application").Select("*", "exact", false).
+		Eq("listing_id", listingID.String()).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching applications of listing", "listing_id", listingID, "error", err)
+		return nil, err
+	}
+
+	var applications []model.ListingApplication
+	if err := json.Unmarshal(data, &applications); err != nil {
+		logging.FromContext(ctx).Error("error parsing listing application data", "error", err)
+		return nil, err
+	}
+
+	return applications, nil
+}
+
+// GetApplicationByID retrieves an application, nil when it does not exist
+func (r *ListingRepository) GetApplicationByID(ctx context.Context, id uuid.UUID) (*model.ListingApplication, error) {
+	data, _, err := r.client.From("listing_application").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching listing application", "id", id, "error", err)
+		return nil, err
+	}
+
+	var applications []model.ListingApplication
+	if err := json.Unmarshal(data, &applications); err != nil {
+		logging.FromContext(ctx).Error("error parsing listing application data", "error", err)
+		return nil, err
+	}
+
+	if len(applications) == 0 {
+		return nil, nil
+	}
+
+	return &applications[0], nil
+}
+
+// CreateApplication records the application of a prospect
+func (r *ListingRepository) CreateApplication(ctx context.Context, application model.ListingApplication) (*model.ListingApplication, error) {
+	if application.ID == uuid.Nil {
+		application.ID = uuid.New()
+	}
+	now := time.Now()
+	application.CreatedAt = now
+	application.UpdatedAt = now
+
+	data, _, err := r.client.From("listing_application").Insert(application, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating application to listing", "listing_id", application.ListingID, "error", err)
+		return nil, fmt.Errorf("failed to create listing application: %w", err)
+	}
+
+	var created []model.ListingApplication
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created listing application data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created listing application, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateApplication saves the status and notes of an application
+func (r *ListingRepository) UpdateApplication(ctx context.Context, application model.ListingApplication) (*model.ListingApplication, error) {
+	application.UpdatedAt = time.Now()
+
+	data, _, err := r.client.From("listing_application").Update(application, "representation", "").
+		Eq("id", application.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating listing application", "application_id", application.ID, "error", err)
+		return nil, fmt.Errorf("failed to update listing application: %w", err)
+	}
+
+	var updated []model.ListingApplication
+	if err := json.Unmarshal(data, &updated); err != nil {
+		logging.FromContext(ctx).Error("error parsing updated listing application data", "error", err)
+		return nil, err
+	}
+
+	if len(updated) == 0 {
+		return nil, fmt.Errorf("listing application %s not found", application.ID)
+	}
+
+	return &updated[0], nil
+}
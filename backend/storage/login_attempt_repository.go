@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// LoginAttemptRepository provides methods to interact with the login_attempt table in Supabase
+type LoginAttemptRepository struct {
+	client *supa.Client
+}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository
+func NewLoginAttemptRepository(client *supa.Client) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		client: client,
+	}
+}
+
+// Create records a login attempt
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt model.LoginAttempt) (*model.LoginAttempt, error) {
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("login_attempt").Insert(attempt, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error recording login attempt", "email", attempt.Email, "error", err)
+		return nil, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+
+	var created []model.LoginAttempt
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created login attempt data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created login attempt, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// GetSince retrieves the attempts whose column (email or ip_address) is value made after since,
+// newest first
+func (r *LoginAttemptRepository) GetSince(ctx context.Context, column, value string, since time.Time) ([]model.LoginAttempt, error) {
+	data, _, err := r.client.From("login_attempt").Select("*", "exact", false).
+		Eq(column, value).
+		Gte("created_at", since.UTC().Format(time.RFC3339Nano)).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching login attempts", "column", column, "key", value, "error", err)
+		return nil, err
+	}
+
+	var attempts []model.LoginAttempt
+	if err := json.Unmarshal(data, &attempts); err != nil {
+		logging.FromContext(ctx).Error("error parsing login attempt data", "error", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// LoginAttemptFilter selects the attempts listed to the admins
+type LoginAttemptFilter struct {
+	Email      string
+	IPAddress  string
+	FailedOnly bool
+	Since      time.Time
+	Limit      int
+}
+
+// List retrieves the attempts matching the filter, newest first
+func (r *LoginAttemptRepository) List(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	query := r.client.From("login_attempt").Select("*", "exact", false).
+		Gte("created_at", filter.Since.UTC().Format(time.RFC3339Nano))
+	if filter.Email != "" {
+		query = query.Eq("email", filter.Email)
+	}
+	if filter.IPAddress != "" {
+		query = query.Eq("ip_address", filter.IPAddress)
+	}
+	if filter.FailedOnly {
+		query = query.Eq("success", "false")
+	}
+	query = query.Order("created_at", &postgrest.OrderOpts{Ascending: false})
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit, "")
+	}
+
+	data, _, err := query.Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing login attempts", "error", err)
+		return nil, err
+	}
+
+	var attempts []model.LoginAttempt
+	if err := json.Unmarshal(data, &attempts); err != nil {
+		logging.FromContext(ctx).Error("error parsing login attempt data", "error", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// DeleteBefore removes the attempts older than before, they are only kept for the lockouts and
+// the recent history
+func (r *LoginAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	_, _, err := r.client.From("login_attempt").Delete("minimal", "").
+		Lt("created_at", before.UTC().Format(time.RFC3339Nano)).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting old login attempts", "error", err)
+		return err
+	}
+
+	return nil
+}
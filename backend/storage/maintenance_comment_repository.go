@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/model"
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// MaintenanceComment represents a comment on a maintenance request in storage
+type MaintenanceComment struct {
+	ID        string             `json:"id"`
+	RequestID string             `json:"request_id"`
+	AuthorID  string             `json:"author_id"`
+	Body      string             `json:"body"`
+	CreatedAt model.FlexibleTime `json:"created_at"`
+}
+
+// MaintenanceCommentRepository interfaces with the maintenance_comment table
+type MaintenanceCommentRepository struct {
+	client *supa.Client
+}
+
+// NewMaintenanceCommentRepository creates a new maintenance comment repository
+func NewMaintenanceCommentRepository(client *supa.Client) *MaintenanceCommentRepository {
+	return &MaintenanceCommentRepository{
+		client: client,
+	}
+}
+
+// GetByRequestID retrieves all comments for a maintenance request, oldest first
+func (r *MaintenanceCommentRepository) GetByRequestID(requestID string) ([]MaintenanceComment, error) {
+	data, count, err := r.client.From("maintenance_comment").Select("*", "exact", false).
+		Eq("request_id", requestID).Execute()
+	if err != nil {
+		slog.Error("error fetching comments for maintenance request", "error", err)
+		return nil, fmt.Errorf("failed to fetch maintenance comments: %w", err)
+	}
+
+	slog.Debug("retrieved comments for maintenance request", "count", count, "maintenance_request_id", requestID)
+
+	var comments []MaintenanceComment
+	err = json.Unmarshal([]byte(data), &comments)
+	if err != nil {
+		slog.Error("error parsing maintenance comment data", "error", err)
+		return nil, fmt.Errorf("failed to parse maintenance comment data: %w", err)
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Time().Before(comments[j].CreatedAt.Time())
+	})
+
+	return comments, nil
+}
+
+// Create adds a new comment to a maintenance request
+func (r *MaintenanceCommentRepository) Create(comment *MaintenanceComment) (*MaintenanceComment, error) {
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+
+	data, _, err := r.client.From("maintenance_comment").Insert(*comment, false, "exact", "", "").Execute()
+	if err != nil {
+		slog.Error("error creating maintenance comment", "error", err)
+		return nil, fmt.Errorf("failed to create maintenance comment: %w", err)
+	}
+
+	var created []MaintenanceComment
+	err = json.Unmarshal([]byte(data), &created)
+	if err != nil {
+		slog.Error("error parsing maintenance comment data", "error", err)
+		return nil, fmt.Errorf("failed to parse maintenance comment data: %w", err)
+	}
+
+	if len(created) == 0 {
+		return nil, errors.New("no maintenance comment was created")
+	}
+
+	return &created[0], nil
+}
+
+// Delete removes a comment from a maintenance request
+func (r *MaintenanceCommentRepository) Delete(id string) error {
+	_, _, err := r.client.From("maintenance_comment").Delete("minimal", "").
+		Eq("id", id).Execute()
+	if err != nil {
+		slog.Error("error deleting maintenance comment", "error", err)
+		return fmt.Errorf("failed to delete maintenance comment: %w", err)
+	}
+
+	return nil
+}
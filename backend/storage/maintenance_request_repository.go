@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/nescool101/rentManager/model"
@@ -19,6 +19,8 @@ type MaintenanceRequest struct {
 	Description string             `json:"description"`
 	RequestDate model.FlexibleTime `json:"request_date"`
 	Status      string             `json:"status"`
+	// AssignedProviderID is the service provider handling the request, empty when unassigned
+	AssignedProviderID string `json:"assigned_provider_id,omitempty"`
 }
 
 // MaintenanceRequestRepository interfaces with the maintenance_request table
@@ -39,28 +41,46 @@ func (r *MaintenanceRequestRepository) GetAll() ([]MaintenanceRequest, error) {
 
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance requests: %v", err)
+		slog.Error("error fetching maintenance requests", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance requests: %w", err)
 	}
 
-	log.Printf("Retrieved %d maintenance requests", count)
+	slog.Debug("retrieved maintenance requests", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
 	return requests, nil
 }
 
+// List retrieves a page of maintenance requests with the total count of requests matching the filters
+func (r *MaintenanceRequestRepository) List(opts ListOptions) ([]MaintenanceRequest, int, error) {
+	query := r.client.From("maintenance_request").Select("*", "exact", false)
+	data, count, err := opts.apply(query, "request_date").Execute()
+	if err != nil {
+		slog.Error("error listing maintenance requests", "error", err)
+		return nil, 0, fmt.Errorf("failed to list maintenance requests: %w", err)
+	}
+
+	var requests []MaintenanceRequest
+	if err := json.Unmarshal(data, &requests); err != nil {
+		slog.Error("error parsing maintenance request data", "error", err)
+		return nil, 0, fmt.Errorf("failed to parse maintenance request data: %w", err)
+	}
+
+	return requests, int(count), nil
+}
+
 // GetByID retrieves a maintenance request by ID
 func (r *MaintenanceRequestRepository) GetByID(id string) (*MaintenanceRequest, error) {
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance request by ID: %v", err)
+		slog.Error("error fetching maintenance request by ID", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance request: %w", err)
 	}
 
@@ -72,7 +92,7 @@ func (r *MaintenanceRequestRepository) GetByID(id string) (*MaintenanceRequest,
 	var requests []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
@@ -88,16 +108,16 @@ func (r *MaintenanceRequestRepository) GetByPropertyID(propertyID string) ([]Mai
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).
 		Eq("property_id", propertyID).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance requests for property: %v", err)
+		slog.Error("error fetching maintenance requests for property", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance requests for property: %w", err)
 	}
 
-	log.Printf("Retrieved %d maintenance requests for property %s", count, propertyID)
+	slog.Debug("retrieved maintenance requests for property", "count", count, "property_id", propertyID)
 
 	var requests []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
@@ -121,16 +141,16 @@ func (r *MaintenanceRequestRepository) GetByPropertyIDs(propertyIDs []string) ([
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).
 		In("property_id", propertyIDs).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance requests for multiple properties: %v", err)
+		slog.Error("error fetching maintenance requests for multiple properties", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance requests for properties: %w", err)
 	}
 
-	log.Printf("Retrieved %d maintenance requests for %d properties", count, len(propertyIDs))
+	slog.Debug("retrieved maintenance requests properties", "count", count, "property_ids_count", len(propertyIDs))
 
 	var requests []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
@@ -142,16 +162,16 @@ func (r *MaintenanceRequestRepository) GetByRenterID(renterID string) ([]Mainten
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).
 		Eq("renter_id", renterID).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance requests for renter: %v", err)
+		slog.Error("error fetching maintenance requests for renter", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance requests for renter: %w", err)
 	}
 
-	log.Printf("Retrieved %d maintenance requests for renter %s", count, renterID)
+	slog.Debug("retrieved maintenance requests for renter", "count", count, "renter_id", renterID)
 
 	var requests []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
@@ -163,16 +183,16 @@ func (r *MaintenanceRequestRepository) GetByStatus(status string) ([]Maintenance
 	data, count, err := r.client.From("maintenance_request").Select("*", "exact", false).
 		Eq("status", status).Execute()
 	if err != nil {
-		log.Printf("Error fetching maintenance requests by status: %v", err)
+		slog.Error("error fetching maintenance requests by status", "error", err)
 		return nil, fmt.Errorf("failed to fetch maintenance requests by status: %w", err)
 	}
 
-	log.Printf("Retrieved %d maintenance requests with status %s", count, status)
+	slog.Debug("retrieved maintenance requests with status", "count", count, "status", status)
 
 	var requests []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &requests)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
@@ -190,23 +210,23 @@ func (r *MaintenanceRequestRepository) Create(request *MaintenanceRequest) (*Mai
 
 	data, count, err := r.client.From("maintenance_request").Insert(*request, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating maintenance request: %v", err)
+		slog.Error("error creating maintenance request", "error", err)
 		return nil, fmt.Errorf("failed to create maintenance request: %w", err)
 	}
 
 	// If count is 0 but data was returned, try to parse it
 	if count == 0 && len(data) > 0 {
-		log.Printf("Insert returned count 0 but has data, attempting to parse: %s", data)
+		slog.Info("insert returned count 0 but has data, attempting to parse", "data", string(data))
 	}
 
 	var createdRequest []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &createdRequest)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 
 		// If we can't parse, try to fetch the request by ID as a fallback
 		if count == 0 {
-			log.Printf("Trying to fetch newly created request by ID as fallback...")
+			slog.Info("trying to fetch newly created request by ID as fallback")
 			return r.GetByID(requestID)
 		}
 
@@ -216,7 +236,7 @@ func (r *MaintenanceRequestRepository) Create(request *MaintenanceRequest) (*Mai
 	if len(createdRequest) == 0 {
 		// If empty result but count was 0, try fetching by ID as a fallback
 		if count == 0 {
-			log.Printf("Insert returned empty result, trying to fetch by ID as fallback...")
+			slog.Info("insert returned empty result, trying to fetch by ID as fallback")
 			return r.GetByID(requestID)
 		}
 
@@ -231,23 +251,59 @@ func (r *MaintenanceRequestRepository) Update(id string, request *MaintenanceReq
 	data, count, err := r.client.From("maintenance_request").Update(*request, "exact", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error updating maintenance request: %v", err)
+		slog.Error("error updating maintenance request", "error", err)
 		return nil, fmt.Errorf("failed to update maintenance request: %w", err)
 	}
 
-	if count == 0 {
-		return nil, errors.New("maintenance request not found")
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(id)
 	}
 
 	var updatedRequest []MaintenanceRequest
 	err = json.Unmarshal([]byte(data), &updatedRequest)
 	if err != nil {
-		log.Printf("Error parsing maintenance request data: %v", err)
+		slog.Error("error parsing maintenance request data", "error", err)
 		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
 	}
 
 	if len(updatedRequest) == 0 {
-		return nil, errors.New("maintenance request not found")
+		return r.GetByID(id)
+	}
+
+	return &updatedRequest[0], nil
+}
+
+// SetAssignedProvider assigns a service provider to a maintenance request.
+// An empty providerID clears the assignment.
+func (r *MaintenanceRequestRepository) SetAssignedProvider(id string, providerID string) (*MaintenanceRequest, error) {
+	var assigned interface{}
+	if providerID != "" {
+		assigned = providerID
+	}
+
+	data, count, err := r.client.From("maintenance_request").
+		Update(map[string]interface{}{"assigned_provider_id": assigned}, "exact", "").
+		Eq("id", id).Execute()
+	if err != nil {
+		slog.Error("error updating assigned provider of maintenance request", "error", err)
+		return nil, fmt.Errorf("failed to update assigned provider: %w", err)
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(id)
+	}
+
+	var updatedRequest []MaintenanceRequest
+	err = json.Unmarshal([]byte(data), &updatedRequest)
+	if err != nil {
+		slog.Error("error parsing maintenance request data", "error", err)
+		return nil, fmt.Errorf("failed to parse maintenance request data: %w", err)
+	}
+
+	if len(updatedRequest) == 0 {
+		return r.GetByID(id)
 	}
 
 	return &updatedRequest[0], nil
@@ -258,7 +314,7 @@ func (r *MaintenanceRequestRepository) Delete(id string) error {
 	_, _, err := r.client.From("maintenance_request").Delete("minimal", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error deleting maintenance request: %v", err)
+		slog.Error("error deleting maintenance request", "error", err)
 		return fmt.Errorf("failed to delete maintenance request: %w", err)
 	}
 
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/model"
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// MaintenanceStatusChange represents a single status transition of a maintenance request
+type MaintenanceStatusChange struct {
+	ID         string             `json:"id"`
+	RequestID  string             `json:"request_id"`
+	FromStatus string             `json:"from_status"`
+	ToStatus   string             `json:"to_status"`
+	ChangedBy  string             `json:"changed_by"`
+	ChangedAt  model.FlexibleTime `json:"changed_at"`
+}
+
+// MaintenanceStatusHistoryRepository interfaces with the maintenance_status_history table
+type MaintenanceStatusHistoryRepository struct {
+	client *supa.Client
+}
+
+// NewMaintenanceStatusHistoryRepository creates a new maintenance status history repository
+func NewMaintenanceStatusHistoryRepository(client *supa.Client) *MaintenanceStatusHistoryRepository {
+	return &MaintenanceStatusHistoryRepository{
+		client: client,
+	}
+}
+
+// GetByRequestID retrieves the status transitions of a maintenance request, oldest first
+func (r *MaintenanceStatusHistoryRepository) GetByRequestID(requestID string) ([]MaintenanceStatusChange, error) {
+	data, count, err := r.client.From("maintenance_status_history").Select("*", "exact", false).
+		Eq("request_id", requestID).Execute()
+	if err != nil {
+		slog.Error("error fetching status history for maintenance request", "error", err)
+		return nil, fmt.Errorf("failed to fetch maintenance status history: %w", err)
+	}
+
+	slog.Debug("retrieved status changes for maintenance request", "count", count, "maintenance_request_id", requestID)
+
+	var changes []MaintenanceStatusChange
+	err = json.Unmarshal([]byte(data), &changes)
+	if err != nil {
+		slog.Error("error parsing maintenance status history data", "error", err)
+		return nil, fmt.Errorf("failed to parse maintenance status history data: %w", err)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ChangedAt.Time().Before(changes[j].ChangedAt.Time())
+	})
+
+	return changes, nil
+}
+
+// Create records a new status transition
+func (r *MaintenanceStatusHistoryRepository) Create(change *MaintenanceStatusChange) (*MaintenanceStatusChange, error) {
+	if change.ID == "" {
+		change.ID = uuid.New().String()
+	}
+
+	data, _, err := r.client.From("maintenance_status_history").Insert(*change, false, "exact", "", "").Execute()
+	if err != nil {
+		slog.Error("error creating maintenance status change", "error", err)
+		return nil, fmt.Errorf("failed to create maintenance status change: %w", err)
+	}
+
+	var created []MaintenanceStatusChange
+	err = json.Unmarshal([]byte(data), &created)
+	if err != nil {
+		slog.Error("error parsing maintenance status history data", "error", err)
+		return nil, fmt.Errorf("failed to parse maintenance status history data: %w", err)
+	}
+
+	if len(created) == 0 {
+		return nil, errors.New("no maintenance status change was created")
+	}
+
+	return &created[0], nil
+}
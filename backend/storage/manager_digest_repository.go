@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ManagerDigestRepository provides methods to interact with the manager_digest_subscription table in Supabase
+type ManagerDigestRepository struct {
+	client *supa.Client
+}
+
+// NewManagerDigestRepository creates a new ManagerDigestRepository
+func NewManagerDigestRepository(client *supa.Client) *ManagerDigestRepository {
+	return &ManagerDigestRepository{
+		client: client,
+	}
+}
+
+// GetSubscribed retrieves the subscriptions with a digest frequency
+func (r *ManagerDigestRepository) GetSubscribed(ctx context.Context) ([]model.ManagerDigestSubscription, error) {
+	data, count, err := r.client.From("manager_digest_subscription").Select("*", "exact", false).
+		Neq("frequency", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching digest subscriptions", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("retrieved digest subscriptions", "count", count)
+
+	var subscriptions []model.ManagerDigestSubscription
+	err = json.Unmarshal([]byte(data), &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing digest subscription data", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetByPersonID retrieves the digest subscription of a manager, nil when the manager has none
+func (r *ManagerDigestRepository) GetByPersonID(ctx context.Context, personID uuid.UUID) (*model.ManagerDigestSubscription, error) {
+	data, count, err := r.client.From("manager_digest_subscription").Select("*", "exact", false).
+		Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching digest subscription for person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var subscriptions []model.ManagerDigestSubscription
+	err = json.Unmarshal([]byte(data), &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing digest subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &subscriptions[0], nil
+}
+
+// Create adds the digest subscription of a manager
+func (r *ManagerDigestRepository) Create(ctx context.Context, subscription model.ManagerDigestSubscription) (*model.ManagerDigestSubscription, error) {
+	if subscription.ID == uuid.Nil {
+		subscription.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("manager_digest_subscription").Insert(subscription, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating digest subscription", "error", err)
+		return nil, fmt.Errorf("failed to create digest subscription: %w", err)
+	}
+
+	var createdSubscriptions []model.ManagerDigestSubscription
+	err = json.Unmarshal(data, &createdSubscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created digest subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(createdSubscriptions) == 0 {
+		return nil, fmt.Errorf("failed to parse created digest subscription, empty result set")
+	}
+
+	return &createdSubscriptions[0], nil
+}
+
+// UpdateFrequency changes the digest frequency of a manager
+func (r *ManagerDigestRepository) UpdateFrequency(ctx context.Context, personID uuid.UUID, frequency string) (*model.ManagerDigestSubscription, error) {
+	_, _, err := r.client.From("manager_digest_subscription").Update(map[string]interface{}{
+		"frequency": frequency,
+	}, "", "").Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating digest subscription for person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	return r.GetByPersonID(ctx, personID)
+}
+
+// MarkSent records a sent digest and the arrears it reported, used to compute the next changes
+func (r *ManagerDigestRepository) MarkSent(ctx context.Context, personID uuid.UUID, sentAt time.Time, arrears map[string]int) error {
+	_, _, err := r.client.From("manager_digest_subscription").Update(map[string]interface{}{
+		"last_sent_at": sentAt,
+		"last_arrears": arrears,
+	}, "", "").Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking digest as sent for person", "person_id", personID, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// NotarizationRepository provides methods to interact with the notarization table in Supabase
+type NotarizationRepository struct {
+	client *supa.Client
+}
+
+// NewNotarizationRepository creates a new NotarizationRepository
+func NewNotarizationRepository(client *supa.Client) *NotarizationRepository {
+	return &NotarizationRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a notarization by ID
+func (r *NotarizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Notarization, error) {
+	data, count, err := r.client.From("notarization").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching notarization", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var notarizations []model.Notarization
+	err = json.Unmarshal(data, &notarizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing notarization data", "error", err)
+		return nil, err
+	}
+
+	if len(notarizations) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &notarizations[0], nil
+}
+
+// GetBySigningID retrieves the notarizations of a signed contract, newest first
+func (r *NotarizationRepository) GetBySigningID(ctx context.Context, signingID string) ([]model.Notarization, error) {
+	data, _, err := r.client.From("notarization").Select("*", "exact", false).
+		Eq("signing_id", signingID).
+		Order("requested_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching notarizations for signing request", "signing_id", signingID, "error", err)
+		return nil, err
+	}
+
+	var notarizations []model.Notarization
+	err = json.Unmarshal(data, &notarizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing notarization data", "error", err)
+		return nil, err
+	}
+
+	return notarizations, nil
+}
+
+// GetByExternalID retrieves a notarization by the ID assigned by the notary service
+func (r *NotarizationRepository) GetByExternalID(ctx context.Context, provider, externalID string) (*model.Notarization, error) {
+	data, _, err := r.client.From("notarization").Select("*", "exact", false).
+		Eq("provider", provider).
+		Eq("external_id", externalID).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching notarization external_id", "provider", provider, "external_id", externalID, "error", err)
+		return nil, err
+	}
+
+	var notarizations []model.Notarization
+	err = json.Unmarshal(data, &notarizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing notarization data", "error", err)
+		return nil, err
+	}
+
+	if len(notarizations) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &notarizations[0], nil
+}
+
+// Create adds a new notarization
+func (r *NotarizationRepository) Create(ctx context.Context, notarization model.Notarization) (*model.Notarization, error) {
+	if notarization.ID == uuid.Nil {
+		notarization.ID = uuid.New()
+	}
+	if notarization.RequestedAt.IsZero() {
+		notarization.RequestedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("notarization").Insert(notarization, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating notarization", "error", err)
+		return nil, fmt.Errorf("failed to create notarization: %w", err)
+	}
+
+	var created []model.Notarization
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created notarization data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created notarization, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateResult stores the status reported by the notary and the authenticated document
+func (r *NotarizationRepository) UpdateResult(ctx context.Context, notarization model.Notarization) (*model.Notarization, error) {
+	_, _, err := r.client.From("notarization").Update(map[string]interface{}{
+		"status":           notarization.Status,
+		"reference":        notarization.Reference,
+		"observations":     notarization.Observations,
+		"stamped_pdf_path": notarization.StampedPDFPath,
+		"completed_at":     notarization.CompletedAt,
+	}, "", "").Eq("id", notarization.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating notarization", "notarization_id", notarization.ID, "error", err)
+		return nil, err
+	}
+
+	return r.GetByID(ctx, notarization.ID)
+}
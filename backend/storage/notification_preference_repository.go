@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// NotificationPreferenceRepository provides methods to interact with the notification_preferences table in Supabase
+type NotificationPreferenceRepository struct {
+	client *supa.Client
+}
+
+// NewNotificationPreferenceRepository creates a new NotificationPreferenceRepository
+func NewNotificationPreferenceRepository(client *supa.Client) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		client: client,
+	}
+}
+
+// notificationPreferenceRow is the editable part of a notification preference. LastSentAt is
+// left out so saving a preference does not reset the frequency window.
+type notificationPreferenceRow struct {
+	PersonID  uuid.UUID `json:"person_id"`
+	Channel   string    `json:"channel"`
+	Type      string    `json:"type"`
+	Frequency string    `json:"frequency"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetByPersonID retrieves the stored notification preferences of a person
+func (r *NotificationPreferenceRepository) GetByPersonID(ctx context.Context, personID uuid.UUID) ([]model.NotificationPreference, error) {
+	data, _, err := r.client.From("notification_preferences").Select("*", "exact", false).
+		Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching notification preferences for person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	var preferences []model.NotificationPreference
+	err = json.Unmarshal(data, &preferences)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing notification preference data", "error", err)
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// Save creates or replaces the given preferences of a person, keyed by channel and type
+func (r *NotificationPreferenceRepository) Save(ctx context.Context, preferences []model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]notificationPreferenceRow, 0, len(preferences))
+	for _, preference := range preferences {
+		rows = append(rows, notificationPreferenceRow{
+			PersonID:  preference.PersonID,
+			Channel:   preference.Channel,
+			Type:      preference.Type,
+			Frequency: preference.Frequency,
+			UpdatedAt: now,
+		})
+	}
+
+	_, _, err := r.client.From("notification_preferences").Upsert(rows, "person_id,channel,type", "minimal", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving notification preferences", "error", err)
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+// MarkSent records when a notification was last delivered under a stored preference
+func (r *NotificationPreferenceRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	_, _, err := r.client.From("notification_preferences").Update(map[string]interface{}{
+		"last_sent_at": sentAt,
+	}, "minimal", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking notification preference as sent", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// NotificationRepository provides methods to interact with the notification table in Supabase
+type NotificationRepository struct {
+	client *supa.Client
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(client *supa.Client) *NotificationRepository {
+	return &NotificationRepository{
+		client: client,
+	}
+}
+
+// Create stores notifications, one per recipient of an event
+func (r *NotificationRepository) Create(ctx context.Context, notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	_, _, err := r.client.From("notification").Insert(notifications, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating notifications", "notifications_count", len(notifications), "error", err)
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUser retrieves a page of the notifications of a user, newest first, with the total
+// number of them. unreadOnly leaves out the ones already read.
+func (r *NotificationRepository) GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]model.Notification, int, error) {
+	query := r.client.From("notification").Select("*", "exact", false).
+		Eq("user_id", userID.String())
+	if unreadOnly {
+		query = query.Is("read_at", "null")
+	}
+	data, count, err := query.
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Range(offset, offset+limit-1, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching notifications of user", "user_id", userID, "error", err)
+		return nil, 0, err
+	}
+
+	var notifications []model.Notification
+	if err := json.Unmarshal(data, &notifications); err != nil {
+		logging.FromContext(ctx).Error("error parsing notification data", "error", err)
+		return nil, 0, err
+	}
+
+	return notifications, int(count), nil
+}
+
+// CountUnread returns how many notifications of a user have not been read
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	_, count, err := r.client.From("notification").Select("id", "exact", true).
+		Eq("user_id", userID.String()).
+		Is("read_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error counting unread notifications of user", "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// MarkRead marks a notification of a user as read and reports whether it was unread; the
+// notifications of other users are not touched
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID, readAt time.Time) (bool, error) {
+	data, _, err := r.client.From("notification").Update(map[string]interface{}{
+		"read_at": readAt,
+	}, "", "").Eq("id", id.String()).Eq("user_id", userID.String()).Is("read_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking notification as read", "id", id, "error", err)
+		return false, err
+	}
+
+	var updated []model.Notification
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns how many were
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int, error) {
+	data, _, err := r.client.From("notification").Update(map[string]interface{}{
+		"read_at": readAt,
+	}, "", "").Eq("user_id", userID.String()).Is("read_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking notifications of user as read", "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	var updated []model.Notification
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return 0, err
+	}
+	return len(updated), nil
+}
+
+// DeleteReadBefore removes the notifications read before before
+func (r *NotificationRepository) DeleteReadBefore(ctx context.Context, before time.Time) error {
+	_, _, err := r.client.From("notification").Delete("minimal", "").
+		Lt("read_at", before.UTC().Format(time.RFC3339Nano)).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting read notifications", "error", err)
+		return err
+	}
+
+	return nil
+}
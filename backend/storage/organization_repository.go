@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// OrganizationRepository provides methods to interact with the organization table in Supabase
+type OrganizationRepository struct {
+	client *supa.Client
+}
+
+// NewOrganizationRepository creates a new OrganizationRepository
+func NewOrganizationRepository(client *supa.Client) *OrganizationRepository {
+	return &OrganizationRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves all organizations
+func (r *OrganizationRepository) GetAll(ctx context.Context) ([]model.Organization, error) {
+	var organizations []model.Organization
+
+	data, count, err := r.client.From("organization").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching organizations", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("retrieved organizations", "count", count)
+
+	err = json.Unmarshal([]byte(data), &organizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing organization data", "error", err)
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+// GetByID retrieves an organization by ID
+func (r *OrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	data, count, err := r.client.From("organization").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching organization", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var organizations []model.Organization
+	err = json.Unmarshal([]byte(data), &organizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing organization data", "error", err)
+		return nil, err
+	}
+
+	if len(organizations) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &organizations[0], nil
+}
+
+// Create adds a new organization
+func (r *OrganizationRepository) Create(ctx context.Context, organization model.Organization) (*model.Organization, error) {
+	if organization.ID == uuid.Nil {
+		organization.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("organization").Insert(organization, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating organization", "error", err)
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	var createdOrganizations []model.Organization
+	err = json.Unmarshal(data, &createdOrganizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created organization data", "error", err)
+		return nil, err
+	}
+
+	if len(createdOrganizations) == 0 {
+		return nil, fmt.Errorf("failed to parse created organization, empty result set")
+	}
+
+	return &createdOrganizations[0], nil
+}
+
+// Update updates an existing organization
+func (r *OrganizationRepository) Update(ctx context.Context, organization model.Organization) (*model.Organization, error) {
+	organizationData := map[string]interface{}{
+		"name":          organization.Name,
+		"manager_ids":   organization.ManagerIDs,
+		"base_url":      organization.BaseURL,
+		"custom_domain": organization.CustomDomain,
+		"branding":      organization.Branding,
+		"timezone":      organization.Timezone,
+		"locale":        organization.Locale,
+	}
+
+	data, count, err := r.client.From("organization").Update(organizationData, "exact", "").
+		Eq("id", organization.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating organization", "organization_id", organization.ID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(ctx, organization.ID)
+	}
+
+	var updatedOrganizations []model.Organization
+	err = json.Unmarshal(data, &updatedOrganizations)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated organization data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedOrganizations) == 0 {
+		return r.GetByID(ctx, organization.ID)
+	}
+
+	return &updatedOrganizations[0], nil
+}
+
+// Delete removes an organization
+func (r *OrganizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("organization").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting organization", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
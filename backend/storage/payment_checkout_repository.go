@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// PaymentCheckoutRepository provides methods to interact with the payment_checkout table in Supabase
+type PaymentCheckoutRepository struct {
+	client *supa.Client
+}
+
+// NewPaymentCheckoutRepository creates a new PaymentCheckoutRepository
+func NewPaymentCheckoutRepository(client *supa.Client) *PaymentCheckoutRepository {
+	return &PaymentCheckoutRepository{
+		client: client,
+	}
+}
+
+// GetByReference retrieves the checkout with a gateway reference, nil when there is none
+func (r *PaymentCheckoutRepository) GetByReference(ctx context.Context, reference string) (*model.PaymentCheckout, error) {
+	data, _, err := r.client.From("payment_checkout").Select("*", "exact", false).
+		Eq("reference", reference).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching payment checkout", "reference", reference, "error", err)
+		return nil, err
+	}
+
+	var checkouts []model.PaymentCheckout
+	err = json.Unmarshal(data, &checkouts)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing payment checkout data", "error", err)
+		return nil, err
+	}
+
+	if len(checkouts) == 0 {
+		return nil, nil
+	}
+
+	return &checkouts[0], nil
+}
+
+// GetApprovedByRentalAndPeriod retrieves the approved checkout paying a billing period of a
+// rental, nil when it was not paid online
+func (r *PaymentCheckoutRepository) GetApprovedByRentalAndPeriod(ctx context.Context, rentalID uuid.UUID, period string) (*model.PaymentCheckout, error) {
+	data, _, err := r.client.From("payment_checkout").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Eq("period", period).
+		Eq("status", model.PaymentCheckoutStatusApproved).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching approved checkout of rental", "rental_id", rentalID, "period", period, "error", err)
+		return nil, err
+	}
+
+	var checkouts []model.PaymentCheckout
+	err = json.Unmarshal(data, &checkouts)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing payment checkout data", "error", err)
+		return nil, err
+	}
+
+	if len(checkouts) == 0 {
+		return nil, nil
+	}
+
+	return &checkouts[0], nil
+}
+
+// Create records a started checkout
+func (r *PaymentCheckoutRepository) Create(ctx context.Context, checkout model.PaymentCheckout) (*model.PaymentCheckout, error) {
+	if checkout.ID == uuid.Nil {
+		checkout.ID = uuid.New()
+	}
+	if checkout.CreatedAt.IsZero() {
+		checkout.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("payment_checkout").Insert(checkout, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating payment checkout", "reference", checkout.Reference, "error", err)
+		return nil, fmt.Errorf("failed to create payment checkout: %w", err)
+	}
+
+	var created []model.PaymentCheckout
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created payment checkout data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created payment checkout, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateResult records the state of the transaction of a checkout and the rent payment it
+// registered
+func (r *PaymentCheckoutRepository) UpdateResult(ctx context.Context, checkout model.PaymentCheckout) error {
+	_, _, err := r.client.From("payment_checkout").Update(map[string]interface{}{
+		"status":          checkout.Status,
+		"transaction_id":  checkout.TransactionID,
+		"payment_method":  checkout.PaymentMethod,
+		"rent_payment_id": checkout.RentPaymentID,
+		"completed_at":    checkout.CompletedAt,
+	}, "minimal", "").Eq("id", checkout.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating payment checkout", "reference", checkout.Reference, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RegisterPayment records the result of an approved checkout and creates its rent payment,
+// numbered as in RentPaymentRepository.CreateNumbered, in one transaction. The checkout row is
+// locked first, so when the payment was already registered, by a retried event or by another
+// instance, the existing payment is returned and none is created: compare its ID with the one
+// of payment to tell them apart.
+func (r *PaymentCheckoutRepository) RegisterPayment(ctx context.Context, checkout model.PaymentCheckout, payment *RentPayment, organizationID uuid.UUID, year int) (*RentPayment, error) {
+	type Result struct {
+		TransactionID string     `json:"transaction_id"`
+		PaymentMethod string     `json:"payment_method"`
+		CompletedAt   *time.Time `json:"completed_at"`
+	}
+	type Request struct {
+		CheckoutID     uuid.UUID    `json:"checkout_id"`
+		Result         Result       `json:"result"`
+		Payment        *RentPayment `json:"payment"`
+		OrganizationID uuid.UUID    `json:"sequence_organization_id"`
+		Year           int          `json:"sequence_year"`
+	}
+
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
+
+	var registered RentPayment
+	err := callTransaction(r.client, "register_checkout_payment", Request{
+		CheckoutID:     checkout.ID,
+		Result:         Result{TransactionID: checkout.TransactionID, PaymentMethod: checkout.PaymentMethod, CompletedAt: checkout.CompletedAt},
+		Payment:        payment,
+		OrganizationID: organizationID,
+		Year:           year,
+	}, &registered)
+	if err != nil {
+		logging.FromContext(ctx).Error("error registering the payment of checkout", "reference", checkout.Reference, "error", err)
+		return nil, fmt.Errorf("failed to register the payment of checkout %s: %w", checkout.Reference, err)
+	}
+	return &registered, nil
+}
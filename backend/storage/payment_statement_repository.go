@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// PaymentStatementRepository provides methods to interact with the payment_statement table in Supabase
+type PaymentStatementRepository struct {
+	client *supa.Client
+}
+
+// NewPaymentStatementRepository creates a new PaymentStatementRepository
+func NewPaymentStatementRepository(client *supa.Client) *PaymentStatementRepository {
+	return &PaymentStatementRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves an issued payment statement by ID
+func (r *PaymentStatementRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PaymentStatement, error) {
+	data, _, err := r.client.From("payment_statement").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching payment statement", "id", id, "error", err)
+		return nil, err
+	}
+
+	var statements []model.PaymentStatement
+	err = json.Unmarshal(data, &statements)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing payment statement data", "error", err)
+		return nil, err
+	}
+
+	if len(statements) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &statements[0], nil
+}
+
+// Create records an issued payment statement
+func (r *PaymentStatementRepository) Create(ctx context.Context, statement model.PaymentStatement) (*model.PaymentStatement, error) {
+	if statement.ID == uuid.Nil {
+		statement.ID = uuid.New()
+	}
+	if statement.IssuedAt.IsZero() {
+		statement.IssuedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("payment_statement").Insert(statement, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating payment statement", "error", err)
+		return nil, fmt.Errorf("failed to create payment statement: %w", err)
+	}
+
+	var created []model.PaymentStatement
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created payment statement data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created payment statement, empty result set")
+	}
+
+	return &created[0], nil
+}
@@ -4,54 +4,85 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 	supa "github.com/supabase-community/supabase-go"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
 // PersonRepository provides methods to interact with the Person table in Supabase
 type PersonRepository struct {
 	client *supa.Client
+	db     *pgxpool.Pool                      // Direct connection of STORAGE_DRIVER=postgres, nil with the REST driver
+	cache  *ttlCache[uuid.UUID, model.Person] // Persons read by ID, see defaultReferenceCacheTTL
 }
 
 // NewPersonRepository creates a new PersonRepository
 func NewPersonRepository(client *supa.Client) *PersonRepository {
 	return &PersonRepository{
 		client: client,
+		cache:  newTTLCache[uuid.UUID, model.Person](defaultReferenceCacheTTL),
 	}
 }
 
+// InvalidateCache drops the given persons from the cache, or every person when none is given
+func (r *PersonRepository) InvalidateCache(ids ...uuid.UUID) {
+	r.cache.Invalidate(ids...)
+}
+
 // GetAll retrieves all persons from the database
 func (r *PersonRepository) GetAll(ctx context.Context) ([]model.Person, error) {
 	var persons []model.Person
 
 	data, count, err := r.client.From("person").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching persons: %v", err)
+		logging.FromContext(ctx).Error("error fetching persons", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d persons", count)
+	logging.FromContext(ctx).Debug("retrieved persons", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &persons)
 	if err != nil {
-		log.Printf("Error parsing person data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
 		return nil, err
 	}
 
 	return persons, nil
 }
 
+// List retrieves a page of persons with the total count of persons matching the filters
+func (r *PersonRepository) List(ctx context.Context, opts ListOptions) ([]model.Person, int, error) {
+	query := r.client.From("person").Select("*", "exact", false)
+	data, count, err := opts.apply(query, "full_name").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing persons", "error", err)
+		return nil, 0, err
+	}
+
+	var persons []model.Person
+	if err := json.Unmarshal(data, &persons); err != nil {
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
+		return nil, 0, err
+	}
+
+	return persons, int(count), nil
+}
+
 // GetByID retrieves a person by ID
 func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Person, error) {
+	if person, ok := r.cache.Get(id); ok {
+		return &person, nil
+	}
+
 	data, count, err := r.client.From("person").Select("*", "exact", false).
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching person by ID: %v", err)
+		logging.FromContext(ctx).Error("error fetching person by ID", "error", err)
 		return nil, err
 	}
 
@@ -63,7 +94,7 @@ func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Pe
 	var persons []model.Person
 	err = json.Unmarshal([]byte(data), &persons)
 	if err != nil {
-		log.Printf("Error parsing person data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
 		return nil, err
 	}
 
@@ -71,6 +102,7 @@ func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Pe
 		return nil, nil // Not found
 	}
 
+	r.cache.Set(id, persons[0])
 	return &persons[0], nil
 }
 
@@ -78,6 +110,17 @@ func (r *PersonRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Pe
 
 // GetByRole retrieves persons by role
 func (r *PersonRepository) GetByRole(ctx context.Context, roleName string) ([]model.Person, error) {
+	if r.db != nil {
+		persons, err := queryJSON[model.Person](ctx, r.db, `SELECT to_jsonb(p) FROM person p
+			JOIN person_role pr ON pr.person_id = p.id
+			JOIN role ro ON ro.id = pr.role_id
+			WHERE ro.role_name = $1`, roleName)
+		if err != nil {
+			logging.FromContext(ctx).Error("error fetching persons by role", "error", err)
+		}
+		return persons, err
+	}
+
 	// For a complex query like this with joins, we'll use an RPC function
 	type Request struct {
 		RoleName string `json:"role_name"`
@@ -87,14 +130,14 @@ func (r *PersonRepository) GetByRole(ctx context.Context, roleName string) ([]mo
 	data := r.client.Rpc("get_persons_by_role", "exact", Request{RoleName: roleName})
 	// Check for errors
 	if err := checkRPCError(data); err != nil {
-		log.Printf("Error fetching persons by role: %v", err)
+		logging.FromContext(ctx).Error("error fetching persons by role", "error", err)
 		return nil, err
 	}
 
 	var persons []model.Person
 	err := json.Unmarshal([]byte(data), &persons)
 	if err != nil {
-		log.Printf("Error parsing person data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
 		return nil, err
 	}
 
@@ -107,7 +150,7 @@ func (r *PersonRepository) Create(ctx context.Context, person model.Person) (*mo
 	// Insert(values interface{}, returning string, count string, onConflict string, ignoreDuplicates string)
 	data, count, err := r.client.From("person").Insert(person, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating person: %v", err)
+		logging.FromContext(ctx).Error("error creating person", "error", err)
 		return nil, err
 	}
 
@@ -118,7 +161,7 @@ func (r *PersonRepository) Create(ctx context.Context, person model.Person) (*mo
 	var persons []model.Person
 	err = json.Unmarshal([]byte(data), &persons)
 	if err != nil {
-		log.Printf("Error parsing person data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
 		return nil, err
 	}
 
@@ -129,30 +172,26 @@ func (r *PersonRepository) Create(ctx context.Context, person model.Person) (*mo
 	return &persons[0], nil
 }
 
-// Update updates an existing person
+// Update updates an existing person. It returns ErrVersionConflict when person.Version is set
+// and the person was updated since, and nil when the person does not exist.
 func (r *PersonRepository) Update(ctx context.Context, person model.Person) (*model.Person, error) {
-	// The method signature for Update is:
-	// Update(values interface{}, returning string, count string)
-	data, count, err := r.client.From("person").Update(person, "exact", "").
-		Eq("id", person.ID.String()).Execute()
+	r.cache.Invalidate(person.ID)
+	data, _, err := filterVersion(r.client.From("person").Update(person, "representation", "").
+		Eq("id", person.ID.String()), person.Version).Execute()
 	if err != nil {
-		log.Printf("Error updating person: %v", err)
+		logging.FromContext(ctx).Error("error updating person", "error", err)
 		return nil, err
 	}
 
-	if count == 0 {
-		return nil, nil // No result returned
-	}
-
 	var persons []model.Person
 	err = json.Unmarshal([]byte(data), &persons)
 	if err != nil {
-		log.Printf("Error parsing person data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data", "error", err)
 		return nil, err
 	}
 
 	if len(persons) == 0 {
-		return nil, nil // No result returned
+		return nil, versionMiss(r.client, "person", person.ID.String(), person.Version)
 	}
 
 	return &persons[0], nil
@@ -160,10 +199,11 @@ func (r *PersonRepository) Update(ctx context.Context, person model.Person) (*mo
 
 // Delete removes a person from the database
 func (r *PersonRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.cache.Invalidate(id)
 	_, _, err := r.client.From("person").Delete("minimal", "").
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error deleting person: %v", err)
+		logging.FromContext(ctx).Error("error deleting person", "error", err)
 		return err
 	}
 
@@ -176,31 +216,59 @@ func (r *PersonRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]mod
 		return []model.Person{}, nil
 	}
 
-	// Convert uuid.UUID slice to string slice for the query
-	stringIDs := make([]string, len(ids))
-	for i, id := range ids {
-		stringIDs[i] = id.String()
+	// Serve the cached persons and query the others
+	var persons []model.Person
+	var stringIDs []string
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if person, ok := r.cache.Get(id); ok {
+			persons = append(persons, person)
+			continue
+		}
+		stringIDs = append(stringIDs, id.String())
+	}
+	if len(stringIDs) == 0 {
+		return persons, nil
+	}
+
+	if r.db != nil {
+		fetched, err := queryJSON[model.Person](ctx, r.db, "SELECT to_jsonb(p) FROM person p WHERE p.id = ANY($1::uuid[])", stringIDs)
+		if err != nil {
+			logging.FromContext(ctx).Error("error fetching persons by IDs", "error", err)
+			return nil, err
+		}
+		for _, person := range fetched {
+			r.cache.Set(person.ID, person)
+		}
+		return append(persons, fetched...), nil
 	}
 
-	var persons []model.Person
+	var fetched []model.Person
 	// Use the .In(column, values) filter
 	data, count, err := r.client.From("person").Select("*", "exact", false).
 		In("id", stringIDs).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching persons by IDs: %v", err)
+		logging.FromContext(ctx).Error("error fetching persons by IDs", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d persons by IDs", count)
+	logging.FromContext(ctx).Debug("retrieved persons by IDs", "count", count)
 
-	err = json.Unmarshal([]byte(data), &persons)
+	err = json.Unmarshal([]byte(data), &fetched)
 	if err != nil {
-		log.Printf("Error parsing person data from GetByIDs: %v", err)
+		logging.FromContext(ctx).Error("error parsing person data from GetByIDs", "error", err)
 		return nil, err
 	}
+	for _, person := range fetched {
+		r.cache.Set(person.ID, person)
+	}
 
-	return persons, nil
+	return append(persons, fetched...), nil
 }
 
 // checkRPCError is a utility function to check for errors in RPC responses
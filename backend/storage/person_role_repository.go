@@ -3,9 +3,9 @@ package storage
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	supa "github.com/supabase-community/supabase-go"
 )
@@ -31,24 +31,24 @@ func (r *PersonRoleRepository) Create(ctx context.Context, personRole model.Pers
 
 	data, count, err := r.client.From("person_role").Insert(personRole, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating person_role: %v", err)
+		logging.FromContext(ctx).Error("error creating person_role", "error", err)
 		return nil, err
 	}
 
 	if count == 0 {
-		log.Println("No rows returned after person_role insert")
+		logging.FromContext(ctx).Info("no rows returned after person_role insert")
 		return nil, nil // Or an error indicating no result
 	}
 
 	var createdPersonRoles []model.PersonRole
 	err = json.Unmarshal([]byte(data), &createdPersonRoles)
 	if err != nil {
-		log.Printf("Error parsing person_role data: %v", err)
+		logging.FromContext(ctx).Error("error parsing person_role data", "error", err)
 		return nil, err
 	}
 
 	if len(createdPersonRoles) == 0 {
-		log.Println("Parsed person_role data is empty")
+		logging.FromContext(ctx).Debug("parsed person_role data is empty")
 		return nil, nil // Or an error
 	}
 
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// PlatformSubscriptionRepository provides methods to interact with the platform_subscription table in Supabase
+type PlatformSubscriptionRepository struct {
+	client *supa.Client
+}
+
+// NewPlatformSubscriptionRepository creates a new PlatformSubscriptionRepository
+func NewPlatformSubscriptionRepository(client *supa.Client) *PlatformSubscriptionRepository {
+	return &PlatformSubscriptionRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves all the platform subscriptions
+func (r *PlatformSubscriptionRepository) GetAll(ctx context.Context) ([]model.PlatformSubscription, error) {
+	data, _, err := r.client.From("platform_subscription").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching platform subscriptions", "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.PlatformSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing platform subscription data", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetByUserID retrieves the subscription of a user, nil when there is none
+func (r *PlatformSubscriptionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.PlatformSubscription, error) {
+	return r.getOne("user_id", userID.String())
+}
+
+// GetByCustomerID retrieves the subscription of a customer of the billing provider, nil when
+// there is none
+func (r *PlatformSubscriptionRepository) GetByCustomerID(ctx context.Context, customerID string) (*model.PlatformSubscription, error) {
+	return r.getOne("customer_id", customerID)
+}
+
+// GetPastDue retrieves the subscriptions with an unpaid invoice whose users are not suspended yet
+func (r *PlatformSubscriptionRepository) GetPastDue(ctx context.Context) ([]model.PlatformSubscription, error) {
+	data, _, err := r.client.From("platform_subscription").Select("*", "exact", false).
+		Not("past_due_since", "is", "null").
+		Is("suspended_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching past due platform subscriptions", "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.PlatformSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing platform subscription data", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *PlatformSubscriptionRepository) getOne(column, value string) (*model.PlatformSubscription, error) {
+	data, _, err := r.client.From("platform_subscription").Select("*", "exact", false).
+		Eq(column, value).Execute()
+	if err != nil {
+		slog.Error("error fetching platform subscription", "column", column, "key", value, "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.PlatformSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		slog.Error("error parsing platform subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+
+	return &subscriptions[0], nil
+}
+
+// Save creates or replaces the subscription of a user
+func (r *PlatformSubscriptionRepository) Save(ctx context.Context, subscription model.PlatformSubscription) (*model.PlatformSubscription, error) {
+	if subscription.ID == uuid.Nil {
+		subscription.ID = uuid.New()
+	}
+	now := time.Now()
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = now
+	}
+	subscription.UpdatedAt = now
+
+	data, _, err := r.client.From("platform_subscription").Upsert(subscription, "user_id", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving platform subscription of user", "user_id", subscription.UserID, "error", err)
+		return nil, fmt.Errorf("failed to save platform subscription: %w", err)
+	}
+
+	var saved []model.PlatformSubscription
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing saved platform subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved platform subscription, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// Update stores the billing state of a subscription
+func (r *PlatformSubscriptionRepository) Update(ctx context.Context, subscription model.PlatformSubscription) error {
+	_, _, err := r.client.From("platform_subscription").Update(map[string]interface{}{
+		"subscription_id":    subscription.SubscriptionID,
+		"status":             subscription.Status,
+		"current_period_end": subscription.CurrentPeriodEnd,
+		"payment_url":        subscription.PaymentURL,
+		"past_due_since":     subscription.PastDueSince,
+		"suspended_at":       subscription.SuspendedAt,
+		"updated_at":         time.Now(),
+	}, "minimal", "").Eq("id", subscription.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating platform subscription", "subscription_id", subscription.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/nescool101/rentManager/config"
+	"github.com/nescool101/rentManager/logging"
+)
+
+// OpenPostgres connects to the database URL of cfg when its driver is postgres, and returns a
+// nil pool with the REST driver. The repositories keep using the Supabase client for the queries
+// the direct driver does not implement, so SUPABASE_URL and SUPABASE_KEY are still required.
+func OpenPostgres(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	switch cfg.Driver {
+	case "", config.StorageDriverREST:
+		return nil, nil
+	case config.StorageDriverPostgres:
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, expected %s or %s", cfg.Driver, config.StorageDriverREST, config.StorageDriverPostgres)
+	}
+
+	if cfg.URL == "" {
+		return nil, errors.New("DATABASE_URL is required with STORAGE_DRIVER=postgres")
+	}
+	pool, err := pgxpool.New(ctx, cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure the Postgres pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("postgres driver connected, max connections", "max_conns", pool.Config().MaxConns)
+	return pool, nil
+}
+
+// queryJSON runs a query whose single column is the JSON of a row and decodes every row into
+// T. Building the rows with to_jsonb keeps the same JSON mapping of the models as PostgREST.
+func queryJSON[T any](ctx context.Context, db *pgxpool.Pool, sql string, args ...any) ([]T, error) {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	raws, err := pgx.CollectRows(rows, pgx.RowTo[[]byte])
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &items[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse row: %w", err)
+		}
+	}
+	return items, nil
+}
+
+// callFunctionSQL calls a database function that writes several rows over the direct
+// connection, the counterpart of callTransaction. A NULL result leaves result untouched.
+func callFunctionSQL(ctx context.Context, db *pgxpool.Pool, sql string, result interface{}, args ...any) error {
+	var raw []byte
+	if err := db.QueryRow(ctx, sql, args...).Scan(&raw); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == versionConflictCode {
+			return ErrVersionConflict
+		}
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// uuidStrings formats IDs for a uuid[] parameter
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
 
 	"github.com/google/uuid"
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 	supa "github.com/supabase-community/supabase-go"
 )
@@ -30,7 +30,7 @@ func (r *PricingRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUI
 		Eq("rental_id", rentalID.String()).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching pricing by rental_id %s: %v", rentalID, err)
+		logging.FromContext(ctx).Error("error fetching pricing by rental_id", "rental_id", rentalID, "error", err)
 		return nil, err
 	}
 
@@ -40,7 +40,7 @@ func (r *PricingRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUI
 
 	err = json.Unmarshal([]byte(data), &results)
 	if err != nil {
-		log.Printf("Error parsing pricing data for rental_id %s: %v", rentalID, err)
+		logging.FromContext(ctx).Error("error parsing pricing data for rental_id", "rental_id", rentalID, "error", err)
 		return nil, err
 	}
 
@@ -51,6 +51,32 @@ func (r *PricingRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUI
 	return &results[0], nil
 }
 
+// GetByRentalIDs retrieves the pricing information of several rentals in a single query
+func (r *PricingRepository) GetByRentalIDs(ctx context.Context, rentalIDs []uuid.UUID) ([]model.Pricing, error) {
+	if len(rentalIDs) == 0 {
+		return []model.Pricing{}, nil
+	}
+
+	ids := make([]string, len(rentalIDs))
+	for i, id := range rentalIDs {
+		ids[i] = id.String()
+	}
+
+	data, _, err := r.client.From("pricing").Select("*", "exact", false).
+		In("rental_id", ids).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching pricing of rentals", "rental_ids_count", len(rentalIDs), "error", err)
+		return nil, err
+	}
+
+	var results []model.Pricing
+	if err := json.Unmarshal(data, &results); err != nil {
+		logging.FromContext(ctx).Error("error parsing pricing data of rentals", "error", err)
+		return nil, err
+	}
+	return results, nil
+}
+
 // Create adds new pricing information to the database
 func (r *PricingRepository) Create(ctx context.Context, pricing model.Pricing) (*model.Pricing, error) {
 	if pricing.ID == uuid.Nil {
@@ -60,24 +86,24 @@ func (r *PricingRepository) Create(ctx context.Context, pricing model.Pricing) (
 	var createdPricing []model.Pricing
 	data, count, err := r.client.From("pricing").Insert(pricing, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating pricing: %v", err)
+		logging.FromContext(ctx).Error("error creating pricing", "error", err)
 		return nil, err
 	}
 
 	if count == 0 {
 		// This might indicate an issue, or perhaps the insert didn't return the row by default
-		log.Printf("Warning: pricing insert returned count 0. Data: %s", string(data))
+		logging.FromContext(ctx).Warn("pricing insert returned count 0", "data", string(data))
 		// Attempt to parse even if count is 0, as Supabase might return data anyway
 	}
 
 	err = json.Unmarshal(data, &createdPricing)
 	if err != nil {
-		log.Printf("Error unmarshalling created pricing data: %v. Data: %s", err, string(data))
+		logging.FromContext(ctx).Error("error unmarshalling created pricing data", "error", err, "data", string(data))
 		return nil, err
 	}
 
 	if len(createdPricing) == 0 {
-		log.Printf("Error: no pricing data returned after insert. Data: %s", string(data))
+		logging.FromContext(ctx).Error("no pricing data returned after insert", "data", string(data))
 		return nil, errors.New("failed to create pricing: no data returned")
 	}
 
@@ -91,7 +117,7 @@ func (r *PricingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.P
 		Eq("id", id.String()).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching pricing by id %s: %v", id, err)
+		logging.FromContext(ctx).Error("error fetching pricing", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -101,7 +127,7 @@ func (r *PricingRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.P
 
 	err = json.Unmarshal(data, &results)
 	if err != nil {
-		log.Printf("Error parsing pricing data for id %s: %v", id, err)
+		logging.FromContext(ctx).Error("error parsing pricing data", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -117,48 +143,40 @@ func (r *PricingRepository) GetAll(ctx context.Context) ([]model.Pricing, error)
 	var pricingList []model.Pricing
 	data, count, err := r.client.From("pricing").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching all pricing: %v", err)
+		logging.FromContext(ctx).Error("error fetching all pricing", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d pricing records", count)
+	logging.FromContext(ctx).Debug("retrieved pricing records", "count", count)
 
 	err = json.Unmarshal(data, &pricingList)
 	if err != nil {
-		log.Printf("Error parsing all pricing data: %v", err)
+		logging.FromContext(ctx).Error("error parsing all pricing data", "error", err)
 		return nil, err
 	}
 	return pricingList, nil
 }
 
-// Update modifies existing pricing information in the database
+// Update modifies existing pricing information in the database. It returns ErrVersionConflict
+// when pricing.Version is set and the pricing was updated since, and nil when it does not exist.
 func (r *PricingRepository) Update(ctx context.Context, pricing model.Pricing) (*model.Pricing, error) {
 	var updatedPricing []model.Pricing
-	data, count, err := r.client.From("pricing").Update(pricing, "exact", "").
-		Eq("id", pricing.ID.String()).Execute()
+	data, _, err := filterVersion(r.client.From("pricing").Update(pricing, "representation", "").
+		Eq("id", pricing.ID.String()), pricing.Version).Execute()
 
 	if err != nil {
-		log.Printf("Error updating pricing ID %s: %v", pricing.ID, err)
+		logging.FromContext(ctx).Error("error updating pricing", "pricing_id", pricing.ID, "error", err)
 		return nil, err
 	}
 
-	if count == 0 {
-		log.Printf("Warning: pricing update for ID %s returned count 0. Data: %s", pricing.ID, string(data))
-		// Not found or no changes made. Supabase might return data if no changes.
-	}
-
 	err = json.Unmarshal(data, &updatedPricing)
 	if err != nil {
-		log.Printf("Error unmarshalling updated pricing data for ID %s: %v. Data: %s", pricing.ID, err, string(data))
+		logging.FromContext(ctx).Error("error unmarshalling updated pricing data", "pricing_id", pricing.ID, "error", err, "data", string(data))
 		return nil, err
 	}
 
 	if len(updatedPricing) == 0 {
-		// It's possible for an update to not return data if nothing changed or if the record doesn't exist.
-		// To be more robust, one might re-fetch by ID here or ensure `returning=representation` (if client supports it well for Update)
-		log.Printf("No pricing data returned after update for ID %s. Data: %s", pricing.ID, string(data))
-		// Check if the record exists as a separate step if needed or return nil if no data
-		return r.GetByID(ctx, pricing.ID) // Attempt to return the record state
+		return nil, versionMiss(r.client, "pricing", pricing.ID.String(), pricing.Version)
 	}
 
 	return &updatedPricing[0], nil
@@ -170,7 +188,7 @@ func (r *PricingRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		Eq("id", id.String()).Execute()
 
 	if err != nil {
-		log.Printf("Error deleting pricing ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error deleting pricing", "id", id, "error", err)
 		return err
 	}
 	return nil
@@ -183,7 +201,7 @@ func (r *PricingRepository) GetByPropertyID(ctx context.Context, propertyID uuid
 		Eq("property_id", propertyID.String()).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching pricing by property_id %s: %v", propertyID, err)
+		logging.FromContext(ctx).Error("error fetching pricing by property_id", "property_id", propertyID, "error", err)
 		return nil, err
 	}
 
@@ -193,7 +211,7 @@ func (r *PricingRepository) GetByPropertyID(ctx context.Context, propertyID uuid
 
 	err = json.Unmarshal([]byte(data), &results)
 	if err != nil {
-		log.Printf("Error parsing pricing data for property_id %s: %v", propertyID, err)
+		logging.FromContext(ctx).Error("error parsing pricing data for property_id", "property_id", propertyID, "error", err)
 		return nil, err
 	}
 
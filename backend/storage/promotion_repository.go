@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// PromotionRepository provides methods to interact with the promotion table in Supabase
+type PromotionRepository struct {
+	client *supa.Client
+}
+
+// NewPromotionRepository creates a new PromotionRepository
+func NewPromotionRepository(client *supa.Client) *PromotionRepository {
+	return &PromotionRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves all promotions, newest first
+func (r *PromotionRepository) GetAll(ctx context.Context) ([]model.Promotion, error) {
+	data, count, err := r.client.From("promotion").Select("*", "exact", false).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching promotions", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("retrieved promotions", "count", count)
+
+	var promotions []model.Promotion
+	err = json.Unmarshal([]byte(data), &promotions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing promotion data", "error", err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// GetByID retrieves a promotion by ID
+func (r *PromotionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Promotion, error) {
+	data, count, err := r.client.From("promotion").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching promotion", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var promotions []model.Promotion
+	err = json.Unmarshal([]byte(data), &promotions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing promotion data", "error", err)
+		return nil, err
+	}
+
+	if len(promotions) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &promotions[0], nil
+}
+
+// GetByPropertyID retrieves the promotions of a property
+func (r *PromotionRepository) GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.Promotion, error) {
+	data, _, err := r.client.From("promotion").Select("*", "exact", false).
+		Eq("property_id", propertyID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching promotions for property", "property_id", propertyID, "error", err)
+		return nil, err
+	}
+
+	var promotions []model.Promotion
+	err = json.Unmarshal([]byte(data), &promotions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing promotion data", "error", err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// Create adds a new promotion
+func (r *PromotionRepository) Create(ctx context.Context, promotion model.Promotion) (*model.Promotion, error) {
+	if promotion.ID == uuid.Nil {
+		promotion.ID = uuid.New()
+	}
+	if promotion.CreatedAt.IsZero() {
+		promotion.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("promotion").Insert(promotion, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating promotion", "error", err)
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	var createdPromotions []model.Promotion
+	err = json.Unmarshal(data, &createdPromotions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created promotion data", "error", err)
+		return nil, err
+	}
+
+	if len(createdPromotions) == 0 {
+		return nil, fmt.Errorf("failed to parse created promotion, empty result set")
+	}
+
+	return &createdPromotions[0], nil
+}
+
+// Update updates an existing promotion
+func (r *PromotionRepository) Update(ctx context.Context, promotion model.Promotion) (*model.Promotion, error) {
+	promotionData := map[string]interface{}{
+		"property_id":         promotion.PropertyID,
+		"name":                promotion.Name,
+		"type":                promotion.Type,
+		"discount_percentage": promotion.DiscountPercentage,
+		"valid_from":          promotion.ValidFrom,
+		"valid_until":         promotion.ValidUntil,
+		"active":              promotion.Active,
+	}
+
+	data, count, err := r.client.From("promotion").Update(promotionData, "exact", "").
+		Eq("id", promotion.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating promotion", "promotion_id", promotion.ID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(ctx, promotion.ID)
+	}
+
+	var updatedPromotions []model.Promotion
+	err = json.Unmarshal(data, &updatedPromotions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated promotion data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedPromotions) == 0 {
+		return r.GetByID(ctx, promotion.ID)
+	}
+
+	return &updatedPromotions[0], nil
+}
+
+// Delete removes a promotion
+func (r *PromotionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("promotion").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting promotion", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateRedemption records a contract offered with a promotion
+func (r *PromotionRepository) CreateRedemption(ctx context.Context, redemption model.PromotionRedemption) (*model.PromotionRedemption, error) {
+	if redemption.ID == uuid.Nil {
+		redemption.ID = uuid.New()
+	}
+	if redemption.OfferedAt.IsZero() {
+		redemption.OfferedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("promotion_redemption").Insert(redemption, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating promotion redemption", "error", err)
+		return nil, fmt.Errorf("failed to create promotion redemption: %w", err)
+	}
+
+	var createdRedemptions []model.PromotionRedemption
+	err = json.Unmarshal(data, &createdRedemptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created promotion redemption data", "error", err)
+		return nil, err
+	}
+
+	if len(createdRedemptions) == 0 {
+		return nil, fmt.Errorf("failed to parse created promotion redemption, empty result set")
+	}
+
+	return &createdRedemptions[0], nil
+}
+
+// GetRedemptions retrieves all promotion redemptions
+func (r *PromotionRepository) GetRedemptions(ctx context.Context) ([]model.PromotionRedemption, error) {
+	data, _, err := r.client.From("promotion_redemption").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching promotion redemptions", "error", err)
+		return nil, err
+	}
+
+	var redemptions []model.PromotionRedemption
+	err = json.Unmarshal([]byte(data), &redemptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing promotion redemption data", "error", err)
+		return nil, err
+	}
+
+	return redemptions, nil
+}
+
+// MarkConverted records that the contract offered with promotions was signed. Redemptions
+// already converted keep their first conversion time.
+func (r *PromotionRepository) MarkConverted(ctx context.Context, contractID string, convertedAt time.Time) error {
+	_, _, err := r.client.From("promotion_redemption").Update(map[string]interface{}{
+		"converted_at": convertedAt,
+	}, "", "").Eq("contract_id", contractID).Is("converted_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking promotion redemptions of contract as converted", "contract_id", contractID, "error", err)
+		return err
+	}
+
+	return nil
+}
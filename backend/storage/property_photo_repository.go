@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// PropertyPhotoRepository provides methods to interact with the property_photo table in Supabase
+type PropertyPhotoRepository struct {
+	client *supa.Client
+}
+
+// NewPropertyPhotoRepository creates a new PropertyPhotoRepository
+func NewPropertyPhotoRepository(client *supa.Client) *PropertyPhotoRepository {
+	return &PropertyPhotoRepository{
+		client: client,
+	}
+}
+
+// GetByPropertyID retrieves the gallery of a property in order
+func (r *PropertyPhotoRepository) GetByPropertyID(ctx context.Context, propertyID uuid.UUID) ([]model.PropertyPhoto, error) {
+	return r.GetByPropertyIDs(ctx, []uuid.UUID{propertyID})
+}
+
+// GetByPropertyIDs retrieves the galleries of several properties, each one in order
+func (r *PropertyPhotoRepository) GetByPropertyIDs(ctx context.Context, propertyIDs []uuid.UUID) ([]model.PropertyPhoto, error) {
+	if len(propertyIDs) == 0 {
+		return []model.PropertyPhoto{}, nil
+	}
+
+	ids := make([]string, len(propertyIDs))
+	for i, id := range propertyIDs {
+		ids[i] = id.String()
+	}
+
+	data, _, err := r.client.From("property_photo").Select("*", "exact", false).
+		In("property_id", ids).
+		Order("position", &postgrest.OrderOpts{Ascending: true}).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching photos properties", "property_ids_count", len(propertyIDs), "error", err)
+		return nil, err
+	}
+
+	var photos []model.PropertyPhoto
+	if err := json.Unmarshal(data, &photos); err != nil {
+		logging.FromContext(ctx).Error("error parsing property photo data", "error", err)
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// GetByID retrieves a property photo, nil when it does not exist
+func (r *PropertyPhotoRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PropertyPhoto, error) {
+	data, _, err := r.client.From("property_photo").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching property photo", "id", id, "error", err)
+		return nil, err
+	}
+
+	var photos []model.PropertyPhoto
+	if err := json.Unmarshal(data, &photos); err != nil {
+		logging.FromContext(ctx).Error("error parsing property photo data", "error", err)
+		return nil, err
+	}
+
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	return &photos[0], nil
+}
+
+// Create adds a photo to the gallery of a property
+func (r *PropertyPhotoRepository) Create(ctx context.Context, photo model.PropertyPhoto) (*model.PropertyPhoto, error) {
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	photo.URL = ""
+	photo.CreatedAt = time.Now()
+
+	data, _, err := r.client.From("property_photo").Insert(photo, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating photo of property", "property_id", photo.PropertyID, "error", err)
+		return nil, fmt.Errorf("failed to create property photo: %w", err)
+	}
+
+	var created []model.PropertyPhoto
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created property photo data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created property photo, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateCaption changes the caption of a property photo
+func (r *PropertyPhotoRepository) UpdateCaption(ctx context.Context, id uuid.UUID, caption string) error {
+	_, _, err := r.client.From("property_photo").Update(map[string]interface{}{"caption": caption}, "minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating caption of property photo", "id", id, "error", err)
+		return fmt.Errorf("failed to update property photo: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePosition moves a property photo to a position of its gallery
+func (r *PropertyPhotoRepository) UpdatePosition(ctx context.Context, id uuid.UUID, position int) error {
+	_, _, err := r.client.From("property_photo").Update(map[string]interface{}{"position": position}, "minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating position of property photo", "id", id, "error", err)
+		return fmt.Errorf("failed to update property photo: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a photo from the gallery of its property
+func (r *PropertyPhotoRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("property_photo").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting property photo", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
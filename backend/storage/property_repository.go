@@ -3,27 +3,129 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/supabase-community/postgrest-go"
 	supa "github.com/supabase-community/supabase-go"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
+// PropertyFilter narrows a list of properties by their characteristics. Zero values and nil
+// flags do not filter.
+type PropertyFilter struct {
+	City         string
+	MinBedrooms  int
+	MinBathrooms int
+	MinAreaM2    float64
+	MaxAreaM2    float64
+	Estrato      int
+	Parking      *bool // With or without parking spots
+	Furnished    *bool
+	PetsAllowed  *bool
+}
+
+// Matches reports whether a property passes the filter
+func (f PropertyFilter) Matches(property model.Property) bool {
+	if f.City != "" && !strings.EqualFold(strings.TrimSpace(property.City), f.City) {
+		return false
+	}
+	if property.Bedrooms < f.MinBedrooms || property.Bathrooms < f.MinBathrooms {
+		return false
+	}
+	if property.AreaM2 < f.MinAreaM2 || (f.MaxAreaM2 > 0 && property.AreaM2 > f.MaxAreaM2) {
+		return false
+	}
+	if f.Estrato != 0 && property.Estrato != f.Estrato {
+		return false
+	}
+	if f.Parking != nil && (property.ParkingSpots > 0) != *f.Parking {
+		return false
+	}
+	if f.Furnished != nil && property.Furnished != *f.Furnished {
+		return false
+	}
+	if f.PetsAllowed != nil && property.PetsAllowed != *f.PetsAllowed {
+		return false
+	}
+	return true
+}
+
+// Filter returns the properties that pass the filter
+func (f PropertyFilter) Filter(properties []model.Property) []model.Property {
+	filtered := make([]model.Property, 0, len(properties))
+	for _, property := range properties {
+		if f.Matches(property) {
+			filtered = append(filtered, property)
+		}
+	}
+	return filtered
+}
+
+// apply adds the filter to a property query
+func (f PropertyFilter) apply(query *postgrest.FilterBuilder) *postgrest.FilterBuilder {
+	if f.City != "" {
+		query = query.Ilike("city", f.City)
+	}
+	if f.MinBedrooms > 0 {
+		query = query.Gte("bedrooms", strconv.Itoa(f.MinBedrooms))
+	}
+	if f.MinBathrooms > 0 {
+		query = query.Gte("bathrooms", strconv.Itoa(f.MinBathrooms))
+	}
+	if f.MinAreaM2 > 0 {
+		query = query.Gte("area_m2", strconv.FormatFloat(f.MinAreaM2, 'f', -1, 64))
+	}
+	if f.MaxAreaM2 > 0 {
+		query = query.Lte("area_m2", strconv.FormatFloat(f.MaxAreaM2, 'f', -1, 64))
+	}
+	if f.Estrato != 0 {
+		query = query.Eq("estrato", strconv.Itoa(f.Estrato))
+	}
+	if f.Parking != nil {
+		if *f.Parking {
+			query = query.Gt("parking_spots", "0")
+		} else {
+			query = query.Eq("parking_spots", "0")
+		}
+	}
+	if f.Furnished != nil {
+		query = query.Eq("furnished", strconv.FormatBool(*f.Furnished))
+	}
+	if f.PetsAllowed != nil {
+		query = query.Eq("pets_allowed", strconv.FormatBool(*f.PetsAllowed))
+	}
+	return query
+}
+
 // PropertyRepository provides methods to interact with the Property table in Supabase
 type PropertyRepository struct {
 	client *supa.Client
+	db     *pgxpool.Pool                        // Direct connection of STORAGE_DRIVER=postgres, nil with the REST driver
+	cache  *ttlCache[uuid.UUID, model.Property] // Properties read by ID with their managers, see defaultReferenceCacheTTL
 }
 
 // NewPropertyRepository creates a new PropertyRepository
 func NewPropertyRepository(client *supa.Client) *PropertyRepository {
 	return &PropertyRepository{
 		client: client,
+		cache:  newTTLCache[uuid.UUID, model.Property](defaultReferenceCacheTTL),
 	}
 }
 
+// InvalidateCache drops the given properties from the cache, or every property when none is
+// given, e.g. after writing the property table from another repository
+func (r *PropertyRepository) InvalidateCache(ids ...uuid.UUID) {
+	r.cache.Invalidate(ids...)
+}
+
 // GetManagerIDsForProperty retrieves all manager person IDs for a given property ID.
 func (r *PropertyRepository) GetManagerIDsForProperty(ctx context.Context, propertyID uuid.UUID) ([]uuid.UUID, error) {
 	var results []struct {
@@ -36,7 +138,7 @@ func (r *PropertyRepository) GetManagerIDsForProperty(ctx context.Context, prope
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching manager IDs for property %s: %v", propertyID, err)
+		logging.FromContext(ctx).Error("error fetching manager IDs for property", "property_id", propertyID, "error", err)
 		return nil, fmt.Errorf("failed to fetch manager IDs: %w", err)
 	}
 
@@ -44,7 +146,7 @@ func (r *PropertyRepository) GetManagerIDsForProperty(ctx context.Context, prope
 		// It's possible that 'data' is empty if no managers are linked, which is not an unmarshal error.
 		// Check if data is effectively empty or just '[]'
 		if string(data) != "" && string(data) != "[]" {
-			log.Printf("Error unmarshaling manager IDs for property %s: %v. Data: %s", propertyID, err, string(data))
+			logging.FromContext(ctx).Error("error unmarshaling manager IDs for property", "property_id", propertyID, "error", err, "data", string(data))
 			return nil, fmt.Errorf("failed to parse manager IDs: %w", err)
 		}
 		// If data is empty or [], it means no managers, so return empty slice
@@ -58,44 +160,121 @@ func (r *PropertyRepository) GetManagerIDsForProperty(ctx context.Context, prope
 	return managerIDs, nil
 }
 
+// GetManagerIDsForProperties retrieves the manager person IDs of several properties in a single
+// query, keyed by property ID. Properties without managers are not in the map.
+func (r *PropertyRepository) GetManagerIDsForProperties(ctx context.Context, propertyIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	managerIDs := make(map[uuid.UUID][]uuid.UUID)
+	if len(propertyIDs) == 0 {
+		return managerIDs, nil
+	}
+
+	ids := make([]string, len(propertyIDs))
+	for i, id := range propertyIDs {
+		ids[i] = id.String()
+	}
+
+	var results []struct {
+		PropertyID      uuid.UUID `json:"property_id"`
+		ManagerPersonID uuid.UUID `json:"manager_person_id"`
+	}
+	data, _, err := r.client.From("property_managers").
+		Select("property_id,manager_person_id", "exact", false).
+		In("property_id", ids).
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching manager IDs properties", "property_ids_count", len(propertyIDs), "error", err)
+		return nil, fmt.Errorf("failed to fetch manager IDs: %w", err)
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		logging.FromContext(ctx).Error("error unmarshaling manager IDs", "error", err, "data", string(data))
+		return nil, fmt.Errorf("failed to parse manager IDs: %w", err)
+	}
+
+	for _, res := range results {
+		managerIDs[res.PropertyID] = append(managerIDs[res.PropertyID], res.ManagerPersonID)
+	}
+	return managerIDs, nil
+}
+
+// populateManagerIDs sets the ManagerIDs of the properties with a single query. On error the
+// properties keep nil ManagerIDs, as when they were fetched one by one.
+func (r *PropertyRepository) populateManagerIDs(ctx context.Context, properties []model.Property) {
+	if len(properties) == 0 {
+		return
+	}
+	managerIDs, err := r.GetManagerIDsForProperties(ctx, model.PropertyIDs(properties))
+	if err != nil {
+		return // Already logged
+	}
+	for i := range properties {
+		properties[i].ManagerIDs = managerIDs[properties[i].ID]
+		if properties[i].ManagerIDs == nil {
+			properties[i].ManagerIDs = []uuid.UUID{}
+		}
+	}
+}
+
 // GetAll retrieves all properties from the database
 func (r *PropertyRepository) GetAll(ctx context.Context) ([]model.Property, error) {
+	if r.db != nil {
+		return r.queryProperties(ctx, "")
+	}
+
 	var properties []model.Property
 
 	data, count, err := r.client.From("property").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching properties: %v", err)
+		logging.FromContext(ctx).Error("error fetching properties", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d properties", count)
+	logging.FromContext(ctx).Debug("retrieved properties", "count", count)
 
 	err = json.Unmarshal([]byte(data), &properties)
 	if err != nil {
-		log.Printf("Error parsing property data: %v", err)
+		logging.FromContext(ctx).Error("error parsing property data", "error", err)
 		return nil, err
 	}
 
-	// Populate ManagerIDs for each property
-	for i := range properties {
-		p := &properties[i]
-		managerIDs, managerErr := r.GetManagerIDsForProperty(ctx, p.ID)
-		if managerErr != nil {
-			log.Printf("Error fetching manager IDs for property %s during GetAll: %v", p.ID, managerErr)
-			// Continue, property will have nil ManagerIDs
-		}
-		p.ManagerIDs = managerIDs
-	}
+	r.populateManagerIDs(ctx, properties)
 
 	return properties, nil
 }
 
+// List retrieves a page of properties passing the filter with the total count of matching properties
+func (r *PropertyRepository) List(ctx context.Context, filter PropertyFilter, opts ListOptions) ([]model.Property, int, error) {
+	query := filter.apply(r.client.From("property").Select("*", "exact", false))
+	data, count, err := opts.apply(query, "address").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing properties", "error", err)
+		return nil, 0, err
+	}
+
+	var properties []model.Property
+	if err := json.Unmarshal(data, &properties); err != nil {
+		logging.FromContext(ctx).Error("error parsing property data", "error", err)
+		return nil, 0, err
+	}
+
+	r.populateManagerIDs(ctx, properties)
+
+	return properties, int(count), nil
+}
+
 // GetByID retrieves a property by ID and populates its ManagerIDs.
 func (r *PropertyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Property, error) {
+	if property, ok := r.cache.Get(id); ok {
+		property.ManagerIDs = slices.Clone(property.ManagerIDs) // The caller may modify the copy
+		return &property, nil
+	}
+	if r.db != nil {
+		return r.getByIDSQL(ctx, id)
+	}
+
 	data, dbCount, err := r.client.From("property").Select("*", "exact", false).
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching property by ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error fetching property", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -105,7 +284,7 @@ func (r *PropertyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.
 
 	var properties []model.Property // Fetch as a slice first
 	if err := json.Unmarshal(data, &properties); err != nil {
-		log.Printf("Error parsing property data for ID %s: %v", id, err)
+		logging.FromContext(ctx).Error("error parsing property data", "id", id, "error", err)
 		return nil, err
 	}
 
@@ -118,21 +297,86 @@ func (r *PropertyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.
 	// Populate ManagerIDs
 	managerIDs, managerErr := r.GetManagerIDsForProperty(ctx, property.ID)
 	if managerErr != nil {
-		log.Printf("Error fetching manager IDs for property %s during GetByID: %v", property.ID, managerErr)
+		logging.FromContext(ctx).Error("error fetching manager IDs for property during GetByID", "property_id", property.ID, "error", managerErr)
 		// Continue, property will have nil ManagerIDs if this fails
 	}
 	property.ManagerIDs = managerIDs
+	if managerErr == nil {
+		cached := *property
+		cached.ManagerIDs = slices.Clone(managerIDs)
+		r.cache.Set(id, cached)
+	}
 
 	return property, nil
 }
 
+// GetByIDs retrieves several properties by their IDs in a single query, with their ManagerIDs
+func (r *PropertyRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Property, error) {
+	if len(ids) == 0 {
+		return []model.Property{}, nil
+	}
+
+	stringIDs := make([]string, len(ids))
+	for i, id := range ids {
+		stringIDs[i] = id.String()
+	}
+	if r.db != nil {
+		return r.queryProperties(ctx, "WHERE p.id = ANY($1::uuid[])", stringIDs)
+	}
+
+	data, _, err := r.client.From("property").Select("*", "exact", false).
+		In("id", stringIDs).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching properties by IDs", "ids_count", len(ids), "error", err)
+		return nil, err
+	}
+
+	var properties []model.Property
+	if err := json.Unmarshal(data, &properties); err != nil {
+		logging.FromContext(ctx).Error("error parsing property data", "error", err)
+		return nil, err
+	}
+
+	r.populateManagerIDs(ctx, properties)
+
+	return properties, nil
+}
+
+// GetByBuildingID retrieves the properties of a building
+func (r *PropertyRepository) GetByBuildingID(ctx context.Context, buildingID uuid.UUID) ([]model.Property, error) {
+	if r.db != nil {
+		return r.queryProperties(ctx, "WHERE p.building_id = $1", buildingID.String())
+	}
+
+	data, _, err := r.client.From("property").Select("*", "exact", false).
+		Eq("building_id", buildingID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching properties of building", "building_id", buildingID, "error", err)
+		return nil, err
+	}
+
+	var properties []model.Property
+	if err := json.Unmarshal(data, &properties); err != nil {
+		logging.FromContext(ctx).Error("error parsing property data for building", "building_id", buildingID, "error", err)
+		return nil, err
+	}
+
+	r.populateManagerIDs(ctx, properties)
+
+	return properties, nil
+}
+
 // GetByResident retrieves properties by resident ID
 func (r *PropertyRepository) GetByResident(ctx context.Context, residentID uuid.UUID) ([]model.Property, error) {
+	if r.db != nil {
+		return r.queryProperties(ctx, "WHERE p.resident_id = $1", residentID.String())
+	}
+
 	var properties []model.Property
 	data, count, err := r.client.From("property").Select("*", "exact", false).
 		Eq("resident_id", residentID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching properties by resident ID %s: %v", residentID, err)
+		logging.FromContext(ctx).Error("error fetching properties by resident", "resident_id", residentID, "error", err)
 		return nil, err
 	}
 
@@ -142,25 +386,22 @@ func (r *PropertyRepository) GetByResident(ctx context.Context, residentID uuid.
 
 	err = json.Unmarshal([]byte(data), &properties)
 	if err != nil {
-		log.Printf("Error parsing property data for resident ID %s: %v", residentID, err)
+		logging.FromContext(ctx).Error("error parsing property data for resident", "resident_id", residentID, "error", err)
 		return nil, err
 	}
 
-	// Populate ManagerIDs for each property
-	for i := range properties {
-		p := &properties[i]
-		managerIDs, managerErr := r.GetManagerIDsForProperty(ctx, p.ID)
-		if managerErr != nil {
-			log.Printf("Error fetching manager IDs for property %s during GetByResident: %v", p.ID, managerErr)
-		}
-		p.ManagerIDs = managerIDs
-	}
+	r.populateManagerIDs(ctx, properties)
 
 	return properties, nil
 }
 
 // GetPropertiesForManager retrieves properties associated with the given manager_person_id.
 func (r *PropertyRepository) GetPropertiesForManager(ctx context.Context, managerPersonID uuid.UUID) ([]model.Property, error) {
+	if r.db != nil {
+		return r.queryProperties(ctx, `WHERE EXISTS (SELECT 1 FROM property_managers pm
+			WHERE pm.property_id = p.id AND pm.manager_person_id = $1)`, managerPersonID.String())
+	}
+
 	var propertyManagerLinks []struct {
 		PropertyID uuid.UUID `json:"property_id"`
 	}
@@ -171,13 +412,13 @@ func (r *PropertyRepository) GetPropertiesForManager(ctx context.Context, manage
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching property links for manager %s: %v", managerPersonID, err)
+		logging.FromContext(ctx).Error("error fetching property links for manager", "manager_person_id", managerPersonID, "error", err)
 		return nil, fmt.Errorf("failed to fetch property links for manager: %w", err)
 	}
 
 	if err := json.Unmarshal(pmData, &propertyManagerLinks); err != nil {
 		if string(pmData) != "" && string(pmData) != "[]" {
-			log.Printf("Error unmarshaling property links for manager %s: %v. Data: %s", managerPersonID, err, string(pmData))
+			logging.FromContext(ctx).Error("error unmarshaling property links for manager", "manager_person_id", managerPersonID, "error", err, "pm_data", string(pmData))
 			return nil, fmt.Errorf("failed to parse property links for manager: %w", err)
 		}
 		return []model.Property{}, nil // No links found
@@ -199,29 +440,23 @@ func (r *PropertyRepository) GetPropertiesForManager(ctx context.Context, manage
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching properties by IDs for manager %s: %v", managerPersonID, err)
+		logging.FromContext(ctx).Error("error fetching properties by IDs for manager", "manager_person_id", managerPersonID, "error", err)
 		return nil, fmt.Errorf("failed to fetch properties for manager: %w", err)
 	}
 
 	if err := json.Unmarshal(propData, &properties); err != nil {
-		log.Printf("Error unmarshaling properties for manager %s: %v", managerPersonID, err)
+		logging.FromContext(ctx).Error("error unmarshaling properties for manager", "manager_person_id", managerPersonID, "error", err)
 		return nil, fmt.Errorf("failed to parse properties for manager: %w", err)
 	}
 
-	for i := range properties {
-		p := &properties[i]
-		managerIDs, managerErr := r.GetManagerIDsForProperty(ctx, p.ID)
-		if managerErr != nil {
-			log.Printf("Error fetching manager IDs for property %s during GetPropertiesForManager: %v", p.ID, managerErr)
-		}
-		p.ManagerIDs = managerIDs
-	}
+	r.populateManagerIDs(ctx, properties)
 
 	return properties, nil
 }
 
 // AddManagerToProperty creates a link between a property and a manager.
 func (r *PropertyRepository) AddManagerToProperty(ctx context.Context, propertyID uuid.UUID, managerPersonID uuid.UUID) error {
+	r.cache.Invalidate(propertyID)
 	_, _, err := r.client.From("property_managers").
 		Insert(map[string]string{
 			"property_id":       propertyID.String(),
@@ -229,7 +464,7 @@ func (r *PropertyRepository) AddManagerToProperty(ctx context.Context, propertyI
 		}, false, "exact", "", "ignore"). // "ignore" on conflict if the link already exists
 		Execute()
 	if err != nil {
-		log.Printf("Error adding manager %s to property %s: %v", managerPersonID, propertyID, err)
+		logging.FromContext(ctx).Error("error adding manager to property", "manager_person_id", managerPersonID, "property_id", propertyID, "error", err)
 		return fmt.Errorf("failed to add manager to property: %w", err)
 	}
 	return nil
@@ -237,141 +472,137 @@ func (r *PropertyRepository) AddManagerToProperty(ctx context.Context, propertyI
 
 // RemoveManagerFromProperty removes a link between a property and a manager.
 func (r *PropertyRepository) RemoveManagerFromProperty(ctx context.Context, propertyID uuid.UUID, managerPersonID uuid.UUID) error {
+	r.cache.Invalidate(propertyID)
 	_, _, err := r.client.From("property_managers").
 		Delete("exact", ""). // Use "exact" to ensure we get a count if needed, or "minimal"
 		Eq("property_id", propertyID.String()).
 		Eq("manager_person_id", managerPersonID.String()).
 		Execute()
 	if err != nil {
-		log.Printf("Error removing manager %s from property %s: %v", managerPersonID, propertyID, err)
+		logging.FromContext(ctx).Error("error removing manager from property", "manager_person_id", managerPersonID, "property_id", propertyID, "error", err)
 		return fmt.Errorf("failed to remove manager from property: %w", err)
 	}
 	return nil
 }
 
-// Create adds a new property to the database and links its managers.
-func (r *PropertyRepository) Create(ctx context.Context, property model.Property) (*model.Property, error) {
-	// Create a map for the property data, excluding ManagerIDs as it's not a direct column
-	propertyData := map[string]interface{}{
-		"id":          property.ID,
-		"address":     property.Address,
-		"apt_number":  property.AptNumber,
-		"city":        property.City,
-		"state":       property.State,
-		"zip_code":    property.ZipCode,
-		"type":        property.Type,
-		"resident_id": property.ResidentID,
-		// ManagerID is no longer here
-	}
-
-	data, _, err := r.client.From("property").Insert(propertyData, false, "exact", "", "").Execute()
-	if err != nil {
-		log.Printf("Error creating property: %v", err)
-		return nil, err
+// propertyColumns are the columns of a property written by Create and Update. ManagerIDs is not
+// a column, the links live in property_managers.
+func propertyColumns(property model.Property) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              property.ID,
+		"address":         property.Address,
+		"apt_number":      property.AptNumber,
+		"city":            property.City,
+		"state":           property.State,
+		"zip_code":        property.ZipCode,
+		"type":            property.Type,
+		"resident_id":     property.ResidentID,
+		"building_id":     property.BuildingID,
+		"bedrooms":        property.Bedrooms,
+		"bathrooms":       property.Bathrooms,
+		"area_m2":         property.AreaM2,
+		"estrato":         property.Estrato,
+		"parking_spots":   property.ParkingSpots,
+		"parking_details": property.ParkingDetails,
+		"furnished":       property.Furnished,
+		"pets_allowed":    property.PetsAllowed,
+		"boundaries":      property.Boundaries,
 	}
+}
 
-	var createdProperties []model.Property
-	err = json.Unmarshal(data, &createdProperties)
+// Create adds a new property to the database and links its managers. Both run in the
+// create_property function, so a failure leaves neither the property nor any link behind.
+func (r *PropertyRepository) Create(ctx context.Context, property model.Property) (*model.Property, error) {
+	managers := property.ManagerIDs
+	if managers == nil {
+		managers = []uuid.UUID{}
+	}
+
+	var createdProperty *model.Property
+	var err error
+	if r.db != nil {
+		err = callFunctionSQL(ctx, r.db, "SELECT create_property($1::jsonb, $2::uuid[])", &createdProperty,
+			propertyColumns(property), uuidStrings(managers))
+	} else {
+		err = callTransaction(r.client, "create_property", map[string]interface{}{
+			"data":     propertyColumns(property),
+			"managers": managers,
+		}, &createdProperty)
+	}
 	if err != nil {
-		log.Printf("Error parsing created property data: %v", err)
+		logging.FromContext(ctx).Error("error creating property", "error", err)
 		return nil, err
 	}
-
-	if len(createdProperties) == 0 {
+	if createdProperty == nil {
 		return nil, fmt.Errorf("failed to parse created property, empty result set")
 	}
 
-	createdProperty := &createdProperties[0]
-
-	// Link managers
-	if len(property.ManagerIDs) > 0 {
-		for _, managerID := range property.ManagerIDs {
-			if err := r.AddManagerToProperty(ctx, createdProperty.ID, managerID); err != nil {
-				// Log error and continue, or decide if this should be a fatal error for the create operation
-				log.Printf("Error linking manager %s to new property %s: %v", managerID, createdProperty.ID, err)
-			}
-		}
-	}
-	// Repopulate ManagerIDs from the database to ensure consistency
-	currentManagerIDs, managerErr := r.GetManagerIDsForProperty(ctx, createdProperty.ID)
-	if managerErr != nil {
-		log.Printf("Error fetching manager IDs for newly created property %s: %v", createdProperty.ID, managerErr)
-	}
-	createdProperty.ManagerIDs = currentManagerIDs
-
 	return createdProperty, nil
 }
 
-// Update updates an existing property and its manager links.
+// Update updates an existing property and replaces its manager links in the update_property
+// function, so a failure leaves the property and its links as they were. It returns
+// ErrVersionConflict when property.Version is set and the property was updated since, and nil
+// when it does not exist.
 func (r *PropertyRepository) Update(ctx context.Context, property model.Property) (*model.Property, error) {
-	// Update scalar fields of the property
-	propertyData := map[string]interface{}{
-		"address":     property.Address,
-		"apt_number":  property.AptNumber,
-		"city":        property.City,
-		"state":       property.State,
-		"zip_code":    property.ZipCode,
-		"type":        property.Type,
-		"resident_id": property.ResidentID,
-	}
-
-	_, _, err := r.client.From("property").Update(propertyData, "exact", "").
-		Eq("id", property.ID.String()).Execute()
-	if err != nil {
-		log.Printf("Error updating property %s: %v", property.ID, err)
-		return nil, fmt.Errorf("failed to update property: %w", err)
+	r.cache.Invalidate(property.ID)
+	managers := property.ManagerIDs
+	if managers == nil {
+		managers = []uuid.UUID{}
+	}
+
+	var updatedProperty *model.Property
+	var err error
+	if r.db != nil {
+		var expectedVersion *int
+		if property.Version > 0 {
+			expectedVersion = &property.Version
+		}
+		err = callFunctionSQL(ctx, r.db, "SELECT update_property($1::jsonb, $2::uuid[], $3)", &updatedProperty,
+			propertyColumns(property), uuidStrings(managers), expectedVersion)
+	} else {
+		params := map[string]interface{}{
+			"data":     propertyColumns(property),
+			"managers": managers,
+		}
+		if property.Version > 0 {
+			params["expected_version"] = property.Version
+		}
+		err = callTransaction(r.client, "update_property", params, &updatedProperty)
 	}
-
-	// Manage manager links
-	currentManagerIDs, err := r.GetManagerIDsForProperty(ctx, property.ID)
 	if err != nil {
-		log.Printf("Error fetching current managers for property %s during update: %v", property.ID, err)
-		return nil, fmt.Errorf("failed to fetch current managers: %w", err)
-	}
-
-	requestedManagerIDsMap := make(map[uuid.UUID]bool)
-	for _, id := range property.ManagerIDs {
-		requestedManagerIDsMap[id] = true
-	}
-
-	currentManagerIDsMap := make(map[uuid.UUID]bool)
-	for _, id := range currentManagerIDs {
-		currentManagerIDsMap[id] = true
-	}
-
-	// Add new managers
-	for managerID := range requestedManagerIDsMap {
-		if !currentManagerIDsMap[managerID] {
-			if err := r.AddManagerToProperty(ctx, property.ID, managerID); err != nil {
-				log.Printf("Error adding manager %s to property %s during update: %v", managerID, property.ID, err)
-				// Potentially collect errors and return them, or handle as critical
-			}
+		if !errors.Is(err, ErrVersionConflict) {
+			logging.FromContext(ctx).Error("error updating property", "property_id", property.ID, "error", err)
 		}
+		return nil, err
 	}
 
-	// Remove old managers
-	for managerID := range currentManagerIDsMap {
-		if !requestedManagerIDsMap[managerID] {
-			if err := r.RemoveManagerFromProperty(ctx, property.ID, managerID); err != nil {
-				log.Printf("Error removing manager %s from property %s during update: %v", managerID, property.ID, err)
-				// Potentially collect errors
-			}
-		}
+	r.cache.Invalidate(property.ID)
+	return updatedProperty, nil
+}
+
+// SetBuilding assigns a property to a building, or detaches it when buildingID is nil
+func (r *PropertyRepository) SetBuilding(ctx context.Context, propertyID uuid.UUID, buildingID *uuid.UUID) error {
+	r.cache.Invalidate(propertyID)
+	_, _, err := r.client.From("property").Update(map[string]interface{}{"building_id": buildingID}, "minimal", "").
+		Eq("id", propertyID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error setting the building of property", "property_id", propertyID, "error", err)
+		return fmt.Errorf("failed to update property: %w", err)
 	}
 
-	// Fetch the updated property to return it with all fields populated correctly
-	// (including any changes to manager IDs from the operations above)
-	return r.GetByID(ctx, property.ID)
+	return nil
 }
 
 // Delete removes a property from the database
 func (r *PropertyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.cache.Invalidate(id)
 	// The property_managers table should have ON DELETE CASCADE for property_id
 	// so manager links will be removed automatically when the property is deleted.
 	_, _, err := r.client.From("property").Delete("minimal", "").
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error deleting property %s: %v", id, err)
+		logging.FromContext(ctx).Error("error deleting property", "id", id, "error", err)
 		return fmt.Errorf("failed to delete property: %w", err)
 	}
 
@@ -380,10 +611,14 @@ func (r *PropertyRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 // GetByUserID retrieves properties that a user is renting
 func (r *PropertyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.Property, error) {
+	if r.db != nil {
+		return r.queryProperties(ctx, "WHERE p.id IN (SELECT property_id FROM rental WHERE renter_id = $1)", userID.String())
+	}
+
 	rentalsData, count, err := r.client.From("rental").Select("property_id", "exact", false).
 		Eq("renter_id", userID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching rentals for user %s: %v", userID.String(), err)
+		logging.FromContext(ctx).Error("error fetching rentals for user", "user_id", userID.String(), "error", err)
 		return nil, err
 	}
 
@@ -396,7 +631,7 @@ func (r *PropertyRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 	}
 	err = json.Unmarshal([]byte(rentalsData), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data for user %s: %v", userID.String(), err)
+		logging.FromContext(ctx).Error("error parsing rental data for user", "user_id", userID.String(), "error", err)
 		return nil, err
 	}
 
@@ -416,25 +651,45 @@ func (r *PropertyRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 		In("id", propertyIDs).Execute()
 
 	if err != nil {
-		log.Printf("Error fetching properties by IDs for user %s: %v", userID.String(), err)
+		logging.FromContext(ctx).Error("error fetching properties by IDs for user", "user_id", userID.String(), "error", err)
 		return nil, err
 	}
 
 	err = json.Unmarshal([]byte(propertiesData), &allProperties)
 	if err != nil {
-		log.Printf("Error parsing properties for user %s: %v", userID.String(), err)
+		logging.FromContext(ctx).Error("error parsing properties for user", "user_id", userID.String(), "error", err)
 		return nil, err
 	}
 
-	// Populate ManagerIDs for each property
-	for i := range allProperties {
-		p := &allProperties[i]
-		managerIDs, managerErr := r.GetManagerIDsForProperty(ctx, p.ID)
-		if managerErr != nil {
-			log.Printf("Error fetching manager IDs for property %s during GetByUserID: %v", p.ID, managerErr)
-		}
-		p.ManagerIDs = managerIDs
-	}
+	r.populateManagerIDs(ctx, allProperties)
 
 	return allProperties, nil
 }
+
+// propertySelectSQL reads the properties with their manager IDs in one query, the same JSON
+// the create_property and update_property functions return
+const propertySelectSQL = "SELECT property_with_managers(p) FROM property p "
+
+// queryProperties runs propertySelectSQL with a WHERE clause over the direct connection
+func (r *PropertyRepository) queryProperties(ctx context.Context, where string, args ...any) ([]model.Property, error) {
+	properties, err := queryJSON[model.Property](ctx, r.db, propertySelectSQL+where, args...)
+	if err != nil {
+		logging.FromContext(ctx).Error("error querying properties", "error", err)
+		return nil, err
+	}
+	return properties, nil
+}
+
+// getByIDSQL is GetByID over the direct connection, nil when the property does not exist
+func (r *PropertyRepository) getByIDSQL(ctx context.Context, id uuid.UUID) (*model.Property, error) {
+	properties, err := r.queryProperties(ctx, "WHERE p.id = $1", id.String())
+	if err != nil || len(properties) == 0 {
+		return nil, err
+	}
+
+	property := properties[0]
+	cached := property
+	cached.ManagerIDs = slices.Clone(property.ManagerIDs)
+	r.cache.Set(id, cached)
+	return &property, nil
+}
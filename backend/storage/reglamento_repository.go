@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ReglamentoRepository provides methods to interact with the building_reglamento and
+// reglamento_acknowledgment tables in Supabase
+type ReglamentoRepository struct {
+	client *supa.Client
+}
+
+// NewReglamentoRepository creates a new ReglamentoRepository
+func NewReglamentoRepository(client *supa.Client) *ReglamentoRepository {
+	return &ReglamentoRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a reglamento version by ID
+func (r *ReglamentoRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Reglamento, error) {
+	data, _, err := r.client.From("building_reglamento").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching reglamento", "id", id, "error", err)
+		return nil, err
+	}
+
+	var reglamentos []model.Reglamento
+	err = json.Unmarshal(data, &reglamentos)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reglamento data", "error", err)
+		return nil, err
+	}
+
+	if len(reglamentos) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &reglamentos[0], nil
+}
+
+// GetLatest retrieves the current version of the reglamento of every building
+func (r *ReglamentoRepository) GetLatest(ctx context.Context) (map[string]model.Reglamento, error) {
+	data, _, err := r.client.From("building_reglamento").Select("*", "exact", false).
+		Order("version", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching reglamentos", "error", err)
+		return nil, err
+	}
+
+	var reglamentos []model.Reglamento
+	err = json.Unmarshal(data, &reglamentos)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reglamento data", "error", err)
+		return nil, err
+	}
+
+	latest := make(map[string]model.Reglamento)
+	for _, reglamento := range reglamentos {
+		if current, ok := latest[reglamento.BuildingKey]; !ok || reglamento.Version > current.Version {
+			latest[reglamento.BuildingKey] = reglamento
+		}
+	}
+	return latest, nil
+}
+
+// GetLatestByBuilding retrieves the current version of the reglamento of a building, nil when
+// none was uploaded
+func (r *ReglamentoRepository) GetLatestByBuilding(ctx context.Context, buildingKey string) (*model.Reglamento, error) {
+	data, _, err := r.client.From("building_reglamento").Select("*", "exact", false).
+		Eq("building_key", buildingKey).
+		Order("version", &postgrest.OrderOpts{Ascending: false}).
+		Limit(1, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching reglamento of building", "building_key", buildingKey, "error", err)
+		return nil, err
+	}
+
+	var reglamentos []model.Reglamento
+	err = json.Unmarshal(data, &reglamentos)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reglamento data", "error", err)
+		return nil, err
+	}
+
+	if len(reglamentos) == 0 {
+		return nil, nil
+	}
+
+	return &reglamentos[0], nil
+}
+
+// Create adds a new version of the reglamento of a building
+func (r *ReglamentoRepository) Create(ctx context.Context, reglamento model.Reglamento) (*model.Reglamento, error) {
+	if reglamento.ID == uuid.Nil {
+		reglamento.ID = uuid.New()
+	}
+	if reglamento.UploadedAt.IsZero() {
+		reglamento.UploadedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("building_reglamento").Insert(reglamento, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating reglamento", "error", err)
+		return nil, fmt.Errorf("failed to create reglamento: %w", err)
+	}
+
+	var created []model.Reglamento
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created reglamento data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created reglamento, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// GetAcknowledgments retrieves the acknowledgments of a reglamento version
+func (r *ReglamentoRepository) GetAcknowledgments(ctx context.Context, reglamentoID uuid.UUID) ([]model.ReglamentoAcknowledgment, error) {
+	data, _, err := r.client.From("reglamento_acknowledgment").Select("*", "exact", false).
+		Eq("reglamento_id", reglamentoID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching acknowledgments of reglamento", "reglamento_id", reglamentoID, "error", err)
+		return nil, err
+	}
+
+	var acknowledgments []model.ReglamentoAcknowledgment
+	err = json.Unmarshal(data, &acknowledgments)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reglamento acknowledgment data", "error", err)
+		return nil, err
+	}
+
+	return acknowledgments, nil
+}
+
+// GetAcknowledgmentsByPerson retrieves the reglamento versions acknowledged by a tenant
+func (r *ReglamentoRepository) GetAcknowledgmentsByPerson(ctx context.Context, personID uuid.UUID) ([]model.ReglamentoAcknowledgment, error) {
+	data, _, err := r.client.From("reglamento_acknowledgment").Select("*", "exact", false).
+		Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching reglamento acknowledgments of person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	var acknowledgments []model.ReglamentoAcknowledgment
+	err = json.Unmarshal(data, &acknowledgments)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reglamento acknowledgment data", "error", err)
+		return nil, err
+	}
+
+	return acknowledgments, nil
+}
+
+// CreateAcknowledgment records that a tenant acknowledged a reglamento version
+func (r *ReglamentoRepository) CreateAcknowledgment(ctx context.Context, acknowledgment model.ReglamentoAcknowledgment) (*model.ReglamentoAcknowledgment, error) {
+	if acknowledgment.ID == uuid.Nil {
+		acknowledgment.ID = uuid.New()
+	}
+	if acknowledgment.AcknowledgedAt.IsZero() {
+		acknowledgment.AcknowledgedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("reglamento_acknowledgment").Insert(acknowledgment, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating reglamento acknowledgment", "error", err)
+		return nil, fmt.Errorf("failed to create reglamento acknowledgment: %w", err)
+	}
+
+	var created []model.ReglamentoAcknowledgment
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created reglamento acknowledgment data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created reglamento acknowledgment, empty result set")
+	}
+
+	return &created[0], nil
+}
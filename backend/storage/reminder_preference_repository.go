@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ReminderPreferenceRepository provides methods to interact with the reminder_preference table in Supabase
+type ReminderPreferenceRepository struct {
+	client *supa.Client
+}
+
+// NewReminderPreferenceRepository creates a new ReminderPreferenceRepository
+func NewReminderPreferenceRepository(client *supa.Client) *ReminderPreferenceRepository {
+	return &ReminderPreferenceRepository{
+		client: client,
+	}
+}
+
+// GetByPersonID retrieves the reminder preference of a person, nil when the person has none
+func (r *ReminderPreferenceRepository) GetByPersonID(ctx context.Context, personID uuid.UUID) (*model.ReminderPreference, error) {
+	data, count, err := r.client.From("reminder_preference").Select("*", "exact", false).
+		Eq("person_id", personID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching reminder preference for person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var preferences []model.ReminderPreference
+	err = json.Unmarshal([]byte(data), &preferences)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing reminder preference data", "error", err)
+		return nil, err
+	}
+
+	if len(preferences) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &preferences[0], nil
+}
+
+// Create adds the reminder preference of a person
+func (r *ReminderPreferenceRepository) Create(ctx context.Context, preference model.ReminderPreference) (*model.ReminderPreference, error) {
+	if preference.ID == uuid.Nil {
+		preference.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("reminder_preference").Insert(preference, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating reminder preference", "error", err)
+		return nil, fmt.Errorf("failed to create reminder preference: %w", err)
+	}
+
+	var createdPreferences []model.ReminderPreference
+	err = json.Unmarshal(data, &createdPreferences)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created reminder preference data", "error", err)
+		return nil, err
+	}
+
+	if len(createdPreferences) == 0 {
+		return nil, fmt.Errorf("failed to parse created reminder preference, empty result set")
+	}
+
+	return &createdPreferences[0], nil
+}
+
+// Update updates the reminder preference of a person
+func (r *ReminderPreferenceRepository) Update(ctx context.Context, preference model.ReminderPreference) (*model.ReminderPreference, error) {
+	preferenceData := map[string]interface{}{
+		"send_hour":          preference.SendHour,
+		"optimize_send_time": preference.OptimizeSendTime,
+	}
+
+	data, count, err := r.client.From("reminder_preference").Update(preferenceData, "exact", "").
+		Eq("person_id", preference.PersonID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating reminder preference for person", "person_id", preference.PersonID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByPersonID(ctx, preference.PersonID)
+	}
+
+	var updatedPreferences []model.ReminderPreference
+	err = json.Unmarshal(data, &updatedPreferences)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated reminder preference data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedPreferences) == 0 {
+		return r.GetByPersonID(ctx, preference.PersonID)
+	}
+
+	return &updatedPreferences[0], nil
+}
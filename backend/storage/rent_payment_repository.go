@@ -4,21 +4,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nescool101/rentManager/model"
+	"github.com/supabase-community/postgrest-go"
 	supa "github.com/supabase-community/supabase-go"
 )
 
 // RentPayment represents a payment made for a rental
 type RentPayment struct {
-	ID          string             `json:"id"`
-	RentalID    string             `json:"rental_id"`
-	PaymentDate model.FlexibleTime `json:"payment_date"`
-	AmountPaid  float64            `json:"amount_paid"`
-	PaidOnTime  bool               `json:"paid_on_time"`
+	ID            string             `json:"id"`
+	RentalID      string             `json:"rental_id"`
+	PaymentDate   model.FlexibleTime `json:"payment_date"`
+	AmountPaid    float64            `json:"amount_paid"`
+	PaidOnTime    bool               `json:"paid_on_time"`
+	ReceiptNumber string             `json:"receipt_number,omitempty"` // e.g. RP-2025-000001, only set by the numbering service
+	RentPaymentInvoice
+}
+
+// RentPaymentInvoice is the DIAN electronic invoice of a payment. Its fields are only written by
+// UpdateInvoice, they are omitted when empty so updates of the payment keep them.
+type RentPaymentInvoice struct {
+	InvoiceSequence   int64      `json:"invoice_sequence,omitempty"` // Consecutive within the numbering resolution
+	InvoiceNumber     string     `json:"invoice_number,omitempty"`   // Prefix and consecutive, e.g. SETP990000001
+	InvoiceCUFE       string     `json:"invoice_cufe,omitempty"`
+	InvoiceStatus     string     `json:"invoice_status,omitempty"` // One of the model.EInvoiceStatus constants
+	InvoiceMessage    string     `json:"invoice_message,omitempty"`
+	InvoiceProvider   string     `json:"invoice_provider,omitempty"`
+	InvoiceExternalID string     `json:"invoice_external_id,omitempty"` // Document ID on the provider
+	InvoicedAt        *time.Time `json:"invoiced_at,omitempty"`
 }
 
 // RentPaymentRepository interfaces with the rent_payment table
@@ -39,16 +55,16 @@ func (r *RentPaymentRepository) GetAll() ([]RentPayment, error) {
 
 	data, count, err := r.client.From("rent_payment").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching rent payments: %v", err)
+		slog.Error("error fetching rent payments", "error", err)
 		return nil, fmt.Errorf("failed to fetch rent payments: %w", err)
 	}
 
-	log.Printf("Retrieved %d rent payments", count)
+	slog.Debug("retrieved rent payments", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -60,7 +76,7 @@ func (r *RentPaymentRepository) GetByID(id string) (*RentPayment, error) {
 	data, count, err := r.client.From("rent_payment").Select("*", "exact", false).
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error fetching rent payment by ID: %v", err)
+		slog.Error("error fetching rent payment by ID", "error", err)
 		return nil, fmt.Errorf("failed to fetch rent payment: %w", err)
 	}
 
@@ -72,7 +88,7 @@ func (r *RentPaymentRepository) GetByID(id string) (*RentPayment, error) {
 	var payments []RentPayment
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -88,22 +104,52 @@ func (r *RentPaymentRepository) GetByRentalID(rentalID string) ([]RentPayment, e
 	data, count, err := r.client.From("rent_payment").Select("*", "exact", false).
 		Eq("rental_id", rentalID).Execute()
 	if err != nil {
-		log.Printf("Error fetching rent payments for rental: %v", err)
+		slog.Error("error fetching rent payments for rental", "error", err)
 		return nil, fmt.Errorf("failed to fetch rent payments for rental: %w", err)
 	}
 
-	log.Printf("Retrieved %d rent payments for rental %s", count, rentalID)
+	slog.Debug("retrieved rent payments for rental", "count", count, "rental_id", rentalID)
 
 	var payments []RentPayment
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
 	return payments, nil
 }
 
+// RentPaymentFilter narrows a list of payments
+type RentPaymentFilter struct {
+	RentalIDs []string // Only the payments of these rentals, every payment when nil
+}
+
+// List retrieves a page of payments passing the filter with the total count of matching payments
+func (r *RentPaymentRepository) List(filter RentPaymentFilter, opts ListOptions) ([]RentPayment, int, error) {
+	if filter.RentalIDs != nil && len(filter.RentalIDs) == 0 {
+		return []RentPayment{}, 0, nil
+	}
+
+	query := r.client.From("rent_payment").Select("*", "exact", false)
+	if filter.RentalIDs != nil {
+		query = query.In("rental_id", filter.RentalIDs)
+	}
+	data, count, err := opts.apply(query, "payment_date").Execute()
+	if err != nil {
+		slog.Error("error listing rent payments", "error", err)
+		return nil, 0, fmt.Errorf("failed to list rent payments: %w", err)
+	}
+
+	var payments []RentPayment
+	if err := json.Unmarshal(data, &payments); err != nil {
+		slog.Error("error parsing rent payment data", "error", err)
+		return nil, 0, fmt.Errorf("failed to parse rent payment data: %w", err)
+	}
+
+	return payments, int(count), nil
+}
+
 // GetByRentalIDs retrieves all payments for a list of rental IDs
 func (r *RentPaymentRepository) GetByRentalIDs(rentalIDs []string) ([]RentPayment, error) {
 	if len(rentalIDs) == 0 {
@@ -113,16 +159,16 @@ func (r *RentPaymentRepository) GetByRentalIDs(rentalIDs []string) ([]RentPaymen
 	data, count, err := r.client.From("rent_payment").Select("*", "exact", false).
 		In("rental_id", rentalIDs).Execute()
 	if err != nil {
-		log.Printf("Error fetching rent payments for multiple rentals: %v", err)
+		slog.Error("error fetching rent payments for multiple rentals", "error", err)
 		return nil, fmt.Errorf("failed to fetch rent payments for rentals: %w", err)
 	}
 
-	log.Printf("Retrieved %d rent payments for %d rentals", count, len(rentalIDs))
+	slog.Debug("retrieved rent payments rentals", "count", count, "rental_ids_count", len(rentalIDs))
 
 	var payments []RentPayment
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -140,23 +186,23 @@ func (r *RentPaymentRepository) Create(payment *RentPayment) (*RentPayment, erro
 
 	data, count, err := r.client.From("rent_payment").Insert(*payment, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating rent payment: %v", err)
+		slog.Error("error creating rent payment", "error", err)
 		return nil, fmt.Errorf("failed to create rent payment: %w", err)
 	}
 
 	// If count is 0 but data was returned, try to parse it
 	if count == 0 && len(data) > 0 {
-		log.Printf("Insert returned count 0 but has data, attempting to parse: %s", data)
+		slog.Info("insert returned count 0 but has data, attempting to parse", "data", string(data))
 	}
 
 	var createdPayment []RentPayment
 	err = json.Unmarshal([]byte(data), &createdPayment)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 
 		// If we can't parse, try to fetch the payment by ID as a fallback
 		if count == 0 {
-			log.Printf("Trying to fetch newly created payment by ID as fallback...")
+			slog.Info("trying to fetch newly created payment by ID as fallback")
 			return r.GetByID(paymentID)
 		}
 
@@ -166,7 +212,7 @@ func (r *RentPaymentRepository) Create(payment *RentPayment) (*RentPayment, erro
 	if len(createdPayment) == 0 {
 		// If empty result but count was 0, try fetching by ID as a fallback
 		if count == 0 {
-			log.Printf("Insert returned empty result, trying to fetch by ID as fallback...")
+			slog.Info("insert returned empty result, trying to fetch by ID as fallback")
 			return r.GetByID(paymentID)
 		}
 
@@ -176,12 +222,35 @@ func (r *RentPaymentRepository) Create(payment *RentPayment) (*RentPayment, erro
 	return &createdPayment[0], nil
 }
 
+// CreateNumbered creates a new rent payment with the next payment receipt number of an
+// organization (uuid.Nil for none) in a year. The number is reserved in the same transaction as
+// the insert, so a payment that fails to be created does not use up a number.
+func (r *RentPaymentRepository) CreateNumbered(payment *RentPayment, organizationID uuid.UUID, year int) (*RentPayment, error) {
+	type Request struct {
+		Payment        *RentPayment `json:"payment"`
+		OrganizationID uuid.UUID    `json:"sequence_organization_id"`
+		Year           int          `json:"sequence_year"`
+	}
+
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
+
+	var created RentPayment
+	err := callTransaction(r.client, "create_numbered_rent_payment", Request{Payment: payment, OrganizationID: organizationID, Year: year}, &created)
+	if err != nil {
+		slog.Error("error creating numbered rent payment", "rental_id", payment.RentalID, "organization_id", organizationID, "year", year, "error", err)
+		return nil, fmt.Errorf("failed to create rent payment: %w", err)
+	}
+	return &created, nil
+}
+
 // Update updates an existing rent payment
 func (r *RentPaymentRepository) Update(id string, payment *RentPayment) (*RentPayment, error) {
 	data, count, err := r.client.From("rent_payment").Update(*payment, "exact", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error updating rent payment: %v", err)
+		slog.Error("error updating rent payment", "error", err)
 		return nil, fmt.Errorf("failed to update rent payment: %w", err)
 	}
 
@@ -192,7 +261,7 @@ func (r *RentPaymentRepository) Update(id string, payment *RentPayment) (*RentPa
 	var updatedPayment []RentPayment
 	err = json.Unmarshal([]byte(data), &updatedPayment)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -208,7 +277,7 @@ func (r *RentPaymentRepository) Delete(id string) error {
 	_, _, err := r.client.From("rent_payment").Delete("minimal", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error deleting rent payment: %v", err)
+		slog.Error("error deleting rent payment", "error", err)
 		return fmt.Errorf("failed to delete rent payment: %w", err)
 	}
 
@@ -225,16 +294,16 @@ func (r *RentPaymentRepository) GetPaymentsByDateRange(startDate, endDate time.T
 		Lte("payment_date", endDateStr).
 		Execute()
 	if err != nil {
-		log.Printf("Error fetching rent payments by date range: %v", err)
+		slog.Error("error fetching rent payments by date range", "error", err)
 		return nil, fmt.Errorf("failed to fetch rent payments by date range: %w", err)
 	}
 
-	log.Printf("Retrieved %d rent payments in date range", count)
+	slog.Debug("retrieved rent payments in date range", "count", count)
 
 	var payments []RentPayment
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -246,16 +315,80 @@ func (r *RentPaymentRepository) GetLatePayments() ([]RentPayment, error) {
 	data, count, err := r.client.From("rent_payment").Select("*", "exact", false).
 		Eq("paid_on_time", "false").Execute()
 	if err != nil {
-		log.Printf("Error fetching late rent payments: %v", err)
+		slog.Error("error fetching late rent payments", "error", err)
 		return nil, fmt.Errorf("failed to fetch late rent payments: %w", err)
 	}
 
-	log.Printf("Retrieved %d late rent payments", count)
+	slog.Debug("retrieved late rent payments", "count", count)
 
 	var payments []RentPayment
 	err = json.Unmarshal([]byte(data), &payments)
 	if err != nil {
-		log.Printf("Error parsing rent payment data: %v", err)
+		slog.Error("error parsing rent payment data", "error", err)
+		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
+	}
+
+	return payments, nil
+}
+
+// UpdateInvoice stores the electronic invoice of a payment
+func (r *RentPaymentRepository) UpdateInvoice(id string, invoice RentPaymentInvoice) error {
+	updates := map[string]interface{}{
+		"invoice_sequence":    invoice.InvoiceSequence,
+		"invoice_number":      invoice.InvoiceNumber,
+		"invoice_cufe":        invoice.InvoiceCUFE,
+		"invoice_status":      invoice.InvoiceStatus,
+		"invoice_message":     invoice.InvoiceMessage,
+		"invoice_provider":    invoice.InvoiceProvider,
+		"invoice_external_id": invoice.InvoiceExternalID,
+		"invoiced_at":         invoice.InvoicedAt,
+	}
+
+	_, _, err := r.client.From("rent_payment").Update(updates, "minimal", "").
+		Eq("id", id).Execute()
+	if err != nil {
+		slog.Error("error updating rent payment invoice", "error", err)
+		return fmt.Errorf("failed to update rent payment invoice: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastInvoiceSequence returns the highest invoice consecutive used, 0 when no payment was invoiced
+func (r *RentPaymentRepository) GetLastInvoiceSequence() (int64, error) {
+	data, _, err := r.client.From("rent_payment").Select("invoice_sequence", "", false).
+		Gt("invoice_sequence", "0").
+		Order("invoice_sequence", &postgrest.OrderOpts{Ascending: false}).
+		Limit(1, "").Execute()
+	if err != nil {
+		slog.Error("error fetching last invoice sequence", "error", err)
+		return 0, fmt.Errorf("failed to fetch last invoice sequence: %w", err)
+	}
+
+	var payments []RentPayment
+	if err := json.Unmarshal(data, &payments); err != nil {
+		slog.Error("error parsing rent payment data", "error", err)
+		return 0, fmt.Errorf("failed to parse rent payment data: %w", err)
+	}
+
+	if len(payments) == 0 {
+		return 0, nil
+	}
+	return payments[0].InvoiceSequence, nil
+}
+
+// GetByInvoiceStatus retrieves the payments whose electronic invoice is in a status
+func (r *RentPaymentRepository) GetByInvoiceStatus(status string) ([]RentPayment, error) {
+	data, _, err := r.client.From("rent_payment").Select("*", "", false).
+		Eq("invoice_status", status).Execute()
+	if err != nil {
+		slog.Error("error fetching rent payments by invoice status", "error", err)
+		return nil, fmt.Errorf("failed to fetch rent payments by invoice status: %w", err)
+	}
+
+	var payments []RentPayment
+	if err := json.Unmarshal(data, &payments); err != nil {
+		slog.Error("error parsing rent payment data", "error", err)
 		return nil, fmt.Errorf("failed to parse rent payment data: %w", err)
 	}
 
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +20,14 @@ type RentalHistory struct {
 	Status    string             `json:"status"`
 	EndReason string             `json:"end_reason"`
 	EndDate   model.FlexibleTime `json:"end_date"`
+	// RentIncrease records how the canon of the following term was computed (Ley 820 traceability)
+	RentIncrease *model.RentIncreaseCalculation `json:"rent_increase,omitempty"`
+	// NextRentalID links the rental that continued this one (renewal or transfer)
+	NextRentalID string `json:"next_rental_id,omitempty"`
+	// DepositTransfer records the deposit carried over when the tenant moved to another property
+	DepositTransfer *model.DepositTransfer `json:"deposit_transfer,omitempty"`
+	// RecordedAt is set by the database when the record is written
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
 }
 
 // RentalHistoryRepository interfaces with the rental_history table
@@ -40,28 +48,46 @@ func (r *RentalHistoryRepository) GetAll() ([]RentalHistory, error) {
 
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental histories: %v", err)
+		slog.Error("error fetching rental histories", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories", count)
+	slog.Debug("retrieved rental histories", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
 	return histories, nil
 }
 
+// List retrieves a page of rental history records with the total count of records matching the filters
+func (r *RentalHistoryRepository) List(opts ListOptions) ([]RentalHistory, int, error) {
+	query := r.client.From("rental_history").Select("*", "exact", false)
+	data, count, err := opts.apply(query, "end_date").Execute()
+	if err != nil {
+		slog.Error("error listing rental histories", "error", err)
+		return nil, 0, fmt.Errorf("failed to list rental histories: %w", err)
+	}
+
+	var histories []RentalHistory
+	if err := json.Unmarshal(data, &histories); err != nil {
+		slog.Error("error parsing rental history data", "error", err)
+		return nil, 0, fmt.Errorf("failed to parse rental history data: %w", err)
+	}
+
+	return histories, int(count), nil
+}
+
 // GetByID retrieves a rental history record by ID
 func (r *RentalHistoryRepository) GetByID(id string) (*RentalHistory, error) {
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental history by ID: %v", err)
+		slog.Error("error fetching rental history by ID", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental history: %w", err)
 	}
 
@@ -73,7 +99,7 @@ func (r *RentalHistoryRepository) GetByID(id string) (*RentalHistory, error) {
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -89,16 +115,16 @@ func (r *RentalHistoryRepository) GetByPersonID(personID string) ([]RentalHistor
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
 		Eq("person_id", personID).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental histories for person: %v", err)
+		slog.Error("error fetching rental histories for person", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories for person: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories for person %s", count, personID)
+	slog.Debug("retrieved rental histories for person", "count", count, "person_id", personID)
 
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -110,16 +136,37 @@ func (r *RentalHistoryRepository) GetByRentalID(rentalID string) ([]RentalHistor
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
 		Eq("rental_id", rentalID).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental histories for rental: %v", err)
+		slog.Error("error fetching rental histories for rental", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories for rental: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories for rental %s", count, rentalID)
+	slog.Debug("retrieved rental histories for rental", "count", count, "rental_id", rentalID)
+
+	var histories []RentalHistory
+	err = json.Unmarshal([]byte(data), &histories)
+	if err != nil {
+		slog.Error("error parsing rental history data", "error", err)
+		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
+	}
+
+	return histories, nil
+}
+
+// GetByNextRentalID retrieves the rental history records of the rentals continued by a rental
+func (r *RentalHistoryRepository) GetByNextRentalID(nextRentalID string) ([]RentalHistory, error) {
+	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
+		Eq("next_rental_id", nextRentalID).Execute()
+	if err != nil {
+		slog.Error("error fetching rental histories for next rental", "error", err)
+		return nil, fmt.Errorf("failed to fetch rental histories for next rental: %w", err)
+	}
+
+	slog.Debug("retrieved rental histories continued by rental", "count", count, "next_rental_id", nextRentalID)
 
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -135,16 +182,16 @@ func (r *RentalHistoryRepository) GetByRentalIDs(rentalIDs []string) ([]RentalHi
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
 		In("rental_id", rentalIDs).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental histories for multiple rentals: %v", err)
+		slog.Error("error fetching rental histories for multiple rentals", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories for rentals: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories for %d rentals", count, len(rentalIDs))
+	slog.Debug("retrieved rental histories rentals", "count", count, "rental_ids_count", len(rentalIDs))
 
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -156,16 +203,16 @@ func (r *RentalHistoryRepository) GetByStatus(status string) ([]RentalHistory, e
 	data, count, err := r.client.From("rental_history").Select("*", "exact", false).
 		Eq("status", status).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental histories by status: %v", err)
+		slog.Error("error fetching rental histories by status", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories by status: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories with status %s", count, status)
+	slog.Debug("retrieved rental histories with status", "count", count, "status", status)
 
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -183,23 +230,23 @@ func (r *RentalHistoryRepository) Create(history *RentalHistory) (*RentalHistory
 
 	data, count, err := r.client.From("rental_history").Insert(*history, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating rental history: %v", err)
+		slog.Error("error creating rental history", "error", err)
 		return nil, fmt.Errorf("failed to create rental history: %w", err)
 	}
 
 	// If count is 0 but data was returned, try to parse it
 	if count == 0 && len(data) > 0 {
-		log.Printf("Insert returned count 0 but has data, attempting to parse: %s", data)
+		slog.Info("insert returned count 0 but has data, attempting to parse", "data", string(data))
 	}
 
 	var createdHistory []RentalHistory
 	err = json.Unmarshal([]byte(data), &createdHistory)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 
 		// If we can't parse, try to fetch the history by ID as a fallback
 		if count == 0 {
-			log.Printf("Trying to fetch newly created history by ID as fallback...")
+			slog.Info("trying to fetch newly created history by ID as fallback")
 			return r.GetByID(historyID)
 		}
 
@@ -209,7 +256,7 @@ func (r *RentalHistoryRepository) Create(history *RentalHistory) (*RentalHistory
 	if len(createdHistory) == 0 {
 		// If empty result but count was 0, try fetching by ID as a fallback
 		if count == 0 {
-			log.Printf("Insert returned empty result, trying to fetch by ID as fallback...")
+			slog.Info("insert returned empty result, trying to fetch by ID as fallback")
 			return r.GetByID(historyID)
 		}
 
@@ -224,7 +271,7 @@ func (r *RentalHistoryRepository) Update(id string, history *RentalHistory) (*Re
 	data, count, err := r.client.From("rental_history").Update(*history, "exact", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error updating rental history: %v", err)
+		slog.Error("error updating rental history", "error", err)
 		return nil, fmt.Errorf("failed to update rental history: %w", err)
 	}
 
@@ -235,7 +282,7 @@ func (r *RentalHistoryRepository) Update(id string, history *RentalHistory) (*Re
 	var updatedHistory []RentalHistory
 	err = json.Unmarshal([]byte(data), &updatedHistory)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
@@ -251,7 +298,7 @@ func (r *RentalHistoryRepository) Delete(id string) error {
 	_, _, err := r.client.From("rental_history").Delete("minimal", "").
 		Eq("id", id).Execute()
 	if err != nil {
-		log.Printf("Error deleting rental history: %v", err)
+		slog.Error("error deleting rental history", "error", err)
 		return fmt.Errorf("failed to delete rental history: %w", err)
 	}
 
@@ -269,16 +316,16 @@ func (r *RentalHistoryRepository) GetRentalHistoryByDateRange(startDate, endDate
 		Execute()
 
 	if err != nil {
-		log.Printf("Error fetching rental histories by date range: %v", err)
+		slog.Error("error fetching rental histories by date range", "error", err)
 		return nil, fmt.Errorf("failed to fetch rental histories by date range: %w", err)
 	}
 
-	log.Printf("Retrieved %d rental histories in date range", count)
+	slog.Debug("retrieved rental histories in date range", "count", count)
 
 	var histories []RentalHistory
 	err = json.Unmarshal([]byte(data), &histories)
 	if err != nil {
-		log.Printf("Error parsing rental history data: %v", err)
+		slog.Error("error parsing rental history data", "error", err)
 		return nil, fmt.Errorf("failed to parse rental history data: %w", err)
 	}
 
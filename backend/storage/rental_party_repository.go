@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// RentalPartyRepository provides methods to interact with the rental_parties table in Supabase
+type RentalPartyRepository struct {
+	client *supa.Client
+}
+
+// NewRentalPartyRepository creates a new RentalPartyRepository
+func NewRentalPartyRepository(client *supa.Client) *RentalPartyRepository {
+	return &RentalPartyRepository{
+		client: client,
+	}
+}
+
+// GetByRentalID retrieves the parties of a rental in the order they were added
+func (r *RentalPartyRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUID) ([]model.RentalParty, error) {
+	data, _, err := r.client.From("rental_parties").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching parties of rental", "rental_id", rentalID, "error", err)
+		return nil, err
+	}
+
+	var parties []model.RentalParty
+	if err := json.Unmarshal(data, &parties); err != nil {
+		logging.FromContext(ctx).Error("error parsing rental party data", "error", err)
+		return nil, err
+	}
+
+	return parties, nil
+}
+
+// GetByID retrieves a rental party, nil when it does not exist
+func (r *RentalPartyRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RentalParty, error) {
+	data, _, err := r.client.From("rental_parties").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching rental party", "id", id, "error", err)
+		return nil, err
+	}
+
+	var parties []model.RentalParty
+	if err := json.Unmarshal(data, &parties); err != nil {
+		logging.FromContext(ctx).Error("error parsing rental party data", "error", err)
+		return nil, err
+	}
+
+	if len(parties) == 0 {
+		return nil, nil
+	}
+
+	return &parties[0], nil
+}
+
+// Create links a person to a rental with a role
+func (r *RentalPartyRepository) Create(ctx context.Context, party model.RentalParty) (*model.RentalParty, error) {
+	if party.ID == uuid.Nil {
+		party.ID = uuid.New()
+	}
+	party.CreatedAt = time.Now()
+
+	data, _, err := r.client.From("rental_parties").Insert(party, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating party of rental", "role", party.Role, "rental_id", party.RentalID, "error", err)
+		return nil, fmt.Errorf("failed to create rental party: %w", err)
+	}
+
+	var created []model.RentalParty
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created rental party data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created rental party, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// Delete removes a party from its rental
+func (r *RentalPartyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("rental_parties").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting rental party", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
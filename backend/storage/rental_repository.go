@@ -3,18 +3,20 @@ package storage
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 	supa "github.com/supabase-community/supabase-go"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
 // RentalRepository provides methods to interact with the Rental table in Supabase
 type RentalRepository struct {
 	client *supa.Client
+	db     *pgxpool.Pool // Direct connection of STORAGE_DRIVER=postgres, nil with the REST driver
 }
 
 // NewRentalRepository creates a new RentalRepository
@@ -30,16 +32,16 @@ func (r *RentalRepository) GetAll(ctx context.Context) ([]model.Rental, error) {
 
 	data, count, err := r.client.From("rental").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching rentals: %v", err)
+		logging.FromContext(ctx).Error("error fetching rentals", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d rentals", count)
+	logging.FromContext(ctx).Debug("retrieved rentals", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
@@ -51,7 +53,7 @@ func (r *RentalRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Re
 	data, count, err := r.client.From("rental").Select("*", "exact", false).
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching rental by ID: %v", err)
+		logging.FromContext(ctx).Error("error fetching rental by ID", "error", err)
 		return nil, err
 	}
 
@@ -63,7 +65,7 @@ func (r *RentalRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Re
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
@@ -79,7 +81,7 @@ func (r *RentalRepository) GetByPropertyID(ctx context.Context, propertyID uuid.
 	data, count, err := r.client.From("rental").Select("*", "exact", false).
 		Eq("property_id", propertyID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching rentals by property ID: %v", err)
+		logging.FromContext(ctx).Error("error fetching rentals by property ID", "error", err)
 		return nil, err
 	}
 
@@ -90,7 +92,57 @@ func (r *RentalRepository) GetByPropertyID(ctx context.Context, propertyID uuid.
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
+		return nil, err
+	}
+
+	return rentals, nil
+}
+
+// GetByPropertyIDs retrieves the rentals of several properties in a single query
+func (r *RentalRepository) GetByPropertyIDs(ctx context.Context, propertyIDs []uuid.UUID) ([]model.Rental, error) {
+	return r.getWhereIn(ctx, "property_id", propertyIDs)
+}
+
+// GetByIDs retrieves several rentals by their IDs in a single query
+func (r *RentalRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Rental, error) {
+	return r.getWhereIn(ctx, "id", ids)
+}
+
+// GetByRenterIDs retrieves the rentals of several renters in a single query
+func (r *RentalRepository) GetByRenterIDs(ctx context.Context, renterIDs []uuid.UUID) ([]model.Rental, error) {
+	return r.getWhereIn(ctx, "renter_id", renterIDs)
+}
+
+// getWhereIn retrieves the rentals whose column is one of the IDs
+func (r *RentalRepository) getWhereIn(ctx context.Context, column string, values []uuid.UUID) ([]model.Rental, error) {
+	if len(values) == 0 {
+		return []model.Rental{}, nil
+	}
+
+	ids := make([]string, len(values))
+	for i, id := range values {
+		ids[i] = id.String()
+	}
+	if r.db != nil {
+		// column is one of the constants of the callers, never user input
+		rentals, err := queryJSON[model.Rental](ctx, r.db, "SELECT to_jsonb(r) FROM rental r WHERE r."+column+" = ANY($1::uuid[])", ids)
+		if err != nil {
+			logging.FromContext(ctx).Error("error fetching rentals", "column", column, "ids_count", len(ids), "error", err)
+		}
+		return rentals, err
+	}
+
+	data, _, err := r.client.From("rental").Select("*", "exact", false).
+		In(column, ids).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching rentals", "column", column, "ids_count", len(ids), "error", err)
+		return nil, err
+	}
+
+	var rentals []model.Rental
+	if err := json.Unmarshal(data, &rentals); err != nil {
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
@@ -99,10 +151,18 @@ func (r *RentalRepository) GetByPropertyID(ctx context.Context, propertyID uuid.
 
 // GetByRenterID retrieves rentals by renter ID
 func (r *RentalRepository) GetByRenterID(ctx context.Context, renterID uuid.UUID) ([]model.Rental, error) {
+	if r.db != nil {
+		rentals, err := queryJSON[model.Rental](ctx, r.db, "SELECT to_jsonb(r) FROM rental r WHERE r.renter_id = $1", renterID.String())
+		if err != nil {
+			logging.FromContext(ctx).Error("error fetching rentals by renter ID", "error", err)
+		}
+		return rentals, err
+	}
+
 	data, count, err := r.client.From("rental").Select("*", "exact", false).
 		Eq("renter_id", renterID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching rentals by renter ID: %v", err)
+		logging.FromContext(ctx).Error("error fetching rentals by renter ID", "error", err)
 		return nil, err
 	}
 
@@ -113,20 +173,53 @@ func (r *RentalRepository) GetByRenterID(ctx context.Context, renterID uuid.UUID
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
 	return rentals, nil
 }
 
+// RentalFilter narrows a list of rentals
+type RentalFilter struct {
+	Active *bool     // Whether the rental runs on Today: it started on or before and ends on or after it
+	Today  time.Time // Start of the calendar day Active is evaluated on
+}
+
+// List retrieves a page of rentals passing the filter with the total count of matching rentals
+func (r *RentalRepository) List(ctx context.Context, filter RentalFilter, opts ListOptions) ([]model.Rental, int, error) {
+	query := r.client.From("rental").Select("*", "exact", false)
+	if filter.Active != nil {
+		today := filter.Today.UTC().Format(time.RFC3339)
+		tomorrow := filter.Today.AddDate(0, 0, 1).UTC().Format(time.RFC3339)
+		if *filter.Active {
+			query = query.Lt("start_date", tomorrow).Gte("end_date", today)
+		} else {
+			query = query.Or("start_date.gte."+tomorrow+",end_date.lt."+today, "")
+		}
+	}
+	data, count, err := opts.apply(query, "start_date").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing rentals", "error", err)
+		return nil, 0, err
+	}
+
+	var rentals []model.Rental
+	if err := json.Unmarshal(data, &rentals); err != nil {
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
+		return nil, 0, err
+	}
+
+	return rentals, int(count), nil
+}
+
 // GetActiveRentals retrieves all active rentals (where end_date is in the future)
 func (r *RentalRepository) GetActiveRentals(ctx context.Context) ([]model.Rental, error) {
 	now := time.Now().Format(time.RFC3339)
 	data, count, err := r.client.From("rental").Select("*", "exact", false).
 		Gte("end_date", now).Execute()
 	if err != nil {
-		log.Printf("Error fetching active rentals: %v", err)
+		logging.FromContext(ctx).Error("error fetching active rentals", "error", err)
 		return nil, err
 	}
 
@@ -137,7 +230,7 @@ func (r *RentalRepository) GetActiveRentals(ctx context.Context) ([]model.Rental
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
@@ -160,7 +253,7 @@ func (r *RentalRepository) Create(ctx context.Context, rental model.Rental) (*mo
 
 	data, count, err := r.client.From("rental").Insert(rentalData, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating rental: %v", err)
+		logging.FromContext(ctx).Error("error creating rental", "error", err)
 		return nil, err
 	}
 
@@ -171,7 +264,7 @@ func (r *RentalRepository) Create(ctx context.Context, rental model.Rental) (*mo
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
@@ -182,7 +275,8 @@ func (r *RentalRepository) Create(ctx context.Context, rental model.Rental) (*mo
 	return &rentals[0], nil
 }
 
-// Update updates an existing rental
+// Update updates an existing rental. It returns ErrVersionConflict when rental.Version is set
+// and the rental was updated since, and nil when the rental does not exist.
 func (r *RentalRepository) Update(ctx context.Context, rental model.Rental) (*model.Rental, error) {
 	// Convert FlexibleTime to time.Time format for database
 	rentalData := map[string]interface{}{
@@ -196,26 +290,22 @@ func (r *RentalRepository) Update(ctx context.Context, rental model.Rental) (*mo
 		"unpaid_months":   rental.UnpaidMonths,
 	}
 
-	data, count, err := r.client.From("rental").Update(rentalData, "exact", "").
-		Eq("id", rental.ID.String()).Execute()
+	data, _, err := filterVersion(r.client.From("rental").Update(rentalData, "representation", "").
+		Eq("id", rental.ID.String()), rental.Version).Execute()
 	if err != nil {
-		log.Printf("Error updating rental: %v", err)
+		logging.FromContext(ctx).Error("error updating rental", "error", err)
 		return nil, err
 	}
 
-	if count == 0 {
-		return nil, nil // No result returned
-	}
-
 	var rentals []model.Rental
 	err = json.Unmarshal([]byte(data), &rentals)
 	if err != nil {
-		log.Printf("Error parsing rental data: %v", err)
+		logging.FromContext(ctx).Error("error parsing rental data", "error", err)
 		return nil, err
 	}
 
 	if len(rentals) == 0 {
-		return nil, nil // No result returned
+		return nil, versionMiss(r.client, "rental", rental.ID.String(), rental.Version)
 	}
 
 	return &rentals[0], nil
@@ -226,7 +316,7 @@ func (r *RentalRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	_, _, err := r.client.From("rental").Delete("minimal", "").
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error deleting rental: %v", err)
+		logging.FromContext(ctx).Error("error deleting rental", "error", err)
 		return err
 	}
 
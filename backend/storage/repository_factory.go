@@ -1,36 +1,109 @@
 package storage
 
+//go:generate go run ./gen -dir . -out interfaces.gen.go
+
 import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/model"
 )
 
 // RepositoryFactory creates and manages repository instances
 type RepositoryFactory struct {
-	client                       *supa.Client
-	personRepository             *PersonRepository
-	propertyRepository           *PropertyRepository
-	rentalRepository             *RentalRepository
-	userRepository               *UserRepository
-	rentPaymentRepository        *RentPaymentRepository
-	rentalHistoryRepository      *RentalHistoryRepository
-	maintenanceRequestRepository *MaintenanceRequestRepository
-	pricingRepository            *PricingRepository
-	contractSigningRepository    *ContractSigningRepository
-	bankAccountRepository        *BankAccountRepository
-	personRoleRepository         *PersonRoleRepository
+	client                           *supa.Client
+	db                               *pgxpool.Pool // Direct connection of STORAGE_DRIVER=postgres, see OpenPostgres
+	referenceCacheTTL                time.Duration // TTL of the person and property caches
+	personRepository                 *PersonRepository
+	propertyRepository               *PropertyRepository
+	rentalRepository                 *RentalRepository
+	userRepository                   *UserRepository
+	rentPaymentRepository            *RentPaymentRepository
+	rentalHistoryRepository          *RentalHistoryRepository
+	maintenanceRequestRepository     *MaintenanceRequestRepository
+	maintenanceCommentRepository     *MaintenanceCommentRepository
+	maintenanceStatusRepository      *MaintenanceStatusHistoryRepository
+	pricingRepository                *PricingRepository
+	contractSigningRepository        *ContractSigningRepository
+	contractSigningEventRepo         *ContractSigningEventRepository
+	bankAccountRepository            *BankAccountRepository
+	personRoleRepository             *PersonRoleRepository
+	serviceProviderRepository        *ServiceProviderRepository
+	organizationRepository           *OrganizationRepository
+	contractTemplateRepository       *ContractTemplateRepository
+	reminderPreferenceRepository     *ReminderPreferenceRepository
+	emailOutboxRepository            *EmailOutboxRepository
+	managerDigestRepository          *ManagerDigestRepository
+	inventoryRepository              *InventoryRepository
+	promotionRepository              *PromotionRepository
+	guaranteeStudyRepository         *GuaranteeStudyRepository
+	notarizationRepository           *NotarizationRepository
+	reglamentoRepository             *ReglamentoRepository
+	contractCessionRepository        *ContractCessionRepository
+	webhookRepository                *WebhookRepository
+	paymentStatementRepository       *PaymentStatementRepository
+	uploadLimitRepository            *UploadLimitRepository
+	userBulkJobRepository            *UserBulkJobRepository
+	fileScanRepository               *FileScanRepository
+	fileMetadataRepository           *FileMetadataRepository
+	trashedFileRepository            *TrashedFileRepository
+	fileIndexRepository              *FileIndexRepository
+	bucketBackupRepository           *BucketBackupRepository
+	emailTemplateRepository          *EmailTemplateRepository
+	notificationPreferenceRepository *NotificationPreferenceRepository
+	billingReceiptRepository         *BillingReceiptRepository
+	documentSequenceRepository       *DocumentSequenceRepository
+	paymentCheckoutRepository        *PaymentCheckoutRepository
+	platformSubscriptionRepository   *PlatformSubscriptionRepository
+	securityDepositRepository        *SecurityDepositRepository
+	inspectionRepository             *InspectionRepository
+	listingRepository                *ListingRepository
+	rentalPartyRepository            *RentalPartyRepository
+	propertyPhotoRepository          *PropertyPhotoRepository
+	buildingRepository               *BuildingRepository
+	searchRepository                 *SearchRepository
+	loginAttemptRepository           *LoginAttemptRepository
+	userSessionRepository            *UserSessionRepository
+	auditLogRepository               *AuditLogRepository
+	invitationRepository             *InvitationRepository
+	notificationRepository           *NotificationRepository
+	featureFlagRepository            *FeatureFlagRepository
 }
 
 // NewRepositoryFactory creates a new repository factory
 func NewRepositoryFactory(client *supa.Client) *RepositoryFactory {
 	return &RepositoryFactory{
-		client: client,
+		client:            client,
+		referenceCacheTTL: defaultReferenceCacheTTL,
 	}
 }
 
+// UsePostgres makes the repositories created afterwards run their joined and batched queries
+// over the direct connection, falling back to the REST client for the rest. A nil pool keeps
+// the REST driver.
+func (f *RepositoryFactory) UsePostgres(db *pgxpool.Pool) *RepositoryFactory {
+	f.db = db
+	return f
+}
+
+// UseReferenceCacheTTL sets how long the repositories created afterwards keep the persons and
+// properties read by ID in memory, 0 disables the caches. A nil ttl keeps the default.
+func (f *RepositoryFactory) UseReferenceCacheTTL(ttl *time.Duration) *RepositoryFactory {
+	if ttl != nil {
+		f.referenceCacheTTL = *ttl
+	}
+	return f
+}
+
 // GetPersonRepository returns a person repository instance
 func (f *RepositoryFactory) GetPersonRepository() *PersonRepository {
 	if f.personRepository == nil {
 		f.personRepository = NewPersonRepository(f.client)
+		f.personRepository.db = f.db
+		f.personRepository.cache = newTTLCache[uuid.UUID, model.Person](f.referenceCacheTTL)
 	}
 	return f.personRepository
 }
@@ -39,6 +112,8 @@ func (f *RepositoryFactory) GetPersonRepository() *PersonRepository {
 func (f *RepositoryFactory) GetPropertyRepository() *PropertyRepository {
 	if f.propertyRepository == nil {
 		f.propertyRepository = NewPropertyRepository(f.client)
+		f.propertyRepository.db = f.db
+		f.propertyRepository.cache = newTTLCache[uuid.UUID, model.Property](f.referenceCacheTTL)
 	}
 	return f.propertyRepository
 }
@@ -47,6 +122,7 @@ func (f *RepositoryFactory) GetPropertyRepository() *PropertyRepository {
 func (f *RepositoryFactory) GetRentalRepository() *RentalRepository {
 	if f.rentalRepository == nil {
 		f.rentalRepository = NewRentalRepository(f.client)
+		f.rentalRepository.db = f.db
 	}
 	return f.rentalRepository
 }
@@ -83,6 +159,22 @@ func (f *RepositoryFactory) GetMaintenanceRequestRepository() *MaintenanceReques
 	return f.maintenanceRequestRepository
 }
 
+// GetMaintenanceCommentRepository returns a maintenance comment repository instance
+func (f *RepositoryFactory) GetMaintenanceCommentRepository() *MaintenanceCommentRepository {
+	if f.maintenanceCommentRepository == nil {
+		f.maintenanceCommentRepository = NewMaintenanceCommentRepository(f.client)
+	}
+	return f.maintenanceCommentRepository
+}
+
+// GetMaintenanceStatusHistoryRepository returns a maintenance status history repository instance
+func (f *RepositoryFactory) GetMaintenanceStatusHistoryRepository() *MaintenanceStatusHistoryRepository {
+	if f.maintenanceStatusRepository == nil {
+		f.maintenanceStatusRepository = NewMaintenanceStatusHistoryRepository(f.client)
+	}
+	return f.maintenanceStatusRepository
+}
+
 // GetPricingRepository returns a pricing repository instance
 func (f *RepositoryFactory) GetPricingRepository() *PricingRepository {
 	if f.pricingRepository == nil {
@@ -115,6 +207,334 @@ func (f *RepositoryFactory) GetPersonRoleRepository() *PersonRoleRepository {
 	return f.personRoleRepository
 }
 
+// GetServiceProviderRepository returns a service provider repository instance
+func (f *RepositoryFactory) GetServiceProviderRepository() *ServiceProviderRepository {
+	if f.serviceProviderRepository == nil {
+		f.serviceProviderRepository = NewServiceProviderRepository(f.client)
+	}
+	return f.serviceProviderRepository
+}
+
+// GetOrganizationRepository returns an organization repository instance
+func (f *RepositoryFactory) GetOrganizationRepository() *OrganizationRepository {
+	if f.organizationRepository == nil {
+		f.organizationRepository = NewOrganizationRepository(f.client)
+	}
+	return f.organizationRepository
+}
+
+// GetContractTemplateRepository returns a contract template repository instance
+func (f *RepositoryFactory) GetContractTemplateRepository() *ContractTemplateRepository {
+	if f.contractTemplateRepository == nil {
+		f.contractTemplateRepository = NewContractTemplateRepository(f.client)
+	}
+	return f.contractTemplateRepository
+}
+
+// GetReminderPreferenceRepository returns a reminder preference repository instance
+func (f *RepositoryFactory) GetReminderPreferenceRepository() *ReminderPreferenceRepository {
+	if f.reminderPreferenceRepository == nil {
+		f.reminderPreferenceRepository = NewReminderPreferenceRepository(f.client)
+	}
+	return f.reminderPreferenceRepository
+}
+
+// GetEmailOutboxRepository returns an email outbox repository instance
+func (f *RepositoryFactory) GetEmailOutboxRepository() *EmailOutboxRepository {
+	if f.emailOutboxRepository == nil {
+		f.emailOutboxRepository = NewEmailOutboxRepository(f.client)
+	}
+	return f.emailOutboxRepository
+}
+
+// GetManagerDigestRepository returns a manager digest subscription repository instance
+func (f *RepositoryFactory) GetManagerDigestRepository() *ManagerDigestRepository {
+	if f.managerDigestRepository == nil {
+		f.managerDigestRepository = NewManagerDigestRepository(f.client)
+	}
+	return f.managerDigestRepository
+}
+
+// GetInventoryRepository returns a property inventory repository instance
+func (f *RepositoryFactory) GetInventoryRepository() *InventoryRepository {
+	if f.inventoryRepository == nil {
+		f.inventoryRepository = NewInventoryRepository(f.client)
+	}
+	return f.inventoryRepository
+}
+
+// GetPromotionRepository returns a listing promotion repository instance
+func (f *RepositoryFactory) GetPromotionRepository() *PromotionRepository {
+	if f.promotionRepository == nil {
+		f.promotionRepository = NewPromotionRepository(f.client)
+	}
+	return f.promotionRepository
+}
+
+// GetGuaranteeStudyRepository returns an afianzadora guarantee study repository instance
+func (f *RepositoryFactory) GetGuaranteeStudyRepository() *GuaranteeStudyRepository {
+	if f.guaranteeStudyRepository == nil {
+		f.guaranteeStudyRepository = NewGuaranteeStudyRepository(f.client)
+	}
+	return f.guaranteeStudyRepository
+}
+
+// GetContractSigningEventRepository returns a contract signing audit trail repository instance
+func (f *RepositoryFactory) GetContractSigningEventRepository() *ContractSigningEventRepository {
+	if f.contractSigningEventRepo == nil {
+		f.contractSigningEventRepo = NewContractSigningEventRepository(f.client)
+	}
+	return f.contractSigningEventRepo
+}
+
+// GetNotarizationRepository returns a contract notarization repository instance
+func (f *RepositoryFactory) GetNotarizationRepository() *NotarizationRepository {
+	if f.notarizationRepository == nil {
+		f.notarizationRepository = NewNotarizationRepository(f.client)
+	}
+	return f.notarizationRepository
+}
+
+// GetReglamentoRepository returns a building reglamento repository instance
+func (f *RepositoryFactory) GetReglamentoRepository() *ReglamentoRepository {
+	if f.reglamentoRepository == nil {
+		f.reglamentoRepository = NewReglamentoRepository(f.client)
+	}
+	return f.reglamentoRepository
+}
+
+// GetContractCessionRepository returns a contract cession repository instance
+func (f *RepositoryFactory) GetContractCessionRepository() *ContractCessionRepository {
+	if f.contractCessionRepository == nil {
+		f.contractCessionRepository = NewContractCessionRepository(f.client)
+	}
+	return f.contractCessionRepository
+}
+
+// GetWebhookRepository returns a webhook repository instance
+func (f *RepositoryFactory) GetWebhookRepository() *WebhookRepository {
+	if f.webhookRepository == nil {
+		f.webhookRepository = NewWebhookRepository(f.client)
+	}
+	return f.webhookRepository
+}
+
+// GetPaymentStatementRepository returns a payment statement repository instance
+func (f *RepositoryFactory) GetPaymentStatementRepository() *PaymentStatementRepository {
+	if f.paymentStatementRepository == nil {
+		f.paymentStatementRepository = NewPaymentStatementRepository(f.client)
+	}
+	return f.paymentStatementRepository
+}
+
+// GetUploadLimitRepository returns an upload limit repository instance
+func (f *RepositoryFactory) GetUploadLimitRepository() *UploadLimitRepository {
+	if f.uploadLimitRepository == nil {
+		f.uploadLimitRepository = NewUploadLimitRepository(f.client)
+	}
+	return f.uploadLimitRepository
+}
+
+// GetUserBulkJobRepository returns a bulk user job repository instance
+func (f *RepositoryFactory) GetUserBulkJobRepository() *UserBulkJobRepository {
+	if f.userBulkJobRepository == nil {
+		f.userBulkJobRepository = NewUserBulkJobRepository(f.client)
+	}
+	return f.userBulkJobRepository
+}
+
+// GetFileScanRepository returns a file scan repository instance
+func (f *RepositoryFactory) GetFileScanRepository() *FileScanRepository {
+	if f.fileScanRepository == nil {
+		f.fileScanRepository = NewFileScanRepository(f.client)
+	}
+	return f.fileScanRepository
+}
+
+// GetFileMetadataRepository returns a file metadata repository instance
+func (f *RepositoryFactory) GetFileMetadataRepository() *FileMetadataRepository {
+	if f.fileMetadataRepository == nil {
+		f.fileMetadataRepository = NewFileMetadataRepository(f.client)
+	}
+	return f.fileMetadataRepository
+}
+
+// GetTrashedFileRepository returns a trashed file repository instance
+func (f *RepositoryFactory) GetTrashedFileRepository() *TrashedFileRepository {
+	if f.trashedFileRepository == nil {
+		f.trashedFileRepository = NewTrashedFileRepository(f.client)
+	}
+	return f.trashedFileRepository
+}
+
+// GetFileIndexRepository returns a file index repository instance
+func (f *RepositoryFactory) GetFileIndexRepository() *FileIndexRepository {
+	if f.fileIndexRepository == nil {
+		f.fileIndexRepository = NewFileIndexRepository(f.client)
+	}
+	return f.fileIndexRepository
+}
+
+// GetBucketBackupRepository returns a bucket backup repository instance
+func (f *RepositoryFactory) GetBucketBackupRepository() *BucketBackupRepository {
+	if f.bucketBackupRepository == nil {
+		f.bucketBackupRepository = NewBucketBackupRepository(f.client)
+	}
+	return f.bucketBackupRepository
+}
+
+// GetEmailTemplateRepository returns an email template repository instance
+func (f *RepositoryFactory) GetEmailTemplateRepository() *EmailTemplateRepository {
+	if f.emailTemplateRepository == nil {
+		f.emailTemplateRepository = NewEmailTemplateRepository(f.client)
+	}
+	return f.emailTemplateRepository
+}
+
+// GetNotificationPreferenceRepository returns a notification preference repository instance
+func (f *RepositoryFactory) GetNotificationPreferenceRepository() *NotificationPreferenceRepository {
+	if f.notificationPreferenceRepository == nil {
+		f.notificationPreferenceRepository = NewNotificationPreferenceRepository(f.client)
+	}
+	return f.notificationPreferenceRepository
+}
+
+// GetBillingReceiptRepository returns a billing receipt repository instance
+func (f *RepositoryFactory) GetBillingReceiptRepository() *BillingReceiptRepository {
+	if f.billingReceiptRepository == nil {
+		f.billingReceiptRepository = NewBillingReceiptRepository(f.client)
+	}
+	return f.billingReceiptRepository
+}
+
+// GetDocumentSequenceRepository returns a document sequence repository instance
+func (f *RepositoryFactory) GetDocumentSequenceRepository() *DocumentSequenceRepository {
+	if f.documentSequenceRepository == nil {
+		f.documentSequenceRepository = NewDocumentSequenceRepository(f.client)
+	}
+	return f.documentSequenceRepository
+}
+
+// GetPaymentCheckoutRepository returns a payment checkout repository instance
+func (f *RepositoryFactory) GetPaymentCheckoutRepository() *PaymentCheckoutRepository {
+	if f.paymentCheckoutRepository == nil {
+		f.paymentCheckoutRepository = NewPaymentCheckoutRepository(f.client)
+	}
+	return f.paymentCheckoutRepository
+}
+
+// GetPlatformSubscriptionRepository returns a platform subscription repository instance
+func (f *RepositoryFactory) GetPlatformSubscriptionRepository() *PlatformSubscriptionRepository {
+	if f.platformSubscriptionRepository == nil {
+		f.platformSubscriptionRepository = NewPlatformSubscriptionRepository(f.client)
+	}
+	return f.platformSubscriptionRepository
+}
+
+// GetSecurityDepositRepository returns a security deposit repository instance
+func (f *RepositoryFactory) GetSecurityDepositRepository() *SecurityDepositRepository {
+	if f.securityDepositRepository == nil {
+		f.securityDepositRepository = NewSecurityDepositRepository(f.client)
+	}
+	return f.securityDepositRepository
+}
+
+// GetInspectionRepository returns an inspection repository instance
+func (f *RepositoryFactory) GetInspectionRepository() *InspectionRepository {
+	if f.inspectionRepository == nil {
+		f.inspectionRepository = NewInspectionRepository(f.client)
+	}
+	return f.inspectionRepository
+}
+
+// GetListingRepository returns a listing repository instance
+func (f *RepositoryFactory) GetListingRepository() *ListingRepository {
+	if f.listingRepository == nil {
+		f.listingRepository = NewListingRepository(f.client)
+	}
+	return f.listingRepository
+}
+
+// GetRentalPartyRepository returns a rental party repository instance
+func (f *RepositoryFactory) GetRentalPartyRepository() *RentalPartyRepository {
+	if f.rentalPartyRepository == nil {
+		f.rentalPartyRepository = NewRentalPartyRepository(f.client)
+	}
+	return f.rentalPartyRepository
+}
+
+// GetPropertyPhotoRepository returns a property photo repository instance
+func (f *RepositoryFactory) GetPropertyPhotoRepository() *PropertyPhotoRepository {
+	if f.propertyPhotoRepository == nil {
+		f.propertyPhotoRepository = NewPropertyPhotoRepository(f.client)
+	}
+	return f.propertyPhotoRepository
+}
+
+// GetBuildingRepository returns a building (copropiedad) repository instance
+func (f *RepositoryFactory) GetBuildingRepository() *BuildingRepository {
+	if f.buildingRepository == nil {
+		f.buildingRepository = NewBuildingRepository(f.client)
+	}
+	return f.buildingRepository
+}
+
+// GetSearchRepository returns a full-text search repository instance
+func (f *RepositoryFactory) GetSearchRepository() *SearchRepository {
+	if f.searchRepository == nil {
+		f.searchRepository = NewSearchRepository(f.client)
+	}
+	return f.searchRepository
+}
+
+// GetLoginAttemptRepository returns a login attempt repository instance
+func (f *RepositoryFactory) GetLoginAttemptRepository() *LoginAttemptRepository {
+	if f.loginAttemptRepository == nil {
+		f.loginAttemptRepository = NewLoginAttemptRepository(f.client)
+	}
+	return f.loginAttemptRepository
+}
+
+// GetUserSessionRepository returns a user session repository instance
+func (f *RepositoryFactory) GetUserSessionRepository() *UserSessionRepository {
+	if f.userSessionRepository == nil {
+		f.userSessionRepository = NewUserSessionRepository(f.client)
+	}
+	return f.userSessionRepository
+}
+
+// GetAuditLogRepository returns an audit log repository instance
+func (f *RepositoryFactory) GetAuditLogRepository() *AuditLogRepository {
+	if f.auditLogRepository == nil {
+		f.auditLogRepository = NewAuditLogRepository(f.client)
+	}
+	return f.auditLogRepository
+}
+
+// GetInvitationRepository returns an invitation repository instance
+func (f *RepositoryFactory) GetInvitationRepository() *InvitationRepository {
+	if f.invitationRepository == nil {
+		f.invitationRepository = NewInvitationRepository(f.client)
+	}
+	return f.invitationRepository
+}
+
+// GetNotificationRepository returns a notification repository instance
+func (f *RepositoryFactory) GetNotificationRepository() *NotificationRepository {
+	if f.notificationRepository == nil {
+		f.notificationRepository = NewNotificationRepository(f.client)
+	}
+	return f.notificationRepository
+}
+
+// GetFeatureFlagRepository returns a feature flag repository instance
+func (f *RepositoryFactory) GetFeatureFlagRepository() *FeatureFlagRepository {
+	if f.featureFlagRepository == nil {
+		f.featureFlagRepository = NewFeatureFlagRepository(f.client)
+	}
+	return f.featureFlagRepository
+}
+
 // GetClient returns the underlying Supabase client
 func (f *RepositoryFactory) GetClient() *supa.Client {
 	return f.client
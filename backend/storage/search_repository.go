@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// SearchRepository runs the Postgres full-text search over persons, properties, rentals and files
+type SearchRepository struct {
+	client *supa.Client
+}
+
+// NewSearchRepository creates a new SearchRepository
+func NewSearchRepository(client *supa.Client) *SearchRepository {
+	return &SearchRepository{
+		client: client,
+	}
+}
+
+// searchRow is a row returned by the search_all function
+type searchRow struct {
+	model.SearchResult
+	Kind string `json:"kind"`
+}
+
+// Search returns up to limit results matching every word of query, best matches first
+func (r *SearchRepository) Search(ctx context.Context, query string, limit int) ([]model.SearchResult, error) {
+	type Request struct {
+		SearchQuery string `json:"search_query"`
+		MaxResults  int    `json:"max_results"`
+	}
+
+	data := r.client.Rpc("search_all", "", Request{SearchQuery: query, MaxResults: limit})
+	if err := checkRPCError(data); err != nil {
+		logging.FromContext(ctx).Error("error searching", "query", query, "error", err)
+		return nil, err
+	}
+
+	var rows []searchRow
+	if err := json.Unmarshal([]byte(data), &rows); err != nil {
+		logging.FromContext(ctx).Error("error parsing search results", "error", err)
+		return nil, err
+	}
+
+	results := make([]model.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		result := row.SearchResult
+		result.Type = row.Kind
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// SecurityDepositRepository provides methods to interact with the security_deposit table in Supabase
+type SecurityDepositRepository struct {
+	client *supa.Client
+}
+
+// NewSecurityDepositRepository creates a new SecurityDepositRepository
+func NewSecurityDepositRepository(client *supa.Client) *SecurityDepositRepository {
+	return &SecurityDepositRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a security deposit, nil when it does not exist
+func (r *SecurityDepositRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SecurityDeposit, error) {
+	data, _, err := r.client.From("security_deposit").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching security deposit", "id", id, "error", err)
+		return nil, err
+	}
+
+	deposits, err := parseSecurityDeposits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deposits) == 0 {
+		return nil, nil
+	}
+
+	return &deposits[0], nil
+}
+
+// GetByRentalID retrieves the security deposit of a rental, nil when it has none
+func (r *SecurityDepositRepository) GetByRentalID(ctx context.Context, rentalID uuid.UUID) (*model.SecurityDeposit, error) {
+	data, _, err := r.client.From("security_deposit").Select("*", "exact", false).
+		Eq("rental_id", rentalID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching security deposit of rental", "rental_id", rentalID, "error", err)
+		return nil, err
+	}
+
+	deposits, err := parseSecurityDeposits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deposits) == 0 {
+		return nil, nil
+	}
+
+	return &deposits[0], nil
+}
+
+// GetByRentalIDs retrieves the security deposits of several rentals
+func (r *SecurityDepositRepository) GetByRentalIDs(ctx context.Context, rentalIDs []uuid.UUID) ([]model.SecurityDeposit, error) {
+	if len(rentalIDs) == 0 {
+		return []model.SecurityDeposit{}, nil
+	}
+
+	ids := make([]string, len(rentalIDs))
+	for i, id := range rentalIDs {
+		ids[i] = id.String()
+	}
+
+	data, _, err := r.client.From("security_deposit").Select("*", "exact", false).
+		In("rental_id", ids).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching security deposits rentals", "rental_ids_count", len(rentalIDs), "error", err)
+		return nil, err
+	}
+
+	deposits, err := parseSecurityDeposits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return deposits, nil
+}
+
+// parseSecurityDeposits decodes the security deposits returned by a query
+func parseSecurityDeposits(data []byte) ([]model.SecurityDeposit, error) {
+	var deposits []model.SecurityDeposit
+	if err := json.Unmarshal(data, &deposits); err != nil {
+		slog.Error("error parsing security deposit data", "error", err)
+		return nil, err
+	}
+
+	return deposits, nil
+}
+
+// Create records the security deposit of a rental
+func (r *SecurityDepositRepository) Create(ctx context.Context, deposit model.SecurityDeposit) (*model.SecurityDeposit, error) {
+	if deposit.ID == uuid.Nil {
+		deposit.ID = uuid.New()
+	}
+	now := time.Now()
+	deposit.CreatedAt = now
+	deposit.UpdatedAt = now
+	withoutReceiptURLs(&deposit)
+
+	data, _, err := r.client.From("security_deposit").Insert(deposit, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating security deposit of rental", "rental_id", deposit.RentalID, "error", err)
+		return nil, fmt.Errorf("failed to create security deposit: %w", err)
+	}
+
+	var created []model.SecurityDeposit
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created security deposit data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created security deposit, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// Update saves a security deposit with its deductions and settlement
+func (r *SecurityDepositRepository) Update(ctx context.Context, deposit model.SecurityDeposit) (*model.SecurityDeposit, error) {
+	deposit.UpdatedAt = time.Now()
+	withoutReceiptURLs(&deposit)
+
+	data, _, err := r.client.From("security_deposit").Update(deposit, "representation", "").
+		Eq("id", deposit.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating security deposit", "deposit_id", deposit.ID, "error", err)
+		return nil, fmt.Errorf("failed to update security deposit: %w", err)
+	}
+
+	var updated []model.SecurityDeposit
+	err = json.Unmarshal(data, &updated)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated security deposit data", "error", err)
+		return nil, err
+	}
+
+	if len(updated) == 0 {
+		return nil, fmt.Errorf("security deposit %s not found", deposit.ID)
+	}
+
+	return &updated[0], nil
+}
+
+// withoutReceiptURLs clears the signed receipt URLs, which expire and are not stored
+func withoutReceiptURLs(deposit *model.SecurityDeposit) {
+	deductions := make([]model.DepositDeduction, len(deposit.Deductions))
+	for i, deduction := range deposit.Deductions {
+		deduction.ReceiptURL = ""
+		deductions[i] = deduction
+	}
+	deposit.Deductions = deductions
+}
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// ServiceProviderRepository provides methods to interact with the service_provider table in Supabase
+type ServiceProviderRepository struct {
+	client *supa.Client
+}
+
+// NewServiceProviderRepository creates a new ServiceProviderRepository
+func NewServiceProviderRepository(client *supa.Client) *ServiceProviderRepository {
+	return &ServiceProviderRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves all service providers
+func (r *ServiceProviderRepository) GetAll(ctx context.Context) ([]model.ServiceProvider, error) {
+	var providers []model.ServiceProvider
+
+	data, count, err := r.client.From("service_provider").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching service providers", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("retrieved service providers", "count", count)
+
+	err = json.Unmarshal([]byte(data), &providers)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing service provider data", "error", err)
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// GetByID retrieves a service provider by ID
+func (r *ServiceProviderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.ServiceProvider, error) {
+	data, count, err := r.client.From("service_provider").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching service provider", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil // Not found
+	}
+
+	var providers []model.ServiceProvider
+	err = json.Unmarshal([]byte(data), &providers)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing service provider data", "error", err)
+		return nil, err
+	}
+
+	if len(providers) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &providers[0], nil
+}
+
+// GetBySpecialty retrieves service providers with a specific specialty
+func (r *ServiceProviderRepository) GetBySpecialty(ctx context.Context, specialty string) ([]model.ServiceProvider, error) {
+	data, count, err := r.client.From("service_provider").Select("*", "exact", false).
+		Eq("specialty", specialty).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching service providers by specialty", "specialty", specialty, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return []model.ServiceProvider{}, nil // No providers found
+	}
+
+	var providers []model.ServiceProvider
+	err = json.Unmarshal([]byte(data), &providers)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing service provider data", "error", err)
+		return nil, err
+	}
+
+	return providers, nil
+}
+
+// Create adds a new service provider
+func (r *ServiceProviderRepository) Create(ctx context.Context, provider model.ServiceProvider) (*model.ServiceProvider, error) {
+	if provider.ID == uuid.Nil {
+		provider.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("service_provider").Insert(provider, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating service provider", "error", err)
+		return nil, fmt.Errorf("failed to create service provider: %w", err)
+	}
+
+	var createdProviders []model.ServiceProvider
+	err = json.Unmarshal(data, &createdProviders)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created service provider data", "error", err)
+		return nil, err
+	}
+
+	if len(createdProviders) == 0 {
+		return nil, fmt.Errorf("failed to parse created service provider, empty result set")
+	}
+
+	return &createdProviders[0], nil
+}
+
+// Update updates an existing service provider
+func (r *ServiceProviderRepository) Update(ctx context.Context, provider model.ServiceProvider) (*model.ServiceProvider, error) {
+	providerData := map[string]interface{}{
+		"name":         provider.Name,
+		"specialty":    provider.Specialty,
+		"contact_name": provider.ContactName,
+		"phone":        provider.Phone,
+		"email":        provider.Email,
+		"notes":        provider.Notes,
+	}
+
+	data, count, err := r.client.From("service_provider").Update(providerData, "exact", "").
+		Eq("id", provider.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating service provider", "provider_id", provider.ID, "error", err)
+		return nil, err
+	}
+
+	// The update does not always return the row, fetch it in that case
+	if count == 0 || len(data) == 0 {
+		return r.GetByID(ctx, provider.ID)
+	}
+
+	var updatedProviders []model.ServiceProvider
+	err = json.Unmarshal(data, &updatedProviders)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated service provider data", "error", err)
+		return nil, err
+	}
+
+	if len(updatedProviders) == 0 {
+		return r.GetByID(ctx, provider.ID)
+	}
+
+	return &updatedProviders[0], nil
+}
+
+// Delete removes a service provider
+func (r *ServiceProviderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("service_provider").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting service provider", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
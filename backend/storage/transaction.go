@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// versionConflictCode is the SQLSTATE the database functions raise when the optimistic
+// concurrency check fails. PostgREST answers the PTxxx codes with the HTTP status xxx.
+const versionConflictCode = "PT409"
+
+// rpcError is the body PostgREST returns when a function raises an error
+type rpcError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// callTransaction calls a database function that writes several rows. PostgREST runs each
+// call in its own transaction, so the function applies all of its steps or none of them. The
+// result of the function is decoded into result; ErrVersionConflict is returned when the
+// function raised versionConflictCode.
+func callTransaction(client *supa.Client, function string, params interface{}, result interface{}) error {
+	data := client.Rpc(function, "", params)
+	if data == "" {
+		return fmt.Errorf("empty response from %s", function)
+	}
+
+	var failure rpcError
+	if err := json.Unmarshal([]byte(data), &failure); err == nil && failure.Code != "" && failure.Message != "" {
+		if failure.Code == versionConflictCode {
+			return ErrVersionConflict
+		}
+		return fmt.Errorf("%s failed: (%s) %s", function, failure.Code, failure.Message)
+	}
+
+	if err := json.Unmarshal([]byte(data), result); err != nil {
+		return fmt.Errorf("failed to parse the result of %s: %w", function, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// TrashedFileRepository provides methods to interact with the trashed_file table in Supabase
+type TrashedFileRepository struct {
+	client *supa.Client
+}
+
+// NewTrashedFileRepository creates a new TrashedFileRepository
+func NewTrashedFileRepository(client *supa.Client) *TrashedFileRepository {
+	return &TrashedFileRepository{
+		client: client,
+	}
+}
+
+// GetAll retrieves the files in the trash, most recently deleted first
+func (r *TrashedFileRepository) GetAll(ctx context.Context) ([]model.TrashedFile, error) {
+	data, _, err := r.client.From("trashed_file").Select("*", "exact", false).
+		Order("deleted_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching trashed files", "error", err)
+		return nil, err
+	}
+
+	var files []model.TrashedFile
+	err = json.Unmarshal(data, &files)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing trashed file data", "error", err)
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// GetByID retrieves a trashed file, nil if it does not exist
+func (r *TrashedFileRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.TrashedFile, error) {
+	data, _, err := r.client.From("trashed_file").Select("*", "exact", false).Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching trashed file", "id", id, "error", err)
+		return nil, err
+	}
+
+	var files []model.TrashedFile
+	err = json.Unmarshal(data, &files)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing trashed file data", "error", err)
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return &files[0], nil
+}
+
+// GetExpired retrieves the trashed files whose retention ended before the given time
+func (r *TrashedFileRepository) GetExpired(ctx context.Context, before time.Time) ([]model.TrashedFile, error) {
+	data, _, err := r.client.From("trashed_file").Select("*", "exact", false).
+		Lte("purge_at", before.UTC().Format(time.RFC3339)).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching expired trashed files", "error", err)
+		return nil, err
+	}
+
+	var files []model.TrashedFile
+	err = json.Unmarshal(data, &files)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing trashed file data", "error", err)
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Create records a file moved to the trash
+func (r *TrashedFileRepository) Create(ctx context.Context, file model.TrashedFile) (*model.TrashedFile, error) {
+	if file.ID == uuid.Nil {
+		file.ID = uuid.New()
+	}
+
+	data, _, err := r.client.From("trashed_file").Insert(file, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating trashed file", "path", file.Path, "error", err)
+		return nil, fmt.Errorf("failed to create trashed file: %w", err)
+	}
+
+	var created []model.TrashedFile
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created trashed file data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created trashed file, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// Delete removes the record of a trashed file, once restored or purged
+func (r *TrashedFileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("trashed_file").Delete("", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting trashed file", "id", id, "error", err)
+		return fmt.Errorf("failed to delete trashed file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// UploadLimitRepository provides methods to interact with the upload_limit table in Supabase
+type UploadLimitRepository struct {
+	client *supa.Client
+}
+
+// NewUploadLimitRepository creates a new UploadLimitRepository
+func NewUploadLimitRepository(client *supa.Client) *UploadLimitRepository {
+	return &UploadLimitRepository{
+		client: client,
+	}
+}
+
+// uploadLimitRow is the stored part of an upload limit
+type uploadLimitRow struct {
+	Role          string     `json:"role"`
+	MaxFileSizeMB int64      `json:"max_file_size_mb"`
+	QuotaMB       int64      `json:"quota_mb"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// GetAll retrieves the upload limits saved by admins
+func (r *UploadLimitRepository) GetAll(ctx context.Context) ([]model.UploadLimit, error) {
+	data, _, err := r.client.From("upload_limit").Select("*", "exact", false).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching upload limits", "error", err)
+		return nil, err
+	}
+
+	var limits []model.UploadLimit
+	err = json.Unmarshal(data, &limits)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing upload limit data", "error", err)
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// Save creates or replaces the upload limit of a role
+func (r *UploadLimitRepository) Save(ctx context.Context, limit model.UploadLimit) (*model.UploadLimit, error) {
+	now := time.Now()
+	row := uploadLimitRow{
+		Role:          limit.Role,
+		MaxFileSizeMB: limit.MaxFileSizeMB,
+		QuotaMB:       limit.QuotaMB,
+		UpdatedAt:     &now,
+	}
+
+	data, _, err := r.client.From("upload_limit").Upsert(row, "role", "representation", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving upload limit of role", "role", limit.Role, "error", err)
+		return nil, fmt.Errorf("failed to save upload limit: %w", err)
+	}
+
+	var saved []model.UploadLimit
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing saved upload limit data", "error", err)
+		return nil, err
+	}
+
+	if len(saved) == 0 {
+		return nil, fmt.Errorf("failed to parse saved upload limit, empty result set")
+	}
+
+	return &saved[0], nil
+}
+
+// Delete removes the upload limit of a role, which falls back to the environment
+func (r *UploadLimitRepository) Delete(ctx context.Context, role string) error {
+	_, _, err := r.client.From("upload_limit").Delete("minimal", "").Eq("role", role).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting upload limit of role", "role", role, "error", err)
+		return err
+	}
+
+	return nil
+}
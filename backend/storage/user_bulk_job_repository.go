@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// UserBulkJobRepository provides methods to interact with the user_bulk_job table in Supabase
+type UserBulkJobRepository struct {
+	client *supa.Client
+}
+
+// NewUserBulkJobRepository creates a new UserBulkJobRepository
+func NewUserBulkJobRepository(client *supa.Client) *UserBulkJobRepository {
+	return &UserBulkJobRepository{
+		client: client,
+	}
+}
+
+// GetByID retrieves a bulk user job by ID
+func (r *UserBulkJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.UserBulkJob, error) {
+	data, _, err := r.client.From("user_bulk_job").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching bulk user job", "id", id, "error", err)
+		return nil, err
+	}
+
+	var jobs []model.UserBulkJob
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bulk user job data", "error", err)
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &jobs[0], nil
+}
+
+// GetRecent retrieves the latest bulk user jobs, newest first
+func (r *UserBulkJobRepository) GetRecent(ctx context.Context, limit int) ([]model.UserBulkJob, error) {
+	data, _, err := r.client.From("user_bulk_job").Select("*", "exact", false).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching bulk user jobs", "error", err)
+		return nil, err
+	}
+
+	var jobs []model.UserBulkJob
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bulk user job data", "error", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetUnfinished retrieves the queued and running jobs, oldest first, to resume them after a restart
+func (r *UserBulkJobRepository) GetUnfinished(ctx context.Context) ([]model.UserBulkJob, error) {
+	data, _, err := r.client.From("user_bulk_job").Select("*", "exact", false).
+		In("status", []string{model.UserBulkJobQueued, model.UserBulkJobRunning}).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching unfinished bulk user jobs", "error", err)
+		return nil, err
+	}
+
+	var jobs []model.UserBulkJob
+	err = json.Unmarshal(data, &jobs)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing bulk user job data", "error", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Create adds a bulk user job
+func (r *UserBulkJobRepository) Create(ctx context.Context, job model.UserBulkJob) (*model.UserBulkJob, error) {
+	if job.Results == nil {
+		job.Results = []model.UserBulkResult{}
+	}
+
+	data, _, err := r.client.From("user_bulk_job").Insert(job, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating bulk user job", "error", err)
+		return nil, fmt.Errorf("failed to create bulk user job: %w", err)
+	}
+
+	var created []model.UserBulkJob
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created bulk user job data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created bulk user job, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// SaveProgress saves the state, counters and report of a job
+func (r *UserBulkJobRepository) SaveProgress(ctx context.Context, job model.UserBulkJob) error {
+	if job.Results == nil {
+		job.Results = []model.UserBulkResult{}
+	}
+
+	_, _, err := r.client.From("user_bulk_job").Update(map[string]interface{}{
+		"status":      job.Status,
+		"total":       job.Total,
+		"succeeded":   job.Succeeded,
+		"skipped":     job.Skipped,
+		"failed":      job.Failed,
+		"results":     job.Results,
+		"error":       job.Error,
+		"started_at":  job.StartedAt,
+		"finished_at": job.FinishedAt,
+	}, "", "").Eq("id", job.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error saving progress of bulk user job", "job_id", job.ID, "error", err)
+		return err
+	}
+	return nil
+}
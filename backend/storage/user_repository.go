@@ -3,11 +3,11 @@ package storage
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"github.com/google/uuid"
 	supa "github.com/supabase-community/supabase-go"
 
+	"github.com/nescool101/rentManager/logging"
 	"github.com/nescool101/rentManager/model"
 )
 
@@ -29,28 +29,46 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]model.User, error) {
 
 	data, count, err := r.client.From("users").Select("*", "exact", false).Execute()
 	if err != nil {
-		log.Printf("Error fetching users: %v", err)
+		logging.FromContext(ctx).Error("error fetching users", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d users", count)
+	logging.FromContext(ctx).Debug("retrieved users", "count", count)
 
 	// Parse the JSON response data into our struct
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data: %v", err)
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
 		return nil, err
 	}
 
 	return users, nil
 }
 
+// List retrieves a page of users with the total count of users matching the filters
+func (r *UserRepository) List(ctx context.Context, opts ListOptions) ([]model.User, int, error) {
+	query := r.client.From("users").Select("*", "exact", false)
+	data, count, err := opts.apply(query, "created_at").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error listing users", "error", err)
+		return nil, 0, err
+	}
+
+	var users []model.User
+	if err := json.Unmarshal(data, &users); err != nil {
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
+		return nil, 0, err
+	}
+
+	return users, int(count), nil
+}
+
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
 	data, count, err := r.client.From("users").Select("*", "exact", false).
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching user by ID: %v", err)
+		logging.FromContext(ctx).Error("error fetching user by ID", "error", err)
 		return nil, err
 	}
 
@@ -62,7 +80,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User
 	var users []model.User
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data: %v", err)
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
 		return nil, err
 	}
 
@@ -78,7 +96,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	data, count, err := r.client.From("users").Select("*", "exact", false).
 		Eq("email", email).Execute()
 	if err != nil {
-		log.Printf("Error fetching user by email: %v", err)
+		logging.FromContext(ctx).Error("error fetching user by email", "error", err)
 		return nil, err
 	}
 
@@ -90,7 +108,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	var users []model.User
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data: %v", err)
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
 		return nil, err
 	}
 
@@ -106,7 +124,7 @@ func (r *UserRepository) GetByPersonID(ctx context.Context, personID uuid.UUID)
 	data, count, err := r.client.From("users").Select("*", "exact", false).
 		Eq("person_id", personID.String()).Execute()
 	if err != nil {
-		log.Printf("Error fetching user by PersonID %s: %v", personID, err)
+		logging.FromContext(ctx).Error("error fetching user by PersonID", "person_id", personID, "error", err)
 		return nil, err
 	}
 
@@ -118,7 +136,7 @@ func (r *UserRepository) GetByPersonID(ctx context.Context, personID uuid.UUID)
 	var users []model.User
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data for PersonID %s: %v", personID, err)
+		logging.FromContext(ctx).Error("error parsing user data for PersonID", "person_id", personID, "error", err)
 		return nil, err
 	}
 
@@ -133,7 +151,7 @@ func (r *UserRepository) GetByPersonID(ctx context.Context, personID uuid.UUID)
 func (r *UserRepository) Create(ctx context.Context, user model.User) (*model.User, error) {
 	data, count, err := r.client.From("users").Insert(user, false, "exact", "", "").Execute()
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
+		logging.FromContext(ctx).Error("error creating user", "error", err)
 		return nil, err
 	}
 
@@ -144,7 +162,7 @@ func (r *UserRepository) Create(ctx context.Context, user model.User) (*model.Us
 	var users []model.User
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data: %v", err)
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
 		return nil, err
 	}
 
@@ -160,7 +178,7 @@ func (r *UserRepository) Update(ctx context.Context, user model.User) (*model.Us
 	data, count, err := r.client.From("users").Update(user, "exact", "").
 		Eq("id", user.ID.String()).Execute()
 	if err != nil {
-		log.Printf("Error updating user: %v", err)
+		logging.FromContext(ctx).Error("error updating user", "error", err)
 		return nil, err
 	}
 
@@ -171,7 +189,7 @@ func (r *UserRepository) Update(ctx context.Context, user model.User) (*model.Us
 	var users []model.User
 	err = json.Unmarshal([]byte(data), &users)
 	if err != nil {
-		log.Printf("Error parsing user data: %v", err)
+		logging.FromContext(ctx).Error("error parsing user data", "error", err)
 		return nil, err
 	}
 
@@ -187,7 +205,7 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	_, _, err := r.client.From("users").Delete("minimal", "").
 		Eq("id", id.String()).Execute()
 	if err != nil {
-		log.Printf("Error deleting user: %v", err)
+		logging.FromContext(ctx).Error("error deleting user", "error", err)
 		return err
 	}
 
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// UserSessionRepository provides methods to interact with the user_session table in Supabase
+type UserSessionRepository struct {
+	client *supa.Client
+}
+
+// NewUserSessionRepository creates a new UserSessionRepository
+func NewUserSessionRepository(client *supa.Client) *UserSessionRepository {
+	return &UserSessionRepository{
+		client: client,
+	}
+}
+
+// Create records a session
+func (r *UserSessionRepository) Create(ctx context.Context, session model.UserSession) (*model.UserSession, error) {
+	data, _, err := r.client.From("user_session").Insert(session, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error recording session of user", "user_id", session.UserID, "error", err)
+		return nil, fmt.Errorf("failed to record session: %w", err)
+	}
+
+	var created []model.UserSession
+	if err := json.Unmarshal(data, &created); err != nil {
+		logging.FromContext(ctx).Error("error parsing created session data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created session, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// GetByID retrieves a session by its ID, the jti of its token
+func (r *UserSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.UserSession, error) {
+	data, count, err := r.client.From("user_session").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching session", "id", id, "error", err)
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	var sessions []model.UserSession
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		logging.FromContext(ctx).Error("error parsing session data", "error", err)
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	return &sessions[0], nil
+}
+
+// GetActiveByUser retrieves the sessions of a user that are neither revoked nor expired at now,
+// most recently used first
+func (r *UserSessionRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]model.UserSession, error) {
+	data, _, err := r.client.From("user_session").Select("*", "exact", false).
+		Eq("user_id", userID.String()).
+		Is("revoked_at", "null").
+		Gt("expires_at", now.UTC().Format(time.RFC3339Nano)).
+		Order("last_seen_at", &postgrest.OrderOpts{Ascending: false}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching sessions of user", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	var sessions []model.UserSession
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		logging.FromContext(ctx).Error("error parsing session data", "error", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Revoke marks a session of a user as revoked and reports whether it was active; the sessions
+// of other users and the ones already revoked are not touched
+func (r *UserSessionRepository) Revoke(ctx context.Context, id, userID uuid.UUID, revokedAt time.Time) (bool, error) {
+	data, _, err := r.client.From("user_session").Update(map[string]interface{}{
+		"revoked_at": revokedAt,
+	}, "", "").Eq("id", id.String()).Eq("user_id", userID.String()).Is("revoked_at", "null").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error revoking session", "id", id, "error", err)
+		return false, err
+	}
+
+	var updated []model.UserSession
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+// Touch records that a session was used at lastSeenAt
+func (r *UserSessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeenAt time.Time) error {
+	_, _, err := r.client.From("user_session").Update(map[string]interface{}{
+		"last_seen_at": lastSeenAt,
+	}, "minimal", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating last use of session", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpiredBefore removes the sessions that expired before before, revoked or not
+func (r *UserSessionRepository) DeleteExpiredBefore(ctx context.Context, before time.Time) error {
+	_, _, err := r.client.From("user_session").Delete("minimal", "").
+		Lt("expires_at", before.UTC().Format(time.RFC3339Nano)).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting expired sessions", "error", err)
+		return err
+	}
+
+	return nil
+}
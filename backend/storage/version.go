@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// ErrVersionConflict is returned by the updates of versioned records (person, property, rental
+// and pricing) when the record changed since the caller read it
+var ErrVersionConflict = errors.New("the record was modified by someone else since it was read")
+
+// filterVersion makes an update apply only to the version the caller read. The version column
+// is incremented by a trigger on every update; a version of 0 skips the check, for the services
+// that overwrite the record without reading it first. The API always sends a version, see
+// requireVersion in the controllers.
+func filterVersion(query *postgrest.FilterBuilder, version int) *postgrest.FilterBuilder {
+	if version > 0 {
+		return query.Eq("version", strconv.Itoa(version))
+	}
+	return query
+}
+
+// versionMiss explains a versioned update that matched no row: ErrVersionConflict when the
+// record still exists, nil when it is missing
+func versionMiss(client *supa.Client, table, id string, version int) error {
+	if version == 0 {
+		return nil
+	}
+	_, count, err := client.From(table).Select("id", "exact", true).Eq("id", id).Execute()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/supabase-community/postgrest-go"
+	supa "github.com/supabase-community/supabase-go"
+
+	"github.com/nescool101/rentManager/logging"
+	"github.com/nescool101/rentManager/model"
+)
+
+// WebhookRepository provides methods to interact with the webhook_subscription and
+// webhook_delivery tables in Supabase
+type WebhookRepository struct {
+	client *supa.Client
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository(client *supa.Client) *WebhookRepository {
+	return &WebhookRepository{
+		client: client,
+	}
+}
+
+// GetSubscriptionByID retrieves a webhook subscription by ID
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error) {
+	data, _, err := r.client.From("webhook_subscription").Select("*", "exact", false).
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching webhook subscription", "id", id, "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.WebhookSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing webhook subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil, nil // Not found
+	}
+
+	return &subscriptions[0], nil
+}
+
+// GetSubscriptionsByPersonID retrieves the webhooks registered by a person, oldest first
+func (r *WebhookRepository) GetSubscriptionsByPersonID(ctx context.Context, personID uuid.UUID) ([]model.WebhookSubscription, error) {
+	data, _, err := r.client.From("webhook_subscription").Select("*", "exact", false).
+		Eq("person_id", personID.String()).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching webhook subscriptions of person", "person_id", personID, "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.WebhookSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing webhook subscription data", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetActiveSubscriptions retrieves every active webhook
+func (r *WebhookRepository) GetActiveSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	data, _, err := r.client.From("webhook_subscription").Select("*", "exact", false).
+		Eq("active", "true").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching active webhook subscriptions", "error", err)
+		return nil, err
+	}
+
+	var subscriptions []model.WebhookSubscription
+	err = json.Unmarshal(data, &subscriptions)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing webhook subscription data", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// CreateSubscription registers a webhook
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if subscription.ID == uuid.Nil {
+		subscription.ID = uuid.New()
+	}
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = time.Now()
+	}
+
+	data, _, err := r.client.From("webhook_subscription").Insert(subscription, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error creating webhook subscription", "url", subscription.URL, "error", err)
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	var created []model.WebhookSubscription
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing created webhook subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse created webhook subscription, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// UpdateSubscription saves the URL, events, description and state of a webhook
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+
+	data, _, err := r.client.From("webhook_subscription").Update(map[string]interface{}{
+		"url":          subscription.URL,
+		"events":       subscription.Events,
+		"all_requests": subscription.AllRequests,
+		"description":  subscription.Description,
+		"active":       subscription.Active,
+	}, "", "").Eq("id", subscription.ID.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error updating webhook subscription", "subscription_id", subscription.ID, "error", err)
+		return nil, err
+	}
+
+	var updated []model.WebhookSubscription
+	err = json.Unmarshal(data, &updated)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing updated webhook subscription data", "error", err)
+		return nil, err
+	}
+
+	if len(updated) == 0 {
+		return r.GetSubscriptionByID(ctx, subscription.ID)
+	}
+
+	return &updated[0], nil
+}
+
+// DeleteSubscription removes a webhook and its deliveries
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	_, _, err := r.client.From("webhook_delivery").Delete("minimal", "").
+		Eq("subscription_id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting deliveries of webhook subscription", "id", id, "error", err)
+		return err
+	}
+
+	_, _, err = r.client.From("webhook_subscription").Delete("minimal", "").
+		Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error deleting webhook subscription", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateDelivery queues an event for a webhook
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.Status == "" {
+		delivery.Status = model.WebhookDeliveryPending
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = delivery.CreatedAt
+	}
+
+	data, _, err := r.client.From("webhook_delivery").Insert(delivery, false, "exact", "", "").Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error queuing delivery for webhook", "event", delivery.Event, "subscription_id", delivery.SubscriptionID, "error", err)
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	var created []model.WebhookDelivery
+	err = json.Unmarshal(data, &created)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing queued webhook delivery data", "error", err)
+		return nil, err
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("failed to parse queued webhook delivery, empty result set")
+	}
+
+	return &created[0], nil
+}
+
+// GetDueDeliveries retrieves up to limit pending deliveries whose next attempt has been reached,
+// oldest first
+func (r *WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	data, _, err := r.client.From("webhook_delivery").Select("*", "exact", false).
+		Eq("status", model.WebhookDeliveryPending).
+		Lte("next_attempt_at", now.UTC().Format(time.RFC3339)).
+		Order("next_attempt_at", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching due webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	var deliveries []model.WebhookDelivery
+	err = json.Unmarshal(data, &deliveries)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing webhook delivery data", "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDeliveriesBySubscriptionID retrieves the most recent deliveries of a webhook, up to limit
+func (r *WebhookRepository) GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]model.WebhookDelivery, error) {
+	data, _, err := r.client.From("webhook_delivery").Select("*", "exact", false).
+		Eq("subscription_id", subscriptionID.String()).
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").
+		Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error fetching deliveries of webhook subscription", "subscription_id", subscriptionID, "error", err)
+		return nil, err
+	}
+
+	var deliveries []model.WebhookDelivery
+	err = json.Unmarshal(data, &deliveries)
+	if err != nil {
+		logging.FromContext(ctx).Error("error parsing webhook delivery data", "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered records that a webhook accepted a delivery
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id uuid.UUID, attempts, responseStatus int, deliveredAt time.Time) error {
+	_, _, err := r.client.From("webhook_delivery").Update(map[string]interface{}{
+		"status":          model.WebhookDeliveryDelivered,
+		"attempts":        attempts,
+		"response_status": responseStatus,
+		"last_error":      "",
+		"delivered_at":    deliveredAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking webhook delivery as delivered", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkDeliveryFailed records a failed attempt. The delivery is retried at nextAttemptAt while
+// status is pending.
+func (r *WebhookRepository) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, status string, attempts, responseStatus int, lastError string, nextAttemptAt time.Time) error {
+	_, _, err := r.client.From("webhook_delivery").Update(map[string]interface{}{
+		"status":          status,
+		"attempts":        attempts,
+		"response_status": responseStatus,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}, "", "").Eq("id", id.String()).Execute()
+	if err != nil {
+		logging.FromContext(ctx).Error("error marking webhook delivery as failed", "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
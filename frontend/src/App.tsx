@@ -17,6 +17,7 @@ const Persons = lazy(() => import('./pages/Persons'));
 const Profile = lazy(() => import('./pages/Profile'));
 const MaintenanceRequests = lazy(() => import('./pages/MaintenanceRequests'));
 const Payments = lazy(() => import('./pages/Payments'));
+const Deposits = lazy(() => import('./pages/Deposits'));
 const RentalHistory = lazy(() => import('./pages/RentalHistory'));
 const Contracts = lazy(() => import('./pages/Contracts'));
 const Users = lazy(() => import('./pages/Users'));
@@ -24,12 +25,14 @@ const NotFound = lazy(() => import('./pages/NotFound'));
 const Landing = lazy(() => import('./pages/Landing'));
 const About = lazy(() => import('./pages/About'));
 const Contact = lazy(() => import('./pages/Contact'));
+const Listings = lazy(() => import('./pages/Listings'));
 const Login = lazy(() => import('./pages/Login'));
 const AccessDenied = lazy(() => import('./pages/AccessDenied'));
 const RentalPricingPage = lazy(() => import('./pages/Admin/RentalPricingPage'));
 const ManualEmailSenderPage = lazy(() => import('./pages/Admin/ManualEmailSenderPage'));
 const ContractGenerationPage = lazy(() => import('./pages/Admin/ContractGenerationPage'));
 const ContractSigningPage = lazy(() => import('./pages/ContractSigningPage'));
+const NotificationPreferencesPage = lazy(() => import('./pages/NotificationPreferencesPage'));
 const ManagerRegistration = lazy(() => import('./pages/ManagerRegistration'));
 const ManagerInvitationPage = lazy(() => import('./pages/Admin/ManagerInvitationPage'));
 const BankAccountManagement = lazy(() => import('./pages/Admin/BankAccountManagement'));
@@ -49,12 +52,14 @@ function App() {
             <Route index element={<Landing />} />
             <Route path="about" element={<About />} />
             <Route path="contact" element={<Contact />} />
+            <Route path="listings" element={<Listings />} />
             <Route path="login" element={<Login />} />
             <Route path="access-denied" element={<AccessDenied />} />
             <Route path="debug" element={<DebugAuth />} />
             <Route path="test-modal" element={<TestModal />} />
             <Route path="sign/:signingId" element={<ContractSigningPage />} />
             <Route path="file-upload" element={<FileUploadPage />} />
+            <Route path="notification-preferences" element={<NotificationPreferencesPage />} />
           </Route>
           
           {/* Protected Routes (require authentication) */}
@@ -83,6 +88,9 @@ function App() {
             <Route path="payments" element={<MainLayout />}>
               <Route index element={<Payments />} />
             </Route>
+            <Route path="deposits" element={<MainLayout />}>
+              <Route index element={<Deposits />} />
+            </Route>
             <Route path="rental-history" element={<MainLayout />}>
               <Route index element={<RentalHistory />} />
             </Route>
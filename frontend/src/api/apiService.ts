@@ -1,5 +1,5 @@
 import axios from 'axios';
-import type { Person, Property, Rental, BankAccount, MaintenanceRequest, RentPayment, RentalHistory, User, Pricing } from '../types';
+import type { Person, Property, PropertyPhoto, Rental, BankAccount, MaintenanceRequest, RentPayment, RentalHistory, User, Pricing, Reglamento, RentalFile, TrashedFile } from '../types';
 
 // Base API URL - automatically proxied through Vite to backend in development
 // In production, use the actual backend URL deployed on Fly.io
@@ -88,8 +88,39 @@ apiClient.interceptors.response.use(
   }
 );
 
+// Paginación, orden y filtros estándar de los listados; el total llega en X-Total-Count
+export interface ListParams {
+  limit?: number;
+  offset?: number;
+  sort?: string; // Columna, con - al inicio para orden descendente
+  filter?: Record<string, string>;
+}
+
+export interface Page<T> {
+  items: T[];
+  total: number;
+}
+
+const listQuery = (params?: ListParams): Record<string, string | number> => {
+  const query: Record<string, string | number> = {};
+  if (params?.limit !== undefined) query.limit = params.limit;
+  if (params?.offset !== undefined) query.offset = params.offset;
+  if (params?.sort) query.sort = params.sort;
+  Object.entries(params?.filter ?? {}).forEach(([column, value]) => {
+    query[`filter[${column}]`] = value;
+  });
+  return query;
+};
+
+const getPage = async <T>(url: string, params?: ListParams, extra?: object): Promise<Page<T>> => {
+  const response = await apiClient.get(url, { params: { ...extra, ...listQuery(params) } });
+  const total = Number(response.headers['x-total-count']);
+  return { items: response.data, total: Number.isNaN(total) ? response.data.length : total };
+};
+
 // Person API
 export const personApi = {
+  getPage: (params?: ListParams): Promise<Page<Person>> => getPage<Person>('/persons', params),
   getAll: async (): Promise<Person[]> => {
     const response = await apiClient.get('/persons');
     return response.data;
@@ -115,10 +146,50 @@ export const personApi = {
   },
 };
 
+// Filtros de características de los inmuebles
+export interface PropertyFilters {
+  city?: string;
+  min_bedrooms?: number;
+  min_bathrooms?: number;
+  min_area?: number;
+  max_area?: number;
+  estrato?: number;
+  parking?: boolean;
+  furnished?: boolean;
+  pets_allowed?: boolean;
+}
+
+// Línea de tiempo de ocupación de un inmueble
+export interface OccupancyPeriod {
+  rental_id: string;
+  renter_id: string;
+  tenant_name: string;
+  start_date: string;
+  end_date: string;
+  monthly_rent: number;
+  status: string; // active, upcoming, ended o el estado del historial (renewed, transferred...)
+  end_reason?: string;
+  days: number;
+}
+
+export interface OccupancyTimeline {
+  property_id: string;
+  from: string;
+  to: string;
+  periods: OccupancyPeriod[];
+  gaps: { start_date: string; end_date: string; days: number }[];
+  total_days: number;
+  occupied_days: number;
+  vacant_days: number;
+  occupancy_rate: number;
+}
+
 // Property API
 export const propertyApi = {
-  getAll: async (): Promise<Property[]> => {
-    const response = await apiClient.get('/properties');
+  getPage: (params?: ListParams, filters?: PropertyFilters): Promise<Page<Property>> =>
+    getPage<Property>('/properties', params, filters),
+  getAll: async (filters?: PropertyFilters): Promise<Property[]> => {
+    const response = await apiClient.get('/properties', { params: filters });
     return response.data;
   },
   
@@ -156,10 +227,40 @@ export const propertyApi = {
   delete: async (id: string): Promise<void> => {
     await apiClient.delete(`/properties/${id}`);
   },
+
+  getOccupancy: async (id: string, range?: { from?: string; to?: string }): Promise<OccupancyTimeline> => {
+    const response = await apiClient.get(`/properties/${id}/occupancy`, { params: range });
+    return response.data;
+  },
+
+  // Galería de fotos del inmueble
+  uploadPhoto: async (id: string, file: File, caption?: string): Promise<PropertyPhoto> => {
+    const formData = new FormData();
+    formData.append('file', file);
+    if (caption) formData.append('caption', caption);
+    const response = await apiClient.post(`/admin/properties/${id}/photos`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+  reorderPhotos: async (id: string, photoIds: string[]): Promise<PropertyPhoto[]> => {
+    const response = await apiClient.put(`/admin/properties/${id}/photos/order`, { photo_ids: photoIds });
+    return response.data;
+  },
+  updatePhotoCaption: async (id: string, photoId: string, caption: string): Promise<PropertyPhoto> => {
+    const response = await apiClient.put(`/admin/properties/${id}/photos/${photoId}`, { caption });
+    return response.data;
+  },
+  deletePhoto: async (id: string, photoId: string): Promise<PropertyPhoto[]> => {
+    const response = await apiClient.delete(`/admin/properties/${id}/photos/${photoId}`);
+    return response.data;
+  },
 };
 
 // Maintenance Request API
 export const maintenanceRequestApi = {
+  getPage: (params?: ListParams): Promise<Page<MaintenanceRequest>> =>
+    getPage<MaintenanceRequest>('/maintenance-requests', params),
   getAll: async (): Promise<MaintenanceRequest[]> => {
     const response = await apiClient.get('/maintenance-requests');
     return response.data;
@@ -293,6 +394,30 @@ export const rentalApi = {
   delete: async (id: string): Promise<void> => {
     await apiClient.delete(`/rentals/${id}`);
   },
+  getFiles: async (id: string): Promise<RentalFile[]> => {
+    const response = await apiClient.get(`/rentals/${id}/files`);
+    return response.data;
+  },
+  downloadFile: async (id: string, fileId: string): Promise<Blob> => {
+    const response = await apiClient.get(`/rentals/${id}/files/${fileId}`, { responseType: 'blob' });
+    return response.data;
+  },
+};
+
+// File trash API (admins)
+export const fileTrashApi = {
+  getAll: async (): Promise<{ retention_days: number; files: TrashedFile[] }> => {
+    const response = await apiClient.get('/admin/file-upload/trash');
+    return response.data;
+  },
+  restore: async (id: string) => {
+    const response = await apiClient.post(`/admin/file-upload/trash/${id}/restore`);
+    return response.data;
+  },
+  purge: async (id: string) => {
+    const response = await apiClient.delete(`/admin/file-upload/trash/${id}`);
+    return response.data;
+  },
 };
 
 // Bank Account API
@@ -322,6 +447,35 @@ export const bankAccountApi = {
   },
 };
 
+// Reglamento (manual de convivencia) API
+export const reglamentoApi = {
+  getPending: async (): Promise<Reglamento[]> => {
+    const response = await apiClient.get('/reglamentos/pending');
+    return response.data;
+  },
+  getPdf: async (id: string): Promise<Blob> => {
+    const response = await apiClient.get(`/reglamentos/${id}/pdf`, { responseType: 'blob' });
+    return response.data;
+  },
+  acknowledge: async (id: string) => {
+    const response = await apiClient.post(`/reglamentos/${id}/acknowledge`);
+    return response.data;
+  },
+  upload: async (propertyId: string, file: File): Promise<Reglamento> => {
+    const formData = new FormData();
+    formData.append('property_id', propertyId);
+    formData.append('file', file);
+    const response = await apiClient.post('/reglamentos', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+  getCompliance: async () => {
+    const response = await apiClient.get('/reglamentos/compliance');
+    return response.data;
+  },
+};
+
 // Rent Payment API
 export const rentPaymentApi = {
   getAll: async (): Promise<RentPayment[]> => {
@@ -413,10 +567,715 @@ export const rentPaymentApi = {
   delete: async (id: string): Promise<void> => {
     await apiClient.delete(`/payments/${id}`);
   },
+
+  // Factura electrónica DIAN del pago (solo admin). Reenvía las rechazadas o no enviadas.
+  issueInvoice: async (id: string): Promise<RentPayment> => {
+    const response = await apiClient.post(`/admin/payments/${id}/einvoice`);
+    return response.data;
+  },
+
+  refreshInvoice: async (id: string): Promise<RentPayment> => {
+    const response = await apiClient.post(`/admin/payments/${id}/einvoice/refresh`);
+    return response.data;
+  },
+
+  // Certificado anual de pagos del arrendatario autenticado (PDF firmado y verificable por QR)
+  getMyStatement: async (year: number): Promise<Blob> => {
+    const response = await apiClient.get('/my/payments/statement', { params: { year }, responseType: 'blob' });
+    return response.data;
+  },
+};
+
+// Pago en línea del arriendo del mes (Wompi: tarjetas, PSE, Nequi)
+export type PaymentCheckoutStatus = 'pending' | 'approved' | 'declined' | 'voided' | 'error';
+
+export interface PaymentCheckout {
+  id: string;
+  reference: string;
+  rental_id: string;
+  period: string;
+  amount_in_cents: number;
+  currency: string;
+  status: PaymentCheckoutStatus;
+  payment_method?: string;
+  rent_payment_id?: string;
+  created_at: string;
+  completed_at?: string;
+}
+
+export const paymentCheckoutApi = {
+  create: async (): Promise<{ checkout: PaymentCheckout; checkout_url: string }> => {
+    const response = await apiClient.post('/payment-checkouts');
+    return response.data;
+  },
+
+  getByReference: async (reference: string): Promise<PaymentCheckout> => {
+    const response = await apiClient.get(`/payment-checkouts/${encodeURIComponent(reference)}`);
+    return response.data;
+  },
+};
+
+// Depósito en garantía del arrendamiento, sus descuentos y la liquidación firmada al entregar el inmueble
+export type SecurityDepositStatus = 'held' | 'settlement_issued' | 'signed' | 'refunded';
+
+export interface DepositDeduction {
+  id: string;
+  category: 'damages' | 'unpaid_rent' | 'utilities' | 'cleaning' | 'other';
+  reason: string;
+  amount: number;
+  receipt_path?: string;
+  receipt_url?: string;
+  created_at: string;
+}
+
+export interface SecurityDeposit {
+  id: string;
+  rental_id: string;
+  amount: number;
+  held_by: 'owner' | 'manager' | 'escrow';
+  bank_account_id?: string;
+  received_at: string;
+  notes?: string;
+  deductions: DepositDeduction[];
+  status: SecurityDepositStatus;
+  move_out_date?: string;
+  settlement_issued_at?: string;
+  signed_at?: string;
+  refunded_at?: string;
+  refund_reference?: string;
+}
+
+export interface SecurityDepositSummary {
+  deposit: SecurityDeposit;
+  total_deductions: number;
+  refund_amount: number;
+  balance_owed: number;
+  status_translated: string;
+}
+
+export const depositApi = {
+  getMine: async (): Promise<SecurityDepositSummary[]> => {
+    const response = await apiClient.get('/deposits/me');
+    return response.data;
+  },
+
+  getSettlement: async (id: string): Promise<Blob> => {
+    const response = await apiClient.get(`/deposits/${id}/settlement`, { responseType: 'blob' });
+    return response.data;
+  },
+
+  signSettlement: async (id: string, location?: SignerLocation): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.post(`/deposits/${id}/settlement/sign`, location);
+    return response.data;
+  },
+
+  getByRental: async (rentalId: string): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.get(`/admin/contracts/${rentalId}/deposit`);
+    return response.data;
+  },
+
+  create: async (rentalId: string, data: { amount?: number; held_by?: string; bank_account_id?: string; received_at?: string; notes?: string }): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.post(`/admin/contracts/${rentalId}/deposit`, data);
+    return response.data;
+  },
+
+  addDeduction: async (id: string, deduction: { category: string; reason: string; amount: number; file?: File }): Promise<SecurityDepositSummary> => {
+    const formData = new FormData();
+    formData.append('category', deduction.category);
+    formData.append('reason', deduction.reason);
+    formData.append('amount', String(deduction.amount));
+    if (deduction.file) {
+      formData.append('file', deduction.file);
+    }
+    const response = await apiClient.post(`/admin/deposits/${id}/deductions`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+
+  removeDeduction: async (id: string, deductionId: string): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.delete(`/admin/deposits/${id}/deductions/${deductionId}`);
+    return response.data;
+  },
+
+  issueSettlement: async (id: string, moveOutDate: string): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.post(`/admin/deposits/${id}/settlement`, { move_out_date: moveOutDate });
+    return response.data;
+  },
+
+  refund: async (id: string, reference: string, refundedAt?: string): Promise<SecurityDepositSummary> => {
+    const response = await apiClient.post(`/admin/deposits/${id}/refund`, { reference, refunded_at: refundedAt });
+    return response.data;
+  },
+};
+
+export interface InspectionPhoto {
+  path: string;
+  url?: string;
+  caption?: string;
+}
+
+export interface InspectionItem {
+  name: string;
+  quantity: number;
+  condition: string;
+  notes?: string;
+  photos?: InspectionPhoto[];
+}
+
+export interface InspectionRoom {
+  name: string;
+  items: InspectionItem[];
+  photos?: InspectionPhoto[];
+}
+
+export interface InspectionTemplate {
+  id: string;
+  name: string;
+  description?: string;
+  rooms: InspectionRoom[];
+}
+
+export interface Inspection {
+  id: string;
+  rental_id: string;
+  kind: 'move_in' | 'move_out';
+  template_id?: string;
+  inspected_at: string;
+  inspector_id: string;
+  rooms: InspectionRoom[];
+  notes?: string;
+  status: 'draft' | 'pending_acknowledgment' | 'acknowledged';
+  signing_id?: string;
+  acknowledged_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface InspectionItemComparison {
+  room: string;
+  item: string;
+  move_in_quantity: number;
+  move_out_quantity: number;
+  move_in_condition?: string;
+  move_out_condition?: string;
+  move_in_notes?: string;
+  move_out_notes?: string;
+  move_out_photos?: InspectionPhoto[];
+  change: 'unchanged' | 'improved' | 'worsened' | 'missing' | 'added';
+}
+
+export interface InspectionComparison {
+  rental_id: string;
+  move_in: Inspection;
+  move_out: Inspection;
+  items: InspectionItemComparison[];
+  worsened_count: number;
+  missing_count: number;
+}
+
+export const inspectionApi = {
+  getTemplates: async (): Promise<{ templates: InspectionTemplate[]; default: InspectionTemplate }> => {
+    const response = await apiClient.get('/admin/inspection-templates');
+    return response.data;
+  },
+
+  saveTemplate: async (template: { id?: string; name: string; description?: string; rooms: InspectionRoom[] }): Promise<InspectionTemplate> => {
+    const { id, ...data } = template;
+    const response = id
+      ? await apiClient.put(`/admin/inspection-templates/${id}`, data)
+      : await apiClient.post('/admin/inspection-templates', data);
+    return response.data;
+  },
+
+  deleteTemplate: async (id: string): Promise<void> => {
+    await apiClient.delete(`/admin/inspection-templates/${id}`);
+  },
+
+  getByRental: async (rentalId: string): Promise<Inspection[]> => {
+    const response = await apiClient.get(`/admin/contracts/${rentalId}/inspections`);
+    return response.data;
+  },
+
+  create: async (rentalId: string, data: { kind: 'move_in' | 'move_out'; template_id?: string; inspected_at?: string; notes?: string }): Promise<Inspection> => {
+    const response = await apiClient.post(`/admin/contracts/${rentalId}/inspections`, data);
+    return response.data;
+  },
+
+  update: async (id: string, data: { rooms: InspectionRoom[]; notes?: string; inspected_at?: string }): Promise<Inspection> => {
+    const response = await apiClient.put(`/admin/inspections/${id}`, data);
+    return response.data;
+  },
+
+  remove: async (id: string): Promise<void> => {
+    await apiClient.delete(`/admin/inspections/${id}`);
+  },
+
+  uploadPhoto: async (id: string, file: File, caption?: string): Promise<InspectionPhoto> => {
+    const formData = new FormData();
+    formData.append('file', file);
+    if (caption) {
+      formData.append('caption', caption);
+    }
+    const response = await apiClient.post(`/admin/inspections/${id}/photos`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+
+  getPDF: async (id: string): Promise<Blob> => {
+    const response = await apiClient.get(`/admin/inspections/${id}/pdf`, { responseType: 'blob' });
+    return response.data;
+  },
+
+  requestAcknowledgment: async (id: string): Promise<{ signing_id: string; expires_at: string }> => {
+    const response = await apiClient.post(`/admin/inspections/${id}/acknowledgment`);
+    return response.data;
+  },
+
+  getComparison: async (rentalId: string): Promise<InspectionComparison> => {
+    const response = await apiClient.get(`/admin/contracts/${rentalId}/inspections/comparison`);
+    return response.data;
+  },
+
+  getComparisonPDF: async (rentalId: string): Promise<Blob> => {
+    const response = await apiClient.get(`/admin/contracts/${rentalId}/inspections/comparison/pdf`, { responseType: 'blob' });
+    return response.data;
+  },
+};
+
+export interface ListingPhoto {
+  path?: string;
+  url?: string;
+  caption?: string;
+}
+
+export interface PublicListing {
+  id: string;
+  city: string;
+  title: string;
+  description?: string;
+  monthly_rent: number;
+  admin_fee: number;
+  deposit_amount: number;
+  min_term_months: number;
+  available_from?: string;
+  bedrooms: number;
+  bathrooms: number;
+  area_m2: number;
+  pets_allowed: boolean;
+  photos: ListingPhoto[];
+  published_at?: string;
+}
+
+export interface PropertyListing extends PublicListing {
+  property_id: string;
+  status: 'available' | 'paused' | 'rented';
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ListingApplication {
+  id: string;
+  listing_id: string;
+  full_name: string;
+  email: string;
+  phone: string;
+  document_number?: string;
+  occupation?: string;
+  monthly_income: number;
+  occupants: number;
+  desired_move_in?: string;
+  message?: string;
+  status: 'new' | 'contacted' | 'approved' | 'rejected';
+  manager_notes?: string;
+  created_at: string;
+}
+
+export interface ListingApplicationInput {
+  full_name: string;
+  email: string;
+  phone: string;
+  document_number?: string;
+  occupation?: string;
+  monthly_income?: number;
+  occupants?: number;
+  desired_move_in?: string;
+  message?: string;
+}
+
+export const listingApi = {
+  getPublic: async (filters?: { city?: string; min_price?: number; max_price?: number }): Promise<PublicListing[]> => {
+    const response = await publicApiClient.get('/public/listings', { params: filters });
+    return response.data;
+  },
+
+  getPublicById: async (id: string): Promise<PublicListing> => {
+    const response = await publicApiClient.get(`/public/listings/${id}`);
+    return response.data;
+  },
+
+  apply: async (id: string, application: ListingApplicationInput): Promise<{ id: string; message: string }> => {
+    const response = await publicApiClient.post(`/public/listings/${id}/applications`, application);
+    return response.data;
+  },
+
+  getAll: async (): Promise<PropertyListing[]> => {
+    const response = await apiClient.get('/admin/listings');
+    return response.data;
+  },
+
+  save: async (propertyId: string, data: Omit<PublicListing, 'id' | 'city' | 'photos' | 'published_at'>): Promise<PropertyListing> => {
+    const response = await apiClient.put(`/admin/listings/property/${propertyId}`, data);
+    return response.data;
+  },
+
+  updateStatus: async (id: string, status: PropertyListing['status']): Promise<PropertyListing> => {
+    const response = await apiClient.put(`/admin/listings/${id}/status`, { status });
+    return response.data;
+  },
+
+  remove: async (id: string): Promise<void> => {
+    await apiClient.delete(`/admin/listings/${id}`);
+  },
+
+  uploadPhoto: async (id: string, file: File, caption?: string): Promise<PropertyListing> => {
+    const formData = new FormData();
+    formData.append('file', file);
+    if (caption) {
+      formData.append('caption', caption);
+    }
+    const response = await apiClient.post(`/admin/listings/${id}/photos`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+    return response.data;
+  },
+
+  removePhoto: async (id: string, path: string): Promise<PropertyListing> => {
+    const response = await apiClient.delete(`/admin/listings/${id}/photos`, { params: { path } });
+    return response.data;
+  },
+
+  getApplications: async (id: string): Promise<ListingApplication[]> => {
+    const response = await apiClient.get(`/admin/listings/${id}/applications`);
+    return response.data;
+  },
+
+  updateApplication: async (id: string, status: ListingApplication['status'], managerNotes?: string): Promise<ListingApplication> => {
+    const response = await apiClient.put(`/admin/listing-applications/${id}`, { status, manager_notes: managerNotes });
+    return response.data;
+  },
+};
+
+export type RentalPartyRole = 'renter' | 'co_signer' | 'witness' | 'owner';
+
+export interface RentalParty {
+  id: string;
+  rental_id: string;
+  person_id: string;
+  role: RentalPartyRole;
+  role_label: string;
+  person?: Person;
+  email?: string;
+  created_at: string;
+}
+
+export const rentalPartyApi = {
+  getByRental: async (rentalId: string): Promise<RentalParty[]> => {
+    const response = await apiClient.get(`/admin/contracts/${rentalId}/parties`);
+    return response.data;
+  },
+
+  add: async (rentalId: string, personId: string, role: RentalPartyRole): Promise<RentalParty> => {
+    const response = await apiClient.post(`/admin/contracts/${rentalId}/parties`, { person_id: personId, role });
+    return response.data;
+  },
+
+  remove: async (rentalId: string, partyId: string): Promise<void> => {
+    await apiClient.delete(`/admin/contracts/${rentalId}/parties/${partyId}`);
+  },
+};
+
+// Copropiedades (edificios) con su cuota de administración y administrador
+export interface Building {
+  id: string;
+  name: string;
+  address: string;
+  city: string;
+  nit?: string;
+  admin_fee: number;
+  administrator_name?: string;
+  administrator_phone?: string;
+  administrator_email?: string;
+  notes?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export type BuildingInput = Omit<Building, 'id' | 'created_at' | 'updated_at'>;
+
+export interface BuildingDetail extends Building {
+  properties: Property[];
+  reglamento?: { id: string; version: number; file_name: string; uploaded_at: string };
+}
+
+export const buildingApi = {
+  getAll: async (): Promise<Building[]> => {
+    const response = await apiClient.get('/admin/buildings');
+    return response.data;
+  },
+  getById: async (id: string): Promise<BuildingDetail> => {
+    const response = await apiClient.get(`/admin/buildings/${id}`);
+    return response.data;
+  },
+  create: async (building: BuildingInput): Promise<Building> => {
+    const response = await apiClient.post('/admin/buildings', building);
+    return response.data;
+  },
+  update: async (id: string, building: BuildingInput): Promise<Building> => {
+    const response = await apiClient.put(`/admin/buildings/${id}`, building);
+    return response.data;
+  },
+  delete: async (id: string): Promise<void> => {
+    await apiClient.delete(`/admin/buildings/${id}`);
+  },
+  addProperty: async (id: string, propertyId: string): Promise<Property> => {
+    const response = await apiClient.put(`/admin/buildings/${id}/properties/${propertyId}`);
+    return response.data;
+  },
+  removeProperty: async (id: string, propertyId: string): Promise<void> => {
+    await apiClient.delete(`/admin/buildings/${id}/properties/${propertyId}`);
+  },
+};
+
+// Resumen del tablero: todos los inmuebles para administradores, los gestionados para managers
+export interface DashboardSummary {
+  scope: 'all' | 'managed';
+  properties: number;
+  active_rentals: number;
+  vacancies: number;
+  pending_signatures: number;
+  overdue_payments: number;
+  open_maintenance: number;
+  expiring_contracts: number;
+  expiration_window_days: number;
+  generated_at: string;
+}
+
+export const dashboardApi = {
+  getSummary: async (): Promise<DashboardSummary> => {
+    const response = await apiClient.get('/dashboard/summary');
+    return response.data;
+  },
+};
+
+// Consultas GraphQL de solo lectura (personas, inmuebles, arriendos, pagos y mantenimiento),
+// limitadas a lo que el rol del usuario puede ver
+export interface GraphQLError {
+  message: string;
+  locations?: { line: number; column: number }[];
+  path?: (string | number)[];
+}
+
+export interface GraphQLResponse<T> {
+  data: T | null;
+  errors?: GraphQLError[];
+}
+
+export const graphqlApi = {
+  // Devuelve la respuesta completa: data puede venir junto con errores de campos puntuales
+  query: async <T>(query: string, variables?: Record<string, unknown>, operationName?: string): Promise<GraphQLResponse<T>> => {
+    const response = await apiClient.post('/graphql', { query, variables, operationName });
+    return response.data;
+  },
+};
+
+// Búsqueda de texto en personas, inmuebles, arriendos y archivos, según el rol del usuario
+export type SearchResultType = 'person' | 'property' | 'rental' | 'file';
+
+export interface SearchResult {
+  type: SearchResultType;
+  id: string;
+  title: string;
+  subtitle?: string;
+  property_id?: string;
+  rental_id?: string;
+  person_id?: string;
+  rank: number;
+}
+
+export const searchApi = {
+  search: async (q: string, options?: { types?: SearchResultType[]; limit?: number }): Promise<SearchResult[]> => {
+    const response = await apiClient.get('/search', {
+      params: { q, types: options?.types?.join(','), limit: options?.limit },
+    });
+    return response.data;
+  },
+};
+
+// Intentos de inicio de sesión recientes (admins), los fallidos por defecto
+export interface LoginAttempt {
+  id: string;
+  email: string;
+  ip_address: string;
+  success: boolean;
+  failure_reason?: 'unknown_email' | 'wrong_password' | 'inactive' | 'locked';
+  user_agent?: string;
+  created_at: string;
+}
+
+export const loginAttemptApi = {
+  getRecent: async (params?: { email?: string; ip?: string; failed?: boolean; hours?: number; limit?: number }): Promise<LoginAttempt[]> => {
+    const response = await apiClient.get('/admin/login-attempts', { params });
+    return response.data;
+  },
+};
+
+// Recuperación de contraseña mediante un enlace enviado por email
+export const passwordResetApi = {
+  forgotPassword: async (email: string): Promise<{ success: boolean; message: string }> => {
+    const response = await apiClient.post('/auth/forgot-password', { email });
+    return response.data;
+  },
+  resetPassword: async (token: string, newPassword: string): Promise<{ success: boolean; message: string }> => {
+    const response = await apiClient.post('/auth/reset-password', { token, new_password: newPassword });
+    return response.data;
+  },
+};
+
+// Registro de nuevos usuarios, que inician sesión tras verificar su email
+export const registrationApi = {
+  register: async (data: { name: string; email: string; password: string; phone?: string }): Promise<{ success: boolean; message: string; user: { id: string; email: string; status: string } }> => {
+    const response = await apiClient.post('/auth/register', data);
+    return response.data;
+  },
+  verifyEmail: async (token: string): Promise<{ success: boolean; message: string }> => {
+    const response = await apiClient.post('/auth/verify-email', { token });
+    return response.data;
+  },
+  resendVerification: async (email: string): Promise<{ success: boolean; message: string }> => {
+    const response = await apiClient.post('/auth/resend-verification', { email });
+    return response.data;
+  },
+};
+
+// Sesiones activas del usuario autenticado, que puede cerrar desde otro dispositivo
+export interface UserSession {
+  id: string;
+  user_id: string;
+  ip_address: string;
+  user_agent?: string;
+  device?: string;
+  created_at: string;
+  last_seen_at: string;
+  expires_at: string;
+  current: boolean;
+}
+
+export const sessionApi = {
+  getMine: async (): Promise<UserSession[]> => {
+    const response = await apiClient.get('/users/me/sessions');
+    return response.data;
+  },
+  revoke: async (id: string): Promise<void> => {
+    await apiClient.delete(`/users/me/sessions/${id}`);
+  },
+};
+
+// Suplantación de usuarios por los administradores y registro de auditoría
+export interface AuditLogEntry {
+  id: string;
+  action: string;
+  entity: string;
+  entity_id: string;
+  changed_by: string;
+  impersonated_by?: string;
+  timestamp: string;
+  details: any;
+}
+
+export const impersonationApi = {
+  start: async (userId: string, reason?: string): Promise<{ token: string; expires_at: string; session_id: string; impersonator_id: string; user: User }> => {
+    const response = await apiClient.post(`/admin/impersonate/${userId}`, { reason });
+    return response.data;
+  },
+  getAuditLog: async (params?: { changed_by?: string; impersonated_by?: string; impersonated?: boolean; hours?: number; limit?: number }): Promise<AuditLogEntry[]> => {
+    const response = await apiClient.get('/admin/audit-log', { params });
+    return response.data;
+  },
+};
+
+// Invitaciones de arrendatarios y coadministradores
+export interface Invitation {
+  id: string;
+  email: string;
+  role: 'manager' | 'resident';
+  person_id: string;
+  organization_id?: string;
+  property_id?: string;
+  message?: string;
+  invited_by: string;
+  created_at: string;
+  expires_at: string;
+  accepted_at?: string;
+  revoked_at?: string;
+  user_id?: string;
+  status?: 'pending' | 'accepted' | 'revoked' | 'expired';
+}
+
+export const invitationApi = {
+  send: async (data: { email: string; role: 'manager' | 'resident'; name?: string; person_id?: string; organization_id?: string; property_id?: string; message?: string }): Promise<Invitation> => {
+    const response = await apiClient.post('/admin/invitations', data);
+    return response.data;
+  },
+  getAll: async (): Promise<Invitation[]> => {
+    const response = await apiClient.get('/admin/invitations');
+    return response.data;
+  },
+  revoke: async (id: string): Promise<void> => {
+    await apiClient.delete(`/admin/invitations/${id}`);
+  },
+  open: async (token: string): Promise<{ email: string; role: string; name?: string; organization?: string; property_address?: string; expires_at: string }> => {
+    const response = await apiClient.get(`/invitations/${encodeURIComponent(token)}`);
+    return response.data;
+  },
+  accept: async (token: string, password: string): Promise<{ success: boolean; message: string; user: User }> => {
+    const response = await apiClient.post('/invitations/accept', { token, password });
+    return response.data;
+  },
+};
+
+// Notificaciones dentro de la aplicación (campana): pagos, firmas y mantenimiento
+export interface AppNotification {
+  id: string;
+  user_id: string;
+  type: 'payment_received' | 'signature_completed' | 'maintenance_updated';
+  title: string;
+  message: string;
+  entity_id?: string;
+  created_at: string;
+  read_at?: string;
+}
+
+export const notificationApi = {
+  getPage: (params?: { limit?: number; offset?: number; unread?: boolean }): Promise<Page<AppNotification>> =>
+    getPage<AppNotification>('/notifications', { limit: params?.limit, offset: params?.offset }, params?.unread ? { unread: true } : undefined),
+  getUnreadCount: async (): Promise<number> => {
+    const response = await apiClient.get('/notifications/unread-count');
+    return response.data.unread;
+  },
+  markRead: async (id: string): Promise<void> => {
+    await apiClient.put(`/notifications/${id}/read`);
+  },
+  markAllRead: async (): Promise<number> => {
+    const response = await apiClient.put('/notifications/read-all');
+    return response.data.marked;
+  },
 };
 
 // Rental History API
 export const rentalHistoryApi = {
+  getPage: (params?: ListParams): Promise<Page<RentalHistory>> => getPage<RentalHistory>('/rental-history', params),
   getAll: async (filters?: { status?: string; startDate?: string; endDate?: string }): Promise<RentalHistory[]> => {
     const response = await apiClient.get('/rental-history', { params: filters });
     return response.data;
@@ -487,6 +1346,7 @@ export const rentalHistoryApi = {
 
 // User API
 export const userApi = {
+  getPage: (params?: ListParams): Promise<Page<User>> => getPage<User>('/users', params),
   getAll: async (): Promise<User[]> => {
     const response = await apiClient.get('/users');
     return response.data;
@@ -571,6 +1431,13 @@ export const utilityApi = {
   }
 };
 
+// Location reported by the signer's browser, kept as evidence of the signature
+export interface SignerLocation {
+  latitude: number;
+  longitude: number;
+  accuracy?: number;
+}
+
 // Contract Signing API
 export const contractSigningApi = {
   // Request a signature for a contract (requires authentication)
@@ -585,16 +1452,79 @@ export const contractSigningApi = {
     const response = await publicApiClient.get(`/public/contract-signing/status/${signingId}`);
     return response.data;
   },
+
+  // Subscribe to the status changes of a signature request while the page is open. The
+  // browser reconnects by itself; call the returned function to stop listening.
+  watchSignatureStatus: (signingId: string, onStatus: (status: any) => void): (() => void) => {
+    const source = new EventSource(`${API_URL}/public/contract-signing/status/${signingId}/stream`);
+    source.addEventListener('status', (event) => {
+      const status = JSON.parse((event as MessageEvent).data);
+      onStatus(status);
+      if (status.status !== 'pending') source.close();
+    });
+    return () => source.close();
+  },
   
-  // Sign a contract
-  signContract: async (signingId: string) => {
-    const response = await publicApiClient.post(`/public/contract-signing/sign/${signingId}`);
+  // Send the one-time code required to sign a contract to the recipient
+  sendSigningCode: async (signingId: string, channel: 'email' | 'sms' = 'email') => {
+    const response = await publicApiClient.post(`/public/contract-signing/otp/${signingId}`, { channel });
+    return response.data;
+  },
+
+  // Sign a contract with the one-time code sent to the recipient
+  signContract: async (signingId: string, otp: string, location?: SignerLocation) => {
+    const response = await publicApiClient.post(`/public/contract-signing/sign/${signingId}`, { otp, ...location });
     return response.data;
   },
   
   // Reject a contract
-  rejectContract: async (signingId: string) => {
-    const response = await publicApiClient.post(`/public/contract-signing/reject/${signingId}`);
+  rejectContract: async (signingId: string, location?: SignerLocation) => {
+    const response = await publicApiClient.post(`/public/contract-signing/reject/${signingId}`, { ...location });
     return response.data;
   }
-}; 
\ No newline at end of file
+}; 
+export type NotificationChannel = 'email' | 'sms';
+export type NotificationType = 'rent_reminder' | 'anniversary_reminder' | 'renewal_reminder';
+export type NotificationFrequency = 'always' | 'monthly' | 'never';
+
+export interface NotificationPreference {
+  channel: NotificationChannel;
+  type: NotificationType;
+  frequency: NotificationFrequency;
+  last_sent_at?: string;
+}
+
+export interface NotificationPreferencesResponse {
+  preferences: NotificationPreference[];
+  sms_available: boolean;
+}
+
+export const notificationPreferenceApi = {
+  // Preferences of the current user
+  getMine: async (): Promise<NotificationPreferencesResponse> => {
+    const response = await apiClient.get('/notification-preferences/me');
+    return response.data;
+  },
+
+  updateMine: async (preferences: NotificationPreference[]): Promise<NotificationPreferencesResponse> => {
+    const response = await apiClient.put('/notification-preferences/me', { preferences });
+    return response.data;
+  },
+
+  // Public endpoints - authenticated by the token of the links in the emails
+  getByToken: async (token: string): Promise<NotificationPreferencesResponse> => {
+    const response = await publicApiClient.get(`/public/notification-preferences/${token}`);
+    return response.data;
+  },
+
+  updateByToken: async (token: string, preferences: NotificationPreference[]): Promise<NotificationPreferencesResponse> => {
+    const response = await publicApiClient.put(`/public/notification-preferences/${token}`, { preferences });
+    return response.data;
+  },
+
+  // Stops the emails of a type, or all notification emails when type is empty
+  unsubscribe: async (token: string, type?: NotificationType): Promise<NotificationPreferencesResponse> => {
+    const response = await publicApiClient.post(`/public/notification-preferences/${token}/unsubscribe`, { type });
+    return response.data;
+  }
+};
@@ -0,0 +1,2538 @@
+// Code generated by backend/sdk/gen from the spec served at /api/docs/openapi.json; DO NOT EDIT.
+
+import { ApiClientBase, paginate } from '../sdkRuntime';
+
+export interface AuditLog {
+  action?: string;
+  changed_by?: string;
+  details?: unknown;
+  entity?: string;
+  entity_id?: string;
+  id?: string;
+  impersonated_by?: string;
+  timestamp?: string;
+}
+
+export interface BankAccount {
+  account_holder?: string;
+  account_number?: string;
+  account_type?: string;
+  bank_name?: string;
+  id?: string;
+  person_id?: string;
+}
+
+export interface BucketBackup {
+  copied?: number;
+  error?: string;
+  failed?: number;
+  file_count?: number;
+  finished_at?: string;
+  id?: string;
+  manifest?: BucketBackupEntry[];
+  manifest_locations?: Record<string, string>;
+  mode?: string;
+  providers?: string[];
+  started_at?: string;
+  status?: string;
+  total_size?: number;
+}
+
+export interface BucketBackupEntry {
+  error?: string;
+  locations?: Record<string, string>;
+  path?: string;
+  sha256?: string;
+  size?: number;
+  updated_at?: string;
+}
+
+export interface Building {
+  address?: string;
+  admin_fee?: number;
+  administrator_email?: string;
+  administrator_name?: string;
+  administrator_phone?: string;
+  city?: string;
+  created_at?: string;
+  id?: string;
+  name?: string;
+  nit?: string;
+  notes?: string;
+  updated_at?: string;
+}
+
+export interface BuildingRequest {
+  address?: string;
+  admin_fee?: number;
+  administrator_email?: string;
+  administrator_name?: string;
+  administrator_phone?: string;
+  city?: string;
+  name?: string;
+  nit?: string;
+  notes?: string;
+}
+
+export interface BuildingResponse {
+  address?: string;
+  admin_fee?: number;
+  administrator_email?: string;
+  administrator_name?: string;
+  administrator_phone?: string;
+  city?: string;
+  created_at?: string;
+  id?: string;
+  name?: string;
+  nit?: string;
+  notes?: string;
+  properties?: Property[];
+  reglamento?: Reglamento;
+  updated_at?: string;
+}
+
+export interface Capabilities {
+  features?: Record<string, boolean>;
+  file_storage_backend?: string;
+}
+
+export interface CreateChunkedUploadRequest {
+  category?: string;
+  content_type?: string;
+  contract_id?: string;
+  file_name?: string;
+  rental_id?: string;
+  size?: number;
+  tags?: string[];
+  token?: string;
+}
+
+export interface CreateInspectionRequest {
+  inspected_at?: string;
+  kind?: string;
+  notes?: string;
+  template_id?: string;
+}
+
+export interface DashboardSummary {
+  active_rentals?: number;
+  expiration_window_days?: number;
+  expiring_contracts?: number;
+  generated_at?: string;
+  open_maintenance?: number;
+  overdue_payments?: number;
+  pending_signatures?: number;
+  properties?: number;
+  scope?: string;
+  vacancies?: number;
+}
+
+export interface DependencyHealth {
+  critical?: boolean;
+  detail?: string;
+  error?: string;
+  latency_ms?: number;
+  status?: string;
+}
+
+export interface EmailTemplate {
+  body?: string;
+  created_at?: string;
+  created_by?: string;
+  id?: string;
+  key?: string;
+  subject?: string;
+  version?: number;
+}
+
+export interface EmailTemplatePreviewRequest {
+  body?: string;
+  subject?: string;
+}
+
+export interface EmailTemplateRequest {
+  body?: string;
+  subject?: string;
+}
+
+export interface EmailTestRequest {
+  driver?: string;
+  to?: string;
+}
+
+export interface Error {
+  /** Untranslated detail appended to the message */
+  detail?: string;
+  /** Message in the locale of the request */
+  error?: string;
+  /** ID of the message in the catalogs */
+  message_id?: string;
+  /** Data of the message */
+  params?: unknown;
+}
+
+export interface FeatureFlag {
+  description?: string;
+  enabled?: boolean;
+  key?: string;
+  source?: string;
+  updated_at?: string;
+  updated_by?: string;
+}
+
+export interface FileMetadata {
+  category?: string;
+  contract_id?: string;
+  created_at?: string;
+  file_name?: string;
+  id?: string;
+  mime_type?: string;
+  original_name?: string;
+  path?: string;
+  rental_id?: string;
+  size?: number;
+  tags?: string[];
+  uploaded_by?: string;
+  user_id?: string;
+}
+
+export interface FileScan {
+  detail?: string;
+  file_name?: string;
+  id?: string;
+  path?: string;
+  provider?: string;
+  result?: string;
+  scanned_at?: string;
+  signature?: string;
+  size?: number;
+  user_id?: string;
+}
+
+export interface GenerateUploadLinkRequest {
+  expiration_days?: number;
+  recipient_email?: string;
+  recipient_name?: string;
+  send_email?: boolean;
+  user_id?: string;
+}
+
+export interface GraphQLError {
+  locations?: Location[];
+  message?: string;
+  path?: unknown[];
+}
+
+export interface GraphQLRequest {
+  operationName?: string;
+  query?: string;
+  variables?: Record<string, unknown>;
+}
+
+export interface GraphQLResponse {
+  data?: unknown;
+  errors?: GraphQLError[];
+}
+
+export interface HealthReport {
+  checked_at?: string;
+  checks?: Record<string, DependencyHealth>;
+  profile?: string;
+  status?: string;
+}
+
+export interface Inspection {
+  acknowledged_at?: string;
+  created_at?: string;
+  id?: string;
+  inspected_at?: string;
+  inspector_id?: string;
+  kind?: string;
+  notes?: string;
+  rental_id?: string;
+  rooms?: InventoryRoom[];
+  signed_pdf_path?: string;
+  signing_id?: string;
+  status?: string;
+  template_id?: string;
+  updated_at?: string;
+}
+
+export interface InspectionComparison {
+  items?: InspectionItemComparison[];
+  missing_count?: number;
+  move_in?: Inspection;
+  move_out?: Inspection;
+  rental_id?: string;
+  worsened_count?: number;
+}
+
+export interface InspectionItemComparison {
+  change?: string;
+  item?: string;
+  move_in_condition?: string;
+  move_in_notes?: string;
+  move_in_quantity?: number;
+  move_out_condition?: string;
+  move_out_notes?: string;
+  move_out_photos?: InventoryPhoto[];
+  move_out_quantity?: number;
+  room?: string;
+}
+
+export interface InventoryItem {
+  condition?: string;
+  name?: string;
+  notes?: string;
+  photos?: InventoryPhoto[];
+  quantity?: number;
+}
+
+export interface InventoryPhoto {
+  caption?: string;
+  path?: string;
+  url?: string;
+}
+
+export interface InventoryRoom {
+  items?: InventoryItem[];
+  name?: string;
+  photos?: InventoryPhoto[];
+}
+
+export interface Invitation {
+  accepted_at?: string;
+  created_at?: string;
+  email?: string;
+  expires_at?: string;
+  id?: string;
+  invited_by?: string;
+  message?: string;
+  organization_id?: string;
+  person_id?: string;
+  property_id?: string;
+  revoked_at?: string;
+  role?: string;
+  user_id?: string;
+}
+
+export interface InvitationInput {
+  email?: string;
+  message?: string;
+  name?: string;
+  organization_id?: string;
+  person_id?: string;
+  property_id?: string;
+  role?: string;
+}
+
+export interface InvitationRequest {
+  email?: string;
+  message?: string;
+  name?: string;
+  status?: string;
+  tempPassword?: string;
+}
+
+export interface IssueSettlementRequest {
+  move_out_date?: string;
+}
+
+export interface ListingApplicationRequest {
+  desired_move_in?: string;
+  document_number?: string;
+  email?: string;
+  full_name?: string;
+  message?: string;
+  monthly_income?: number;
+  occupants?: number;
+  occupation?: string;
+  phone?: string;
+}
+
+export interface ListingRequest {
+  admin_fee?: number;
+  area_m2?: number;
+  available_from?: string;
+  bathrooms?: number;
+  bedrooms?: number;
+  deposit_amount?: number;
+  description?: string;
+  min_term_months?: number;
+  monthly_rent?: number;
+  pets_allowed?: boolean;
+  title?: string;
+}
+
+export interface ListingStatusRequest {
+  status?: string;
+}
+
+export interface Location {
+  column?: number;
+  line?: number;
+}
+
+export interface LoginAttempt {
+  created_at?: string;
+  email?: string;
+  failure_reason?: string;
+  id?: string;
+  ip_address?: string;
+  success?: boolean;
+  user_agent?: string;
+}
+
+export interface LoginRequest {
+  email?: string;
+  password?: string;
+}
+
+export interface LoginResponse {
+  success?: boolean;
+  token?: string;
+  user?: LoginUser;
+}
+
+export interface LoginStatus {
+  captcha_required?: boolean;
+  failed_attempts?: number;
+  locked?: boolean;
+  locked_until?: string;
+}
+
+export interface LoginUser {
+  email?: string;
+  id?: string;
+  person_id?: string;
+  role?: string;
+  status?: string;
+}
+
+export interface ManagerRegistrationRequest {
+  account_holder?: string;
+  account_number?: string;
+  account_type?: string;
+  bank_name?: string;
+  due_day?: number;
+  email?: string;
+  end_date?: string;
+  full_name?: string;
+  late_fee?: number;
+  monthly_rent?: number;
+  nit?: string;
+  password?: string;
+  payment_terms?: string;
+  phone?: string;
+  property_address?: string;
+  property_apt_number?: string;
+  property_city?: string;
+  property_state?: string;
+  property_type?: string;
+  property_zip_code?: string;
+  security_deposit?: number;
+  start_date?: string;
+  tenant_responsible_for?: string[];
+  utilities_included?: string[];
+}
+
+export interface ManagerRegistrationResponse {
+  message?: string;
+  payment_url?: string;
+  person_id?: string;
+  property_id?: string;
+  success?: boolean;
+  user_id?: string;
+}
+
+export interface Notification {
+  created_at?: string;
+  entity_id?: string;
+  id?: string;
+  message?: string;
+  read_at?: string;
+  title?: string;
+  type?: string;
+  user_id?: string;
+}
+
+export interface NotificationPreferenceInput {
+  channel?: string;
+  frequency?: string;
+  type?: string;
+}
+
+export interface NotificationPreferencesRequest {
+  preferences?: NotificationPreferenceInput[];
+}
+
+export interface OccupancyGap {
+  days?: number;
+  end_date?: string;
+  start_date?: string;
+}
+
+export interface OccupancyPeriod {
+  days?: number;
+  end_date?: string;
+  end_reason?: string;
+  monthly_rent?: number;
+  rental_id?: string;
+  renter_id?: string;
+  start_date?: string;
+  status?: string;
+  tenant_name?: string;
+}
+
+export interface OccupancyTimeline {
+  from?: string;
+  gaps?: OccupancyGap[];
+  occupancy_rate?: number;
+  occupied_days?: number;
+  periods?: OccupancyPeriod[];
+  property_id?: string;
+  to?: string;
+  total_days?: number;
+  vacant_days?: number;
+}
+
+export interface PaymentCheckout {
+  amount_in_cents?: number;
+  completed_at?: string;
+  created_at?: string;
+  currency?: string;
+  id?: string;
+  payment_method?: string;
+  period?: string;
+  person_id?: string;
+  provider?: string;
+  reference?: string;
+  rent_payment_id?: string;
+  rental_id?: string;
+  status?: string;
+  transaction_id?: string;
+}
+
+export interface Person {
+  full_name?: string;
+  id?: string;
+  nit?: string;
+  phone?: string;
+  version?: number;
+}
+
+export interface PlatformSubscription {
+  created_at?: string;
+  current_period_end?: string;
+  customer_id?: string;
+  id?: string;
+  past_due_since?: string;
+  payment_url?: string;
+  price_id?: string;
+  provider?: string;
+  status?: string;
+  subscription_id?: string;
+  suspended_at?: string;
+  updated_at?: string;
+  user_id?: string;
+}
+
+export interface Property {
+  address?: string;
+  apt_number?: string;
+  area_m2?: number;
+  bathrooms?: number;
+  bedrooms?: number;
+  boundaries?: string;
+  building_id?: string;
+  city?: string;
+  estrato?: number;
+  furnished?: boolean;
+  id?: string;
+  manager_ids?: string[];
+  parking_details?: string;
+  parking_spots?: number;
+  pets_allowed?: boolean;
+  photos?: PropertyPhoto[];
+  resident_id?: string;
+  state?: string;
+  type?: string;
+  version?: number;
+  zip_code?: string;
+}
+
+export interface PropertyListing {
+  admin_fee?: number;
+  area_m2?: number;
+  available_from?: string;
+  bathrooms?: number;
+  bedrooms?: number;
+  city?: string;
+  created_at?: string;
+  deposit_amount?: number;
+  description?: string;
+  id?: string;
+  min_term_months?: number;
+  monthly_rent?: number;
+  pets_allowed?: boolean;
+  photos?: InventoryPhoto[];
+  property_id?: string;
+  published_at?: string;
+  status?: string;
+  title?: string;
+  updated_at?: string;
+}
+
+export interface PropertyPhoto {
+  caption?: string;
+  created_at?: string;
+  id?: string;
+  path?: string;
+  position?: number;
+  property_id?: string;
+  url?: string;
+}
+
+export interface PublicListing {
+  admin_fee?: number;
+  area_m2?: number;
+  available_from?: string;
+  bathrooms?: number;
+  bedrooms?: number;
+  city?: string;
+  deposit_amount?: number;
+  description?: string;
+  id?: string;
+  min_term_months?: number;
+  monthly_rent?: number;
+  pets_allowed?: boolean;
+  photos?: InventoryPhoto[];
+  published_at?: string;
+  title?: string;
+}
+
+export interface RefundDepositRequest {
+  reference?: string;
+  refunded_at?: string;
+}
+
+export interface Reglamento {
+  address?: string;
+  building_key?: string;
+  city?: string;
+  file_name?: string;
+  file_path?: string;
+  id?: string;
+  uploaded_at?: string;
+  uploaded_by?: string;
+  version?: number;
+}
+
+export interface RentCheckout {
+  checkout?: PaymentCheckout;
+  checkout_url?: string;
+}
+
+export interface RentPayment {
+  amount_paid?: number;
+  id?: string;
+  invoice_cufe?: string;
+  invoice_external_id?: string;
+  invoice_message?: string;
+  invoice_number?: string;
+  invoice_provider?: string;
+  invoice_sequence?: number;
+  invoice_status?: string;
+  invoiced_at?: string;
+  paid_on_time?: boolean;
+  payment_date?: string;
+  receipt_number?: string;
+  rental_id?: string;
+}
+
+export interface Rental {
+  bank_account_id?: string;
+  end_date?: string;
+  id?: string;
+  payment_terms?: string;
+  property_id?: string;
+  renter_id?: string;
+  start_date?: string;
+  unpaid_months?: number;
+  version?: number;
+}
+
+export interface RentalPartyRequest {
+  person_id?: string;
+  role?: string;
+}
+
+export interface RentalPartyResponse {
+  created_at?: string;
+  email?: string;
+  id?: string;
+  person?: Person;
+  person_id?: string;
+  rental_id?: string;
+  role?: string;
+  role_label?: string;
+}
+
+export interface ReorderPhotosRequest {
+  photo_ids?: string[];
+}
+
+export interface RestoreBucketBackupRequest {
+  overwrite?: boolean;
+  paths?: string[];
+}
+
+export interface SearchResult {
+  id?: string;
+  person_id?: string;
+  property_id?: string;
+  rank?: number;
+  rental_id?: string;
+  subtitle?: string;
+  title?: string;
+  type?: string;
+}
+
+export interface SecurityDepositRequest {
+  amount?: number;
+  bank_account_id?: string;
+  held_by?: string;
+  notes?: string;
+  received_at?: string;
+}
+
+export interface SignerLocation {
+  accuracy?: number;
+  latitude?: number;
+  longitude?: number;
+}
+
+export interface StartBucketBackupRequest {
+  full?: boolean;
+}
+
+export interface StorageUsage {
+  file_count?: number;
+  max_file_size_bytes?: number;
+  quota_bytes?: number;
+  remaining_bytes?: number;
+  role?: string;
+  used_bytes?: number;
+  user_id?: string;
+}
+
+export interface SupabaseFileInfo {
+  category?: string;
+  contract_id?: string;
+  download_url?: string;
+  mime_type?: string;
+  name?: string;
+  path?: string;
+  rental_id?: string;
+  size?: number;
+  tags?: string[];
+  uploaded_at?: string;
+}
+
+export interface SupabaseUploadResponse {
+  bucket_name?: string;
+  category?: string;
+  contract_id?: string;
+  key?: string;
+  link?: string;
+  mime_type?: string;
+  name?: string;
+  path?: string;
+  rental_id?: string;
+  size?: number;
+  success?: boolean;
+  tags?: string[];
+  uploaded_at?: string;
+  uploaded_by?: string;
+}
+
+export interface UnsubscribeRequest {
+  type?: string;
+}
+
+export interface UpdateFeatureFlagRequest {
+  enabled?: boolean;
+}
+
+export interface UpdateInspectionRequest {
+  inspected_at?: string;
+  notes?: string;
+  rooms?: InventoryRoom[];
+}
+
+export interface UpdatePhotoRequest {
+  caption?: string;
+}
+
+export interface UpdateUploadLimitRequest {
+  max_file_size_mb?: number;
+  quota_mb?: number;
+}
+
+export interface UploadLimit {
+  max_file_size_mb?: number;
+  quota_mb?: number;
+  role?: string;
+  source?: string;
+  updated_at?: string;
+}
+
+export interface UploadToken {
+  created_at?: string;
+  created_by?: string;
+  email?: string;
+  expires_at?: string;
+  name?: string;
+  person_id?: string;
+  token?: string;
+  used?: boolean;
+  user_id?: string;
+}
+
+export interface User {
+  created_at?: string;
+  email?: string;
+  email_pending_verification?: boolean;
+  id?: string;
+  locale?: string;
+  password_base64?: string;
+  person_id?: string;
+  role?: string;
+  status?: string;
+}
+
+export interface UserBulkFilter {
+  created_from?: string;
+  created_to?: string;
+  organization_id?: string;
+  role?: string;
+  status?: string;
+}
+
+export interface UserBulkJob {
+  action?: string;
+  created_at?: string;
+  created_by?: string;
+  error?: string;
+  failed?: number;
+  filter?: UserBulkFilter;
+  finished_at?: string;
+  id?: string;
+  results?: UserBulkResult[];
+  skipped?: number;
+  started_at?: string;
+  status?: string;
+  succeeded?: number;
+  total?: number;
+}
+
+export interface UserBulkRequest {
+  action?: string;
+  dry_run?: boolean;
+  filter?: UserBulkFilter;
+}
+
+export interface UserBulkResult {
+  email?: string;
+  message?: string;
+  outcome?: string;
+  previous_status?: string;
+  user_id?: string;
+}
+
+export interface invitationResponse {
+  accepted_at?: string;
+  created_at?: string;
+  email?: string;
+  expires_at?: string;
+  id?: string;
+  invited_by?: string;
+  message?: string;
+  organization_id?: string;
+  person_id?: string;
+  property_id?: string;
+  revoked_at?: string;
+  role?: string;
+  status?: string;
+  user_id?: string;
+}
+
+export interface sessionResponse {
+  created_at?: string;
+  current?: boolean;
+  device?: string;
+  expires_at?: string;
+  id?: string;
+  impersonator_id?: string;
+  ip_address?: string;
+  last_seen_at?: string;
+  revoked_at?: string;
+  user_agent?: string;
+  user_id?: string;
+}
+
+export interface EmailEmailHealthParams {
+  driver?: string;
+}
+
+export interface FileUploadListFileScansParams {
+  result?: string;
+}
+
+export interface FileUploadListUploadedFilesParams {
+  category?: string;
+  tag?: string[];
+  limit?: number;
+  offset?: number;
+}
+
+export interface FileUploadListUserFilesParams {
+  category?: string;
+  tag?: string[];
+  limit?: number;
+  offset?: number;
+}
+
+export interface ImpersonationGetAuditLogParams {
+  changed_by?: string;
+  impersonated_by?: string;
+  impersonated?: boolean;
+  hours?: number;
+  limit?: number;
+}
+
+export interface ListingGetPublicListingsParams {
+  city?: string;
+  min_price?: number;
+  max_price?: number;
+  limit?: number;
+  offset?: number;
+}
+
+export interface NotificationGetMineParams {
+  unread?: boolean;
+  limit?: number;
+  offset?: number;
+}
+
+export interface PersonGetAllParams {
+  limit?: number;
+  offset?: number;
+  sort?: string;
+}
+
+export interface PropertyGetAllParams {
+  city?: string;
+  min_bedrooms?: number;
+  min_bathrooms?: number;
+  min_area?: number;
+  max_area?: number;
+  estrato?: number;
+  parking?: boolean;
+  furnished?: boolean;
+  pets_allowed?: boolean;
+  limit?: number;
+  offset?: number;
+  sort?: string;
+}
+
+export interface RentalHistoryGetPropertyOccupancyParams {
+  from?: string;
+  to?: string;
+}
+
+export interface SearchSearchParams {
+  q?: string;
+  types?: string;
+  limit?: number;
+}
+
+export interface UserGetAllParams {
+  limit?: number;
+  offset?: number;
+  sort?: string;
+}
+
+export interface UserGetByEmailParams {
+  email?: string;
+}
+
+export interface UserGetLoginAttemptsParams {
+  email?: string;
+  ip?: string;
+  failed?: boolean;
+  hours?: number;
+  limit?: number;
+}
+
+export class ApiClient extends ApiClientBase {
+  /** Create a new bank account (POST /bank-accounts) */
+  bankAccountCreate(body: BankAccount): Promise<BankAccount> {
+    return this.request<BankAccount>('POST', `/bank-accounts`, { body });
+  }
+
+  /** Delete a bank account (DELETE /bank-accounts/{id}) */
+  bankAccountDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/bank-accounts/${encodeURIComponent(id)}`);
+  }
+
+  /** Get all bank accounts (GET /bank-accounts) */
+  bankAccountGetAll(): Promise<BankAccount[]> {
+    return this.request<BankAccount[]>('GET', `/bank-accounts`);
+  }
+
+  /** Get bank account by ID (GET /bank-accounts/{id}) */
+  bankAccountGetByID(id: string): Promise<BankAccount> {
+    return this.request<BankAccount>('GET', `/bank-accounts/${encodeURIComponent(id)}`);
+  }
+
+  /** Get bank accounts by person ID (GET /bank-accounts/person/{personId}) */
+  bankAccountGetByPersonID(personId: string): Promise<BankAccount[]> {
+    return this.request<BankAccount[]>('GET', `/bank-accounts/person/${encodeURIComponent(personId)}`);
+  }
+
+  /** Update a bank account (PUT /bank-accounts/{id}) */
+  bankAccountUpdate(id: string, body: BankAccount): Promise<BankAccount> {
+    return this.request<BankAccount>('PUT', `/bank-accounts/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Consultar backup del bucket (GET /admin/file-backups/{id}) */
+  bucketBackupGetBackup(id: string): Promise<BucketBackup> {
+    return this.request<BucketBackup>('GET', `/admin/file-backups/${encodeURIComponent(id)}`);
+  }
+
+  /** Listar backups del bucket (GET /admin/file-backups) */
+  bucketBackupListBackups(): Promise<BucketBackup[]> {
+    return this.request<BucketBackup[]>('GET', `/admin/file-backups`);
+  }
+
+  /** Restaurar archivos de un backup (POST /admin/file-backups/{id}/restore) */
+  bucketBackupRestoreBackup(id: string, body: RestoreBucketBackupRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/file-backups/${encodeURIComponent(id)}/restore`, { body });
+  }
+
+  /** Iniciar backup del bucket (POST /admin/file-backups) */
+  bucketBackupStartBackup(body: StartBucketBackupRequest): Promise<BucketBackup> {
+    return this.request<BucketBackup>('POST', `/admin/file-backups`, { body });
+  }
+
+  /**  (PUT /admin/buildings/{id}/properties/{propertyId}) */
+  buildingAddProperty(id: string, propertyId: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/buildings/${encodeURIComponent(id)}/properties/${encodeURIComponent(propertyId)}`);
+  }
+
+  /** Create a building (POST /admin/buildings) */
+  buildingCreate(body: BuildingRequest): Promise<Building> {
+    return this.request<Building>('POST', `/admin/buildings`, { body });
+  }
+
+  /**  (DELETE /admin/buildings/{id}) */
+  buildingDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/buildings/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/buildings) */
+  buildingGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/buildings`);
+  }
+
+  /** Get a building (GET /admin/buildings/{id}) */
+  buildingGetByID(id: string): Promise<BuildingResponse> {
+    return this.request<BuildingResponse>('GET', `/admin/buildings/${encodeURIComponent(id)}`);
+  }
+
+  /**  (DELETE /admin/buildings/{id}/properties/{propertyId}) */
+  buildingRemoveProperty(id: string, propertyId: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/buildings/${encodeURIComponent(id)}/properties/${encodeURIComponent(propertyId)}`);
+  }
+
+  /**  (PUT /admin/buildings/{id}) */
+  buildingUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/buildings/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /admin/contracts/{id}/cession) */
+  contractCessionCreate(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/contracts/${encodeURIComponent(id)}/cession`);
+  }
+
+  /**  (GET /admin/contracts/{id}/cessions) */
+  contractCessionGetByRentalID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contracts/${encodeURIComponent(id)}/cessions`);
+  }
+
+  /**  (GET /admin/contracts/{id}/owner-payments) */
+  contractCessionGetPaymentsByOwner(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contracts/${encodeURIComponent(id)}/owner-payments`);
+  }
+
+  /**  (GET /admin/contract-cessions/{id}/letter) */
+  contractCessionServeLetter(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-cessions/${encodeURIComponent(id)}/letter`);
+  }
+
+  /**  (POST /admin/contracts/generate) */
+  contractGenerateContract(): Promise<void> {
+    return this.request<void>('POST', `/admin/contracts/generate`);
+  }
+
+  /**  (GET /admin/contracts/{id}/chain) */
+  contractGetRentalChain(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contracts/${encodeURIComponent(id)}/chain`);
+  }
+
+  /**  (POST /admin/contracts/{id}/renew) */
+  contractRenewContract(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/contracts/${encodeURIComponent(id)}/renew`);
+  }
+
+  /**  (POST /admin/contract-signing/request) */
+  contractSigningCreateSigningRequest(): Promise<void> {
+    return this.request<void>('POST', `/admin/contract-signing/request`);
+  }
+
+  /**  (GET /admin/contract-signing/{id}/events) */
+  contractSigningGetSigningEvents(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-signing/${encodeURIComponent(id)}/events`);
+  }
+
+  /**  (GET /contract-signing/status/{id}) */
+  contractSigningGetSigningStatus(id: string): Promise<void> {
+    return this.request<void>('GET', `/contract-signing/status/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /public/contract-signing/status/{id}) */
+  contractSigningGetSigningStatus2(id: string): Promise<void> {
+    return this.request<void>('GET', `/public/contract-signing/status/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /public/esign/webhook/{provider}) */
+  contractSigningProviderWebhook(provider: string): Promise<void> {
+    return this.request<void>('POST', `/public/esign/webhook/${encodeURIComponent(provider)}`);
+  }
+
+  /**  (POST /contract-signing/reject/{id}) */
+  contractSigningRejectContract(id: string): Promise<void> {
+    return this.request<void>('POST', `/contract-signing/reject/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /public/contract-signing/reject/{id}) */
+  contractSigningRejectContract2(id: string): Promise<void> {
+    return this.request<void>('POST', `/public/contract-signing/reject/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /public/contract-signing/otp/{id}) */
+  contractSigningSendSigningOTP(id: string): Promise<void> {
+    return this.request<void>('POST', `/public/contract-signing/otp/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /contract-signing/pdf/{id}) */
+  contractSigningServePDF(id: string): Promise<void> {
+    return this.request<void>('GET', `/contract-signing/pdf/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /public/contract-signing/pdf/{id}) */
+  contractSigningServePDF2(id: string): Promise<void> {
+    return this.request<void>('GET', `/public/contract-signing/pdf/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /contract-signing/sign/{id}) */
+  contractSigningSignContract(id: string): Promise<void> {
+    return this.request<void>('POST', `/contract-signing/sign/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /public/contract-signing/sign/{id}) */
+  contractSigningSignContract2(id: string): Promise<void> {
+    return this.request<void>('POST', `/public/contract-signing/sign/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /public/contract-signing/verify/{id}) */
+  contractSigningVerifySignature(id: string): Promise<void> {
+    return this.request<void>('GET', `/public/contract-signing/verify/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /public/contract-signing/verify) */
+  contractSigningVerifySignedPDF(): Promise<void> {
+    return this.request<void>('POST', `/public/contract-signing/verify`);
+  }
+
+  /**  (POST /admin/contract-templates) */
+  contractTemplateCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/contract-templates`);
+  }
+
+  /**  (DELETE /admin/contract-templates/{id}) */
+  contractTemplateDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/contract-templates/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/contract-templates) */
+  contractTemplateGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-templates`);
+  }
+
+  /**  (GET /admin/contract-templates/{id}) */
+  contractTemplateGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-templates/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/contract-templates/placeholders) */
+  contractTemplateGetPlaceholders(): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-templates/placeholders`);
+  }
+
+  /**  (GET /admin/contract-templates/{id}/preview) */
+  contractTemplatePreview(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-templates/${encodeURIComponent(id)}/preview`);
+  }
+
+  /**  (PUT /admin/contract-templates/{id}) */
+  contractTemplateUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/contract-templates/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /admin/contracts/{id}/transfer) */
+  contractTransferContract(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/contracts/${encodeURIComponent(id)}/transfer`);
+  }
+
+  /** Dashboard summary (GET /dashboard/summary) */
+  dashboardGetSummary(): Promise<DashboardSummary> {
+    return this.request<DashboardSummary>('GET', `/dashboard/summary`);
+  }
+
+  /** Refresh the status of the electronic invoice of a payment (POST /admin/payments/{id}/einvoice/refresh) */
+  eInvoiceRefresh(id: string): Promise<RentPayment> {
+    return this.request<RentPayment>('POST', `/admin/payments/${encodeURIComponent(id)}/einvoice/refresh`);
+  }
+
+  /** Issue the DIAN electronic invoice of a payment (POST /admin/payments/{id}/einvoice) */
+  eInvoiceSubmit(id: string): Promise<RentPayment> {
+    return this.request<RentPayment>('POST', `/admin/payments/${encodeURIComponent(id)}/einvoice`);
+  }
+
+  /** Email driver health (GET /admin/email/health) */
+  emailEmailHealth(params: EmailEmailHealthParams = {}): Promise<unknown> {
+    return this.request<unknown>('GET', `/admin/email/health`, { query: { ...params } });
+  }
+
+  /**  (POST /admin/emails/custom) */
+  emailSendCustomEmail(): Promise<void> {
+    return this.request<void>('POST', `/admin/emails/custom`);
+  }
+
+  /** Send test email (POST /admin/email/test) */
+  emailSendTestEmail(body: EmailTestRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/email/test`, { body });
+  }
+
+  /** Get email template (GET /admin/email-templates/{key}) */
+  emailTemplateGetTemplate(key: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/admin/email-templates/${encodeURIComponent(key)}`);
+  }
+
+  /** List email templates (GET /admin/email-templates) */
+  emailTemplateListTemplates(): Promise<unknown[]> {
+    return this.request<unknown[]>('GET', `/admin/email-templates`);
+  }
+
+  /** Preview email template (POST /admin/email-templates/{key}/preview) */
+  emailTemplatePreviewTemplate(key: string, body: EmailTemplatePreviewRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/email-templates/${encodeURIComponent(key)}/preview`, { body });
+  }
+
+  /** Reset email template (DELETE /admin/email-templates/{key}) */
+  emailTemplateResetTemplate(key: string): Promise<unknown> {
+    return this.request<unknown>('DELETE', `/admin/email-templates/${encodeURIComponent(key)}`);
+  }
+
+  /** Save email template (PUT /admin/email-templates/{key}) */
+  emailTemplateSaveTemplate(key: string, body: EmailTemplateRequest): Promise<EmailTemplate> {
+    return this.request<EmailTemplate>('PUT', `/admin/email-templates/${encodeURIComponent(key)}`, { body });
+  }
+
+  /**  (GET /public/email/open/{file}) */
+  emailTrackingTrackOpen(file: string): Promise<void> {
+    return this.request<void>('GET', `/public/email/open/${encodeURIComponent(file)}`);
+  }
+
+  /**  (POST /admin/emails/annual-renewal-reminders) */
+  emailTriggerAnnualRenewalReminders(): Promise<void> {
+    return this.request<void>('POST', `/admin/emails/annual-renewal-reminders`);
+  }
+
+  /** List feature flags (GET /admin/feature-flags) */
+  featureFlagListFeatureFlags(): Promise<FeatureFlag[]> {
+    return this.request<FeatureFlag[]>('GET', `/admin/feature-flags`);
+  }
+
+  /** Reset a feature flag (DELETE /admin/feature-flags/{key}) */
+  featureFlagResetFeatureFlag(key: string): Promise<FeatureFlag> {
+    return this.request<FeatureFlag>('DELETE', `/admin/feature-flags/${encodeURIComponent(key)}`);
+  }
+
+  /** Toggle a feature flag (PUT /admin/feature-flags/{key}) */
+  featureFlagUpdateFeatureFlag(key: string, body: UpdateFeatureFlagRequest): Promise<FeatureFlag> {
+    return this.request<FeatureFlag>('PUT', `/admin/feature-flags/${encodeURIComponent(key)}`, { body });
+  }
+
+  /** Cancelar subida por partes (DELETE /upload/chunked-authenticated/{id}) */
+  fileUploadAbortChunkedUpload(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/upload/chunked-authenticated/${encodeURIComponent(id)}`);
+  }
+
+  /** Cancelar subida por partes (DELETE /upload/chunked/{id}) */
+  fileUploadAbortChunkedUpload2(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/upload/chunked/${encodeURIComponent(id)}`);
+  }
+
+  /** Completar subida por partes (POST /upload/chunked-authenticated/{id}/complete) */
+  fileUploadCompleteChunkedUpload(id: string): Promise<SupabaseUploadResponse> {
+    return this.request<SupabaseUploadResponse>('POST', `/upload/chunked-authenticated/${encodeURIComponent(id)}/complete`);
+  }
+
+  /** Completar subida por partes (POST /upload/chunked/{id}/complete) */
+  fileUploadCompleteChunkedUpload2(id: string): Promise<SupabaseUploadResponse> {
+    return this.request<SupabaseUploadResponse>('POST', `/upload/chunked/${encodeURIComponent(id)}/complete`);
+  }
+
+  /** Iniciar subida por partes (POST /upload/chunked) */
+  fileUploadCreateChunkedUpload(body: CreateChunkedUploadRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/upload/chunked`, { body });
+  }
+
+  /** Iniciar subida por partes (POST /upload/chunked-authenticated) */
+  fileUploadCreateChunkedUpload2(body: CreateChunkedUploadRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/upload/chunked-authenticated`, { body });
+  }
+
+  /** Eliminar archivo (DELETE /admin/file-upload/files/{filePath}) */
+  fileUploadDeleteFile(filePath: string): Promise<unknown> {
+    return this.request<unknown>('DELETE', `/admin/file-upload/files/${encodeURIComponent(filePath)}`);
+  }
+
+  /**  (POST /admin/file-upload/files/{filePath}) */
+  fileUploadFileAction(filePath: string): Promise<void> {
+    return this.request<void>('POST', `/admin/file-upload/files/${encodeURIComponent(filePath)}`);
+  }
+
+  /** Generar enlace de subida (POST /admin/file-upload/generate-link) */
+  fileUploadGenerateUploadLink(body: GenerateUploadLinkRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/file-upload/generate-link`, { body });
+  }
+
+  /** Consultar subida por partes (GET /upload/chunked-authenticated/{id}) */
+  fileUploadGetChunkedUpload(id: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/upload/chunked-authenticated/${encodeURIComponent(id)}`);
+  }
+
+  /** Consultar subida por partes (HEAD /upload/chunked-authenticated/{id}) */
+  fileUploadGetChunkedUpload2(id: string): Promise<unknown> {
+    return this.request<unknown>('HEAD', `/upload/chunked-authenticated/${encodeURIComponent(id)}`);
+  }
+
+  /** Consultar subida por partes (GET /upload/chunked/{id}) */
+  fileUploadGetChunkedUpload3(id: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/upload/chunked/${encodeURIComponent(id)}`);
+  }
+
+  /** Consultar subida por partes (HEAD /upload/chunked/{id}) */
+  fileUploadGetChunkedUpload4(id: string): Promise<unknown> {
+    return this.request<unknown>('HEAD', `/upload/chunked/${encodeURIComponent(id)}`);
+  }
+
+  /** Consultar uso de almacenamiento (GET /upload/usage) */
+  fileUploadGetMyStorageUsage(): Promise<StorageUsage> {
+    return this.request<StorageUsage>('GET', `/upload/usage`);
+  }
+
+  /** Listar análisis de virus (GET /admin/file-upload/scans) */
+  fileUploadListFileScans(params: FileUploadListFileScansParams = {}): Promise<FileScan[]> {
+    return this.request<FileScan[]>('GET', `/admin/file-upload/scans`, { query: { ...params } });
+  }
+
+  /** Listar documentos de un arriendo (GET /rentals/{id}/files) */
+  fileUploadListRentalFiles(id: string): Promise<FileMetadata[]> {
+    return this.request<FileMetadata[]>('GET', `/rentals/${encodeURIComponent(id)}/files`);
+  }
+
+  /** Listar papelera (GET /admin/file-upload/trash) */
+  fileUploadListTrash(): Promise<unknown> {
+    return this.request<unknown>('GET', `/admin/file-upload/trash`);
+  }
+
+  /** Listar límites de subida (GET /admin/file-upload/limits) */
+  fileUploadListUploadLimits(): Promise<UploadLimit[]> {
+    return this.request<UploadLimit[]>('GET', `/admin/file-upload/limits`);
+  }
+
+  /** Listar tokens de subida (GET /admin/file-upload/tokens) */
+  fileUploadListUploadTokens(): Promise<UploadToken[]> {
+    return this.request<UploadToken[]>('GET', `/admin/file-upload/tokens`);
+  }
+
+  /** Listar archivos subidos (GET /admin/file-upload/files) */
+  fileUploadListUploadedFiles(params: FileUploadListUploadedFilesParams = {}): Promise<SupabaseFileInfo[]> {
+    return this.request<SupabaseFileInfo[]>('GET', `/admin/file-upload/files`, { query: { ...params } });
+  }
+
+  /** Iterates every item of fileUploadListUploadedFiles, requesting pages of pageSize items */
+  fileUploadListUploadedFilesIter(params: Omit<FileUploadListUploadedFilesParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<SupabaseFileInfo> {
+    return paginate(pageSize, (limit, offset) => this.fileUploadListUploadedFiles({ ...params, limit, offset }));
+  }
+
+  /** Listar archivos de un usuario (GET /admin/file-upload/files/{userID}) */
+  fileUploadListUserFiles(userID: string, params: FileUploadListUserFilesParams = {}): Promise<SupabaseFileInfo[]> {
+    return this.request<SupabaseFileInfo[]>('GET', `/admin/file-upload/files/${encodeURIComponent(userID)}`, { query: { ...params } });
+  }
+
+  /** Iterates every item of fileUploadListUserFiles, requesting pages of pageSize items */
+  fileUploadListUserFilesIter(userID: string, params: Omit<FileUploadListUserFilesParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<SupabaseFileInfo> {
+    return paginate(pageSize, (limit, offset) => this.fileUploadListUserFiles(userID, { ...params, limit, offset }));
+  }
+
+  /** Purgar archivo (DELETE /admin/file-upload/trash/{id}) */
+  fileUploadPurgeTrashedFile(id: string): Promise<unknown> {
+    return this.request<unknown>('DELETE', `/admin/file-upload/trash/${encodeURIComponent(id)}`);
+  }
+
+  /** Restablecer límites de subida (DELETE /admin/file-upload/limits/{role}) */
+  fileUploadResetUploadLimit(role: string): Promise<UploadLimit> {
+    return this.request<UploadLimit>('DELETE', `/admin/file-upload/limits/${encodeURIComponent(role)}`);
+  }
+
+  /** Restaurar archivo (POST /admin/file-upload/trash/{id}/restore) */
+  fileUploadRestoreTrashedFile(id: string): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/file-upload/trash/${encodeURIComponent(id)}/restore`);
+  }
+
+  /** Definir límites de subida (PUT /admin/file-upload/limits/{role}) */
+  fileUploadUpdateUploadLimit(role: string, body: UpdateUploadLimitRequest): Promise<UploadLimit> {
+    return this.request<UploadLimit>('PUT', `/admin/file-upload/limits/${encodeURIComponent(role)}`, { body });
+  }
+
+  /** Subir parte (PATCH /upload/chunked-authenticated/{id}) */
+  fileUploadUploadChunk(id: string): Promise<unknown> {
+    return this.request<unknown>('PATCH', `/upload/chunked-authenticated/${encodeURIComponent(id)}`);
+  }
+
+  /** Subir parte (PATCH /upload/chunked/{id}) */
+  fileUploadUploadChunk2(id: string): Promise<unknown> {
+    return this.request<unknown>('PATCH', `/upload/chunked/${encodeURIComponent(id)}`);
+  }
+
+  /** Validar token de subida (GET /upload/validate-token/{token}) */
+  fileUploadValidateToken(token: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/upload/validate-token/${encodeURIComponent(token)}`);
+  }
+
+  /** Enabled capabilities (GET /capabilities) */
+  getCapabilitiesFunc1(): Promise<Capabilities> {
+    return this.request<Capabilities>('GET', `/capabilities`);
+  }
+
+  /**  (GET /admin/deprecated-routes) */
+  getDeprecatedRouteUsage(): Promise<void> {
+    return this.request<void>('GET', `/admin/deprecated-routes`);
+  }
+
+  /** Liveness and dependency status (GET /health) */
+  getHealthFunc1(): Promise<HealthReport> {
+    return this.request<HealthReport>('GET', `/health`);
+  }
+
+  /** GraphQL query (POST /graphql) */
+  graphQLQuery(body: GraphQLRequest): Promise<GraphQLResponse> {
+    return this.request<GraphQLResponse>('POST', `/graphql`, { body });
+  }
+
+  /**  (GET /guarantee-studies/{id}) */
+  guaranteeGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/guarantee-studies/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /guarantee-studies/renter/{renterId}) */
+  guaranteeGetByRenterID(renterId: string): Promise<void> {
+    return this.request<void>('GET', `/guarantee-studies/renter/${encodeURIComponent(renterId)}`);
+  }
+
+  /**  (POST /admin/guarantee-studies/{id}/refresh) */
+  guaranteeRefresh(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/guarantee-studies/${encodeURIComponent(id)}/refresh`);
+  }
+
+  /**  (POST /admin/guarantee-studies) */
+  guaranteeSubmit(): Promise<void> {
+    return this.request<void>('POST', `/admin/guarantee-studies`);
+  }
+
+  /** Recent audit log entries (GET /admin/audit-log) */
+  impersonationGetAuditLog(params: ImpersonationGetAuditLogParams = {}): Promise<AuditLog[]> {
+    return this.request<AuditLog[]>('GET', `/admin/audit-log`, { query: { ...params } });
+  }
+
+  /** Impersonate a user (POST /admin/impersonate/{userId}) */
+  impersonationImpersonate(userId: string, body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/impersonate/${encodeURIComponent(userId)}`, { body });
+  }
+
+  /** Start an inspection of a rental (POST /admin/contracts/{id}/inspections) */
+  inspectionCreate(id: string, body: CreateInspectionRequest): Promise<Inspection> {
+    return this.request<Inspection>('POST', `/admin/contracts/${encodeURIComponent(id)}/inspections`, { body });
+  }
+
+  /**  (POST /admin/inspection-templates) */
+  inspectionCreateTemplate(): Promise<void> {
+    return this.request<void>('POST', `/admin/inspection-templates`);
+  }
+
+  /**  (DELETE /admin/inspections/{id}) */
+  inspectionDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/inspections/${encodeURIComponent(id)}`);
+  }
+
+  /**  (DELETE /admin/inspection-templates/{id}) */
+  inspectionDeleteTemplate(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/inspection-templates/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/inspections/{id}) */
+  inspectionGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/inspections/${encodeURIComponent(id)}`);
+  }
+
+  /** List the inspections of a rental (GET /admin/contracts/{id}/inspections) */
+  inspectionGetByRentalID(id: string): Promise<Inspection[]> {
+    return this.request<Inspection[]>('GET', `/admin/contracts/${encodeURIComponent(id)}/inspections`);
+  }
+
+  /** Compare the move-in and move-out inspections of a rental (GET /admin/contracts/{id}/inspections/comparison) */
+  inspectionGetComparison(id: string): Promise<InspectionComparison> {
+    return this.request<InspectionComparison>('GET', `/admin/contracts/${encodeURIComponent(id)}/inspections/comparison`);
+  }
+
+  /**  (GET /admin/inspection-templates) */
+  inspectionGetTemplates(): Promise<void> {
+    return this.request<void>('GET', `/admin/inspection-templates`);
+  }
+
+  /** Send an inspection to the tenant to sign (POST /admin/inspections/{id}/acknowledgment) */
+  inspectionRequestAcknowledgment(id: string): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/inspections/${encodeURIComponent(id)}/acknowledgment`);
+  }
+
+  /**  (GET /admin/contracts/{id}/inspections/comparison/pdf) */
+  inspectionServeComparisonPDF(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contracts/${encodeURIComponent(id)}/inspections/comparison/pdf`);
+  }
+
+  /**  (GET /admin/inspections/{id}/pdf) */
+  inspectionServePDF(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/inspections/${encodeURIComponent(id)}/pdf`);
+  }
+
+  /** Update an inspection (PUT /admin/inspections/{id}) */
+  inspectionUpdate(id: string, body: UpdateInspectionRequest): Promise<Inspection> {
+    return this.request<Inspection>('PUT', `/admin/inspections/${encodeURIComponent(id)}`, { body });
+  }
+
+  /**  (PUT /admin/inspection-templates/{id}) */
+  inspectionUpdateTemplate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/inspection-templates/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /admin/inspections/{id}/photos) */
+  inspectionUploadPhoto(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/inspections/${encodeURIComponent(id)}/photos`);
+  }
+
+  /**  (DELETE /admin/inventories/property/{propertyId}) */
+  inventoryDelete(propertyId: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/inventories/property/${encodeURIComponent(propertyId)}`);
+  }
+
+  /**  (GET /inventories/property/{propertyId}) */
+  inventoryGetByPropertyID(propertyId: string): Promise<void> {
+    return this.request<void>('GET', `/inventories/property/${encodeURIComponent(propertyId)}`);
+  }
+
+  /**  (GET /admin/inventories/conditions) */
+  inventoryGetConditions(): Promise<void> {
+    return this.request<void>('GET', `/admin/inventories/conditions`);
+  }
+
+  /**  (GET /admin/inventories/property/{propertyId}/annex) */
+  inventoryPreviewAnnex(propertyId: string): Promise<void> {
+    return this.request<void>('GET', `/admin/inventories/property/${encodeURIComponent(propertyId)}/annex`);
+  }
+
+  /**  (PUT /admin/inventories/property/{propertyId}) */
+  inventorySave(propertyId: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/inventories/property/${encodeURIComponent(propertyId)}`);
+  }
+
+  /**  (POST /admin/inventories/property/{propertyId}/photos) */
+  inventoryUploadPhoto(propertyId: string): Promise<void> {
+    return this.request<void>('POST', `/admin/inventories/property/${encodeURIComponent(propertyId)}/photos`);
+  }
+
+  /** Accept an invitation (POST /invitations/accept) */
+  invitationAccept(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/invitations/accept`, { body });
+  }
+
+  /** List invitations (GET /admin/invitations) */
+  invitationGetAll(): Promise<invitationResponse[]> {
+    return this.request<invitationResponse[]>('GET', `/admin/invitations`);
+  }
+
+  /** Open an invitation link (GET /invitations/{token}) */
+  invitationGetByToken(token: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/invitations/${encodeURIComponent(token)}`);
+  }
+
+  /** Revoke an invitation (DELETE /admin/invitations/{id}) */
+  invitationRevoke(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/invitations/${encodeURIComponent(id)}`);
+  }
+
+  /** Send an invitation (POST /admin/invitations) */
+  invitationSend(body: InvitationInput): Promise<Invitation> {
+    return this.request<Invitation>('POST', `/admin/invitations`, { body });
+  }
+
+  /** Apply to rent an available property (POST /public/listings/{id}/applications) */
+  listingApply(id: string, body: ListingApplicationRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/public/listings/${encodeURIComponent(id)}/applications`, { body });
+  }
+
+  /**  (DELETE /admin/listings/{id}) */
+  listingDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/listings/${encodeURIComponent(id)}`);
+  }
+
+  /**  (DELETE /admin/listings/{id}/photos) */
+  listingDeletePhoto(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/listings/${encodeURIComponent(id)}/photos`);
+  }
+
+  /**  (GET /admin/listings) */
+  listingGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/listings`);
+  }
+
+  /**  (GET /admin/listings/{id}/applications) */
+  listingGetApplications(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/listings/${encodeURIComponent(id)}/applications`);
+  }
+
+  /**  (GET /admin/listings/{id}) */
+  listingGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/listings/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /public/listings/{id}) */
+  listingGetPublicListing(id: string): Promise<void> {
+    return this.request<void>('GET', `/public/listings/${encodeURIComponent(id)}`);
+  }
+
+  /** List the available properties (GET /public/listings) */
+  listingGetPublicListings(params: ListingGetPublicListingsParams = {}): Promise<PublicListing[]> {
+    return this.request<PublicListing[]>('GET', `/public/listings`, { query: { ...params } });
+  }
+
+  /** Iterates every item of listingGetPublicListings, requesting pages of pageSize items */
+  listingGetPublicListingsIter(params: Omit<ListingGetPublicListingsParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<PublicListing> {
+    return paginate(pageSize, (limit, offset) => this.listingGetPublicListings({ ...params, limit, offset }));
+  }
+
+  /** Create or update the listing of a property (PUT /admin/listings/property/{propertyId}) */
+  listingSave(propertyId: string, body: ListingRequest): Promise<PropertyListing> {
+    return this.request<PropertyListing>('PUT', `/admin/listings/property/${encodeURIComponent(propertyId)}`, { body });
+  }
+
+  /**  (PUT /admin/listing-applications/{id}) */
+  listingUpdateApplication(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/listing-applications/${encodeURIComponent(id)}`);
+  }
+
+  /** Change the status of a listing (PUT /admin/listings/{id}/status) */
+  listingUpdateStatus(id: string, body: ListingStatusRequest): Promise<PropertyListing> {
+    return this.request<PropertyListing>('PUT', `/admin/listings/${encodeURIComponent(id)}/status`, { body });
+  }
+
+  /**  (POST /admin/listings/{id}/photos) */
+  listingUploadPhoto(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/listings/${encodeURIComponent(id)}/photos`);
+  }
+
+  /**  (POST /maintenance-requests/{id}/comments) */
+  maintenanceRequestAddComment(id: string): Promise<void> {
+    return this.request<void>('POST', `/maintenance-requests/${encodeURIComponent(id)}/comments`);
+  }
+
+  /**  (PUT /admin/maintenance-requests/{id}/assign) */
+  maintenanceRequestAssignProvider(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/maintenance-requests/${encodeURIComponent(id)}/assign`);
+  }
+
+  /**  (POST /maintenance-requests) */
+  maintenanceRequestCreate(): Promise<void> {
+    return this.request<void>('POST', `/maintenance-requests`);
+  }
+
+  /**  (DELETE /admin/maintenance-requests/{id}) */
+  maintenanceRequestDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/maintenance-requests/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /maintenance-requests) */
+  maintenanceRequestGetAll(): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests`);
+  }
+
+  /**  (GET /maintenance-requests/{id}) */
+  maintenanceRequestGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /maintenance-requests/property/{propertyId}) */
+  maintenanceRequestGetByPropertyID(propertyId: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/property/${encodeURIComponent(propertyId)}`);
+  }
+
+  /**  (POST /maintenance-requests/property-ids) */
+  maintenanceRequestGetByPropertyIDs(): Promise<void> {
+    return this.request<void>('POST', `/maintenance-requests/property-ids`);
+  }
+
+  /**  (GET /maintenance-requests/renter/{renterId}) */
+  maintenanceRequestGetByRenterID(renterId: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/renter/${encodeURIComponent(renterId)}`);
+  }
+
+  /**  (GET /maintenance-requests/status/{status}) */
+  maintenanceRequestGetByStatus(status: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/status/${encodeURIComponent(status)}`);
+  }
+
+  /**  (GET /maintenance-requests/{id}/comments) */
+  maintenanceRequestGetComments(id: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/${encodeURIComponent(id)}/comments`);
+  }
+
+  /**  (GET /maintenance-requests/{id}/history) */
+  maintenanceRequestGetStatusHistory(id: string): Promise<void> {
+    return this.request<void>('GET', `/maintenance-requests/${encodeURIComponent(id)}/history`);
+  }
+
+  /**  (DELETE /admin/maintenance-requests/{id}/assign) */
+  maintenanceRequestUnassignProvider(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/maintenance-requests/${encodeURIComponent(id)}/assign`);
+  }
+
+  /**  (PUT /admin/maintenance-requests/{id}) */
+  maintenanceRequestUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/maintenance-requests/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /digest-subscription/me) */
+  managerDigestGetMine(): Promise<void> {
+    return this.request<void>('GET', `/digest-subscription/me`);
+  }
+
+  /**  (GET /digest-subscription/me/preview) */
+  managerDigestPreviewMine(): Promise<void> {
+    return this.request<void>('GET', `/digest-subscription/me/preview`);
+  }
+
+  /**  (POST /admin/digests/send) */
+  managerDigestSendDue(): Promise<void> {
+    return this.request<void>('POST', `/admin/digests/send`);
+  }
+
+  /**  (PUT /digest-subscription/me) */
+  managerDigestUpdateMine(): Promise<void> {
+    return this.request<void>('PUT', `/digest-subscription/me`);
+  }
+
+  /** Send invitation to a manager (POST /admin/invitations/manager) */
+  managerInvitationSendInvitation(body: InvitationRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/invitations/manager`, { body });
+  }
+
+  /** Register a new manager (POST /register/manager) */
+  managerRegistrationRegister(body: ManagerRegistrationRequest): Promise<ManagerRegistrationResponse> {
+    return this.request<ManagerRegistrationResponse>('POST', `/register/manager`, { body });
+  }
+
+  /**  (GET /admin/contract-signing/{id}/dossier) */
+  notaryGetDossier(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/contract-signing/${encodeURIComponent(id)}/dossier`);
+  }
+
+  /**  (GET /admin/notarizations/{id}/pdf) */
+  notaryServeStampedPDF(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/notarizations/${encodeURIComponent(id)}/pdf`);
+  }
+
+  /**  (POST /admin/contract-signing/{id}/notarize) */
+  notarySubmit(id: string): Promise<void> {
+    return this.request<void>('POST', `/admin/contract-signing/${encodeURIComponent(id)}/notarize`);
+  }
+
+  /**  (POST /public/notary/webhook) */
+  notaryWebhook(): Promise<void> {
+    return this.request<void>('POST', `/public/notary/webhook`);
+  }
+
+  /** List my notifications (GET /notifications) */
+  notificationGetMine(params: NotificationGetMineParams = {}): Promise<Notification[]> {
+    return this.request<Notification[]>('GET', `/notifications`, { query: { ...params } });
+  }
+
+  /** Iterates every item of notificationGetMine, requesting pages of pageSize items */
+  notificationGetMineIter(params: Omit<NotificationGetMineParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<Notification> {
+    return paginate(pageSize, (limit, offset) => this.notificationGetMine({ ...params, limit, offset }));
+  }
+
+  /** Count my unread notifications (GET /notifications/unread-count) */
+  notificationGetUnreadCount(): Promise<unknown> {
+    return this.request<unknown>('GET', `/notifications/unread-count`);
+  }
+
+  /** Mark all my notifications as read (PUT /notifications/read-all) */
+  notificationMarkAllRead(): Promise<unknown> {
+    return this.request<unknown>('PUT', `/notifications/read-all`);
+  }
+
+  /** Mark a notification as read (PUT /notifications/{id}/read) */
+  notificationMarkRead(id: string): Promise<void> {
+    return this.request<void>('PUT', `/notifications/${encodeURIComponent(id)}/read`);
+  }
+
+  /** Get notification preferences of a person (GET /admin/notification-preferences/{personId}) */
+  notificationPreferenceGetByPersonID(personId: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/admin/notification-preferences/${encodeURIComponent(personId)}`);
+  }
+
+  /** Get notification preferences from an email link (GET /public/notification-preferences/{token}) */
+  notificationPreferenceGetByToken(token: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/public/notification-preferences/${encodeURIComponent(token)}`);
+  }
+
+  /** Get my notification preferences (GET /notification-preferences/me) */
+  notificationPreferenceGetMine(): Promise<unknown> {
+    return this.request<unknown>('GET', `/notification-preferences/me`);
+  }
+
+  /** Unsubscribe from notification emails (POST /public/notification-preferences/{token}/unsubscribe) */
+  notificationPreferenceUnsubscribeByToken(token: string, body: UnsubscribeRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/public/notification-preferences/${encodeURIComponent(token)}/unsubscribe`, { body });
+  }
+
+  /** Update notification preferences of a person (PUT /admin/notification-preferences/{personId}) */
+  notificationPreferenceUpdateByPersonID(personId: string, body: NotificationPreferencesRequest): Promise<unknown> {
+    return this.request<unknown>('PUT', `/admin/notification-preferences/${encodeURIComponent(personId)}`, { body });
+  }
+
+  /** Update notification preferences from an email link (PUT /public/notification-preferences/{token}) */
+  notificationPreferenceUpdateByToken(token: string, body: NotificationPreferencesRequest): Promise<unknown> {
+    return this.request<unknown>('PUT', `/public/notification-preferences/${encodeURIComponent(token)}`, { body });
+  }
+
+  /** Update my notification preferences (PUT /notification-preferences/me) */
+  notificationPreferenceUpdateMine(body: NotificationPreferencesRequest): Promise<unknown> {
+    return this.request<unknown>('PUT', `/notification-preferences/me`, { body });
+  }
+
+  /**  (POST /admin/organizations) */
+  organizationCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/organizations`);
+  }
+
+  /**  (DELETE /admin/organizations/{id}) */
+  organizationDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/organizations/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/organizations) */
+  organizationGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/organizations`);
+  }
+
+  /**  (GET /admin/organizations/{id}) */
+  organizationGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/organizations/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /public/organization) */
+  organizationGetCurrent(): Promise<void> {
+    return this.request<void>('GET', `/public/organization`);
+  }
+
+  /**  (PUT /admin/organizations/{id}) */
+  organizationUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/organizations/${encodeURIComponent(id)}`);
+  }
+
+  /** Request a password reset (POST /auth/forgot-password) */
+  passwordResetForgotPassword(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/auth/forgot-password`, { body });
+  }
+
+  /** Reset a forgotten password (POST /auth/reset-password) */
+  passwordResetResetPassword(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/auth/reset-password`, { body });
+  }
+
+  /** Pay this month's rent online (POST /payment-checkouts) */
+  paymentCheckoutCreate(): Promise<RentCheckout> {
+    return this.request<RentCheckout>('POST', `/payment-checkouts`);
+  }
+
+  /** Get the state of an online payment (GET /payment-checkouts/{reference}) */
+  paymentCheckoutGetByReference(reference: string): Promise<PaymentCheckout> {
+    return this.request<PaymentCheckout>('GET', `/payment-checkouts/${encodeURIComponent(reference)}`);
+  }
+
+  /** Payment gateway webhook (POST /public/payment-gateway/webhook) */
+  paymentCheckoutWebhook(): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>('POST', `/public/payment-gateway/webhook`);
+  }
+
+  /**  (GET /my/payments/statement) */
+  paymentStatementGetMyStatement(): Promise<void> {
+    return this.request<void>('GET', `/my/payments/statement`);
+  }
+
+  /**  (GET /public/payment-statements/verify/{id}) */
+  paymentStatementVerify(id: string): Promise<void> {
+    return this.request<void>('GET', `/public/payment-statements/verify/${encodeURIComponent(id)}`);
+  }
+
+  /** Create a new person (POST /persons) */
+  personCreate(body: Person): Promise<Person> {
+    return this.request<Person>('POST', `/persons`, { body });
+  }
+
+  /** Delete a person (DELETE /persons/{id}) */
+  personDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/persons/${encodeURIComponent(id)}`);
+  }
+
+  /** Get all persons (role-based) (GET /persons) */
+  personGetAll(params: PersonGetAllParams = {}): Promise<Person[]> {
+    return this.request<Person[]>('GET', `/persons`, { query: { ...params } });
+  }
+
+  /** Iterates every item of personGetAll, requesting pages of pageSize items */
+  personGetAllIter(params: Omit<PersonGetAllParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<Person> {
+    return paginate(pageSize, (limit, offset) => this.personGetAll({ ...params, limit, offset }));
+  }
+
+  /** Get person by ID (GET /persons/{id}) */
+  personGetByID(id: string): Promise<Person> {
+    return this.request<Person>('GET', `/persons/${encodeURIComponent(id)}`);
+  }
+
+  /** Get persons by role (GET /persons/role/{role}) */
+  personGetByRole(role: string): Promise<Person[]> {
+    return this.request<Person[]>('GET', `/persons/role/${encodeURIComponent(role)}`);
+  }
+
+  /** Update a person (PUT /persons/{id}) */
+  personUpdate(id: string, body: Person): Promise<Person> {
+    return this.request<Person>('PUT', `/persons/${encodeURIComponent(id)}`, { body });
+  }
+
+  /**  (POST /admin/pricing) */
+  pricingCreatePricing(): Promise<void> {
+    return this.request<void>('POST', `/admin/pricing`);
+  }
+
+  /**  (DELETE /admin/pricing/{id}) */
+  pricingDeletePricing(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/pricing/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/pricing) */
+  pricingGetAllPricing(): Promise<void> {
+    return this.request<void>('GET', `/admin/pricing`);
+  }
+
+  /**  (GET /admin/pricing/{id}/breakdown) */
+  pricingGetPricingBreakdown(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/pricing/${encodeURIComponent(id)}/breakdown`);
+  }
+
+  /**  (GET /admin/pricing/{id}) */
+  pricingGetPricingByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/pricing/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/pricing/rental/{rentalId}) */
+  pricingGetPricingByRentalID(rentalId: string): Promise<void> {
+    return this.request<void>('GET', `/admin/pricing/rental/${encodeURIComponent(rentalId)}`);
+  }
+
+  /**  (GET /admin/pricing/summary) */
+  pricingGetPricingSummary(): Promise<void> {
+    return this.request<void>('GET', `/admin/pricing/summary`);
+  }
+
+  /**  (PUT /admin/pricing/{id}) */
+  pricingUpdatePricing(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/pricing/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /admin/promotions) */
+  promotionCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/promotions`);
+  }
+
+  /**  (DELETE /admin/promotions/{id}) */
+  promotionDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/promotions/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/promotions) */
+  promotionGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/promotions`);
+  }
+
+  /**  (GET /admin/promotions/{id}) */
+  promotionGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/promotions/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /promotions/property/{propertyId}/offer) */
+  promotionGetOffer(propertyId: string): Promise<void> {
+    return this.request<void>('GET', `/promotions/property/${encodeURIComponent(propertyId)}/offer`);
+  }
+
+  /**  (GET /admin/promotions/stats) */
+  promotionGetStats(): Promise<void> {
+    return this.request<void>('GET', `/admin/promotions/stats`);
+  }
+
+  /**  (PUT /admin/promotions/{id}) */
+  promotionUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/promotions/${encodeURIComponent(id)}`);
+  }
+
+  /** Create a new property (POST /properties) */
+  propertyCreate(body: Property): Promise<Property> {
+    return this.request<Property>('POST', `/properties`, { body });
+  }
+
+  /** Delete a property (DELETE /admin/properties/{id}) */
+  propertyDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/properties/${encodeURIComponent(id)}`);
+  }
+
+  /** Delete a property photo (DELETE /admin/properties/{id}/photos/{photoId}) */
+  propertyDeletePhoto(id: string, photoId: string): Promise<PropertyPhoto[]> {
+    return this.request<PropertyPhoto[]>('DELETE', `/admin/properties/${encodeURIComponent(id)}/photos/${encodeURIComponent(photoId)}`);
+  }
+
+  /** Get properties (role-based) (GET /properties) */
+  propertyGetAll(params: PropertyGetAllParams = {}): Promise<Property[]> {
+    return this.request<Property[]>('GET', `/properties`, { query: { ...params } });
+  }
+
+  /** Iterates every item of propertyGetAll, requesting pages of pageSize items */
+  propertyGetAllIter(params: Omit<PropertyGetAllParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<Property> {
+    return paginate(pageSize, (limit, offset) => this.propertyGetAll({ ...params, limit, offset }));
+  }
+
+  /** Get property by ID (GET /properties/{id}) */
+  propertyGetByID(id: string): Promise<Property> {
+    return this.request<Property>('GET', `/properties/${encodeURIComponent(id)}`);
+  }
+
+  /** Get properties by manager ID (GET /properties/manager/{managerId}) */
+  propertyGetByManagerID(managerId: string): Promise<Property[]> {
+    return this.request<Property[]>('GET', `/properties/manager/${encodeURIComponent(managerId)}`);
+  }
+
+  /** Get properties by resident ID (GET /properties/resident/{residentId}) */
+  propertyGetByResident(residentId: string): Promise<Property[]> {
+    return this.request<Property[]>('GET', `/properties/resident/${encodeURIComponent(residentId)}`);
+  }
+
+  /** Get properties for a specific user (GET /properties/user/{userId}) */
+  propertyGetByUserID(userId: string): Promise<Property[]> {
+    return this.request<Property[]>('GET', `/properties/user/${encodeURIComponent(userId)}`);
+  }
+
+  /** Reorder the photos of a property (PUT /admin/properties/{id}/photos/order) */
+  propertyReorderPhotos(id: string, body: ReorderPhotosRequest): Promise<PropertyPhoto[]> {
+    return this.request<PropertyPhoto[]>('PUT', `/admin/properties/${encodeURIComponent(id)}/photos/order`, { body });
+  }
+
+  /** Update a property (PUT /admin/properties/{id}) */
+  propertyUpdate(id: string, body: Property): Promise<Property> {
+    return this.request<Property>('PUT', `/admin/properties/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Caption a property photo (PUT /admin/properties/{id}/photos/{photoId}) */
+  propertyUpdatePhoto(id: string, photoId: string, body: UpdatePhotoRequest): Promise<PropertyPhoto> {
+    return this.request<PropertyPhoto>('PUT', `/admin/properties/${encodeURIComponent(id)}/photos/${encodeURIComponent(photoId)}`, { body });
+  }
+
+  /**  (POST /reglamentos/{id}/acknowledge) */
+  reglamentoAcknowledge(id: string): Promise<void> {
+    return this.request<void>('POST', `/reglamentos/${encodeURIComponent(id)}/acknowledge`);
+  }
+
+  /**  (GET /reglamentos/compliance) */
+  reglamentoGetCompliance(): Promise<void> {
+    return this.request<void>('GET', `/reglamentos/compliance`);
+  }
+
+  /**  (GET /reglamentos/pending) */
+  reglamentoGetPending(): Promise<void> {
+    return this.request<void>('GET', `/reglamentos/pending`);
+  }
+
+  /**  (GET /reglamentos/{id}/pdf) */
+  reglamentoServePDF(id: string): Promise<void> {
+    return this.request<void>('GET', `/reglamentos/${encodeURIComponent(id)}/pdf`);
+  }
+
+  /**  (POST /reglamentos) */
+  reglamentoUpload(): Promise<void> {
+    return this.request<void>('POST', `/reglamentos`);
+  }
+
+  /**  (GET /admin/reminder-preferences/{personId}) */
+  reminderPreferenceGetByPersonID(personId: string): Promise<void> {
+    return this.request<void>('GET', `/admin/reminder-preferences/${encodeURIComponent(personId)}`);
+  }
+
+  /**  (GET /reminder-preferences/me) */
+  reminderPreferenceGetMine(): Promise<void> {
+    return this.request<void>('GET', `/reminder-preferences/me`);
+  }
+
+  /**  (PUT /admin/reminder-preferences/{personId}) */
+  reminderPreferenceUpdateByPersonID(personId: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/reminder-preferences/${encodeURIComponent(personId)}`);
+  }
+
+  /**  (PUT /reminder-preferences/me) */
+  reminderPreferenceUpdateMine(): Promise<void> {
+    return this.request<void>('PUT', `/reminder-preferences/me`);
+  }
+
+  /**  (POST /admin/payments) */
+  rentPaymentCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/payments`);
+  }
+
+  /**  (DELETE /admin/payments/{id}) */
+  rentPaymentDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/payments/${encodeURIComponent(id)}`);
+  }
+
+  /** Get all rent payments (Admin only) (GET /payments) */
+  rentPaymentGetAll(): Promise<RentPayment[]> {
+    return this.request<RentPayment[]>('GET', `/payments`);
+  }
+
+  /**  (GET /admin/payments/date-range) */
+  rentPaymentGetByDateRange(): Promise<void> {
+    return this.request<void>('GET', `/admin/payments/date-range`);
+  }
+
+  /** Get rent payment by ID (GET /payments/{id}) */
+  rentPaymentGetByID(id: string): Promise<RentPayment> {
+    return this.request<RentPayment>('GET', `/payments/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /payments/rental/{rentalId}) */
+  rentPaymentGetByRentalID(rentalId: string): Promise<void> {
+    return this.request<void>('GET', `/payments/rental/${encodeURIComponent(rentalId)}`);
+  }
+
+  /** Get payments by multiple rental IDs (GET /payments/rental-ids) */
+  rentPaymentGetByRentalIDs(body: string[]): Promise<RentPayment[]> {
+    return this.request<RentPayment[]>('GET', `/payments/rental-ids`, { body });
+  }
+
+  /**  (GET /admin/payments/late) */
+  rentPaymentGetLatePayments(): Promise<void> {
+    return this.request<void>('GET', `/admin/payments/late`);
+  }
+
+  /**  (PUT /admin/payments/{id}) */
+  rentPaymentUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/payments/${encodeURIComponent(id)}`);
+  }
+
+  /** Create a new rental (POST /admin/rentals) */
+  rentalCreate(body: Rental): Promise<Rental> {
+    return this.request<Rental>('POST', `/admin/rentals`, { body });
+  }
+
+  /** Delete a rental (DELETE /admin/rentals/{id}) */
+  rentalDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/rentals/${encodeURIComponent(id)}`);
+  }
+
+  /** Get rentals (role-based for admin/manager) (GET /rentals) */
+  rentalGetAll(): Promise<Rental[]> {
+    return this.request<Rental[]>('GET', `/rentals`);
+  }
+
+  /** Get rental by ID (GET /rentals/{id}) */
+  rentalGetByID(id: string): Promise<Rental> {
+    return this.request<Rental>('GET', `/rentals/${encodeURIComponent(id)}`);
+  }
+
+  /** Get rentals by property ID (GET /rentals/by-property/{property_id}) */
+  rentalGetByPropertyID(property_id: string): Promise<Rental[]> {
+    return this.request<Rental[]>('GET', `/rentals/by-property/${encodeURIComponent(property_id)}`);
+  }
+
+  /** Get rentals by renter ID (GET /rentals/by-renter/{renter_id}) */
+  rentalGetByRenterID(renter_id: string): Promise<Rental[]> {
+    return this.request<Rental[]>('GET', `/rentals/by-renter/${encodeURIComponent(renter_id)}`);
+  }
+
+  /**  (POST /admin/rental-history) */
+  rentalHistoryCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/rental-history`);
+  }
+
+  /**  (DELETE /admin/rental-history/{id}) */
+  rentalHistoryDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/rental-history/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /rental-history) */
+  rentalHistoryGetAll(): Promise<void> {
+    return this.request<void>('GET', `/rental-history`);
+  }
+
+  /**  (GET /rental-history/date-range) */
+  rentalHistoryGetByDateRange(): Promise<void> {
+    return this.request<void>('GET', `/rental-history/date-range`);
+  }
+
+  /**  (GET /rental-history/{id}) */
+  rentalHistoryGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/rental-history/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /rental-history/person/{personId}) */
+  rentalHistoryGetByPersonID(personId: string): Promise<void> {
+    return this.request<void>('GET', `/rental-history/person/${encodeURIComponent(personId)}`);
+  }
+
+  /**  (GET /rental-history/rental/{rentalId}) */
+  rentalHistoryGetByRentalID(rentalId: string): Promise<void> {
+    return this.request<void>('GET', `/rental-history/rental/${encodeURIComponent(rentalId)}`);
+  }
+
+  /**  (GET /rental-history/status/{status}) */
+  rentalHistoryGetByStatus(status: string): Promise<void> {
+    return this.request<void>('GET', `/rental-history/status/${encodeURIComponent(status)}`);
+  }
+
+  /**  (POST /rental-history/for-rentals) */
+  rentalHistoryGetMultipleByRentalIDs(): Promise<void> {
+    return this.request<void>('POST', `/rental-history/for-rentals`);
+  }
+
+  /** Occupancy timeline of a property (GET /properties/{id}/occupancy) */
+  rentalHistoryGetPropertyOccupancy(id: string, params: RentalHistoryGetPropertyOccupancyParams = {}): Promise<OccupancyTimeline> {
+    return this.request<OccupancyTimeline>('GET', `/properties/${encodeURIComponent(id)}/occupancy`, { query: { ...params } });
+  }
+
+  /**  (PUT /admin/rental-history/{id}) */
+  rentalHistoryUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/rental-history/${encodeURIComponent(id)}`);
+  }
+
+  /** Add a party to a rental contract (POST /admin/contracts/{id}/parties) */
+  rentalPartyCreate(id: string, body: RentalPartyRequest): Promise<RentalPartyResponse> {
+    return this.request<RentalPartyResponse>('POST', `/admin/contracts/${encodeURIComponent(id)}/parties`, { body });
+  }
+
+  /**  (DELETE /admin/contracts/{id}/parties/{partyId}) */
+  rentalPartyDelete(id: string, partyId: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/contracts/${encodeURIComponent(id)}/parties/${encodeURIComponent(partyId)}`);
+  }
+
+  /** List the parties of a rental contract (GET /admin/contracts/{id}/parties) */
+  rentalPartyGetByRentalID(id: string): Promise<RentalPartyResponse[]> {
+    return this.request<RentalPartyResponse[]>('GET', `/admin/contracts/${encodeURIComponent(id)}/parties`);
+  }
+
+  /** Update a rental (PUT /admin/rentals/{id}) */
+  rentalUpdate(id: string, body: Rental): Promise<Rental> {
+    return this.request<Rental>('PUT', `/admin/rentals/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Full-text search (GET /search) */
+  searchSearch(params: SearchSearchParams = {}): Promise<SearchResult[]> {
+    return this.request<SearchResult[]>('GET', `/search`, { query: { ...params } });
+  }
+
+  /** Record the security deposit of a rental (POST /admin/contracts/{id}/deposit) */
+  securityDepositCreate(id: string, body: SecurityDepositRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/contracts/${encodeURIComponent(id)}/deposit`, { body });
+  }
+
+  /** Get the security deposit of a rental (GET /admin/contracts/{id}/deposit) */
+  securityDepositGetByRentalID(id: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/admin/contracts/${encodeURIComponent(id)}/deposit`);
+  }
+
+  /**  (GET /admin/deposits/deduction-categories) */
+  securityDepositGetDeductionCategories(): Promise<void> {
+    return this.request<void>('GET', `/admin/deposits/deduction-categories`);
+  }
+
+  /** List my security deposits (GET /deposits/me) */
+  securityDepositGetMine(): Promise<unknown[]> {
+    return this.request<unknown[]>('GET', `/deposits/me`);
+  }
+
+  /** Issue the move-out settlement of a deposit (POST /admin/deposits/{id}/settlement) */
+  securityDepositIssueSettlement(id: string, body: IssueSettlementRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/deposits/${encodeURIComponent(id)}/settlement`, { body });
+  }
+
+  /** Record the refund of a deposit (POST /admin/deposits/{id}/refund) */
+  securityDepositRefund(id: string, body: RefundDepositRequest): Promise<unknown> {
+    return this.request<unknown>('POST', `/admin/deposits/${encodeURIComponent(id)}/refund`, { body });
+  }
+
+  /** Remove a deduction from a security deposit (DELETE /admin/deposits/{id}/deductions/{deductionId}) */
+  securityDepositRemoveDeduction(id: string, deductionId: string): Promise<unknown> {
+    return this.request<unknown>('DELETE', `/admin/deposits/${encodeURIComponent(id)}/deductions/${encodeURIComponent(deductionId)}`);
+  }
+
+  /** Sign the settlement of my deposit (POST /deposits/{id}/settlement/sign) */
+  securityDepositSignSettlement(id: string, body: SignerLocation): Promise<unknown> {
+    return this.request<unknown>('POST', `/deposits/${encodeURIComponent(id)}/settlement/sign`, { body });
+  }
+
+  /** Update a security deposit (PUT /admin/deposits/{id}) */
+  securityDepositUpdate(id: string, body: SecurityDepositRequest): Promise<unknown> {
+    return this.request<unknown>('PUT', `/admin/deposits/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Verify a signed deposit settlement (GET /public/deposit-settlements/verify/{id}) */
+  securityDepositVerifySettlement(id: string): Promise<unknown> {
+    return this.request<unknown>('GET', `/public/deposit-settlements/verify/${encodeURIComponent(id)}`);
+  }
+
+  /**  (POST /admin/service-providers) */
+  serviceProviderCreate(): Promise<void> {
+    return this.request<void>('POST', `/admin/service-providers`);
+  }
+
+  /**  (DELETE /admin/service-providers/{id}) */
+  serviceProviderDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/admin/service-providers/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /admin/service-providers) */
+  serviceProviderGetAll(): Promise<void> {
+    return this.request<void>('GET', `/admin/service-providers`);
+  }
+
+  /**  (GET /admin/service-providers/{id}) */
+  serviceProviderGetByID(id: string): Promise<void> {
+    return this.request<void>('GET', `/admin/service-providers/${encodeURIComponent(id)}`);
+  }
+
+  /**  (PUT /admin/service-providers/{id}) */
+  serviceProviderUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/admin/service-providers/${encodeURIComponent(id)}`);
+  }
+
+  /** List my active sessions (GET /users/me/sessions) */
+  sessionGetMine(): Promise<sessionResponse[]> {
+    return this.request<sessionResponse[]>('GET', `/users/me/sessions`);
+  }
+
+  /** Revoke one of my sessions (DELETE /users/me/sessions/{id}) */
+  sessionRevokeMine(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/users/me/sessions/${encodeURIComponent(id)}`);
+  }
+
+  /**  (DELETE /admin/signing-certificate) */
+  signingCertificateDelete(): Promise<void> {
+    return this.request<void>('DELETE', `/admin/signing-certificate`);
+  }
+
+  /**  (GET /admin/signing-certificate) */
+  signingCertificateGet(): Promise<void> {
+    return this.request<void>('GET', `/admin/signing-certificate`);
+  }
+
+  /**  (POST /admin/signing-certificate/reload) */
+  signingCertificateReload(): Promise<void> {
+    return this.request<void>('POST', `/admin/signing-certificate/reload`);
+  }
+
+  /**  (POST /admin/signing-certificate) */
+  signingCertificateUpload(): Promise<void> {
+    return this.request<void>('POST', `/admin/signing-certificate`);
+  }
+
+  /** List the platform subscriptions (GET /admin/subscriptions) */
+  subscriptionGetAll(): Promise<PlatformSubscription[]> {
+    return this.request<PlatformSubscription[]>('GET', `/admin/subscriptions`);
+  }
+
+  /** Get my platform subscription (GET /subscription/me) */
+  subscriptionGetMine(): Promise<PlatformSubscription> {
+    return this.request<PlatformSubscription>('GET', `/subscription/me`);
+  }
+
+  /** Subscribe a manager to the platform plan (POST /admin/users/{id}/subscription) */
+  subscriptionSubscribe(id: string): Promise<PlatformSubscription> {
+    return this.request<PlatformSubscription>('POST', `/admin/users/${encodeURIComponent(id)}/subscription`);
+  }
+
+  /** Subscription billing webhook (POST /public/subscriptions/webhook) */
+  subscriptionWebhook(): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>('POST', `/public/subscriptions/webhook`);
+  }
+
+  /** Bulk user operation (POST /admin/users/bulk) */
+  userBulkCreateJob(body: UserBulkRequest): Promise<UserBulkJob> {
+    return this.request<UserBulkJob>('POST', `/admin/users/bulk`, { body });
+  }
+
+  /** Get bulk user job (GET /admin/users/bulk/{id}) */
+  userBulkGetJob(id: string): Promise<UserBulkJob> {
+    return this.request<UserBulkJob>('GET', `/admin/users/bulk/${encodeURIComponent(id)}`);
+  }
+
+  /** List bulk user jobs (GET /admin/users/bulk) */
+  userBulkListJobs(): Promise<UserBulkJob[]> {
+    return this.request<UserBulkJob[]>('GET', `/admin/users/bulk`);
+  }
+
+  /** Change user password (PUT /users/{id}/change-password) */
+  userChangePassword(id: string, body: unknown): Promise<unknown> {
+    return this.request<unknown>('PUT', `/users/${encodeURIComponent(id)}/change-password`, { body });
+  }
+
+  /** Create a new user (POST /users) */
+  userCreate(body: User): Promise<User> {
+    return this.request<User>('POST', `/users`, { body });
+  }
+
+  /** Delete a user (DELETE /users/{id}) */
+  userDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/users/${encodeURIComponent(id)}`);
+  }
+
+  /** Get all users (GET /users) */
+  userGetAll(params: UserGetAllParams = {}): Promise<User[]> {
+    return this.request<User[]>('GET', `/users`, { query: { ...params } });
+  }
+
+  /** Iterates every item of userGetAll, requesting pages of pageSize items */
+  userGetAllIter(params: Omit<UserGetAllParams, 'limit' | 'offset'> = {}, pageSize?: number): AsyncGenerator<User> {
+    return paginate(pageSize, (limit, offset) => this.userGetAll({ ...params, limit, offset }));
+  }
+
+  /** Get user by email (GET /users/email) */
+  userGetByEmail(params: UserGetByEmailParams = {}): Promise<User> {
+    return this.request<User>('GET', `/users/email`, { query: { ...params } });
+  }
+
+  /** Get user by ID (GET /users/{id}) */
+  userGetByID(id: string): Promise<User> {
+    return this.request<User>('GET', `/users/${encodeURIComponent(id)}`);
+  }
+
+  /** Recent login attempts (GET /admin/login-attempts) */
+  userGetLoginAttempts(params: UserGetLoginAttemptsParams = {}): Promise<LoginAttempt[]> {
+    return this.request<LoginAttempt[]>('GET', `/admin/login-attempts`, { query: { ...params } });
+  }
+
+  /** Login user (POST /users/login) */
+  userLogin(body: LoginRequest): Promise<LoginResponse> {
+    return this.request<LoginResponse>('POST', `/users/login`, { body });
+  }
+
+  /** Register a new user (POST /auth/register) */
+  userRegistrationRegister(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/auth/register`, { body });
+  }
+
+  /** Resend the verification email (POST /auth/resend-verification) */
+  userRegistrationResendVerification(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/auth/resend-verification`, { body });
+  }
+
+  /** Verify the email of a registered user (POST /auth/verify-email) */
+  userRegistrationVerifyEmail(body: unknown): Promise<unknown> {
+    return this.request<unknown>('POST', `/auth/verify-email`, { body });
+  }
+
+  /** Update a user (PUT /users/{id}) */
+  userUpdate(id: string, body: User): Promise<User> {
+    return this.request<User>('PUT', `/users/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Set my language (PUT /users/me/locale) */
+  userUpdateMyLocale(body: unknown): Promise<unknown> {
+    return this.request<unknown>('PUT', `/users/me/locale`, { body });
+  }
+
+  /**  (POST /webhooks) */
+  webhookCreate(): Promise<void> {
+    return this.request<void>('POST', `/webhooks`);
+  }
+
+  /**  (DELETE /webhooks/{id}) */
+  webhookDelete(id: string): Promise<void> {
+    return this.request<void>('DELETE', `/webhooks/${encodeURIComponent(id)}`);
+  }
+
+  /**  (GET /webhooks/{id}/deliveries) */
+  webhookGetDeliveries(id: string): Promise<void> {
+    return this.request<void>('GET', `/webhooks/${encodeURIComponent(id)}/deliveries`);
+  }
+
+  /**  (GET /webhooks) */
+  webhookList(): Promise<void> {
+    return this.request<void>('GET', `/webhooks`);
+  }
+
+  /**  (GET /webhooks/events) */
+  webhookListEvents(): Promise<void> {
+    return this.request<void>('GET', `/webhooks/events`);
+  }
+
+  /**  (POST /webhooks/{id}/test) */
+  webhookSendTest(id: string): Promise<void> {
+    return this.request<void>('POST', `/webhooks/${encodeURIComponent(id)}/test`);
+  }
+
+  /**  (PUT /webhooks/{id}) */
+  webhookUpdate(id: string): Promise<void> {
+    return this.request<void>('PUT', `/webhooks/${encodeURIComponent(id)}`);
+  }
+}
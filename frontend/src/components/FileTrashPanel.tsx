@@ -0,0 +1,101 @@
+import { ActionIcon, Group, Paper, Table, Text, Title, Tooltip } from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { IconArrowBackUp, IconTrash, IconTrashX } from '@tabler/icons-react';
+import { fileTrashApi } from '../api/apiService';
+import type { TrashedFile } from '../types';
+
+// Files deleted by admins, kept for the retention period so they can be restored
+export default function FileTrashPanel({ onRestored }: { onRestored?: () => void }) {
+  const queryClient = useQueryClient();
+  const { data, isError } = useQuery({
+    queryKey: ['file-trash'],
+    queryFn: fileTrashApi.getAll,
+    retry: false,
+  });
+
+  // The trash is disabled when files are deleted immediately
+  if (isError || !data) {
+    return null;
+  }
+
+  const refresh = () => queryClient.invalidateQueries({ queryKey: ['file-trash'] });
+
+  const restore = async (file: TrashedFile) => {
+    try {
+      await fileTrashApi.restore(file.id);
+      notifications.show({ title: 'Archivo restaurado', message: file.path, color: 'green' });
+      refresh();
+      onRestored?.();
+    } catch (error) {
+      console.error('Error restoring file:', error);
+      notifications.show({ title: 'Error', message: 'No se pudo restaurar el archivo', color: 'red' });
+    }
+  };
+
+  const purge = async (file: TrashedFile) => {
+    if (!window.confirm(`¿Eliminar definitivamente ${file.file_name}? No se podrá recuperar.`)) {
+      return;
+    }
+    try {
+      await fileTrashApi.purge(file.id);
+      notifications.show({ title: 'Archivo eliminado', message: file.path, color: 'green' });
+      refresh();
+    } catch (error) {
+      console.error('Error purging file:', error);
+      notifications.show({ title: 'Error', message: 'No se pudo eliminar el archivo', color: 'red' });
+    }
+  };
+
+  return (
+    <Paper shadow="sm" p="md" mt="md">
+      <Group gap="xs" mb="sm">
+        <IconTrash size={20} />
+        <Title order={4}>Papelera</Title>
+        <Text size="sm" c="dimmed">Los archivos se eliminan definitivamente a los {data.retention_days} días</Text>
+      </Group>
+      {data.files.length === 0 ? (
+        <Text size="sm" c="dimmed">La papelera está vacía.</Text>
+      ) : (
+        <Table striped>
+          <Table.Thead>
+            <Table.Tr>
+              <Table.Th>Archivo</Table.Th>
+              <Table.Th>Eliminado por</Table.Th>
+              <Table.Th>Eliminado</Table.Th>
+              <Table.Th>Se purga</Table.Th>
+              <Table.Th>Acciones</Table.Th>
+            </Table.Tr>
+          </Table.Thead>
+          <Table.Tbody>
+            {data.files.map((file) => (
+              <Table.Tr key={file.id}>
+                <Table.Td>
+                  <Text size="sm">{file.file_name}</Text>
+                  <Text size="xs" c="dimmed" ff="monospace">{file.path}</Text>
+                </Table.Td>
+                <Table.Td>{file.deleted_by}</Table.Td>
+                <Table.Td>{new Date(file.deleted_at).toLocaleString('es-CO')}</Table.Td>
+                <Table.Td>{new Date(file.purge_at).toLocaleDateString('es-CO')}</Table.Td>
+                <Table.Td>
+                  <Group gap="xs" wrap="nowrap">
+                    <Tooltip label="Restaurar">
+                      <ActionIcon variant="subtle" color="green" onClick={() => restore(file)}>
+                        <IconArrowBackUp size={16} />
+                      </ActionIcon>
+                    </Tooltip>
+                    <Tooltip label="Eliminar definitivamente">
+                      <ActionIcon variant="subtle" color="red" onClick={() => purge(file)}>
+                        <IconTrashX size={16} />
+                      </ActionIcon>
+                    </Tooltip>
+                  </Group>
+                </Table.Td>
+              </Table.Tr>
+            ))}
+          </Table.Tbody>
+        </Table>
+      )}
+    </Paper>
+  );
+}
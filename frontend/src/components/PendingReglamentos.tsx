@@ -0,0 +1,94 @@
+import { useState } from 'react';
+import { Alert, Button, Checkbox, Group, Modal, Stack, Text } from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { IconBook } from '@tabler/icons-react';
+import { reglamentoApi } from '../api/apiService';
+import type { Reglamento } from '../types';
+
+// Asks tenants to read and accept the current manual de convivencia of their buildings
+export default function PendingReglamentos() {
+  const queryClient = useQueryClient();
+  const [selected, setSelected] = useState<Reglamento | null>(null);
+  const [pdfUrl, setPdfUrl] = useState<string | null>(null);
+  const [accepted, setAccepted] = useState(false);
+  const [saving, setSaving] = useState(false);
+
+  const { data: pending = [] } = useQuery({
+    queryKey: ['reglamentos', 'pending'],
+    queryFn: reglamentoApi.getPending,
+  });
+
+  if (pending.length === 0) {
+    return null;
+  }
+
+  const open = async (reglamento: Reglamento) => {
+    setSelected(reglamento);
+    setAccepted(false);
+    try {
+      const blob = await reglamentoApi.getPdf(reglamento.id);
+      setPdfUrl(URL.createObjectURL(blob));
+    } catch (error) {
+      console.error('Error loading reglamento:', error);
+      notifications.show({ title: 'Error', message: 'No se pudo cargar el manual de convivencia', color: 'red' });
+    }
+  };
+
+  const close = () => {
+    if (pdfUrl) {
+      URL.revokeObjectURL(pdfUrl);
+    }
+    setPdfUrl(null);
+    setSelected(null);
+  };
+
+  const acknowledge = async () => {
+    if (!selected) return;
+    setSaving(true);
+    try {
+      await reglamentoApi.acknowledge(selected.id);
+      notifications.show({ title: 'Gracias', message: 'Registramos que aceptaste el manual de convivencia', color: 'green' });
+      close();
+      queryClient.invalidateQueries({ queryKey: ['reglamentos', 'pending'] });
+    } catch (error) {
+      console.error('Error acknowledging reglamento:', error);
+      notifications.show({ title: 'Error', message: 'No se pudo registrar la aceptación', color: 'red' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <>
+      <Stack mb="lg">
+        {pending.map((reglamento) => (
+          <Alert key={reglamento.id} icon={<IconBook size={20} />} title="Manual de convivencia pendiente" color="orange">
+            <Group justify="space-between">
+              <Text size="sm">
+                El edificio de {reglamento.address} publicó la versión {reglamento.version} de su manual de convivencia. Debes leerlo y aceptarlo.
+              </Text>
+              <Button size="xs" onClick={() => open(reglamento)}>Leer y aceptar</Button>
+            </Group>
+          </Alert>
+        ))}
+      </Stack>
+
+      <Modal opened={selected !== null} onClose={close} title="Manual de convivencia" size="xl">
+        {pdfUrl && (
+          <iframe src={pdfUrl} title="Manual de convivencia" style={{ width: '100%', height: '60vh', border: 0 }} />
+        )}
+        <Checkbox
+          mt="md"
+          checked={accepted}
+          onChange={(event) => setAccepted(event.currentTarget.checked)}
+          label="Leí el manual de convivencia y me comprometo a cumplirlo"
+        />
+        <Group justify="flex-end" mt="md">
+          <Button variant="default" onClick={close}>Cerrar</Button>
+          <Button onClick={acknowledge} disabled={!accepted} loading={saving}>Aceptar</Button>
+        </Group>
+      </Modal>
+    </>
+  );
+}
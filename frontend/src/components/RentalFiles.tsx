@@ -0,0 +1,55 @@
+import { ActionIcon, Badge, Group, Loader, Stack, Text } from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { useQuery } from '@tanstack/react-query';
+import { IconDownload, IconFile } from '@tabler/icons-react';
+import { rentalApi } from '../api/apiService';
+import type { RentalFile } from '../types';
+import { fileCategoryLabel } from '../types/fileCategories';
+
+// Lists the documents uploaded for a rental or its contract
+export default function RentalFiles({ rentalId }: { rentalId: string }) {
+  const { data: files = [], isLoading } = useQuery({
+    queryKey: ['rentals', rentalId, 'files'],
+    queryFn: () => rentalApi.getFiles(rentalId),
+    enabled: !!rentalId,
+  });
+
+  const download = async (file: RentalFile) => {
+    try {
+      const blob = await rentalApi.downloadFile(rentalId, file.id);
+      const url = URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = file.original_name || file.file_name;
+      a.click();
+      URL.revokeObjectURL(url);
+    } catch (error) {
+      console.error('Error downloading rental file:', error);
+      notifications.show({ title: 'Error', message: 'No se pudo descargar el documento', color: 'red' });
+    }
+  };
+
+  return (
+    <Stack gap="xs">
+      <Text fw={500}>Documentos</Text>
+      {isLoading ? (
+        <Loader size="sm" />
+      ) : files.length === 0 ? (
+        <Text size="sm" c="dimmed">No hay documentos vinculados a este alquiler.</Text>
+      ) : (
+        files.map((file) => (
+          <Group key={file.id} justify="space-between" wrap="nowrap">
+            <Group gap="xs" wrap="nowrap">
+              <IconFile size={16} />
+              <Text size="sm">{file.original_name || file.file_name}</Text>
+              {file.category && <Badge size="xs" color="grape">{fileCategoryLabel(file.category)}</Badge>}
+            </Group>
+            <ActionIcon variant="subtle" color="blue" onClick={() => download(file)} title="Descargar">
+              <IconDownload size={16} />
+            </ActionIcon>
+          </Group>
+        ))
+      )}
+    </Stack>
+  );
+}
@@ -13,7 +13,7 @@ import {
   ActionIcon,
   Button
 } from '@mantine/core';
-import { IconHome, IconBuilding, IconUser, IconTools, IconReportMoney, IconNote, IconMoon, IconSun, IconHistory, IconLogout, IconUsers, IconCurrencyDollar, IconMail, IconFileTypePdf, IconUserPlus, IconCreditCard, IconCloudUpload, IconFileText } from '@tabler/icons-react';
+import { IconHome, IconBuilding, IconUser, IconTools, IconReportMoney, IconNote, IconMoon, IconSun, IconHistory, IconLogout, IconUsers, IconCurrencyDollar, IconMail, IconFileTypePdf, IconUserPlus, IconCreditCard, IconCloudUpload, IconFileText, IconShieldCheck } from '@tabler/icons-react';
 import { useAuth } from '../contexts/AuthContext';
 import { PendingActivationNotice } from '../components/PendingActivationNotice';
 
@@ -41,6 +41,7 @@ export default function MainLayout({ children }: MainLayoutProps) {
     { label: 'Personas', icon: <IconUser size={20} stroke={1.5} />, to: '/persons' },
     { label: 'Mantenimiento', icon: <IconTools size={20} stroke={1.5} />, to: '/maintenance' },
     { label: 'Pagos', icon: <IconReportMoney size={20} stroke={1.5} />, to: '/payments' },
+    { label: 'Depósito', icon: <IconShieldCheck size={20} stroke={1.5} />, to: '/deposits' },
     { label: 'Historial de Alquiler', icon: <IconHistory size={20} stroke={1.5} />, to: '/rental-history' },
     { label: 'Contratos', icon: <IconNote size={20} stroke={1.5} />, to: '/contracts' },
   ];
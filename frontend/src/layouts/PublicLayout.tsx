@@ -37,6 +37,7 @@ export default function PublicLayout({ children }: PublicLayoutProps) {
 
   const navItems = [
     { label: 'Inicio', to: '/' },
+    { label: 'Inmuebles', to: '/listings' },
     { label: 'Acerca de', to: '/about' },
     { label: 'Contacto', to: '/contact' },
   ];
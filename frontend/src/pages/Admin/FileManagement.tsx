@@ -19,6 +19,7 @@ import {
   TextInput,
   Card,
   Grid,
+  Checkbox,
   useMantineTheme,
 } from '@mantine/core';
 import {
@@ -36,9 +37,13 @@ import {
   IconUser,
   IconFilter,
   IconSearch,
+  IconShare,
 } from '@tabler/icons-react';
 import { notifications } from '@mantine/notifications';
 import { useDisclosure } from '@mantine/hooks';
+import { useQueryClient } from '@tanstack/react-query';
+import { FILE_CATEGORIES, fileCategoryLabel } from '../../types/fileCategories';
+import FileTrashPanel from '../../components/FileTrashPanel';
 
 interface SupabaseFileInfo {
   name: string;
@@ -47,6 +52,8 @@ interface SupabaseFileInfo {
   mime_type: string;
   uploaded_at: string;
   download_url: string;
+  category?: string;
+  tags?: string[];
 }
 
 interface FileManagementResponse {
@@ -56,15 +63,19 @@ interface FileManagementResponse {
 
 const FileManagement: React.FC = () => {
   const theme = useMantineTheme();
+  const queryClient = useQueryClient();
   const [files, setFiles] = useState<SupabaseFileInfo[]>([]);
   const [filteredFiles, setFilteredFiles] = useState<SupabaseFileInfo[]>([]);
   const [loading, setLoading] = useState(true);
   const [selectedFile, setSelectedFile] = useState<SupabaseFileInfo | null>(null);
   const [modalOpened, { open: openModal, close: closeModal }] = useDisclosure(false);
   const [actionLoading, setActionLoading] = useState<string | null>(null);
+  const [selectedPaths, setSelectedPaths] = useState<string[]>([]);
   const [filterType, setFilterType] = useState<string>('all');
   const [searchTerm, setSearchTerm] = useState('');
   const [selectedUser, setSelectedUser] = useState<string>('all');
+  // La categoría se filtra en el servidor
+  const [category, setCategory] = useState<string | null>(null);
 
   // Estados para estadísticas
   const [totalFiles, setTotalFiles] = useState(0);
@@ -76,7 +87,8 @@ const FileManagement: React.FC = () => {
     try {
       setLoading(true);
       const token = localStorage.getItem('auth_token');
-      const response = await fetch('/api/admin/file-upload/files', {
+      const query = category ? `?category=${encodeURIComponent(category)}` : '';
+      const response = await fetch(`/api/admin/file-upload/files${query}`, {
         headers: {
           'Authorization': `Bearer ${token}`,
         },
@@ -89,6 +101,7 @@ const FileManagement: React.FC = () => {
       const data: FileManagementResponse = await response.json();
       const filesList = data.files || [];
       setFiles(filesList);
+      setSelectedPaths([]);
       setFilteredFiles(filesList);
       
       // Calcular estadísticas
@@ -175,13 +188,14 @@ const FileManagement: React.FC = () => {
 
       notifications.show({
         title: '🗑️ Archivo Eliminado',
-        message: 'El archivo ha sido eliminado exitosamente',
+        message: 'El archivo se eliminó o se movió a la papelera',
         color: 'green',
         icon: <IconCheck size={16} />,
       });
 
-      // Actualizar lista
+      // Actualizar lista y papelera
       await fetchFiles();
+      queryClient.invalidateQueries({ queryKey: ['file-trash'] });
     } catch (error) {
       console.error('Error:', error);
       notifications.show({
@@ -195,6 +209,159 @@ const FileManagement: React.FC = () => {
     }
   };
 
+  // Generar un enlace de descarga que caduca y copiarlo al portapapeles
+  const shareFile = async (filePath: string) => {
+    try {
+      setActionLoading(filePath);
+      const token = localStorage.getItem('auth_token');
+      const encodedPath = encodeURIComponent(filePath);
+      const response = await fetch(`/api/admin/file-upload/files/${encodedPath}/share`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${token}`,
+        },
+      });
+
+      if (!response.ok) {
+        throw new Error('Error generando enlace');
+      }
+
+      const data: { url: string; expires_at: string } = await response.json();
+      await navigator.clipboard.writeText(data.url);
+
+      notifications.show({
+        title: '🔗 Enlace copiado',
+        message: `El enlace caduca el ${formatDate(data.expires_at)}`,
+        color: 'blue',
+        icon: <IconShare size={16} />,
+      });
+
+    } catch (error) {
+      console.error('Error:', error);
+      notifications.show({
+        title: 'Error',
+        message: 'No se pudo generar el enlace',
+        color: 'red',
+        icon: <IconX size={16} />,
+      });
+    } finally {
+      setActionLoading(null);
+    }
+  };
+
+  const toggleSelected = (filePath: string) => {
+    setSelectedPaths((current) =>
+      current.includes(filePath) ? current.filter((p) => p !== filePath) : [...current, filePath]
+    );
+  };
+
+  const allVisibleSelected = filteredFiles.length > 0 && filteredFiles.every((file) => selectedPaths.includes(file.path));
+
+  const toggleAllVisible = () => {
+    setSelectedPaths(allVisibleSelected ? [] : filteredFiles.map((file) => file.path));
+  };
+
+  // Descargar los archivos seleccionados en un ZIP
+  const downloadSelectedZip = async () => {
+    try {
+      setActionLoading('zip');
+      const token = localStorage.getItem('auth_token');
+      const response = await fetch('/api/admin/file-upload/files/zip', {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${token}`,
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ paths: selectedPaths }),
+      });
+
+      if (!response.ok) {
+        throw new Error('Error generando ZIP');
+      }
+
+      const blob = await response.blob();
+      const url = window.URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `archivos_${new Date().toISOString().slice(0, 10)}.zip`;
+      document.body.appendChild(a);
+      a.click();
+      window.URL.revokeObjectURL(url);
+      document.body.removeChild(a);
+
+      notifications.show({
+        title: '📦 ZIP descargado',
+        message: `${selectedPaths.length} archivos`,
+        color: 'blue',
+        icon: <IconDownload size={16} />,
+      });
+    } catch (error) {
+      console.error('Error:', error);
+      notifications.show({
+        title: 'Error',
+        message: 'No se pudo descargar el ZIP',
+        color: 'red',
+        icon: <IconX size={16} />,
+      });
+    } finally {
+      setActionLoading(null);
+    }
+  };
+
+  // Eliminar los archivos seleccionados
+  const deleteSelected = async () => {
+    if (!window.confirm(`¿Eliminar ${selectedPaths.length} archivos?`)) {
+      return;
+    }
+    try {
+      setActionLoading('bulk-delete');
+      const token = localStorage.getItem('auth_token');
+      const response = await fetch('/api/admin/file-upload/files/bulk-delete', {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${token}`,
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ paths: selectedPaths }),
+      });
+
+      if (!response.ok) {
+        throw new Error('Error eliminando archivos');
+      }
+
+      const data: { deleted: number; failed: number; results: { path: string; success: boolean; error?: string }[] } = await response.json();
+      if (data.failed > 0) {
+        const failedPaths = data.results.filter((r) => !r.success).map((r) => r.path).join(', ');
+        notifications.show({
+          title: `⚠️ ${data.deleted} eliminados, ${data.failed} con error`,
+          message: failedPaths,
+          color: 'orange',
+          autoClose: false,
+        });
+      } else {
+        notifications.show({
+          title: '🗑️ Archivos eliminados',
+          message: `${data.deleted} archivos se eliminaron o se movieron a la papelera`,
+          color: 'green',
+          icon: <IconCheck size={16} />,
+        });
+      }
+
+      await fetchFiles();
+      queryClient.invalidateQueries({ queryKey: ['file-trash'] });
+    } catch (error) {
+      console.error('Error:', error);
+      notifications.show({
+        title: 'Error',
+        message: 'No se pudieron eliminar los archivos',
+        color: 'red',
+        icon: <IconX size={16} />,
+      });
+    } finally {
+      setActionLoading(null);
+    }
+  };
+
   // Descargar archivo SIN eliminar
   const downloadFileOnly = async (filePath: string, fileName: string) => {
     try {
@@ -276,8 +443,9 @@ const FileManagement: React.FC = () => {
         icon: <IconDownload size={16} />,
       });
 
-      // Actualizar lista
+      // Actualizar lista y papelera
       await fetchFiles();
+      queryClient.invalidateQueries({ queryKey: ['file-trash'] });
     } catch (error) {
       console.error('Error:', error);
       notifications.show({
@@ -335,7 +503,7 @@ const FileManagement: React.FC = () => {
 
   useEffect(() => {
     fetchFiles();
-  }, []);
+  }, [category]);
 
   return (
     <Container size="xl" py="md">
@@ -394,6 +562,7 @@ const FileManagement: React.FC = () => {
               setFilterType('all');
               setSelectedUser('all');
               setSearchTerm('');
+              setCategory(null);
             }}
           >
             Limpiar Filtros
@@ -428,6 +597,14 @@ const FileManagement: React.FC = () => {
             ]}
             leftSection={<IconUser size={16} />}
           />
+          <Select
+            placeholder="Todas las categorías"
+            value={category}
+            onChange={setCategory}
+            data={FILE_CATEGORIES}
+            leftSection={<IconFileText size={16} />}
+            clearable
+          />
         </Group>
       </Paper>
 
@@ -452,14 +629,38 @@ const FileManagement: React.FC = () => {
           <Text fw={500}>
             Archivos ({filteredFiles.length}/{totalFiles})
           </Text>
-          <Button
-            variant="light"
-            leftSection={<IconDownload size={16} />}
-            onClick={fetchFiles}
-            loading={loading}
-          >
-            Actualizar
-          </Button>
+          <Group gap="xs">
+            {selectedPaths.length > 0 && (
+              <>
+                <Button
+                  variant="light"
+                  color="blue"
+                  leftSection={<IconDownload size={16} />}
+                  onClick={downloadSelectedZip}
+                  loading={actionLoading === 'zip'}
+                >
+                  Descargar ZIP ({selectedPaths.length})
+                </Button>
+                <Button
+                  variant="light"
+                  color="red"
+                  leftSection={<IconTrash size={16} />}
+                  onClick={deleteSelected}
+                  loading={actionLoading === 'bulk-delete'}
+                >
+                  Eliminar ({selectedPaths.length})
+                </Button>
+              </>
+            )}
+            <Button
+              variant="light"
+              leftSection={<IconDownload size={16} />}
+              onClick={fetchFiles}
+              loading={loading}
+            >
+              Actualizar
+            </Button>
+          </Group>
         </Group>
 
         {filteredFiles.length === 0 ? (
@@ -471,6 +672,14 @@ const FileManagement: React.FC = () => {
             <Table verticalSpacing="md" highlightOnHover>
               <Table.Thead>
                 <Table.Tr>
+                  <Table.Th>
+                    <Checkbox
+                      checked={allVisibleSelected}
+                      indeterminate={selectedPaths.length > 0 && !allVisibleSelected}
+                      onChange={toggleAllVisible}
+                      aria-label="Seleccionar todos"
+                    />
+                  </Table.Th>
                   <Table.Th>Archivo</Table.Th>
                   <Table.Th>Usuario</Table.Th>
                   <Table.Th>Tamaño</Table.Th>
@@ -482,6 +691,13 @@ const FileManagement: React.FC = () => {
               <Table.Tbody>
                 {filteredFiles.map((file) => (
                   <Table.Tr key={file.path}>
+                    <Table.Td>
+                      <Checkbox
+                        checked={selectedPaths.includes(file.path)}
+                        onChange={() => toggleSelected(file.path)}
+                        aria-label={`Seleccionar ${file.name}`}
+                      />
+                    </Table.Td>
                     <Table.Td>
                       <Group gap="sm">
                         {getFileIcon(file.mime_type)}
@@ -492,6 +708,16 @@ const FileManagement: React.FC = () => {
                           <Text size="xs" c="dimmed">
                             {file.path}
                           </Text>
+                          {(file.category || (file.tags && file.tags.length > 0)) && (
+                            <Group gap={4} mt={4}>
+                              {file.category && (
+                                <Badge size="xs" color="grape">{fileCategoryLabel(file.category)}</Badge>
+                              )}
+                              {file.tags?.map((tag) => (
+                                <Badge key={tag} size="xs" variant="outline" color="gray">{tag}</Badge>
+                              ))}
+                            </Group>
+                          )}
                         </div>
                       </Group>
                     </Table.Td>
@@ -573,6 +799,8 @@ const FileManagement: React.FC = () => {
         )}
       </Paper>
 
+      <FileTrashPanel onRestored={fetchFiles} />
+
       {/* Modal de detalles */}
       <Modal
         opened={modalOpened}
@@ -616,7 +844,7 @@ const FileManagement: React.FC = () => {
               <Text>{formatDate(selectedFile.uploaded_at)}</Text>
             </div>
             <div>
-              <Text size="sm" c="dimmed">URL de descarga</Text>
+              <Text size="sm" c="dimmed">URL de descarga (caduca)</Text>
               <Anchor 
                 href={selectedFile.download_url} 
                 target="_blank" 
@@ -638,6 +866,15 @@ const FileManagement: React.FC = () => {
                >
                  Descargar
                </Button>
+               <Button
+                 variant="light"
+                 color="grape"
+                 leftSection={<IconShare size={16} />}
+                 loading={actionLoading === selectedFile.path}
+                 onClick={() => shareFile(selectedFile.path)}
+               >
+                 Compartir enlace
+               </Button>
                <Button
                  variant="light"
                  color="green"
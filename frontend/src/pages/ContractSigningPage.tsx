@@ -1,9 +1,9 @@
 import { useEffect, useState } from 'react';
 import { useParams } from 'react-router-dom';
-import { Box, Title, Paper, Button, Group, Loader, Alert, Stack, Center, Text } from '@mantine/core';
+import { Box, Title, Paper, Button, Group, Loader, Alert, Stack, Center, Text, PinInput, SegmentedControl } from '@mantine/core';
 import { notifications } from '@mantine/notifications';
-import { IconCheck, IconAlertCircle, IconX, IconSignature, IconDownload } from '@tabler/icons-react';
-import { contractSigningApi } from '../api/apiService';
+import { IconCheck, IconAlertCircle, IconX, IconSignature, IconDownload, IconShieldLock } from '@tabler/icons-react';
+import { contractSigningApi, SignerLocation } from '../api/apiService';
 
 interface SigningStatusData {
   id: string;
@@ -16,6 +16,26 @@ interface SigningStatusData {
   signed_at?: string;
 }
 
+// Asks the browser for the signer's location, which is optional evidence of the signature: a
+// denied permission or a timeout resolves to undefined
+const getSignerLocation = (): Promise<SignerLocation | undefined> =>
+  new Promise((resolve) => {
+    if (!navigator.geolocation) {
+      resolve(undefined);
+      return;
+    }
+    navigator.geolocation.getCurrentPosition(
+      (position) =>
+        resolve({
+          latitude: position.coords.latitude,
+          longitude: position.coords.longitude,
+          accuracy: position.coords.accuracy,
+        }),
+      () => resolve(undefined),
+      { timeout: 5000, maximumAge: 60000 },
+    );
+  });
+
 const ContractSigningPage = () => {
   const { signingId } = useParams<{ signingId: string }>();
   const [loading, setLoading] = useState(true);
@@ -25,6 +45,10 @@ const ContractSigningPage = () => {
   const [isSigning, setIsSigning] = useState(false);
   const [isRejecting, setIsRejecting] = useState(false);
   const [isDownloading, setIsDownloading] = useState(false);
+  const [otpChannel, setOtpChannel] = useState<'email' | 'sms'>('email');
+  const [otpDestination, setOtpDestination] = useState<string | null>(null);
+  const [otp, setOtp] = useState('');
+  const [isSendingCode, setIsSendingCode] = useState(false);
 
   // Fetch signing status on load
   useEffect(() => {
@@ -53,12 +77,41 @@ const ContractSigningPage = () => {
     fetchSigningStatus();
   }, [signingId]);
 
+  const handleSendCode = async () => {
+    if (!signingId) return;
+
+    setIsSendingCode(true);
+    try {
+      const data = await contractSigningApi.sendSigningCode(signingId, otpChannel);
+      setOtpDestination(data.destination);
+      setOtp('');
+
+      notifications.show({
+        title: 'Código enviado',
+        message: `Enviamos un código de verificación a ${data.destination}.`,
+        color: 'blue',
+        icon: <IconShieldLock />,
+      });
+    } catch (error: any) {
+      console.error('Error sending signing code:', error);
+      notifications.show({
+        title: 'Error',
+        message: error.response?.data?.error || 'No se pudo enviar el código de verificación.',
+        color: 'red',
+        icon: <IconAlertCircle />,
+      });
+    } finally {
+      setIsSendingCode(false);
+    }
+  };
+
   const handleSign = async () => {
     if (!signingId) return;
     
     setIsSigning(true);
     try {
-      await contractSigningApi.signContract(signingId);
+      const location = await getSignerLocation();
+      await contractSigningApi.signContract(signingId, otp, location);
       
       notifications.show({
         title: 'Contrato firmado',
@@ -74,11 +127,11 @@ const ContractSigningPage = () => {
       // Update PDF URL to get the signed version
       setPdfUrl(`/api/public/contract-signing/pdf/${signingId}?signed=true`);
       
-    } catch (error) {
+    } catch (error: any) {
       console.error('Error signing contract:', error);
       notifications.show({
         title: 'Error',
-        message: 'No se pudo firmar el contrato. Por favor intente nuevamente.',
+        message: error.response?.data?.error || 'No se pudo firmar el contrato. Por favor intente nuevamente.',
         color: 'red',
         icon: <IconAlertCircle />,
       });
@@ -92,7 +145,8 @@ const ContractSigningPage = () => {
     
     setIsRejecting(true);
     try {
-      await contractSigningApi.rejectContract(signingId);
+      const location = await getSignerLocation();
+      await contractSigningApi.rejectContract(signingId, location);
       
       notifications.show({
         title: 'Firma rechazada',
@@ -223,6 +277,37 @@ const ContractSigningPage = () => {
           </Text>
         )}
         
+        <Stack gap="xs">
+          <Text fw={500}>Verificación de identidad</Text>
+          <Text size="sm" c="dimmed">
+            Para firmar debe ingresar el código de 6 dígitos que le enviaremos.
+          </Text>
+          <Group>
+            <SegmentedControl
+              value={otpChannel}
+              onChange={(value) => setOtpChannel(value as 'email' | 'sms')}
+              data={[
+                { label: 'Correo', value: 'email' },
+                { label: 'SMS', value: 'sms' },
+              ]}
+            />
+            <Button
+              variant="light"
+              leftSection={<IconShieldLock />}
+              onClick={handleSendCode}
+              loading={isSendingCode}
+            >
+              {otpDestination ? 'Reenviar código' : 'Enviar código'}
+            </Button>
+          </Group>
+          {otpDestination && (
+            <>
+              <Text size="sm">Código enviado a {otpDestination}</Text>
+              <PinInput length={6} type="number" oneTimeCode value={otp} onChange={setOtp} />
+            </>
+          )}
+        </Stack>
+
         <Group>
           <Button 
             color="red" 
@@ -237,6 +322,7 @@ const ContractSigningPage = () => {
             leftSection={<IconSignature />}
             onClick={handleSign}
             loading={isSigning}
+            disabled={otp.length !== 6}
           >
             Firmar Contrato
           </Button>
@@ -4,6 +4,7 @@ import { personApi, propertyApi, rentalApi } from '../api/apiService';
 import { useAuth } from '../contexts/AuthContext';
 import { Link } from 'react-router-dom';
 import { IconUserCircle, IconHomeCog, IconCreditCard, IconHistory, IconBuildingCommunity, IconAlertCircle, IconSettings, IconCloudUpload } from '@tabler/icons-react';
+import PendingReglamentos from '../components/PendingReglamentos';
 import styles from './Dashboard.module.css';
 
 export default function Dashboard() {
@@ -144,6 +145,7 @@ export default function Dashboard() {
         </>
       ) : (
         <>
+          <PendingReglamentos />
           <Text mb="md" c="dimmed">Bienvenido a tu portal personal. Desde aquí puedes acceder a tu información y servicios:</Text>
           <Paper withBorder radius="md" shadow="sm" className={styles.quickAccessList}>
             <Title order={3} mb="lg" style={{ padding: '24px 24px 0 24px' }}>Accesos Rápidos</Title>
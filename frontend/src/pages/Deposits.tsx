@@ -0,0 +1,178 @@
+import { useState } from 'react';
+import { Container, Title, Text, Paper, Group, Badge, Table, Button, Alert, Stack, LoadingOverlay, Anchor } from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { IconAlertCircle, IconDownload, IconSignature, IconShieldCheck } from '@tabler/icons-react';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { depositApi, SecurityDepositSummary, SignerLocation } from '../api/apiService';
+
+const categoryLabels: Record<string, string> = {
+  damages: 'Reparación de daños',
+  unpaid_rent: 'Cánones o intereses pendientes',
+  utilities: 'Servicios públicos pendientes',
+  cleaning: 'Aseo',
+  other: 'Otro',
+};
+
+const statusColors: Record<string, string> = {
+  held: 'blue',
+  settlement_issued: 'yellow',
+  signed: 'teal',
+  refunded: 'green',
+};
+
+const formatMoney = (value: number) =>
+  new Intl.NumberFormat('es-CO', { style: 'currency', currency: 'COP', maximumFractionDigits: 0 }).format(value);
+
+// La ubicación es evidencia opcional de la firma: si el navegador la niega se firma sin ella
+const getSignerLocation = (): Promise<SignerLocation | undefined> =>
+  new Promise((resolve) => {
+    if (!navigator.geolocation) {
+      resolve(undefined);
+      return;
+    }
+    navigator.geolocation.getCurrentPosition(
+      (position) =>
+        resolve({
+          latitude: position.coords.latitude,
+          longitude: position.coords.longitude,
+          accuracy: position.coords.accuracy,
+        }),
+      () => resolve(undefined),
+      { timeout: 5000, maximumAge: 60000 },
+    );
+  });
+
+export default function Deposits() {
+  const queryClient = useQueryClient();
+  const [signingId, setSigningId] = useState<string | null>(null);
+
+  const { data: deposits = [], isLoading, error } = useQuery<SecurityDepositSummary[]>({
+    queryKey: ['myDeposits'],
+    queryFn: depositApi.getMine,
+  });
+
+  const downloadSettlement = async (summary: SecurityDepositSummary) => {
+    try {
+      const blob = await depositApi.getSettlement(summary.deposit.id);
+      const url = window.URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = summary.deposit.signed_at ? 'liquidacion_deposito_firmada.pdf' : 'liquidacion_deposito.pdf';
+      document.body.appendChild(a);
+      a.click();
+      window.URL.revokeObjectURL(url);
+      document.body.removeChild(a);
+    } catch {
+      notifications.show({ title: 'Error', message: 'No se pudo descargar la liquidación', color: 'red' });
+    }
+  };
+
+  const signSettlement = async (summary: SecurityDepositSummary) => {
+    setSigningId(summary.deposit.id);
+    try {
+      const location = await getSignerLocation();
+      await depositApi.signSettlement(summary.deposit.id, location);
+      notifications.show({ title: 'Liquidación firmada', message: 'Procederemos con el reembolso de su depósito', color: 'green' });
+      queryClient.invalidateQueries({ queryKey: ['myDeposits'] });
+    } catch {
+      notifications.show({ title: 'Error', message: 'No se pudo firmar la liquidación', color: 'red' });
+    } finally {
+      setSigningId(null);
+    }
+  };
+
+  return (
+    <Container size="lg" py="md" pos="relative">
+      <LoadingOverlay visible={isLoading} />
+      <Title order={2} mb="md">Depósito en garantía</Title>
+
+      {error && (
+        <Alert icon={<IconAlertCircle size={16} />} color="red" mb="md">
+          No se pudieron cargar sus depósitos
+        </Alert>
+      )}
+
+      {!isLoading && deposits.length === 0 && (
+        <Text c="dimmed">No tiene depósitos en garantía registrados.</Text>
+      )}
+
+      <Stack>
+        {deposits.map((summary) => {
+          const { deposit } = summary;
+          const hasSettlement = deposit.status !== 'held';
+          return (
+            <Paper key={deposit.id} withBorder p="md" radius="md">
+              <Group justify="space-between" mb="sm">
+                <div>
+                  <Text fw={600}>Depósito de {formatMoney(deposit.amount)}</Text>
+                  <Text size="sm" c="dimmed">Recibido el {new Date(deposit.received_at).toLocaleDateString('es-CO')}</Text>
+                </div>
+                <Badge color={statusColors[deposit.status]}>{summary.status_translated}</Badge>
+              </Group>
+
+              {deposit.deductions.length > 0 && (
+                <Table striped mb="sm">
+                  <Table.Thead>
+                    <Table.Tr>
+                      <Table.Th>Concepto</Table.Th>
+                      <Table.Th>Motivo</Table.Th>
+                      <Table.Th>Valor</Table.Th>
+                    </Table.Tr>
+                  </Table.Thead>
+                  <Table.Tbody>
+                    {deposit.deductions.map((deduction) => (
+                      <Table.Tr key={deduction.id}>
+                        <Table.Td>{categoryLabels[deduction.category] ?? deduction.category}</Table.Td>
+                        <Table.Td>
+                          {deduction.reason}
+                          {deduction.receipt_url && (
+                            <Anchor href={deduction.receipt_url} target="_blank" ml="xs" size="sm">
+                              Ver soporte
+                            </Anchor>
+                          )}
+                        </Table.Td>
+                        <Table.Td>{formatMoney(deduction.amount)}</Table.Td>
+                      </Table.Tr>
+                    ))}
+                  </Table.Tbody>
+                </Table>
+              )}
+
+              <Group gap="xl" mb="sm">
+                <Text size="sm">Descuentos: <b>{formatMoney(summary.total_deductions)}</b></Text>
+                <Text size="sm">A reembolsar: <b>{formatMoney(summary.refund_amount)}</b></Text>
+                {summary.balance_owed > 0 && (
+                  <Text size="sm" c="red">Saldo a su cargo: <b>{formatMoney(summary.balance_owed)}</b></Text>
+                )}
+              </Group>
+
+              {deposit.status === 'refunded' && (
+                <Alert icon={<IconShieldCheck size={16} />} color="green" mb="sm">
+                  Reembolsado el {new Date(deposit.refunded_at as string).toLocaleDateString('es-CO')}
+                  {deposit.refund_reference ? ` (referencia ${deposit.refund_reference})` : ''}
+                </Alert>
+              )}
+
+              {hasSettlement && (
+                <Group>
+                  <Button variant="light" leftSection={<IconDownload size={16} />} onClick={() => downloadSettlement(summary)}>
+                    Descargar liquidación
+                  </Button>
+                  {deposit.status === 'settlement_issued' && (
+                    <Button
+                      leftSection={<IconSignature size={16} />}
+                      loading={signingId === deposit.id}
+                      onClick={() => signSettlement(summary)}
+                    >
+                      Firmar liquidación
+                    </Button>
+                  )}
+                </Group>
+              )}
+            </Paper>
+          );
+        })}
+      </Stack>
+    </Container>
+  );
+}
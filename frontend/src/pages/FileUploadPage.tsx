@@ -14,7 +14,9 @@ import {
   Card,
   SimpleGrid,
   Center,
-  Loader
+  Loader,
+  Select,
+  TagsInput
 } from '@mantine/core';
 import { Dropzone, FileWithPath, MIME_TYPES } from '@mantine/dropzone';
 import { useQuery, useMutation } from '@tanstack/react-query';
@@ -31,6 +33,7 @@ import {
   IconLogin
 } from '@tabler/icons-react';
 import { useAuth } from '../contexts/AuthContext';
+import { FILE_CATEGORIES } from '../types/fileCategories';
 import axios from 'axios';
 
 interface UploadResponse {
@@ -59,16 +62,30 @@ const uploadApiClient = axios.create({
   baseURL: '/api',
 });
 
+// Categoría y etiquetas que se envían con cada archivo
+interface FileCategorization {
+  category: string | null;
+  tags: string[];
+}
+
+const appendCategorization = (formData: FormData, categorization: FileCategorization) => {
+  if (categorization.category) {
+    formData.append('category', categorization.category);
+  }
+  categorization.tags.forEach((tag) => formData.append('tags', tag));
+};
+
 const uploadApi = {
   validateToken: async (token: string) => {
     const response = await publicApiClient.get(`/upload/validate-token/${token}`);
     return response.data;
   },
 
-  uploadFile: async (file: File, token: string, folderName?: string) => {
+  uploadFile: async (file: File, token: string, categorization: FileCategorization, folderName?: string) => {
     const formData = new FormData();
     formData.append('file', file);
     formData.append('token', token);
+    appendCategorization(formData, categorization);
     if (folderName) {
       formData.append('folder_name', folderName);
     }
@@ -81,9 +98,10 @@ const uploadApi = {
     return response.data;
   },
 
-  uploadFileAuthenticated: async (file: File, authToken: string) => {
+  uploadFileAuthenticated: async (file: File, authToken: string, categorization: FileCategorization) => {
     const formData = new FormData();
     formData.append('file', file);
+    appendCategorization(formData, categorization);
 
     const response = await uploadApiClient.post('/upload/file-authenticated', formData, {
       headers: {
@@ -102,6 +120,8 @@ function FileUploadPage() {
   const [selectedFiles, setSelectedFiles] = useState<FileWithPath[]>([]);
   const [uploadProgress, setUploadProgress] = useState<{ [key: string]: number }>({});
   const [uploadedFiles, setUploadedFiles] = useState<UploadResponse[]>([]);
+  const [category, setCategory] = useState<string | null>(null);
+  const [tags, setTags] = useState<string[]>([]);
 
   // Query para validar token
   const { data: tokenData, isLoading: isValidating, error: tokenError } = useQuery({
@@ -114,7 +134,7 @@ function FileUploadPage() {
   // Mutation para subir archivos con token
   const uploadMutation = useMutation({
     mutationFn: ({ file, token }: { file: File; token: string }) => 
-      uploadApi.uploadFile(file, token),
+      uploadApi.uploadFile(file, token, { category, tags }),
     onSuccess: (data, variables) => {
       if (data.success) {
         setUploadedFiles(prev => [...prev, data]);
@@ -159,7 +179,7 @@ function FileUploadPage() {
   // Mutation para subir archivos autenticados (sin token)
   const uploadAuthenticatedMutation = useMutation({
     mutationFn: ({ file, authToken }: { file: File; authToken: string }) => 
-      uploadApi.uploadFileAuthenticated(file, authToken),
+      uploadApi.uploadFileAuthenticated(file, authToken, { category, tags }),
     onSuccess: (data, variables) => {
       if (data.success) {
         setUploadedFiles(prev => [...prev, data]);
@@ -469,6 +489,24 @@ function FileUploadPage() {
                   ))}
                 </SimpleGrid>
 
+                <Group grow align="flex-start">
+                  <Select
+                    label="Tipo de documento"
+                    placeholder="Sin categoría"
+                    data={FILE_CATEGORIES}
+                    value={category}
+                    onChange={setCategory}
+                    clearable
+                  />
+                  <TagsInput
+                    label="Etiquetas"
+                    placeholder="Escribe y presiona Enter"
+                    value={tags}
+                    onChange={setTags}
+                    clearable
+                  />
+                </Group>
+
                 <Group justify="center" mt="md">
                   <Button
                     leftSection={<IconUpload size={16} />}
@@ -0,0 +1,134 @@
+import { useState } from 'react';
+import {
+  Container, Title, Text, Paper, Group, Badge, Button, Alert, SimpleGrid, Image, TextInput, NumberInput,
+  Modal, Stack, Textarea, LoadingOverlay,
+} from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { IconAlertCircle, IconBed, IconBath, IconRuler, IconSearch } from '@tabler/icons-react';
+import { useQuery } from '@tanstack/react-query';
+import { listingApi, ListingApplicationInput, PublicListing } from '../api/apiService';
+
+const formatMoney = (value: number) =>
+  new Intl.NumberFormat('es-CO', { style: 'currency', currency: 'COP', maximumFractionDigits: 0 }).format(value);
+
+const emptyApplication: ListingApplicationInput = { full_name: '', email: '', phone: '', occupants: 1 };
+
+// Catálogo público de los inmuebles disponibles, con la solicitud de los interesados
+export default function Listings() {
+  const [city, setCity] = useState('');
+  const [minPrice, setMinPrice] = useState<number | string>('');
+  const [maxPrice, setMaxPrice] = useState<number | string>('');
+  const [filters, setFilters] = useState<{ city?: string; min_price?: number; max_price?: number }>({});
+  const [selected, setSelected] = useState<PublicListing | null>(null);
+  const [application, setApplication] = useState<ListingApplicationInput>(emptyApplication);
+  const [submitting, setSubmitting] = useState(false);
+
+  const { data: listings = [], isLoading, error } = useQuery<PublicListing[]>({
+    queryKey: ['publicListings', filters],
+    queryFn: () => listingApi.getPublic(filters),
+  });
+
+  const search = () => {
+    setFilters({
+      city: city.trim() || undefined,
+      min_price: typeof minPrice === 'number' ? minPrice : undefined,
+      max_price: typeof maxPrice === 'number' ? maxPrice : undefined,
+    });
+  };
+
+  const submitApplication = async () => {
+    if (!selected) return;
+    setSubmitting(true);
+    try {
+      await listingApi.apply(selected.id, application);
+      notifications.show({
+        title: 'Solicitud enviada',
+        message: 'El administrador del inmueble se comunicará con usted',
+        color: 'green',
+      });
+      setSelected(null);
+      setApplication(emptyApplication);
+    } catch {
+      notifications.show({ title: 'Error', message: 'No se pudo enviar la solicitud, revise sus datos', color: 'red' });
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <Container size="lg" py="xl" pos="relative">
+      <LoadingOverlay visible={isLoading} />
+      <Title order={2} mb="md">Inmuebles disponibles</Title>
+
+      <Group align="flex-end" mb="lg">
+        <TextInput label="Ciudad" value={city} onChange={(e) => setCity(e.currentTarget.value)} />
+        <NumberInput label="Canon mínimo" value={minPrice} onChange={setMinPrice} min={0} thousandSeparator="." decimalSeparator="," />
+        <NumberInput label="Canon máximo" value={maxPrice} onChange={setMaxPrice} min={0} thousandSeparator="." decimalSeparator="," />
+        <Button leftSection={<IconSearch size={16} />} onClick={search}>Buscar</Button>
+      </Group>
+
+      {error && (
+        <Alert icon={<IconAlertCircle size={16} />} color="red" mb="md">
+          No se pudieron cargar los inmuebles disponibles
+        </Alert>
+      )}
+
+      {!isLoading && listings.length === 0 && (
+        <Text c="dimmed">No hay inmuebles disponibles con estos filtros.</Text>
+      )}
+
+      <SimpleGrid cols={{ base: 1, sm: 2, md: 3 }}>
+        {listings.map((listing) => (
+          <Paper key={listing.id} withBorder radius="md" p="md">
+            {listing.photos[0]?.url && (
+              <Image src={listing.photos[0].url} alt={listing.photos[0].caption ?? listing.title} h={180} radius="sm" mb="sm" />
+            )}
+            <Group justify="space-between" mb="xs">
+              <Text fw={600}>{listing.title}</Text>
+              <Badge>{listing.city}</Badge>
+            </Group>
+            <Text size="lg" fw={700}>{formatMoney(listing.monthly_rent)} / mes</Text>
+            {listing.admin_fee > 0 && <Text size="sm" c="dimmed">+ administración {formatMoney(listing.admin_fee)}</Text>}
+            <Group gap="md" my="xs">
+              <Group gap={4}><IconBed size={16} /><Text size="sm">{listing.bedrooms}</Text></Group>
+              <Group gap={4}><IconBath size={16} /><Text size="sm">{listing.bathrooms}</Text></Group>
+              {listing.area_m2 > 0 && <Group gap={4}><IconRuler size={16} /><Text size="sm">{listing.area_m2} m²</Text></Group>}
+            </Group>
+            {listing.description && <Text size="sm" lineClamp={3} mb="sm">{listing.description}</Text>}
+            <Text size="xs" c="dimmed" mb="sm">
+              Plazo mínimo {listing.min_term_months} meses
+              {listing.available_from ? ` · Disponible desde ${new Date(listing.available_from).toLocaleDateString('es-CO')}` : ''}
+              {listing.pets_allowed ? ' · Se aceptan mascotas' : ''}
+            </Text>
+            <Button fullWidth onClick={() => setSelected(listing)}>Me interesa</Button>
+          </Paper>
+        ))}
+      </SimpleGrid>
+
+      <Modal opened={selected !== null} onClose={() => setSelected(null)} title={selected ? `Solicitud para ${selected.title}` : ''}>
+        <Stack>
+          <TextInput label="Nombre completo" required value={application.full_name}
+            onChange={(e) => setApplication({ ...application, full_name: e.currentTarget.value })} />
+          <TextInput label="Email" type="email" required value={application.email}
+            onChange={(e) => setApplication({ ...application, email: e.currentTarget.value })} />
+          <TextInput label="Teléfono" required value={application.phone}
+            onChange={(e) => setApplication({ ...application, phone: e.currentTarget.value })} />
+          <TextInput label="Ocupación" value={application.occupation ?? ''}
+            onChange={(e) => setApplication({ ...application, occupation: e.currentTarget.value })} />
+          <NumberInput label="Ingresos mensuales" min={0} thousandSeparator="." decimalSeparator="," value={application.monthly_income ?? ''}
+            onChange={(value) => setApplication({ ...application, monthly_income: typeof value === 'number' ? value : undefined })} />
+          <NumberInput label="Número de ocupantes" min={1} value={application.occupants ?? 1}
+            onChange={(value) => setApplication({ ...application, occupants: typeof value === 'number' ? value : 1 })} />
+          <TextInput label="Fecha deseada de ingreso" type="date" value={application.desired_move_in ?? ''}
+            onChange={(e) => setApplication({ ...application, desired_move_in: e.currentTarget.value || undefined })} />
+          <Textarea label="Mensaje" value={application.message ?? ''}
+            onChange={(e) => setApplication({ ...application, message: e.currentTarget.value })} />
+          <Button loading={submitting} onClick={submitApplication}
+            disabled={!application.full_name || !application.email || !application.phone}>
+            Enviar solicitud
+          </Button>
+        </Stack>
+      </Modal>
+    </Container>
+  );
+}
@@ -61,6 +61,7 @@ export default function ManagerRegistration() {
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState('');
   const [success, setSuccess] = useState(false);
+  const [paymentUrl, setPaymentUrl] = useState<string | null>(null);
   const { user } = useAuth();
   const navigate = useNavigate();
 
@@ -241,6 +242,7 @@ export default function ManagerRegistration() {
           color: 'green',
           icon: <IconCheck size={16} />,
         });
+        setPaymentUrl(response.data.payment_url || null);
         setSuccess(true);
       } else {
         setError('Hubo un problema al procesar su solicitud.');
@@ -264,16 +266,33 @@ export default function ManagerRegistration() {
           <Stack align="center" gap="lg">
             <IconCheck size={50} color="green" />
             <Title order={2} ta="center">¡Registro exitoso!</Title>
-            <Text ta="center">
-              Su solicitud de registro como administrador ha sido recibida correctamente. El estado de su cuenta es actualmente "pendiente".
-              Un administrador revisará su información y aprobará su cuenta pronto.
-            </Text>
-            <Text ta="center">
-              Una vez aprobada, recibirá un correo electrónico de confirmación y podrá iniciar sesión en el sistema.
-            </Text>
-            <Button component={Link} to="/login" mt="lg">
-              Ir a la página de inicio de sesión
-            </Button>
+            {paymentUrl ? (
+              <>
+                <Text ta="center">
+                  Su solicitud de registro como administrador ha sido recibida correctamente. El estado de su cuenta es actualmente "pendiente".
+                  Para activarla, pague el primer mes de su plan; la renovación se cobrará automáticamente cada mes.
+                </Text>
+                <Button component="a" href={paymentUrl} mt="lg">
+                  Pagar suscripción
+                </Button>
+                <Button component={Link} to="/login" variant="subtle">
+                  Ir a la página de inicio de sesión
+                </Button>
+              </>
+            ) : (
+              <>
+                <Text ta="center">
+                  Su solicitud de registro como administrador ha sido recibida correctamente. El estado de su cuenta es actualmente "pendiente".
+                  Un administrador revisará su información y aprobará su cuenta pronto.
+                </Text>
+                <Text ta="center">
+                  Una vez aprobada, recibirá un correo electrónico de confirmación y podrá iniciar sesión en el sistema.
+                </Text>
+                <Button component={Link} to="/login" mt="lg">
+                  Ir a la página de inicio de sesión
+                </Button>
+              </>
+            )}
           </Stack>
         </Paper>
       </Container>
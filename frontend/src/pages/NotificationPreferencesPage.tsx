@@ -0,0 +1,179 @@
+import { useEffect, useState } from 'react';
+import { useSearchParams } from 'react-router-dom';
+import { Box, Title, Paper, Button, Group, Loader, Alert, Stack, Center, Text, Select, Table } from '@mantine/core';
+import { notifications } from '@mantine/notifications';
+import { IconAlertCircle, IconBellOff, IconCheck } from '@tabler/icons-react';
+import {
+  notificationPreferenceApi,
+  NotificationChannel,
+  NotificationFrequency,
+  NotificationPreference,
+  NotificationType,
+} from '../api/apiService';
+
+const TYPE_LABELS: Record<NotificationType, string> = {
+  rent_reminder: 'Recordatorio de pago del arriendo',
+  anniversary_reminder: 'Aniversario del contrato',
+  renewal_reminder: 'Renovación del contrato',
+};
+
+const FREQUENCY_OPTIONS = [
+  { value: 'always', label: 'Siempre' },
+  { value: 'monthly', label: 'Máximo una vez al mes' },
+  { value: 'never', label: 'Nunca' },
+];
+
+const CHANNELS: NotificationChannel[] = ['email', 'sms'];
+
+// Page opened from the links at the bottom of the notification emails. The token of the link
+// identifies the tenant, so it works without logging in.
+const NotificationPreferencesPage = () => {
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') ?? '';
+  const unsubscribeType = searchParams.get('unsubscribe') as NotificationType | null;
+
+  const [loading, setLoading] = useState(true);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [preferences, setPreferences] = useState<NotificationPreference[]>([]);
+  const [smsAvailable, setSmsAvailable] = useState(false);
+  const [unsubscribed, setUnsubscribed] = useState(false);
+
+  useEffect(() => {
+    if (!token) {
+      setError('El enlace no es válido');
+      setLoading(false);
+      return;
+    }
+    notificationPreferenceApi
+      .getByToken(token)
+      .then((data) => {
+        setPreferences(data.preferences);
+        setSmsAvailable(data.sms_available);
+      })
+      .catch(() => setError('El enlace no es válido o ha expirado'))
+      .finally(() => setLoading(false));
+  }, [token]);
+
+  const applyResponse = (data: { preferences: NotificationPreference[]; sms_available: boolean }) => {
+    setPreferences(data.preferences);
+    setSmsAvailable(data.sms_available);
+  };
+
+  const handleUnsubscribe = async (type?: NotificationType) => {
+    setSaving(true);
+    try {
+      applyResponse(await notificationPreferenceApi.unsubscribe(token, type));
+      setUnsubscribed(true);
+      notifications.show({ title: 'Listo', message: 'Ya no recibirás estos correos', color: 'green', icon: <IconCheck size={16} /> });
+    } catch {
+      notifications.show({ title: 'Error', message: 'No se pudo completar la solicitud', color: 'red' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleSave = async () => {
+    setSaving(true);
+    try {
+      applyResponse(await notificationPreferenceApi.updateByToken(token, preferences));
+      notifications.show({ title: 'Guardado', message: 'Tus preferencias fueron actualizadas', color: 'green', icon: <IconCheck size={16} /> });
+    } catch {
+      notifications.show({ title: 'Error', message: 'No se pudieron guardar las preferencias', color: 'red' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const frequencyOf = (type: NotificationType, channel: NotificationChannel) =>
+    preferences.find((p) => p.type === type && p.channel === channel)?.frequency ?? 'never';
+
+  const setFrequency = (type: NotificationType, channel: NotificationChannel, frequency: NotificationFrequency) => {
+    setPreferences((current) => [
+      ...current.filter((p) => !(p.type === type && p.channel === channel)),
+      { type, channel, frequency },
+    ]);
+  };
+
+  if (loading) {
+    return (
+      <Center h={300}>
+        <Loader />
+      </Center>
+    );
+  }
+
+  if (error) {
+    return (
+      <Box maw={600} mx="auto" mt="xl">
+        <Alert icon={<IconAlertCircle size={16} />} color="red" title="Error">
+          {error}
+        </Alert>
+      </Box>
+    );
+  }
+
+  const types = Object.keys(TYPE_LABELS) as NotificationType[];
+  const channels = CHANNELS.filter((channel) => channel === 'email' || smsAvailable);
+
+  return (
+    <Box maw={700} mx="auto" mt="xl" mb="xl">
+      <Stack>
+        <Title order={2}>Preferencias de notificación</Title>
+
+        {unsubscribeType && TYPE_LABELS[unsubscribeType] && !unsubscribed && (
+          <Alert icon={<IconBellOff size={16} />} color="orange" title="Darse de baja">
+            <Stack gap="sm">
+              <Text size="sm">¿Quieres dejar de recibir correos de «{TYPE_LABELS[unsubscribeType]}»?</Text>
+              <Group>
+                <Button color="orange" loading={saving} onClick={() => handleUnsubscribe(unsubscribeType)}>
+                  Darme de baja
+                </Button>
+                <Button variant="subtle" color="orange" loading={saving} onClick={() => handleUnsubscribe()}>
+                  Darme de baja de todos los correos
+                </Button>
+              </Group>
+            </Stack>
+          </Alert>
+        )}
+
+        <Paper withBorder p="md">
+          <Table>
+            <Table.Thead>
+              <Table.Tr>
+                <Table.Th>Notificación</Table.Th>
+                {channels.map((channel) => (
+                  <Table.Th key={channel}>{channel === 'email' ? 'Correo' : 'SMS'}</Table.Th>
+                ))}
+              </Table.Tr>
+            </Table.Thead>
+            <Table.Tbody>
+              {types.map((type) => (
+                <Table.Tr key={type}>
+                  <Table.Td>{TYPE_LABELS[type]}</Table.Td>
+                  {channels.map((channel) => (
+                    <Table.Td key={channel}>
+                      <Select
+                        data={FREQUENCY_OPTIONS}
+                        value={frequencyOf(type, channel)}
+                        onChange={(value) => value && setFrequency(type, channel, value as NotificationFrequency)}
+                        allowDeselect={false}
+                      />
+                    </Table.Td>
+                  ))}
+                </Table.Tr>
+              ))}
+            </Table.Tbody>
+          </Table>
+          <Group justify="flex-end" mt="md">
+            <Button loading={saving} onClick={handleSave}>
+              Guardar preferencias
+            </Button>
+          </Group>
+        </Paper>
+      </Stack>
+    </Box>
+  );
+};
+
+export default NotificationPreferencesPage;
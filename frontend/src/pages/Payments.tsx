@@ -12,12 +12,14 @@ import {
   NumberInput,
   Select,
   Checkbox,
-  LoadingOverlay
+  LoadingOverlay,
+  Alert
 } from '@mantine/core';
-import { IconCreditCard, IconEdit, IconTrash, IconPlus, IconFilter, IconReceipt } from '@tabler/icons-react';
+import { IconCreditCard, IconEdit, IconTrash, IconPlus, IconFilter, IconReceipt, IconFileCertificate, IconFileInvoice, IconRefresh, IconCash } from '@tabler/icons-react';
 import { useAuth } from '../contexts/AuthContext';
 import { useState, useEffect } from 'react';
-import { rentPaymentApi, rentalApi, propertyApi, personApi } from '../api/apiService';
+import { rentPaymentApi, rentalApi, propertyApi, personApi, paymentCheckoutApi, PaymentCheckout } from '../api/apiService';
+import { useSearchParams } from 'react-router-dom';
 import { notifications } from '@mantine/notifications';
 import { useDisclosure } from '@mantine/hooks';
 import { RentPayment, Rental, Property, User, Person } from '../types';
@@ -132,6 +134,29 @@ export default function Payments() {
     }
   };
 
+  const INVOICE_STATUS: Record<string, { label: string; color: string }> = {
+    pending: { label: 'En validación', color: 'yellow' },
+    accepted: { label: 'Aceptada DIAN', color: 'green' },
+    rejected: { label: 'Rechazada DIAN', color: 'red' },
+    failed: { label: 'Error de envío', color: 'red' },
+  };
+
+  const handleInvoice = async (payment: RentPayment) => {
+    const refresh = payment.invoice_status === 'pending';
+    try {
+      const updated = refresh ? await rentPaymentApi.refreshInvoice(payment.id) : await rentPaymentApi.issueInvoice(payment.id);
+      refetchPayments();
+      notifications.show({
+        title: 'Factura electrónica',
+        message: `Factura ${updated.invoice_number}: ${INVOICE_STATUS[updated.invoice_status ?? 'pending']?.label}`,
+        color: updated.invoice_status === 'accepted' || updated.invoice_status === 'pending' ? 'green' : 'red',
+      });
+    } catch (error: any) {
+      notifications.show({ title: 'Error', message: error?.response?.data?.error || 'No se pudo emitir la factura electrónica.', color: 'red' });
+      refetchPayments();
+    }
+  };
+
   const handleSubmit = async () => {
     if (!currentPayment || !currentPayment.rental_id) {
       notifications.show({ title: 'Error', message: 'Alquiler es requerido.', color: 'red' });
@@ -189,16 +214,108 @@ export default function Payments() {
     return `${propDisplay} - ${renterDisplay} (Fin: ${formatDate(rental.end_date)})`;
   };
 
+  const statementYears = Array.from({ length: 5 }, (_, i) => String(new Date().getFullYear() - i));
+  const [statementYear, setStatementYear] = useState<string>(String(new Date().getFullYear() - 1));
+  const [isDownloadingStatement, setIsDownloadingStatement] = useState(false);
+
+  const handleDownloadStatement = async () => {
+    setIsDownloadingStatement(true);
+    try {
+      const blob = await rentPaymentApi.getMyStatement(Number(statementYear));
+      const url = window.URL.createObjectURL(blob);
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `certificado_pagos_${statementYear}.pdf`;
+      document.body.appendChild(a);
+      a.click();
+      window.URL.revokeObjectURL(url);
+      document.body.removeChild(a);
+    } catch (error) {
+      notifications.show({ title: 'Error', message: `No hay pagos registrados en ${statementYear} o no se pudo generar el certificado.`, color: 'red' });
+    } finally {
+      setIsDownloadingStatement(false);
+    }
+  };
+
+  // Pago en línea: la pasarela redirige de vuelta con ?checkout=<referencia>
+  const [searchParams, setSearchParams] = useSearchParams();
+  const checkoutReference = searchParams.get('checkout');
+  const [returnedCheckout, setReturnedCheckout] = useState<PaymentCheckout | null>(null);
+  const [isStartingCheckout, setIsStartingCheckout] = useState(false);
+
+  useEffect(() => {
+    if (!checkoutReference) return;
+    paymentCheckoutApi
+      .getByReference(checkoutReference)
+      .then((checkout) => {
+        setReturnedCheckout(checkout);
+        if (checkout.status === 'approved') refetchPayments();
+      })
+      .catch(() => setReturnedCheckout(null));
+  }, [checkoutReference]);
+
+  const CHECKOUT_RESULT: Record<string, { title: string; color: string }> = {
+    pending: { title: 'Pago en proceso: te avisaremos cuando la pasarela lo confirme.', color: 'yellow' },
+    approved: { title: 'Pago aprobado y registrado. ¡Gracias!', color: 'green' },
+    declined: { title: 'El pago fue rechazado. Puedes intentarlo de nuevo.', color: 'red' },
+    voided: { title: 'El pago fue anulado.', color: 'red' },
+    error: { title: 'Hubo un error con el pago. Puedes intentarlo de nuevo.', color: 'red' },
+  };
+
+  const handlePayOnline = async () => {
+    setIsStartingCheckout(true);
+    try {
+      const { checkout_url } = await paymentCheckoutApi.create();
+      window.location.href = checkout_url;
+    } catch (error: any) {
+      notifications.show({ title: 'Error', message: error?.response?.data?.error || 'No se pudo iniciar el pago en línea.', color: 'red' });
+      setIsStartingCheckout(false);
+    }
+  };
+
   const isLoading = isLoadingPayments || ( (isAdmin || isManager) && (isLoadingAllRentals || isLoadingAllProperties));
 
   return (
     <Container size="xl">
       <LoadingOverlay visible={isLoading} overlayProps={{ blur: 2 }} />
       {paymentsError && <Text color="red" ta="center">Error al cargar pagos: {(paymentsError as Error).message}</Text>}
+      {checkoutReference && returnedCheckout && CHECKOUT_RESULT[returnedCheckout.status] && (
+        <Alert
+          mb="md"
+          color={CHECKOUT_RESULT[returnedCheckout.status].color}
+          title={CHECKOUT_RESULT[returnedCheckout.status].title}
+          withCloseButton
+          onClose={() => setSearchParams({})}
+        >
+          Referencia {returnedCheckout.reference} · ${(returnedCheckout.amount_in_cents / 100).toFixed(2)} {returnedCheckout.currency}
+        </Alert>
+      )}
       
       <Group justify="space-between" mb="xl">
         <Title order={1}>{(isStandardUser && user?.person_id) ? 'Mis Pagos' : 'Gestión de Pagos'}</Title>
         <Group>
+          {isStandardUser && user?.person_id && (
+            <>
+              <Button leftSection={<IconCash size={16} />} loading={isStartingCheckout} onClick={handlePayOnline}>
+                Pagar arriendo del mes
+              </Button>
+              <Select
+                data={statementYears}
+                value={statementYear}
+                onChange={(value) => value && setStatementYear(value)}
+                w={100}
+                aria-label="Año del certificado"
+              />
+              <Button
+                leftSection={<IconFileCertificate size={16} />}
+                variant="outline"
+                loading={isDownloadingStatement}
+                onClick={handleDownloadStatement}
+              >
+                Certificado de Pagos
+              </Button>
+            </>
+          )}
           {isAdmin && (
             <Button 
               leftSection={<IconFilter size={16} />} 
@@ -261,6 +378,11 @@ export default function Payments() {
                       <Badge color={payment.paid_on_time ? 'green' : 'red'}>
                         {payment.paid_on_time ? 'A Tiempo' : 'Atrasado'}
                       </Badge>
+                      {isAdmin && payment.invoice_status && (
+                        <Badge ml="xs" variant="light" color={INVOICE_STATUS[payment.invoice_status]?.color} title={payment.invoice_message || payment.invoice_cufe}>
+                          {payment.invoice_number} · {INVOICE_STATUS[payment.invoice_status]?.label}
+                        </Badge>
+                      )}
                     </Table.Td>
                     {(isAdmin || isManager) && canManagePayment(payment) && (
                       <Table.Td>
@@ -271,6 +393,16 @@ export default function Payments() {
                           <ActionIcon variant="subtle" color="red" onClick={() => handleDelete(payment)} title="Eliminar">
                             <IconTrash size={16} />
                           </ActionIcon>
+                          {isAdmin && payment.invoice_status !== 'accepted' && (
+                            <ActionIcon
+                              variant="subtle"
+                              color="teal"
+                              onClick={() => handleInvoice(payment)}
+                              title={payment.invoice_status === 'pending' ? 'Consultar estado de la factura' : 'Emitir factura electrónica'}
+                            >
+                              {payment.invoice_status === 'pending' ? <IconRefresh size={16} /> : <IconFileInvoice size={16} />}
+                            </ActionIcon>
+                          )}
                         </Group>
                       </Table.Td>
                     )}
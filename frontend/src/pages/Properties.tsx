@@ -14,7 +14,10 @@ import {
   Paper,
   Card,
   ThemeIcon,
-  MultiSelect
+  MultiSelect,
+  NumberInput,
+  Switch,
+  Textarea
 } from '@mantine/core';
 import { notifications } from '@mantine/notifications';
 import { IconEdit, IconTrash, IconPlus, IconSearch, IconHomeCheck, IconBuilding } from '@tabler/icons-react';
@@ -398,6 +401,53 @@ export default function Properties() {
               error={formSubmitted && !selectedProperty.type ? "Campo requerido" : null}
             />
           </Group>
+
+          <Text fw={500} size="sm" mb="xs">Características</Text>
+          <Group grow mb="sm">
+            <NumberInput label="Habitaciones" min={0}
+              value={selectedProperty.bedrooms ?? 0}
+              onChange={(value) => setSelectedProperty({...selectedProperty, bedrooms: typeof value === 'number' ? value : 0})}
+            />
+            <NumberInput label="Baños" min={0}
+              value={selectedProperty.bathrooms ?? 0}
+              onChange={(value) => setSelectedProperty({...selectedProperty, bathrooms: typeof value === 'number' ? value : 0})}
+            />
+            <NumberInput label="Área (m²)" min={0} decimalScale={2} decimalSeparator=","
+              value={selectedProperty.area_m2 ?? 0}
+              onChange={(value) => setSelectedProperty({...selectedProperty, area_m2: typeof value === 'number' ? value : 0})}
+            />
+          </Group>
+          <Group grow mb="sm">
+            <Select label="Estrato" placeholder="Sin definir" clearable
+              data={['1', '2', '3', '4', '5', '6']}
+              value={selectedProperty.estrato ? String(selectedProperty.estrato) : null}
+              onChange={(value) => setSelectedProperty({...selectedProperty, estrato: value ? Number(value) : 0})}
+            />
+            <NumberInput label="Parqueaderos" min={0}
+              value={selectedProperty.parking_spots ?? 0}
+              onChange={(value) => setSelectedProperty({...selectedProperty, parking_spots: typeof value === 'number' ? value : 0})}
+            />
+          </Group>
+          {(selectedProperty.parking_spots ?? 0) > 0 && (
+            <TextInput label="Detalle del garaje" placeholder="Ej: Garaje cubierto No. 12, sótano 1" mb="sm"
+              value={selectedProperty.parking_details || ''}
+              onChange={(e) => setSelectedProperty({...selectedProperty, parking_details: e.target.value})}
+            />
+          )}
+          <Group mb="sm">
+            <Switch label="Amoblado"
+              checked={selectedProperty.furnished ?? false}
+              onChange={(e) => setSelectedProperty({...selectedProperty, furnished: e.currentTarget.checked})}
+            />
+            <Switch label="Se aceptan mascotas"
+              checked={selectedProperty.pets_allowed ?? false}
+              onChange={(e) => setSelectedProperty({...selectedProperty, pets_allowed: e.currentTarget.checked})}
+            />
+          </Group>
+          <Textarea label="Linderos (Opcional)" placeholder="Se imprimen en el contrato de arrendamiento" mb="sm" autosize minRows={2}
+            value={selectedProperty.boundaries || ''}
+            onChange={(e) => setSelectedProperty({...selectedProperty, boundaries: e.target.value})}
+          />
           
           {isAdmin && (
               <>
@@ -23,6 +23,7 @@ import { RentalHistory, Person, Rental, Property } from '../types';
 import { notifications } from '@mantine/notifications';
 import { useDisclosure } from '@mantine/hooks';
 import { StableModal } from '../components/ui/StableModal';
+import RentalFiles from '../components/RentalFiles';
 
 export default function RentalHistoryPage() {
   const { user } = useAuth();
@@ -427,6 +428,9 @@ export default function RentalHistoryPage() {
             onChange={(event) => setCurrentHistory(prev => ({ ...prev, end_reason: event.currentTarget.value }))}
             disabled={isViewMode || !isAdmin}
           />
+          {isViewMode && currentHistory?.rental_id && (
+            <RentalFiles rentalId={currentHistory.rental_id} />
+          )}
           <Group justify="flex-end" mt="md">
             <Button variant="default" onClick={close}>{(isViewMode || !isAdmin) ? 'Cerrar' : 'Cancelar'}</Button>
             {!isViewMode && isAdmin && (
@@ -0,0 +1,11 @@
+// Categorías de documento que se pueden asignar al subir un archivo
+export const FILE_CATEGORIES = [
+  { value: 'cedula', label: 'Cédula' },
+  { value: 'contrato', label: 'Contrato' },
+  { value: 'comprobante_pago', label: 'Comprobante de pago' },
+  { value: 'certificado_laboral', label: 'Certificado laboral' },
+  { value: 'otro', label: 'Otro' },
+];
+
+export const fileCategoryLabel = (category?: string) =>
+  FILE_CATEGORIES.find((c) => c.value === category)?.label ?? category ?? '';
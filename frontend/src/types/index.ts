@@ -4,6 +4,7 @@ export type Person = {
   phone: string;
   nit: string;
   address?: string;
+  version?: number; // Se envía al actualizar; la API responde 409 si alguien más lo modificó
 };
 
 export type Role = {
@@ -29,6 +30,28 @@ export type Property = {
   type: string;
   resident_id: string;
   manager_ids: string[];
+  building_id?: string;
+  photos?: PropertyPhoto[];
+  bedrooms?: number;
+  bathrooms?: number;
+  area_m2?: number;
+  estrato?: number; // 1 a 6, 0 si no se conoce
+  parking_spots?: number;
+  parking_details?: string;
+  furnished?: boolean;
+  pets_allowed?: boolean;
+  boundaries?: string; // Linderos
+  version?: number;
+};
+
+export type PropertyPhoto = {
+  id: string;
+  property_id: string;
+  path: string;
+  url?: string;
+  caption: string;
+  position: number;
+  created_at: string;
 };
 
 export type BankAccount = {
@@ -49,6 +72,7 @@ export type Rental = {
   end_date: string;
   payment_terms: string;
   unpaid_months: number;
+  version?: number;
 };
 
 export type Pricing = {
@@ -60,6 +84,17 @@ export type Pricing = {
   tenant_responsible_for: string[];
   late_fee: number;
   due_day: number;
+  canon_taxable?: boolean;
+  components?: PricingComponent[];
+  version?: number;
+};
+
+export type PricingComponent = {
+  type: 'administracion' | 'parqueadero' | 'otro';
+  description?: string;
+  amount: number;
+  responsibility?: 'tenant' | 'owner';
+  taxable?: boolean;
 };
 
 export type PaymentSchedule = {
@@ -79,6 +114,12 @@ export type RentPayment = {
   payment_date: string;
   amount_paid: number;
   paid_on_time: boolean;
+  // Factura electrónica DIAN del pago, solo la asigna el backend
+  invoice_number?: string;
+  invoice_cufe?: string;
+  invoice_status?: 'pending' | 'accepted' | 'rejected' | 'failed';
+  invoice_message?: string;
+  invoiced_at?: string;
 };
 
 export type Document = {
@@ -126,4 +167,40 @@ export type User = {
   person_id?: string;
   password_base64?: string;
   status?: string;
-}; 
\ No newline at end of file
+  email_pending_verification?: boolean;
+}; 
+export type Reglamento = {
+  id: string;
+  building_key: string;
+  address: string;
+  city: string;
+  version: number;
+  file_name: string;
+  uploaded_by: string;
+  uploaded_at: string;
+  pdf_url: string;
+  property_id?: string;
+};
+export type RentalFile = {
+  id: string;
+  file_name: string;
+  path: string;
+  original_name: string;
+  size: number;
+  mime_type?: string;
+  category?: string;
+  tags: string[];
+  rental_id?: string;
+  contract_id?: string;
+  uploaded_by: string;
+  created_at: string;
+};
+export type TrashedFile = {
+  id: string;
+  path: string;
+  trash_path: string;
+  file_name: string;
+  deleted_by: string;
+  deleted_at: string;
+  purge_at: string;
+};